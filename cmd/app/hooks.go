@@ -0,0 +1,18 @@
+package main
+
+import "vision-api-app/internal/presentation/di"
+
+// containerOptions DIコンテナに組み込む導入先ごとのオプション（レシート処理のフックなど）
+// 導入先ではこのパッケージにファイルを追加し、init関数で追加する。例:
+//
+//	func init() {
+//		containerOptions = append(containerOptions, di.WithReceiptHooks(
+//			usecase.ReceiptHookFunc(func(ctx context.Context, receipt *entity.Receipt) error {
+//				if receipt.TotalAmount >= 50000 {
+//					receipt.Tags = append(receipt.Tags, "要承認")
+//				}
+//				return nil
+//			}),
+//		))
+//	}
+var containerOptions []di.Option
@@ -8,12 +8,15 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
+	_ "time/tzdata" // zoneinfoのないコンテナでも locale.timezone を読み込めるようにする
 
 	"vision-api-app/internal/config"
 	"vision-api-app/internal/presentation/di"
 	"vision-api-app/internal/presentation/http/router"
+	"vision-api-app/internal/presentation/http/servertls"
 )
 
 // AppConfig アプリケーション設定
@@ -28,12 +31,23 @@ type ServerInterface interface {
 	Shutdown(ctx context.Context) error
 }
 
+// tlsServer HTTPSで待ち受けるサーバー（証明書はTLSConfigに読み込み済み）
+type tlsServer struct {
+	*http.Server
+}
+
+// ListenAndServe HTTPSで待ち受け
+func (s tlsServer) ListenAndServe() error {
+	return s.ListenAndServeTLS("", "")
+}
+
 // App アプリケーション構造体（Seamパターン）
 type App struct {
-	config     *AppConfig
-	container  *di.Container
-	server     *http.Server
-	serverSeam ServerInterface // テスト用のSeam
+	config           *AppConfig
+	container        *di.Container
+	server           *http.Server
+	serverSeam       ServerInterface // テスト用のSeam
+	diagnosticServer *http.Server    // pprof・expvarの診断用サーバー（別ポートを指定した場合のみ）
 }
 
 // NewApp 新しいAppを作成
@@ -51,7 +65,7 @@ func NewApp(appCfg *AppConfig) (*App, error) {
 	}
 
 	// DIコンテナの初期化
-	container, err := di.NewContainer(cfg)
+	container, err := di.NewContainer(cfg, containerOptions...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize DI container: %w", err)
 	}
@@ -76,6 +90,25 @@ func NewApp(appCfg *AppConfig) (*App, error) {
 	// デフォルトでは実際のサーバーを使用
 	app.serverSeam = server
 
+	// HTTPS（クライアント証明書を要求する場合はハンドシェイクで検証する）
+	if serverTLS := container.ServerTLS(); serverTLS.Enabled {
+		tlsConfig, err := servertls.NewConfig(&serverTLS)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure https: %w", err)
+		}
+		server.TLSConfig = tlsConfig
+		app.serverSeam = tlsServer{server}
+	}
+
+	// 診断用サーバー（プロファイル取得に時間がかかるため書き込みのタイムアウトは設定しない）
+	if diagnostics := container.Diagnostics(); diagnostics.Enabled && diagnostics.Address != "" {
+		app.diagnosticServer = &http.Server{
+			Addr:              diagnostics.Address,
+			Handler:           router.NewDiagnosticsHandler(),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+	}
+
 	return app, nil
 }
 
@@ -84,6 +117,15 @@ func (a *App) Start() error {
 	// 起動メッセージ
 	a.printStartupMessage()
 
+	// 診断用サーバー起動（失敗してもAPIサーバーは起動する）
+	if a.diagnosticServer != nil {
+		go func() {
+			if err := a.diagnosticServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("Diagnostics server failed: %v", err)
+			}
+		}()
+	}
+
 	// サーバー起動（Seamを使用）
 	return a.serverSeam.ListenAndServe()
 }
@@ -92,13 +134,120 @@ func (a *App) Start() error {
 func (a *App) printStartupMessage() {
 	fmt.Println("=== Vision API Server (Clean Architecture) ===")
 	fmt.Printf("AI Provider: %s\n", a.container.AICorrectionUseCase().GetProviderName())
-	fmt.Printf("Server listening on http://0.0.0.0:%s\n", a.config.Port)
+	if serverTLS := a.container.ServerTLS(); serverTLS.Enabled {
+		fmt.Printf("Server listening on https://0.0.0.0:%s\n", a.config.Port)
+		switch {
+		case serverTLS.ClientAuth.Enabled && len(serverTLS.ClientAuth.AllowedCNs) > 0:
+			fmt.Printf("Client certificates: required, allowed CNs: %s\n", strings.Join(serverTLS.ClientAuth.AllowedCNs, ", "))
+		case serverTLS.ClientAuth.Enabled:
+			fmt.Println("Client certificates: required, any certificate issued by client_auth.ca_file")
+		}
+	} else {
+		fmt.Printf("Server listening on http://0.0.0.0:%s\n", a.config.Port)
+	}
+	if a.diagnosticServer != nil {
+		fmt.Printf("Diagnostics (pprof/expvar) listening on http://%s/debug/\n", a.diagnosticServer.Addr)
+	} else if a.container.Diagnostics().Enabled {
+		fmt.Println("Diagnostics (pprof/expvar): /debug/pprof/, /debug/vars (admin token required)")
+	}
+	if !a.container.PersistenceEnabled() {
+		fmt.Println("Receipt persistence: disabled (MySQL not configured, only Vision API is available)")
+	}
 	fmt.Println()
 	fmt.Println("Endpoints:")
+	fmt.Println("  GET  /                            - Web UI (SPA)")
+	fmt.Println("  GET  /reports                     - Monthly and medical reports (レポート画面)")
 	fmt.Println("  GET  /health                      - Health check")
+	fmt.Println("  GET  /ready                       - Readiness check, reports degraded DB (レディネスチェック)")
 	fmt.Println("  POST /api/v1/vision/analyze       - Vision API (汎用OCR)")
 	fmt.Println("  POST /api/v1/vision/receipt       - Receipt recognition (レシート認識)")
+	fmt.Println("  POST /api/v1/vision/receipt/simple - Receipt recognition from raw image body, X-API-Key (ショートカット向け)")
 	fmt.Println("  POST /api/v1/vision/categorize    - Receipt categorization (カテゴリ判定)")
+	fmt.Println("  POST /api/v1/vision/compare       - Line diff of text from before/after images (テキストの比較)")
+	fmt.Println("  GET  /api/v1/receipts              - List receipts, filter by ?tag=&q= (レシート一覧)")
+	fmt.Println("  POST /api/v1/receipts              - Upload and register receipt (レシート登録)")
+	fmt.Println("  GET  /api/v1/receipts/needs-review - Receipts needing review (要確認レシート)")
+	fmt.Println("  GET  /api/v1/receipts/{id}         - Get receipt (レシート取得)")
+	fmt.Println("  PUT  /api/v1/receipts/{id}         - Replace all editable receipt fields (レシートの置き換え)")
+	fmt.Println("  PATCH /api/v1/receipts/{id}        - Correct receipt fields/items/tags/memo (レシート修正)")
+	fmt.Println("  DELETE /api/v1/receipts/{id}       - Move receipt to trash (レシート削除)")
+	fmt.Println("  GET  /api/v1/expenses              - List expenses by ?category=&from=&to= (家計簿エントリ一覧)")
+	fmt.Println("  POST /api/v1/expenses              - Create an expense without a receipt (家計簿エントリ登録)")
+	fmt.Println("  GET  /api/v1/expenses/{id}         - Get expense (家計簿エントリ取得)")
+	fmt.Println("  PUT  /api/v1/expenses/{id}         - Replace expense (家計簿エントリ置き換え)")
+	fmt.Println("  POST /api/v1/expenses/categorize   - Suggest categories for expense descriptions in batches (摘要のカテゴリー一括判定)")
+	fmt.Println("  GET  /api/v1/expenses/summary      - Summarize receipts and expenses by category for ?month=YYYY-MM (月のカテゴリー別集計)")
+	fmt.Println("  PATCH /api/v1/expenses/{id}        - Update expense memo (家計簿メモ)")
+	fmt.Println("  DELETE /api/v1/expenses/{id}       - Move expense to trash (家計簿エントリ削除)")
+	fmt.Println("  GET  /api/v1/trash                 - Trash with retention countdown, ?kind= (ゴミ箱)")
+	fmt.Println("  POST /api/v1/trash/restore         - Bulk restore from trash (まとめて元に戻す)")
+	fmt.Println("  POST /api/v1/trash/purge           - Bulk purge from trash (まとめて完全に削除)")
+	fmt.Println("  GET  /api/v1/receipts/{id}/history - Receipt change history (変更履歴)")
+	fmt.Println("  POST /api/v1/receipts/{id}/revert  - Revert receipt to a revision (変更の取り消し)")
+	fmt.Println("  GET  /api/v1/receipts/{id}/export  - Export receipt and expenses as versioned JSON, ?schema_version= (移行用の書き出し)")
+	fmt.Println("  POST /api/v1/receipts/import       - Import exported receipt JSON from another instance (移行用の読み込み)")
+	fmt.Println("  POST /api/v1/receipts/merge        - Merge a duplicate receipt into another (二重登録の統合)")
+	fmt.Println("  POST /api/v1/receipts/{id}/unmerge - Undo a merge and restore the merged receipt (統合の取り消し)")
+	fmt.Println("  POST /api/v1/receipts/{id}/reprocess - Reprocess from stored image (再処理)")
+	fmt.Println("  GET  /api/v1/receipts/{id}/extraction - Compare raw AI output with receipt (読み取り原文の比較)")
+	fmt.Println("  POST /api/v1/backfills             - Re-read stored images in an AI batch (バッチでの読み直し)")
+	fmt.Println("  GET  /api/v1/backfills/{id}        - Backfill progress (読み直しの進み具合)")
+	fmt.Println("  GET  /api/v1/usage/storage         - Stored image usage and quota (保存容量)")
+	fmt.Println("  GET  /api/v1/categories            - List categories (カテゴリー一覧)")
+	fmt.Println("  POST /api/v1/categories            - Create category, 409 on duplicate name (カテゴリー登録)")
+	fmt.Println("  PUT  /api/v1/categories/{id}       - Replace category name, description and color (カテゴリー更新)")
+	fmt.Println("  DELETE /api/v1/categories/{id}     - Delete category (カテゴリー削除)")
+	fmt.Println("  GET  /api/v1/categories/{name}/receipts - Receipts containing the category (カテゴリー別レシート)")
+	fmt.Println("  GET  /api/v1/categories/{name}/items - Items in the category across receipts (カテゴリー別明細)")
+	fmt.Println("  GET  /api/v1/items/price-history   - Price history of an item by ?name= (価格推移)")
+	fmt.Println("  GET  /api/v1/items/aliases         - List item name aliases, admin token (商品名の別名辞書)")
+	fmt.Println("  PUT  /api/v1/items/aliases         - Register an item name alias, admin token (別名の登録)")
+	fmt.Println("  DELETE /api/v1/items/aliases/{alias} - Delete an item name alias, admin token (別名の削除)")
+	fmt.Println("  GET  /api/v1/suggestions/shopping-list - Shopping list from purchase patterns (買い物リスト)")
+	fmt.Println("  GET  /api/v1/public/stats/spend    - Average grocery spend by region/month, k-anonymous (公開統計)")
+	fmt.Println("  POST /api/v1/public/stats/contributions - Contribute monthly grocery spend, X-API-Key (集計値の提供)")
+	fmt.Println("  GET  /api/v1/warranties/expiring   - Items with return/warranty deadlines due (返品・保証期限)")
+	fmt.Println("  PUT  /api/v1/receipts/{id}/split   - Split receipt items among participants (割り勘)")
+	fmt.Println("  PATCH /api/v1/receipts/{id}/split/participants/{name} - Mark participant settled (精算)")
+	fmt.Println("  GET  /api/v1/splits                - Splits with unsettled participants (未精算の割り勘)")
+	fmt.Println("  POST /api/v1/receipts/{id}/sync/{provider} - Push receipt to freee/moneyforward (会計サービス連携)")
+	fmt.Println("  GET  /api/v1/accounting/{provider}/syncs - Sync conflicts/failures by ?status= (同期状態)")
+	fmt.Println("  POST /api/v1/reconciliations       - Match bank/card statement CSV to receipts (利用明細の突き合わせ)")
+	fmt.Println("  POST /api/v1/documents             - Detect receipt image or statement CSV and process it (ファイルの自動振り分け)")
+	fmt.Println("  GET  /api/v1/collections           - List collections (コレクション一覧)")
+	fmt.Println("  POST /api/v1/collections           - Create collection (コレクションの作成)")
+	fmt.Println("  POST /api/v1/collections/{id}/receipts - Add receipts to collection (レシートの追加)")
+	fmt.Println("  GET  /api/v1/collections/{id}/report - Collection totals by category (コレクションの集計)")
+	fmt.Println("  GET  /api/v1/collections/{id}/export - Collection items as CSV/xlsx (コレクションの明細のダウンロード)")
+	fmt.Println("  GET  /api/v1/expense-reports       - List expense reports, ?status= (経費報告書一覧)")
+	fmt.Println("  POST /api/v1/expense-reports       - Create expense report from receipts (経費報告書の作成)")
+	fmt.Println("  POST /api/v1/expense-reports/{id}/submit - Submit expense report for approval (申請)")
+	fmt.Println("  POST /api/v1/expense-reports/{id}/approve - Approve/reject (/reject) or reimburse (/reimburse), X-Approver-Key (承認・精算)")
+	fmt.Println("  GET  /api/v1/expense-reports/{id}/pdf - Expense report as PDF (経費報告書のPDF)")
+	fmt.Println("  POST /api/v1/uploads/presign       - Issue signed upload URL (署名付きアップロードURL)")
+	fmt.Println("  PUT  /api/v1/uploads/{id}          - Direct image upload, chunked with Content-Range (直接アップロード)")
+	fmt.Println("  GET  /api/v1/uploads/{id}          - Upload progress for resuming (受信状況)")
+	fmt.Println("  POST /api/v1/uploads/{id}/complete - Process uploaded image (アップロード完了)")
+	fmt.Println("  GET  /api/v1/reports/monthly       - Monthly spending by category (月別集計)")
+	fmt.Println("  GET  /api/v1/reports/summary       - Daily/weekly/monthly spending for a date range (期間別集計)")
+	fmt.Println("  GET  /api/v1/reports/calendar      - Per-day receipt totals and counts, ?year=&month= (支出カレンダー)")
+	fmt.Println("  GET  /api/v1/reports/widget        - Chart-ready series by metric/group_by/period/filters (ウィジェット)")
+	fmt.Println("  GET  /api/v1/reports/charts/categories - Category pie chart as PNG, ?from=&to=&width=&height= (カテゴリー別の円グラフ)")
+	fmt.Println("  GET  /api/v1/reports/charts/monthly - Monthly spending trend chart as PNG, ?year= (月別の推移のグラフ)")
+	fmt.Println("  GET  /api/v1/reports/medical-deduction - Medical expense deduction report (医療費控除)")
+	fmt.Println("  GET  /api/v1/reports/ledger        - hledger/beancount journal export (複式簿記の仕訳)")
+	fmt.Println("  GET  /api/v1/reports/export        - Receipt items as CSV/xlsx (明細のエクスポート)")
+	fmt.Println("  POST /api/v1/webhooks/line         - LINE bot receipt photos, line.enabled (LINEからのレシート登録)")
+	fmt.Println("  GET/PUT /api/v1/admin/maintenance  - Maintenance mode (メンテナンスモード)")
+	fmt.Println("  GET  /api/v1/admin/features        - Feature flags (機能フラグ)")
+	fmt.Println("  GET  /api/v1/admin/container       - Wired implementations and config fingerprints (組み立てた実装の確認)")
+	fmt.Println("  GET  /api/v1/admin/slo             - Receipt processing SLO status (SLOの状態)")
+	fmt.Println("  GET  /api/v1/admin/cache/stats     - Cache hit rate per endpoint/tenant and keyspace (キャッシュの利用状況)")
+	fmt.Println("  POST /api/v1/admin/repair/totals   - Repair receipt totals, ?dry_run=true (合計金額の修復)")
+	fmt.Println("  POST /api/v1/admin/repair/names    - Normalize store/item names, ?dry_run=true (店名・商品名の正規化)")
+	fmt.Println("  POST /api/v1/admin/warehouse/export - Export Parquet files for a month, ?year=&month= (分析用ファイルの書き出し)")
+	fmt.Println("  GET/DELETE /api/v1/admin/ai-debug  - AI request/response debug log, ?limit= (AIのデバッグ記録)")
+	fmt.Println("  GET/PUT /api/v1/admin/ai-budget    - Monthly AI cost and downgrade override (AIの予算と縮退の切り替え)")
 	fmt.Println()
 }
 
@@ -110,6 +259,16 @@ func (a *App) Shutdown(ctx context.Context) error {
 	if err := a.serverSeam.Shutdown(ctx); err != nil {
 		return fmt.Errorf("server shutdown failed: %w", err)
 	}
+	if a.diagnosticServer != nil {
+		if err := a.diagnosticServer.Shutdown(ctx); err != nil {
+			log.Printf("Diagnostics server shutdown failed: %v", err)
+		}
+	}
+
+	// バックグラウンドジョブの完了待機
+	if err := a.container.Drain(ctx); err != nil {
+		return fmt.Errorf("job drain failed: %w", err)
+	}
 
 	// コンテナのクローズ
 	if err := a.container.Close(); err != nil {
@@ -169,6 +328,11 @@ func realMain() error {
 		Port:       port,
 	}
 
+	// サブコマンド（repair-totals・normalize-names・rescale-amounts）の場合はサーバーを起動せずに実行して終了
+	if handled, err := runCommand(appCfg, os.Args[1:]); handled {
+		return err
+	}
+
 	// アプリケーションの作成
 	app, err := NewApp(appCfg)
 	if err != nil {
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	householdUsecase "vision-api-app/internal/modules/household/usecase"
+)
+
+// normalizeNamesCommand 店名・商品名の正規化を実行するサブコマンド名
+const normalizeNamesCommand = "normalize-names"
+
+// runNormalizeNames 保存済みのレシートの店名・商品名を names の設定で正規化し、結果をJSONで出力する
+// 使い方: vision-api normalize-names [-dry-run]
+func runNormalizeNames(appCfg *AppConfig, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet(normalizeNamesCommand, flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "検出のみで保存しない")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return runReceiptCommand(appCfg, out, func(ctx context.Context, uc *householdUsecase.ReceiptUseCase) (any, error) {
+		report, err := uc.NormalizeNames(ctx, *dryRun)
+		if err != nil {
+			return nil, fmt.Errorf("failed to normalize receipt names: %w", err)
+		}
+		return report, nil
+	})
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"vision-api-app/internal/config"
+	householdUsecase "vision-api-app/internal/modules/household/usecase"
+	"vision-api-app/internal/presentation/di"
+)
+
+// repairTotalsCommand 合計金額の修復を実行するサブコマンド名
+const repairTotalsCommand = "repair-totals"
+
+// runRepairTotals 保存済みのレシートの合計金額を検証・修復し、結果をJSONで出力する
+// 使い方: vision-api repair-totals [-dry-run]
+func runRepairTotals(appCfg *AppConfig, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet(repairTotalsCommand, flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "検出のみで保存しない")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return runReceiptCommand(appCfg, out, func(ctx context.Context, uc *householdUsecase.ReceiptUseCase) (any, error) {
+		report, err := uc.RepairTotals(ctx, *dryRun)
+		if err != nil {
+			return nil, fmt.Errorf("failed to repair receipt totals: %w", err)
+		}
+		return report, nil
+	})
+}
+
+// runReceiptCommand DIコンテナのレシートのユースケースで保存済みのレシートを処理し、結果をJSONで出力する
+func runReceiptCommand(appCfg *AppConfig, out io.Writer, run func(context.Context, *householdUsecase.ReceiptUseCase) (any, error)) error {
+	cfg, err := config.Load(appCfg.ConfigPath)
+	if err != nil {
+		log.Printf("Failed to load config: %v. Using defaults.", err)
+		cfg = config.DefaultConfig()
+	}
+
+	container, err := di.NewContainer(cfg, containerOptions...)
+	if err != nil {
+		return fmt.Errorf("failed to initialize DI container: %w", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := container.Drain(ctx); err != nil {
+			log.Printf("Job drain failed: %v", err)
+		}
+		if err := container.Close(); err != nil {
+			log.Printf("Container close failed: %v", err)
+		}
+	}()
+
+	receiptUseCase := container.ReceiptUseCase()
+	if receiptUseCase == nil {
+		return errors.New("receipt persistence is disabled (MySQL not configured)")
+	}
+
+	report, err := run(context.Background(), receiptUseCase)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+// runCommand サブコマンドを実行（サブコマンドでない場合はfalse）
+func runCommand(appCfg *AppConfig, args []string) (bool, error) {
+	if len(args) == 0 {
+		return false, nil
+	}
+	switch args[0] {
+	case repairTotalsCommand:
+		return true, runRepairTotals(appCfg, args[1:], os.Stdout)
+	case normalizeNamesCommand:
+		return true, runNormalizeNames(appCfg, args[1:], os.Stdout)
+	case rescaleAmountsCommand:
+		return true, runRescaleAmounts(appCfg, args[1:], os.Stdout)
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+
+	"vision-api-app/internal/config"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+	sharedDB "vision-api-app/internal/modules/shared/infrastructure/database"
+)
+
+// rescaleAmountsCommand 保存済みの金額の換算を実行するサブコマンド名
+const rescaleAmountsCommand = "rescale-amounts"
+
+// runRescaleAmounts 保存済みの金額を locale.currency（-to で上書き可）の最小単位に換算し、結果をJSONで出力する
+// 使い方: vision-api rescale-amounts [-to USD] [-dry-run]
+// サーバーは保存している通貨と設定の通貨が異なると起動しないため、DIコンテナを使わずにデータベースへ接続する
+func runRescaleAmounts(appCfg *AppConfig, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet(rescaleAmountsCommand, flag.ContinueOnError)
+	to := fs.String("to", "", "換算先の通貨コード（省略時は locale.currency）")
+	dryRun := fs.Bool("dry-run", false, "換算する件数を数えるのみで保存しない")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(appCfg.ConfigPath)
+	if err != nil {
+		log.Printf("Failed to load config: %v. Using defaults.", err)
+		cfg = config.DefaultConfig()
+	}
+	if !cfg.MySQL.Configured() {
+		return errors.New("receipt persistence is disabled (MySQL not configured)")
+	}
+
+	code := *to
+	if code == "" {
+		code = cfg.Locale.Currency
+	}
+	currency, err := sharedDomain.LookupCurrency(code)
+	if err != nil {
+		return err
+	}
+
+	rescaler, err := sharedDB.NewBunAmountRescaler(&cfg.MySQL)
+	if err != nil {
+		return fmt.Errorf("failed to initialize amount rescaler: %w", err)
+	}
+	defer func() {
+		if err := rescaler.Close(); err != nil {
+			log.Printf("Database close failed: %v", err)
+		}
+	}()
+
+	report, err := rescaler.Rescale(context.Background(), currency, *dryRun)
+	if err != nil {
+		return fmt.Errorf("failed to rescale amounts: %w", err)
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
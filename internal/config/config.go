@@ -3,15 +3,61 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 // Config アプリケーション全体の設定
 type Config struct {
-	Anthropic AnthropicConfig `yaml:"anthropic"`
-	Redis     RedisConfig     `yaml:"redis"`
-	MySQL     MySQLConfig     `yaml:"mysql"`
+	AI             AIConfig             `yaml:"ai"`
+	Anthropic      AnthropicConfig      `yaml:"anthropic"`
+	Gemini         GeminiConfig         `yaml:"gemini"`
+	Redis          RedisConfig          `yaml:"redis"`
+	MySQL          MySQLConfig          `yaml:"mysql"`
+	Queue          QueueConfig          `yaml:"queue"`
+	Storage        StorageConfig        `yaml:"storage"`
+	IDs            IDsConfig            `yaml:"ids"`
+	Names          NamesConfig          `yaml:"names"`
+	PurchaseDates  PurchaseDatesConfig  `yaml:"purchase_dates"`
+	Backfills      BackfillsConfig      `yaml:"backfills"`
+	Uploads        UploadsConfig        `yaml:"uploads"`
+	Drafts         DraftsConfig         `yaml:"drafts"`
+	Trash          TrashConfig          `yaml:"trash"`
+	Scanner        ScannerConfig        `yaml:"scanner"`
+	ImageURLs      ImageURLsConfig      `yaml:"image_urls"`
+	Outbound       OutboundConfig       `yaml:"outbound"`
+	Reports        ReportsConfig        `yaml:"reports"`
+	Analytics      AnalyticsConfig      `yaml:"analytics"`
+	Warranties     WarrantiesConfig     `yaml:"warranties"`
+	Reconciliation ReconciliationConfig `yaml:"reconciliation"`
+	ExpenseReports ExpenseReportsConfig `yaml:"expense_reports"`
+	Notifications  NotificationsConfig  `yaml:"notifications"`
+	Events         EventsConfig         `yaml:"events"`
+	Accounting     AccountingConfig     `yaml:"accounting"`
+	Warehouse      WarehouseConfig      `yaml:"warehouse"`
+	Line           LineConfig           `yaml:"line"`
+	Maintenance    MaintenanceConfig    `yaml:"maintenance"`
+	SLO            SLOConfig            `yaml:"slo"`
+	CacheStats     CacheStatsConfig     `yaml:"cache_stats"`
+	RepoCache      RepoCacheConfig      `yaml:"repository_cache"`
+	Admin          AdminConfig          `yaml:"admin"`
+	APIKeys        APIKeysConfig        `yaml:"api_keys"`
+	ClientIP       ClientIPConfig       `yaml:"client_ip"`
+	LoadShedding   LoadSheddingConfig   `yaml:"load_shedding"`
+	Diagnostics    DiagnosticsConfig    `yaml:"diagnostics"`
+	ServerTLS      ServerTLSConfig      `yaml:"server_tls"`
+	AIDebug        AIDebugConfig        `yaml:"ai_debug"`
+	AIBudget       AIBudgetConfig       `yaml:"ai_budget"`
+	Web            WebConfig            `yaml:"web"`
+	Response       ResponseConfig       `yaml:"response"`
+	Locale         LocaleConfig         `yaml:"locale"`
+	Features       FeaturesConfig       `yaml:"features"`
+}
+
+// AIConfig 画像の読み取り・カテゴリーの判定に使うAIの設定
+type AIConfig struct {
+	Provider string `yaml:"provider"` // anthropic（既定）・gemini
 }
 
 // AnthropicConfig Anthropic APIの設定
@@ -19,6 +65,25 @@ type AnthropicConfig struct {
 	APIKey    string `yaml:"api_key"`
 	Model     string `yaml:"model"`
 	MaxTokens int    `yaml:"max_tokens"`
+
+	TaskMaxTokens  AnthropicTaskMaxTokens `yaml:"task_max_tokens"`  // 処理の種類ごとの最大出力トークン数
+	MaxTokensLimit int                    `yaml:"max_tokens_limit"` // 出力が途中で切れた場合に最大出力トークン数を倍にして再試行する上限（0の場合は再試行しない）
+}
+
+// AnthropicTaskMaxTokens 処理の種類ごとの最大出力トークン数（0の場合はmax_tokensを使う）
+type AnthropicTaskMaxTokens struct {
+	Receipt    int `yaml:"receipt"`    // レシートの読み取り
+	Categorize int `yaml:"categorize"` // カテゴリーの判定
+	Image      int `yaml:"image"`      // 画像のテキスト抽出
+	Correct    int `yaml:"correct"`    // テキストの補正
+}
+
+// GeminiConfig Google Gemini APIの設定（ai.providerがgeminiの場合に使う）
+type GeminiConfig struct {
+	APIKey         string `yaml:"api_key"`
+	Model          string `yaml:"model"`
+	MaxTokens      int    `yaml:"max_tokens"`       // 最大出力トークン数（思考に使うトークンを含む）
+	MaxTokensLimit int    `yaml:"max_tokens_limit"` // 出力が途中で切れた場合に最大出力トークン数を倍にして再試行する上限（0の場合は再試行しない）
 }
 
 // RedisConfig Redisの設定
@@ -27,6 +92,9 @@ type RedisConfig struct {
 	Port     int    `yaml:"port"`
 	Password string `yaml:"password"`
 	DB       int    `yaml:"db"`
+
+	FailureThreshold     int `yaml:"failure_threshold"`      // 連続して何回失敗したらキャッシュの利用を停止するか
+	ProbeIntervalSeconds int `yaml:"probe_interval_seconds"` // 利用停止中に復旧を確認する間隔（秒）
 }
 
 // MySQLConfig MySQLの設定
@@ -38,6 +106,409 @@ type MySQLConfig struct {
 	Database string `yaml:"database"`
 }
 
+// Configured MySQLの接続先が設定されているか（未設定の場合はレシートの保存を無効化する）
+func (c *MySQLConfig) Configured() bool {
+	return c.Host != ""
+}
+
+// QueueConfig ジョブキューの設定
+type QueueConfig struct {
+	Backend       string `yaml:"backend"`        // ジョブキューの実装（memory: プロセス内, redis: Redis Streams）
+	Workers       int    `yaml:"workers"`        // カテゴリー判定などを処理するワーカー数
+	BufferSize    int    `yaml:"buffer_size"`    // 投入待ちジョブのバッファサイズ（memoryのみ）
+	Stream        string `yaml:"stream"`         // ジョブを格納するストリーム名（redisのみ）
+	Group         string `yaml:"group"`          // ジョブを分担するコンシューマーグループ名（redisのみ）
+	MaxDeliveries int    `yaml:"max_deliveries"` // 引き取ったジョブの最大配信回数（redisのみ、超えたジョブは<stream>:deadに移す）
+}
+
+// StorageConfig アップロード画像の保存設定
+type StorageConfig struct {
+	ImageDir           string `yaml:"image_dir"`            // 元画像を保存するディレクトリ（再処理に使用）
+	ImageRetentionDays int    `yaml:"image_retention_days"` // 元画像の保持日数（0は無期限）
+	SpoolDir           string `yaml:"spool_dir"`            // データベース障害中に受け付けたレシートの一時保管先
+	QuotaMB            int    `yaml:"quota_mb"`             // 画像の保存容量の上限（MB、0は無制限）
+}
+
+// UploadsConfig 署名付きURLによる直接アップロードの設定
+type UploadsConfig struct {
+	Secret        string          `yaml:"secret"`         // URL署名の鍵（空の場合は起動ごとに生成するため、複数台構成では共通の値を設定）
+	ExpiryMinutes int             `yaml:"expiry_minutes"` // 署名付きURLの有効期間（分）
+	MaxSizeMB     int             `yaml:"max_size_mb"`    // 直接アップロードできる画像の最大サイズ（MB）
+	S3            UploadsS3Config `yaml:"s3"`
+}
+
+// UploadsS3Config 画像本体を直接アップロードさせるS3互換のストレージ（AWS S3・MinIO・Cloudflare R2など）
+// アクセスキーは設定ファイルではなく秘密情報（環境変数 UPLOADS_S3_ACCESS_KEY_ID / UPLOADS_S3_SECRET_ACCESS_KEY）から取得する
+type UploadsS3Config struct {
+	Endpoint string `yaml:"endpoint"` // エンドポイントのURL（空の場合はregionのAWSのエンドポイント）
+	Region   string `yaml:"region"`
+	Bucket   string `yaml:"bucket"` // バケット（空の場合はAPIサーバーが画像本体を受信する）
+	Prefix   string `yaml:"prefix"` // キーの前に付けるパス（例: uploads）
+}
+
+// DraftsConfig 読み取り結果を確認してから保存する2段階のレシート登録の設定
+type DraftsConfig struct {
+	ExpiryMinutes int `yaml:"expiry_minutes"` // 下書きを確認できる期間（分、過ぎた下書きは画像とともに削除する）
+}
+
+// TrashConfig 削除したレシート・家計簿エントリのゴミ箱の設定
+type TrashConfig struct {
+	RetentionDays int `yaml:"retention_days"` // ゴミ箱に残す日数（過ぎた項目は元画像とともに完全に削除する）
+}
+
+// IDsConfig レシート・明細などの識別子の生成設定
+type IDsConfig struct {
+	Strategy string `yaml:"strategy"` // 生成方式（uuidv7: 時刻順, uuidv4: ランダム, hash: 画像から決定的に生成）
+}
+
+// NamesConfig OCRで読み取った店名・商品名の表記の整形設定
+type NamesConfig struct {
+	Normalize bool             `yaml:"normalize"` // NFKCで全角・半角や空白の表記ゆれをそろえる
+	Rules     []NameRuleConfig `yaml:"rules"`     // 正規化の後に順に適用する置き換え
+}
+
+// PurchaseDatesConfig AIが読み取った購入日の妥当性の確認設定
+// 数字の読み間違いによる未来の日付・1970年代の日付などを範囲外として要確認にする
+type PurchaseDatesConfig struct {
+	MaxAgeYears          int  `yaml:"max_age_years"`          // 登録日時から何年より前の購入日を範囲外とするか（0は確認しない）
+	FutureToleranceHours int  `yaml:"future_tolerance_hours"` // 登録日時から何時間先までの購入日を許容するか（タイムゾーンの違いなど、負の値は未来の日付を確認しない）
+	Clamp                bool `yaml:"clamp"`                  // 範囲外の購入日を置き換える（未来の日付は登録日時、古すぎる日付は範囲の下限。falseの場合は読み取ったまま保存する）
+}
+
+// BackfillsConfig 保存済みの元画像をAIのバッチ処理でまとめて読み直す設定（ai.providerがanthropicの場合のみ使える）
+type BackfillsConfig struct {
+	BatchSize           int `yaml:"batch_size"`            // 1つのバッチにまとめるレシート数
+	BatchMaxMB          int `yaml:"batch_max_mb"`          // 1つのバッチにまとめる画像のbase64に変換した後の合計サイズ（MB、APIの上限は256MB）
+	MaxReceipts         int `yaml:"max_receipts"`          // 1回の読み直しで指定できるレシート数
+	PollIntervalMinutes int `yaml:"poll_interval_minutes"` // バッチの処理状況を確認する間隔（分）
+}
+
+// NameRuleConfig 店名・商品名の置き換えルール
+type NameRuleConfig struct {
+	Pattern string `yaml:"pattern"` // 置き換える部分の正規表現（Goのregexp）
+	Replace string `yaml:"replace"` // 置き換え後の文字列（$1などで一致した部分を参照できる）
+}
+
+// ScannerConfig アップロードファイルのウイルス検査の設定
+type ScannerConfig struct {
+	Backend        string `yaml:"backend"`         // 検査方式（none: 検査しない, clamav: ClamAV(clamd), http: 外部の検査API）
+	Address        string `yaml:"address"`         // clamdのアドレス（clamavのみ、例: clamav:3310）
+	URL            string `yaml:"url"`             // 検査APIのURL（httpのみ）
+	TimeoutSeconds int    `yaml:"timeout_seconds"` // 1ファイルあたりの検査のタイムアウト（秒）
+}
+
+// ImageURLsConfig URLで指定された画像の取得（image_url）の設定
+// SSRF対策のため、httpsかつallowed_hostsのホスト（サブドメインを含む）のみ取得し、プライベートなアドレスには接続しない
+type ImageURLsConfig struct {
+	Enabled        bool     `yaml:"enabled"`
+	AllowedHosts   []string `yaml:"allowed_hosts"`   // 取得を許可するホスト（例: example.com、空の場合はすべて拒否）
+	MaxSizeMB      int      `yaml:"max_size_mb"`     // 取得する画像の最大サイズ（MB）
+	TimeoutSeconds int      `yaml:"timeout_seconds"` // 取得のタイムアウト（秒）
+	MaxRedirects   int      `yaml:"max_redirects"`   // リダイレクトを追跡する最大回数
+}
+
+// OutboundConfig 外部（AI・Webhook・画像のURL・会計サービスなど）へのHTTP接続の設定
+type OutboundConfig struct {
+	ProxyURL string `yaml:"proxy_url"` // 経由するプロキシ（空の場合は環境変数 HTTPS_PROXY / HTTP_PROXY / NO_PROXY に従う）
+	NoProxy  string `yaml:"no_proxy"`  // プロキシを経由しないホスト（カンマ区切り、proxy_urlを指定した場合のみ）
+	CAFile   string `yaml:"ca_file"`   // システムの証明書に加えて信頼するルート証明書（PEM）
+}
+
+// ReportsConfig レポートの設定
+type ReportsConfig struct {
+	MonthStartDay   int                 `yaml:"month_start_day"`   // 月別集計の月の開始日（1〜28、給料日などから集計する場合に指定）
+	CacheTTLSeconds int                 `yaml:"cache_ttl_seconds"` // レポートのレスポンスをRedisにキャッシュする秒数（0の場合はキャッシュしない）
+	Medical         MedicalReportConfig `yaml:"medical"`
+	Ledger          LedgerConfig        `yaml:"ledger"`
+}
+
+// MedicalReportConfig 医療費控除レポートの判定ルール
+type MedicalReportConfig struct {
+	Categories       []string `yaml:"categories"`         // 医療費として扱うカテゴリー
+	Keywords         []string `yaml:"keywords"`           // 店名・商品名に含まれる場合に医療費として扱うキーワード
+	PatientTagPrefix string   `yaml:"patient_tag_prefix"` // 受診者を表すタグの接頭辞（例: "受診者:山田太郎"）
+	DefaultPatient   string   `yaml:"default_patient"`    // 受診者タグがない場合の受診者名
+}
+
+// LedgerConfig 複式簿記（hledger/beancount）の仕訳の勘定科目
+type LedgerConfig struct {
+	Currency          string            `yaml:"currency"`           // 仕訳に書く通貨コード（省略時は locale.currency）
+	Accounts          map[string]string `yaml:"accounts"`           // カテゴリーごとの費用の勘定科目
+	DefaultAccount    string            `yaml:"default_account"`    // 対応付けのないカテゴリーの勘定科目
+	PaymentAccounts   map[string]string `yaml:"payment_accounts"`   // 支払い方法ごとの支払元の勘定科目
+	PaymentAccount    string            `yaml:"payment_account"`    // 対応付けのない支払い方法の勘定科目
+	AdjustmentAccount string            `yaml:"adjustment_account"` // 支払額と明細の合計の差額（外税・値引きなど）の勘定科目
+}
+
+// AnalyticsConfig 購入パターン分析の設定
+type AnalyticsConfig struct {
+	ShoppingList ShoppingListConfig `yaml:"shopping_list"`
+	PublicStats  PublicStatsConfig  `yaml:"public_stats"`
+}
+
+// ShoppingListConfig 買い物リストの推定ルール
+type ShoppingListConfig struct {
+	LookbackDays int `yaml:"lookback_days"` // 購入パターンの分析に使う期間（日数）
+	MinPurchases int `yaml:"min_purchases"` // 定期的に購入しているとみなす最小の購入日数
+	HorizonDays  int `yaml:"horizon_days"`  // 何日先までに購入が見込まれる商品を候補にするか（?days= で上書き可能）
+}
+
+// PublicStatsConfig 参加に同意した世帯の地域別の平均の食費の公開統計の設定
+// enabledは統計を集計するサーバーとして集計値を受け付けて公開する設定、shareはこの世帯の集計値を集計するサーバーに提供する設定
+type PublicStatsConfig struct {
+	Enabled         bool               `yaml:"enabled"`
+	MinContributors int                `yaml:"min_contributors"` // 公開に必要な提供した世帯数（k-匿名性のしきい値、3未満は3として扱う）
+	RoundTo         int                `yaml:"round_to"`         // 平均を丸める単位（locale.currency の単位）
+	MaxMonths       int                `yaml:"max_months"`       // 1回で取得できる月数の上限
+	Share           SpendSharingConfig `yaml:"share"`
+}
+
+// SpendSharingConfig この世帯の食費の合計を公開統計に提供する設定（参加に同意した場合のみ有効にする）
+// 集計するサーバーのAPIキーは設定ファイルではなく秘密情報（環境変数 PUBLIC_STATS_API_KEY）から取得する
+type SpendSharingConfig struct {
+	Enabled        bool     `yaml:"enabled"`
+	Endpoint       string   `yaml:"endpoint"`        // 統計を集計するサーバーのURL
+	ContributorID  string   `yaml:"contributor_id"`  // 世帯ごとのランダムな識別子（16〜64文字の英数字・'-'・'_'）
+	Region         string   `yaml:"region"`          // 提供する地域（例: 東京都）
+	Categories     []string `yaml:"categories"`      // 食費として合計するカテゴリー
+	TimeoutSeconds int      `yaml:"timeout_seconds"` // 送信のタイムアウト（秒）
+}
+
+// WarrantiesConfig 高額な商品の返品・保証期限の管理の設定
+type WarrantiesConfig struct {
+	MinAmount     int                    `yaml:"min_amount"`     // 期限を管理する明細の単価の下限（locale.currency の単位）
+	DefaultMonths int                    `yaml:"default_months"` // 保証期間を設定していない明細の保証期間（月数、0は保証期限を管理しない）
+	ReturnDays    int                    `yaml:"return_days"`    // 返品を受け付ける日数（0は返品期限を管理しない）
+	NotifyDays    int                    `yaml:"notify_days"`    // 期限の何日前に通知するか
+	Calendar      WarrantyCalendarConfig `yaml:"calendar"`
+}
+
+// WarrantyCalendarConfig 返品・保証期限のiCalendarのフィードの設定
+type WarrantyCalendarConfig struct {
+	Secret string `yaml:"secret"` // フィードのトークンの署名鍵（空の場合は起動ごとに生成するため、購読を続けるには固定の値を設定）
+	Days   int    `yaml:"days"`   // フィードに含める期限の期間（今日から何日先まで）
+}
+
+// ReconciliationConfig 銀行・クレジットカードの利用明細とレシートの突き合わせの設定
+type ReconciliationConfig struct {
+	DateWindowDays int      `yaml:"date_window_days"` // 利用日と購入日のずれを許容する日数
+	PaymentMethods []string `yaml:"payment_methods"`  // 明細に請求が載るはずの支払い方法（空の場合はすべてのレシートを請求なしの対象にする）
+}
+
+// ExpenseReportsConfig 会社の経費報告書の承認フローの設定
+type ExpenseReportsConfig struct {
+	Approvers []ExpenseApproverConfig `yaml:"approvers"` // 承認フローを進められる承認者（空の場合は申請までのみ）
+}
+
+// ExpenseApproverConfig 経費報告書の承認者
+type ExpenseApproverConfig struct {
+	Name string `yaml:"name"` // 承認者の名前（報告書に記録し、申請者と同じ名前の報告書は承認できない）
+	Role string `yaml:"role"` // 役割（approver: 承認・差し戻し, accountant: 承認済みの報告書の精算）
+	Key  string `yaml:"key"`  // X-Approver-Key ヘッダーで指定するキー（空の承認者は無効）
+}
+
+// AccountingConfig 会計サービス（freee / マネーフォワード クラウド会計）との同期の設定
+// OAuthの認証情報は設定ファイルではなく秘密情報（環境変数 FREEE_CLIENT_ID など）から取得する
+type AccountingConfig struct {
+	SyncIntervalMinutes int                      `yaml:"sync_interval_minutes"` // 確認済みのレシートを送信する間隔（分）
+	MaxAttempts         int                      `yaml:"max_attempts"`          // 失敗したレシートを自動で再送する回数の上限
+	BatchSize           int                      `yaml:"batch_size"`            // 1回の同期で送信するレシート数の上限
+	TimeoutSeconds      int                      `yaml:"timeout_seconds"`       // API呼び出しのタイムアウト（秒）
+	Freee               AccountingProviderConfig `yaml:"freee"`
+	MoneyForward        AccountingProviderConfig `yaml:"moneyforward"`
+}
+
+// AccountingProviderConfig 会計サービスごとの接続先と勘定科目の対応付け
+type AccountingProviderConfig struct {
+	Enabled        bool              `yaml:"enabled"`
+	BaseURL        string            `yaml:"base_url"`        // APIのURL
+	TokenURL       string            `yaml:"token_url"`       // OAuth2のトークンエンドポイント
+	CompanyID      string            `yaml:"company_id"`      // 事業所ID（freeeのみ）
+	Accounts       map[string]string `yaml:"accounts"`        // カテゴリーごとの勘定科目ID
+	DefaultAccount string            `yaml:"default_account"` // 対応付けのないカテゴリーの勘定科目ID
+	PaymentAccount string            `yaml:"payment_account"` // 支払元（貸方）の勘定科目ID（マネーフォワードのみ）
+	TaxCode        string            `yaml:"tax_code"`        // 税区分コード（freeeのみ）
+}
+
+// WarehouseConfig 分析用のParquetファイルの書き出し（DuckDB・BigQueryなどのデータウェアハウス向け）の設定
+// s3.bucketを設定した場合はS3互換のストレージ、それ以外はdirに書き出す。
+// S3互換のストレージのアクセスキーは設定ファイルではなく秘密情報（環境変数 WAREHOUSE_S3_ACCESS_KEY_ID / WAREHOUSE_S3_SECRET_ACCESS_KEY）から取得する
+type WarehouseConfig struct {
+	Enabled        bool              `yaml:"enabled"`
+	IntervalHours  int               `yaml:"interval_hours"`  // 書き出す間隔（時間）
+	LookbackMonths int               `yaml:"lookback_months"` // 当月に加えて毎回書き出し直す過去の月数（過去の月のレシートの修正を反映する）
+	Dir            string            `yaml:"dir"`             // 書き出し先のディレクトリ
+	S3             WarehouseS3Config `yaml:"s3"`
+}
+
+// WarehouseS3Config 分析用のファイルの書き出し先のS3互換のストレージ（AWS S3・MinIO・Cloudflare R2など）
+type WarehouseS3Config struct {
+	Endpoint string `yaml:"endpoint"` // エンドポイントのURL（空の場合はregionのAWSのエンドポイント）
+	Region   string `yaml:"region"`
+	Bucket   string `yaml:"bucket"` // バケット（空の場合はdirに書き出す）
+	Prefix   string `yaml:"prefix"` // キーの前に付けるパス（例: warehouse）
+}
+
+// LineConfig LINE Messaging APIのWebhook（トークで送ったレシートの写真の登録）の設定
+// チャネルシークレット・チャネルアクセストークンは設定ファイルではなく秘密情報（環境変数 LINE_CHANNEL_SECRET / LINE_CHANNEL_ACCESS_TOKEN）から取得する
+type LineConfig struct {
+	Enabled        bool     `yaml:"enabled"`
+	APIBaseURL     string   `yaml:"api_base_url"`      // Messaging APIのURL
+	DataAPIBaseURL string   `yaml:"data_api_base_url"` // 画像の取得に使うMessaging APIのURL
+	Tags           []string `yaml:"tags"`              // LINEから登録したレシートに付けるタグ
+	TimeoutSeconds int      `yaml:"timeout_seconds"`   // API呼び出しのタイムアウト（秒）
+}
+
+// NotificationsConfig 通知の設定
+type NotificationsConfig struct {
+	WebhookURL     string `yaml:"webhook_url"`     // 通知をPOSTするURL（空の場合はログに出力）
+	TimeoutSeconds int    `yaml:"timeout_seconds"` // 通知の送信のタイムアウト（秒）
+}
+
+// EventsConfig ドメインイベント（レシートの登録・合計金額の修正・明細のカテゴリー判定など）の配信の設定
+type EventsConfig struct {
+	AuditLog bool     `yaml:"audit_log"` // すべてのイベントをログに出力する
+	Notify   []string `yaml:"notify"`    // 通知先（notifications.webhook_url）へ送るイベントの種類（"*"はすべて）
+}
+
+// MaintenanceConfig メンテナンスモードの設定
+type MaintenanceConfig struct {
+	Enabled bool   `yaml:"enabled"` // 起動時からメンテナンスモードにする
+	Message string `yaml:"message"` // メンテナンス中に更新系リクエストへ返すメッセージ
+}
+
+// SLOConfig レシート処理のSLO（サービスレベル目標）の監視の設定
+// 直近window_minutes分のレシート処理の遅延・エラー率を集計し、目標を外れた場合に通知する
+type SLOConfig struct {
+	Enabled              bool    `yaml:"enabled"`
+	WindowMinutes        int     `yaml:"window_minutes"`         // 集計するローリングウィンドウ（分）
+	LatencyPercentile    float64 `yaml:"latency_percentile"`     // 遅延を判定するパーセンタイル（例: 95）
+	LatencyThresholdMs   int     `yaml:"latency_threshold_ms"`   // パーセンタイルの遅延の上限（ミリ秒）
+	SuccessTarget        float64 `yaml:"success_target"`         // 成功率の目標（%、エラーバジェットは100との差）
+	MinRequests          int     `yaml:"min_requests"`           // 判定に必要な最小のリクエスト数（少ない間は通知しない）
+	CheckIntervalSeconds int     `yaml:"check_interval_seconds"` // SLOを判定する間隔（秒）
+}
+
+// CacheStatsConfig エンドポイント・テナントごとのキャッシュの利用状況の集計の設定
+type CacheStatsConfig struct {
+	Enabled      bool   `yaml:"enabled"`
+	TenantHeader string `yaml:"tenant_header"` // テナントを指定するリクエストヘッダー（ない場合は default として集計する）
+	MaxSeries    int    `yaml:"max_series"`    // 集計するエンドポイントとテナントの組み合わせの上限（超えたテナントは (other) にまとめる）
+	SampleKeys   int    `yaml:"sample_keys"`   // 管理APIでSCANして調べるキーの数の既定値
+}
+
+// RepoCacheConfig リポジトリの読み込みのキャッシュの設定
+type RepoCacheConfig struct {
+	Enabled    bool `yaml:"enabled"`
+	TTLSeconds int  `yaml:"ttl_seconds"` // キャッシュの有効期限（秒、書き込んだ場合はその前に削除する）
+}
+
+// AdminConfig 管理APIの設定
+type AdminConfig struct {
+	Token string `yaml:"token"` // 管理APIのBearerトークン（空の場合は管理APIを無効化）
+}
+
+// APIKeysConfig 自動化ツール（iOSショートカット・curlなど）向けAPIの設定
+type APIKeysConfig struct {
+	Keys []string `yaml:"keys"` // APIキー（X-API-Key ヘッダーで指定、空の場合はAPIキー認証のAPIを無効化）
+}
+
+// ClientIPConfig リクエストの送信元のIPアドレスの解決の設定（ログ・監査ログに出力する）
+type ClientIPConfig struct {
+	TrustedProxies []string `yaml:"trusted_proxies"` // X-Forwarded-For / X-Real-IP を信頼するプロキシ（CIDRまたはIPアドレス、空の場合はヘッダーを使わない）
+}
+
+// LoadSheddingConfig 同時に処理するリクエスト数の制限（過負荷の場合は503を返す）の設定
+type LoadSheddingConfig struct {
+	Enabled             bool `yaml:"enabled"`
+	MaxInFlight         int  `yaml:"max_in_flight"`         // 同時に処理するリクエストの上限
+	MaxQueue            int  `yaml:"max_queue"`             // 上限に達した場合に空きを待つリクエストの上限
+	QueueTimeoutSeconds int  `yaml:"queue_timeout_seconds"` // 空きを待つ最大時間（秒）
+	LargeRequestKB      int  `yaml:"large_request_kb"`      // 大きなリクエストとみなすボディのサイズ（KB）
+	MaxLargeInFlight    int  `yaml:"max_large_in_flight"`   // 同時に処理する大きなリクエスト（画像のアップロードなど）の上限
+	RetryAfterSeconds   int  `yaml:"retry_after_seconds"`   // 503のRetry-After（秒）
+}
+
+// DiagnosticsConfig pprof・expvarによるランタイム診断の設定
+type DiagnosticsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Address string `yaml:"address"` // 診断用に別ポートで待ち受けるアドレス（例: 127.0.0.1:6060、空の場合は管理APIと同じトークン認証で /debug/ 配下に公開）
+}
+
+// ServerTLSConfig APIサーバーのHTTPSの設定（ゲートウェイを置かずに内部のサービスから呼び出す場合など）
+type ServerTLSConfig struct {
+	Enabled    bool             `yaml:"enabled"`
+	CertFile   string           `yaml:"cert_file"` // サーバー証明書（PEM、中間証明書を連結する）
+	KeyFile    string           `yaml:"key_file"`  // サーバー証明書の秘密鍵（PEM）
+	ClientAuth ClientAuthConfig `yaml:"client_auth"`
+}
+
+// ClientAuthConfig クライアント証明書による認証（mTLS）の設定
+type ClientAuthConfig struct {
+	Enabled    bool     `yaml:"enabled"`     // クライアント証明書を要求する（ない・検証できない接続は切断する）
+	CAFile     string   `yaml:"ca_file"`     // クライアント証明書を発行したCAの証明書（PEM、複数を連結してもよい）
+	AllowedCNs []string `yaml:"allowed_cns"` // 接続を許可するクライアント証明書のCN（空の場合はCAが発行したすべての証明書を許可）
+}
+
+// AIDebugConfig AI APIへのリクエストとレスポンスのデバッグ用の記録の設定
+type AIDebugConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Capacity int    `yaml:"capacity"` // メモリに保持する直近の件数（超えた場合は古いものから捨てる）
+	File     string `yaml:"file"`     // あわせてJSON Lines形式で追記するファイル（空の場合はメモリのみ）
+}
+
+// AIBudgetConfig AI APIの月間の利用料金の予算と、超えた場合のカテゴリーの判定の縮退の設定
+type AIBudgetConfig struct {
+	MonthlyUSD float64                 `yaml:"monthly_usd"` // 月間の予算（USD、0の場合は予算を設けない）
+	Downgrade  string                  `yaml:"downgrade"`   // 超えた場合のカテゴリーの判定（cheap_model: cheap_modelで判定、rules: AIを使わずrulesで判定）
+	CheapModel string                  `yaml:"cheap_model"` // cheap_modelで使う最も安いモデル（ai.providerのモデル）
+	Prices     map[string]AIModelPrice `yaml:"prices"`      // モデルごとの料金（未登録のモデルは0として数える）
+	Rules      map[string][]string     `yaml:"rules"`       // rulesで使うカテゴリーごとの商品名・摘要のキーワード（一致しない場合はその他・要確認）
+}
+
+// AIModelPrice 100万トークンあたりの料金（USD）
+type AIModelPrice struct {
+	InputPerMTok  float64 `yaml:"input_per_mtok"`
+	OutputPerMTok float64 `yaml:"output_per_mtok"`
+}
+
+// WebConfig Web UIの設定
+type WebConfig struct {
+	UI string `yaml:"ui"` // トップページのUI（spa: 埋め込みSPA, classic: サーバーレンダリング）
+}
+
+// ResponseConfig APIレスポンスの表現の設定（保存するデータは変更しない）
+type ResponseConfig struct {
+	FieldNaming     string                       `yaml:"field_naming"`     // JSONのフィールド名（snake / camel、リクエストの X-JSON-Naming ヘッダーで上書きできる）
+	LocalizedFields []string                     `yaml:"localized_fields"` // 翻訳するフィールド（スネークケースの名前）
+	Translations    map[string]map[string]string `yaml:"translations"`     // 言語ごとの表示文字列の翻訳（Accept-Language で選択）
+}
+
+// LocaleConfig 日付・金額の扱いの設定
+type LocaleConfig struct {
+	Timezone string `yaml:"timezone"` // 購入日の解釈・期間の集計に使うタイムゾーン（IANA名、リクエストの X-Timezone ヘッダー・tz パラメーターで上書きできる）
+	Currency string `yaml:"currency"` // 金額の通貨（ISO 4217、金額は通貨の最小単位の整数で保存する）
+}
+
+// Location タイムゾーンを読み込む（未設定の場合はサーバーのタイムゾーン）
+func (c *LocaleConfig) Location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", c.Timezone, err)
+	}
+	return loc, nil
+}
+
+// FeaturesConfig 機能フラグの設定
+type FeaturesConfig struct {
+	Flags          map[string]bool `yaml:"flags"`           // 機能ごとの有効・無効（未設定の機能は無効）
+	RemoteURL      string          `yaml:"remote_url"`      // 機能フラグを取得するURL（取得した値はflagsより優先）
+	RefreshSeconds int             `yaml:"refresh_seconds"` // リモートの機能フラグを再取得する間隔（秒）
+}
+
 // Load 設定ファイルを読み込む
 func Load(configPath string) (*Config, error) {
 	// 設定ファイルが存在しない場合はデフォルト設定を返す
@@ -72,16 +543,32 @@ func DefaultConfig() *Config {
 	}
 
 	return &Config{
+		AI: AIConfig{
+			Provider: "anthropic",
+		},
 		Anthropic: AnthropicConfig{
 			APIKey:    os.Getenv("ANTHROPIC_API_KEY"),
 			Model:     "claude-haiku-4-5-20251001",
 			MaxTokens: 4096,
+			TaskMaxTokens: AnthropicTaskMaxTokens{
+				Receipt: 8192,
+			},
+			MaxTokensLimit: 32768,
+		},
+		Gemini: GeminiConfig{
+			APIKey:         os.Getenv("GEMINI_API_KEY"),
+			Model:          "gemini-2.5-flash",
+			MaxTokens:      8192,
+			MaxTokensLimit: 32768,
 		},
 		Redis: RedisConfig{
 			Host:     redisHost,
 			Port:     6379,
 			Password: "",
 			DB:       0,
+
+			FailureThreshold:     3,
+			ProbeIntervalSeconds: 5,
 		},
 		MySQL: MySQLConfig{
 			Host:     mysqlHost,
@@ -90,6 +577,229 @@ func DefaultConfig() *Config {
 			Password: os.Getenv("MYSQL_ROOT_PASSWORD"),
 			Database: "household",
 		},
+		Queue: QueueConfig{
+			Backend:       "memory",
+			Workers:       2,
+			BufferSize:    100,
+			Stream:        "vision:jobs",
+			Group:         "vision-workers",
+			MaxDeliveries: 5,
+		},
+		Storage: StorageConfig{
+			ImageDir: "data/images",
+			SpoolDir: "data/spool",
+		},
+		IDs: IDsConfig{
+			Strategy: "uuidv7",
+		},
+		Names: NamesConfig{
+			Normalize: true,
+		},
+		PurchaseDates: PurchaseDatesConfig{
+			MaxAgeYears:          10,
+			FutureToleranceHours: 24,
+		},
+		Backfills: BackfillsConfig{
+			BatchSize:           20,
+			BatchMaxMB:          32,
+			MaxReceipts:         5000,
+			PollIntervalMinutes: 5,
+		},
+		Uploads: UploadsConfig{
+			Secret:        os.Getenv("UPLOAD_SECRET"),
+			ExpiryMinutes: 15,
+			MaxSizeMB:     20,
+			S3: UploadsS3Config{
+				Region: "ap-northeast-1",
+			},
+		},
+		Drafts: DraftsConfig{
+			ExpiryMinutes: 60,
+		},
+		Trash: TrashConfig{
+			RetentionDays: 30,
+		},
+		Scanner: ScannerConfig{
+			Backend:        "none",
+			TimeoutSeconds: 30,
+		},
+		ImageURLs: ImageURLsConfig{
+			MaxSizeMB:      10,
+			TimeoutSeconds: 10,
+			MaxRedirects:   3,
+		},
+		Reports: ReportsConfig{
+			MonthStartDay:   1,
+			CacheTTLSeconds: 60,
+			Medical: MedicalReportConfig{
+				Categories:       []string{"医療費"},
+				Keywords:         []string{"病院", "クリニック", "医院", "歯科", "薬局", "調剤"},
+				PatientTagPrefix: "受診者:",
+				DefaultPatient:   "本人",
+			},
+			Ledger: LedgerConfig{
+				Accounts: map[string]string{
+					"食費":  "Expenses:Food",
+					"日用品": "Expenses:Household",
+					"交通費": "Expenses:Transportation",
+					"医療費": "Expenses:Medical",
+					"娯楽費": "Expenses:Entertainment",
+					"通信費": "Expenses:Communication",
+					"光熱費": "Expenses:Utilities",
+				},
+				DefaultAccount: "Expenses:Misc",
+				PaymentAccounts: map[string]string{
+					"クレジットカード": "Liabilities:CreditCard",
+					"電子マネー":    "Assets:EMoney",
+				},
+				PaymentAccount:    "Assets:Cash",
+				AdjustmentAccount: "Expenses:Tax",
+			},
+		},
+		Analytics: AnalyticsConfig{
+			ShoppingList: ShoppingListConfig{
+				LookbackDays: 180,
+				MinPurchases: 3,
+				HorizonDays:  7,
+			},
+			PublicStats: PublicStatsConfig{
+				MinContributors: 5,
+				RoundTo:         100,
+				MaxMonths:       12,
+				Share: SpendSharingConfig{
+					Categories:     []string{"食費"},
+					TimeoutSeconds: 10,
+				},
+			},
+		},
+		Warranties: WarrantiesConfig{
+			MinAmount:     10000,
+			DefaultMonths: 12,
+			ReturnDays:    14,
+			NotifyDays:    7,
+			Calendar: WarrantyCalendarConfig{
+				Secret: os.Getenv("CALENDAR_SECRET"),
+				Days:   365,
+			},
+		},
+		Reconciliation: ReconciliationConfig{
+			DateWindowDays: 5,
+			PaymentMethods: []string{"クレジットカード"},
+		},
+		Accounting: AccountingConfig{
+			SyncIntervalMinutes: 15,
+			MaxAttempts:         5,
+			BatchSize:           50,
+			TimeoutSeconds:      30,
+			Freee: AccountingProviderConfig{
+				BaseURL:  "https://api.freee.co.jp",
+				TokenURL: "https://accounts.secure.freee.co.jp/public_api/token",
+			},
+			MoneyForward: AccountingProviderConfig{
+				BaseURL:  "https://api-accounting.moneyforward.com",
+				TokenURL: "https://api.biz.moneyforward.com/token",
+			},
+		},
+		Warehouse: WarehouseConfig{
+			IntervalHours:  24,
+			LookbackMonths: 1,
+			Dir:            "data/warehouse",
+			S3: WarehouseS3Config{
+				Region: "ap-northeast-1",
+			},
+		},
+		Line: LineConfig{
+			APIBaseURL:     "https://api.line.me",
+			DataAPIBaseURL: "https://api-data.line.me",
+			Tags:           []string{"LINE"},
+			TimeoutSeconds: 30,
+		},
+		Notifications: NotificationsConfig{
+			TimeoutSeconds: 10,
+		},
+		Events: EventsConfig{
+			AuditLog: true,
+		},
+		Web: WebConfig{
+			UI: "spa",
+		},
+		Response: ResponseConfig{
+			FieldNaming:     "snake",
+			LocalizedFields: []string{"category", "payment_method"},
+			Translations: map[string]map[string]string{
+				"en": {
+					"食費":       "Food",
+					"日用品":      "Daily Necessities",
+					"医療費":      "Medical",
+					"娯楽費":      "Entertainment",
+					"交通費":      "Transportation",
+					"通信費":      "Communication",
+					"光熱費":      "Utilities",
+					"その他":      "Other",
+					"現金":       "Cash",
+					"クレジットカード": "Credit Card",
+					"電子マネー":    "E-Money",
+				},
+			},
+		},
+		Locale: LocaleConfig{
+			Timezone: "Asia/Tokyo",
+			Currency: "JPY",
+		},
+		Features: FeaturesConfig{
+			Flags: map[string]bool{
+				"direct_upload": true,
+			},
+			RefreshSeconds: 60,
+		},
+		Maintenance: MaintenanceConfig{
+			Message: "ただいまメンテナンス中です。しばらくしてから再度お試しください。",
+		},
+		SLO: SLOConfig{
+			Enabled:              true,
+			WindowMinutes:        60,
+			LatencyPercentile:    95,
+			LatencyThresholdMs:   15000,
+			SuccessTarget:        99,
+			MinRequests:          20,
+			CheckIntervalSeconds: 60,
+		},
+		CacheStats: CacheStatsConfig{
+			Enabled:      true,
+			TenantHeader: "X-Tenant-ID",
+			MaxSeries:    1000,
+			SampleKeys:   1000,
+		},
+		LoadShedding: LoadSheddingConfig{
+			Enabled:             true,
+			MaxInFlight:         100,
+			MaxQueue:            200,
+			QueueTimeoutSeconds: 10,
+			LargeRequestKB:      1024,
+			MaxLargeInFlight:    8,
+			RetryAfterSeconds:   5,
+		},
+		RepoCache: RepoCacheConfig{
+			Enabled:    true,
+			TTLSeconds: 30,
+		},
+		AIDebug: AIDebugConfig{
+			Capacity: 100,
+		},
+		AIBudget: AIBudgetConfig{
+			Downgrade: "rules",
+			Prices: map[string]AIModelPrice{
+				"claude-haiku-4-5-20251001": {InputPerMTok: 1, OutputPerMTok: 5},
+				"gemini-2.5-flash":          {InputPerMTok: 0.3, OutputPerMTok: 2.5},
+				"gemini-2.5-flash-lite":     {InputPerMTok: 0.1, OutputPerMTok: 0.4},
+			},
+			Rules: map[string][]string{
+				"食費":  {"牛乳", "パン", "卵", "弁当", "おにぎり", "野菜", "肉", "魚", "米"},
+				"日用品": {"洗剤", "ティッシュ", "トイレットペーパー", "シャンプー", "歯ブラシ"},
+				"医療費": {"薬", "湿布", "目薬", "マスク"},
+				"交通費": {"切符", "乗車券", "ガソリン", "駐車"},
+			},
+		},
 	}
 }
 
@@ -17,6 +17,10 @@ func TestDefaultConfig(t *testing.T) {
 		t.Error("Expected non-empty model")
 	}
 
+	if cfg.AI.Provider != "anthropic" || cfg.Gemini.Model == "" {
+		t.Errorf("Expected anthropic provider with gemini model, got %q, %q", cfg.AI.Provider, cfg.Gemini.Model)
+	}
+
 	if cfg.Redis.Port <= 0 {
 		t.Error("Expected positive Redis port")
 	}
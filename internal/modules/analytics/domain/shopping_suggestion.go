@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// ShoppingSuggestion 購入パターンから推定した買い物リストの候補
+type ShoppingSuggestion struct {
+	Name           string // 直近の購入時にレシートに印字された商品名
+	NormalizedName string
+	Purchases      int       // 購入した日数
+	IntervalDays   int       // 購入間隔（日数の中央値）
+	LastPurchased  time.Time // 最後に購入した日
+	NextPurchase   time.Time // 次に購入すると推定される日
+	LastPrice      int64     // 最後に購入したときの単価
+	LastStore      string    // 最後に購入した店舗
+}
+
+// Overdue 推定した購入日を過ぎているかチェック
+func (s *ShoppingSuggestion) Overdue(now time.Time) bool {
+	return s.NextPurchase.Before(now)
+}
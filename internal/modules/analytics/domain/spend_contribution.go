@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrInvalidContribution 統計に提供された集計値が不正
+var ErrInvalidContribution = errors.New("invalid spend contribution")
+
+// 提供された集計値の制約
+const (
+	maxContributorIDLength = 64
+	maxRegionLength        = 50
+)
+
+var (
+	contributorIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{16,64}$`)
+	monthPattern         = regexp.MustCompile(`^\d{4}-(0[1-9]|1[0-2])$`)
+	currencyPattern      = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// SpendContribution 統計への参加に同意した世帯が提供する、ある月の食費の合計
+// 世帯はランダムな識別子でのみ区別し、レシートの内容・店名など個人を推測できる情報は受け取らない
+type SpendContribution struct {
+	ContributorID string // 世帯ごとのランダムな識別子（同じ世帯・月の提供を置き換えるのに使う）
+	Region        string // 都道府県などの地域
+	Month         string // 対象月（YYYY-MM）
+	Amount        int64  // 月の食費の合計（通貨の最小単位）
+	Currency      string // ISO 4217の通貨コード
+	UpdatedAt     time.Time
+}
+
+// Validate 提供された集計値を検証し、地域の前後の空白を取り除く
+func (c *SpendContribution) Validate() error {
+	c.Region = strings.TrimSpace(c.Region)
+	switch {
+	case !contributorIDPattern.MatchString(c.ContributorID):
+		return fmt.Errorf("%w: contributor_id must be 16-%d letters, digits, '-' or '_'", ErrInvalidContribution, maxContributorIDLength)
+	case c.Region == "" || utf8.RuneCountInString(c.Region) > maxRegionLength:
+		return fmt.Errorf("%w: region must be 1-%d characters", ErrInvalidContribution, maxRegionLength)
+	case !monthPattern.MatchString(c.Month):
+		return fmt.Errorf("%w: month must be YYYY-MM", ErrInvalidContribution)
+	case c.Amount < 0:
+		return fmt.Errorf("%w: amount must not be negative", ErrInvalidContribution)
+	case !currencyPattern.MatchString(c.Currency):
+		return fmt.Errorf("%w: currency must be an ISO 4217 code", ErrInvalidContribution)
+	}
+	return nil
+}
+
+// SpendGroup 地域・月・通貨ごとの提供された集計値の合計（データベースで集計した結果）
+type SpendGroup struct {
+	Region       string
+	Month        string
+	Currency     string
+	Contributors int // 提供した世帯数
+	Total        int64
+}
+
+// RegionalSpend 公開する地域・月ごとの1世帯あたりの平均の食費
+type RegionalSpend struct {
+	Region        string
+	Month         string
+	Currency      string
+	Contributors  int
+	AverageAmount int64 // 通貨の最小単位（公開する単位に丸めた値）
+}
+
+// SpendContributionRepository 統計に提供された集計値のリポジトリのインターフェース
+type SpendContributionRepository interface {
+	// Save 集計値を保存（同じ世帯・月の集計値は地域を含めて上書き）
+	Save(ctx context.Context, contribution *SpendContribution) error
+	// Aggregate fromMonthからtoMonthまで（YYYY-MM）の集計値を地域・月・通貨ごとに合計（regionが空の場合はすべての地域）
+	Aggregate(ctx context.Context, fromMonth, toMonth, region string) ([]*SpendGroup, error)
+}
+
+// SpendContributionSink この世帯の集計値を統計を集計するサーバーに送る送信先のインターフェース
+type SpendContributionSink interface {
+	Contribute(ctx context.Context, contribution *SpendContribution) error
+}
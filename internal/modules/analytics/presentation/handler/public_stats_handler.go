@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"vision-api-app/internal/modules/analytics/domain"
+	"vision-api-app/internal/modules/analytics/usecase"
+)
+
+// maxContributionBodySize 提供された集計値のリクエストの上限
+const maxContributionBodySize = 4 << 10
+
+// PublicStatsHandler 地域・月ごとの平均の食費の公開統計APIのハンドラー
+type PublicStatsHandler struct {
+	publicStatsUseCase *usecase.PublicStatsUseCase
+}
+
+// NewPublicStatsHandler 新しいPublicStatsHandlerを作成
+func NewPublicStatsHandler(publicStatsUseCase *usecase.PublicStatsUseCase) *PublicStatsHandler {
+	return &PublicStatsHandler{
+		publicStatsUseCase: publicStatsUseCase,
+	}
+}
+
+// SpendContributionRequest 世帯から提供される集計値のリクエスト
+type SpendContributionRequest struct {
+	ContributorID string `json:"contributor_id"`
+	Region        string `json:"region"`
+	Month         string `json:"month"`  // YYYY-MM
+	Amount        int64  `json:"amount"` // 月の食費の合計（通貨の最小単位）
+	Currency      string `json:"currency"`
+}
+
+// RegionalSpendResponse 地域・月ごとの平均の食費のレスポンス
+type RegionalSpendResponse struct {
+	Region        string `json:"region"`
+	Month         string `json:"month"`
+	Currency      string `json:"currency"`
+	Contributors  int    `json:"contributors"`
+	AverageAmount int64  `json:"average_amount"`
+}
+
+// PublicStatsResponse 公開統計のレスポンス
+type PublicStatsResponse struct {
+	MinContributors int                     `json:"min_contributors"` // 公開に必要な提供した世帯数（満たない地域・月は含めない）
+	Stats           []RegionalSpendResponse `json:"stats"`
+}
+
+// HandleGetSpendStats 地域・月ごとの1世帯あたりの平均の食費を取得
+// ?from= と ?to=（YYYY-MM、省略した場合は前月）で期間、?region= で地域を指定する
+func (h *PublicStatsHandler) HandleGetSpendStats(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	now := time.Now()
+	previous := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, -1, 0).Format("2006-01")
+	from, to := query.Get("from"), query.Get("to")
+	if from == "" {
+		from = previous
+	}
+	if to == "" {
+		to = max(from, previous)
+	}
+
+	stats, err := h.publicStatsUseCase.RegionalSpend(r.Context(), from, to, query.Get("region"))
+	if errors.Is(err, usecase.ErrInvalidStatsPeriod) {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to get public spend stats", "error", err)
+		writeError(w, "Failed to get stats", http.StatusInternalServerError)
+		return
+	}
+
+	response := PublicStatsResponse{
+		MinContributors: h.publicStatsUseCase.MinContributors(),
+		Stats:           make([]RegionalSpendResponse, 0, len(stats)),
+	}
+	for _, s := range stats {
+		response.Stats = append(response.Stats, RegionalSpendResponse{
+			Region:        s.Region,
+			Month:         s.Month,
+			Currency:      s.Currency,
+			Contributors:  s.Contributors,
+			AverageAmount: s.AverageAmount,
+		})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// HandleContribute 参加に同意した世帯から月の食費の合計を受け付ける（同じ世帯・月の集計値は置き換える）
+func (h *PublicStatsHandler) HandleContribute(w http.ResponseWriter, r *http.Request) {
+	var req SpendContributionRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxContributionBodySize)).Decode(&req); err != nil {
+		writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	contribution := &domain.SpendContribution{
+		ContributorID: req.ContributorID,
+		Region:        req.Region,
+		Month:         req.Month,
+		Amount:        req.Amount,
+		Currency:      req.Currency,
+	}
+	err := h.publicStatsUseCase.Contribute(r.Context(), contribution)
+	if errors.Is(err, domain.ErrInvalidContribution) {
+		writeError(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to save spend contribution", "error", err)
+		writeError(w, "Failed to save contribution", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
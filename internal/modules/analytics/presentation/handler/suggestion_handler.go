@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"vision-api-app/internal/modules/analytics/domain"
+	"vision-api-app/internal/modules/analytics/usecase"
+)
+
+// maxHorizonDays 買い物リストの対象期間の上限（日数）
+const maxHorizonDays = 365
+
+// APIResponse 統一的なAPIレスポンス
+type APIResponse struct {
+	Success bool        `json:"success"`
+	Data    interface{} `json:"data,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// SuggestionHandler 購入パターンに基づく提案APIのハンドラー
+type SuggestionHandler struct {
+	shoppingListUseCase *usecase.ShoppingListUseCase
+}
+
+// NewSuggestionHandler 新しいSuggestionHandlerを作成
+func NewSuggestionHandler(shoppingListUseCase *usecase.ShoppingListUseCase) *SuggestionHandler {
+	return &SuggestionHandler{
+		shoppingListUseCase: shoppingListUseCase,
+	}
+}
+
+// ShoppingSuggestionResponse 買い物リストの候補のレスポンス
+type ShoppingSuggestionResponse struct {
+	Name           string    `json:"name"`
+	NormalizedName string    `json:"normalized_name"`
+	Purchases      int       `json:"purchases"`
+	IntervalDays   int       `json:"interval_days"`
+	LastPurchased  time.Time `json:"last_purchased"`
+	NextPurchase   time.Time `json:"next_purchase"`
+	Overdue        bool      `json:"overdue"` // 推定した購入日を過ぎている
+	LastPrice      int64     `json:"last_price"`
+	LastStore      string    `json:"last_store"`
+}
+
+// HandleShoppingList 購入パターンから推定した買い物リストを取得（?days= で何日先までを対象にするか指定）
+func (h *SuggestionHandler) HandleShoppingList(w http.ResponseWriter, r *http.Request) {
+	days := 0
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxHorizonDays {
+			writeError(w, "invalid days: "+v, http.StatusBadRequest)
+			return
+		}
+		days = n
+	}
+
+	suggestions, err := h.shoppingListUseCase.SuggestShoppingList(r.Context(), days)
+	if err != nil {
+		writeError(w, "Failed to suggest shopping list", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newShoppingListResponse(suggestions, time.Now()))
+}
+
+// newShoppingListResponse 買い物リストの候補からレスポンスを作成
+func newShoppingListResponse(suggestions []domain.ShoppingSuggestion, now time.Time) []ShoppingSuggestionResponse {
+	responses := make([]ShoppingSuggestionResponse, 0, len(suggestions))
+	for _, s := range suggestions {
+		responses = append(responses, ShoppingSuggestionResponse{
+			Name:           s.Name,
+			NormalizedName: s.NormalizedName,
+			Purchases:      s.Purchases,
+			IntervalDays:   s.IntervalDays,
+			LastPurchased:  s.LastPurchased,
+			NextPurchase:   s.NextPurchase,
+			Overdue:        s.Overdue(now),
+			LastPrice:      s.LastPrice,
+			LastStore:      s.LastStore,
+		})
+	}
+	return responses
+}
+
+// writeJSON 成功レスポンスを送信
+func writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(APIResponse{
+		Success: true,
+		Data:    data,
+	})
+}
+
+// writeError エラーレスポンスを送信
+func writeError(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(APIResponse{
+		Success: false,
+		Error:   message,
+	})
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"vision-api-app/internal/modules/analytics/domain"
+)
+
+// ErrInvalidStatsPeriod 公開統計の取得期間が不正
+var ErrInvalidStatsPeriod = errors.New("invalid stats period")
+
+// minContributorsFloor k-匿名性のしきい値の下限（設定が小さくても1〜2世帯の集計値は公開しない）
+const minContributorsFloor = 3
+
+// PublicStatsRules 公開統計のルール
+type PublicStatsRules struct {
+	MinContributors int   // k-匿名性のしきい値（提供した世帯数がこれ未満の地域・月は公開しない）
+	RoundTo         int64 // 平均を丸める単位（通貨の最小単位、1以下は丸めない）
+	MaxMonths       int   // 1回で取得できる月数の上限
+}
+
+// PublicStatsUseCase 参加に同意した世帯から提供された集計値を地域・月ごとに平均して公開するユースケース
+// 提供した世帯数がしきい値に満たない地域・月は結果に含めず、平均は丸めて公開する
+type PublicStatsUseCase struct {
+	repo  domain.SpendContributionRepository
+	rules PublicStatsRules
+	now   func() time.Time
+}
+
+// NewPublicStatsUseCase 新しいPublicStatsUseCaseを作成
+func NewPublicStatsUseCase(repo domain.SpendContributionRepository, rules PublicStatsRules) *PublicStatsUseCase {
+	rules.MinContributors = max(rules.MinContributors, minContributorsFloor)
+	if rules.MaxMonths <= 0 {
+		rules.MaxMonths = 12
+	}
+	return &PublicStatsUseCase{
+		repo:  repo,
+		rules: rules,
+		now:   time.Now,
+	}
+}
+
+// MinContributors 公開に必要な提供した世帯数
+func (uc *PublicStatsUseCase) MinContributors() int {
+	return uc.rules.MinContributors
+}
+
+// Contribute 世帯から提供された集計値を保存（同じ世帯・月の集計値は置き換える）
+// 集計の終わっていない翌月以降の集計値は受け付けない
+func (uc *PublicStatsUseCase) Contribute(ctx context.Context, contribution *domain.SpendContribution) error {
+	if err := contribution.Validate(); err != nil {
+		return err
+	}
+	now := uc.now()
+	if contribution.Month > now.Format("2006-01") {
+		return fmt.Errorf("%w: month %s is in the future", domain.ErrInvalidContribution, contribution.Month)
+	}
+
+	contribution.UpdatedAt = now
+	if err := uc.repo.Save(ctx, contribution); err != nil {
+		return fmt.Errorf("failed to save spend contribution: %w", err)
+	}
+	return nil
+}
+
+// RegionalSpend fromMonthからtoMonthまで（YYYY-MM）の地域・月ごとの1世帯あたりの平均の食費を取得（regionが空の場合はすべての地域）
+func (uc *PublicStatsUseCase) RegionalSpend(ctx context.Context, fromMonth, toMonth, region string) ([]domain.RegionalSpend, error) {
+	from, err := time.Parse("2006-01", fromMonth)
+	if err != nil {
+		return nil, fmt.Errorf("%w: from must be YYYY-MM", ErrInvalidStatsPeriod)
+	}
+	to, err := time.Parse("2006-01", toMonth)
+	if err != nil {
+		return nil, fmt.Errorf("%w: to must be YYYY-MM", ErrInvalidStatsPeriod)
+	}
+	months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1
+	if months < 1 || months > uc.rules.MaxMonths {
+		return nil, fmt.Errorf("%w: period must be 1-%d months", ErrInvalidStatsPeriod, uc.rules.MaxMonths)
+	}
+
+	groups, err := uc.repo.Aggregate(ctx, fromMonth, toMonth, region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate spend contributions: %w", err)
+	}
+
+	stats := make([]domain.RegionalSpend, 0, len(groups))
+	for _, g := range groups {
+		if g.Contributors < uc.rules.MinContributors {
+			continue
+		}
+		stats = append(stats, domain.RegionalSpend{
+			Region:        g.Region,
+			Month:         g.Month,
+			Currency:      g.Currency,
+			Contributors:  g.Contributors,
+			AverageAmount: roundTo(divRound(g.Total, int64(g.Contributors)), uc.rules.RoundTo),
+		})
+	}
+	return stats, nil
+}
+
+// divRound 0以上の整数の割り算を四捨五入する
+func divRound(total, n int64) int64 {
+	return (total + n/2) / n
+}
+
+// roundTo 0以上の整数を単位の倍数に四捨五入する（単位が1以下の場合はそのまま）
+func roundTo(amount, unit int64) int64 {
+	if unit <= 1 {
+		return amount
+	}
+	return divRound(amount, unit) * unit
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/analytics/domain"
+)
+
+// memorySpendContributionRepository 集計結果を返すだけの集計値のリポジトリ
+type memorySpendContributionRepository struct {
+	saved  []*domain.SpendContribution
+	groups []*domain.SpendGroup
+	from   string
+	to     string
+	region string
+}
+
+func (m *memorySpendContributionRepository) Save(ctx context.Context, contribution *domain.SpendContribution) error {
+	m.saved = append(m.saved, contribution)
+	return nil
+}
+
+func (m *memorySpendContributionRepository) Aggregate(ctx context.Context, fromMonth, toMonth, region string) ([]*domain.SpendGroup, error) {
+	m.from, m.to, m.region = fromMonth, toMonth, region
+	return m.groups, nil
+}
+
+func TestPublicStatsUseCase_RegionalSpend(t *testing.T) {
+	repo := &memorySpendContributionRepository{
+		groups: []*domain.SpendGroup{
+			{Region: "東京都", Month: "2024-03", Currency: "JPY", Contributors: 5, Total: 5*48000 + 240},
+			// しきい値未満の地域は公開しない
+			{Region: "鳥取県", Month: "2024-03", Currency: "JPY", Contributors: 4, Total: 4 * 40000},
+			{Region: "大阪府", Month: "2024-04", Currency: "JPY", Contributors: 6, Total: 6*41000 + 6*51},
+		},
+	}
+	uc := NewPublicStatsUseCase(repo, PublicStatsRules{MinContributors: 5, RoundTo: 100})
+
+	stats, err := uc.RegionalSpend(context.Background(), "2024-03", "2024-04", "")
+	if err != nil {
+		t.Fatalf("RegionalSpend() error = %v", err)
+	}
+	if repo.from != "2024-03" || repo.to != "2024-04" {
+		t.Errorf("Aggregate() period = %s..%s", repo.from, repo.to)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("got %d stats, want 2: %+v", len(stats), stats)
+	}
+	// 48048 → 48000、41051 → 41100（100円単位に四捨五入）
+	if stats[0].Region != "東京都" || stats[0].Contributors != 5 || stats[0].AverageAmount != 48000 {
+		t.Errorf("stats[0] = %+v, want 東京都 48000", stats[0])
+	}
+	if stats[1].Region != "大阪府" || stats[1].AverageAmount != 41100 {
+		t.Errorf("stats[1] = %+v, want 大阪府 41100", stats[1])
+	}
+}
+
+func TestPublicStatsUseCase_MinContributorsFloor(t *testing.T) {
+	repo := &memorySpendContributionRepository{
+		groups: []*domain.SpendGroup{
+			{Region: "東京都", Month: "2024-03", Currency: "JPY", Contributors: 2, Total: 80000},
+			{Region: "大阪府", Month: "2024-03", Currency: "JPY", Contributors: 3, Total: 90001},
+		},
+	}
+	// しきい値を1にしても1〜2世帯の集計値は公開しない
+	uc := NewPublicStatsUseCase(repo, PublicStatsRules{MinContributors: 1})
+	if uc.MinContributors() != 3 {
+		t.Errorf("MinContributors() = %d, want 3", uc.MinContributors())
+	}
+
+	stats, err := uc.RegionalSpend(context.Background(), "2024-03", "2024-03", "")
+	if err != nil {
+		t.Fatalf("RegionalSpend() error = %v", err)
+	}
+	if len(stats) != 1 || stats[0].Region != "大阪府" || stats[0].AverageAmount != 30000 {
+		t.Errorf("stats = %+v, want 大阪府 30000 only", stats)
+	}
+}
+
+func TestPublicStatsUseCase_RegionalSpend_InvalidPeriod(t *testing.T) {
+	uc := NewPublicStatsUseCase(&memorySpendContributionRepository{}, PublicStatsRules{MinContributors: 5, MaxMonths: 12})
+
+	tests := []struct {
+		name string
+		from string
+		to   string
+	}{
+		{name: "異常系: 月の形式の誤り", from: "2024-3", to: "2024-04"},
+		{name: "異常系: 終了月が開始月より前", from: "2024-04", to: "2024-03"},
+		{name: "異常系: 上限を超える期間", from: "2023-01", to: "2024-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.RegionalSpend(context.Background(), tt.from, tt.to, ""); !errors.Is(err, ErrInvalidStatsPeriod) {
+				t.Errorf("RegionalSpend() error = %v, want ErrInvalidStatsPeriod", err)
+			}
+		})
+	}
+}
+
+func TestPublicStatsUseCase_Contribute(t *testing.T) {
+	now := time.Date(2024, time.April, 10, 12, 0, 0, 0, time.UTC)
+	contribution := func(month string, amount int64) *domain.SpendContribution {
+		return &domain.SpendContribution{ContributorID: "household-0000001", Region: " 東京都 ", Month: month, Amount: amount, Currency: "JPY"}
+	}
+
+	tests := []struct {
+		name         string
+		contribution *domain.SpendContribution
+		wantErr      bool
+	}{
+		{name: "正常系: 前月", contribution: contribution("2024-03", 45000)},
+		{name: "正常系: 当月", contribution: contribution("2024-04", 20000)},
+		{name: "異常系: 翌月", contribution: contribution("2024-05", 45000), wantErr: true},
+		{name: "異常系: 負の金額", contribution: contribution("2024-03", -1), wantErr: true},
+		{name: "異常系: 短い識別子", contribution: &domain.SpendContribution{ContributorID: "short", Region: "東京都", Month: "2024-03", Currency: "JPY"}, wantErr: true},
+		{name: "異常系: 通貨の誤り", contribution: &domain.SpendContribution{ContributorID: "household-0000001", Region: "東京都", Month: "2024-03", Currency: "yen"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memorySpendContributionRepository{}
+			uc := NewPublicStatsUseCase(repo, PublicStatsRules{MinContributors: 5})
+			uc.now = func() time.Time { return now }
+
+			err := uc.Contribute(context.Background(), tt.contribution)
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrInvalidContribution) {
+					t.Errorf("Contribute() error = %v, want ErrInvalidContribution", err)
+				}
+				if len(repo.saved) != 0 {
+					t.Errorf("saved %d contributions, want none", len(repo.saved))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Contribute() error = %v", err)
+			}
+			if len(repo.saved) != 1 || repo.saved[0].Region != "東京都" || !repo.saved[0].UpdatedAt.Equal(now) {
+				t.Errorf("saved = %+v, want trimmed region and UpdatedAt", repo.saved)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"vision-api-app/internal/modules/analytics/domain"
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+)
+
+// ShoppingListRules 買い物リストの推定ルール
+type ShoppingListRules struct {
+	LookbackDays int // 購入パターンの分析に使う期間（日数）
+	MinPurchases int // 定期的に購入しているとみなす最小の購入日数
+	HorizonDays  int // 何日先までに購入が見込まれる商品を候補にするか
+}
+
+// ShoppingListUseCase 購入パターンから買い物リストを推定するユースケース
+type ShoppingListUseCase struct {
+	receiptRepo repository.ReceiptRepository
+	rules       ShoppingListRules
+	now         func() time.Time
+}
+
+// NewShoppingListUseCase 新しいShoppingListUseCaseを作成
+func NewShoppingListUseCase(receiptRepo repository.ReceiptRepository, rules ShoppingListRules) *ShoppingListUseCase {
+	return &ShoppingListUseCase{
+		receiptRepo: receiptRepo,
+		rules:       rules,
+		now:         time.Now,
+	}
+}
+
+// purchaseDay 商品を購入した1日分の記録
+type purchaseDay struct {
+	date  time.Time
+	name  string
+	price int64
+	store string
+}
+
+// SuggestShoppingList 定期的に購入している商品のうち、horizonDays日以内に購入が見込まれるものを推定日の近い順に返す
+// 商品名は entity.NormalizeItemName で正規化して集計する。horizonDaysが0以下の場合はルールの既定値を使う。
+// 次の購入日は最後の購入日に購入間隔の中央値を足して推定する（まとめ買いなどの外れ値の影響を抑えるため平均は使わない）
+func (uc *ShoppingListUseCase) SuggestShoppingList(ctx context.Context, horizonDays int) ([]domain.ShoppingSuggestion, error) {
+	if horizonDays <= 0 {
+		horizonDays = uc.rules.HorizonDays
+	}
+
+	now := uc.now()
+	today := truncateDay(now)
+	receipts, err := uc.receiptRepo.FindByDateRange(ctx, today.AddDate(0, 0, -uc.rules.LookbackDays), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find receipts: %w", err)
+	}
+
+	// 商品ごとに購入日をまとめる（同じ日の複数回の購入は1回として扱う）
+	history := make(map[string]map[time.Time]purchaseDay)
+	for _, receipt := range receipts {
+		day := truncateDay(receipt.PurchaseDate)
+		for _, item := range receipt.Items {
+			normalized := entity.NormalizeItemName(item.Name)
+			if normalized == "" {
+				continue
+			}
+			if history[normalized] == nil {
+				history[normalized] = make(map[time.Time]purchaseDay)
+			}
+			history[normalized][day] = purchaseDay{date: day, name: item.Name, price: item.Price, store: receipt.StoreName}
+		}
+	}
+
+	horizon := today.AddDate(0, 0, horizonDays)
+	suggestions := []domain.ShoppingSuggestion{}
+	for normalized, days := range history {
+		if len(days) < max(uc.rules.MinPurchases, 2) {
+			continue
+		}
+
+		purchases := make([]purchaseDay, 0, len(days))
+		for _, day := range days {
+			purchases = append(purchases, day)
+		}
+		sort.Slice(purchases, func(i, j int) bool {
+			return purchases[i].date.Before(purchases[j].date)
+		})
+
+		interval := medianIntervalDays(purchases)
+		last := purchases[len(purchases)-1]
+		next := last.date.AddDate(0, 0, interval)
+		if next.After(horizon) {
+			continue
+		}
+
+		suggestions = append(suggestions, domain.ShoppingSuggestion{
+			Name:           last.name,
+			NormalizedName: normalized,
+			Purchases:      len(purchases),
+			IntervalDays:   interval,
+			LastPurchased:  last.date,
+			NextPurchase:   next,
+			LastPrice:      last.price,
+			LastStore:      last.store,
+		})
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if !suggestions[i].NextPurchase.Equal(suggestions[j].NextPurchase) {
+			return suggestions[i].NextPurchase.Before(suggestions[j].NextPurchase)
+		}
+		return suggestions[i].NormalizedName < suggestions[j].NormalizedName
+	})
+	return suggestions, nil
+}
+
+// medianIntervalDays 購入日の間隔（日数）の中央値
+func medianIntervalDays(purchases []purchaseDay) int {
+	intervals := make([]int, 0, len(purchases)-1)
+	for i := 1; i < len(purchases); i++ {
+		intervals = append(intervals, int(purchases[i].date.Sub(purchases[i-1].date).Hours()/24+0.5))
+	}
+	sort.Ints(intervals)
+
+	mid := len(intervals) / 2
+	if len(intervals)%2 == 0 {
+		return (intervals[mid-1] + intervals[mid] + 1) / 2
+	}
+	return intervals[mid]
+}
+
+// truncateDay 日付の0時に切り捨てる
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
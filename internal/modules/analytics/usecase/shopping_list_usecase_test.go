@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+)
+
+// stubReceiptRepository 日付範囲の検索のみを実装したレシートリポジトリ
+type stubReceiptRepository struct {
+	repository.ReceiptRepository
+
+	receipts []*entity.Receipt
+	err      error
+	start    time.Time
+}
+
+func (s *stubReceiptRepository) FindByDateRange(ctx context.Context, start, end time.Time) ([]*entity.Receipt, error) {
+	s.start = start
+	return s.receipts, s.err
+}
+
+func TestShoppingListUseCase_SuggestShoppingList(t *testing.T) {
+	now := time.Date(2025, time.March, 1, 15, 0, 0, 0, time.Local)
+	daysAgo := func(days int) time.Time {
+		return time.Date(2025, time.March, 1, 10, 0, 0, 0, time.Local).AddDate(0, 0, -days)
+	}
+	receipt := func(storeName string, purchaseDate time.Time, items ...entity.ReceiptItem) *entity.Receipt {
+		return &entity.Receipt{StoreName: storeName, PurchaseDate: purchaseDate, Items: items}
+	}
+	item := func(name string, price int64) entity.ReceiptItem {
+		return entity.ReceiptItem{Name: name, Quantity: 1, Price: price}
+	}
+
+	repo := &stubReceiptRepository{
+		receipts: []*entity.Receipt{
+			// 牛乳: 7日ごと、最後は6日前 → 明日
+			receipt("スーパーA", daysAgo(27), item("牛乳", 210)),
+			receipt("スーパーA", daysAgo(20), item("牛乳", 210), item("洗剤", 298)),
+			receipt("スーパーA", daysAgo(13), item("牛乳", 215)),
+			// 同じ日の2回目の購入は1回として扱う
+			receipt("コンビニ", daysAgo(13), item("牛乳", 250)),
+			receipt("スーパーB", daysAgo(6), item("※ 牛乳", 198)),
+			// 卵: 間隔 10, 10, 30 日（中央値10日）、最後は12日前 → 2日前（期限切れ）
+			receipt("スーパーA", daysAgo(62), item("卵", 250)),
+			receipt("スーパーA", daysAgo(32), item("卵", 260)),
+			receipt("スーパーA", daysAgo(22), item("卵", 260), item("洗剤", 298)),
+			receipt("スーパーA", daysAgo(12), item("卵", 270)),
+			// 洗剤: 2回のみのため対象外
+			// パン: 30日ごと、最後は2日前 → 28日後（期間外）
+			receipt("パン屋", daysAgo(62), item("食パン", 300)),
+			receipt("パン屋", daysAgo(32), item("食パン", 300)),
+			receipt("パン屋", daysAgo(2), item("食パン", 320)),
+		},
+	}
+
+	uc := NewShoppingListUseCase(repo, ShoppingListRules{LookbackDays: 90, MinPurchases: 3, HorizonDays: 7})
+	uc.now = func() time.Time { return now }
+
+	suggestions, err := uc.SuggestShoppingList(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("SuggestShoppingList() error = %v", err)
+	}
+
+	if want := time.Date(2024, time.December, 1, 0, 0, 0, 0, time.Local); !repo.start.Equal(want) {
+		t.Errorf("start = %v, want %v", repo.start, want)
+	}
+
+	if len(suggestions) != 2 {
+		t.Fatalf("got %d suggestions, want 2: %+v", len(suggestions), suggestions)
+	}
+
+	egg := suggestions[0]
+	if egg.NormalizedName != "卵" || egg.Purchases != 4 || egg.IntervalDays != 10 || egg.LastPrice != 270 {
+		t.Errorf("suggestions[0] = %+v, want 卵", egg)
+	}
+	if !egg.Overdue(now) || !egg.NextPurchase.Equal(time.Date(2025, time.February, 27, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("卵 NextPurchase = %v, want 2025-02-27 (overdue)", egg.NextPurchase)
+	}
+
+	milk := suggestions[1]
+	if milk.NormalizedName != "牛乳" || milk.Name != "※ 牛乳" || milk.Purchases != 4 || milk.IntervalDays != 7 || milk.LastStore != "スーパーB" {
+		t.Errorf("suggestions[1] = %+v, want 牛乳", milk)
+	}
+	if milk.Overdue(now) || !milk.NextPurchase.Equal(time.Date(2025, time.March, 2, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("牛乳 NextPurchase = %v, want 2025-03-02", milk.NextPurchase)
+	}
+
+	// 期間を延ばすとパンも候補になる
+	suggestions, err = uc.SuggestShoppingList(context.Background(), 30)
+	if err != nil {
+		t.Fatalf("SuggestShoppingList() error = %v", err)
+	}
+	if len(suggestions) != 3 || suggestions[2].NormalizedName != "食パン" {
+		t.Errorf("SuggestShoppingList(30) = %+v, want 3 suggestions ending with 食パン", suggestions)
+	}
+}
+
+func TestShoppingListUseCase_SuggestShoppingList_Error(t *testing.T) {
+	repo := &stubReceiptRepository{err: errors.New("db error")}
+	uc := NewShoppingListUseCase(repo, ShoppingListRules{LookbackDays: 90, MinPurchases: 3, HorizonDays: 7})
+
+	if _, err := uc.SuggestShoppingList(context.Background(), 0); err == nil {
+		t.Error("SuggestShoppingList() error = nil, want error")
+	}
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"vision-api-app/internal/modules/analytics/domain"
+	"vision-api-app/internal/modules/household/domain/repository"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// SpendSharingRules この世帯の集計値を公開統計に提供するルール
+type SpendSharingRules struct {
+	ContributorID string   // 世帯ごとのランダムな識別子
+	Region        string   // 提供する地域
+	Categories    []string // 食費として合計するカテゴリー
+	Currency      string   // 金額の通貨
+}
+
+// SpendSharingUseCase 参加に同意した世帯の月の食費の合計を、統計を集計するサーバーに提供するユースケース
+// 提供するのは地域・月・合計金額のみで、レシートの内容・店名は送らない
+type SpendSharingUseCase struct {
+	aggregateRepo repository.AggregateRepository
+	sink          domain.SpendContributionSink
+	rules         SpendSharingRules
+	now           func() time.Time
+}
+
+// NewSpendSharingUseCase 新しいSpendSharingUseCaseを作成
+func NewSpendSharingUseCase(aggregateRepo repository.AggregateRepository, sink domain.SpendContributionSink, rules SpendSharingRules) *SpendSharingUseCase {
+	return &SpendSharingUseCase{
+		aggregateRepo: aggregateRepo,
+		sink:          sink,
+		rules:         rules,
+		now:           time.Now,
+	}
+}
+
+// SharePreviousMonth 前月の食費の合計を提供する（集計の終わった月のみ提供するため当月は送らない）
+func (uc *SpendSharingUseCase) SharePreviousMonth(ctx context.Context) (*domain.SpendContribution, error) {
+	now := uc.now().In(sharedDomain.LocationFromContext(ctx))
+	previous := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, -1, 0)
+	return uc.ShareMonth(ctx, previous.Year(), int(previous.Month()))
+}
+
+// ShareMonth 指定月の食費の合計（明細と家計簿エントリのうち対象のカテゴリー）を提供する
+// 食費の記録がない月は平均を下げないよう提供せず、nilを返す
+func (uc *SpendSharingUseCase) ShareMonth(ctx context.Context, year, month int) (*domain.SpendContribution, error) {
+	loc := sharedDomain.LocationFromContext(ctx)
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, loc)
+	end := start.AddDate(0, 1, 0).Add(-time.Nanosecond)
+
+	items, err := uc.aggregateRepo.SumItemsByCategory(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum receipt items: %w", err)
+	}
+	expenses, err := uc.aggregateRepo.SumExpensesByCategory(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum expense entries: %w", err)
+	}
+
+	var total int64
+	for _, amount := range append(items, expenses...) {
+		if slices.Contains(uc.rules.Categories, amount.Category) {
+			total += amount.Total
+		}
+	}
+	if total <= 0 {
+		return nil, nil
+	}
+
+	contribution := &domain.SpendContribution{
+		ContributorID: uc.rules.ContributorID,
+		Region:        uc.rules.Region,
+		Month:         start.Format("2006-01"),
+		Amount:        total,
+		Currency:      uc.rules.Currency,
+	}
+	if err := contribution.Validate(); err != nil {
+		return nil, err
+	}
+	if err := uc.sink.Contribute(ctx, contribution); err != nil {
+		return nil, fmt.Errorf("failed to share spend contribution: %w", err)
+	}
+	return contribution, nil
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/analytics/domain"
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// stubAggregateRepository カテゴリーごとの合計を返す集計リポジトリ
+type stubAggregateRepository struct {
+	repository.AggregateRepository
+
+	items    []*entity.CategoryAmount
+	expenses []*entity.CategoryAmount
+	start    time.Time
+	end      time.Time
+}
+
+func (s *stubAggregateRepository) SumItemsByCategory(ctx context.Context, start, end time.Time) ([]*entity.CategoryAmount, error) {
+	s.start, s.end = start, end
+	return s.items, nil
+}
+
+func (s *stubAggregateRepository) SumExpensesByCategory(ctx context.Context, start, end time.Time) ([]*entity.CategoryAmount, error) {
+	return s.expenses, nil
+}
+
+// recordingSink 送信した集計値を記録する送信先
+type recordingSink struct {
+	contributions []*domain.SpendContribution
+}
+
+func (s *recordingSink) Contribute(ctx context.Context, contribution *domain.SpendContribution) error {
+	s.contributions = append(s.contributions, contribution)
+	return nil
+}
+
+func TestSpendSharingUseCase_SharePreviousMonth(t *testing.T) {
+	loc := time.FixedZone("JST", 9*60*60)
+	ctx := sharedDomain.WithLocation(context.Background(), loc)
+
+	aggregateRepo := &stubAggregateRepository{
+		items: []*entity.CategoryAmount{
+			{Category: "食費", Total: 30000},
+			{Category: "日用品", Total: 5000},
+			{Category: "食費", Total: 12000},
+		},
+		expenses: []*entity.CategoryAmount{
+			{Category: "食費", Total: 3000},
+			{Category: "交通費", Total: 8000},
+		},
+	}
+	sink := &recordingSink{}
+	uc := NewSpendSharingUseCase(aggregateRepo, sink, SpendSharingRules{
+		ContributorID: "household-0000001",
+		Region:        "東京都",
+		Categories:    []string{"食費"},
+		Currency:      "JPY",
+	})
+	// UTCでは3月31日だが、JSTでは4月1日のため3月分を提供する
+	uc.now = func() time.Time { return time.Date(2024, time.March, 31, 16, 0, 0, 0, time.UTC) }
+
+	contribution, err := uc.SharePreviousMonth(ctx)
+	if err != nil {
+		t.Fatalf("SharePreviousMonth() error = %v", err)
+	}
+	if want := time.Date(2024, time.March, 1, 0, 0, 0, 0, loc); !aggregateRepo.start.Equal(want) {
+		t.Errorf("start = %v, want %v", aggregateRepo.start, want)
+	}
+	if want := time.Date(2024, time.April, 1, 0, 0, 0, 0, loc); !aggregateRepo.end.Before(want) || aggregateRepo.end.Add(time.Second).Before(want) {
+		t.Errorf("end = %v, want just before %v", aggregateRepo.end, want)
+	}
+	if contribution == nil || contribution.Month != "2024-03" || contribution.Amount != 45000 || contribution.Region != "東京都" {
+		t.Fatalf("contribution = %+v, want 2024-03 45000", contribution)
+	}
+	if len(sink.contributions) != 1 {
+		t.Errorf("sent %d contributions, want 1", len(sink.contributions))
+	}
+}
+
+func TestSpendSharingUseCase_ShareMonth_NoSpend(t *testing.T) {
+	aggregateRepo := &stubAggregateRepository{
+		items: []*entity.CategoryAmount{{Category: "日用品", Total: 5000}},
+	}
+	sink := &recordingSink{}
+	uc := NewSpendSharingUseCase(aggregateRepo, sink, SpendSharingRules{
+		ContributorID: "household-0000001",
+		Region:        "東京都",
+		Categories:    []string{"食費"},
+		Currency:      "JPY",
+	})
+
+	// 食費の記録がない月は平均を下げないよう提供しない
+	contribution, err := uc.ShareMonth(context.Background(), 2024, 3)
+	if err != nil {
+		t.Fatalf("ShareMonth() error = %v", err)
+	}
+	if contribution != nil || len(sink.contributions) != 0 {
+		t.Errorf("contribution = %+v, sent %d, want none", contribution, len(sink.contributions))
+	}
+}
@@ -0,0 +1,23 @@
+package domain
+
+// 承認者の役割
+const (
+	RoleApprover   = "approver"   // 申請の承認・差し戻し
+	RoleAccountant = "accountant" // 承認済みの報告書の精算
+)
+
+// Approver 経費報告書の承認フローを進める承認者
+type Approver struct {
+	Name string
+	Role string // RoleApprover・RoleAccountant
+}
+
+// CanApprove 申請を承認・差し戻しできるかチェック
+func (a Approver) CanApprove() bool {
+	return a.Role == RoleApprover
+}
+
+// CanReimburse 承認済みの報告書を精算済みにできるかチェック
+func (a Approver) CanReimburse() bool {
+	return a.Role == RoleAccountant
+}
@@ -0,0 +1,165 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// 経費報告書の状態
+const (
+	StatusDraft      = "draft"      // 作成中（レシートの追加・削除ができる）
+	StatusSubmitted  = "submitted"  // 申請済み（承認待ち）
+	StatusApproved   = "approved"   // 承認済み（精算待ち）
+	StatusReimbursed = "reimbursed" // 精算済み
+)
+
+// Statuses 経費報告書の状態（承認フローの順）
+var Statuses = []string{StatusDraft, StatusSubmitted, StatusApproved, StatusReimbursed}
+
+var (
+	// ErrInvalidReport 経費報告書の指定が不正（件名・申請者が空、レシートの重複など）
+	ErrInvalidReport = errors.New("invalid expense report")
+	// ErrInvalidTransition 現在の状態からは実行できない操作（申請済みの報告書の編集、未承認の報告書の精算など）
+	ErrInvalidTransition = errors.New("invalid expense report status transition")
+	// ErrNotPermitted 承認者の役割では実行できない操作（経理担当者による承認、申請者自身による承認など）
+	ErrNotPermitted = errors.New("approver is not permitted")
+)
+
+// ExpenseReport 経費報告書エンティティ
+// 会社の経費として精算するレシートをまとめ、作成中→申請済み→承認済み→精算済みの順に承認フローを進める。
+// 差し戻した報告書は作成中に戻り、修正して申請し直せる
+type ExpenseReport struct {
+	ID           string
+	Title        string
+	Applicant    string   // 申請者
+	Status       string   // 状態（StatusDraftなど）
+	ReceiptIDs   []string // 含めるレシート（1件のレシートは1つの報告書にのみ含められる）
+	TotalAmount  int64    // 申請時に確定した合計金額（通貨の最小単位、作成中は0）
+	Approver     string   // 承認・差し戻しした承認者
+	ReimbursedBy string   // 精算した経理担当者
+	Comment      string   // 承認・差し戻しのコメント
+	SubmittedAt  *time.Time
+	ApprovedAt   *time.Time
+	ReimbursedAt *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// NewExpenseReport 作成中の経費報告書を作成
+func NewExpenseReport(id, title, applicant string, receiptIDs []string, now time.Time) (*ExpenseReport, error) {
+	report := &ExpenseReport{
+		ID:        id,
+		Status:    StatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := report.Edit(title, applicant, receiptIDs, now); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Editable 件名・レシートを変更できるかチェック（作成中のみ）
+func (r *ExpenseReport) Editable() bool {
+	return r.Status == StatusDraft
+}
+
+// Edit 件名・申請者・含めるレシートを変更
+func (r *ExpenseReport) Edit(title, applicant string, receiptIDs []string, now time.Time) error {
+	if !r.Editable() {
+		return fmt.Errorf("%w: %s report cannot be edited", ErrInvalidTransition, r.Status)
+	}
+	title, applicant = strings.TrimSpace(title), strings.TrimSpace(applicant)
+	if title == "" || applicant == "" {
+		return fmt.Errorf("%w: title and applicant are required", ErrInvalidReport)
+	}
+	ids := make([]string, 0, len(receiptIDs))
+	for _, id := range receiptIDs {
+		if id == "" || slices.Contains(ids, id) {
+			return fmt.Errorf("%w: empty or duplicate receipt id: %q", ErrInvalidReport, id)
+		}
+		ids = append(ids, id)
+	}
+
+	r.Title = title
+	r.Applicant = applicant
+	r.ReceiptIDs = ids
+	r.UpdatedAt = now
+	return nil
+}
+
+// Submit 申請する（合計金額は申請時のレシートの合計で確定する）
+func (r *ExpenseReport) Submit(totalAmount int64, now time.Time) error {
+	if r.Status != StatusDraft {
+		return fmt.Errorf("%w: %s report cannot be submitted", ErrInvalidTransition, r.Status)
+	}
+	if len(r.ReceiptIDs) == 0 {
+		return fmt.Errorf("%w: report has no receipts", ErrInvalidReport)
+	}
+	r.Status = StatusSubmitted
+	r.TotalAmount = totalAmount
+	r.Approver = ""
+	r.Comment = ""
+	r.SubmittedAt = &now
+	r.UpdatedAt = now
+	return nil
+}
+
+// Approve 申請を承認する（承認者の役割が必要で、申請者自身は承認できない）
+func (r *ExpenseReport) Approve(approver Approver, comment string, now time.Time) error {
+	if err := r.review(approver); err != nil {
+		return err
+	}
+	r.Status = StatusApproved
+	r.Approver = approver.Name
+	r.Comment = strings.TrimSpace(comment)
+	r.ApprovedAt = &now
+	r.UpdatedAt = now
+	return nil
+}
+
+// Reject 申請を差し戻して作成中に戻す（確定した合計金額は取り消す）
+func (r *ExpenseReport) Reject(approver Approver, comment string, now time.Time) error {
+	if err := r.review(approver); err != nil {
+		return err
+	}
+	r.Status = StatusDraft
+	r.TotalAmount = 0
+	r.Approver = approver.Name
+	r.Comment = strings.TrimSpace(comment)
+	r.SubmittedAt = nil
+	r.UpdatedAt = now
+	return nil
+}
+
+// review 申請済みの報告書を承認・差し戻しできるかチェック
+func (r *ExpenseReport) review(approver Approver) error {
+	if r.Status != StatusSubmitted {
+		return fmt.Errorf("%w: %s report cannot be reviewed", ErrInvalidTransition, r.Status)
+	}
+	if !approver.CanApprove() {
+		return fmt.Errorf("%w: %s cannot approve reports", ErrNotPermitted, approver.Name)
+	}
+	if approver.Name == r.Applicant {
+		return fmt.Errorf("%w: applicant cannot review own report", ErrNotPermitted)
+	}
+	return nil
+}
+
+// Reimburse 承認済みの報告書を精算済みにする（経理担当者の役割が必要）
+func (r *ExpenseReport) Reimburse(approver Approver, now time.Time) error {
+	if r.Status != StatusApproved {
+		return fmt.Errorf("%w: %s report cannot be reimbursed", ErrInvalidTransition, r.Status)
+	}
+	if !approver.CanReimburse() {
+		return fmt.Errorf("%w: %s cannot reimburse reports", ErrNotPermitted, approver.Name)
+	}
+	r.Status = StatusReimbursed
+	r.ReimbursedBy = approver.Name
+	r.ReimbursedAt = &now
+	r.UpdatedAt = now
+	return nil
+}
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"context"
+
+	"vision-api-app/internal/modules/household/domain/repository"
+)
+
+// ExpenseReportRepository 経費報告書リポジトリのインターフェース
+type ExpenseReportRepository interface {
+	repository.CRUDRepository[ExpenseReport]
+	// FindAll 経費報告書を更新日時の新しい順に取得（statusが空の場合はすべての状態）
+	FindAll(ctx context.Context, status string, limit, offset int) ([]*ExpenseReport, error)
+	// FindByReceiptIDs 指定したレシートのいずれかを含む経費報告書を取得（他の報告書に含まれるレシートの検出に使う）
+	FindByReceiptIDs(ctx context.Context, receiptIDs []string) ([]*ExpenseReport, error)
+}
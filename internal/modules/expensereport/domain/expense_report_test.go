@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewExpenseReport(t *testing.T) {
+	now := time.Date(2025, time.March, 1, 10, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name       string
+		title      string
+		applicant  string
+		receiptIDs []string
+		wantErr    bool
+	}{
+		{name: "レシートなしで作成", title: "3月 出張", applicant: "山田"},
+		{name: "件名の前後の空白は除く", title: " 3月 出張 ", applicant: "山田", receiptIDs: []string{"r1", "r2"}},
+		{name: "件名が空", title: " ", applicant: "山田", wantErr: true},
+		{name: "申請者が空", title: "3月 出張", applicant: "", wantErr: true},
+		{name: "レシートの重複", title: "3月 出張", applicant: "山田", receiptIDs: []string{"r1", "r1"}, wantErr: true},
+		{name: "空のレシートID", title: "3月 出張", applicant: "山田", receiptIDs: []string{""}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := NewExpenseReport("report-1", tt.title, tt.applicant, tt.receiptIDs, now)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidReport) {
+					t.Errorf("NewExpenseReport() error = %v, want %v", err, ErrInvalidReport)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewExpenseReport() error = %v", err)
+			}
+			if report.Status != StatusDraft || report.Title != "3月 出張" || len(report.ReceiptIDs) != len(tt.receiptIDs) {
+				t.Errorf("NewExpenseReport() = %+v", report)
+			}
+		})
+	}
+}
+
+func TestExpenseReport_Workflow(t *testing.T) {
+	now := time.Date(2025, time.March, 1, 10, 0, 0, 0, time.Local)
+	approver := Approver{Name: "佐藤", Role: RoleApprover}
+	accountant := Approver{Name: "鈴木", Role: RoleAccountant}
+
+	report, err := NewExpenseReport("report-1", "3月 出張", "山田", []string{"r1"}, now)
+	if err != nil {
+		t.Fatalf("NewExpenseReport() error = %v", err)
+	}
+
+	// 作成中の報告書は承認・精算できない
+	if err := report.Approve(approver, "", now); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Approve() before submit error = %v, want %v", err, ErrInvalidTransition)
+	}
+	if err := report.Reimburse(accountant, now); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Reimburse() before approve error = %v, want %v", err, ErrInvalidTransition)
+	}
+
+	if err := report.Submit(12000, now); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if report.Status != StatusSubmitted || report.TotalAmount != 12000 || report.SubmittedAt == nil {
+		t.Errorf("Submit() = %+v", report)
+	}
+	// 申請済みの報告書は編集できない
+	if err := report.Edit("変更", "山田", nil, now); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Edit() after submit error = %v, want %v", err, ErrInvalidTransition)
+	}
+
+	// 差し戻すと作成中に戻り、合計金額は取り消す
+	if err := report.Reject(approver, " 領収書が不足 ", now); err != nil {
+		t.Fatalf("Reject() error = %v", err)
+	}
+	if report.Status != StatusDraft || report.TotalAmount != 0 || report.SubmittedAt != nil || report.Comment != "領収書が不足" {
+		t.Errorf("Reject() = %+v", report)
+	}
+
+	if err := report.Submit(15000, now); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	// 申請を承認できるのは承認者の役割のみ
+	if err := report.Approve(accountant, "", now); !errors.Is(err, ErrNotPermitted) {
+		t.Errorf("Approve() by accountant error = %v, want %v", err, ErrNotPermitted)
+	}
+	if err := report.Approve(approver, "", now); err != nil {
+		t.Fatalf("Approve() error = %v", err)
+	}
+	if report.Status != StatusApproved || report.Approver != "佐藤" || report.Comment != "" || report.ApprovedAt == nil {
+		t.Errorf("Approve() = %+v", report)
+	}
+
+	// 精算できるのは経理担当者の役割のみ
+	if err := report.Reimburse(approver, now); !errors.Is(err, ErrNotPermitted) {
+		t.Errorf("Reimburse() by approver error = %v, want %v", err, ErrNotPermitted)
+	}
+	if err := report.Reimburse(accountant, now); err != nil {
+		t.Fatalf("Reimburse() error = %v", err)
+	}
+	if report.Status != StatusReimbursed || report.ReimbursedBy != "鈴木" || report.ReimbursedAt == nil {
+		t.Errorf("Reimburse() = %+v", report)
+	}
+}
+
+func TestExpenseReport_Rules(t *testing.T) {
+	now := time.Date(2025, time.March, 1, 10, 0, 0, 0, time.Local)
+
+	// レシートのない報告書は申請できない
+	empty, _ := NewExpenseReport("report-1", "3月 出張", "山田", nil, now)
+	if err := empty.Submit(0, now); !errors.Is(err, ErrInvalidReport) {
+		t.Errorf("Submit() without receipts error = %v, want %v", err, ErrInvalidReport)
+	}
+
+	// 申請者自身は承認できない
+	report, _ := NewExpenseReport("report-2", "3月 出張", "佐藤", []string{"r1"}, now)
+	_ = report.Submit(1000, now)
+	if err := report.Approve(Approver{Name: "佐藤", Role: RoleApprover}, "", now); !errors.Is(err, ErrNotPermitted) {
+		t.Errorf("Approve() by applicant error = %v, want %v", err, ErrNotPermitted)
+	}
+}
@@ -0,0 +1,341 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"vision-api-app/internal/modules/expensereport/domain"
+	"vision-api-app/internal/modules/expensereport/usecase"
+	"vision-api-app/internal/modules/household/domain/entity"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// 一覧のページサイズ
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// approverKeyHeader 承認者を認証するキーを指定するヘッダー
+const approverKeyHeader = "X-Approver-Key"
+
+// APIResponse 統一的なAPIレスポンス
+type APIResponse struct {
+	Success bool        `json:"success"`
+	Data    interface{} `json:"data,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// ExpenseReportHandler 経費報告書APIのハンドラー
+type ExpenseReportHandler struct {
+	reportUseCase *usecase.ExpenseReportUseCase
+	currency      sharedDomain.Currency
+}
+
+// NewExpenseReportHandler 新しいExpenseReportHandlerを作成（currencyはPDFの金額の表示に使う）
+func NewExpenseReportHandler(reportUseCase *usecase.ExpenseReportUseCase, currency sharedDomain.Currency) *ExpenseReportHandler {
+	return &ExpenseReportHandler{
+		reportUseCase: reportUseCase,
+		currency:      currency,
+	}
+}
+
+// createReportRequest 経費報告書の作成リクエスト
+type createReportRequest struct {
+	Title      string   `json:"title"`
+	Applicant  string   `json:"applicant"`
+	ReceiptIDs []string `json:"receipt_ids"`
+}
+
+// patchReportRequest 経費報告書の部分更新リクエスト（省略した項目は変更しない）
+type patchReportRequest struct {
+	Title      *string   `json:"title"`
+	Applicant  *string   `json:"applicant"`
+	ReceiptIDs *[]string `json:"receipt_ids"`
+}
+
+// reviewRequest 承認・差し戻しのリクエスト
+type reviewRequest struct {
+	Comment string `json:"comment"`
+}
+
+// ExpenseReportResponse 経費報告書のレスポンス
+type ExpenseReportResponse struct {
+	ID           string                   `json:"id"`
+	Title        string                   `json:"title"`
+	Applicant    string                   `json:"applicant"`
+	Status       string                   `json:"status"`
+	ReceiptIDs   []string                 `json:"receipt_ids"`
+	Receipts     []ExpenseReceiptResponse `json:"receipts,omitempty"` // 含まれるレシートの概要（取得時のみ）
+	TotalAmount  int64                    `json:"total_amount"`       // 申請時に確定した合計金額（作成中は0）
+	Approver     string                   `json:"approver,omitempty"`
+	ReimbursedBy string                   `json:"reimbursed_by,omitempty"`
+	Comment      string                   `json:"comment,omitempty"`
+	SubmittedAt  *time.Time               `json:"submitted_at,omitempty"`
+	ApprovedAt   *time.Time               `json:"approved_at,omitempty"`
+	ReimbursedAt *time.Time               `json:"reimbursed_at,omitempty"`
+	CreatedAt    time.Time                `json:"created_at"`
+	UpdatedAt    time.Time                `json:"updated_at"`
+}
+
+// ExpenseReceiptResponse 経費報告書に含まれるレシートの概要
+type ExpenseReceiptResponse struct {
+	ID           string    `json:"id"`
+	StoreName    string    `json:"store_name"`
+	PurchaseDate time.Time `json:"purchase_date"`
+	TotalAmount  int64     `json:"total_amount"`
+	Refund       bool      `json:"refund"`
+	NeedsReview  bool      `json:"needs_review"`
+}
+
+// HandleList 経費報告書を更新日時の新しい順に取得（?status= で状態を指定）
+func (h *ExpenseReportHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	reports, err := h.reportUseCase.ListReports(r.Context(), r.URL.Query().Get("status"), limit, offset)
+	if err != nil {
+		writeReportError(w, err, "Failed to list expense reports")
+		return
+	}
+
+	responses := make([]ExpenseReportResponse, 0, len(reports))
+	for _, report := range reports {
+		responses = append(responses, newExpenseReportResponse(report, nil))
+	}
+	writeJSON(w, http.StatusOK, responses)
+}
+
+// HandleCreate 作成中の経費報告書を作成
+func (h *ExpenseReportHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	var req createReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.reportUseCase.CreateReport(r.Context(), req.Title, req.Applicant, req.ReceiptIDs)
+	if err != nil {
+		writeReportError(w, err, "Failed to create expense report")
+		return
+	}
+	h.writeReport(w, r, http.StatusCreated, report)
+}
+
+// HandleGet 経費報告書を含まれるレシートの概要とともに取得
+func (h *ExpenseReportHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	report, err := h.reportUseCase.GetReport(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeReportError(w, err, "Failed to get expense report")
+		return
+	}
+	h.writeReport(w, r, http.StatusOK, report)
+}
+
+// HandlePatch 作成中の経費報告書の件名・申請者・レシートを変更
+func (h *ExpenseReportHandler) HandlePatch(w http.ResponseWriter, r *http.Request) {
+	var req patchReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.reportUseCase.UpdateReport(r.Context(), r.PathValue("id"), usecase.ReportPatch{
+		Title:      req.Title,
+		Applicant:  req.Applicant,
+		ReceiptIDs: req.ReceiptIDs,
+	})
+	if err != nil {
+		writeReportError(w, err, "Failed to update expense report")
+		return
+	}
+	h.writeReport(w, r, http.StatusOK, report)
+}
+
+// HandleDelete 作成中の経費報告書を削除
+func (h *ExpenseReportHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	if err := h.reportUseCase.DeleteReport(r.Context(), r.PathValue("id")); err != nil {
+		writeReportError(w, err, "Failed to delete expense report")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleSubmit 経費報告書を申請する
+func (h *ExpenseReportHandler) HandleSubmit(w http.ResponseWriter, r *http.Request) {
+	report, err := h.reportUseCase.SubmitReport(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeReportError(w, err, "Failed to submit expense report")
+		return
+	}
+	h.writeReport(w, r, http.StatusOK, report)
+}
+
+// HandleApprove 申請済みの経費報告書を承認する（承認者のキーが必要）
+func (h *ExpenseReportHandler) HandleApprove(w http.ResponseWriter, r *http.Request) {
+	h.handleReview(w, r, h.reportUseCase.ApproveReport)
+}
+
+// HandleReject 申請済みの経費報告書を差し戻す（承認者のキーが必要）
+func (h *ExpenseReportHandler) HandleReject(w http.ResponseWriter, r *http.Request) {
+	h.handleReview(w, r, h.reportUseCase.RejectReport)
+}
+
+// handleReview 承認・差し戻しの共通処理（コメントは省略できる）
+func (h *ExpenseReportHandler) handleReview(w http.ResponseWriter, r *http.Request, review func(ctx context.Context, id string, approver domain.Approver, comment string) (*domain.ExpenseReport, error)) {
+	approver, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+	var req reviewRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	report, err := review(r.Context(), r.PathValue("id"), approver, req.Comment)
+	if err != nil {
+		writeReportError(w, err, "Failed to review expense report")
+		return
+	}
+	h.writeReport(w, r, http.StatusOK, report)
+}
+
+// HandleReimburse 承認済みの経費報告書を精算済みにする（経理担当者のキーが必要）
+func (h *ExpenseReportHandler) HandleReimburse(w http.ResponseWriter, r *http.Request) {
+	approver, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.reportUseCase.ReimburseReport(r.Context(), r.PathValue("id"), approver)
+	if err != nil {
+		writeReportError(w, err, "Failed to reimburse expense report")
+		return
+	}
+	h.writeReport(w, r, http.StatusOK, report)
+}
+
+// authenticate X-Approver-Keyヘッダーから承認者を認証する（認証できない場合は401を返す）
+func (h *ExpenseReportHandler) authenticate(w http.ResponseWriter, r *http.Request) (domain.Approver, bool) {
+	approver, ok := h.reportUseCase.Authenticate(r.Header.Get(approverKeyHeader))
+	if !ok {
+		writeError(w, "Unauthorized: invalid or missing "+approverKeyHeader, http.StatusUnauthorized)
+	}
+	return approver, ok
+}
+
+// writeReport 経費報告書を含まれるレシートの概要とともに返す
+func (h *ExpenseReportHandler) writeReport(w http.ResponseWriter, r *http.Request, statusCode int, report *domain.ExpenseReport) {
+	receipts, err := h.reportUseCase.ReportReceipts(r.Context(), report)
+	if err != nil {
+		writeError(w, "Failed to find receipts", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, statusCode, newExpenseReportResponse(report, receipts))
+}
+
+// newExpenseReportResponse 経費報告書からレスポンスを作成（receiptsがnilの場合はレシートの概要を含めない）
+func newExpenseReportResponse(report *domain.ExpenseReport, receipts []*entity.Receipt) ExpenseReportResponse {
+	response := ExpenseReportResponse{
+		ID:           report.ID,
+		Title:        report.Title,
+		Applicant:    report.Applicant,
+		Status:       report.Status,
+		ReceiptIDs:   report.ReceiptIDs,
+		TotalAmount:  report.TotalAmount,
+		Approver:     report.Approver,
+		ReimbursedBy: report.ReimbursedBy,
+		Comment:      report.Comment,
+		SubmittedAt:  report.SubmittedAt,
+		ApprovedAt:   report.ApprovedAt,
+		ReimbursedAt: report.ReimbursedAt,
+		CreatedAt:    report.CreatedAt,
+		UpdatedAt:    report.UpdatedAt,
+	}
+	if response.ReceiptIDs == nil {
+		response.ReceiptIDs = []string{}
+	}
+	if receipts != nil {
+		response.Receipts = make([]ExpenseReceiptResponse, 0, len(receipts))
+		for _, receipt := range receipts {
+			response.Receipts = append(response.Receipts, ExpenseReceiptResponse{
+				ID:           receipt.ID,
+				StoreName:    receipt.StoreName,
+				PurchaseDate: receipt.PurchaseDate,
+				TotalAmount:  receipt.TotalAmount,
+				Refund:       receipt.IsRefund(),
+				NeedsReview:  receipt.NeedsReview,
+			})
+		}
+	}
+	return response
+}
+
+// writeReportError ユースケースのエラーをステータスコードに変換して返す
+func writeReportError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, usecase.ErrReportNotFound):
+		writeError(w, "Expense report not found", http.StatusNotFound)
+	case errors.Is(err, domain.ErrNotPermitted):
+		writeError(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, domain.ErrInvalidReport), errors.Is(err, usecase.ErrReceiptNotFound):
+		writeError(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, domain.ErrInvalidTransition), errors.Is(err, usecase.ErrReceiptInOtherReport), errors.Is(err, usecase.ErrReceiptNeedsReview):
+		writeError(w, err.Error(), http.StatusConflict)
+	default:
+		writeError(w, message, http.StatusInternalServerError)
+	}
+}
+
+// parsePagination limit・offsetクエリパラメータを解析（limitは最大maxPageLimit）
+func parsePagination(r *http.Request) (int, int, error) {
+	limit := defaultPageLimit
+	offset := 0
+
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return 0, 0, fmt.Errorf("invalid limit: %s", v)
+		}
+		limit = min(n, maxPageLimit)
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return 0, 0, fmt.Errorf("invalid offset: %s", v)
+		}
+		offset = n
+	}
+	return limit, offset, nil
+}
+
+// writeJSON 成功レスポンスを送信
+func writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(APIResponse{
+		Success: true,
+		Data:    data,
+	})
+}
+
+// writeError エラーレスポンスを送信
+func writeError(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(APIResponse{
+		Success: false,
+		Error:   message,
+	})
+}
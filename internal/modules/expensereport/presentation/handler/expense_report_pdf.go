@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"vision-api-app/internal/modules/expensereport/domain"
+	"vision-api-app/internal/modules/household/domain/entity"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+	"vision-api-app/internal/modules/shared/presentation/chart"
+	"vision-api-app/internal/modules/shared/presentation/pdf"
+)
+
+// PDFのレイアウト（ポイント、用紙の左上が原点）
+const (
+	pdfMarginLeft   = 40.0
+	pdfMarginRight  = pdf.PageWidth - 40
+	pdfMarginBottom = pdf.PageHeight - 60
+	pdfRowHeight    = 16.0
+	pdfFontSize     = 10.0
+	pdfStoreColumn  = 130.0 // 店名の列の左端
+	pdfStoreWidth   = 320.0 // 店名の列の幅（超える場合は省略する）
+	pdfChartSize    = 150.0 // カテゴリー別の円グラフの大きさ
+	pdfChartPixels  = 450   // 円グラフの画像の大きさ（ピクセル、印刷で粗くならないよう描く大きさの3倍）
+)
+
+// statusLabels PDFに表示する経費報告書の状態
+var statusLabels = map[string]string{
+	domain.StatusDraft:      "作成中",
+	domain.StatusSubmitted:  "申請済み",
+	domain.StatusApproved:   "承認済み",
+	domain.StatusReimbursed: "精算済み",
+}
+
+// HandlePDF 経費報告書をPDFで出力（含まれるレシートの一覧と合計金額）
+func (h *ExpenseReportHandler) HandlePDF(w http.ResponseWriter, r *http.Request) {
+	report, err := h.reportUseCase.GetReport(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeReportError(w, err, "Failed to get expense report")
+		return
+	}
+	receipts, err := h.reportUseCase.ReportReceipts(r.Context(), report)
+	if err != nil {
+		writeError(w, "Failed to find receipts", http.StatusInternalServerError)
+		return
+	}
+
+	// 書き出しに失敗した場合にエラーを返せるよう、すべて作成してから送信する
+	var buf bytes.Buffer
+	doc := renderReportPDF(report, receipts, h.currency, sharedDomain.LocationFromContext(r.Context()))
+	if _, err := doc.WriteTo(&buf); err != nil {
+		writeError(w, "Failed to render expense report", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "expense-report-"+report.ID+".pdf"))
+	w.WriteHeader(http.StatusOK)
+	_, _ = buf.WriteTo(w)
+}
+
+// renderReportPDF 経費報告書のPDFを作成
+// 申請前の報告書は確定した合計金額がないため、現在のレシートの合計を表示する
+func renderReportPDF(report *domain.ExpenseReport, receipts []*entity.Receipt, currency sharedDomain.Currency, loc *time.Location) *pdf.Document {
+	doc := pdf.NewDocument("経費報告書 " + report.Title)
+	page := doc.AddPage()
+	page.Text(pdfMarginLeft, 60, 18, "経費報告書")
+
+	y := 95.0
+	field := func(label, value string) {
+		if value == "" {
+			return
+		}
+		page.Text(pdfMarginLeft, y, pdfFontSize, label)
+		page.Text(pdfStoreColumn, y, pdfFontSize, value)
+		y += pdfRowHeight
+	}
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.In(loc).Format("2006-01-02")
+	}
+	field("件名", report.Title)
+	field("申請者", report.Applicant)
+	field("状態", statusLabels[report.Status])
+	field("申請日", formatTime(report.SubmittedAt))
+	field("承認者", report.Approver)
+	field("承認日", formatTime(report.ApprovedAt))
+	field("精算者", report.ReimbursedBy)
+	field("精算日", formatTime(report.ReimbursedAt))
+	field("コメント", report.Comment)
+
+	// レシートの一覧（ページに収まらない場合は次のページに続ける）
+	header := func(y float64) float64 {
+		page.Text(pdfMarginLeft, y, pdfFontSize, "購入日")
+		page.Text(pdfStoreColumn, y, pdfFontSize, "店名")
+		page.TextRight(pdfMarginRight, y, pdfFontSize, "金額")
+		page.Line(pdfMarginLeft, y+5, pdfMarginRight, y+5)
+		return y + pdfRowHeight + 4
+	}
+	y = header(y + pdfRowHeight)
+
+	var total int64
+	for _, receipt := range receipts {
+		if y > pdfMarginBottom {
+			page = doc.AddPage()
+			y = header(60)
+		}
+		store := truncateText(receipt.StoreName, pdfStoreWidth, pdfFontSize)
+		if receipt.IsRefund() {
+			store = truncateText("（返品）"+receipt.StoreName, pdfStoreWidth, pdfFontSize)
+		}
+		page.Text(pdfMarginLeft, y, pdfFontSize, receipt.PurchaseDate.In(loc).Format("2006-01-02"))
+		page.Text(pdfStoreColumn, y, pdfFontSize, store)
+		page.TextRight(pdfMarginRight, y, pdfFontSize, currency.Display(receipt.TotalAmount))
+		total += receipt.TotalAmount
+		y += pdfRowHeight
+	}
+
+	if report.Status != domain.StatusDraft {
+		total = report.TotalAmount
+	}
+	page.Line(pdfMarginLeft, y-pdfRowHeight+5, pdfMarginRight, y-pdfRowHeight+5)
+	page.Text(pdfStoreColumn, y+4, 12, fmt.Sprintf("合計（%d件）", len(receipts)))
+	page.TextRight(pdfMarginRight, y+4, 12, currency.Display(total))
+
+	drawCategoryChart(doc, page, y+40, receipts, currency)
+	return doc
+}
+
+// drawCategoryChart レシートのカテゴリー別の内訳を円グラフと凡例で描画（ページに収まらない場合は次のページに描く）
+// 返品・返金はカテゴリーごとに差し引き、金額が正のカテゴリーのみ描く
+func drawCategoryChart(doc *pdf.Document, page *pdf.Page, y float64, receipts []*entity.Receipt, currency sharedDomain.Currency) {
+	totals := make(map[string]int64)
+	for _, receipt := range receipts {
+		category := receipt.Category
+		if category == "" {
+			category = entity.DefaultCategory
+		}
+		totals[category] += receipt.TotalAmount
+	}
+	slices := make([]chart.Slice, 0, len(totals))
+	for category, total := range totals {
+		slices = append(slices, chart.Slice{Label: category, Value: total})
+	}
+	slices = chart.TopSlices(slices, entity.DefaultCategory)
+	if len(slices) == 0 {
+		return
+	}
+
+	if y+pdfRowHeight+pdfChartSize > pdfMarginBottom {
+		page = doc.AddPage()
+		y = 60
+	}
+	page.Text(pdfMarginLeft, y, pdfFontSize, "カテゴリー別")
+	y += pdfRowHeight / 2
+	page.Image(pdfMarginLeft, y, pdfChartSize, pdfChartSize, chart.Pie(slices, pdfChartPixels, pdfChartPixels))
+
+	// 凡例は円グラフの右に縦中央揃えで並べる
+	legendX := pdfMarginLeft + pdfChartSize + 30
+	legendY := y + (pdfChartSize-float64(len(slices))*pdfRowHeight)/2 + pdfFontSize
+	for i, slice := range slices {
+		page.Rect(legendX, legendY-pdfFontSize+1, pdfFontSize-1, pdfFontSize-1, chart.Color(i))
+		page.Text(legendX+pdfFontSize+6, legendY, pdfFontSize, truncateText(slice.Label, pdfStoreWidth-pdfChartSize, pdfFontSize))
+		page.TextRight(pdfMarginRight, legendY, pdfFontSize, currency.Display(slice.Value))
+		legendY += pdfRowHeight
+	}
+}
+
+// truncateText 幅に収まらない文字列を末尾を省略して返す
+func truncateText(text string, width, size float64) string {
+	if pdf.TextWidth(text, size) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && pdf.TextWidth(string(runes)+"…", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
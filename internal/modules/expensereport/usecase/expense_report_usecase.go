@@ -0,0 +1,279 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"vision-api-app/internal/modules/expensereport/domain"
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+var (
+	// ErrReportNotFound 経費報告書が存在しない
+	ErrReportNotFound = errors.New("expense report not found")
+	// ErrReceiptNotFound 報告書に含めるレシートが存在しない
+	ErrReceiptNotFound = errors.New("receipt not found")
+	// ErrReceiptInOtherReport レシートがすでに別の経費報告書に含まれている
+	ErrReceiptInOtherReport = errors.New("receipt is already in another expense report")
+	// ErrReceiptNeedsReview 要確認のレシートを含む報告書は申請できない
+	ErrReceiptNeedsReview = errors.New("receipt needs review")
+)
+
+// ApproverCredential 承認者と認証に使うキー
+type ApproverCredential struct {
+	Key      string
+	Approver domain.Approver
+}
+
+// ReportPatch 経費報告書の部分更新（nilの項目は変更しない）
+type ReportPatch struct {
+	Title      *string
+	Applicant  *string
+	ReceiptIDs *[]string
+}
+
+// ExpenseReportUseCase 経費報告書の作成と承認フローのユースケース
+type ExpenseReportUseCase struct {
+	reportRepo  domain.ExpenseReportRepository
+	receiptRepo repository.ReceiptRepository
+	idGenerator sharedDomain.IDGenerator
+	approvers   []ApproverCredential
+	now         func() time.Time
+}
+
+// NewExpenseReportUseCase 新しいExpenseReportUseCaseを作成
+func NewExpenseReportUseCase(reportRepo domain.ExpenseReportRepository, receiptRepo repository.ReceiptRepository) *ExpenseReportUseCase {
+	return &ExpenseReportUseCase{
+		reportRepo:  reportRepo,
+		receiptRepo: receiptRepo,
+		now:         time.Now,
+	}
+}
+
+// SetIDGenerator 報告書のIDの生成方法を設定（未設定の場合はUUID v4）
+func (uc *ExpenseReportUseCase) SetIDGenerator(generator sharedDomain.IDGenerator) {
+	uc.idGenerator = generator
+}
+
+// SetApprovers 承認フローを進められる承認者を設定（未設定の場合は承認・差し戻し・精算はできない）
+func (uc *ExpenseReportUseCase) SetApprovers(approvers []ApproverCredential) {
+	uc.approvers = approvers
+}
+
+// Authenticate キーに対応する承認者を返す（キーは一定時間で比較する）
+func (uc *ExpenseReportUseCase) Authenticate(key string) (domain.Approver, bool) {
+	if key == "" {
+		return domain.Approver{}, false
+	}
+	for _, credential := range uc.approvers {
+		if credential.Key != "" && subtle.ConstantTimeCompare([]byte(credential.Key), []byte(key)) == 1 {
+			return credential.Approver, true
+		}
+	}
+	return domain.Approver{}, false
+}
+
+// CreateReport 作成中の経費報告書を作成
+func (uc *ExpenseReportUseCase) CreateReport(ctx context.Context, title, applicant string, receiptIDs []string) (*domain.ExpenseReport, error) {
+	report, err := domain.NewExpenseReport(uc.newID(), title, applicant, receiptIDs, uc.now())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.checkReceipts(ctx, report); err != nil {
+		return nil, err
+	}
+	if err := uc.reportRepo.Create(ctx, report); err != nil {
+		return nil, fmt.Errorf("failed to create expense report: %w", err)
+	}
+	return report, nil
+}
+
+// GetReport 経費報告書を取得
+func (uc *ExpenseReportUseCase) GetReport(ctx context.Context, id string) (*domain.ExpenseReport, error) {
+	report, err := uc.reportRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrReportNotFound, err)
+	}
+	return report, nil
+}
+
+// ListReports 経費報告書を更新日時の新しい順に取得（statusが空の場合はすべての状態）
+func (uc *ExpenseReportUseCase) ListReports(ctx context.Context, status string, limit, offset int) ([]*domain.ExpenseReport, error) {
+	if status != "" && !slices.Contains(domain.Statuses, status) {
+		return nil, fmt.Errorf("%w: unknown status: %s", domain.ErrInvalidReport, status)
+	}
+	reports, err := uc.reportRepo.FindAll(ctx, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expense reports: %w", err)
+	}
+	return reports, nil
+}
+
+// UpdateReport 作成中の経費報告書の件名・申請者・レシートを変更
+func (uc *ExpenseReportUseCase) UpdateReport(ctx context.Context, id string, patch ReportPatch) (*domain.ExpenseReport, error) {
+	report, err := uc.GetReport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	title, applicant, receiptIDs := report.Title, report.Applicant, report.ReceiptIDs
+	if patch.Title != nil {
+		title = *patch.Title
+	}
+	if patch.Applicant != nil {
+		applicant = *patch.Applicant
+	}
+	if patch.ReceiptIDs != nil {
+		receiptIDs = *patch.ReceiptIDs
+	}
+	if err := report.Edit(title, applicant, receiptIDs, uc.now()); err != nil {
+		return nil, err
+	}
+	if _, err := uc.checkReceipts(ctx, report); err != nil {
+		return nil, err
+	}
+	return report, uc.save(ctx, report)
+}
+
+// DeleteReport 作成中の経費報告書を削除（申請済み以降の報告書は記録として残すため削除できない）
+func (uc *ExpenseReportUseCase) DeleteReport(ctx context.Context, id string) error {
+	report, err := uc.GetReport(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !report.Editable() {
+		return fmt.Errorf("%w: %s report cannot be deleted", domain.ErrInvalidTransition, report.Status)
+	}
+	if err := uc.reportRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete expense report: %w", err)
+	}
+	return nil
+}
+
+// SubmitReport 経費報告書を申請する
+// 合計金額は申請時のレシートの合計で確定する（返品・返金のレシートは差し引く）。要確認のレシートを含む場合は申請できない
+func (uc *ExpenseReportUseCase) SubmitReport(ctx context.Context, id string) (*domain.ExpenseReport, error) {
+	report, err := uc.GetReport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	receipts, err := uc.checkReceipts(ctx, report)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	for _, receipt := range receipts {
+		if receipt.NeedsReview {
+			return nil, fmt.Errorf("%w: %s", ErrReceiptNeedsReview, receipt.ID)
+		}
+		total += receipt.TotalAmount
+	}
+	if err := report.Submit(total, uc.now()); err != nil {
+		return nil, err
+	}
+	return report, uc.save(ctx, report)
+}
+
+// ApproveReport 申請済みの経費報告書を承認する
+func (uc *ExpenseReportUseCase) ApproveReport(ctx context.Context, id string, approver domain.Approver, comment string) (*domain.ExpenseReport, error) {
+	return uc.transition(ctx, id, func(report *domain.ExpenseReport, now time.Time) error {
+		return report.Approve(approver, comment, now)
+	})
+}
+
+// RejectReport 申請済みの経費報告書を差し戻して作成中に戻す
+func (uc *ExpenseReportUseCase) RejectReport(ctx context.Context, id string, approver domain.Approver, comment string) (*domain.ExpenseReport, error) {
+	return uc.transition(ctx, id, func(report *domain.ExpenseReport, now time.Time) error {
+		return report.Reject(approver, comment, now)
+	})
+}
+
+// ReimburseReport 承認済みの経費報告書を精算済みにする
+func (uc *ExpenseReportUseCase) ReimburseReport(ctx context.Context, id string, approver domain.Approver) (*domain.ExpenseReport, error) {
+	return uc.transition(ctx, id, func(report *domain.ExpenseReport, now time.Time) error {
+		return report.Reimburse(approver, now)
+	})
+}
+
+// ReportReceipts 経費報告書に含まれるレシートを報告書の順に取得（削除されたレシートは含めない）
+func (uc *ExpenseReportUseCase) ReportReceipts(ctx context.Context, report *domain.ExpenseReport) ([]*entity.Receipt, error) {
+	if len(report.ReceiptIDs) == 0 {
+		return []*entity.Receipt{}, nil
+	}
+	receipts, err := uc.receiptRepo.FindByIDs(ctx, report.ReceiptIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find receipts: %w", err)
+	}
+	return receipts, nil
+}
+
+// transition 経費報告書の状態を変更して保存する
+func (uc *ExpenseReportUseCase) transition(ctx context.Context, id string, apply func(*domain.ExpenseReport, time.Time) error) (*domain.ExpenseReport, error) {
+	report, err := uc.GetReport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := apply(report, uc.now()); err != nil {
+		return nil, err
+	}
+	return report, uc.save(ctx, report)
+}
+
+// checkReceipts 報告書のレシートがすべて存在し、別の報告書に含まれていないかチェック
+func (uc *ExpenseReportUseCase) checkReceipts(ctx context.Context, report *domain.ExpenseReport) ([]*entity.Receipt, error) {
+	receipts, err := uc.ReportReceipts(ctx, report)
+	if err != nil {
+		return nil, err
+	}
+	if len(receipts) != len(report.ReceiptIDs) {
+		for _, id := range report.ReceiptIDs {
+			if !slices.ContainsFunc(receipts, func(receipt *entity.Receipt) bool { return receipt.ID == id }) {
+				return nil, fmt.Errorf("%w: %s", ErrReceiptNotFound, id)
+			}
+		}
+	}
+
+	if len(report.ReceiptIDs) == 0 {
+		return receipts, nil
+	}
+	others, err := uc.reportRepo.FindByReceiptIDs(ctx, report.ReceiptIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expense reports: %w", err)
+	}
+	for _, other := range others {
+		if other.ID == report.ID {
+			continue
+		}
+		for _, id := range report.ReceiptIDs {
+			if slices.Contains(other.ReceiptIDs, id) {
+				return nil, fmt.Errorf("%w: %s is in %s", ErrReceiptInOtherReport, id, other.ID)
+			}
+		}
+	}
+	return receipts, nil
+}
+
+// save 経費報告書を保存
+func (uc *ExpenseReportUseCase) save(ctx context.Context, report *domain.ExpenseReport) error {
+	if err := uc.reportRepo.Update(ctx, report); err != nil {
+		return fmt.Errorf("failed to update expense report: %w", err)
+	}
+	return nil
+}
+
+// newID 報告書のIDを生成
+func (uc *ExpenseReportUseCase) newID() string {
+	if uc.idGenerator != nil {
+		return uc.idGenerator.NewID(nil)
+	}
+	return uuid.NewString()
+}
@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/expensereport/domain"
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+)
+
+// memoryReportRepository メモリ上の経費報告書リポジトリ
+type memoryReportRepository struct {
+	reports map[string]*domain.ExpenseReport
+}
+
+func newMemoryReportRepository(reports ...*domain.ExpenseReport) *memoryReportRepository {
+	repo := &memoryReportRepository{reports: make(map[string]*domain.ExpenseReport)}
+	for _, report := range reports {
+		repo.reports[report.ID] = report
+	}
+	return repo
+}
+
+func (m *memoryReportRepository) Create(ctx context.Context, report *domain.ExpenseReport) error {
+	m.reports[report.ID] = report
+	return nil
+}
+
+func (m *memoryReportRepository) FindByID(ctx context.Context, id string) (*domain.ExpenseReport, error) {
+	report, ok := m.reports[id]
+	if !ok {
+		return nil, errors.New("expense report not found: " + id)
+	}
+	clone := *report
+	clone.ReceiptIDs = slices.Clone(report.ReceiptIDs)
+	return &clone, nil
+}
+
+func (m *memoryReportRepository) Update(ctx context.Context, report *domain.ExpenseReport) error {
+	m.reports[report.ID] = report
+	return nil
+}
+
+func (m *memoryReportRepository) Delete(ctx context.Context, id string) error {
+	delete(m.reports, id)
+	return nil
+}
+
+func (m *memoryReportRepository) FindAll(ctx context.Context, status string, limit, offset int) ([]*domain.ExpenseReport, error) {
+	var reports []*domain.ExpenseReport
+	for _, report := range m.reports {
+		if status == "" || report.Status == status {
+			reports = append(reports, report)
+		}
+	}
+	return reports, nil
+}
+
+func (m *memoryReportRepository) FindByReceiptIDs(ctx context.Context, receiptIDs []string) ([]*domain.ExpenseReport, error) {
+	var reports []*domain.ExpenseReport
+	for _, report := range m.reports {
+		if slices.ContainsFunc(report.ReceiptIDs, func(id string) bool { return slices.Contains(receiptIDs, id) }) {
+			reports = append(reports, report)
+		}
+	}
+	return reports, nil
+}
+
+// stubReceiptRepository IDでのまとめての取得のみを実装したレシートリポジトリ
+type stubReceiptRepository struct {
+	repository.ReceiptRepository
+
+	receipts []*entity.Receipt
+}
+
+func (s *stubReceiptRepository) FindByIDs(ctx context.Context, ids []string) ([]*entity.Receipt, error) {
+	var receipts []*entity.Receipt
+	for _, id := range ids {
+		for _, receipt := range s.receipts {
+			if receipt.ID == id {
+				receipts = append(receipts, receipt)
+			}
+		}
+	}
+	return receipts, nil
+}
+
+func newTestUseCase(reports ...*domain.ExpenseReport) *ExpenseReportUseCase {
+	receipts := &stubReceiptRepository{receipts: []*entity.Receipt{
+		{ID: "r1", StoreName: "タクシー", TotalAmount: 3200},
+		{ID: "r2", StoreName: "ホテル", TotalAmount: 9800},
+		{ID: "r3", StoreName: "ホテル", TotalAmount: -1800, Type: entity.ReceiptTypeRefund},
+		{ID: "r4", StoreName: "不明", TotalAmount: 500, NeedsReview: true},
+	}}
+	uc := NewExpenseReportUseCase(newMemoryReportRepository(reports...), receipts)
+	uc.now = func() time.Time { return time.Date(2025, time.March, 1, 10, 0, 0, 0, time.Local) }
+	uc.SetApprovers([]ApproverCredential{
+		{Key: "approver-key", Approver: domain.Approver{Name: "佐藤", Role: domain.RoleApprover}},
+		{Key: "accountant-key", Approver: domain.Approver{Name: "鈴木", Role: domain.RoleAccountant}},
+	})
+	return uc
+}
+
+func TestExpenseReportUseCase_Workflow(t *testing.T) {
+	ctx := context.Background()
+	uc := newTestUseCase()
+
+	report, err := uc.CreateReport(ctx, "3月 出張", "山田", []string{"r1", "r2", "r3"})
+	if err != nil {
+		t.Fatalf("CreateReport() error = %v", err)
+	}
+	if report.ID == "" || report.Status != domain.StatusDraft {
+		t.Errorf("CreateReport() = %+v", report)
+	}
+
+	// 申請時の合計金額は返品・返金のレシートを差し引く
+	submitted, err := uc.SubmitReport(ctx, report.ID)
+	if err != nil {
+		t.Fatalf("SubmitReport() error = %v", err)
+	}
+	if submitted.Status != domain.StatusSubmitted || submitted.TotalAmount != 11200 {
+		t.Errorf("SubmitReport() = %+v, want submitted with total 11200", submitted)
+	}
+
+	approver, ok := uc.Authenticate("approver-key")
+	if !ok {
+		t.Fatal("Authenticate(approver-key) = false")
+	}
+	if _, err := uc.ApproveReport(ctx, report.ID, approver, "承認します"); err != nil {
+		t.Fatalf("ApproveReport() error = %v", err)
+	}
+
+	accountant, _ := uc.Authenticate("accountant-key")
+	reimbursed, err := uc.ReimburseReport(ctx, report.ID, accountant)
+	if err != nil {
+		t.Fatalf("ReimburseReport() error = %v", err)
+	}
+	if reimbursed.Status != domain.StatusReimbursed || reimbursed.Approver != "佐藤" || reimbursed.ReimbursedBy != "鈴木" {
+		t.Errorf("ReimburseReport() = %+v", reimbursed)
+	}
+
+	// 精算済みの報告書は削除できない
+	if err := uc.DeleteReport(ctx, report.ID); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("DeleteReport() error = %v, want %v", err, domain.ErrInvalidTransition)
+	}
+}
+
+func TestExpenseReportUseCase_Receipts(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, time.February, 1, 10, 0, 0, 0, time.Local)
+	other, _ := domain.NewExpenseReport("other", "2月 出張", "田中", []string{"r2"}, now)
+	uc := newTestUseCase(other)
+
+	// 存在しないレシートは含められない
+	if _, err := uc.CreateReport(ctx, "3月 出張", "山田", []string{"r1", "missing"}); !errors.Is(err, ErrReceiptNotFound) {
+		t.Errorf("CreateReport() with missing receipt error = %v, want %v", err, ErrReceiptNotFound)
+	}
+	// 別の報告書に含まれるレシートは含められない
+	if _, err := uc.CreateReport(ctx, "3月 出張", "山田", []string{"r1", "r2"}); !errors.Is(err, ErrReceiptInOtherReport) {
+		t.Errorf("CreateReport() with receipt in other report error = %v, want %v", err, ErrReceiptInOtherReport)
+	}
+	// 自身に含まれるレシートは別の報告書として扱わない
+	receiptIDs := []string{"r2", "r3"}
+	updated, err := uc.UpdateReport(ctx, other.ID, ReportPatch{ReceiptIDs: &receiptIDs})
+	if err != nil {
+		t.Fatalf("UpdateReport() error = %v", err)
+	}
+	if !slices.Equal(updated.ReceiptIDs, receiptIDs) || updated.Title != "2月 出張" {
+		t.Errorf("UpdateReport() = %+v", updated)
+	}
+
+	// 要確認のレシートを含む報告書は申請できない
+	report, err := uc.CreateReport(ctx, "3月 雑費", "山田", []string{"r4"})
+	if err != nil {
+		t.Fatalf("CreateReport() error = %v", err)
+	}
+	if _, err := uc.SubmitReport(ctx, report.ID); !errors.Is(err, ErrReceiptNeedsReview) {
+		t.Errorf("SubmitReport() with receipt needing review error = %v, want %v", err, ErrReceiptNeedsReview)
+	}
+}
+
+func TestExpenseReportUseCase_Authenticate(t *testing.T) {
+	uc := newTestUseCase()
+
+	tests := []struct {
+		key    string
+		want   string
+		wantOK bool
+	}{
+		{key: "approver-key", want: "佐藤", wantOK: true},
+		{key: "accountant-key", want: "鈴木", wantOK: true},
+		{key: "wrong-key"},
+		{key: ""},
+	}
+	for _, tt := range tests {
+		approver, ok := uc.Authenticate(tt.key)
+		if ok != tt.wantOK || approver.Name != tt.want {
+			t.Errorf("Authenticate(%q) = %+v, %v, want %s, %v", tt.key, approver, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	// 存在しない報告書
+	if _, err := uc.GetReport(context.Background(), "missing"); !errors.Is(err, ErrReportNotFound) {
+		t.Errorf("GetReport() error = %v, want %v", err, ErrReportNotFound)
+	}
+}
@@ -0,0 +1,48 @@
+package entity
+
+import "time"
+
+// 会計サービス
+const (
+	AccountingProviderFreee        = "freee"
+	AccountingProviderMoneyForward = "moneyforward"
+)
+
+// 会計サービスとの同期状態
+const (
+	SyncStatusSynced   = "synced"   // 最新の内容を送信済み
+	SyncStatusFailed   = "failed"   // 送信に失敗（次回の同期で再送）
+	SyncStatusConflict = "conflict" // 会計サービス側で変更されたため送信を保留（解決するまで同期しない）
+)
+
+// AccountingSync レシートと会計サービスの取引の同期状態
+type AccountingSync struct {
+	ReceiptID        string
+	Provider         string
+	Status           string
+	ExternalID       string    // 会計サービス側の取引ID
+	RemoteVersion    string    // 最後に送信・確認した会計サービス側の取引の版（内容のハッシュ）
+	ReceiptUpdatedAt time.Time // 最後に送信したレシートの更新日時
+	Attempts         int       // 連続して失敗した回数
+	LastError        string
+	SyncedAt         *time.Time
+	UpdatedAt        time.Time
+}
+
+// AccountingDeal 会計サービスへ送信する取引（レシート1件の支出）
+type AccountingDeal struct {
+	ReceiptID     string
+	Date          time.Time
+	Partner       string // 取引先（店名）
+	PaymentMethod string
+	Amount        int64 // 支払額
+	Lines         []AccountingDealLine
+	Memo          string
+}
+
+// AccountingDealLine 取引のカテゴリーごとの明細
+type AccountingDealLine struct {
+	Category    string
+	Amount      int64
+	Description string // 明細の商品名（カンマ区切り）
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// ErrCategoryExists 同じ名前のカテゴリが登録済み
+	ErrCategoryExists = errors.New("category already exists")
+	// ErrCategoryNotFound 指定したカテゴリが登録されていない
+	ErrCategoryNotFound = errors.New("category not found")
+)
+
+// MaxCategoryNameLength カテゴリ名の最大文字数（categories.nameの長さ）
+const MaxCategoryNameLength = 50
+
+// categoryColorPattern カテゴリの表示色（#RRGGBB）
+var categoryColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// Validate カテゴリを検証し、不正な項目を説明するエラーを返す
+// 表示色は省略できる
+func (c *Category) Validate() error {
+	switch {
+	case strings.TrimSpace(c.Name) == "":
+		return errors.New("name is required")
+	case utf8.RuneCountInString(c.Name) > MaxCategoryNameLength:
+		return fmt.Errorf("name must be at most %d characters", MaxCategoryNameLength)
+	case c.Color != "" && !categoryColorPattern.MatchString(c.Color):
+		return fmt.Errorf("color must be #RRGGBB: %s", c.Color)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCategory_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		category Category
+		wantErr  string
+	}{
+		{name: "有効", category: Category{Name: "食費", Color: "#FF6b6B"}},
+		{name: "表示色なし", category: Category{Name: "食費"}},
+		{name: "名前が空白", category: Category{Name: "  "}, wantErr: "name is required"},
+		{name: "名前が長すぎる", category: Category{Name: strings.Repeat("費", 51)}, wantErr: "name must be at most 50 characters"},
+		{name: "表示色が短い", category: Category{Name: "食費", Color: "#FFF"}, wantErr: "color must be #RRGGBB: #FFF"},
+		{name: "表示色に#がない", category: Category{Name: "食費", Color: "FF6B6B"}, wantErr: "color must be #RRGGBB: FF6B6B"},
+		{name: "表示色が16進数でない", category: Category{Name: "食費", Color: "#GG6B6B"}, wantErr: "color must be #RRGGBB: #GG6B6B"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.category.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Validate() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
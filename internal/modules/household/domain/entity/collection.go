@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxCollectionNameLength コレクション名の最大文字数
+const MaxCollectionNameLength = 100
+
+// ErrInvalidCollection コレクションの指定が不正（名前が空・長すぎる、レシートIDが空など）
+var ErrInvalidCollection = errors.New("invalid collection")
+
+// Collection レシートをまとめるコレクション（フォルダー）エンティティ
+// 「2025年 確定申告」「キッチンのリフォーム」など目的ごとにレシートをまとめ、まとめたレシートだけで書き出し・集計する。
+// 1件のレシートは複数のコレクションに含められ、レシートを削除してもコレクションからは外さない（ゴミ箱から戻した場合に再び含まれる）
+type Collection struct {
+	ID          string
+	Name        string
+	Description string
+	ReceiptIDs  []string // 含めるレシート（追加した順）
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewCollection 空のコレクションを作成
+func NewCollection(id, name, description string, now time.Time) (*Collection, error) {
+	collection := &Collection{
+		ID:         id,
+		ReceiptIDs: []string{},
+		CreatedAt:  now,
+	}
+	if err := collection.Rename(name, description, now); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+// Rename 名前・説明を変更
+func (c *Collection) Rename(name, description string, now time.Time) error {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > MaxCollectionNameLength {
+		return fmt.Errorf("%w: name must be 1-%d characters", ErrInvalidCollection, MaxCollectionNameLength)
+	}
+	c.Name = name
+	c.Description = strings.TrimSpace(description)
+	c.UpdatedAt = now
+	return nil
+}
+
+// Contains レシートがコレクションに含まれるかチェック
+func (c *Collection) Contains(receiptID string) bool {
+	return slices.Contains(c.ReceiptIDs, receiptID)
+}
+
+// AddReceipts レシートを末尾に追加し、追加した件数を返す（含まれているレシートは追加しない）
+func (c *Collection) AddReceipts(receiptIDs []string, now time.Time) (int, error) {
+	if slices.Contains(receiptIDs, "") {
+		return 0, fmt.Errorf("%w: receipt id is empty", ErrInvalidCollection)
+	}
+	added := 0
+	for _, id := range receiptIDs {
+		if c.Contains(id) {
+			continue
+		}
+		c.ReceiptIDs = append(c.ReceiptIDs, id)
+		added++
+	}
+	if added > 0 {
+		c.UpdatedAt = now
+	}
+	return added, nil
+}
+
+// RemoveReceipt レシートを外す（含まれていない場合はfalse）
+func (c *Collection) RemoveReceipt(receiptID string, now time.Time) bool {
+	i := slices.Index(c.ReceiptIDs, receiptID)
+	if i < 0 {
+		return false
+	}
+	c.ReceiptIDs = slices.Delete(c.ReceiptIDs, i, i+1)
+	c.UpdatedAt = now
+	return true
+}
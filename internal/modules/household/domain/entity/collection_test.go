@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewCollection(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		input    string
+		wantName string
+		wantErr  bool
+	}{
+		{name: "正常系: 前後の空白は除く", input: " 2025年 確定申告 ", wantName: "2025年 確定申告"},
+		{name: "正常系: 最大文字数", input: strings.Repeat("あ", MaxCollectionNameLength), wantName: strings.Repeat("あ", MaxCollectionNameLength)},
+		{name: "異常系: 空の名前", input: "  ", wantErr: true},
+		{name: "異常系: 長すぎる名前", input: strings.Repeat("あ", MaxCollectionNameLength+1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collection, err := NewCollection("c1", tt.input, "", now)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCollection) {
+					t.Errorf("NewCollection() error = %v, want ErrInvalidCollection", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewCollection() error = %v", err)
+			}
+			if collection.Name != tt.wantName || len(collection.ReceiptIDs) != 0 {
+				t.Errorf("NewCollection() = %+v, want name %q and no receipts", collection, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestCollection_Membership(t *testing.T) {
+	created := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	later := created.Add(time.Hour)
+	collection, err := NewCollection("c1", "キッチンのリフォーム", "", created)
+	if err != nil {
+		t.Fatalf("NewCollection() error = %v", err)
+	}
+
+	added, err := collection.AddReceipts([]string{"r1", "r2", "r1"}, later)
+	if err != nil || added != 2 {
+		t.Fatalf("AddReceipts() = %d, %v, want 2 added", added, err)
+	}
+	if added, _ := collection.AddReceipts([]string{"r2"}, later.Add(time.Hour)); added != 0 || !collection.UpdatedAt.Equal(later) {
+		t.Errorf("AddReceipts() of a member = %d, UpdatedAt = %v, want no change", added, collection.UpdatedAt)
+	}
+	if _, err := collection.AddReceipts([]string{"r3", ""}, later); !errors.Is(err, ErrInvalidCollection) {
+		t.Errorf("AddReceipts() with an empty id error = %v, want ErrInvalidCollection", err)
+	}
+	if collection.Contains("r3") {
+		t.Error("AddReceipts() with an empty id added r3, want no change")
+	}
+
+	if !collection.RemoveReceipt("r1", later) || collection.RemoveReceipt("r1", later) {
+		t.Error("RemoveReceipt() should remove r1 only once")
+	}
+	if got := strings.Join(collection.ReceiptIDs, ","); got != "r2" {
+		t.Errorf("ReceiptIDs = %s, want r2", got)
+	}
+}
@@ -0,0 +1,69 @@
+package entity
+
+import "time"
+
+// ドメインイベントの種類
+const (
+	EventReceiptCreated  = "receipt.created"          // レシートを登録した
+	EventTotalCorrected  = "receipt.total_corrected"  // レシートの合計金額を修正した
+	EventItemCategorized = "receipt.item_categorized" // 明細のカテゴリーを判定・設定した
+	EventExpenseUpdated  = "expense.updated"          // 家計簿エントリを修正した
+)
+
+// ReceiptCreated レシートを登録した
+type ReceiptCreated struct {
+	ReceiptID   string    `json:"receipt_id"`
+	StoreName   string    `json:"store_name"`
+	TotalAmount int64     `json:"total_amount"`
+	ItemCount   int       `json:"item_count"`
+	At          time.Time `json:"occurred_at"`
+}
+
+// EventName イベントの種類
+func (e ReceiptCreated) EventName() string { return EventReceiptCreated }
+
+// OccurredAt イベントが起きた日時
+func (e ReceiptCreated) OccurredAt() time.Time { return e.At }
+
+// TotalCorrected レシートの合計金額を修正した
+type TotalCorrected struct {
+	ReceiptID string    `json:"receipt_id"`
+	Before    int64     `json:"before"`
+	After     int64     `json:"after"`
+	At        time.Time `json:"occurred_at"`
+}
+
+// EventName イベントの種類
+func (e TotalCorrected) EventName() string { return EventTotalCorrected }
+
+// OccurredAt イベントが起きた日時
+func (e TotalCorrected) OccurredAt() time.Time { return e.At }
+
+// ItemCategorized 明細のカテゴリーを判定・設定した（Statusは判定状態 CategoryStatus*）
+type ItemCategorized struct {
+	ReceiptID string    `json:"receipt_id"`
+	ItemID    string    `json:"item_id"`
+	ItemName  string    `json:"item_name"`
+	Category  string    `json:"category"`
+	Status    string    `json:"status"`
+	At        time.Time `json:"occurred_at"`
+}
+
+// EventName イベントの種類
+func (e ItemCategorized) EventName() string { return EventItemCategorized }
+
+// OccurredAt イベントが起きた日時
+func (e ItemCategorized) OccurredAt() time.Time { return e.At }
+
+// ExpenseUpdated 家計簿エントリを修正した（Fieldsは変更した項目）
+type ExpenseUpdated struct {
+	EntryID string    `json:"entry_id"`
+	Fields  []string  `json:"fields"`
+	At      time.Time `json:"occurred_at"`
+}
+
+// EventName イベントの種類
+func (e ExpenseUpdated) EventName() string { return EventExpenseUpdated }
+
+// OccurredAt イベントが起きた日時
+func (e ExpenseUpdated) OccurredAt() time.Time { return e.At }
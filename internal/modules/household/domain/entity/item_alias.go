@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrItemAliasNotFound 指定した別名が登録されていない
+var ErrItemAliasNotFound = errors.New("item alias not found")
+
+// ItemAlias 商品名の別名（OCRで読み取った略称など）と正式な商品名の対応
+// 別名はNormalizeItemNameで正規化して照合するため、全角・半角や空白の違いは同じ別名として扱う
+type ItemAlias struct {
+	Alias         string // 登録時の別名の表記（例: "ｷｬﾍﾞﾂ1/2"）
+	CanonicalName string // 正式な商品名（例: "キャベツ 1/2玉"）
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Key 別名を照合するキー
+func (a *ItemAlias) Key() string {
+	return ItemAliasKey(a.Alias)
+}
+
+// Validate 別名を検証し、不正な項目を説明するエラーを返す
+func (a *ItemAlias) Validate() error {
+	switch {
+	case a.Key() == "":
+		return errors.New("alias is required")
+	case strings.TrimSpace(a.CanonicalName) == "":
+		return errors.New("canonical_name is required")
+	}
+	return nil
+}
+
+// ItemAliasKey 商品名・別名を照合するキー（NormalizeItemName）
+func ItemAliasKey(name string) string {
+	return NormalizeItemName(name)
+}
+
+// ItemAliasDictionary 別名のキーから正式な商品名を引く辞書
+type ItemAliasDictionary map[string]string
+
+// NewItemAliasDictionary 別名の一覧から辞書を作成
+func NewItemAliasDictionary(aliases []*ItemAlias) ItemAliasDictionary {
+	dictionary := make(ItemAliasDictionary, len(aliases))
+	for _, alias := range aliases {
+		dictionary[alias.Key()] = alias.CanonicalName
+	}
+	return dictionary
+}
+
+// Canonicalize 別名に一致する商品名を正式な商品名に置き換える（一致しない場合はそのまま）
+func (d ItemAliasDictionary) Canonicalize(name string) string {
+	if canonical, ok := d[ItemAliasKey(name)]; ok {
+		return canonical
+	}
+	return name
+}
+
+// CanonicalizeReceipt 明細の商品名を正式な商品名に置き換え、変わった場合はtrueを返す
+func (d ItemAliasDictionary) CanonicalizeReceipt(receipt *Receipt) bool {
+	changed := false
+	for i := range receipt.Items {
+		if name := d.Canonicalize(receipt.Items[i].Name); name != receipt.Items[i].Name {
+			receipt.Items[i].Name = name
+			changed = true
+		}
+	}
+	return changed
+}
@@ -0,0 +1,109 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// itemNameMarks レシートで商品名の前後に印字される記号（軽減税率の印など）
+const itemNameMarks = "*＊※★☆◎○●◆◇■□・"
+
+// itemNameDashes カタカナの後では長音として扱うダッシュ・ハイフン類
+const itemNameDashes = "-‐‑–—―−~〜"
+
+// NormalizeItemName 同じ商品の表記ゆれを同一視するため商品名を正規化する
+// NFKCで全角英数字・半角カタカナを統一し、空白と印字記号を除去、英字は小文字、ひらがなはカタカナにそろえる。
+// カタカナの後のダッシュ・ハイフンは長音（ー）として扱う（例: "ロ－ソン" → "ローソン"）
+func NormalizeItemName(name string) string {
+	var b strings.Builder
+	var prev rune
+	for _, r := range norm.NFKC.String(name) {
+		switch {
+		case unicode.IsSpace(r), strings.ContainsRune(itemNameMarks, r):
+			continue
+		case strings.ContainsRune(itemNameDashes, r) && isKatakana(prev):
+			r = 'ー'
+		case r >= 'ぁ' && r <= 'ゖ':
+			r += 'ァ' - 'ぁ'
+		default:
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+		prev = r
+	}
+	return b.String()
+}
+
+// isKatakana カタカナ（長音を含む）かチェック
+func isKatakana(r rune) bool {
+	return r == 'ー' || unicode.Is(unicode.Katakana, r)
+}
+
+// NormalizeDisplayName OCRで読み取った店名・商品名の表記ゆれを表示用にそろえる
+// NFKCで全角英数字・記号と半角カタカナを統一し、連続する空白を1つにまとめて前後の空白を除去する。
+// カタカナの後のダッシュ・ハイフンは長音（ー）として扱う（例: "ﾛ－ｿﾝ" → "ローソン"）。英字の大小文字・ひらがなはそのまま残す
+func NormalizeDisplayName(name string) string {
+	var b strings.Builder
+	var prev rune
+	space := false
+	for _, r := range norm.NFKC.String(name) {
+		if unicode.IsSpace(r) {
+			space = b.Len() > 0
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		if strings.ContainsRune(itemNameDashes, r) && isKatakana(prev) {
+			r = 'ー'
+		}
+		b.WriteRune(r)
+		prev = r
+	}
+	return b.String()
+}
+
+// NameRule 店名・商品名の置き換えルール（Patternに一致した部分をReplaceに置き換える）
+type NameRule struct {
+	Pattern *regexp.Regexp
+	Replace string
+}
+
+// NameNormalizer 読み取った店名・商品名をNormalizeDisplayNameでそろえ、置き換えルールを順に適用する
+type NameNormalizer struct {
+	rules []NameRule
+}
+
+// NewNameNormalizer 新しいNameNormalizerを作成
+func NewNameNormalizer(rules []NameRule) *NameNormalizer {
+	return &NameNormalizer{rules: rules}
+}
+
+// Normalize 名前を正規化する（置き換えで生じた前後の空白も除去する）
+func (n *NameNormalizer) Normalize(name string) string {
+	name = NormalizeDisplayName(name)
+	for _, rule := range n.rules {
+		name = rule.Pattern.ReplaceAllString(name, rule.Replace)
+	}
+	return strings.TrimSpace(name)
+}
+
+// NormalizeReceipt レシートの店名と明細の商品名を正規化し、変わった場合はtrueを返す
+func (n *NameNormalizer) NormalizeReceipt(receipt *Receipt) bool {
+	changed := false
+	if name := n.Normalize(receipt.StoreName); name != receipt.StoreName {
+		receipt.StoreName = name
+		changed = true
+	}
+	for i := range receipt.Items {
+		if name := n.Normalize(receipt.Items[i].Name); name != receipt.Items[i].Name {
+			receipt.Items[i].Name = name
+			changed = true
+		}
+	}
+	return changed
+}
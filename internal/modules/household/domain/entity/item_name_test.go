@@ -0,0 +1,105 @@
+package entity
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestNormalizeItemName(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "そのまま", in: "牛乳", want: "牛乳"},
+		{name: "空白を除去", in: " 明治 おいしい　牛乳 ", want: "明治オイシイ牛乳"},
+		{name: "半角カタカナを全角に", in: "ｷｬﾍﾞﾂ", want: "キャベツ"},
+		{name: "ひらがなをカタカナに", in: "ばなな", want: "バナナ"},
+		{name: "全角英数字を半角小文字に", in: "ＣＯＯＰ牛乳１Ｌ", want: "coop牛乳1l"},
+		{name: "軽減税率の印を除去", in: "※食パン*", want: "食パン"},
+		{name: "カタカナの後のダッシュは長音", in: "コ－ヒ-", want: "コーヒー"},
+		{name: "カタカナ以外の後のハイフンは残す", in: "A-1ソース", want: "a-1ソース"},
+		{name: "空文字", in: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeItemName(tt.in); got != tt.want {
+				t.Errorf("NormalizeItemName(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeDisplayName(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "そのまま", in: "ローソン", want: "ローソン"},
+		{name: "全角ダッシュを長音に", in: "ロ－ソン", want: "ローソン"},
+		{name: "半角カタカナを全角に", in: "ﾛｰｿﾝ", want: "ローソン"},
+		{name: "全角英数字を半角に（大小文字は残す）", in: "ＣＯＯＰ牛乳１Ｌ", want: "COOP牛乳1L"},
+		{name: "連続する空白をまとめて前後を除去", in: "　ファミリーマート　　新宿店 ", want: "ファミリーマート 新宿店"},
+		{name: "ひらがなは残す", in: "おにぎり", want: "おにぎり"},
+		{name: "カタカナ以外の後のハイフンは残す", in: "A-1ソース", want: "A-1ソース"},
+		{name: "空白のみ", in: "　 ", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeDisplayName(tt.in); got != tt.want {
+				t.Errorf("NormalizeDisplayName(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNameNormalizer_Normalize(t *testing.T) {
+	normalizer := NewNameNormalizer([]NameRule{
+		{Pattern: regexp.MustCompile(`^\(株\)`), Replace: ""},
+		{Pattern: regexp.MustCompile(`(\d+)個入`), Replace: "${1}個"},
+	})
+
+	tests := map[string]string{
+		"㈱マルエツ":    "マルエツ",
+		"卵 10個入":   "卵 10個",
+		"ﾏﾙｴﾂ (株)": "マルエツ (株)",
+	}
+	for in, want := range tests {
+		if got := normalizer.Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestItemAliasDictionary_Canonicalize(t *testing.T) {
+	dictionary := NewItemAliasDictionary([]*ItemAlias{
+		{Alias: "ｷｬﾍﾞﾂ1/2", CanonicalName: "キャベツ 1/2玉"},
+		{Alias: "ｷﾞｭｳﾆｭｳ", CanonicalName: "牛乳"},
+	})
+
+	tests := map[string]string{
+		"ｷｬﾍﾞﾂ1/2": "キャベツ 1/2玉",
+		"キャベツ 1/2": "キャベツ 1/2玉",
+		"ｷﾞｭｳﾆｭｳ":  "牛乳",
+		"キャベツ":     "キャベツ",
+	}
+	for in, want := range tests {
+		if got := dictionary.Canonicalize(in); got != want {
+			t.Errorf("Canonicalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	receipt := &Receipt{Items: []ReceiptItem{{Name: "牛乳"}, {Name: "ｷﾞｭｳﾆｭｳ"}}}
+	if !dictionary.CanonicalizeReceipt(receipt) || receipt.Items[1].Name != "牛乳" {
+		t.Errorf("CanonicalizeReceipt() items = %+v", receipt.Items)
+	}
+	if dictionary.CanonicalizeReceipt(receipt) {
+		t.Error("CanonicalizeReceipt() = true for already canonical names")
+	}
+	if (&ItemAlias{Alias: " ", CanonicalName: "牛乳"}).Validate() == nil {
+		t.Error("Validate() = nil for an empty alias")
+	}
+}
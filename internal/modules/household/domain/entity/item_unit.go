@@ -0,0 +1,106 @@
+package entity
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// 明細の数量の単位
+const (
+	UnitPiece    = "個"  // 個数で数える明細（空の場合も個数として扱う）
+	UnitGram     = "g"  // 量り売りの明細（グラム）
+	UnitKilogram = "kg" // 量り売りの明細（キログラム）
+)
+
+// unitPriceTolerance 量り売りの金額と「単価×計量値」の端数処理の違いとして許容する差（通貨の最小単位）
+const unitPriceTolerance = 1
+
+// NormalizeUnit 読み取った数量の単位を正規化する（グラム・キログラム以外は個数）
+func NormalizeUnit(unit string) string {
+	switch strings.ToLower(norm.NFKC.String(strings.TrimSpace(unit))) {
+	case "g", "gr", "グラム":
+		return UnitGram
+	case "kg", "キロ", "キログラム":
+		return UnitKilogram
+	default:
+		return UnitPiece
+	}
+}
+
+// IsWeighed 量り売りの明細かチェック
+// 量り売りの明細は数量を1とし、Priceに計量した金額、Measureに計量値を持つ
+func (ri *ReceiptItem) IsWeighed() bool {
+	return ri.Unit == UnitGram || ri.Unit == UnitKilogram
+}
+
+// Kilograms 量り売りの計量値をキログラムで返す（量り売りでない場合は0）
+func (ri *ReceiptItem) Kilograms() float64 {
+	switch ri.Unit {
+	case UnitGram:
+		return ri.Measure / 1000
+	case UnitKilogram:
+		return ri.Measure
+	default:
+		return 0
+	}
+}
+
+// QuantityLabel 数量の表示（量り売りは計量値と単位の "320g"、個数で数える明細は "2"）
+func (ri *ReceiptItem) QuantityLabel() string {
+	if ri.IsWeighed() {
+		return strconv.FormatFloat(ri.Measure, 'f', -1, 64) + ri.Unit
+	}
+	return strconv.Itoa(ri.Quantity)
+}
+
+// WeighedAmount 1kgあたりの単価と計量値から求めた金額（単価・計量値がない場合は0）
+func (ri *ReceiptItem) WeighedAmount() int64 {
+	return int64(math.Round(float64(ri.UnitPrice) * ri.Kilograms()))
+}
+
+// HasUnitPriceMismatch 量り売りの金額が「単価×計量値」と一致しないかチェック
+// 単価・計量値を読み取れなかった明細は判定しない
+func (ri *ReceiptItem) HasUnitPriceMismatch() bool {
+	if !ri.IsWeighed() || ri.UnitPrice == 0 || ri.Measure == 0 {
+		return false
+	}
+	// 返品の明細は金額が負のため、金額の絶対値と比較する
+	price := ri.Price
+	if price < 0 {
+		price = -price
+	}
+	diff := price - ri.WeighedAmount()
+	return diff > unitPriceTolerance || diff < -unitPriceTolerance
+}
+
+// HasUnitPriceMismatch 量り売りの明細に金額が「単価×計量値」と一致しないものがあるかチェック
+func (r *Receipt) HasUnitPriceMismatch() bool {
+	for _, item := range r.Items {
+		if item.HasUnitPriceMismatch() {
+			return true
+		}
+	}
+	return false
+}
+
+// PerKilogram 印字された単価（基準量perあたり、unitの単位）を1kgあたりの単価に換算する
+// 例: 100g当り198円は1980円、perが0以下の場合はグラムは100g、キログラムは1kgあたりとする
+func PerKilogram(unitPrice int64, unit string, per float64) int64 {
+	switch unit {
+	case UnitGram:
+		if per <= 0 {
+			per = 100
+		}
+		return int64(math.Round(float64(unitPrice) * 1000 / per))
+	case UnitKilogram:
+		if per <= 0 {
+			per = 1
+		}
+		return int64(math.Round(float64(unitPrice) / per))
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,77 @@
+package entity
+
+import "testing"
+
+func TestNormalizeUnit(t *testing.T) {
+	tests := map[string]string{
+		"g":   UnitGram,
+		"ｇ":   UnitGram,
+		"KG":  UnitKilogram,
+		"キロ":  UnitKilogram,
+		"個":   UnitPiece,
+		"":    UnitPiece,
+		"pcs": UnitPiece,
+	}
+	for in, want := range tests {
+		if got := NormalizeUnit(in); got != want {
+			t.Errorf("NormalizeUnit(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestPerKilogram(t *testing.T) {
+	tests := []struct {
+		unitPrice int64
+		unit      string
+		per       float64
+		want      int64
+	}{
+		{unitPrice: 198, unit: UnitGram, per: 100, want: 1980},
+		{unitPrice: 198, unit: UnitGram, per: 0, want: 1980},
+		{unitPrice: 1200, unit: UnitKilogram, per: 0, want: 1200},
+		{unitPrice: 600, unit: UnitKilogram, per: 0.5, want: 1200},
+		{unitPrice: 100, unit: UnitPiece, per: 1, want: 0},
+	}
+	for _, tt := range tests {
+		if got := PerKilogram(tt.unitPrice, tt.unit, tt.per); got != tt.want {
+			t.Errorf("PerKilogram(%d, %s, %g) = %d, want %d", tt.unitPrice, tt.unit, tt.per, got, tt.want)
+		}
+	}
+}
+
+func TestReceiptItem_Weighed(t *testing.T) {
+	// 100g当り198円の豚肉320g（198×3.2=633.6円）
+	item := ReceiptItem{Name: "豚こま切れ", Quantity: 1, Price: 634, Unit: UnitGram, Measure: 320, UnitPrice: 1980}
+	if item.Amount() != 634 || item.WeighedAmount() != 634 || item.QuantityLabel() != "320g" {
+		t.Errorf("Amount() = %d, WeighedAmount() = %d, QuantityLabel() = %q", item.Amount(), item.WeighedAmount(), item.QuantityLabel())
+	}
+	if item.HasUnitPriceMismatch() {
+		t.Error("HasUnitPriceMismatch() = true for a rounded amount")
+	}
+	// 端数の切り捨ても許容する
+	item.Price = 633
+	if item.HasUnitPriceMismatch() {
+		t.Error("HasUnitPriceMismatch() = true for a truncated amount")
+	}
+	item.Price = 700
+	if !item.HasUnitPriceMismatch() {
+		t.Error("HasUnitPriceMismatch() = false, want true")
+	}
+
+	receipt := &Receipt{StoreName: "スーパー", TotalAmount: 900, Items: []ReceiptItem{item, {Name: "牛乳", Quantity: 1, Price: 200}}}
+	if !receipt.HasUnitPriceMismatch() || receipt.HasTotalMismatch() {
+		t.Errorf("HasUnitPriceMismatch() = %v, HasTotalMismatch() = %v", receipt.HasUnitPriceMismatch(), receipt.HasTotalMismatch())
+	}
+
+	invalid := []ReceiptItem{
+		{Name: "豚肉", Quantity: 1, Price: 634, Unit: UnitGram},
+		{Name: "豚肉", Quantity: 1, Price: 634, Unit: "lb", Measure: 1},
+		{Name: "牛乳", Quantity: 1, Price: 200, Unit: UnitPiece, Measure: 1},
+		{Name: "豚肉", Quantity: 1, Price: 634, Unit: UnitKilogram, Measure: 0.32, UnitPrice: -1},
+	}
+	for _, item := range invalid {
+		if err := item.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want error", item)
+		}
+	}
+}
@@ -1,33 +1,157 @@
 package entity
 
 import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
+
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// 明細カテゴリーの判定状態
+const (
+	CategoryStatusPending    = "pending"     // 判定待ち
+	CategoryStatusAuto       = "auto"        // AIによる自動判定
+	CategoryStatusAutoFailed = "auto_failed" // 自動判定に失敗（要確認）
+	CategoryStatusManual     = "manual"      // 手動設定
+)
+
+// 明細の期限の種類
+const (
+	DeadlineReturn   = "return"   // 返品期限
+	DeadlineWarranty = "warranty" // 保証期限
+)
+
+// レシートの種類
+const (
+	ReceiptTypePurchase = "purchase" // 購入
+	ReceiptTypeRefund   = "refund"   // 返品・返金（合計金額・消費税額は0以下）
 )
 
+// DefaultCategory カテゴリーが未設定の明細を集計するカテゴリー
+const DefaultCategory = "その他"
+
 // Receipt レシートエンティティ
+// 金額はすべて通貨（locale.currency）の最小単位の整数（円・セントなど）
 type Receipt struct {
-	ID            string
-	StoreName     string
-	PurchaseDate  time.Time
-	TotalAmount   int    // 実際に使った金額
-	TaxAmount     int    // 消費税額
-	PaymentMethod string // 支払い方法
-	ReceiptNumber string // レシート番号
-	Category      string
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	Items         []ReceiptItem
+	ID                string
+	StoreName         string
+	PurchaseDate      time.Time
+	TotalAmount       int64  // 実際に使った金額（返品は負）
+	TaxAmount         int64  // 消費税額（返品は負）
+	PaymentMethod     string // 支払い方法
+	ReceiptNumber     string // レシート番号
+	Type              string // レシートの種類（ReceiptTypePurchase・ReceiptTypeRefund、空の場合は購入）
+	Locale            string // レシートのロケール（BCP 47、例: ja-JP、en-US）
+	Currency          string // レシートに印字された通貨（ISO 4217、空の場合は設定の通貨）
+	Category          string
+	NeedsReview       bool              // 要確認フラグ
+	JSONRepaired      bool              // AIの応答のJSONを修復して読み取った（途中で切れた応答など、品質の集計に使う）
+	DateOutOfRange    bool              // 読み取った購入日が妥当な範囲外だった（未来・古すぎる日付、読み間違いの可能性）
+	Extraction        ReceiptExtraction // AIによる画像の読み取りの記録
+	CategorizationRaw string            // カテゴリー判定時のAIレスポンス（原文）
+	Tags              []string
+	Memo              string // 利用者が自由に記入するメモ
+	ImageHash         string // 元画像のSHA256ハッシュ（同じ画像の重複登録の検出に使用）
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	Items             []ReceiptItem
+
+	sharedDomain.Events // 保存後に配信するドメインイベント
+}
+
+// ReceiptExtraction AIによるレシート画像の読み取りの記録（手動登録のレシートはゼロ値）
+type ReceiptExtraction struct {
+	Model      string // 読み取ったAIのモデル（キャッシュした結果の場合は空）
+	StopReason string // AIの出力の終了理由（end_turn、max_tokensなど）
+	CacheHit   bool   // キャッシュした読み取り結果を使った
+	DurationMs int64  // 読み取りにかかった時間（ミリ秒）
+	Raw        string // AIの出力の原文（解析前の読み取り結果のJSON、記録する前に登録したレシートは空）
+}
+
+// IsZero 読み取りの記録がないかチェック
+func (e ReceiptExtraction) IsZero() bool {
+	return e == ReceiptExtraction{}
 }
 
 // ReceiptItem レシート明細エンティティ
 type ReceiptItem struct {
-	ID        string
-	ReceiptID string
-	Name      string
-	Quantity  int
-	Price     int
-	Category  string // 明細項目のカテゴリー
-	CreatedAt time.Time
+	ID             string
+	ReceiptID      string
+	Name           string
+	Quantity       int
+	Price          int64   // 単価（量り売りの明細は計量した金額、値引き・返品は負）
+	Unit           string  // 数量の単位（UnitPiece、量り売りはUnitGram・UnitKilogram、空の場合は個数）
+	Measure        float64 // 量り売りの計量値（Unitの単位）
+	UnitPrice      int64   // 量り売りの1kgあたりの単価
+	Category       string  // 明細項目のカテゴリー
+	CategoryStatus string  // カテゴリーの判定状態
+	WarrantyMonths *int    // 保証期間（月数）、nilの場合は設定の既定値を使う
+	CreatedAt      time.Time
+
+	ReturnNotifiedAt   *time.Time // 返品期限が近いことを通知した日時
+	WarrantyNotifiedAt *time.Time // 保証期限が近いことを通知した日時
+}
+
+// PurchasedItem 購入した明細（明細と購入したレシートの情報）
+type PurchasedItem struct {
+	Item         ReceiptItem
+	StoreName    string
+	PurchaseDate time.Time
+}
+
+// Amount 明細の金額（単価×数量）
+func (p *PurchasedItem) Amount() int64 {
+	return p.Item.Amount()
+}
+
+// CategoryAmount 集計単位の時間帯・カテゴリーごとの支出の合計（データベースで集計した結果）
+type CategoryAmount struct {
+	Time     time.Time // 集計単位の時間帯の開始日時
+	Category string    // 空の場合はカテゴリー未設定
+	Count    int       // 明細・家計簿エントリの件数
+	Total    int64
+}
+
+// ReceiptAmount 集計単位の時間帯ごとのレシートの枚数と合計金額（データベースで集計した結果）
+type ReceiptAmount struct {
+	Time  time.Time // 集計単位の時間帯の開始日時
+	Count int       // レシートの枚数
+	Total int64     // レシートの合計金額の合計
+}
+
+// CategoryBreakdown 期間のカテゴリーごとのレシートの明細・家計簿エントリの件数と金額（データベースで集計した結果）
+type CategoryBreakdown struct {
+	Category     string // 空の場合はカテゴリー未設定
+	ReceiptCount int    // そのカテゴリーの明細を含むレシートの枚数
+	ItemCount    int    // レシートの明細の件数
+	ItemTotal    int64  // レシートの明細の金額（単価×数量）の合計
+	ExpenseCount int    // 家計簿エントリの件数
+	ExpenseTotal int64  // 家計簿エントリの金額の合計
+}
+
+// WarrantyExpiresAt 保証の期限（購入日 + 保証期間）
+// 保証期間が未設定の場合はdefaultMonthsを使い、保証期間が0の場合は保証なしとしてfalseを返す
+func (p *PurchasedItem) WarrantyExpiresAt(defaultMonths int) (time.Time, bool) {
+	months := defaultMonths
+	if p.Item.WarrantyMonths != nil {
+		months = *p.Item.WarrantyMonths
+	}
+	if months <= 0 {
+		return time.Time{}, false
+	}
+	return p.PurchaseDate.AddDate(0, months, 0), true
+}
+
+// ReturnDeadline 返品の期限（購入日 + returnDays日）
+// returnDaysが0以下の場合は返品期限を管理しないためfalseを返す
+func (p *PurchasedItem) ReturnDeadline(returnDays int) (time.Time, bool) {
+	if returnDays <= 0 {
+		return time.Time{}, false
+	}
+	return p.PurchaseDate.AddDate(0, 0, returnDays), true
 }
 
 // ExpenseEntry 家計簿エントリエンティティ
@@ -36,11 +160,14 @@ type ExpenseEntry struct {
 	ReceiptID   *string
 	Date        time.Time
 	Category    string
-	Amount      int
+	Amount      int64
 	Description string
 	Tags        []string
+	Memo        string // 利用者が自由に記入するメモ
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+
+	sharedDomain.Events // 保存後に配信するドメインイベント
 }
 
 // Category カテゴリエンティティ
@@ -52,9 +179,8 @@ type Category struct {
 	CreatedAt   time.Time
 }
 
-// NewReceipt 新しいReceiptを作成
-func NewReceipt(id, storeName string, purchaseDate time.Time, totalAmount, taxAmount int, category string) *Receipt {
-	now := time.Now()
+// NewReceipt 新しいReceiptを作成（nowは作成日時・更新日時、呼び出し側の時計の現在時刻）
+func NewReceipt(id, storeName string, purchaseDate time.Time, totalAmount, taxAmount int64, category string, now time.Time) *Receipt {
 	return &Receipt{
 		ID:            id,
 		StoreName:     storeName,
@@ -70,21 +196,20 @@ func NewReceipt(id, storeName string, purchaseDate time.Time, totalAmount, taxAm
 	}
 }
 
-// NewReceiptItem 新しいReceiptItemを作成
-func NewReceiptItem(id, receiptID, name string, quantity, price int) *ReceiptItem {
+// NewReceiptItem 新しいReceiptItemを作成（nowは作成日時）
+func NewReceiptItem(id, receiptID, name string, quantity int, price int64, now time.Time) *ReceiptItem {
 	return &ReceiptItem{
 		ID:        id,
 		ReceiptID: receiptID,
 		Name:      name,
 		Quantity:  quantity,
 		Price:     price,
-		CreatedAt: time.Now(),
+		CreatedAt: now,
 	}
 }
 
-// NewExpenseEntry 新しいExpenseEntryを作成
-func NewExpenseEntry(id string, date time.Time, category string, amount int, description string, tags []string) *ExpenseEntry {
-	now := time.Now()
+// NewExpenseEntry 新しいExpenseEntryを作成（nowは作成日時・更新日時）
+func NewExpenseEntry(id string, date time.Time, category string, amount int64, description string, tags []string, now time.Time) *ExpenseEntry {
 	return &ExpenseEntry{
 		ID:          id,
 		Date:        date,
@@ -97,14 +222,14 @@ func NewExpenseEntry(id string, date time.Time, category string, amount int, des
 	}
 }
 
-// NewCategory 新しいCategoryを作成
-func NewCategory(id, name, description, color string) *Category {
+// NewCategory 新しいCategoryを作成（nowは作成日時）
+func NewCategory(id, name, description, color string, now time.Time) *Category {
 	return &Category{
 		ID:          id,
 		Name:        name,
 		Description: description,
 		Color:       color,
-		CreatedAt:   time.Now(),
+		CreatedAt:   now,
 	}
 }
 
@@ -113,27 +238,299 @@ func (r *Receipt) AddItem(item *ReceiptItem) {
 	r.Items = append(r.Items, *item)
 }
 
+// RecordCreated レシートの登録をドメインイベントとして記録
+func (r *Receipt) RecordCreated() {
+	r.Record(ReceiptCreated{
+		ReceiptID:   r.ID,
+		StoreName:   r.StoreName,
+		TotalAmount: r.TotalAmount,
+		ItemCount:   len(r.Items),
+		At:          time.Now(),
+	})
+}
+
+// CorrectTotal 合計金額を修正し、変わった場合はドメインイベントとして記録
+func (r *Receipt) CorrectTotal(amount int64) {
+	if amount == r.TotalAmount {
+		return
+	}
+	r.Record(TotalCorrected{ReceiptID: r.ID, Before: r.TotalAmount, After: amount, At: time.Now()})
+	r.TotalAmount = amount
+}
+
+// CategorizeItem i番目の明細のカテゴリーと判定状態を設定し、変わった場合はドメインイベントとして記録
+func (r *Receipt) CategorizeItem(i int, category, status string) {
+	item := &r.Items[i]
+	if item.Category == category && item.CategoryStatus == status {
+		return
+	}
+	item.Category = category
+	item.CategoryStatus = status
+	r.RecordItemCategorized(*item)
+}
+
+// RecordItemCategorized 明細のカテゴリーの設定をドメインイベントとして記録
+// 明細を作り直す場合など、CategorizeItemを使わずにカテゴリーを設定したときに使う
+func (r *Receipt) RecordItemCategorized(item ReceiptItem) {
+	r.Record(ItemCategorized{
+		ReceiptID: r.ID,
+		ItemID:    item.ID,
+		ItemName:  item.Name,
+		Category:  item.Category,
+		Status:    item.CategoryStatus,
+		At:        time.Now(),
+	})
+}
+
+// MarkItemsCategoryFailed 判定待ちの明細をデフォルトカテゴリー・判定失敗として要確認にする
+// 手動で設定したカテゴリーなど判定済みの明細は変更しない
+func (r *Receipt) MarkItemsCategoryFailed(defaultCategory string) {
+	for i := range r.Items {
+		if r.Items[i].IsCategoryPending() {
+			r.CategorizeItem(i, defaultCategory, CategoryStatusAutoFailed)
+			r.NeedsReview = true
+		}
+	}
+}
+
+// HasFailedCategories 判定に失敗した明細があるかチェック
+func (r *Receipt) HasFailedCategories() bool {
+	for _, item := range r.Items {
+		if item.CategoryStatus == CategoryStatusAutoFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPendingCategories カテゴリー判定待ちの明細があるかチェック
+func (r *Receipt) HasPendingCategories() bool {
+	for _, item := range r.Items {
+		if item.CategoryStatus == CategoryStatusPending {
+			return true
+		}
+	}
+	return false
+}
+
+// ItemsTotal 明細の金額（単価×数量）の合計を返す
+func (r *Receipt) ItemsTotal() int64 {
+	var total int64
+	for _, item := range r.Items {
+		total += item.Amount()
+	}
+	return total
+}
+
+// HasTotalMismatch 合計金額が明細の合計と一致しないかチェック
+// 外税のレシートは明細の合計に消費税額を足した金額と一致すればよい。合計金額・明細がない場合は判定しない
+func (r *Receipt) HasTotalMismatch() bool {
+	itemsTotal := r.ItemsTotal()
+	if r.TotalAmount == 0 || itemsTotal == 0 {
+		return false
+	}
+	return r.TotalAmount != itemsTotal && r.TotalAmount != itemsTotal+r.TaxAmount
+}
+
+// IsRefund 返品・返金のレシートかチェック
+func (r *Receipt) IsRefund() bool {
+	return r.Type == ReceiptTypeRefund
+}
+
+// NormalizeRefundAmounts 返品のレシートの合計金額・消費税額を負にそろえる
+// 明細の金額がすべて0以上の場合（印字の絶対値で読み取った場合）は明細の金額も負にする。
+// 返品と購入が混在する明細（交換など）は符号をそのまま使う
+func (r *Receipt) NormalizeRefundAmounts() {
+	if !r.IsRefund() {
+		return
+	}
+	r.TotalAmount = -absAmount(r.TotalAmount)
+	r.TaxAmount = -absAmount(r.TaxAmount)
+	for _, item := range r.Items {
+		if item.Price < 0 {
+			return
+		}
+	}
+	for i := range r.Items {
+		r.Items[i].Price = -r.Items[i].Price
+	}
+}
+
+// absAmount 金額の絶対値
+func absAmount(amount int64) int64 {
+	if amount < 0 {
+		return -amount
+	}
+	return amount
+}
+
+// HasTag 指定したタグが付いているかチェック
+func (r *Receipt) HasTag(tag string) bool {
+	return containsTag(r.Tags, tag)
+}
+
 // TotalItems 明細の合計数を返す
 func (r *Receipt) TotalItems() int {
 	return len(r.Items)
 }
 
-// IsValid レシートが有効かチェック
+// IsValid レシートが有効かチェック（明細は含めない）
 func (r *Receipt) IsValid() bool {
-	return r.StoreName != "" && r.TotalAmount >= 0
+	return r.validateFields() == nil
+}
+
+// Validate レシートと明細を検証し、不正な項目を説明するエラーを返す
+func (r *Receipt) Validate() error {
+	if err := r.validateFields(); err != nil {
+		return err
+	}
+	for i := range r.Items {
+		if err := r.Items[i].Validate(); err != nil {
+			return fmt.Errorf("items[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateFields レシート本体の項目を検証
+// 購入のレシートの合計金額・消費税額は0以上、返品のレシートは0以下とする
+func (r *Receipt) validateFields() error {
+	switch {
+	case strings.TrimSpace(r.StoreName) == "":
+		return errors.New("store_name is required")
+	case r.Type != "" && r.Type != ReceiptTypePurchase && r.Type != ReceiptTypeRefund:
+		return fmt.Errorf("receipt_type must be %s or %s: %q", ReceiptTypePurchase, ReceiptTypeRefund, r.Type)
+	case r.IsRefund() && r.TotalAmount > 0:
+		return fmt.Errorf("total_amount of a refund must not be positive: %d", r.TotalAmount)
+	case r.IsRefund() && r.TaxAmount > 0:
+		return fmt.Errorf("tax_amount of a refund must not be positive: %d", r.TaxAmount)
+	case !r.IsRefund() && r.TotalAmount < 0:
+		return fmt.Errorf("total_amount must not be negative: %d", r.TotalAmount)
+	case !r.IsRefund() && r.TaxAmount < 0:
+		return fmt.Errorf("tax_amount must not be negative: %d", r.TaxAmount)
+	}
+	return nil
+}
+
+// Amount 明細の金額（単価×数量）
+func (ri *ReceiptItem) Amount() int64 {
+	return ri.Price * int64(ri.Quantity)
+}
+
+// IsCategoryPending カテゴリーの判定待ちかチェック（状態が未設定の明細も判定待ちとして扱う）
+func (ri *ReceiptItem) IsCategoryPending() bool {
+	return ri.CategoryStatus == CategoryStatusPending || ri.CategoryStatus == ""
 }
 
 // IsValid 明細が有効かチェック
 func (ri *ReceiptItem) IsValid() bool {
-	return ri.Name != "" && ri.Quantity > 0 && ri.Price >= 0
+	return ri.Validate() == nil
+}
+
+// Validate 明細を検証し、不正な項目を説明するエラーを返す
+func (ri *ReceiptItem) Validate() error {
+	switch {
+	case strings.TrimSpace(ri.Name) == "":
+		return errors.New("name is required")
+	case ri.Quantity <= 0:
+		return fmt.Errorf("quantity must be positive: %d", ri.Quantity)
+	case ri.Unit != "" && ri.Unit != UnitPiece && !ri.IsWeighed():
+		return fmt.Errorf("unit must be %s, %s or %s: %q", UnitPiece, UnitGram, UnitKilogram, ri.Unit)
+	case ri.IsWeighed() && ri.Measure <= 0:
+		return fmt.Errorf("measure must be positive for unit %s: %g", ri.Unit, ri.Measure)
+	case !ri.IsWeighed() && ri.Measure != 0:
+		return fmt.Errorf("measure requires unit %s or %s", UnitGram, UnitKilogram)
+	case ri.UnitPrice < 0:
+		return fmt.Errorf("unit_price must not be negative: %d", ri.UnitPrice)
+	case ri.WarrantyMonths != nil && *ri.WarrantyMonths < 0:
+		return fmt.Errorf("warranty_months must not be negative: %d", *ri.WarrantyMonths)
+	}
+	return nil
+}
+
+// UpdateMemo メモを修正し、変わった場合はドメインイベントとして記録
+func (e *ExpenseEntry) UpdateMemo(memo string) {
+	if memo == e.Memo {
+		return
+	}
+	e.Memo = memo
+	e.Record(ExpenseUpdated{EntryID: e.ID, Fields: []string{"memo"}, At: time.Now()})
+}
+
+// UpdateDetails 日付・カテゴリー・金額・摘要・タグを修正し、変わった項目をドメインイベントとして記録
+func (e *ExpenseEntry) UpdateDetails(date time.Time, category string, amount int64, description string, tags []string) {
+	var fields []string
+	if !date.Equal(e.Date) {
+		e.Date = date
+		fields = append(fields, "date")
+	}
+	if category != e.Category {
+		e.Category = category
+		fields = append(fields, "category")
+	}
+	if amount != e.Amount {
+		e.Amount = amount
+		fields = append(fields, "amount")
+	}
+	if description != e.Description {
+		e.Description = description
+		fields = append(fields, "description")
+	}
+	if !slices.Equal(tags, e.Tags) {
+		e.Tags = tags
+		fields = append(fields, "tags")
+	}
+	if len(fields) == 0 {
+		return
+	}
+	e.Record(ExpenseUpdated{EntryID: e.ID, Fields: fields, At: time.Now()})
+}
+
+// HasTag 指定したタグが付いているかチェック
+func (e *ExpenseEntry) HasTag(tag string) bool {
+	return containsTag(e.Tags, tag)
 }
 
 // IsValid 家計簿エントリが有効かチェック
 func (e *ExpenseEntry) IsValid() bool {
-	return e.Category != "" && e.Amount >= 0
+	return e.Validate() == nil
+}
+
+// Validate 家計簿エントリを検証し、不正な項目を説明するエラーを返す
+func (e *ExpenseEntry) Validate() error {
+	switch {
+	case strings.TrimSpace(e.Category) == "":
+		return errors.New("category is required")
+	case e.Amount < 0:
+		return fmt.Errorf("amount must not be negative: %d", e.Amount)
+	}
+	return nil
 }
 
 // IsValid カテゴリが有効かチェック
 func (c *Category) IsValid() bool {
-	return c.Name != ""
+	return c.Validate() == nil
+}
+
+// NormalizeTags タグの前後の空白を除去し、空文字と重複を取り除く（順序は維持）
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || containsTag(normalized, tag) {
+			continue
+		}
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// containsTag タグ一覧に指定したタグが含まれるかチェック
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
 }
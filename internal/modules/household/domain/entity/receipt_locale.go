@@ -0,0 +1,118 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultReceiptLocale AIがロケールを判定できなかった場合のレシートのロケール
+const DefaultReceiptLocale = "ja-JP"
+
+// DateOrder 日付の年・月・日の並び順
+type DateOrder string
+
+const (
+	DateOrderYMD DateOrder = "ymd" // 2025/03/04（日本・中国・韓国など）
+	DateOrderMDY DateOrder = "mdy" // 03/04/2025（米国など）
+	DateOrderDMY DateOrder = "dmy" // 04.03.2025（欧州など）
+)
+
+// mdyRegions 月/日/年の順で日付を印字する地域
+var mdyRegions = map[string]bool{"US": true, "PH": true, "FM": true, "MH": true, "PW": true}
+
+// ymdRegions 年/月/日の順で日付を印字する地域
+var ymdRegions = map[string]bool{"JP": true, "CN": true, "KR": true, "TW": true, "HU": true, "LT": true, "MN": true}
+
+// decimalCommaLanguages 小数点にカンマ、桁区切りにピリオド・空白を使う言語
+var decimalCommaLanguages = map[string]bool{
+	"de": true, "fr": true, "es": true, "it": true, "nl": true, "pt": true, "ru": true, "pl": true,
+	"sv": true, "da": true, "fi": true, "nb": true, "no": true, "cs": true, "sk": true, "tr": true,
+	"id": true, "vi": true, "el": true, "hu": true, "ro": true, "uk": true, "hr": true, "sl": true,
+}
+
+// ReceiptLocale レシートのロケールと、ロケールに応じた読み取り結果の解釈規則
+type ReceiptLocale struct {
+	Tag          string    // BCP 47の言語タグ（例: ja-JP、en-US、de-DE）
+	DateOrder    DateOrder // 日付の並び順
+	DecimalComma bool      // 小数点がカンマ（例: 12,99）
+}
+
+// NewReceiptLocale 言語タグからレシートのロケールを作成
+// 地域のないタグ（"de"など）は言語から最も可能性の高い地域を補い、解析できないタグは既定値とする
+func NewReceiptLocale(tag string) ReceiptLocale {
+	parsed, err := language.Parse(strings.TrimSpace(tag))
+	if err != nil || parsed == language.Und {
+		parsed = language.MustParse(DefaultReceiptLocale)
+	}
+	base, _ := parsed.Base()
+	region, _ := parsed.Region()
+
+	locale := ReceiptLocale{
+		Tag:          base.String() + "-" + region.String(),
+		DateOrder:    DateOrderDMY,
+		DecimalComma: decimalCommaLanguages[base.String()],
+	}
+	switch {
+	case ymdRegions[region.String()]:
+		locale.DateOrder = DateOrderYMD
+	case mdyRegions[region.String()]:
+		locale.DateOrder = DateOrderMDY
+	}
+	return locale
+}
+
+// dateLayouts 日付の並び順ごとの印字形式
+// 年から始まる形式はどのロケールでも年/月/日の順のため、すべての並び順で解釈する
+var dateLayouts = map[DateOrder][]string{
+	DateOrderYMD: nil,
+	DateOrderMDY: {"01/02/2006", "1/2/2006", "01/02/06", "1/2/06", "01-02-2006", "Jan 2, 2006", "January 2, 2006"},
+	DateOrderDMY: {"02/01/2006", "2/1/2006", "02/01/06", "2/1/06", "02.01.2006", "2.1.2006", "02.01.06", "02-01-2006", "2 Jan 2006", "2 January 2006"},
+}
+
+// isoDateLayouts 年から始まる日付の形式
+var isoDateLayouts = []string{"2006-01-02", "2006/01/02", "2006.01.02", "2006/1/2", "2006年1月2日"}
+
+// timeLayouts 日付に続く時刻の形式（時刻なしを含む）
+var timeLayouts = []string{" 15:04", " 15:04:05", " 3:04 PM", " 3:04PM", ""}
+
+// ParseDate ロケールの並び順で購入日時を解釈する（解釈できない場合はfalse）
+func (l ReceiptLocale) ParseDate(value string, loc *time.Location) (time.Time, bool) {
+	value = strings.Join(strings.Fields(value), " ")
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, dateLayouts := range [][]string{isoDateLayouts, dateLayouts[l.DateOrder]} {
+		for _, dateLayout := range dateLayouts {
+			for _, timeLayout := range timeLayouts {
+				if t, err := time.ParseInLocation(dateLayout+timeLayout, value, loc); err == nil {
+					return t, true
+				}
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// NormalizeAmount ロケールの区切り文字で印字された金額を、小数点がピリオドで桁区切りのない形式にする
+// 通貨記号・空白は取り除く。例: de-DEの "1.234,56 €" → "1234.56"、en-USの "$1,234.56" → "1234.56"
+// 返品・値引きの負の記号（"-"、"△"・"▲"、末尾の "-"）は先頭の "-" にする。例: "1,000-" → "-1000"
+func (l ReceiptLocale) NormalizeAmount(value string) string {
+	negative := strings.ContainsAny(value, "-−－△▲")
+	value = strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || r == '.' || r == ',' {
+			return r
+		}
+		return -1
+	}, value)
+	if l.DecimalComma {
+		value = strings.ReplaceAll(strings.ReplaceAll(value, ".", ""), ",", ".")
+	} else {
+		value = strings.ReplaceAll(value, ",", "")
+	}
+	if negative && value != "" {
+		value = "-" + value
+	}
+	return value
+}
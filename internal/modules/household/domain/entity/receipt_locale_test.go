@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewReceiptLocale(t *testing.T) {
+	tests := []struct {
+		tag          string
+		want         string
+		order        DateOrder
+		decimalComma bool
+	}{
+		{tag: "ja-JP", want: "ja-JP", order: DateOrderYMD},
+		{tag: "en-US", want: "en-US", order: DateOrderMDY},
+		{tag: "en-GB", want: "en-GB", order: DateOrderDMY},
+		{tag: "de", want: "de-DE", order: DateOrderDMY, decimalComma: true},
+		{tag: "fr_FR", want: "fr-FR", order: DateOrderDMY, decimalComma: true},
+		{tag: "", want: "ja-JP", order: DateOrderYMD},
+		{tag: "not a tag", want: "ja-JP", order: DateOrderYMD},
+	}
+	for _, tt := range tests {
+		got := NewReceiptLocale(tt.tag)
+		if got.Tag != tt.want || got.DateOrder != tt.order || got.DecimalComma != tt.decimalComma {
+			t.Errorf("NewReceiptLocale(%q) = %+v, want %s %s decimalComma=%v", tt.tag, got, tt.want, tt.order, tt.decimalComma)
+		}
+	}
+}
+
+func TestReceiptLocale_ParseDate(t *testing.T) {
+	tests := []struct {
+		locale string
+		value  string
+		want   time.Time
+	}{
+		{locale: "ja-JP", value: "2025-11-22 14:30", want: time.Date(2025, 11, 22, 14, 30, 0, 0, time.UTC)},
+		{locale: "ja-JP", value: "2025/3/4", want: time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{locale: "en-US", value: "03/04/2025 2:15 PM", want: time.Date(2025, 3, 4, 14, 15, 0, 0, time.UTC)},
+		{locale: "en-GB", value: "03/04/2025", want: time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)},
+		{locale: "de-DE", value: "04.03.25  18:05", want: time.Date(2025, 3, 4, 18, 5, 0, 0, time.UTC)},
+		// 年から始まる日付はどのロケールでも年/月/日の順
+		{locale: "en-US", value: "2025-03-04", want: time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, ok := NewReceiptLocale(tt.locale).ParseDate(tt.value, time.UTC)
+		if !ok || !got.Equal(tt.want) {
+			t.Errorf("ParseDate(%s, %q) = %v, %v, want %v", tt.locale, tt.value, got, ok, tt.want)
+		}
+	}
+
+	if _, ok := NewReceiptLocale("ja-JP").ParseDate("03/04/2025", time.UTC); ok {
+		t.Error("ParseDate() = true for a month/day date in a year-first locale")
+	}
+}
+
+func TestReceiptLocale_NormalizeAmount(t *testing.T) {
+	tests := []struct {
+		locale string
+		value  string
+		want   string
+	}{
+		{locale: "en-US", value: "$1,234.56", want: "1234.56"},
+		{locale: "de-DE", value: "1.234,56 €", want: "1234.56"},
+		{locale: "fr-FR", value: "12,99", want: "12.99"},
+		{locale: "ja-JP", value: "¥1,500", want: "1500"},
+		{locale: "ja-JP", value: "1,000-", want: "-1000"},
+		{locale: "ja-JP", value: "△¥500", want: "-500"},
+		{locale: "de-DE", value: "-12,99 €", want: "-12.99"},
+	}
+	for _, tt := range tests {
+		if got := NewReceiptLocale(tt.locale).NormalizeAmount(tt.value); got != tt.want {
+			t.Errorf("NormalizeAmount(%s, %q) = %q, want %q", tt.locale, tt.value, got, tt.want)
+		}
+	}
+}
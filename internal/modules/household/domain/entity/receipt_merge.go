@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// ReceiptMerge レシートの統合の記録
+// 同じ買い物を別の写真から二重に登録した場合に、一方のレシートへ明細をまとめてもう一方を削除する。
+// 統合を取り消せるよう、削除したレシートと統合前の残したレシートのスナップショットを保持する
+type ReceiptMerge struct {
+	ID              string
+	ReceiptID       string   // 残したレシートのID
+	MergedReceiptID string   // 統合して削除したレシートのID
+	Before          Receipt  // 統合前の残したレシートのスナップショット
+	MergedReceipt   Receipt  // 削除したレシートのスナップショット
+	AddedItemIDs    []string // 統合で残したレシートに追加した明細のID
+	CreatedAt       time.Time
+}
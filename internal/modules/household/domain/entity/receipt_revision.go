@@ -0,0 +1,41 @@
+package entity
+
+import "time"
+
+// レシート変更の発生元
+const (
+	RevisionSourceOriginal  = "original"  // 変更前の初期状態
+	RevisionSourceManual    = "manual"    // 手動修正
+	RevisionSourceReprocess = "reprocess" // 再処理
+	RevisionSourceRevert    = "revert"    // 過去リビジョンへの巻き戻し
+	RevisionSourceRepair    = "repair"    // 合計金額の修復
+	RevisionSourceMerge     = "merge"     // 二重登録したレシートの統合
+	RevisionSourceUnmerge   = "unmerge"   // 統合の取り消し
+	RevisionSourceNormalize = "normalize" // 店名・商品名の表記の正規化
+)
+
+// ReceiptRevision レシートの変更履歴エンティティ
+// 各リビジョンは変更後のレシート全体のスナップショットを保持する
+type ReceiptRevision struct {
+	ID        string
+	ReceiptID string
+	Revision  int
+	Source    string
+	Snapshot  Receipt
+	CreatedAt time.Time
+}
+
+// NewReceiptRevision 新しいReceiptRevisionを作成（nowは記録日時）
+func NewReceiptRevision(id string, receipt *Receipt, revision int, source string, now time.Time) *ReceiptRevision {
+	snapshot := *receipt
+	snapshot.Items = append([]ReceiptItem(nil), receipt.Items...)
+
+	return &ReceiptRevision{
+		ID:        id,
+		ReceiptID: receipt.ID,
+		Revision:  revision,
+		Source:    source,
+		Snapshot:  snapshot,
+		CreatedAt: now,
+	}
+}
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"slices"
 	"testing"
 	"time"
 )
@@ -9,11 +10,16 @@ func TestNewReceipt(t *testing.T) {
 	id := "test-id"
 	storeName := "テストストア"
 	purchaseDate := time.Now()
-	totalAmount := 1000
-	taxAmount := 100
+	totalAmount := int64(1000)
+	taxAmount := int64(100)
 	category := "食費"
+	createdAt := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
 
-	receipt := NewReceipt(id, storeName, purchaseDate, totalAmount, taxAmount, category)
+	receipt := NewReceipt(id, storeName, purchaseDate, totalAmount, taxAmount, category, createdAt)
+
+	if !receipt.CreatedAt.Equal(createdAt) || !receipt.UpdatedAt.Equal(createdAt) {
+		t.Errorf("CreatedAt/UpdatedAt = %v/%v, want %v", receipt.CreatedAt, receipt.UpdatedAt, createdAt)
+	}
 
 	if receipt.ID != id {
 		t.Errorf("ID = %v, want %v", receipt.ID, id)
@@ -36,10 +42,10 @@ func TestNewReceipt(t *testing.T) {
 }
 
 func TestReceipt_AddItem(t *testing.T) {
-	receipt := NewReceipt("receipt-id", "ストア", time.Now(), 1000, 100, "食費")
+	receipt := NewReceipt("receipt-id", "ストア", time.Now(), 1000, 100, "食費", time.Now())
 
-	item1 := NewReceiptItem("item-1", receipt.ID, "商品1", 2, 500)
-	item2 := NewReceiptItem("item-2", receipt.ID, "商品2", 1, 300)
+	item1 := NewReceiptItem("item-1", receipt.ID, "商品1", 2, 500, time.Now())
+	item2 := NewReceiptItem("item-2", receipt.ID, "商品2", 1, 300, time.Now())
 
 	receipt.AddItem(item1)
 	if receipt.TotalItems() != 1 {
@@ -56,7 +62,7 @@ func TestReceipt_IsValid(t *testing.T) {
 	tests := []struct {
 		name        string
 		storeName   string
-		totalAmount int
+		totalAmount int64
 		want        bool
 	}{
 		{"正常_通常のレシート", "ストア", 1000, true},
@@ -74,6 +80,7 @@ func TestReceipt_IsValid(t *testing.T) {
 				tt.totalAmount,
 				0,
 				"",
+				time.Now(),
 			)
 
 			if got := receipt.IsValid(); got != tt.want {
@@ -88,9 +95,14 @@ func TestNewReceiptItem(t *testing.T) {
 	receiptID := "receipt-id"
 	name := "商品名"
 	quantity := 3
-	price := 500
+	price := int64(500)
+	createdAt := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
 
-	item := NewReceiptItem(id, receiptID, name, quantity, price)
+	item := NewReceiptItem(id, receiptID, name, quantity, price, createdAt)
+
+	if !item.CreatedAt.Equal(createdAt) {
+		t.Errorf("CreatedAt = %v, want %v", item.CreatedAt, createdAt)
+	}
 
 	if item.ID != id {
 		t.Errorf("ID = %v, want %v", item.ID, id)
@@ -114,7 +126,7 @@ func TestReceiptItem_IsValid(t *testing.T) {
 		name     string
 		itemName string
 		quantity int
-		price    int
+		price    int64
 		want     bool
 	}{
 		{"正常_通常の商品", "商品", 1, 100, true},
@@ -123,7 +135,7 @@ func TestReceiptItem_IsValid(t *testing.T) {
 		{"異常_空の商品名", "", 1, 100, false},
 		{"異常_ゼロ数量", "商品", 0, 100, false},
 		{"異常_負の数量", "商品", -1, 100, false},
-		{"異常_負の価格", "商品", 1, -100, false},
+		{"正常_負の価格（値引き・返品）", "商品", 1, -100, true},
 	}
 
 	for _, tt := range tests {
@@ -134,6 +146,7 @@ func TestReceiptItem_IsValid(t *testing.T) {
 				tt.itemName,
 				tt.quantity,
 				tt.price,
+				time.Now(),
 			)
 
 			if got := item.IsValid(); got != tt.want {
@@ -147,11 +160,16 @@ func TestNewExpenseEntry(t *testing.T) {
 	id := "entry-id"
 	date := time.Now()
 	category := "食費"
-	amount := 1500
+	amount := int64(1500)
 	description := "ランチ"
 	tags := []string{"外食", "平日"}
+	createdAt := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
 
-	entry := NewExpenseEntry(id, date, category, amount, description, tags)
+	entry := NewExpenseEntry(id, date, category, amount, description, tags, createdAt)
+
+	if !entry.CreatedAt.Equal(createdAt) || !entry.UpdatedAt.Equal(createdAt) {
+		t.Errorf("CreatedAt/UpdatedAt = %v/%v, want %v", entry.CreatedAt, entry.UpdatedAt, createdAt)
+	}
 
 	if entry.ID != id {
 		t.Errorf("ID = %v, want %v", entry.ID, id)
@@ -174,7 +192,7 @@ func TestExpenseEntry_IsValid(t *testing.T) {
 	tests := []struct {
 		name     string
 		category string
-		amount   int
+		amount   int64
 		want     bool
 	}{
 		{"正常_通常のエントリ", "食費", 1000, true},
@@ -192,6 +210,7 @@ func TestExpenseEntry_IsValid(t *testing.T) {
 				tt.amount,
 				"",
 				[]string{},
+				time.Now(),
 			)
 
 			if got := entry.IsValid(); got != tt.want {
@@ -206,8 +225,13 @@ func TestNewCategory(t *testing.T) {
 	name := "食費"
 	description := "食料品・飲料"
 	color := "#FF6B6B"
+	createdAt := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	category := NewCategory(id, name, description, color, createdAt)
 
-	category := NewCategory(id, name, description, color)
+	if !category.CreatedAt.Equal(createdAt) {
+		t.Errorf("CreatedAt = %v, want %v", category.CreatedAt, createdAt)
+	}
 
 	if category.ID != id {
 		t.Errorf("ID = %v, want %v", category.ID, id)
@@ -240,6 +264,7 @@ func TestCategory_IsValid(t *testing.T) {
 				tt.categoryName,
 				"",
 				"",
+				time.Now(),
 			)
 
 			if got := category.IsValid(); got != tt.want {
@@ -250,7 +275,7 @@ func TestCategory_IsValid(t *testing.T) {
 }
 
 func TestReceipt_TotalItems(t *testing.T) {
-	receipt := NewReceipt("receipt-id", "ストア", time.Now(), 1000, 100, "食費")
+	receipt := NewReceipt("receipt-id", "ストア", time.Now(), 1000, 100, "食費", time.Now())
 
 	// 初期状態
 	if receipt.TotalItems() != 0 {
@@ -259,7 +284,7 @@ func TestReceipt_TotalItems(t *testing.T) {
 
 	// 3個追加
 	for i := 0; i < 3; i++ {
-		item := NewReceiptItem("item-id", receipt.ID, "商品", 1, 100)
+		item := NewReceiptItem("item-id", receipt.ID, "商品", 1, 100, time.Now())
 		receipt.AddItem(item)
 	}
 
@@ -267,3 +292,243 @@ func TestReceipt_TotalItems(t *testing.T) {
 		t.Errorf("TotalItems() = %v, want 3", receipt.TotalItems())
 	}
 }
+
+func TestReceipt_MarkItemsCategoryFailed(t *testing.T) {
+	receipt := NewReceipt("receipt-id", "ストア", time.Now(), 300, 0, "", time.Now())
+	receipt.AddItem(NewReceiptItem("item-1", receipt.ID, "商品1", 1, 100, time.Now()))
+	receipt.AddItem(NewReceiptItem("item-2", receipt.ID, "商品2", 1, 200, time.Now()))
+
+	if receipt.HasFailedCategories() {
+		t.Error("HasFailedCategories() = true before marking, want false")
+	}
+
+	receipt.MarkItemsCategoryFailed("その他")
+
+	for i, item := range receipt.Items {
+		if item.Category != "その他" {
+			t.Errorf("Items[%d].Category = %v, want その他", i, item.Category)
+		}
+		if item.CategoryStatus != CategoryStatusAutoFailed {
+			t.Errorf("Items[%d].CategoryStatus = %v, want %v", i, item.CategoryStatus, CategoryStatusAutoFailed)
+		}
+	}
+	if !receipt.NeedsReview {
+		t.Error("NeedsReview = false, want true")
+	}
+	if !receipt.HasFailedCategories() {
+		t.Error("HasFailedCategories() = false, want true")
+	}
+}
+
+func TestReceipt_MarkItemsCategoryFailed_NoItems(t *testing.T) {
+	receipt := NewReceipt("receipt-id", "ストア", time.Now(), 0, 0, "", time.Now())
+
+	receipt.MarkItemsCategoryFailed("その他")
+
+	if receipt.NeedsReview {
+		t.Error("NeedsReview = true for receipt without items, want false")
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{name: "空白の除去", tags: []string{" 旅行 ", "出張"}, want: []string{"旅行", "出張"}},
+		{name: "重複と空文字の除去", tags: []string{"旅行", "", "旅行", "  "}, want: []string{"旅行"}},
+		{name: "nil", tags: nil, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NormalizeTags(tt.tags)
+			if len(got) != len(tt.want) {
+				t.Fatalf("NormalizeTags() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("NormalizeTags()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReceipt_HasTag(t *testing.T) {
+	receipt := &Receipt{Tags: []string{"旅行"}}
+	if !receipt.HasTag("旅行") {
+		t.Error("HasTag(旅行) = false, want true")
+	}
+	if receipt.HasTag("出張") {
+		t.Error("HasTag(出張) = true, want false")
+	}
+}
+
+func TestReceipt_HasTotalMismatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		receipt Receipt
+		want    bool
+	}{
+		{name: "正常系: 明細の合計と一致", receipt: Receipt{TotalAmount: 300, Items: []ReceiptItem{{Quantity: 3, Price: 100}}}, want: false},
+		{name: "正常系: 外税で一致", receipt: Receipt{TotalAmount: 330, TaxAmount: 30, Items: []ReceiptItem{{Quantity: 3, Price: 100}}}, want: false},
+		{name: "正常系: 明細なし", receipt: Receipt{TotalAmount: 300}, want: false},
+		{name: "正常系: 合計金額が未設定", receipt: Receipt{Items: []ReceiptItem{{Quantity: 3, Price: 100}}}, want: false},
+		{name: "異常系: 値引きなどで不一致", receipt: Receipt{TotalAmount: 250, Items: []ReceiptItem{{Quantity: 3, Price: 100}}}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.receipt.HasTotalMismatch(); got != tt.want {
+				t.Errorf("HasTotalMismatch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPurchasedItem_Deadlines(t *testing.T) {
+	purchased := time.Date(2025, time.January, 31, 0, 0, 0, 0, time.UTC)
+	months := func(n int) *int { return &n }
+
+	tests := []struct {
+		name           string
+		warrantyMonths *int
+		defaultMonths  int
+		wantWarranty   time.Time
+		wantOK         bool
+	}{
+		{name: "既定の保証期間", defaultMonths: 12, wantWarranty: time.Date(2026, time.January, 31, 0, 0, 0, 0, time.UTC), wantOK: true},
+		{name: "明細の保証期間を優先", warrantyMonths: months(3), defaultMonths: 12, wantWarranty: time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC), wantOK: true},
+		{name: "保証なし", warrantyMonths: months(0), defaultMonths: 12},
+		{name: "既定値も未設定", defaultMonths: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := &PurchasedItem{Item: ReceiptItem{WarrantyMonths: tt.warrantyMonths}, PurchaseDate: purchased}
+			got, ok := item.WarrantyExpiresAt(tt.defaultMonths)
+			if ok != tt.wantOK || !got.Equal(tt.wantWarranty) {
+				t.Errorf("WarrantyExpiresAt() = %v, %v, want %v, %v", got, ok, tt.wantWarranty, tt.wantOK)
+			}
+		})
+	}
+
+	item := &PurchasedItem{PurchaseDate: purchased}
+	if got, ok := item.ReturnDeadline(14); !ok || !got.Equal(time.Date(2025, time.February, 14, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("ReturnDeadline(14) = %v, %v", got, ok)
+	}
+	if _, ok := item.ReturnDeadline(0); ok {
+		t.Error("ReturnDeadline(0) ok = true, want false")
+	}
+}
+
+func TestReceipt_DomainEvents(t *testing.T) {
+	receipt := NewReceipt("receipt-1", "ストア", time.Now(), 1000, 100, "食費", time.Now())
+	receipt.AddItem(NewReceiptItem("item-1", "receipt-1", "牛乳", 1, 200, time.Now()))
+
+	receipt.RecordCreated()
+	receipt.CorrectTotal(1000) // 変わらない場合は記録しない
+	receipt.CorrectTotal(1200)
+	receipt.CategorizeItem(0, "食費", CategoryStatusAuto)
+	receipt.CategorizeItem(0, "食費", CategoryStatusAuto)
+
+	events := receipt.PullEvents()
+	want := []string{EventReceiptCreated, EventTotalCorrected, EventItemCategorized}
+	if len(events) != len(want) {
+		t.Fatalf("len(PullEvents()) = %d, want %d", len(events), len(want))
+	}
+	for i, name := range want {
+		if events[i].EventName() != name {
+			t.Errorf("events[%d] = %s, want %s", i, events[i].EventName(), name)
+		}
+	}
+	if got := events[1].(TotalCorrected); got.Before != 1000 || got.After != 1200 {
+		t.Errorf("TotalCorrected = %+v, want 1000 -> 1200", got)
+	}
+	if receipt.TotalAmount != 1200 || receipt.Items[0].Category != "食費" {
+		t.Errorf("TotalAmount/Category = %d/%s, want 1200/食費", receipt.TotalAmount, receipt.Items[0].Category)
+	}
+	if len(receipt.PullEvents()) != 0 {
+		t.Error("PullEvents() should clear recorded events")
+	}
+}
+
+func TestExpenseEntry_UpdateMemo(t *testing.T) {
+	entry := NewExpenseEntry("entry-1", time.Now(), "食費", 500, "昼食代", nil, time.Now())
+	entry.UpdateMemo("")
+	entry.UpdateMemo("昼食")
+
+	events := entry.PullEvents()
+	if len(events) != 1 || events[0].EventName() != EventExpenseUpdated {
+		t.Fatalf("PullEvents() = %v, want one %s", events, EventExpenseUpdated)
+	}
+	if entry.Memo != "昼食" {
+		t.Errorf("Memo = %q, want 昼食", entry.Memo)
+	}
+}
+
+func TestExpenseEntry_UpdateDetails(t *testing.T) {
+	date := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	entry := NewExpenseEntry("entry-1", date, "食費", 500, "昼食代", []string{"外食"}, time.Now())
+	entry.UpdateDetails(date, "食費", 500, "昼食代", []string{"外食"})
+	entry.UpdateDetails(date, "交際費", 800, "昼食代", []string{"外食", "会食"})
+
+	events := entry.PullEvents()
+	if len(events) != 1 {
+		t.Fatalf("PullEvents() = %v, want one event", events)
+	}
+	updated, ok := events[0].(ExpenseUpdated)
+	if !ok || !slices.Equal(updated.Fields, []string{"category", "amount", "tags"}) {
+		t.Errorf("event = %+v, want fields category, amount, tags", events[0])
+	}
+	if entry.Category != "交際費" || entry.Amount != 800 || len(entry.Tags) != 2 {
+		t.Errorf("entry = %+v", entry)
+	}
+}
+
+func TestReceipt_Validate(t *testing.T) {
+	negative := -1
+	tests := []struct {
+		name    string
+		modify  func(r *Receipt)
+		wantErr string
+	}{
+		{name: "有効", modify: func(r *Receipt) {}},
+		{name: "店名が空白", modify: func(r *Receipt) { r.StoreName = "  " }, wantErr: "store_name is required"},
+		{name: "合計金額が負", modify: func(r *Receipt) { r.TotalAmount = -1 }, wantErr: "total_amount must not be negative: -1"},
+		{name: "消費税額が負", modify: func(r *Receipt) { r.TaxAmount = -1 }, wantErr: "tax_amount must not be negative: -1"},
+		{name: "値引きの明細", modify: func(r *Receipt) { r.Items[1].Price = -50; r.TotalAmount = 150 }},
+		{name: "返品", modify: func(r *Receipt) {
+			r.Type = ReceiptTypeRefund
+			r.TotalAmount, r.TaxAmount = -300, -27
+			r.Items[0].Price, r.Items[1].Price = -200, -100
+		}},
+		{name: "返品の合計金額が正", modify: func(r *Receipt) { r.Type = ReceiptTypeRefund }, wantErr: "total_amount of a refund must not be positive: 300"},
+		{name: "不明な種類", modify: func(r *Receipt) { r.Type = "exchange" }, wantErr: `receipt_type must be purchase or refund: "exchange"`},
+		{name: "商品名が空", modify: func(r *Receipt) { r.Items[1].Name = "" }, wantErr: "items[1]: name is required"},
+		{name: "数量が0", modify: func(r *Receipt) { r.Items[0].Quantity = 0 }, wantErr: "items[0]: quantity must be positive: 0"},
+		{name: "保証期間が負", modify: func(r *Receipt) { r.Items[0].WarrantyMonths = &negative }, wantErr: "items[0]: warranty_months must not be negative: -1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipt := NewReceipt("receipt-1", "ストア", time.Now(), 300, 0, "食費", time.Now())
+			receipt.AddItem(NewReceiptItem("item-1", "receipt-1", "牛乳", 1, 200, time.Now()))
+			receipt.AddItem(NewReceiptItem("item-2", "receipt-1", "パン", 1, 100, time.Now()))
+			tt.modify(receipt)
+
+			err := receipt.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
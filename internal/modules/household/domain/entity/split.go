@@ -0,0 +1,232 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSplit 割り勘の指定が不正
+var ErrInvalidSplit = errors.New("invalid split")
+
+// SplitAssignment 参加者ごとの担当する明細の指定
+type SplitAssignment struct {
+	Participant string
+	ItemIDs     []string
+}
+
+// Split レシートの割り勘エンティティ（レシート1件につき1つ）
+type Split struct {
+	ReceiptID    string
+	Payer        string // 支払いを立て替えた参加者（空の場合は精算の状態のみを管理）
+	Participants []SplitParticipant
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// SplitParticipant 割り勘の参加者ごとの負担額と精算の状態
+type SplitParticipant struct {
+	Name      string
+	ItemIDs   []string // 担当する明細（複数の参加者が担当する明細は人数で等分）
+	Subtotal  int64    // 担当する明細の金額
+	Tax       int64    // 支払額と明細の合計の差額（外税・値引きなど）を小計の比率で按分した額
+	Total     int64    // 負担額（Subtotal + Tax）
+	Settled   bool     // 精算済みか（立て替えた参加者は常に精算済み）
+	SettledAt *time.Time
+}
+
+// NewSplit 明細の割り当てから参加者ごとの負担額を計算して割り勘を作成
+// どの参加者にも割り当てていない明細は全員で等分する。
+// 端数は参加者の指定順に最小単位（1円・1セントなど）ずつ配分するため、負担額の合計はレシートの支払額（未設定の場合は明細の合計）と一致する
+func NewSplit(receipt *Receipt, payer string, assignments []SplitAssignment) (*Split, error) {
+	if len(assignments) == 0 {
+		return nil, fmt.Errorf("%w: participants are required", ErrInvalidSplit)
+	}
+
+	items := make(map[string]bool, len(receipt.Items))
+	for _, item := range receipt.Items {
+		items[item.ID] = true
+	}
+
+	participants := make([]SplitParticipant, len(assignments))
+	names := make(map[string]bool, len(assignments))
+	owners := make(map[string][]int)
+	for i, assignment := range assignments {
+		name := strings.TrimSpace(assignment.Participant)
+		if name == "" {
+			return nil, fmt.Errorf("%w: participant name is required", ErrInvalidSplit)
+		}
+		if names[name] {
+			return nil, fmt.Errorf("%w: duplicate participant: %s", ErrInvalidSplit, name)
+		}
+		names[name] = true
+
+		participants[i] = SplitParticipant{Name: name, ItemIDs: []string{}}
+		for _, itemID := range assignment.ItemIDs {
+			if !items[itemID] {
+				return nil, fmt.Errorf("%w: unknown item: %s", ErrInvalidSplit, itemID)
+			}
+			if owned := owners[itemID]; len(owned) > 0 && owned[len(owned)-1] == i {
+				continue
+			}
+			owners[itemID] = append(owners[itemID], i)
+			participants[i].ItemIDs = append(participants[i].ItemIDs, itemID)
+		}
+	}
+
+	payer = strings.TrimSpace(payer)
+	if payer != "" && !names[payer] {
+		return nil, fmt.Errorf("%w: payer is not a participant: %s", ErrInvalidSplit, payer)
+	}
+
+	everyone := make([]int, len(participants))
+	for i := range everyone {
+		everyone[i] = i
+	}
+
+	subtotals := make([]int64, len(participants))
+	var itemsTotal int64
+	for _, item := range receipt.Items {
+		owned := owners[item.ID]
+		if len(owned) == 0 {
+			owned = everyone
+		}
+		amount := item.Amount()
+		itemsTotal += amount
+		for k, share := range divide(amount, len(owned)) {
+			subtotals[owned[k]] += share
+		}
+	}
+
+	var adjustment int64
+	if receipt.TotalAmount != 0 {
+		adjustment = receipt.TotalAmount - itemsTotal
+	}
+	taxes := prorate(adjustment, subtotals)
+
+	now := time.Now()
+	for i := range participants {
+		participants[i].Subtotal = subtotals[i]
+		participants[i].Tax = taxes[i]
+		participants[i].Total = subtotals[i] + taxes[i]
+		if participants[i].Name == payer {
+			participants[i].Settled = true
+			participants[i].SettledAt = &now
+		}
+	}
+
+	return &Split{
+		ReceiptID:    receipt.ID,
+		Payer:        payer,
+		Participants: participants,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, nil
+}
+
+// Participant 参加者を名前で検索
+func (s *Split) Participant(name string) (*SplitParticipant, bool) {
+	for i := range s.Participants {
+		if s.Participants[i].Name == name {
+			return &s.Participants[i], true
+		}
+	}
+	return nil, false
+}
+
+// IsSettled 全員の精算が済んでいるかチェック
+func (s *Split) IsSettled() bool {
+	for _, p := range s.Participants {
+		if !p.Settled {
+			return false
+		}
+	}
+	return true
+}
+
+// UnsettledTotal 未精算の負担額の合計
+func (s *Split) UnsettledTotal() int64 {
+	var total int64
+	for _, p := range s.Participants {
+		if !p.Settled {
+			total += p.Total
+		}
+	}
+	return total
+}
+
+// divide 金額をn人で等分する（端数は先頭から最小単位ずつ配分、返品の負の金額も同様）
+func divide(amount int64, n int) []int64 {
+	sign := int64(1)
+	if amount < 0 {
+		sign, amount = -1, -amount
+	}
+	shares := make([]int64, n)
+	for i := range shares {
+		shares[i] = amount / int64(n)
+		if int64(i) < amount%int64(n) {
+			shares[i]++
+		}
+		shares[i] *= sign
+	}
+	return shares
+}
+
+// prorate 金額を重みの比率で配分する（最大剰余方式で合計を一致させる）
+// 重みの合計が0の場合は等分する
+func prorate(amount int64, weights []int64) []int64 {
+	result := make([]int64, len(weights))
+	if amount == 0 || len(weights) == 0 {
+		return result
+	}
+
+	sign := int64(1)
+	if amount < 0 {
+		sign, amount = -1, -amount
+	}
+
+	var total int64
+	for _, w := range weights {
+		total += w
+	}
+	if total == 0 {
+		for i, share := range divide(amount, len(weights)) {
+			result[i] = sign * share
+		}
+		return result
+	}
+	if total < 0 {
+		// 返品のレシートは小計が負のため、重みの符号を反転して比率を求める
+		negated := make([]int64, len(weights))
+		for i, w := range weights {
+			negated[i] = -w
+		}
+		weights, total = negated, -total
+	}
+
+	type remainder struct {
+		index int
+		value int64
+	}
+	remainders := make([]remainder, len(weights))
+	var allocated int64
+	for i, w := range weights {
+		product := amount * w
+		result[i] = product / total
+		remainders[i] = remainder{index: i, value: product % total}
+		allocated += result[i]
+	}
+	sort.SliceStable(remainders, func(i, j int) bool {
+		return remainders[i].value > remainders[j].value
+	})
+	for k := int64(0); k < amount-allocated; k++ {
+		result[remainders[k].index]++
+	}
+
+	for i := range result {
+		result[i] *= sign
+	}
+	return result
+}
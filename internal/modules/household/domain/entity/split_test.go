@@ -0,0 +1,159 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewSplit(t *testing.T) {
+	receipt := &Receipt{
+		ID:          "receipt-1",
+		TotalAmount: 1100,
+		Items: []ReceiptItem{
+			{ID: "beer", Quantity: 2, Price: 300},
+			{ID: "salad", Quantity: 1, Price: 200},
+			{ID: "snack", Quantity: 1, Price: 100},
+			{ID: "water", Quantity: 1, Price: 100},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		receipt      *Receipt
+		payer        string
+		assignments  []SplitAssignment
+		wantSubtotal []int64
+		wantTax      []int64
+		wantErr      bool
+	}{
+		{
+			name:  "正常系: 担当する明細と消費税の按分",
+			payer: "A",
+			assignments: []SplitAssignment{
+				{Participant: "A", ItemIDs: []string{"beer"}},
+				{Participant: "B", ItemIDs: []string{"salad", "snack"}},
+				{Participant: "C", ItemIDs: []string{"water"}},
+			},
+			wantSubtotal: []int64{600, 300, 100},
+			wantTax:      []int64{60, 30, 10},
+		},
+		{
+			name: "正常系: 共有する明細と割り当てのない明細は等分（端数は指定順）",
+			assignments: []SplitAssignment{
+				{Participant: "A", ItemIDs: []string{"beer", "snack"}},
+				{Participant: "B", ItemIDs: []string{"beer", "salad"}},
+				{Participant: "C"},
+			},
+			// beer 600 = 300 + 300, snack 100, salad 200, water 100 = 34 + 33 + 33
+			wantSubtotal: []int64{434, 533, 33},
+			wantTax:      []int64{44, 53, 3},
+		},
+		{
+			name: "正常系: 返品は負の金額を按分",
+			receipt: &Receipt{ID: "r", Type: ReceiptTypeRefund, TotalAmount: -1100, Items: []ReceiptItem{
+				{ID: "x", Quantity: 1, Price: -600}, {ID: "y", Quantity: 1, Price: -400}, {ID: "z", Quantity: 1, Price: -1},
+			}},
+			assignments: []SplitAssignment{
+				{Participant: "A", ItemIDs: []string{"x", "z"}},
+				{Participant: "B", ItemIDs: []string{"y"}},
+				{Participant: "C", ItemIDs: []string{"z"}},
+			},
+			// z -1 = -1 + 0
+			wantSubtotal: []int64{-601, -400, 0},
+			wantTax:      []int64{-59, -40, 0},
+		},
+		{
+			name:    "正常系: 値引きは負の額で按分",
+			receipt: &Receipt{ID: "r", TotalAmount: 900, Items: []ReceiptItem{{ID: "x", Quantity: 1, Price: 500}, {ID: "y", Quantity: 1, Price: 500}}},
+			assignments: []SplitAssignment{
+				{Participant: "A", ItemIDs: []string{"x"}},
+				{Participant: "B", ItemIDs: []string{"y"}},
+			},
+			wantSubtotal: []int64{500, 500},
+			wantTax:      []int64{-50, -50},
+		},
+		{
+			name:    "正常系: 合計金額が未設定の場合は按分しない",
+			receipt: &Receipt{ID: "r", Items: []ReceiptItem{{ID: "x", Quantity: 1, Price: 500}}},
+			assignments: []SplitAssignment{
+				{Participant: "A"},
+				{Participant: "B"},
+				{Participant: "C"},
+			},
+			wantSubtotal: []int64{167, 167, 166},
+			wantTax:      []int64{0, 0, 0},
+		},
+		{name: "異常系: 参加者なし", wantErr: true},
+		{name: "異常系: 参加者名が空", assignments: []SplitAssignment{{Participant: " "}}, wantErr: true},
+		{name: "異常系: 参加者名の重複", assignments: []SplitAssignment{{Participant: "A"}, {Participant: "A"}}, wantErr: true},
+		{name: "異常系: 存在しない明細", assignments: []SplitAssignment{{Participant: "A", ItemIDs: []string{"unknown"}}}, wantErr: true},
+		{name: "異常系: 立て替えた人が参加者にいない", payer: "Z", assignments: []SplitAssignment{{Participant: "A"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.receipt
+			if r == nil {
+				r = receipt
+			}
+
+			split, err := NewSplit(r, tt.payer, tt.assignments)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidSplit) {
+					t.Fatalf("NewSplit() error = %v, want ErrInvalidSplit", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewSplit() error = %v", err)
+			}
+
+			var total int64
+			for i, p := range split.Participants {
+				if p.Subtotal != tt.wantSubtotal[i] || p.Tax != tt.wantTax[i] || p.Total != p.Subtotal+p.Tax {
+					t.Errorf("participant %s = subtotal %d, tax %d, total %d, want subtotal %d, tax %d",
+						p.Name, p.Subtotal, p.Tax, p.Total, tt.wantSubtotal[i], tt.wantTax[i])
+				}
+				if p.Settled != (p.Name == tt.payer) {
+					t.Errorf("participant %s settled = %v", p.Name, p.Settled)
+				}
+				total += p.Total
+			}
+
+			want := r.TotalAmount
+			if want == 0 {
+				want = r.ItemsTotal()
+			}
+			if total != want {
+				t.Errorf("sum of totals = %d, want %d", total, want)
+			}
+		})
+	}
+}
+
+func TestSplit_Settlement(t *testing.T) {
+	split := &Split{Participants: []SplitParticipant{
+		{Name: "A", Total: 500, Settled: true},
+		{Name: "B", Total: 300},
+		{Name: "C", Total: 200},
+	}}
+
+	if split.IsSettled() {
+		t.Error("IsSettled() = true, want false")
+	}
+	if got := split.UnsettledTotal(); got != 500 {
+		t.Errorf("UnsettledTotal() = %d, want 500", got)
+	}
+	if p, ok := split.Participant("B"); !ok || p.Total != 300 {
+		t.Errorf("Participant(B) = %+v, %v", p, ok)
+	}
+	if _, ok := split.Participant("Z"); ok {
+		t.Error("Participant(Z) found, want not found")
+	}
+
+	split.Participants[1].Settled = true
+	split.Participants[2].Settled = true
+	if !split.IsSettled() || split.UnsettledTotal() != 0 {
+		t.Errorf("IsSettled() = %v, UnsettledTotal() = %d after settling all", split.IsSettled(), split.UnsettledTotal())
+	}
+}
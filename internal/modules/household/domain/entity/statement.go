@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// StatementLine 銀行・クレジットカードの利用明細（CSV）の1行
+type StatementLine struct {
+	Row         int       // CSVの行番号（1始まり）
+	Date        time.Time // 利用日（取引日）
+	Description string    // 摘要・利用店名
+	Amount      int64     // 金額（通貨の最小単位、支払いは正、入金・返金は負）
+}
+
+// IsCharge 支払い（レシートと突き合わせる対象）かチェック
+func (l StatementLine) IsCharge() bool {
+	return l.Amount > 0
+}
+
+// IsRefund 入金・返金（返品のレシートと突き合わせる対象）かチェック
+func (l StatementLine) IsRefund() bool {
+	return l.Amount < 0
+}
@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+// ゴミ箱の項目の種類
+const (
+	TrashKindReceipt = "receipt" // レシート
+	TrashKindExpense = "expense" // 家計簿エントリ
+)
+
+// TrashEntry 削除してゴミ箱に移したレシート・家計簿エントリ
+// 削除時点のスナップショットを保持し、保持期限（PurgeAt）までは元に戻せる
+type TrashEntry struct {
+	Kind      string
+	ID        string        // 削除したレシート・家計簿エントリのID
+	Receipt   *Receipt      // Kindがreceiptの場合のスナップショット
+	Expense   *ExpenseEntry // Kindがexpenseの場合のスナップショット
+	DeletedAt time.Time
+	PurgeAt   time.Time // 完全に削除する日時
+}
+
+// RemainingDays 完全に削除されるまでの日数（1日未満は切り上げ、期限を過ぎた場合は0）
+func (e *TrashEntry) RemainingDays(now time.Time) int {
+	remaining := e.PurgeAt.Sub(now)
+	if remaining <= 0 {
+		return 0
+	}
+	days := int(remaining / (24 * time.Hour))
+	if remaining%(24*time.Hour) > 0 {
+		days++
+	}
+	return days
+}
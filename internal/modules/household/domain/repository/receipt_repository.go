@@ -7,35 +7,137 @@ import (
 	"vision-api-app/internal/modules/household/domain/entity"
 )
 
+// ReceiptFilter レシート検索条件（空の項目は条件に含めない）
+type ReceiptFilter struct {
+	Tag      string // 指定したタグが付いているレシート
+	Keyword  string // 店名・メモ・商品名の部分一致
+	Category string // 指定したカテゴリーの明細を含むレシート
+}
+
+// ExpenseFilter 家計簿エントリの検索条件（空の項目は条件にしない）
+type ExpenseFilter struct {
+	Category string    // 指定したカテゴリーの家計簿エントリ
+	Start    time.Time // 日付がStart以降の家計簿エントリ
+	End      time.Time // 日付がEndより前の家計簿エントリ
+}
+
+// CRUDRepository IDで作成・取得・更新・削除するリポジトリの共通のインターフェース
+// エンティティごとのリポジトリはこれを埋め込み、検索などの固有のメソッドを追加する
+type CRUDRepository[E any] interface {
+	Create(ctx context.Context, e *E) error
+	FindByID(ctx context.Context, id string) (*E, error)
+	Update(ctx context.Context, e *E) error
+	Delete(ctx context.Context, id string) error
+}
+
 // ReceiptRepository レシートリポジトリのインターフェース
 type ReceiptRepository interface {
-	Create(ctx context.Context, receipt *entity.Receipt) error
-	FindByID(ctx context.Context, id string) (*entity.Receipt, error)
+	CRUDRepository[entity.Receipt]
+	// FindByIDs 指定したIDのレシートを明細とともにまとめて取得（idsの順、存在しないIDは含めない）
+	// レシートごとにFindByIDを呼ぶ代わりに使い、件数によらずクエリ数を一定にする
+	FindByIDs(ctx context.Context, ids []string) ([]*entity.Receipt, error)
+	FindByImageHash(ctx context.Context, imageHash string) (*entity.Receipt, error)
 	FindAll(ctx context.Context, limit, offset int) ([]*entity.Receipt, error)
 	FindByDateRange(ctx context.Context, start, end time.Time) ([]*entity.Receipt, error)
-	Update(ctx context.Context, receipt *entity.Receipt) error
-	Delete(ctx context.Context, id string) error
+	// ForEachByDateRange 購入日時がstartからendまでのレシートを購入日の古い順に1件ずつfnに渡す
+	// 一定件数ごとに読み込むため、期間のレシート全体をメモリに保持しない。fnがエラーを返した場合はそのエラーで終了する
+	ForEachByDateRange(ctx context.Context, start, end time.Time, fn func(*entity.Receipt) error) error
+	FindNeedsReview(ctx context.Context, limit, offset int) ([]*entity.Receipt, error)
+	FindByFilter(ctx context.Context, filter ReceiptFilter, limit, offset int) ([]*entity.Receipt, error)
+	// FindItemsByCategory 指定したカテゴリーの明細をレシートをまたいで購入日の新しい順に取得
+	FindItemsByCategory(ctx context.Context, category string, limit, offset int) ([]*entity.PurchasedItem, error)
+	// FindItemsByNormalizedName 正規化した商品名（entity.NormalizeItemName）が一致する明細を購入日の古い順に取得
+	FindItemsByNormalizedName(ctx context.Context, normalizedName string, limit, offset int) ([]*entity.PurchasedItem, error)
+	// FindWarrantyItems 単価がminPrice以上、または保証期間を設定した明細を購入日の古い順に取得
+	FindWarrantyItems(ctx context.Context, minPrice int64) ([]*entity.PurchasedItem, error)
+	// MarkItemNotified 明細の期限（entity.DeadlineReturn / entity.DeadlineWarranty）を通知済みにする
+	MarkItemNotified(ctx context.Context, itemID, kind string, notifiedAt time.Time) error
+}
+
+// ReceiptSpool データベースに保存できなかったレシートの一時保管先のインターフェース
+// 障害中に受け付けたレシートを保持し、復旧後にデータベースへ書き戻すために使用する
+type ReceiptSpool interface {
+	// Put レシートを一時保管（同じIDのレシートは上書き）
+	Put(ctx context.Context, receipt *entity.Receipt) error
+	// List 一時保管中のレシートを保管した順に取得
+	List(ctx context.Context) ([]*entity.Receipt, error)
+	// Remove 一時保管中のレシートを削除（存在しない場合もエラーにしない）
+	Remove(ctx context.Context, id string) error
+}
+
+// ReceiptRevisionRepository レシート変更履歴リポジトリのインターフェース
+type ReceiptRevisionRepository interface {
+	Create(ctx context.Context, revision *entity.ReceiptRevision) error
+	FindByReceiptID(ctx context.Context, receiptID string) ([]*entity.ReceiptRevision, error)
+	FindByRevision(ctx context.Context, receiptID string, revision int) (*entity.ReceiptRevision, error)
+}
+
+// ReceiptMergeRepository レシートの統合の記録リポジトリのインターフェース
+type ReceiptMergeRepository interface {
+	Create(ctx context.Context, merge *entity.ReceiptMerge) error
+	// FindByReceiptID 残したレシートに統合したレシートの記録を統合した順に取得
+	FindByReceiptID(ctx context.Context, receiptID string) ([]*entity.ReceiptMerge, error)
+	// FindByMergedReceiptID 統合して削除したレシートのIDで記録を取得
+	FindByMergedReceiptID(ctx context.Context, mergedReceiptID string) (*entity.ReceiptMerge, error)
+	// DeleteByMergedReceiptID 統合の記録を削除（統合の取り消し後に使う）
+	DeleteByMergedReceiptID(ctx context.Context, mergedReceiptID string) error
+}
+
+// TrashRepository ゴミ箱リポジトリのインターフェース
+type TrashRepository interface {
+	Create(ctx context.Context, entry *entity.TrashEntry) error
+	// FindAll ゴミ箱の項目を削除日時の新しい順に取得（kindが空の場合はすべての種類）
+	FindAll(ctx context.Context, kind string, limit, offset int) ([]*entity.TrashEntry, error)
+	FindByID(ctx context.Context, kind, id string) (*entity.TrashEntry, error)
+	// FindExpired 完全に削除する日時がbefore以前の項目を古い順に最大limit件取得
+	FindExpired(ctx context.Context, before time.Time, limit int) ([]*entity.TrashEntry, error)
+	Delete(ctx context.Context, kind, id string) error
+}
+
+// ItemAliasRepository 商品名の別名辞書リポジトリのインターフェース
+type ItemAliasRepository interface {
+	// FindAll 別名を登録時の表記の順に取得
+	FindAll(ctx context.Context) ([]*entity.ItemAlias, error)
+	// Save 別名を登録（同じキーの別名が登録済みの場合は上書き）
+	Save(ctx context.Context, alias *entity.ItemAlias) error
+	// Delete 別名を削除（登録されていない場合はentity.ErrItemAliasNotFound）
+	Delete(ctx context.Context, alias string) error
 }
 
 // ExpenseRepository 家計簿リポジトリのインターフェース
 type ExpenseRepository interface {
-	Create(ctx context.Context, entry *entity.ExpenseEntry) error
-	FindByID(ctx context.Context, id string) (*entity.ExpenseEntry, error)
+	CRUDRepository[entity.ExpenseEntry]
 	FindAll(ctx context.Context, limit, offset int) ([]*entity.ExpenseEntry, error)
 	FindByDateRange(ctx context.Context, start, end time.Time) ([]*entity.ExpenseEntry, error)
 	FindByCategory(ctx context.Context, category string) ([]*entity.ExpenseEntry, error)
-	Update(ctx context.Context, entry *entity.ExpenseEntry) error
-	Delete(ctx context.Context, id string) error
+	// FindByFilter 検索条件に一致する家計簿エントリを日付の新しい順に取得
+	FindByFilter(ctx context.Context, filter ExpenseFilter, limit, offset int) ([]*entity.ExpenseEntry, error)
+	// FindByReceiptID レシートに紐づく家計簿エントリを日付の古い順に取得
+	FindByReceiptID(ctx context.Context, receiptID string) ([]*entity.ExpenseEntry, error)
+}
+
+// AggregateBucket 集計リポジトリが支出をまとめる時間帯の長さ
+// すべてのタイムゾーンのUTCからのずれは15分の倍数のため、どのタイムゾーンの日の境界も時間帯の途中にならない
+const AggregateBucket = 15 * time.Minute
+
+// AggregateRepository レポート用の集計リポジトリのインターフェース
+// レシート・明細を読み込まずにデータベースで合計し、集計単位の時間帯（AggregateBucket）・カテゴリーごとに返す
+type AggregateRepository interface {
+	// SumItemsByCategory 購入日時がstartからendまでのレシートの明細の金額（単価×数量）を合計
+	SumItemsByCategory(ctx context.Context, start, end time.Time) ([]*entity.CategoryAmount, error)
+	// SumExpensesByCategory 日付がstartからendまでのカテゴリー付きの家計簿エントリの金額を合計
+	SumExpensesByCategory(ctx context.Context, start, end time.Time) ([]*entity.CategoryAmount, error)
+	// SumReceipts 購入日時がstartからendまでのレシートの枚数と合計金額を合計
+	SumReceipts(ctx context.Context, start, end time.Time) ([]*entity.ReceiptAmount, error)
+	// SumByCategory 購入日時・日付がstartからendまでのレシートの明細とカテゴリー付きの家計簿エントリを、時間帯に分けずにカテゴリーごとに合計
+	SumByCategory(ctx context.Context, start, end time.Time) ([]*entity.CategoryBreakdown, error)
 }
 
 // CategoryRepository カテゴリリポジトリのインターフェース
 type CategoryRepository interface {
-	Create(ctx context.Context, category *entity.Category) error
-	FindByID(ctx context.Context, id string) (*entity.Category, error)
+	CRUDRepository[entity.Category]
 	FindAll(ctx context.Context) ([]*entity.Category, error)
 	FindByName(ctx context.Context, name string) (*entity.Category, error)
-	Update(ctx context.Context, category *entity.Category) error
-	Delete(ctx context.Context, id string) error
 }
 
 // CacheRepository キャッシュリポジトリのインターフェース
@@ -45,3 +147,49 @@ type CacheRepository interface {
 	Delete(ctx context.Context, key string) error
 	Exists(ctx context.Context, key string) (bool, error)
 }
+
+// SplitRepository 割り勘リポジトリのインターフェース
+type SplitRepository interface {
+	// Save 割り勘を保存（同じレシートの割り勘は上書き）
+	Save(ctx context.Context, split *entity.Split) error
+	FindByReceiptID(ctx context.Context, receiptID string) (*entity.Split, error)
+	// FindUnsettled 未精算の参加者が残る割り勘を新しい順に取得
+	FindUnsettled(ctx context.Context, limit, offset int) ([]*entity.Split, error)
+	DeleteByReceiptID(ctx context.Context, receiptID string) error
+}
+
+// CollectionRepository コレクションリポジトリのインターフェース（Create・Updateは含めるレシートを含めて上書きする）
+type CollectionRepository interface {
+	CRUDRepository[entity.Collection]
+	// FindAll コレクションを名前の順に取得
+	FindAll(ctx context.Context, limit, offset int) ([]*entity.Collection, error)
+	// FindByReceiptID 指定したレシートを含むコレクションを名前の順に取得
+	FindByReceiptID(ctx context.Context, receiptID string) ([]*entity.Collection, error)
+}
+
+// AccountingSyncRepository 会計サービスとの同期状態リポジトリのインターフェース
+type AccountingSyncRepository interface {
+	// Save 同期状態を保存（同じレシート・会計サービスの同期状態は上書き）
+	Save(ctx context.Context, sync *entity.AccountingSync) error
+	// FindByReceiptID レシートの会計サービスごとの同期状態を取得
+	FindByReceiptID(ctx context.Context, receiptID string) ([]*entity.AccountingSync, error)
+	// FindByReceiptIDs 複数のレシートの会計サービスごとの同期状態をまとめて取得
+	FindByReceiptIDs(ctx context.Context, receiptIDs []string) ([]*entity.AccountingSync, error)
+	// FindByStatus 指定した状態の同期状態を更新日時の新しい順に取得
+	FindByStatus(ctx context.Context, provider, status string, limit, offset int) ([]*entity.AccountingSync, error)
+	// FindPendingReceiptIDs 送信が必要なレシートのIDを取得
+	// 要確認ではなく、未送信・送信後に更新された・失敗回数がmaxAttempts未満のレシートが対象（競合中は除く）
+	FindPendingReceiptIDs(ctx context.Context, provider string, maxAttempts, limit int) ([]string, error)
+}
+
+// AccountingRepository 会計サービスのAPIのインターフェース
+type AccountingRepository interface {
+	// Provider 会計サービスの名前（entity.AccountingProviderFreee など）
+	Provider() string
+	// CreateDeal 取引を登録し、取引IDと登録後の版を返す
+	CreateDeal(ctx context.Context, deal *entity.AccountingDeal) (externalID, version string, err error)
+	// UpdateDeal 取引を更新し、更新後の版を返す
+	UpdateDeal(ctx context.Context, externalID string, deal *entity.AccountingDeal) (version string, err error)
+	// DealVersion 会計サービス側の取引の現在の版を取得
+	DealVersion(ctx context.Context, externalID string) (string, error)
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/usecase"
+)
+
+// AccountingHandler 会計サービスとの同期APIのハンドラー
+type AccountingHandler struct {
+	syncUseCase *usecase.AccountingSyncUseCase
+}
+
+// NewAccountingHandler 新しいAccountingHandlerを作成
+func NewAccountingHandler(syncUseCase *usecase.AccountingSyncUseCase) *AccountingHandler {
+	return &AccountingHandler{
+		syncUseCase: syncUseCase,
+	}
+}
+
+// resolveRequest 競合の解決リクエスト
+type resolveRequest struct {
+	Strategy string `json:"strategy"` // local: レシートで上書き, remote: 会計サービス側を正とする
+}
+
+// AccountingSyncResponse 同期状態のレスポンス
+type AccountingSyncResponse struct {
+	ReceiptID  string     `json:"receipt_id"`
+	Provider   string     `json:"provider"`
+	Status     string     `json:"status"`
+	ExternalID string     `json:"external_id,omitempty"`
+	Attempts   int        `json:"attempts"`
+	LastError  string     `json:"last_error,omitempty"`
+	SyncedAt   *time.Time `json:"synced_at,omitempty"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// HandleGetStatus レシートの会計サービスごとの同期状態を取得
+func (h *AccountingHandler) HandleGetStatus(w http.ResponseWriter, r *http.Request) {
+	syncs, err := h.syncUseCase.GetSyncStatus(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, "Failed to get sync status", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newAccountingSyncResponses(syncs))
+}
+
+// HandleSync レシートを会計サービスへすぐに送信する
+func (h *AccountingHandler) HandleSync(w http.ResponseWriter, r *http.Request) {
+	sync, err := h.syncUseCase.SyncReceipt(r.Context(), r.PathValue("provider"), r.PathValue("id"))
+	if errors.Is(err, usecase.ErrUnknownProvider) {
+		writeError(w, "Accounting provider is not enabled", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, usecase.ErrSyncConflict) {
+		writeJSON(w, http.StatusConflict, newAccountingSyncResponse(sync))
+		return
+	}
+	if errors.Is(err, usecase.ErrReceiptNotFound) {
+		writeError(w, "Receipt not found", http.StatusNotFound)
+		return
+	}
+	if err != nil && sync != nil {
+		writeError(w, "Failed to sync receipt: "+sync.LastError, http.StatusBadGateway)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to sync receipt", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newAccountingSyncResponse(sync))
+}
+
+// HandleResolve 競合した同期状態を解決する
+func (h *AccountingHandler) HandleResolve(w http.ResponseWriter, r *http.Request) {
+	var req resolveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil ||
+		(req.Strategy != usecase.SyncResolveLocal && req.Strategy != usecase.SyncResolveRemote) {
+		writeError(w, "Invalid request: strategy must be local or remote", http.StatusBadRequest)
+		return
+	}
+
+	sync, err := h.syncUseCase.Resolve(r.Context(), r.PathValue("provider"), r.PathValue("id"), req.Strategy)
+	if errors.Is(err, usecase.ErrUnknownProvider) {
+		writeError(w, "Accounting provider is not enabled", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, usecase.ErrSyncNotFound) {
+		writeError(w, "Sync status not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, usecase.ErrSyncNotConflicted) {
+		writeError(w, "Sync is not in conflict", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to resolve conflict", http.StatusBadGateway)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newAccountingSyncResponse(sync))
+}
+
+// HandleList 会計サービスの同期状態を状態（?status=、省略時は conflict）で絞り込んで取得
+func (h *AccountingHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = entity.SyncStatusConflict
+	}
+	if status != entity.SyncStatusSynced && status != entity.SyncStatusFailed && status != entity.SyncStatusConflict {
+		writeError(w, "invalid status: "+status, http.StatusBadRequest)
+		return
+	}
+
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	syncs, err := h.syncUseCase.ListByStatus(r.Context(), r.PathValue("provider"), status, limit, offset)
+	if errors.Is(err, usecase.ErrUnknownProvider) {
+		writeError(w, "Accounting provider is not enabled", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to get sync status", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newAccountingSyncResponses(syncs))
+}
+
+// newAccountingSyncResponse 同期状態をレスポンスに変換
+func newAccountingSyncResponse(sync *entity.AccountingSync) AccountingSyncResponse {
+	return AccountingSyncResponse{
+		ReceiptID:  sync.ReceiptID,
+		Provider:   sync.Provider,
+		Status:     sync.Status,
+		ExternalID: sync.ExternalID,
+		Attempts:   sync.Attempts,
+		LastError:  sync.LastError,
+		SyncedAt:   sync.SyncedAt,
+		UpdatedAt:  sync.UpdatedAt,
+	}
+}
+
+// newAccountingSyncResponses 同期状態の一覧をレスポンスに変換
+func newAccountingSyncResponses(syncs []*entity.AccountingSync) []AccountingSyncResponse {
+	responses := make([]AccountingSyncResponse, 0, len(syncs))
+	for _, sync := range syncs {
+		responses = append(responses, newAccountingSyncResponse(sync))
+	}
+	return responses
+}
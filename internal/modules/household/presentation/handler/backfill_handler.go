@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"vision-api-app/internal/modules/household/usecase"
+)
+
+// BackfillHandler 保存済みの元画像をAIのバッチ処理で読み直すAPIのハンドラー
+type BackfillHandler struct {
+	backfillUseCase *usecase.BackfillUseCase
+}
+
+// NewBackfillHandler 新しいBackfillHandlerを作成
+func NewBackfillHandler(backfillUseCase *usecase.BackfillUseCase) *BackfillHandler {
+	return &BackfillHandler{
+		backfillUseCase: backfillUseCase,
+	}
+}
+
+// startBackfillRequest 読み直しの開始リクエスト
+type startBackfillRequest struct {
+	ReceiptIDs []string `json:"receipt_ids"`
+}
+
+// BackfillResponse 読み直しの進み具合のレスポンス
+type BackfillResponse struct {
+	ID               string    `json:"id"`
+	State            string    `json:"state"` // running・completed・failed
+	Total            int       `json:"total"`
+	Succeeded        int       `json:"succeeded"`
+	Failed           int       `json:"failed"`
+	FailedReceiptIDs []string  `json:"failed_receipt_ids"`
+	Batches          int       `json:"batches"`
+	Error            string    `json:"error,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// newBackfillResponse 読み直しの進み具合からレスポンスを作成
+func newBackfillResponse(backfill *usecase.Backfill) BackfillResponse {
+	failed := backfill.FailedReceiptIDs
+	if failed == nil {
+		failed = []string{}
+	}
+	return BackfillResponse{
+		ID:               backfill.ID,
+		State:            backfill.State,
+		Total:            backfill.Total,
+		Succeeded:        backfill.Succeeded,
+		Failed:           backfill.Failed,
+		FailedReceiptIDs: failed,
+		Batches:          backfill.Batches,
+		Error:            backfill.Error,
+		CreatedAt:        backfill.CreatedAt,
+		UpdatedAt:        backfill.UpdatedAt,
+	}
+}
+
+// HandleStart 指定したレシートの読み直しを開始（結果はバッチの処理が終わった後に反映するため202を返す）
+func (h *BackfillHandler) HandleStart(w http.ResponseWriter, r *http.Request) {
+	var req startBackfillRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	backfill, err := h.backfillUseCase.StartBackfill(r.Context(), req.ReceiptIDs)
+	if errors.Is(err, usecase.ErrInvalidBackfill) {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, usecase.ErrImageNotStored) {
+		writeError(w, "Original images are not stored", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to start backfill", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, newBackfillResponse(backfill))
+}
+
+// HandleGet 読み直しの進み具合を取得
+func (h *BackfillHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	backfill, err := h.backfillUseCase.GetBackfill(r.Context(), r.PathValue("id"))
+	if errors.Is(err, usecase.ErrBackfillNotFound) {
+		writeError(w, "Backfill not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to get backfill", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newBackfillResponse(backfill))
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"vision-api-app/internal/modules/household/usecase"
+)
+
+// CalendarHandler 返品・保証期限のiCalendarのフィードのハンドラー
+type CalendarHandler struct {
+	calendarUseCase *usecase.CalendarUseCase
+}
+
+// NewCalendarHandler 新しいCalendarHandlerを作成
+func NewCalendarHandler(calendarUseCase *usecase.CalendarUseCase) *CalendarHandler {
+	return &CalendarHandler{
+		calendarUseCase: calendarUseCase,
+	}
+}
+
+// CalendarFeedResponse フィードの購読URLのレスポンス
+type CalendarFeedResponse struct {
+	FeedURL string `json:"feed_url"` // カレンダーのアプリに登録するURL（ホストはリクエスト元で補う）
+}
+
+// HandleIssueFeed フィードを購読するためのトークンを発行し、購読URLを返す
+func (h *CalendarHandler) HandleIssueFeed(w http.ResponseWriter, r *http.Request) {
+	token, err := h.calendarUseCase.IssueFeedToken()
+	if err != nil {
+		writeError(w, "Failed to issue calendar feed token", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, CalendarFeedResponse{
+		FeedURL: "/api/v1/warranties/calendar.ics?token=" + url.QueryEscape(token),
+	})
+}
+
+// HandleFeed ?token= のトークンを検証し、返品・保証期限のiCalendarを返す
+func (h *CalendarHandler) HandleFeed(w http.ResponseWriter, r *http.Request) {
+	data, err := h.calendarUseCase.Feed(r.Context(), r.URL.Query().Get("token"))
+	if errors.Is(err, usecase.ErrInvalidFeedToken) {
+		writeError(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to get calendar feed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+	"vision-api-app/internal/modules/household/usecase"
+)
+
+// CategoryHandler カテゴリーの管理・カテゴリー別のレシート・明細APIのハンドラー
+type CategoryHandler struct {
+	receiptUseCase  *usecase.ReceiptUseCase
+	categoryUseCase *usecase.CategoryUseCase
+}
+
+// NewCategoryHandler 新しいCategoryHandlerを作成
+func NewCategoryHandler(receiptUseCase *usecase.ReceiptUseCase, categoryUseCase *usecase.CategoryUseCase) *CategoryHandler {
+	return &CategoryHandler{
+		receiptUseCase:  receiptUseCase,
+		categoryUseCase: categoryUseCase,
+	}
+}
+
+// categoryRequest カテゴリーの登録・置き換えリクエスト
+type categoryRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Color       string `json:"color"` // #RRGGBB
+}
+
+// CategoryResponse カテゴリーのレスポンス
+type CategoryResponse struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Color       string    `json:"color"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// newCategoryResponse エンティティからレスポンスを作成
+func newCategoryResponse(category *entity.Category) CategoryResponse {
+	return CategoryResponse{
+		ID:          category.ID,
+		Name:        category.Name,
+		Description: category.Description,
+		Color:       category.Color,
+		CreatedAt:   category.CreatedAt,
+	}
+}
+
+// HandleList カテゴリー一覧を名前の順に取得
+func (h *CategoryHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.categoryUseCase.ListCategories(r.Context())
+	if err != nil {
+		writeError(w, "Failed to get categories", http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]CategoryResponse, 0, len(categories))
+	for _, category := range categories {
+		response = append(response, newCategoryResponse(category))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// HandleCreate カテゴリーを登録
+func (h *CategoryHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	var req categoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	category, err := h.categoryUseCase.CreateCategory(r.Context(), usecase.CategoryInput{
+		Name:        req.Name,
+		Description: req.Description,
+		Color:       req.Color,
+	})
+	if err != nil {
+		writeCategoryError(w, err, "Failed to create category")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newCategoryResponse(category))
+}
+
+// HandlePut カテゴリーの名前・説明・表示色を置き換える（省略した説明・表示色は空になる）
+func (h *CategoryHandler) HandlePut(w http.ResponseWriter, r *http.Request) {
+	var req categoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	category, err := h.categoryUseCase.ReplaceCategory(r.Context(), r.PathValue("id"), usecase.CategoryInput{
+		Name:        req.Name,
+		Description: req.Description,
+		Color:       req.Color,
+	})
+	if err != nil {
+		writeCategoryError(w, err, "Failed to update category")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newCategoryResponse(category))
+}
+
+// HandleDelete カテゴリーを削除（登録済みの明細・家計簿エントリのカテゴリーは変えない）
+func (h *CategoryHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	if err := h.categoryUseCase.DeleteCategory(r.Context(), r.PathValue("id")); err != nil {
+		writeCategoryError(w, err, "Failed to delete category")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeCategoryError カテゴリーの管理のエラーをステータスコードに対応付けて送信
+func writeCategoryError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, entity.ErrCategoryNotFound):
+		writeError(w, "Category not found", http.StatusNotFound)
+	case errors.Is(err, usecase.ErrInvalidCategory):
+		writeError(w, err.Error(), http.StatusUnprocessableEntity)
+	case errors.Is(err, entity.ErrCategoryExists):
+		writeError(w, err.Error(), http.StatusConflict)
+	default:
+		writeError(w, message, http.StatusInternalServerError)
+	}
+}
+
+// HandleListReceipts 指定したカテゴリーの明細を含むレシート一覧を取得
+func (h *CategoryHandler) HandleListReceipts(w http.ResponseWriter, r *http.Request) {
+	category := strings.TrimSpace(r.PathValue("name"))
+	if category == "" {
+		writeError(w, "Category is required", http.StatusBadRequest)
+		return
+	}
+
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter := repository.ReceiptFilter{Category: category}
+	receipts, err := h.receiptUseCase.SearchReceipts(r.Context(), filter, limit, offset)
+	if err != nil {
+		writeError(w, "Failed to get receipts", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newReceiptListResponse(receipts))
+}
+
+// HandleListItems 指定したカテゴリーの明細をレシートをまたいで取得
+func (h *CategoryHandler) HandleListItems(w http.ResponseWriter, r *http.Request) {
+	category := strings.TrimSpace(r.PathValue("name"))
+	if category == "" {
+		writeError(w, "Category is required", http.StatusBadRequest)
+		return
+	}
+
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	items, err := h.receiptUseCase.ListCategoryItems(r.Context(), category, limit, offset)
+	if err != nil {
+		writeError(w, "Failed to get items", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newCategoryItemListResponse(items))
+}
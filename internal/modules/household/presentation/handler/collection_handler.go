@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/usecase"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// CollectionHandler レシートのコレクション（フォルダー）APIのハンドラー
+type CollectionHandler struct {
+	collectionUseCase *usecase.CollectionUseCase
+	currency          sharedDomain.Currency
+}
+
+// NewCollectionHandler 新しいCollectionHandlerを作成
+func NewCollectionHandler(collectionUseCase *usecase.CollectionUseCase) *CollectionHandler {
+	return &CollectionHandler{
+		collectionUseCase: collectionUseCase,
+		currency:          sharedDomain.DefaultCurrency,
+	}
+}
+
+// SetCurrency 書き出す金額の通貨を設定する
+func (h *CollectionHandler) SetCurrency(currency sharedDomain.Currency) {
+	h.currency = currency
+}
+
+// collectionRequest コレクションの作成・変更リクエスト（変更ではnilの項目は変更しない）
+type collectionRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+// collectionReceiptsRequest コレクションへのレシートの追加リクエスト
+type collectionReceiptsRequest struct {
+	ReceiptIDs []string `json:"receipt_ids"`
+}
+
+// CollectionResponse コレクションのレスポンス
+type CollectionResponse struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ReceiptIDs  []string  `json:"receipt_ids"` // 追加した順
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CollectionReportResponse コレクションの集計のレスポンス
+type CollectionReportResponse struct {
+	Collection   CollectionResponse        `json:"collection"`
+	ReceiptCount int                       `json:"receipt_count"`
+	Total        int64                     `json:"total"`
+	Tax          int64                     `json:"tax"`
+	Start        *time.Time                `json:"start,omitempty"` // 最も古いレシートの購入日時
+	End          *time.Time                `json:"end,omitempty"`   // 最も新しいレシートの購入日時
+	Categories   []CategorySummaryResponse `json:"categories"`
+}
+
+// HandleCreate 空のコレクションを作成
+func (h *CollectionHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	var req collectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Name == nil {
+		writeError(w, "Invalid request: name is required", http.StatusBadRequest)
+		return
+	}
+	var description string
+	if req.Description != nil {
+		description = *req.Description
+	}
+
+	collection, err := h.collectionUseCase.CreateCollection(r.Context(), *req.Name, description)
+	if err != nil {
+		h.writeCollectionError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, newCollectionResponse(collection))
+}
+
+// HandleList コレクションを名前の順に取得
+func (h *CollectionHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	collections, err := h.collectionUseCase.ListCollections(r.Context(), limit, offset)
+	if err != nil {
+		writeError(w, "Failed to get collections", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, newCollectionListResponse(collections))
+}
+
+// HandleGet コレクションを取得
+func (h *CollectionHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	collection, err := h.collectionUseCase.GetCollection(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.writeCollectionError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newCollectionResponse(collection))
+}
+
+// HandlePatch コレクションの名前・説明を変更
+func (h *CollectionHandler) HandlePatch(w http.ResponseWriter, r *http.Request) {
+	var req collectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	collection, err := h.collectionUseCase.UpdateCollection(r.Context(), r.PathValue("id"), usecase.CollectionPatch{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		h.writeCollectionError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newCollectionResponse(collection))
+}
+
+// HandleDelete コレクションを削除（含めていたレシートは削除しない）
+func (h *CollectionHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	if err := h.collectionUseCase.DeleteCollection(r.Context(), r.PathValue("id")); err != nil {
+		h.writeCollectionError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleListReceipts コレクションに含めるレシートを追加した順に取得（ゴミ箱にあるレシートは含めない）
+func (h *CollectionHandler) HandleListReceipts(w http.ResponseWriter, r *http.Request) {
+	receipts, err := h.collectionUseCase.ListReceipts(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.writeCollectionError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newReceiptListResponse(receipts))
+}
+
+// HandleAddReceipts レシートをコレクションに追加（含まれているレシートは無視する）
+func (h *CollectionHandler) HandleAddReceipts(w http.ResponseWriter, r *http.Request) {
+	var req collectionReceiptsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	collection, err := h.collectionUseCase.AddReceipts(r.Context(), r.PathValue("id"), req.ReceiptIDs)
+	if err != nil {
+		h.writeCollectionError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newCollectionResponse(collection))
+}
+
+// HandleRemoveReceipt レシートをコレクションから外す（レシート自体は削除しない）
+func (h *CollectionHandler) HandleRemoveReceipt(w http.ResponseWriter, r *http.Request) {
+	collection, err := h.collectionUseCase.RemoveReceipt(r.Context(), r.PathValue("id"), r.PathValue("receipt_id"))
+	if err != nil {
+		h.writeCollectionError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newCollectionResponse(collection))
+}
+
+// HandleListByReceipt レシートを含むコレクションを名前の順に取得
+func (h *CollectionHandler) HandleListByReceipt(w http.ResponseWriter, r *http.Request) {
+	collections, err := h.collectionUseCase.ListReceiptCollections(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.writeCollectionError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newCollectionListResponse(collections))
+}
+
+// HandleReport コレクションに含めるレシートの枚数・合計金額・期間・カテゴリー別の合計を取得
+func (h *CollectionHandler) HandleReport(w http.ResponseWriter, r *http.Request) {
+	report, err := h.collectionUseCase.Report(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.writeCollectionError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, CollectionReportResponse{
+		Collection:   newCollectionResponse(report.Collection),
+		ReceiptCount: report.ReceiptCount,
+		Total:        report.Total,
+		Tax:          report.Tax,
+		Start:        report.Start,
+		End:          report.End,
+		Categories:   newCategorySummaryResponses(report.Categories),
+	})
+}
+
+// HandleExport コレクションに含めるレシートの明細をダウンロード（format=csv/xlsx、列は /api/v1/reports/export と同じ）
+func (h *CollectionHandler) HandleExport(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "xlsx" {
+		writeError(w, fmt.Sprintf("unsupported format: %s", format), http.StatusBadRequest)
+		return
+	}
+
+	// 存在しないコレクションは書き出し始める前に404を返す
+	id := r.PathValue("id")
+	if _, err := h.collectionUseCase.GetCollection(r.Context(), id); err != nil {
+		h.writeCollectionError(w, err)
+		return
+	}
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(exportWriteTimeout)); err != nil {
+		slog.Debug("Failed to extend write deadline for export", "error", err)
+	}
+
+	forEach := func(fn func(usecase.ExportRow) error) error {
+		return h.collectionUseCase.ForEachRow(r.Context(), id, fn)
+	}
+	filename := "collection-" + id
+	if format == "xlsx" {
+		writeExportXLSX(w, h.currency, filename+".xlsx", forEach)
+		return
+	}
+	writeExportCSV(w, h.currency, filename+".csv", forEach)
+}
+
+// writeCollectionError コレクションの操作のエラーをステータスコードに変換して送信
+func (h *CollectionHandler) writeCollectionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrCollectionNotFound):
+		writeError(w, "Collection not found", http.StatusNotFound)
+	case errors.Is(err, usecase.ErrReceiptNotFound), errors.Is(err, usecase.ErrNotInCollection):
+		writeError(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, entity.ErrInvalidCollection):
+		writeError(w, err.Error(), http.StatusBadRequest)
+	default:
+		slog.Error("Failed to process collection", "error", err)
+		writeError(w, "Failed to process collection", http.StatusInternalServerError)
+	}
+}
+
+// newCollectionResponse コレクションをレスポンスに変換
+func newCollectionResponse(collection *entity.Collection) CollectionResponse {
+	receiptIDs := collection.ReceiptIDs
+	if receiptIDs == nil {
+		receiptIDs = []string{}
+	}
+	return CollectionResponse{
+		ID:          collection.ID,
+		Name:        collection.Name,
+		Description: collection.Description,
+		ReceiptIDs:  receiptIDs,
+		CreatedAt:   collection.CreatedAt,
+		UpdatedAt:   collection.UpdatedAt,
+	}
+}
+
+// newCollectionListResponse コレクションの一覧をレスポンスに変換
+func newCollectionListResponse(collections []*entity.Collection) []CollectionResponse {
+	responses := make([]CollectionResponse, 0, len(collections))
+	for _, collection := range collections {
+		responses = append(responses, newCollectionResponse(collection))
+	}
+	return responses
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"vision-api-app/internal/modules/household/usecase"
+	"vision-api-app/internal/modules/shared/presentation/bufpool"
+)
+
+// maxDocumentSize 自動で振り分けるファイルの上限サイズ
+const maxDocumentSize = 10 << 20 // 10MB
+
+// DocumentHandler ファイルの種類を判定して対応する処理に振り分けるAPIのハンドラー
+type DocumentHandler struct {
+	documentUseCase *usecase.DocumentUseCase
+}
+
+// NewDocumentHandler 新しいDocumentHandlerを作成
+func NewDocumentHandler(documentUseCase *usecase.DocumentUseCase) *DocumentHandler {
+	return &DocumentHandler{
+		documentUseCase: documentUseCase,
+	}
+}
+
+// DocumentResponse 振り分けた先の処理結果のレスポンス（typeに対応するフィールドのみ含める）
+type DocumentResponse struct {
+	Type           string                  `json:"type"` // receipt・statement
+	Receipt        *ReceiptResponse        `json:"receipt,omitempty"`
+	Reconciliation *ReconciliationResponse `json:"reconciliation,omitempty"`
+}
+
+// HandleCreate ファイルの種類を判定し、レシートの画像は登録、利用明細のCSVはレシートと突き合わせる
+// ファイルは multipart/form-data の file フィールド（tags, memo, keep_location はレシートの登録に使う）、またはリクエストボディで受け付ける。
+// ?payment_method= （複数指定可）で利用明細に請求が載るはずのレシートの支払い方法を指定できる
+func (h *DocumentHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	var body io.Reader = http.MaxBytesReader(w, r.Body, maxDocumentSize)
+	sizeHint := r.ContentLength
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(maxDocumentSize); err != nil {
+			writeError(w, "Failed to parse form", http.StatusBadRequest)
+			return
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			writeError(w, "File is required", http.StatusBadRequest)
+			return
+		}
+		defer func() { _ = file.Close() }()
+		body, sizeHint = file, header.Size
+	}
+
+	// ファイルは振り分けた先の処理中のみ参照するため、バッファはレスポンスを返した後にプールへ戻す
+	buf, err := bufpool.ReadAll(body, sizeHint)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, "File is too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		writeError(w, "Failed to read file", http.StatusInternalServerError)
+		return
+	}
+	defer buf.Release()
+	if len(buf.Bytes()) == 0 {
+		writeError(w, "File is required", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.documentUseCase.Process(r.Context(), buf.Bytes(), usecase.DocumentOptions{
+		Receipt: usecase.ProcessOptions{
+			Tags: parseTagList(r.FormValue("tags")),
+			Memo: r.FormValue("memo"),
+			// 位置情報の保存に同意した場合のみ画像のメタデータを残す
+			KeepLocation: r.FormValue("keep_location") == "true",
+		},
+		PaymentMethods: r.URL.Query()["payment_method"],
+	})
+	switch {
+	case errors.Is(err, usecase.ErrUnsupportedDocument):
+		writeError(w, "Unsupported file: upload a receipt image (JPEG, PNG, GIF, WebP) or a statement CSV", http.StatusUnsupportedMediaType)
+		return
+	case errors.Is(err, usecase.ErrSavePending):
+		// データベース障害中は一時保管し、復旧後に保存される
+		receipt := newReceiptResponse(result.Receipt)
+		writeJSON(w, http.StatusAccepted, DocumentResponse{Type: result.Kind, Receipt: &receipt})
+		return
+	case err != nil && result.Kind == usecase.DocumentKindStatement:
+		writeError(w, "Failed to reconcile statement", http.StatusInternalServerError)
+		return
+	case err != nil:
+		writeProcessError(w, err)
+		return
+	}
+
+	if result.Kind == usecase.DocumentKindStatement {
+		reconciliation := newReconciliationResponse(result.Reconciliation)
+		writeJSON(w, http.StatusOK, DocumentResponse{Type: result.Kind, Reconciliation: &reconciliation})
+		return
+	}
+	receipt := newReceiptResponse(result.Receipt)
+	writeJSON(w, http.StatusCreated, DocumentResponse{Type: result.Kind, Receipt: &receipt})
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"vision-api-app/internal/modules/household/usecase"
+	"vision-api-app/internal/modules/shared/presentation/bufpool"
+)
+
+// DraftHandler 読み取り結果を確認してから保存する2段階のレシート登録のハンドラー
+type DraftHandler struct {
+	draftUseCase *usecase.DraftUseCase
+}
+
+// NewDraftHandler 新しいDraftHandlerを作成
+func NewDraftHandler(draftUseCase *usecase.DraftUseCase) *DraftHandler {
+	return &DraftHandler{
+		draftUseCase: draftUseCase,
+	}
+}
+
+// DraftResponse レシートの下書きのレスポンス
+type DraftResponse struct {
+	DraftToken  string          `json:"draft_token"`
+	ExpiresAt   time.Time       `json:"expires_at"`
+	DuplicateOf string          `json:"duplicate_of,omitempty"` // 同じ画像から登録済みのレシートのID
+	Receipt     ReceiptResponse `json:"receipt"`
+}
+
+// confirmDraftRequest 下書きの確認リクエスト（レシートの項目は修正する場合のみ指定）
+type confirmDraftRequest struct {
+	DraftToken string `json:"draft_token"`
+	patchReceiptRequest
+}
+
+// HandleExtract レシート画像を読み取って下書きを返す（multipart: image, tags, memo, keep_location）
+// 下書きは確認されるまで家計簿に反映しない
+func (h *DraftHandler) HandleExtract(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(10 << 20); err != nil { // 10MB制限
+		writeError(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	file, header, err := r.FormFile("image")
+	if err != nil {
+		writeError(w, "Image file is required", http.StatusBadRequest)
+		return
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	// 画像は下書きの保存が終わるまでのみ参照するため、バッファはレスポンスを返した後にプールへ戻す
+	buf, err := bufpool.ReadAll(file, header.Size)
+	if err != nil {
+		writeError(w, "Failed to read image", http.StatusInternalServerError)
+		return
+	}
+	defer buf.Release()
+
+	draft, err := h.draftUseCase.Extract(r.Context(), buf.Bytes(), usecase.ProcessOptions{
+		Tags:         parseTagList(r.FormValue("tags")),
+		Memo:         r.FormValue("memo"),
+		KeepLocation: r.FormValue("keep_location") == "true",
+	})
+	if err != nil {
+		writeProcessError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, DraftResponse{
+		DraftToken:  draft.Token,
+		ExpiresAt:   draft.ExpiresAt,
+		DuplicateOf: draft.DuplicateOf,
+		Receipt:     newReceiptResponse(draft.Receipt),
+	})
+}
+
+// HandleConfirm 下書きに修正を反映してレシートを登録
+func (h *DraftHandler) HandleConfirm(w http.ResponseWriter, r *http.Request) {
+	var req confirmDraftRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.DraftToken == "" {
+		writeError(w, "draft_token is required", http.StatusBadRequest)
+		return
+	}
+
+	receipt, err := h.draftUseCase.Confirm(r.Context(), req.DraftToken, req.toPatch())
+	switch {
+	case errors.Is(err, usecase.ErrDraftNotFound):
+		writeError(w, "Draft not found or expired", http.StatusNotFound)
+		return
+	case errors.Is(err, usecase.ErrInvalidReceipt):
+		writeError(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	case errors.Is(err, usecase.ErrSavePending):
+		// データベース障害中は一時保管し、復旧後に保存される
+		writeJSON(w, http.StatusAccepted, newReceiptResponse(receipt))
+		return
+	case err != nil:
+		writeError(w, "Failed to save receipt", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newReceiptResponse(receipt))
+}
@@ -0,0 +1,343 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/repository"
+	"vision-api-app/internal/modules/household/usecase"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// ExpenseHandler 家計簿エントリREST APIのハンドラー
+type ExpenseHandler struct {
+	expenseUseCase        *usecase.ExpenseUseCase
+	categorizationUseCase *usecase.ExpenseCategorizationUseCase
+}
+
+// NewExpenseHandler 新しいExpenseHandlerを作成
+func NewExpenseHandler(expenseUseCase *usecase.ExpenseUseCase, categorizationUseCase *usecase.ExpenseCategorizationUseCase) *ExpenseHandler {
+	return &ExpenseHandler{
+		expenseUseCase:        expenseUseCase,
+		categorizationUseCase: categorizationUseCase,
+	}
+}
+
+// expenseRequest 家計簿エントリの登録・置き換えリクエスト
+type expenseRequest struct {
+	Date        string   `json:"date"` // RFC3339、またはYYYY-MM-DD（タイムゾーンのその日の0時）
+	Category    string   `json:"category"`
+	Amount      int64    `json:"amount"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	Memo        string   `json:"memo"`
+}
+
+// toInput リクエストを登録・置き換えの内容に変換
+func (req *expenseRequest) toInput(loc *time.Location) (usecase.ExpenseInput, error) {
+	input := usecase.ExpenseInput{
+		Category:    req.Category,
+		Amount:      req.Amount,
+		Description: req.Description,
+		Tags:        req.Tags,
+		Memo:        req.Memo,
+	}
+	if req.Date != "" {
+		date, err := parseCreatedAt(req.Date, loc)
+		if err != nil {
+			return input, fmt.Errorf("invalid date: %s", req.Date)
+		}
+		input.Date = date
+	}
+	return input, nil
+}
+
+// HandleList 家計簿エントリ一覧を日付の新しい順に取得
+// category で絞り込み、from / to（YYYY-MM-DD、両端の日を含む）で日付の範囲を指定できる
+func (h *ExpenseHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	loc := sharedDomain.LocationFromContext(r.Context())
+	filter := repository.ExpenseFilter{Category: query.Get("category")}
+	if v := query.Get("from"); v != "" {
+		from, err := time.ParseInLocation(time.DateOnly, v, loc)
+		if err != nil {
+			writeError(w, fmt.Sprintf("invalid from: %s", v), http.StatusBadRequest)
+			return
+		}
+		filter.Start = from
+	}
+	if v := query.Get("to"); v != "" {
+		to, err := time.ParseInLocation(time.DateOnly, v, loc)
+		if err != nil {
+			writeError(w, fmt.Sprintf("invalid to: %s", v), http.StatusBadRequest)
+			return
+		}
+		filter.End = to.AddDate(0, 0, 1)
+	}
+
+	entries, err := h.expenseUseCase.ListExpenses(r.Context(), filter, limit, offset)
+	if err != nil {
+		writeError(w, "Failed to get expenses", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newExpenseListResponse(entries))
+}
+
+// HandleGet 家計簿エントリを取得
+func (h *ExpenseHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	entry, err := h.expenseUseCase.GetExpense(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, "Expense not found", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newExpenseResponse(entry))
+}
+
+// HandleCreate レシートに紐づかない家計簿エントリを登録（現金の支出など）
+func (h *ExpenseHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	var req expenseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	input, err := req.toInput(sharedDomain.LocationFromContext(r.Context()))
+	if err != nil {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.expenseUseCase.CreateExpense(r.Context(), input)
+	if errors.Is(err, usecase.ErrInvalidExpense) {
+		writeError(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to create expense", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newExpenseResponse(entry))
+}
+
+// HandlePut 家計簿エントリの編集できる項目をすべて置き換える（省略した項目は空になる）
+func (h *ExpenseHandler) HandlePut(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	var req expenseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	input, err := req.toInput(sharedDomain.LocationFromContext(r.Context()))
+	if err != nil {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.expenseUseCase.GetExpense(r.Context(), id); err != nil {
+		writeError(w, "Expense not found", http.StatusNotFound)
+		return
+	}
+
+	entry, err := h.expenseUseCase.ReplaceExpense(r.Context(), id, input)
+	if errors.Is(err, usecase.ErrInvalidExpense) {
+		writeError(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to update expense", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newExpenseResponse(entry))
+}
+
+// patchExpenseRequest 家計簿エントリの部分修正リクエスト
+type patchExpenseRequest struct {
+	Memo *string `json:"memo"`
+}
+
+// HandlePatch 家計簿エントリを部分的に修正
+func (h *ExpenseHandler) HandlePatch(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	var req patchExpenseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.expenseUseCase.GetExpense(r.Context(), id); err != nil {
+		writeError(w, "Expense not found", http.StatusNotFound)
+		return
+	}
+
+	entry, err := h.expenseUseCase.PatchExpense(r.Context(), id, usecase.ExpensePatch{
+		Memo: req.Memo,
+	})
+	if errors.Is(err, usecase.ErrInvalidExpense) {
+		writeError(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to update expense", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newExpenseResponse(entry))
+}
+
+// categorizeExpensesRequest 摘要のカテゴリーの一括判定リクエスト
+type categorizeExpensesRequest struct {
+	Descriptions []string `json:"descriptions"`
+}
+
+// CategorySuggestionResponse 摘要ごとのカテゴリーの判定結果のレスポンス
+type CategorySuggestionResponse struct {
+	Description string `json:"description"`
+	Category    string `json:"category"`
+	Status      string `json:"status"`
+}
+
+// CategorizationTokensResponse 一括判定で使用したAIトークン数のレスポンス
+type CategorizationTokensResponse struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
+
+// CategorizeExpensesResponse 摘要のカテゴリーの一括判定のレスポンス
+type CategorizeExpensesResponse struct {
+	Suggestions []CategorySuggestionResponse `json:"suggestions"`
+	Tokens      CategorizationTokensResponse `json:"tokens"`
+}
+
+// HandleCategorize 取り込んだ利用明細などの摘要のカテゴリーをまとめて判定
+// 判定結果は保存せず、リクエストと同じ順番で返す
+func (h *ExpenseHandler) HandleCategorize(w http.ResponseWriter, r *http.Request) {
+	var req categorizeExpensesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.categorizationUseCase.Categorize(r.Context(), req.Descriptions)
+	if errors.Is(err, usecase.ErrInvalidCategorizationRequest) {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to categorize expenses", http.StatusInternalServerError)
+		return
+	}
+
+	response := CategorizeExpensesResponse{
+		Suggestions: make([]CategorySuggestionResponse, len(result.Suggestions)),
+		Tokens: CategorizationTokensResponse{
+			InputTokens:  result.InputTokens,
+			OutputTokens: result.OutputTokens,
+			TotalTokens:  result.InputTokens + result.OutputTokens,
+		},
+	}
+	for i, suggestion := range result.Suggestions {
+		response.Suggestions[i] = CategorySuggestionResponse{
+			Description: suggestion.Description,
+			Category:    suggestion.Category,
+			Status:      suggestion.Status,
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// ExpenseCategoryShareResponse カテゴリーごとの支出の集計のレスポンス
+type ExpenseCategoryShareResponse struct {
+	Category     string  `json:"category"`
+	Count        int     `json:"count"`
+	Total        int64   `json:"total"`
+	Share        float64 `json:"share"` // 月の支出に占める割合（%、小数第2位まで）
+	ReceiptCount int     `json:"receipt_count"`
+	ItemCount    int     `json:"item_count"`
+	ItemTotal    int64   `json:"item_total"`
+	ExpenseCount int     `json:"expense_count"`
+	ExpenseTotal int64   `json:"expense_total"`
+}
+
+// ExpenseSummaryResponse 月のカテゴリーごとの支出の集計のレスポンス
+type ExpenseSummaryResponse struct {
+	Month      string                         `json:"month"` // YYYY-MM
+	Start      time.Time                      `json:"start"`
+	End        time.Time                      `json:"end"`
+	Count      int                            `json:"count"`
+	Total      int64                          `json:"total"`
+	Categories []ExpenseCategoryShareResponse `json:"categories"`
+}
+
+// HandleSummary 月のレシートの明細・家計簿エントリをカテゴリーごとに集計
+// month（YYYY-MM、省略時は当月）で月を指定する。割合は明細と家計簿エントリの合計に対する割合
+// 月の開始日はレポートと同じ reports.month_start_day で、month_start_day（1〜28）でリクエストごとに指定できる
+func (h *ExpenseHandler) HandleSummary(w http.ResponseWriter, r *http.Request) {
+	monthStartDay := 0
+	if v := r.URL.Query().Get("month_start_day"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > usecase.MaxMonthStartDay {
+			writeError(w, fmt.Sprintf("invalid month_start_day: %s", v), http.StatusBadRequest)
+			return
+		}
+		monthStartDay = n
+	}
+
+	year, month := h.expenseUseCase.CurrentMonth(r.Context(), monthStartDay)
+	if v := r.URL.Query().Get("month"); v != "" {
+		parsed, err := time.Parse("2006-01", v)
+		if err != nil {
+			writeError(w, fmt.Sprintf("invalid month: %s", v), http.StatusBadRequest)
+			return
+		}
+		year, month = parsed.Year(), int(parsed.Month())
+	}
+
+	summary, err := h.expenseUseCase.GetMonthlySummary(r.Context(), year, month, monthStartDay)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidReportPeriod) {
+			writeError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeError(w, "Failed to summarize expenses", http.StatusInternalServerError)
+		return
+	}
+
+	response := ExpenseSummaryResponse{
+		Month:      summary.Start.Format("2006-01"),
+		Start:      summary.Start,
+		End:        summary.End,
+		Count:      summary.Count,
+		Total:      summary.Total,
+		Categories: make([]ExpenseCategoryShareResponse, len(summary.Categories)),
+	}
+	for i, share := range summary.Categories {
+		response.Categories[i] = ExpenseCategoryShareResponse{
+			Category:     share.Category,
+			Count:        share.Count,
+			Total:        share.Total,
+			Share:        share.Share,
+			ReceiptCount: share.ReceiptCount,
+			ItemCount:    share.ItemCount,
+			ItemTotal:    share.ItemTotal,
+			ExpenseCount: share.ExpenseCount,
+			ExpenseTotal: share.ExpenseTotal,
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"vision-api-app/internal/modules/household/usecase"
+)
+
+// schemaVersionHeader 書き出した文書のスキーマの版・読み込みに対応する版を返すヘッダー
+const schemaVersionHeader = "X-Schema-Version"
+
+// InterchangeHandler インスタンス間でレシートを移行する書き出し・読み込みAPIのハンドラー
+type InterchangeHandler struct {
+	interchangeUseCase *usecase.InterchangeUseCase
+}
+
+// NewInterchangeHandler 新しいInterchangeHandlerを作成
+func NewInterchangeHandler(interchangeUseCase *usecase.InterchangeUseCase) *InterchangeHandler {
+	return &InterchangeHandler{
+		interchangeUseCase: interchangeUseCase,
+	}
+}
+
+// HandleExport レシートと紐づく家計簿エントリを移行用JSONで書き出す
+// schema_versionで版を指定でき（省略時は現在の版）、対応していない版の場合は406と対応する版を返す。
+// 書き出した文書はそのまま読み込みAPIに送れるよう、レスポンスの共通の形式で包まない
+func (h *InterchangeHandler) HandleExport(w http.ResponseWriter, r *http.Request) {
+	version, err := usecase.NegotiateSchemaVersion(r.URL.Query().Get("schema_version"))
+	if err != nil {
+		h.writeUnsupportedVersion(w, err, http.StatusNotAcceptable)
+		return
+	}
+
+	id := r.PathValue("id")
+	doc, err := h.interchangeUseCase.Export(r.Context(), id, version)
+	switch {
+	case errors.Is(err, usecase.ErrReceiptNotFound):
+		writeError(w, "Receipt not found", http.StatusNotFound)
+		return
+	case err != nil:
+		writeError(w, "Failed to export receipt", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "receipt-"+id+".json"))
+	w.Header().Set(schemaVersionHeader, strconv.Itoa(doc.SchemaVersion))
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(doc)
+}
+
+// HandleImport 移行用JSONのレシートと家計簿エントリを登録
+// 対応していない版の文書は422と対応する版を返し、同じIDのレシート・家計簿エントリが登録済みの場合は409を返す
+func (h *InterchangeHandler) HandleImport(w http.ResponseWriter, r *http.Request) {
+	var doc usecase.InterchangeDocument
+	if err := json.NewDecoder(r.Body).Decode(&doc); err != nil {
+		writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	receipt, err := h.interchangeUseCase.Import(r.Context(), &doc)
+	switch {
+	case errors.Is(err, usecase.ErrUnsupportedSchemaVersion):
+		h.writeUnsupportedVersion(w, err, http.StatusUnprocessableEntity)
+		return
+	case errors.Is(err, usecase.ErrInvalidInterchange):
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, usecase.ErrInvalidReceipt), errors.Is(err, usecase.ErrInvalidExpense):
+		writeError(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	case errors.Is(err, usecase.ErrImportConflict):
+		writeError(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		writeError(w, "Failed to import receipt", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newReceiptResponse(receipt))
+}
+
+// writeUnsupportedVersion 対応していない版のエラーを、対応する版（最も新しい版）のヘッダーとともに送信
+func (h *InterchangeHandler) writeUnsupportedVersion(w http.ResponseWriter, err error, statusCode int) {
+	w.Header().Set(schemaVersionHeader, strconv.Itoa(usecase.InterchangeSchemaVersion))
+	writeError(w, err.Error(), statusCode)
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/usecase"
+)
+
+// ItemHandler 商品（明細）APIのハンドラー
+type ItemHandler struct {
+	priceHistoryUseCase *usecase.PriceHistoryUseCase
+	itemAliasUseCase    *usecase.ItemAliasUseCase
+}
+
+// NewItemHandler 新しいItemHandlerを作成
+func NewItemHandler(priceHistoryUseCase *usecase.PriceHistoryUseCase, itemAliasUseCase *usecase.ItemAliasUseCase) *ItemHandler {
+	return &ItemHandler{
+		priceHistoryUseCase: priceHistoryUseCase,
+		itemAliasUseCase:    itemAliasUseCase,
+	}
+}
+
+// PricePointResponse 商品の購入1回分の価格のレスポンス
+type PricePointResponse struct {
+	Date      time.Time `json:"date"`
+	StoreName string    `json:"store_name"`
+	ReceiptID string    `json:"receipt_id"`
+	Name      string    `json:"name"`
+	Price     int64     `json:"price"`
+	Quantity  int       `json:"quantity"`
+}
+
+// StorePriceResponse 店舗ごとの価格の集計のレスポンス
+type StorePriceResponse struct {
+	StoreName  string    `json:"store_name"`
+	Count      int       `json:"count"`
+	MinPrice   int64     `json:"min_price"`
+	MaxPrice   int64     `json:"max_price"`
+	AvgPrice   int64     `json:"avg_price"`
+	LastPrice  int64     `json:"last_price"`
+	LastBought time.Time `json:"last_bought"`
+}
+
+// PriceHistoryResponse 商品の価格の推移のレスポンス
+type PriceHistoryResponse struct {
+	Name           string               `json:"name"`
+	NormalizedName string               `json:"normalized_name"`
+	Points         []PricePointResponse `json:"points"` // 購入日の古い順
+	Stores         []StorePriceResponse `json:"stores"` // 平均単価の安い順
+}
+
+// ItemAliasResponse 商品名の別名のレスポンス
+type ItemAliasResponse struct {
+	Alias         string    `json:"alias"`
+	CanonicalName string    `json:"canonical_name"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// itemAliasRequest 商品名の別名の登録リクエスト
+type itemAliasRequest struct {
+	Alias         string `json:"alias"`
+	CanonicalName string `json:"canonical_name"`
+}
+
+// newItemAliasResponse 別名からレスポンスを作成
+func newItemAliasResponse(alias *entity.ItemAlias) ItemAliasResponse {
+	return ItemAliasResponse{
+		Alias:         alias.Alias,
+		CanonicalName: alias.CanonicalName,
+		UpdatedAt:     alias.UpdatedAt,
+	}
+}
+
+// newPriceHistoryResponse 価格の推移からレスポンスを作成
+func newPriceHistoryResponse(history *usecase.PriceHistory) PriceHistoryResponse {
+	response := PriceHistoryResponse{
+		Name:           history.Name,
+		NormalizedName: history.NormalizedName,
+		Points:         make([]PricePointResponse, 0, len(history.Points)),
+		Stores:         make([]StorePriceResponse, 0, len(history.Stores)),
+	}
+	for _, point := range history.Points {
+		response.Points = append(response.Points, PricePointResponse(point))
+	}
+	for _, store := range history.Stores {
+		response.Stores = append(response.Stores, StorePriceResponse(store))
+	}
+	return response
+}
+
+// HandlePriceHistory 商品名（?name=）の価格の推移と店舗ごとの価格を取得
+func (h *ItemHandler) HandlePriceHistory(w http.ResponseWriter, r *http.Request) {
+	history, err := h.priceHistoryUseCase.GetPriceHistory(r.Context(), r.URL.Query().Get("name"))
+	if errors.Is(err, usecase.ErrItemNameRequired) {
+		writeError(w, "name is required", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to get price history", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newPriceHistoryResponse(history))
+}
+
+// HandleListAliases 商品名の別名辞書の一覧を取得
+func (h *ItemHandler) HandleListAliases(w http.ResponseWriter, r *http.Request) {
+	aliases, err := h.itemAliasUseCase.ListAliases(r.Context())
+	if err != nil {
+		writeError(w, "Failed to list item aliases", http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]ItemAliasResponse, 0, len(aliases))
+	for _, alias := range aliases {
+		response = append(response, newItemAliasResponse(alias))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// HandlePutAlias 商品名の別名を登録（同じ別名が登録済みの場合は上書き）
+func (h *ItemHandler) HandlePutAlias(w http.ResponseWriter, r *http.Request) {
+	var req itemAliasRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	alias, err := h.itemAliasUseCase.SaveAlias(r.Context(), req.Alias, req.CanonicalName)
+	if errors.Is(err, usecase.ErrInvalidItemAlias) {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to save item alias", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newItemAliasResponse(alias))
+}
+
+// HandleDeleteAlias 商品名の別名を削除
+func (h *ItemHandler) HandleDeleteAlias(w http.ResponseWriter, r *http.Request) {
+	err := h.itemAliasUseCase.DeleteAlias(r.Context(), r.PathValue("alias"))
+	if errors.Is(err, entity.ErrItemAliasNotFound) {
+		writeError(w, "Item alias not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to delete item alias", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
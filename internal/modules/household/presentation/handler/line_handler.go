@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"vision-api-app/internal/modules/household/usecase"
+)
+
+// maxLineWebhookSize Webhookのリクエストボディの最大サイズ
+const maxLineWebhookSize = 1 << 20
+
+// LineHandler LINE Messaging APIのWebhookのハンドラー
+type LineHandler struct {
+	lineUseCase   *usecase.LineUseCase
+	channelSecret []byte
+}
+
+// NewLineHandler 新しいLineHandlerを作成（channelSecretは署名の検証に使うチャネルシークレット）
+func NewLineHandler(lineUseCase *usecase.LineUseCase, channelSecret string) *LineHandler {
+	return &LineHandler{
+		lineUseCase:   lineUseCase,
+		channelSecret: []byte(channelSecret),
+	}
+}
+
+// lineWebhookRequest Webhookのリクエスト
+type lineWebhookRequest struct {
+	Events []lineEvent `json:"events"`
+}
+
+// lineEvent Webhookのイベント（メッセージイベント以外は無視する）
+type lineEvent struct {
+	Type       string `json:"type"`
+	ReplyToken string `json:"replyToken"`
+	Message    struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	} `json:"message"`
+}
+
+// HandleWebhook LINEのトークで受信したメッセージを処理（画像はレシートとして登録して結果を返信）
+// X-Line-Signatureの署名が一致しない場合は401を返す
+func (h *LineHandler) HandleWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxLineWebhookSize+1))
+	if err != nil || len(body) > maxLineWebhookSize {
+		writeError(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+	if !h.validSignature(body, r.Header.Get("X-Line-Signature")) {
+		writeError(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var req lineWebhookRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	for _, event := range req.Events {
+		// 再送されたイベントの同じ画像は、既存のレシートとして返信される
+		if event.Type != "message" || event.ReplyToken == "" {
+			continue
+		}
+		switch event.Message.Type {
+		case "image":
+			err = h.lineUseCase.HandleImageMessage(ctx, event.Message.ID, event.ReplyToken)
+		default:
+			err = h.lineUseCase.HandleTextMessage(ctx, event.ReplyToken)
+		}
+		if err != nil {
+			// 失敗は利用者へ返信済みのため、LINEプラットフォームには成功を返す
+			slog.Warn("Failed to handle LINE event", "message_id", event.Message.ID, "error", err)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, nil)
+}
+
+// validSignature チャネルシークレットによるリクエストボディの署名（HMAC-SHA256、Base64）を検証
+func (h *LineHandler) validSignature(body []byte, signature string) bool {
+	got, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || len(got) == 0 {
+		return false
+	}
+	mac := hmac.New(sha256.New, h.channelSecret)
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/usecase"
+)
+
+// MergeHandler 二重に登録したレシートの統合APIのハンドラー
+type MergeHandler struct {
+	mergeUseCase *usecase.MergeUseCase
+}
+
+// NewMergeHandler 新しいMergeHandlerを作成
+func NewMergeHandler(mergeUseCase *usecase.MergeUseCase) *MergeHandler {
+	return &MergeHandler{
+		mergeUseCase: mergeUseCase,
+	}
+}
+
+// mergeRequest レシートの統合リクエスト
+type mergeRequest struct {
+	ReceiptID       string `json:"receipt_id"`        // 残すレシート
+	MergedReceiptID string `json:"merged_receipt_id"` // 統合して削除するレシート
+}
+
+// unmergeRequest 統合の取り消しリクエスト
+type unmergeRequest struct {
+	MergedReceiptID string `json:"merged_receipt_id"`
+}
+
+// ReceiptMergeResponse 統合の記録のレスポンス
+type ReceiptMergeResponse struct {
+	MergedReceiptID string          `json:"merged_receipt_id"`
+	MergedReceipt   ReceiptResponse `json:"merged_receipt"` // 統合した時点の削除したレシート
+	AddedItemIDs    []string        `json:"added_item_ids"` // 残したレシートに追加した明細のID
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+// MergeResponse レシートの統合のレスポンス
+type MergeResponse struct {
+	Receipt ReceiptResponse      `json:"receipt"`
+	Merge   ReceiptMergeResponse `json:"merge"`
+}
+
+// UnmergeResponse 統合の取り消しのレスポンス
+type UnmergeResponse struct {
+	Receipt  ReceiptResponse `json:"receipt"`  // 統合前の状態に戻したレシート
+	Restored ReceiptResponse `json:"restored"` // 登録し直したレシート
+}
+
+// HandleMerge 二重に登録したレシートを一方に統合する
+func (h *MergeHandler) HandleMerge(w http.ResponseWriter, r *http.Request) {
+	var req mergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ReceiptID == "" || req.MergedReceiptID == "" {
+		writeError(w, "Invalid request: receipt_id and merged_receipt_id are required", http.StatusBadRequest)
+		return
+	}
+
+	receipt, merge, err := h.mergeUseCase.MergeReceipts(r.Context(), req.ReceiptID, req.MergedReceiptID)
+	if errors.Is(err, usecase.ErrReceiptNotFound) {
+		writeError(w, "Receipt not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, usecase.ErrInvalidMerge) {
+		writeError(w, "Invalid merge: receipts must differ and the merged receipt must not have merged receipts", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to merge receipts", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, MergeResponse{
+		Receipt: newReceiptResponse(receipt),
+		Merge:   newReceiptMergeResponse(merge),
+	})
+}
+
+// HandleUnmerge 統合を取り消し、削除したレシートを登録し直す
+func (h *MergeHandler) HandleUnmerge(w http.ResponseWriter, r *http.Request) {
+	var req unmergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.MergedReceiptID == "" {
+		writeError(w, "Invalid request: merged_receipt_id is required", http.StatusBadRequest)
+		return
+	}
+
+	receipt, restored, err := h.mergeUseCase.UnmergeReceipt(r.Context(), r.PathValue("id"), req.MergedReceiptID)
+	if errors.Is(err, usecase.ErrMergeNotFound) {
+		writeError(w, "Merge not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, usecase.ErrReceiptNotFound) {
+		writeError(w, "Receipt not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to unmerge receipts", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, UnmergeResponse{
+		Receipt:  newReceiptResponse(receipt),
+		Restored: newReceiptResponse(restored),
+	})
+}
+
+// HandleList レシートに統合したレシートの記録を取得
+func (h *MergeHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	merges, err := h.mergeUseCase.ListMerges(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, "Failed to get receipt merges", http.StatusInternalServerError)
+		return
+	}
+
+	responses := make([]ReceiptMergeResponse, 0, len(merges))
+	for _, merge := range merges {
+		responses = append(responses, newReceiptMergeResponse(merge))
+	}
+	writeJSON(w, http.StatusOK, responses)
+}
+
+// newReceiptMergeResponse 統合の記録からレスポンスを作成
+func newReceiptMergeResponse(merge *entity.ReceiptMerge) ReceiptMergeResponse {
+	addedItemIDs := merge.AddedItemIDs
+	if addedItemIDs == nil {
+		addedItemIDs = []string{}
+	}
+	return ReceiptMergeResponse{
+		MergedReceiptID: merge.MergedReceiptID,
+		MergedReceipt:   newReceiptResponse(&merge.MergedReceipt),
+		AddedItemIDs:    addedItemIDs,
+		CreatedAt:       merge.CreatedAt,
+	}
+}
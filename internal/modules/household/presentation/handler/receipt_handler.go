@@ -0,0 +1,372 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/repository"
+	"vision-api-app/internal/modules/household/usecase"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+	"vision-api-app/internal/modules/shared/presentation/bufpool"
+)
+
+// ReceiptHandler レシートREST APIのハンドラー
+type ReceiptHandler struct {
+	receiptUseCase *usecase.ReceiptUseCase
+}
+
+// NewReceiptHandler 新しいReceiptHandlerを作成
+func NewReceiptHandler(receiptUseCase *usecase.ReceiptUseCase) *ReceiptHandler {
+	return &ReceiptHandler{
+		receiptUseCase: receiptUseCase,
+	}
+}
+
+// patchReceiptRequest レシートの部分修正リクエスト
+type patchReceiptRequest struct {
+	StoreName    *string             `json:"store_name"`
+	PurchaseDate *time.Time          `json:"purchase_date"`
+	TotalAmount  *int64              `json:"total_amount"`
+	ReceiptType  *string             `json:"receipt_type"` // purchase・refund
+	Tags         *[]string           `json:"tags"`
+	Memo         *string             `json:"memo"`
+	Items        *[]patchItemRequest `json:"items"`
+}
+
+// patchItemRequest 明細の修正リクエスト（idを省略した明細は追加扱い）
+type patchItemRequest struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	Quantity       int      `json:"quantity"`
+	Price          int64    `json:"price"`
+	Unit           string   `json:"unit"`       // 数量の単位（個・g・kg）、省略時は既存の明細の単位
+	Measure        *float64 `json:"measure"`    // 量り売りの計量値
+	UnitPrice      *int64   `json:"unit_price"` // 量り売りの1kgあたりの単価
+	Category       string   `json:"category"`
+	WarrantyMonths *int     `json:"warranty_months"` // 保証期間（月数）、0は保証なし
+}
+
+// toPatch リクエストからユースケースの修正内容を作成
+func (req *patchReceiptRequest) toPatch() usecase.ReceiptPatch {
+	patch := usecase.ReceiptPatch{
+		StoreName:    req.StoreName,
+		PurchaseDate: req.PurchaseDate,
+		TotalAmount:  req.TotalAmount,
+		ReceiptType:  req.ReceiptType,
+		Tags:         req.Tags,
+		Memo:         req.Memo,
+	}
+	if req.Items != nil {
+		items := make([]usecase.ItemPatch, 0, len(*req.Items))
+		for _, item := range *req.Items {
+			items = append(items, usecase.ItemPatch{
+				ID:             item.ID,
+				Name:           item.Name,
+				Quantity:       item.Quantity,
+				Price:          item.Price,
+				Unit:           item.Unit,
+				Measure:        item.Measure,
+				UnitPrice:      item.UnitPrice,
+				Category:       item.Category,
+				WarrantyMonths: item.WarrantyMonths,
+			})
+		}
+		patch.Items = &items
+	}
+	return patch
+}
+
+// HandleCreate レシート画像をアップロードして登録（multipart: image, tags, memo, keep_location, created_at）
+// created_at（RFC3339またはYYYY-MM-DD）を指定した場合は、過去のレシートとしてその日時で登録する
+func (h *ReceiptHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(10 << 20); err != nil { // 10MB制限
+		writeError(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	var createdAt time.Time
+	if v := r.FormValue("created_at"); v != "" {
+		t, err := parseCreatedAt(v, sharedDomain.LocationFromContext(r.Context()))
+		if err != nil {
+			writeError(w, fmt.Sprintf("invalid created_at: %s", v), http.StatusBadRequest)
+			return
+		}
+		createdAt = t
+	}
+
+	file, header, err := r.FormFile("image")
+	if err != nil {
+		writeError(w, "Image file is required", http.StatusBadRequest)
+		return
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	// 画像はレシートの処理中のみ参照するため、バッファはレスポンスを返した後にプールへ戻す
+	buf, err := bufpool.ReadAll(file, header.Size)
+	if err != nil {
+		writeError(w, "Failed to read image", http.StatusInternalServerError)
+		return
+	}
+	defer buf.Release()
+	imageData := buf.Bytes()
+
+	receipt, err := h.receiptUseCase.ProcessReceiptImageWithOptions(r.Context(), imageData, usecase.ProcessOptions{
+		Tags: parseTagList(r.FormValue("tags")),
+		Memo: r.FormValue("memo"),
+		// 位置情報の保存に同意した場合のみ画像のメタデータを残す
+		KeepLocation: r.FormValue("keep_location") == "true",
+		CreatedAt:    createdAt,
+	})
+	if errors.Is(err, usecase.ErrSavePending) {
+		// データベース障害中は一時保管し、復旧後に保存される
+		writeJSON(w, http.StatusAccepted, newReceiptResponse(receipt))
+		return
+	}
+	if err != nil {
+		writeProcessError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newReceiptResponse(receipt))
+}
+
+// parseCreatedAt 登録日時を解析（日付のみの場合はlocのタイムゾーンのその日の0時）
+func parseCreatedAt(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation(time.DateOnly, value, loc)
+}
+
+// HandleGet レシートを取得
+func (h *ReceiptHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	receipt, err := h.receiptUseCase.GetReceipt(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, "Receipt not found", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newReceiptResponse(receipt))
+}
+
+// HandleList レシート一覧を取得（tag・qクエリで絞り込み）
+func (h *ReceiptHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter := repository.ReceiptFilter{
+		Tag:     r.URL.Query().Get("tag"),
+		Keyword: strings.TrimSpace(r.URL.Query().Get("q")),
+	}
+
+	receipts, err := h.receiptUseCase.SearchReceipts(r.Context(), filter, limit, offset)
+	if err != nil {
+		writeError(w, "Failed to get receipts", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newReceiptListResponse(receipts))
+}
+
+// HandlePatch レシートを部分的に修正
+func (h *ReceiptHandler) HandlePatch(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	var req patchReceiptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.receiptUseCase.GetReceipt(r.Context(), id); err != nil {
+		writeError(w, "Receipt not found", http.StatusNotFound)
+		return
+	}
+
+	receipt, err := h.receiptUseCase.PatchReceipt(r.Context(), id, req.toPatch())
+	if errors.Is(err, usecase.ErrInvalidReceipt) {
+		writeError(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to update receipt", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newReceiptResponse(receipt))
+}
+
+// HandlePut レシートの編集できる項目をすべて置き換える
+// 本文はPATCHと同じ形式で、store_name・purchase_date・total_amount・itemsは必須
+func (h *ReceiptHandler) HandlePut(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	var req patchReceiptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.receiptUseCase.GetReceipt(r.Context(), id); err != nil {
+		writeError(w, "Receipt not found", http.StatusNotFound)
+		return
+	}
+
+	receipt, err := h.receiptUseCase.ReplaceReceipt(r.Context(), id, req.toPatch())
+	if errors.Is(err, usecase.ErrInvalidReceipt) {
+		writeError(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to update receipt", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newReceiptResponse(receipt))
+}
+
+// HandleListNeedsReview 要確認のレシート一覧を取得
+func (h *ReceiptHandler) HandleListNeedsReview(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	receipts, err := h.receiptUseCase.ListNeedsReview(r.Context(), limit, offset)
+	if err != nil {
+		writeError(w, "Failed to get receipts", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newReceiptListResponse(receipts))
+}
+
+// revertRequest 巻き戻しリクエスト
+type revertRequest struct {
+	Revision int `json:"revision"`
+}
+
+// HandleGetHistory レシートの変更履歴を取得
+func (h *ReceiptHandler) HandleGetHistory(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	if _, err := h.receiptUseCase.GetReceipt(r.Context(), id); err != nil {
+		writeError(w, "Receipt not found", http.StatusNotFound)
+		return
+	}
+
+	revisions, err := h.receiptUseCase.GetReceiptHistory(r.Context(), id)
+	if err != nil {
+		writeError(w, "Failed to get receipt history", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newReceiptHistoryResponse(revisions))
+}
+
+// HandleRevert レシートを指定リビジョンの状態に戻す
+func (h *ReceiptHandler) HandleRevert(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	var req revertRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Revision <= 0 {
+		writeError(w, "Invalid request: revision is required", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.receiptUseCase.GetReceipt(r.Context(), id); err != nil {
+		writeError(w, "Receipt not found", http.StatusNotFound)
+		return
+	}
+
+	receipt, err := h.receiptUseCase.RevertReceipt(r.Context(), id, req.Revision)
+	if errors.Is(err, usecase.ErrRevisionNotFound) {
+		writeError(w, "Revision not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to revert receipt", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newReceiptResponse(receipt))
+}
+
+// HandleReprocess 保存済みの元画像からレシートを再処理
+func (h *ReceiptHandler) HandleReprocess(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	if _, err := h.receiptUseCase.GetReceipt(r.Context(), id); err != nil {
+		writeError(w, "Receipt not found", http.StatusNotFound)
+		return
+	}
+
+	receipt, err := h.receiptUseCase.ReprocessReceipt(r.Context(), id)
+	if errors.Is(err, usecase.ErrImageNotStored) {
+		writeError(w, "Original image is not stored for this receipt", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, usecase.ErrInvalidReceipt) {
+		writeError(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to reprocess receipt", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newReceiptResponse(receipt))
+}
+
+// HandleGetExtraction 保存したAIの出力の原文を解析し直し、現在のレシートと比較する（AIは呼び出さない）
+func (h *ReceiptHandler) HandleGetExtraction(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	if _, err := h.receiptUseCase.GetReceipt(r.Context(), id); err != nil {
+		writeError(w, "Receipt not found", http.StatusNotFound)
+		return
+	}
+
+	comparison, err := h.receiptUseCase.CompareExtraction(r.Context(), id)
+	if errors.Is(err, usecase.ErrExtractionNotStored) {
+		writeError(w, "Raw AI output is not stored for this receipt", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to compare extraction", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newExtractionComparisonResponse(comparison))
+}
+
+// HandleStorageUsage 元画像の保存容量の使用状況を取得
+func (h *ReceiptHandler) HandleStorageUsage(w http.ResponseWriter, r *http.Request) {
+	usage, err := h.receiptUseCase.StorageUsage(r.Context())
+	if err != nil {
+		writeError(w, "Failed to get storage usage", http.StatusInternalServerError)
+		return
+	}
+
+	resp := StorageUsageResponse{
+		UsedBytes:  usage.UsedBytes,
+		QuotaBytes: usage.QuotaBytes,
+		Images:     usage.Images,
+		Exceeded:   usage.QuotaBytes > 0 && usage.UsedBytes >= usage.QuotaBytes,
+	}
+	if usage.QuotaBytes > 0 {
+		remaining := max(usage.QuotaBytes-usage.UsedBytes, 0)
+		resp.RemainingBytes = &remaining
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
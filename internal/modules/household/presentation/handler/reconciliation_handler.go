@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/usecase"
+)
+
+// maxStatementSize 利用明細のCSVの上限サイズ
+const maxStatementSize = 10 << 20 // 10MB
+
+// ReconciliationHandler 利用明細とレシートの突き合わせAPIのハンドラー
+type ReconciliationHandler struct {
+	reconciliationUseCase *usecase.ReconciliationUseCase
+}
+
+// NewReconciliationHandler 新しいReconciliationHandlerを作成
+func NewReconciliationHandler(reconciliationUseCase *usecase.ReconciliationUseCase) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		reconciliationUseCase: reconciliationUseCase,
+	}
+}
+
+// StatementLineResponse 利用明細の行のレスポンス
+type StatementLineResponse struct {
+	Row         int       `json:"row"` // CSVの行番号
+	Date        time.Time `json:"date"`
+	Description string    `json:"description"`
+	Amount      int64     `json:"amount"`
+}
+
+// ReconciledReceiptResponse 突き合わせたレシートのレスポンス
+type ReconciledReceiptResponse struct {
+	ID            string    `json:"id"`
+	StoreName     string    `json:"store_name"`
+	PurchaseDate  time.Time `json:"purchase_date"`
+	TotalAmount   int64     `json:"total_amount"`
+	PaymentMethod string    `json:"payment_method"`
+}
+
+// ReconciliationMatchResponse 利用明細の行と対応するレシートのレスポンス
+type ReconciliationMatchResponse struct {
+	Line         StatementLineResponse     `json:"line"`
+	Receipt      ReconciledReceiptResponse `json:"receipt"`
+	DaysApart    int                       `json:"days_apart"`    // 利用日と購入日の差（日数）
+	StoreMatched bool                      `json:"store_matched"` // 摘要と店名が一致した
+}
+
+// ReconciliationResponse 突き合わせ結果のレスポンス
+type ReconciliationResponse struct {
+	Start             time.Time                     `json:"start"`
+	End               time.Time                     `json:"end"`
+	Matches           []ReconciliationMatchResponse `json:"matches"`
+	UnmatchedLines    []StatementLineResponse       `json:"unmatched_lines"`    // レシートのない支払い
+	UnmatchedReceipts []ReconciledReceiptResponse   `json:"unmatched_receipts"` // 明細に請求がないレシート
+	Skipped           int                           `json:"skipped"`            // 入金・返金など対象外の行数
+}
+
+// HandleReconcile 銀行・クレジットカードの利用明細のCSVを取り込み、レシートと突き合わせる
+// CSVは multipart/form-data の file フィールド、またはリクエストボディ（text/csv）で受け付ける。
+// ?payment_method= （複数指定可）で請求が載るはずのレシートの支払い方法を指定できる
+func (h *ReconciliationHandler) HandleReconcile(w http.ResponseWriter, r *http.Request) {
+	var body io.Reader = http.MaxBytesReader(w, r.Body, maxStatementSize)
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(maxStatementSize); err != nil {
+			writeError(w, "Failed to parse form", http.StatusBadRequest)
+			return
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			writeError(w, "Statement file is required", http.StatusBadRequest)
+			return
+		}
+		defer func() { _ = file.Close() }()
+		body = file
+	}
+
+	lines, err := usecase.ParseStatementCSV(body, h.reconciliationUseCase.Currency())
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytesErr):
+			writeError(w, "Statement is too large", http.StatusRequestEntityTooLarge)
+		default:
+			writeError(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	report, err := h.reconciliationUseCase.Reconcile(r.Context(), lines, r.URL.Query()["payment_method"])
+	if err != nil {
+		writeError(w, "Failed to reconcile statement", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newReconciliationResponse(report))
+}
+
+// newReconciliationResponse 突き合わせ結果をレスポンスに変換
+func newReconciliationResponse(report *usecase.ReconciliationReport) ReconciliationResponse {
+	response := ReconciliationResponse{
+		Start:             report.Start,
+		End:               report.End,
+		Matches:           make([]ReconciliationMatchResponse, 0, len(report.Matches)),
+		UnmatchedLines:    make([]StatementLineResponse, 0, len(report.UnmatchedLines)),
+		UnmatchedReceipts: make([]ReconciledReceiptResponse, 0, len(report.UnmatchedReceipts)),
+		Skipped:           report.Skipped,
+	}
+	for _, m := range report.Matches {
+		response.Matches = append(response.Matches, ReconciliationMatchResponse{
+			Line:         newStatementLineResponse(m.Line),
+			Receipt:      newReconciledReceiptResponse(m.Receipt),
+			DaysApart:    m.DaysApart,
+			StoreMatched: m.StoreMatched,
+		})
+	}
+	for _, line := range report.UnmatchedLines {
+		response.UnmatchedLines = append(response.UnmatchedLines, newStatementLineResponse(line))
+	}
+	for _, receipt := range report.UnmatchedReceipts {
+		response.UnmatchedReceipts = append(response.UnmatchedReceipts, newReconciledReceiptResponse(receipt))
+	}
+	return response
+}
+
+// newStatementLineResponse 利用明細の行をレスポンスに変換
+func newStatementLineResponse(line entity.StatementLine) StatementLineResponse {
+	return StatementLineResponse{
+		Row:         line.Row,
+		Date:        line.Date,
+		Description: line.Description,
+		Amount:      line.Amount,
+	}
+}
+
+// newReconciledReceiptResponse 突き合わせたレシートをレスポンスに変換
+func newReconciledReceiptResponse(receipt *entity.Receipt) ReconciledReceiptResponse {
+	return ReconciledReceiptResponse{
+		ID:            receipt.ID,
+		StoreName:     receipt.StoreName,
+		PurchaseDate:  receipt.PurchaseDate,
+		TotalAmount:   receipt.TotalAmount,
+		PaymentMethod: receipt.PaymentMethod,
+	}
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"net/http"
+	"strconv"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/usecase"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+	"vision-api-app/internal/modules/shared/presentation/chart"
+)
+
+// HandleCategoryChart カテゴリー別の支出の円グラフをPNGで取得
+// from / to（YYYY-MM-DD、両端の日を含む）を省略した場合は今月の1日から今日まで。
+// 凡例の色の順序は金額の大きい順で、Paletteの色数を超えるカテゴリーは最後の項目にまとめる
+func (h *ReportHandler) HandleCategoryChart(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	loc := sharedDomain.LocationFromContext(r.Context())
+	now := h.clock.Now().In(loc)
+
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+	if v := query.Get("from"); v != "" {
+		t, err := time.ParseInLocation(time.DateOnly, v, loc)
+		if err != nil {
+			writeError(w, fmt.Sprintf("invalid from: %s", v), http.StatusBadRequest)
+			return
+		}
+		from = t
+	}
+	to := now
+	if v := query.Get("to"); v != "" {
+		t, err := time.ParseInLocation(time.DateOnly, v, loc)
+		if err != nil {
+			writeError(w, fmt.Sprintf("invalid to: %s", v), http.StatusBadRequest)
+			return
+		}
+		to = t
+	}
+	width, height, err := parseChartSize(r)
+	if err != nil {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	summaries, err := h.householdUseCase.GetPeriodSummary(r.Context(), from, to, usecase.ReportGroupMonth, 0)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidReportPeriod) {
+			writeError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeError(w, "Failed to generate category chart", http.StatusInternalServerError)
+		return
+	}
+
+	var categories [][]usecase.CategorySummary
+	for _, summary := range summaries {
+		categories = append(categories, summary.Categories)
+	}
+	currency := h.exportUseCase.Currency()
+	writePNG(w, chart.Pie(categorySlices(currency, categories...), width, height))
+}
+
+// HandleMonthlyChart 月別の支出の推移の折れ線グラフをPNGで取得（yearを省略した場合は今年）
+// month_start_day で月の開始日（1〜28、省略時は reports.month_start_day）を指定できる
+func (h *ReportHandler) HandleMonthlyChart(w http.ResponseWriter, r *http.Request) {
+	year := h.clock.Now().In(sharedDomain.LocationFromContext(r.Context())).Year()
+	if v := r.URL.Query().Get("year"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1900 || n > 9999 {
+			writeError(w, fmt.Sprintf("invalid year: %s", v), http.StatusBadRequest)
+			return
+		}
+		year = n
+	}
+	monthStartDay := 0
+	if v := r.URL.Query().Get("month_start_day"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > usecase.MaxMonthStartDay {
+			writeError(w, fmt.Sprintf("invalid month_start_day: %s", v), http.StatusBadRequest)
+			return
+		}
+		monthStartDay = n
+	}
+	width, height, err := parseChartSize(r)
+	if err != nil {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	summaries, err := h.householdUseCase.GetMonthlySummary(r.Context(), year, monthStartDay)
+	if err != nil {
+		writeError(w, "Failed to generate monthly chart", http.StatusInternalServerError)
+		return
+	}
+
+	currency := h.exportUseCase.Currency()
+	points := make([]chart.Point, 0, len(summaries))
+	for _, summary := range summaries {
+		points = append(points, chart.Point{
+			Label: strconv.Itoa(summary.Month),
+			Value: chartAmount(currency, summary.Total),
+		})
+	}
+	writePNG(w, chart.Line(points, width, height))
+}
+
+// categorySlices カテゴリー別集計を合算して金額の大きい順の円グラフの項目に変換
+func categorySlices(currency sharedDomain.Currency, periods ...[]usecase.CategorySummary) []chart.Slice {
+	totals := make(map[string]int64)
+	var order []string
+	for _, categories := range periods {
+		for _, category := range categories {
+			if _, ok := totals[category.Category]; !ok {
+				order = append(order, category.Category)
+			}
+			totals[category.Category] += category.Total
+		}
+	}
+
+	slices := make([]chart.Slice, 0, len(order))
+	for _, category := range order {
+		slices = append(slices, chart.Slice{Label: category, Value: totals[category]})
+	}
+	slices = chart.TopSlices(slices, entity.DefaultCategory)
+	for i := range slices {
+		slices[i].Value = chartAmount(currency, slices[i].Value)
+	}
+	return slices
+}
+
+// chartAmount 最小単位の金額をグラフに描く通貨の単位の整数に丸める
+func chartAmount(currency sharedDomain.Currency, amount int64) int64 {
+	scale := currency.Scale()
+	if amount < 0 {
+		return -((-amount + scale/2) / scale)
+	}
+	return (amount + scale/2) / scale
+}
+
+// parseChartSize クエリパラメーターのwidth・height（ピクセル、省略時はデフォルトの大きさ）を解析
+func parseChartSize(r *http.Request) (int, int, error) {
+	size := [2]int{}
+	for i, name := range []string{"width", "height"} {
+		v := r.URL.Query().Get(name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < chart.MinSize || n > chart.MaxSize {
+			return 0, 0, fmt.Errorf("invalid %s: %s (%d-%d)", name, v, chart.MinSize, chart.MaxSize)
+		}
+		size[i] = n
+	}
+	width, height := chart.Size(size[0], size[1])
+	return width, height, nil
+}
+
+// writePNG グラフをPNGで書き出す（書き出しに失敗した場合にエラーを返せるよう、すべて作成してから送信する）
+func writePNG(w http.ResponseWriter, img image.Image) {
+	var buf bytes.Buffer
+	if err := chart.EncodePNG(&buf, img); err != nil {
+		writeError(w, "Failed to render chart", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.WriteHeader(http.StatusOK)
+	_, _ = buf.WriteTo(w)
+}
@@ -0,0 +1,763 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	"vision-api-app/internal/modules/household/usecase"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// medicalReportHeader 医療費控除の明細書の列
+var medicalReportHeader = []string{
+	"医療を受けた人",
+	"病院・薬局などの名称",
+	"医療費の区分",
+	"支払った医療費の額",
+	"左のうち補填される金額",
+	"支払年月日",
+}
+
+// exportHeader 明細のエクスポートの列
+var exportHeader = []string{
+	"購入日",
+	"店名",
+	"支払い方法",
+	"商品名",
+	"数量",
+	"単価",
+	"金額",
+	"カテゴリー",
+	"レシートID",
+}
+
+// exportWriteTimeout 明細のエクスポートの書き込み期限（サーバーのWriteTimeoutでは1年分の書き出しが終わらない場合があるため延長する）
+const exportWriteTimeout = 5 * time.Minute
+
+// ReportHandler レポートAPIのハンドラー
+type ReportHandler struct {
+	medicalReportUseCase *usecase.MedicalReportUseCase
+	householdUseCase     *usecase.HouseholdUseCase
+	ledgerUseCase        *usecase.LedgerUseCase
+	exportUseCase        *usecase.ExportUseCase
+	clock                sharedDomain.Clock
+}
+
+// NewReportHandler 新しいReportHandlerを作成
+func NewReportHandler(medicalReportUseCase *usecase.MedicalReportUseCase, householdUseCase *usecase.HouseholdUseCase, ledgerUseCase *usecase.LedgerUseCase, exportUseCase *usecase.ExportUseCase) *ReportHandler {
+	return &ReportHandler{
+		medicalReportUseCase: medicalReportUseCase,
+		householdUseCase:     householdUseCase,
+		ledgerUseCase:        ledgerUseCase,
+		exportUseCase:        exportUseCase,
+		clock:                sharedDomain.SystemClock{},
+	}
+}
+
+// SetClock 期間を省略した場合の今年・今月の判定に使う時計を設定する
+func (h *ReportHandler) SetClock(clock sharedDomain.Clock) {
+	h.clock = clock
+}
+
+// CategorySummaryResponse カテゴリ別集計のレスポンス
+type CategorySummaryResponse struct {
+	Category string `json:"category"`
+	Count    int    `json:"count"`
+	Total    int64  `json:"total"`
+}
+
+// MonthlySummaryResponse 月別集計のレスポンス
+type MonthlySummaryResponse struct {
+	Month      int                       `json:"month"`
+	Start      time.Time                 `json:"start"` // 集計期間の開始日時
+	End        time.Time                 `json:"end"`   // 集計期間の終了日時（この日時を含まない）
+	Total      int64                     `json:"total"`
+	Categories []CategorySummaryResponse `json:"categories"`
+}
+
+// MonthlyReportResponse 月別レポートのレスポンス
+type MonthlyReportResponse struct {
+	Year   int                      `json:"year"`
+	Months []MonthlySummaryResponse `json:"months"`
+}
+
+// HandleMonthly 月別・カテゴリ別の支出集計を取得（yearを省略した場合は今年）
+// month_start_day で月の開始日（1〜28、省略時は reports.month_start_day）を指定できる
+func (h *ReportHandler) HandleMonthly(w http.ResponseWriter, r *http.Request) {
+	year := h.clock.Now().In(sharedDomain.LocationFromContext(r.Context())).Year()
+	if v := r.URL.Query().Get("year"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1900 || n > 9999 {
+			writeError(w, fmt.Sprintf("invalid year: %s", v), http.StatusBadRequest)
+			return
+		}
+		year = n
+	}
+
+	monthStartDay := 0
+	if v := r.URL.Query().Get("month_start_day"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > usecase.MaxMonthStartDay {
+			writeError(w, fmt.Sprintf("invalid month_start_day: %s", v), http.StatusBadRequest)
+			return
+		}
+		monthStartDay = n
+	}
+
+	summaries, err := h.householdUseCase.GetMonthlySummary(r.Context(), year, monthStartDay)
+	if err != nil {
+		writeError(w, "Failed to generate monthly report", http.StatusInternalServerError)
+		return
+	}
+
+	response := MonthlyReportResponse{
+		Year:   year,
+		Months: make([]MonthlySummaryResponse, 0, len(summaries)),
+	}
+	for _, summary := range summaries {
+		response.Months = append(response.Months, MonthlySummaryResponse{
+			Month:      summary.Month,
+			Start:      summary.Start,
+			End:        summary.End,
+			Total:      summary.Total,
+			Categories: newCategorySummaryResponses(summary.Categories),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// PeriodSummaryResponse 期間別集計のレスポンス
+type PeriodSummaryResponse struct {
+	Start      time.Time                 `json:"start"` // 集計期間の開始日時
+	End        time.Time                 `json:"end"`   // 集計期間の終了日時（この日時を含まない）
+	Total      int64                     `json:"total"`
+	Categories []CategorySummaryResponse `json:"categories"`
+}
+
+// PeriodReportResponse 期間別レポートのレスポンス
+type PeriodReportResponse struct {
+	From    string                  `json:"from"`
+	To      string                  `json:"to"`
+	GroupBy string                  `json:"group_by"`
+	Total   int64                   `json:"total"`
+	Periods []PeriodSummaryResponse `json:"periods"`
+}
+
+// HandleSummary 任意の期間の支出を日・週・月ごとにカテゴリ別集計
+// from / to（YYYY-MM-DD、両端の日を含む）は必須、group_by は day / week / month（省略時は month）。
+// month_start_day で月の開始日（1〜28、省略時は reports.month_start_day）を指定できる
+func (h *ReportHandler) HandleSummary(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	loc := sharedDomain.LocationFromContext(r.Context())
+
+	from, err := time.ParseInLocation(time.DateOnly, query.Get("from"), loc)
+	if err != nil {
+		writeError(w, fmt.Sprintf("invalid from: %s", query.Get("from")), http.StatusBadRequest)
+		return
+	}
+	to, err := time.ParseInLocation(time.DateOnly, query.Get("to"), loc)
+	if err != nil {
+		writeError(w, fmt.Sprintf("invalid to: %s", query.Get("to")), http.StatusBadRequest)
+		return
+	}
+
+	groupBy := usecase.ReportGroupMonth
+	if v := query.Get("group_by"); v != "" {
+		groupBy = v
+	}
+
+	monthStartDay := 0
+	if v := query.Get("month_start_day"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > usecase.MaxMonthStartDay {
+			writeError(w, fmt.Sprintf("invalid month_start_day: %s", v), http.StatusBadRequest)
+			return
+		}
+		monthStartDay = n
+	}
+
+	summaries, err := h.householdUseCase.GetPeriodSummary(r.Context(), from, to, groupBy, monthStartDay)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidReportPeriod) {
+			writeError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeError(w, "Failed to generate summary report", http.StatusInternalServerError)
+		return
+	}
+
+	response := PeriodReportResponse{
+		From:    from.Format(time.DateOnly),
+		To:      to.Format(time.DateOnly),
+		GroupBy: groupBy,
+		Periods: make([]PeriodSummaryResponse, 0, len(summaries)),
+	}
+	for _, summary := range summaries {
+		response.Total += summary.Total
+		response.Periods = append(response.Periods, PeriodSummaryResponse{
+			Start:      summary.Start,
+			End:        summary.End,
+			Total:      summary.Total,
+			Categories: newCategorySummaryResponses(summary.Categories),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// calendarLevels カレンダーのヒートマップの色の段階数（支出のない日の0を除く）
+const calendarLevels = 4
+
+// CalendarDayResponse カレンダーの日ごとの集計のレスポンス
+type CalendarDayResponse struct {
+	Date    string `json:"date"`    // YYYY-MM-DD
+	Weekday int    `json:"weekday"` // 0（日曜）〜6（土曜）
+	Count   int    `json:"count"`
+	Total   int64  `json:"total"`
+	Level   int    `json:"level"` // ヒートマップの色の段階（0: 支出なし、1〜4: 最大の日に対する割合）
+}
+
+// CalendarResponse カレンダーのレスポンス
+type CalendarResponse struct {
+	Year     int                   `json:"year"`
+	Month    int                   `json:"month,omitempty"` // 省略時は年全体
+	Count    int                   `json:"count"`
+	Total    int64                 `json:"total"`
+	MaxTotal int64                 `json:"max_total"` // 1日の合計金額の最大
+	Days     []CalendarDayResponse `json:"days"`
+}
+
+// HandleCalendar 日ごとのレシートの枚数と合計金額を取得（支出カレンダー・ヒートマップ用）
+// yearを省略した場合は今年、monthを省略した場合は年全体。日の区切りはタイムゾーンの暦日
+func (h *ReportHandler) HandleCalendar(w http.ResponseWriter, r *http.Request) {
+	year := h.clock.Now().In(sharedDomain.LocationFromContext(r.Context())).Year()
+	if v := r.URL.Query().Get("year"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1900 || n > 9999 {
+			writeError(w, fmt.Sprintf("invalid year: %s", v), http.StatusBadRequest)
+			return
+		}
+		year = n
+	}
+	month := 0
+	if v := r.URL.Query().Get("month"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 12 {
+			writeError(w, fmt.Sprintf("invalid month: %s", v), http.StatusBadRequest)
+			return
+		}
+		month = n
+	}
+
+	days, err := h.householdUseCase.GetCalendar(r.Context(), year, month)
+	if err != nil {
+		writeError(w, "Failed to generate calendar", http.StatusInternalServerError)
+		return
+	}
+
+	response := CalendarResponse{
+		Year:  year,
+		Month: month,
+		Days:  make([]CalendarDayResponse, 0, len(days)),
+	}
+	for _, day := range days {
+		response.Count += day.Count
+		response.Total += day.Total
+		response.MaxTotal = max(response.MaxTotal, day.Total)
+	}
+	for _, day := range days {
+		level := 0
+		if day.Total > 0 {
+			level = int((day.Total*calendarLevels + response.MaxTotal - 1) / response.MaxTotal)
+		}
+		response.Days = append(response.Days, CalendarDayResponse{
+			Date:    day.Date.Format(time.DateOnly),
+			Weekday: int(day.Date.Weekday()),
+			Count:   day.Count,
+			Total:   day.Total,
+			Level:   level,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// newCategorySummaryResponses カテゴリ別集計をレスポンスに変換
+func newCategorySummaryResponses(categories []usecase.CategorySummary) []CategorySummaryResponse {
+	responses := make([]CategorySummaryResponse, 0, len(categories))
+	for _, category := range categories {
+		responses = append(responses, CategorySummaryResponse{
+			Category: category.Category,
+			Count:    category.Count,
+			Total:    category.Total,
+		})
+	}
+	return responses
+}
+
+// MedicalExpenseRowResponse 医療費控除の明細行のレスポンス
+type MedicalExpenseRowResponse struct {
+	ReceiptID string    `json:"receipt_id"`
+	Patient   string    `json:"patient"`
+	Provider  string    `json:"provider"`
+	Kind      string    `json:"kind"`
+	Amount    int64     `json:"amount"`
+	Date      time.Time `json:"date"`
+}
+
+// MedicalDeductionResponse 医療費控除レポートのレスポンス
+type MedicalDeductionResponse struct {
+	Year      int                         `json:"year"`
+	Rows      []MedicalExpenseRowResponse `json:"rows"`
+	Total     int64                       `json:"total"`
+	Deduction int64                       `json:"deduction"`
+}
+
+// HandleMedicalDeduction 医療費控除レポートを取得（format=json/csv/xlsx）
+// yearを省略した場合は確定申告の対象となる前年
+func (h *ReportHandler) HandleMedicalDeduction(w http.ResponseWriter, r *http.Request) {
+	year := h.clock.Now().In(sharedDomain.LocationFromContext(r.Context())).Year() - 1
+	if v := r.URL.Query().Get("year"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1900 || n > 9999 {
+			writeError(w, fmt.Sprintf("invalid year: %s", v), http.StatusBadRequest)
+			return
+		}
+		year = n
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" && format != "xlsx" {
+		writeError(w, fmt.Sprintf("unsupported format: %s", format), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.medicalReportUseCase.GenerateReport(r.Context(), year)
+	if err != nil {
+		writeError(w, "Failed to generate medical deduction report", http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("medical-deduction-%d.%s", year, format)
+	switch format {
+	case "csv":
+		data, err := medicalReportCSV(report)
+		if err != nil {
+			writeError(w, "Failed to export report", http.StatusInternalServerError)
+			return
+		}
+		writeAttachment(w, "text/csv; charset=utf-8", filename, data)
+	case "xlsx":
+		data, err := medicalReportXLSX(report)
+		if err != nil {
+			writeError(w, "Failed to export report", http.StatusInternalServerError)
+			return
+		}
+		writeAttachment(w, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", filename, data)
+	default:
+		writeJSON(w, http.StatusOK, newMedicalDeductionResponse(report))
+	}
+}
+
+// newMedicalDeductionResponse レポートからレスポンスを作成
+func newMedicalDeductionResponse(report *usecase.MedicalDeductionReport) MedicalDeductionResponse {
+	response := MedicalDeductionResponse{
+		Year:      report.Year,
+		Rows:      make([]MedicalExpenseRowResponse, 0, len(report.Rows)),
+		Total:     report.Total,
+		Deduction: report.Deduction,
+	}
+	for _, row := range report.Rows {
+		response.Rows = append(response.Rows, MedicalExpenseRowResponse{
+			ReceiptID: row.ReceiptID,
+			Patient:   row.Patient,
+			Provider:  row.Provider,
+			Kind:      row.Kind,
+			Amount:    row.Amount,
+			Date:      row.Date,
+		})
+	}
+	return response
+}
+
+// medicalReportRecords レポートを明細書の行に変換（末尾に合計行）
+func medicalReportRecords(report *usecase.MedicalDeductionReport) [][]string {
+	records := [][]string{medicalReportHeader}
+	for _, row := range report.Rows {
+		records = append(records, []string{
+			row.Patient,
+			row.Provider,
+			row.Kind,
+			strconv.FormatInt(row.Amount, 10),
+			"0",
+			row.Date.Format("2006/01/02"),
+		})
+	}
+	records = append(records, []string{"合計", "", "", strconv.FormatInt(report.Total, 10), "0", ""})
+	return records
+}
+
+// medicalReportCSV レポートをCSVに変換（Excelで開けるようBOM付きUTF-8）
+func medicalReportCSV(report *usecase.MedicalDeductionReport) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("\ufeff")
+
+	writer := csv.NewWriter(&buf)
+	if err := writer.WriteAll(medicalReportRecords(report)); err != nil {
+		return nil, fmt.Errorf("failed to write csv: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// medicalReportXLSX レポートをxlsxに変換
+func medicalReportXLSX(report *usecase.MedicalDeductionReport) ([]byte, error) {
+	f := excelize.NewFile()
+	defer func() {
+		_ = f.Close()
+	}()
+
+	sheet := "医療費控除"
+	if err := f.SetSheetName("Sheet1", sheet); err != nil {
+		return nil, fmt.Errorf("failed to create sheet: %w", err)
+	}
+
+	for i, record := range medicalReportRecords(report) {
+		values := make([]interface{}, len(record))
+		for j, v := range record {
+			// 金額列は数値として書き込む
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil && (j == 3 || j == 4) && i > 0 {
+				values[j] = n
+				continue
+			}
+			values[j] = v
+		}
+
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write row: %w", err)
+		}
+		if err := f.SetSheetRow(sheet, cell, &values); err != nil {
+			return nil, fmt.Errorf("failed to write row: %w", err)
+		}
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write xlsx: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// HandleLedger レシートを複式簿記の仕訳としてダウンロード（format=hledger/beancount）
+// yearを省略した場合は今年、monthを指定した場合はその月のみ
+func (h *ReportHandler) HandleLedger(w http.ResponseWriter, r *http.Request) {
+	year, month, err := h.parseYearMonth(r)
+	if err != nil {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = usecase.LedgerFormatHledger
+	}
+	if format != usecase.LedgerFormatHledger && format != usecase.LedgerFormatBeancount {
+		writeError(w, fmt.Sprintf("unsupported format: %s", format), http.StatusBadRequest)
+		return
+	}
+
+	journal, err := h.ledgerUseCase.GenerateJournal(r.Context(), year, month)
+	if err != nil {
+		writeError(w, "Failed to generate ledger", http.StatusInternalServerError)
+		return
+	}
+
+	period := strconv.Itoa(year)
+	if month > 0 {
+		period = fmt.Sprintf("%d-%02d", year, month)
+	}
+	if format == usecase.LedgerFormatBeancount {
+		writeAttachment(w, "text/plain; charset=utf-8", fmt.Sprintf("household-%s.beancount", period), beancountLedger(journal))
+		return
+	}
+	writeAttachment(w, "text/plain; charset=utf-8", fmt.Sprintf("household-%s.journal", period), hledgerJournal(journal))
+}
+
+// HandleExport レシートの明細をダウンロード（format=csv/xlsx）
+// yearを省略した場合は今年、monthを指定した場合はその月のみ
+// レシートを読み込みながら書き出すため、期間のレシート全体をメモリに保持しない
+func (h *ReportHandler) HandleExport(w http.ResponseWriter, r *http.Request) {
+	year, month, err := h.parseYearMonth(r)
+	if err != nil {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "xlsx" {
+		writeError(w, fmt.Sprintf("unsupported format: %s", format), http.StatusBadRequest)
+		return
+	}
+
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(exportWriteTimeout)); err != nil {
+		slog.Debug("Failed to extend write deadline for export", "error", err)
+	}
+
+	filename := fmt.Sprintf("receipts-%d", year)
+	if month > 0 {
+		filename = fmt.Sprintf("receipts-%d-%02d", year, month)
+	}
+	forEach := func(fn func(usecase.ExportRow) error) error {
+		return h.exportUseCase.ForEachRow(r.Context(), year, month, fn)
+	}
+	if format == "xlsx" {
+		writeExportXLSX(w, h.exportUseCase.Currency(), filename+".xlsx", forEach)
+		return
+	}
+	writeExportCSV(w, h.exportUseCase.Currency(), filename+".csv", forEach)
+}
+
+// writeExportCSV forEachが渡す明細を1行ずつCSV（Excelで開けるようBOM付きUTF-8）で書き出す
+// 書き出し始めた後に失敗した場合は、不完全なファイルを正常なものと誤認させないよう接続を切る
+func writeExportCSV(w http.ResponseWriter, currency sharedDomain.Currency, filename string, forEach func(func(usecase.ExportRow) error) error) {
+	writer := csv.NewWriter(w)
+	started := false
+	// start 最初の行を書き出す前にレスポンスヘッダーとヘッダー行を書き込む（取得前の失敗はエラーのJSONを返せるようにする）
+	start := func() error {
+		started = true
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		w.WriteHeader(http.StatusOK)
+		if _, err := io.WriteString(w, "\ufeff"); err != nil {
+			return err
+		}
+		return writer.Write(exportHeader)
+	}
+
+	err := forEach(func(row usecase.ExportRow) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return writer.Write(exportRecord(row, currency))
+	})
+	if err == nil && !started {
+		// 明細がない場合はヘッダー行のみのファイルを返す
+		err = start()
+	}
+	if err == nil {
+		writer.Flush()
+		err = writer.Error()
+	}
+	if err != nil {
+		if !started {
+			writeError(w, "Failed to export receipts", http.StatusInternalServerError)
+			return
+		}
+		slog.Error("Failed to export receipts", "format", "csv", "error", err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// writeExportXLSX forEachが渡す明細をxlsxで書き出す
+// 行はexcelizeのストリーム書き込みで一時ファイルに書き出し、すべての行を書き終えてからレスポンスを返す
+func writeExportXLSX(w http.ResponseWriter, currency sharedDomain.Currency, filename string, forEach func(func(usecase.ExportRow) error) error) {
+	f := excelize.NewFile()
+	defer func() {
+		_ = f.Close()
+	}()
+
+	sheet := "明細"
+	err := func() error {
+		if err := f.SetSheetName("Sheet1", sheet); err != nil {
+			return err
+		}
+		stream, err := f.NewStreamWriter(sheet)
+		if err != nil {
+			return err
+		}
+
+		rowNumber := 1
+		writeRow := func(values []interface{}) error {
+			cell, err := excelize.CoordinatesToCellName(1, rowNumber)
+			if err != nil {
+				return err
+			}
+			rowNumber++
+			return stream.SetRow(cell, values)
+		}
+
+		header := make([]interface{}, len(exportHeader))
+		for i, v := range exportHeader {
+			header[i] = v
+		}
+		if err := writeRow(header); err != nil {
+			return err
+		}
+		err = forEach(func(row usecase.ExportRow) error {
+			// 数量・単価・金額は数値として書き込む（明細のないレシートは数量・単価を空欄にする）
+			var quantity, price interface{}
+			if row.ItemName != "" {
+				quantity, price = row.Quantity, xlsxAmount(currency, row.Price)
+			}
+			return writeRow([]interface{}{
+				row.Date.Format("2006/01/02"),
+				row.StoreName,
+				row.PaymentMethod,
+				row.ItemName,
+				quantity,
+				price,
+				xlsxAmount(currency, row.Amount),
+				row.Category,
+				row.ReceiptID,
+			})
+		})
+		if err != nil {
+			return err
+		}
+		return stream.Flush()
+	}()
+	if err != nil {
+		slog.Error("Failed to export receipts", "format", "xlsx", "error", err)
+		writeError(w, "Failed to export receipts", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	if err := f.Write(w); err != nil {
+		slog.Error("Failed to write xlsx", "error", err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// exportRecord 明細をCSVの行に変換（単価・金額は通貨の単位の小数で書き出す）
+func exportRecord(row usecase.ExportRow, currency sharedDomain.Currency) []string {
+	quantity, price := "", ""
+	if row.ItemName != "" {
+		quantity = strconv.Itoa(row.Quantity)
+		price = currency.Format(row.Price)
+	}
+	return []string{
+		row.Date.Format("2006/01/02"),
+		row.StoreName,
+		row.PaymentMethod,
+		row.ItemName,
+		quantity,
+		price,
+		currency.Format(row.Amount),
+		row.Category,
+		row.ReceiptID,
+	}
+}
+
+// xlsxAmount 最小単位の金額をxlsxのセルの数値に変換（小数のある通貨は通貨の単位の小数にする）
+func xlsxAmount(currency sharedDomain.Currency, amount int64) interface{} {
+	if currency.Digits == 0 {
+		return amount
+	}
+	return float64(amount) / float64(currency.Scale())
+}
+
+// parseYearMonth クエリパラメーターのyear（省略時は今年）・month（省略時は0で年全体）を解析
+func (h *ReportHandler) parseYearMonth(r *http.Request) (int, int, error) {
+	year := h.clock.Now().In(sharedDomain.LocationFromContext(r.Context())).Year()
+	if v := r.URL.Query().Get("year"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1900 || n > 9999 {
+			return 0, 0, fmt.Errorf("invalid year: %s", v)
+		}
+		year = n
+	}
+
+	month := 0
+	if v := r.URL.Query().Get("month"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 12 {
+			return 0, 0, fmt.Errorf("invalid month: %s", v)
+		}
+		month = n
+	}
+	return year, month, nil
+}
+
+// hledgerJournal 仕訳をhledgerのジャーナル形式に変換
+func hledgerJournal(journal *usecase.LedgerJournal) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "; %s - %s\n", journal.Start.Format("2006-01-02"), journal.End.AddDate(0, 0, -1).Format("2006-01-02"))
+	for _, account := range journal.Accounts {
+		fmt.Fprintf(&buf, "account %s\n", account)
+	}
+
+	width := ledgerAccountWidth(journal)
+	for _, tx := range journal.Transactions {
+		fmt.Fprintf(&buf, "\n%s %s  ; receipt_id:%s\n", tx.Date.Format("2006-01-02"), ledgerText(tx.Payee), tx.ReceiptID)
+		if tx.Memo != "" {
+			fmt.Fprintf(&buf, "    ; %s\n", ledgerText(tx.Memo))
+		}
+		for _, posting := range tx.Postings {
+			fmt.Fprintf(&buf, "    %-*s  %s %s\n", width, posting.Account, journal.Currency.Format(posting.Amount), journal.Currency.Code)
+		}
+	}
+	return buf.Bytes()
+}
+
+// beancountLedger 仕訳をbeancountの形式に変換（使用する勘定科目は期間の初日に開設する）
+func beancountLedger(journal *usecase.LedgerJournal) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "; %s - %s\n", journal.Start.Format("2006-01-02"), journal.End.AddDate(0, 0, -1).Format("2006-01-02"))
+	fmt.Fprintf(&buf, "option \"operating_currency\" \"%s\"\n\n", journal.Currency.Code)
+	for _, account := range journal.Accounts {
+		fmt.Fprintf(&buf, "%s open %s %s\n", journal.Start.Format("2006-01-02"), account, journal.Currency.Code)
+	}
+
+	width := ledgerAccountWidth(journal)
+	for _, tx := range journal.Transactions {
+		fmt.Fprintf(&buf, "\n%s * %s %s\n", tx.Date.Format("2006-01-02"), beancountString(tx.Payee), beancountString(tx.Memo))
+		fmt.Fprintf(&buf, "  receipt_id: %s\n", beancountString(tx.ReceiptID))
+		for _, posting := range tx.Postings {
+			fmt.Fprintf(&buf, "  %-*s  %s %s\n", width, posting.Account, journal.Currency.Format(posting.Amount), journal.Currency.Code)
+		}
+	}
+	return buf.Bytes()
+}
+
+// ledgerAccountWidth 金額の列を揃えるための勘定科目の最大幅
+func ledgerAccountWidth(journal *usecase.LedgerJournal) int {
+	width := 0
+	for _, account := range journal.Accounts {
+		if len(account) > width {
+			width = len(account)
+		}
+	}
+	return width
+}
+
+// ledgerText 改行を空白に置き換えて1行にする
+func ledgerText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// beancountString beancountの文字列リテラルに変換
+func beancountString(s string) string {
+	return strconv.Quote(ledgerText(s))
+}
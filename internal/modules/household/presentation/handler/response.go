@@ -0,0 +1,357 @@
+package handler
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/usecase"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+const (
+	// defaultPageLimit 一覧取得時のデフォルト件数
+	defaultPageLimit = 20
+	// maxPageLimit 一覧取得時の最大件数
+	maxPageLimit = 100
+)
+
+// APIResponse 家計簿APIの共通レスポンス
+type APIResponse struct {
+	Success bool        `json:"success"`
+	Data    interface{} `json:"data,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// ReceiptResponse レシートのレスポンス
+type ReceiptResponse struct {
+	ID                string                `json:"id"`
+	StoreName         string                `json:"store_name"`
+	PurchaseDate      time.Time             `json:"purchase_date"`
+	TotalAmount       int64                 `json:"total_amount"`
+	TaxAmount         int64                 `json:"tax_amount"`
+	PaymentMethod     string                `json:"payment_method"`
+	ReceiptNumber     string                `json:"receipt_number"`
+	ReceiptType       string                `json:"receipt_type"`
+	Locale            string                `json:"locale,omitempty"`
+	Currency          string                `json:"currency,omitempty"`
+	Category          string                `json:"category"`
+	NeedsReview       bool                  `json:"needs_review"`
+	JSONRepaired      bool                  `json:"json_repaired,omitempty"`
+	DateOutOfRange    bool                  `json:"date_out_of_range,omitempty"`
+	Extraction        *ExtractionResponse   `json:"extraction,omitempty"` // AIによる読み取りの記録（手動登録のレシートは省略）
+	CategorizationRaw string                `json:"categorization_raw,omitempty"`
+	Tags              []string              `json:"tags"`
+	Memo              string                `json:"memo"`
+	Items             []ReceiptItemResponse `json:"items"`
+	CreatedAt         time.Time             `json:"created_at"`
+	UpdatedAt         time.Time             `json:"updated_at"`
+}
+
+// ExtractionResponse AIによるレシート画像の読み取りの記録のレスポンス
+type ExtractionResponse struct {
+	Model      string `json:"model,omitempty"`
+	StopReason string `json:"stop_reason,omitempty"`
+	Cached     bool   `json:"cached"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// ExtractionComparisonResponse AIの出力の原文と解析結果・現在のレシートの比較のレスポンス
+type ExtractionComparisonResponse struct {
+	Raw           string          `json:"raw"`            // AIの出力の原文
+	Extracted     ReceiptResponse `json:"extracted"`      // 原文を現在の解析処理で読み取ったレシート
+	Current       ReceiptResponse `json:"current"`        // 保存されている現在のレシート
+	ChangedFields []string        `json:"changed_fields"` // 読み取った値と現在の値が異なる項目
+}
+
+// ReceiptItemResponse レシート明細のレスポンス
+type ReceiptItemResponse struct {
+	ID             string  `json:"id"`
+	Name           string  `json:"name"`
+	Quantity       int     `json:"quantity"`
+	Price          int64   `json:"price"`
+	Unit           string  `json:"unit"`
+	Measure        float64 `json:"measure,omitempty"`
+	UnitPrice      int64   `json:"unit_price,omitempty"`
+	Category       string  `json:"category"`
+	CategoryStatus string  `json:"category_status"`
+	WarrantyMonths *int    `json:"warranty_months,omitempty"`
+}
+
+// CategoryItemResponse カテゴリー別の明細のレスポンス
+type CategoryItemResponse struct {
+	ID             string    `json:"id"`
+	ReceiptID      string    `json:"receipt_id"`
+	Name           string    `json:"name"`
+	Quantity       int       `json:"quantity"`
+	Price          int64     `json:"price"`
+	Amount         int64     `json:"amount"` // 単価×数量
+	Category       string    `json:"category"`
+	CategoryStatus string    `json:"category_status"`
+	StoreName      string    `json:"store_name"`
+	PurchaseDate   time.Time `json:"purchase_date"`
+}
+
+// ReceiptRevisionResponse レシート変更履歴のレスポンス
+type ReceiptRevisionResponse struct {
+	Revision  int             `json:"revision"`
+	Source    string          `json:"source"`
+	Receipt   ReceiptResponse `json:"receipt"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// StorageUsageResponse 画像の保存容量の使用状況のレスポンス
+type StorageUsageResponse struct {
+	UsedBytes      int64  `json:"used_bytes"`
+	QuotaBytes     int64  `json:"quota_bytes"`               // 0は無制限
+	RemainingBytes *int64 `json:"remaining_bytes,omitempty"` // 無制限の場合は省略
+	Images         int    `json:"images"`
+	Exceeded       bool   `json:"exceeded"`
+}
+
+// ExpenseResponse 家計簿エントリのレスポンス
+type ExpenseResponse struct {
+	ID          string    `json:"id"`
+	ReceiptID   *string   `json:"receipt_id"`
+	Date        time.Time `json:"date"`
+	Category    string    `json:"category"`
+	Amount      int64     `json:"amount"`
+	Description string    `json:"description"`
+	Tags        []string  `json:"tags"`
+	Memo        string    `json:"memo"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// newExpenseResponse エンティティからレスポンスを作成
+func newExpenseResponse(entry *entity.ExpenseEntry) ExpenseResponse {
+	response := ExpenseResponse{
+		ID:          entry.ID,
+		ReceiptID:   entry.ReceiptID,
+		Date:        entry.Date,
+		Category:    entry.Category,
+		Amount:      entry.Amount,
+		Description: entry.Description,
+		Tags:        entry.Tags,
+		Memo:        entry.Memo,
+		CreatedAt:   entry.CreatedAt,
+		UpdatedAt:   entry.UpdatedAt,
+	}
+
+	if response.Tags == nil {
+		response.Tags = []string{}
+	}
+
+	return response
+}
+
+// newReceiptResponse エンティティからレスポンスを作成
+func newReceiptResponse(receipt *entity.Receipt) ReceiptResponse {
+	response := ReceiptResponse{
+		ID:                receipt.ID,
+		StoreName:         receipt.StoreName,
+		PurchaseDate:      receipt.PurchaseDate,
+		TotalAmount:       receipt.TotalAmount,
+		TaxAmount:         receipt.TaxAmount,
+		PaymentMethod:     receipt.PaymentMethod,
+		ReceiptNumber:     receipt.ReceiptNumber,
+		ReceiptType:       cmp.Or(receipt.Type, entity.ReceiptTypePurchase),
+		Locale:            receipt.Locale,
+		Currency:          receipt.Currency,
+		Category:          receipt.Category,
+		NeedsReview:       receipt.NeedsReview,
+		JSONRepaired:      receipt.JSONRepaired,
+		DateOutOfRange:    receipt.DateOutOfRange,
+		CategorizationRaw: receipt.CategorizationRaw,
+		Tags:              receipt.Tags,
+		Memo:              receipt.Memo,
+		Items:             make([]ReceiptItemResponse, 0, len(receipt.Items)),
+		CreatedAt:         receipt.CreatedAt,
+		UpdatedAt:         receipt.UpdatedAt,
+	}
+
+	if response.Tags == nil {
+		response.Tags = []string{}
+	}
+	if !receipt.Extraction.IsZero() {
+		response.Extraction = &ExtractionResponse{
+			Model:      receipt.Extraction.Model,
+			StopReason: receipt.Extraction.StopReason,
+			Cached:     receipt.Extraction.CacheHit,
+			DurationMs: receipt.Extraction.DurationMs,
+		}
+	}
+
+	for _, item := range receipt.Items {
+		response.Items = append(response.Items, ReceiptItemResponse{
+			ID:             item.ID,
+			Name:           item.Name,
+			Quantity:       item.Quantity,
+			Price:          item.Price,
+			Unit:           cmp.Or(item.Unit, entity.UnitPiece),
+			Measure:        item.Measure,
+			UnitPrice:      item.UnitPrice,
+			Category:       item.Category,
+			CategoryStatus: item.CategoryStatus,
+			WarrantyMonths: item.WarrantyMonths,
+		})
+	}
+
+	return response
+}
+
+// newReceiptListResponse エンティティ一覧からレスポンスを作成
+func newReceiptListResponse(receipts []*entity.Receipt) []ReceiptResponse {
+	responses := make([]ReceiptResponse, 0, len(receipts))
+	for _, receipt := range receipts {
+		responses = append(responses, newReceiptResponse(receipt))
+	}
+	return responses
+}
+
+// newExpenseListResponse エンティティ一覧からレスポンスを作成
+func newExpenseListResponse(entries []*entity.ExpenseEntry) []ExpenseResponse {
+	responses := make([]ExpenseResponse, 0, len(entries))
+	for _, entry := range entries {
+		responses = append(responses, newExpenseResponse(entry))
+	}
+	return responses
+}
+
+// newCategoryItemListResponse カテゴリー別の明細一覧からレスポンスを作成
+func newCategoryItemListResponse(items []*entity.PurchasedItem) []CategoryItemResponse {
+	responses := make([]CategoryItemResponse, 0, len(items))
+	for _, item := range items {
+		category := item.Item.Category
+		if category == "" {
+			category = entity.DefaultCategory
+		}
+		responses = append(responses, CategoryItemResponse{
+			ID:             item.Item.ID,
+			ReceiptID:      item.Item.ReceiptID,
+			Name:           item.Item.Name,
+			Quantity:       item.Item.Quantity,
+			Price:          item.Item.Price,
+			Amount:         item.Amount(),
+			Category:       category,
+			CategoryStatus: item.Item.CategoryStatus,
+			StoreName:      item.StoreName,
+			PurchaseDate:   item.PurchaseDate,
+		})
+	}
+	return responses
+}
+
+// newReceiptHistoryResponse 変更履歴からレスポンスを作成
+func newReceiptHistoryResponse(revisions []*entity.ReceiptRevision) []ReceiptRevisionResponse {
+	responses := make([]ReceiptRevisionResponse, 0, len(revisions))
+	for _, revision := range revisions {
+		responses = append(responses, ReceiptRevisionResponse{
+			Revision:  revision.Revision,
+			Source:    revision.Source,
+			Receipt:   newReceiptResponse(&revision.Snapshot),
+			CreatedAt: revision.CreatedAt,
+		})
+	}
+	return responses
+}
+
+// newExtractionComparisonResponse AIの出力の原文の比較からレスポンスを作成
+func newExtractionComparisonResponse(comparison *usecase.ExtractionComparison) ExtractionComparisonResponse {
+	return ExtractionComparisonResponse{
+		Raw:           comparison.Raw,
+		Extracted:     newReceiptResponse(comparison.Extracted),
+		Current:       newReceiptResponse(comparison.Current),
+		ChangedFields: comparison.ChangedFields,
+	}
+}
+
+// writeJSON 成功レスポンスを送信
+func writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(APIResponse{
+		Success: true,
+		Data:    data,
+	})
+}
+
+// storageQuotaMessage 保存容量の上限を超えた場合のエラーメッセージ
+const storageQuotaMessage = "Storage quota exceeded: delete old receipts or raise storage.quota_mb"
+
+// writeError エラーレスポンスを送信
+func writeError(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(APIResponse{
+		Success: false,
+		Error:   message,
+	})
+}
+
+// writeProcessError レシート画像の処理エラーを送信
+// ウイルスを検出したファイル・読み取り結果が不正なレシートは422、保存容量の上限を超える場合は507で拒否する
+func writeProcessError(w http.ResponseWriter, err error) {
+	if errors.Is(err, sharedDomain.ErrFileInfected) {
+		writeError(w, "File rejected by malware scan", http.StatusUnprocessableEntity)
+		return
+	}
+	if errors.Is(err, usecase.ErrInvalidReceipt) {
+		writeError(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if errors.Is(err, sharedDomain.ErrStorageQuotaExceeded) {
+		writeError(w, storageQuotaMessage, http.StatusInsufficientStorage)
+		return
+	}
+	writeError(w, fmt.Sprintf("Failed to process receipt: %v", err), http.StatusInternalServerError)
+}
+
+// writeAttachment ファイルのダウンロードレスポンスを送信
+func writeAttachment(w http.ResponseWriter, contentType, filename string, data []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+// parseTagList カンマ区切りのタグ文字列を解析
+func parseTagList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return entity.NormalizeTags(strings.Split(value, ","))
+}
+
+// parsePagination limit/offsetクエリパラメータを解析
+func parsePagination(r *http.Request) (int, int, error) {
+	limit := defaultPageLimit
+	offset := 0
+
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return 0, 0, fmt.Errorf("invalid limit: %s", v)
+		}
+		limit = n
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+
+	if v := r.URL.Query().Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return 0, 0, fmt.Errorf("invalid offset: %s", v)
+		}
+		offset = n
+	}
+
+	return limit, offset, nil
+}
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/usecase"
+)
+
+// SplitHandler レシートの割り勘APIのハンドラー
+type SplitHandler struct {
+	splitUseCase *usecase.SplitUseCase
+}
+
+// NewSplitHandler 新しいSplitHandlerを作成
+func NewSplitHandler(splitUseCase *usecase.SplitUseCase) *SplitHandler {
+	return &SplitHandler{
+		splitUseCase: splitUseCase,
+	}
+}
+
+// splitRequest 割り勘リクエスト
+type splitRequest struct {
+	Payer        string                    `json:"payer"`
+	Participants []splitParticipantRequest `json:"participants"`
+}
+
+// splitParticipantRequest 参加者と担当する明細
+type splitParticipantRequest struct {
+	Name    string   `json:"name"`
+	ItemIDs []string `json:"item_ids"`
+}
+
+// settleRequest 精算状態の変更リクエスト
+type settleRequest struct {
+	Settled *bool `json:"settled"`
+}
+
+// SplitResponse 割り勘のレスポンス
+type SplitResponse struct {
+	ReceiptID      string                     `json:"receipt_id"`
+	Payer          string                     `json:"payer,omitempty"`
+	Participants   []SplitParticipantResponse `json:"participants"`
+	Settled        bool                       `json:"settled"`         // 全員の精算が済んでいるか
+	UnsettledTotal int64                      `json:"unsettled_total"` // 未精算の負担額の合計
+	CreatedAt      time.Time                  `json:"created_at"`
+	UpdatedAt      time.Time                  `json:"updated_at"`
+}
+
+// SplitParticipantResponse 参加者ごとの負担額のレスポンス
+type SplitParticipantResponse struct {
+	Name      string     `json:"name"`
+	ItemIDs   []string   `json:"item_ids"`
+	Subtotal  int64      `json:"subtotal"`
+	Tax       int64      `json:"tax"` // 支払額と明細の合計の差額（外税・値引きなど）の按分額
+	Total     int64      `json:"total"`
+	Settled   bool       `json:"settled"`
+	SettledAt *time.Time `json:"settled_at,omitempty"`
+}
+
+// HandlePut 明細を参加者に割り当てて負担額を計算する（既存の割り勘は置き換え）
+func (h *SplitHandler) HandlePut(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	var req splitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	assignments := make([]entity.SplitAssignment, 0, len(req.Participants))
+	for _, p := range req.Participants {
+		assignments = append(assignments, entity.SplitAssignment{Participant: p.Name, ItemIDs: p.ItemIDs})
+	}
+
+	split, err := h.splitUseCase.SplitReceipt(r.Context(), id, req.Payer, assignments)
+	if errors.Is(err, usecase.ErrReceiptNotFound) {
+		writeError(w, "Receipt not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, entity.ErrInvalidSplit) {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to split receipt", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newSplitResponse(split))
+}
+
+// HandleGet レシートの割り勘を取得
+func (h *SplitHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	split, err := h.splitUseCase.GetSplit(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, "Split not found", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newSplitResponse(split))
+}
+
+// HandleDelete レシートの割り勘を削除
+func (h *SplitHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	err := h.splitUseCase.DeleteSplit(r.Context(), r.PathValue("id"))
+	if errors.Is(err, usecase.ErrSplitNotFound) {
+		writeError(w, "Split not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to delete split", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleSettle 参加者の精算状態を変更する
+func (h *SplitHandler) HandleSettle(w http.ResponseWriter, r *http.Request) {
+	var req settleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Settled == nil {
+		writeError(w, "Invalid request: settled is required", http.StatusBadRequest)
+		return
+	}
+
+	split, err := h.splitUseCase.SetSettled(r.Context(), r.PathValue("id"), r.PathValue("name"), *req.Settled)
+	if errors.Is(err, usecase.ErrSplitNotFound) {
+		writeError(w, "Split not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, usecase.ErrParticipantNotFound) {
+		writeError(w, "Participant not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, entity.ErrInvalidSplit) {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to update settlement", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newSplitResponse(split))
+}
+
+// HandleListUnsettled 未精算の参加者が残る割り勘を取得
+func (h *SplitHandler) HandleListUnsettled(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	splits, err := h.splitUseCase.ListUnsettled(r.Context(), limit, offset)
+	if err != nil {
+		writeError(w, "Failed to get splits", http.StatusInternalServerError)
+		return
+	}
+
+	responses := make([]SplitResponse, 0, len(splits))
+	for _, split := range splits {
+		responses = append(responses, newSplitResponse(split))
+	}
+	writeJSON(w, http.StatusOK, responses)
+}
+
+// newSplitResponse 割り勘をレスポンスに変換
+func newSplitResponse(split *entity.Split) SplitResponse {
+	participants := make([]SplitParticipantResponse, 0, len(split.Participants))
+	for _, p := range split.Participants {
+		participants = append(participants, SplitParticipantResponse{
+			Name:      p.Name,
+			ItemIDs:   p.ItemIDs,
+			Subtotal:  p.Subtotal,
+			Tax:       p.Tax,
+			Total:     p.Total,
+			Settled:   p.Settled,
+			SettledAt: p.SettledAt,
+		})
+	}
+
+	return SplitResponse{
+		ReceiptID:      split.ReceiptID,
+		Payer:          split.Payer,
+		Participants:   participants,
+		Settled:        split.IsSettled(),
+		UnsettledTotal: split.UnsettledTotal(),
+		CreatedAt:      split.CreatedAt,
+		UpdatedAt:      split.UpdatedAt,
+	}
+}
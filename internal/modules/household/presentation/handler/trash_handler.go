@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/usecase"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// maxTrashBatchSize 一括操作で一度に指定できる項目数
+const maxTrashBatchSize = 100
+
+// 一括操作の項目ごとの結果
+const (
+	trashStatusRestored = "restored"  // 元に戻した
+	trashStatusPurged   = "purged"    // 完全に削除した
+	trashStatusNotFound = "not_found" // ゴミ箱にない
+	trashStatusConflict = "conflict"  // 同じIDの項目が登録済み
+	trashStatusInvalid  = "invalid"   // 種類が不正
+	trashStatusFailed   = "failed"
+)
+
+// TrashHandler 削除したレシート・家計簿エントリのゴミ箱APIのハンドラー
+type TrashHandler struct {
+	trashUseCase *usecase.TrashUseCase
+	clock        sharedDomain.Clock
+}
+
+// NewTrashHandler 新しいTrashHandlerを作成
+func NewTrashHandler(trashUseCase *usecase.TrashUseCase) *TrashHandler {
+	return &TrashHandler{
+		trashUseCase: trashUseCase,
+		clock:        sharedDomain.SystemClock{},
+	}
+}
+
+// SetClock 完全に削除されるまでの日数の計算に使う時計を設定する
+func (h *TrashHandler) SetClock(clock sharedDomain.Clock) {
+	h.clock = clock
+}
+
+// trashBatchRequest ゴミ箱の一括操作リクエスト
+type trashBatchRequest struct {
+	Items []trashItemRequest `json:"items"`
+}
+
+// trashItemRequest 一括操作する項目
+type trashItemRequest struct {
+	Kind string `json:"kind"` // receipt / expense
+	ID   string `json:"id"`
+}
+
+// TrashEntryResponse ゴミ箱の項目のレスポンス
+type TrashEntryResponse struct {
+	Kind          string           `json:"kind"`
+	ID            string           `json:"id"`
+	DeletedAt     time.Time        `json:"deleted_at"`
+	PurgeAt       time.Time        `json:"purge_at"`
+	RemainingDays int              `json:"remaining_days"` // 完全に削除されるまでの日数
+	Receipt       *ReceiptResponse `json:"receipt,omitempty"`
+	Expense       *ExpenseResponse `json:"expense,omitempty"`
+}
+
+// TrashResultResponse 一括操作の項目ごとの結果のレスポンス
+type TrashResultResponse struct {
+	Kind   string `json:"kind"`
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+// HandleDeleteReceipt レシートをゴミ箱に移す
+func (h *TrashHandler) HandleDeleteReceipt(w http.ResponseWriter, r *http.Request) {
+	err := h.trashUseCase.TrashReceipt(r.Context(), r.PathValue("id"))
+	if errors.Is(err, usecase.ErrReceiptNotFound) {
+		writeError(w, "Receipt not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to delete receipt", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleDeleteExpense 家計簿エントリをゴミ箱に移す
+func (h *TrashHandler) HandleDeleteExpense(w http.ResponseWriter, r *http.Request) {
+	err := h.trashUseCase.TrashExpense(r.Context(), r.PathValue("id"))
+	if errors.Is(err, usecase.ErrExpenseNotFound) {
+		writeError(w, "Expense not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to delete expense", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleList ゴミ箱の項目を削除日時の新しい順に取得（?kind=receipt|expense で絞り込み）
+func (h *TrashHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.trashUseCase.ListTrash(r.Context(), r.URL.Query().Get("kind"), limit, offset)
+	if errors.Is(err, usecase.ErrInvalidTrashKind) {
+		writeError(w, "Invalid kind: must be receipt or expense", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to get trash", http.StatusInternalServerError)
+		return
+	}
+
+	now := h.clock.Now()
+	responses := make([]TrashEntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		responses = append(responses, newTrashEntryResponse(entry, now))
+	}
+	writeJSON(w, http.StatusOK, responses)
+}
+
+// HandleRestore ゴミ箱の項目をまとめて元に戻す
+func (h *TrashHandler) HandleRestore(w http.ResponseWriter, r *http.Request) {
+	refs, ok := decodeTrashBatch(w, r)
+	if !ok {
+		return
+	}
+
+	results := h.trashUseCase.RestoreEntries(r.Context(), refs)
+	writeJSON(w, http.StatusOK, newTrashResultResponses(results, trashStatusRestored))
+}
+
+// HandlePurge ゴミ箱の項目をまとめて完全に削除する
+func (h *TrashHandler) HandlePurge(w http.ResponseWriter, r *http.Request) {
+	refs, ok := decodeTrashBatch(w, r)
+	if !ok {
+		return
+	}
+
+	results := h.trashUseCase.PurgeEntries(r.Context(), refs)
+	writeJSON(w, http.StatusOK, newTrashResultResponses(results, trashStatusPurged))
+}
+
+// decodeTrashBatch 一括操作リクエストを読み取る（不正な場合は400を返してfalse）
+func decodeTrashBatch(w http.ResponseWriter, r *http.Request) ([]usecase.TrashRef, bool) {
+	var req trashBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Items) == 0 {
+		writeError(w, "Invalid request: items are required", http.StatusBadRequest)
+		return nil, false
+	}
+	if len(req.Items) > maxTrashBatchSize {
+		writeError(w, "Too many items: up to 100 items per request", http.StatusBadRequest)
+		return nil, false
+	}
+
+	refs := make([]usecase.TrashRef, 0, len(req.Items))
+	for _, item := range req.Items {
+		refs = append(refs, usecase.TrashRef{Kind: item.Kind, ID: item.ID})
+	}
+	return refs, true
+}
+
+// newTrashEntryResponse ゴミ箱の項目からレスポンスを作成
+func newTrashEntryResponse(entry *entity.TrashEntry, now time.Time) TrashEntryResponse {
+	response := TrashEntryResponse{
+		Kind:          entry.Kind,
+		ID:            entry.ID,
+		DeletedAt:     entry.DeletedAt,
+		PurgeAt:       entry.PurgeAt,
+		RemainingDays: entry.RemainingDays(now),
+	}
+	if entry.Receipt != nil {
+		receipt := newReceiptResponse(entry.Receipt)
+		response.Receipt = &receipt
+	}
+	if entry.Expense != nil {
+		expense := newExpenseResponse(entry.Expense)
+		response.Expense = &expense
+	}
+	return response
+}
+
+// newTrashResultResponses 一括操作の結果からレスポンスを作成
+func newTrashResultResponses(results []usecase.TrashResult, success string) []TrashResultResponse {
+	responses := make([]TrashResultResponse, 0, len(results))
+	for _, result := range results {
+		status := success
+		switch {
+		case result.Err == nil:
+		case errors.Is(result.Err, usecase.ErrTrashEntryNotFound):
+			status = trashStatusNotFound
+		case errors.Is(result.Err, usecase.ErrRestoreConflict):
+			status = trashStatusConflict
+		case errors.Is(result.Err, usecase.ErrInvalidTrashKind):
+			status = trashStatusInvalid
+		default:
+			status = trashStatusFailed
+		}
+		responses = append(responses, TrashResultResponse{Kind: result.Kind, ID: result.ID, Status: status})
+	}
+	return responses
+}
@@ -0,0 +1,255 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"vision-api-app/internal/modules/household/usecase"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+	"vision-api-app/internal/modules/shared/presentation/bufpool"
+)
+
+// UploadHandler 署名付きURLによる直接アップロードのハンドラー
+type UploadHandler struct {
+	uploadUseCase *usecase.UploadUseCase
+}
+
+// NewUploadHandler 新しいUploadHandlerを作成
+func NewUploadHandler(uploadUseCase *usecase.UploadUseCase) *UploadHandler {
+	return &UploadHandler{
+		uploadUseCase: uploadUseCase,
+	}
+}
+
+// PresignResponse 署名付きアップロードURLのレスポンス
+type PresignResponse struct {
+	UploadID    string    `json:"upload_id"`
+	Method      string    `json:"method"`
+	UploadURL   string    `json:"upload_url"`
+	CompleteURL string    `json:"complete_url"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	MaxSize     int64     `json:"max_size"`
+}
+
+// UploadProgressResponse 分割アップロードの進捗のレスポンス
+type UploadProgressResponse struct {
+	UploadID string `json:"upload_id"`
+	Offset   int64  `json:"offset"`
+	Complete bool   `json:"complete"`
+}
+
+// completeUploadRequest アップロード完了通知のリクエスト（すべて任意）
+type completeUploadRequest struct {
+	Tags         []string `json:"tags"`
+	Memo         string   `json:"memo"`
+	KeepLocation bool     `json:"keep_location"` // 位置情報などのメタデータを画像に残す
+}
+
+// HandlePresign アップロード用の署名付きURLを発行
+func (h *UploadHandler) HandlePresign(w http.ResponseWriter, r *http.Request) {
+	upload, err := h.uploadUseCase.Presign(r.Context())
+	if err != nil {
+		writeError(w, "Failed to issue upload URL", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, PresignResponse{
+		UploadID:    upload.UploadID,
+		Method:      http.MethodPut,
+		UploadURL:   upload.UploadURL,
+		CompleteURL: upload.CompleteURL,
+		ExpiresAt:   upload.ExpiresAt,
+		MaxSize:     h.uploadUseCase.MaxSize(),
+	})
+}
+
+// HandleUpload 署名付きURLへの画像本体のアップロードを受け付ける（リクエストボディが画像）
+// Content-Range ヘッダー（bytes 開始-終了/全体）を指定すると分割アップロードとして追記する
+// 署名のないリクエストで大きなボディを受信しないよう、署名と有効期限はボディを読み込む前に確認する
+func (h *UploadHandler) HandleUpload(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	err := h.uploadUseCase.VerifyUpload(r.PathValue("id"), query.Get("expires"), query.Get("signature"))
+	if errors.Is(err, usecase.ErrUploadToObjectStore) {
+		// オブジェクトストレージを使う構成では、画像本体は発行したupload_urlへ直接アップロードさせる
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeError(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	if r.Header.Get("Content-Range") != "" {
+		h.handleChunk(w, r)
+		return
+	}
+
+	// 画像は保存先へ書き込むまでのみ参照するため、バッファはレスポンスを返した後にプールへ戻す
+	body := http.MaxBytesReader(w, r.Body, h.uploadUseCase.MaxSize())
+	buf, err := bufpool.ReadAll(body, r.ContentLength)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, "Image is too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		writeError(w, "Failed to read image", http.StatusBadRequest)
+		return
+	}
+	defer buf.Release()
+	data := buf.Bytes()
+	if len(data) == 0 {
+		writeError(w, "Image is required", http.StatusBadRequest)
+		return
+	}
+
+	err = h.uploadUseCase.StoreUpload(r.Context(), r.PathValue("id"), query.Get("expires"), query.Get("signature"), data)
+	if errors.Is(err, usecase.ErrInvalidUploadSignature) {
+		writeError(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if errors.Is(err, sharedDomain.ErrStorageQuotaExceeded) {
+		writeError(w, storageQuotaMessage, http.StatusInsufficientStorage)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to store image", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"upload_id": r.PathValue("id")})
+}
+
+// handleChunk 分割アップロードの一部を受け付ける
+func (h *UploadHandler) handleChunk(w http.ResponseWriter, r *http.Request) {
+	start, end, total, err := parseContentRange(r.Header.Get("Content-Range"))
+	if err != nil {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if total > h.uploadUseCase.MaxSize() {
+		writeError(w, "Image is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	buf, err := bufpool.ReadAll(http.MaxBytesReader(w, r.Body, h.uploadUseCase.MaxSize()), r.ContentLength)
+	if err != nil {
+		writeError(w, "Failed to read chunk", http.StatusBadRequest)
+		return
+	}
+	defer buf.Release()
+	data := buf.Bytes()
+	if int64(len(data)) != end-start+1 {
+		writeError(w, "Chunk size does not match Content-Range", http.StatusBadRequest)
+		return
+	}
+
+	uploadID := r.PathValue("id")
+	query := r.URL.Query()
+	progress, err := h.uploadUseCase.StoreChunk(r.Context(), uploadID, query.Get("expires"), query.Get("signature"), start, total, data)
+	switch {
+	case errors.Is(err, usecase.ErrInvalidUploadSignature):
+		writeError(w, err.Error(), http.StatusForbidden)
+		return
+	case errors.Is(err, usecase.ErrUploadTooLarge):
+		writeError(w, "Image is too large", http.StatusRequestEntityTooLarge)
+		return
+	case errors.Is(err, sharedDomain.ErrStorageQuotaExceeded):
+		writeError(w, storageQuotaMessage, http.StatusInsufficientStorage)
+		return
+	case errors.Is(err, usecase.ErrUploadOffsetMismatch):
+		// 受信済みの位置を返し、クライアントにそこから再送させる
+		w.Header().Set("Upload-Offset", strconv.FormatInt(progress.Offset, 10))
+		writeError(w, fmt.Sprintf("Chunk must start at offset %d", progress.Offset), http.StatusConflict)
+		return
+	case err != nil:
+		writeError(w, "Failed to store chunk", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeProgress(w, uploadID, progress)
+}
+
+// HandleStatus アップロードの受信状況を取得（中断した分割アップロードの再開位置の確認に使用）
+func (h *UploadHandler) HandleStatus(w http.ResponseWriter, r *http.Request) {
+	uploadID := r.PathValue("id")
+	query := r.URL.Query()
+	progress, err := h.uploadUseCase.UploadStatus(r.Context(), uploadID, query.Get("expires"), query.Get("signature"))
+	if errors.Is(err, usecase.ErrInvalidUploadSignature) {
+		writeError(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if errors.Is(err, usecase.ErrUploadToObjectStore) {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeError(w, "Failed to get upload status", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeProgress(w, uploadID, progress)
+}
+
+// writeProgress 受信状況をレスポンスとして送信
+func (h *UploadHandler) writeProgress(w http.ResponseWriter, uploadID string, progress *usecase.UploadProgress) {
+	w.Header().Set("Upload-Offset", strconv.FormatInt(progress.Offset, 10))
+	writeJSON(w, http.StatusOK, UploadProgressResponse{
+		UploadID: uploadID,
+		Offset:   progress.Offset,
+		Complete: progress.Complete,
+	})
+}
+
+// parseContentRange Content-Range ヘッダー（bytes 開始-終了/全体）を解析
+func parseContentRange(value string) (int64, int64, int64, error) {
+	var start, end, total int64
+	if _, err := fmt.Sscanf(value, "bytes %d-%d/%d", &start, &end, &total); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid Content-Range: %s", value)
+	}
+	if start < 0 || end < start || total <= end {
+		return 0, 0, 0, fmt.Errorf("invalid Content-Range: %s", value)
+	}
+	return start, end, total, nil
+}
+
+// HandleComplete アップロード完了通知を受けてレシートを登録
+func (h *UploadHandler) HandleComplete(w http.ResponseWriter, r *http.Request) {
+	var req completeUploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	receipt, err := h.uploadUseCase.CompleteUpload(r.Context(), r.PathValue("id"), query.Get("expires"), query.Get("signature"), usecase.ProcessOptions{
+		Tags:         req.Tags,
+		Memo:         req.Memo,
+		KeepLocation: req.KeepLocation,
+	})
+	switch {
+	case errors.Is(err, usecase.ErrInvalidUploadSignature):
+		writeError(w, err.Error(), http.StatusForbidden)
+		return
+	case errors.Is(err, usecase.ErrUploadNotFound):
+		writeError(w, "Upload not found", http.StatusNotFound)
+		return
+	case errors.Is(err, usecase.ErrUploadTooLarge):
+		writeError(w, "Image is too large", http.StatusRequestEntityTooLarge)
+		return
+	case errors.Is(err, usecase.ErrSavePending):
+		// データベース障害中は一時保管し、復旧後に保存される
+		writeJSON(w, http.StatusAccepted, newReceiptResponse(receipt))
+		return
+	case err != nil:
+		writeProcessError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newReceiptResponse(receipt))
+}
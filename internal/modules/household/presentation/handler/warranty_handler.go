@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"vision-api-app/internal/modules/household/usecase"
+)
+
+const (
+	// defaultExpiringDays 期限が近い商品を取得する期間のデフォルト（日数）
+	defaultExpiringDays = 30
+	// maxExpiringDays 期限が近い商品を取得する期間の上限（日数）
+	maxExpiringDays = 365
+)
+
+// WarrantyHandler 返品・保証期限APIのハンドラー
+type WarrantyHandler struct {
+	warrantyUseCase *usecase.WarrantyUseCase
+}
+
+// NewWarrantyHandler 新しいWarrantyHandlerを作成
+func NewWarrantyHandler(warrantyUseCase *usecase.WarrantyUseCase) *WarrantyHandler {
+	return &WarrantyHandler{
+		warrantyUseCase: warrantyUseCase,
+	}
+}
+
+// ExpiringItemResponse 期限が近い商品のレスポンス
+type ExpiringItemResponse struct {
+	ReceiptID      string    `json:"receipt_id"`
+	ItemID         string    `json:"item_id"`
+	Name           string    `json:"name"`
+	Price          int64     `json:"price"`
+	StoreName      string    `json:"store_name"`
+	PurchaseDate   time.Time `json:"purchase_date"`
+	WarrantyMonths *int      `json:"warranty_months,omitempty"` // 省略時は warranties.default_months
+	Kind           string    `json:"kind"`                      // return: 返品期限, warranty: 保証期限
+	Deadline       time.Time `json:"deadline"`
+}
+
+// HandleListExpiring ?days= 日以内に返品・保証期限を迎える商品を取得
+func (h *WarrantyHandler) HandleListExpiring(w http.ResponseWriter, r *http.Request) {
+	days := defaultExpiringDays
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || n > maxExpiringDays {
+			writeError(w, "invalid days: "+v, http.StatusBadRequest)
+			return
+		}
+		days = n
+	}
+
+	expiring, err := h.warrantyUseCase.ListExpiring(r.Context(), days)
+	if err != nil {
+		writeError(w, "Failed to get expiring warranties", http.StatusInternalServerError)
+		return
+	}
+
+	responses := make([]ExpiringItemResponse, 0, len(expiring))
+	for _, e := range expiring {
+		responses = append(responses, ExpiringItemResponse{
+			ReceiptID:      e.Item.Item.ReceiptID,
+			ItemID:         e.Item.Item.ID,
+			Name:           e.Item.Item.Name,
+			Price:          e.Item.Item.Price,
+			StoreName:      e.Item.StoreName,
+			PurchaseDate:   e.Item.PurchaseDate,
+			WarrantyMonths: e.Item.Item.WarrantyMonths,
+			Kind:           e.Kind,
+			Deadline:       e.Deadline,
+		})
+	}
+	writeJSON(w, http.StatusOK, responses)
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"vision-api-app/internal/modules/household/usecase"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// maxWidgetSeries ウィジェットの系列数に指定できる上限
+const maxWidgetSeries = 50
+
+// WidgetHandler ダッシュボードのウィジェット向けの集計APIのハンドラー
+type WidgetHandler struct {
+	widgetUseCase *usecase.WidgetUseCase
+}
+
+// NewWidgetHandler 新しいWidgetHandlerを作成
+func NewWidgetHandler(widgetUseCase *usecase.WidgetUseCase) *WidgetHandler {
+	return &WidgetHandler{
+		widgetUseCase: widgetUseCase,
+	}
+}
+
+// WidgetPeriodResponse ウィジェットの横軸の期間のレスポンス
+type WidgetPeriodResponse struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"` // この日時を含まない
+}
+
+// WidgetSeriesResponse ウィジェットの系列のレスポンス
+type WidgetSeriesResponse struct {
+	Name  string  `json:"name"`
+	Total int64   `json:"total"`
+	Data  []int64 `json:"data"` // labelsと同じ順の値
+}
+
+// WidgetResponse ウィジェットの集計結果のレスポンス（グラフライブラリにそのまま渡せる形）
+type WidgetResponse struct {
+	Metric  string                 `json:"metric"`
+	GroupBy string                 `json:"group_by"`
+	Period  string                 `json:"period"`
+	From    string                 `json:"from"`
+	To      string                 `json:"to"`
+	Labels  []string               `json:"labels"` // 期間の開始日（period=allの場合は「from〜to」）
+	Periods []WidgetPeriodResponse `json:"periods"`
+	Series  []WidgetSeriesResponse `json:"series"`
+}
+
+// HandleWidget 指標・系列の分け方・期間・絞り込みを指定してレシートの明細を集計
+// from / to（YYYY-MM-DD、両端の日を含む）は必須。metric は amount / item_count / receipt_count / average（省略時は amount）、
+// group_by は none / category / store / payment_method / tag（省略時は none）、period は day / week / month / all（省略時は month）。
+// category / store / payment_method / tag で絞り込み、limit で系列数（1〜50、省略時は10）を指定できる
+func (h *WidgetHandler) HandleWidget(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	loc := sharedDomain.LocationFromContext(r.Context())
+
+	from, err := time.ParseInLocation(time.DateOnly, query.Get("from"), loc)
+	if err != nil {
+		writeError(w, fmt.Sprintf("invalid from: %s", query.Get("from")), http.StatusBadRequest)
+		return
+	}
+	to, err := time.ParseInLocation(time.DateOnly, query.Get("to"), loc)
+	if err != nil {
+		writeError(w, fmt.Sprintf("invalid to: %s", query.Get("to")), http.StatusBadRequest)
+		return
+	}
+
+	widgetQuery := usecase.WidgetQuery{
+		Metric:  usecase.WidgetMetricAmount,
+		GroupBy: usecase.WidgetGroupNone,
+		Period:  usecase.ReportGroupMonth,
+		From:    from,
+		To:      to,
+		Filter: usecase.WidgetFilter{
+			Category:      query.Get("category"),
+			StoreName:     query.Get("store"),
+			PaymentMethod: query.Get("payment_method"),
+			Tag:           query.Get("tag"),
+		},
+	}
+	if v := query.Get("metric"); v != "" {
+		widgetQuery.Metric = v
+	}
+	if v := query.Get("group_by"); v != "" {
+		widgetQuery.GroupBy = v
+	}
+	if v := query.Get("period"); v != "" {
+		widgetQuery.Period = v
+	}
+	if v := query.Get("month_start_day"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > usecase.MaxMonthStartDay {
+			writeError(w, fmt.Sprintf("invalid month_start_day: %s", v), http.StatusBadRequest)
+			return
+		}
+		widgetQuery.MonthStartDay = n
+	}
+	if v := query.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxWidgetSeries {
+			writeError(w, fmt.Sprintf("invalid limit: %s", v), http.StatusBadRequest)
+			return
+		}
+		widgetQuery.MaxSeries = n
+	}
+
+	data, err := h.widgetUseCase.GetWidgetData(r.Context(), widgetQuery)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidReportPeriod) {
+			writeError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeError(w, "Failed to generate widget data", http.StatusInternalServerError)
+		return
+	}
+
+	response := WidgetResponse{
+		Metric:  widgetQuery.Metric,
+		GroupBy: widgetQuery.GroupBy,
+		Period:  widgetQuery.Period,
+		From:    from.Format(time.DateOnly),
+		To:      to.Format(time.DateOnly),
+		Labels:  make([]string, 0, len(data.Periods)),
+		Periods: make([]WidgetPeriodResponse, 0, len(data.Periods)),
+		Series:  make([]WidgetSeriesResponse, 0, len(data.Series)),
+	}
+	for _, period := range data.Periods {
+		label := period.Start.Format(time.DateOnly)
+		if widgetQuery.Period == usecase.WidgetPeriodAll {
+			label = response.From + "〜" + response.To
+		}
+		response.Labels = append(response.Labels, label)
+		response.Periods = append(response.Periods, WidgetPeriodResponse{Start: period.Start, End: period.End})
+	}
+	for _, series := range data.Series {
+		response.Series = append(response.Series, WidgetSeriesResponse{Name: series.Name, Total: series.Total, Data: series.Data})
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
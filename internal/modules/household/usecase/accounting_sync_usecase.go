@@ -0,0 +1,376 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// 競合の解決方法
+const (
+	SyncResolveLocal  = "local"  // レシートの内容で会計サービス側を上書きする
+	SyncResolveRemote = "remote" // 会計サービス側の内容を正として同期済みにする
+)
+
+var (
+	// ErrUnknownProvider 有効になっていない会計サービス
+	ErrUnknownProvider = errors.New("unknown accounting provider")
+	// ErrSyncNotFound レシートの同期状態が存在しない
+	ErrSyncNotFound = errors.New("accounting sync not found")
+	// ErrSyncNotConflicted 競合していない同期状態は解決できない
+	ErrSyncNotConflicted = errors.New("accounting sync is not in conflict")
+	// ErrSyncConflict 会計サービス側で取引が変更されている
+	ErrSyncConflict = errors.New("accounting deal was modified remotely")
+)
+
+// AccountingSyncRules 会計サービスとの同期のルール
+type AccountingSyncRules struct {
+	MaxAttempts int // 連続して失敗した場合に自動で再送する回数の上限
+	BatchSize   int // 1回の同期で送信するレシート数の上限（会計サービスごと）
+}
+
+// AccountingSyncReport 1回の同期の結果
+type AccountingSyncReport struct {
+	Synced    int `json:"synced"`
+	Failed    int `json:"failed"`
+	Conflicts int `json:"conflicts"`
+}
+
+// AccountingSyncUseCase 確認済みのレシートを会計サービスへ送信するユースケース
+type AccountingSyncUseCase struct {
+	receiptRepo repository.ReceiptRepository
+	syncRepo    repository.AccountingSyncRepository
+	providers   map[string]repository.AccountingRepository
+	rules       AccountingSyncRules
+	now         func() time.Time
+}
+
+// NewAccountingSyncUseCase 新しいAccountingSyncUseCaseを作成
+func NewAccountingSyncUseCase(receiptRepo repository.ReceiptRepository, syncRepo repository.AccountingSyncRepository, rules AccountingSyncRules) *AccountingSyncUseCase {
+	return &AccountingSyncUseCase{
+		receiptRepo: receiptRepo,
+		syncRepo:    syncRepo,
+		providers:   make(map[string]repository.AccountingRepository),
+		rules:       rules,
+		now:         time.Now,
+	}
+}
+
+// AddProvider 同期先の会計サービスを追加する
+func (uc *AccountingSyncUseCase) AddProvider(provider repository.AccountingRepository) {
+	uc.providers[provider.Provider()] = provider
+}
+
+// Providers 同期先の会計サービスの名前を取得
+func (uc *AccountingSyncUseCase) Providers() []string {
+	names := make([]string, 0, len(uc.providers))
+	for name := range uc.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SyncPending 送信が必要なレシートを会計サービスごとに送信する
+// 失敗したレシートは次回の同期で再送し、競合したレシートは解決されるまで送信しない
+func (uc *AccountingSyncUseCase) SyncPending(ctx context.Context) (*AccountingSyncReport, error) {
+	report := &AccountingSyncReport{}
+	for _, name := range uc.Providers() {
+		receiptIDs, err := uc.syncRepo.FindPendingReceiptIDs(ctx, name, uc.rules.MaxAttempts, uc.rules.BatchSize)
+		if err != nil {
+			return report, fmt.Errorf("failed to find receipts to sync: %w", err)
+		}
+		if len(receiptIDs) == 0 {
+			continue
+		}
+
+		// レシートと同期状態はまとめて取得する（レシートごとに取得しない）
+		receipts, err := uc.receiptRepo.FindByIDs(ctx, receiptIDs)
+		if err != nil {
+			return report, fmt.Errorf("failed to get receipts: %w", err)
+		}
+		syncs, err := uc.syncRepo.FindByReceiptIDs(ctx, receiptIDs)
+		if err != nil {
+			return report, fmt.Errorf("failed to find accounting syncs: %w", err)
+		}
+		receiptByID := make(map[string]*entity.Receipt, len(receipts))
+		for _, receipt := range receipts {
+			receiptByID[receipt.ID] = receipt
+		}
+		syncByID := make(map[string]*entity.AccountingSync, len(syncs))
+		for _, sync := range syncs {
+			if sync.Provider == name {
+				syncByID[sync.ReceiptID] = sync
+			}
+		}
+
+		for _, receiptID := range receiptIDs {
+			if err := ctx.Err(); err != nil {
+				return report, err
+			}
+
+			receipt, ok := receiptByID[receiptID]
+			if !ok {
+				report.Failed++
+				slog.Warn("Failed to sync receipt to accounting service", "provider", name, "receipt_id", receiptID, "error", ErrReceiptNotFound)
+				continue
+			}
+			current := syncByID[receiptID]
+			if current == nil {
+				current = &entity.AccountingSync{ReceiptID: receiptID, Provider: name}
+			}
+
+			sync, err := uc.push(ctx, name, receipt, current, false)
+			switch {
+			case errors.Is(err, ErrSyncConflict):
+				report.Conflicts++
+			case err != nil:
+				report.Failed++
+				slog.Warn("Failed to sync receipt to accounting service", "provider", name, "receipt_id", receiptID, "error", err)
+			case sync != nil:
+				report.Synced++
+			}
+		}
+	}
+
+	if report.Synced > 0 || report.Failed > 0 || report.Conflicts > 0 {
+		slog.Info("Accounting sync finished", "synced", report.Synced, "failed", report.Failed, "conflicts", report.Conflicts)
+	}
+	return report, nil
+}
+
+// SyncReceipt レシートを会計サービスへ送信する
+// 送信済みの取引が会計サービス側で変更されていた場合は上書きせず、競合として ErrSyncConflict を返す
+func (uc *AccountingSyncUseCase) SyncReceipt(ctx context.Context, providerName, receiptID string) (*entity.AccountingSync, error) {
+	return uc.sync(ctx, providerName, receiptID, false)
+}
+
+// GetSyncStatus レシートの会計サービスごとの同期状態を取得
+func (uc *AccountingSyncUseCase) GetSyncStatus(ctx context.Context, receiptID string) ([]*entity.AccountingSync, error) {
+	return uc.syncRepo.FindByReceiptID(ctx, receiptID)
+}
+
+// ListByStatus 指定した状態（競合・失敗など）の同期状態を取得
+func (uc *AccountingSyncUseCase) ListByStatus(ctx context.Context, providerName, status string, limit, offset int) ([]*entity.AccountingSync, error) {
+	if _, ok := uc.providers[providerName]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, providerName)
+	}
+	return uc.syncRepo.FindByStatus(ctx, providerName, status, limit, offset)
+}
+
+// Resolve 競合した同期状態を解決する
+// local はレシートの内容で会計サービス側を上書きし、remote は会計サービス側の内容を正として同期済みにする
+func (uc *AccountingSyncUseCase) Resolve(ctx context.Context, providerName, receiptID, strategy string) (*entity.AccountingSync, error) {
+	provider, ok := uc.providers[providerName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, providerName)
+	}
+
+	current, err := uc.findSync(ctx, providerName, receiptID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSyncNotFound, receiptID)
+	}
+	if current.Status != entity.SyncStatusConflict {
+		return nil, fmt.Errorf("%w: %s", ErrSyncNotConflicted, current.Status)
+	}
+
+	switch strategy {
+	case SyncResolveLocal:
+		return uc.sync(ctx, providerName, receiptID, true)
+	case SyncResolveRemote:
+		receipt, err := uc.receiptRepo.FindByID(ctx, receiptID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrReceiptNotFound, err)
+		}
+		version, err := provider.DealVersion(ctx, current.ExternalID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get accounting deal: %w", err)
+		}
+		uc.markSynced(current, receipt, version)
+		if err := uc.syncRepo.Save(ctx, current); err != nil {
+			return nil, fmt.Errorf("failed to save accounting sync: %w", err)
+		}
+		return current, nil
+	default:
+		return nil, fmt.Errorf("unsupported resolve strategy: %s", strategy)
+	}
+}
+
+// sync レシートを送信して同期状態を保存する（forceの場合は会計サービス側の変更を確認せず上書き）
+func (uc *AccountingSyncUseCase) sync(ctx context.Context, providerName, receiptID string, force bool) (*entity.AccountingSync, error) {
+	if _, ok := uc.providers[providerName]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, providerName)
+	}
+
+	receipt, err := uc.receiptRepo.FindByID(ctx, receiptID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrReceiptNotFound, err)
+	}
+
+	current, err := uc.findSync(ctx, providerName, receiptID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		current = &entity.AccountingSync{ReceiptID: receiptID, Provider: providerName}
+	}
+	return uc.push(ctx, providerName, receipt, current, force)
+}
+
+// push 取得済みのレシートを送信して同期状態を保存する（forceの場合は会計サービス側の変更を確認せず上書き）
+func (uc *AccountingSyncUseCase) push(ctx context.Context, providerName string, receipt *entity.Receipt, current *entity.AccountingSync, force bool) (*entity.AccountingSync, error) {
+	provider, ok := uc.providers[providerName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, providerName)
+	}
+	receiptID := receipt.ID
+
+	deal := newAccountingDeal(receipt, sharedDomain.LocationFromContext(ctx))
+	pushErr := func() error {
+		if current.ExternalID == "" {
+			externalID, version, err := provider.CreateDeal(ctx, deal)
+			if err != nil {
+				return err
+			}
+			current.ExternalID = externalID
+			uc.markSynced(current, receipt, version)
+			return nil
+		}
+
+		if !force {
+			remote, err := provider.DealVersion(ctx, current.ExternalID)
+			if err != nil {
+				return err
+			}
+			if remote != current.RemoteVersion {
+				return ErrSyncConflict
+			}
+		}
+
+		version, err := provider.UpdateDeal(ctx, current.ExternalID, deal)
+		if err != nil {
+			return err
+		}
+		uc.markSynced(current, receipt, version)
+		return nil
+	}()
+
+	current.UpdatedAt = uc.now()
+	switch {
+	case errors.Is(pushErr, ErrSyncConflict):
+		current.Status = entity.SyncStatusConflict
+		current.LastError = pushErr.Error()
+	case pushErr != nil:
+		current.Status = entity.SyncStatusFailed
+		current.Attempts++
+		current.LastError = pushErr.Error()
+	}
+
+	if err := uc.syncRepo.Save(ctx, current); err != nil {
+		return nil, fmt.Errorf("failed to save accounting sync: %w", err)
+	}
+	if pushErr != nil {
+		return current, fmt.Errorf("failed to sync receipt %s to %s: %w", receiptID, providerName, pushErr)
+	}
+	return current, nil
+}
+
+// findSync レシートの指定した会計サービスの同期状態を取得（未送信の場合はnil）
+func (uc *AccountingSyncUseCase) findSync(ctx context.Context, providerName, receiptID string) (*entity.AccountingSync, error) {
+	syncs, err := uc.syncRepo.FindByReceiptID(ctx, receiptID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find accounting sync: %w", err)
+	}
+	for _, sync := range syncs {
+		if sync.Provider == providerName {
+			return sync, nil
+		}
+	}
+	return nil, nil
+}
+
+// markSynced 同期状態を送信済みにする
+func (uc *AccountingSyncUseCase) markSynced(sync *entity.AccountingSync, receipt *entity.Receipt, version string) {
+	now := uc.now()
+	sync.Status = entity.SyncStatusSynced
+	sync.RemoteVersion = version
+	sync.ReceiptUpdatedAt = receipt.UpdatedAt
+	sync.Attempts = 0
+	sync.LastError = ""
+	sync.SyncedAt = &now
+	sync.UpdatedAt = now
+}
+
+// newAccountingDeal レシートから取引を作成
+// 明細はカテゴリーごとにまとめ、支払額と明細の合計の差額（外税・値引きなど）は金額の最も大きいカテゴリーに含める
+func newAccountingDeal(receipt *entity.Receipt, loc *time.Location) *entity.AccountingDeal {
+	deal := &entity.AccountingDeal{
+		ReceiptID:     receipt.ID,
+		Date:          receipt.PurchaseDate.In(loc),
+		Partner:       receipt.StoreName,
+		PaymentMethod: receipt.PaymentMethod,
+		Amount:        receipt.TotalAmount,
+		Memo:          receipt.Memo,
+	}
+
+	index := make(map[string]int)
+	names := make(map[string][]string)
+	var itemsTotal int64
+	for _, item := range receipt.Items {
+		category := item.Category
+		if category == "" {
+			category = receipt.Category
+		}
+		if category == "" {
+			category = entity.DefaultCategory
+		}
+		if _, ok := index[category]; !ok {
+			index[category] = len(deal.Lines)
+			deal.Lines = append(deal.Lines, entity.AccountingDealLine{Category: category})
+		}
+		amount := item.Amount()
+		deal.Lines[index[category]].Amount += amount
+		names[category] = append(names[category], item.Name)
+		itemsTotal += amount
+	}
+	for i := range deal.Lines {
+		deal.Lines[i].Description = strings.Join(names[deal.Lines[i].Category], ", ")
+	}
+
+	if deal.Amount == 0 {
+		deal.Amount = itemsTotal
+	}
+	if diff := deal.Amount - itemsTotal; diff != 0 {
+		if len(deal.Lines) == 0 {
+			category := receipt.Category
+			if category == "" {
+				category = entity.DefaultCategory
+			}
+			deal.Lines = append(deal.Lines, entity.AccountingDealLine{Category: category})
+		}
+		deal.Lines[largestDealLine(deal.Lines)].Amount += diff
+	}
+	return deal
+}
+
+// largestDealLine 金額の最も大きい明細の位置
+func largestDealLine(lines []entity.AccountingDealLine) int {
+	largest := 0
+	for i, line := range lines {
+		if line.Amount > lines[largest].Amount {
+			largest = i
+		}
+	}
+	return largest
+}
@@ -0,0 +1,274 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+)
+
+// memoryAccountingSyncRepository 同期状態をメモリに保存する
+type memoryAccountingSyncRepository struct {
+	syncs      map[string]*entity.AccountingSync
+	pending    []string
+	batchLoads int
+}
+
+func newMemoryAccountingSyncRepository() *memoryAccountingSyncRepository {
+	return &memoryAccountingSyncRepository{syncs: make(map[string]*entity.AccountingSync)}
+}
+
+func (r *memoryAccountingSyncRepository) Save(ctx context.Context, sync *entity.AccountingSync) error {
+	saved := *sync
+	r.syncs[sync.Provider+"/"+sync.ReceiptID] = &saved
+	return nil
+}
+
+func (r *memoryAccountingSyncRepository) FindByReceiptID(ctx context.Context, receiptID string) ([]*entity.AccountingSync, error) {
+	syncs := []*entity.AccountingSync{}
+	for _, sync := range r.syncs {
+		if sync.ReceiptID == receiptID {
+			found := *sync
+			syncs = append(syncs, &found)
+		}
+	}
+	return syncs, nil
+}
+
+func (r *memoryAccountingSyncRepository) FindByReceiptIDs(ctx context.Context, receiptIDs []string) ([]*entity.AccountingSync, error) {
+	r.batchLoads++
+	syncs := []*entity.AccountingSync{}
+	for _, receiptID := range receiptIDs {
+		found, _ := r.FindByReceiptID(ctx, receiptID)
+		syncs = append(syncs, found...)
+	}
+	return syncs, nil
+}
+
+func (r *memoryAccountingSyncRepository) FindByStatus(ctx context.Context, provider, status string, limit, offset int) ([]*entity.AccountingSync, error) {
+	syncs := []*entity.AccountingSync{}
+	for _, sync := range r.syncs {
+		if sync.Provider == provider && sync.Status == status {
+			syncs = append(syncs, sync)
+		}
+	}
+	return syncs, nil
+}
+
+func (r *memoryAccountingSyncRepository) FindPendingReceiptIDs(ctx context.Context, provider string, maxAttempts, limit int) ([]string, error) {
+	return r.pending, nil
+}
+
+// fakeAccountingRepository 取引を記録する会計サービス
+type fakeAccountingRepository struct {
+	deals     map[string]*entity.AccountingDeal
+	versions  map[string]string
+	createErr error
+	created   int
+	updated   int
+}
+
+func newFakeAccountingRepository() *fakeAccountingRepository {
+	return &fakeAccountingRepository{deals: make(map[string]*entity.AccountingDeal), versions: make(map[string]string)}
+}
+
+func (f *fakeAccountingRepository) Provider() string { return entity.AccountingProviderFreee }
+
+func (f *fakeAccountingRepository) CreateDeal(ctx context.Context, deal *entity.AccountingDeal) (string, string, error) {
+	if f.createErr != nil {
+		return "", "", f.createErr
+	}
+	f.created++
+	id := fmt.Sprintf("deal-%d", f.created)
+	f.deals[id] = deal
+	f.versions[id] = "v1"
+	return id, "v1", nil
+}
+
+func (f *fakeAccountingRepository) UpdateDeal(ctx context.Context, externalID string, deal *entity.AccountingDeal) (string, error) {
+	f.updated++
+	f.deals[externalID] = deal
+	f.versions[externalID] = fmt.Sprintf("v%d", f.updated+1)
+	return f.versions[externalID], nil
+}
+
+func (f *fakeAccountingRepository) DealVersion(ctx context.Context, externalID string) (string, error) {
+	return f.versions[externalID], nil
+}
+
+func TestAccountingSyncUseCase(t *testing.T) {
+	now := time.Date(2025, time.June, 12, 10, 0, 0, 0, time.Local)
+	receipt := &entity.Receipt{
+		ID:           "receipt-1",
+		StoreName:    "スーパーA",
+		PurchaseDate: time.Date(2025, time.June, 5, 0, 0, 0, 0, time.Local),
+		TotalAmount:  1200,
+		UpdatedAt:    now.Add(-time.Hour),
+		Items: []entity.ReceiptItem{
+			{Name: "牛乳", Quantity: 2, Price: 200, Category: "食費"},
+			{Name: "パン", Quantity: 1, Price: 400, Category: "食費"},
+			{Name: "洗剤", Quantity: 1, Price: 300, Category: "日用品"},
+		},
+	}
+	mockReceipt := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			if id != receipt.ID {
+				return nil, fmt.Errorf("receipt not found: %s", id)
+			}
+			return receipt, nil
+		},
+	}
+	newUseCase := func() (*AccountingSyncUseCase, *memoryAccountingSyncRepository, *fakeAccountingRepository) {
+		syncRepo := newMemoryAccountingSyncRepository()
+		syncRepo.pending = []string{receipt.ID}
+		provider := newFakeAccountingRepository()
+		uc := NewAccountingSyncUseCase(mockReceipt, syncRepo, AccountingSyncRules{MaxAttempts: 3, BatchSize: 10})
+		uc.AddProvider(provider)
+		uc.now = func() time.Time { return now }
+		return uc, syncRepo, provider
+	}
+
+	t.Run("正常系: 新規登録と更新", func(t *testing.T) {
+		uc, _, provider := newUseCase()
+
+		report, err := uc.SyncPending(context.Background())
+		if err != nil || report.Synced != 1 {
+			t.Fatalf("SyncPending() = %+v, %v", report, err)
+		}
+
+		deal := provider.deals["deal-1"]
+		if deal == nil || deal.Amount != 1200 || len(deal.Lines) != 2 {
+			t.Fatalf("deal = %+v", deal)
+		}
+		// 外税100円は金額の最も大きい食費に含める
+		if deal.Lines[0].Category != "食費" || deal.Lines[0].Amount != 900 || deal.Lines[0].Description != "牛乳, パン" || deal.Lines[1].Amount != 300 {
+			t.Errorf("lines = %+v", deal.Lines)
+		}
+
+		sync, err := uc.SyncReceipt(context.Background(), entity.AccountingProviderFreee, receipt.ID)
+		if err != nil {
+			t.Fatalf("SyncReceipt() error = %v", err)
+		}
+		if provider.created != 1 || provider.updated != 1 || sync.Status != entity.SyncStatusSynced || sync.RemoteVersion != "v2" {
+			t.Errorf("created = %d, updated = %d, sync = %+v", provider.created, provider.updated, sync)
+		}
+		if !sync.ReceiptUpdatedAt.Equal(receipt.UpdatedAt) || sync.SyncedAt == nil || !sync.SyncedAt.Equal(now) {
+			t.Errorf("sync = %+v", sync)
+		}
+	})
+
+	t.Run("正常系: 会計サービス側の変更は競合として解決を待つ", func(t *testing.T) {
+		uc, syncRepo, provider := newUseCase()
+		if _, err := uc.SyncPending(context.Background()); err != nil {
+			t.Fatalf("SyncPending() error = %v", err)
+		}
+
+		provider.versions["deal-1"] = "edited"
+		report, err := uc.SyncPending(context.Background())
+		if err != nil || report.Conflicts != 1 {
+			t.Fatalf("SyncPending() = %+v, %v", report, err)
+		}
+		if provider.updated != 0 {
+			t.Error("conflicted deal was overwritten")
+		}
+		conflicts, _ := uc.ListByStatus(context.Background(), entity.AccountingProviderFreee, entity.SyncStatusConflict, 20, 0)
+		if len(conflicts) != 1 {
+			t.Fatalf("conflicts = %d, want 1", len(conflicts))
+		}
+
+		sync, err := uc.Resolve(context.Background(), entity.AccountingProviderFreee, receipt.ID, SyncResolveRemote)
+		if err != nil || sync.Status != entity.SyncStatusSynced || sync.RemoteVersion != "edited" || provider.updated != 0 {
+			t.Errorf("Resolve(remote) = %+v, %v", sync, err)
+		}
+
+		// 再び競合させてレシートの内容で上書き
+		provider.versions["deal-1"] = "edited-again"
+		_, _ = uc.SyncReceipt(context.Background(), entity.AccountingProviderFreee, receipt.ID)
+		sync, err = uc.Resolve(context.Background(), entity.AccountingProviderFreee, receipt.ID, SyncResolveLocal)
+		if err != nil || sync.Status != entity.SyncStatusSynced || provider.updated != 1 {
+			t.Errorf("Resolve(local) = %+v, %v", sync, err)
+		}
+		if _, err := uc.Resolve(context.Background(), entity.AccountingProviderFreee, receipt.ID, SyncResolveLocal); !errors.Is(err, ErrSyncNotConflicted) {
+			t.Errorf("Resolve() on synced error = %v, want ErrSyncNotConflicted", err)
+		}
+		if len(syncRepo.syncs) != 1 {
+			t.Errorf("syncs = %d, want 1", len(syncRepo.syncs))
+		}
+	})
+
+	t.Run("異常系: 失敗した回数を記録", func(t *testing.T) {
+		uc, syncRepo, provider := newUseCase()
+		provider.createErr = errors.New("api error")
+
+		for i := 0; i < 2; i++ {
+			report, err := uc.SyncPending(context.Background())
+			if err != nil || report.Failed != 1 {
+				t.Fatalf("SyncPending() = %+v, %v", report, err)
+			}
+		}
+		sync := syncRepo.syncs[entity.AccountingProviderFreee+"/"+receipt.ID]
+		if sync.Status != entity.SyncStatusFailed || sync.Attempts != 2 || sync.LastError != "api error" {
+			t.Errorf("sync = %+v", sync)
+		}
+
+		provider.createErr = nil
+		sync, err := uc.SyncReceipt(context.Background(), entity.AccountingProviderFreee, receipt.ID)
+		if err != nil || sync.Attempts != 0 || sync.LastError != "" {
+			t.Errorf("SyncReceipt() = %+v, %v", sync, err)
+		}
+	})
+
+	t.Run("正常系: 送信するレシートと同期状態はまとめて取得する", func(t *testing.T) {
+		second := *receipt
+		second.ID = "receipt-2"
+		findByIDs := 0
+		batchReceipt := &MockReceiptRepository{
+			FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+				t.Errorf("FindByID(%s) called for each pending receipt", id)
+				return nil, errors.New("unexpected")
+			},
+			FindByIDsFunc: func(ctx context.Context, ids []string) ([]*entity.Receipt, error) {
+				findByIDs++
+				return []*entity.Receipt{receipt, &second}, nil
+			},
+		}
+		syncRepo := newMemoryAccountingSyncRepository()
+		syncRepo.pending = []string{receipt.ID, "deleted", second.ID}
+		uc := NewAccountingSyncUseCase(batchReceipt, syncRepo, AccountingSyncRules{MaxAttempts: 3, BatchSize: 10})
+		uc.AddProvider(newFakeAccountingRepository())
+		uc.now = func() time.Time { return now }
+
+		report, err := uc.SyncPending(context.Background())
+		if err != nil {
+			t.Fatalf("SyncPending() error = %v", err)
+		}
+		// 取得時に削除されていたレシートは失敗として数える
+		if report.Synced != 2 || report.Failed != 1 {
+			t.Errorf("SyncPending() = %+v, want 2 synced and 1 failed", report)
+		}
+		if findByIDs != 1 || syncRepo.batchLoads != 1 {
+			t.Errorf("FindByIDs() = %d calls, FindByReceiptIDs() = %d calls, want 1 each", findByIDs, syncRepo.batchLoads)
+		}
+	})
+
+	tests := []struct {
+		name     string
+		provider string
+		receipt  string
+		wantErr  error
+	}{
+		{name: "異常系: 有効でない会計サービス", provider: entity.AccountingProviderMoneyForward, receipt: receipt.ID, wantErr: ErrUnknownProvider},
+		{name: "異常系: レシートが存在しない", provider: entity.AccountingProviderFreee, receipt: "unknown", wantErr: ErrReceiptNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, _, _ := newUseCase()
+			if _, err := uc.SyncReceipt(context.Background(), tt.provider, tt.receipt); !errors.Is(err, tt.wantErr) {
+				t.Errorf("SyncReceipt() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+const (
+	// calendarProductID iCalendarのPRODID
+	calendarProductID = "-//vision-api-app//Household Deadlines//JA"
+	// calendarLineLimit iCalendarの1行の最大オクテット数（超える行は折り返す）
+	calendarLineLimit = 75
+)
+
+// ErrInvalidFeedToken カレンダーのフィードのトークンが不正
+var ErrInvalidFeedToken = errors.New("invalid calendar feed token")
+
+// CalendarUseCase 返品・保証期限を購読用のiCalendarのフィードとして提供するユースケース
+// カレンダーのアプリは認証ヘッダーを送れないため、フィードのURLには署名付きのトークンを含める
+type CalendarUseCase struct {
+	warrantyUseCase *WarrantyUseCase
+	secret          []byte
+	days            int
+	currency        sharedDomain.Currency
+	clock           sharedDomain.Clock
+}
+
+// NewCalendarUseCase 新しいCalendarUseCaseを作成（daysは今日から何日先までの期限をフィードに含めるか）
+func NewCalendarUseCase(warrantyUseCase *WarrantyUseCase, secret []byte, days int) *CalendarUseCase {
+	return &CalendarUseCase{
+		warrantyUseCase: warrantyUseCase,
+		secret:          secret,
+		days:            days,
+		currency:        sharedDomain.DefaultCurrency,
+		clock:           sharedDomain.SystemClock{},
+	}
+}
+
+// SetCurrency 金額の通貨を設定する
+// 未設定の場合は既定の通貨を使う
+func (uc *CalendarUseCase) SetCurrency(currency sharedDomain.Currency) {
+	uc.currency = currency
+}
+
+// SetClock フィードの作成日時に使う時計を設定する
+// 未設定の場合はシステムの時計を使う
+func (uc *CalendarUseCase) SetClock(clock sharedDomain.Clock) {
+	uc.clock = clock
+}
+
+// IssueFeedToken フィードを購読するためのトークンを発行
+// トークンに有効期限はなく、無効にするには署名鍵（calendar.secret）を変更する
+func (uc *CalendarUseCase) IssueFeedToken() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate feed token: %w", err)
+	}
+	id := hex.EncodeToString(buf)
+	return id + "." + uc.sign(id), nil
+}
+
+// Feed トークンを検証し、今日から設定の日数以内の返品・保証期限を終日の予定としたiCalendarを返す
+func (uc *CalendarUseCase) Feed(ctx context.Context, token string) ([]byte, error) {
+	id, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(uc.sign(id))) {
+		return nil, ErrInvalidFeedToken
+	}
+
+	expiring, err := uc.warrantyUseCase.ListExpiring(ctx, uc.days)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	stamp := uc.clock.Now().UTC().Format("20060102T150405Z")
+	writeCalendarLine(&buf, "BEGIN:VCALENDAR")
+	writeCalendarLine(&buf, "VERSION:2.0")
+	writeCalendarLine(&buf, "PRODID:"+calendarProductID)
+	writeCalendarLine(&buf, "CALSCALE:GREGORIAN")
+	writeCalendarLine(&buf, "METHOD:PUBLISH")
+	writeCalendarLine(&buf, "X-WR-CALNAME:"+escapeCalendarText("返品・保証期限"))
+	for _, e := range expiring {
+		label := "保証期限"
+		if e.Kind == entity.DeadlineReturn {
+			label = "返品期限"
+		}
+		summary := label + ": " + e.Item.Item.Name
+		if e.Item.StoreName != "" {
+			summary += "（" + e.Item.StoreName + "）"
+		}
+		description := fmt.Sprintf("単価: %s\n購入日: %s\nレシート: %s",
+			uc.currency.Display(e.Item.Item.Price), e.Item.PurchaseDate.Format("2006-01-02"), e.Item.Item.ReceiptID)
+
+		writeCalendarLine(&buf, "BEGIN:VEVENT")
+		// 同じ明細・期限の予定は再取得しても同じUIDにし、カレンダーのアプリで重複させない
+		writeCalendarLine(&buf, "UID:"+e.Item.Item.ID+"-"+e.Kind+"@vision-api-app")
+		writeCalendarLine(&buf, "DTSTAMP:"+stamp)
+		writeCalendarLine(&buf, "DTSTART;VALUE=DATE:"+e.Deadline.Format("20060102"))
+		writeCalendarLine(&buf, "DTEND;VALUE=DATE:"+e.Deadline.AddDate(0, 0, 1).Format("20060102"))
+		writeCalendarLine(&buf, "SUMMARY:"+escapeCalendarText(summary))
+		writeCalendarLine(&buf, "DESCRIPTION:"+escapeCalendarText(description))
+		writeCalendarLine(&buf, "TRANSP:TRANSPARENT")
+		writeCalendarLine(&buf, "END:VEVENT")
+	}
+	writeCalendarLine(&buf, "END:VCALENDAR")
+	return buf.Bytes(), nil
+}
+
+// sign トークンのIDに対するHMAC-SHA256署名を生成
+func (uc *CalendarUseCase) sign(id string) string {
+	mac := hmac.New(sha256.New, uc.secret)
+	_, _ = fmt.Fprintf(mac, "calendar\n%s", id)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// escapeCalendarText iCalendarのテキストの値をエスケープする（\ ; , と改行）
+func escapeCalendarText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// writeCalendarLine 1行をCRLFで書き込み、75オクテットを超える行は文字の途中で切らずに折り返す
+func writeCalendarLine(buf *bytes.Buffer, line string) {
+	limit := calendarLineLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		// 折り返した行は先頭の空白の分だけ短くする
+		limit = calendarLineLimit - 1
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+func TestCalendarUseCase_Feed(t *testing.T) {
+	now := time.Date(2025, time.June, 10, 15, 0, 0, 0, time.Local)
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	}
+	mockReceipt := &MockReceiptRepository{
+		FindWarrantyItemsFunc: func(ctx context.Context, minPrice int64) ([]*entity.PurchasedItem, error) {
+			return []*entity.PurchasedItem{
+				// 返品期限: 2025-06-12、保証期限: 2026-06-05
+				{Item: entity.ReceiptItem{ID: "pc", ReceiptID: "r1", Name: "ノートPC, 14インチ", Price: 150000, Quantity: 1}, StoreName: "家電店", PurchaseDate: date(2025, time.June, 5)},
+				// 保証期限切れ: 2025-06-01
+				{Item: entity.ReceiptItem{ID: "tv", ReceiptID: "r2", Name: "テレビ", Price: 80000, Quantity: 1}, StoreName: "家電店", PurchaseDate: date(2024, time.June, 1)},
+			}, nil
+		},
+	}
+	warrantyUseCase := NewWarrantyUseCase(mockReceipt, WarrantyRules{MinAmount: 10000, DefaultMonths: 12, ReturnDays: 7})
+	warrantyUseCase.now = func() time.Time { return now }
+	uc := NewCalendarUseCase(warrantyUseCase, []byte("secret"), 365)
+	uc.SetClock(sharedDomain.FixedClock(now))
+
+	token, err := uc.IssueFeedToken()
+	if err != nil {
+		t.Fatalf("IssueFeedToken() error = %v", err)
+	}
+	data, err := uc.Feed(context.Background(), token)
+	if err != nil {
+		t.Fatalf("Feed() error = %v", err)
+	}
+	feed := string(data)
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:pc-return@vision-api-app\r\n",
+		"DTSTART;VALUE=DATE:20250612\r\nDTEND;VALUE=DATE:20250613\r\n",
+		"SUMMARY:返品期限: ノートPC\\, 14インチ（家電店）\r\n",
+		"UID:pc-warranty@vision-api-app\r\n",
+		"DTSTART;VALUE=DATE:20260605\r\n",
+		"DTSTAMP:" + now.UTC().Format("20060102T150405Z") + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(feed, want) {
+			t.Errorf("feed does not contain %q:\n%s", want, feed)
+		}
+	}
+	// 期限切れの明細は含めない
+	if strings.Contains(feed, "UID:tv-") {
+		t.Errorf("feed contains expired item:\n%s", feed)
+	}
+	// 75オクテットを超える行は折り返す
+	for _, line := range strings.Split(feed, "\r\n") {
+		if len(line) > calendarLineLimit {
+			t.Errorf("line is longer than %d octets: %q", calendarLineLimit, line)
+		}
+	}
+
+	for _, invalid := range []string{"", token + "x", "other." + strings.SplitN(token, ".", 2)[1]} {
+		if _, err := uc.Feed(context.Background(), invalid); !errors.Is(err, ErrInvalidFeedToken) {
+			t.Errorf("Feed(%q) error = %v, want %v", invalid, err, ErrInvalidFeedToken)
+		}
+	}
+	// 署名鍵が異なるトークンは使えない
+	other := NewCalendarUseCase(warrantyUseCase, []byte("rotated"), 365)
+	if _, err := other.Feed(context.Background(), token); !errors.Is(err, ErrInvalidFeedToken) {
+		t.Errorf("Feed() with rotated secret error = %v, want %v", err, ErrInvalidFeedToken)
+	}
+}
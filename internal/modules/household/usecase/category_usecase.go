@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// ErrInvalidCategory 登録・修正するカテゴリが不正（名前が空、表示色が#RRGGBBでないなど）
+var ErrInvalidCategory = errors.New("invalid category")
+
+// CategoryInput カテゴリの登録・置き換えの内容
+type CategoryInput struct {
+	Name        string
+	Description string
+	Color       string // #RRGGBB（空の場合は表示色なし）
+}
+
+// CategoryUseCase カテゴリの管理のユースケース
+// カテゴリは名前で参照するため、名前を変えても登録済みの明細・家計簿エントリのカテゴリは変わらない
+type CategoryUseCase struct {
+	categoryRepo repository.CategoryRepository
+	idGenerator  sharedDomain.IDGenerator
+	clock        sharedDomain.Clock
+}
+
+// NewCategoryUseCase 新しいCategoryUseCaseを作成
+func NewCategoryUseCase(categoryRepo repository.CategoryRepository) *CategoryUseCase {
+	return &CategoryUseCase{
+		categoryRepo: categoryRepo,
+		clock:        sharedDomain.SystemClock{},
+	}
+}
+
+// SetIDGenerator 登録するカテゴリのIDの生成器を設定する
+// 未設定の場合はUUIDを使う
+func (uc *CategoryUseCase) SetIDGenerator(generator sharedDomain.IDGenerator) {
+	uc.idGenerator = generator
+}
+
+// SetClock 登録日時に使う時計を設定する
+// 未設定の場合はシステムの時計を使う
+func (uc *CategoryUseCase) SetClock(clock sharedDomain.Clock) {
+	uc.clock = clock
+}
+
+// ListCategories カテゴリ一覧を名前の順に取得
+func (uc *CategoryUseCase) ListCategories(ctx context.Context) ([]*entity.Category, error) {
+	return uc.categoryRepo.FindAll(ctx)
+}
+
+// CreateCategory カテゴリを登録
+// 不正な場合はErrInvalidCategory、同じ名前のカテゴリが登録済みの場合はentity.ErrCategoryExistsを返す
+func (uc *CategoryUseCase) CreateCategory(ctx context.Context, input CategoryInput) (*entity.Category, error) {
+	category := entity.NewCategory(uc.newID(), strings.TrimSpace(input.Name), strings.TrimSpace(input.Description), strings.TrimSpace(input.Color), uc.clock.Now())
+	if err := category.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCategory, err)
+	}
+
+	if err := uc.categoryRepo.Create(ctx, category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// ReplaceCategory カテゴリの名前・説明・表示色を置き換える
+// 登録されていない場合はentity.ErrCategoryNotFound、不正な場合はErrInvalidCategory、
+// 同じ名前の別のカテゴリが登録済みの場合はentity.ErrCategoryExistsを返す
+func (uc *CategoryUseCase) ReplaceCategory(ctx context.Context, id string, input CategoryInput) (*entity.Category, error) {
+	category, err := uc.categoryRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	category.Name = strings.TrimSpace(input.Name)
+	category.Description = strings.TrimSpace(input.Description)
+	category.Color = strings.TrimSpace(input.Color)
+	if err := category.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCategory, err)
+	}
+
+	if err := uc.categoryRepo.Update(ctx, category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// DeleteCategory カテゴリを削除（登録されていない場合はentity.ErrCategoryNotFound）
+// 登録済みの明細・家計簿エントリのカテゴリは変えない
+func (uc *CategoryUseCase) DeleteCategory(ctx context.Context, id string) error {
+	return uc.categoryRepo.Delete(ctx, id)
+}
+
+// newID 登録するカテゴリのIDを生成
+func (uc *CategoryUseCase) newID() string {
+	if uc.idGenerator != nil {
+		return uc.idGenerator.NewID(nil)
+	}
+	return uuid.NewString()
+}
@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+)
+
+// fakeCategoryRepository 名前の重複を一意制約と同じく拒否するテスト用のカテゴリリポジトリ
+type fakeCategoryRepository struct {
+	categories map[string]*entity.Category
+	err        error // 検索で返すエラー（データベースの障害など）
+}
+
+func (f *fakeCategoryRepository) conflicts(category *entity.Category) bool {
+	for _, c := range f.categories {
+		if c.ID != category.ID && c.Name == category.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeCategoryRepository) Create(ctx context.Context, category *entity.Category) error {
+	if f.conflicts(category) {
+		return fmt.Errorf("%w: %s", entity.ErrCategoryExists, category.Name)
+	}
+	copied := *category
+	f.categories[category.ID] = &copied
+	return nil
+}
+
+func (f *fakeCategoryRepository) FindByID(ctx context.Context, id string) (*entity.Category, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	category, ok := f.categories[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", entity.ErrCategoryNotFound, id)
+	}
+	copied := *category
+	return &copied, nil
+}
+
+func (f *fakeCategoryRepository) Update(ctx context.Context, category *entity.Category) error {
+	if f.conflicts(category) {
+		return fmt.Errorf("%w: %s", entity.ErrCategoryExists, category.Name)
+	}
+	copied := *category
+	f.categories[category.ID] = &copied
+	return nil
+}
+
+func (f *fakeCategoryRepository) Delete(ctx context.Context, id string) error {
+	if _, ok := f.categories[id]; !ok {
+		return fmt.Errorf("%w: %s", entity.ErrCategoryNotFound, id)
+	}
+	delete(f.categories, id)
+	return nil
+}
+
+func (f *fakeCategoryRepository) FindAll(ctx context.Context) ([]*entity.Category, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	categories := make([]*entity.Category, 0, len(f.categories))
+	for _, category := range f.categories {
+		categories = append(categories, category)
+	}
+	return categories, nil
+}
+
+func (f *fakeCategoryRepository) FindByName(ctx context.Context, name string) (*entity.Category, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestCategoryUseCase(t *testing.T) {
+	repo := &fakeCategoryRepository{categories: map[string]*entity.Category{}}
+	uc := NewCategoryUseCase(repo)
+	ctx := context.Background()
+
+	food, err := uc.CreateCategory(ctx, CategoryInput{Name: " 食費 ", Description: "食料品", Color: "#FF6B6B"})
+	if err != nil {
+		t.Fatalf("CreateCategory() error = %v", err)
+	}
+	if food.ID == "" || food.Name != "食費" || food.Color != "#FF6B6B" || food.CreatedAt.IsZero() {
+		t.Errorf("CreateCategory() = %+v", food)
+	}
+	daily, err := uc.CreateCategory(ctx, CategoryInput{Name: "日用品"})
+	if err != nil {
+		t.Fatalf("CreateCategory() error = %v", err)
+	}
+
+	if _, err := uc.CreateCategory(ctx, CategoryInput{Name: "食費"}); !errors.Is(err, entity.ErrCategoryExists) {
+		t.Errorf("CreateCategory() error = %v, want %v", err, entity.ErrCategoryExists)
+	}
+	if _, err := uc.CreateCategory(ctx, CategoryInput{Name: "交通費", Color: "blue"}); !errors.Is(err, ErrInvalidCategory) {
+		t.Errorf("CreateCategory() error = %v, want %v", err, ErrInvalidCategory)
+	}
+
+	// 置き換えでは省略した説明・表示色を空にする
+	replaced, err := uc.ReplaceCategory(ctx, daily.ID, CategoryInput{Name: "生活用品", Color: "#4ecdc4"})
+	if err != nil {
+		t.Fatalf("ReplaceCategory() error = %v", err)
+	}
+	if replaced.Name != "生活用品" || replaced.Color != "#4ecdc4" || !replaced.CreatedAt.Equal(daily.CreatedAt) {
+		t.Errorf("ReplaceCategory() = %+v", replaced)
+	}
+	if _, err := uc.ReplaceCategory(ctx, daily.ID, CategoryInput{Name: "食費"}); !errors.Is(err, entity.ErrCategoryExists) {
+		t.Errorf("ReplaceCategory() error = %v, want %v", err, entity.ErrCategoryExists)
+	}
+	if _, err := uc.ReplaceCategory(ctx, daily.ID, CategoryInput{Name: " "}); !errors.Is(err, ErrInvalidCategory) {
+		t.Errorf("ReplaceCategory() error = %v, want %v", err, ErrInvalidCategory)
+	}
+	if _, err := uc.ReplaceCategory(ctx, "missing", CategoryInput{Name: "雑費"}); !errors.Is(err, entity.ErrCategoryNotFound) {
+		t.Errorf("ReplaceCategory() error = %v, want %v", err, entity.ErrCategoryNotFound)
+	}
+
+	if err := uc.DeleteCategory(ctx, food.ID); err != nil {
+		t.Fatalf("DeleteCategory() error = %v", err)
+	}
+	if err := uc.DeleteCategory(ctx, food.ID); !errors.Is(err, entity.ErrCategoryNotFound) {
+		t.Errorf("DeleteCategory() error = %v, want %v", err, entity.ErrCategoryNotFound)
+	}
+	categories, err := uc.ListCategories(ctx)
+	if err != nil {
+		t.Fatalf("ListCategories() error = %v", err)
+	}
+	if len(categories) != 1 || categories[0].Name != "生活用品" {
+		t.Errorf("ListCategories() = %+v", categories)
+	}
+
+	// データベースの障害は未登録として扱わない
+	repo.err = errors.New("connection refused")
+	if _, err := uc.ReplaceCategory(ctx, daily.ID, CategoryInput{Name: "雑費"}); err == nil || errors.Is(err, entity.ErrCategoryNotFound) {
+		t.Errorf("ReplaceCategory() error = %v, want database error", err)
+	}
+}
+
+func TestReceiptUseCase_categoryNames(t *testing.T) {
+	ctx := context.Background()
+	uc := NewReceiptUseCase(&MockAIRepository{}, nil, nil)
+
+	// 取得元が未設定の場合は既定のカテゴリー
+	if names := uc.categoryNames(ctx); !slices.Equal(names, autoCategories) {
+		t.Errorf("categoryNames() = %v, want default categories", names)
+	}
+
+	// 登録されているカテゴリーに判定失敗時のカテゴリー（その他）を加える
+	repo := &fakeCategoryRepository{categories: map[string]*entity.Category{
+		"category-1": {ID: "category-1", Name: "食費"},
+		"category-2": {ID: "category-2", Name: "ペット"},
+	}}
+	uc.SetCategoryRepository(repo)
+	names := uc.categoryNames(ctx)
+	if len(names) != 3 || !slices.Contains(names, "ペット") || !slices.Contains(names, "食費") || names[2] != entity.DefaultCategory {
+		t.Errorf("categoryNames() = %v, want registered categories and %s", names, entity.DefaultCategory)
+	}
+
+	// 取得に失敗した場合は既定のカテゴリー
+	repo.err = errors.New("connection refused")
+	if names := uc.categoryNames(ctx); !slices.Equal(names, autoCategories) {
+		t.Errorf("categoryNames() = %v, want default categories on error", names)
+	}
+}
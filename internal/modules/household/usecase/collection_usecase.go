@@ -0,0 +1,294 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+var (
+	// ErrCollectionNotFound コレクションが存在しない
+	ErrCollectionNotFound = errors.New("collection not found")
+	// ErrNotInCollection レシートがコレクションに含まれていない
+	ErrNotInCollection = errors.New("receipt is not in the collection")
+)
+
+// CollectionPatch コレクションの部分更新（nilの項目は変更しない）
+type CollectionPatch struct {
+	Name        *string
+	Description *string
+}
+
+// CollectionReport コレクションに含めるレシートの集計結果
+type CollectionReport struct {
+	Collection   *entity.Collection
+	ReceiptCount int
+	Total        int64             // レシートの合計金額の合計（返品・返金のレシートは差し引く）
+	Tax          int64             // 消費税額の合計
+	Start        *time.Time        // 最も古いレシートの購入日時（レシートがない場合はnil）
+	End          *time.Time        // 最も新しいレシートの購入日時（レシートがない場合はnil）
+	Categories   []CategorySummary // 明細のカテゴリー別の合計（金額の大きい順）
+}
+
+// CollectionUseCase レシートをコレクション（フォルダー）にまとめ、コレクションごとに書き出し・集計するユースケース
+type CollectionUseCase struct {
+	collectionRepo repository.CollectionRepository
+	receiptRepo    repository.ReceiptRepository
+	idGenerator    sharedDomain.IDGenerator
+	now            func() time.Time
+}
+
+// NewCollectionUseCase 新しいCollectionUseCaseを作成
+func NewCollectionUseCase(collectionRepo repository.CollectionRepository, receiptRepo repository.ReceiptRepository) *CollectionUseCase {
+	return &CollectionUseCase{
+		collectionRepo: collectionRepo,
+		receiptRepo:    receiptRepo,
+		now:            time.Now,
+	}
+}
+
+// SetIDGenerator コレクションのIDの生成方法を設定（未設定の場合はUUID v4）
+func (uc *CollectionUseCase) SetIDGenerator(generator sharedDomain.IDGenerator) {
+	uc.idGenerator = generator
+}
+
+// CreateCollection 空のコレクションを作成
+func (uc *CollectionUseCase) CreateCollection(ctx context.Context, name, description string) (*entity.Collection, error) {
+	collection, err := entity.NewCollection(uc.newID(), name, description, uc.now())
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.collectionRepo.Create(ctx, collection); err != nil {
+		return nil, fmt.Errorf("failed to create collection: %w", err)
+	}
+	return collection, nil
+}
+
+// GetCollection コレクションを取得
+func (uc *CollectionUseCase) GetCollection(ctx context.Context, id string) (*entity.Collection, error) {
+	collection, err := uc.collectionRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCollectionNotFound, err)
+	}
+	return collection, nil
+}
+
+// ListCollections コレクションを名前の順に取得
+func (uc *CollectionUseCase) ListCollections(ctx context.Context, limit, offset int) ([]*entity.Collection, error) {
+	collections, err := uc.collectionRepo.FindAll(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find collections: %w", err)
+	}
+	return collections, nil
+}
+
+// ListReceiptCollections 指定したレシートを含むコレクションを名前の順に取得
+func (uc *CollectionUseCase) ListReceiptCollections(ctx context.Context, receiptID string) ([]*entity.Collection, error) {
+	if _, err := uc.receiptRepo.FindByID(ctx, receiptID); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrReceiptNotFound, err)
+	}
+	collections, err := uc.collectionRepo.FindByReceiptID(ctx, receiptID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find collections: %w", err)
+	}
+	return collections, nil
+}
+
+// UpdateCollection コレクションの名前・説明を変更
+func (uc *CollectionUseCase) UpdateCollection(ctx context.Context, id string, patch CollectionPatch) (*entity.Collection, error) {
+	collection, err := uc.GetCollection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	name, description := collection.Name, collection.Description
+	if patch.Name != nil {
+		name = *patch.Name
+	}
+	if patch.Description != nil {
+		description = *patch.Description
+	}
+	if err := collection.Rename(name, description, uc.now()); err != nil {
+		return nil, err
+	}
+	return collection, uc.save(ctx, collection)
+}
+
+// DeleteCollection コレクションを削除（含めていたレシートは削除しない）
+func (uc *CollectionUseCase) DeleteCollection(ctx context.Context, id string) error {
+	if _, err := uc.GetCollection(ctx, id); err != nil {
+		return err
+	}
+	if err := uc.collectionRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete collection: %w", err)
+	}
+	return nil
+}
+
+// AddReceipts レシートをコレクションに追加（含まれているレシートは追加しない）
+// 存在しないレシートを含む場合は ErrReceiptNotFound を返し、どのレシートも追加しない
+func (uc *CollectionUseCase) AddReceipts(ctx context.Context, id string, receiptIDs []string) (*entity.Collection, error) {
+	collection, err := uc.GetCollection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(receiptIDs) == 0 {
+		return nil, fmt.Errorf("%w: receipt_ids is required", entity.ErrInvalidCollection)
+	}
+
+	receipts, err := uc.receiptRepo.FindByIDs(ctx, receiptIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find receipts: %w", err)
+	}
+	for _, receiptID := range receiptIDs {
+		if !slices.ContainsFunc(receipts, func(receipt *entity.Receipt) bool { return receipt.ID == receiptID }) {
+			return nil, fmt.Errorf("%w: %s", ErrReceiptNotFound, receiptID)
+		}
+	}
+
+	added, err := collection.AddReceipts(receiptIDs, uc.now())
+	if err != nil {
+		return nil, err
+	}
+	if added == 0 {
+		return collection, nil
+	}
+	return collection, uc.save(ctx, collection)
+}
+
+// RemoveReceipt レシートをコレクションから外す（レシート自体は削除しない）
+func (uc *CollectionUseCase) RemoveReceipt(ctx context.Context, id, receiptID string) (*entity.Collection, error) {
+	collection, err := uc.GetCollection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !collection.RemoveReceipt(receiptID, uc.now()) {
+		return nil, fmt.Errorf("%w: %s", ErrNotInCollection, receiptID)
+	}
+	return collection, uc.save(ctx, collection)
+}
+
+// ListReceipts コレクションに含めるレシートを追加した順に取得（ゴミ箱にあるレシートは含めない）
+func (uc *CollectionUseCase) ListReceipts(ctx context.Context, id string) ([]*entity.Receipt, error) {
+	collection, err := uc.GetCollection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return uc.receipts(ctx, collection)
+}
+
+// Report コレクションに含めるレシートの枚数・合計金額・期間・カテゴリー別の合計を集計
+// 明細のないレシートはレシートのカテゴリーで合計全体を集計する
+func (uc *CollectionUseCase) Report(ctx context.Context, id string) (*CollectionReport, error) {
+	collection, err := uc.GetCollection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	receipts, err := uc.receipts(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CollectionReport{Collection: collection, ReceiptCount: len(receipts)}
+	categories := make(map[string]*CategorySummary)
+	add := func(category string, amount int64) {
+		if category == "" {
+			category = "その他"
+		}
+		summary, ok := categories[category]
+		if !ok {
+			summary = &CategorySummary{Category: category}
+			categories[category] = summary
+		}
+		summary.Count++
+		summary.Total += amount
+	}
+	for _, receipt := range receipts {
+		report.Total += receipt.TotalAmount
+		report.Tax += receipt.TaxAmount
+		if report.Start == nil || receipt.PurchaseDate.Before(*report.Start) {
+			report.Start = &receipt.PurchaseDate
+		}
+		if report.End == nil || receipt.PurchaseDate.After(*report.End) {
+			report.End = &receipt.PurchaseDate
+		}
+
+		if len(receipt.Items) == 0 {
+			add(receipt.Category, receipt.TotalAmount)
+			continue
+		}
+		for _, item := range receipt.Items {
+			category := item.Category
+			if category == "" {
+				category = receipt.Category
+			}
+			add(category, item.Amount())
+		}
+	}
+
+	report.Categories = make([]CategorySummary, 0, len(categories))
+	for _, summary := range categories {
+		report.Categories = append(report.Categories, *summary)
+	}
+	slices.SortFunc(report.Categories, func(a, b CategorySummary) int {
+		return cmp.Or(cmp.Compare(b.Total, a.Total), cmp.Compare(a.Category, b.Category))
+	})
+	return report, nil
+}
+
+// ForEachRow コレクションに含めるレシートの明細を購入日の古い順に1行ずつfnに渡す（ExportUseCase.ForEachRowと同じ形式）
+// コレクションが存在しない場合はfnを呼ぶ前にエラーを返す
+func (uc *CollectionUseCase) ForEachRow(ctx context.Context, id string, fn func(ExportRow) error) error {
+	receipts, err := uc.ListReceipts(ctx, id)
+	if err != nil {
+		return err
+	}
+	slices.SortStableFunc(receipts, func(a, b *entity.Receipt) int {
+		return a.PurchaseDate.Compare(b.PurchaseDate)
+	})
+
+	loc := sharedDomain.LocationFromContext(ctx)
+	for _, receipt := range receipts {
+		if err := forEachExportRow(receipt, loc, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// receipts コレクションに含めるレシートを明細とともに取得
+func (uc *CollectionUseCase) receipts(ctx context.Context, collection *entity.Collection) ([]*entity.Receipt, error) {
+	if len(collection.ReceiptIDs) == 0 {
+		return []*entity.Receipt{}, nil
+	}
+	receipts, err := uc.receiptRepo.FindByIDs(ctx, collection.ReceiptIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find collection receipts: %w", err)
+	}
+	return receipts, nil
+}
+
+// save コレクションを保存
+func (uc *CollectionUseCase) save(ctx context.Context, collection *entity.Collection) error {
+	if err := uc.collectionRepo.Update(ctx, collection); err != nil {
+		return fmt.Errorf("failed to update collection: %w", err)
+	}
+	return nil
+}
+
+// newID コレクションのIDを生成
+func (uc *CollectionUseCase) newID() string {
+	if uc.idGenerator != nil {
+		return uc.idGenerator.NewID(nil)
+	}
+	return uuid.NewString()
+}
@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// memoryCollectionRepository コレクションをメモリに保存する
+type memoryCollectionRepository struct {
+	collections map[string]*entity.Collection
+}
+
+func newMemoryCollectionRepository() *memoryCollectionRepository {
+	return &memoryCollectionRepository{collections: make(map[string]*entity.Collection)}
+}
+
+func (r *memoryCollectionRepository) Create(ctx context.Context, collection *entity.Collection) error {
+	return r.Update(ctx, collection)
+}
+
+func (r *memoryCollectionRepository) Update(ctx context.Context, collection *entity.Collection) error {
+	saved := *collection
+	saved.ReceiptIDs = slices.Clone(collection.ReceiptIDs)
+	r.collections[collection.ID] = &saved
+	return nil
+}
+
+func (r *memoryCollectionRepository) FindByID(ctx context.Context, id string) (*entity.Collection, error) {
+	collection, ok := r.collections[id]
+	if !ok {
+		return nil, errors.New("collection not found: " + id)
+	}
+	found := *collection
+	found.ReceiptIDs = slices.Clone(collection.ReceiptIDs)
+	return &found, nil
+}
+
+func (r *memoryCollectionRepository) Delete(ctx context.Context, id string) error {
+	delete(r.collections, id)
+	return nil
+}
+
+func (r *memoryCollectionRepository) FindAll(ctx context.Context, limit, offset int) ([]*entity.Collection, error) {
+	collections := []*entity.Collection{}
+	for id := range r.collections {
+		found, _ := r.FindByID(ctx, id)
+		collections = append(collections, found)
+	}
+	slices.SortFunc(collections, func(a, b *entity.Collection) int { return strings.Compare(a.Name, b.Name) })
+	return collections, nil
+}
+
+func (r *memoryCollectionRepository) FindByReceiptID(ctx context.Context, receiptID string) ([]*entity.Collection, error) {
+	all, _ := r.FindAll(ctx, 0, 0)
+	return slices.DeleteFunc(all, func(c *entity.Collection) bool { return !c.Contains(receiptID) }), nil
+}
+
+func TestCollectionUseCase(t *testing.T) {
+	ctx := sharedDomain.WithLocation(context.Background(), time.UTC)
+	receipts := map[string]*entity.Receipt{
+		"sink": {
+			ID: "sink", StoreName: "ホームセンター", PurchaseDate: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), TotalAmount: 55000, TaxAmount: 5000,
+			Items: []entity.ReceiptItem{{Name: "シンク", Quantity: 1, Price: 50000, Category: "住居"}},
+		},
+		"paint": {
+			ID: "paint", StoreName: "ホームセンター", PurchaseDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), TotalAmount: 3300, TaxAmount: 300,
+			Items:    []entity.ReceiptItem{{Name: "塗料", Quantity: 2, Price: 1000}, {Name: "刷毛", Quantity: 1, Price: 1000, Category: "日用品"}},
+			Category: "住居",
+		},
+		"lunch": {ID: "lunch", StoreName: "食堂", PurchaseDate: time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC), TotalAmount: 800, Category: "外食"},
+	}
+	receiptRepo := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			if receipt, ok := receipts[id]; ok {
+				return receipt, nil
+			}
+			return nil, errors.New("receipt not found")
+		},
+	}
+
+	collectionRepo := newMemoryCollectionRepository()
+	uc := NewCollectionUseCase(collectionRepo, receiptRepo)
+
+	collection, err := uc.CreateCollection(ctx, "キッチンのリフォーム", "")
+	if err != nil {
+		t.Fatalf("CreateCollection() error = %v", err)
+	}
+	if _, err := uc.CreateCollection(ctx, " ", ""); !errors.Is(err, entity.ErrInvalidCollection) {
+		t.Errorf("CreateCollection() with an empty name error = %v, want ErrInvalidCollection", err)
+	}
+
+	t.Run("正常系: レシートの追加・集計・書き出し", func(t *testing.T) {
+		if _, err := uc.AddReceipts(ctx, collection.ID, []string{"sink", "paint", "lunch"}); err != nil {
+			t.Fatalf("AddReceipts() error = %v", err)
+		}
+
+		report, err := uc.Report(ctx, collection.ID)
+		if err != nil {
+			t.Fatalf("Report() error = %v", err)
+		}
+		if report.ReceiptCount != 3 || report.Total != 59100 || report.Tax != 5300 {
+			t.Errorf("Report() = %d receipts, total %d, tax %d, want 3, 59100, 5300", report.ReceiptCount, report.Total, report.Tax)
+		}
+		if !report.Start.Equal(receipts["paint"].PurchaseDate) || !report.End.Equal(receipts["sink"].PurchaseDate) {
+			t.Errorf("Report() period = %v - %v", report.Start, report.End)
+		}
+		// 明細のカテゴリーが空の場合はレシートのカテゴリー、明細のないレシートは合計金額で集計する
+		want := []CategorySummary{{Category: "住居", Count: 2, Total: 52000}, {Category: "日用品", Count: 1, Total: 1000}, {Category: "外食", Count: 1, Total: 800}}
+		if !slices.Equal(report.Categories, want) {
+			t.Errorf("Report().Categories = %+v, want %+v", report.Categories, want)
+		}
+
+		var rows []string
+		err = uc.ForEachRow(ctx, collection.ID, func(row ExportRow) error {
+			rows = append(rows, row.ReceiptID+":"+row.ItemName)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("ForEachRow() error = %v", err)
+		}
+		if got := strings.Join(rows, ","); got != "paint:塗料,paint:刷毛,lunch:,sink:シンク" {
+			t.Errorf("ForEachRow() = %s, want purchase date order", got)
+		}
+	})
+
+	t.Run("異常系: 存在しないレシートはどれも追加しない", func(t *testing.T) {
+		other, _ := uc.CreateCollection(ctx, "2025年 確定申告", "")
+		if _, err := uc.AddReceipts(ctx, other.ID, []string{"sink", "missing"}); !errors.Is(err, ErrReceiptNotFound) {
+			t.Fatalf("AddReceipts() error = %v, want ErrReceiptNotFound", err)
+		}
+		if found, _ := uc.GetCollection(ctx, other.ID); len(found.ReceiptIDs) != 0 {
+			t.Errorf("ReceiptIDs = %v, want none", found.ReceiptIDs)
+		}
+	})
+
+	t.Run("正常系: レシートを外す・レシートを含むコレクション", func(t *testing.T) {
+		if _, err := uc.RemoveReceipt(ctx, collection.ID, "lunch"); err != nil {
+			t.Fatalf("RemoveReceipt() error = %v", err)
+		}
+		if _, err := uc.RemoveReceipt(ctx, collection.ID, "lunch"); !errors.Is(err, ErrNotInCollection) {
+			t.Errorf("RemoveReceipt() twice error = %v, want ErrNotInCollection", err)
+		}
+
+		byReceipt, err := uc.ListReceiptCollections(ctx, "sink")
+		if err != nil || len(byReceipt) != 1 || byReceipt[0].ID != collection.ID {
+			t.Errorf("ListReceiptCollections() = %+v, %v", byReceipt, err)
+		}
+		if _, err := uc.ListReceiptCollections(ctx, "missing"); !errors.Is(err, ErrReceiptNotFound) {
+			t.Errorf("ListReceiptCollections() error = %v, want ErrReceiptNotFound", err)
+		}
+	})
+
+	t.Run("正常系: 名前の変更と削除", func(t *testing.T) {
+		name := "キッチン"
+		updated, err := uc.UpdateCollection(ctx, collection.ID, CollectionPatch{Name: &name})
+		if err != nil || updated.Name != name {
+			t.Fatalf("UpdateCollection() = %+v, %v", updated, err)
+		}
+		if err := uc.DeleteCollection(ctx, collection.ID); err != nil {
+			t.Fatalf("DeleteCollection() error = %v", err)
+		}
+		if _, err := uc.GetCollection(ctx, collection.ID); !errors.Is(err, ErrCollectionNotFound) {
+			t.Errorf("GetCollection() after delete error = %v, want ErrCollectionNotFound", err)
+		}
+		if err := uc.ForEachRow(ctx, collection.ID, func(ExportRow) error { return nil }); !errors.Is(err, ErrCollectionNotFound) {
+			t.Errorf("ForEachRow() after delete error = %v, want ErrCollectionNotFound", err)
+		}
+	})
+}
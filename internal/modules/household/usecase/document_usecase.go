@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+)
+
+// ErrUnsupportedDocument 自動で振り分けられない種類のファイル
+var ErrUnsupportedDocument = errors.New("unsupported document")
+
+// 自動で振り分けるファイルの種類
+const (
+	DocumentKindReceipt   = "receipt"   // レシートの画像（レシートとして登録）
+	DocumentKindStatement = "statement" // 銀行・クレジットカードの利用明細のCSV（レシートと突き合わせ）
+)
+
+// documentImageTypes レシートとして扱う画像の形式
+var documentImageTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+// DocumentOptions 振り分けた先の処理のオプション
+type DocumentOptions struct {
+	Receipt        ProcessOptions // レシートの登録のオプション
+	PaymentMethods []string       // 利用明細に請求が載るはずのレシートの支払い方法
+}
+
+// DocumentResult 振り分けた先の処理結果（Kindに対応するフィールドのみ設定する）
+type DocumentResult struct {
+	Kind           string
+	Receipt        *entity.Receipt
+	Reconciliation *ReconciliationReport
+}
+
+// DocumentUseCase 種類を問わずアップロードされたファイルを判定し、対応する処理に振り分けるユースケース
+type DocumentUseCase struct {
+	receiptUseCase        *ReceiptUseCase
+	reconciliationUseCase *ReconciliationUseCase
+}
+
+// NewDocumentUseCase 新しいDocumentUseCaseを作成
+func NewDocumentUseCase(receiptUseCase *ReceiptUseCase, reconciliationUseCase *ReconciliationUseCase) *DocumentUseCase {
+	return &DocumentUseCase{
+		receiptUseCase:        receiptUseCase,
+		reconciliationUseCase: reconciliationUseCase,
+	}
+}
+
+// ClassifyDocument ファイルの内容から種類を判定
+// 画像はレシート、日付と金額の列を含むCSVは利用明細とし、それ以外は ErrUnsupportedDocument を返す
+func (uc *DocumentUseCase) ClassifyDocument(data []byte) (string, error) {
+	mediaType, _, _ := strings.Cut(http.DetectContentType(data), ";")
+	for _, imageType := range documentImageTypes {
+		if mediaType == imageType {
+			return DocumentKindReceipt, nil
+		}
+	}
+
+	// Shift_JISのCSVは application/octet-stream と判定されるため、テキストに限らず利用明細として読めるかを確認する
+	if _, err := ParseStatementCSV(bytes.NewReader(data), uc.reconciliationUseCase.Currency()); err == nil {
+		return DocumentKindStatement, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedDocument, mediaType)
+}
+
+// Process ファイルの種類を判定し、レシートは登録、利用明細はレシートと突き合わせる
+// 種類を判定できた後のエラーでも、結果のKindには判定した種類を設定して返す
+// データベース障害中にレシートを一時保管した場合は、登録したレシートとあわせて ErrSavePending を返す
+func (uc *DocumentUseCase) Process(ctx context.Context, data []byte, opts DocumentOptions) (*DocumentResult, error) {
+	kind, err := uc.ClassifyDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case DocumentKindReceipt:
+		receipt, err := uc.receiptUseCase.ProcessReceiptImageWithOptions(ctx, data, opts.Receipt)
+		return &DocumentResult{Kind: kind, Receipt: receipt}, err
+	default:
+		lines, err := ParseStatementCSV(bytes.NewReader(data), uc.reconciliationUseCase.Currency())
+		if err != nil {
+			return &DocumentResult{Kind: kind}, err
+		}
+		report, err := uc.reconciliationUseCase.Reconcile(ctx, lines, opts.PaymentMethods)
+		return &DocumentResult{Kind: kind, Reconciliation: report}, err
+	}
+}
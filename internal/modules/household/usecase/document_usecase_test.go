@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// pngHeader PNGとして判定されるファイルの先頭
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestDocumentUseCase_ClassifyDocument(t *testing.T) {
+	uc := NewDocumentUseCase(nil, NewReconciliationUseCase(&MockReceiptRepository{}, ReconciliationRules{}))
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    string
+		wantErr bool
+	}{
+		{name: "正常系: PNGはレシート", data: pngHeader, want: DocumentKindReceipt},
+		{name: "正常系: JPEGはレシート", data: []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00"), want: DocumentKindReceipt},
+		{name: "正常系: 日付と金額の列を含むCSVは利用明細", data: []byte("利用日,利用店名,利用金額\n2025/06/03,ｲｵﾝ,3280\n"), want: DocumentKindStatement},
+		{name: "正常系: Shift_JISのCSV", data: []byte("\x97\x98\x97p\x93\xfa,\x8b\xe0\x8az\n2025/06/03,3280\n"), want: DocumentKindStatement},
+		{name: "異常系: 列のわからないCSV", data: []byte("name,value\na,1\n"), wantErr: true},
+		{name: "異常系: PDF", data: []byte("%PDF-1.7\n"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := uc.ClassifyDocument(tt.data)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnsupportedDocument) {
+					t.Errorf("ClassifyDocument() error = %v, want ErrUnsupportedDocument", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ClassifyDocument() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ClassifyDocument() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocumentUseCase_Process(t *testing.T) {
+	ctx := sharedDomain.WithLocation(context.Background(), time.UTC)
+	var created *entity.Receipt
+	receiptRepo := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			return nil, errors.New("not found")
+		},
+		CreateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			created = receipt
+			return nil
+		},
+		FindByDateRangeFunc: func(ctx context.Context, start, end time.Time) ([]*entity.Receipt, error) {
+			return []*entity.Receipt{
+				{ID: "aeon", StoreName: "イオン", PurchaseDate: time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC), TotalAmount: 3280, PaymentMethod: "クレジットカード"},
+			}, nil
+		},
+	}
+	uc := NewDocumentUseCase(
+		NewReceiptUseCase(&MockAIRepository{}, receiptRepo, &MockCacheRepository{}),
+		NewReconciliationUseCase(receiptRepo, ReconciliationRules{DateWindowDays: 5}),
+	)
+
+	t.Run("正常系: 画像はレシートとして登録", func(t *testing.T) {
+		result, err := uc.Process(ctx, pngHeader, DocumentOptions{Receipt: ProcessOptions{Tags: []string{"自動"}}})
+		if err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+		if result.Kind != DocumentKindReceipt || result.Receipt == nil || result.Reconciliation != nil {
+			t.Fatalf("Process() = %+v, want receipt only", result)
+		}
+		if created == nil || created.ID != result.Receipt.ID || len(created.Tags) != 1 {
+			t.Errorf("created = %+v, want the returned receipt with tags", created)
+		}
+	})
+
+	t.Run("正常系: 利用明細はレシートと突き合わせ", func(t *testing.T) {
+		result, err := uc.Process(ctx, []byte("利用日,利用店名,利用金額\n2025/06/03,ｲｵﾝ,3280\n"), DocumentOptions{})
+		if err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+		if result.Kind != DocumentKindStatement || result.Receipt != nil || result.Reconciliation == nil {
+			t.Fatalf("Process() = %+v, want reconciliation only", result)
+		}
+		if len(result.Reconciliation.Matches) != 1 || result.Reconciliation.Matches[0].Receipt.ID != "aeon" {
+			t.Errorf("Matches = %+v, want aeon", result.Reconciliation.Matches)
+		}
+	})
+
+	t.Run("異常系: 振り分けられないファイル", func(t *testing.T) {
+		if _, err := uc.Process(ctx, []byte("hello"), DocumentOptions{}); !errors.Is(err, ErrUnsupportedDocument) {
+			t.Errorf("Process() error = %v, want ErrUnsupportedDocument", err)
+		}
+	})
+}
@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+const (
+	// draftKeyPrefix 確認待ちの下書きの画像・読み取り結果の保存キーの接頭辞
+	draftKeyPrefix = "draft-"
+	// draftDataSuffix 下書きの読み取り結果の保存キーの接尾辞
+	draftDataSuffix = ".json"
+)
+
+// ErrDraftNotFound 下書きが存在しない（確認済み・期限切れ・不正なトークン）
+var ErrDraftNotFound = errors.New("receipt draft not found")
+
+// ReceiptDraft 読み取ったまま保存していないレシートの下書き
+type ReceiptDraft struct {
+	Token       string
+	Receipt     *entity.Receipt
+	DuplicateOf string // 同じ画像から登録済みのレシートのID（確認すると登録済みのレシートを返す）
+	ExpiresAt   time.Time
+}
+
+// draftData 画像保存先に保存する下書きの内容
+type draftData struct {
+	Receipt   *entity.Receipt `json:"receipt"`
+	ExpiresAt time.Time       `json:"expires_at"`
+}
+
+// DraftUseCase 読み取り結果を確認してから保存する2段階のレシート登録のユースケース
+// 読み取りでは下書きとして画像保存先に置くだけで、確認されるまで家計簿に反映しない
+type DraftUseCase struct {
+	receiptUseCase *ReceiptUseCase
+	imageStorage   sharedDomain.ImageStorage
+	ttl            time.Duration
+}
+
+// NewDraftUseCase 新しいDraftUseCaseを作成
+func NewDraftUseCase(receiptUseCase *ReceiptUseCase, imageStorage sharedDomain.ImageStorage, ttl time.Duration) *DraftUseCase {
+	return &DraftUseCase{
+		receiptUseCase: receiptUseCase,
+		imageStorage:   imageStorage,
+		ttl:            ttl,
+	}
+}
+
+// Extract レシート画像を読み取って下書きを作成
+// 確認画面でカテゴリーも修正できるよう、明細のカテゴリーは同期的に判定する
+func (uc *DraftUseCase) Extract(ctx context.Context, imageData []byte, opts ProcessOptions) (*ReceiptDraft, error) {
+	rc := uc.receiptUseCase
+	imageData, receiptJSON, extraction, err := rc.recognize(ctx, imageData, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	imageHash := sha256.Sum256(imageData)
+	receipt, err := rc.parseReceiptJSON(receiptJSON, rc.newReceiptID(imageData), sharedDomain.LocationFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse receipt JSON: %w", err)
+	}
+	rc.canonicalizeItems(ctx, receipt)
+	receipt.ImageHash = hex.EncodeToString(imageHash[:])
+	receipt.Extraction = extraction
+	receipt.Tags = entity.NormalizeTags(opts.Tags)
+	receipt.Memo = strings.TrimSpace(opts.Memo)
+	if err := rc.enrichReceipt(ctx, receipt); err != nil {
+		return nil, err
+	}
+	_ = rc.categorizeReceiptItems(ctx, receipt)
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to generate draft token: %w", err)
+	}
+	draft := &ReceiptDraft{
+		Token:     hex.EncodeToString(buf),
+		Receipt:   receipt,
+		ExpiresAt: rc.clock.Now().Add(uc.ttl).Truncate(time.Second),
+	}
+	if existing := rc.findDuplicate(ctx, receipt.ImageHash, imageData); existing != nil {
+		draft.DuplicateOf = existing.ID
+	}
+
+	data, err := json.Marshal(draftData{Receipt: receipt, ExpiresAt: draft.ExpiresAt})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode draft: %w", err)
+	}
+	key := draftKeyPrefix + draft.Token
+	if err := uc.imageStorage.Save(ctx, key, imageData); err != nil {
+		return nil, fmt.Errorf("failed to store draft image: %w", err)
+	}
+	if err := uc.imageStorage.Save(ctx, key+draftDataSuffix, data); err != nil {
+		return nil, fmt.Errorf("failed to store draft: %w", err)
+	}
+	return draft, nil
+}
+
+// Confirm 下書きに利用者の修正を反映してレシートを登録
+// 同じ画像のレシートが登録済みの場合はそれを返す。データベースに接続できず一時保管した場合は、レシートとErrSavePendingを返す
+func (uc *DraftUseCase) Confirm(ctx context.Context, token string, patch ReceiptPatch) (*entity.Receipt, error) {
+	if !isDraftToken(token) {
+		return nil, ErrDraftNotFound
+	}
+	key := draftKeyPrefix + token
+	data, err := uc.imageStorage.Load(ctx, key+draftDataSuffix)
+	if errors.Is(err, sharedDomain.ErrImageNotFound) {
+		return nil, ErrDraftNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load draft: %w", err)
+	}
+	var draft draftData
+	if err := json.Unmarshal(data, &draft); err != nil {
+		return nil, fmt.Errorf("failed to decode draft: %w", err)
+	}
+	if draft.Receipt == nil {
+		return nil, ErrDraftNotFound
+	}
+	if uc.receiptUseCase.clock.Now().After(draft.ExpiresAt) {
+		return nil, ErrDraftNotFound
+	}
+	imageData, err := uc.imageStorage.Load(ctx, key)
+	if errors.Is(err, sharedDomain.ErrImageNotFound) {
+		return nil, ErrDraftNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load draft image: %w", err)
+	}
+
+	rc := uc.receiptUseCase
+	receipt := draft.Receipt
+	if err := rc.applyPatch(receipt, patch); err != nil {
+		return nil, err
+	}
+
+	result, err := uc.save(ctx, receipt, imageData)
+	if err != nil && !errors.Is(err, ErrSavePending) {
+		return nil, err
+	}
+
+	// 登録したレシートのIDで画像を保存し直したため、下書きは削除する
+	if deleteErr := uc.delete(ctx, token); deleteErr != nil {
+		return nil, deleteErr
+	}
+	return result, err
+}
+
+// save 確認したレシートと元画像を保存（同じ画像のレシートが登録済みの場合はそれを返す）
+func (uc *DraftUseCase) save(ctx context.Context, receipt *entity.Receipt, imageData []byte) (*entity.Receipt, error) {
+	rc := uc.receiptUseCase
+	if existing := rc.findDuplicate(ctx, receipt.ImageHash, imageData); existing != nil {
+		return existing, nil
+	}
+
+	now := rc.clock.Now()
+	receipt.CreatedAt = now
+	receipt.UpdatedAt = now
+	if err := rc.persist(ctx, receipt, rc.receiptRepo.Create); err != nil {
+		if !errors.Is(err, ErrSavePending) {
+			return nil, fmt.Errorf("failed to save receipt: %w", err)
+		}
+		rc.saveImage(ctx, receipt.ID, imageData)
+		return receipt, err
+	}
+	rc.saveImage(ctx, receipt.ID, imageData)
+	return receipt, nil
+}
+
+// CleanupExpiredDrafts 確認されないまま有効期限を過ぎた下書きを削除
+func (uc *DraftUseCase) CleanupExpiredDrafts(ctx context.Context) (int, error) {
+	keys, err := uc.imageStorage.ListBefore(ctx, uc.receiptUseCase.clock.Now().Add(-uc.ttl))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list drafts: %w", err)
+	}
+
+	deleted := 0
+	for _, key := range keys {
+		if !strings.HasPrefix(key, draftKeyPrefix) {
+			continue
+		}
+		if err := uc.imageStorage.Delete(ctx, key); err != nil {
+			return deleted, fmt.Errorf("failed to delete draft: %w", err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// delete 下書きの画像と読み取り結果を削除
+func (uc *DraftUseCase) delete(ctx context.Context, token string) error {
+	key := draftKeyPrefix + token
+	for _, k := range []string{key, key + draftDataSuffix} {
+		if err := uc.imageStorage.Delete(ctx, k); err != nil {
+			return fmt.Errorf("failed to delete draft: %w", err)
+		}
+	}
+	return nil
+}
+
+// isDraftToken 下書きのトークンの形式（32桁の16進数）か判定
+func isDraftToken(token string) bool {
+	if len(token) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(token)
+	return err == nil
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/shared/infrastructure/storage"
+)
+
+func TestDraftUseCase_ExtractAndConfirm(t *testing.T) {
+	var stored *entity.Receipt
+	mockReceipt := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			return nil, errors.New("not found")
+		},
+		CreateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			stored = receipt
+			return nil
+		},
+	}
+	imageStorage, err := storage.NewLocalImageStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalImageStorage() error = %v", err)
+	}
+
+	receiptUseCase := NewReceiptUseCase(&MockAIRepository{}, mockReceipt, nil)
+	receiptUseCase.SetImageStorage(imageStorage)
+	uc := NewDraftUseCase(receiptUseCase, imageStorage, time.Hour)
+	ctx := context.Background()
+
+	draft, err := uc.Extract(ctx, []byte("receipt image"), ProcessOptions{Tags: []string{"旅行"}})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if stored != nil {
+		t.Fatal("Expected draft not to be saved before confirmation")
+	}
+	if len(draft.Token) != 32 || draft.Receipt.StoreName != "Test Store" || draft.DuplicateOf != "" {
+		t.Errorf("draft = %+v", draft)
+	}
+	if len(draft.Receipt.Items) != 1 || draft.Receipt.Items[0].CategoryStatus == entity.CategoryStatusPending {
+		t.Errorf("Expected items to be categorized in the draft, got %+v", draft.Receipt.Items)
+	}
+
+	// 利用者が店名と明細を修正して確認
+	storeName := "修正後の店"
+	items := []ItemPatch{
+		{ID: draft.Receipt.Items[0].ID, Name: "Item1", Quantity: 1, Price: 500},
+		{Name: "追加", Quantity: 2, Price: 250, Category: "食費"},
+	}
+	receipt, err := uc.Confirm(ctx, draft.Token, ReceiptPatch{StoreName: &storeName, Items: &items})
+	if err != nil {
+		t.Fatalf("Confirm() error = %v", err)
+	}
+	if stored == nil || stored.ID != receipt.ID || stored.StoreName != storeName || len(stored.Items) != 2 {
+		t.Fatalf("stored = %+v, want confirmed receipt", stored)
+	}
+	if stored.Items[0].Category != draft.Receipt.Items[0].Category || stored.Items[1].CategoryStatus != entity.CategoryStatusManual {
+		t.Errorf("items = %+v", stored.Items)
+	}
+	if len(stored.Tags) != 1 || stored.ImageHash == "" {
+		t.Errorf("receipt = %+v, want tags and image hash from the draft", stored)
+	}
+
+	// 元画像はレシートIDで保存し、下書きは削除する
+	if _, err := imageStorage.Load(ctx, receipt.ID); err != nil {
+		t.Errorf("Expected receipt image to be stored: %v", err)
+	}
+	if _, err := imageStorage.Load(ctx, draftKeyPrefix+draft.Token); err == nil {
+		t.Error("Expected draft image to be deleted")
+	}
+
+	// 同じ下書きは二度確認できない
+	if _, err := uc.Confirm(ctx, draft.Token, ReceiptPatch{}); !errors.Is(err, ErrDraftNotFound) {
+		t.Errorf("Confirm() error = %v, want ErrDraftNotFound", err)
+	}
+}
+
+func TestDraftUseCase_Confirm_Errors(t *testing.T) {
+	imageStorage, err := storage.NewLocalImageStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalImageStorage() error = %v", err)
+	}
+	mockReceipt := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			return nil, errors.New("not found")
+		},
+		CreateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			t.Error("Expected receipt not to be saved")
+			return nil
+		},
+	}
+	receiptUseCase := NewReceiptUseCase(&MockAIRepository{}, mockReceipt, nil)
+	ctx := context.Background()
+
+	t.Run("不正なトークン", func(t *testing.T) {
+		uc := NewDraftUseCase(receiptUseCase, imageStorage, time.Hour)
+		for _, token := range []string{"", "../receipt", "0123456789abcdef0123456789abcdef"} {
+			if _, err := uc.Confirm(ctx, token, ReceiptPatch{}); !errors.Is(err, ErrDraftNotFound) {
+				t.Errorf("Confirm(%q) error = %v, want ErrDraftNotFound", token, err)
+			}
+		}
+	})
+
+	t.Run("不正な修正", func(t *testing.T) {
+		uc := NewDraftUseCase(receiptUseCase, imageStorage, time.Hour)
+		draft, err := uc.Extract(ctx, []byte("invalid"), ProcessOptions{})
+		if err != nil {
+			t.Fatalf("Extract() error = %v", err)
+		}
+		empty := ""
+		if _, err := uc.Confirm(ctx, draft.Token, ReceiptPatch{StoreName: &empty}); !errors.Is(err, ErrInvalidReceipt) {
+			t.Errorf("Confirm() error = %v, want ErrInvalidReceipt", err)
+		}
+	})
+
+	t.Run("期限切れ", func(t *testing.T) {
+		uc := NewDraftUseCase(receiptUseCase, imageStorage, -time.Minute)
+		draft, err := uc.Extract(ctx, []byte("expired"), ProcessOptions{})
+		if err != nil {
+			t.Fatalf("Extract() error = %v", err)
+		}
+		if _, err := uc.Confirm(ctx, draft.Token, ReceiptPatch{}); !errors.Is(err, ErrDraftNotFound) {
+			t.Errorf("Confirm() error = %v, want ErrDraftNotFound", err)
+		}
+
+		deleted, err := uc.CleanupExpiredDrafts(ctx)
+		if err != nil {
+			t.Fatalf("CleanupExpiredDrafts() error = %v", err)
+		}
+		if deleted < 2 {
+			t.Errorf("CleanupExpiredDrafts() = %d, want draft image and data deleted", deleted)
+		}
+	})
+}
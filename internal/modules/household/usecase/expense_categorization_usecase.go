@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/vision/domain"
+)
+
+const (
+	// categorizationBatchSize 1回のAIの呼び出しで判定する摘要の件数
+	// 件数が多いほどシステムプロンプトの繰り返しが減るが、出力が途中で切れやすくなる
+	categorizationBatchSize = 50
+	// maxCategorizationDescriptions 1回のリクエストで判定できる摘要の件数
+	maxCategorizationDescriptions = 500
+)
+
+// ErrInvalidCategorizationRequest 判定する摘要の指定が不正（空、件数の上限を超えるなど）
+var ErrInvalidCategorizationRequest = errors.New("invalid categorization request")
+
+// CategorySuggestion 摘要ごとのカテゴリーの判定結果
+type CategorySuggestion struct {
+	Description string
+	Category    string
+	Status      string // entity.CategoryStatusAuto・entity.CategoryStatusAutoFailed（判定できなかった場合はその他）
+}
+
+// ExpenseCategorization 摘要のカテゴリーの一括判定結果（Suggestionsはリクエストと同じ順番）
+type ExpenseCategorization struct {
+	Suggestions  []CategorySuggestion
+	InputTokens  int
+	OutputTokens int
+}
+
+// ExpenseCategorizationUseCase 取り込んだ銀行・カードの利用明細などの摘要のカテゴリーをまとめて判定するユースケース
+// 同じ摘要は1回だけ判定し、摘要をcategorizationBatchSize件ずつ1つのプロンプトにまとめてAIの呼び出しとトークンを減らす
+type ExpenseCategorizationUseCase struct {
+	receiptUseCase *ReceiptUseCase
+}
+
+// NewExpenseCategorizationUseCase 新しいExpenseCategorizationUseCaseを作成
+func NewExpenseCategorizationUseCase(receiptUseCase *ReceiptUseCase) *ExpenseCategorizationUseCase {
+	return &ExpenseCategorizationUseCase{
+		receiptUseCase: receiptUseCase,
+	}
+}
+
+// Categorize 摘要ごとのカテゴリーを判定
+// AIの呼び出し・応答の解析に失敗したまとまりは、レシートの明細と同じく判定失敗（その他）とする
+func (uc *ExpenseCategorizationUseCase) Categorize(ctx context.Context, descriptions []string) (*ExpenseCategorization, error) {
+	if len(descriptions) == 0 {
+		return nil, fmt.Errorf("%w: descriptions are required", ErrInvalidCategorizationRequest)
+	}
+	if len(descriptions) > maxCategorizationDescriptions {
+		return nil, fmt.Errorf("%w: at most %d descriptions can be categorized at once", ErrInvalidCategorizationRequest, maxCategorizationDescriptions)
+	}
+
+	// 同じ摘要は1回だけ判定する
+	unique := make([]string, 0, len(descriptions))
+	for i, description := range descriptions {
+		description = strings.TrimSpace(description)
+		if description == "" {
+			return nil, fmt.Errorf("%w: descriptions[%d] is empty", ErrInvalidCategorizationRequest, i)
+		}
+		if !slices.Contains(unique, description) {
+			unique = append(unique, description)
+		}
+	}
+
+	result := &ExpenseCategorization{Suggestions: make([]CategorySuggestion, 0, len(descriptions))}
+	categories := make(map[string]CategorySuggestion, len(unique))
+	for batch := range slices.Chunk(unique, categorizationBatchSize) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for _, suggestion := range uc.categorizeBatch(ctx, batch, result) {
+			categories[suggestion.Description] = suggestion
+		}
+	}
+
+	for _, description := range descriptions {
+		result.Suggestions = append(result.Suggestions, categories[strings.TrimSpace(description)])
+	}
+	return result, nil
+}
+
+// categorizeBatch 摘要のまとまりを1回のAIの呼び出しで判定し、使用したトークン数をresultに加える
+func (uc *ExpenseCategorizationUseCase) categorizeBatch(ctx context.Context, batch []string, result *ExpenseCategorization) []CategorySuggestion {
+	suggestions := make([]CategorySuggestion, len(batch))
+	for i, description := range batch {
+		suggestions[i] = CategorySuggestion{Description: description, Category: entity.DefaultCategory, Status: entity.CategoryStatusAutoFailed}
+	}
+
+	names := uc.receiptUseCase.categoryNames(ctx)
+	info := fmt.Sprintf("以下は銀行・クレジットカードの利用明細の摘要です。それぞれの支出のカテゴリーを判定し、同じ順番のJSON配列で返してください（%s）:\n", strings.Join(names, "、"))
+	for i, description := range batch {
+		info += fmt.Sprintf("%d. %s\n", i+1, description)
+	}
+
+	aiResult, err := uc.receiptUseCase.aiRepo.CategorizeReceipt(info)
+	if errors.Is(err, domain.ErrAIDowngraded) {
+		// AIの予算超過で縮退中の場合はキーワードのルールで判定する
+		for i, description := range batch {
+			if category, ok := uc.receiptUseCase.categoryRules.categorize(description); ok {
+				suggestions[i].Category = category
+				suggestions[i].Status = entity.CategoryStatusAuto
+			}
+		}
+		return suggestions
+	}
+	if err != nil {
+		slog.Warn("Expense categorization failed", "descriptions", len(batch), "error", err)
+		return suggestions
+	}
+	result.InputTokens += aiResult.InputTokens
+	result.OutputTokens += aiResult.OutputTokens
+
+	categories, err := uc.receiptUseCase.parseItemCategories(aiResult.CorrectedText, len(batch))
+	if err != nil {
+		slog.Warn("Failed to parse expense categories", "descriptions", len(batch), "error", err)
+		return suggestions
+	}
+	// 判定結果が不足している摘要・判定対象外のカテゴリーは判定失敗として扱う
+	for i := range suggestions {
+		if i < len(categories) && slices.Contains(names, strings.TrimSpace(categories[i])) {
+			suggestions[i].Category = strings.TrimSpace(categories[i])
+			suggestions[i].Status = entity.CategoryStatusAuto
+		}
+	}
+	return suggestions
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/vision/domain"
+)
+
+func TestExpenseCategorizationUseCase_Categorize(t *testing.T) {
+	var prompts []string
+	mockAI := &MockAIRepository{
+		CategorizeReceiptFunc: func(receiptInfo string) (*domain.AIResult, error) {
+			prompts = append(prompts, receiptInfo)
+			return domain.NewAIResult(receiptInfo, `["交通費", "食費", "家具"]`, 100, 10, "test"), nil
+		},
+	}
+	uc := NewExpenseCategorizationUseCase(NewReceiptUseCase(mockAI, nil, nil))
+
+	result, err := uc.Categorize(context.Background(), []string{"JR東日本 モバイルSuica", " セブンイレブン ", "ニトリ", "JR東日本 モバイルSuica"})
+	if err != nil {
+		t.Fatalf("Categorize() error = %v", err)
+	}
+
+	// 同じ摘要は1回だけ判定し、1回の呼び出しにまとめる
+	if len(prompts) != 1 || strings.Count(prompts[0], "JR東日本") != 1 || !strings.Contains(prompts[0], "2. セブンイレブン\n") {
+		t.Errorf("prompts = %q", prompts)
+	}
+	want := []CategorySuggestion{
+		{Description: "JR東日本 モバイルSuica", Category: "交通費", Status: entity.CategoryStatusAuto},
+		{Description: "セブンイレブン", Category: "食費", Status: entity.CategoryStatusAuto},
+		// 判定対象外のカテゴリーは判定失敗とする
+		{Description: "ニトリ", Category: entity.DefaultCategory, Status: entity.CategoryStatusAutoFailed},
+		{Description: "JR東日本 モバイルSuica", Category: "交通費", Status: entity.CategoryStatusAuto},
+	}
+	if len(result.Suggestions) != len(want) {
+		t.Fatalf("Suggestions = %+v", result.Suggestions)
+	}
+	for i := range want {
+		if result.Suggestions[i] != want[i] {
+			t.Errorf("Suggestions[%d] = %+v, want %+v", i, result.Suggestions[i], want[i])
+		}
+	}
+	if result.InputTokens != 100 || result.OutputTokens != 10 {
+		t.Errorf("tokens = (%d, %d), want (100, 10)", result.InputTokens, result.OutputTokens)
+	}
+}
+
+func TestExpenseCategorizationUseCase_Categorize_RegisteredCategories(t *testing.T) {
+	var prompt string
+	mockAI := &MockAIRepository{
+		CategorizeReceiptFunc: func(receiptInfo string) (*domain.AIResult, error) {
+			prompt = receiptInfo
+			return domain.NewAIResult(receiptInfo, `["家具"]`, 100, 10, "test"), nil
+		},
+	}
+	receiptUseCase := NewReceiptUseCase(mockAI, nil, nil)
+	receiptUseCase.SetCategoryRepository(&fakeCategoryRepository{categories: map[string]*entity.Category{
+		"category-1": {ID: "category-1", Name: "家具"},
+	}})
+	uc := NewExpenseCategorizationUseCase(receiptUseCase)
+
+	result, err := uc.Categorize(context.Background(), []string{"ニトリ"})
+	if err != nil {
+		t.Fatalf("Categorize() error = %v", err)
+	}
+
+	// 利用者が登録したカテゴリーをAIに示し、判定結果として受け付ける
+	if !strings.Contains(prompt, "（家具、その他）") {
+		t.Errorf("prompt = %q, want registered categories", prompt)
+	}
+	want := CategorySuggestion{Description: "ニトリ", Category: "家具", Status: entity.CategoryStatusAuto}
+	if len(result.Suggestions) != 1 || result.Suggestions[0] != want {
+		t.Errorf("Suggestions = %+v, want [%+v]", result.Suggestions, want)
+	}
+}
+
+func TestExpenseCategorizationUseCase_Categorize_Batches(t *testing.T) {
+	calls := 0
+	mockAI := &MockAIRepository{
+		CategorizeReceiptFunc: func(receiptInfo string) (*domain.AIResult, error) {
+			calls++
+			if calls == 2 {
+				return nil, errors.New("rate limited")
+			}
+			n := strings.Count(receiptInfo, "\n") - 1
+			categories := make([]string, n)
+			for i := range categories {
+				categories[i] = "食費"
+			}
+			data, _ := json.Marshal(categories)
+			return domain.NewAIResult(receiptInfo, string(data), 100, 10, "test"), nil
+		},
+	}
+	uc := NewExpenseCategorizationUseCase(NewReceiptUseCase(mockAI, nil, nil))
+
+	descriptions := make([]string, categorizationBatchSize+10)
+	for i := range descriptions {
+		descriptions[i] = fmt.Sprintf("店舗%d", i)
+	}
+	result, err := uc.Categorize(context.Background(), descriptions)
+	if err != nil {
+		t.Fatalf("Categorize() error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+	// 呼び出しに失敗したまとまりだけ判定失敗とする
+	if s := result.Suggestions[0]; s.Category != "食費" || s.Status != entity.CategoryStatusAuto {
+		t.Errorf("Suggestions[0] = %+v", s)
+	}
+	if s := result.Suggestions[categorizationBatchSize]; s.Category != entity.DefaultCategory || s.Status != entity.CategoryStatusAutoFailed {
+		t.Errorf("Suggestions[%d] = %+v", categorizationBatchSize, s)
+	}
+	if result.InputTokens != 100 {
+		t.Errorf("InputTokens = %d, want 100", result.InputTokens)
+	}
+}
+
+func TestExpenseCategorizationUseCase_Categorize_Invalid(t *testing.T) {
+	uc := NewExpenseCategorizationUseCase(NewReceiptUseCase(&MockAIRepository{}, nil, nil))
+
+	tests := map[string][]string{
+		"empty":      nil,
+		"blank":      {"スーパー", " "},
+		"over limit": make([]string, maxCategorizationDescriptions+1),
+	}
+	for name, descriptions := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := uc.Categorize(context.Background(), descriptions); !errors.Is(err, ErrInvalidCategorizationRequest) {
+				t.Errorf("Categorize() error = %v, want ErrInvalidCategorizationRequest", err)
+			}
+		})
+	}
+}
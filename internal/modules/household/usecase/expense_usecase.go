@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// ErrInvalidExpense 保存・修正する家計簿エントリが不正（カテゴリーが空、金額が負など）
+var ErrInvalidExpense = errors.New("invalid expense")
+
+// ExpensePatch 家計簿エントリの部分更新内容（nilの項目は変更しない）
+type ExpensePatch struct {
+	Memo *string
+}
+
+// ExpenseInput 家計簿エントリの登録・置き換えの内容
+type ExpenseInput struct {
+	Date        time.Time
+	Category    string
+	Amount      int64
+	Description string
+	Tags        []string
+	Memo        string
+}
+
+// ExpenseCategoryShare 月のカテゴリーごとの支出と、月の支出に占める割合
+type ExpenseCategoryShare struct {
+	entity.CategoryBreakdown
+	Count int     // 明細・家計簿エントリの件数
+	Total int64   // 明細・家計簿エントリの金額の合計
+	Share float64 // 月の支出に占める割合（%、小数第2位まで）
+}
+
+// ExpenseSummary 月のカテゴリーごとの支出の集計
+type ExpenseSummary struct {
+	Start      time.Time // 月の初日の0時
+	End        time.Time // 翌月の初日の0時（この日時を含まない）
+	Count      int
+	Total      int64
+	Categories []ExpenseCategoryShare // 金額の大きい順
+}
+
+// ExpenseUseCase 家計簿エントリのユースケース
+type ExpenseUseCase struct {
+	expenseRepo    repository.ExpenseRepository
+	aggregateRepo  repository.AggregateRepository
+	eventPublisher sharedDomain.EventPublisher
+	idGenerator    sharedDomain.IDGenerator
+	clock          sharedDomain.Clock
+	monthStartDay  int
+}
+
+// NewExpenseUseCase 新しいExpenseUseCaseを作成
+func NewExpenseUseCase(expenseRepo repository.ExpenseRepository) *ExpenseUseCase {
+	return &ExpenseUseCase{
+		expenseRepo:   expenseRepo,
+		clock:         sharedDomain.SystemClock{},
+		monthStartDay: 1,
+	}
+}
+
+// SetMonthStartDay 月の集計の月の開始日（給料日など）を設定する
+// 家計簿の月別集計（HouseholdUseCase）と同じ期間にそろえるため、reports.month_start_dayを設定する。範囲外の場合は1日とする
+func (uc *ExpenseUseCase) SetMonthStartDay(day int) {
+	uc.monthStartDay = resolveMonthStartDay(day, 1)
+}
+
+// SetClock 更新日時に使う時計を設定する
+// 未設定の場合はシステムの時計を使う
+func (uc *ExpenseUseCase) SetClock(clock sharedDomain.Clock) {
+	uc.clock = clock
+}
+
+// SetIDGenerator 登録する家計簿エントリのIDの生成器を設定する
+// 未設定の場合はUUIDを使う
+func (uc *ExpenseUseCase) SetIDGenerator(generator sharedDomain.IDGenerator) {
+	uc.idGenerator = generator
+}
+
+// SetEventPublisher ドメインイベントの配信先を設定する
+// 家計簿エントリの修正を保存後に配信する。未設定の場合は配信しない
+func (uc *ExpenseUseCase) SetEventPublisher(eventPublisher sharedDomain.EventPublisher) {
+	uc.eventPublisher = eventPublisher
+}
+
+// SetAggregateRepository 月のカテゴリーごとの支出をデータベースで集計するリポジトリを設定する
+// 未設定の場合は月の集計を行わない
+func (uc *ExpenseUseCase) SetAggregateRepository(repo repository.AggregateRepository) {
+	uc.aggregateRepo = repo
+}
+
+// GetExpense 家計簿エントリを取得
+func (uc *ExpenseUseCase) GetExpense(ctx context.Context, id string) (*entity.ExpenseEntry, error) {
+	return uc.expenseRepo.FindByID(ctx, id)
+}
+
+// ListExpenses 検索条件に一致する家計簿エントリを日付の新しい順に取得
+func (uc *ExpenseUseCase) ListExpenses(ctx context.Context, filter repository.ExpenseFilter, limit, offset int) ([]*entity.ExpenseEntry, error) {
+	return uc.expenseRepo.FindByFilter(ctx, filter, limit, offset)
+}
+
+// CreateExpense レシートに紐づかない家計簿エントリを登録（不正な場合は保存せずErrInvalidExpenseを返す）
+func (uc *ExpenseUseCase) CreateExpense(ctx context.Context, input ExpenseInput) (*entity.ExpenseEntry, error) {
+	if input.Date.IsZero() {
+		return nil, fmt.Errorf("%w: date is required", ErrInvalidExpense)
+	}
+
+	entry := entity.NewExpenseEntry(uc.newID(), input.Date, strings.TrimSpace(input.Category), input.Amount, strings.TrimSpace(input.Description), entity.NormalizeTags(input.Tags), uc.clock.Now())
+	entry.Memo = strings.TrimSpace(input.Memo)
+	if err := entry.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidExpense, err)
+	}
+
+	if err := uc.expenseRepo.Create(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to create expense: %w", err)
+	}
+	return entry, nil
+}
+
+// ReplaceExpense 家計簿エントリの編集できる項目をすべて置き換える（紐づくレシートは変えない）
+// 不正な場合は保存せずErrInvalidExpenseを返す
+func (uc *ExpenseUseCase) ReplaceExpense(ctx context.Context, id string, input ExpenseInput) (*entity.ExpenseEntry, error) {
+	if input.Date.IsZero() {
+		return nil, fmt.Errorf("%w: date is required", ErrInvalidExpense)
+	}
+
+	entry, err := uc.expenseRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.UpdateDetails(input.Date, strings.TrimSpace(input.Category), input.Amount, strings.TrimSpace(input.Description), entity.NormalizeTags(input.Tags))
+	entry.UpdateMemo(strings.TrimSpace(input.Memo))
+	if err := entry.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidExpense, err)
+	}
+
+	entry.UpdatedAt = uc.clock.Now()
+	if err := uc.expenseRepo.Update(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to update expense: %w", err)
+	}
+	publishEvents(ctx, uc.eventPublisher, entry)
+	return entry, nil
+}
+
+// PatchExpense 家計簿エントリを部分的に修正（不正な場合は保存せずErrInvalidExpenseを返す）
+func (uc *ExpenseUseCase) PatchExpense(ctx context.Context, id string, patch ExpensePatch) (*entity.ExpenseEntry, error) {
+	entry, err := uc.expenseRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.Memo != nil {
+		entry.UpdateMemo(strings.TrimSpace(*patch.Memo))
+	}
+
+	if err := entry.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidExpense, err)
+	}
+
+	entry.UpdatedAt = uc.clock.Now()
+	if err := uc.expenseRepo.Update(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to update expense: %w", err)
+	}
+	publishEvents(ctx, uc.eventPublisher, entry)
+	return entry, nil
+}
+
+// CurrentMonth 現在日時を含む月（月の開始日がmonthStartDay日の場合、開始日より前は前月）
+// monthStartDayが0の場合は設定の開始日を使う
+func (uc *ExpenseUseCase) CurrentMonth(ctx context.Context, monthStartDay int) (year, month int) {
+	start := monthStartOf(uc.clock.Now().In(sharedDomain.LocationFromContext(ctx)), resolveMonthStartDay(monthStartDay, uc.monthStartDay))
+	return start.Year(), int(start.Month())
+}
+
+// GetMonthlySummary 指定年月のレシートの明細・家計簿エントリをカテゴリーごとに集計し、月の支出に占める割合を返す
+// 月は家計簿の月別集計と同じくmonthStartDay日から翌月のmonthStartDay日の前日まで（0の場合は設定の開始日）とし、
+// 日の区切りはコンテキストのタイムゾーンとする。カテゴリー未設定の明細は「その他」に含める
+func (uc *ExpenseUseCase) GetMonthlySummary(ctx context.Context, year, month, monthStartDay int) (*ExpenseSummary, error) {
+	if month < 1 || month > 12 {
+		return nil, fmt.Errorf("%w: month must be 1-12", ErrInvalidReportPeriod)
+	}
+	if uc.aggregateRepo == nil {
+		return nil, errors.New("aggregate repository is not configured")
+	}
+
+	start := monthStart(year, time.Month(month), resolveMonthStartDay(monthStartDay, uc.monthStartDay), sharedDomain.LocationFromContext(ctx))
+	end := start.AddDate(0, 1, 0)
+	breakdowns, err := uc.aggregateRepo.SumByCategory(ctx, start, end.Add(-time.Nanosecond))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum expenses by category: %w", err)
+	}
+
+	summary := &ExpenseSummary{Start: start, End: end}
+	shares := make(map[string]*ExpenseCategoryShare, len(breakdowns))
+	for _, breakdown := range breakdowns {
+		category := breakdown.Category
+		if category == "" {
+			category = entity.DefaultCategory
+		}
+		share, ok := shares[category]
+		if !ok {
+			share = &ExpenseCategoryShare{CategoryBreakdown: entity.CategoryBreakdown{Category: category}}
+			shares[category] = share
+		}
+		share.ReceiptCount += breakdown.ReceiptCount
+		share.ItemCount += breakdown.ItemCount
+		share.ItemTotal += breakdown.ItemTotal
+		share.ExpenseCount += breakdown.ExpenseCount
+		share.ExpenseTotal += breakdown.ExpenseTotal
+		share.Count = share.ItemCount + share.ExpenseCount
+		share.Total = share.ItemTotal + share.ExpenseTotal
+		summary.Count += breakdown.ItemCount + breakdown.ExpenseCount
+		summary.Total += breakdown.ItemTotal + breakdown.ExpenseTotal
+	}
+
+	summary.Categories = make([]ExpenseCategoryShare, 0, len(shares))
+	for _, share := range shares {
+		if summary.Total != 0 {
+			share.Share = math.Round(float64(share.Total)*10000/float64(summary.Total)) / 100
+		}
+		summary.Categories = append(summary.Categories, *share)
+	}
+	sort.Slice(summary.Categories, func(a, b int) bool {
+		if summary.Categories[a].Total != summary.Categories[b].Total {
+			return summary.Categories[a].Total > summary.Categories[b].Total
+		}
+		return summary.Categories[a].Category < summary.Categories[b].Category
+	})
+	return summary, nil
+}
+
+// newID 登録する家計簿エントリのIDを生成
+func (uc *ExpenseUseCase) newID() string {
+	if uc.idGenerator != nil {
+		return uc.idGenerator.NewID(nil)
+	}
+	return uuid.NewString()
+}
@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+func TestExpenseUseCase_PatchExpense(t *testing.T) {
+	var updated *entity.ExpenseEntry
+	mockExpense := &MockExpenseRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.ExpenseEntry, error) {
+			if id != "expense-1" {
+				return nil, errors.New("not found")
+			}
+			return &entity.ExpenseEntry{ID: id, Category: "食費", Amount: 1000, Memo: "旧メモ"}, nil
+		},
+		UpdateFunc: func(ctx context.Context, entry *entity.ExpenseEntry) error {
+			updated = entry
+			return nil
+		},
+	}
+
+	uc := NewExpenseUseCase(mockExpense)
+	ctx := context.Background()
+
+	memo := "  友人と割り勘  "
+	entry, err := uc.PatchExpense(ctx, "expense-1", ExpensePatch{Memo: &memo})
+	if err != nil {
+		t.Fatalf("PatchExpense() error = %v", err)
+	}
+	if entry.Memo != "友人と割り勘" || updated == nil || updated.Memo != "友人と割り勘" {
+		t.Errorf("Memo = %q, want 友人と割り勘", entry.Memo)
+	}
+	if entry.Category != "食費" || entry.Amount != 1000 {
+		t.Error("PatchExpense() should not change other fields")
+	}
+
+	if _, err := uc.PatchExpense(ctx, "missing", ExpensePatch{Memo: &memo}); err == nil {
+		t.Error("Expected error for missing expense")
+	}
+}
+
+func TestExpenseUseCase_PatchExpense_Invalid(t *testing.T) {
+	updated := false
+	mockExpense := &MockExpenseRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.ExpenseEntry, error) {
+			return &entity.ExpenseEntry{ID: id, Category: "", Amount: 1000}, nil
+		},
+		UpdateFunc: func(ctx context.Context, entry *entity.ExpenseEntry) error {
+			updated = true
+			return nil
+		},
+	}
+
+	memo := "メモ"
+	_, err := NewExpenseUseCase(mockExpense).PatchExpense(context.Background(), "expense-1", ExpensePatch{Memo: &memo})
+	if !errors.Is(err, ErrInvalidExpense) {
+		t.Errorf("PatchExpense() error = %v, want %v", err, ErrInvalidExpense)
+	}
+	if updated {
+		t.Error("Expected invalid expense not to be updated")
+	}
+}
+
+func TestExpenseUseCase_CreateExpense(t *testing.T) {
+	var created *entity.ExpenseEntry
+	mockExpense := &MockExpenseRepository{
+		CreateFunc: func(ctx context.Context, entry *entity.ExpenseEntry) error {
+			created = entry
+			return nil
+		},
+	}
+	uc := NewExpenseUseCase(mockExpense)
+	ctx := context.Background()
+
+	date := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	entry, err := uc.CreateExpense(ctx, ExpenseInput{
+		Date:        date,
+		Category:    " 交通費 ",
+		Amount:      1200,
+		Description: "タクシー",
+		Tags:        []string{"出張", " 出張 ", ""},
+		Memo:        " 深夜 ",
+	})
+	if err != nil {
+		t.Fatalf("CreateExpense() error = %v", err)
+	}
+	if created == nil || entry.ID == "" || entry.ReceiptID != nil {
+		t.Fatalf("created = %+v", created)
+	}
+	if entry.Category != "交通費" || entry.Memo != "深夜" || len(entry.Tags) != 1 || !entry.Date.Equal(date) {
+		t.Errorf("CreateExpense() = %+v", entry)
+	}
+
+	for name, input := range map[string]ExpenseInput{
+		"日付がない":    {Category: "食費", Amount: 100},
+		"カテゴリーが空白": {Date: date, Category: " ", Amount: 100},
+		"金額が負":     {Date: date, Category: "食費", Amount: -1},
+	} {
+		if _, err := uc.CreateExpense(ctx, input); !errors.Is(err, ErrInvalidExpense) {
+			t.Errorf("%s: CreateExpense() error = %v, want %v", name, err, ErrInvalidExpense)
+		}
+	}
+}
+
+func TestExpenseUseCase_ReplaceExpense(t *testing.T) {
+	receiptID := "receipt-1"
+	var updated *entity.ExpenseEntry
+	mockExpense := &MockExpenseRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.ExpenseEntry, error) {
+			if id != "expense-1" {
+				return nil, errors.New("not found")
+			}
+			return &entity.ExpenseEntry{ID: id, ReceiptID: &receiptID, Category: "食費", Amount: 1000, Tags: []string{"外食"}, Memo: "旧メモ"}, nil
+		},
+		UpdateFunc: func(ctx context.Context, entry *entity.ExpenseEntry) error {
+			updated = entry
+			return nil
+		},
+	}
+	uc := NewExpenseUseCase(mockExpense)
+	ctx := context.Background()
+
+	date := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	entry, err := uc.ReplaceExpense(ctx, "expense-1", ExpenseInput{Date: date, Category: "交際費", Amount: 1500})
+	if err != nil {
+		t.Fatalf("ReplaceExpense() error = %v", err)
+	}
+	if updated == nil || entry.Category != "交際費" || entry.Amount != 1500 || !entry.Date.Equal(date) {
+		t.Errorf("ReplaceExpense() = %+v", entry)
+	}
+	// 省略した項目は空にし、紐づくレシートは変えない
+	if entry.Memo != "" || len(entry.Tags) != 0 || entry.ReceiptID == nil || *entry.ReceiptID != receiptID {
+		t.Errorf("ReplaceExpense() = %+v", entry)
+	}
+
+	if _, err := uc.ReplaceExpense(ctx, "expense-1", ExpenseInput{Category: "食費"}); !errors.Is(err, ErrInvalidExpense) {
+		t.Errorf("ReplaceExpense() error = %v, want %v", err, ErrInvalidExpense)
+	}
+	if _, err := uc.ReplaceExpense(ctx, "missing", ExpenseInput{Date: date, Category: "食費"}); err == nil {
+		t.Error("Expected error for missing expense")
+	}
+}
+
+func TestExpenseUseCase_GetMonthlySummary(t *testing.T) {
+	tokyo := time.FixedZone("Asia/Tokyo", 9*60*60)
+	var gotStart, gotEnd time.Time
+	uc := NewExpenseUseCase(&MockExpenseRepository{})
+	uc.SetAggregateRepository(&MockAggregateRepository{
+		SumByCategoryFunc: func(ctx context.Context, start, end time.Time) ([]*entity.CategoryBreakdown, error) {
+			gotStart, gotEnd = start, end
+			return []*entity.CategoryBreakdown{
+				{Category: "", ReceiptCount: 1, ItemCount: 1, ItemTotal: 500},
+				{Category: "その他", ExpenseCount: 1, ExpenseTotal: 500},
+				{Category: "交通費", ExpenseCount: 2, ExpenseTotal: 1000},
+				{Category: "食費", ReceiptCount: 2, ItemCount: 3, ItemTotal: 2000},
+			}, nil
+		},
+	})
+	ctx := sharedDomain.WithLocation(context.Background(), tokyo)
+
+	summary, err := uc.GetMonthlySummary(ctx, 2025, 11, 0)
+	if err != nil {
+		t.Fatalf("GetMonthlySummary() error = %v", err)
+	}
+	if !gotStart.Equal(time.Date(2025, 11, 1, 0, 0, 0, 0, tokyo)) || !gotEnd.Equal(time.Date(2025, 12, 1, 0, 0, 0, 0, tokyo).Add(-time.Nanosecond)) {
+		t.Errorf("SumByCategory() period = %v - %v", gotStart, gotEnd)
+	}
+	if summary.Count != 7 || summary.Total != 4000 || len(summary.Categories) != 3 {
+		t.Fatalf("GetMonthlySummary() = %+v", summary)
+	}
+
+	// カテゴリー未設定の明細は「その他」に含め、金額の大きい順に並べる
+	want := []struct {
+		category string
+		count    int
+		total    int64
+		share    float64
+	}{
+		{"食費", 3, 2000, 50},
+		{"その他", 2, 1000, 25},
+		{"交通費", 2, 1000, 25},
+	}
+	for i, w := range want {
+		got := summary.Categories[i]
+		if got.Category != w.category || got.Count != w.count || got.Total != w.total || got.Share != w.share {
+			t.Errorf("Categories[%d] = %+v, want %+v", i, got, w)
+		}
+	}
+	if other := summary.Categories[1]; other.ItemTotal != 500 || other.ExpenseTotal != 500 || other.ReceiptCount != 1 {
+		t.Errorf("Categories[1] = %+v", other)
+	}
+
+	if _, err := uc.GetMonthlySummary(ctx, 2025, 13, 0); !errors.Is(err, ErrInvalidReportPeriod) {
+		t.Errorf("GetMonthlySummary() error = %v, want %v", err, ErrInvalidReportPeriod)
+	}
+
+	// 家計簿の月別集計と同じく、設定の月の開始日から翌月の開始日の前日までを集計する
+	uc.SetMonthStartDay(25)
+	if _, err := uc.GetMonthlySummary(ctx, 2025, 11, 0); err != nil {
+		t.Fatalf("GetMonthlySummary() error = %v", err)
+	}
+	if !gotStart.Equal(time.Date(2025, 11, 25, 0, 0, 0, 0, tokyo)) || !gotEnd.Equal(time.Date(2025, 12, 25, 0, 0, 0, 0, tokyo).Add(-time.Nanosecond)) {
+		t.Errorf("SumByCategory() period = %v - %v, want from the 25th", gotStart, gotEnd)
+	}
+	// リクエストで指定した開始日は設定より優先する
+	if _, err := uc.GetMonthlySummary(ctx, 2025, 11, 10); err != nil {
+		t.Fatalf("GetMonthlySummary() error = %v", err)
+	}
+	if !gotStart.Equal(time.Date(2025, 11, 10, 0, 0, 0, 0, tokyo)) {
+		t.Errorf("SumByCategory() start = %v, want 2025-11-10", gotStart)
+	}
+}
+
+func TestExpenseUseCase_CurrentMonth(t *testing.T) {
+	tokyo := time.FixedZone("Asia/Tokyo", 9*60*60)
+	uc := NewExpenseUseCase(&MockExpenseRepository{})
+	// 日本時間では11月20日
+	uc.SetClock(sharedDomain.FixedClock(time.Date(2025, 11, 19, 20, 0, 0, 0, time.UTC)))
+	uc.SetMonthStartDay(25)
+	ctx := sharedDomain.WithLocation(context.Background(), tokyo)
+
+	tests := []struct {
+		name          string
+		monthStartDay int
+		wantMonth     int
+	}{
+		{name: "開始日より前は前月", monthStartDay: 0, wantMonth: 10},
+		{name: "開始日以降は当月", monthStartDay: 20, wantMonth: 11},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			year, month := uc.CurrentMonth(ctx, tt.monthStartDay)
+			if year != 2025 || month != tt.wantMonth {
+				t.Errorf("CurrentMonth() = %d-%02d, want 2025-%02d", year, month, tt.wantMonth)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// ExportRow エクスポートする明細の1行（明細のないレシートはレシート全体を1行とする）
+type ExportRow struct {
+	Date          time.Time
+	ReceiptID     string
+	StoreName     string
+	PaymentMethod string
+	ItemName      string
+	Quantity      int
+	Price         int64
+	Amount        int64 // 単価×数量（明細のないレシートは支払額）
+	Category      string
+}
+
+// ExportUseCase レシートの明細をCSV・xlsxなどに書き出すユースケース
+type ExportUseCase struct {
+	receiptRepo repository.ReceiptRepository
+	currency    sharedDomain.Currency
+}
+
+// NewExportUseCase 新しいExportUseCaseを作成
+func NewExportUseCase(receiptRepo repository.ReceiptRepository) *ExportUseCase {
+	return &ExportUseCase{
+		receiptRepo: receiptRepo,
+		currency:    sharedDomain.DefaultCurrency,
+	}
+}
+
+// SetCurrency 金額の通貨を設定する
+func (uc *ExportUseCase) SetCurrency(currency sharedDomain.Currency) {
+	uc.currency = currency
+}
+
+// Currency 書き出す金額の通貨（単価・金額は通貨の最小単位のため、書き出す際に通貨の単位に直す）
+func (uc *ExportUseCase) Currency() sharedDomain.Currency {
+	return uc.currency
+}
+
+// ForEachRow 指定年（monthが1〜12の場合はその月のみ）のレシートの明細を購入日の古い順に1行ずつfnに渡す
+// レシートは一定件数ごとに読み込むため、期間が長くても全件をメモリに保持しない。fnがエラーを返した場合はそのエラーで終了する
+func (uc *ExportUseCase) ForEachRow(ctx context.Context, year, month int, fn func(ExportRow) error) error {
+	loc := sharedDomain.LocationFromContext(ctx)
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	end := start.AddDate(1, 0, 0)
+	if month >= 1 && month <= 12 {
+		start = time.Date(year, time.Month(month), 1, 0, 0, 0, 0, loc)
+		end = start.AddDate(0, 1, 0)
+	}
+
+	return uc.receiptRepo.ForEachByDateRange(ctx, start, end.Add(-time.Nanosecond), func(receipt *entity.Receipt) error {
+		return forEachExportRow(receipt, loc, fn)
+	})
+}
+
+// forEachExportRow レシートの明細を1行ずつfnに渡す（明細のないレシートはレシート全体を1行とする）
+func forEachExportRow(receipt *entity.Receipt, loc *time.Location, fn func(ExportRow) error) error {
+	row := ExportRow{
+		Date:          receipt.PurchaseDate.In(loc),
+		ReceiptID:     receipt.ID,
+		StoreName:     receipt.StoreName,
+		PaymentMethod: receipt.PaymentMethod,
+	}
+	if len(receipt.Items) == 0 {
+		row.Amount = receipt.TotalAmount
+		row.Category = receipt.Category
+		return fn(row)
+	}
+
+	for _, item := range receipt.Items {
+		row.ItemName = item.Name
+		row.Quantity = item.Quantity
+		row.Price = item.Price
+		row.Amount = item.Amount()
+		row.Category = item.Category
+		if row.Category == "" {
+			row.Category = receipt.Category
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
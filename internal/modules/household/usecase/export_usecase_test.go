@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+func TestExportUseCase_ForEachRow(t *testing.T) {
+	receipts := []*entity.Receipt{
+		{
+			ID: "r1", StoreName: "スーパーA", PaymentMethod: "現金", Category: "食費",
+			PurchaseDate: time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC),
+			Items: []entity.ReceiptItem{
+				{Name: "牛乳", Quantity: 2, Price: 200, Category: "食費"},
+				// カテゴリーのない明細はレシートのカテゴリー
+				{Name: "パン", Quantity: 1, Price: 150},
+			},
+		},
+		{
+			ID: "r2", StoreName: "タクシー", Category: "交通費", TotalAmount: 1800,
+			PurchaseDate: time.Date(2025, 6, 2, 23, 0, 0, 0, time.UTC),
+		},
+	}
+
+	tests := []struct {
+		name      string
+		month     int
+		fnErr     error
+		wantStart time.Time
+		wantEnd   time.Time
+		wantRows  []ExportRow
+		wantErr   error
+	}{
+		{
+			name:      "正常系: 明細ごとに1行、明細のないレシートは支払額を1行",
+			month:     6,
+			wantStart: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond),
+			wantRows: []ExportRow{
+				{ReceiptID: "r1", StoreName: "スーパーA", PaymentMethod: "現金", ItemName: "牛乳", Quantity: 2, Price: 200, Amount: 400, Category: "食費"},
+				{ReceiptID: "r1", StoreName: "スーパーA", PaymentMethod: "現金", ItemName: "パン", Quantity: 1, Price: 150, Amount: 150, Category: "食費"},
+				{ReceiptID: "r2", StoreName: "タクシー", Amount: 1800, Category: "交通費"},
+			},
+		},
+		{
+			name:      "正常系: monthを省略すると年全体",
+			wantStart: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond),
+			wantRows:  make([]ExportRow, 3),
+		},
+		{
+			name:      "異常系: 書き出しのエラーで終了",
+			month:     6,
+			fnErr:     errors.New("write error"),
+			wantStart: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond),
+			wantRows:  make([]ExportRow, 1),
+			wantErr:   errors.New("write error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotStart, gotEnd time.Time
+			repo := &MockReceiptRepository{
+				FindByDateRangeFunc: func(ctx context.Context, start, end time.Time) ([]*entity.Receipt, error) {
+					gotStart, gotEnd = start, end
+					return receipts, nil
+				},
+			}
+			uc := NewExportUseCase(repo)
+
+			var rows []ExportRow
+			err := uc.ForEachRow(sharedDomain.WithLocation(context.Background(), time.UTC), 2025, tt.month, func(row ExportRow) error {
+				rows = append(rows, row)
+				return tt.fnErr
+			})
+			if (err != nil) != (tt.wantErr != nil) || (err != nil && err.Error() != tt.wantErr.Error()) {
+				t.Fatalf("ForEachRow() error = %v, want %v", err, tt.wantErr)
+			}
+			if !gotStart.Equal(tt.wantStart) || !gotEnd.Equal(tt.wantEnd) {
+				t.Errorf("ForEachByDateRange(%v, %v), want (%v, %v)", gotStart, gotEnd, tt.wantStart, tt.wantEnd)
+			}
+			if len(rows) != len(tt.wantRows) {
+				t.Fatalf("ForEachRow() = %d rows, want %d", len(rows), len(tt.wantRows))
+			}
+			for i, want := range tt.wantRows {
+				if want.ReceiptID == "" {
+					continue
+				}
+				got := rows[i]
+				got.Date = time.Time{}
+				if got != want {
+					t.Errorf("rows[%d] = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
@@ -2,8 +2,14 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
 
+	"vision-api-app/internal/modules/household/domain/entity"
 	"vision-api-app/internal/modules/household/domain/repository"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
 )
 
 // CategorySummary カテゴリ別集計結果
@@ -13,24 +19,117 @@ type CategorySummary struct {
 	Total    int64 // オーバーフロー対策のためint64を使用
 }
 
+// MaxMonthStartDay 月の開始日に指定できる最大の日（すべての月に存在する日に限る）
+const MaxMonthStartDay = 28
+
+// resolveMonthStartDay 月の開始日の指定を解決する（範囲外・0の場合は設定の開始日configured）
+func resolveMonthStartDay(monthStartDay, configured int) int {
+	if monthStartDay < 1 || monthStartDay > MaxMonthStartDay {
+		return configured
+	}
+	return monthStartDay
+}
+
+// monthStart year年month月の集計期間の開始日時（monthStartDay日の0時）
+// 月はmonthStartDay日から翌月のmonthStartDay日の前日までとし、開始日を含む月で表す
+func monthStart(year int, month time.Month, monthStartDay int, loc *time.Location) time.Time {
+	return time.Date(year, month, monthStartDay, 0, 0, 0, 0, loc)
+}
+
+// monthStartOf tを含む月の集計期間の開始日時（tのタイムゾーン）
+func monthStartOf(t time.Time, monthStartDay int) time.Time {
+	start := monthStart(t.Year(), t.Month(), monthStartDay, t.Location())
+	if t.Before(start) {
+		start = start.AddDate(0, -1, 0)
+	}
+	return start
+}
+
+// 期間別集計の単位
+const (
+	ReportGroupDay   = "day"
+	ReportGroupWeek  = "week"  // 月曜始まり
+	ReportGroupMonth = "month" // 月の開始日（reports.month_start_day）始まり
+)
+
+// MaxReportPeriods 期間別集計で一度に返す期間数の上限
+const MaxReportPeriods = 1000
+
+// ErrInvalidReportPeriod 期間別集計の期間・単位が不正
+var ErrInvalidReportPeriod = errors.New("invalid report period")
+
+// MonthlySummary 月別の集計結果
+type MonthlySummary struct {
+	Month      int       // 1〜12（月の開始日を含む月）
+	Start      time.Time // 集計期間の開始日時
+	End        time.Time // 集計期間の終了日時（この日時を含まない）
+	Total      int64
+	Categories []CategorySummary // 金額の大きい順
+}
+
+// PeriodSummary 期間ごとの集計結果
+type PeriodSummary struct {
+	Start      time.Time // 期間の開始日時
+	End        time.Time // 期間の終了日時（この日時を含まない）
+	Total      int64
+	Categories []CategorySummary // 金額の大きい順
+}
+
+// DaySummary 日ごとのレシートの集計結果（カレンダー表示用）
+type DaySummary struct {
+	Date  time.Time // その日の0時（タイムゾーンの暦日）
+	Count int       // レシートの枚数
+	Total int64     // レシートの合計金額の合計
+}
+
 // HouseholdUseCase 家計簿集計のユースケース
 type HouseholdUseCase struct {
-	receiptRepo repository.ReceiptRepository
-	expenseRepo repository.ExpenseRepository
+	receiptRepo   repository.ReceiptRepository
+	expenseRepo   repository.ExpenseRepository
+	aggregateRepo repository.AggregateRepository
+	monthStartDay int
 }
 
 // NewHouseholdUseCase 新しいHouseholdUseCaseを作成
 func NewHouseholdUseCase(receiptRepo repository.ReceiptRepository, expenseRepo repository.ExpenseRepository) *HouseholdUseCase {
 	return &HouseholdUseCase{
-		receiptRepo: receiptRepo,
-		expenseRepo: expenseRepo,
+		receiptRepo:   receiptRepo,
+		expenseRepo:   expenseRepo,
+		monthStartDay: 1,
 	}
 }
 
+// SetMonthStartDay 月別集計の月の開始日（給料日など）を設定する
+// 範囲外（1〜MaxMonthStartDay以外）の場合は1日とする
+func (uc *HouseholdUseCase) SetMonthStartDay(day int) {
+	if day < 1 || day > MaxMonthStartDay {
+		day = 1
+	}
+	uc.monthStartDay = day
+}
+
+// SetAggregateRepository 期間別集計をデータベースで集計するリポジトリを設定する
+// 未設定の場合はレシート・家計簿エントリを取得してメモリ上で集計する
+func (uc *HouseholdUseCase) SetAggregateRepository(repo repository.AggregateRepository) {
+	uc.aggregateRepo = repo
+}
+
 // GetCategorySummary カテゴリ別集計を取得（明細項目ベース + expense_entries）
 func (uc *HouseholdUseCase) GetCategorySummary(ctx context.Context) ([]CategorySummary, error) {
+	return uc.GetCategorySummaryByTag(ctx, "")
+}
+
+// GetCategorySummaryByTag 指定タグのレシート・家計簿エントリのみでカテゴリ別集計を取得
+// タグが空の場合は全件を集計する
+func (uc *HouseholdUseCase) GetCategorySummaryByTag(ctx context.Context, tag string) ([]CategorySummary, error) {
 	// レシート一覧を取得
-	receipts, err := uc.receiptRepo.FindAll(ctx, 0, 0)
+	var receipts []*entity.Receipt
+	var err error
+	if tag == "" {
+		receipts, err = uc.receiptRepo.FindAll(ctx, 0, 0)
+	} else {
+		receipts, err = uc.receiptRepo.FindByFilter(ctx, repository.ReceiptFilter{Tag: tag}, 0, 0)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -69,6 +168,9 @@ func (uc *HouseholdUseCase) GetCategorySummary(ctx context.Context) ([]CategoryS
 		if expense.Category == "" {
 			continue
 		}
+		if tag != "" && !expense.HasTag(tag) {
+			continue
+		}
 		if _, exists := summaryMap[expense.Category]; !exists {
 			summaryMap[expense.Category] = &CategorySummary{
 				Category: expense.Category,
@@ -88,3 +190,224 @@ func (uc *HouseholdUseCase) GetCategorySummary(ctx context.Context) ([]CategoryS
 
 	return summaries, nil
 }
+
+// GetMonthlySummary 指定年の月別・カテゴリ別集計を取得（明細項目ベース + expense_entries）
+// 支出のない月も含めて12か月分を返す。月はmonthStartDay日から翌月のmonthStartDay日の前日までとし、
+// 開始日を含む月で表す（例: 25日始まりの1月は1月25日〜2月24日）。monthStartDayが0の場合は設定の開始日を使う
+func (uc *HouseholdUseCase) GetMonthlySummary(ctx context.Context, year, monthStartDay int) ([]MonthlySummary, error) {
+	monthStartDay = resolveMonthStartDay(monthStartDay, uc.monthStartDay)
+	start := monthStart(year, time.January, monthStartDay, sharedDomain.LocationFromContext(ctx))
+
+	starts := make([]time.Time, 12)
+	for i := range starts {
+		starts[i] = start.AddDate(0, i, 0)
+	}
+	periods, err := uc.summarizePeriods(ctx, starts, start.AddDate(1, 0, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]MonthlySummary, 0, len(periods))
+	for i, period := range periods {
+		summaries = append(summaries, MonthlySummary{
+			Month:      i + 1,
+			Start:      period.Start,
+			End:        period.End,
+			Total:      period.Total,
+			Categories: period.Categories,
+		})
+	}
+	return summaries, nil
+}
+
+// GetPeriodSummary fromからtoまで（両端の日を含む）の支出をgroupBy（day / week / month）ごとに集計
+// 週は月曜始まり、月はmonthStartDay日始まり（0の場合は設定の開始日）とし、先頭・末尾の期間はfrom・toで区切る。
+// 支出のない期間も含めて返す
+func (uc *HouseholdUseCase) GetPeriodSummary(ctx context.Context, from, to time.Time, groupBy string, monthStartDay int) ([]PeriodSummary, error) {
+	starts, end, err := uc.periodStarts(ctx, from, to, groupBy, monthStartDay)
+	if err != nil {
+		return nil, err
+	}
+	return uc.summarizePeriods(ctx, starts, end)
+}
+
+// periodStarts fromからtoまで（両端の日を含む）をgroupBy（day / week / month）ごとに区切り、各期間の開始日時と最後の期間の終了日時を返す
+func (uc *HouseholdUseCase) periodStarts(ctx context.Context, from, to time.Time, groupBy string, monthStartDay int) ([]time.Time, time.Time, error) {
+	monthStartDay = resolveMonthStartDay(monthStartDay, uc.monthStartDay)
+	loc := sharedDomain.LocationFromContext(ctx)
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	end := time.Date(to.Year(), to.Month(), to.Day()+1, 0, 0, 0, 0, loc)
+	if !from.Before(end) {
+		return nil, time.Time{}, fmt.Errorf("%w: from must not be after to", ErrInvalidReportPeriod)
+	}
+
+	var align func(t time.Time) time.Time
+	var next func(t time.Time) time.Time
+	switch groupBy {
+	case ReportGroupDay:
+		align = func(t time.Time) time.Time { return t }
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+	case ReportGroupWeek:
+		align = func(t time.Time) time.Time { return t.AddDate(0, 0, -(int(t.Weekday())+6)%7) }
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }
+	case ReportGroupMonth:
+		align = func(t time.Time) time.Time { return monthStartOf(t, monthStartDay) }
+		next = func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }
+	default:
+		return nil, time.Time{}, fmt.Errorf("%w: unknown group_by %q", ErrInvalidReportPeriod, groupBy)
+	}
+
+	starts := []time.Time{from}
+	for t := next(align(from)); t.Before(end); t = next(t) {
+		if len(starts) >= MaxReportPeriods {
+			return nil, time.Time{}, fmt.Errorf("%w: more than %d periods", ErrInvalidReportPeriod, MaxReportPeriods)
+		}
+		starts = append(starts, t)
+	}
+	return starts, end, nil
+}
+
+// GetCalendar 指定年月の日ごとのレシートの枚数と合計金額を取得（monthが0の場合は年全体）
+// 日の区切りはコンテキストのタイムゾーンの暦日とし、レシートのない日も含めて返す
+func (uc *HouseholdUseCase) GetCalendar(ctx context.Context, year, month int) ([]DaySummary, error) {
+	loc := sharedDomain.LocationFromContext(ctx)
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	end := start.AddDate(1, 0, 0)
+	if month != 0 {
+		start = time.Date(year, time.Month(month), 1, 0, 0, 0, 0, loc)
+		end = start.AddDate(0, 1, 0)
+	}
+
+	days := make([]DaySummary, 0, 366)
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		days = append(days, DaySummary{Date: d})
+	}
+	// dayOf 日時を含む日の番号（範囲外は-1）
+	dayOf := func(t time.Time) int {
+		if t.Before(start) || !t.Before(end) {
+			return -1
+		}
+		return sort.Search(len(days), func(i int) bool { return days[i].Date.After(t) }) - 1
+	}
+	add := func(t time.Time, count int, total int64) {
+		if i := dayOf(t); i >= 0 {
+			days[i].Count += count
+			days[i].Total += total
+		}
+	}
+
+	if uc.aggregateRepo != nil {
+		// データベースで集計単位の時間帯ごとに合計し、時間帯をタイムゾーンの日に振り分ける
+		amounts, err := uc.aggregateRepo.SumReceipts(ctx, start, end.Add(-time.Nanosecond))
+		if err != nil {
+			return nil, fmt.Errorf("failed to sum receipts: %w", err)
+		}
+		for _, amount := range amounts {
+			add(amount.Time, amount.Count, amount.Total)
+		}
+	} else {
+		receipts, err := uc.receiptRepo.FindByDateRange(ctx, start, end.Add(-time.Nanosecond))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get receipts: %w", err)
+		}
+		for _, receipt := range receipts {
+			add(receipt.PurchaseDate, 1, int64(receipt.TotalAmount))
+		}
+	}
+
+	return days, nil
+}
+
+// summarizePeriods starts[i]からstarts[i+1]（最後はend）までの期間ごとにカテゴリ別集計（明細項目ベース + expense_entries）
+func (uc *HouseholdUseCase) summarizePeriods(ctx context.Context, starts []time.Time, end time.Time) ([]PeriodSummary, error) {
+	// periodOf 日時を含む期間の番号（範囲外は-1）
+	periodOf := func(t time.Time) int {
+		if t.Before(starts[0]) || !t.Before(end) {
+			return -1
+		}
+		return sort.Search(len(starts), func(i int) bool { return starts[i].After(t) }) - 1
+	}
+
+	// 期間ごとのカテゴリ別集計
+	buckets := make([]map[string]*CategorySummary, len(starts))
+	for i := range buckets {
+		buckets[i] = make(map[string]*CategorySummary)
+	}
+	add := func(period int, category string, count int, amount int64) {
+		if period < 0 {
+			return
+		}
+		if category == "" {
+			category = "その他"
+		}
+		summaryMap := buckets[period]
+		if _, exists := summaryMap[category]; !exists {
+			summaryMap[category] = &CategorySummary{Category: category}
+		}
+		summaryMap[category].Count += count
+		summaryMap[category].Total += amount
+	}
+
+	if uc.aggregateRepo != nil {
+		// データベースで集計単位の時間帯ごとに合計し、時間帯を期間に振り分ける
+		// 期間の境界はタイムゾーンの0時で集計単位の倍数のため、時間帯が期間をまたぐことはない
+		items, err := uc.aggregateRepo.SumItemsByCategory(ctx, starts[0], end.Add(-time.Nanosecond))
+		if err != nil {
+			return nil, fmt.Errorf("failed to sum receipt items: %w", err)
+		}
+		expenses, err := uc.aggregateRepo.SumExpensesByCategory(ctx, starts[0], end.Add(-time.Nanosecond))
+		if err != nil {
+			return nil, fmt.Errorf("failed to sum expense entries: %w", err)
+		}
+		for _, amount := range append(items, expenses...) {
+			add(periodOf(amount.Time), amount.Category, amount.Count, amount.Total)
+		}
+	} else {
+		receipts, err := uc.receiptRepo.FindByDateRange(ctx, starts[0], end.Add(-time.Nanosecond))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get receipts: %w", err)
+		}
+		expenses, err := uc.expenseRepo.FindByDateRange(ctx, starts[0], end.Add(-time.Nanosecond))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get expense entries: %w", err)
+		}
+
+		for _, receipt := range receipts {
+			period := periodOf(receipt.PurchaseDate)
+			for _, item := range receipt.Items {
+				add(period, item.Category, 1, int64(item.Price)*int64(item.Quantity))
+			}
+		}
+		for _, expense := range expenses {
+			if expense.Category == "" {
+				continue
+			}
+			add(periodOf(expense.Date), expense.Category, 1, int64(expense.Amount))
+		}
+	}
+
+	summaries := make([]PeriodSummary, 0, len(starts))
+	for i, summaryMap := range buckets {
+		summary := PeriodSummary{
+			Start:      starts[i],
+			End:        end,
+			Categories: make([]CategorySummary, 0, len(summaryMap)),
+		}
+		if i+1 < len(starts) {
+			summary.End = starts[i+1]
+		}
+		for _, category := range summaryMap {
+			summary.Categories = append(summary.Categories, *category)
+			summary.Total += category.Total
+		}
+		sort.Slice(summary.Categories, func(a, b int) bool {
+			if summary.Categories[a].Total != summary.Categories[b].Total {
+				return summary.Categories[a].Total > summary.Categories[b].Total
+			}
+			return summary.Categories[a].Category < summary.Categories[b].Category
+		})
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
@@ -7,18 +7,33 @@ import (
 	"time"
 
 	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
 )
 
 // MockExpenseRepository モック家計簿リポジトリ
 type MockExpenseRepository struct {
-	FindAllFunc func(ctx context.Context, limit, offset int) ([]*entity.ExpenseEntry, error)
+	FindAllFunc  func(ctx context.Context, limit, offset int) ([]*entity.ExpenseEntry, error)
+	FindByIDFunc func(ctx context.Context, id string) (*entity.ExpenseEntry, error)
+	UpdateFunc   func(ctx context.Context, entry *entity.ExpenseEntry) error
+
+	FindByDateRangeFunc func(ctx context.Context, start, end time.Time) ([]*entity.ExpenseEntry, error)
+	FindByReceiptIDFunc func(ctx context.Context, receiptID string) ([]*entity.ExpenseEntry, error)
+	FindByFilterFunc    func(ctx context.Context, filter repository.ExpenseFilter, limit, offset int) ([]*entity.ExpenseEntry, error)
+	CreateFunc          func(ctx context.Context, entry *entity.ExpenseEntry) error
 }
 
 func (m *MockExpenseRepository) Create(ctx context.Context, entry *entity.ExpenseEntry) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, entry)
+	}
 	return errors.New("not implemented")
 }
 
 func (m *MockExpenseRepository) FindByID(ctx context.Context, id string) (*entity.ExpenseEntry, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(ctx, id)
+	}
 	return nil, errors.New("not implemented")
 }
 
@@ -30,6 +45,9 @@ func (m *MockExpenseRepository) FindAll(ctx context.Context, limit, offset int)
 }
 
 func (m *MockExpenseRepository) FindByDateRange(ctx context.Context, start, end time.Time) ([]*entity.ExpenseEntry, error) {
+	if m.FindByDateRangeFunc != nil {
+		return m.FindByDateRangeFunc(ctx, start, end)
+	}
 	return nil, errors.New("not implemented")
 }
 
@@ -37,7 +55,24 @@ func (m *MockExpenseRepository) FindByCategory(ctx context.Context, category str
 	return nil, errors.New("not implemented")
 }
 
+func (m *MockExpenseRepository) FindByFilter(ctx context.Context, filter repository.ExpenseFilter, limit, offset int) ([]*entity.ExpenseEntry, error) {
+	if m.FindByFilterFunc != nil {
+		return m.FindByFilterFunc(ctx, filter, limit, offset)
+	}
+	return []*entity.ExpenseEntry{}, nil
+}
+
+func (m *MockExpenseRepository) FindByReceiptID(ctx context.Context, receiptID string) ([]*entity.ExpenseEntry, error) {
+	if m.FindByReceiptIDFunc != nil {
+		return m.FindByReceiptIDFunc(ctx, receiptID)
+	}
+	return []*entity.ExpenseEntry{}, nil
+}
+
 func (m *MockExpenseRepository) Update(ctx context.Context, entry *entity.ExpenseEntry) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, entry)
+	}
 	return errors.New("not implemented")
 }
 
@@ -266,3 +301,600 @@ func TestHouseholdUseCase_GetCategorySummary_LargeValues(t *testing.T) {
 		t.Errorf("Expected total %d, got %d", expectedTotal, summary[0].Total)
 	}
 }
+
+// TestHouseholdUseCase_GetCategorySummary_Refund 返品のレシートは購入と相殺して集計する
+func TestHouseholdUseCase_GetCategorySummary_Refund(t *testing.T) {
+	mockReceipt := &MockReceiptRepository{
+		FindAllFunc: func(ctx context.Context, limit, offset int) ([]*entity.Receipt, error) {
+			return []*entity.Receipt{
+				{
+					ID:          "purchase",
+					TotalAmount: 5500,
+					Items: []entity.ReceiptItem{
+						{Name: "シャツ", Category: "衣服", Price: 3000, Quantity: 1},
+						{Name: "靴下", Category: "衣服", Price: 500, Quantity: 1},
+						{Name: "弁当", Category: "食費", Price: 2000, Quantity: 1},
+					},
+				},
+				{
+					ID:          "refund",
+					Type:        entity.ReceiptTypeRefund,
+					TotalAmount: -3000,
+					Items: []entity.ReceiptItem{
+						{Name: "シャツ", Category: "衣服", Price: -3000, Quantity: 1},
+					},
+				},
+			}, nil
+		},
+	}
+	mockExpense := &MockExpenseRepository{
+		FindAllFunc: func(ctx context.Context, limit, offset int) ([]*entity.ExpenseEntry, error) {
+			return []*entity.ExpenseEntry{}, nil
+		},
+	}
+
+	uc := NewHouseholdUseCase(mockReceipt, mockExpense)
+	summary, err := uc.GetCategorySummary(context.Background())
+	if err != nil {
+		t.Fatalf("GetCategorySummary() error = %v", err)
+	}
+
+	totals := make(map[string]int64)
+	for _, s := range summary {
+		totals[s.Category] = s.Total
+	}
+	if totals["衣服"] != 500 || totals["食費"] != 2000 {
+		t.Errorf("totals = %v, want 衣服 500 and 食費 2000", totals)
+	}
+}
+
+// TestHouseholdUseCase_GetCategorySummaryByTag タグで絞り込んだ集計のテスト
+func TestHouseholdUseCase_GetCategorySummaryByTag(t *testing.T) {
+	mockReceipt := &MockReceiptRepository{
+		FindByFilterFunc: func(ctx context.Context, filter repository.ReceiptFilter, limit, offset int) ([]*entity.Receipt, error) {
+			if filter.Tag != "旅行" {
+				t.Errorf("FindByFilter() tag = %s, want 旅行", filter.Tag)
+			}
+			return []*entity.Receipt{
+				{ID: "1", Tags: []string{"旅行"}, Items: []entity.ReceiptItem{
+					{Name: "駅弁", Category: "食費", Price: 1200, Quantity: 1},
+				}},
+			}, nil
+		},
+	}
+	mockExpense := &MockExpenseRepository{
+		FindAllFunc: func(ctx context.Context, limit, offset int) ([]*entity.ExpenseEntry, error) {
+			return []*entity.ExpenseEntry{
+				{ID: "e1", Category: "交通費", Amount: 5000, Tags: []string{"旅行"}},
+				{ID: "e2", Category: "交通費", Amount: 300, Tags: []string{}},
+			}, nil
+		},
+	}
+
+	uc := NewHouseholdUseCase(mockReceipt, mockExpense)
+
+	summary, err := uc.GetCategorySummaryByTag(context.Background(), "旅行")
+	if err != nil {
+		t.Fatalf("GetCategorySummaryByTag() error = %v", err)
+	}
+
+	totals := map[string]int64{}
+	for _, s := range summary {
+		totals[s.Category] = s.Total
+	}
+	if len(totals) != 2 || totals["食費"] != 1200 || totals["交通費"] != 5000 {
+		t.Errorf("totals = %v, want 食費=1200, 交通費=5000", totals)
+	}
+}
+
+func TestHouseholdUseCase_GetMonthlySummary(t *testing.T) {
+	receipts := []*entity.Receipt{
+		{
+			ID:           "r1",
+			PurchaseDate: time.Date(2025, 1, 10, 12, 0, 0, 0, time.Local),
+			Items: []entity.ReceiptItem{
+				{Name: "牛乳", Quantity: 2, Price: 200, Category: "食費"},
+				{Name: "洗剤", Quantity: 1, Price: 300, Category: "日用品"},
+			},
+		},
+		{
+			ID:           "r2",
+			PurchaseDate: time.Date(2025, 3, 5, 9, 0, 0, 0, time.Local),
+			Items: []entity.ReceiptItem{
+				{Name: "パン", Quantity: 1, Price: 150},
+			},
+		},
+	}
+	expenses := []*entity.ExpenseEntry{
+		{ID: "e1", Date: time.Date(2025, 1, 20, 0, 0, 0, 0, time.Local), Category: "交通費", Amount: 1000},
+		{ID: "e2", Date: time.Date(2025, 1, 21, 0, 0, 0, 0, time.Local), Category: "", Amount: 500},
+	}
+
+	var gotStart, gotEnd time.Time
+	mockReceipt := &MockReceiptRepository{
+		FindByDateRangeFunc: func(ctx context.Context, start, end time.Time) ([]*entity.Receipt, error) {
+			gotStart, gotEnd = start, end
+			return receipts, nil
+		},
+	}
+	mockExpense := &MockExpenseRepository{
+		FindByDateRangeFunc: func(ctx context.Context, start, end time.Time) ([]*entity.ExpenseEntry, error) {
+			return expenses, nil
+		},
+	}
+
+	uc := NewHouseholdUseCase(mockReceipt, mockExpense)
+	summaries, err := uc.GetMonthlySummary(context.Background(), 2025, 0)
+	if err != nil {
+		t.Fatalf("GetMonthlySummary() error = %v", err)
+	}
+
+	if gotStart.Year() != 2025 || gotStart.Month() != time.January || gotEnd.Year() != 2025 || gotEnd.Month() != time.December {
+		t.Errorf("date range = %v - %v, want 2025", gotStart, gotEnd)
+	}
+	if len(summaries) != 12 {
+		t.Fatalf("len(summaries) = %d, want 12", len(summaries))
+	}
+
+	jan := summaries[0]
+	if jan.Month != 1 || jan.Total != 1700 {
+		t.Errorf("January = %d/%d, want 1/1700", jan.Month, jan.Total)
+	}
+	// 金額の大きい順
+	wantCategories := []string{"交通費", "食費", "日用品"}
+	if len(jan.Categories) != len(wantCategories) {
+		t.Fatalf("January categories = %+v", jan.Categories)
+	}
+	for i, want := range wantCategories {
+		if jan.Categories[i].Category != want {
+			t.Errorf("January categories[%d] = %s, want %s", i, jan.Categories[i].Category, want)
+		}
+	}
+
+	if summaries[1].Total != 0 || len(summaries[1].Categories) != 0 {
+		t.Errorf("February = %+v, want empty", summaries[1])
+	}
+	if mar := summaries[2]; mar.Total != 150 || mar.Categories[0].Category != "その他" {
+		t.Errorf("March = %+v, want その他 150", mar)
+	}
+}
+
+func TestHouseholdUseCase_GetMonthlySummary_Location(t *testing.T) {
+	// UTCでは1月31日、日本時間では2月1日の購入
+	jst := time.FixedZone("JST", 9*60*60)
+	receipts := []*entity.Receipt{
+		{
+			ID:           "r1",
+			PurchaseDate: time.Date(2025, 1, 31, 16, 0, 0, 0, time.UTC),
+			Items:        []entity.ReceiptItem{{Name: "牛乳", Quantity: 1, Price: 200, Category: "食費"}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		loc       *time.Location
+		wantMonth int
+		wantStart time.Time
+	}{
+		{name: "正常系: UTCの暦日で集計", loc: time.UTC, wantMonth: 1, wantStart: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "正常系: 日本時間の暦日で集計", loc: jst, wantMonth: 2, wantStart: time.Date(2025, 1, 1, 0, 0, 0, 0, jst)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotStart time.Time
+			mockReceipt := &MockReceiptRepository{
+				FindByDateRangeFunc: func(ctx context.Context, start, end time.Time) ([]*entity.Receipt, error) {
+					gotStart = start
+					return receipts, nil
+				},
+			}
+			mockExpense := &MockExpenseRepository{
+				FindByDateRangeFunc: func(ctx context.Context, start, end time.Time) ([]*entity.ExpenseEntry, error) {
+					return nil, nil
+				},
+			}
+			uc := NewHouseholdUseCase(mockReceipt, mockExpense)
+
+			summaries, err := uc.GetMonthlySummary(sharedDomain.WithLocation(context.Background(), tt.loc), 2025, 0)
+			if err != nil {
+				t.Fatalf("GetMonthlySummary() error = %v", err)
+			}
+			if !gotStart.Equal(tt.wantStart) {
+				t.Errorf("start = %v, want %v", gotStart, tt.wantStart)
+			}
+			if summaries[tt.wantMonth-1].Total != 200 {
+				t.Errorf("month %d total = %d, want 200", tt.wantMonth, summaries[tt.wantMonth-1].Total)
+			}
+		})
+	}
+}
+
+func TestHouseholdUseCase_GetMonthlySummary_MonthStartDay(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 12, 0, 0, 0, time.UTC)
+	}
+	receipts := []*entity.Receipt{
+		// 25日始まりでは前年の12月分（集計対象外）
+		{ID: "r0", PurchaseDate: day(2025, 1, 24), Items: []entity.ReceiptItem{{Name: "A", Quantity: 1, Price: 100, Category: "食費"}}},
+		{ID: "r1", PurchaseDate: day(2025, 1, 25), Items: []entity.ReceiptItem{{Name: "B", Quantity: 1, Price: 200, Category: "食費"}}},
+		{ID: "r2", PurchaseDate: day(2025, 2, 24), Items: []entity.ReceiptItem{{Name: "C", Quantity: 1, Price: 300, Category: "食費"}}},
+		{ID: "r3", PurchaseDate: day(2026, 1, 10), Items: []entity.ReceiptItem{{Name: "D", Quantity: 1, Price: 400, Category: "食費"}}},
+	}
+
+	tests := []struct {
+		name          string
+		configured    int
+		monthStartDay int
+		wantStart     time.Time
+		wantTotals    map[int]int64 // 月 → 合計
+	}{
+		{
+			name:       "正常系: 設定の開始日で集計",
+			configured: 25,
+			wantStart:  time.Date(2025, 1, 25, 0, 0, 0, 0, time.UTC),
+			wantTotals: map[int]int64{1: 500, 12: 400},
+		},
+		{
+			name:          "正常系: リクエストの開始日が設定より優先",
+			configured:    25,
+			monthStartDay: 1,
+			wantStart:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantTotals:    map[int]int64{1: 300, 2: 300},
+		},
+		{
+			name:       "正常系: 範囲外の設定は1日始まり",
+			configured: 31,
+			wantStart:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantTotals: map[int]int64{1: 300, 2: 300},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotStart time.Time
+			mockReceipt := &MockReceiptRepository{
+				FindByDateRangeFunc: func(ctx context.Context, start, end time.Time) ([]*entity.Receipt, error) {
+					gotStart = start
+					return receipts, nil
+				},
+			}
+			mockExpense := &MockExpenseRepository{
+				FindByDateRangeFunc: func(ctx context.Context, start, end time.Time) ([]*entity.ExpenseEntry, error) {
+					return nil, nil
+				},
+			}
+			uc := NewHouseholdUseCase(mockReceipt, mockExpense)
+			uc.SetMonthStartDay(tt.configured)
+
+			summaries, err := uc.GetMonthlySummary(sharedDomain.WithLocation(context.Background(), time.UTC), 2025, tt.monthStartDay)
+			if err != nil {
+				t.Fatalf("GetMonthlySummary() error = %v", err)
+			}
+			if !gotStart.Equal(tt.wantStart) {
+				t.Errorf("start = %v, want %v", gotStart, tt.wantStart)
+			}
+			if !summaries[0].Start.Equal(tt.wantStart) || !summaries[0].End.Equal(tt.wantStart.AddDate(0, 1, 0)) {
+				t.Errorf("January period = %v - %v", summaries[0].Start, summaries[0].End)
+			}
+			for _, summary := range summaries {
+				if summary.Total != tt.wantTotals[summary.Month] {
+					t.Errorf("month %d total = %d, want %d", summary.Month, summary.Total, tt.wantTotals[summary.Month])
+				}
+			}
+		})
+	}
+}
+
+func TestHouseholdUseCase_GetPeriodSummary(t *testing.T) {
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2025, month, d, 12, 0, 0, 0, time.UTC)
+	}
+	receipts := []*entity.Receipt{
+		{ID: "r1", PurchaseDate: day(6, 2), Items: []entity.ReceiptItem{{Name: "A", Quantity: 1, Price: 100, Category: "食費"}}},
+		{ID: "r2", PurchaseDate: day(6, 8), Items: []entity.ReceiptItem{{Name: "B", Quantity: 2, Price: 150, Category: "日用品"}}},
+		{ID: "r3", PurchaseDate: day(6, 9), Items: []entity.ReceiptItem{{Name: "C", Quantity: 1, Price: 500, Category: "食費"}}},
+		{ID: "r4", PurchaseDate: day(7, 1), Items: []entity.ReceiptItem{{Name: "D", Quantity: 1, Price: 700, Category: "食費"}}},
+	}
+	expenses := []*entity.ExpenseEntry{
+		{ID: "e1", Date: day(6, 4), Category: "交通費", Amount: 220},
+	}
+
+	tests := []struct {
+		name          string
+		from          time.Time
+		to            time.Time
+		groupBy       string
+		monthStartDay int
+		wantStarts    []string
+		wantTotals    []int64
+		wantErr       bool
+	}{
+		{
+			name:       "正常系: 週ごと（月曜始まり、先頭と末尾はfrom・toで区切る）",
+			from:       day(6, 4),
+			to:         day(6, 18),
+			groupBy:    ReportGroupWeek,
+			wantStarts: []string{"2025-06-04", "2025-06-09", "2025-06-16"},
+			wantTotals: []int64{520, 500, 0},
+		},
+		{
+			name:       "正常系: 日ごと",
+			from:       day(6, 1),
+			to:         day(6, 3),
+			groupBy:    ReportGroupDay,
+			wantStarts: []string{"2025-06-01", "2025-06-02", "2025-06-03"},
+			wantTotals: []int64{0, 100, 0},
+		},
+		{
+			name:          "正常系: 月ごと（開始日を指定）",
+			from:          day(5, 20),
+			to:            day(7, 10),
+			groupBy:       ReportGroupMonth,
+			monthStartDay: 5,
+			wantStarts:    []string{"2025-05-20", "2025-06-05", "2025-07-05"},
+			wantTotals:    []int64{320, 1500, 0},
+		},
+		{
+			name:    "異常系: 不明な単位",
+			from:    day(6, 1),
+			to:      day(6, 30),
+			groupBy: "year",
+			wantErr: true,
+		},
+		{
+			name:    "異常系: fromがtoより後",
+			from:    day(6, 30),
+			to:      day(6, 1),
+			groupBy: ReportGroupDay,
+			wantErr: true,
+		},
+		{
+			name:    "異常系: 期間数が上限を超える",
+			from:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:      time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+			groupBy: ReportGroupDay,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockReceipt := &MockReceiptRepository{
+				FindByDateRangeFunc: func(ctx context.Context, start, end time.Time) ([]*entity.Receipt, error) {
+					return receipts, nil
+				},
+			}
+			mockExpense := &MockExpenseRepository{
+				FindByDateRangeFunc: func(ctx context.Context, start, end time.Time) ([]*entity.ExpenseEntry, error) {
+					return expenses, nil
+				},
+			}
+			uc := NewHouseholdUseCase(mockReceipt, mockExpense)
+
+			summaries, err := uc.GetPeriodSummary(sharedDomain.WithLocation(context.Background(), time.UTC), tt.from, tt.to, tt.groupBy, tt.monthStartDay)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidReportPeriod) {
+					t.Fatalf("GetPeriodSummary() error = %v, want ErrInvalidReportPeriod", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetPeriodSummary() error = %v", err)
+			}
+			if len(summaries) != len(tt.wantStarts) {
+				t.Fatalf("GetPeriodSummary() = %d periods, want %d", len(summaries), len(tt.wantStarts))
+			}
+			for i, summary := range summaries {
+				if got := summary.Start.Format(time.DateOnly); got != tt.wantStarts[i] {
+					t.Errorf("periods[%d].Start = %s, want %s", i, got, tt.wantStarts[i])
+				}
+				if summary.Total != tt.wantTotals[i] {
+					t.Errorf("periods[%d].Total = %d, want %d", i, summary.Total, tt.wantTotals[i])
+				}
+			}
+			// 最後の期間はtoの翌日0時で終わる
+			wantEnd := time.Date(tt.to.Year(), tt.to.Month(), tt.to.Day()+1, 0, 0, 0, 0, time.UTC)
+			if last := summaries[len(summaries)-1]; !last.End.Equal(wantEnd) {
+				t.Errorf("last End = %v, want %v", last.End, wantEnd)
+			}
+		})
+	}
+}
+
+// MockAggregateRepository モック集計リポジトリ
+type MockAggregateRepository struct {
+	SumItemsByCategoryFunc    func(ctx context.Context, start, end time.Time) ([]*entity.CategoryAmount, error)
+	SumExpensesByCategoryFunc func(ctx context.Context, start, end time.Time) ([]*entity.CategoryAmount, error)
+	SumReceiptsFunc           func(ctx context.Context, start, end time.Time) ([]*entity.ReceiptAmount, error)
+	SumByCategoryFunc         func(ctx context.Context, start, end time.Time) ([]*entity.CategoryBreakdown, error)
+}
+
+func (m *MockAggregateRepository) SumItemsByCategory(ctx context.Context, start, end time.Time) ([]*entity.CategoryAmount, error) {
+	if m.SumItemsByCategoryFunc != nil {
+		return m.SumItemsByCategoryFunc(ctx, start, end)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockAggregateRepository) SumExpensesByCategory(ctx context.Context, start, end time.Time) ([]*entity.CategoryAmount, error) {
+	if m.SumExpensesByCategoryFunc != nil {
+		return m.SumExpensesByCategoryFunc(ctx, start, end)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockAggregateRepository) SumReceipts(ctx context.Context, start, end time.Time) ([]*entity.ReceiptAmount, error) {
+	if m.SumReceiptsFunc != nil {
+		return m.SumReceiptsFunc(ctx, start, end)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockAggregateRepository) SumByCategory(ctx context.Context, start, end time.Time) ([]*entity.CategoryBreakdown, error) {
+	if m.SumByCategoryFunc != nil {
+		return m.SumByCategoryFunc(ctx, start, end)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func TestHouseholdUseCase_GetPeriodSummary_Aggregate(t *testing.T) {
+	tokyo := time.FixedZone("Asia/Tokyo", 9*60*60)
+	// データベースはUTCの時間帯で返す（日本時間の6/2 0:00はUTCの6/1 15:00）
+	bucket := func(d, hour, minute int) time.Time {
+		return time.Date(2025, 6, d, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name       string
+		items      []*entity.CategoryAmount
+		expenses   []*entity.CategoryAmount
+		itemsErr   error
+		wantTotals []int64
+		wantFirst  []CategorySummary
+		wantErr    bool
+	}{
+		{
+			name: "正常系: 時間帯ごとの合計をタイムゾーンの日に振り分ける",
+			items: []*entity.CategoryAmount{
+				{Time: bucket(1, 14, 45), Category: "食費", Count: 2, Total: 300},
+				{Time: bucket(1, 15, 0), Category: "食費", Count: 1, Total: 500},
+				{Time: bucket(1, 15, 0), Category: "", Count: 1, Total: 80},
+			},
+			expenses: []*entity.CategoryAmount{
+				{Time: bucket(2, 3, 15), Category: "食費", Count: 1, Total: 200},
+			},
+			wantTotals: []int64{300, 780},
+			wantFirst:  []CategorySummary{{Category: "食費", Count: 2, Total: 300}},
+		},
+		{
+			name:     "異常系: 集計に失敗",
+			itemsErr: errors.New("db error"),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockReceipt := &MockReceiptRepository{
+				FindByDateRangeFunc: func(ctx context.Context, start, end time.Time) ([]*entity.Receipt, error) {
+					t.Error("FindByDateRange() should not be called when the aggregate repository is set")
+					return nil, nil
+				},
+			}
+			var gotStart, gotEnd time.Time
+			mockAggregate := &MockAggregateRepository{
+				SumItemsByCategoryFunc: func(ctx context.Context, start, end time.Time) ([]*entity.CategoryAmount, error) {
+					gotStart, gotEnd = start, end
+					return tt.items, tt.itemsErr
+				},
+				SumExpensesByCategoryFunc: func(ctx context.Context, start, end time.Time) ([]*entity.CategoryAmount, error) {
+					return tt.expenses, nil
+				},
+			}
+			uc := NewHouseholdUseCase(mockReceipt, &MockExpenseRepository{})
+			uc.SetAggregateRepository(mockAggregate)
+
+			from := time.Date(2025, 6, 1, 0, 0, 0, 0, tokyo)
+			to := time.Date(2025, 6, 2, 0, 0, 0, 0, tokyo)
+			summaries, err := uc.GetPeriodSummary(sharedDomain.WithLocation(context.Background(), tokyo), from, to, ReportGroupDay, 0)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("GetPeriodSummary() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetPeriodSummary() error = %v", err)
+			}
+
+			if !gotStart.Equal(from) || !gotEnd.Equal(time.Date(2025, 6, 3, 0, 0, 0, 0, tokyo).Add(-time.Nanosecond)) {
+				t.Errorf("SumItemsByCategory(%v, %v)", gotStart, gotEnd)
+			}
+			if len(summaries) != len(tt.wantTotals) {
+				t.Fatalf("GetPeriodSummary() = %d periods, want %d", len(summaries), len(tt.wantTotals))
+			}
+			for i, summary := range summaries {
+				if summary.Total != tt.wantTotals[i] {
+					t.Errorf("periods[%d].Total = %d, want %d", i, summary.Total, tt.wantTotals[i])
+				}
+			}
+			if got := summaries[0].Categories; len(got) != len(tt.wantFirst) || got[0] != tt.wantFirst[0] {
+				t.Errorf("periods[0].Categories = %+v, want %+v", got, tt.wantFirst)
+			}
+			// 2日目は食費（明細+家計簿エントリ）、カテゴリーなしの明細は「その他」
+			second := summaries[1].Categories
+			if len(second) != 2 || second[0] != (CategorySummary{Category: "食費", Count: 2, Total: 700}) || second[1] != (CategorySummary{Category: "その他", Count: 1, Total: 80}) {
+				t.Errorf("periods[1].Categories = %+v", second)
+			}
+		})
+	}
+}
+
+func TestHouseholdUseCase_GetCalendar(t *testing.T) {
+	tokyo := time.FixedZone("Asia/Tokyo", 9*60*60)
+	ctx := sharedDomain.WithLocation(context.Background(), tokyo)
+
+	t.Run("正常系: 時間帯ごとの合計をタイムゾーンの日に振り分ける", func(t *testing.T) {
+		var gotStart, gotEnd time.Time
+		uc := NewHouseholdUseCase(&MockReceiptRepository{}, &MockExpenseRepository{})
+		uc.SetAggregateRepository(&MockAggregateRepository{
+			SumReceiptsFunc: func(ctx context.Context, start, end time.Time) ([]*entity.ReceiptAmount, error) {
+				gotStart, gotEnd = start, end
+				// 日本時間の2/2 0:00はUTCの2/1 15:00
+				return []*entity.ReceiptAmount{
+					{Time: time.Date(2025, 2, 1, 14, 45, 0, 0, time.UTC), Count: 2, Total: 800},
+					{Time: time.Date(2025, 2, 1, 15, 0, 0, 0, time.UTC), Count: 1, Total: 300},
+					{Time: time.Date(2025, 2, 28, 14, 45, 0, 0, time.UTC), Count: 1, Total: 500},
+				}, nil
+			},
+		})
+
+		days, err := uc.GetCalendar(ctx, 2025, 2)
+		if err != nil {
+			t.Fatalf("GetCalendar() error = %v", err)
+		}
+		if !gotStart.Equal(time.Date(2025, 2, 1, 0, 0, 0, 0, tokyo)) || !gotEnd.Before(time.Date(2025, 3, 1, 0, 0, 0, 0, tokyo)) {
+			t.Errorf("range = %v - %v", gotStart, gotEnd)
+		}
+		if len(days) != 28 {
+			t.Fatalf("GetCalendar() = %d days, want 28", len(days))
+		}
+		want := map[int]DaySummary{0: {Count: 2, Total: 800}, 1: {Count: 1, Total: 300}, 27: {Count: 1, Total: 500}}
+		for i, day := range days {
+			if day.Count != want[i].Count || day.Total != want[i].Total {
+				t.Errorf("days[%d] = %+v, want %+v", i, day, want[i])
+			}
+		}
+		if days[1].Date.Format(time.DateOnly) != "2025-02-02" {
+			t.Errorf("days[1].Date = %v", days[1].Date)
+		}
+	})
+
+	t.Run("正常系: 月を省略すると年全体を返す", func(t *testing.T) {
+		uc := NewHouseholdUseCase(&MockReceiptRepository{
+			FindByDateRangeFunc: func(ctx context.Context, start, end time.Time) ([]*entity.Receipt, error) {
+				return []*entity.Receipt{
+					{ID: "r1", PurchaseDate: time.Date(2024, 12, 31, 10, 0, 0, 0, tokyo), TotalAmount: 1000},
+					{ID: "r2", PurchaseDate: time.Date(2024, 2, 29, 10, 0, 0, 0, tokyo), TotalAmount: 250},
+				}, nil
+			},
+		}, &MockExpenseRepository{})
+
+		days, err := uc.GetCalendar(ctx, 2024, 0)
+		if err != nil {
+			t.Fatalf("GetCalendar() error = %v", err)
+		}
+		if len(days) != 366 || days[365].Total != 1000 || days[59].Total != 250 || days[59].Count != 1 {
+			t.Errorf("GetCalendar() = %d days, last = %+v, 2/29 = %+v", len(days), days[365], days[59])
+		}
+	})
+
+	t.Run("異常系: 集計に失敗", func(t *testing.T) {
+		uc := NewHouseholdUseCase(&MockReceiptRepository{}, &MockExpenseRepository{})
+		uc.SetAggregateRepository(&MockAggregateRepository{})
+		if _, err := uc.GetCalendar(ctx, 2025, 2); err == nil {
+			t.Error("GetCalendar() error = nil, want error")
+		}
+	})
+}
@@ -0,0 +1,370 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+)
+
+// InterchangeSchemaVersion レシートの移行用JSON（InterchangeDocument）の現在のスキーマの版
+// 項目の追加など、以前の版の読み込みに影響しない変更では版を上げない。項目の削除・意味の変更をした場合は版を上げ、
+// SupportedInterchangeVersionsに以前の版を残して読み込み・書き出しできるようにする
+const InterchangeSchemaVersion = 1
+
+// SupportedInterchangeVersions 読み込み・書き出しに対応するスキーマの版（古い順）
+var SupportedInterchangeVersions = []int{1}
+
+var (
+	// ErrUnsupportedSchemaVersion 対応していないスキーマの版（書き出しの指定・読み込んだ文書の版）
+	ErrUnsupportedSchemaVersion = errors.New("unsupported schema version")
+	// ErrInvalidInterchange 読み込む文書が不正（レシートがない、通貨が異なるなど）
+	ErrInvalidInterchange = errors.New("invalid interchange document")
+	// ErrImportConflict 同じIDのレシート・家計簿エントリが登録済みのため読み込めない
+	ErrImportConflict = errors.New("a receipt or expense with the same ID already exists")
+)
+
+// InterchangeDocument インスタンス間でレシートを移行するためのJSON（スキーマの版1）
+// 金額はすべてcurrencyの最小単位の整数。元画像・AIの読み取りの記録・変更履歴は含めない。
+// 読み込みでは知らない項目を無視するため、新しい版で追加した項目は以前の版のインスタンスでも読み込める
+type InterchangeDocument struct {
+	SchemaVersion int                  `json:"schema_version"`
+	ExportedAt    time.Time            `json:"exported_at"`
+	Currency      string               `json:"currency"` // 金額の通貨（書き出したインスタンスの設定の通貨）
+	Receipt       *InterchangeReceipt  `json:"receipt"`
+	Expenses      []InterchangeExpense `json:"expenses"`
+}
+
+// InterchangeReceipt 移行用JSONのレシート
+type InterchangeReceipt struct {
+	ID            string            `json:"id"`
+	StoreName     string            `json:"store_name"`
+	PurchaseDate  time.Time         `json:"purchase_date"`
+	TotalAmount   int64             `json:"total_amount"`
+	TaxAmount     int64             `json:"tax_amount"`
+	PaymentMethod string            `json:"payment_method,omitempty"`
+	ReceiptNumber string            `json:"receipt_number,omitempty"`
+	ReceiptType   string            `json:"receipt_type,omitempty"`
+	Locale        string            `json:"locale,omitempty"`
+	Currency      string            `json:"currency,omitempty"` // レシートに印字された通貨
+	Category      string            `json:"category,omitempty"`
+	NeedsReview   bool              `json:"needs_review"`
+	Tags          []string          `json:"tags"`
+	Memo          string            `json:"memo,omitempty"`
+	ImageHash     string            `json:"image_hash,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+	Items         []InterchangeItem `json:"items"`
+}
+
+// InterchangeItem 移行用JSONの明細
+type InterchangeItem struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	Quantity       int       `json:"quantity"`
+	Price          int64     `json:"price"`
+	Unit           string    `json:"unit,omitempty"`
+	Measure        float64   `json:"measure,omitempty"`
+	UnitPrice      int64     `json:"unit_price,omitempty"`
+	Category       string    `json:"category,omitempty"`
+	CategoryStatus string    `json:"category_status,omitempty"`
+	WarrantyMonths *int      `json:"warranty_months,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// InterchangeExpense 移行用JSONのレシートに紐づく家計簿エントリ
+type InterchangeExpense struct {
+	ID          string    `json:"id"`
+	Date        time.Time `json:"date"`
+	Category    string    `json:"category"`
+	Amount      int64     `json:"amount"`
+	Description string    `json:"description,omitempty"`
+	Tags        []string  `json:"tags"`
+	Memo        string    `json:"memo,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// InterchangeUseCase セルフホストからクラウドへの移行など、インスタンス間でレシートを書き出し・読み込むユースケース
+// IDは書き出したインスタンスのものをそのまま使い、同じ文書を二重に読み込んだ場合は登録済みとして拒否する
+type InterchangeUseCase struct {
+	receiptUseCase *ReceiptUseCase
+	expenseRepo    repository.ExpenseRepository
+}
+
+// NewInterchangeUseCase 新しいInterchangeUseCaseを作成
+func NewInterchangeUseCase(receiptUseCase *ReceiptUseCase, expenseRepo repository.ExpenseRepository) *InterchangeUseCase {
+	return &InterchangeUseCase{
+		receiptUseCase: receiptUseCase,
+		expenseRepo:    expenseRepo,
+	}
+}
+
+// NegotiateSchemaVersion 書き出すスキーマの版を決める（空の場合は現在の版、対応していない場合はErrUnsupportedSchemaVersion）
+func NegotiateSchemaVersion(requested string) (int, error) {
+	if requested == "" {
+		return InterchangeSchemaVersion, nil
+	}
+	version, err := strconv.Atoi(strings.TrimSpace(requested))
+	if err != nil || !slices.Contains(SupportedInterchangeVersions, version) {
+		return 0, unsupportedSchemaVersion(requested)
+	}
+	return version, nil
+}
+
+// unsupportedSchemaVersion 対応する版を含めたErrUnsupportedSchemaVersion
+func unsupportedSchemaVersion(version any) error {
+	return fmt.Errorf("%w: %v (supported: %s)", ErrUnsupportedSchemaVersion, version, supportedVersionsString())
+}
+
+// supportedVersionsString 対応するスキーマの版のカンマ区切り
+func supportedVersionsString() string {
+	versions := make([]string, 0, len(SupportedInterchangeVersions))
+	for _, version := range SupportedInterchangeVersions {
+		versions = append(versions, strconv.Itoa(version))
+	}
+	return strings.Join(versions, ",")
+}
+
+// Export レシートと紐づく家計簿エントリを指定したスキーマの版の文書に書き出す
+func (uc *InterchangeUseCase) Export(ctx context.Context, id string, version int) (*InterchangeDocument, error) {
+	if !slices.Contains(SupportedInterchangeVersions, version) {
+		return nil, unsupportedSchemaVersion(version)
+	}
+	receipt, err := uc.receiptUseCase.receiptRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrReceiptNotFound, err)
+	}
+	expenses, err := uc.expenseRepo.FindByReceiptID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expenses: %w", err)
+	}
+
+	doc := &InterchangeDocument{
+		SchemaVersion: version,
+		ExportedAt:    uc.receiptUseCase.clock.Now().UTC(),
+		Currency:      uc.receiptUseCase.Currency().Code,
+		Receipt:       toInterchangeReceipt(receipt),
+		Expenses:      make([]InterchangeExpense, 0, len(expenses)),
+	}
+	for _, expense := range expenses {
+		doc.Expenses = append(doc.Expenses, toInterchangeExpense(expense))
+	}
+	return doc, nil
+}
+
+// Import 文書のレシートと家計簿エントリを登録し、登録したレシートを返す
+// すべての項目を検証してから登録し、途中で失敗した場合は登録した分を削除する
+func (uc *InterchangeUseCase) Import(ctx context.Context, doc *InterchangeDocument) (*entity.Receipt, error) {
+	receipt, expenses, err := uc.decode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	receiptRepo := uc.receiptUseCase.receiptRepo
+	if _, err := receiptRepo.FindByID(ctx, receipt.ID); err == nil {
+		return nil, fmt.Errorf("%w: receipt %s", ErrImportConflict, receipt.ID)
+	}
+	for _, expense := range expenses {
+		if _, err := uc.expenseRepo.FindByID(ctx, expense.ID); err == nil {
+			return nil, fmt.Errorf("%w: expense %s", ErrImportConflict, expense.ID)
+		}
+	}
+
+	receipt.RecordCreated()
+	if err := receiptRepo.Create(ctx, receipt); err != nil {
+		return nil, fmt.Errorf("failed to save receipt: %w", err)
+	}
+	for i, expense := range expenses {
+		if err := uc.expenseRepo.Create(ctx, expense); err != nil {
+			uc.rollback(ctx, receipt.ID, expenses[:i])
+			return nil, fmt.Errorf("failed to save expense: %w", err)
+		}
+	}
+	uc.receiptUseCase.publishEvents(ctx, receipt)
+	return receipt, nil
+}
+
+// decode 文書を検証してレシート・家計簿エントリに変換
+func (uc *InterchangeUseCase) decode(doc *InterchangeDocument) (*entity.Receipt, []*entity.ExpenseEntry, error) {
+	switch {
+	case doc.SchemaVersion == 0:
+		return nil, nil, fmt.Errorf("%w: schema_version is required", ErrInvalidInterchange)
+	case !slices.Contains(SupportedInterchangeVersions, doc.SchemaVersion):
+		return nil, nil, unsupportedSchemaVersion(doc.SchemaVersion)
+	case doc.Receipt == nil:
+		return nil, nil, fmt.Errorf("%w: receipt is required", ErrInvalidInterchange)
+	case strings.TrimSpace(doc.Receipt.ID) == "":
+		return nil, nil, fmt.Errorf("%w: receipt.id is required", ErrInvalidInterchange)
+	}
+	// 金額は通貨の最小単位のため、通貨の異なるインスタンスには読み込まない
+	if currency := uc.receiptUseCase.Currency().Code; !strings.EqualFold(doc.Currency, currency) {
+		return nil, nil, fmt.Errorf("%w: currency %q does not match %q", ErrInvalidInterchange, doc.Currency, currency)
+	}
+
+	receipt := fromInterchangeReceipt(doc.Receipt, uc.receiptUseCase.clock.Now())
+	if err := validateReceipt(receipt); err != nil {
+		return nil, nil, err
+	}
+
+	expenses := make([]*entity.ExpenseEntry, 0, len(doc.Expenses))
+	for i, data := range doc.Expenses {
+		if strings.TrimSpace(data.ID) == "" {
+			return nil, nil, fmt.Errorf("%w: expenses[%d].id is required", ErrInvalidInterchange, i)
+		}
+		expense := fromInterchangeExpense(data, receipt.ID, uc.receiptUseCase.clock.Now())
+		if err := expense.Validate(); err != nil {
+			return nil, nil, fmt.Errorf("%w: expenses[%d]: %w", ErrInvalidExpense, i, err)
+		}
+		expenses = append(expenses, expense)
+	}
+	return receipt, expenses, nil
+}
+
+// rollback 読み込みの途中で失敗した場合に、登録したレシート・家計簿エントリを削除
+func (uc *InterchangeUseCase) rollback(ctx context.Context, receiptID string, expenses []*entity.ExpenseEntry) {
+	ctx = context.WithoutCancel(ctx)
+	for _, expense := range expenses {
+		_ = uc.expenseRepo.Delete(ctx, expense.ID)
+	}
+	_ = uc.receiptUseCase.receiptRepo.Delete(ctx, receiptID)
+}
+
+// toInterchangeReceipt レシートを移行用JSONのレシートに変換
+func toInterchangeReceipt(receipt *entity.Receipt) *InterchangeReceipt {
+	data := &InterchangeReceipt{
+		ID:            receipt.ID,
+		StoreName:     receipt.StoreName,
+		PurchaseDate:  receipt.PurchaseDate,
+		TotalAmount:   receipt.TotalAmount,
+		TaxAmount:     receipt.TaxAmount,
+		PaymentMethod: receipt.PaymentMethod,
+		ReceiptNumber: receipt.ReceiptNumber,
+		ReceiptType:   receipt.Type,
+		Locale:        receipt.Locale,
+		Currency:      receipt.Currency,
+		Category:      receipt.Category,
+		NeedsReview:   receipt.NeedsReview,
+		Tags:          nonNilTags(receipt.Tags),
+		Memo:          receipt.Memo,
+		ImageHash:     receipt.ImageHash,
+		CreatedAt:     receipt.CreatedAt,
+		UpdatedAt:     receipt.UpdatedAt,
+		Items:         make([]InterchangeItem, 0, len(receipt.Items)),
+	}
+	for _, item := range receipt.Items {
+		data.Items = append(data.Items, InterchangeItem{
+			ID:             item.ID,
+			Name:           item.Name,
+			Quantity:       item.Quantity,
+			Price:          item.Price,
+			Unit:           item.Unit,
+			Measure:        item.Measure,
+			UnitPrice:      item.UnitPrice,
+			Category:       item.Category,
+			CategoryStatus: item.CategoryStatus,
+			WarrantyMonths: item.WarrantyMonths,
+			CreatedAt:      item.CreatedAt,
+		})
+	}
+	return data
+}
+
+// fromInterchangeReceipt 移行用JSONのレシートをレシートに変換
+// IDのない明細はレシートのIDと順番から作り、作成日時・更新日時のない項目はnowとする
+func fromInterchangeReceipt(data *InterchangeReceipt, now time.Time) *entity.Receipt {
+	receipt := &entity.Receipt{
+		ID:            data.ID,
+		StoreName:     data.StoreName,
+		PurchaseDate:  data.PurchaseDate,
+		TotalAmount:   data.TotalAmount,
+		TaxAmount:     data.TaxAmount,
+		PaymentMethod: data.PaymentMethod,
+		ReceiptNumber: data.ReceiptNumber,
+		Type:          data.ReceiptType,
+		Locale:        data.Locale,
+		Currency:      data.Currency,
+		Category:      data.Category,
+		NeedsReview:   data.NeedsReview,
+		Tags:          entity.NormalizeTags(data.Tags),
+		Memo:          data.Memo,
+		ImageHash:     data.ImageHash,
+		CreatedAt:     orNow(data.CreatedAt, now),
+		UpdatedAt:     orNow(data.UpdatedAt, now),
+		Items:         make([]entity.ReceiptItem, 0, len(data.Items)),
+	}
+	for i, item := range data.Items {
+		id := item.ID
+		if id == "" {
+			id = fmt.Sprintf("%s-%d", data.ID, i+1)
+		}
+		receipt.Items = append(receipt.Items, entity.ReceiptItem{
+			ID:             id,
+			ReceiptID:      data.ID,
+			Name:           item.Name,
+			Quantity:       item.Quantity,
+			Price:          item.Price,
+			Unit:           item.Unit,
+			Measure:        item.Measure,
+			UnitPrice:      item.UnitPrice,
+			Category:       item.Category,
+			CategoryStatus: item.CategoryStatus,
+			WarrantyMonths: item.WarrantyMonths,
+			CreatedAt:      orNow(item.CreatedAt, now),
+		})
+	}
+	return receipt
+}
+
+// toInterchangeExpense 家計簿エントリを移行用JSONの家計簿エントリに変換
+func toInterchangeExpense(expense *entity.ExpenseEntry) InterchangeExpense {
+	return InterchangeExpense{
+		ID:          expense.ID,
+		Date:        expense.Date,
+		Category:    expense.Category,
+		Amount:      expense.Amount,
+		Description: expense.Description,
+		Tags:        nonNilTags(expense.Tags),
+		Memo:        expense.Memo,
+		CreatedAt:   expense.CreatedAt,
+		UpdatedAt:   expense.UpdatedAt,
+	}
+}
+
+// fromInterchangeExpense 移行用JSONの家計簿エントリを読み込むレシートに紐づく家計簿エントリに変換
+func fromInterchangeExpense(data InterchangeExpense, receiptID string, now time.Time) *entity.ExpenseEntry {
+	return &entity.ExpenseEntry{
+		ID:          data.ID,
+		ReceiptID:   &receiptID,
+		Date:        data.Date,
+		Category:    data.Category,
+		Amount:      data.Amount,
+		Description: data.Description,
+		Tags:        entity.NormalizeTags(data.Tags),
+		Memo:        data.Memo,
+		CreatedAt:   orNow(data.CreatedAt, now),
+		UpdatedAt:   orNow(data.UpdatedAt, now),
+	}
+}
+
+// orNow 日時が未設定の場合はnow
+func orNow(t, now time.Time) time.Time {
+	if t.IsZero() {
+		return now
+	}
+	return t
+}
+
+// nonNilTags タグがnilの場合は空のスライス（JSONでnullではなく[]を書き出す）
+func nonNilTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
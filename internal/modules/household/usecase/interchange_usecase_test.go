@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+func TestNegotiateSchemaVersion(t *testing.T) {
+	tests := []struct {
+		requested string
+		want      int
+		wantErr   bool
+	}{
+		{requested: "", want: InterchangeSchemaVersion},
+		{requested: "1", want: 1},
+		{requested: "2", wantErr: true},
+		{requested: "v1", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := NegotiateSchemaVersion(tt.requested)
+		if tt.wantErr {
+			if !errors.Is(err, ErrUnsupportedSchemaVersion) {
+				t.Errorf("NegotiateSchemaVersion(%q) error = %v, want ErrUnsupportedSchemaVersion", tt.requested, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("NegotiateSchemaVersion(%q) = %d, %v, want %d", tt.requested, got, err, tt.want)
+		}
+	}
+}
+
+func TestInterchangeUseCase_ExportAndImport(t *testing.T) {
+	now := time.Date(2025, time.June, 10, 15, 0, 0, 0, time.UTC)
+	warranty := 12
+	receipt := &entity.Receipt{
+		ID:           "receipt-a",
+		StoreName:    "家電店",
+		PurchaseDate: now,
+		TotalAmount:  3300,
+		TaxAmount:    300,
+		Tags:         []string{"家電"},
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		Items: []entity.ReceiptItem{
+			{ID: "item-a", ReceiptID: "receipt-a", Name: "ケトル", Quantity: 1, Price: 3000, Category: "日用品", CategoryStatus: entity.CategoryStatusManual, WarrantyMonths: &warranty},
+		},
+	}
+	receiptID := receipt.ID
+	expense := &entity.ExpenseEntry{ID: "expense-a", ReceiptID: &receiptID, Date: now, Category: "日用品", Amount: 3300}
+
+	sourceRepo, _ := newMemoryReceiptRepository(receipt)
+	sourceExpenses := &memoryExpenseRepository{entries: map[string]*entity.ExpenseEntry{expense.ID: expense}}
+	sourceExpenses.FindByReceiptIDFunc = func(ctx context.Context, id string) ([]*entity.ExpenseEntry, error) {
+		return []*entity.ExpenseEntry{expense}, nil
+	}
+	sourceReceipts := NewReceiptUseCase(&MockAIRepository{}, sourceRepo, nil)
+	sourceReceipts.SetClock(sharedDomain.FixedClock(now))
+	source := NewInterchangeUseCase(sourceReceipts, sourceExpenses)
+	ctx := context.Background()
+
+	doc, err := source.Export(ctx, "receipt-a", InterchangeSchemaVersion)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if doc.SchemaVersion != 1 || doc.Currency != "JPY" || len(doc.Receipt.Items) != 1 || len(doc.Expenses) != 1 {
+		t.Fatalf("Export() = %+v", doc)
+	}
+	if _, err := source.Export(ctx, "missing", InterchangeSchemaVersion); !errors.Is(err, ErrReceiptNotFound) {
+		t.Errorf("Export() error = %v, want ErrReceiptNotFound", err)
+	}
+	if _, err := source.Export(ctx, "receipt-a", 99); !errors.Is(err, ErrUnsupportedSchemaVersion) {
+		t.Errorf("Export() error = %v, want ErrUnsupportedSchemaVersion", err)
+	}
+
+	// JSONを経由して別のインスタンスに読み込む
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var decoded InterchangeDocument
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	targetRepo, storedReceipts := newMemoryReceiptRepository()
+	targetExpenses := &memoryExpenseRepository{entries: map[string]*entity.ExpenseEntry{}}
+	target := NewInterchangeUseCase(NewReceiptUseCase(&MockAIRepository{}, targetRepo, nil), targetExpenses)
+
+	imported, err := target.Import(ctx, &decoded)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	stored := storedReceipts["receipt-a"]
+	if imported.ID != "receipt-a" || stored == nil || stored.StoreName != "家電店" || !stored.PurchaseDate.Equal(now) {
+		t.Fatalf("Import() stored %+v", stored)
+	}
+	if item := stored.Items[0]; item.ReceiptID != "receipt-a" || item.WarrantyMonths == nil || *item.WarrantyMonths != 12 {
+		t.Errorf("imported item = %+v", item)
+	}
+	if entry := targetExpenses.entries["expense-a"]; entry == nil || entry.ReceiptID == nil || *entry.ReceiptID != "receipt-a" || entry.Amount != 3300 {
+		t.Errorf("imported expense = %+v", entry)
+	}
+
+	// 同じ文書を二重に読み込んだ場合は登録済みとして拒否する
+	if _, err := target.Import(ctx, &decoded); !errors.Is(err, ErrImportConflict) {
+		t.Errorf("Import() error = %v, want ErrImportConflict", err)
+	}
+}
+
+func TestInterchangeUseCase_ImportRejectsInvalidDocuments(t *testing.T) {
+	valid := func() *InterchangeDocument {
+		return &InterchangeDocument{
+			SchemaVersion: 1,
+			Currency:      "JPY",
+			Receipt:       &InterchangeReceipt{ID: "receipt-a", StoreName: "スーパー", TotalAmount: 100},
+			Expenses:      []InterchangeExpense{{ID: "expense-a", Category: "食費", Amount: 100}},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*InterchangeDocument)
+		want   error
+	}{
+		{name: "missing version", modify: func(d *InterchangeDocument) { d.SchemaVersion = 0 }, want: ErrInvalidInterchange},
+		{name: "newer version", modify: func(d *InterchangeDocument) { d.SchemaVersion = 2 }, want: ErrUnsupportedSchemaVersion},
+		{name: "missing receipt", modify: func(d *InterchangeDocument) { d.Receipt = nil }, want: ErrInvalidInterchange},
+		{name: "other currency", modify: func(d *InterchangeDocument) { d.Currency = "USD" }, want: ErrInvalidInterchange},
+		{name: "invalid receipt", modify: func(d *InterchangeDocument) { d.Receipt.StoreName = "" }, want: ErrInvalidReceipt},
+		{name: "invalid expense", modify: func(d *InterchangeDocument) { d.Expenses[0].Category = "" }, want: ErrInvalidExpense},
+		{name: "expense without id", modify: func(d *InterchangeDocument) { d.Expenses[0].ID = "" }, want: ErrInvalidInterchange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiptRepo, stored := newMemoryReceiptRepository()
+			uc := NewInterchangeUseCase(NewReceiptUseCase(&MockAIRepository{}, receiptRepo, nil), &memoryExpenseRepository{entries: map[string]*entity.ExpenseEntry{}})
+			doc := valid()
+			tt.modify(doc)
+			if _, err := uc.Import(context.Background(), doc); !errors.Is(err, tt.want) {
+				t.Errorf("Import() error = %v, want %v", err, tt.want)
+			}
+			if len(stored) != 0 {
+				t.Error("Expected nothing to be saved")
+			}
+		})
+	}
+}
+
+func TestInterchangeUseCase_ImportRollsBackOnExpenseFailure(t *testing.T) {
+	receiptRepo, stored := newMemoryReceiptRepository()
+	expenses := &failingExpenseRepository{memoryExpenseRepository: memoryExpenseRepository{entries: map[string]*entity.ExpenseEntry{}}, failID: "expense-b"}
+	receiptUseCase := NewReceiptUseCase(&MockAIRepository{}, receiptRepo, nil)
+	receiptUseCase.SetCurrency(sharedDomain.DefaultCurrency)
+	uc := NewInterchangeUseCase(receiptUseCase, expenses)
+
+	doc := &InterchangeDocument{
+		SchemaVersion: 1,
+		Currency:      "jpy",
+		Receipt:       &InterchangeReceipt{ID: "receipt-a", StoreName: "スーパー", TotalAmount: 300},
+		Expenses: []InterchangeExpense{
+			{ID: "expense-a", Category: "食費", Amount: 100},
+			{ID: "expense-b", Category: "食費", Amount: 200},
+		},
+	}
+	if _, err := uc.Import(context.Background(), doc); err == nil {
+		t.Fatal("Expected error")
+	}
+	if len(stored) != 0 || len(expenses.entries) != 0 {
+		t.Errorf("Expected rollback, got receipts=%d expenses=%d", len(stored), len(expenses.entries))
+	}
+}
+
+// failingExpenseRepository 指定したIDの家計簿エントリの保存に失敗する
+type failingExpenseRepository struct {
+	memoryExpenseRepository
+	failID string
+}
+
+func (r *failingExpenseRepository) Create(ctx context.Context, entry *entity.ExpenseEntry) error {
+	if entry.ID == r.failID {
+		return errors.New("insert failed")
+	}
+	return r.memoryExpenseRepository.Create(ctx, entry)
+}
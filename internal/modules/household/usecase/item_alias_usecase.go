@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+)
+
+var (
+	// ErrInvalidItemAlias 登録する商品名の別名が不正（別名・正式な商品名が空など）
+	ErrInvalidItemAlias = errors.New("invalid item alias")
+)
+
+// ItemAliasUseCase 商品名の別名辞書を管理するユースケース
+// 登録した別名は以降に読み取るレシートに適用される。登録済みのレシートには
+// 商品名の正規化（NormalizeNames）を実行して適用する
+type ItemAliasUseCase struct {
+	itemAliasRepo repository.ItemAliasRepository
+	now           func() time.Time
+}
+
+// NewItemAliasUseCase 新しいItemAliasUseCaseを作成
+func NewItemAliasUseCase(itemAliasRepo repository.ItemAliasRepository) *ItemAliasUseCase {
+	return &ItemAliasUseCase{
+		itemAliasRepo: itemAliasRepo,
+		now:           time.Now,
+	}
+}
+
+// ListAliases 登録済みの別名の一覧
+func (uc *ItemAliasUseCase) ListAliases(ctx context.Context) ([]*entity.ItemAlias, error) {
+	return uc.itemAliasRepo.FindAll(ctx)
+}
+
+// SaveAlias 別名を登録する（全角・半角や空白だけが異なる別名が登録済みの場合は上書き）
+func (uc *ItemAliasUseCase) SaveAlias(ctx context.Context, alias, canonicalName string) (*entity.ItemAlias, error) {
+	now := uc.now()
+	itemAlias := &entity.ItemAlias{
+		Alias:         strings.TrimSpace(alias),
+		CanonicalName: strings.TrimSpace(canonicalName),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := itemAlias.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidItemAlias, err)
+	}
+	if err := uc.itemAliasRepo.Save(ctx, itemAlias); err != nil {
+		return nil, fmt.Errorf("failed to save item alias: %w", err)
+	}
+	return itemAlias, nil
+}
+
+// DeleteAlias 別名を削除する（登録されていない場合はentity.ErrItemAliasNotFound）
+func (uc *ItemAliasUseCase) DeleteAlias(ctx context.Context, alias string) error {
+	return uc.itemAliasRepo.Delete(ctx, alias)
+}
@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+)
+
+// memoryItemAliasRepository 商品名の別名をメモリに保存する
+type memoryItemAliasRepository struct {
+	aliases map[string]*entity.ItemAlias
+}
+
+func newMemoryItemAliasRepository(aliases ...*entity.ItemAlias) *memoryItemAliasRepository {
+	r := &memoryItemAliasRepository{aliases: make(map[string]*entity.ItemAlias)}
+	for _, alias := range aliases {
+		r.aliases[alias.Key()] = alias
+	}
+	return r
+}
+
+func (r *memoryItemAliasRepository) FindAll(ctx context.Context) ([]*entity.ItemAlias, error) {
+	aliases := make([]*entity.ItemAlias, 0, len(r.aliases))
+	for _, alias := range r.aliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Slice(aliases, func(i, j int) bool { return aliases[i].Alias < aliases[j].Alias })
+	return aliases, nil
+}
+
+func (r *memoryItemAliasRepository) Save(ctx context.Context, alias *entity.ItemAlias) error {
+	r.aliases[alias.Key()] = alias
+	return nil
+}
+
+func (r *memoryItemAliasRepository) Delete(ctx context.Context, alias string) error {
+	key := entity.ItemAliasKey(alias)
+	if _, ok := r.aliases[key]; !ok {
+		return fmt.Errorf("%w: %s", entity.ErrItemAliasNotFound, alias)
+	}
+	delete(r.aliases, key)
+	return nil
+}
+
+// failingItemAliasRepository 別名辞書を読み込めないリポジトリ
+type failingItemAliasRepository struct {
+	memoryItemAliasRepository
+}
+
+func (r *failingItemAliasRepository) FindAll(ctx context.Context) ([]*entity.ItemAlias, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestItemAliasUseCase(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryItemAliasRepository()
+	uc := NewItemAliasUseCase(repo)
+
+	if _, err := uc.SaveAlias(ctx, " ｷｬﾍﾞﾂ1/2 ", "キャベツ 1/2玉"); err != nil {
+		t.Fatalf("SaveAlias() error = %v", err)
+	}
+	// 全角・半角だけが異なる別名は上書きになる
+	if _, err := uc.SaveAlias(ctx, "キャベツ1/2", "キャベツ（1/2玉）"); err != nil {
+		t.Fatalf("SaveAlias() error = %v", err)
+	}
+	aliases, err := uc.ListAliases(ctx)
+	if err != nil || len(aliases) != 1 || aliases[0].CanonicalName != "キャベツ（1/2玉）" {
+		t.Fatalf("ListAliases() = %+v, %v", aliases, err)
+	}
+
+	for _, tt := range []struct{ alias, canonical string }{{"", "キャベツ"}, {"ｷｬﾍﾞﾂ", "  "}} {
+		if _, err := uc.SaveAlias(ctx, tt.alias, tt.canonical); !errors.Is(err, ErrInvalidItemAlias) {
+			t.Errorf("SaveAlias(%q, %q) error = %v, want ErrInvalidItemAlias", tt.alias, tt.canonical, err)
+		}
+	}
+
+	if err := uc.DeleteAlias(ctx, "ｷｬﾍﾞﾂ1/2"); err != nil {
+		t.Fatalf("DeleteAlias() error = %v", err)
+	}
+	if err := uc.DeleteAlias(ctx, "ｷｬﾍﾞﾂ1/2"); !errors.Is(err, entity.ErrItemAliasNotFound) {
+		t.Errorf("DeleteAlias() error = %v, want ErrItemAliasNotFound", err)
+	}
+}
+
+func TestReceiptUseCase_CanonicalizeItems(t *testing.T) {
+	receiptJSON := `{"store_name":"マルエツ","items":[{"name":"ｷｬﾍﾞﾂ1/2","quantity":1,"price":120},{"name":"牛乳","quantity":1,"price":200}]}`
+	now := time.Now()
+
+	uc := NewReceiptUseCase(&MockAIRepository{}, &MockReceiptRepository{}, nil)
+	uc.SetItemAliasRepository(newMemoryItemAliasRepository(&entity.ItemAlias{Alias: "キャベツ1/2", CanonicalName: "キャベツ 1/2玉", CreatedAt: now}))
+
+	receipt, err := uc.parseReceiptJSON(receiptJSON, "receipt-1", time.UTC)
+	if err != nil {
+		t.Fatalf("parseReceiptJSON() error = %v", err)
+	}
+	itemID := receipt.Items[0].ID
+	uc.canonicalizeItems(context.Background(), receipt)
+	if receipt.Items[0].Name != "キャベツ 1/2玉" || receipt.Items[1].Name != "牛乳" {
+		t.Errorf("Items = %+v, want canonical name for the alias only", receipt.Items)
+	}
+	// 明細IDは読み取った商品名から作ったまま変えない
+	if receipt.Items[0].ID != itemID {
+		t.Errorf("item ID = %q, want %q", receipt.Items[0].ID, itemID)
+	}
+
+	// 辞書を読み込めない場合は読み取った商品名のまま
+	uc.SetItemAliasRepository(&failingItemAliasRepository{})
+	receipt, _ = uc.parseReceiptJSON(receiptJSON, "receipt-1", time.UTC)
+	uc.canonicalizeItems(context.Background(), receipt)
+	if receipt.Items[0].Name != "ｷｬﾍﾞﾂ1/2" {
+		t.Errorf("Name = %q, want the recognized name", receipt.Items[0].Name)
+	}
+}
+
+func TestReceiptUseCase_NormalizeNames_ItemAliases(t *testing.T) {
+	receipts := []*entity.Receipt{
+		{ID: "alias", StoreName: "マルエツ", TotalAmount: 120, Items: []entity.ReceiptItem{{Name: "ｷｬﾍﾞﾂ1/2", Quantity: 1, Price: 120}}},
+		{ID: "ok", StoreName: "マルエツ", TotalAmount: 200, Items: []entity.ReceiptItem{{Name: "牛乳", Quantity: 1, Price: 200}}},
+	}
+	var updated []string
+	mockReceipt := &MockReceiptRepository{
+		FindAllFunc: func(ctx context.Context, limit, offset int) ([]*entity.Receipt, error) {
+			if offset >= len(receipts) {
+				return []*entity.Receipt{}, nil
+			}
+			return receipts[offset:], nil
+		},
+		UpdateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			updated = append(updated, receipt.ID+":"+receipt.Items[0].Name)
+			return nil
+		},
+	}
+
+	// 正規化の規則が無効でも、別名辞書だけで適用できる
+	uc := NewReceiptUseCase(&MockAIRepository{}, mockReceipt, nil)
+	uc.SetItemAliasRepository(newMemoryItemAliasRepository(&entity.ItemAlias{Alias: "ｷｬﾍﾞﾂ1/2", CanonicalName: "キャベツ 1/2玉"}))
+
+	report, err := uc.NormalizeNames(context.Background(), false)
+	if err != nil {
+		t.Fatalf("NormalizeNames() error = %v", err)
+	}
+	if report.Normalized != 1 || !slices.Equal(updated, []string{"alias:キャベツ 1/2玉"}) {
+		t.Errorf("report = %+v, updated = %v", report, updated)
+	}
+}
@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// 複式簿記の出力形式
+const (
+	LedgerFormatHledger   = "hledger"
+	LedgerFormatBeancount = "beancount"
+)
+
+// LedgerRules 勘定科目の対応付け
+type LedgerRules struct {
+	Currency          sharedDomain.Currency // 通貨（仕訳の金額は通貨の単位の小数で書き出す）
+	Accounts          map[string]string     // カテゴリーごとの費用の勘定科目
+	DefaultAccount    string                // 対応付けのないカテゴリーの勘定科目
+	PaymentAccounts   map[string]string     // 支払い方法ごとの支払元の勘定科目
+	PaymentAccount    string                // 対応付けのない支払い方法の勘定科目
+	AdjustmentAccount string                // 支払額と明細の合計の差額（外税・値引きなど）の勘定科目
+}
+
+// LedgerPosting 仕訳の1行（正の金額は借方、負の金額は貸方）
+type LedgerPosting struct {
+	Account string
+	Amount  int64
+}
+
+// LedgerTransaction レシート1件の仕訳
+type LedgerTransaction struct {
+	Date      time.Time
+	Payee     string // 店名
+	ReceiptID string
+	Memo      string
+	Postings  []LedgerPosting // 費用の行（勘定科目順）の後に支払元の行
+}
+
+// LedgerJournal 期間内の仕訳
+type LedgerJournal struct {
+	Start        time.Time
+	End          time.Time // この日時を含まない
+	Currency     sharedDomain.Currency
+	Accounts     []string // 仕訳で使う勘定科目（名前順）
+	Transactions []LedgerTransaction
+}
+
+// LedgerUseCase レシートを複式簿記の仕訳に変換するユースケース
+type LedgerUseCase struct {
+	receiptRepo repository.ReceiptRepository
+	rules       LedgerRules
+}
+
+// NewLedgerUseCase 新しいLedgerUseCaseを作成
+func NewLedgerUseCase(receiptRepo repository.ReceiptRepository, rules LedgerRules) *LedgerUseCase {
+	return &LedgerUseCase{
+		receiptRepo: receiptRepo,
+		rules:       rules,
+	}
+}
+
+// GenerateJournal 指定年（monthが1〜12の場合はその月のみ）のレシートを仕訳に変換
+// 明細はカテゴリーごとの勘定科目にまとめ、支払額は支払い方法の勘定科目から支払ったものとして記帳する
+func (uc *LedgerUseCase) GenerateJournal(ctx context.Context, year, month int) (*LedgerJournal, error) {
+	loc := sharedDomain.LocationFromContext(ctx)
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	end := start.AddDate(1, 0, 0)
+	if month >= 1 && month <= 12 {
+		start = time.Date(year, time.Month(month), 1, 0, 0, 0, 0, loc)
+		end = start.AddDate(0, 1, 0)
+	}
+
+	journal := &LedgerJournal{
+		Start:        start,
+		End:          end,
+		Currency:     uc.rules.Currency,
+		Accounts:     []string{},
+		Transactions: []LedgerTransaction{},
+	}
+
+	// レシートは1件ずつ仕訳に変換し、明細を含むレシート全体は保持しない
+	used := make(map[string]bool)
+	err := uc.receiptRepo.ForEachByDateRange(ctx, start, end.Add(-time.Nanosecond), func(receipt *entity.Receipt) error {
+		tx, ok := uc.transaction(receipt, loc)
+		if !ok {
+			return nil
+		}
+		for _, posting := range tx.Postings {
+			if !used[posting.Account] {
+				used[posting.Account] = true
+				journal.Accounts = append(journal.Accounts, posting.Account)
+			}
+		}
+		journal.Transactions = append(journal.Transactions, tx)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receipts: %w", err)
+	}
+
+	sort.Strings(journal.Accounts)
+	sort.SliceStable(journal.Transactions, func(i, j int) bool {
+		return journal.Transactions[i].Date.Before(journal.Transactions[j].Date)
+	})
+
+	return journal, nil
+}
+
+// transaction レシートを仕訳に変換（日付はlocの暦日、支払額が0の場合は記帳しない）
+func (uc *LedgerUseCase) transaction(receipt *entity.Receipt, loc *time.Location) (LedgerTransaction, bool) {
+	amounts := make(map[string]int64)
+	var itemsTotal int64
+	for _, item := range receipt.Items {
+		category := item.Category
+		if category == "" {
+			category = receipt.Category
+		}
+		amount := item.Amount()
+		amounts[uc.expenseAccount(category)] += amount
+		itemsTotal += amount
+	}
+
+	total := receipt.TotalAmount
+	if total == 0 {
+		total = itemsTotal
+	}
+	if total != itemsTotal {
+		if len(receipt.Items) == 0 {
+			amounts[uc.expenseAccount(receipt.Category)] += total
+		} else {
+			amounts[uc.rules.AdjustmentAccount] += total - itemsTotal
+		}
+	}
+	if total == 0 {
+		return LedgerTransaction{}, false
+	}
+
+	tx := LedgerTransaction{
+		Date:      receipt.PurchaseDate.In(loc),
+		Payee:     receipt.StoreName,
+		ReceiptID: receipt.ID,
+		Memo:      receipt.Memo,
+		Postings:  make([]LedgerPosting, 0, len(amounts)+1),
+	}
+	for account, amount := range amounts {
+		if amount != 0 {
+			tx.Postings = append(tx.Postings, LedgerPosting{Account: account, Amount: amount})
+		}
+	}
+	sort.Slice(tx.Postings, func(i, j int) bool {
+		return tx.Postings[i].Account < tx.Postings[j].Account
+	})
+	tx.Postings = append(tx.Postings, LedgerPosting{Account: uc.paymentAccount(receipt.PaymentMethod), Amount: -total})
+
+	return tx, true
+}
+
+// expenseAccount カテゴリーの勘定科目（未分類は「その他」の対応付けを使う）
+func (uc *LedgerUseCase) expenseAccount(category string) string {
+	if category == "" {
+		category = entity.DefaultCategory
+	}
+	if account, ok := uc.rules.Accounts[category]; ok && account != "" {
+		return account
+	}
+	return uc.rules.DefaultAccount
+}
+
+// paymentAccount 支払い方法の勘定科目
+func (uc *LedgerUseCase) paymentAccount(method string) string {
+	if account, ok := uc.rules.PaymentAccounts[method]; ok && account != "" {
+		return account
+	}
+	return uc.rules.PaymentAccount
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+func TestLedgerUseCase_GenerateJournal(t *testing.T) {
+	rules := LedgerRules{
+		Currency:          sharedDomain.DefaultCurrency,
+		Accounts:          map[string]string{"食費": "Expenses:Food", "その他": "Expenses:Other"},
+		DefaultAccount:    "Expenses:Misc",
+		PaymentAccounts:   map[string]string{"クレジットカード": "Liabilities:CreditCard"},
+		PaymentAccount:    "Assets:Cash",
+		AdjustmentAccount: "Expenses:Tax",
+	}
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2025, month, day, 12, 0, 0, 0, time.Local)
+	}
+	receipts := []*entity.Receipt{
+		// 外税: 明細の合計1000円 + 消費税100円
+		{ID: "r2", StoreName: "スーパーA", PurchaseDate: date(time.June, 20), TotalAmount: 1100, PaymentMethod: "クレジットカード", Items: []entity.ReceiptItem{
+			{Name: "牛乳", Quantity: 2, Price: 200, Category: "食費"},
+			{Name: "洗剤", Quantity: 1, Price: 500, Category: "日用品"},
+			{Name: "袋", Quantity: 1, Price: 100},
+		}},
+		// 明細なし: レシートのカテゴリーで計上
+		{ID: "r1", StoreName: "カフェ", PurchaseDate: date(time.June, 5), TotalAmount: 450, Category: "食費"},
+		// 支払額なし: 記帳しない
+		{ID: "r3", StoreName: "無料", PurchaseDate: date(time.June, 6)},
+	}
+
+	var gotStart, gotEnd time.Time
+	mockReceipt := &MockReceiptRepository{
+		FindByDateRangeFunc: func(ctx context.Context, start, end time.Time) ([]*entity.Receipt, error) {
+			gotStart, gotEnd = start, end
+			return receipts, nil
+		},
+	}
+	uc := NewLedgerUseCase(mockReceipt, rules)
+
+	journal, err := uc.GenerateJournal(context.Background(), 2025, 6)
+	if err != nil {
+		t.Fatalf("GenerateJournal() error = %v", err)
+	}
+
+	if !gotStart.Equal(time.Date(2025, time.June, 1, 0, 0, 0, 0, time.Local)) || !gotEnd.Before(time.Date(2025, time.July, 1, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("FindByDateRange(%v, %v), want June 2025", gotStart, gotEnd)
+	}
+	if journal.Currency.Code != "JPY" || !journal.End.Equal(time.Date(2025, time.July, 1, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("journal = %+v", journal)
+	}
+
+	wantAccounts := []string{"Assets:Cash", "Expenses:Food", "Expenses:Misc", "Expenses:Other", "Expenses:Tax", "Liabilities:CreditCard"}
+	if len(journal.Accounts) != len(wantAccounts) {
+		t.Fatalf("Accounts = %v, want %v", journal.Accounts, wantAccounts)
+	}
+	for i, account := range wantAccounts {
+		if journal.Accounts[i] != account {
+			t.Errorf("Accounts[%d] = %s, want %s", i, journal.Accounts[i], account)
+		}
+	}
+
+	if len(journal.Transactions) != 2 || journal.Transactions[0].ReceiptID != "r1" || journal.Transactions[1].ReceiptID != "r2" {
+		t.Fatalf("Transactions = %+v, want r1, r2 in date order", journal.Transactions)
+	}
+
+	tests := []struct {
+		name string
+		tx   LedgerTransaction
+		want []LedgerPosting
+	}{
+		{
+			name: "明細なしのレシート",
+			tx:   journal.Transactions[0],
+			want: []LedgerPosting{{Account: "Expenses:Food", Amount: 450}, {Account: "Assets:Cash", Amount: -450}},
+		},
+		{
+			name: "カテゴリーごとの勘定科目と外税",
+			tx:   journal.Transactions[1],
+			want: []LedgerPosting{
+				{Account: "Expenses:Food", Amount: 400},
+				{Account: "Expenses:Misc", Amount: 500},
+				{Account: "Expenses:Other", Amount: 100},
+				{Account: "Expenses:Tax", Amount: 100},
+				{Account: "Liabilities:CreditCard", Amount: -1100},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.tx.Postings) != len(tt.want) {
+				t.Fatalf("Postings = %+v, want %+v", tt.tx.Postings, tt.want)
+			}
+			var sum int64
+			for i, posting := range tt.tx.Postings {
+				if posting != tt.want[i] {
+					t.Errorf("Postings[%d] = %+v, want %+v", i, posting, tt.want[i])
+				}
+				sum += posting.Amount
+			}
+			if sum != 0 {
+				t.Errorf("postings do not balance: %d", sum)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+const (
+	// jobTypeLineReceipt LINEで受信したレシート画像の処理ジョブの種別
+	jobTypeLineReceipt = "line.receipt"
+
+	// lineSummaryItems 返信に載せる明細の最大数
+	lineSummaryItems = 10
+
+	// lineUsageMessage 画像以外のメッセージへの返信
+	lineUsageMessage = "レシートの写真を送ると家計簿に登録します。"
+	// lineFailureMessage レシートを処理できなかった場合の返信
+	lineFailureMessage = "レシートを読み取れませんでした。明るい場所でレシート全体が写るように撮影して、もう一度送ってください。"
+)
+
+// lineReceiptJobPayload LINEで受信したレシート画像の処理ジョブのペイロード
+type lineReceiptJobPayload struct {
+	MessageID  string `json:"message_id"`
+	ReplyToken string `json:"reply_token"`
+	Timezone   string `json:"timezone"` // 購入日の解釈に使うタイムゾーン（IANA名）
+}
+
+// LineUseCase LINEのトークで送られたレシート画像を登録して結果を返信するユースケース
+type LineUseCase struct {
+	receiptUseCase *ReceiptUseCase
+	messenger      sharedDomain.Messenger
+	tags           []string
+	jobQueue       sharedDomain.JobQueue
+}
+
+// NewLineUseCase 新しいLineUseCaseを作成（tagsはLINEから登録したレシートに付けるタグ）
+func NewLineUseCase(receiptUseCase *ReceiptUseCase, messenger sharedDomain.Messenger, tags []string) *LineUseCase {
+	return &LineUseCase{
+		receiptUseCase: receiptUseCase,
+		messenger:      messenger,
+		tags:           tags,
+	}
+}
+
+// SetJobQueue ジョブキューを設定し、レシート画像の処理を非同期化する
+// 未設定の場合はHandleImageMessage内で処理して返信する
+func (uc *LineUseCase) SetJobQueue(jobQueue sharedDomain.JobQueue) {
+	uc.jobQueue = jobQueue
+	if jobQueue != nil {
+		jobQueue.Register(jobTypeLineReceipt, uc.handleReceiptJob)
+	}
+}
+
+// HandleImageMessage 受信した画像メッセージをレシートとして登録し、結果を返信する
+// Webhookにすぐ応答できるよう、ジョブキューが設定されている場合は投入して戻る
+func (uc *LineUseCase) HandleImageMessage(ctx context.Context, messageID, replyToken string) error {
+	payload := lineReceiptJobPayload{
+		MessageID:  messageID,
+		ReplyToken: replyToken,
+		Timezone:   sharedDomain.LocationFromContext(ctx).String(),
+	}
+	if uc.jobQueue != nil {
+		data, err := json.Marshal(payload)
+		if err == nil {
+			if err = uc.jobQueue.Enqueue(ctx, jobTypeLineReceipt, data); err == nil {
+				return nil
+			}
+		}
+		slog.Warn("Failed to enqueue LINE receipt, processing synchronously", "message_id", messageID, "error", err)
+	}
+	return uc.processReceipt(ctx, payload)
+}
+
+// HandleTextMessage 画像以外のメッセージに使い方を返信する
+func (uc *LineUseCase) HandleTextMessage(ctx context.Context, replyToken string) error {
+	if err := uc.messenger.Reply(ctx, replyToken, lineUsageMessage); err != nil {
+		return fmt.Errorf("failed to reply to line message: %w", err)
+	}
+	return nil
+}
+
+// handleReceiptJob LINEで受信したレシート画像の処理ジョブ
+func (uc *LineUseCase) handleReceiptJob(ctx context.Context, job *sharedDomain.Job) error {
+	var payload lineReceiptJobPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid line receipt job payload: %w", err)
+	}
+	if loc, err := time.LoadLocation(payload.Timezone); err == nil {
+		ctx = sharedDomain.WithLocation(ctx, loc)
+	}
+	return uc.processReceipt(ctx, payload)
+}
+
+// processReceipt 画像を取得してレシートを登録し、結果を返信する
+// 登録できなかった場合も利用者が再送できるよう失敗を返信する
+func (uc *LineUseCase) processReceipt(ctx context.Context, payload lineReceiptJobPayload) error {
+	reply := lineFailureMessage
+	var processErr error
+
+	imageData, err := uc.messenger.DownloadContent(ctx, payload.MessageID)
+	if err != nil {
+		processErr = fmt.Errorf("failed to download line content: %w", err)
+	} else {
+		receipt, err := uc.receiptUseCase.ProcessReceiptImageWithOptions(ctx, imageData, ProcessOptions{Tags: uc.tags})
+		switch {
+		case err == nil || errors.Is(err, ErrSavePending):
+			// 一時保管したレシートもデータベースの復旧後に保存されるため、登録済みとして返信する
+			reply = lineReceiptSummary(receipt, uc.receiptUseCase.Currency())
+		case errors.Is(err, sharedDomain.ErrStorageQuotaExceeded):
+			reply = "保存容量の上限に達したため、レシートを登録できませんでした。"
+			processErr = err
+		default:
+			processErr = fmt.Errorf("failed to process line receipt: %w", err)
+		}
+	}
+
+	if err := uc.messenger.Reply(ctx, payload.ReplyToken, reply); err != nil {
+		// 返信用トークンの期限切れなどで返信できなくても、登録したレシートはそのまま残す
+		slog.Warn("Failed to reply to LINE message", "message_id", payload.MessageID, "error", err)
+	}
+	if processErr != nil {
+		slog.Error("Failed to register receipt from LINE", "message_id", payload.MessageID, "error", processErr)
+	}
+	return processErr
+}
+
+// lineReceiptSummary 登録したレシートの返信メッセージ（金額はcurrencyの記号と3桁区切りで表示）
+func lineReceiptSummary(receipt *entity.Receipt, currency sharedDomain.Currency) string {
+	var b strings.Builder
+	b.WriteString("レシートを登録しました\n")
+	if receipt.IsRefund() {
+		b.WriteString("種類: 返品\n")
+	}
+	fmt.Fprintf(&b, "店名: %s\n", receipt.StoreName)
+	fmt.Fprintf(&b, "日付: %s\n", receipt.PurchaseDate.Format("2006/01/02"))
+	fmt.Fprintf(&b, "合計: %s", currency.Display(receipt.TotalAmount))
+
+	for i, item := range receipt.Items {
+		if i == lineSummaryItems {
+			fmt.Fprintf(&b, "\nほか%d点", len(receipt.Items)-lineSummaryItems)
+			break
+		}
+		if item.IsWeighed() {
+			fmt.Fprintf(&b, "\n・%s %s %s", item.Name, item.QuantityLabel(), currency.Display(item.Amount()))
+			continue
+		}
+		fmt.Fprintf(&b, "\n・%s ×%d %s", item.Name, item.Quantity, currency.Display(item.Amount()))
+	}
+	if receipt.NeedsReview {
+		b.WriteString("\n\nカテゴリーの確認が必要な明細があります。")
+	}
+	return b.String()
+}
@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+	"vision-api-app/internal/modules/shared/infrastructure/queue"
+	"vision-api-app/internal/modules/vision/domain"
+)
+
+// stubMessenger 受信した画像を返し、返信を記録するMessenger
+type stubMessenger struct {
+	mu          sync.Mutex
+	content     map[string][]byte
+	replies     map[string][]string
+	downloadErr error
+}
+
+func (m *stubMessenger) DownloadContent(ctx context.Context, messageID string) ([]byte, error) {
+	if m.downloadErr != nil {
+		return nil, m.downloadErr
+	}
+	data, ok := m.content[messageID]
+	if !ok {
+		return nil, errors.New("content not found")
+	}
+	return data, nil
+}
+
+func (m *stubMessenger) Reply(ctx context.Context, replyToken string, texts ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.replies == nil {
+		m.replies = make(map[string][]string)
+	}
+	m.replies[replyToken] = append(m.replies[replyToken], texts...)
+	return nil
+}
+
+func TestLineUseCase_HandleImageMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		messageID   string
+		downloadErr error
+		aiErr       error
+		wantErr     bool
+		wantReply   []string
+	}{
+		{
+			name:      "正常系: レシートを登録して返信",
+			messageID: "m1",
+			wantReply: []string{"レシートを登録しました", "店名: Test Store", "日付: 2025/11/23", "合計: ¥1,000", "・Item1 ×1 ¥500"},
+		},
+		{
+			name:        "異常系: 画像を取得できない",
+			messageID:   "m1",
+			downloadErr: errors.New("line api returned status 404"),
+			wantErr:     true,
+			wantReply:   []string{lineFailureMessage},
+		},
+		{
+			name:      "異常系: レシートを認識できない",
+			messageID: "m1",
+			aiErr:     errors.New("AI error"),
+			wantErr:   true,
+			wantReply: []string{lineFailureMessage},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *entity.Receipt
+			mockAI := &MockAIRepository{
+				RecognizeReceiptFunc: func(imageData []byte) (*domain.AIResult, error) {
+					if tt.aiErr != nil {
+						return nil, tt.aiErr
+					}
+					return (&MockAIRepository{}).RecognizeReceipt(imageData)
+				},
+			}
+			mockReceipt := &MockReceiptRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+					return nil, errors.New("not found")
+				},
+				CreateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+					created = receipt
+					return nil
+				},
+			}
+			messenger := &stubMessenger{
+				content:     map[string][]byte{"m1": []byte("line image")},
+				downloadErr: tt.downloadErr,
+			}
+
+			uc := NewLineUseCase(NewReceiptUseCase(mockAI, mockReceipt, &MockCacheRepository{}), messenger, []string{"LINE"})
+			err := uc.HandleImageMessage(context.Background(), tt.messageID, "reply-1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HandleImageMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			replies := messenger.replies["reply-1"]
+			if len(replies) != 1 {
+				t.Fatalf("replies = %v, want 1 reply", replies)
+			}
+			for _, want := range tt.wantReply {
+				if !strings.Contains(replies[0], want) {
+					t.Errorf("reply = %q, want to contain %q", replies[0], want)
+				}
+			}
+			if !tt.wantErr && (created == nil || len(created.Tags) != 1 || created.Tags[0] != "LINE") {
+				t.Errorf("created = %+v, want receipt tagged LINE", created)
+			}
+		})
+	}
+}
+
+func TestLineUseCase_HandleImageMessage_JobQueue(t *testing.T) {
+	q := queue.NewMemoryQueue(1, 10)
+	defer func() {
+		_ = q.Close(context.Background())
+	}()
+
+	var created *entity.Receipt
+	mockReceipt := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			return nil, errors.New("not found")
+		},
+		CreateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			created = receipt
+			return nil
+		},
+	}
+	messenger := &stubMessenger{content: map[string][]byte{"m1": []byte("queued image")}}
+
+	uc := NewLineUseCase(NewReceiptUseCase(&MockAIRepository{}, mockReceipt, &MockCacheRepository{}), messenger, nil)
+	uc.SetJobQueue(q)
+
+	// 購入日はWebhookを受信したリクエストのタイムゾーンで解釈する
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data is not available: %v", err)
+	}
+	if err := uc.HandleImageMessage(sharedDomain.WithLocation(context.Background(), loc), "m1", "reply-1"); err != nil {
+		t.Fatalf("HandleImageMessage() error = %v", err)
+	}
+	if err := q.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	if created == nil {
+		t.Fatal("Expected receipt to be created by job")
+	}
+	if got := created.PurchaseDate.Location().String(); got != "America/New_York" {
+		t.Errorf("PurchaseDate location = %s, want America/New_York", got)
+	}
+	if len(messenger.replies["reply-1"]) != 1 {
+		t.Errorf("replies = %v, want 1 reply", messenger.replies)
+	}
+}
+
+func TestLineUseCase_HandleTextMessage(t *testing.T) {
+	messenger := &stubMessenger{}
+	uc := NewLineUseCase(nil, messenger, nil)
+
+	if err := uc.HandleTextMessage(context.Background(), "reply-1"); err != nil {
+		t.Fatalf("HandleTextMessage() error = %v", err)
+	}
+	if got := messenger.replies["reply-1"]; len(got) != 1 || got[0] != lineUsageMessage {
+		t.Errorf("replies = %v", got)
+	}
+}
+
+func TestLineReceiptSummary(t *testing.T) {
+	receipt := &entity.Receipt{
+		StoreName:    "スーパー",
+		PurchaseDate: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		TotalAmount:  123456,
+	}
+	for i := 0; i < lineSummaryItems+2; i++ {
+		receipt.Items = append(receipt.Items, entity.ReceiptItem{Name: "牛乳", Quantity: 2, Price: 1200})
+	}
+
+	summary := lineReceiptSummary(receipt, sharedDomain.DefaultCurrency)
+	for _, want := range []string{"合計: ¥123,456", "・牛乳 ×2 ¥2,400", "ほか2点"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary = %q, want to contain %q", summary, want)
+		}
+	}
+	if got := strings.Count(summary, "・"); got != lineSummaryItems {
+		t.Errorf("summary items = %d, want %d", got, lineSummaryItems)
+	}
+}
@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// 医療費の区分（医療費控除の明細書の区分に対応）
+const (
+	MedicalKindTreatment = "診療・治療"
+	MedicalKindMedicine  = "医薬品購入"
+	MedicalKindOther     = "その他の医療費"
+)
+
+const (
+	// medicalDeductionThreshold 医療費控除の足切り額（10万円）
+	medicalDeductionThreshold = 100000
+	// medicalDeductionLimit 医療費控除の上限額（200万円）
+	medicalDeductionLimit = 2000000
+)
+
+var (
+	// medicineProviderKeywords 医薬品購入として扱う店名のキーワード
+	medicineProviderKeywords = []string{"薬局", "調剤", "ドラッグ", "薬店"}
+	// treatmentProviderKeywords 診療・治療として扱う店名のキーワード
+	treatmentProviderKeywords = []string{"病院", "クリニック", "医院", "歯科", "診療所", "整骨院", "接骨院"}
+)
+
+// MedicalRules 医療費の判定ルール
+type MedicalRules struct {
+	Categories       []string
+	Keywords         []string
+	PatientTagPrefix string
+	DefaultPatient   string
+}
+
+// MedicalExpenseRow 医療費控除の明細行
+type MedicalExpenseRow struct {
+	ReceiptID string
+	Patient   string // 医療を受けた人
+	Provider  string // 病院・薬局などの名称
+	Kind      string // 医療費の区分
+	Amount    int64  // 支払った医療費の額
+	Date      time.Time
+}
+
+// MedicalDeductionReport 医療費控除レポート
+type MedicalDeductionReport struct {
+	Year      int
+	Rows      []MedicalExpenseRow
+	Total     int64 // 支払った医療費の合計
+	Deduction int64 // 医療費控除額の目安（所得200万円以上、補填なしの場合）
+}
+
+// MedicalReportUseCase 医療費控除レポートのユースケース
+type MedicalReportUseCase struct {
+	receiptRepo repository.ReceiptRepository
+	rules       MedicalRules
+}
+
+// NewMedicalReportUseCase 新しいMedicalReportUseCaseを作成
+func NewMedicalReportUseCase(receiptRepo repository.ReceiptRepository, rules MedicalRules) *MedicalReportUseCase {
+	return &MedicalReportUseCase{
+		receiptRepo: receiptRepo,
+		rules:       rules,
+	}
+}
+
+// GenerateReport 指定年（1月1日〜12月31日）の医療費控除レポートを作成
+func (uc *MedicalReportUseCase) GenerateReport(ctx context.Context, year int) (*MedicalDeductionReport, error) {
+	loc := sharedDomain.LocationFromContext(ctx)
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	end := start.AddDate(1, 0, 0).Add(-time.Nanosecond)
+
+	report := &MedicalDeductionReport{
+		Year: year,
+		Rows: []MedicalExpenseRow{},
+	}
+
+	// 医療費に該当するレシートのみを保持する（1年分のレシートをまとめて読み込まない）
+	err := uc.receiptRepo.ForEachByDateRange(ctx, start, end, func(receipt *entity.Receipt) error {
+		amount := uc.medicalAmount(receipt)
+		if amount <= 0 {
+			return nil
+		}
+
+		report.Rows = append(report.Rows, MedicalExpenseRow{
+			ReceiptID: receipt.ID,
+			Patient:   uc.patient(receipt),
+			Provider:  receipt.StoreName,
+			Kind:      classifyMedicalKind(receipt.StoreName),
+			Amount:    amount,
+			Date:      receipt.PurchaseDate.In(loc),
+		})
+		report.Total += amount
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receipts: %w", err)
+	}
+
+	// 明細書の記載順に合わせて受診者・支払日順に並べる
+	sort.SliceStable(report.Rows, func(i, j int) bool {
+		if report.Rows[i].Patient != report.Rows[j].Patient {
+			return report.Rows[i].Patient < report.Rows[j].Patient
+		}
+		return report.Rows[i].Date.Before(report.Rows[j].Date)
+	})
+
+	report.Deduction = report.Total - medicalDeductionThreshold
+	if report.Deduction < 0 {
+		report.Deduction = 0
+	}
+	if report.Deduction > medicalDeductionLimit {
+		report.Deduction = medicalDeductionLimit
+	}
+
+	return report, nil
+}
+
+// medicalAmount レシートのうち医療費に該当する金額を求める
+// 店名またはレシートのカテゴリーが該当する場合はレシート全体、それ以外は該当する明細のみを合計する
+func (uc *MedicalReportUseCase) medicalAmount(receipt *entity.Receipt) int64 {
+	if uc.isMedicalCategory(receipt.Category) || uc.containsKeyword(receipt.StoreName) {
+		return int64(receipt.TotalAmount)
+	}
+
+	var amount int64
+	for _, item := range receipt.Items {
+		if uc.isMedicalCategory(item.Category) || uc.containsKeyword(item.Name) {
+			amount += int64(item.Price) * int64(item.Quantity)
+		}
+	}
+	return amount
+}
+
+// patient 受診者タグから医療を受けた人を求める
+func (uc *MedicalReportUseCase) patient(receipt *entity.Receipt) string {
+	if uc.rules.PatientTagPrefix != "" {
+		for _, tag := range receipt.Tags {
+			if name, ok := strings.CutPrefix(tag, uc.rules.PatientTagPrefix); ok && name != "" {
+				return name
+			}
+		}
+	}
+	return uc.rules.DefaultPatient
+}
+
+// isMedicalCategory 医療費のカテゴリーかチェック
+func (uc *MedicalReportUseCase) isMedicalCategory(category string) bool {
+	if category == "" {
+		return false
+	}
+	for _, c := range uc.rules.Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// containsKeyword 医療費のキーワードを含むかチェック
+func (uc *MedicalReportUseCase) containsKeyword(text string) bool {
+	for _, keyword := range uc.rules.Keywords {
+		if keyword != "" && strings.Contains(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyMedicalKind 店名から医療費の区分を判定
+func classifyMedicalKind(provider string) string {
+	for _, keyword := range medicineProviderKeywords {
+		if strings.Contains(provider, keyword) {
+			return MedicalKindMedicine
+		}
+	}
+	for _, keyword := range treatmentProviderKeywords {
+		if strings.Contains(provider, keyword) {
+			return MedicalKindTreatment
+		}
+	}
+	return MedicalKindOther
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+)
+
+func TestMedicalReportUseCase_GenerateReport(t *testing.T) {
+	date := func(month, day int) time.Time {
+		return time.Date(2025, time.Month(month), day, 10, 0, 0, 0, time.Local)
+	}
+
+	mockReceipt := &MockReceiptRepository{
+		FindByDateRangeFunc: func(ctx context.Context, start, end time.Time) ([]*entity.Receipt, error) {
+			if start.Year() != 2025 || end.Year() != 2025 || end.Month() != time.December {
+				t.Errorf("date range = %v - %v, want year 2025", start, end)
+			}
+			return []*entity.Receipt{
+				// 店名がキーワードに一致するためレシート全体が対象
+				{ID: "r1", StoreName: "さくら歯科クリニック", PurchaseDate: date(3, 1), TotalAmount: 80000,
+					Tags: []string{"受診者:山田花子"}},
+				// 医療費カテゴリーの明細のみが対象
+				{ID: "r2", StoreName: "ドラッグストアABC", PurchaseDate: date(2, 1), TotalAmount: 3000,
+					Items: []entity.ReceiptItem{
+						{Name: "かぜ薬", Category: "医療費", Price: 1500, Quantity: 2},
+						{Name: "洗剤", Category: "日用品", Price: 500, Quantity: 1},
+					}},
+				// 対象外
+				{ID: "r3", StoreName: "スーパー", PurchaseDate: date(1, 1), TotalAmount: 2000,
+					Items: []entity.ReceiptItem{{Name: "牛乳", Category: "食費", Price: 2000, Quantity: 1}}},
+				{ID: "r4", StoreName: "中央病院", PurchaseDate: date(1, 15), TotalAmount: 50000},
+			}, nil
+		},
+	}
+
+	uc := NewMedicalReportUseCase(mockReceipt, MedicalRules{
+		Categories:       []string{"医療費"},
+		Keywords:         []string{"病院", "クリニック", "歯科"},
+		PatientTagPrefix: "受診者:",
+		DefaultPatient:   "本人",
+	})
+
+	report, err := uc.GenerateReport(context.Background(), 2025)
+	if err != nil {
+		t.Fatalf("GenerateReport() error = %v", err)
+	}
+
+	if len(report.Rows) != 3 {
+		t.Fatalf("rows = %d, want 3", len(report.Rows))
+	}
+
+	// 受診者・支払日順
+	want := []struct {
+		id, patient, kind string
+		amount            int64
+	}{
+		{"r1", "山田花子", MedicalKindTreatment, 80000},
+		{"r4", "本人", MedicalKindTreatment, 50000},
+		{"r2", "本人", MedicalKindMedicine, 3000},
+	}
+	for i, w := range want {
+		row := report.Rows[i]
+		if row.ReceiptID != w.id || row.Patient != w.patient || row.Kind != w.kind || row.Amount != w.amount {
+			t.Errorf("Rows[%d] = %+v, want %+v", i, row, w)
+		}
+	}
+	if report.Rows[1].Date.After(report.Rows[2].Date) {
+		t.Error("rows for the same patient should be sorted by date")
+	}
+
+	if report.Total != 133000 {
+		t.Errorf("Total = %d, want 133000", report.Total)
+	}
+	if report.Deduction != 33000 {
+		t.Errorf("Deduction = %d, want 33000", report.Deduction)
+	}
+}
+
+func TestMedicalReportUseCase_DeductionBelowThreshold(t *testing.T) {
+	mockReceipt := &MockReceiptRepository{
+		FindByDateRangeFunc: func(ctx context.Context, start, end time.Time) ([]*entity.Receipt, error) {
+			return []*entity.Receipt{
+				{ID: "r1", StoreName: "〇〇薬局", PurchaseDate: time.Now(), TotalAmount: 5000},
+			}, nil
+		},
+	}
+
+	uc := NewMedicalReportUseCase(mockReceipt, MedicalRules{Keywords: []string{"薬局"}, DefaultPatient: "本人"})
+
+	report, err := uc.GenerateReport(context.Background(), 2025)
+	if err != nil {
+		t.Fatalf("GenerateReport() error = %v", err)
+	}
+	if report.Total != 5000 || report.Deduction != 0 {
+		t.Errorf("Total = %d, Deduction = %d, want 5000, 0", report.Total, report.Deduction)
+	}
+}
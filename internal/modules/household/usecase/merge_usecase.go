@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+)
+
+var (
+	// ErrInvalidMerge 統合できない組み合わせ（同じレシート同士、他のレシートを統合済みのレシートの削除など）
+	ErrInvalidMerge = errors.New("invalid receipt merge")
+	// ErrMergeNotFound 指定したレシートの統合の記録が存在しない
+	ErrMergeNotFound = errors.New("receipt merge not found")
+)
+
+// MergeUseCase 二重に登録したレシートの統合と取り消しのユースケース
+type MergeUseCase struct {
+	receiptUseCase *ReceiptUseCase
+	mergeRepo      repository.ReceiptMergeRepository
+	now            func() time.Time
+}
+
+// NewMergeUseCase 新しいMergeUseCaseを作成
+func NewMergeUseCase(receiptUseCase *ReceiptUseCase, mergeRepo repository.ReceiptMergeRepository) *MergeUseCase {
+	return &MergeUseCase{
+		receiptUseCase: receiptUseCase,
+		mergeRepo:      mergeRepo,
+		now:            time.Now,
+	}
+}
+
+// MergeReceipts mergedIDのレシートをreceiptIDのレシートに統合し、mergedIDのレシートを削除
+// 商品名（正規化後）・単価・数量が同じ明細は同じ明細とみなし、残すレシートにない明細だけを追加する。
+// 合計金額は大きい方、空の支払い方法・レシート番号・税額・メモは削除するレシートの値で補い、タグは両方をまとめる。
+// 削除するレシートの元画像は統合の取り消しに備えて残す
+func (uc *MergeUseCase) MergeReceipts(ctx context.Context, receiptID, mergedID string) (*entity.Receipt, *entity.ReceiptMerge, error) {
+	if receiptID == "" || mergedID == "" || receiptID == mergedID {
+		return nil, nil, ErrInvalidMerge
+	}
+
+	rc := uc.receiptUseCase
+	receipt, err := rc.receiptRepo.FindByID(ctx, receiptID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrReceiptNotFound, err)
+	}
+	merged, err := rc.receiptRepo.FindByID(ctx, mergedID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrReceiptNotFound, err)
+	}
+
+	// 他のレシートを統合済みのレシートを削除すると、その統合の記録も削除されて取り消せなくなる
+	merges, err := uc.mergeRepo.FindByReceiptID(ctx, mergedID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get receipt merges: %w", err)
+	}
+	if len(merges) > 0 {
+		return nil, nil, fmt.Errorf("%w: receipt %s has merged receipts", ErrInvalidMerge, mergedID)
+	}
+
+	merge := &entity.ReceiptMerge{
+		ID:              rc.newID(),
+		ReceiptID:       receiptID,
+		MergedReceiptID: mergedID,
+		Before:          cloneReceipt(receipt),
+		MergedReceipt:   cloneReceipt(merged),
+		CreatedAt:       uc.now(),
+	}
+	merge.AddedItemIDs = uc.combineItems(receipt, merged)
+	combineHeader(receipt, merged)
+	receipt.NeedsReview = rc.needsReview(receipt)
+
+	// 記録を先に保存し、途中で失敗しても削除したレシートを失わないようにする
+	if err := uc.mergeRepo.Create(ctx, merge); err != nil {
+		return nil, nil, fmt.Errorf("failed to save receipt merge: %w", err)
+	}
+	if err := rc.UpdateReceipt(ctx, receipt, entity.RevisionSourceMerge); err != nil {
+		_ = uc.mergeRepo.DeleteByMergedReceiptID(ctx, mergedID)
+		return nil, nil, fmt.Errorf("failed to update receipt: %w", err)
+	}
+	if err := rc.receiptRepo.Delete(ctx, mergedID); err != nil {
+		return nil, nil, fmt.Errorf("failed to delete merged receipt: %w", err)
+	}
+	return receipt, merge, nil
+}
+
+// UnmergeReceipt 統合を取り消し、削除したレシートを統合した時点の状態で登録し直す
+// 残したレシートからは統合で追加した明細を除き、合計金額・支払い方法などの項目は統合前の値に戻す。
+// 統合後に行った既存の明細の修正は残る
+func (uc *MergeUseCase) UnmergeReceipt(ctx context.Context, receiptID, mergedID string) (*entity.Receipt, *entity.Receipt, error) {
+	merge, err := uc.mergeRepo.FindByMergedReceiptID(ctx, mergedID)
+	if err != nil || merge.ReceiptID != receiptID {
+		return nil, nil, ErrMergeNotFound
+	}
+
+	rc := uc.receiptUseCase
+	receipt, err := rc.receiptRepo.FindByID(ctx, receiptID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrReceiptNotFound, err)
+	}
+
+	receipt.Items = slices.DeleteFunc(receipt.Items, func(item entity.ReceiptItem) bool {
+		return slices.Contains(merge.AddedItemIDs, item.ID)
+	})
+	receipt.TotalAmount = merge.Before.TotalAmount
+	receipt.TaxAmount = merge.Before.TaxAmount
+	receipt.PaymentMethod = merge.Before.PaymentMethod
+	receipt.ReceiptNumber = merge.Before.ReceiptNumber
+	receipt.Memo = merge.Before.Memo
+	receipt.Tags = append([]string(nil), merge.Before.Tags...)
+	receipt.NeedsReview = rc.needsReview(receipt)
+
+	restored := cloneReceipt(&merge.MergedReceipt)
+	restored.UpdatedAt = uc.now()
+	if err := rc.receiptRepo.Create(ctx, &restored); err != nil {
+		return nil, nil, fmt.Errorf("failed to restore merged receipt: %w", err)
+	}
+	if err := rc.UpdateReceipt(ctx, receipt, entity.RevisionSourceUnmerge); err != nil {
+		return nil, nil, fmt.Errorf("failed to update receipt: %w", err)
+	}
+	if err := uc.mergeRepo.DeleteByMergedReceiptID(ctx, mergedID); err != nil {
+		return nil, nil, fmt.Errorf("failed to delete receipt merge: %w", err)
+	}
+	return receipt, &restored, nil
+}
+
+// ListMerges レシートに統合したレシートの記録を統合した順に取得
+func (uc *MergeUseCase) ListMerges(ctx context.Context, receiptID string) ([]*entity.ReceiptMerge, error) {
+	return uc.mergeRepo.FindByReceiptID(ctx, receiptID)
+}
+
+// mergeItemKey 同じ明細とみなす項目
+type mergeItemKey struct {
+	name     string
+	price    int64
+	quantity int
+}
+
+// combineItems 統合するレシートの明細のうち、残すレシートにない明細を追加し、追加した明細のIDを返す
+func (uc *MergeUseCase) combineItems(receipt, merged *entity.Receipt) []string {
+	remaining := make(map[mergeItemKey]int, len(receipt.Items))
+	ids := make(map[string]bool, len(receipt.Items))
+	for _, item := range receipt.Items {
+		remaining[mergeItemKey{entity.NormalizeItemName(item.Name), item.Price, item.Quantity}]++
+		ids[item.ID] = true
+	}
+
+	added := []string{}
+	for _, item := range merged.Items {
+		key := mergeItemKey{entity.NormalizeItemName(item.Name), item.Price, item.Quantity}
+		if remaining[key] > 0 {
+			remaining[key]--
+			continue
+		}
+
+		// 明細IDの形式は残すレシートの既存の明細と揃える
+		index := len(receipt.Items)
+		item.ID = uc.receiptUseCase.newItemID(receipt.ID, index, item.Name, item.Price)
+		for ids[item.ID] {
+			index++
+			item.ID = uc.receiptUseCase.newItemID(receipt.ID, index, item.Name, item.Price)
+		}
+		ids[item.ID] = true
+		item.ReceiptID = receipt.ID
+		receipt.Items = append(receipt.Items, item)
+		added = append(added, item.ID)
+	}
+	return added
+}
+
+// combineHeader 残すレシートの空の項目を統合するレシートの値で補う
+func combineHeader(receipt, merged *entity.Receipt) {
+	receipt.TotalAmount = max(receipt.TotalAmount, merged.TotalAmount)
+	if receipt.TaxAmount == 0 {
+		receipt.TaxAmount = merged.TaxAmount
+	}
+	if receipt.PaymentMethod == "" {
+		receipt.PaymentMethod = merged.PaymentMethod
+	}
+	if receipt.ReceiptNumber == "" {
+		receipt.ReceiptNumber = merged.ReceiptNumber
+	}
+	if strings.TrimSpace(receipt.Memo) == "" {
+		receipt.Memo = merged.Memo
+	}
+	receipt.Tags = entity.NormalizeTags(append(append([]string(nil), receipt.Tags...), merged.Tags...))
+}
+
+// cloneReceipt 明細・タグを含めてレシートを複製
+func cloneReceipt(receipt *entity.Receipt) entity.Receipt {
+	clone := *receipt
+	clone.Items = append([]entity.ReceiptItem(nil), receipt.Items...)
+	clone.Tags = append([]string(nil), receipt.Tags...)
+	return clone
+}
@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+)
+
+// memoryMergeRepository 統合の記録をメモリに保存する
+type memoryMergeRepository struct {
+	merges []*entity.ReceiptMerge
+}
+
+func (r *memoryMergeRepository) Create(ctx context.Context, merge *entity.ReceiptMerge) error {
+	r.merges = append(r.merges, merge)
+	return nil
+}
+
+func (r *memoryMergeRepository) FindByReceiptID(ctx context.Context, receiptID string) ([]*entity.ReceiptMerge, error) {
+	merges := []*entity.ReceiptMerge{}
+	for _, merge := range r.merges {
+		if merge.ReceiptID == receiptID {
+			merges = append(merges, merge)
+		}
+	}
+	return merges, nil
+}
+
+func (r *memoryMergeRepository) FindByMergedReceiptID(ctx context.Context, mergedReceiptID string) (*entity.ReceiptMerge, error) {
+	for _, merge := range r.merges {
+		if merge.MergedReceiptID == mergedReceiptID {
+			return merge, nil
+		}
+	}
+	return nil, fmt.Errorf("receipt merge not found: %s", mergedReceiptID)
+}
+
+func (r *memoryMergeRepository) DeleteByMergedReceiptID(ctx context.Context, mergedReceiptID string) error {
+	for i, merge := range r.merges {
+		if merge.MergedReceiptID == mergedReceiptID {
+			r.merges = append(r.merges[:i], r.merges[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// newMemoryReceiptRepository レシートをメモリに保存するモック
+func newMemoryReceiptRepository(receipts ...*entity.Receipt) (*MockReceiptRepository, map[string]*entity.Receipt) {
+	stored := make(map[string]*entity.Receipt)
+	for _, receipt := range receipts {
+		stored[receipt.ID] = receipt
+	}
+	load := func(id string) (*entity.Receipt, error) {
+		receipt, ok := stored[id]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+		copied := cloneReceipt(receipt)
+		return &copied, nil
+	}
+	save := func(ctx context.Context, receipt *entity.Receipt) error {
+		copied := cloneReceipt(receipt)
+		stored[receipt.ID] = &copied
+		return nil
+	}
+	return &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) { return load(id) },
+		CreateFunc:   save,
+		UpdateFunc:   save,
+		DeleteFunc: func(ctx context.Context, id string) error {
+			delete(stored, id)
+			return nil
+		},
+	}, stored
+}
+
+func TestMergeUseCase_MergeAndUnmerge(t *testing.T) {
+	date := time.Date(2025, time.June, 10, 15, 0, 0, 0, time.Local)
+	receipt := &entity.Receipt{
+		ID: "receipt-a", StoreName: "スーパー", PurchaseDate: date, TotalAmount: 300, Tags: []string{"食品"},
+		Items: []entity.ReceiptItem{
+			{ID: "receipt-a-00000000", ReceiptID: "receipt-a", Name: "牛乳", Quantity: 1, Price: 200, Category: "食費", CategoryStatus: entity.CategoryStatusAuto},
+			{ID: "receipt-a-00000001", ReceiptID: "receipt-a", Name: "パン", Quantity: 1, Price: 100, Category: "食費", CategoryStatus: entity.CategoryStatusAuto},
+		},
+	}
+	duplicate := &entity.Receipt{
+		ID: "receipt-b", StoreName: "スーパー", PurchaseDate: date, TotalAmount: 500, PaymentMethod: "現金", Tags: []string{"まとめ買い"},
+		Items: []entity.ReceiptItem{
+			{ID: "receipt-b-00000000", ReceiptID: "receipt-b", Name: "牛 乳", Quantity: 1, Price: 200, Category: "食費", CategoryStatus: entity.CategoryStatusAuto},
+			{ID: "receipt-b-00000001", ReceiptID: "receipt-b", Name: "卵", Quantity: 1, Price: 200, Category: "食費", CategoryStatus: entity.CategoryStatusAuto},
+			{ID: "receipt-b-00000002", ReceiptID: "receipt-b", Name: "牛乳", Quantity: 1, Price: 200, Category: "食費", CategoryStatus: entity.CategoryStatusAuto},
+		},
+	}
+	receiptRepo, stored := newMemoryReceiptRepository(receipt, duplicate)
+	revisionRepo := &MockReceiptRevisionRepository{}
+	receiptUseCase := NewReceiptUseCase(&MockAIRepository{}, receiptRepo, nil)
+	receiptUseCase.SetRevisionRepository(revisionRepo)
+	mergeRepo := &memoryMergeRepository{}
+	uc := NewMergeUseCase(receiptUseCase, mergeRepo)
+	ctx := context.Background()
+
+	merged, merge, err := uc.MergeReceipts(ctx, "receipt-a", "receipt-b")
+	if err != nil {
+		t.Fatalf("MergeReceipts() error = %v", err)
+	}
+
+	// 表記ゆれの牛乳1件は同じ明細とみなし、2件目の牛乳と卵を追加する
+	if len(merged.Items) != 4 || merged.Items[2].Name != "卵" || merged.Items[3].Name != "牛乳" {
+		t.Fatalf("items = %+v", merged.Items)
+	}
+	if merged.Items[2].ID != receiptUseCase.newItemID("receipt-a", 2, "卵", 200) || merged.Items[2].ReceiptID != "receipt-a" {
+		t.Errorf("added item = %+v, want re-numbered for the kept receipt", merged.Items[2])
+	}
+	if merged.TotalAmount != 500 || merged.PaymentMethod != "現金" || len(merged.Tags) != 2 {
+		t.Errorf("merged = %+v", merged)
+	}
+	if len(merge.AddedItemIDs) != 2 || merge.MergedReceipt.ID != "receipt-b" || merge.Before.TotalAmount != 300 {
+		t.Errorf("merge = %+v", merge)
+	}
+	if _, ok := stored["receipt-b"]; ok {
+		t.Error("Expected merged receipt to be deleted")
+	}
+	history, _ := receiptUseCase.GetReceiptHistory(ctx, "receipt-a")
+	if len(history) != 2 || history[1].Source != entity.RevisionSourceMerge {
+		t.Errorf("history = %+v, want original and merge revisions", history)
+	}
+
+	// 統合済みのレシートを統合したレシートは削除できない
+	third := &entity.Receipt{ID: "receipt-c", StoreName: "スーパー", PurchaseDate: date, TotalAmount: 100}
+	stored[third.ID] = third
+	if _, _, err := uc.MergeReceipts(ctx, "receipt-c", "receipt-a"); !errors.Is(err, ErrInvalidMerge) {
+		t.Errorf("MergeReceipts() error = %v, want ErrInvalidMerge", err)
+	}
+
+	// 統合後の既存明細の修正は取り消し後も残る
+	stored["receipt-a"].Items[0].Category = "日用品"
+
+	kept, restored, err := uc.UnmergeReceipt(ctx, "receipt-a", "receipt-b")
+	if err != nil {
+		t.Fatalf("UnmergeReceipt() error = %v", err)
+	}
+	if len(kept.Items) != 2 || kept.Items[0].Category != "日用品" || kept.TotalAmount != 300 || kept.PaymentMethod != "" || len(kept.Tags) != 1 {
+		t.Errorf("kept = %+v", kept)
+	}
+	if restored.ID != "receipt-b" || len(restored.Items) != 3 || stored["receipt-b"] == nil {
+		t.Errorf("restored = %+v", restored)
+	}
+	if merges, _ := uc.ListMerges(ctx, "receipt-a"); len(merges) != 0 {
+		t.Errorf("ListMerges() = %d, want 0 after unmerge", len(merges))
+	}
+	if _, _, err := uc.UnmergeReceipt(ctx, "receipt-a", "receipt-b"); !errors.Is(err, ErrMergeNotFound) {
+		t.Errorf("UnmergeReceipt() error = %v, want ErrMergeNotFound", err)
+	}
+}
+
+func TestMergeUseCase_KeepsReviewReasons(t *testing.T) {
+	date := time.Date(2025, time.June, 10, 15, 0, 0, 0, time.Local)
+	// JSONを修復して読み取ったレシートは、統合・取り消しの後も要確認のまま残す
+	receipt := &entity.Receipt{
+		ID: "receipt-a", StoreName: "スーパー", PurchaseDate: date, TotalAmount: 200, JSONRepaired: true, NeedsReview: true,
+		Items: []entity.ReceiptItem{{ID: "receipt-a-00000000", ReceiptID: "receipt-a", Name: "牛乳", Quantity: 1, Price: 200, Category: "食費", CategoryStatus: entity.CategoryStatusAuto}},
+	}
+	duplicate := &entity.Receipt{ID: "receipt-b", StoreName: "スーパー", PurchaseDate: date, TotalAmount: 200}
+	receiptRepo, _ := newMemoryReceiptRepository(receipt, duplicate)
+	receiptUseCase := NewReceiptUseCase(&MockAIRepository{}, receiptRepo, nil)
+	receiptUseCase.SetRevisionRepository(&MockReceiptRevisionRepository{})
+	uc := NewMergeUseCase(receiptUseCase, &memoryMergeRepository{})
+	ctx := context.Background()
+
+	merged, _, err := uc.MergeReceipts(ctx, "receipt-a", "receipt-b")
+	if err != nil {
+		t.Fatalf("MergeReceipts() error = %v", err)
+	}
+	if !merged.NeedsReview {
+		t.Error("NeedsReview = false after merge, want true for a repaired receipt")
+	}
+
+	kept, _, err := uc.UnmergeReceipt(ctx, "receipt-a", "receipt-b")
+	if err != nil {
+		t.Fatalf("UnmergeReceipt() error = %v", err)
+	}
+	if !kept.NeedsReview {
+		t.Error("NeedsReview = false after unmerge, want true for a repaired receipt")
+	}
+}
+
+func TestMergeUseCase_MergeReceipts_Errors(t *testing.T) {
+	receipt := &entity.Receipt{ID: "receipt-a", StoreName: "スーパー", PurchaseDate: time.Now(), TotalAmount: 100}
+	receiptRepo, _ := newMemoryReceiptRepository(receipt)
+	uc := NewMergeUseCase(NewReceiptUseCase(&MockAIRepository{}, receiptRepo, nil), &memoryMergeRepository{})
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		receiptID string
+		mergedID  string
+		wantErr   error
+	}{
+		{name: "同じレシート", receiptID: "receipt-a", mergedID: "receipt-a", wantErr: ErrInvalidMerge},
+		{name: "IDが空", receiptID: "receipt-a", mergedID: "", wantErr: ErrInvalidMerge},
+		{name: "存在しないレシート", receiptID: "receipt-a", mergedID: "missing", wantErr: ErrReceiptNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := uc.MergeReceipts(ctx, tt.receiptID, tt.mergedID); !errors.Is(err, tt.wantErr) {
+				t.Errorf("MergeReceipts() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+)
+
+// ErrItemNameRequired 商品名が指定されていない（正規化後に空になる場合を含む）
+var ErrItemNameRequired = errors.New("item name is required")
+
+// PricePoint 商品の購入1回分の価格
+type PricePoint struct {
+	Date      time.Time
+	StoreName string
+	ReceiptID string
+	Name      string // レシートに印字された商品名
+	Price     int64  // 単価
+	Quantity  int
+}
+
+// StorePrice 店舗ごとの価格の集計
+type StorePrice struct {
+	StoreName  string
+	Count      int
+	MinPrice   int64
+	MaxPrice   int64
+	AvgPrice   int64 // 単価の平均（最小単位未満四捨五入）
+	LastPrice  int64 // 最後に購入したときの単価
+	LastBought time.Time
+}
+
+// PriceHistory 商品の価格の推移
+type PriceHistory struct {
+	Name           string
+	NormalizedName string
+	Points         []PricePoint // 購入日の古い順
+	Stores         []StorePrice // 平均単価の安い順
+}
+
+// PriceHistoryUseCase 商品の価格推移のユースケース
+type PriceHistoryUseCase struct {
+	receiptRepo repository.ReceiptRepository
+}
+
+// NewPriceHistoryUseCase 新しいPriceHistoryUseCaseを作成
+func NewPriceHistoryUseCase(receiptRepo repository.ReceiptRepository) *PriceHistoryUseCase {
+	return &PriceHistoryUseCase{
+		receiptRepo: receiptRepo,
+	}
+}
+
+// GetPriceHistory 商品名の価格の推移をレシートをまたいで取得
+// 商品名は entity.NormalizeItemName で正規化して照合するため、全角・半角や空白の違いは同じ商品として扱う
+func (uc *PriceHistoryUseCase) GetPriceHistory(ctx context.Context, name string) (*PriceHistory, error) {
+	normalized := entity.NormalizeItemName(name)
+	if normalized == "" {
+		return nil, ErrItemNameRequired
+	}
+
+	items, err := uc.receiptRepo.FindItemsByNormalizedName(ctx, normalized, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find items: %w", err)
+	}
+
+	history := &PriceHistory{
+		Name:           strings.TrimSpace(name),
+		NormalizedName: normalized,
+		Points:         make([]PricePoint, 0, len(items)),
+		Stores:         []StorePrice{},
+	}
+
+	stores := make(map[string]*StorePrice)
+	totals := make(map[string]int64)
+	for _, item := range items {
+		history.Points = append(history.Points, PricePoint{
+			Date:      item.PurchaseDate,
+			StoreName: item.StoreName,
+			ReceiptID: item.Item.ReceiptID,
+			Name:      item.Item.Name,
+			Price:     item.Item.Price,
+			Quantity:  item.Item.Quantity,
+		})
+
+		store, ok := stores[item.StoreName]
+		if !ok {
+			store = &StorePrice{
+				StoreName: item.StoreName,
+				MinPrice:  item.Item.Price,
+				MaxPrice:  item.Item.Price,
+			}
+			stores[item.StoreName] = store
+		}
+		store.Count++
+		store.MinPrice = min(store.MinPrice, item.Item.Price)
+		store.MaxPrice = max(store.MaxPrice, item.Item.Price)
+		totals[item.StoreName] += item.Item.Price
+		// 購入日の古い順に並んでいるため、最後の明細が最新
+		store.LastPrice = item.Item.Price
+		store.LastBought = item.PurchaseDate
+	}
+
+	for storeName, store := range stores {
+		store.AvgPrice = (totals[storeName]*2 + int64(store.Count)) / (int64(store.Count) * 2)
+		history.Stores = append(history.Stores, *store)
+	}
+	sort.Slice(history.Stores, func(i, j int) bool {
+		if history.Stores[i].AvgPrice != history.Stores[j].AvgPrice {
+			return history.Stores[i].AvgPrice < history.Stores[j].AvgPrice
+		}
+		return history.Stores[i].StoreName < history.Stores[j].StoreName
+	})
+
+	return history, nil
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+)
+
+func TestPriceHistoryUseCase_GetPriceHistory(t *testing.T) {
+	date := func(month, day int) time.Time {
+		return time.Date(2025, time.Month(month), day, 10, 0, 0, 0, time.Local)
+	}
+	item := func(receiptID, storeName string, purchaseDate time.Time, name string, price int64) *entity.PurchasedItem {
+		return &entity.PurchasedItem{
+			Item:         entity.ReceiptItem{ReceiptID: receiptID, Name: name, Quantity: 1, Price: price},
+			StoreName:    storeName,
+			PurchaseDate: purchaseDate,
+		}
+	}
+
+	var gotName string
+	mockReceipt := &MockReceiptRepository{
+		FindItemsByNormalizedNameFunc: func(ctx context.Context, normalizedName string, limit, offset int) ([]*entity.PurchasedItem, error) {
+			gotName = normalizedName
+			return []*entity.PurchasedItem{
+				item("r1", "スーパーA", date(1, 5), "牛乳", 210),
+				item("r2", "スーパーB", date(1, 12), "ｷﾞｭｳﾆｭｳ", 198),
+				item("r3", "スーパーA", date(2, 3), "牛乳", 231),
+				item("r4", "スーパーB", date(2, 20), "牛乳", 199),
+			}, nil
+		},
+	}
+	uc := NewPriceHistoryUseCase(mockReceipt)
+
+	history, err := uc.GetPriceHistory(context.Background(), " 牛乳 ")
+	if err != nil {
+		t.Fatalf("GetPriceHistory() error = %v", err)
+	}
+
+	if gotName != "牛乳" || history.Name != "牛乳" || history.NormalizedName != "牛乳" {
+		t.Errorf("name = %q, history = %q/%q, want 牛乳", gotName, history.Name, history.NormalizedName)
+	}
+	if len(history.Points) != 4 || history.Points[1].Name != "ｷﾞｭｳﾆｭｳ" || history.Points[1].Price != 198 {
+		t.Errorf("Points = %+v", history.Points)
+	}
+
+	// 平均単価の安い順
+	want := []StorePrice{
+		{StoreName: "スーパーB", Count: 2, MinPrice: 198, MaxPrice: 199, AvgPrice: 199, LastPrice: 199, LastBought: date(2, 20)},
+		{StoreName: "スーパーA", Count: 2, MinPrice: 210, MaxPrice: 231, AvgPrice: 221, LastPrice: 231, LastBought: date(2, 3)},
+	}
+	if len(history.Stores) != len(want) {
+		t.Fatalf("Stores = %+v, want %d stores", history.Stores, len(want))
+	}
+	for i, w := range want {
+		if history.Stores[i] != w {
+			t.Errorf("Stores[%d] = %+v, want %+v", i, history.Stores[i], w)
+		}
+	}
+}
+
+func TestPriceHistoryUseCase_GetPriceHistory_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		item    string
+		repoErr error
+		wantErr error
+	}{
+		{name: "異常系: 商品名が空", item: " ※ ", wantErr: ErrItemNameRequired},
+		{name: "異常系: リポジトリエラー", item: "牛乳", repoErr: errors.New("db error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockReceipt := &MockReceiptRepository{
+				FindItemsByNormalizedNameFunc: func(ctx context.Context, normalizedName string, limit, offset int) ([]*entity.PurchasedItem, error) {
+					return nil, tt.repoErr
+				},
+			}
+			uc := NewPriceHistoryUseCase(mockReceipt)
+
+			_, err := uc.GetPriceHistory(context.Background(), tt.item)
+			if err == nil {
+				t.Fatal("GetPriceHistory() error = nil, want error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetPriceHistory() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,379 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+	"vision-api-app/internal/modules/vision/domain"
+)
+
+// jobTypeReceiptBackfill 保存済みの元画像をバッチで読み直すジョブ（バッチの依頼まで行い、結果はPollBackfillsで反映する）
+const jobTypeReceiptBackfill = "receipt.backfill"
+
+var (
+	// ErrInvalidBackfill 読み直すレシートの指定が不正（空、件数の上限を超えるなど）
+	ErrInvalidBackfill = errors.New("invalid backfill request")
+	// ErrBackfillNotFound 読み直しが存在しない（保持期間を過ぎたものを含む）
+	ErrBackfillNotFound = errors.New("backfill not found")
+)
+
+// BackfillRules バッチでの読み直しの設定
+type BackfillRules struct {
+	BatchSize     int   // 1つのバッチにまとめるレシート数
+	BatchMaxBytes int64 // 1つのバッチにまとめる画像のbase64に変換した後の合計サイズ（0の場合は制限しない）
+	MaxReceipts   int   // 1回の読み直しで指定できるレシート数
+}
+
+// Backfill バッチでの読み直しの進み具合
+type Backfill struct {
+	ID               string
+	State            string // sharedDomain.JobStateRunning・JobStateCompleted・JobStateFailed
+	Total            int
+	Succeeded        int
+	Failed           int
+	FailedReceiptIDs []string // 読み直せなかったレシート（元画像がない、読み取りに失敗したなど）
+	Batches          int      // 依頼したバッチ数
+	Error            string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// backfillState 読み直しの途中の状態（JobStatus.Dataに保存する）
+type backfillState struct {
+	Submitted        bool            `json:"submitted"` // すべてのバッチを依頼した
+	Batches          []backfillBatch `json:"batches"`
+	FailedReceiptIDs []string        `json:"failed_receipt_ids"`
+}
+
+// backfillBatch 依頼したバッチ
+type backfillBatch struct {
+	ID   string `json:"id"`
+	Done bool   `json:"done"` // 結果を反映した
+}
+
+// backfillJobPayload 読み直しジョブのペイロード
+type backfillJobPayload struct {
+	BackfillID string   `json:"backfill_id"`
+	ReceiptIDs []string `json:"receipt_ids"`
+}
+
+// BackfillUseCase 保存済みの元画像をAIのバッチ処理で読み直すユースケース
+// 読み取りの改善後に過去のレシートをまとめて読み直す場合など、急がない大量の読み取りを通常のAPIより安く行う。
+// バッチの依頼はジョブキューで行い、結果は定期的なPollBackfillsで確認して反映する。進み具合はジョブの記録先に保存する
+type BackfillUseCase struct {
+	receiptUseCase *ReceiptUseCase
+	batchRepo      domain.BatchAIRepository
+	tracker        sharedDomain.JobTracker
+	jobQueue       sharedDomain.JobQueue
+	rules          BackfillRules
+}
+
+// NewBackfillUseCase 新しいBackfillUseCaseを作成
+func NewBackfillUseCase(receiptUseCase *ReceiptUseCase, batchRepo domain.BatchAIRepository, tracker sharedDomain.JobTracker, rules BackfillRules) *BackfillUseCase {
+	return &BackfillUseCase{
+		receiptUseCase: receiptUseCase,
+		batchRepo:      batchRepo,
+		tracker:        tracker,
+		rules:          rules,
+	}
+}
+
+// SetJobQueue ジョブキューを設定し、バッチの依頼を非同期化する
+// 未設定の場合はStartBackfill内でバッチを依頼する
+func (uc *BackfillUseCase) SetJobQueue(jobQueue sharedDomain.JobQueue) {
+	uc.jobQueue = jobQueue
+	if jobQueue != nil {
+		jobQueue.Register(jobTypeReceiptBackfill, uc.handleBackfillJob)
+	}
+}
+
+// StartBackfill 指定したレシートの読み直しを開始
+func (uc *BackfillUseCase) StartBackfill(ctx context.Context, receiptIDs []string) (*Backfill, error) {
+	ids := make([]string, 0, len(receiptIDs))
+	for _, id := range receiptIDs {
+		if id == "" {
+			return nil, fmt.Errorf("%w: receipt_ids must not contain empty ids", ErrInvalidBackfill)
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: receipt_ids are required", ErrInvalidBackfill)
+	}
+	if uc.rules.MaxReceipts > 0 && len(ids) > uc.rules.MaxReceipts {
+		return nil, fmt.Errorf("%w: at most %d receipts can be backfilled at once", ErrInvalidBackfill, uc.rules.MaxReceipts)
+	}
+	if uc.receiptUseCase.imageStorage == nil {
+		return nil, ErrImageNotStored
+	}
+
+	now := uc.receiptUseCase.clock.Now()
+	status := &sharedDomain.JobStatus{
+		ID:        uc.receiptUseCase.newID(),
+		Type:      jobTypeReceiptBackfill,
+		State:     sharedDomain.JobStateRunning,
+		Total:     len(ids),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := uc.save(ctx, status, &backfillState{}); err != nil {
+		return nil, err
+	}
+
+	if uc.jobQueue == nil {
+		return uc.submit(ctx, status.ID, ids)
+	}
+	payload, err := json.Marshal(backfillJobPayload{BackfillID: status.ID, ReceiptIDs: ids})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal backfill job: %w", err)
+	}
+	if err := uc.jobQueue.Enqueue(ctx, jobTypeReceiptBackfill, payload); err != nil {
+		return nil, uc.fail(ctx, status, fmt.Errorf("failed to enqueue backfill job: %w", err))
+	}
+	return newBackfill(status, &backfillState{}), nil
+}
+
+// GetBackfill 読み直しの進み具合を取得
+func (uc *BackfillUseCase) GetBackfill(ctx context.Context, id string) (*Backfill, error) {
+	status, state, err := uc.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return newBackfill(status, state), nil
+}
+
+// handleBackfillJob 読み直しジョブを処理（バッチを依頼する）
+func (uc *BackfillUseCase) handleBackfillJob(ctx context.Context, job *sharedDomain.Job) error {
+	var payload backfillJobPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid backfill job payload: %w", err)
+	}
+	_, err := uc.submit(ctx, payload.BackfillID, payload.ReceiptIDs)
+	return err
+}
+
+// submit 元画像を読み込み、BatchSize件・BatchMaxBytesまでずつバッチを依頼
+// 画像はbase64でリクエストに含めるため、大きな画像が多い場合は件数より先にサイズで区切る（1枚でサイズを超える画像は単独で依頼する）。
+// 元画像のないレシート・依頼に失敗したバッチのレシートは失敗として数え、1つも依頼できなかった場合は読み直し全体を失敗とする
+func (uc *BackfillUseCase) submit(ctx context.Context, id string, receiptIDs []string) (*Backfill, error) {
+	status, state, err := uc.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// 停止したインスタンスから引き取ったジョブなどで、同じバッチを二重に依頼しない
+	if state.Submitted {
+		return newBackfill(status, state), nil
+	}
+
+	var lastErr error
+	var images []domain.BatchImage
+	var size int64
+	flush := func() {
+		if len(images) == 0 {
+			return
+		}
+		batchID, err := uc.batchRepo.SubmitReceiptBatch(images)
+		if err != nil {
+			slog.Error("Failed to submit backfill batch", "backfill_id", id, "receipts", len(images), "bytes", size, "error", err)
+			lastErr = err
+			for _, image := range images {
+				state.fail(status, image.CustomID)
+			}
+		} else {
+			state.Batches = append(state.Batches, backfillBatch{ID: batchID})
+		}
+		images, size = nil, 0
+	}
+
+	batchSize := max(uc.rules.BatchSize, 1)
+	for _, receiptID := range receiptIDs {
+		imageData, err := uc.receiptUseCase.imageStorage.Load(ctx, receiptID)
+		if err != nil {
+			slog.Warn("Skipped receipt without stored image for backfill", "backfill_id", id, "receipt_id", receiptID, "error", err)
+			state.fail(status, receiptID)
+			continue
+		}
+
+		encoded := int64(base64.StdEncoding.EncodedLen(len(imageData)))
+		if uc.rules.BatchMaxBytes > 0 && size+encoded > uc.rules.BatchMaxBytes {
+			flush()
+		}
+		images = append(images, domain.BatchImage{CustomID: receiptID, ImageData: imageData})
+		size += encoded
+		if len(images) >= batchSize {
+			flush()
+		}
+	}
+	flush()
+
+	state.Submitted = true
+	if len(state.Batches) == 0 {
+		if lastErr == nil {
+			lastErr = ErrImageNotStored
+		}
+		status.State = sharedDomain.JobStateFailed
+		status.Error = lastErr.Error()
+	}
+	status.UpdatedAt = uc.receiptUseCase.clock.Now()
+	if err := uc.save(ctx, status, state); err != nil {
+		return nil, err
+	}
+	return newBackfill(status, state), nil
+}
+
+// PollBackfills 処理中の読み直しのバッチの状況を確認し、処理が終わったバッチの結果をレシートに反映
+// すべてのバッチの結果を反映した読み直しは完了とし、反映したバッチ数を返す
+func (uc *BackfillUseCase) PollBackfills(ctx context.Context) (int, error) {
+	statuses, err := uc.tracker.ListRunning(ctx, jobTypeReceiptBackfill)
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, status := range statuses {
+		var state backfillState
+		if err := json.Unmarshal(status.Data, &state); err != nil {
+			return applied, fmt.Errorf("invalid backfill state: %w", err)
+		}
+		// バッチの依頼中はジョブの完了を待つ
+		if !state.Submitted {
+			continue
+		}
+
+		for i := range state.Batches {
+			batch := &state.Batches[i]
+			if batch.Done {
+				continue
+			}
+			ended, err := uc.applyBatch(ctx, status, &state, batch.ID)
+			if err != nil {
+				slog.Error("Failed to poll backfill batch", "backfill_id", status.ID, "batch_id", batch.ID, "error", err)
+				continue
+			}
+			if ended {
+				batch.Done = true
+				applied++
+			}
+		}
+
+		if !slices.ContainsFunc(state.Batches, func(b backfillBatch) bool { return !b.Done }) {
+			status.State = sharedDomain.JobStateCompleted
+		}
+		status.UpdatedAt = uc.receiptUseCase.clock.Now()
+		if err := uc.save(ctx, status, &state); err != nil {
+			return applied, err
+		}
+	}
+	return applied, nil
+}
+
+// applyBatch バッチの処理が終わっていれば結果をレシートに反映する（処理中の場合はfalse）
+func (uc *BackfillUseCase) applyBatch(ctx context.Context, status *sharedDomain.JobStatus, state *backfillState, batchID string) (bool, error) {
+	batch, err := uc.batchRepo.GetBatch(batchID)
+	if err != nil {
+		return false, err
+	}
+	if !batch.Ended {
+		return false, nil
+	}
+	results, err := uc.batchRepo.BatchResults(batchID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, result := range results {
+		if result.Result == nil {
+			slog.Warn("Backfill request failed", "backfill_id", status.ID, "receipt_id", result.CustomID, "error", result.Error)
+			state.fail(status, result.CustomID)
+			continue
+		}
+		if err := uc.applyResult(ctx, result.CustomID, result.Result); err != nil {
+			slog.Warn("Failed to apply backfill result", "backfill_id", status.ID, "receipt_id", result.CustomID, "error", err)
+			state.fail(status, result.CustomID)
+			continue
+		}
+		status.Succeeded++
+	}
+	return true, nil
+}
+
+// applyResult 読み直した結果でレシートを置き換える（依頼後に削除したレシートは失敗とする）
+func (uc *BackfillUseCase) applyResult(ctx context.Context, receiptID string, aiResult *domain.AIResult) error {
+	current, err := uc.receiptUseCase.receiptRepo.FindByID(ctx, receiptID)
+	if err != nil {
+		return err
+	}
+	_, err = uc.receiptUseCase.applyRecognition(ctx, current, aiResult, 0)
+	return err
+}
+
+// fail 読み直し全体を失敗として保存し、errを返す
+func (uc *BackfillUseCase) fail(ctx context.Context, status *sharedDomain.JobStatus, err error) error {
+	status.State = sharedDomain.JobStateFailed
+	status.Error = err.Error()
+	status.UpdatedAt = uc.receiptUseCase.clock.Now()
+	if saveErr := uc.save(ctx, status, &backfillState{Submitted: true}); saveErr != nil {
+		slog.Error("Failed to save backfill status", "backfill_id", status.ID, "error", saveErr)
+	}
+	return err
+}
+
+// load 読み直しの状態を取得
+func (uc *BackfillUseCase) load(ctx context.Context, id string) (*sharedDomain.JobStatus, *backfillState, error) {
+	status, err := uc.tracker.Get(ctx, id)
+	if errors.Is(err, sharedDomain.ErrJobNotFound) || (err == nil && status.Type != jobTypeReceiptBackfill) {
+		return nil, nil, ErrBackfillNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get backfill: %w", err)
+	}
+
+	var state backfillState
+	if err := json.Unmarshal(status.Data, &state); err != nil {
+		return nil, nil, fmt.Errorf("invalid backfill state: %w", err)
+	}
+	return status, &state, nil
+}
+
+// save 読み直しの状態を保存
+func (uc *BackfillUseCase) save(ctx context.Context, status *sharedDomain.JobStatus, state *backfillState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal backfill state: %w", err)
+	}
+	status.Data = data
+	if err := uc.tracker.Save(ctx, status); err != nil {
+		return fmt.Errorf("failed to save backfill: %w", err)
+	}
+	return nil
+}
+
+// fail レシートを読み直せなかったものとして数える
+func (s *backfillState) fail(status *sharedDomain.JobStatus, receiptID string) {
+	status.Failed++
+	s.FailedReceiptIDs = append(s.FailedReceiptIDs, receiptID)
+}
+
+// newBackfill 保存した状態から読み直しの進み具合を作成
+func newBackfill(status *sharedDomain.JobStatus, state *backfillState) *Backfill {
+	return &Backfill{
+		ID:               status.ID,
+		State:            status.State,
+		Total:            status.Total,
+		Succeeded:        status.Succeeded,
+		Failed:           status.Failed,
+		FailedReceiptIDs: state.FailedReceiptIDs,
+		Batches:          len(state.Batches),
+		Error:            status.Error,
+		CreatedAt:        status.CreatedAt,
+		UpdatedAt:        status.UpdatedAt,
+	}
+}
@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+	"vision-api-app/internal/modules/shared/infrastructure/queue"
+	"vision-api-app/internal/modules/shared/infrastructure/storage"
+	"vision-api-app/internal/modules/vision/domain"
+)
+
+// fakeBatchAIRepository 依頼されたバッチを記録し、endedに含めたバッチの結果を返すテスト用のバッチ処理
+type fakeBatchAIRepository struct {
+	submitted [][]string // バッチごとのCustomID
+	ended     map[string]bool
+	results   map[string][]domain.BatchResult
+}
+
+func (f *fakeBatchAIRepository) SubmitReceiptBatch(images []domain.BatchImage) (string, error) {
+	ids := make([]string, 0, len(images))
+	for _, image := range images {
+		ids = append(ids, image.CustomID)
+	}
+	f.submitted = append(f.submitted, ids)
+	return fmt.Sprintf("batch-%d", len(f.submitted)), nil
+}
+
+func (f *fakeBatchAIRepository) GetBatch(batchID string) (*domain.BatchStatus, error) {
+	return &domain.BatchStatus{ID: batchID, Ended: f.ended[batchID]}, nil
+}
+
+func (f *fakeBatchAIRepository) BatchResults(batchID string) ([]domain.BatchResult, error) {
+	if !f.ended[batchID] {
+		return nil, errors.New("not ended")
+	}
+	return f.results[batchID], nil
+}
+
+func newTestBackfillUseCase(t *testing.T, stored map[string]*entity.Receipt, batchRepo domain.BatchAIRepository) *BackfillUseCase {
+	t.Helper()
+	imageStorage, err := storage.NewLocalImageStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalImageStorage() error = %v", err)
+	}
+	for id := range stored {
+		if err := imageStorage.Save(context.Background(), id, []byte("image-"+id)); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	mockReceipt := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			receipt, ok := stored[id]
+			if !ok {
+				return nil, errors.New("not found")
+			}
+			copied := *receipt
+			return &copied, nil
+		},
+		UpdateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			stored[receipt.ID] = receipt
+			return nil
+		},
+	}
+	receiptUseCase := NewReceiptUseCase(&MockAIRepository{}, mockReceipt, nil)
+	receiptUseCase.SetImageStorage(imageStorage)
+	return NewBackfillUseCase(receiptUseCase, batchRepo, queue.NewMemoryJobTracker(), BackfillRules{BatchSize: 1, MaxReceipts: 10})
+}
+
+func TestBackfillUseCase_StartAndPoll(t *testing.T) {
+	stored := map[string]*entity.Receipt{
+		"receipt-1": {ID: "receipt-1", StoreName: "読み間違えた店", TotalAmount: 100},
+		"receipt-2": {ID: "receipt-2", StoreName: "Store", TotalAmount: 200},
+	}
+	batchRepo := &fakeBatchAIRepository{
+		ended: map[string]bool{},
+		results: map[string][]domain.BatchResult{
+			"batch-1": {{CustomID: "receipt-1", Result: domain.NewAIResult("", `{"store_name":"テストマート","purchase_date":"2025-06-01 10:00","total_amount":150,"items":[{"name":"牛乳","quantity":1,"price":150}]}`, 1200, 150, "test")}},
+			"batch-2": {{CustomID: "receipt-2", Error: "expired"}},
+		},
+	}
+	uc := newTestBackfillUseCase(t, stored, batchRepo)
+	ctx := context.Background()
+
+	// 元画像のないレシートは依頼せずに失敗として数える
+	backfill, err := uc.StartBackfill(ctx, []string{"receipt-1", "receipt-2", "receipt-3", "receipt-1"})
+	if err != nil {
+		t.Fatalf("StartBackfill() error = %v", err)
+	}
+	if backfill.State != sharedDomain.JobStateRunning || backfill.Total != 3 || backfill.Batches != 2 || backfill.Failed != 1 {
+		t.Errorf("StartBackfill() = %+v", backfill)
+	}
+	if len(batchRepo.submitted) != 2 || batchRepo.submitted[0][0] != "receipt-1" || batchRepo.submitted[1][0] != "receipt-2" {
+		t.Errorf("submitted = %v", batchRepo.submitted)
+	}
+
+	// 処理が終わったバッチの結果だけ反映する
+	batchRepo.ended["batch-1"] = true
+	applied, err := uc.PollBackfills(ctx)
+	if err != nil {
+		t.Fatalf("PollBackfills() error = %v", err)
+	}
+	if applied != 1 {
+		t.Errorf("applied = %d, want 1", applied)
+	}
+	if receipt := stored["receipt-1"]; receipt.StoreName != "テストマート" || receipt.TotalAmount != 150 || receipt.Extraction.Model != "test" {
+		t.Errorf("receipt-1 = %+v", receipt)
+	}
+	backfill, err = uc.GetBackfill(ctx, backfill.ID)
+	if err != nil {
+		t.Fatalf("GetBackfill() error = %v", err)
+	}
+	if backfill.State != sharedDomain.JobStateRunning || backfill.Succeeded != 1 {
+		t.Errorf("GetBackfill() = %+v", backfill)
+	}
+
+	batchRepo.ended["batch-2"] = true
+	if _, err := uc.PollBackfills(ctx); err != nil {
+		t.Fatalf("PollBackfills() error = %v", err)
+	}
+	backfill, err = uc.GetBackfill(ctx, backfill.ID)
+	if err != nil {
+		t.Fatalf("GetBackfill() error = %v", err)
+	}
+	if backfill.State != sharedDomain.JobStateCompleted || backfill.Succeeded != 1 || backfill.Failed != 2 {
+		t.Errorf("GetBackfill() = %+v", backfill)
+	}
+	if !slices.Equal(backfill.FailedReceiptIDs, []string{"receipt-3", "receipt-2"}) {
+		t.Errorf("FailedReceiptIDs = %v", backfill.FailedReceiptIDs)
+	}
+	if stored["receipt-2"].StoreName != "Store" {
+		t.Errorf("receipt-2 = %+v, want unchanged", stored["receipt-2"])
+	}
+}
+
+func TestBackfillUseCase_StartBackfill_BatchMaxBytes(t *testing.T) {
+	stored := map[string]*entity.Receipt{
+		"receipt-1": {ID: "receipt-1"},
+		"receipt-2": {ID: "receipt-2"},
+		"receipt-3": {ID: "receipt-3"},
+	}
+	batchRepo := &fakeBatchAIRepository{}
+	uc := newTestBackfillUseCase(t, stored, batchRepo)
+	// 元画像（"image-receipt-N"）はbase64で20バイトのため、件数に余裕があっても2件ずつに区切られる
+	uc.rules = BackfillRules{BatchSize: 10, BatchMaxBytes: 40, MaxReceipts: 10}
+
+	backfill, err := uc.StartBackfill(context.Background(), []string{"receipt-1", "receipt-2", "receipt-3"})
+	if err != nil {
+		t.Fatalf("StartBackfill() error = %v", err)
+	}
+	if backfill.Batches != 2 || backfill.Failed != 0 {
+		t.Errorf("StartBackfill() = %+v", backfill)
+	}
+	if len(batchRepo.submitted) != 2 || !slices.Equal(batchRepo.submitted[0], []string{"receipt-1", "receipt-2"}) || !slices.Equal(batchRepo.submitted[1], []string{"receipt-3"}) {
+		t.Errorf("submitted = %v", batchRepo.submitted)
+	}
+}
+
+func TestBackfillUseCase_StartBackfill_JobQueue(t *testing.T) {
+	stored := map[string]*entity.Receipt{"receipt-1": {ID: "receipt-1"}}
+	batchRepo := &fakeBatchAIRepository{}
+	uc := newTestBackfillUseCase(t, stored, batchRepo)
+	q := queue.NewMemoryQueue(1, 10)
+	defer func() {
+		_ = q.Close(context.Background())
+	}()
+	uc.SetJobQueue(q)
+	ctx := context.Background()
+
+	backfill, err := uc.StartBackfill(ctx, []string{"receipt-1"})
+	if err != nil {
+		t.Fatalf("StartBackfill() error = %v", err)
+	}
+	if err := q.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	backfill, err = uc.GetBackfill(ctx, backfill.ID)
+	if err != nil {
+		t.Fatalf("GetBackfill() error = %v", err)
+	}
+	if backfill.Batches != 1 || len(batchRepo.submitted) != 1 {
+		t.Errorf("GetBackfill() = %+v, submitted = %v", backfill, batchRepo.submitted)
+	}
+}
+
+func TestBackfillUseCase_Invalid(t *testing.T) {
+	uc := newTestBackfillUseCase(t, map[string]*entity.Receipt{}, &fakeBatchAIRepository{})
+	ctx := context.Background()
+
+	for name, ids := range map[string][]string{
+		"empty":      nil,
+		"blank":      {"receipt-1", ""},
+		"over limit": {"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := uc.StartBackfill(ctx, ids); !errors.Is(err, ErrInvalidBackfill) {
+				t.Errorf("StartBackfill() error = %v, want ErrInvalidBackfill", err)
+			}
+		})
+	}
+
+	// 元画像が1件もない場合は読み直し全体を失敗とする
+	backfill, err := uc.StartBackfill(ctx, []string{"missing"})
+	if err != nil {
+		t.Fatalf("StartBackfill() error = %v", err)
+	}
+	if backfill.State != sharedDomain.JobStateFailed || backfill.Error == "" {
+		t.Errorf("StartBackfill() = %+v, want failed", backfill)
+	}
+
+	if _, err := uc.GetBackfill(ctx, "unknown"); !errors.Is(err, ErrBackfillNotFound) {
+		t.Errorf("GetBackfill() error = %v, want ErrBackfillNotFound", err)
+	}
+}
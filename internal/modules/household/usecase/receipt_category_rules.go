@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+)
+
+// CategoryRules AIを使わずに商品名・摘要に含まれるキーワードでカテゴリーを判定するルール
+// AIの予算超過でカテゴリーの判定を縮退している間に使う
+type CategoryRules map[string][]string // カテゴリー → キーワード
+
+// SetCategoryRules AIを使わずにカテゴリーを判定するルールを設定する
+// 未設定の場合、縮退中の明細はすべて判定失敗（その他・要確認）として扱う
+func (uc *ReceiptUseCase) SetCategoryRules(rules CategoryRules) {
+	uc.categoryRules = rules
+}
+
+// categorize 名前にキーワードを含むカテゴリーを返す（カテゴリー名の順に調べ、最初に一致したもの）
+func (r CategoryRules) categorize(name string) (string, bool) {
+	for _, category := range slices.Sorted(maps.Keys(r)) {
+		for _, keyword := range r[category] {
+			if keyword != "" && strings.Contains(name, keyword) {
+				return category, true
+			}
+		}
+	}
+	return "", false
+}
+
+// categorizeItemsByRules ルールで判定待ちの明細のカテゴリーを判定（一致しない明細は判定失敗として扱う）
+func (uc *ReceiptUseCase) categorizeItemsByRules(receipt *entity.Receipt) {
+	for i, item := range receipt.Items {
+		if !item.IsCategoryPending() {
+			continue
+		}
+		if category, ok := uc.categoryRules.categorize(item.Name); ok {
+			receipt.CategorizeItem(i, category, entity.CategoryStatusAuto)
+		} else {
+			receipt.CategorizeItem(i, entity.DefaultCategory, entity.CategoryStatusAutoFailed)
+		}
+	}
+	receipt.NeedsReview = uc.needsReview(receipt)
+}
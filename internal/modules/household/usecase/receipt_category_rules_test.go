@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/vision/domain"
+)
+
+func TestReceiptUseCase_categorizeReceiptItems_Downgraded(t *testing.T) {
+	mockAI := &MockAIRepository{
+		CategorizeReceiptFunc: func(receiptInfo string) (*domain.AIResult, error) {
+			return nil, fmt.Errorf("%w: monthly AI budget exceeded", domain.ErrAIDowngraded)
+		},
+	}
+	uc := NewReceiptUseCase(mockAI, nil, nil)
+	uc.SetCategoryRules(CategoryRules{
+		"食費":  {"牛乳", "パン"},
+		"日用品": {"洗剤"},
+	})
+
+	receipt := &entity.Receipt{ID: "receipt-1", Items: []entity.ReceiptItem{{Name: "低脂肪牛乳"}, {Name: "衣料用洗剤"}, {Name: "文庫本"}}}
+	if err := uc.categorizeReceiptItems(context.Background(), receipt); err != nil {
+		t.Fatalf("categorizeReceiptItems() error = %v", err)
+	}
+
+	want := []struct{ category, status string }{
+		{"食費", entity.CategoryStatusAuto},
+		{"日用品", entity.CategoryStatusAuto},
+		{entity.DefaultCategory, entity.CategoryStatusAutoFailed},
+	}
+	for i, w := range want {
+		if item := receipt.Items[i]; item.Category != w.category || item.CategoryStatus != w.status {
+			t.Errorf("Items[%d] = (%s, %s), want (%s, %s)", i, item.Category, item.CategoryStatus, w.category, w.status)
+		}
+	}
+	if !receipt.NeedsReview {
+		t.Error("NeedsReview = false, want true")
+	}
+}
+
+func TestExpenseCategorizationUseCase_Downgraded(t *testing.T) {
+	mockAI := &MockAIRepository{
+		CategorizeReceiptFunc: func(receiptInfo string) (*domain.AIResult, error) {
+			return nil, domain.ErrAIDowngraded
+		},
+	}
+	receiptUseCase := NewReceiptUseCase(mockAI, nil, nil)
+	receiptUseCase.SetCategoryRules(CategoryRules{"交通費": {"タクシー"}})
+	uc := NewExpenseCategorizationUseCase(receiptUseCase)
+
+	result, err := uc.Categorize(context.Background(), []string{"ABCタクシー", "AMAZON.CO.JP"})
+	if err != nil {
+		t.Fatalf("Categorize() error = %v", err)
+	}
+	if got := result.Suggestions; got[0].Category != "交通費" || got[0].Status != entity.CategoryStatusAuto ||
+		got[1].Category != entity.DefaultCategory || got[1].Status != entity.CategoryStatusAutoFailed {
+		t.Errorf("Suggestions = %+v", got)
+	}
+}
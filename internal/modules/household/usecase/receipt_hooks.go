@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+)
+
+// ImageHook AIに送る前のレシート画像を加工するフック（傾き補正・トリミング・透かしの除去など）
+// 加工後の画像をAIに送り、元画像として保存する
+type ImageHook interface {
+	TransformImage(ctx context.Context, imageData []byte) ([]byte, error)
+}
+
+// ImageHookFunc 関数をImageHookとして使うためのアダプター
+type ImageHookFunc func(ctx context.Context, imageData []byte) ([]byte, error)
+
+// TransformImage 関数を呼び出す
+func (f ImageHookFunc) TransformImage(ctx context.Context, imageData []byte) ([]byte, error) {
+	return f(ctx, imageData)
+}
+
+// ReceiptHook 読み取ったレシートを保存前に補完するフック（会社の経費規程に応じたタグ付けなど）
+// 利用者が指定したタグ・メモを設定した後、保存・下書きの作成の前に呼び出す
+type ReceiptHook interface {
+	EnrichReceipt(ctx context.Context, receipt *entity.Receipt) error
+}
+
+// ReceiptHookFunc 関数をReceiptHookとして使うためのアダプター
+type ReceiptHookFunc func(ctx context.Context, receipt *entity.Receipt) error
+
+// EnrichReceipt 関数を呼び出す
+func (f ReceiptHookFunc) EnrichReceipt(ctx context.Context, receipt *entity.Receipt) error {
+	return f(ctx, receipt)
+}
+
+// transformImage 画像を加工するフックを順に適用する
+func (uc *ReceiptUseCase) transformImage(ctx context.Context, imageData []byte) ([]byte, error) {
+	for _, hook := range uc.imageHooks {
+		transformed, err := hook.TransformImage(ctx, imageData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to transform image: %w", err)
+		}
+		imageData = transformed
+	}
+	return imageData, nil
+}
+
+// enrichReceipt レシートを補完するフックを順に適用する
+// フックが追加したタグも利用者が指定したタグと同じく正規化する
+func (uc *ReceiptUseCase) enrichReceipt(ctx context.Context, receipt *entity.Receipt) error {
+	if len(uc.receiptHooks) == 0 {
+		return nil
+	}
+	for _, hook := range uc.receiptHooks {
+		if err := hook.EnrichReceipt(ctx, receipt); err != nil {
+			return fmt.Errorf("failed to enrich receipt: %w", err)
+		}
+	}
+	receipt.Tags = entity.NormalizeTags(receipt.Tags)
+	return nil
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/vision/domain"
+)
+
+func TestReceiptUseCase_Hooks(t *testing.T) {
+	var recognized []byte
+	mockAI := &MockAIRepository{
+		RecognizeReceiptFunc: func(imageData []byte) (*domain.AIResult, error) {
+			recognized = imageData
+			return domain.NewAIResult("", `{"store_name":"Test","purchase_date":"2025-11-23 12:00","total_amount":60000,"items":[]}`, 10, 5, "test"), nil
+		},
+	}
+	var created *entity.Receipt
+	mockReceipt := &MockReceiptRepository{
+		CreateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			created = receipt
+			return nil
+		},
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			return nil, errors.New("not found")
+		},
+	}
+	uc := NewReceiptUseCase(mockAI, mockReceipt, nil)
+	uc.SetImageHooks(
+		ImageHookFunc(func(ctx context.Context, imageData []byte) ([]byte, error) {
+			return append(slices.Clone(imageData), " cropped"...), nil
+		}),
+		ImageHookFunc(func(ctx context.Context, imageData []byte) ([]byte, error) {
+			return bytes.ToUpper(imageData), nil
+		}),
+	)
+	uc.SetReceiptHooks(ReceiptHookFunc(func(ctx context.Context, receipt *entity.Receipt) error {
+		if receipt.TotalAmount >= 50000 {
+			receipt.Tags = append(receipt.Tags, " 要承認 ", "出張")
+		}
+		return nil
+	}))
+
+	receipt, err := uc.ProcessReceiptImageWithOptions(context.Background(), []byte("receipt image"), ProcessOptions{Tags: []string{"出張"}})
+	if err != nil {
+		t.Fatalf("ProcessReceiptImageWithOptions() error = %v", err)
+	}
+
+	// 画像のフックは登録順に適用し、加工後の画像をAIに送る
+	if string(recognized) != "RECEIPT IMAGE CROPPED" {
+		t.Errorf("recognized image = %q, want %q", recognized, "RECEIPT IMAGE CROPPED")
+	}
+	// レシートのフックが追加したタグも正規化して保存する
+	if created != receipt || !slices.Equal(receipt.Tags, []string{"出張", "要承認"}) {
+		t.Errorf("Tags = %v, want [出張 要承認]", receipt.Tags)
+	}
+}
+
+func TestReceiptUseCase_HookErrors(t *testing.T) {
+	errHook := errors.New("hook failed")
+	tests := []struct {
+		name  string
+		setup func(uc *ReceiptUseCase)
+	}{
+		{
+			name: "画像の加工に失敗",
+			setup: func(uc *ReceiptUseCase) {
+				uc.SetImageHooks(ImageHookFunc(func(ctx context.Context, imageData []byte) ([]byte, error) {
+					return nil, errHook
+				}))
+			},
+		},
+		{
+			name: "レシートの補完に失敗",
+			setup: func(uc *ReceiptUseCase) {
+				uc.SetReceiptHooks(ReceiptHookFunc(func(ctx context.Context, receipt *entity.Receipt) error {
+					return errHook
+				}))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockReceipt := &MockReceiptRepository{
+				CreateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+					t.Error("Create() should not be called")
+					return nil
+				},
+				FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+					return nil, errors.New("not found")
+				},
+			}
+			uc := NewReceiptUseCase(&MockAIRepository{}, mockReceipt, nil)
+			tt.setup(uc)
+
+			// フックが失敗した場合はレシートを保存しない
+			if _, err := uc.ProcessReceiptImage(context.Background(), []byte("receipt image")); !errors.Is(err, errHook) {
+				t.Errorf("ProcessReceiptImage() error = %v, want %v", err, errHook)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+)
+
+// jsonCut 途中で切れたJSONを切り詰める位置と、その位置で閉じていない括弧を閉じる文字列
+type jsonCut struct {
+	end     int
+	closers []byte
+}
+
+// repairJSON AIの応答の途中で切れた・軽微な誤りのあるJSONを修復する（修復した場合はtrue）
+// 文字列の外の閉じ括弧の直前のカンマを取り除き、完結したJSONの後ろの余分な文字列は切り捨てる。
+// 閉じていない括弧は、最後に完結した値までで切り詰めてから閉じる。配列の要素のオブジェクト（明細など）は
+// 途中で切れたものを残さず、要素ごと捨てる。
+// 修復できない場合は元のデータとfalseを返す
+func repairJSON(data []byte) ([]byte, bool) {
+	if json.Valid(data) {
+		return data, false
+	}
+
+	trimmed := removeTrailingCommas(data)
+	if json.Valid(trimmed) {
+		return trimmed, true
+	}
+
+	var (
+		stack    []byte // 閉じていない括弧に対応する閉じ括弧
+		cuts     []jsonCut
+		inString bool
+		escaped  bool
+	)
+	for i, c := range trimmed {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{':
+			stack = append(stack, '}')
+		case '[':
+			stack = append(stack, ']')
+		case '}', ']':
+			if len(stack) == 0 || stack[len(stack)-1] != c {
+				return data, false
+			}
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				// 完結したJSONの後ろに説明文などが続く場合は切り捨てる
+				repaired := trimmed[:i+1]
+				if !json.Valid(repaired) {
+					return data, false
+				}
+				return repaired, true
+			}
+			if !insideArrayElement(stack) {
+				cuts = append(cuts, jsonCut{end: i + 1, closers: closersOf(stack)})
+			}
+		case ',':
+			if !insideArrayElement(stack) {
+				cuts = append(cuts, jsonCut{end: i, closers: closersOf(stack)})
+			}
+		}
+	}
+
+	// 途中で切れている場合は、後ろの切り詰め位置から順に閉じて有効なJSONになるものを使う
+	for k := len(cuts) - 1; k >= 0; k-- {
+		candidate := append(slices.Clip(trimmed[:cuts[k].end]), cuts[k].closers...)
+		if json.Valid(candidate) {
+			return candidate, true
+		}
+	}
+	return data, false
+}
+
+// insideArrayElement 配列の要素のオブジェクトの途中かチェック（最も内側の配列より内側にオブジェクトが開いている）
+func insideArrayElement(stack []byte) bool {
+	array := bytes.LastIndexByte(stack, ']')
+	return array >= 0 && array < len(stack)-1
+}
+
+// closersOf 閉じていない括弧を内側から閉じる文字列
+func closersOf(stack []byte) []byte {
+	closers := slices.Clone(stack)
+	slices.Reverse(closers)
+	return closers
+}
+
+// removeTrailingCommas 文字列の外で、閉じ括弧の直前（空白を挟む場合を含む）にあるカンマを取り除く
+func removeTrailingCommas(data []byte) []byte {
+	var (
+		out      bytes.Buffer
+		inString bool
+		escaped  bool
+	)
+	out.Grow(len(data))
+	for i, c := range data {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			out.WriteByte(c)
+			continue
+		}
+		if c == '"' {
+			inString = true
+		}
+		if c == ',' {
+			rest := bytes.TrimLeft(data[i+1:], " \t\r\n")
+			if len(rest) > 0 && (rest[0] == '}' || rest[0] == ']') {
+				continue
+			}
+		}
+		out.WriteByte(c)
+	}
+	return out.Bytes()
+}
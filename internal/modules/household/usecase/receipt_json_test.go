@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/vision/domain"
+)
+
+func TestRepairJSON(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         string
+		want         string
+		wantRepaired bool
+	}{
+		{
+			name: "正常なJSONはそのまま",
+			data: `{"store_name":"A","items":[]}`,
+			want: `{"store_name":"A","items":[]}`,
+		},
+		{
+			name:         "末尾のカンマ",
+			data:         `{"store_name":"A","items":[{"name":"x","price":1},],}`,
+			want:         `{"store_name":"A","items":[{"name":"x","price":1}]}`,
+			wantRepaired: true,
+		},
+		{
+			name:         "文字列中のカンマと括弧は変えない",
+			data:         `{"store_name":"A, ]","items":[{"name":"x,}","price":1},]}`,
+			want:         `{"store_name":"A, ]","items":[{"name":"x,}","price":1}]}`,
+			wantRepaired: true,
+		},
+		{
+			name:         "明細の途中で切れた応答",
+			data:         `{"store_name":"A","items":[{"name":"x","price":1},{"name":"y","pri`,
+			want:         `{"store_name":"A","items":[{"name":"x","price":1}]}`,
+			wantRepaired: true,
+		},
+		{
+			name:         "文字列の途中で切れた応答",
+			data:         `{"store_name":"A","items":[{"name":"x","price":1},{"name":"長い商品`,
+			want:         `{"store_name":"A","items":[{"name":"x","price":1}]}`,
+			wantRepaired: true,
+		},
+		{
+			name:         "最上位の項目の途中で切れた応答",
+			data:         `{"store_name":"A","total_amount":30`,
+			want:         `{"store_name":"A"}`,
+			wantRepaired: true,
+		},
+		{
+			name:         "JSONの後ろの説明文",
+			data:         `{"store_name":"A"} 以上です。`,
+			want:         `{"store_name":"A"}`,
+			wantRepaired: true,
+		},
+		{
+			name: "修復できない",
+			data: `not json`,
+			want: `not json`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, repaired := repairJSON([]byte(tt.data))
+			if string(got) != tt.want || repaired != tt.wantRepaired {
+				t.Errorf("repairJSON() = %s, %v, want %s, %v", got, repaired, tt.want, tt.wantRepaired)
+			}
+		})
+	}
+}
+
+func TestReceiptUseCase_parseReceiptJSON_Repaired(t *testing.T) {
+	mockAI := &MockAIRepository{
+		CategorizeReceiptFunc: func(receiptInfo string) (*domain.AIResult, error) {
+			return domain.NewAIResult(receiptInfo, `["食費","食費"]`, 10, 5, "test"), nil
+		},
+	}
+	uc := NewReceiptUseCase(mockAI, &MockReceiptRepository{}, &MockCacheRepository{})
+
+	receipt, err := uc.parseReceiptJSON("```json\n"+`{"store_name":"テストストア","total_amount":300,"items":[{"name":"牛乳","quantity":1,"price":200},{"name":"パン","quantity":1,"price":100},{"name":"卵","quan`, "receipt-1", time.UTC)
+	if err != nil {
+		t.Fatalf("parseReceiptJSON() error = %v", err)
+	}
+	if !receipt.JSONRepaired || !receipt.NeedsReview {
+		t.Errorf("JSONRepaired = %v, NeedsReview = %v, want both true", receipt.JSONRepaired, receipt.NeedsReview)
+	}
+	if len(receipt.Items) != 2 || receipt.TotalAmount != 300 {
+		t.Errorf("items = %d, total = %d, want 2 items totaling 300", len(receipt.Items), receipt.TotalAmount)
+	}
+
+	// 修復したレシートはカテゴリー判定後も要確認のまま
+	if err := uc.categorizeReceiptItems(context.Background(), receipt); err != nil {
+		t.Fatalf("categorizeReceiptItems() error = %v", err)
+	}
+	if receipt.HasFailedCategories() || !receipt.NeedsReview {
+		t.Errorf("NeedsReview = %v after categorization, want true", receipt.NeedsReview)
+	}
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+)
+
+// NameNormalizationReport 保存済みのレシートの店名・商品名の正規化結果
+type NameNormalizationReport struct {
+	DryRun     bool     `json:"dry_run"`     // trueの場合は検出のみで保存しない
+	Scanned    int      `json:"scanned"`     // 確認したレシート数
+	Normalized int      `json:"normalized"`  // 店名・商品名が変わったレシート数
+	Failed     int      `json:"failed"`      // 保存に失敗した件数
+	ChangedIDs []string `json:"changed_ids"` // 店名・商品名が変わったレシートのID
+}
+
+// NormalizeNames 保存済みのレシートの店名・商品名を、読み取り時と同じ規則で正規化する
+// 正規化の導入前や、置き換えルール・商品名の別名辞書を変更する前に登録したレシートに適用する。
+// 変更内容は変更履歴に normalize として記録する。dryRunの場合は件数の集計のみで保存しない
+func (uc *ReceiptUseCase) NormalizeNames(ctx context.Context, dryRun bool) (*NameNormalizationReport, error) {
+	if uc.nameNormalizer == nil && uc.itemAliasRepo == nil {
+		return nil, errors.New("name normalization is disabled (names.normalize is false and no item alias dictionary is configured)")
+	}
+	dictionary, err := uc.itemAliasDictionary(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &NameNormalizationReport{
+		DryRun:     dryRun,
+		ChangedIDs: []string{},
+	}
+
+	// 正規化しても一覧の並び順は変わらないため、offsetで順に読み進める
+	for offset := 0; ; offset += repairBatchSize {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		receipts, err := uc.receiptRepo.FindAll(ctx, repairBatchSize, offset)
+		if err != nil {
+			return report, fmt.Errorf("failed to list receipts: %w", err)
+		}
+
+		for _, receipt := range receipts {
+			report.Scanned++
+			normalized := uc.nameNormalizer != nil && uc.nameNormalizer.NormalizeReceipt(receipt)
+			canonicalized := dictionary.CanonicalizeReceipt(receipt)
+			if !normalized && !canonicalized {
+				continue
+			}
+			report.Normalized++
+			report.ChangedIDs = append(report.ChangedIDs, receipt.ID)
+			if dryRun {
+				continue
+			}
+			if err := uc.UpdateReceipt(ctx, receipt, entity.RevisionSourceNormalize); err != nil {
+				report.Failed++
+				slog.Error("Failed to normalize receipt names", "receipt_id", receipt.ID, "error", err)
+			}
+		}
+
+		if len(receipts) < repairBatchSize {
+			break
+		}
+	}
+
+	slog.Info("Receipt names normalization finished",
+		"dry_run", report.DryRun,
+		"scanned", report.Scanned,
+		"normalized", report.Normalized,
+		"failed", report.Failed,
+	)
+	return report, nil
+}
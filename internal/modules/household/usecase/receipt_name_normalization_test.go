@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+)
+
+func TestReceiptUseCase_NormalizeNames(t *testing.T) {
+	newReceipts := func() []*entity.Receipt {
+		return []*entity.Receipt{
+			{ID: "ok", StoreName: "ローソン", TotalAmount: 300, Items: []entity.ReceiptItem{{Name: "牛乳", Quantity: 1, Price: 300}}},
+			{ID: "store", StoreName: "ﾛ－ｿﾝ　新宿店", TotalAmount: 300, Items: []entity.ReceiptItem{{Name: "牛乳", Quantity: 1, Price: 300}}},
+			{ID: "item", StoreName: "㈱マルエツ", TotalAmount: 150, Items: []entity.ReceiptItem{{Name: "ｺｰﾋｰ  ＢＯＳＳ", Quantity: 1, Price: 150}}},
+		}
+	}
+
+	for _, dryRun := range []bool{false, true} {
+		receipts := newReceipts()
+		var updated []*entity.Receipt
+		mockReceipt := &MockReceiptRepository{
+			FindAllFunc: func(ctx context.Context, limit, offset int) ([]*entity.Receipt, error) {
+				if offset >= len(receipts) {
+					return []*entity.Receipt{}, nil
+				}
+				return receipts[offset:], nil
+			},
+			UpdateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+				updated = append(updated, receipt)
+				return nil
+			},
+		}
+		uc := NewReceiptUseCase(&MockAIRepository{}, mockReceipt, nil)
+		uc.SetNameNormalizer(entity.NewNameNormalizer([]entity.NameRule{
+			{Pattern: regexp.MustCompile(`^\(株\)`), Replace: ""},
+		}))
+
+		report, err := uc.NormalizeNames(context.Background(), dryRun)
+		if err != nil {
+			t.Fatalf("NormalizeNames(dryRun=%v) error = %v", dryRun, err)
+		}
+		if report.Scanned != 3 || report.Normalized != 2 || !slices.Equal(report.ChangedIDs, []string{"store", "item"}) {
+			t.Errorf("report = %+v, want scanned=3 normalized=2 changed=[store item]", report)
+		}
+		if dryRun {
+			if len(updated) != 0 {
+				t.Errorf("dry run updated %d receipts", len(updated))
+			}
+			continue
+		}
+		if len(updated) != 2 || updated[0].StoreName != "ローソン 新宿店" || updated[1].StoreName != "マルエツ" || updated[1].Items[0].Name != "コーヒー BOSS" {
+			t.Errorf("updated = %+v", updated)
+		}
+	}
+
+	if _, err := NewReceiptUseCase(&MockAIRepository{}, &MockReceiptRepository{}, nil).NormalizeNames(context.Background(), true); err == nil {
+		t.Error("Expected error when name normalization is disabled")
+	}
+}
+
+func TestReceiptUseCase_ParseReceiptJSON_NormalizeNames(t *testing.T) {
+	uc := NewReceiptUseCase(&MockAIRepository{}, &MockReceiptRepository{}, nil)
+	uc.SetNameNormalizer(entity.NewNameNormalizer(nil))
+
+	receipt, err := uc.parseReceiptJSON(`{"store_name":" ﾛｰｿﾝ ","items":[{"name":"ｷｬﾍﾞﾂ","quantity":1,"price":200},{"name":"　","quantity":1,"price":0}]}`, "receipt-1", time.UTC)
+	if err != nil {
+		t.Fatalf("parseReceiptJSON() error = %v", err)
+	}
+	if receipt.StoreName != "ローソン" {
+		t.Errorf("StoreName = %q, want ローソン", receipt.StoreName)
+	}
+	// 正規化して空になった明細は読み取らない
+	if len(receipt.Items) != 1 || receipt.Items[0].Name != "キャベツ" || receipt.Items[0].ID != uc.newItemID("receipt-1", 0, "キャベツ", 200) {
+		t.Errorf("Items = %+v, want [キャベツ]", receipt.Items)
+	}
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import "time"
+
+// PurchaseDateRules AIが読み取った購入日の妥当な範囲
+// 範囲は登録日時を基準とし、範囲外の購入日は数字の読み間違いの可能性があるため要確認にする
+type PurchaseDateRules struct {
+	MaxAgeYears     int           // 登録日時から何年より前の購入日を範囲外とするか（0は確認しない）
+	FutureTolerance time.Duration // 登録日時からどれだけ先までの購入日を許容するか（負の値は未来の日付を確認しない）
+	Clamp           bool          // 範囲外の購入日を置き換える（未来の日付は登録日時、古すぎる日付は範囲の下限）
+}
+
+// SetPurchaseDateRules 読み取った購入日の妥当な範囲を設定する
+// 未設定の場合は確認しない
+func (uc *ReceiptUseCase) SetPurchaseDateRules(rules PurchaseDateRules) {
+	uc.purchaseDateRules = &rules
+}
+
+// checkPurchaseDate 購入日が範囲内かを確認し、保存する購入日と範囲外かどうかを返す
+// Clampの場合は範囲外の購入日を登録日時・範囲の下限に置き換える
+func (r *PurchaseDateRules) checkPurchaseDate(date, now time.Time) (time.Time, bool) {
+	if r == nil {
+		return date, false
+	}
+	if r.FutureTolerance >= 0 {
+		if latest := now.Add(r.FutureTolerance); date.After(latest) {
+			if r.Clamp {
+				return now, true
+			}
+			return date, true
+		}
+	}
+	if r.MaxAgeYears > 0 {
+		if earliest := now.AddDate(-r.MaxAgeYears, 0, 0); date.Before(earliest) {
+			if r.Clamp {
+				return earliest, true
+			}
+			return date, true
+		}
+	}
+	return date, false
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+func TestPurchaseDateRules_checkPurchaseDate(t *testing.T) {
+	now := time.Date(2025, time.June, 10, 12, 0, 0, 0, time.UTC)
+	rules := &PurchaseDateRules{MaxAgeYears: 10, FutureTolerance: 24 * time.Hour}
+	clamp := &PurchaseDateRules{MaxAgeYears: 10, FutureTolerance: 24 * time.Hour, Clamp: true}
+
+	tests := []struct {
+		name         string
+		rules        *PurchaseDateRules
+		date         time.Time
+		want         time.Time
+		wantOutRange bool
+	}{
+		{name: "範囲内", rules: rules, date: now.AddDate(0, -1, 0), want: now.AddDate(0, -1, 0)},
+		{name: "タイムゾーンの違いの範囲", rules: rules, date: now.Add(20 * time.Hour), want: now.Add(20 * time.Hour)},
+		{name: "未来", rules: rules, date: now.AddDate(1, 0, 0), want: now.AddDate(1, 0, 0), wantOutRange: true},
+		{name: "古すぎる", rules: rules, date: time.Date(1975, time.June, 10, 0, 0, 0, 0, time.UTC), want: time.Date(1975, time.June, 10, 0, 0, 0, 0, time.UTC), wantOutRange: true},
+		{name: "未来を登録日時に置き換える", rules: clamp, date: now.AddDate(1, 0, 0), want: now, wantOutRange: true},
+		{name: "古すぎる日付を下限に置き換える", rules: clamp, date: time.Date(1975, time.June, 10, 0, 0, 0, 0, time.UTC), want: now.AddDate(-10, 0, 0), wantOutRange: true},
+		{name: "確認しない", rules: &PurchaseDateRules{FutureTolerance: -1}, date: time.Date(2099, time.January, 1, 0, 0, 0, 0, time.UTC), want: time.Date(2099, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "未設定", rules: nil, date: time.Date(1975, time.June, 10, 0, 0, 0, 0, time.UTC), want: time.Date(1975, time.June, 10, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, outOfRange := tt.rules.checkPurchaseDate(tt.date, now)
+			if !got.Equal(tt.want) || outOfRange != tt.wantOutRange {
+				t.Errorf("checkPurchaseDate() = %v, %v, want %v, %v", got, outOfRange, tt.want, tt.wantOutRange)
+			}
+		})
+	}
+}
+
+func TestReceiptUseCase_parseReceiptJSON_DateOutOfRange(t *testing.T) {
+	now := time.Date(2025, time.June, 10, 12, 0, 0, 0, time.UTC)
+	uc := NewReceiptUseCase(nil, nil, nil)
+	uc.SetClock(sharedDomain.FixedClock(now))
+	uc.SetPurchaseDateRules(PurchaseDateRules{MaxAgeYears: 10, FutureTolerance: 24 * time.Hour})
+
+	// 2025を1975と読み間違えたレシート
+	receipt, err := uc.parseReceiptJSON(`{"store_name":"Test","purchase_date":"1975-06-01 10:00","total_amount":100,"items":[{"name":"Item","quantity":1,"price":100}]}`, "receipt-1", time.UTC)
+	if err != nil {
+		t.Fatalf("parseReceiptJSON() error = %v", err)
+	}
+	if !receipt.DateOutOfRange || !receipt.NeedsReview || receipt.PurchaseDate.Year() != 1975 {
+		t.Errorf("receipt = (%v, %v, %v), want out of range and needs review", receipt.DateOutOfRange, receipt.NeedsReview, receipt.PurchaseDate)
+	}
+
+	// 購入日を修正すると確認済みとする
+	fixed := time.Date(2025, time.June, 1, 10, 0, 0, 0, time.UTC)
+	if err := uc.applyPatch(receipt, ReceiptPatch{PurchaseDate: &fixed}); err != nil {
+		t.Fatalf("applyPatch() error = %v", err)
+	}
+	if receipt.DateOutOfRange || receipt.NeedsReview {
+		t.Errorf("receipt = (%v, %v), want cleared", receipt.DateOutOfRange, receipt.NeedsReview)
+	}
+
+	// 購入日を修正しても、合計金額の不一致が残っている場合は要確認のまま
+	receipt, err = uc.parseReceiptJSON(`{"store_name":"Test","purchase_date":"1975-06-01 10:00","total_amount":300,"items":[{"name":"Item","quantity":1,"price":100}]}`, "receipt-3", time.UTC)
+	if err != nil {
+		t.Fatalf("parseReceiptJSON() error = %v", err)
+	}
+	if err := uc.applyPatch(receipt, ReceiptPatch{PurchaseDate: &fixed}); err != nil {
+		t.Fatalf("applyPatch() error = %v", err)
+	}
+	if receipt.DateOutOfRange || !receipt.NeedsReview {
+		t.Errorf("receipt = (%v, %v), want date cleared and still needs review", receipt.DateOutOfRange, receipt.NeedsReview)
+	}
+
+	// 明細を修正して合計金額と一致すると確認済みとする
+	items := []ItemPatch{{Name: "Item", Quantity: 1, Price: 300, Category: "食費"}}
+	if err := uc.applyPatch(receipt, ReceiptPatch{Items: &items}); err != nil {
+		t.Fatalf("applyPatch() error = %v", err)
+	}
+	if receipt.NeedsReview {
+		t.Error("NeedsReview = true after fixing the items, want false")
+	}
+
+	// 読み取れなかった購入日は登録日時のため範囲内
+	receipt, err = uc.parseReceiptJSON(`{"store_name":"Test","total_amount":100,"items":[{"name":"Item","quantity":1,"price":100}]}`, "receipt-2", time.UTC)
+	if err != nil {
+		t.Fatalf("parseReceiptJSON() error = %v", err)
+	}
+	if receipt.DateOutOfRange {
+		t.Error("Expected missing purchase date not to be out of range")
+	}
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+)
+
+// repairBatchSize 合計金額の修復で一度に読み込むレシート数
+const repairBatchSize = 100
+
+// TotalsRepairReport 合計金額の修復結果
+type TotalsRepairReport struct {
+	DryRun        bool     `json:"dry_run"`        // trueの場合は検出のみで保存しない
+	Scanned       int      `json:"scanned"`        // 確認したレシート数
+	Rederived     int      `json:"rederived"`      // 合計金額が未設定で明細の合計から求め直した件数
+	Mismatched    int      `json:"mismatched"`     // 合計金額が明細の合計と一致しない件数
+	Flagged       int      `json:"flagged"`        // 新たに要確認にした件数
+	Failed        int      `json:"failed"`         // 保存に失敗した件数
+	MismatchedIDs []string `json:"mismatched_ids"` // 合計金額が明細の合計と一致しないレシートのID
+}
+
+// RepairTotals 保存済みのレシートの合計金額を明細から検証・修復する
+// 合計金額が未設定（0）のレシートは明細の合計で求め直し、明細の合計と一致しないレシートは要確認にする。
+// 印字された合計金額を正とするため、一致しない場合も合計金額自体は書き換えない。
+// 修復内容は変更履歴に repair として記録する。dryRunの場合は件数の集計のみで保存しない
+func (uc *ReceiptUseCase) RepairTotals(ctx context.Context, dryRun bool) (*TotalsRepairReport, error) {
+	report := &TotalsRepairReport{
+		DryRun:        dryRun,
+		MismatchedIDs: []string{},
+	}
+
+	// 修復で要確認になったレシートも一覧の並び順は変わらないため、offsetで順に読み進める
+	for offset := 0; ; offset += repairBatchSize {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		receipts, err := uc.receiptRepo.FindAll(ctx, repairBatchSize, offset)
+		if err != nil {
+			return report, fmt.Errorf("failed to list receipts: %w", err)
+		}
+
+		for _, receipt := range receipts {
+			report.Scanned++
+			if !uc.repairTotal(receipt, report) || dryRun {
+				continue
+			}
+			if err := uc.UpdateReceipt(ctx, receipt, entity.RevisionSourceRepair); err != nil {
+				report.Failed++
+				slog.Error("Failed to repair receipt total", "receipt_id", receipt.ID, "error", err)
+			}
+		}
+
+		if len(receipts) < repairBatchSize {
+			break
+		}
+	}
+
+	slog.Info("Receipt totals repair finished",
+		"dry_run", report.DryRun,
+		"scanned", report.Scanned,
+		"rederived", report.Rederived,
+		"mismatched", report.Mismatched,
+		"flagged", report.Flagged,
+		"failed", report.Failed,
+	)
+	return report, nil
+}
+
+// repairTotal レシート1件の合計金額を検証し、修復内容を集計する（変更が必要な場合はtrue）
+func (uc *ReceiptUseCase) repairTotal(receipt *entity.Receipt, report *TotalsRepairReport) bool {
+	changed := false
+
+	if receipt.TotalAmount == 0 && receipt.ItemsTotal() > 0 {
+		receipt.TotalAmount = receipt.ItemsTotal()
+		report.Rederived++
+		changed = true
+	}
+
+	if receipt.HasTotalMismatch() {
+		report.Mismatched++
+		report.MismatchedIDs = append(report.MismatchedIDs, receipt.ID)
+		if !receipt.NeedsReview {
+			receipt.NeedsReview = true
+			report.Flagged++
+			changed = true
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+)
+
+func TestReceiptUseCase_RepairTotals(t *testing.T) {
+	newReceipts := func() []*entity.Receipt {
+		return []*entity.Receipt{
+			// 一致している
+			{ID: "ok", StoreName: "A", TotalAmount: 300, Items: []entity.ReceiptItem{{Name: "牛乳", Quantity: 1, Price: 300}}},
+			// 外税（明細の合計 + 消費税額）
+			{ID: "tax", StoreName: "B", TotalAmount: 330, TaxAmount: 30, Items: []entity.ReceiptItem{{Name: "パン", Quantity: 2, Price: 150}}},
+			// 合計金額が未設定
+			{ID: "zero", StoreName: "C", TotalAmount: 0, Items: []entity.ReceiptItem{{Name: "卵", Quantity: 1, Price: 250}}},
+			// 明細の合計と一致しない
+			{ID: "mismatch", StoreName: "D", TotalAmount: 500, Items: []entity.ReceiptItem{{Name: "米", Quantity: 1, Price: 800}}},
+			// 一致しないが確認待ち済み
+			{ID: "flagged", StoreName: "E", TotalAmount: 100, NeedsReview: true, Items: []entity.ReceiptItem{{Name: "水", Quantity: 1, Price: 200}}},
+			// 明細なし
+			{ID: "no-items", StoreName: "F", TotalAmount: 1000},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		dryRun      bool
+		updateErr   error
+		wantUpdated []string
+		wantFailed  int
+	}{
+		{name: "正常系: 修復して保存", wantUpdated: []string{"zero", "mismatch"}},
+		{name: "正常系: dry_runでは保存しない", dryRun: true},
+		{name: "異常系: 保存に失敗した件数を集計", updateErr: errors.New("db error"), wantFailed: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipts := newReceipts()
+			var updated []*entity.Receipt
+			mockReceipt := &MockReceiptRepository{
+				FindAllFunc: func(ctx context.Context, limit, offset int) ([]*entity.Receipt, error) {
+					if offset >= len(receipts) {
+						return []*entity.Receipt{}, nil
+					}
+					return receipts[offset:], nil
+				},
+				UpdateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+					if tt.updateErr != nil {
+						return tt.updateErr
+					}
+					updated = append(updated, receipt)
+					return nil
+				},
+			}
+			uc := NewReceiptUseCase(&MockAIRepository{}, mockReceipt, &MockCacheRepository{})
+
+			report, err := uc.RepairTotals(context.Background(), tt.dryRun)
+			if err != nil {
+				t.Fatalf("RepairTotals() error = %v", err)
+			}
+
+			if report.DryRun != tt.dryRun || report.Scanned != 6 || report.Rederived != 1 || report.Mismatched != 2 || report.Flagged != 1 || report.Failed != tt.wantFailed {
+				t.Errorf("RepairTotals() report = %+v", report)
+			}
+			if len(report.MismatchedIDs) != 2 || report.MismatchedIDs[0] != "mismatch" || report.MismatchedIDs[1] != "flagged" {
+				t.Errorf("MismatchedIDs = %v, want [mismatch flagged]", report.MismatchedIDs)
+			}
+
+			if len(updated) != len(tt.wantUpdated) {
+				t.Fatalf("updated %d receipts, want %d", len(updated), len(tt.wantUpdated))
+			}
+			for i, id := range tt.wantUpdated {
+				if updated[i].ID != id {
+					t.Errorf("updated[%d] = %s, want %s", i, updated[i].ID, id)
+				}
+			}
+			if len(updated) == 2 {
+				if updated[0].TotalAmount != 250 {
+					t.Errorf("rederived TotalAmount = %d, want 250", updated[0].TotalAmount)
+				}
+				// 一致しない場合も印字された合計金額は書き換えない
+				if updated[1].TotalAmount != 500 || !updated[1].NeedsReview {
+					t.Errorf("mismatched receipt = %+v, want TotalAmount 500 and NeedsReview", updated[1])
+				}
+			}
+		})
+	}
+}
@@ -6,19 +6,131 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
+
 	"vision-api-app/internal/modules/household/domain/entity"
 	"vision-api-app/internal/modules/household/domain/repository"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
 	"vision-api-app/internal/modules/vision/domain"
 )
 
+const (
+	// jobTypeCategorizeReceipt 明細カテゴリー判定ジョブの種別
+	jobTypeCategorizeReceipt = "receipt.categorize"
+	// jobTypeScrubReceipt 削除・保持期限切れレシートの関連データ消去ジョブの種別
+	jobTypeScrubReceipt = "receipt.scrub"
+)
+
+// cacheKeyPrefixes レシート画像のハッシュから作られるキャッシュキーの接頭辞とAIの処理の種類
+// vision APIの解析結果も同じ画像ハッシュでキャッシュされるため、あわせて消去する
+var cacheKeyPrefixes = []struct{ prefix, operation string }{
+	{prefix: "receipt", operation: domain.OperationRecognizeReceipt},
+	{prefix: "analyze", operation: domain.OperationRecognizeImage},
+}
+
+var (
+	// ErrReceiptNotFound 指定されたレシートが存在しない
+	ErrReceiptNotFound = errors.New("receipt not found")
+	// ErrRevisionNotFound 指定されたリビジョンが存在しない
+	ErrRevisionNotFound = errors.New("receipt revision not found")
+	// ErrImageNotStored 再処理に必要な元画像が保存されていない
+	ErrImageNotStored = errors.New("original receipt image is not stored")
+	// ErrExtractionNotStored AIの出力の原文が保存されていない（手動登録・記録する前に登録したレシート）
+	ErrExtractionNotStored = errors.New("raw AI extraction is not stored")
+	// ErrInvalidReceipt 保存・修正するレシートが不正（店名・明細が空、金額が負など）
+	ErrInvalidReceipt = errors.New("invalid receipt")
+	// ErrSavePending データベースに接続できないため一時保管した（復旧後に保存される）
+	ErrSavePending = errors.New("receipt save is pending until the database recovers")
+)
+
+// categorizeJobPayload 明細カテゴリー判定ジョブのペイロード
+type categorizeJobPayload struct {
+	ReceiptID string `json:"receipt_id"`
+}
+
+// scrubJobPayload 関連データ消去ジョブのペイロード
+type scrubJobPayload struct {
+	ReceiptID string `json:"receipt_id"`
+	Reason    string `json:"reason"`
+}
+
+// ProcessOptions レシート登録時に利用者が指定する付加情報
+type ProcessOptions struct {
+	Tags []string
+	Memo string
+	// KeepLocation 位置情報などのメタデータを画像に残す（利用者が位置情報の保存に同意した場合のみ）
+	KeepLocation bool
+	// CreatedAt 作成日時（過去のレシートをまとめて取り込む場合に指定、ゼロ値の場合は現在時刻）
+	// 購入日を読み取れなかった場合の購入日にも使う。下書きは確認した日時で登録するため使わない
+	CreatedAt time.Time
+}
+
+// ReceiptPatch レシートの部分更新内容（nilの項目は変更しない）
+type ReceiptPatch struct {
+	StoreName    *string
+	PurchaseDate *time.Time
+	TotalAmount  *int64  // 通貨の最小単位
+	ReceiptType  *string // entity.ReceiptTypePurchase・entity.ReceiptTypeRefund
+	Tags         *[]string
+	Memo         *string
+	Items        *[]ItemPatch // 指定した場合は明細全体を置き換える
+}
+
+// SaveStatus レシートの保存先の状態
+type SaveStatus struct {
+	DatabaseAvailable bool // データベースに接続できるか
+	SpoolEnabled      bool // 障害時の一時保管が有効か
+	PendingSaves      int  // 一時保管中でデータベースへの保存を待っているレシート数
+}
+
+// ItemPatch 明細の修正内容
+// IDが既存の明細と一致し、カテゴリーが空の場合は既存のカテゴリーを引き継ぐ
+// 保証期間も同様に、nilの場合は既存の明細の値を引き継ぐ。単位が空の場合は既存の明細の単位・計量値・単価を引き継ぐ
+type ItemPatch struct {
+	ID             string
+	Name           string
+	Quantity       int
+	Price          int64 // 通貨の最小単位（量り売りの明細は計量した金額）
+	Unit           string
+	Measure        *float64
+	UnitPrice      *int64 // 1kgあたりの単価
+	Category       string
+	WarrantyMonths *int
+}
+
 // ReceiptUseCase レシート処理のユースケース
 type ReceiptUseCase struct {
-	aiRepo      domain.AIRepository
-	receiptRepo repository.ReceiptRepository
-	cacheRepo   repository.CacheRepository
+	aiRepo       domain.AIRepository
+	receiptRepo  repository.ReceiptRepository
+	cacheRepo    repository.CacheRepository
+	jobQueue     sharedDomain.JobQueue
+	revisionRepo repository.ReceiptRevisionRepository
+	imageStorage sharedDomain.ImageStorage
+
+	metadataStripper sharedDomain.MetadataStripper
+	fileScanner      sharedDomain.FileScanner
+	receiptSpool     repository.ReceiptSpool
+	idGenerator      sharedDomain.IDGenerator
+	currency         sharedDomain.Currency
+	eventPublisher   sharedDomain.EventPublisher
+	nameNormalizer   *entity.NameNormalizer
+	itemAliasRepo    repository.ItemAliasRepository
+	categoryRepo     repository.CategoryRepository
+	imageHooks       []ImageHook
+	receiptHooks     []ReceiptHook
+	clock            sharedDomain.Clock
+
+	purchaseDateRules *PurchaseDateRules
+	categoryRules     CategoryRules
 }
 
 // NewReceiptUseCase 新しいReceiptUseCaseを作成
@@ -27,19 +139,236 @@ func NewReceiptUseCase(aiRepo domain.AIRepository, receiptRepo repository.Receip
 		aiRepo:      aiRepo,
 		receiptRepo: receiptRepo,
 		cacheRepo:   cacheRepo,
+		currency:    sharedDomain.DefaultCurrency,
+		clock:       sharedDomain.SystemClock{},
 	}
 }
 
+// SetClock 作成日時・更新日時・購入日の既定値に使う時計を設定する
+// 未設定の場合はシステムの時計を使う
+func (uc *ReceiptUseCase) SetClock(clock sharedDomain.Clock) {
+	uc.clock = clock
+}
+
+// SetNameNormalizer 読み取った店名・商品名の正規化を設定する
+// 未設定の場合はAIの応答のまま保存する
+func (uc *ReceiptUseCase) SetNameNormalizer(nameNormalizer *entity.NameNormalizer) {
+	uc.nameNormalizer = nameNormalizer
+}
+
+// SetItemAliasRepository 商品名の別名辞書を設定する
+// 設定した場合は、読み取った商品名のうち別名に一致するものを正式な商品名に置き換える
+func (uc *ReceiptUseCase) SetItemAliasRepository(itemAliasRepo repository.ItemAliasRepository) {
+	uc.itemAliasRepo = itemAliasRepo
+}
+
+// SetCategoryRepository カテゴリーの取得元を設定する
+// 設定した場合は、登録されているカテゴリーの中からAIにカテゴリーを判定させる（未設定の場合は既定のカテゴリー）
+func (uc *ReceiptUseCase) SetCategoryRepository(categoryRepo repository.CategoryRepository) {
+	uc.categoryRepo = categoryRepo
+}
+
+// SetCurrency 金額の通貨を設定する
+// AIの応答の金額（小数）はこの通貨の最小単位の整数に変換して保存する
+func (uc *ReceiptUseCase) SetCurrency(currency sharedDomain.Currency) {
+	uc.currency = currency
+}
+
+// Currency 金額の通貨
+func (uc *ReceiptUseCase) Currency() sharedDomain.Currency {
+	return uc.currency
+}
+
+// SetJobQueue ジョブキューを設定し、明細カテゴリー判定を非同期化する
+// 未設定の場合はProcessReceiptImage内で同期的に判定する
+func (uc *ReceiptUseCase) SetJobQueue(jobQueue sharedDomain.JobQueue) {
+	uc.jobQueue = jobQueue
+	if jobQueue != nil {
+		jobQueue.Register(jobTypeCategorizeReceipt, uc.handleCategorizeJob)
+		jobQueue.Register(jobTypeScrubReceipt, uc.handleScrubJob)
+	}
+}
+
+// SetRevisionRepository 変更履歴リポジトリを設定する
+// 未設定の場合はUpdateReceiptで変更履歴を記録しない
+func (uc *ReceiptUseCase) SetRevisionRepository(revisionRepo repository.ReceiptRevisionRepository) {
+	uc.revisionRepo = revisionRepo
+}
+
+// SetIDGenerator レシート・明細・変更履歴の識別子の生成方式を設定する
+// 未設定の場合は画像から決定的に生成する従来の方式（レシートIDは画像のハッシュ、明細IDはレシートID・順番・商品名・単価のハッシュ）
+func (uc *ReceiptUseCase) SetIDGenerator(idGenerator sharedDomain.IDGenerator) {
+	uc.idGenerator = idGenerator
+}
+
+// SetImageStorage 元画像の保存先を設定する
+// 未設定の場合は画像を保存せず、再処理もできない
+func (uc *ReceiptUseCase) SetImageStorage(imageStorage sharedDomain.ImageStorage) {
+	uc.imageStorage = imageStorage
+}
+
+// SetMetadataStripper 画像メタデータの除去処理を設定する
+// 未設定の場合は受け取った画像をそのまま解析・保存する
+func (uc *ReceiptUseCase) SetMetadataStripper(metadataStripper sharedDomain.MetadataStripper) {
+	uc.metadataStripper = metadataStripper
+}
+
+// SetFileScanner アップロードファイルのウイルス検査を設定する
+// 未設定の場合は検査しない
+func (uc *ReceiptUseCase) SetFileScanner(fileScanner sharedDomain.FileScanner) {
+	uc.fileScanner = fileScanner
+}
+
+// SetReceiptSpool データベース障害時のレシートの一時保管先を設定する
+// 未設定の場合はデータベースに保存できないレシートの登録をエラーにする
+func (uc *ReceiptUseCase) SetReceiptSpool(receiptSpool repository.ReceiptSpool) {
+	uc.receiptSpool = receiptSpool
+}
+
+// SetEventPublisher ドメインイベントの配信先を設定する
+// レシートの登録・合計金額の修正・明細のカテゴリー判定を保存後に配信する。未設定の場合は配信しない
+func (uc *ReceiptUseCase) SetEventPublisher(eventPublisher sharedDomain.EventPublisher) {
+	uc.eventPublisher = eventPublisher
+}
+
+// SetImageHooks AIに送る前の画像を加工するフックを設定する（登録順に呼び出す）
+// 未設定の場合はメタデータの除去のみ行った画像をそのまま解析・保存する
+func (uc *ReceiptUseCase) SetImageHooks(hooks ...ImageHook) {
+	uc.imageHooks = hooks
+}
+
+// SetReceiptHooks 読み取ったレシートを補完するフックを設定する（登録順に呼び出す）
+func (uc *ReceiptUseCase) SetReceiptHooks(hooks ...ReceiptHook) {
+	uc.receiptHooks = hooks
+}
+
 // ProcessReceiptImage レシート画像を処理してデータベースに保存
 func (uc *ReceiptUseCase) ProcessReceiptImage(ctx context.Context, imageData []byte) (*entity.Receipt, error) {
+	return uc.ProcessReceiptImageWithOptions(ctx, imageData, ProcessOptions{})
+}
+
+// ProcessReceiptImageWithOptions タグなどの付加情報を指定してレシート画像を処理
+// データベースに接続できずレシートを一時保管した場合は、レシートとErrSavePendingを返す
+func (uc *ReceiptUseCase) ProcessReceiptImageWithOptions(ctx context.Context, imageData []byte, opts ProcessOptions) (*entity.Receipt, error) {
+	createdAt, err := uc.createdAt(opts)
+	if err != nil {
+		return nil, err
+	}
+	imageData, receiptJSON, extraction, err := uc.recognize(ctx, imageData, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// 既に同じ画像のレシートが存在する場合は、それを返す
+	imageHash := sha256.Sum256(imageData)
+	if existingReceipt := uc.findDuplicate(ctx, hex.EncodeToString(imageHash[:]), imageData); existingReceipt != nil {
+		return existingReceipt, nil
+	}
+
+	// JSONをパース（IDを渡してパース時に設定）
+	receipt, err := uc.parseReceiptJSONAt(receiptJSON, uc.newReceiptID(imageData), sharedDomain.LocationFromContext(ctx), createdAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse receipt JSON: %w", err)
+	}
+	uc.canonicalizeItems(ctx, receipt)
+	receipt.ImageHash = hex.EncodeToString(imageHash[:])
+	receipt.Extraction = extraction
+	receipt.Tags = entity.NormalizeTags(opts.Tags)
+	receipt.Memo = strings.TrimSpace(opts.Memo)
+	if err := uc.enrichReceipt(ctx, receipt); err != nil {
+		return nil, err
+	}
+	receipt.RecordCreated()
+
+	// ジョブキューが設定されている場合は、カテゴリー未判定のまま先に保存して後から更新する
+	if uc.jobQueue != nil {
+		if err := uc.persist(ctx, receipt, uc.receiptRepo.Create); err != nil {
+			if !errors.Is(err, ErrSavePending) {
+				return nil, fmt.Errorf("failed to save receipt: %w", err)
+			}
+			// カテゴリー判定ジョブはデータベースへの書き戻し後に投入する
+			uc.saveImage(ctx, receipt.ID, imageData)
+			return receipt, err
+		}
+		uc.publishEvents(ctx, receipt)
+		uc.saveImage(ctx, receipt.ID, imageData)
+		uc.enqueueCategorization(ctx, receipt)
+		return receipt, nil
+	}
+
+	// 明細項目ごとにカテゴリーを判定
+	// カテゴリー判定エラーは致命的ではないので無視
+	_ = uc.categorizeReceiptItems(ctx, receipt)
+
+	// データベースに保存
+	if err := uc.persist(ctx, receipt, uc.receiptRepo.Create); err != nil {
+		if !errors.Is(err, ErrSavePending) {
+			return nil, fmt.Errorf("failed to save receipt: %w", err)
+		}
+		uc.saveImage(ctx, receipt.ID, imageData)
+		return receipt, err
+	}
+	uc.publishEvents(ctx, receipt)
+	uc.saveImage(ctx, receipt.ID, imageData)
+
+	return receipt, nil
+}
+
+// createdAt 登録するレシートの作成日時（指定がない場合は現在時刻、未来の日時は受け付けない）
+func (uc *ReceiptUseCase) createdAt(opts ProcessOptions) (time.Time, error) {
+	now := uc.clock.Now()
+	if opts.CreatedAt.IsZero() {
+		return now, nil
+	}
+	if opts.CreatedAt.After(now) {
+		return time.Time{}, fmt.Errorf("%w: created_at must not be in the future", ErrInvalidReceipt)
+	}
+	return opts.CreatedAt, nil
+}
+
+// recognize 画像を検査・前処理してAIでレシートを読み取り、前処理後の画像と読み取り結果のJSON、読み取りの記録を返す
+// 同じ画像の読み取り結果はキャッシュを使う
+func (uc *ReceiptUseCase) recognize(ctx context.Context, imageData []byte, opts ProcessOptions) ([]byte, string, entity.ReceiptExtraction, error) {
+	var extraction entity.ReceiptExtraction
+
+	// ウイルス検査: 検出されたファイルや検査できなかったファイルは一切処理しない
+	if uc.fileScanner != nil {
+		if err := uc.fileScanner.Scan(ctx, imageData); err != nil {
+			return nil, "", extraction, fmt.Errorf("failed to scan image: %w", err)
+		}
+	}
+
+	// 前処理: 保存・AI送信の前に撮影位置などのメタデータを取り除く
+	// ハッシュも除去後の画像で計算するため、メタデータだけが異なる同じ写真は同じレシートになる
+	if uc.metadataStripper != nil && !opts.KeepLocation {
+		stripped, err := uc.metadataStripper.StripMetadata(imageData)
+		if err != nil {
+			return nil, "", extraction, fmt.Errorf("failed to strip image metadata: %w", err)
+		}
+		imageData = stripped
+	}
+
+	// 導入先ごとの画像の加工（ハッシュ・キャッシュも加工後の画像で扱う）
+	imageData, err := uc.transformImage(ctx, imageData)
+	if err != nil {
+		return nil, "", extraction, err
+	}
+
+	// 保存容量の上限を超える場合はAIを呼び出す前に拒否する
+	if err := uc.checkStorageQuota(ctx, int64(len(imageData))); err != nil {
+		return nil, "", extraction, err
+	}
+
 	// キャッシュキーの生成（画像データのSHA256ハッシュ）
-	cacheKey := uc.generateCacheKey("receipt", imageData)
+	cacheKey := uc.generateCacheKey("receipt", domain.OperationRecognizeReceipt, imageData)
+	startedAt := time.Now()
 
 	// キャッシュチェック
 	var receiptJSON string
 	if uc.cacheRepo != nil {
 		if cached, err := uc.cacheRepo.Get(ctx, cacheKey); err == nil && len(cached) > 0 {
 			receiptJSON = string(cached)
+			extraction.CacheHit = true
 		}
 	}
 
@@ -47,47 +376,532 @@ func (uc *ReceiptUseCase) ProcessReceiptImage(ctx context.Context, imageData []b
 	if receiptJSON == "" {
 		aiResult, err := uc.aiRepo.RecognizeReceipt(imageData)
 		if err != nil {
-			return nil, fmt.Errorf("failed to recognize receipt: %w", err)
+			return nil, "", extraction, fmt.Errorf("failed to recognize receipt: %w", err)
 		}
 		receiptJSON = aiResult.CorrectedText
+		extraction.Model = aiResult.Model
+		extraction.StopReason = aiResult.StopReason
 
 		// キャッシュに保存（24時間）
 		if uc.cacheRepo != nil {
 			_ = uc.cacheRepo.Set(ctx, cacheKey, []byte(receiptJSON), 24*time.Hour)
 		}
 	}
+	extraction.DurationMs = time.Since(startedAt).Milliseconds()
+	extraction.Raw = receiptJSON
 
-	// 画像ハッシュから一意のレシートIDを生成
-	receiptID := uc.generateDeterministicReceiptID(imageData)
+	return imageData, receiptJSON, extraction, nil
+}
 
-	// 既存のレシートをチェック
-	existingReceipt, err := uc.receiptRepo.FindByID(ctx, receiptID)
-	if err == nil && existingReceipt != nil {
-		// 既に同じ画像のレシートが存在する場合は、それを返す
-		return existingReceipt, nil
+// persist レシートをデータベースに保存
+// データベースに接続できない場合は一時保管してErrSavePendingを返し、復旧後にReplayPendingSavesで書き戻す
+func (uc *ReceiptUseCase) persist(ctx context.Context, receipt *entity.Receipt, save func(context.Context, *entity.Receipt) error) error {
+	// 不正なレシートは保存も一時保管もしない
+	if err := validateReceipt(receipt); err != nil {
+		return err
 	}
 
-	// JSONをパース（IDを渡してパース時に設定）
-	receipt, err := uc.parseReceiptJSON(receiptJSON, receiptID)
+	err := save(ctx, receipt)
+	if err == nil || uc.receiptSpool == nil || uc.databaseAvailable(ctx) {
+		return err
+	}
+
+	if spoolErr := uc.receiptSpool.Put(ctx, receipt); spoolErr != nil {
+		return errors.Join(err, fmt.Errorf("failed to spool receipt: %w", spoolErr))
+	}
+	slog.Warn("Database unavailable, receipt spooled until recovery",
+		"receipt_id", receipt.ID,
+		"error", err,
+	)
+	return ErrSavePending
+}
+
+// databaseAvailable データベースに接続できるか確認
+// 接続を確認できないリポジトリの場合は、保存エラーを障害によるものとみなさない
+func (uc *ReceiptUseCase) databaseAvailable(ctx context.Context) bool {
+	checker, ok := uc.receiptRepo.(sharedDomain.HealthChecker)
+	if !ok {
+		return true
+	}
+	return checker.Ping(ctx) == nil
+}
+
+// ReplayPendingSaves 一時保管中のレシートをデータベースへ書き戻し、書き戻した件数を返す
+// データベースが復旧していない場合は何もしない
+func (uc *ReceiptUseCase) ReplayPendingSaves(ctx context.Context) (int, error) {
+	if uc.receiptSpool == nil {
+		return 0, nil
+	}
+
+	receipts, err := uc.receiptSpool.List(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse receipt JSON: %w", err)
+		return 0, fmt.Errorf("failed to list spooled receipts: %w", err)
+	}
+	if len(receipts) == 0 || !uc.databaseAvailable(ctx) {
+		return 0, nil
 	}
 
-	// 明細項目ごとにカテゴリーを判定
-	// カテゴリー判定エラーは致命的ではないので無視
-	_ = uc.categorizeReceiptItems(receipt)
+	replayed := 0
+	for _, receipt := range receipts {
+		// 保存できていたのに応答が失われた場合や、保存後の更新を一時保管した場合は上書きする
+		// 一時保管したレシートはイベントを持たないため、登録のイベントは書き戻し時に記録する
+		if _, err := uc.receiptRepo.FindByID(ctx, receipt.ID); err == nil {
+			err = uc.receiptRepo.Update(ctx, receipt)
+		} else {
+			receipt.RecordCreated()
+			err = uc.receiptRepo.Create(ctx, receipt)
+		}
+		if err != nil {
+			return replayed, fmt.Errorf("failed to replay spooled receipt %s: %w", receipt.ID, err)
+		}
+		uc.publishEvents(ctx, receipt)
+		if err := uc.receiptSpool.Remove(ctx, receipt.ID); err != nil {
+			return replayed, fmt.Errorf("failed to remove replayed receipt %s: %w", receipt.ID, err)
+		}
+		replayed++
 
-	// データベースに保存
-	if err := uc.receiptRepo.Create(ctx, receipt); err != nil {
-		return nil, fmt.Errorf("failed to save receipt: %w", err)
+		if uc.jobQueue != nil && receipt.HasPendingCategories() {
+			uc.enqueueCategorization(ctx, receipt)
+		}
+	}
+
+	slog.Info("Spooled receipts replayed", "count", replayed)
+	return replayed, nil
+}
+
+// SaveStatus データベースへの接続状態と一時保管中のレシート数を取得
+func (uc *ReceiptUseCase) SaveStatus(ctx context.Context) (SaveStatus, error) {
+	status := SaveStatus{
+		DatabaseAvailable: uc.databaseAvailable(ctx),
+		SpoolEnabled:      uc.receiptSpool != nil,
+	}
+	if uc.receiptSpool == nil {
+		return status, nil
+	}
+
+	receipts, err := uc.receiptSpool.List(ctx)
+	if err != nil {
+		return status, fmt.Errorf("failed to list spooled receipts: %w", err)
+	}
+	status.PendingSaves = len(receipts)
+	return status, nil
+}
+
+// findSpooledReceipt 一時保管中のレシートを取得（見つからない場合はnil）
+func (uc *ReceiptUseCase) findSpooledReceipt(ctx context.Context, id string) *entity.Receipt {
+	if uc.receiptSpool == nil {
+		return nil
+	}
+	receipts, err := uc.receiptSpool.List(ctx)
+	if err != nil {
+		return nil
+	}
+	for _, receipt := range receipts {
+		if receipt.ID == id {
+			return receipt
+		}
+	}
+	return nil
+}
+
+// StorageUsage 元画像の保存容量の使用状況を取得
+// 使用状況を返せない保存先の場合は空の使用状況（無制限）を返す
+func (uc *ReceiptUseCase) StorageUsage(ctx context.Context) (sharedDomain.StorageUsage, error) {
+	reporter, ok := uc.imageStorage.(sharedDomain.StorageUsageReporter)
+	if !ok {
+		return sharedDomain.StorageUsage{}, nil
+	}
+	return reporter.Usage(ctx)
+}
+
+// checkStorageQuota 指定したサイズの元画像を保存すると上限を超える場合はErrStorageQuotaExceededを返す
+// 使用状況を取得できない場合は登録を妨げない
+func (uc *ReceiptUseCase) checkStorageQuota(ctx context.Context, size int64) error {
+	usage, err := uc.StorageUsage(ctx)
+	if err != nil {
+		slog.Warn("Failed to get storage usage", "error", err)
+		return nil
+	}
+	if usage.Exceeds(size) {
+		return fmt.Errorf("%w: %d of %d bytes used", sharedDomain.ErrStorageQuotaExceeded, usage.UsedBytes, usage.QuotaBytes)
+	}
+	return nil
+}
+
+// saveImage 再処理用に元画像を保存
+// 保存に失敗してもレシートの登録自体は成功として扱う
+func (uc *ReceiptUseCase) saveImage(ctx context.Context, receiptID string, imageData []byte) {
+	if uc.imageStorage == nil {
+		return
+	}
+	if err := uc.imageStorage.Save(ctx, receiptID, imageData); err != nil {
+		slog.Warn("Failed to store receipt image", "receipt_id", receiptID, "error", err)
+	}
+}
+
+// ReprocessReceipt 保存済みの元画像を現在のプロンプト・モデルで再解析する
+// キャッシュは参照せず、結果は新しいリビジョンとして保存してキャッシュも更新する
+func (uc *ReceiptUseCase) ReprocessReceipt(ctx context.Context, id string) (*entity.Receipt, error) {
+	current, err := uc.receiptRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if uc.imageStorage == nil {
+		return nil, ErrImageNotStored
+	}
+	imageData, err := uc.imageStorage.Load(ctx, id)
+	if errors.Is(err, sharedDomain.ErrImageNotFound) {
+		return nil, ErrImageNotStored
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load receipt image: %w", err)
+	}
+
+	startedAt := time.Now()
+	aiResult, err := uc.aiRepo.RecognizeReceipt(imageData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recognize receipt: %w", err)
+	}
+
+	receipt, err := uc.applyRecognition(ctx, current, aiResult, time.Since(startedAt))
+	if err != nil {
+		return nil, err
+	}
+
+	if uc.cacheRepo != nil {
+		_ = uc.cacheRepo.Set(ctx, uc.generateCacheKey("receipt", domain.OperationRecognizeReceipt, imageData), []byte(aiResult.CorrectedText), 24*time.Hour)
 	}
 
 	return receipt, nil
 }
 
+// applyRecognition 読み直した結果で保存済みのレシートを置き換えて保存（再処理・バッチでの読み直しで共通）
+// durationは読み取りにかかった時間（バッチの場合は0）
+func (uc *ReceiptUseCase) applyRecognition(ctx context.Context, current *entity.Receipt, aiResult *domain.AIResult, duration time.Duration) (*entity.Receipt, error) {
+	receipt, err := uc.parseReceiptJSON(aiResult.CorrectedText, current.ID, sharedDomain.LocationFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse receipt JSON: %w", err)
+	}
+	uc.canonicalizeItems(ctx, receipt)
+	receipt.CreatedAt = current.CreatedAt
+	receipt.ImageHash = current.ImageHash
+	receipt.Extraction = entity.ReceiptExtraction{
+		Model:      aiResult.Model,
+		StopReason: aiResult.StopReason,
+		DurationMs: duration.Milliseconds(),
+		Raw:        aiResult.CorrectedText,
+	}
+	if err := uc.enrichReceipt(ctx, receipt); err != nil {
+		return nil, err
+	}
+
+	// 再処理結果をリビジョンに含めるため、カテゴリー判定は同期的に行う
+	_ = uc.categorizeReceiptItems(ctx, receipt)
+
+	if err := uc.UpdateReceipt(ctx, receipt, entity.RevisionSourceReprocess); err != nil {
+		return nil, fmt.Errorf("failed to save reprocessed receipt: %w", err)
+	}
+	return receipt, nil
+}
+
+// ExtractionComparison 保存したAIの出力の原文と、原文を解析し直したレシート・現在のレシートの比較
+type ExtractionComparison struct {
+	Raw           string          // AIの出力の原文
+	Extracted     *entity.Receipt // 原文を現在の解析処理で読み取ったレシート（カテゴリーは判定しない）
+	Current       *entity.Receipt // 保存されている現在のレシート（利用者の修正を含む）
+	ChangedFields []string        // 読み取った値と現在の値が異なる項目
+}
+
+// CompareExtraction 保存したAIの出力の原文を解析し直し、現在のレシートと比較する
+// AIは呼び出さないため、解析処理の変更や利用者の修正による読み取り精度の評価・監査に使う
+func (uc *ReceiptUseCase) CompareExtraction(ctx context.Context, id string) (*ExtractionComparison, error) {
+	current, err := uc.receiptRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current.Extraction.Raw == "" {
+		return nil, ErrExtractionNotStored
+	}
+
+	extracted, err := uc.parseReceiptJSON(current.Extraction.Raw, id, sharedDomain.LocationFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse receipt JSON: %w", err)
+	}
+	uc.canonicalizeItems(ctx, extracted)
+	extracted.ImageHash = current.ImageHash
+	extracted.Extraction = current.Extraction
+	extracted.CreatedAt = current.CreatedAt
+	extracted.UpdatedAt = current.UpdatedAt
+
+	return &ExtractionComparison{
+		Raw:           current.Extraction.Raw,
+		Extracted:     extracted,
+		Current:       current,
+		ChangedFields: changedReceiptFields(extracted, current),
+	}, nil
+}
+
+// changedReceiptFields 2つのレシートで値が異なる項目（APIのフィールド名）
+// 明細は並び順・商品名・数量・単位・金額を比較し、IDやカテゴリーは比較しない
+func changedReceiptFields(a, b *entity.Receipt) []string {
+	fields := []string{}
+	if a.StoreName != b.StoreName {
+		fields = append(fields, "store_name")
+	}
+	if !a.PurchaseDate.Equal(b.PurchaseDate) {
+		fields = append(fields, "purchase_date")
+	}
+	if a.TotalAmount != b.TotalAmount {
+		fields = append(fields, "total_amount")
+	}
+	if a.TaxAmount != b.TaxAmount {
+		fields = append(fields, "tax_amount")
+	}
+	if a.PaymentMethod != b.PaymentMethod {
+		fields = append(fields, "payment_method")
+	}
+	if a.ReceiptNumber != b.ReceiptNumber {
+		fields = append(fields, "receipt_number")
+	}
+	if a.Type != b.Type {
+		fields = append(fields, "receipt_type")
+	}
+	if a.Currency != b.Currency {
+		fields = append(fields, "currency")
+	}
+	if !slices.EqualFunc(a.Items, b.Items, func(x, y entity.ReceiptItem) bool {
+		return x.Name == y.Name && x.Quantity == y.Quantity && x.Unit == y.Unit && x.Price == y.Price
+	}) {
+		fields = append(fields, "items")
+	}
+	return fields
+}
+
+// enqueueCategorization 明細カテゴリー判定ジョブを投入
+// 投入できない場合はその場で判定して更新する
+func (uc *ReceiptUseCase) enqueueCategorization(ctx context.Context, receipt *entity.Receipt) {
+	if len(receipt.Items) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(categorizeJobPayload{ReceiptID: receipt.ID})
+	if err == nil {
+		if err = uc.jobQueue.Enqueue(ctx, jobTypeCategorizeReceipt, payload); err == nil {
+			return
+		}
+	}
+
+	slog.Warn("Failed to enqueue categorization, categorizing synchronously",
+		"receipt_id", receipt.ID,
+		"error", err,
+	)
+	_ = uc.categorizeReceiptItems(ctx, receipt)
+	receipt.UpdatedAt = uc.clock.Now()
+	switch err := uc.persist(ctx, receipt, uc.receiptRepo.Update); {
+	case err == nil:
+		uc.publishEvents(ctx, receipt)
+	case !errors.Is(err, ErrSavePending):
+		slog.Error("Failed to update item categories", "receipt_id", receipt.ID, "error", err)
+	}
+}
+
+// handleCategorizeJob 保存済みレシートの明細カテゴリーを判定して更新
+func (uc *ReceiptUseCase) handleCategorizeJob(ctx context.Context, job *sharedDomain.Job) error {
+	var payload categorizeJobPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid categorize job payload: %w", err)
+	}
+
+	receipt, err := uc.receiptRepo.FindByID(ctx, payload.ReceiptID)
+	if err != nil {
+		return fmt.Errorf("failed to load receipt for categorization: %w", err)
+	}
+	if !slices.ContainsFunc(receipt.Items, func(item entity.ReceiptItem) bool { return item.IsCategoryPending() }) {
+		return nil
+	}
+
+	_ = uc.categorizeReceiptItems(ctx, receipt)
+
+	// AIの判定を待つ間に手動で変更された内容を上書きしないよう、読み直したレシートの判定待ちのままの明細にだけ判定結果を反映する
+	latest, err := uc.receiptRepo.FindByID(ctx, payload.ReceiptID)
+	if err != nil {
+		return fmt.Errorf("failed to reload receipt for categorization: %w", err)
+	}
+	if !uc.mergeItemCategories(latest, receipt) {
+		return nil
+	}
+	latest.UpdatedAt = uc.clock.Now()
+
+	// 判定結果が失われないよう、データベースに接続できない場合は一時保管する
+	switch err := uc.persist(ctx, latest, uc.receiptRepo.Update); {
+	case err == nil:
+		uc.publishEvents(ctx, latest)
+	case !errors.Is(err, ErrSavePending):
+		return fmt.Errorf("failed to update item categories: %w", err)
+	}
+	return nil
+}
+
+// mergeItemCategories 判定したレシートの明細カテゴリーを、最新のレシートの判定待ちのままの明細（IDと商品名が同じもの）に反映
+// 反映した明細がない場合はfalseを返す
+func (uc *ReceiptUseCase) mergeItemCategories(latest, categorized *entity.Receipt) bool {
+	results := make(map[string]entity.ReceiptItem, len(categorized.Items))
+	for _, item := range categorized.Items {
+		if !item.IsCategoryPending() {
+			results[item.ID] = item
+		}
+	}
+
+	merged := false
+	for i, item := range latest.Items {
+		result, ok := results[item.ID]
+		if !ok || !item.IsCategoryPending() || item.Name != result.Name {
+			continue
+		}
+		latest.CategorizeItem(i, result.Category, result.CategoryStatus)
+		merged = true
+	}
+	if merged {
+		latest.CategorizationRaw = categorized.CategorizationRaw
+		latest.NeedsReview = uc.needsReview(latest)
+	}
+	return merged
+}
+
+// DeleteReceipt レシートを削除し、元画像とキャッシュの消去を依頼
+// 消去はジョブキューで行い、キューがない場合はその場で行う
+func (uc *ReceiptUseCase) DeleteReceipt(ctx context.Context, id string) error {
+	if _, err := uc.receiptRepo.FindByID(ctx, id); err != nil {
+		return err
+	}
+	if err := uc.receiptRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete receipt: %w", err)
+	}
+
+	uc.requestScrub(ctx, id, "deleted")
+	return nil
+}
+
+// ScrubExpiredImages 保持期間を過ぎた元画像とキャッシュの消去を依頼
+// レシートのデータ自体は残し、消去を依頼した件数を返す
+func (uc *ReceiptUseCase) ScrubExpiredImages(ctx context.Context, retention time.Duration) (int, error) {
+	if uc.imageStorage == nil || retention <= 0 {
+		return 0, nil
+	}
+
+	keys, err := uc.imageStorage.ListBefore(ctx, uc.clock.Now().Add(-retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expired images: %w", err)
+	}
+
+	for _, key := range keys {
+		uc.requestScrub(ctx, key, "expired")
+	}
+	return len(keys), nil
+}
+
+// requestScrub 関連データ消去ジョブを投入
+// 投入できない場合はその場で消去する
+func (uc *ReceiptUseCase) requestScrub(ctx context.Context, receiptID, reason string) {
+	if uc.jobQueue != nil {
+		payload, err := json.Marshal(scrubJobPayload{ReceiptID: receiptID, Reason: reason})
+		if err == nil {
+			if err = uc.jobQueue.Enqueue(ctx, jobTypeScrubReceipt, payload); err == nil {
+				return
+			}
+		}
+		slog.Warn("Failed to enqueue scrub, scrubbing synchronously",
+			"receipt_id", receiptID,
+			"error", err,
+		)
+	}
+
+	if err := uc.scrubReceipt(ctx, receiptID, reason); err != nil {
+		slog.Error("Failed to scrub receipt data", "receipt_id", receiptID, "error", err)
+	}
+}
+
+// handleScrubJob 関連データ消去ジョブを処理
+func (uc *ReceiptUseCase) handleScrubJob(ctx context.Context, job *sharedDomain.Job) error {
+	var payload scrubJobPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid scrub job payload: %w", err)
+	}
+	return uc.scrubReceipt(ctx, payload.ReceiptID, payload.Reason)
+}
+
+// scrubReceipt レシートの元画像と、その画像から作られたキャッシュを消去して結果を検証
+// キャッシュキーは画像のハッシュから求めるため、画像より先にキャッシュを消去する
+func (uc *ReceiptUseCase) scrubReceipt(ctx context.Context, receiptID, reason string) error {
+	if uc.imageStorage == nil {
+		return nil
+	}
+
+	imageData, err := uc.imageStorage.Load(ctx, receiptID)
+	if errors.Is(err, sharedDomain.ErrImageNotFound) {
+		slog.Info("Receipt image already scrubbed", "receipt_id", receiptID, "reason", reason)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load receipt image: %w", err)
+	}
+
+	var cacheKeys []string
+	if uc.cacheRepo != nil {
+		for _, p := range cacheKeyPrefixes {
+			// 現在のプロンプトの版のキーと、版を含めていなかった頃のキーを消去する
+			keys := []string{uc.generateCacheKey(p.prefix, p.operation, imageData)}
+			if legacy := domain.AICacheKey(p.prefix, imageData, ""); legacy != keys[0] {
+				keys = append(keys, legacy)
+			}
+			for _, key := range keys {
+				if err := uc.cacheRepo.Delete(ctx, key); err != nil {
+					return fmt.Errorf("failed to delete cache entry: %w", err)
+				}
+				cacheKeys = append(cacheKeys, key)
+			}
+		}
+	}
+
+	if err := uc.imageStorage.Delete(ctx, receiptID); err != nil {
+		return fmt.Errorf("failed to delete receipt image: %w", err)
+	}
+
+	// 消去できたことを確認してから完了としてログに残す
+	if _, err := uc.imageStorage.Load(ctx, receiptID); !errors.Is(err, sharedDomain.ErrImageNotFound) {
+		return fmt.Errorf("receipt image still exists after scrub: %s", receiptID)
+	}
+	for _, key := range cacheKeys {
+		exists, err := uc.cacheRepo.Exists(ctx, key)
+		if err != nil {
+			return fmt.Errorf("failed to verify cache entry: %w", err)
+		}
+		if exists {
+			return fmt.Errorf("cache entry still exists after scrub: %s", key)
+		}
+	}
+
+	slog.Info("Receipt data scrubbed",
+		"receipt_id", receiptID,
+		"reason", reason,
+		"image_deleted", true,
+		"cache_keys_deleted", len(cacheKeys),
+		"verified", true,
+	)
+	return nil
+}
+
 // GetReceipt レシートを取得
+// データベースへの保存を待っている一時保管中のレシートも取得できる
 func (uc *ReceiptUseCase) GetReceipt(ctx context.Context, id string) (*entity.Receipt, error) {
-	return uc.receiptRepo.FindByID(ctx, id)
+	receipt, err := uc.receiptRepo.FindByID(ctx, id)
+	if err != nil {
+		if spooled := uc.findSpooledReceipt(ctx, id); spooled != nil {
+			return spooled, nil
+		}
+	}
+	return receipt, err
 }
 
 // ListReceipts レシート一覧を取得
@@ -95,8 +909,270 @@ func (uc *ReceiptUseCase) ListReceipts(ctx context.Context, limit, offset int) (
 	return uc.receiptRepo.FindAll(ctx, limit, offset)
 }
 
-// parseReceiptJSON JSONからレシートエンティティを作成
-func (uc *ReceiptUseCase) parseReceiptJSON(receiptJSON string, receiptID string) (*entity.Receipt, error) {
+// SearchReceipts 検索条件で絞り込んだレシート一覧を取得（条件が空の場合は全件）
+func (uc *ReceiptUseCase) SearchReceipts(ctx context.Context, filter repository.ReceiptFilter, limit, offset int) ([]*entity.Receipt, error) {
+	if filter == (repository.ReceiptFilter{}) {
+		return uc.receiptRepo.FindAll(ctx, limit, offset)
+	}
+	return uc.receiptRepo.FindByFilter(ctx, filter, limit, offset)
+}
+
+// PatchReceipt レシートを部分的に修正し、手動修正として変更履歴に記録
+func (uc *ReceiptUseCase) PatchReceipt(ctx context.Context, id string, patch ReceiptPatch) (*entity.Receipt, error) {
+	receipt, err := uc.receiptRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.applyPatch(receipt, patch); err != nil {
+		return nil, err
+	}
+
+	if err := uc.UpdateReceipt(ctx, receipt, entity.RevisionSourceManual); err != nil {
+		return nil, fmt.Errorf("failed to update receipt: %w", err)
+	}
+	return receipt, nil
+}
+
+// ReplaceReceipt レシートの編集できる項目をすべて置き換える（PUT）
+// 店舗名・購入日・合計金額・明細は必須で、種類・タグ・メモを省略した場合は既定値（購入・なし）にする
+func (uc *ReceiptUseCase) ReplaceReceipt(ctx context.Context, id string, replacement ReceiptPatch) (*entity.Receipt, error) {
+	switch {
+	case replacement.StoreName == nil:
+		return nil, fmt.Errorf("%w: store_name is required", ErrInvalidReceipt)
+	case replacement.PurchaseDate == nil:
+		return nil, fmt.Errorf("%w: purchase_date is required", ErrInvalidReceipt)
+	case replacement.TotalAmount == nil:
+		return nil, fmt.Errorf("%w: total_amount is required", ErrInvalidReceipt)
+	case replacement.Items == nil:
+		return nil, fmt.Errorf("%w: items is required", ErrInvalidReceipt)
+	}
+	if replacement.ReceiptType == nil {
+		receiptType := entity.ReceiptTypePurchase
+		replacement.ReceiptType = &receiptType
+	}
+	if replacement.Tags == nil {
+		replacement.Tags = &[]string{}
+	}
+	if replacement.Memo == nil {
+		memo := ""
+		replacement.Memo = &memo
+	}
+	return uc.PatchReceipt(ctx, id, replacement)
+}
+
+// applyPatch 修正内容をレシートに反映して検証する（不正な場合はErrInvalidReceipt）
+func (uc *ReceiptUseCase) applyPatch(receipt *entity.Receipt, patch ReceiptPatch) error {
+	if patch.StoreName != nil {
+		receipt.StoreName = strings.TrimSpace(*patch.StoreName)
+	}
+	if patch.PurchaseDate != nil {
+		// 利用者が購入日を修正した場合は範囲外の記録を消し、修正で確認済みとする
+		receipt.PurchaseDate = *patch.PurchaseDate
+		receipt.DateOutOfRange = false
+	}
+	if patch.TotalAmount != nil {
+		receipt.CorrectTotal(*patch.TotalAmount)
+	}
+	if patch.ReceiptType != nil {
+		receipt.Type = strings.TrimSpace(*patch.ReceiptType)
+	}
+	if patch.Tags != nil {
+		receipt.Tags = entity.NormalizeTags(*patch.Tags)
+	}
+	if patch.Memo != nil {
+		receipt.Memo = strings.TrimSpace(*patch.Memo)
+	}
+	if patch.Items != nil {
+		receipt.Items = uc.patchItems(receipt, *patch.Items)
+	}
+	// 修正で解消した理由だけを外し、合計金額の不一致など残っている理由があれば要確認のままにする
+	if patch.PurchaseDate != nil || patch.TotalAmount != nil || patch.Items != nil {
+		receipt.NeedsReview = uc.needsReview(receipt)
+	}
+
+	return validateReceipt(receipt)
+}
+
+// validateReceipt 保存前にレシートと明細を検証する（不正な場合は理由を付けたErrInvalidReceipt）
+func validateReceipt(receipt *entity.Receipt) error {
+	if err := receipt.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidReceipt, err)
+	}
+	return nil
+}
+
+// patchItems 修正内容から明細を作り直す
+// 手動で指定したカテゴリーは手動設定とし、カテゴリー未指定の新しい明細は要確認にする
+func (uc *ReceiptUseCase) patchItems(receipt *entity.Receipt, patches []ItemPatch) []entity.ReceiptItem {
+	existing := make(map[string]entity.ReceiptItem, len(receipt.Items))
+	for _, item := range receipt.Items {
+		existing[item.ID] = item
+	}
+
+	items := make([]entity.ReceiptItem, 0, len(patches))
+	for i, patch := range patches {
+		item := entity.ReceiptItem{
+			// IDの形式はparseReceiptJSONと揃える
+			ID:        uc.newItemID(receipt.ID, i, strings.TrimSpace(patch.Name), patch.Price),
+			ReceiptID: receipt.ID,
+			Name:      strings.TrimSpace(patch.Name),
+			Quantity:  patch.Quantity,
+			Price:     patch.Price,
+			CreatedAt: uc.clock.Now(),
+		}
+
+		prev, found := existing[patch.ID]
+		category := strings.TrimSpace(patch.Category)
+		switch {
+		case found && category == "",
+			found && category == prev.Category && prev.CategoryStatus != entity.CategoryStatusAutoFailed:
+			// 既存の明細のカテゴリーを引き継ぐ
+			item.Category = prev.Category
+			item.CategoryStatus = prev.CategoryStatus
+			item.CreatedAt = prev.CreatedAt
+		case category != "":
+			// 要確認の明細に同じカテゴリーを指定した場合も確認済みとして手動設定にする
+			item.Category = category
+			item.CategoryStatus = entity.CategoryStatusManual
+			receipt.RecordItemCategorized(item)
+		default:
+			item.Category = "その他"
+			item.CategoryStatus = entity.CategoryStatusAutoFailed
+		}
+
+		item.Unit = entity.UnitPiece
+		if patch.Unit != "" {
+			item.Unit = strings.TrimSpace(patch.Unit)
+		} else if found {
+			item.Unit, item.Measure, item.UnitPrice = prev.Unit, prev.Measure, prev.UnitPrice
+		}
+		if patch.Measure != nil {
+			item.Measure = *patch.Measure
+		}
+		if patch.UnitPrice != nil {
+			item.UnitPrice = *patch.UnitPrice
+		}
+
+		item.WarrantyMonths = patch.WarrantyMonths
+		if found {
+			if item.WarrantyMonths == nil {
+				item.WarrantyMonths = prev.WarrantyMonths
+			}
+			// 通知済みの期限を再通知しない（保証期間を変更した場合は保証期限を通知し直す）
+			item.ReturnNotifiedAt = prev.ReturnNotifiedAt
+			if equalWarrantyMonths(item.WarrantyMonths, prev.WarrantyMonths) {
+				item.WarrantyNotifiedAt = prev.WarrantyNotifiedAt
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// equalWarrantyMonths 保証期間が同じかチェック
+func equalWarrantyMonths(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// ListCategoryItems 指定したカテゴリーの明細をレシートをまたいで取得
+func (uc *ReceiptUseCase) ListCategoryItems(ctx context.Context, category string, limit, offset int) ([]*entity.PurchasedItem, error) {
+	return uc.receiptRepo.FindItemsByCategory(ctx, category, limit, offset)
+}
+
+// ListNeedsReview 要確認のレシート一覧を取得
+func (uc *ReceiptUseCase) ListNeedsReview(ctx context.Context, limit, offset int) ([]*entity.Receipt, error) {
+	return uc.receiptRepo.FindNeedsReview(ctx, limit, offset)
+}
+
+// UpdateReceipt レシートを更新し、変更後の状態を変更履歴に記録
+// 初回の変更時は変更前の状態もoriginalとして記録する。不正なレシートは保存せずErrInvalidReceiptを返す
+func (uc *ReceiptUseCase) UpdateReceipt(ctx context.Context, receipt *entity.Receipt, source string) error {
+	if err := validateReceipt(receipt); err != nil {
+		return err
+	}
+
+	if uc.revisionRepo == nil {
+		if err := uc.receiptRepo.Update(ctx, receipt); err != nil {
+			return err
+		}
+		uc.publishEvents(ctx, receipt)
+		return nil
+	}
+
+	revisions, err := uc.revisionRepo.FindByReceiptID(ctx, receipt.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get receipt history: %w", err)
+	}
+
+	next := 1
+	if len(revisions) == 0 {
+		current, err := uc.receiptRepo.FindByID(ctx, receipt.ID)
+		if err != nil {
+			return err
+		}
+		original := entity.NewReceiptRevision(uc.newID(), current, next, entity.RevisionSourceOriginal, uc.clock.Now())
+		if err := uc.revisionRepo.Create(ctx, original); err != nil {
+			return fmt.Errorf("failed to save original revision: %w", err)
+		}
+		next++
+	} else {
+		next = revisions[len(revisions)-1].Revision + 1
+	}
+
+	receipt.UpdatedAt = uc.clock.Now()
+	if err := uc.receiptRepo.Update(ctx, receipt); err != nil {
+		return err
+	}
+	uc.publishEvents(ctx, receipt)
+
+	if err := uc.revisionRepo.Create(ctx, entity.NewReceiptRevision(uc.newID(), receipt, next, source, uc.clock.Now())); err != nil {
+		return fmt.Errorf("failed to save receipt revision: %w", err)
+	}
+	return nil
+}
+
+// GetReceiptHistory レシートの変更履歴を取得
+func (uc *ReceiptUseCase) GetReceiptHistory(ctx context.Context, id string) ([]*entity.ReceiptRevision, error) {
+	if uc.revisionRepo == nil {
+		return []*entity.ReceiptRevision{}, nil
+	}
+	return uc.revisionRepo.FindByReceiptID(ctx, id)
+}
+
+// RevertReceipt レシートを指定リビジョンの状態に戻す
+// 巻き戻し自体も新しいリビジョンとして記録されるため、巻き戻しも取り消せる
+func (uc *ReceiptUseCase) RevertReceipt(ctx context.Context, id string, revision int) (*entity.Receipt, error) {
+	if uc.revisionRepo == nil {
+		return nil, fmt.Errorf("receipt history is not enabled")
+	}
+
+	target, err := uc.revisionRepo.FindByRevision(ctx, id, revision)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRevisionNotFound, err)
+	}
+
+	restored := target.Snapshot
+	restored.Items = append([]entity.ReceiptItem(nil), target.Snapshot.Items...)
+	if err := uc.UpdateReceipt(ctx, &restored, entity.RevisionSourceRevert); err != nil {
+		return nil, fmt.Errorf("failed to revert receipt: %w", err)
+	}
+
+	return &restored, nil
+}
+
+// parseReceiptJSON JSONからレシートエンティティを作成（作成日時は現在時刻）
+// レシートに印字された購入日時はlocのタイムゾーンの時刻として解釈する
+func (uc *ReceiptUseCase) parseReceiptJSON(receiptJSON string, receiptID string, loc *time.Location) (*entity.Receipt, error) {
+	return uc.parseReceiptJSONAt(receiptJSON, receiptID, loc, uc.clock.Now())
+}
+
+// parseReceiptJSONAt 作成日時をnowとしてJSONからレシートエンティティを作成
+// 購入日時を読み取れなかった場合もnowとする
+func (uc *ReceiptUseCase) parseReceiptJSONAt(receiptJSON string, receiptID string, loc *time.Location, now time.Time) (*entity.Receipt, error) {
 	// Claude APIは```json```で囲まれた形式で返すことがあるため、クリーンアップ
 	cleanJSON := receiptJSON
 	if idx := bytes.Index([]byte(receiptJSON), []byte("```json")); idx != -1 {
@@ -106,96 +1182,301 @@ func (uc *ReceiptUseCase) parseReceiptJSON(receiptJSON string, receiptID string)
 		}
 	}
 	cleanJSONBytes := bytes.TrimSpace([]byte(cleanJSON))
+	// 応答が途中で切れた・末尾のカンマなどの軽微な誤りがある場合は、読み取れる範囲で修復して要確認にする
+	cleanJSONBytes, repaired := repairJSON(cleanJSONBytes)
+	if repaired {
+		slog.Warn("Repaired malformed receipt JSON from AI", "receipt_id", receiptID)
+	}
 
 	var receiptData struct {
-		StoreName     string `json:"store_name"`
-		PurchaseDate  string `json:"purchase_date"`
-		TotalAmount   int    `json:"total_amount"`
-		TaxAmount     int    `json:"tax_amount"`
-		PaymentMethod string `json:"payment_method"`
-		ReceiptNumber string `json:"receipt_number"`
-		Items         []struct {
-			Name     string `json:"name"`
-			Quantity int    `json:"quantity"`
-			Price    int    `json:"price"`
-		} `json:"items"`
+		StoreName     string            `json:"store_name"`
+		PurchaseDate  string            `json:"purchase_date"`
+		TotalAmount   receiptAmount     `json:"total_amount"` // 金額は通貨の単位の小数（例: USDの12.99）
+		TaxAmount     receiptAmount     `json:"tax_amount"`
+		PaymentMethod string            `json:"payment_method"`
+		ReceiptNumber string            `json:"receipt_number"`
+		Locale        string            `json:"locale"`
+		Currency      string            `json:"currency"`
+		ReceiptType   string            `json:"receipt_type"`
+		Items         []receiptItemData `json:"items"`
 	}
 
 	if err := json.Unmarshal(cleanJSONBytes, &receiptData); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
 	}
 
-	// 【重要】total_amountをitemsの合計で強制的に上書き
-	calculatedTotal := 0
-	for _, item := range receiptData.Items {
-		calculatedTotal += item.Price * item.Quantity
+	// レシートのロケールに応じて日付の並び順・小数点の記号を切り替える
+	// 金額は通貨の最小単位の整数に変換する（保存する金額はレシートの通貨によらず設定の通貨の単位）
+	locale := entity.NewReceiptLocale(receiptData.Locale)
+	currencyCode := uc.receiptCurrencyCode(receiptData.Currency)
+	totalAmount, err := uc.currency.Parse(receiptData.TotalAmount.text(locale))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse total_amount: %w", err)
 	}
-	if calculatedTotal > 0 {
-		receiptData.TotalAmount = calculatedTotal
+	taxAmount, err := uc.currency.Parse(receiptData.TaxAmount.text(locale))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse tax_amount: %w", err)
 	}
-
-	// 購入日時のパース
-	var purchaseDate time.Time
-	if receiptData.PurchaseDate != "" {
-		formats := []string{
-			"2006-01-02 15:04",
-			"2006-01-02",
-			"2006/01/02 15:04",
-			"2006/01/02",
+	parsedItems := make([]entity.ReceiptItem, len(receiptData.Items))
+	for i, item := range receiptData.Items {
+		if parsedItems[i], err = uc.parseReceiptItem(item, locale); err != nil {
+			return nil, err
 		}
-		for _, format := range formats {
-			if t, err := time.Parse(format, receiptData.PurchaseDate); err == nil {
-				purchaseDate = t
-				break
-			}
+	}
+
+	// 合計金額はレシートに印字された値を優先し、読み取れなかった場合のみitemsの合計で補う
+	// 値引き・外税などで明細の合計と一致しない場合は、上書きせずに要確認とする（後段で判定）
+	if totalAmount == 0 {
+		for _, item := range parsedItems {
+			totalAmount += item.Amount()
 		}
 	}
-	if purchaseDate.IsZero() {
-		purchaseDate = time.Now()
+
+	// 購入日時のパース（AIが印字どおりの表記で返した場合はロケールの並び順で解釈する）
+	purchaseDate, ok := locale.ParseDate(receiptData.PurchaseDate, loc)
+	if !ok {
+		purchaseDate = now
 	}
+	purchaseDate, dateOutOfRange := uc.purchaseDateRules.checkPurchaseDate(purchaseDate, now)
 
 	// レシートエンティティの作成
 	receipt := &entity.Receipt{
-		ID:            receiptID,
-		StoreName:     receiptData.StoreName,
-		PurchaseDate:  purchaseDate,
-		TotalAmount:   receiptData.TotalAmount,
-		TaxAmount:     receiptData.TaxAmount,
-		PaymentMethod: receiptData.PaymentMethod,
-		ReceiptNumber: receiptData.ReceiptNumber,
-		Category:      "",
-		Items:         make([]entity.ReceiptItem, 0, len(receiptData.Items)),
-		CreatedAt:     time.Now(),
-		UpdatedAt:     time.Now(),
+		ID:             receiptID,
+		StoreName:      uc.normalizeName(receiptData.StoreName),
+		PurchaseDate:   purchaseDate,
+		TotalAmount:    totalAmount,
+		TaxAmount:      taxAmount,
+		PaymentMethod:  receiptData.PaymentMethod,
+		ReceiptNumber:  receiptData.ReceiptNumber,
+		Type:           receiptType(receiptData.ReceiptType, totalAmount),
+		Locale:         locale.Tag,
+		Currency:       currencyCode,
+		JSONRepaired:   repaired,
+		DateOutOfRange: dateOutOfRange,
+		Category:       "",
+		Items:          make([]entity.ReceiptItem, 0, len(receiptData.Items)),
+		CreatedAt:      now,
+		UpdatedAt:      now,
 	}
 
 	// 商品アイテムの追加
-	for i, item := range receiptData.Items {
-		if item.Name != "" {
-			// アイテムIDはレシートID（36文字） + "-" + インデックス（8桁）で45文字の識別子を生成します
-			// これはRFC 4122準拠のUUIDではなく、レシートとの関連性を保持するためのカスタム識別子です
-			// 例: b5377e40-a9f1-4426-6dfe-bd1e2c3f4a5b-00000000
-			// インデックスは8桁（最大99,999,999アイテム）で実用上十分な範囲をカバーします
-			itemID := fmt.Sprintf("%s-%08d", receiptID, i)
-			receiptItem := entity.ReceiptItem{
-				ID:        itemID,
-				ReceiptID: receiptID,
-				Name:      item.Name,
-				Quantity:  item.Quantity,
-				Price:     item.Price,
-				CreatedAt: time.Now(),
-			}
+	for _, receiptItem := range parsedItems {
+		name := uc.normalizeName(receiptItem.Name)
+		if name != "" {
+			// アイテムIDはIDGeneratorで生成する（未設定の場合はレシートID・順番・商品名・単価のハッシュ）
+			receiptItem.ID = uc.newItemID(receiptID, len(receipt.Items), name, receiptItem.Price)
+			receiptItem.ReceiptID = receiptID
+			receiptItem.Name = name
+			receiptItem.CategoryStatus = entity.CategoryStatusPending
+			receiptItem.CreatedAt = now
 			receipt.Items = append(receipt.Items, receiptItem)
 		}
 	}
+	receipt.NormalizeRefundAmounts()
+	receipt.NeedsReview = uc.needsReview(receipt)
 
 	return receipt, nil
 }
 
-// generateCacheKey キャッシュキーを生成
-func (uc *ReceiptUseCase) generateCacheKey(prefix string, data []byte) string {
-	hash := sha256.Sum256(data)
-	return fmt.Sprintf("vision:%s:%s", prefix, hex.EncodeToString(hash[:]))
+// receiptType AIが判定したレシートの種類（判定がなく合計金額が負の場合は返品とする）
+func receiptType(value string, totalAmount int64) string {
+	if strings.EqualFold(strings.TrimSpace(value), entity.ReceiptTypeRefund) || totalAmount < 0 {
+		return entity.ReceiptTypeRefund
+	}
+	return entity.ReceiptTypePurchase
+}
+
+// needsReview 読み取り結果を利用者が確認する必要があるかチェック
+// カテゴリー判定の失敗、合計金額・量り売りの金額の不一致、AIの応答のJSONの修復（明細が欠けている可能性がある）、
+// 範囲外の購入日（読み間違いの可能性がある）のほか、
+// 設定と異なる通貨のレシートは金額を設定の通貨に換算する必要があるため要確認とする
+func (uc *ReceiptUseCase) needsReview(receipt *entity.Receipt) bool {
+	return receipt.HasFailedCategories() ||
+		receipt.JSONRepaired ||
+		receipt.DateOutOfRange ||
+		receipt.HasTotalMismatch() ||
+		receipt.HasUnitPriceMismatch() ||
+		(receipt.Currency != "" && receipt.Currency != uc.currency.Code)
+}
+
+// receiptItemData AIの応答の明細
+// 量り売りの明細は quantity に計量値、unit に単位（g・kg）、price に計量した金額、
+// unit_price に印字された単価、unit_price_per に単価の基準量（100g当りなら100）が入る
+type receiptItemData struct {
+	Name         string        `json:"name"`
+	Quantity     receiptAmount `json:"quantity"`
+	Unit         string        `json:"unit"`
+	Price        receiptAmount `json:"price"`
+	UnitPrice    receiptAmount `json:"unit_price"`
+	UnitPricePer receiptAmount `json:"unit_price_per"`
+}
+
+// parseReceiptItem AIの応答の明細から数量・単位・金額を読み取る（ID・カテゴリーは設定しない）
+func (uc *ReceiptUseCase) parseReceiptItem(data receiptItemData, locale entity.ReceiptLocale) (entity.ReceiptItem, error) {
+	price, err := uc.currency.Parse(data.Price.text(locale))
+	if err != nil {
+		return entity.ReceiptItem{}, fmt.Errorf("failed to parse price of %q: %w", data.Name, err)
+	}
+	quantity, _ := strconv.ParseFloat(data.Quantity.text(locale), 64)
+
+	item := entity.ReceiptItem{Name: data.Name, Quantity: 1, Price: price, Unit: entity.NormalizeUnit(data.Unit)}
+	if item.IsWeighed() && quantity > 0 {
+		unitPrice, err := uc.currency.Parse(data.UnitPrice.text(locale))
+		if err != nil {
+			return entity.ReceiptItem{}, fmt.Errorf("failed to parse unit_price of %q: %w", data.Name, err)
+		}
+		per, _ := strconv.ParseFloat(data.UnitPricePer.text(locale), 64)
+		item.Measure = quantity
+		item.UnitPrice = entity.PerKilogram(unitPrice, item.Unit, per)
+		// 金額を読み取れなかった場合は単価と計量値から求める
+		if item.Price == 0 {
+			item.Price = item.WeighedAmount()
+		}
+		return item, nil
+	}
+
+	// 数量を読み取れなかった明細は1個とする（計量値のない量り売りの明細も1個として扱う）
+	item.Unit = entity.UnitPiece
+	if n := int(math.Round(quantity)); n > 0 {
+		item.Quantity = n
+	}
+	return item, nil
+}
+
+// receiptAmount AIの応答の金額（数値、またはレシートに印字された表記の文字列）
+type receiptAmount struct {
+	value  string
+	quoted bool // 文字列の場合はロケールの区切り文字で書かれている
+}
+
+// UnmarshalJSON 数値・文字列・nullの金額を読み込む
+func (a *receiptAmount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		a.quoted = true
+		return json.Unmarshal(data, &a.value)
+	}
+	a.value = string(data)
+	return nil
+}
+
+// text 金額を小数点がピリオドの表記で返す（数字を含まない文字列はそのまま返し、変換時のエラーとする）
+func (a receiptAmount) text(locale entity.ReceiptLocale) string {
+	if !a.quoted {
+		return a.value
+	}
+	if normalized := locale.NormalizeAmount(a.value); strings.ContainsAny(normalized, "0123456789") {
+		return normalized
+	}
+	return a.value
+}
+
+// receiptCurrencyCode AIが読み取ったレシートの通貨コード（読み取れなかった場合は設定の通貨）
+func (uc *ReceiptUseCase) receiptCurrencyCode(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return uc.currency.Code
+	}
+	return code
+}
+
+// normalizeName 読み取った店名・商品名を正規化（未設定の場合はそのまま）
+func (uc *ReceiptUseCase) normalizeName(name string) string {
+	if uc.nameNormalizer == nil {
+		return name
+	}
+	return uc.nameNormalizer.Normalize(name)
+}
+
+// itemAliasDictionary 商品名の別名辞書を読み込む（未設定の場合はnil）
+func (uc *ReceiptUseCase) itemAliasDictionary(ctx context.Context) (entity.ItemAliasDictionary, error) {
+	if uc.itemAliasRepo == nil {
+		return nil, nil
+	}
+	aliases, err := uc.itemAliasRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load item aliases: %w", err)
+	}
+	return entity.NewItemAliasDictionary(aliases), nil
+}
+
+// canonicalizeItems 別名に一致する商品名を正式な商品名に置き換える
+// 明細IDは読み取った商品名から作るため、辞書を変更しても同じ画像の明細IDは変わらない。
+// 辞書を読み込めない場合はレシートの登録を止めず、読み取った商品名のまま保存する
+func (uc *ReceiptUseCase) canonicalizeItems(ctx context.Context, receipt *entity.Receipt) {
+	dictionary, err := uc.itemAliasDictionary(ctx)
+	if err != nil {
+		slog.Warn("Item names are not canonicalized", "receipt_id", receipt.ID, "error", err)
+		return
+	}
+	dictionary.CanonicalizeReceipt(receipt)
+}
+
+// publishEvents 保存したレシートが記録したドメインイベントを配信
+func (uc *ReceiptUseCase) publishEvents(ctx context.Context, receipt *entity.Receipt) {
+	publishEvents(ctx, uc.eventPublisher, receipt)
+}
+
+// eventSource ドメインイベントを記録するエンティティ
+type eventSource interface {
+	PullEvents() []sharedDomain.DomainEvent
+}
+
+// publishEvents エンティティが記録したドメインイベントを取り出して配信（配信先が未設定の場合は破棄）
+func publishEvents(ctx context.Context, publisher sharedDomain.EventPublisher, source eventSource) {
+	events := source.PullEvents()
+	if publisher == nil || len(events) == 0 {
+		return
+	}
+	publisher.Publish(ctx, events...)
+}
+
+// generateCacheKey キャッシュキーを生成（プロンプト・モデルの版を含め、変更した場合は以前の解析結果を使わない）
+func (uc *ReceiptUseCase) generateCacheKey(prefix, operation string, data []byte) string {
+	return domain.AICacheKey(prefix, data, domain.PromptVersionOf(uc.aiRepo, operation))
+}
+
+// findDuplicate 同じ画像から登録済みのレシートを探す（見つからない場合はnil）
+// 画像のハッシュで検索し、見つからない場合は画像のハッシュを保存する前に登録されたレシートを
+// 画像から決定的に生成したIDで探す
+func (uc *ReceiptUseCase) findDuplicate(ctx context.Context, imageHash string, imageData []byte) *entity.Receipt {
+	if receipt, err := uc.receiptRepo.FindByImageHash(ctx, imageHash); err == nil && receipt != nil {
+		return receipt
+	}
+	if receipt, err := uc.receiptRepo.FindByID(ctx, uc.generateDeterministicReceiptID(imageData)); err == nil && receipt != nil {
+		return receipt
+	}
+	return nil
+}
+
+// newReceiptID 新しいレシートIDを生成
+func (uc *ReceiptUseCase) newReceiptID(imageData []byte) string {
+	if uc.idGenerator == nil {
+		return uc.generateDeterministicReceiptID(imageData)
+	}
+	return uc.idGenerator.NewID(imageData)
+}
+
+// newItemID レシートの明細IDを生成
+// 決定的な方式ではレシートID・順番・商品名・単価から生成するため、
+// 再処理で明細の並びが変わっても別の明細と同じIDにならない
+func (uc *ReceiptUseCase) newItemID(receiptID string, index int, name string, price int64) string {
+	seed := fmt.Appendf(nil, "%s\x00%d", name, price)
+	if uc.idGenerator == nil {
+		// hash方式と同じ識別子（UUID形式の36文字）
+		return uc.generateDeterministicReceiptID(fmt.Appendf(nil, "%s\x00%d\x00%s", receiptID, index, seed))
+	}
+	return uc.idGenerator.ChildID(receiptID, index, seed)
+}
+
+// newID 変更履歴などの識別子を生成
+func (uc *ReceiptUseCase) newID() string {
+	if uc.idGenerator == nil {
+		return uuid.NewString()
+	}
+	return uc.idGenerator.NewID(nil)
 }
 
 // generateDeterministicReceiptID 画像データから決定的なレシートIDを生成します
@@ -214,54 +1495,94 @@ func (uc *ReceiptUseCase) generateDeterministicReceiptID(imageData []byte) strin
 }
 
 // categorizeReceiptItems 明細項目ごとにカテゴリーを判定
-func (uc *ReceiptUseCase) categorizeReceiptItems(receipt *entity.Receipt) error {
-	if len(receipt.Items) == 0 {
-		return nil
-	}
-
-	// 商品名リストを作成
-	itemNames := make([]string, len(receipt.Items))
+func (uc *ReceiptUseCase) categorizeReceiptItems(ctx context.Context, receipt *entity.Receipt) error {
+	// 手動で設定したカテゴリーなど判定済みの明細は上書きしないよう、判定待ちの明細だけを判定する
+	pending := make([]int, 0, len(receipt.Items))
 	for i, item := range receipt.Items {
-		itemNames[i] = item.Name
+		if item.IsCategoryPending() {
+			pending = append(pending, i)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
 	}
 
 	// AI APIで一括カテゴリー判定
-	itemsInfo := fmt.Sprintf("店名: %s\n以下の商品それぞれのカテゴリーを判定してください（食費、日用品、医療費、娯楽費、交通費、通信費、光熱費、その他）:\n", receipt.StoreName)
-	for i, name := range itemNames {
-		itemsInfo += fmt.Sprintf("%d. %s\n", i+1, name)
+	itemsInfo := fmt.Sprintf("店名: %s\n以下の商品それぞれのカテゴリーを判定してください（%s）:\n", receipt.StoreName, strings.Join(uc.categoryNames(ctx), "、"))
+	for n, i := range pending {
+		itemsInfo += fmt.Sprintf("%d. %s\n", n+1, receipt.Items[i].Name)
 	}
 
 	result, err := uc.aiRepo.CategorizeReceipt(itemsInfo)
+	if errors.Is(err, domain.ErrAIDowngraded) {
+		// AIの予算超過で縮退中の場合はキーワードのルールで判定する
+		uc.categorizeItemsByRules(receipt)
+		slog.Info("Items categorized by rules", "receipt_id", receipt.ID, "reason", err)
+		return nil
+	}
 	if err != nil {
-		// AI APIエラーの場合は全てデフォルトカテゴリーを設定
-		for i := range receipt.Items {
-			receipt.Items[i].Category = "その他"
-		}
+		// AI APIエラーの場合は全てデフォルトカテゴリーを設定し、要確認にする
+		receipt.MarkItemsCategoryFailed("その他")
+		slog.Warn("Item categorization failed", "receipt_id", receipt.ID, "error", err)
 		return nil
 	}
 
+	// 判定結果の原文を保存（要確認時の調査用）
+	receipt.CategorizationRaw = result.CorrectedText
+
 	// レスポンスをパース
-	categories, err := uc.parseItemCategories(result.CorrectedText, len(receipt.Items))
+	categories, err := uc.parseItemCategories(result.CorrectedText, len(pending))
 	if err != nil {
-		// パースエラーの場合は全てデフォルトカテゴリーを設定
-		for i := range receipt.Items {
-			receipt.Items[i].Category = "その他"
-		}
+		// パースエラーの場合は全てデフォルトカテゴリーを設定し、要確認にする
+		receipt.MarkItemsCategoryFailed("その他")
+		slog.Warn("Failed to parse item categories", "receipt_id", receipt.ID, "error", err)
 		return nil
 	}
 
-	// 各明細項目にカテゴリーを設定
-	for i := range receipt.Items {
-		if i < len(categories) && categories[i] != "" {
-			receipt.Items[i].Category = categories[i]
+	// 判定待ちの明細項目にカテゴリーを設定
+	for n, i := range pending {
+		if n < len(categories) && categories[n] != "" {
+			receipt.CategorizeItem(i, categories[n], entity.CategoryStatusAuto)
 		} else {
-			receipt.Items[i].Category = "その他"
+			// 判定結果が不足している明細は判定失敗として扱う
+			receipt.CategorizeItem(i, "その他", entity.CategoryStatusAutoFailed)
 		}
 	}
+	receipt.NeedsReview = uc.needsReview(receipt)
 
 	return nil
 }
 
+// autoCategories AIに判定させる既定のカテゴリー（カテゴリーの取得元が未設定・取得に失敗した場合に使う）
+var autoCategories = []string{"食費", "日用品", "医療費", "娯楽費", "交通費", "通信費", "光熱費", "その他"}
+
+// categoryNames AIに判定させるカテゴリー名
+// 利用者が登録したカテゴリーを含めるため取得元から読み込み、判定に失敗した明細に設定する既定のカテゴリー（その他）は必ず含める
+func (uc *ReceiptUseCase) categoryNames(ctx context.Context) []string {
+	if uc.categoryRepo == nil {
+		return autoCategories
+	}
+	categories, err := uc.categoryRepo.FindAll(ctx)
+	if err != nil {
+		slog.Warn("Failed to load categories, using default categories", "error", err)
+		return autoCategories
+	}
+
+	names := make([]string, 0, len(categories)+1)
+	for _, category := range categories {
+		if name := strings.TrimSpace(category.Name); name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return autoCategories
+	}
+	if !slices.Contains(names, entity.DefaultCategory) {
+		names = append(names, entity.DefaultCategory)
+	}
+	return names
+}
+
 // parseItemCategories AI APIのレスポンスから商品ごとのカテゴリーを抽出
 func (uc *ReceiptUseCase) parseItemCategories(response string, itemCount int) ([]string, error) {
 	// ```json で囲まれている場合は抽出
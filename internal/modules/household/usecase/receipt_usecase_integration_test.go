@@ -0,0 +1,70 @@
+//go:build !no_ai
+
+package usecase
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"vision-api-app/internal/config"
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/shared/infrastructure/ai"
+	"vision-api-app/internal/modules/shared/infrastructure/testsupport"
+)
+
+func TestReceiptUseCase_ProcessReceiptImage_GoldenIntegration(t *testing.T) {
+	server := testsupport.NewFakeAnthropicServer(t, filepath.Join("testdata", "golden"))
+
+	aiRepo := ai.NewClaudeRepository(&config.AnthropicConfig{
+		APIKey:    "test-key",
+		Model:     "claude-haiku-4-5-20251001",
+		MaxTokens: 4096,
+	})
+	aiRepo.SetHTTPClient(server.Client())
+	aiRepo.SetAPIEndpoint(server.URL())
+
+	var saved *entity.Receipt
+	mockReceipt := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			return nil, errors.New("not found")
+		},
+		CreateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			saved = receipt
+			return nil
+		},
+	}
+
+	uc := NewReceiptUseCase(aiRepo, mockReceipt, &MockCacheRepository{})
+
+	receipt, err := uc.ProcessReceiptImage(context.Background(), []byte("receipt-image"))
+	if err != nil {
+		t.Fatalf("ProcessReceiptImage() error = %v", err)
+	}
+
+	if saved == nil {
+		t.Fatal("Expected receipt to be saved")
+	}
+	if receipt.StoreName != "テストマート" {
+		t.Errorf("StoreName = %s, want テストマート", receipt.StoreName)
+	}
+	if receipt.TotalAmount != 300 {
+		t.Errorf("TotalAmount = %d, want 300", receipt.TotalAmount)
+	}
+	if len(receipt.Items) != 2 {
+		t.Fatalf("Items length = %d, want 2", len(receipt.Items))
+	}
+
+	wantCategories := []string{"食費", "食費"}
+	for i, item := range receipt.Items {
+		if item.Category != wantCategories[i] {
+			t.Errorf("Items[%d].Category = %s, want %s", i, item.Category, wantCategories[i])
+		}
+	}
+
+	// レシート認識 + カテゴリー判定の2回のみ呼び出される
+	if server.RequestCount() != 2 {
+		t.Errorf("RequestCount() = %d, want 2", server.RequestCount())
+	}
+}
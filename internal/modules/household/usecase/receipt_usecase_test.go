@@ -1,13 +1,27 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
+
 	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+	"vision-api-app/internal/modules/shared/infrastructure/idgen"
+	"vision-api-app/internal/modules/shared/infrastructure/queue"
+	"vision-api-app/internal/modules/shared/infrastructure/storage"
 	"vision-api-app/internal/modules/vision/domain"
 )
 
@@ -48,6 +62,21 @@ type MockReceiptRepository struct {
 	CreateFunc   func(ctx context.Context, receipt *entity.Receipt) error
 	FindByIDFunc func(ctx context.Context, id string) (*entity.Receipt, error)
 	FindAllFunc  func(ctx context.Context, limit, offset int) ([]*entity.Receipt, error)
+	UpdateFunc   func(ctx context.Context, receipt *entity.Receipt) error
+	DeleteFunc   func(ctx context.Context, id string) error
+	PingFunc     func(ctx context.Context) error
+
+	FindByIDsFunc          func(ctx context.Context, ids []string) ([]*entity.Receipt, error)
+	FindByImageHashFunc    func(ctx context.Context, imageHash string) (*entity.Receipt, error)
+	FindNeedsReviewFunc    func(ctx context.Context, limit, offset int) ([]*entity.Receipt, error)
+	FindByDateRangeFunc    func(ctx context.Context, start, end time.Time) ([]*entity.Receipt, error)
+	ForEachByDateRangeFunc func(ctx context.Context, start, end time.Time, fn func(*entity.Receipt) error) error
+	FindByFilterFunc       func(ctx context.Context, filter repository.ReceiptFilter, limit, offset int) ([]*entity.Receipt, error)
+
+	FindItemsByCategoryFunc       func(ctx context.Context, category string, limit, offset int) ([]*entity.PurchasedItem, error)
+	FindItemsByNormalizedNameFunc func(ctx context.Context, normalizedName string, limit, offset int) ([]*entity.PurchasedItem, error)
+	FindWarrantyItemsFunc         func(ctx context.Context, minPrice int64) ([]*entity.PurchasedItem, error)
+	MarkItemNotifiedFunc          func(ctx context.Context, itemID, kind string, notifiedAt time.Time) error
 }
 
 func (m *MockReceiptRepository) Create(ctx context.Context, receipt *entity.Receipt) error {
@@ -64,6 +93,26 @@ func (m *MockReceiptRepository) FindByID(ctx context.Context, id string) (*entit
 	return &entity.Receipt{ID: id}, nil
 }
 
+func (m *MockReceiptRepository) FindByIDs(ctx context.Context, ids []string) ([]*entity.Receipt, error) {
+	if m.FindByIDsFunc != nil {
+		return m.FindByIDsFunc(ctx, ids)
+	}
+	receipts := []*entity.Receipt{}
+	for _, id := range ids {
+		if receipt, err := m.FindByID(ctx, id); err == nil {
+			receipts = append(receipts, receipt)
+		}
+	}
+	return receipts, nil
+}
+
+func (m *MockReceiptRepository) FindByImageHash(ctx context.Context, imageHash string) (*entity.Receipt, error) {
+	if m.FindByImageHashFunc != nil {
+		return m.FindByImageHashFunc(ctx, imageHash)
+	}
+	return nil, errors.New("receipt not found")
+}
+
 func (m *MockReceiptRepository) FindAll(ctx context.Context, limit, offset int) ([]*entity.Receipt, error) {
 	if m.FindAllFunc != nil {
 		return m.FindAllFunc(ctx, limit, offset)
@@ -72,17 +121,120 @@ func (m *MockReceiptRepository) FindAll(ctx context.Context, limit, offset int)
 }
 
 func (m *MockReceiptRepository) FindByDateRange(ctx context.Context, start, end time.Time) ([]*entity.Receipt, error) {
+	if m.FindByDateRangeFunc != nil {
+		return m.FindByDateRangeFunc(ctx, start, end)
+	}
 	return nil, errors.New("not implemented")
 }
 
+func (m *MockReceiptRepository) ForEachByDateRange(ctx context.Context, start, end time.Time, fn func(*entity.Receipt) error) error {
+	if m.ForEachByDateRangeFunc != nil {
+		return m.ForEachByDateRangeFunc(ctx, start, end, fn)
+	}
+	receipts, err := m.FindByDateRange(ctx, start, end)
+	if err != nil {
+		return err
+	}
+	for _, receipt := range receipts {
+		if err := fn(receipt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MockReceiptRepository) FindNeedsReview(ctx context.Context, limit, offset int) ([]*entity.Receipt, error) {
+	if m.FindNeedsReviewFunc != nil {
+		return m.FindNeedsReviewFunc(ctx, limit, offset)
+	}
+	return []*entity.Receipt{}, nil
+}
+
+func (m *MockReceiptRepository) FindByFilter(ctx context.Context, filter repository.ReceiptFilter, limit, offset int) ([]*entity.Receipt, error) {
+	if m.FindByFilterFunc != nil {
+		return m.FindByFilterFunc(ctx, filter, limit, offset)
+	}
+	return []*entity.Receipt{}, nil
+}
+
+func (m *MockReceiptRepository) FindItemsByCategory(ctx context.Context, category string, limit, offset int) ([]*entity.PurchasedItem, error) {
+	if m.FindItemsByCategoryFunc != nil {
+		return m.FindItemsByCategoryFunc(ctx, category, limit, offset)
+	}
+	return []*entity.PurchasedItem{}, nil
+}
+
+func (m *MockReceiptRepository) FindItemsByNormalizedName(ctx context.Context, normalizedName string, limit, offset int) ([]*entity.PurchasedItem, error) {
+	if m.FindItemsByNormalizedNameFunc != nil {
+		return m.FindItemsByNormalizedNameFunc(ctx, normalizedName, limit, offset)
+	}
+	return []*entity.PurchasedItem{}, nil
+}
+
+func (m *MockReceiptRepository) FindWarrantyItems(ctx context.Context, minPrice int64) ([]*entity.PurchasedItem, error) {
+	if m.FindWarrantyItemsFunc != nil {
+		return m.FindWarrantyItemsFunc(ctx, minPrice)
+	}
+	return []*entity.PurchasedItem{}, nil
+}
+
+func (m *MockReceiptRepository) MarkItemNotified(ctx context.Context, itemID, kind string, notifiedAt time.Time) error {
+	if m.MarkItemNotifiedFunc != nil {
+		return m.MarkItemNotifiedFunc(ctx, itemID, kind, notifiedAt)
+	}
+	return nil
+}
+
 func (m *MockReceiptRepository) Update(ctx context.Context, receipt *entity.Receipt) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, receipt)
+	}
 	return errors.New("not implemented")
 }
 
 func (m *MockReceiptRepository) Delete(ctx context.Context, id string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, id)
+	}
 	return errors.New("not implemented")
 }
 
+func (m *MockReceiptRepository) Ping(ctx context.Context) error {
+	if m.PingFunc != nil {
+		return m.PingFunc(ctx)
+	}
+	return nil
+}
+
+// MockReceiptRevisionRepository メモリ上に変更履歴を保持するモック
+type MockReceiptRevisionRepository struct {
+	revisions []*entity.ReceiptRevision
+}
+
+func (m *MockReceiptRevisionRepository) Create(ctx context.Context, revision *entity.ReceiptRevision) error {
+	m.revisions = append(m.revisions, revision)
+	return nil
+}
+
+func (m *MockReceiptRevisionRepository) FindByReceiptID(ctx context.Context, receiptID string) ([]*entity.ReceiptRevision, error) {
+	var revisions []*entity.ReceiptRevision
+	for _, revision := range m.revisions {
+		if revision.ReceiptID == receiptID {
+			revisions = append(revisions, revision)
+		}
+	}
+	return revisions, nil
+}
+
+func (m *MockReceiptRevisionRepository) FindByRevision(ctx context.Context, receiptID string, revision int) (*entity.ReceiptRevision, error) {
+	for _, r := range m.revisions {
+		if r.ReceiptID == receiptID && r.Revision == revision {
+			return r, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
 // MockCacheRepository モックキャッシュリポジトリ
 type MockCacheRepository struct {
 	GetFunc    func(ctx context.Context, key string) ([]byte, error)
@@ -328,19 +480,91 @@ func TestReceiptUseCase_ProcessReceiptImage_Deduplication(t *testing.T) {
 		t.Errorf("Expected 1 receipt in storage, got %d", len(savedReceipts))
 	}
 
-	// レシートアイテムのIDが正しい形式であることを確認（45文字：36文字のレシートID + "-" + 8桁のインデックス）
+	// レシートアイテムのIDが正しい形式であることを確認（UUID形式の36文字）
 	for _, item := range receipt1.Items {
-		if len(item.ID) != 45 {
-			t.Errorf("Item ID length should be 45, got %d: %s", len(item.ID), item.ID)
+		if len(item.ID) != 36 {
+			t.Errorf("Item ID length should be 36, got %d: %s", len(item.ID), item.ID)
 		}
 		if item.ReceiptID != receipt1.ID {
 			t.Errorf("Item ReceiptID should match receipt ID: got %s, want %s", item.ReceiptID, receipt1.ID)
 		}
-		// アイテムIDがレシートIDで始まることを確認
-		if len(item.ID) >= len(receipt1.ID) && item.ID[:len(receipt1.ID)] != receipt1.ID {
-			t.Errorf("Item ID should start with receipt ID: got %s, want prefix %s", item.ID, receipt1.ID)
+	}
+}
+
+func TestReceiptUseCase_ProcessReceiptImage_IDGenerator(t *testing.T) {
+	mockAI := &MockAIRepository{
+		RecognizeReceiptFunc: func(imageData []byte) (*domain.AIResult, error) {
+			return domain.NewAIResult("", `{"store_name":"Test Store","total_amount":1000,"items":[{"name":"Item1","quantity":1,"price":500},{"name":"Item2","quantity":2,"price":250}]}`, 10, 5, "test"), nil
+		},
+	}
+
+	legacyImage := []byte("legacy image")
+	legacyID := NewReceiptUseCase(nil, nil, nil).generateDeterministicReceiptID(legacyImage)
+
+	saved := make(map[string]*entity.Receipt)
+	mockReceipt := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			// 画像のハッシュを保存する前に登録されたレシート
+			if id == legacyID {
+				return &entity.Receipt{ID: legacyID, StoreName: "Legacy Store"}, nil
+			}
+			return nil, errors.New("not found")
+		},
+		FindByImageHashFunc: func(ctx context.Context, imageHash string) (*entity.Receipt, error) {
+			if receipt, ok := saved[imageHash]; ok {
+				return receipt, nil
+			}
+			return nil, errors.New("not found")
+		},
+		CreateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			saved[receipt.ImageHash] = receipt
+			return nil
+		},
+	}
+
+	uc := NewReceiptUseCase(mockAI, mockReceipt, &MockCacheRepository{})
+	uc.SetIDGenerator(idgen.UUIDv7Generator{})
+	ctx := context.Background()
+
+	imageData := []byte("test image data")
+	receipt, err := uc.ProcessReceiptImage(ctx, imageData)
+	if err != nil {
+		t.Fatalf("ProcessReceiptImage() error = %v", err)
+	}
+
+	if id, err := uuid.Parse(receipt.ID); err != nil || id.Version() != 7 {
+		t.Errorf("receipt ID = %s, want UUIDv7", receipt.ID)
+	}
+	for i, item := range receipt.Items {
+		if id, err := uuid.Parse(item.ID); err != nil || id.Version() != 7 {
+			t.Errorf("Items[%d].ID = %s, want UUIDv7", i, item.ID)
+		}
+		if item.ReceiptID != receipt.ID {
+			t.Errorf("Items[%d].ReceiptID = %s, want %s", i, item.ReceiptID, receipt.ID)
 		}
 	}
+	hash := sha256.Sum256(imageData)
+	if receipt.ImageHash != hex.EncodeToString(hash[:]) {
+		t.Errorf("ImageHash = %s, want SHA256 of image", receipt.ImageHash)
+	}
+
+	// 同じ画像はIDが画像から決まらなくても、画像のハッシュで重複を検出する
+	again, err := uc.ProcessReceiptImage(ctx, imageData)
+	if err != nil {
+		t.Fatalf("second ProcessReceiptImage() error = %v", err)
+	}
+	if again.ID != receipt.ID || len(saved) != 1 {
+		t.Errorf("duplicate upload created a new receipt: %s (saved %d)", again.ID, len(saved))
+	}
+
+	// 画像のハッシュを保存する前に登録されたレシートも重複として扱う
+	legacy, err := uc.ProcessReceiptImage(ctx, legacyImage)
+	if err != nil {
+		t.Fatalf("legacy ProcessReceiptImage() error = %v", err)
+	}
+	if legacy.ID != legacyID || len(saved) != 1 {
+		t.Errorf("legacy duplicate = %s (saved %d), want %s", legacy.ID, len(saved), legacyID)
+	}
 }
 
 // TestReceiptUseCase_generateDeterministicReceiptID 決定的なレシートID生成のテスト
@@ -569,7 +793,7 @@ func TestReceiptUseCase_categorizeReceiptItems(t *testing.T) {
 
 			uc := NewReceiptUseCase(mockAI, nil, nil)
 
-			err := uc.categorizeReceiptItems(tt.receipt)
+			err := uc.categorizeReceiptItems(context.Background(), tt.receipt)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("categorizeReceiptItems() error = %v, wantErr %v", err, tt.wantErr)
@@ -591,6 +815,104 @@ func TestReceiptUseCase_categorizeReceiptItems(t *testing.T) {
 	}
 }
 
+// TestReceiptUseCase_categorizeReceiptItems_ReviewState 判定状態と要確認フラグのテスト
+func TestReceiptUseCase_categorizeReceiptItems_ReviewState(t *testing.T) {
+	tests := []struct {
+		name            string
+		aiResponse      string
+		aiErr           error
+		wantStatuses    []string
+		wantNeedsReview bool
+		wantRaw         string
+	}{
+		{
+			name:            "全件判定成功",
+			aiResponse:      `["食費", "日用品"]`,
+			wantStatuses:    []string{entity.CategoryStatusAuto, entity.CategoryStatusAuto},
+			wantNeedsReview: false,
+			wantRaw:         `["食費", "日用品"]`,
+		},
+		{
+			name:            "判定結果が不足",
+			aiResponse:      `["食費"]`,
+			wantStatuses:    []string{entity.CategoryStatusAuto, entity.CategoryStatusAutoFailed},
+			wantNeedsReview: true,
+			wantRaw:         `["食費"]`,
+		},
+		{
+			name:            "パースエラー",
+			aiResponse:      "   ",
+			wantStatuses:    []string{entity.CategoryStatusAutoFailed, entity.CategoryStatusAutoFailed},
+			wantNeedsReview: true,
+			wantRaw:         "   ",
+		},
+		{
+			name:            "AI APIエラー",
+			aiErr:           errors.New("AI error"),
+			wantStatuses:    []string{entity.CategoryStatusAutoFailed, entity.CategoryStatusAutoFailed},
+			wantNeedsReview: true,
+			wantRaw:         "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAI := &MockAIRepository{
+				CategorizeReceiptFunc: func(receiptInfo string) (*domain.AIResult, error) {
+					if tt.aiErr != nil {
+						return nil, tt.aiErr
+					}
+					return domain.NewAIResult("", tt.aiResponse, 10, 5, "test"), nil
+				},
+			}
+			receipt := &entity.Receipt{
+				StoreName: "テスト店",
+				Items: []entity.ReceiptItem{
+					{Name: "牛乳", Quantity: 1, Price: 200},
+					{Name: "洗剤", Quantity: 1, Price: 300},
+				},
+			}
+
+			uc := NewReceiptUseCase(mockAI, nil, nil)
+			if err := uc.categorizeReceiptItems(context.Background(), receipt); err != nil {
+				t.Fatalf("categorizeReceiptItems() error = %v", err)
+			}
+
+			for i, item := range receipt.Items {
+				if item.CategoryStatus != tt.wantStatuses[i] {
+					t.Errorf("Items[%d].CategoryStatus = %s, want %s", i, item.CategoryStatus, tt.wantStatuses[i])
+				}
+			}
+			if receipt.NeedsReview != tt.wantNeedsReview {
+				t.Errorf("NeedsReview = %v, want %v", receipt.NeedsReview, tt.wantNeedsReview)
+			}
+			if receipt.CategorizationRaw != tt.wantRaw {
+				t.Errorf("CategorizationRaw = %q, want %q", receipt.CategorizationRaw, tt.wantRaw)
+			}
+		})
+	}
+}
+
+func TestReceiptUseCase_ListNeedsReview(t *testing.T) {
+	mockReceipt := &MockReceiptRepository{
+		FindNeedsReviewFunc: func(ctx context.Context, limit, offset int) ([]*entity.Receipt, error) {
+			if limit != 20 || offset != 40 {
+				t.Errorf("FindNeedsReview(%d, %d), want (20, 40)", limit, offset)
+			}
+			return []*entity.Receipt{{ID: "review-1", NeedsReview: true}}, nil
+		},
+	}
+
+	uc := NewReceiptUseCase(&MockAIRepository{}, mockReceipt, nil)
+	receipts, err := uc.ListNeedsReview(context.Background(), 20, 40)
+	if err != nil {
+		t.Fatalf("ListNeedsReview() error = %v", err)
+	}
+	if len(receipts) != 1 || receipts[0].ID != "review-1" {
+		t.Errorf("ListNeedsReview() = %v, want [review-1]", receipts)
+	}
+}
+
 // TestReceiptUseCase_parseItemCategories カテゴリーパース機能のテスト
 func TestReceiptUseCase_parseItemCategories(t *testing.T) {
 	uc := NewReceiptUseCase(nil, nil, nil)
@@ -684,7 +1006,7 @@ func TestReceiptUseCase_parseItemCategories(t *testing.T) {
 	}
 }
 
-// TestReceiptUseCase_ItemIDLength アイテムIDの長さが45文字であることを検証
+// TestReceiptUseCase_ItemIDLength アイテムIDがUUID形式の36文字で、明細ごとに決定的であることを検証
 func TestReceiptUseCase_ItemIDLength(t *testing.T) {
 	mockAI := &MockAIRepository{}
 	mockReceipt := &MockReceiptRepository{}
@@ -705,7 +1027,7 @@ func TestReceiptUseCase_ItemIDLength(t *testing.T) {
 		]
 	}`
 
-	receipt, err := uc.parseReceiptJSON(receiptJSON, testReceiptID)
+	receipt, err := uc.parseReceiptJSON(receiptJSON, testReceiptID, time.UTC)
 	if err != nil {
 		t.Fatalf("parseReceiptJSON() error = %v", err)
 	}
@@ -715,25 +1037,48 @@ func TestReceiptUseCase_ItemIDLength(t *testing.T) {
 	}
 
 	for i, item := range receipt.Items {
-		// アイテムIDは45文字であることを確認
-		if len(item.ID) != 45 {
-			t.Errorf("Item[%d] ID length = %d, want 45: %s", i, len(item.ID), item.ID)
+		// アイテムIDはUUID形式の36文字であることを確認
+		if len(item.ID) != 36 {
+			t.Errorf("Item[%d] ID length = %d, want 36: %s", i, len(item.ID), item.ID)
 		}
 
-		// アイテムIDがレシートIDで始まることを確認
-		if item.ID[:36] != testReceiptID {
-			t.Errorf("Item[%d] ID should start with receipt ID: got %s, want prefix %s", i, item.ID, testReceiptID)
-		}
-
-		// アイテムIDの形式を確認（36文字のレシートID + "-" + 8桁の数字）
-		expectedID := fmt.Sprintf("%s-%08d", testReceiptID, i)
+		// hash方式と同じ識別子であることを確認
+		expectedID := idgen.HashGenerator{}.ChildID(testReceiptID, i, fmt.Appendf(nil, "%s\x00%d", item.Name, item.Price))
 		if item.ID != expectedID {
 			t.Errorf("Item[%d] ID = %s, want %s", i, item.ID, expectedID)
 		}
+	}
+
+	// 同じレシートを明細の並びを変えて再処理しても、別の明細と同じIDにならない
+	reordered, err := uc.parseReceiptJSON(`{
+		"store_name": "Test Store",
+		"purchase_date": "2025-11-23 12:00",
+		"total_amount": 1000,
+		"tax_amount": 100,
+		"items": [
+			{"name": "Item2", "quantity": 2, "price": 250},
+			{"name": "Item1", "quantity": 1, "price": 500}
+		]
+	}`, testReceiptID, time.UTC)
+	if err != nil {
+		t.Fatalf("parseReceiptJSON() error = %v", err)
+	}
+	for _, item := range reordered.Items {
+		for _, prev := range receipt.Items {
+			if item.ID == prev.ID {
+				t.Errorf("reordered item %s reuses id %s of %s", item.Name, item.ID, prev.Name)
+			}
+		}
+	}
 
-		// データベース制約（VARCHAR(50)）に収まることを確認
-		if len(item.ID) > 50 {
-			t.Errorf("Item[%d] ID length %d exceeds database constraint VARCHAR(50)", i, len(item.ID))
+	// 同じ内容で再処理した場合は同じID（upsertで既存の行を更新する）
+	again, err := uc.parseReceiptJSON(receiptJSON, testReceiptID, time.UTC)
+	if err != nil {
+		t.Fatalf("parseReceiptJSON() error = %v", err)
+	}
+	for i := range again.Items {
+		if again.Items[i].ID != receipt.Items[i].ID {
+			t.Errorf("Item[%d] ID = %s on reprocess, want %s", i, again.Items[i].ID, receipt.Items[i].ID)
 		}
 	}
 }
@@ -770,7 +1115,7 @@ func TestReceiptUseCase_parseReceiptJSON(t *testing.T) {
 
 			// UUID形式のレシートID（36文字）を使用
 			testReceiptID := "12345678-1234-1234-1234-123456789012"
-			receipt, err := uc.parseReceiptJSON(tt.json, testReceiptID)
+			receipt, err := uc.parseReceiptJSON(tt.json, testReceiptID, time.UTC)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("parseReceiptJSON() error = %v, wantErr %v", err, tt.wantErr)
@@ -784,18 +1129,1653 @@ func TestReceiptUseCase_parseReceiptJSON(t *testing.T) {
 			// 正常ケースの場合、アイテムIDの長さを確認
 			if !tt.wantErr && receipt != nil {
 				for _, item := range receipt.Items {
-					if len(item.ID) != 45 {
-						t.Errorf("Item ID length should be 45, got %d: %s", len(item.ID), item.ID)
+					if len(item.ID) != 36 {
+						t.Errorf("Item ID length should be 36, got %d: %s", len(item.ID), item.ID)
 					}
 					if item.ReceiptID != testReceiptID {
 						t.Errorf("Item ReceiptID should match receipt ID: got %s, want %s", item.ReceiptID, testReceiptID)
 					}
-					// アイテムIDがレシートIDで始まることを確認
-					if len(item.ID) >= len(testReceiptID) && item.ID[:len(testReceiptID)] != testReceiptID {
-						t.Errorf("Item ID should start with receipt ID: got %s, want prefix %s", item.ID, testReceiptID)
-					}
 				}
 			}
 		})
 	}
 }
+
+func TestReceiptUseCase_parseReceiptJSON_Location(t *testing.T) {
+	// 購入日時は指定したタイムゾーンの時刻として解釈する（UTCとは日付が異なる時刻）
+	tokyo := time.FixedZone("JST", 9*60*60)
+	uc := NewReceiptUseCase(&MockAIRepository{}, &MockReceiptRepository{}, &MockCacheRepository{})
+
+	receipt, err := uc.parseReceiptJSON(`{"store_name":"Test","purchase_date":"2025-12-01 08:30","total_amount":100}`, "12345678-1234-1234-1234-123456789012", tokyo)
+	if err != nil {
+		t.Fatalf("parseReceiptJSON() error = %v", err)
+	}
+
+	want := time.Date(2025, 11, 30, 23, 30, 0, 0, time.UTC)
+	if !receipt.PurchaseDate.Equal(want) {
+		t.Errorf("PurchaseDate = %v, want %v", receipt.PurchaseDate, want)
+	}
+}
+
+func TestReceiptUseCase_parseReceiptJSON_TotalAmount(t *testing.T) {
+	tests := []struct {
+		name            string
+		json            string
+		wantTotal       int64
+		wantNeedsReview bool
+	}{
+		{
+			name:      "明細の合計と一致",
+			json:      `{"store_name":"Test","total_amount":1000,"items":[{"name":"Item","quantity":2,"price":500}]}`,
+			wantTotal: 1000,
+		},
+		{
+			name:      "合計金額が読み取れない場合は明細の合計で補う",
+			json:      `{"store_name":"Test","total_amount":0,"items":[{"name":"Item","quantity":2,"price":500}]}`,
+			wantTotal: 1000,
+		},
+		{
+			name:            "値引きなどで一致しない場合は印字された合計を残して要確認",
+			json:            `{"store_name":"Test","total_amount":900,"items":[{"name":"Item","quantity":2,"price":500}]}`,
+			wantTotal:       900,
+			wantNeedsReview: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewReceiptUseCase(&MockAIRepository{}, &MockReceiptRepository{}, &MockCacheRepository{})
+
+			receipt, err := uc.parseReceiptJSON(tt.json, "12345678-1234-1234-1234-123456789012", time.UTC)
+			if err != nil {
+				t.Fatalf("parseReceiptJSON() error = %v", err)
+			}
+			if receipt.TotalAmount != tt.wantTotal {
+				t.Errorf("TotalAmount = %d, want %d", receipt.TotalAmount, tt.wantTotal)
+			}
+			if receipt.NeedsReview != tt.wantNeedsReview {
+				t.Errorf("NeedsReview = %v, want %v", receipt.NeedsReview, tt.wantNeedsReview)
+			}
+		})
+	}
+}
+
+func TestReceiptUseCase_parseReceiptJSON_Currency(t *testing.T) {
+	usd, err := sharedDomain.LookupCurrency("usd")
+	if err != nil {
+		t.Fatalf("LookupCurrency() error = %v", err)
+	}
+	uc := NewReceiptUseCase(&MockAIRepository{}, &MockReceiptRepository{}, &MockCacheRepository{})
+	uc.SetCurrency(usd)
+
+	receipt, err := uc.parseReceiptJSON(`{"store_name":"Test","total_amount":0,"tax_amount":1.045,"items":[{"name":"Milk","quantity":2,"price":3.49},{"name":"Bread","quantity":1,"price":2}]}`, "12345678-1234-1234-1234-123456789012", time.UTC)
+	if err != nil {
+		t.Fatalf("parseReceiptJSON() error = %v", err)
+	}
+	// 金額はセント単位の整数に変換し、セント未満は四捨五入する
+	if receipt.Items[0].Price != 349 || receipt.Items[1].Price != 200 {
+		t.Errorf("prices = %d, %d, want 349, 200", receipt.Items[0].Price, receipt.Items[1].Price)
+	}
+	if receipt.TaxAmount != 105 {
+		t.Errorf("TaxAmount = %d, want 105", receipt.TaxAmount)
+	}
+	if receipt.TotalAmount != 898 {
+		t.Errorf("TotalAmount = %d, want 898", receipt.TotalAmount)
+	}
+	if got := usd.Display(receipt.TotalAmount); got != "$8.98" {
+		t.Errorf("Display() = %q, want %q", got, "$8.98")
+	}
+
+	if _, err := uc.parseReceiptJSON(`{"store_name":"Test","total_amount":"abc"}`, "12345678-1234-1234-1234-123456789012", time.UTC); err == nil {
+		t.Error("Expected error for non-numeric total_amount")
+	}
+}
+
+func TestReceiptUseCase_parseReceiptJSON_Locale(t *testing.T) {
+	uc := NewReceiptUseCase(&MockAIRepository{}, &MockReceiptRepository{}, &MockCacheRepository{})
+
+	// 欧州のレシート: 日/月/年の日付と小数点のカンマ、設定（JPY）と異なる通貨
+	receipt, err := uc.parseReceiptJSON(`{"store_name":"Rewe","purchase_date":"04.03.2025 18:05","locale":"de-DE","currency":"eur","total_amount":"3,48","items":[{"name":"Milch","quantity":2,"price":"1,74"}]}`, "receipt-de", time.UTC)
+	if err != nil {
+		t.Fatalf("parseReceiptJSON() error = %v", err)
+	}
+	if !receipt.PurchaseDate.Equal(time.Date(2025, 3, 4, 18, 5, 0, 0, time.UTC)) {
+		t.Errorf("PurchaseDate = %v, want 2025-03-04 18:05", receipt.PurchaseDate)
+	}
+	if receipt.Locale != "de-DE" || receipt.Currency != "EUR" {
+		t.Errorf("Locale, Currency = %q, %q, want de-DE, EUR", receipt.Locale, receipt.Currency)
+	}
+	// 金額は設定の通貨（JPY）の単位で保存し、換算は要確認で利用者が行う
+	if receipt.TotalAmount != 3 || receipt.Items[0].Price != 2 {
+		t.Errorf("amounts = %d, %d, want 3, 2", receipt.TotalAmount, receipt.Items[0].Price)
+	}
+	if !receipt.NeedsReview {
+		t.Error("NeedsReview = false, want true for a currency other than the configured one")
+	}
+
+	// 米国のレシート: 月/日/年の日付（数値の金額はそのまま小数として扱う）
+	receipt, err = uc.parseReceiptJSON(`{"store_name":"Target","purchase_date":"03/04/2025","locale":"en-US","currency":"JPY","total_amount":1500,"items":[{"name":"Towel","quantity":1,"price":1500}]}`, "receipt-us", time.UTC)
+	if err != nil {
+		t.Fatalf("parseReceiptJSON() error = %v", err)
+	}
+	if receipt.PurchaseDate.Month() != time.March || receipt.PurchaseDate.Day() != 4 {
+		t.Errorf("PurchaseDate = %v, want March 4", receipt.PurchaseDate)
+	}
+	if receipt.Currency != "JPY" || receipt.NeedsReview {
+		t.Errorf("Currency = %q, NeedsReview = %v, want JPY without review", receipt.Currency, receipt.NeedsReview)
+	}
+
+	// ロケールを読み取れなかった場合は既定値
+	receipt, err = uc.parseReceiptJSON(`{"store_name":"テスト","purchase_date":"2025-11-22 14:30","total_amount":100,"items":[{"name":"牛乳","quantity":1,"price":100}]}`, "receipt-jp", time.UTC)
+	if err != nil {
+		t.Fatalf("parseReceiptJSON() error = %v", err)
+	}
+	if receipt.Locale != entity.DefaultReceiptLocale || receipt.Currency != "JPY" {
+		t.Errorf("Locale, Currency = %q, %q, want defaults", receipt.Locale, receipt.Currency)
+	}
+}
+
+func TestReceiptUseCase_parseReceiptJSON_Refund(t *testing.T) {
+	uc := NewReceiptUseCase(&MockAIRepository{}, &MockReceiptRepository{}, &MockCacheRepository{})
+
+	tests := []struct {
+		name      string
+		json      string
+		wantType  string
+		wantTotal int64
+		wantTax   int64
+		wantPrice []int64
+	}{
+		{
+			name:      "印字の絶対値で読み取った返品",
+			json:      `{"store_name":"ストア","receipt_type":"refund","total_amount":1100,"tax_amount":100,"items":[{"name":"シャツ","quantity":1,"price":1100}]}`,
+			wantType:  entity.ReceiptTypeRefund,
+			wantTotal: -1100,
+			wantTax:   -100,
+			wantPrice: []int64{-1100},
+		},
+		{
+			name:      "末尾のマイナス記号",
+			json:      `{"store_name":"ストア","total_amount":"1,100-","items":[{"name":"シャツ","quantity":1,"price":"△1,100"}]}`,
+			wantType:  entity.ReceiptTypeRefund,
+			wantTotal: -1100,
+			wantPrice: []int64{-1100},
+		},
+		{
+			name:      "交換（返品と購入の混在）",
+			json:      `{"store_name":"ストア","receipt_type":"refund","total_amount":-300,"items":[{"name":"シャツM","quantity":1,"price":-1100},{"name":"シャツL","quantity":1,"price":800}]}`,
+			wantType:  entity.ReceiptTypeRefund,
+			wantTotal: -300,
+			wantPrice: []int64{-1100, 800},
+		},
+		{
+			name:      "値引きのある購入",
+			json:      `{"store_name":"ストア","total_amount":400,"items":[{"name":"弁当","quantity":1,"price":500},{"name":"値引","quantity":1,"price":-100}]}`,
+			wantType:  entity.ReceiptTypePurchase,
+			wantTotal: 400,
+			wantPrice: []int64{500, -100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipt, err := uc.parseReceiptJSON(tt.json, "receipt-1", time.UTC)
+			if err != nil {
+				t.Fatalf("parseReceiptJSON() error = %v", err)
+			}
+			if receipt.Type != tt.wantType || receipt.TotalAmount != tt.wantTotal || receipt.TaxAmount != tt.wantTax {
+				t.Errorf("receipt = type %s, total %d, tax %d, want %s, %d, %d", receipt.Type, receipt.TotalAmount, receipt.TaxAmount, tt.wantType, tt.wantTotal, tt.wantTax)
+			}
+			for i, want := range tt.wantPrice {
+				if receipt.Items[i].Price != want {
+					t.Errorf("Items[%d].Price = %d, want %d", i, receipt.Items[i].Price, want)
+				}
+			}
+			if err := receipt.Validate(); err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+			if receipt.NeedsReview {
+				t.Error("NeedsReview = true, want false")
+			}
+		})
+	}
+}
+
+func TestReceiptUseCase_parseReceiptJSON_WeighedItems(t *testing.T) {
+	uc := NewReceiptUseCase(&MockAIRepository{}, &MockReceiptRepository{}, &MockCacheRepository{})
+
+	receipt, err := uc.parseReceiptJSON(`{"store_name":"スーパー","total_amount":1236,"items":[
+		{"name":"豚こま切れ","quantity":320,"unit":"g","price":634,"unit_price":198,"unit_price_per":100},
+		{"name":"鶏もも","quantity":0.5,"unit":"kg","unit_price":1200},
+		{"name":"牛乳","quantity":1,"unit":"個","price":2}]}`, "receipt-1", time.UTC)
+	if err != nil {
+		t.Fatalf("parseReceiptJSON() error = %v", err)
+	}
+
+	pork, chicken, milk := receipt.Items[0], receipt.Items[1], receipt.Items[2]
+	if pork.Quantity != 1 || pork.Unit != entity.UnitGram || pork.Measure != 320 || pork.UnitPrice != 1980 || pork.Price != 634 {
+		t.Errorf("pork = %+v, want 320g of 1980/kg for 634", pork)
+	}
+	// 金額を読み取れなかった場合は単価と計量値から求める
+	if chicken.Price != 600 || chicken.Amount() != 600 {
+		t.Errorf("chicken price = %d, want 600", chicken.Price)
+	}
+	if milk.Unit != entity.UnitPiece || milk.Measure != 0 {
+		t.Errorf("milk = %+v, want a piece item", milk)
+	}
+	if receipt.NeedsReview {
+		t.Error("NeedsReview = true, want false when weighed amounts match the total")
+	}
+
+	// 単価×計量値と金額が一致しない明細は要確認
+	receipt, err = uc.parseReceiptJSON(`{"store_name":"スーパー","total_amount":900,"items":[{"name":"豚こま切れ","quantity":320,"unit":"g","price":900,"unit_price":198,"unit_price_per":100}]}`, "receipt-2", time.UTC)
+	if err != nil {
+		t.Fatalf("parseReceiptJSON() error = %v", err)
+	}
+	if !receipt.NeedsReview {
+		t.Error("NeedsReview = false, want true for a weighed amount mismatch")
+	}
+}
+
+func TestReceiptUseCase_ProcessReceiptImage_AsyncCategorization(t *testing.T) {
+	mockAI := &MockAIRepository{
+		RecognizeReceiptFunc: func(imageData []byte) (*domain.AIResult, error) {
+			return domain.NewAIResult("", `{"store_name":"Test","purchase_date":"2025-11-23 12:00","items":[{"name":"牛乳","quantity":1,"price":200},{"name":"洗剤","quantity":1,"price":300}]}`, 10, 5, "test"), nil
+		},
+		CategorizeReceiptFunc: func(receiptInfo string) (*domain.AIResult, error) {
+			return domain.NewAIResult("", `["食費", "日用品"]`, 10, 5, "test"), nil
+		},
+	}
+
+	var stored *entity.Receipt
+	var updated *entity.Receipt
+	mockReceipt := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			if stored == nil {
+				return nil, errors.New("not found")
+			}
+			// 保存済みデータのコピーを返す
+			copied := *stored
+			copied.Items = append([]entity.ReceiptItem(nil), stored.Items...)
+			return &copied, nil
+		},
+		CreateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			copied := *receipt
+			copied.Items = append([]entity.ReceiptItem(nil), receipt.Items...)
+			stored = &copied
+			return nil
+		},
+		UpdateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			updated = receipt
+			return nil
+		},
+	}
+
+	q := queue.NewMemoryQueue(1, 10)
+	defer func() {
+		_ = q.Close(context.Background())
+	}()
+
+	uc := NewReceiptUseCase(mockAI, mockReceipt, &MockCacheRepository{})
+	uc.SetJobQueue(q)
+
+	receipt, err := uc.ProcessReceiptImage(context.Background(), []byte("async image"))
+	if err != nil {
+		t.Fatalf("ProcessReceiptImage() error = %v", err)
+	}
+
+	// 保存時点ではカテゴリー未判定
+	for _, item := range stored.Items {
+		if item.Category != "" {
+			t.Errorf("stored item %s category = %s, want empty", item.Name, item.Category)
+		}
+	}
+
+	if err := q.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	if updated == nil {
+		t.Fatal("Expected receipt to be updated by categorization job")
+	}
+	if updated.ID != receipt.ID {
+		t.Errorf("updated ID = %s, want %s", updated.ID, receipt.ID)
+	}
+	want := []string{"食費", "日用品"}
+	for i, item := range updated.Items {
+		if item.Category != want[i] {
+			t.Errorf("Items[%d].Category = %s, want %s", i, item.Category, want[i])
+		}
+	}
+}
+
+// TestReceiptUseCase_handleCategorizeJob_KeepsManualChanges 判定を待つ間の手動の変更を上書きしないことのテスト
+func TestReceiptUseCase_handleCategorizeJob_KeepsManualChanges(t *testing.T) {
+	stored := &entity.Receipt{
+		ID:        "receipt-1",
+		StoreName: "テストマート",
+		Items: []entity.ReceiptItem{
+			{ID: "item-1", ReceiptID: "receipt-1", Name: "牛乳", Quantity: 1, Price: 200, CategoryStatus: entity.CategoryStatusPending},
+			{ID: "item-2", ReceiptID: "receipt-1", Name: "洗剤", Quantity: 1, Price: 300, CategoryStatus: entity.CategoryStatusPending},
+		},
+	}
+	var prompt string
+	mockAI := &MockAIRepository{
+		CategorizeReceiptFunc: func(receiptInfo string) (*domain.AIResult, error) {
+			prompt = receiptInfo
+			// AIの判定中に利用者が1件目のカテゴリーとメモを変更する
+			stored.Items[0].Category = "日用品"
+			stored.Items[0].CategoryStatus = entity.CategoryStatusManual
+			stored.Memo = "手動で変更"
+			return domain.NewAIResult("", `["食費", "日用品"]`, 10, 5, "test"), nil
+		},
+	}
+	var updated *entity.Receipt
+	mockReceipt := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			copied := *stored
+			copied.Items = append([]entity.ReceiptItem(nil), stored.Items...)
+			return &copied, nil
+		},
+		UpdateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			updated = receipt
+			return nil
+		},
+	}
+	uc := NewReceiptUseCase(mockAI, mockReceipt, nil)
+
+	if err := uc.handleCategorizeJob(context.Background(), &sharedDomain.Job{Payload: []byte(`{"receipt_id":"receipt-1"}`)}); err != nil {
+		t.Fatalf("handleCategorizeJob() error = %v", err)
+	}
+	if updated == nil {
+		t.Fatal("Expected receipt to be updated")
+	}
+	if item := updated.Items[0]; item.Category != "日用品" || item.CategoryStatus != entity.CategoryStatusManual {
+		t.Errorf("Items[0] = (%s, %s), want manual 日用品", item.Category, item.CategoryStatus)
+	}
+	if item := updated.Items[1]; item.Category != "日用品" || item.CategoryStatus != entity.CategoryStatusAuto {
+		t.Errorf("Items[1] = (%s, %s), want auto 日用品", item.Category, item.CategoryStatus)
+	}
+	if updated.Memo != "手動で変更" {
+		t.Errorf("Memo = %q, want 手動で変更", updated.Memo)
+	}
+
+	// 判定済みの明細はAIに送らず、判定待ちの明細がなければ何もしない
+	stored.Items[1].Category = ""
+	stored.Items[1].CategoryStatus = entity.CategoryStatusPending
+	updated = nil
+	if err := uc.handleCategorizeJob(context.Background(), &sharedDomain.Job{Payload: []byte(`{"receipt_id":"receipt-1"}`)}); err != nil {
+		t.Fatalf("handleCategorizeJob() error = %v", err)
+	}
+	if prompt == "" || strings.Contains(prompt, "牛乳") || !strings.Contains(prompt, "1. 洗剤") {
+		t.Errorf("prompt = %q, want only 洗剤", prompt)
+	}
+	stored.Items[1].CategoryStatus = entity.CategoryStatusAuto
+	updated, prompt = nil, ""
+	if err := uc.handleCategorizeJob(context.Background(), &sharedDomain.Job{Payload: []byte(`{"receipt_id":"receipt-1"}`)}); err != nil {
+		t.Fatalf("handleCategorizeJob() error = %v", err)
+	}
+	if updated != nil || prompt != "" {
+		t.Error("Expected receipt without pending items not to be categorized")
+	}
+}
+
+// TestReceiptUseCase_UpdateReceiptHistory 変更履歴の記録と巻き戻しのテスト
+func TestReceiptUseCase_UpdateReceiptHistory(t *testing.T) {
+	stored := &entity.Receipt{
+		ID:          "receipt-1",
+		StoreName:   "テストマート",
+		TotalAmount: 300,
+		Items: []entity.ReceiptItem{
+			{ID: "item-1", ReceiptID: "receipt-1", Name: "牛乳", Quantity: 1, Price: 300, Category: "食費"},
+		},
+	}
+	mockReceipt := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			copied := *stored
+			copied.Items = append([]entity.ReceiptItem(nil), stored.Items...)
+			return &copied, nil
+		},
+		UpdateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			copied := *receipt
+			copied.Items = append([]entity.ReceiptItem(nil), receipt.Items...)
+			stored = &copied
+			return nil
+		},
+	}
+	revisionRepo := &MockReceiptRevisionRepository{}
+
+	uc := NewReceiptUseCase(&MockAIRepository{}, mockReceipt, &MockCacheRepository{})
+	uc.SetRevisionRepository(revisionRepo)
+	ctx := context.Background()
+
+	// 1回目の修正では変更前の状態も記録される
+	edited, _ := uc.GetReceipt(ctx, "receipt-1")
+	edited.StoreName = "誤った店名"
+	edited.Items[0].Category = "日用品"
+	if err := uc.UpdateReceipt(ctx, edited, entity.RevisionSourceManual); err != nil {
+		t.Fatalf("UpdateReceipt() error = %v", err)
+	}
+
+	history, err := uc.GetReceiptHistory(ctx, "receipt-1")
+	if err != nil {
+		t.Fatalf("GetReceiptHistory() error = %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("history length = %d, want 2", len(history))
+	}
+	if history[0].Source != entity.RevisionSourceOriginal || history[0].Snapshot.StoreName != "テストマート" {
+		t.Errorf("history[0] = %s/%s, want original/テストマート", history[0].Source, history[0].Snapshot.StoreName)
+	}
+	if history[1].Source != entity.RevisionSourceManual || history[1].Revision != 2 {
+		t.Errorf("history[1] = %s/%d, want manual/2", history[1].Source, history[1].Revision)
+	}
+
+	// 元の状態に戻す
+	reverted, err := uc.RevertReceipt(ctx, "receipt-1", 1)
+	if err != nil {
+		t.Fatalf("RevertReceipt() error = %v", err)
+	}
+	if reverted.StoreName != "テストマート" || stored.StoreName != "テストマート" {
+		t.Errorf("StoreName = %s (stored %s), want テストマート", reverted.StoreName, stored.StoreName)
+	}
+	if stored.Items[0].Category != "食費" {
+		t.Errorf("Items[0].Category = %s, want 食費", stored.Items[0].Category)
+	}
+
+	history, _ = uc.GetReceiptHistory(ctx, "receipt-1")
+	if len(history) != 3 || history[2].Source != entity.RevisionSourceRevert {
+		t.Errorf("expected revert revision to be recorded, got %d revisions", len(history))
+	}
+
+	// 存在しないリビジョン
+	if _, err := uc.RevertReceipt(ctx, "receipt-1", 10); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("RevertReceipt() error = %v, want ErrRevisionNotFound", err)
+	}
+}
+
+// TestReceiptUseCase_ReprocessReceipt 保存済み画像からの再処理テスト
+func TestReceiptUseCase_ReprocessReceipt(t *testing.T) {
+	var stored *entity.Receipt
+	var cacheHits int
+	mockAI := &MockAIRepository{}
+	mockReceipt := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			if stored == nil {
+				return nil, errors.New("not found")
+			}
+			copied := *stored
+			copied.Items = append([]entity.ReceiptItem(nil), stored.Items...)
+			return &copied, nil
+		},
+		CreateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			copied := *receipt
+			stored = &copied
+			return nil
+		},
+		UpdateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			copied := *receipt
+			stored = &copied
+			return nil
+		},
+	}
+	mockCache := &MockCacheRepository{
+		GetFunc: func(ctx context.Context, key string) ([]byte, error) {
+			cacheHits++
+			return nil, errors.New("not found")
+		},
+	}
+
+	imageStorage, err := storage.NewLocalImageStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalImageStorage() error = %v", err)
+	}
+	revisionRepo := &MockReceiptRevisionRepository{}
+
+	uc := NewReceiptUseCase(mockAI, mockReceipt, mockCache)
+	uc.SetImageStorage(imageStorage)
+	uc.SetRevisionRepository(revisionRepo)
+	ctx := context.Background()
+
+	receipt, err := uc.ProcessReceiptImage(ctx, []byte("receipt image"))
+	if err != nil {
+		t.Fatalf("ProcessReceiptImage() error = %v", err)
+	}
+	createdAt := stored.CreatedAt
+
+	// プロンプト改善後の結果を返すようにする
+	mockAI.RecognizeReceiptFunc = func(imageData []byte) (*domain.AIResult, error) {
+		if string(imageData) != "receipt image" {
+			t.Errorf("imageData = %s, want stored image", imageData)
+		}
+		return domain.NewAIResult("", `{"store_name":"Improved Store","purchase_date":"2025-11-23 12:00","total_amount":1000,"items":[{"name":"Item1","quantity":1,"price":1000}]}`, 10, 5, "test"), nil
+	}
+	hitsBefore := cacheHits
+
+	reprocessed, err := uc.ReprocessReceipt(ctx, receipt.ID)
+	if err != nil {
+		t.Fatalf("ReprocessReceipt() error = %v", err)
+	}
+	if cacheHits != hitsBefore {
+		t.Error("ReprocessReceipt() should bypass the cache")
+	}
+	if reprocessed.ID != receipt.ID || stored.StoreName != "Improved Store" {
+		t.Errorf("stored = %s/%s, want %s/Improved Store", stored.ID, stored.StoreName, receipt.ID)
+	}
+	if !stored.CreatedAt.Equal(createdAt) {
+		t.Errorf("CreatedAt = %v, want %v", stored.CreatedAt, createdAt)
+	}
+
+	history, _ := uc.GetReceiptHistory(ctx, receipt.ID)
+	if len(history) != 2 || history[1].Source != entity.RevisionSourceReprocess {
+		t.Errorf("expected original and reprocess revisions, got %d", len(history))
+	}
+
+	// 画像が保存されていないレシート
+	if err := imageStorage.Delete(ctx, receipt.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := uc.ReprocessReceipt(ctx, receipt.ID); !errors.Is(err, ErrImageNotStored) {
+		t.Errorf("ReprocessReceipt() error = %v, want ErrImageNotStored", err)
+	}
+}
+
+// TestReceiptUseCase_Tags 登録時のタグ・メモ指定と修正のテスト
+func TestReceiptUseCase_Tags(t *testing.T) {
+	var stored *entity.Receipt
+	mockReceipt := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			if stored == nil {
+				return nil, errors.New("not found")
+			}
+			copied := *stored
+			return &copied, nil
+		},
+		CreateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			copied := *receipt
+			stored = &copied
+			return nil
+		},
+		UpdateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			copied := *receipt
+			stored = &copied
+			return nil
+		},
+		FindByFilterFunc: func(ctx context.Context, filter repository.ReceiptFilter, limit, offset int) ([]*entity.Receipt, error) {
+			if stored != nil && stored.HasTag(filter.Tag) {
+				return []*entity.Receipt{stored}, nil
+			}
+			return []*entity.Receipt{}, nil
+		},
+	}
+
+	uc := NewReceiptUseCase(&MockAIRepository{}, mockReceipt, &MockCacheRepository{})
+	ctx := context.Background()
+
+	receipt, err := uc.ProcessReceiptImageWithOptions(ctx, []byte("tagged image"), ProcessOptions{
+		Tags: []string{" 旅行", "旅行", "出張"},
+		Memo: " 出張の帰り ",
+	})
+	if err != nil {
+		t.Fatalf("ProcessReceiptImageWithOptions() error = %v", err)
+	}
+	if len(stored.Tags) != 2 || stored.Tags[0] != "旅行" || stored.Tags[1] != "出張" {
+		t.Errorf("stored Tags = %v, want [旅行 出張]", stored.Tags)
+	}
+	if stored.Memo != "出張の帰り" {
+		t.Errorf("stored Memo = %q, want 出張の帰り", stored.Memo)
+	}
+
+	found, err := uc.SearchReceipts(ctx, repository.ReceiptFilter{Tag: "出張"}, 20, 0)
+	if err != nil {
+		t.Fatalf("SearchReceipts() error = %v", err)
+	}
+	if len(found) != 1 {
+		t.Errorf("SearchReceipts(出張) returned %d receipts, want 1", len(found))
+	}
+
+	tags := []string{"家族"}
+	if _, err := uc.PatchReceipt(ctx, receipt.ID, ReceiptPatch{Tags: &tags}); err != nil {
+		t.Fatalf("PatchReceipt() error = %v", err)
+	}
+	if stored.Memo != "出張の帰り" {
+		t.Errorf("Memo changed by tag-only patch: %q", stored.Memo)
+	}
+	if len(stored.Tags) != 1 || stored.Tags[0] != "家族" {
+		t.Errorf("stored Tags = %v, want [家族]", stored.Tags)
+	}
+
+	found, _ = uc.SearchReceipts(ctx, repository.ReceiptFilter{Tag: "出張"}, 20, 0)
+	if len(found) != 0 {
+		t.Errorf("SearchReceipts(出張) after patch returned %d receipts, want 0", len(found))
+	}
+}
+
+// newMemoryCache メモリ上にキャッシュを保持するモックを作成
+func newMemoryCache() (*MockCacheRepository, map[string][]byte) {
+	entries := make(map[string][]byte)
+	return &MockCacheRepository{
+		GetFunc: func(ctx context.Context, key string) ([]byte, error) {
+			if value, ok := entries[key]; ok {
+				return value, nil
+			}
+			return nil, errors.New("not found")
+		},
+		SetFunc: func(ctx context.Context, key string, value []byte, expiration time.Duration) error {
+			entries[key] = value
+			return nil
+		},
+		DeleteFunc: func(ctx context.Context, key string) error {
+			delete(entries, key)
+			return nil
+		},
+		ExistsFunc: func(ctx context.Context, key string) (bool, error) {
+			_, ok := entries[key]
+			return ok, nil
+		},
+	}, entries
+}
+
+func TestReceiptUseCase_Extraction(t *testing.T) {
+	calls := 0
+	mockAI := &MockAIRepository{
+		RecognizeReceiptFunc: func(imageData []byte) (*domain.AIResult, error) {
+			calls++
+			result := domain.NewAIResult("", `{"store_name":"Test","purchase_date":"2025-11-23 12:00","total_amount":100,"items":[]}`, 10, 5, "test-model")
+			result.StopReason = "end_turn"
+			return result, nil
+		},
+	}
+	mockReceipt := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			return nil, errors.New("not found")
+		},
+	}
+	mockCache, _ := newMemoryCache()
+	uc := NewReceiptUseCase(mockAI, mockReceipt, mockCache)
+	ctx := context.Background()
+	imageData := []byte("receipt image")
+
+	// AIで読み取った場合はモデルと終了理由を記録する
+	receipt, err := uc.ProcessReceiptImage(ctx, imageData)
+	if err != nil {
+		t.Fatalf("ProcessReceiptImage() error = %v", err)
+	}
+	if got := receipt.Extraction; got.Model != "test-model" || got.StopReason != "end_turn" || got.CacheHit {
+		t.Errorf("Extraction = %+v, want model test-model, stop_reason end_turn, cache miss", got)
+	}
+
+	// キャッシュした読み取り結果を使った場合はAIを呼び出さず、モデルは記録しない
+	receipt, err = uc.ProcessReceiptImage(ctx, imageData)
+	if err != nil {
+		t.Fatalf("ProcessReceiptImage() error = %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("RecognizeReceipt calls = %d, want 1", calls)
+	}
+	if got := receipt.Extraction; got.Model != "" || got.StopReason != "" || !got.CacheHit {
+		t.Errorf("Extraction = %+v, want cache hit without model", got)
+	}
+	// キャッシュした結果でもAIの出力の原文を保存する
+	if !strings.Contains(receipt.Extraction.Raw, `"store_name":"Test"`) {
+		t.Errorf("Extraction.Raw = %q, want raw AI output", receipt.Extraction.Raw)
+	}
+}
+
+func TestReceiptUseCase_CompareExtraction(t *testing.T) {
+	calls := 0
+	mockAI := &MockAIRepository{
+		RecognizeReceiptFunc: func(imageData []byte) (*domain.AIResult, error) {
+			calls++
+			return domain.NewAIResult("", "```json\n"+`{"store_name":"Tset Mart","purchase_date":"2025-11-23 12:00","total_amount":300,"items":[{"name":"Milk","quantity":1,"price":300}]}`+"\n```", 10, 5, "test-model"), nil
+		},
+	}
+	var stored *entity.Receipt
+	mockReceipt := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			if stored == nil || stored.ID != id {
+				return nil, errors.New("not found")
+			}
+			copied := *stored
+			copied.Items = append([]entity.ReceiptItem(nil), stored.Items...)
+			return &copied, nil
+		},
+		CreateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			copied := *receipt
+			copied.Items = append([]entity.ReceiptItem(nil), receipt.Items...)
+			stored = &copied
+			return nil
+		},
+	}
+	uc := NewReceiptUseCase(mockAI, mockReceipt, nil)
+	ctx := context.Background()
+
+	receipt, err := uc.ProcessReceiptImage(ctx, []byte("receipt image"))
+	if err != nil {
+		t.Fatalf("ProcessReceiptImage() error = %v", err)
+	}
+	if !strings.HasPrefix(stored.Extraction.Raw, "```json") {
+		t.Errorf("stored Extraction.Raw = %q, want raw AI output as returned", stored.Extraction.Raw)
+	}
+
+	// 利用者が店名を修正した
+	stored.StoreName = "Test Mart"
+
+	comparison, err := uc.CompareExtraction(ctx, receipt.ID)
+	if err != nil {
+		t.Fatalf("CompareExtraction() error = %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("RecognizeReceipt calls = %d, want 1 (comparison must not call AI)", calls)
+	}
+	if comparison.Extracted.StoreName != "Tset Mart" || comparison.Current.StoreName != "Test Mart" {
+		t.Errorf("store names = %q / %q, want extracted and corrected", comparison.Extracted.StoreName, comparison.Current.StoreName)
+	}
+	if !slices.Equal(comparison.ChangedFields, []string{"store_name"}) {
+		t.Errorf("ChangedFields = %v, want [store_name]", comparison.ChangedFields)
+	}
+
+	// 原文を保存していないレシート（手動登録など）は比較できない
+	stored.Extraction = entity.ReceiptExtraction{}
+	if _, err := uc.CompareExtraction(ctx, receipt.ID); !errors.Is(err, ErrExtractionNotStored) {
+		t.Errorf("CompareExtraction() error = %v, want ErrExtractionNotStored", err)
+	}
+}
+
+func TestReceiptUseCase_DeleteReceipt(t *testing.T) {
+	tests := []struct {
+		name     string
+		useQueue bool
+	}{
+		{name: "ジョブキューで消去", useQueue: true},
+		{name: "キューなしで同期的に消去", useQueue: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := map[string]*entity.Receipt{}
+			mockAI := &MockAIRepository{
+				RecognizeReceiptFunc: func(imageData []byte) (*domain.AIResult, error) {
+					return domain.NewAIResult("", `{"store_name":"Test","purchase_date":"2025-11-23 12:00","total_amount":100,"items":[]}`, 10, 5, "test"), nil
+				},
+			}
+			mockReceipt := &MockReceiptRepository{
+				CreateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+					stored[receipt.ID] = receipt
+					return nil
+				},
+				FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+					if receipt, ok := stored[id]; ok {
+						return receipt, nil
+					}
+					return nil, errors.New("not found")
+				},
+				DeleteFunc: func(ctx context.Context, id string) error {
+					delete(stored, id)
+					return nil
+				},
+			}
+			mockCache, entries := newMemoryCache()
+
+			imageStorage, err := storage.NewLocalImageStorage(t.TempDir())
+			if err != nil {
+				t.Fatalf("NewLocalImageStorage() error = %v", err)
+			}
+
+			uc := NewReceiptUseCase(mockAI, mockReceipt, mockCache)
+			uc.SetImageStorage(imageStorage)
+			var q *queue.MemoryQueue
+			if tt.useQueue {
+				q = queue.NewMemoryQueue(1, 10)
+				defer func() {
+					_ = q.Close(context.Background())
+				}()
+				uc.SetJobQueue(q)
+			}
+			ctx := context.Background()
+
+			imageData := []byte("receipt image")
+			receipt, err := uc.ProcessReceiptImage(ctx, imageData)
+			if err != nil {
+				t.Fatalf("ProcessReceiptImage() error = %v", err)
+			}
+			// vision APIの解析結果も同じ画像でキャッシュされている
+			entries[uc.generateCacheKey("analyze", domain.OperationRecognizeImage, imageData)] = []byte("{}")
+			if len(entries) != 2 {
+				t.Fatalf("cache entries = %d, want 2", len(entries))
+			}
+
+			if err := uc.DeleteReceipt(ctx, receipt.ID); err != nil {
+				t.Fatalf("DeleteReceipt() error = %v", err)
+			}
+			if q != nil {
+				if err := q.Flush(ctx); err != nil {
+					t.Fatalf("Flush() error = %v", err)
+				}
+			}
+
+			if _, ok := stored[receipt.ID]; ok {
+				t.Error("Expected receipt to be deleted")
+			}
+			if _, err := imageStorage.Load(ctx, receipt.ID); err == nil {
+				t.Error("Expected stored image to be deleted")
+			}
+			if len(entries) != 0 {
+				t.Errorf("cache entries = %d, want 0", len(entries))
+			}
+
+			if err := uc.DeleteReceipt(ctx, receipt.ID); err == nil {
+				t.Error("Expected error for deleted receipt")
+			}
+		})
+	}
+}
+
+func TestReceiptUseCase_StorageQuota(t *testing.T) {
+	imageStorage, err := storage.NewLocalImageStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalImageStorage() error = %v", err)
+	}
+	imageStorage.SetQuota(20)
+
+	recognized := 0
+	mockAI := &MockAIRepository{
+		RecognizeReceiptFunc: func(imageData []byte) (*domain.AIResult, error) {
+			recognized++
+			return domain.NewAIResult("", `{"store_name":"Test Store","total_amount":100,"items":[]}`, 10, 5, "test"), nil
+		},
+	}
+	mockReceipt := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			return nil, errors.New("not found")
+		},
+	}
+	uc := NewReceiptUseCase(mockAI, mockReceipt, &MockCacheRepository{})
+	uc.SetImageStorage(imageStorage)
+	ctx := context.Background()
+
+	if _, err := uc.ProcessReceiptImage(ctx, []byte("receipt image 1")); err != nil {
+		t.Fatalf("ProcessReceiptImage() error = %v", err)
+	}
+
+	// 上限を超える画像はAIを呼び出す前に拒否する
+	_, err = uc.ProcessReceiptImage(ctx, []byte("receipt image 2"))
+	if !errors.Is(err, sharedDomain.ErrStorageQuotaExceeded) {
+		t.Errorf("ProcessReceiptImage() error = %v, want ErrStorageQuotaExceeded", err)
+	}
+	if recognized != 1 {
+		t.Errorf("RecognizeReceipt called %d times, want 1", recognized)
+	}
+
+	usage, err := uc.StorageUsage(ctx)
+	if err != nil {
+		t.Fatalf("StorageUsage() error = %v", err)
+	}
+	if usage.UsedBytes != 15 || usage.QuotaBytes != 20 || usage.Images != 1 {
+		t.Errorf("StorageUsage() = %+v, want 15/20 bytes and 1 image", usage)
+	}
+}
+
+func TestReceiptUseCase_ScrubExpiredImages(t *testing.T) {
+	dir := t.TempDir()
+	imageStorage, err := storage.NewLocalImageStorage(dir)
+	if err != nil {
+		t.Fatalf("NewLocalImageStorage() error = %v", err)
+	}
+	mockCache, entries := newMemoryCache()
+
+	uc := NewReceiptUseCase(&MockAIRepository{}, &MockReceiptRepository{}, mockCache)
+	uc.SetImageStorage(imageStorage)
+	ctx := context.Background()
+
+	images := map[string][]byte{"old-receipt": []byte("old image"), "new-receipt": []byte("new image")}
+	for id, data := range images {
+		if err := imageStorage.Save(ctx, id, data); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		entries[uc.generateCacheKey("receipt", domain.OperationRecognizeReceipt, data)] = []byte("{}")
+	}
+	old := time.Now().Add(-40 * 24 * time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "old-receipt"), old, old); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+
+	count, err := uc.ScrubExpiredImages(ctx, 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("ScrubExpiredImages() error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("count = %d, want 1", count)
+	}
+
+	if _, err := imageStorage.Load(ctx, "old-receipt"); err == nil {
+		t.Error("Expected expired image to be deleted")
+	}
+	if _, err := imageStorage.Load(ctx, "new-receipt"); err != nil {
+		t.Errorf("Expected new image to remain: %v", err)
+	}
+	if _, ok := entries[uc.generateCacheKey("receipt", domain.OperationRecognizeReceipt, images["old-receipt"])]; ok {
+		t.Error("Expected cache entry of expired image to be deleted")
+	}
+	if _, ok := entries[uc.generateCacheKey("receipt", domain.OperationRecognizeReceipt, images["new-receipt"])]; !ok {
+		t.Error("Expected cache entry of new image to remain")
+	}
+
+	// 保持期間が未設定の場合は何もしない
+	if count, err := uc.ScrubExpiredImages(ctx, 0); err != nil || count != 0 {
+		t.Errorf("ScrubExpiredImages(0) = %d, %v, want 0, nil", count, err)
+	}
+}
+
+func TestReceiptUseCase_PatchReceiptItems(t *testing.T) {
+	newStored := func() *entity.Receipt {
+		return &entity.Receipt{
+			ID:          "receipt-1",
+			StoreName:   "Test Store",
+			TotalAmount: 500,
+			NeedsReview: true,
+			Items: []entity.ReceiptItem{
+				{ID: "receipt-1-00000000", ReceiptID: "receipt-1", Name: "牛乳", Quantity: 1, Price: 200, Category: "食費", CategoryStatus: entity.CategoryStatusAuto},
+				{ID: "receipt-1-00000001", ReceiptID: "receipt-1", Name: "謎の品", Quantity: 1, Price: 300, Category: "その他", CategoryStatus: entity.CategoryStatusAutoFailed},
+			},
+		}
+	}
+
+	storeName := "Fixed Store"
+	total := int64(650)
+	tests := []struct {
+		name        string
+		patch       ReceiptPatch
+		wantErr     error
+		wantItems   []entity.ReceiptItem
+		wantReview  bool
+		wantStore   string
+		wantTotal   int64
+		checkFields bool
+	}{
+		{
+			name: "既存カテゴリーの引き継ぎと手動設定・追加",
+			patch: ReceiptPatch{
+				StoreName:   &storeName,
+				TotalAmount: &total,
+				Items: &[]ItemPatch{
+					{ID: "receipt-1-00000000", Name: "牛乳", Quantity: 1, Price: 200},
+					{ID: "receipt-1-00000001", Name: "洗剤", Quantity: 1, Price: 300, Category: "日用品"},
+					{Name: "パン", Quantity: 1, Price: 150, Category: "食費"},
+				},
+			},
+			wantItems: []entity.ReceiptItem{
+				{Name: "牛乳", Category: "食費", CategoryStatus: entity.CategoryStatusAuto},
+				{Name: "洗剤", Category: "日用品", CategoryStatus: entity.CategoryStatusManual},
+				{Name: "パン", Category: "食費", CategoryStatus: entity.CategoryStatusManual},
+			},
+			wantReview:  false,
+			wantStore:   "Fixed Store",
+			wantTotal:   650,
+			checkFields: true,
+		},
+		{
+			name: "要確認の明細に同じカテゴリーを指定すると確認済みになる",
+			patch: ReceiptPatch{
+				Items: &[]ItemPatch{
+					{ID: "receipt-1-00000000", Name: "牛乳", Quantity: 1, Price: 200},
+					{ID: "receipt-1-00000001", Name: "謎の品", Quantity: 1, Price: 300, Category: "その他"},
+				},
+			},
+			wantItems: []entity.ReceiptItem{
+				{Name: "牛乳", Category: "食費", CategoryStatus: entity.CategoryStatusAuto},
+				{Name: "謎の品", Category: "その他", CategoryStatus: entity.CategoryStatusManual},
+			},
+			wantReview: false,
+		},
+		{
+			name: "カテゴリーを確認しても合計金額と一致しない場合は要確認のまま",
+			patch: ReceiptPatch{
+				Items: &[]ItemPatch{
+					{ID: "receipt-1-00000001", Name: "謎の品", Quantity: 1, Price: 300, Category: "その他"},
+				},
+			},
+			wantItems: []entity.ReceiptItem{
+				{Name: "謎の品", Category: "その他", CategoryStatus: entity.CategoryStatusManual},
+			},
+			wantReview: true,
+		},
+		{
+			name: "カテゴリー未指定の新しい明細は要確認",
+			patch: ReceiptPatch{
+				Items: &[]ItemPatch{
+					{Name: "新商品", Quantity: 1, Price: 100},
+				},
+			},
+			wantItems: []entity.ReceiptItem{
+				{Name: "新商品", Category: "その他", CategoryStatus: entity.CategoryStatusAutoFailed},
+			},
+			wantReview: true,
+		},
+		{
+			name: "異常系: 不正な明細",
+			patch: ReceiptPatch{
+				Items: &[]ItemPatch{
+					{Name: "", Quantity: 1, Price: 100},
+				},
+			},
+			wantErr: ErrInvalidReceipt,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := newStored()
+			updated := false
+			mockReceipt := &MockReceiptRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+					return stored, nil
+				},
+				UpdateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+					updated = true
+					return nil
+				},
+			}
+			uc := NewReceiptUseCase(&MockAIRepository{}, mockReceipt, nil)
+
+			receipt, err := uc.PatchReceipt(context.Background(), "receipt-1", tt.patch)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("PatchReceipt() error = %v, want %v", err, tt.wantErr)
+				}
+				if updated {
+					t.Error("Expected receipt not to be updated")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PatchReceipt() error = %v", err)
+			}
+
+			if len(receipt.Items) != len(tt.wantItems) {
+				t.Fatalf("len(Items) = %d, want %d", len(receipt.Items), len(tt.wantItems))
+			}
+			for i, want := range tt.wantItems {
+				got := receipt.Items[i]
+				if got.ID != uc.newItemID("receipt-1", i, got.Name, got.Price) || got.Name != want.Name || got.Category != want.Category || got.CategoryStatus != want.CategoryStatus {
+					t.Errorf("Items[%d] = %+v, want %+v", i, got, want)
+				}
+			}
+			if receipt.NeedsReview != tt.wantReview {
+				t.Errorf("NeedsReview = %v, want %v", receipt.NeedsReview, tt.wantReview)
+			}
+			if tt.checkFields && (receipt.StoreName != tt.wantStore || receipt.TotalAmount != tt.wantTotal) {
+				t.Errorf("StoreName/TotalAmount = %s/%d, want %s/%d", receipt.StoreName, receipt.TotalAmount, tt.wantStore, tt.wantTotal)
+			}
+		})
+	}
+}
+
+// stubMetadataStripper 末尾のメタデータ（"|"以降）を取り除くスタブ
+type stubMetadataStripper struct{}
+
+func (stubMetadataStripper) StripMetadata(data []byte) ([]byte, error) {
+	if i := bytes.IndexByte(data, '|'); i >= 0 {
+		return data[:i], nil
+	}
+	return data, nil
+}
+
+func TestReceiptUseCase_StripMetadata(t *testing.T) {
+	tests := []struct {
+		name         string
+		keepLocation bool
+		wantStored   string
+	}{
+		{name: "正常系: メタデータを除去して解析・保存", keepLocation: false, wantStored: "receipt image"},
+		{name: "正常系: 位置情報の保存に同意した場合は残す", keepLocation: true, wantStored: "receipt image|GPS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var recognized []byte
+			mockAI := &MockAIRepository{
+				RecognizeReceiptFunc: func(imageData []byte) (*domain.AIResult, error) {
+					recognized = imageData
+					return domain.NewAIResult("", `{"store_name":"Test","purchase_date":"2025-11-23 12:00","total_amount":100,"items":[]}`, 10, 5, "test"), nil
+				},
+			}
+			mockReceipt := &MockReceiptRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+					return nil, errors.New("not found")
+				},
+			}
+			imageStorage, err := storage.NewLocalImageStorage(t.TempDir())
+			if err != nil {
+				t.Fatalf("NewLocalImageStorage() error = %v", err)
+			}
+
+			uc := NewReceiptUseCase(mockAI, mockReceipt, nil)
+			uc.SetImageStorage(imageStorage)
+			uc.SetMetadataStripper(stubMetadataStripper{})
+			ctx := context.Background()
+
+			receipt, err := uc.ProcessReceiptImageWithOptions(ctx, []byte("receipt image|GPS"), ProcessOptions{KeepLocation: tt.keepLocation})
+			if err != nil {
+				t.Fatalf("ProcessReceiptImageWithOptions() error = %v", err)
+			}
+
+			stored, err := imageStorage.Load(ctx, receipt.ID)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if string(stored) != tt.wantStored || string(recognized) != tt.wantStored {
+				t.Errorf("stored = %q, recognized = %q, want %q", stored, recognized, tt.wantStored)
+			}
+			if receipt.ID != uc.generateDeterministicReceiptID([]byte(tt.wantStored)) {
+				t.Error("receipt ID should be derived from the preprocessed image")
+			}
+		})
+	}
+}
+
+// stubFileScanner EICARを含むファイルを検出するスタブ
+type stubFileScanner struct {
+	err error
+}
+
+func (s stubFileScanner) Scan(ctx context.Context, data []byte) error {
+	if s.err != nil {
+		return s.err
+	}
+	if bytes.Contains(data, []byte("EICAR")) {
+		return fmt.Errorf("%w: Eicar-Test-Signature", sharedDomain.ErrFileInfected)
+	}
+	return nil
+}
+
+func TestReceiptUseCase_FileScanner(t *testing.T) {
+	tests := []struct {
+		name         string
+		scanner      stubFileScanner
+		imageData    []byte
+		wantInfected bool
+		wantErr      bool
+	}{
+		{name: "正常系: 問題のないファイル", imageData: []byte("receipt image")},
+		{name: "異常系: ウイルスを検出", imageData: []byte("EICAR test"), wantInfected: true, wantErr: true},
+		{name: "異常系: 検査サービスに接続できない", scanner: stubFileScanner{err: errors.New("connection refused")}, imageData: []byte("receipt image"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recognized := false
+			created := false
+			mockAI := &MockAIRepository{
+				RecognizeReceiptFunc: func(imageData []byte) (*domain.AIResult, error) {
+					recognized = true
+					return domain.NewAIResult("", `{"store_name":"Test","purchase_date":"2025-11-23 12:00","total_amount":100,"items":[]}`, 10, 5, "test"), nil
+				},
+			}
+			mockReceipt := &MockReceiptRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+					return nil, errors.New("not found")
+				},
+				CreateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+					created = true
+					return nil
+				},
+			}
+
+			uc := NewReceiptUseCase(mockAI, mockReceipt, nil)
+			uc.SetFileScanner(tt.scanner)
+
+			_, err := uc.ProcessReceiptImage(context.Background(), tt.imageData)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ProcessReceiptImage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, sharedDomain.ErrFileInfected) != tt.wantInfected {
+				t.Errorf("ProcessReceiptImage() error = %v, wantInfected %v", err, tt.wantInfected)
+			}
+			// 検査を通過しなかったファイルはAIに送信せず保存もしない
+			if tt.wantErr && (recognized || created) {
+				t.Error("rejected file should not be recognized or saved")
+			}
+		})
+	}
+}
+
+func TestReceiptUseCase_SpoolWhileDatabaseDown(t *testing.T) {
+	ctx := context.Background()
+	dbDown := true
+	saved := make(map[string]*entity.Receipt)
+	connErr := errors.New("dial tcp: connection refused")
+
+	mockAI := &MockAIRepository{
+		RecognizeReceiptFunc: func(imageData []byte) (*domain.AIResult, error) {
+			return domain.NewAIResult("", `{"store_name":"Test","purchase_date":"2025-11-23 12:00","total_amount":100,"items":[]}`, 10, 5, "test"), nil
+		},
+	}
+	mockReceipt := &MockReceiptRepository{
+		PingFunc: func(ctx context.Context) error {
+			if dbDown {
+				return connErr
+			}
+			return nil
+		},
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			if receipt, ok := saved[id]; ok && !dbDown {
+				return receipt, nil
+			}
+			return nil, errors.New("not found")
+		},
+		CreateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			if dbDown {
+				return connErr
+			}
+			saved[receipt.ID] = receipt
+			return nil
+		},
+	}
+
+	spool, err := storage.NewFileReceiptSpool(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileReceiptSpool() error = %v", err)
+	}
+	uc := NewReceiptUseCase(mockAI, mockReceipt, nil)
+	uc.SetReceiptSpool(spool)
+
+	// 障害中: レシートは一時保管され、登録結果として返る
+	receipt, err := uc.ProcessReceiptImage(ctx, []byte("receipt image"))
+	if !errors.Is(err, ErrSavePending) {
+		t.Fatalf("ProcessReceiptImage() error = %v, want ErrSavePending", err)
+	}
+	if receipt == nil || receipt.StoreName != "Test" {
+		t.Fatalf("ProcessReceiptImage() receipt = %+v, want spooled receipt", receipt)
+	}
+
+	got, err := uc.GetReceipt(ctx, receipt.ID)
+	if err != nil || got.ID != receipt.ID {
+		t.Errorf("GetReceipt() = %v, %v, want spooled receipt", got, err)
+	}
+
+	status, err := uc.SaveStatus(ctx)
+	if err != nil {
+		t.Fatalf("SaveStatus() error = %v", err)
+	}
+	if status.DatabaseAvailable || status.PendingSaves != 1 {
+		t.Errorf("SaveStatus() = %+v, want unavailable with 1 pending save", status)
+	}
+
+	// 障害中は書き戻さない
+	if n, err := uc.ReplayPendingSaves(ctx); err != nil || n != 0 {
+		t.Errorf("ReplayPendingSaves() while down = %d, %v, want 0, nil", n, err)
+	}
+
+	// 復旧後: 一時保管したレシートをデータベースへ書き戻す
+	dbDown = false
+	n, err := uc.ReplayPendingSaves(ctx)
+	if err != nil {
+		t.Fatalf("ReplayPendingSaves() error = %v", err)
+	}
+	if n != 1 {
+		t.Errorf("ReplayPendingSaves() = %d, want 1", n)
+	}
+	if _, ok := saved[receipt.ID]; !ok {
+		t.Error("spooled receipt was not saved to the database")
+	}
+
+	status, err = uc.SaveStatus(ctx)
+	if err != nil {
+		t.Fatalf("SaveStatus() error = %v", err)
+	}
+	if !status.DatabaseAvailable || status.PendingSaves != 0 {
+		t.Errorf("SaveStatus() after recovery = %+v, want available with no pending saves", status)
+	}
+}
+
+func TestReceiptUseCase_SaveErrorWithoutOutage(t *testing.T) {
+	mockAI := &MockAIRepository{
+		RecognizeReceiptFunc: func(imageData []byte) (*domain.AIResult, error) {
+			return domain.NewAIResult("", `{"store_name":"Test","purchase_date":"2025-11-23 12:00","total_amount":100,"items":[]}`, 10, 5, "test"), nil
+		},
+	}
+	mockReceipt := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			return nil, errors.New("not found")
+		},
+		CreateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			return errors.New("duplicate entry")
+		},
+	}
+
+	spool, err := storage.NewFileReceiptSpool(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileReceiptSpool() error = %v", err)
+	}
+	uc := NewReceiptUseCase(mockAI, mockReceipt, nil)
+	uc.SetReceiptSpool(spool)
+
+	// データベースに接続できる場合の保存エラーは一時保管せずエラーにする
+	_, err = uc.ProcessReceiptImage(context.Background(), []byte("receipt image"))
+	if err == nil || errors.Is(err, ErrSavePending) {
+		t.Fatalf("ProcessReceiptImage() error = %v, want save error", err)
+	}
+	if receipts, _ := spool.List(context.Background()); len(receipts) != 0 {
+		t.Errorf("spooled %d receipts, want 0", len(receipts))
+	}
+}
+
+func TestReceiptUseCase_PatchReceiptItems_Warranty(t *testing.T) {
+	months := func(n int) *int { return &n }
+	notified := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.Local)
+
+	var saved *entity.Receipt
+	mockReceipt := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			return &entity.Receipt{
+				ID:          id,
+				StoreName:   "家電店",
+				TotalAmount: 230000,
+				Items: []entity.ReceiptItem{
+					{ID: "tv", ReceiptID: id, Name: "テレビ", Quantity: 1, Price: 80000, Category: "家電", WarrantyMonths: months(12), ReturnNotifiedAt: &notified, WarrantyNotifiedAt: &notified},
+					{ID: "pc", ReceiptID: id, Name: "ノートPC", Quantity: 1, Price: 150000, Category: "家電", WarrantyMonths: months(12), ReturnNotifiedAt: &notified, WarrantyNotifiedAt: &notified},
+				},
+			}, nil
+		},
+		UpdateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			saved = receipt
+			return nil
+		},
+	}
+	uc := NewReceiptUseCase(&MockAIRepository{}, mockReceipt, &MockCacheRepository{})
+
+	_, err := uc.PatchReceipt(context.Background(), "receipt-1", ReceiptPatch{
+		Items: &[]ItemPatch{
+			// 保証期間を省略した場合は引き継ぐ
+			{ID: "tv", Name: "テレビ", Quantity: 1, Price: 80000},
+			// 保証期間を変更した場合は保証期限を通知し直す
+			{ID: "pc", Name: "ノートPC", Quantity: 1, Price: 150000, WarrantyMonths: months(36)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("PatchReceipt() error = %v", err)
+	}
+
+	tv, pc := saved.Items[0], saved.Items[1]
+	if tv.WarrantyMonths == nil || *tv.WarrantyMonths != 12 || tv.WarrantyNotifiedAt == nil || tv.ReturnNotifiedAt == nil {
+		t.Errorf("tv = %+v, want warranty 12 months and notified", tv)
+	}
+	if pc.WarrantyMonths == nil || *pc.WarrantyMonths != 36 || pc.WarrantyNotifiedAt != nil || pc.ReturnNotifiedAt == nil {
+		t.Errorf("pc = %+v, want warranty 36 months and warranty not notified", pc)
+	}
+
+	// 保証期間は0以上
+	_, err = uc.PatchReceipt(context.Background(), "receipt-1", ReceiptPatch{
+		Items: &[]ItemPatch{{ID: "tv", Name: "テレビ", Quantity: 1, Price: 80000, WarrantyMonths: months(-1)}},
+	})
+	if !errors.Is(err, ErrInvalidReceipt) {
+		t.Errorf("PatchReceipt() error = %v, want ErrInvalidReceipt", err)
+	}
+}
+
+func TestReceiptUseCase_ReplaceReceipt(t *testing.T) {
+	var saved *entity.Receipt
+	mockReceipt := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			return &entity.Receipt{
+				ID:          id,
+				StoreName:   "スーパー",
+				TotalAmount: 200,
+				Type:        entity.ReceiptTypeRefund,
+				Tags:        []string{"旅行"},
+				Memo:        "返品",
+				Items:       []entity.ReceiptItem{{ID: "milk", ReceiptID: id, Name: "牛乳", Quantity: 1, Price: 200, Category: "食費"}},
+			}, nil
+		},
+		UpdateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			saved = receipt
+			return nil
+		},
+	}
+	uc := NewReceiptUseCase(&MockAIRepository{}, mockReceipt, &MockCacheRepository{})
+
+	storeName := "ドラッグストア"
+	purchaseDate := time.Date(2025, time.June, 1, 10, 0, 0, 0, time.UTC)
+	total := int64(300)
+	items := []ItemPatch{{Name: "洗剤", Quantity: 1, Price: 300, Category: "日用品"}}
+
+	// 省略した種類・タグ・メモは既定値に戻す
+	_, err := uc.ReplaceReceipt(context.Background(), "receipt-1", ReceiptPatch{
+		StoreName:    &storeName,
+		PurchaseDate: &purchaseDate,
+		TotalAmount:  &total,
+		Items:        &items,
+	})
+	if err != nil {
+		t.Fatalf("ReplaceReceipt() error = %v", err)
+	}
+	if saved.StoreName != storeName || !saved.PurchaseDate.Equal(purchaseDate) || saved.TotalAmount != total {
+		t.Errorf("saved = %+v", saved)
+	}
+	if saved.Type != entity.ReceiptTypePurchase || len(saved.Tags) != 0 || saved.Memo != "" {
+		t.Errorf("saved = (%q, %v, %q), want defaults", saved.Type, saved.Tags, saved.Memo)
+	}
+	if len(saved.Items) != 1 || saved.Items[0].Name != "洗剤" {
+		t.Errorf("Items = %+v", saved.Items)
+	}
+
+	// 必須の項目を省略した場合は不正
+	_, err = uc.ReplaceReceipt(context.Background(), "receipt-1", ReceiptPatch{
+		StoreName:   &storeName,
+		TotalAmount: &total,
+		Items:       &items,
+	})
+	if !errors.Is(err, ErrInvalidReceipt) {
+		t.Errorf("ReplaceReceipt() error = %v, want ErrInvalidReceipt", err)
+	}
+}
+
+func TestReceiptUseCase_PatchReceiptItems_Unit(t *testing.T) {
+	var saved *entity.Receipt
+	mockReceipt := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			return &entity.Receipt{
+				ID:          id,
+				StoreName:   "スーパー",
+				TotalAmount: 834,
+				Items: []entity.ReceiptItem{
+					{ID: "pork", ReceiptID: id, Name: "豚こま切れ", Quantity: 1, Price: 634, Unit: entity.UnitGram, Measure: 320, UnitPrice: 1980, Category: "食費"},
+					{ID: "milk", ReceiptID: id, Name: "牛乳", Quantity: 1, Price: 200, Unit: entity.UnitPiece, Category: "食費"},
+				},
+			}, nil
+		},
+		UpdateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			saved = receipt
+			return nil
+		},
+	}
+	uc := NewReceiptUseCase(&MockAIRepository{}, mockReceipt, &MockCacheRepository{})
+
+	measure := 0.5
+	_, err := uc.PatchReceipt(context.Background(), "receipt-1", ReceiptPatch{
+		Items: &[]ItemPatch{
+			// 単位を省略した場合は計量値・単価を引き継ぐ
+			{ID: "pork", Name: "豚こま切れ", Quantity: 1, Price: 634},
+			{ID: "milk", Name: "キャベツ", Quantity: 1, Price: 200, Unit: entity.UnitKilogram, Measure: &measure},
+		},
+	})
+	if err != nil {
+		t.Fatalf("PatchReceipt() error = %v", err)
+	}
+	pork, cabbage := saved.Items[0], saved.Items[1]
+	if pork.Unit != entity.UnitGram || pork.Measure != 320 || pork.UnitPrice != 1980 {
+		t.Errorf("pork = %+v, want inherited unit, measure and unit price", pork)
+	}
+	if cabbage.Unit != entity.UnitKilogram || cabbage.Measure != 0.5 || cabbage.QuantityLabel() != "0.5kg" {
+		t.Errorf("cabbage = %+v, want 0.5kg", cabbage)
+	}
+
+	// 量り売りの明細は計量値が必要
+	_, err = uc.PatchReceipt(context.Background(), "receipt-1", ReceiptPatch{
+		Items: &[]ItemPatch{{Name: "豚ひき肉", Quantity: 1, Price: 500, Unit: entity.UnitGram}},
+	})
+	if !errors.Is(err, ErrInvalidReceipt) {
+		t.Errorf("PatchReceipt() error = %v, want ErrInvalidReceipt", err)
+	}
+}
+
+// recordingPublisher 配信されたドメインイベントの種類を記録する
+type recordingPublisher struct {
+	names []string
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, events ...sharedDomain.DomainEvent) {
+	for _, event := range events {
+		p.names = append(p.names, event.EventName())
+	}
+}
+
+func TestReceiptUseCase_PatchReceipt_PublishesEvents(t *testing.T) {
+	updateErr := errors.New("update failed")
+	mockReceipt := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			return &entity.Receipt{
+				ID:          "receipt-1",
+				StoreName:   "Test Store",
+				TotalAmount: 500,
+				Items: []entity.ReceiptItem{
+					{ID: "receipt-1-00000000", ReceiptID: "receipt-1", Name: "牛乳", Quantity: 1, Price: 200, Category: "その他", CategoryStatus: entity.CategoryStatusAutoFailed},
+				},
+			}, nil
+		},
+		UpdateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			return updateErr
+		},
+	}
+	publisher := &recordingPublisher{}
+	uc := NewReceiptUseCase(&MockAIRepository{}, mockReceipt, nil)
+	uc.SetEventPublisher(publisher)
+
+	total := int64(650)
+	patch := ReceiptPatch{
+		TotalAmount: &total,
+		Items:       &[]ItemPatch{{ID: "receipt-1-00000000", Name: "牛乳", Quantity: 1, Price: 200, Category: "食費"}},
+	}
+
+	// 保存に失敗した変更のイベントは配信しない
+	if _, err := uc.PatchReceipt(context.Background(), "receipt-1", patch); !errors.Is(err, updateErr) {
+		t.Fatalf("PatchReceipt() error = %v, want %v", err, updateErr)
+	}
+	if len(publisher.names) != 0 {
+		t.Fatalf("published = %v, want none", publisher.names)
+	}
+
+	updateErr = nil
+	if _, err := uc.PatchReceipt(context.Background(), "receipt-1", patch); err != nil {
+		t.Fatalf("PatchReceipt() error = %v", err)
+	}
+	want := []string{entity.EventTotalCorrected, entity.EventItemCategorized}
+	if !slices.Equal(publisher.names, want) {
+		t.Errorf("published = %v, want %v", publisher.names, want)
+	}
+}
+
+func TestReceiptUseCase_ProcessReceiptImage_Clock(t *testing.T) {
+	now := time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC)
+	mockAI := &MockAIRepository{
+		RecognizeReceiptFunc: func(imageData []byte) (*domain.AIResult, error) {
+			// 購入日を読み取れなかったレシート
+			return domain.NewAIResult("", `{"store_name":"Test","total_amount":100,"items":[{"name":"Item","quantity":1,"price":100}]}`, 10, 5, "test"), nil
+		},
+	}
+	mockReceipt := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			return nil, errors.New("receipt not found")
+		},
+		CreateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			return nil
+		},
+	}
+	uc := NewReceiptUseCase(mockAI, mockReceipt, nil)
+	uc.SetClock(sharedDomain.FixedClock(now))
+	ctx := context.Background()
+
+	receipt, err := uc.ProcessReceiptImage(ctx, []byte("image"))
+	if err != nil {
+		t.Fatalf("ProcessReceiptImage() error = %v", err)
+	}
+	if !receipt.PurchaseDate.Equal(now) || !receipt.CreatedAt.Equal(now) || !receipt.Items[0].CreatedAt.Equal(now) {
+		t.Errorf("dates = (%v, %v, %v), want %v", receipt.PurchaseDate, receipt.CreatedAt, receipt.Items[0].CreatedAt, now)
+	}
+
+	// 過去のレシートとして登録する場合は、指定した日時を作成日時・購入日の既定値とする
+	backdated := now.AddDate(-1, 0, 0)
+	receipt, err = uc.ProcessReceiptImageWithOptions(ctx, []byte("old image"), ProcessOptions{CreatedAt: backdated})
+	if err != nil {
+		t.Fatalf("ProcessReceiptImageWithOptions() error = %v", err)
+	}
+	if !receipt.PurchaseDate.Equal(backdated) || !receipt.CreatedAt.Equal(backdated) || !receipt.UpdatedAt.Equal(backdated) {
+		t.Errorf("dates = (%v, %v, %v), want %v", receipt.PurchaseDate, receipt.CreatedAt, receipt.UpdatedAt, backdated)
+	}
+
+	// 未来の日時は受け付けない
+	_, err = uc.ProcessReceiptImageWithOptions(ctx, []byte("future image"), ProcessOptions{CreatedAt: now.Add(time.Hour)})
+	if !errors.Is(err, ErrInvalidReceipt) {
+		t.Errorf("ProcessReceiptImageWithOptions() error = %v, want ErrInvalidReceipt", err)
+	}
+}
+
+func TestReceiptUseCase_ProcessReceiptImage_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		wantText string
+	}{
+		{
+			name:     "店名が空",
+			json:     `{"store_name":"","total_amount":1000,"items":[{"name":"Item","quantity":1,"price":1000}]}`,
+			wantText: "store_name is required",
+		},
+		{
+			name:     "購入の消費税額が負",
+			json:     `{"store_name":"Test","total_amount":1000,"tax_amount":-100,"items":[{"name":"Item","quantity":1,"price":1000}]}`,
+			wantText: "tax_amount must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := false
+			mockAI := &MockAIRepository{
+				RecognizeReceiptFunc: func(imageData []byte) (*domain.AIResult, error) {
+					return domain.NewAIResult("", tt.json, 10, 5, "test"), nil
+				},
+			}
+			mockReceipt := &MockReceiptRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+					return nil, errors.New("receipt not found")
+				},
+				CreateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+					created = true
+					return nil
+				},
+			}
+			uc := NewReceiptUseCase(mockAI, mockReceipt, nil)
+
+			_, err := uc.ProcessReceiptImage(context.Background(), []byte("image"))
+			if !errors.Is(err, ErrInvalidReceipt) || !strings.Contains(err.Error(), tt.wantText) {
+				t.Errorf("ProcessReceiptImage() error = %v, want %v containing %q", err, ErrInvalidReceipt, tt.wantText)
+			}
+			if created {
+				t.Error("Expected invalid receipt not to be created")
+			}
+		})
+	}
+}
+
+func TestReceiptUseCase_ParseReceiptJSON_DefaultQuantity(t *testing.T) {
+	uc := NewReceiptUseCase(&MockAIRepository{}, &MockReceiptRepository{}, nil)
+	receipt, err := uc.parseReceiptJSON(`{"store_name":"Test","items":[{"name":"Item","price":300}]}`, "receipt-1", time.UTC)
+	if err != nil {
+		t.Fatalf("parseReceiptJSON() error = %v", err)
+	}
+	if receipt.Items[0].Quantity != 1 || receipt.TotalAmount != 300 {
+		t.Errorf("Quantity/TotalAmount = %d/%d, want 1/300", receipt.Items[0].Quantity, receipt.TotalAmount)
+	}
+}
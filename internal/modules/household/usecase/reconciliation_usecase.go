@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// ReconciliationRules 利用明細とレシートの突き合わせルール
+type ReconciliationRules struct {
+	DateWindowDays int      // 利用日と購入日のずれを許容する日数
+	PaymentMethods []string // 明細に請求が載るはずの支払い方法（空の場合はすべてのレシート）
+}
+
+// ReconciliationMatch 利用明細の行と対応するレシート
+type ReconciliationMatch struct {
+	Line         entity.StatementLine
+	Receipt      *entity.Receipt
+	DaysApart    int  // 利用日と購入日の差（日数、利用日が後の場合は正）
+	StoreMatched bool // 摘要と店名が一致した
+}
+
+// ReconciliationReport 利用明細とレシートの突き合わせ結果
+type ReconciliationReport struct {
+	Start             time.Time // 明細の期間の開始日
+	End               time.Time // 明細の期間の終了日（この日を含む）
+	Matches           []ReconciliationMatch
+	UnmatchedLines    []entity.StatementLine // レシートのない支払い
+	UnmatchedReceipts []*entity.Receipt      // 明細に請求がないレシート
+	Skipped           int                    // 返品のレシートと対応しない入金・返金など、突き合わせの対象外の行数
+}
+
+// ReconciliationUseCase 銀行・クレジットカードの利用明細とレシートを突き合わせるユースケース
+type ReconciliationUseCase struct {
+	receiptRepo repository.ReceiptRepository
+	rules       ReconciliationRules
+	currency    sharedDomain.Currency
+}
+
+// NewReconciliationUseCase 新しいReconciliationUseCaseを作成
+func NewReconciliationUseCase(receiptRepo repository.ReceiptRepository, rules ReconciliationRules) *ReconciliationUseCase {
+	return &ReconciliationUseCase{
+		receiptRepo: receiptRepo,
+		rules:       rules,
+		currency:    sharedDomain.DefaultCurrency,
+	}
+}
+
+// SetCurrency 利用明細の金額の通貨を設定する
+func (uc *ReconciliationUseCase) SetCurrency(currency sharedDomain.Currency) {
+	uc.currency = currency
+}
+
+// Currency 利用明細の金額の通貨
+func (uc *ReconciliationUseCase) Currency() sharedDomain.Currency {
+	return uc.currency
+}
+
+// reconciliationCandidate 突き合わせの候補（明細の行とレシートの組）
+type reconciliationCandidate struct {
+	line         int
+	receipt      int
+	daysApart    int
+	storeMatched bool
+}
+
+// Reconcile 利用明細の支払いとレシートを金額・日付・店名で突き合わせる
+// 返金の行は合計金額が負の返品のレシートと突き合わせ、対応する返品のレシートがない返金は入金と区別できないため対象外とする。
+// 金額が一致し、利用日と購入日のずれが許容日数以内のレシートを候補とし、店名が一致する組・日付の近い組から順に1対1で対応付ける。
+// paymentMethodsを指定した場合はルールの支払い方法の代わりに使い、明細に請求がないレシートはその支払い方法のものに限る
+func (uc *ReconciliationUseCase) Reconcile(ctx context.Context, lines []entity.StatementLine, paymentMethods []string) (*ReconciliationReport, error) {
+	if len(paymentMethods) == 0 {
+		paymentMethods = uc.rules.PaymentMethods
+	}
+
+	report := &ReconciliationReport{
+		Matches:           []ReconciliationMatch{},
+		UnmatchedLines:    []entity.StatementLine{},
+		UnmatchedReceipts: []*entity.Receipt{},
+	}
+
+	charges := make([]entity.StatementLine, 0, len(lines))
+	for _, line := range lines {
+		if !line.IsCharge() && !line.IsRefund() {
+			report.Skipped++
+			continue
+		}
+		charges = append(charges, line)
+		if report.Start.IsZero() || line.Date.Before(report.Start) {
+			report.Start = line.Date
+		}
+		if line.Date.After(report.End) {
+			report.End = line.Date
+		}
+	}
+	if len(charges) == 0 {
+		return report, nil
+	}
+
+	// カードの利用日は購入日より後になることが多いため、期間の前後に許容日数を加えてレシートを取得する
+	// 明細の日付は暦日のみのため、コンテキストのタイムゾーンの暦日としてレシートの購入日と比較する
+	loc := sharedDomain.LocationFromContext(ctx)
+	window := uc.rules.DateWindowDays
+	start := time.Date(report.Start.Year(), report.Start.Month(), report.Start.Day()-window, 0, 0, 0, 0, loc)
+	end := time.Date(report.End.Year(), report.End.Month(), report.End.Day()+window+1, 0, 0, 0, 0, loc).Add(-time.Nanosecond)
+	receipts, err := uc.receiptRepo.FindByDateRange(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receipts: %w", err)
+	}
+
+	var candidates []reconciliationCandidate
+	for i, line := range charges {
+		description := entity.NormalizeItemName(line.Description)
+		for j, receipt := range receipts {
+			if receipt.TotalAmount != line.Amount {
+				continue
+			}
+			daysApart := dayNumber(line.Date) - dayNumber(receipt.PurchaseDate.In(loc))
+			if daysApart < -window || daysApart > window {
+				continue
+			}
+			candidates = append(candidates, reconciliationCandidate{
+				line:         i,
+				receipt:      j,
+				daysApart:    daysApart,
+				storeMatched: storeNameMatches(description, entity.NormalizeItemName(receipt.StoreName)),
+			})
+		}
+	}
+
+	sort.SliceStable(candidates, func(a, b int) bool {
+		ca, cb := candidates[a], candidates[b]
+		if ca.storeMatched != cb.storeMatched {
+			return ca.storeMatched
+		}
+		if da, db := absInt(ca.daysApart), absInt(cb.daysApart); da != db {
+			return da < db
+		}
+		return ca.line < cb.line
+	})
+
+	matchedLines := make([]bool, len(charges))
+	matchedReceipts := make([]bool, len(receipts))
+	for _, c := range candidates {
+		if matchedLines[c.line] || matchedReceipts[c.receipt] {
+			continue
+		}
+		matchedLines[c.line] = true
+		matchedReceipts[c.receipt] = true
+		report.Matches = append(report.Matches, ReconciliationMatch{
+			Line:         charges[c.line],
+			Receipt:      receipts[c.receipt],
+			DaysApart:    c.daysApart,
+			StoreMatched: c.storeMatched,
+		})
+	}
+	sort.SliceStable(report.Matches, func(a, b int) bool {
+		return report.Matches[a].Line.Row < report.Matches[b].Line.Row
+	})
+
+	for i, line := range charges {
+		switch {
+		case matchedLines[i]:
+		case line.IsRefund():
+			report.Skipped++
+		default:
+			report.UnmatchedLines = append(report.UnmatchedLines, line)
+		}
+	}
+
+	// 明細の期間外のレシートは前後の明細に請求が載るため、期間内のレシートのみ請求なしとする
+	first, last := dayNumber(report.Start), dayNumber(report.End)
+	for j, receipt := range receipts {
+		day := dayNumber(receipt.PurchaseDate.In(loc))
+		if matchedReceipts[j] || day < first || day > last {
+			continue
+		}
+		if len(paymentMethods) > 0 && !slices.Contains(paymentMethods, receipt.PaymentMethod) {
+			continue
+		}
+		report.UnmatchedReceipts = append(report.UnmatchedReceipts, receipt)
+	}
+	sort.SliceStable(report.UnmatchedReceipts, func(a, b int) bool {
+		return report.UnmatchedReceipts[a].PurchaseDate.Before(report.UnmatchedReceipts[b].PurchaseDate)
+	})
+
+	return report, nil
+}
+
+// storeNameMatches 正規化した摘要と店名の一方が他方を含むかチェック（カード明細の摘要は店名を省略・付加していることがある）
+func storeNameMatches(description, storeName string) bool {
+	if description == "" || storeName == "" {
+		return false
+	}
+	return strings.Contains(description, storeName) || strings.Contains(storeName, description)
+}
+
+// dayNumber 日付の通し番号（時刻とタイムゾーンを無視して暦日で比較する）
+func dayNumber(t time.Time) int {
+	return int(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400)
+}
+
+// absInt 絶対値
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+func TestReconciliationUseCase_Reconcile(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2025, 6, d, 12, 0, 0, 0, time.UTC)
+	}
+	receipts := []*entity.Receipt{
+		{ID: "aeon", StoreName: "イオンモール", PurchaseDate: day(2), TotalAmount: 3280, PaymentMethod: "クレジットカード"},
+		// 同じ金額で店名が異なる（店名の一致するレシートを優先する）
+		{ID: "other", StoreName: "ドラッグストア", PurchaseDate: day(3), TotalAmount: 3280, PaymentMethod: "クレジットカード"},
+		{ID: "amazon", StoreName: "Amazon", PurchaseDate: day(8), TotalAmount: 1500, PaymentMethod: "クレジットカード"},
+		// 許容日数を超えて離れている
+		{ID: "late", StoreName: "書店", PurchaseDate: day(20), TotalAmount: 990, PaymentMethod: "クレジットカード"},
+		// 返品のレシートは返金の行と突き合わせる
+		{ID: "refund", StoreName: "家電量販店", PurchaseDate: day(15), TotalAmount: -2000, Type: entity.ReceiptTypeRefund, PaymentMethod: "クレジットカード"},
+		{ID: "cash", StoreName: "八百屋", PurchaseDate: day(5), TotalAmount: 400, PaymentMethod: "現金"},
+		// 明細の期間外（前後の明細に載る）
+		{ID: "outside", StoreName: "コンビニ", PurchaseDate: day(28), TotalAmount: 200, PaymentMethod: "クレジットカード"},
+	}
+	lines := []entity.StatementLine{
+		{Row: 2, Date: day(3), Description: "ｲｵﾝ ﾓｰﾙ", Amount: 3280},
+		{Row: 3, Date: day(10), Description: "AMAZON.CO.JP", Amount: 1500},
+		{Row: 4, Date: day(12), Description: "ﾃﾞﾝｷﾀﾞｲ", Amount: 990},
+		{Row: 5, Date: day(14), Description: "ﾍﾝﾋﾟﾝ", Amount: -500},
+		{Row: 6, Date: day(25), Description: "ｼｮﾃﾝ", Amount: 990},
+		{Row: 7, Date: day(16), Description: "ｶﾃﾞﾝﾘｮｳﾊﾝﾃﾝ ﾍﾝﾋﾟﾝ", Amount: -2000},
+	}
+
+	tests := []struct {
+		name           string
+		paymentMethods []string
+		repoErr        error
+		wantMatches    map[int]string // 明細の行番号 → レシートID
+		wantLines      []int
+		wantReceipts   []string
+		wantErr        bool
+	}{
+		{
+			name:         "正常系: 金額・日付・店名で突き合わせる",
+			wantMatches:  map[int]string{2: "aeon", 3: "amazon", 6: "late", 7: "refund"},
+			wantLines:    []int{4},
+			wantReceipts: []string{"other"},
+		},
+		{
+			name:           "正常系: 支払い方法を指定すると請求なしのレシートを絞り込む",
+			paymentMethods: []string{"現金"},
+			wantMatches:    map[int]string{2: "aeon", 3: "amazon", 6: "late", 7: "refund"},
+			wantLines:      []int{4},
+			wantReceipts:   []string{"cash"},
+		},
+		{
+			name:    "異常系: レシートの取得に失敗",
+			repoErr: errors.New("db error"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotStart, gotEnd time.Time
+			repo := &MockReceiptRepository{
+				FindByDateRangeFunc: func(ctx context.Context, start, end time.Time) ([]*entity.Receipt, error) {
+					gotStart, gotEnd = start, end
+					return receipts, tt.repoErr
+				},
+			}
+			uc := NewReconciliationUseCase(repo, ReconciliationRules{
+				DateWindowDays: 5,
+				PaymentMethods: []string{"クレジットカード"},
+			})
+
+			report, err := uc.Reconcile(sharedDomain.WithLocation(context.Background(), time.UTC), lines, tt.paymentMethods)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Reconcile() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			// 明細の期間の前後に許容日数を加えた暦日で取得する
+			if !gotStart.Equal(time.Date(2025, 5, 29, 0, 0, 0, 0, time.UTC)) || !gotEnd.Equal(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond)) {
+				t.Errorf("FindByDateRange(%v, %v)", gotStart, gotEnd)
+			}
+			if !report.Start.Equal(day(3)) || !report.End.Equal(day(25)) || report.Skipped != 1 {
+				t.Errorf("report = start %v, end %v, skipped %d", report.Start, report.End, report.Skipped)
+			}
+
+			if len(report.Matches) != len(tt.wantMatches) {
+				t.Fatalf("Matches = %d, want %d", len(report.Matches), len(tt.wantMatches))
+			}
+			for _, m := range report.Matches {
+				if tt.wantMatches[m.Line.Row] != m.Receipt.ID {
+					t.Errorf("row %d matched %s, want %s", m.Line.Row, m.Receipt.ID, tt.wantMatches[m.Line.Row])
+				}
+			}
+			if m := report.Matches[0]; !m.StoreMatched || m.DaysApart != 1 {
+				t.Errorf("Matches[0] = %+v, want store matched and 1 day apart", m)
+			}
+
+			if len(report.UnmatchedLines) != len(tt.wantLines) {
+				t.Fatalf("UnmatchedLines = %+v, want rows %v", report.UnmatchedLines, tt.wantLines)
+			}
+			for i, row := range tt.wantLines {
+				if report.UnmatchedLines[i].Row != row {
+					t.Errorf("UnmatchedLines[%d].Row = %d, want %d", i, report.UnmatchedLines[i].Row, row)
+				}
+			}
+
+			if len(report.UnmatchedReceipts) != len(tt.wantReceipts) {
+				t.Fatalf("UnmatchedReceipts = %d, want %v", len(report.UnmatchedReceipts), tt.wantReceipts)
+			}
+			for i, id := range tt.wantReceipts {
+				if report.UnmatchedReceipts[i].ID != id {
+					t.Errorf("UnmatchedReceipts[%d].ID = %s, want %s", i, report.UnmatchedReceipts[i].ID, id)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+)
+
+var (
+	// ErrSplitNotFound レシートの割り勘が登録されていない
+	ErrSplitNotFound = errors.New("receipt split not found")
+	// ErrParticipantNotFound 割り勘に指定した参加者がいない
+	ErrParticipantNotFound = errors.New("split participant not found")
+)
+
+// SplitUseCase レシートの割り勘と精算状態を管理するユースケース
+type SplitUseCase struct {
+	receiptRepo repository.ReceiptRepository
+	splitRepo   repository.SplitRepository
+	now         func() time.Time
+}
+
+// NewSplitUseCase 新しいSplitUseCaseを作成
+func NewSplitUseCase(receiptRepo repository.ReceiptRepository, splitRepo repository.SplitRepository) *SplitUseCase {
+	return &SplitUseCase{
+		receiptRepo: receiptRepo,
+		splitRepo:   splitRepo,
+		now:         time.Now,
+	}
+}
+
+// SplitReceipt 明細の割り当てから参加者ごとの負担額を計算して保存する
+// 割り勘をやり直した場合も、負担額が変わらない参加者の精算状態は引き継ぐ
+func (uc *SplitUseCase) SplitReceipt(ctx context.Context, receiptID, payer string, assignments []entity.SplitAssignment) (*entity.Split, error) {
+	receipt, err := uc.receiptRepo.FindByID(ctx, receiptID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrReceiptNotFound, err)
+	}
+
+	split, err := entity.NewSplit(receipt, payer, assignments)
+	if err != nil {
+		return nil, err
+	}
+
+	now := uc.now()
+	split.CreatedAt = now
+	split.UpdatedAt = now
+	for i := range split.Participants {
+		if split.Participants[i].Settled {
+			split.Participants[i].SettledAt = &now
+		}
+	}
+
+	if previous, err := uc.splitRepo.FindByReceiptID(ctx, receiptID); err == nil && previous != nil {
+		split.CreatedAt = previous.CreatedAt
+		for i := range split.Participants {
+			p := &split.Participants[i]
+			if prev, ok := previous.Participant(p.Name); ok && prev.Settled && !p.Settled && prev.Total == p.Total {
+				p.Settled = true
+				p.SettledAt = prev.SettledAt
+			}
+		}
+	}
+
+	if err := uc.splitRepo.Save(ctx, split); err != nil {
+		return nil, fmt.Errorf("failed to save receipt split: %w", err)
+	}
+	return split, nil
+}
+
+// GetSplit レシートの割り勘を取得
+func (uc *SplitUseCase) GetSplit(ctx context.Context, receiptID string) (*entity.Split, error) {
+	split, err := uc.splitRepo.FindByReceiptID(ctx, receiptID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSplitNotFound, err)
+	}
+	return split, nil
+}
+
+// ListUnsettled 未精算の参加者が残る割り勘を取得
+func (uc *SplitUseCase) ListUnsettled(ctx context.Context, limit, offset int) ([]*entity.Split, error) {
+	return uc.splitRepo.FindUnsettled(ctx, limit, offset)
+}
+
+// SetSettled 参加者の精算状態を変更する
+// 立て替えた参加者は常に精算済みのため、未精算には戻せない
+func (uc *SplitUseCase) SetSettled(ctx context.Context, receiptID, participant string, settled bool) (*entity.Split, error) {
+	split, err := uc.GetSplit(ctx, receiptID)
+	if err != nil {
+		return nil, err
+	}
+
+	p, ok := split.Participant(participant)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrParticipantNotFound, participant)
+	}
+	if !settled && p.Name == split.Payer {
+		return nil, fmt.Errorf("%w: payer cannot be unsettled", entity.ErrInvalidSplit)
+	}
+	if p.Settled == settled {
+		return split, nil
+	}
+
+	now := uc.now()
+	p.Settled = settled
+	p.SettledAt = nil
+	if settled {
+		p.SettledAt = &now
+	}
+	split.UpdatedAt = now
+
+	if err := uc.splitRepo.Save(ctx, split); err != nil {
+		return nil, fmt.Errorf("failed to save receipt split: %w", err)
+	}
+	return split, nil
+}
+
+// DeleteSplit レシートの割り勘を削除
+func (uc *SplitUseCase) DeleteSplit(ctx context.Context, receiptID string) error {
+	if _, err := uc.GetSplit(ctx, receiptID); err != nil {
+		return err
+	}
+	return uc.splitRepo.DeleteByReceiptID(ctx, receiptID)
+}
@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+)
+
+// memorySplitRepository 割り勘をメモリに保存する
+type memorySplitRepository struct {
+	splits  map[string]*entity.Split
+	saveErr error
+}
+
+func newMemorySplitRepository() *memorySplitRepository {
+	return &memorySplitRepository{splits: make(map[string]*entity.Split)}
+}
+
+func (r *memorySplitRepository) Save(ctx context.Context, split *entity.Split) error {
+	if r.saveErr != nil {
+		return r.saveErr
+	}
+	saved := *split
+	saved.Participants = append([]entity.SplitParticipant(nil), split.Participants...)
+	r.splits[split.ReceiptID] = &saved
+	return nil
+}
+
+func (r *memorySplitRepository) FindByReceiptID(ctx context.Context, receiptID string) (*entity.Split, error) {
+	split, ok := r.splits[receiptID]
+	if !ok {
+		return nil, fmt.Errorf("receipt split not found: %s", receiptID)
+	}
+	found := *split
+	found.Participants = append([]entity.SplitParticipant(nil), split.Participants...)
+	return &found, nil
+}
+
+func (r *memorySplitRepository) FindUnsettled(ctx context.Context, limit, offset int) ([]*entity.Split, error) {
+	splits := []*entity.Split{}
+	for _, split := range r.splits {
+		if !split.IsSettled() {
+			splits = append(splits, split)
+		}
+	}
+	return splits, nil
+}
+
+func (r *memorySplitRepository) DeleteByReceiptID(ctx context.Context, receiptID string) error {
+	delete(r.splits, receiptID)
+	return nil
+}
+
+func TestSplitUseCase(t *testing.T) {
+	now := time.Date(2025, time.June, 10, 15, 0, 0, 0, time.Local)
+	receipt := &entity.Receipt{
+		ID:          "receipt-1",
+		TotalAmount: 1100,
+		Items: []entity.ReceiptItem{
+			{ID: "item-1", Name: "ビール", Quantity: 2, Price: 300},
+			{ID: "item-2", Name: "サラダ", Quantity: 1, Price: 400},
+		},
+	}
+	mockReceipt := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			if id != receipt.ID {
+				return nil, fmt.Errorf("receipt not found: %s", id)
+			}
+			return receipt, nil
+		},
+	}
+	assignments := []entity.SplitAssignment{
+		{Participant: "A", ItemIDs: []string{"item-1"}},
+		{Participant: "B", ItemIDs: []string{"item-2"}},
+	}
+
+	t.Run("正常系: 割り勘を保存し精算状態を変更", func(t *testing.T) {
+		splitRepo := newMemorySplitRepository()
+		uc := NewSplitUseCase(mockReceipt, splitRepo)
+		uc.now = func() time.Time { return now }
+
+		split, err := uc.SplitReceipt(context.Background(), receipt.ID, "A", assignments)
+		if err != nil {
+			t.Fatalf("SplitReceipt() error = %v", err)
+		}
+		if split.Participants[0].Total != 660 || split.Participants[1].Total != 440 {
+			t.Errorf("totals = %d, %d, want 660, 440", split.Participants[0].Total, split.Participants[1].Total)
+		}
+		if !split.Participants[0].Settled || !split.Participants[0].SettledAt.Equal(now) || split.Participants[1].Settled {
+			t.Errorf("settlement = %+v", split.Participants)
+		}
+
+		split, err = uc.SetSettled(context.Background(), receipt.ID, "B", true)
+		if err != nil {
+			t.Fatalf("SetSettled() error = %v", err)
+		}
+		if !split.IsSettled() {
+			t.Error("IsSettled() = false after settling B")
+		}
+		if unsettled, _ := uc.ListUnsettled(context.Background(), 20, 0); len(unsettled) != 0 {
+			t.Errorf("ListUnsettled() = %d splits, want 0", len(unsettled))
+		}
+	})
+
+	t.Run("正常系: やり直しても負担額が同じ参加者の精算状態は引き継ぐ", func(t *testing.T) {
+		splitRepo := newMemorySplitRepository()
+		uc := NewSplitUseCase(mockReceipt, splitRepo)
+		uc.now = func() time.Time { return now }
+
+		if _, err := uc.SplitReceipt(context.Background(), receipt.ID, "", assignments); err != nil {
+			t.Fatalf("SplitReceipt() error = %v", err)
+		}
+		if _, err := uc.SetSettled(context.Background(), receipt.ID, "A", true); err != nil {
+			t.Fatalf("SetSettled() error = %v", err)
+		}
+		if _, err := uc.SetSettled(context.Background(), receipt.ID, "B", true); err != nil {
+			t.Fatalf("SetSettled() error = %v", err)
+		}
+
+		uc.now = func() time.Time { return now.Add(time.Hour) }
+		split, err := uc.SplitReceipt(context.Background(), receipt.ID, "", []entity.SplitAssignment{
+			{Participant: "A", ItemIDs: []string{"item-1"}},
+			{Participant: "B", ItemIDs: []string{"item-2"}},
+			{Participant: "C", ItemIDs: []string{"item-2"}},
+		})
+		if err != nil {
+			t.Fatalf("SplitReceipt() error = %v", err)
+		}
+		if p, _ := split.Participant("A"); !p.Settled || !p.SettledAt.Equal(now) {
+			t.Errorf("A = %+v, want settled at %v", p, now)
+		}
+		if p, _ := split.Participant("B"); p.Settled {
+			t.Errorf("B = %+v, want unsettled because total changed", p)
+		}
+		if !split.CreatedAt.Equal(now) || !split.UpdatedAt.Equal(now.Add(time.Hour)) {
+			t.Errorf("CreatedAt = %v, UpdatedAt = %v", split.CreatedAt, split.UpdatedAt)
+		}
+	})
+
+	t.Run("正常系: 割り勘を削除", func(t *testing.T) {
+		splitRepo := newMemorySplitRepository()
+		uc := NewSplitUseCase(mockReceipt, splitRepo)
+
+		if _, err := uc.SplitReceipt(context.Background(), receipt.ID, "", assignments); err != nil {
+			t.Fatalf("SplitReceipt() error = %v", err)
+		}
+		if err := uc.DeleteSplit(context.Background(), receipt.ID); err != nil {
+			t.Fatalf("DeleteSplit() error = %v", err)
+		}
+		if _, err := uc.GetSplit(context.Background(), receipt.ID); !errors.Is(err, ErrSplitNotFound) {
+			t.Errorf("GetSplit() error = %v, want ErrSplitNotFound", err)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		run     func(uc *SplitUseCase) error
+		wantErr error
+	}{
+		{
+			name: "異常系: レシートが存在しない",
+			run: func(uc *SplitUseCase) error {
+				_, err := uc.SplitReceipt(context.Background(), "unknown", "", assignments)
+				return err
+			},
+			wantErr: ErrReceiptNotFound,
+		},
+		{
+			name: "異常系: 存在しない明細",
+			run: func(uc *SplitUseCase) error {
+				_, err := uc.SplitReceipt(context.Background(), receipt.ID, "", []entity.SplitAssignment{{Participant: "A", ItemIDs: []string{"unknown"}}})
+				return err
+			},
+			wantErr: entity.ErrInvalidSplit,
+		},
+		{
+			name: "異常系: 割り勘が未登録",
+			run: func(uc *SplitUseCase) error {
+				_, err := uc.SetSettled(context.Background(), "unknown", "A", true)
+				return err
+			},
+			wantErr: ErrSplitNotFound,
+		},
+		{
+			name: "異常系: 存在しない参加者",
+			run: func(uc *SplitUseCase) error {
+				if _, err := uc.SplitReceipt(context.Background(), receipt.ID, "A", assignments); err != nil {
+					return err
+				}
+				_, err := uc.SetSettled(context.Background(), receipt.ID, "Z", true)
+				return err
+			},
+			wantErr: ErrParticipantNotFound,
+		},
+		{
+			name: "異常系: 立て替えた人は未精算に戻せない",
+			run: func(uc *SplitUseCase) error {
+				if _, err := uc.SplitReceipt(context.Background(), receipt.ID, "A", assignments); err != nil {
+					return err
+				}
+				_, err := uc.SetSettled(context.Background(), receipt.ID, "A", false)
+				return err
+			},
+			wantErr: entity.ErrInvalidSplit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewSplitUseCase(mockReceipt, newMemorySplitRepository())
+			if err := tt.run(uc); !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/transform"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// ErrInvalidStatement 利用明細のCSVを読み取れない
+var ErrInvalidStatement = errors.New("invalid statement")
+
+// statementHeaderRows ヘッダー行を探す行数（カード会社のCSVは先頭に会員情報などの行がある）
+const statementHeaderRows = 10
+
+// 利用明細のCSVの列名
+// 銀行・カード会社・Plaidのエクスポートで使われる列名を受け付ける
+var (
+	statementDateColumns = []string{
+		"日付", "利用日", "ご利用日", "取引日", "お取引日", "年月日", "利用年月日", "date", "transactiondate", "posteddate",
+	}
+	statementDescriptionColumns = []string{
+		"摘要", "内容", "利用店名", "ご利用店名", "利用先", "ご利用先", "取引内容", "店名", "お取引内容", "description", "name", "merchant", "merchantname", "payee",
+	}
+	statementAmountColumns = []string{
+		"金額", "利用金額", "ご利用金額", "支払金額", "お支払金額", "出金額", "出金", "お引出し", "お支払い", "amount", "withdrawal", "debit",
+	}
+	statementCreditColumns = []string{
+		"入金額", "入金", "お預入れ", "お預かり", "deposit", "credit",
+	}
+)
+
+// statementDateLayouts 利用日として受け付ける日付の書式
+var statementDateLayouts = []string{
+	"2006/01/02",
+	"2006/1/2",
+	"2006-01-02",
+	"2006-1-2",
+	"2006.01.02",
+	"2006.1.2",
+	"20060102",
+	"2006年1月2日",
+}
+
+// statementColumns ヘッダー行から求めた列の位置（-1は列なし）
+type statementColumns struct {
+	date, description, amount, credit int
+}
+
+// ParseStatementCSV 銀行・クレジットカードの利用明細のCSVを読み取る
+// 文字コードはUTF-8（BOM付きを含む）とShift_JISに対応し、先頭の数行から日付と金額の列を含むヘッダー行を探す。
+// 金額は支払いを正とし、入金の列がある場合は入金を負の金額として読み取る（Plaidと同じく支払いが正）。
+// 金額はcurrencyの最小単位の整数に変換する
+func ParseStatementCSV(r io.Reader, currency sharedDomain.Currency) ([]entity.StatementLine, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidStatement, err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(data) {
+		if data, _, err = transform.Bytes(japanese.ShiftJIS.NewDecoder(), data); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidStatement, err)
+		}
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1 // 前置きの行とヘッダー以降で列数が異なる
+	reader.LazyQuotes = true
+	var records [][]string
+	var rows []int // 各レコードのCSV上の行番号（空行は読み飛ばされるため位置から求めない）
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidStatement, err)
+		}
+		row, _ := reader.FieldPos(0)
+		records = append(records, record)
+		rows = append(rows, row)
+	}
+
+	header, columns, ok := findStatementHeader(records)
+	if !ok {
+		return nil, fmt.Errorf("%w: date and amount columns not found", ErrInvalidStatement)
+	}
+
+	lines := []entity.StatementLine{}
+	for i := header + 1; i < len(records); i++ {
+		line, ok, err := parseStatementRecord(records[i], columns, currency)
+		if err != nil {
+			return nil, fmt.Errorf("%w: row %d: %v", ErrInvalidStatement, rows[i], err)
+		}
+		if !ok {
+			continue
+		}
+		line.Row = rows[i]
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// findStatementHeader 日付と金額の列を含む最初の行をヘッダー行として探す
+func findStatementHeader(records [][]string) (int, statementColumns, bool) {
+	for i := 0; i < len(records) && i < statementHeaderRows; i++ {
+		columns := statementColumns{date: -1, description: -1, amount: -1, credit: -1}
+		for j, name := range records[i] {
+			name = normalizeStatementColumn(name)
+			switch {
+			case columns.date < 0 && matchesStatementColumn(statementDateColumns, name):
+				columns.date = j
+			case columns.description < 0 && matchesStatementColumn(statementDescriptionColumns, name):
+				columns.description = j
+			case columns.amount < 0 && matchesStatementColumn(statementAmountColumns, name):
+				columns.amount = j
+			case columns.credit < 0 && matchesStatementColumn(statementCreditColumns, name):
+				columns.credit = j
+			}
+		}
+		if columns.date >= 0 && columns.amount >= 0 {
+			return i, columns, true
+		}
+	}
+	return 0, statementColumns{}, false
+}
+
+// normalizeStatementColumn 列名を比較用に正規化する（"利用金額(円)" → "利用金額"、"Transaction Date" → "transactiondate"）
+func normalizeStatementColumn(name string) string {
+	name = entity.NormalizeItemName(name)
+	if i := strings.IndexAny(name, "(["); i > 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// matchesStatementColumn 正規化した列名が候補のいずれかと一致するかチェック
+func matchesStatementColumn(candidates []string, name string) bool {
+	for _, candidate := range candidates {
+		if normalizeStatementColumn(candidate) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// parseStatementRecord CSVの1行を利用明細として読み取る
+// 日付が空の行（合計行など）と金額が空の行は読み飛ばす（ok=false）
+func parseStatementRecord(record []string, columns statementColumns, currency sharedDomain.Currency) (entity.StatementLine, bool, error) {
+	field := func(i int) string {
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	rawDate := field(columns.date)
+	if rawDate == "" {
+		return entity.StatementLine{}, false, nil
+	}
+	date, err := parseStatementDate(rawDate)
+	if err != nil {
+		return entity.StatementLine{}, false, err
+	}
+
+	amount, ok, err := parseStatementAmount(field(columns.amount), currency)
+	if err != nil {
+		return entity.StatementLine{}, false, err
+	}
+	if !ok {
+		credit, ok, err := parseStatementAmount(field(columns.credit), currency)
+		if err != nil || !ok {
+			return entity.StatementLine{}, false, err
+		}
+		amount = -credit
+	}
+
+	return entity.StatementLine{
+		Date:        date,
+		Description: field(columns.description),
+		Amount:      amount,
+	}, true, nil
+}
+
+// parseStatementDate 利用日を読み取る
+func parseStatementDate(value string) (time.Time, error) {
+	for _, layout := range statementDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date: %q", value)
+}
+
+// parseStatementAmount 金額を通貨の最小単位で読み取る（"¥1,234"、"1234.00"、"(500)" などに対応、空の場合はok=false）
+func parseStatementAmount(value string, currency sharedDomain.Currency) (int64, bool, error) {
+	value = strings.NewReplacer(",", "", "¥", "", "￥", "", "円", "", "$", "", " ", "").Replace(value)
+	if symbol := strings.TrimSpace(currency.Symbol); symbol != "" {
+		value = strings.ReplaceAll(value, symbol, "")
+	}
+	if value == "" || value == "-" {
+		return 0, false, nil
+	}
+
+	negative := false
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		negative = true
+		value = value[1 : len(value)-1]
+	}
+
+	amount, err := currency.Parse(value)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid amount: %q", value)
+	}
+	if negative {
+		amount = -amount
+	}
+	return amount, true, nil
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/transform"
+
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+func TestParseStatementCSV(t *testing.T) {
+	shiftJIS := func(s string) string {
+		b, _, err := transform.Bytes(japanese.ShiftJIS.NewEncoder(), []byte(s))
+		if err != nil {
+			t.Fatalf("encode Shift_JIS: %v", err)
+		}
+		return string(b)
+	}
+
+	type line struct {
+		row         int
+		date        string
+		description string
+		amount      int64
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    []line
+		wantErr bool
+	}{
+		{
+			name:  "正常系: カード会社の明細（前置きの行と合計行を読み飛ばす）",
+			input: "会員名,山田太郎\n\nご利用日,ご利用店名,ご利用金額(円),支払区分\n2025/06/03,ｲｵﾝ ﾓｰﾙ,\"3,280\",1回払い\n2025/6/10,ｱﾏｿﾞﾝ,-500,返品\n,合計,2780,\n",
+			want: []line{
+				{row: 4, date: "2025-06-03", description: "ｲｵﾝ ﾓｰﾙ", amount: 3280},
+				{row: 5, date: "2025-06-10", description: "ｱﾏｿﾞﾝ", amount: -500},
+			},
+		},
+		{
+			name:  "正常系: 銀行の明細（Shift_JIS、出金と入金の列）",
+			input: shiftJIS("取引日,摘要,お引出し,お預入れ,残高\n20250605,カード　ＡＢＣ,\"1,000\",,9000\n20250625,給与,,300000,309000\n"),
+			want: []line{
+				{row: 2, date: "2025-06-05", description: "カード　ＡＢＣ", amount: 1000},
+				{row: 3, date: "2025-06-25", description: "給与", amount: -300000},
+			},
+		},
+		{
+			name:  "正常系: Plaidのエクスポート（BOM付き、小数の金額）",
+			input: "\xef\xbb\xbfDate,Name,Amount\n2025-06-07,Starbucks,450.00\n2025-06-08,Refund,(120.40)\n",
+			want: []line{
+				{row: 2, date: "2025-06-07", description: "Starbucks", amount: 450},
+				{row: 3, date: "2025-06-08", description: "Refund", amount: -120},
+			},
+		},
+		{
+			name:    "異常系: 日付と金額の列がない",
+			input:   "name,memo\nfoo,bar\n",
+			wantErr: true,
+		},
+		{
+			name:    "異常系: 日付を読み取れない",
+			input:   "日付,金額\n6月3日,100\n",
+			wantErr: true,
+		},
+		{
+			name:    "異常系: 金額を読み取れない",
+			input:   "日付,金額\n2025/06/03,abc\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := ParseStatementCSV(strings.NewReader(tt.input), sharedDomain.DefaultCurrency)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidStatement) {
+					t.Fatalf("ParseStatementCSV() error = %v, want ErrInvalidStatement", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseStatementCSV() error = %v", err)
+			}
+			if len(lines) != len(tt.want) {
+				t.Fatalf("ParseStatementCSV() = %+v, want %d lines", lines, len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := lines[i]
+				if got.Row != want.row || got.Date.Format(time.DateOnly) != want.date || got.Description != want.description || got.Amount != want.amount {
+					t.Errorf("lines[%d] = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
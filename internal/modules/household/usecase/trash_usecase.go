@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+)
+
+// trashPurgeBatchSize 保持期限を過ぎた項目を一度に読み込む件数
+const trashPurgeBatchSize = 100
+
+var (
+	// ErrExpenseNotFound 指定された家計簿エントリが存在しない
+	ErrExpenseNotFound = errors.New("expense not found")
+	// ErrTrashEntryNotFound ゴミ箱に指定した項目がない
+	ErrTrashEntryNotFound = errors.New("trash entry not found")
+	// ErrInvalidTrashKind ゴミ箱の項目の種類が不正
+	ErrInvalidTrashKind = errors.New("invalid trash kind")
+	// ErrRestoreConflict 同じIDのレシート・家計簿エントリが登録済みのため元に戻せない
+	ErrRestoreConflict = errors.New("an item with the same ID already exists")
+)
+
+// TrashRef ゴミ箱の項目の指定
+type TrashRef struct {
+	Kind string
+	ID   string
+}
+
+// TrashResult 一括操作の項目ごとの結果（Errがnilの場合は成功）
+type TrashResult struct {
+	TrashRef
+	Err error
+}
+
+// TrashUseCase 削除したレシート・家計簿エントリを保持期限まで残すゴミ箱のユースケース
+// 削除時点のスナップショットをゴミ箱に移して元のデータを削除するため、一覧・集計には含まれない。
+// 変更履歴・割り勘・会計サービスとの同期状態などの関連データは削除時に失われ、元に戻しても復元されない
+type TrashUseCase struct {
+	receiptUseCase *ReceiptUseCase
+	expenseRepo    repository.ExpenseRepository
+	trashRepo      repository.TrashRepository
+	retention      time.Duration
+	now            func() time.Time
+}
+
+// NewTrashUseCase 新しいTrashUseCaseを作成
+func NewTrashUseCase(receiptUseCase *ReceiptUseCase, expenseRepo repository.ExpenseRepository, trashRepo repository.TrashRepository, retention time.Duration) *TrashUseCase {
+	return &TrashUseCase{
+		receiptUseCase: receiptUseCase,
+		expenseRepo:    expenseRepo,
+		trashRepo:      trashRepo,
+		retention:      retention,
+		now:            time.Now,
+	}
+}
+
+// TrashReceipt レシートをゴミ箱に移す（元画像は完全に削除するまで残す）
+func (uc *TrashUseCase) TrashReceipt(ctx context.Context, id string) error {
+	receiptRepo := uc.receiptUseCase.receiptRepo
+	receipt, err := receiptRepo.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrReceiptNotFound, err)
+	}
+
+	if err := uc.trashRepo.Create(ctx, uc.newEntry(entity.TrashKindReceipt, id, receipt, nil)); err != nil {
+		return fmt.Errorf("failed to move receipt to trash: %w", err)
+	}
+	if err := receiptRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete receipt: %w", err)
+	}
+	return nil
+}
+
+// TrashExpense 家計簿エントリをゴミ箱に移す
+func (uc *TrashUseCase) TrashExpense(ctx context.Context, id string) error {
+	entry, err := uc.expenseRepo.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrExpenseNotFound, err)
+	}
+
+	if err := uc.trashRepo.Create(ctx, uc.newEntry(entity.TrashKindExpense, id, nil, entry)); err != nil {
+		return fmt.Errorf("failed to move expense to trash: %w", err)
+	}
+	if err := uc.expenseRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete expense: %w", err)
+	}
+	return nil
+}
+
+// ListTrash ゴミ箱の項目を削除日時の新しい順に取得（kindが空の場合はすべての種類）
+func (uc *TrashUseCase) ListTrash(ctx context.Context, kind string, limit, offset int) ([]*entity.TrashEntry, error) {
+	if kind != "" && !isTrashKind(kind) {
+		return nil, ErrInvalidTrashKind
+	}
+	return uc.trashRepo.FindAll(ctx, kind, limit, offset)
+}
+
+// RestoreEntries ゴミ箱の項目をまとめて元に戻す（失敗した項目があっても残りの項目は処理する）
+func (uc *TrashUseCase) RestoreEntries(ctx context.Context, refs []TrashRef) []TrashResult {
+	results := make([]TrashResult, 0, len(refs))
+	for _, ref := range refs {
+		results = append(results, TrashResult{TrashRef: ref, Err: uc.restore(ctx, ref)})
+	}
+	return results
+}
+
+// restore ゴミ箱の項目を元に戻す
+func (uc *TrashUseCase) restore(ctx context.Context, ref TrashRef) error {
+	entry, err := uc.find(ctx, ref)
+	if err != nil {
+		return err
+	}
+
+	switch entry.Kind {
+	case entity.TrashKindReceipt:
+		receiptRepo := uc.receiptUseCase.receiptRepo
+		if _, err := receiptRepo.FindByID(ctx, entry.ID); err == nil {
+			return ErrRestoreConflict
+		}
+		if err := receiptRepo.Create(ctx, entry.Receipt); err != nil {
+			return fmt.Errorf("failed to restore receipt: %w", err)
+		}
+	case entity.TrashKindExpense:
+		if _, err := uc.expenseRepo.FindByID(ctx, entry.ID); err == nil {
+			return ErrRestoreConflict
+		}
+		if err := uc.expenseRepo.Create(ctx, entry.Expense); err != nil {
+			return fmt.Errorf("failed to restore expense: %w", err)
+		}
+	}
+
+	if err := uc.trashRepo.Delete(ctx, entry.Kind, entry.ID); err != nil {
+		return fmt.Errorf("failed to delete trash entry: %w", err)
+	}
+	return nil
+}
+
+// PurgeEntries ゴミ箱の項目をまとめて完全に削除する（失敗した項目があっても残りの項目は処理する）
+func (uc *TrashUseCase) PurgeEntries(ctx context.Context, refs []TrashRef) []TrashResult {
+	results := make([]TrashResult, 0, len(refs))
+	for _, ref := range refs {
+		entry, err := uc.find(ctx, ref)
+		if err == nil {
+			err = uc.purge(ctx, entry)
+		}
+		results = append(results, TrashResult{TrashRef: ref, Err: err})
+	}
+	return results
+}
+
+// PurgeExpired 保持期限を過ぎた項目を完全に削除し、削除した件数を返す
+func (uc *TrashUseCase) PurgeExpired(ctx context.Context) (int, error) {
+	purged := 0
+	for {
+		entries, err := uc.trashRepo.FindExpired(ctx, uc.now(), trashPurgeBatchSize)
+		if err != nil {
+			return purged, fmt.Errorf("failed to find expired trash entries: %w", err)
+		}
+		for _, entry := range entries {
+			if err := uc.purge(ctx, entry); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+		if len(entries) < trashPurgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// purge ゴミ箱の項目を削除し、レシートの場合は元画像とキャッシュの消去を依頼
+func (uc *TrashUseCase) purge(ctx context.Context, entry *entity.TrashEntry) error {
+	if err := uc.trashRepo.Delete(ctx, entry.Kind, entry.ID); err != nil {
+		return fmt.Errorf("failed to purge trash entry: %w", err)
+	}
+
+	// 同じ画像から登録し直したレシートは同じIDになるため、その元画像は消去しない
+	if entry.Kind == entity.TrashKindReceipt {
+		if _, err := uc.receiptUseCase.receiptRepo.FindByID(ctx, entry.ID); err != nil {
+			uc.receiptUseCase.requestScrub(ctx, entry.ID, "purged")
+		}
+	}
+	return nil
+}
+
+// find ゴミ箱の項目を取得
+func (uc *TrashUseCase) find(ctx context.Context, ref TrashRef) (*entity.TrashEntry, error) {
+	if !isTrashKind(ref.Kind) {
+		return nil, ErrInvalidTrashKind
+	}
+	entry, err := uc.trashRepo.FindByID(ctx, ref.Kind, ref.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTrashEntryNotFound, err)
+	}
+	return entry, nil
+}
+
+// newEntry 削除時点のスナップショットからゴミ箱の項目を作成
+func (uc *TrashUseCase) newEntry(kind, id string, receipt *entity.Receipt, expense *entity.ExpenseEntry) *entity.TrashEntry {
+	now := uc.now()
+	return &entity.TrashEntry{
+		Kind:      kind,
+		ID:        id,
+		Receipt:   receipt,
+		Expense:   expense,
+		DeletedAt: now,
+		PurgeAt:   now.Add(uc.retention),
+	}
+}
+
+// isTrashKind ゴミ箱の項目の種類か判定
+func isTrashKind(kind string) bool {
+	return kind == entity.TrashKindReceipt || kind == entity.TrashKindExpense
+}
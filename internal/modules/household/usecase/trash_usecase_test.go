@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+)
+
+// memoryTrashRepository ゴミ箱の項目をメモリに保存する
+type memoryTrashRepository struct {
+	entries map[string]*entity.TrashEntry
+}
+
+func newMemoryTrashRepository() *memoryTrashRepository {
+	return &memoryTrashRepository{entries: make(map[string]*entity.TrashEntry)}
+}
+
+func (r *memoryTrashRepository) Create(ctx context.Context, entry *entity.TrashEntry) error {
+	r.entries[entry.Kind+"/"+entry.ID] = entry
+	return nil
+}
+
+func (r *memoryTrashRepository) FindAll(ctx context.Context, kind string, limit, offset int) ([]*entity.TrashEntry, error) {
+	entries := []*entity.TrashEntry{}
+	for _, entry := range r.entries {
+		if kind == "" || entry.Kind == kind {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].DeletedAt.After(entries[j].DeletedAt) })
+	return entries, nil
+}
+
+func (r *memoryTrashRepository) FindByID(ctx context.Context, kind, id string) (*entity.TrashEntry, error) {
+	entry, ok := r.entries[kind+"/"+id]
+	if !ok {
+		return nil, fmt.Errorf("trash entry not found: %s/%s", kind, id)
+	}
+	return entry, nil
+}
+
+func (r *memoryTrashRepository) FindExpired(ctx context.Context, before time.Time, limit int) ([]*entity.TrashEntry, error) {
+	entries := []*entity.TrashEntry{}
+	for _, entry := range r.entries {
+		if !entry.PurgeAt.After(before) && len(entries) < limit {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (r *memoryTrashRepository) Delete(ctx context.Context, kind, id string) error {
+	delete(r.entries, kind+"/"+id)
+	return nil
+}
+
+// memoryExpenseRepository 家計簿エントリをメモリに保存するモック
+type memoryExpenseRepository struct {
+	MockExpenseRepository
+	entries map[string]*entity.ExpenseEntry
+}
+
+func (r *memoryExpenseRepository) Create(ctx context.Context, entry *entity.ExpenseEntry) error {
+	r.entries[entry.ID] = entry
+	return nil
+}
+
+func (r *memoryExpenseRepository) FindByID(ctx context.Context, id string) (*entity.ExpenseEntry, error) {
+	entry, ok := r.entries[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return entry, nil
+}
+
+func (r *memoryExpenseRepository) Delete(ctx context.Context, id string) error {
+	delete(r.entries, id)
+	return nil
+}
+
+func TestTrashUseCase_TrashAndRestore(t *testing.T) {
+	now := time.Date(2025, time.June, 10, 15, 0, 0, 0, time.Local)
+	receipt := &entity.Receipt{ID: "receipt-a", StoreName: "スーパー", PurchaseDate: now, TotalAmount: 300}
+	receiptRepo, storedReceipts := newMemoryReceiptRepository(receipt)
+
+	storedExpenses := map[string]*entity.ExpenseEntry{
+		"expense-a": {ID: "expense-a", Date: now, Category: "食費", Amount: 500},
+	}
+	expenseRepo := &memoryExpenseRepository{entries: storedExpenses}
+
+	trashRepo := newMemoryTrashRepository()
+	uc := NewTrashUseCase(NewReceiptUseCase(&MockAIRepository{}, receiptRepo, nil), expenseRepo, trashRepo, 30*24*time.Hour)
+	uc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if err := uc.TrashReceipt(ctx, "receipt-a"); err != nil {
+		t.Fatalf("TrashReceipt() error = %v", err)
+	}
+	if err := uc.TrashExpense(ctx, "expense-a"); err != nil {
+		t.Fatalf("TrashExpense() error = %v", err)
+	}
+	if _, ok := storedReceipts["receipt-a"]; ok {
+		t.Error("Expected receipt to be removed from receipts")
+	}
+	if err := uc.TrashReceipt(ctx, "missing"); !errors.Is(err, ErrReceiptNotFound) {
+		t.Errorf("TrashReceipt() error = %v, want ErrReceiptNotFound", err)
+	}
+
+	entries, err := uc.ListTrash(ctx, entity.TrashKindReceipt, 20, 0)
+	if err != nil || len(entries) != 1 || entries[0].Receipt.StoreName != "スーパー" {
+		t.Fatalf("ListTrash() = %+v, error = %v", entries, err)
+	}
+	if days := entries[0].RemainingDays(now.Add(36 * time.Hour)); days != 29 {
+		t.Errorf("RemainingDays() = %d, want 29", days)
+	}
+	if _, err := uc.ListTrash(ctx, "unknown", 20, 0); !errors.Is(err, ErrInvalidTrashKind) {
+		t.Errorf("ListTrash() error = %v, want ErrInvalidTrashKind", err)
+	}
+
+	// 同じIDの家計簿エントリを登録し直した場合は元に戻せない
+	storedExpenses["expense-a"] = &entity.ExpenseEntry{ID: "expense-a", Amount: 800}
+	results := uc.RestoreEntries(ctx, []TrashRef{
+		{Kind: entity.TrashKindReceipt, ID: "receipt-a"},
+		{Kind: entity.TrashKindExpense, ID: "expense-a"},
+		{Kind: entity.TrashKindReceipt, ID: "missing"},
+	})
+	if results[0].Err != nil {
+		t.Errorf("restore receipt error = %v", results[0].Err)
+	}
+	if !errors.Is(results[1].Err, ErrRestoreConflict) {
+		t.Errorf("restore expense error = %v, want ErrRestoreConflict", results[1].Err)
+	}
+	if !errors.Is(results[2].Err, ErrTrashEntryNotFound) {
+		t.Errorf("restore missing error = %v, want ErrTrashEntryNotFound", results[2].Err)
+	}
+	if restored, ok := storedReceipts["receipt-a"]; !ok || restored.TotalAmount != 300 {
+		t.Errorf("restored receipt = %+v", restored)
+	}
+	if _, err := trashRepo.FindByID(ctx, entity.TrashKindExpense, "expense-a"); err != nil {
+		t.Error("Expected conflicting expense to stay in trash")
+	}
+
+	results = uc.PurgeEntries(ctx, []TrashRef{{Kind: entity.TrashKindExpense, ID: "expense-a"}})
+	if results[0].Err != nil {
+		t.Errorf("PurgeEntries() error = %v", results[0].Err)
+	}
+	if len(trashRepo.entries) != 0 {
+		t.Errorf("trash = %v, want empty", trashRepo.entries)
+	}
+}
+
+func TestTrashUseCase_PurgeExpired(t *testing.T) {
+	now := time.Date(2025, time.June, 10, 15, 0, 0, 0, time.Local)
+	trashRepo := newMemoryTrashRepository()
+	for i := range trashPurgeBatchSize + 5 {
+		id := fmt.Sprintf("expense-%03d", i)
+		_ = trashRepo.Create(context.Background(), &entity.TrashEntry{
+			Kind: entity.TrashKindExpense, ID: id, Expense: &entity.ExpenseEntry{ID: id},
+			DeletedAt: now.Add(-31 * 24 * time.Hour), PurgeAt: now.Add(-24 * time.Hour),
+		})
+	}
+	_ = trashRepo.Create(context.Background(), &entity.TrashEntry{
+		Kind: entity.TrashKindExpense, ID: "recent", Expense: &entity.ExpenseEntry{ID: "recent"},
+		DeletedAt: now, PurgeAt: now.Add(30 * 24 * time.Hour),
+	})
+
+	uc := NewTrashUseCase(NewReceiptUseCase(&MockAIRepository{}, &MockReceiptRepository{}, nil), &MockExpenseRepository{}, trashRepo, 30*24*time.Hour)
+	uc.now = func() time.Time { return now }
+
+	purged, err := uc.PurgeExpired(context.Background())
+	if err != nil {
+		t.Fatalf("PurgeExpired() error = %v", err)
+	}
+	if purged != trashPurgeBatchSize+5 || len(trashRepo.entries) != 1 {
+		t.Errorf("PurgeExpired() = %d, remaining = %d, want %d purged and 1 remaining", purged, len(trashRepo.entries), trashPurgeBatchSize+5)
+	}
+}
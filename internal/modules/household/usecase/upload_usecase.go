@@ -0,0 +1,339 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+const (
+	// uploadKeyPrefix 直接アップロードされた処理待ち画像の保存キーの接頭辞
+	uploadKeyPrefix = "upload-"
+	// partialKeySuffix 分割アップロード中のデータの保存キーの接尾辞
+	partialKeySuffix = ".part"
+)
+
+var (
+	// ErrInvalidUploadSignature 署名付きURLの署名が不正または期限切れ
+	ErrInvalidUploadSignature = errors.New("invalid or expired upload signature")
+	// ErrUploadNotFound アップロードが完了していない
+	ErrUploadNotFound = errors.New("upload not found")
+	// ErrUploadOffsetMismatch 分割アップロードの開始位置が受信済みのサイズと一致しない
+	ErrUploadOffsetMismatch = errors.New("upload offset mismatch")
+	// ErrUploadTooLarge アップロードされた画像が最大サイズを超えている
+	ErrUploadTooLarge = errors.New("upload too large")
+	// ErrUploadToObjectStore 画像本体はオブジェクトストレージの署名付きURLへ直接アップロードするため、APIサーバーでは受信しない
+	ErrUploadToObjectStore = errors.New("upload the image to the presigned object storage URL")
+)
+
+// PresignedUpload 署名付きアップロードURLの発行結果
+type PresignedUpload struct {
+	UploadID    string
+	UploadURL   string // 画像本体をPUTするURL（オブジェクトストレージを設定した場合はバケットの署名付きURL）
+	CompleteURL string // アップロード完了後にPOSTして処理を開始するURL
+	ExpiresAt   time.Time
+}
+
+// UploadProgress 分割アップロードの進捗
+type UploadProgress struct {
+	Offset   int64 // 受信済みのバイト数
+	Complete bool  // 全体の受信が完了し、完了通知で処理できる状態
+}
+
+// UploadUseCase 署名付きURLによる直接アップロードのユースケース
+// 大きな画像をAPIリクエストに載せず画像保存先へ直接書き込ませ、完了通知を受けてから処理する
+type UploadUseCase struct {
+	receiptUseCase *ReceiptUseCase
+	imageStorage   sharedDomain.ImageStorage
+	objectStore    sharedDomain.DirectUploadStore
+	secret         []byte
+	ttl            time.Duration
+	maxSize        int64
+	clock          sharedDomain.Clock
+}
+
+// NewUploadUseCase 新しいUploadUseCaseを作成
+func NewUploadUseCase(receiptUseCase *ReceiptUseCase, imageStorage sharedDomain.ImageStorage, secret []byte, ttl time.Duration, maxSize int64) *UploadUseCase {
+	return &UploadUseCase{
+		receiptUseCase: receiptUseCase,
+		imageStorage:   imageStorage,
+		secret:         secret,
+		ttl:            ttl,
+		maxSize:        maxSize,
+		clock:          sharedDomain.SystemClock{},
+	}
+}
+
+// SetClock 有効期限の判定に使う時計を設定する
+// 未設定の場合はシステムの時計を使う
+func (uc *UploadUseCase) SetClock(clock sharedDomain.Clock) {
+	uc.clock = clock
+}
+
+// SetObjectStore 画像本体を直接アップロードさせるオブジェクトストレージ（S3互換のバケット）を設定する
+// 設定した場合は署名付きURLをバケットへのPUTにし、APIサーバーは画像本体を受信せず完了通知で読み込む
+// 未設定の場合はAPIサーバーが画像本体を受信して画像の保存先に書き込む
+func (uc *UploadUseCase) SetObjectStore(store sharedDomain.DirectUploadStore) {
+	uc.objectStore = store
+}
+
+// MaxSize アップロードできる画像の最大サイズ（バイト）
+func (uc *UploadUseCase) MaxSize() int64 {
+	return uc.maxSize
+}
+
+// Presign アップロード用の署名付きURLを発行
+func (uc *UploadUseCase) Presign(ctx context.Context) (*PresignedUpload, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to generate upload id: %w", err)
+	}
+	uploadID := hex.EncodeToString(buf)
+	expiresAt := uc.clock.Now().Add(uc.ttl).Truncate(time.Second)
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expiresAt.Unix(), 10))
+	query.Set("signature", uc.sign(uploadID, expiresAt.Unix()))
+
+	uploadURL := fmt.Sprintf("/api/v1/uploads/%s?%s", uploadID, query.Encode())
+	if uc.objectStore != nil {
+		presigned, err := uc.objectStore.PresignPut(uploadKeyPrefix+uploadID, uc.ttl)
+		if err != nil {
+			return nil, fmt.Errorf("failed to presign upload: %w", err)
+		}
+		uploadURL = presigned
+	}
+
+	return &PresignedUpload{
+		UploadID:    uploadID,
+		UploadURL:   uploadURL,
+		CompleteURL: fmt.Sprintf("/api/v1/uploads/%s/complete?%s", uploadID, query.Encode()),
+		ExpiresAt:   expiresAt,
+	}, nil
+}
+
+// StoreUpload 署名を検証して画像を処理待ちとして保存
+func (uc *UploadUseCase) StoreUpload(ctx context.Context, uploadID, expires, signature string, data []byte) error {
+	if err := uc.VerifyUpload(uploadID, expires, signature); err != nil {
+		return err
+	}
+
+	if err := uc.imageStorage.Save(ctx, uploadKeyPrefix+uploadID, data); err != nil {
+		return fmt.Errorf("failed to store upload: %w", err)
+	}
+	return nil
+}
+
+// StoreChunk 分割アップロードの一部を受信済みのデータに追記
+// 通信が途切れた場合は UploadStatus で受信済みの位置を確認し、そこから再送する。
+// 受信済みの範囲と重なるチャンク（応答を受け取れずに再送されたもの）は重複部分を読み捨てる
+func (uc *UploadUseCase) StoreChunk(ctx context.Context, uploadID, expires, signature string, start, total int64, data []byte) (*UploadProgress, error) {
+	if err := uc.VerifyUpload(uploadID, expires, signature); err != nil {
+		return nil, err
+	}
+	if total > uc.maxSize {
+		return nil, ErrUploadTooLarge
+	}
+
+	progress, err := uc.progress(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if progress.Complete {
+		return progress, nil
+	}
+
+	partialKey := uploadKeyPrefix + uploadID + partialKeySuffix
+	var received []byte
+	if progress.Offset > 0 {
+		received, err = uc.imageStorage.Load(ctx, partialKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load partial upload: %w", err)
+		}
+	}
+
+	end := start + int64(len(data))
+	if start > progress.Offset || end < progress.Offset || end > total {
+		return progress, ErrUploadOffsetMismatch
+	}
+	received = append(received, data[progress.Offset-start:]...)
+
+	// 全体を受信したら処理待ちの画像として保存し直す
+	if int64(len(received)) == total {
+		if err := uc.imageStorage.Save(ctx, uploadKeyPrefix+uploadID, received); err != nil {
+			return nil, fmt.Errorf("failed to store upload: %w", err)
+		}
+		if err := uc.imageStorage.Delete(ctx, partialKey); err != nil {
+			return nil, fmt.Errorf("failed to delete partial upload: %w", err)
+		}
+		return &UploadProgress{Offset: total, Complete: true}, nil
+	}
+
+	if err := uc.imageStorage.Save(ctx, partialKey, received); err != nil {
+		return nil, fmt.Errorf("failed to store partial upload: %w", err)
+	}
+	return &UploadProgress{Offset: int64(len(received))}, nil
+}
+
+// UploadStatus アップロードの受信状況を取得
+func (uc *UploadUseCase) UploadStatus(ctx context.Context, uploadID, expires, signature string) (*UploadProgress, error) {
+	if _, err := uc.verify(uploadID, expires, signature); err != nil {
+		return nil, err
+	}
+	if uc.objectStore != nil {
+		return nil, ErrUploadToObjectStore
+	}
+	return uc.progress(ctx, uploadID)
+}
+
+// progress 保存済みのデータから受信状況を求める
+func (uc *UploadUseCase) progress(ctx context.Context, uploadID string) (*UploadProgress, error) {
+	data, err := uc.imageStorage.Load(ctx, uploadKeyPrefix+uploadID)
+	if err == nil {
+		return &UploadProgress{Offset: int64(len(data)), Complete: true}, nil
+	}
+	if !errors.Is(err, sharedDomain.ErrImageNotFound) {
+		return nil, fmt.Errorf("failed to load upload: %w", err)
+	}
+
+	data, err = uc.imageStorage.Load(ctx, uploadKeyPrefix+uploadID+partialKeySuffix)
+	if errors.Is(err, sharedDomain.ErrImageNotFound) {
+		return &UploadProgress{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load partial upload: %w", err)
+	}
+	return &UploadProgress{Offset: int64(len(data))}, nil
+}
+
+// CompleteUpload アップロード済みの画像からレシートを登録
+// アップロード直後に期限を迎えても完了できるよう、完了通知では有効期限を確認しない
+func (uc *UploadUseCase) CompleteUpload(ctx context.Context, uploadID, expires, signature string, opts ProcessOptions) (*entity.Receipt, error) {
+	if _, err := uc.verify(uploadID, expires, signature); err != nil {
+		return nil, err
+	}
+
+	key := uploadKeyPrefix + uploadID
+	imageData, err := uc.loadUpload(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	// データベース障害中に一時保管した場合も、レシートとErrSavePendingをそのまま返す
+	receipt, err := uc.receiptUseCase.ProcessReceiptImageWithOptions(ctx, imageData, opts)
+	if err != nil && !errors.Is(err, ErrSavePending) {
+		return nil, err
+	}
+
+	// レシートIDで画像を保存し直すため、処理待ちの画像は削除する
+	if deleteErr := uc.deleteUpload(ctx, key); deleteErr != nil {
+		return nil, fmt.Errorf("failed to delete upload: %w", deleteErr)
+	}
+	return receipt, err
+}
+
+// loadUpload アップロードされた画像を読み込む（オブジェクトストレージを設定した場合はバケットから）
+// バケットへの署名付きURLではサイズを制限できないため、最大サイズを超えた画像は削除してErrUploadTooLargeを返す
+func (uc *UploadUseCase) loadUpload(ctx context.Context, key string) ([]byte, error) {
+	if uc.objectStore == nil {
+		imageData, err := uc.imageStorage.Load(ctx, key)
+		if errors.Is(err, sharedDomain.ErrImageNotFound) {
+			return nil, ErrUploadNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load upload: %w", err)
+		}
+		return imageData, nil
+	}
+
+	imageData, err := uc.objectStore.Get(ctx, key)
+	if errors.Is(err, sharedDomain.ErrObjectNotFound) {
+		return nil, ErrUploadNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load upload: %w", err)
+	}
+	if int64(len(imageData)) > uc.maxSize {
+		if err := uc.objectStore.Delete(ctx, key); err != nil {
+			return nil, fmt.Errorf("failed to delete upload: %w", err)
+		}
+		return nil, ErrUploadTooLarge
+	}
+	return imageData, nil
+}
+
+// deleteUpload 処理を終えたアップロードの画像を削除
+func (uc *UploadUseCase) deleteUpload(ctx context.Context, key string) error {
+	if uc.objectStore != nil {
+		return uc.objectStore.Delete(ctx, key)
+	}
+	return uc.imageStorage.Delete(ctx, key)
+}
+
+// CleanupExpiredUploads 完了通知のないまま有効期限を過ぎたアップロードを削除
+func (uc *UploadUseCase) CleanupExpiredUploads(ctx context.Context) (int, error) {
+	keys, err := uc.imageStorage.ListBefore(ctx, uc.clock.Now().Add(-uc.ttl))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list uploads: %w", err)
+	}
+
+	deleted := 0
+	for _, key := range keys {
+		if !strings.HasPrefix(key, uploadKeyPrefix) {
+			continue
+		}
+		if err := uc.imageStorage.Delete(ctx, key); err != nil {
+			return deleted, fmt.Errorf("failed to delete upload: %w", err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// verify 署名を検証して有効期限を返す
+func (uc *UploadUseCase) verify(uploadID, expires, signature string) (time.Time, error) {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return time.Time{}, ErrInvalidUploadSignature
+	}
+	expected := uc.sign(uploadID, unix)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return time.Time{}, ErrInvalidUploadSignature
+	}
+	return time.Unix(unix, 0), nil
+}
+
+// VerifyUpload 署名付きURLの署名と有効期限を確認（不正・期限切れの場合はErrInvalidUploadSignature）
+// 署名のないリクエストで大きな画像を受信しないよう、リクエストボディを読み込む前に呼び出す
+// オブジェクトストレージを設定した場合、画像本体はAPIサーバーで受信しないためErrUploadToObjectStoreを返す
+func (uc *UploadUseCase) VerifyUpload(uploadID, expires, signature string) error {
+	expiresAt, err := uc.verify(uploadID, expires, signature)
+	if err != nil {
+		return err
+	}
+	if uc.objectStore != nil {
+		return ErrUploadToObjectStore
+	}
+	if uc.clock.Now().After(expiresAt) {
+		return ErrInvalidUploadSignature
+	}
+	return nil
+}
+
+// sign アップロードIDと有効期限に対するHMAC-SHA256署名を生成
+func (uc *UploadUseCase) sign(uploadID string, expires int64) string {
+	mac := hmac.New(sha256.New, uc.secret)
+	_, _ = fmt.Fprintf(mac, "%s\n%d", uploadID, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,342 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+	"vision-api-app/internal/modules/shared/infrastructure/storage"
+)
+
+// presignedParams 署名付きURLからexpires・signatureを取り出す
+func presignedParams(t *testing.T, rawURL string) (string, string) {
+	t.Helper()
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+	return parsed.Query().Get("expires"), parsed.Query().Get("signature")
+}
+
+func TestUploadUseCase_PresignAndComplete(t *testing.T) {
+	var stored *entity.Receipt
+	mockReceipt := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			return nil, errors.New("not found")
+		},
+		CreateFunc: func(ctx context.Context, receipt *entity.Receipt) error {
+			stored = receipt
+			return nil
+		},
+	}
+	imageStorage, err := storage.NewLocalImageStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalImageStorage() error = %v", err)
+	}
+
+	receiptUseCase := NewReceiptUseCase(&MockAIRepository{}, mockReceipt, nil)
+	receiptUseCase.SetImageStorage(imageStorage)
+	uc := NewUploadUseCase(receiptUseCase, imageStorage, []byte("secret"), 15*time.Minute, 1<<20)
+	ctx := context.Background()
+
+	upload, err := uc.Presign(ctx)
+	if err != nil {
+		t.Fatalf("Presign() error = %v", err)
+	}
+	if !strings.HasPrefix(upload.UploadURL, "/api/v1/uploads/"+upload.UploadID+"?") {
+		t.Errorf("UploadURL = %s", upload.UploadURL)
+	}
+	if !strings.HasPrefix(upload.CompleteURL, "/api/v1/uploads/"+upload.UploadID+"/complete?") {
+		t.Errorf("CompleteURL = %s", upload.CompleteURL)
+	}
+	expires, signature := presignedParams(t, upload.UploadURL)
+
+	// 未アップロードのまま完了通知
+	if _, err := uc.CompleteUpload(ctx, upload.UploadID, expires, signature, ProcessOptions{}); !errors.Is(err, ErrUploadNotFound) {
+		t.Errorf("CompleteUpload() error = %v, want ErrUploadNotFound", err)
+	}
+
+	if err := uc.StoreUpload(ctx, upload.UploadID, expires, signature, []byte("receipt image")); err != nil {
+		t.Fatalf("StoreUpload() error = %v", err)
+	}
+
+	receipt, err := uc.CompleteUpload(ctx, upload.UploadID, expires, signature, ProcessOptions{Tags: []string{"旅行"}, Memo: "出張"})
+	if err != nil {
+		t.Fatalf("CompleteUpload() error = %v", err)
+	}
+	if stored == nil || stored.ID != receipt.ID || receipt.Memo != "出張" || len(receipt.Tags) != 1 {
+		t.Errorf("receipt = %+v, want stored with tags and memo", receipt)
+	}
+
+	// 処理待ちの画像はレシートIDの画像に置き換わる
+	if _, err := imageStorage.Load(ctx, uploadKeyPrefix+upload.UploadID); err == nil {
+		t.Error("Expected pending upload to be deleted")
+	}
+	if _, err := imageStorage.Load(ctx, receipt.ID); err != nil {
+		t.Errorf("Expected receipt image to be stored: %v", err)
+	}
+}
+
+// fakeDirectUploadStore 署名付きURLの代わりにキーを返すテスト用のオブジェクトストレージ
+type fakeDirectUploadStore struct {
+	objects map[string][]byte
+}
+
+func (f *fakeDirectUploadStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	f.objects[key] = data
+	return nil
+}
+
+func (f *fakeDirectUploadStore) Get(ctx context.Context, key string) ([]byte, error) {
+	data, ok := f.objects[key]
+	if !ok {
+		return nil, sharedDomain.ErrObjectNotFound
+	}
+	return data, nil
+}
+
+func (f *fakeDirectUploadStore) PresignPut(key string, expires time.Duration) (string, error) {
+	return "https://bucket.example.com/" + key + "?X-Amz-Expires=" + strconv.Itoa(int(expires.Seconds())), nil
+}
+
+func (f *fakeDirectUploadStore) Delete(ctx context.Context, key string) error {
+	delete(f.objects, key)
+	return nil
+}
+
+func TestUploadUseCase_ObjectStore(t *testing.T) {
+	mockReceipt := &MockReceiptRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Receipt, error) {
+			return nil, errors.New("not found")
+		},
+		CreateFunc: func(ctx context.Context, receipt *entity.Receipt) error { return nil },
+	}
+	imageStorage, err := storage.NewLocalImageStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalImageStorage() error = %v", err)
+	}
+	receiptUseCase := NewReceiptUseCase(&MockAIRepository{}, mockReceipt, nil)
+	receiptUseCase.SetImageStorage(imageStorage)
+	store := &fakeDirectUploadStore{objects: map[string][]byte{}}
+	uc := NewUploadUseCase(receiptUseCase, imageStorage, []byte("secret"), 15*time.Minute, 16)
+	uc.SetObjectStore(store)
+	ctx := context.Background()
+
+	// 画像本体はバケットへの署名付きURLへアップロードさせ、完了通知だけをAPIサーバーで受ける
+	upload, err := uc.Presign(ctx)
+	if err != nil {
+		t.Fatalf("Presign() error = %v", err)
+	}
+	if upload.UploadURL != "https://bucket.example.com/upload-"+upload.UploadID+"?X-Amz-Expires=900" {
+		t.Errorf("UploadURL = %s", upload.UploadURL)
+	}
+	expires, signature := presignedParams(t, upload.CompleteURL)
+	if err := uc.VerifyUpload(upload.UploadID, expires, signature); !errors.Is(err, ErrUploadToObjectStore) {
+		t.Errorf("VerifyUpload() error = %v, want %v", err, ErrUploadToObjectStore)
+	}
+	if _, err := uc.CompleteUpload(ctx, upload.UploadID, expires, signature, ProcessOptions{}); !errors.Is(err, ErrUploadNotFound) {
+		t.Errorf("CompleteUpload() error = %v, want %v", err, ErrUploadNotFound)
+	}
+
+	key := uploadKeyPrefix + upload.UploadID
+	store.objects[key] = []byte("receipt image")
+	receipt, err := uc.CompleteUpload(ctx, upload.UploadID, expires, signature, ProcessOptions{})
+	if err != nil {
+		t.Fatalf("CompleteUpload() error = %v", err)
+	}
+	if _, ok := store.objects[key]; ok {
+		t.Error("Expected uploaded object to be deleted")
+	}
+	if _, err := imageStorage.Load(ctx, receipt.ID); err != nil {
+		t.Errorf("Expected receipt image to be stored: %v", err)
+	}
+
+	// 署名付きURLではサイズを制限できないため、完了通知で最大サイズを確認する
+	store.objects[key] = []byte("too large receipt image")
+	if _, err := uc.CompleteUpload(ctx, upload.UploadID, expires, signature, ProcessOptions{}); !errors.Is(err, ErrUploadTooLarge) {
+		t.Errorf("CompleteUpload() error = %v, want %v", err, ErrUploadTooLarge)
+	}
+	if _, ok := store.objects[key]; ok {
+		t.Error("Expected too large object to be deleted")
+	}
+}
+
+func TestUploadUseCase_StoreUpload_Signature(t *testing.T) {
+	imageStorage, err := storage.NewLocalImageStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalImageStorage() error = %v", err)
+	}
+	uc := NewUploadUseCase(nil, imageStorage, []byte("secret"), 15*time.Minute, 1<<20)
+	ctx := context.Background()
+
+	upload, err := uc.Presign(ctx)
+	if err != nil {
+		t.Fatalf("Presign() error = %v", err)
+	}
+	expires, signature := presignedParams(t, upload.UploadURL)
+	past := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+
+	tests := []struct {
+		name      string
+		uploadID  string
+		expires   string
+		signature string
+		wantErr   error
+	}{
+		{
+			name:      "正常系: 有効な署名",
+			uploadID:  upload.UploadID,
+			expires:   expires,
+			signature: signature,
+		},
+		{
+			name:      "異常系: 別のアップロードIDの署名",
+			uploadID:  "other",
+			expires:   expires,
+			signature: signature,
+			wantErr:   ErrInvalidUploadSignature,
+		},
+		{
+			name:      "異常系: 有効期限の改ざん",
+			uploadID:  upload.UploadID,
+			expires:   strconv.FormatInt(upload.ExpiresAt.Add(time.Hour).Unix(), 10),
+			signature: signature,
+			wantErr:   ErrInvalidUploadSignature,
+		},
+		{
+			name:      "異常系: 期限切れ",
+			uploadID:  upload.UploadID,
+			expires:   past,
+			signature: uc.sign(upload.UploadID, time.Now().Add(-time.Minute).Unix()),
+			wantErr:   ErrInvalidUploadSignature,
+		},
+		{
+			name:      "異常系: 署名なし",
+			uploadID:  upload.UploadID,
+			expires:   expires,
+			signature: "",
+			wantErr:   ErrInvalidUploadSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := uc.VerifyUpload(tt.uploadID, tt.expires, tt.signature); !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyUpload() error = %v, want %v", err, tt.wantErr)
+			}
+			err := uc.StoreUpload(ctx, tt.uploadID, tt.expires, tt.signature, []byte("image"))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("StoreUpload() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUploadUseCase_CleanupExpiredUploads(t *testing.T) {
+	dir := t.TempDir()
+	imageStorage, err := storage.NewLocalImageStorage(dir)
+	if err != nil {
+		t.Fatalf("NewLocalImageStorage() error = %v", err)
+	}
+	uc := NewUploadUseCase(nil, imageStorage, []byte("secret"), 15*time.Minute, 1<<20)
+	ctx := context.Background()
+
+	for _, key := range []string{"upload-old", "upload-new", "old-receipt"} {
+		if err := imageStorage.Save(ctx, key, []byte("image")); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	old := time.Now().Add(-time.Hour)
+	for _, key := range []string{"upload-old", "old-receipt"} {
+		if err := os.Chtimes(filepath.Join(dir, key), old, old); err != nil {
+			t.Fatalf("Chtimes() error = %v", err)
+		}
+	}
+
+	count, err := uc.CleanupExpiredUploads(ctx)
+	if err != nil {
+		t.Fatalf("CleanupExpiredUploads() error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("count = %d, want 1", count)
+	}
+	if _, err := imageStorage.Load(ctx, "upload-old"); err == nil {
+		t.Error("Expected expired upload to be deleted")
+	}
+	// 期限内のアップロードとレシートの画像は残す
+	for _, key := range []string{"upload-new", "old-receipt"} {
+		if _, err := imageStorage.Load(ctx, key); err != nil {
+			t.Errorf("Expected %s to remain: %v", key, err)
+		}
+	}
+}
+
+func TestUploadUseCase_StoreChunk(t *testing.T) {
+	imageStorage, err := storage.NewLocalImageStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalImageStorage() error = %v", err)
+	}
+	uc := NewUploadUseCase(nil, imageStorage, []byte("secret"), 15*time.Minute, 16)
+	ctx := context.Background()
+
+	upload, err := uc.Presign(ctx)
+	if err != nil {
+		t.Fatalf("Presign() error = %v", err)
+	}
+	expires, signature := presignedParams(t, upload.UploadURL)
+	image := []byte("receipt-image")
+	total := int64(len(image))
+
+	steps := []struct {
+		name         string
+		start        int64
+		data         []byte
+		wantErr      error
+		wantOffset   int64
+		wantComplete bool
+	}{
+		{name: "最初のチャンク", start: 0, data: image[:5], wantOffset: 5},
+		{name: "受信済みの位置より先のチャンク", start: 8, data: image[8:], wantErr: ErrUploadOffsetMismatch, wantOffset: 5},
+		{name: "応答を受け取れず再送されたチャンク", start: 0, data: image[:8], wantOffset: 8},
+		{name: "最後のチャンク", start: 8, data: image[8:], wantOffset: total, wantComplete: true},
+	}
+
+	for _, step := range steps {
+		progress, err := uc.StoreChunk(ctx, upload.UploadID, expires, signature, step.start, total, step.data)
+		if !errors.Is(err, step.wantErr) {
+			t.Fatalf("%s: StoreChunk() error = %v, want %v", step.name, err, step.wantErr)
+		}
+		if progress.Offset != step.wantOffset || progress.Complete != step.wantComplete {
+			t.Errorf("%s: progress = %+v, want offset %d complete %v", step.name, progress, step.wantOffset, step.wantComplete)
+		}
+
+		status, err := uc.UploadStatus(ctx, upload.UploadID, expires, signature)
+		if err != nil {
+			t.Fatalf("%s: UploadStatus() error = %v", step.name, err)
+		}
+		if status.Offset != step.wantOffset {
+			t.Errorf("%s: status offset = %d, want %d", step.name, status.Offset, step.wantOffset)
+		}
+	}
+
+	// 組み立てた画像が処理待ちとして保存され、途中のデータは残らない
+	data, err := imageStorage.Load(ctx, uploadKeyPrefix+upload.UploadID)
+	if err != nil || string(data) != string(image) {
+		t.Errorf("assembled = %q (err %v), want %q", data, err, image)
+	}
+	if _, err := imageStorage.Load(ctx, uploadKeyPrefix+upload.UploadID+partialKeySuffix); err == nil {
+		t.Error("Expected partial upload to be deleted")
+	}
+
+	if _, err := uc.StoreChunk(ctx, upload.UploadID, expires, signature, 0, 17, []byte("x")); !errors.Is(err, ErrUploadTooLarge) {
+		t.Errorf("StoreChunk() error = %v, want ErrUploadTooLarge", err)
+	}
+}
@@ -0,0 +1,437 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// ErrIncompatibleSchema 書き出す表の列が書き出し済みのスキーマと互換性がない（列の削除・型の変更・nullを許さない列の追加）
+var ErrIncompatibleSchema = errors.New("incompatible warehouse schema")
+
+// 分析用に書き出す表
+const (
+	WarehouseTableReceipts     = "receipts"
+	WarehouseTableReceiptItems = "receipt_items"
+	WarehouseTableExpenses     = "expenses"
+)
+
+// warehouseSchemaKey 表ごとのスキーマの記録のキー（表のディレクトリの直下）
+const warehouseSchemaKey = "_schema.json"
+
+// warehouseTables 書き出す表の列（金額は通貨の最小単位の整数、日付は設定のタイムゾーンの暦日）
+// 列を追加する場合は末尾にOptionalで追加する。書き出し済みの月のファイルに列がなくても、nullとして読めるようにするため
+var warehouseTables = map[string][]sharedDomain.TableColumn{
+	WarehouseTableReceipts: {
+		{Name: "id", Type: sharedDomain.ColumnString},
+		{Name: "store_name", Type: sharedDomain.ColumnString},
+		{Name: "purchase_date", Type: sharedDomain.ColumnDate},
+		{Name: "purchased_at", Type: sharedDomain.ColumnTimestamp},
+		{Name: "total_amount", Type: sharedDomain.ColumnInt64},
+		{Name: "tax_amount", Type: sharedDomain.ColumnInt64},
+		{Name: "currency", Type: sharedDomain.ColumnString},
+		{Name: "payment_method", Type: sharedDomain.ColumnString},
+		{Name: "receipt_number", Type: sharedDomain.ColumnString},
+		{Name: "type", Type: sharedDomain.ColumnString},
+		{Name: "category", Type: sharedDomain.ColumnString},
+		{Name: "needs_review", Type: sharedDomain.ColumnBool},
+		{Name: "tags", Type: sharedDomain.ColumnString},
+		{Name: "memo", Type: sharedDomain.ColumnString},
+		{Name: "item_count", Type: sharedDomain.ColumnInt64},
+		{Name: "created_at", Type: sharedDomain.ColumnTimestamp},
+		{Name: "updated_at", Type: sharedDomain.ColumnTimestamp},
+	},
+	WarehouseTableReceiptItems: {
+		{Name: "id", Type: sharedDomain.ColumnString},
+		{Name: "receipt_id", Type: sharedDomain.ColumnString},
+		{Name: "purchase_date", Type: sharedDomain.ColumnDate},
+		{Name: "store_name", Type: sharedDomain.ColumnString},
+		{Name: "name", Type: sharedDomain.ColumnString},
+		{Name: "quantity", Type: sharedDomain.ColumnInt64},
+		{Name: "price", Type: sharedDomain.ColumnInt64},
+		{Name: "amount", Type: sharedDomain.ColumnInt64},
+		{Name: "unit", Type: sharedDomain.ColumnString},
+		{Name: "measure", Type: sharedDomain.ColumnDouble, Optional: true},
+		{Name: "currency", Type: sharedDomain.ColumnString},
+		{Name: "category", Type: sharedDomain.ColumnString},
+	},
+	WarehouseTableExpenses: {
+		{Name: "id", Type: sharedDomain.ColumnString},
+		{Name: "receipt_id", Type: sharedDomain.ColumnString, Optional: true},
+		{Name: "date", Type: sharedDomain.ColumnDate},
+		{Name: "category", Type: sharedDomain.ColumnString},
+		{Name: "amount", Type: sharedDomain.ColumnInt64},
+		{Name: "currency", Type: sharedDomain.ColumnString},
+		{Name: "description", Type: sharedDomain.ColumnString},
+		{Name: "tags", Type: sharedDomain.ColumnString},
+		{Name: "memo", Type: sharedDomain.ColumnString},
+		{Name: "created_at", Type: sharedDomain.ColumnTimestamp},
+		{Name: "updated_at", Type: sharedDomain.ColumnTimestamp},
+	},
+}
+
+// warehouseTableOrder 表を書き出す順
+var warehouseTableOrder = []string{WarehouseTableReceipts, WarehouseTableReceiptItems, WarehouseTableExpenses}
+
+// WarehouseSchema オブジェクトストレージに記録する表のスキーマ（列を変更するたびにバージョンを上げる）
+type WarehouseSchema struct {
+	Table     string                     `json:"table"`
+	Version   int                        `json:"version"`
+	Columns   []sharedDomain.TableColumn `json:"columns"`
+	UpdatedAt time.Time                  `json:"updated_at"`
+}
+
+// WarehouseTableReport 1つの表の書き出し結果
+type WarehouseTableReport struct {
+	Table         string `json:"table"`
+	Key           string `json:"key"`
+	Rows          int    `json:"rows"`
+	Bytes         int    `json:"bytes"`
+	SchemaVersion int    `json:"schema_version"`
+}
+
+// WarehouseExportReport 1か月分の書き出し結果
+type WarehouseExportReport struct {
+	Year   int                    `json:"year"`
+	Month  int                    `json:"month"`
+	Tables []WarehouseTableReport `json:"tables"`
+}
+
+// WarehouseExportUseCase レシート・明細・家計簿エントリを分析用のファイル（Parquetなど）としてオブジェクトストレージに書き出すユースケース
+// 表ごとに year=YYYY/month=MM で分割したディレクトリに月ごとのファイルを書き出し（Hive形式のパーティション）、
+// 同じ月を書き出し直すとファイルを置き換える。DuckDB・BigQueryなどから月を指定して読み込める
+type WarehouseExportUseCase struct {
+	receiptRepo    repository.ReceiptRepository
+	expenseRepo    repository.ExpenseRepository
+	store          sharedDomain.ObjectStore
+	format         sharedDomain.TableFormat
+	currency       sharedDomain.Currency
+	lookbackMonths int
+	now            func() time.Time
+}
+
+// NewWarehouseExportUseCase 新しいWarehouseExportUseCaseを作成
+func NewWarehouseExportUseCase(receiptRepo repository.ReceiptRepository, expenseRepo repository.ExpenseRepository, store sharedDomain.ObjectStore, format sharedDomain.TableFormat) *WarehouseExportUseCase {
+	return &WarehouseExportUseCase{
+		receiptRepo: receiptRepo,
+		expenseRepo: expenseRepo,
+		store:       store,
+		format:      format,
+		currency:    sharedDomain.DefaultCurrency,
+		now:         time.Now,
+	}
+}
+
+// SetCurrency 金額の通貨を設定する（通貨の記録がないレシート・家計簿エントリのcurrency列に使う）
+func (uc *WarehouseExportUseCase) SetCurrency(currency sharedDomain.Currency) {
+	uc.currency = currency
+}
+
+// SetLookbackMonths 定期実行で当月に加えて書き出し直す過去の月数を設定する（0以下は当月のみ）
+// 過去の月のレシートの修正・追加を反映するため
+func (uc *WarehouseExportUseCase) SetLookbackMonths(months int) {
+	uc.lookbackMonths = max(months, 0)
+}
+
+// ExportRecent 当月と過去の月（SetLookbackMonths）を古い月から順に書き出す
+func (uc *WarehouseExportUseCase) ExportRecent(ctx context.Context) ([]*WarehouseExportReport, error) {
+	now := uc.now().In(sharedDomain.LocationFromContext(ctx))
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	reports := make([]*WarehouseExportReport, 0, uc.lookbackMonths+1)
+	for i := uc.lookbackMonths; i >= 0; i-- {
+		month := current.AddDate(0, -i, 0)
+		report, err := uc.ExportMonth(ctx, month.Year(), int(month.Month()))
+		if err != nil {
+			return reports, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// ExportMonth 指定月のレシート・明細・家計簿エントリを表ごとのファイルに書き出す
+// 書き出す前にすべての表の列を書き出し済みのスキーマと照合し、互換性がない場合は何も書き出さずにErrIncompatibleSchemaを返す
+func (uc *WarehouseExportUseCase) ExportMonth(ctx context.Context, year, month int) (*WarehouseExportReport, error) {
+	if year < 1 || month < 1 || month > 12 {
+		return nil, fmt.Errorf("%w: %04d-%02d", ErrInvalidReportPeriod, year, month)
+	}
+
+	schemas := make(map[string]*WarehouseSchema, len(warehouseTableOrder))
+	changed := make(map[string]bool, len(warehouseTableOrder))
+	for _, table := range warehouseTableOrder {
+		schema, updated, err := uc.resolveSchema(ctx, table)
+		if err != nil {
+			return nil, err
+		}
+		schemas[table] = schema
+		changed[table] = updated
+	}
+
+	loc := sharedDomain.LocationFromContext(ctx)
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, loc)
+	end := start.AddDate(0, 1, 0).Add(-time.Nanosecond)
+
+	receipts := uc.newTable(WarehouseTableReceipts, schemas)
+	items := uc.newTable(WarehouseTableReceiptItems, schemas)
+	err := uc.receiptRepo.ForEachByDateRange(ctx, start, end, func(receipt *entity.Receipt) error {
+		if err := receipts.write(uc.receiptRow(receipt, loc)); err != nil {
+			return err
+		}
+		for _, item := range receipt.Items {
+			if err := items.write(uc.itemRow(receipt, item, loc)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to export receipts: %w", err)
+	}
+
+	expenses := uc.newTable(WarehouseTableExpenses, schemas)
+	entries, err := uc.expenseRepo.FindByDateRange(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export expenses: %w", err)
+	}
+	for _, entry := range entries {
+		if err := expenses.write(uc.expenseRow(entry, loc)); err != nil {
+			return nil, fmt.Errorf("failed to export expenses: %w", err)
+		}
+	}
+
+	report := &WarehouseExportReport{Year: year, Month: month}
+	for _, table := range []*warehouseTable{receipts, items, expenses} {
+		key := fmt.Sprintf("%s/year=%04d/month=%02d/part-0%s", table.name, year, month, uc.format.Extension())
+		if err := table.writer.Close(); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", table.name, err)
+		}
+		if err := uc.store.Put(ctx, key, table.buf.Bytes(), uc.format.ContentType()); err != nil {
+			return nil, fmt.Errorf("failed to upload %s: %w", key, err)
+		}
+		report.Tables = append(report.Tables, WarehouseTableReport{
+			Table:         table.name,
+			Key:           key,
+			Rows:          table.rows,
+			Bytes:         table.buf.Len(),
+			SchemaVersion: schemas[table.name].Version,
+		})
+	}
+
+	// スキーマの記録はファイルを書き出した後に更新し、新しい列のファイルが1つもない状態で記録だけが進まないようにする
+	for _, table := range warehouseTableOrder {
+		if !changed[table] {
+			continue
+		}
+		if err := uc.saveSchema(ctx, schemas[table]); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// resolveSchema 書き出し済みのスキーマと現在の列を照合し、書き出すスキーマを求める（列が変わった場合はupdatedがtrue）
+// 列の追加（nullを許す列のみ）とnullを許さない列をnullを許す列に変える変更は互換性があるとみなし、バージョンを上げる
+func (uc *WarehouseExportUseCase) resolveSchema(ctx context.Context, table string) (schema *WarehouseSchema, updated bool, err error) {
+	columns := warehouseTables[table]
+	current := &WarehouseSchema{Table: table, Version: 1, Columns: columns, UpdatedAt: uc.now()}
+
+	data, err := uc.store.Get(ctx, table+"/"+warehouseSchemaKey)
+	if errors.Is(err, sharedDomain.ErrObjectNotFound) {
+		return current, true, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load %s schema: %w", table, err)
+	}
+	var previous WarehouseSchema
+	if err := json.Unmarshal(data, &previous); err != nil {
+		return nil, false, fmt.Errorf("failed to decode %s schema: %w", table, err)
+	}
+
+	if err := checkSchemaCompatibility(previous.Columns, columns); err != nil {
+		return nil, false, fmt.Errorf("%w: %s (version %d): %v", ErrIncompatibleSchema, table, previous.Version, err)
+	}
+	if sameColumns(previous.Columns, columns) {
+		return &previous, false, nil
+	}
+	current.Version = previous.Version + 1
+	return current, true, nil
+}
+
+// checkSchemaCompatibility 書き出し済みの列から現在の列への変更が、過去のファイルとまとめて読める変更かをチェック
+func checkSchemaCompatibility(previous, current []sharedDomain.TableColumn) error {
+	byName := make(map[string]sharedDomain.TableColumn, len(current))
+	for _, c := range current {
+		byName[c.Name] = c
+	}
+	known := make(map[string]bool, len(previous))
+	for _, p := range previous {
+		known[p.Name] = true
+		c, ok := byName[p.Name]
+		switch {
+		case !ok:
+			return fmt.Errorf("column %q was removed", p.Name)
+		case c.Type != p.Type:
+			return fmt.Errorf("column %q changed type from %s to %s", p.Name, p.Type, c.Type)
+		case p.Optional && !c.Optional:
+			return fmt.Errorf("column %q became required", p.Name)
+		}
+	}
+	for _, c := range current {
+		if !known[c.Name] && !c.Optional {
+			return fmt.Errorf("added column %q must be optional", c.Name)
+		}
+	}
+	return nil
+}
+
+// sameColumns 列の定義が同じかチェック
+func sameColumns(a, b []sharedDomain.TableColumn) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// saveSchema スキーマの記録を保存
+func (uc *WarehouseExportUseCase) saveSchema(ctx context.Context, schema *WarehouseSchema) error {
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s schema: %w", schema.Table, err)
+	}
+	if err := uc.store.Put(ctx, schema.Table+"/"+warehouseSchemaKey, data, "application/json"); err != nil {
+		return fmt.Errorf("failed to save %s schema: %w", schema.Table, err)
+	}
+	return nil
+}
+
+// warehouseTable 書き出し中の表
+type warehouseTable struct {
+	name   string
+	buf    *bytes.Buffer
+	writer sharedDomain.TableWriter
+	rows   int
+}
+
+// newTable 表の書き出しを開始する（ファイルには表の名前とスキーマのバージョンを埋め込む）
+func (uc *WarehouseExportUseCase) newTable(name string, schemas map[string]*WarehouseSchema) *warehouseTable {
+	schema := schemas[name]
+	buf := &bytes.Buffer{}
+	return &warehouseTable{
+		name: name,
+		buf:  buf,
+		writer: uc.format.NewWriter(buf, schema.Columns, map[string]string{
+			"table":          name,
+			"schema_version": strconv.Itoa(schema.Version),
+		}),
+	}
+}
+
+// write 1行を書き出す
+func (t *warehouseTable) write(row []any) error {
+	if err := t.writer.Write(row); err != nil {
+		return fmt.Errorf("failed to write %s row: %w", t.name, err)
+	}
+	t.rows++
+	return nil
+}
+
+// receiptRow レシートをreceiptsの行にする
+func (uc *WarehouseExportUseCase) receiptRow(receipt *entity.Receipt, loc *time.Location) []any {
+	return []any{
+		receipt.ID,
+		receipt.StoreName,
+		receipt.PurchaseDate.In(loc),
+		receipt.PurchaseDate,
+		receipt.TotalAmount,
+		receipt.TaxAmount,
+		uc.currencyCode(receipt.Currency),
+		receipt.PaymentMethod,
+		receipt.ReceiptNumber,
+		warehouseReceiptType(receipt),
+		receipt.Category,
+		receipt.NeedsReview,
+		strings.Join(receipt.Tags, ","),
+		receipt.Memo,
+		int64(len(receipt.Items)),
+		receipt.CreatedAt,
+		receipt.UpdatedAt,
+	}
+}
+
+// itemRow 明細をreceipt_itemsの行にする（カテゴリーのない明細はレシートのカテゴリー）
+func (uc *WarehouseExportUseCase) itemRow(receipt *entity.Receipt, item entity.ReceiptItem, loc *time.Location) []any {
+	category := item.Category
+	if category == "" {
+		category = receipt.Category
+	}
+	var measure any
+	if item.Measure != 0 {
+		measure = item.Measure
+	}
+	return []any{
+		item.ID,
+		receipt.ID,
+		receipt.PurchaseDate.In(loc),
+		receipt.StoreName,
+		item.Name,
+		int64(item.Quantity),
+		item.Price,
+		item.Amount(),
+		item.Unit,
+		measure,
+		uc.currencyCode(receipt.Currency),
+		category,
+	}
+}
+
+// expenseRow 家計簿エントリをexpensesの行にする
+func (uc *WarehouseExportUseCase) expenseRow(entry *entity.ExpenseEntry, loc *time.Location) []any {
+	var receiptID any
+	if entry.ReceiptID != nil {
+		receiptID = *entry.ReceiptID
+	}
+	return []any{
+		entry.ID,
+		receiptID,
+		entry.Date.In(loc),
+		entry.Category,
+		entry.Amount,
+		uc.currency.Code,
+		entry.Description,
+		strings.Join(entry.Tags, ","),
+		entry.Memo,
+		entry.CreatedAt,
+		entry.UpdatedAt,
+	}
+}
+
+// currencyCode レシートの通貨（記録がない場合は設定の通貨）
+func (uc *WarehouseExportUseCase) currencyCode(code string) string {
+	if code == "" {
+		return uc.currency.Code
+	}
+	return code
+}
+
+// warehouseReceiptType レシートの種類（記録がない場合は購入）
+func warehouseReceiptType(receipt *entity.Receipt) string {
+	if receipt.IsRefund() {
+		return entity.ReceiptTypeRefund
+	}
+	return entity.ReceiptTypePurchase
+}
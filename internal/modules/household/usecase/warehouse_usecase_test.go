@@ -0,0 +1,311 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// memoryObjectStore メモリ上のオブジェクトストレージ
+type memoryObjectStore struct {
+	objects map[string][]byte
+	puts    []string
+}
+
+func newMemoryObjectStore() *memoryObjectStore {
+	return &memoryObjectStore{objects: map[string][]byte{}}
+}
+
+func (s *memoryObjectStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	s.objects[key] = append([]byte(nil), data...)
+	s.puts = append(s.puts, key)
+	return nil
+}
+
+func (s *memoryObjectStore) Get(ctx context.Context, key string) ([]byte, error) {
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, sharedDomain.ErrObjectNotFound
+	}
+	return data, nil
+}
+
+// recordingTableFormat 書き出した行をファイルの内容の代わりにJSONで書き出す表形式
+type recordingTableFormat struct{}
+
+type recordedTable struct {
+	Columns  []sharedDomain.TableColumn `json:"columns"`
+	Metadata map[string]string          `json:"metadata"`
+	Rows     [][]any                    `json:"rows"`
+}
+
+func (recordingTableFormat) Extension() string   { return ".json" }
+func (recordingTableFormat) ContentType() string { return "application/json" }
+func (recordingTableFormat) NewWriter(w io.Writer, columns []sharedDomain.TableColumn, metadata map[string]string) sharedDomain.TableWriter {
+	return &recordingTableWriter{w: w, table: recordedTable{Columns: columns, Metadata: metadata, Rows: [][]any{}}}
+}
+
+type recordingTableWriter struct {
+	w     io.Writer
+	table recordedTable
+}
+
+func (r *recordingTableWriter) Write(row []any) error {
+	if len(row) != len(r.table.Columns) {
+		return errors.New("column count mismatch")
+	}
+	r.table.Rows = append(r.table.Rows, row)
+	return nil
+}
+
+func (r *recordingTableWriter) Close() error {
+	return json.NewEncoder(r.w).Encode(r.table)
+}
+
+func newTestWarehouseUseCase(store *memoryObjectStore) *WarehouseExportUseCase {
+	receiptID := "r1"
+	receiptRepo := &MockReceiptRepository{
+		FindByDateRangeFunc: func(ctx context.Context, start, end time.Time) ([]*entity.Receipt, error) {
+			if start.Month() != time.March {
+				return nil, nil
+			}
+			return []*entity.Receipt{{
+				ID: "r1", StoreName: "スーパーA", Category: "食費", TotalAmount: 550, TaxAmount: 50, Tags: []string{"家族", "週末"},
+				PurchaseDate: time.Date(2025, time.March, 31, 23, 30, 0, 0, time.UTC),
+				Items: []entity.ReceiptItem{
+					{ID: "i1", Name: "牛乳", Quantity: 2, Price: 200, Category: "飲料"},
+					{ID: "i2", Name: "豚肉", Quantity: 1, Price: 150, Unit: entity.UnitGram, Measure: 320},
+				},
+			}}, nil
+		},
+	}
+	expenseRepo := &MockExpenseRepository{
+		FindByDateRangeFunc: func(ctx context.Context, start, end time.Time) ([]*entity.ExpenseEntry, error) {
+			if start.Month() != time.March {
+				return nil, nil
+			}
+			return []*entity.ExpenseEntry{
+				{ID: "e1", ReceiptID: &receiptID, Date: time.Date(2025, time.March, 31, 23, 30, 0, 0, time.UTC), Category: "食費", Amount: 550},
+				{ID: "e2", Date: time.Date(2025, time.March, 2, 0, 0, 0, 0, time.UTC), Category: "交通費", Amount: 1800},
+			}, nil
+		},
+	}
+	uc := NewWarehouseExportUseCase(receiptRepo, expenseRepo, store, recordingTableFormat{})
+	uc.now = func() time.Time { return time.Date(2025, time.March, 15, 12, 0, 0, 0, time.UTC) }
+	return uc
+}
+
+func readRecordedTable(t *testing.T, store *memoryObjectStore, key string) recordedTable {
+	t.Helper()
+	data, ok := store.objects[key]
+	if !ok {
+		t.Fatalf("object %s was not written", key)
+	}
+	var table recordedTable
+	if err := json.Unmarshal(data, &table); err != nil {
+		t.Fatalf("failed to decode %s: %v", key, err)
+	}
+	return table
+}
+
+func readSchema(t *testing.T, store *memoryObjectStore, table string) WarehouseSchema {
+	t.Helper()
+	var schema WarehouseSchema
+	if err := json.Unmarshal(store.objects[table+"/_schema.json"], &schema); err != nil {
+		t.Fatalf("failed to decode %s schema: %v", table, err)
+	}
+	return schema
+}
+
+func TestWarehouseExportUseCase_ExportMonth(t *testing.T) {
+	store := newMemoryObjectStore()
+	uc := newTestWarehouseUseCase(store)
+	jst := time.FixedZone("JST", 9*60*60)
+	ctx := sharedDomain.WithLocation(context.Background(), jst)
+
+	report, err := uc.ExportMonth(ctx, 2025, 3)
+	if err != nil {
+		t.Fatalf("ExportMonth() error = %v", err)
+	}
+
+	wantTables := []WarehouseTableReport{
+		{Table: "receipts", Key: "receipts/year=2025/month=03/part-0.json", Rows: 1, SchemaVersion: 1},
+		{Table: "receipt_items", Key: "receipt_items/year=2025/month=03/part-0.json", Rows: 2, SchemaVersion: 1},
+		{Table: "expenses", Key: "expenses/year=2025/month=03/part-0.json", Rows: 2, SchemaVersion: 1},
+	}
+	if len(report.Tables) != len(wantTables) {
+		t.Fatalf("Tables = %+v", report.Tables)
+	}
+	for i, want := range wantTables {
+		got := report.Tables[i]
+		got.Bytes = 0
+		if got != want {
+			t.Errorf("Tables[%d] = %+v, want %+v", i, got, want)
+		}
+	}
+
+	receipts := readRecordedTable(t, store, "receipts/year=2025/month=03/part-0.json")
+	if receipts.Metadata["schema_version"] != "1" || receipts.Metadata["table"] != "receipts" {
+		t.Errorf("metadata = %v", receipts.Metadata)
+	}
+	row := receipts.Rows[0]
+	// 日付は設定のタイムゾーンの暦日、通貨の記録がないレシートは設定の通貨、タグはカンマ区切り
+	if row[2] != "2025-04-01T08:30:00+09:00" || row[6] != "JPY" || row[9] != entity.ReceiptTypePurchase || row[12] != "家族,週末" || row[14] != float64(2) {
+		t.Errorf("receipt row = %v", row)
+	}
+
+	items := readRecordedTable(t, store, "receipt_items/year=2025/month=03/part-0.json")
+	// カテゴリーのない明細はレシートのカテゴリー、量り売りでない明細の計量値はnull
+	if items.Rows[0][7] != float64(400) || items.Rows[0][9] != nil || items.Rows[0][11] != "飲料" {
+		t.Errorf("item row = %v", items.Rows[0])
+	}
+	if items.Rows[1][9] != float64(320) || items.Rows[1][11] != "食費" {
+		t.Errorf("item row = %v", items.Rows[1])
+	}
+
+	expenses := readRecordedTable(t, store, "expenses/year=2025/month=03/part-0.json")
+	if expenses.Rows[0][1] != "r1" || expenses.Rows[1][1] != nil {
+		t.Errorf("expense receipt_id = %v, %v", expenses.Rows[0][1], expenses.Rows[1][1])
+	}
+
+	for _, table := range []string{"receipts", "receipt_items", "expenses"} {
+		if schema := readSchema(t, store, table); schema.Version != 1 || len(schema.Columns) != len(warehouseTables[table]) {
+			t.Errorf("%s schema = %+v", table, schema)
+		}
+	}
+
+	// 列が変わらなければスキーマの記録は更新せず、データのファイルだけを置き換える
+	store.puts = nil
+	if _, err := uc.ExportMonth(ctx, 2025, 3); err != nil {
+		t.Fatalf("ExportMonth() again error = %v", err)
+	}
+	if len(store.puts) != 3 {
+		t.Errorf("puts = %v, want data files only", store.puts)
+	}
+}
+
+func TestWarehouseExportUseCase_SchemaEvolution(t *testing.T) {
+	columns := warehouseTables[WarehouseTableReceiptItems]
+
+	tests := []struct {
+		name        string
+		previous    []sharedDomain.TableColumn
+		wantVersion int
+		wantErr     error
+	}{
+		{
+			name:     "異常系: nullを許さない列の追加",
+			previous: withoutColumn(columns, "currency"),
+			wantErr:  ErrIncompatibleSchema,
+		},
+		{
+			name:        "正常系: nullを許す列の追加",
+			previous:    withoutColumn(columns, "measure"),
+			wantVersion: 4,
+		},
+		{
+			name: "正常系: nullを許さない列をnullを許す列に変更",
+			previous: func() []sharedDomain.TableColumn {
+				previous := append([]sharedDomain.TableColumn(nil), columns...)
+				previous[9].Optional = false
+				return previous
+			}(),
+			wantVersion: 4,
+		},
+		{
+			name:     "異常系: 列の削除",
+			previous: append(append([]sharedDomain.TableColumn(nil), columns...), sharedDomain.TableColumn{Name: "legacy", Type: sharedDomain.ColumnString}),
+			wantErr:  ErrIncompatibleSchema,
+		},
+		{
+			name: "異常系: 型の変更",
+			previous: func() []sharedDomain.TableColumn {
+				previous := append([]sharedDomain.TableColumn(nil), columns...)
+				previous[6].Type = sharedDomain.ColumnDouble
+				return previous
+			}(),
+			wantErr: ErrIncompatibleSchema,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemoryObjectStore()
+			previous, _ := json.Marshal(WarehouseSchema{Table: WarehouseTableReceiptItems, Version: 3, Columns: tt.previous})
+			store.objects["receipt_items/_schema.json"] = previous
+			uc := newTestWarehouseUseCase(store)
+
+			report, err := uc.ExportMonth(sharedDomain.WithLocation(context.Background(), time.UTC), 2025, 3)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ExportMonth() error = %v, want %v", err, tt.wantErr)
+				}
+				// 互換性がない場合はどの表も書き出さない
+				if len(store.puts) != 0 {
+					t.Errorf("puts = %v, want none", store.puts)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExportMonth() error = %v", err)
+			}
+			if got := report.Tables[1].SchemaVersion; got != tt.wantVersion {
+				t.Errorf("SchemaVersion = %d, want %d", got, tt.wantVersion)
+			}
+			if schema := readSchema(t, store, WarehouseTableReceiptItems); schema.Version != tt.wantVersion || len(schema.Columns) != len(columns) {
+				t.Errorf("schema = %+v", schema)
+			}
+			items := readRecordedTable(t, store, "receipt_items/year=2025/month=03/part-0.json")
+			if items.Metadata["schema_version"] != "4" {
+				t.Errorf("metadata = %v", items.Metadata)
+			}
+		})
+	}
+}
+
+func withoutColumn(columns []sharedDomain.TableColumn, name string) []sharedDomain.TableColumn {
+	var result []sharedDomain.TableColumn
+	for _, c := range columns {
+		if c.Name != name {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+func TestWarehouseExportUseCase_ExportRecent(t *testing.T) {
+	store := newMemoryObjectStore()
+	uc := newTestWarehouseUseCase(store)
+	uc.SetLookbackMonths(2)
+
+	reports, err := uc.ExportRecent(sharedDomain.WithLocation(context.Background(), time.UTC))
+	if err != nil {
+		t.Fatalf("ExportRecent() error = %v", err)
+	}
+	var months []int
+	for _, report := range reports {
+		months = append(months, report.Month)
+	}
+	if len(months) != 3 || months[0] != 1 || months[1] != 2 || months[2] != 3 {
+		t.Errorf("months = %v, want [1 2 3]", months)
+	}
+	// 行のない月も空のファイルで置き換える（削除したレシートを反映するため）
+	if table := readRecordedTable(t, store, "receipts/year=2025/month=01/part-0.json"); len(table.Rows) != 0 {
+		t.Errorf("rows = %v, want none", table.Rows)
+	}
+}
+
+func TestWarehouseExportUseCase_InvalidMonth(t *testing.T) {
+	uc := newTestWarehouseUseCase(newMemoryObjectStore())
+	for _, month := range []int{0, 13} {
+		if _, err := uc.ExportMonth(context.Background(), 2025, month); !errors.Is(err, ErrInvalidReportPeriod) {
+			t.Errorf("ExportMonth(%d) error = %v, want ErrInvalidReportPeriod", month, err)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// NotificationEventWarrantyExpiring 返品・保証期限が近い商品の通知イベント
+const NotificationEventWarrantyExpiring = "warranty.expiring"
+
+// WarrantyRules 返品・保証期限の管理ルール
+type WarrantyRules struct {
+	MinAmount     int64 // 期限を管理する明細の単価の下限（通貨の最小単位、保証期間を設定した明細は金額によらず対象）
+	DefaultMonths int   // 保証期間が未設定の明細の保証期間（月数、0は保証期限を管理しない）
+	ReturnDays    int   // 返品を受け付ける日数（0は返品期限を管理しない）
+	NotifyDays    int   // 期限の何日前に通知するか
+}
+
+// ExpiringItem 期限が近い明細
+type ExpiringItem struct {
+	Item     entity.PurchasedItem
+	Kind     string    // entity.DeadlineReturn / entity.DeadlineWarranty
+	Deadline time.Time // 期限日（この日まで有効）
+}
+
+// expiringNotification 期限が近い明細の通知内容
+type expiringNotification struct {
+	ReceiptID string    `json:"receipt_id"`
+	ItemID    string    `json:"item_id"`
+	Name      string    `json:"name"`
+	Price     int64     `json:"price"`
+	StoreName string    `json:"store_name"`
+	Kind      string    `json:"kind"`
+	Deadline  time.Time `json:"deadline"`
+}
+
+// WarrantyUseCase 高額な商品の返品・保証期限を管理するユースケース
+type WarrantyUseCase struct {
+	receiptRepo repository.ReceiptRepository
+	rules       WarrantyRules
+	notifier    sharedDomain.Notifier
+	now         func() time.Time
+}
+
+// NewWarrantyUseCase 新しいWarrantyUseCaseを作成
+func NewWarrantyUseCase(receiptRepo repository.ReceiptRepository, rules WarrantyRules) *WarrantyUseCase {
+	return &WarrantyUseCase{
+		receiptRepo: receiptRepo,
+		rules:       rules,
+		now:         time.Now,
+	}
+}
+
+// SetNotifier 期限が近い商品の通知先を設定する
+// 未設定の場合は通知しない
+func (uc *WarrantyUseCase) SetNotifier(notifier sharedDomain.Notifier) {
+	uc.notifier = notifier
+}
+
+// ListExpiring 今日からdays日以内に返品・保証期限を迎える明細を期限の近い順に取得
+// 今日・期限日はコンテキストのタイムゾーンの暦日で判定する
+func (uc *WarrantyUseCase) ListExpiring(ctx context.Context, days int) ([]ExpiringItem, error) {
+	today := truncateDay(uc.now().In(sharedDomain.LocationFromContext(ctx)))
+	return uc.findExpiring(ctx, today, today.AddDate(0, 0, days))
+}
+
+// NotifyExpiring 通知日数以内に期限を迎える未通知の明細をまとめて通知し、通知済みにする
+// 通知に失敗した場合は通知済みにしないため、次回の実行で再度通知する
+func (uc *WarrantyUseCase) NotifyExpiring(ctx context.Context) (int, error) {
+	if uc.notifier == nil {
+		return 0, nil
+	}
+
+	now := uc.now()
+	today := truncateDay(now.In(sharedDomain.LocationFromContext(ctx)))
+	expiring, err := uc.findExpiring(ctx, today, today.AddDate(0, 0, uc.rules.NotifyDays))
+	if err != nil {
+		return 0, err
+	}
+
+	pending := make([]ExpiringItem, 0, len(expiring))
+	data := make([]expiringNotification, 0, len(expiring))
+	for _, e := range expiring {
+		if notifiedAt(&e.Item.Item, e.Kind) != nil {
+			continue
+		}
+		pending = append(pending, e)
+		data = append(data, expiringNotification{
+			ReceiptID: e.Item.Item.ReceiptID,
+			ItemID:    e.Item.Item.ID,
+			Name:      e.Item.Item.Name,
+			Price:     e.Item.Item.Price,
+			StoreName: e.Item.StoreName,
+			Kind:      e.Kind,
+			Deadline:  e.Deadline,
+		})
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	if err := uc.notifier.Notify(ctx, sharedDomain.Notification{
+		Event:     NotificationEventWarrantyExpiring,
+		Message:   fmt.Sprintf("返品・保証期限が%d日以内の商品が%d件あります", uc.rules.NotifyDays, len(pending)),
+		Data:      data,
+		CreatedAt: now,
+	}); err != nil {
+		return 0, fmt.Errorf("failed to notify expiring warranties: %w", err)
+	}
+
+	for _, e := range pending {
+		if err := uc.receiptRepo.MarkItemNotified(ctx, e.Item.Item.ID, e.Kind, now); err != nil {
+			slog.Error("Failed to mark item notified", "item_id", e.Item.Item.ID, "kind", e.Kind, "error", err)
+		}
+	}
+	return len(pending), nil
+}
+
+// findExpiring fromからtoまで（両端を含む）に返品・保証期限を迎える明細を期限の近い順に取得
+// 期限日はfromのタイムゾーンの暦日に切り捨てて比較する
+func (uc *WarrantyUseCase) findExpiring(ctx context.Context, from, to time.Time) ([]ExpiringItem, error) {
+	items, err := uc.receiptRepo.FindWarrantyItems(ctx, uc.rules.MinAmount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find warranty items: %w", err)
+	}
+
+	expiring := []ExpiringItem{}
+	add := func(item *entity.PurchasedItem, kind string, deadline time.Time) {
+		deadline = truncateDay(deadline.In(from.Location()))
+		if deadline.Before(from) || deadline.After(to) {
+			return
+		}
+		expiring = append(expiring, ExpiringItem{Item: *item, Kind: kind, Deadline: deadline})
+	}
+
+	for _, item := range items {
+		if item.Item.Price >= uc.rules.MinAmount {
+			if deadline, ok := item.ReturnDeadline(uc.rules.ReturnDays); ok {
+				add(item, entity.DeadlineReturn, deadline)
+			}
+		}
+		if deadline, ok := item.WarrantyExpiresAt(uc.rules.DefaultMonths); ok {
+			add(item, entity.DeadlineWarranty, deadline)
+		}
+	}
+
+	sort.SliceStable(expiring, func(i, j int) bool {
+		return expiring[i].Deadline.Before(expiring[j].Deadline)
+	})
+	return expiring, nil
+}
+
+// notifiedAt 明細の期限を通知した日時（未通知の場合はnil）
+func notifiedAt(item *entity.ReceiptItem, kind string) *time.Time {
+	if kind == entity.DeadlineReturn {
+		return item.ReturnNotifiedAt
+	}
+	return item.WarrantyNotifiedAt
+}
+
+// truncateDay 日付の0時に切り捨てる
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
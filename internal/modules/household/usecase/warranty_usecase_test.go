@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// recordingNotifier 送信した通知を記録する
+type recordingNotifier struct {
+	notifications []sharedDomain.Notification
+	err           error
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification sharedDomain.Notification) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.notifications = append(n.notifications, notification)
+	return nil
+}
+
+func TestWarrantyUseCase(t *testing.T) {
+	now := time.Date(2025, time.June, 10, 15, 0, 0, 0, time.Local)
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	}
+	months := func(n int) *int { return &n }
+	notified := now.AddDate(0, 0, -1)
+
+	newItems := func() []*entity.PurchasedItem {
+		return []*entity.PurchasedItem{
+			// 保証期限: 2025-06-15（既定の12か月）
+			{Item: entity.ReceiptItem{ID: "tv", Name: "テレビ", Price: 80000, Quantity: 1}, StoreName: "家電店", PurchaseDate: date(2024, time.June, 15)},
+			// 返品期限: 2025-06-12、保証期限: 2027-06-05（24か月）
+			{Item: entity.ReceiptItem{ID: "pc", Name: "ノートPC", Price: 150000, Quantity: 1, WarrantyMonths: months(24)}, StoreName: "家電店", PurchaseDate: date(2025, time.June, 5)},
+			// 単価は下限未満だが保証期間を設定: 保証期限 2025-06-11
+			{Item: entity.ReceiptItem{ID: "kettle", Name: "電気ケトル", Price: 3000, Quantity: 1, WarrantyMonths: months(6)}, StoreName: "雑貨店", PurchaseDate: date(2024, time.December, 11)},
+			// 保証なし・返品期限: 2025-06-09（期限切れ）
+			{Item: entity.ReceiptItem{ID: "chair", Name: "椅子", Price: 20000, Quantity: 1, WarrantyMonths: months(0)}, StoreName: "家具店", PurchaseDate: date(2025, time.June, 2)},
+			// 通知済み: 保証期限 2025-06-13
+			{Item: entity.ReceiptItem{ID: "camera", Name: "カメラ", Price: 60000, Quantity: 1, WarrantyNotifiedAt: &notified}, StoreName: "家電店", PurchaseDate: date(2024, time.June, 13)},
+		}
+	}
+	rules := WarrantyRules{MinAmount: 10000, DefaultMonths: 12, ReturnDays: 7, NotifyDays: 3}
+
+	t.Run("正常系: 期限の近い順に取得", func(t *testing.T) {
+		var gotMinPrice int64
+		mockReceipt := &MockReceiptRepository{
+			FindWarrantyItemsFunc: func(ctx context.Context, minPrice int64) ([]*entity.PurchasedItem, error) {
+				gotMinPrice = minPrice
+				return newItems(), nil
+			},
+		}
+		uc := NewWarrantyUseCase(mockReceipt, rules)
+		uc.now = func() time.Time { return now }
+
+		expiring, err := uc.ListExpiring(context.Background(), 30)
+		if err != nil {
+			t.Fatalf("ListExpiring() error = %v", err)
+		}
+		if gotMinPrice != 10000 {
+			t.Errorf("minPrice = %d, want 10000", gotMinPrice)
+		}
+
+		want := []struct {
+			id       string
+			kind     string
+			deadline time.Time
+		}{
+			{"kettle", entity.DeadlineWarranty, date(2025, time.June, 11)},
+			{"pc", entity.DeadlineReturn, date(2025, time.June, 12)},
+			{"camera", entity.DeadlineWarranty, date(2025, time.June, 13)},
+			{"tv", entity.DeadlineWarranty, date(2025, time.June, 15)},
+		}
+		if len(expiring) != len(want) {
+			t.Fatalf("got %d items, want %d: %+v", len(expiring), len(want), expiring)
+		}
+		for i, w := range want {
+			if expiring[i].Item.Item.ID != w.id || expiring[i].Kind != w.kind || !expiring[i].Deadline.Equal(w.deadline) {
+				t.Errorf("expiring[%d] = %s %s %v, want %s %s %v", i, expiring[i].Item.Item.ID, expiring[i].Kind, expiring[i].Deadline, w.id, w.kind, w.deadline)
+			}
+		}
+	})
+
+	t.Run("正常系: 未通知の明細をまとめて通知", func(t *testing.T) {
+		var marked []string
+		mockReceipt := &MockReceiptRepository{
+			FindWarrantyItemsFunc: func(ctx context.Context, minPrice int64) ([]*entity.PurchasedItem, error) {
+				return newItems(), nil
+			},
+			MarkItemNotifiedFunc: func(ctx context.Context, itemID, kind string, notifiedAt time.Time) error {
+				marked = append(marked, itemID+":"+kind)
+				return nil
+			},
+		}
+		notifier := &recordingNotifier{}
+		uc := NewWarrantyUseCase(mockReceipt, rules)
+		uc.SetNotifier(notifier)
+		uc.now = func() time.Time { return now }
+
+		count, err := uc.NotifyExpiring(context.Background())
+		if err != nil {
+			t.Fatalf("NotifyExpiring() error = %v", err)
+		}
+		if count != 2 || len(notifier.notifications) != 1 {
+			t.Fatalf("count = %d, notifications = %d, want 2 items in 1 notification", count, len(notifier.notifications))
+		}
+		if notifier.notifications[0].Event != NotificationEventWarrantyExpiring {
+			t.Errorf("Event = %s", notifier.notifications[0].Event)
+		}
+		if len(marked) != 2 || marked[0] != "kettle:warranty" || marked[1] != "pc:return" {
+			t.Errorf("marked = %v, want [kettle:warranty pc:return]", marked)
+		}
+	})
+
+	t.Run("異常系: 通知に失敗した場合は通知済みにしない", func(t *testing.T) {
+		mockReceipt := &MockReceiptRepository{
+			FindWarrantyItemsFunc: func(ctx context.Context, minPrice int64) ([]*entity.PurchasedItem, error) {
+				return newItems(), nil
+			},
+			MarkItemNotifiedFunc: func(ctx context.Context, itemID, kind string, notifiedAt time.Time) error {
+				t.Errorf("MarkItemNotified(%s, %s) called", itemID, kind)
+				return nil
+			},
+		}
+		uc := NewWarrantyUseCase(mockReceipt, rules)
+		uc.SetNotifier(&recordingNotifier{err: errors.New("webhook error")})
+		uc.now = func() time.Time { return now }
+
+		if _, err := uc.NotifyExpiring(context.Background()); err == nil {
+			t.Error("NotifyExpiring() error = nil, want error")
+		}
+	})
+
+	t.Run("正常系: 通知先が未設定の場合は何もしない", func(t *testing.T) {
+		uc := NewWarrantyUseCase(&MockReceiptRepository{}, rules)
+		if count, err := uc.NotifyExpiring(context.Background()); count != 0 || err != nil {
+			t.Errorf("NotifyExpiring() = %d, %v, want 0, nil", count, err)
+		}
+	})
+}
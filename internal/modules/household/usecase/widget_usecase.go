@@ -0,0 +1,319 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+)
+
+// ダッシュボードのウィジェットの指標
+const (
+	WidgetMetricAmount       = "amount"        // 明細の金額（単価×数量）の合計
+	WidgetMetricItemCount    = "item_count"    // 明細の件数
+	WidgetMetricReceiptCount = "receipt_count" // レシートの枚数
+	WidgetMetricAverage      = "average"       // レシート1枚あたりの金額（円未満は四捨五入）
+)
+
+// ダッシュボードのウィジェットの系列の分け方
+const (
+	WidgetGroupNone          = "none" // 系列を分けない
+	WidgetGroupCategory      = "category"
+	WidgetGroupStore         = "store"
+	WidgetGroupPaymentMethod = "payment_method"
+	WidgetGroupTag           = "tag" // 複数のタグを付けたレシートはタグごとの系列に含める
+)
+
+// WidgetPeriodAll 期間を区切らずに集計する（円グラフ向け）
+const WidgetPeriodAll = "all"
+
+// DefaultWidgetMaxSeries ウィジェットの系列数の既定値
+const DefaultWidgetMaxSeries = 10
+
+// widgetOthersSeries 上限を超えた系列・値のない項目をまとめる系列
+const widgetOthersSeries = entity.DefaultCategory
+
+// WidgetFilter ウィジェットの集計対象の絞り込み（空のフィールドは絞り込まない）
+type WidgetFilter struct {
+	Category      string // 明細のカテゴリー
+	StoreName     string
+	PaymentMethod string
+	Tag           string
+}
+
+// WidgetQuery ウィジェットの集計条件
+type WidgetQuery struct {
+	Metric        string
+	GroupBy       string
+	Period        string    // day / week / month / all
+	From          time.Time // 両端の日を含む
+	To            time.Time
+	MonthStartDay int // 0の場合は設定の開始日
+	Filter        WidgetFilter
+	MaxSeries     int // 金額の大きい順に残す系列数（残りは「その他」にまとめる）、0の場合は既定値
+}
+
+// WidgetPeriod ウィジェットの横軸の期間
+type WidgetPeriod struct {
+	Start time.Time
+	End   time.Time // この日時を含まない
+}
+
+// WidgetSeries ウィジェットの系列
+type WidgetSeries struct {
+	Name  string
+	Total int64   // 期間全体の値
+	Data  []int64 // 期間ごとの値（Periodsと同じ順）
+}
+
+// WidgetData グラフにそのまま渡せる形のウィジェットの集計結果
+type WidgetData struct {
+	Periods []WidgetPeriod
+	Series  []WidgetSeries // 期間全体の値の大きい順（「その他」は最後）
+}
+
+// widgetCell 系列・期間ごとの集計途中の値
+type widgetCell struct {
+	amount   int64
+	items    int
+	receipts int
+}
+
+// value 指標の値
+func (c *widgetCell) value(metric string) int64 {
+	switch metric {
+	case WidgetMetricItemCount:
+		return int64(c.items)
+	case WidgetMetricReceiptCount:
+		return int64(c.receipts)
+	case WidgetMetricAverage:
+		if c.receipts == 0 {
+			return 0
+		}
+		return (c.amount*2 + int64(c.receipts)) / (int64(c.receipts) * 2)
+	default:
+		return c.amount
+	}
+}
+
+// add 集計途中の値を足し合わせる
+func (c *widgetCell) add(other *widgetCell) {
+	c.amount += other.amount
+	c.items += other.items
+	c.receipts += other.receipts
+}
+
+// WidgetUseCase ダッシュボードのウィジェット向けに指標・系列・期間を指定してレシートを集計するユースケース
+// 系列に店名・支払い方法を使えるよう、家計簿エントリは含めずレシートの明細だけを集計する
+type WidgetUseCase struct {
+	householdUseCase *HouseholdUseCase
+}
+
+// NewWidgetUseCase 新しいWidgetUseCaseを作成
+func NewWidgetUseCase(householdUseCase *HouseholdUseCase) *WidgetUseCase {
+	return &WidgetUseCase{
+		householdUseCase: householdUseCase,
+	}
+}
+
+// GetWidgetData 条件に合うレシートの明細を期間・系列ごとに集計
+// 支出のない期間も含めて返す。不正な条件の場合はErrInvalidReportPeriodを返す
+func (uc *WidgetUseCase) GetWidgetData(ctx context.Context, query WidgetQuery) (*WidgetData, error) {
+	switch query.Metric {
+	case WidgetMetricAmount, WidgetMetricItemCount, WidgetMetricReceiptCount, WidgetMetricAverage:
+	default:
+		return nil, fmt.Errorf("%w: unknown metric %q", ErrInvalidReportPeriod, query.Metric)
+	}
+	keys, err := widgetKeyFunc(query.GroupBy)
+	if err != nil {
+		return nil, err
+	}
+	maxSeries := query.MaxSeries
+	if maxSeries <= 0 {
+		maxSeries = DefaultWidgetMaxSeries
+	}
+
+	var starts []time.Time
+	var end time.Time
+	if query.Period == WidgetPeriodAll {
+		// 期間全体を1つの期間として区切るため、日単位で区切った最初と最後の日時を使う
+		days, dayEnd, err := uc.householdUseCase.periodStarts(ctx, query.From, query.To, ReportGroupDay, query.MonthStartDay)
+		if err != nil {
+			return nil, err
+		}
+		starts, end = days[:1], dayEnd
+	} else {
+		starts, end, err = uc.householdUseCase.periodStarts(ctx, query.From, query.To, query.Period, query.MonthStartDay)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	receipts, err := uc.householdUseCase.receiptRepo.FindByDateRange(ctx, starts[0], end.Add(-time.Nanosecond))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receipts: %w", err)
+	}
+
+	cells := make(map[string][]widgetCell)
+	for _, receipt := range receipts {
+		if receipt.PurchaseDate.Before(starts[0]) || !receipt.PurchaseDate.Before(end) || !query.Filter.matchReceipt(receipt) {
+			continue
+		}
+		period := sort.Search(len(starts), func(i int) bool { return starts[i].After(receipt.PurchaseDate) }) - 1
+
+		// レシートの枚数は系列ごとに1枚として数える
+		counted := make(map[string]bool)
+		for _, item := range receipt.Items {
+			if query.Filter.Category != "" && widgetSeriesName(item.Category) != query.Filter.Category {
+				continue
+			}
+			for _, key := range keys(receipt, &item) {
+				if _, ok := cells[key]; !ok {
+					cells[key] = make([]widgetCell, len(starts))
+				}
+				cell := &cells[key][period]
+				cell.amount += int64(item.Price) * int64(item.Quantity)
+				cell.items++
+				if !counted[key] {
+					cell.receipts++
+					counted[key] = true
+				}
+			}
+		}
+	}
+
+	data := &WidgetData{Periods: make([]WidgetPeriod, len(starts))}
+	for i, start := range starts {
+		data.Periods[i] = WidgetPeriod{Start: start, End: end}
+		if i+1 < len(starts) {
+			data.Periods[i].End = starts[i+1]
+		}
+	}
+	data.Series = buildWidgetSeries(cells, len(starts), query.Metric, maxSeries)
+	return data, nil
+}
+
+// buildWidgetSeries 系列を期間全体の値の大きい順に並べ、maxSeriesを超えた系列を「その他」にまとめる
+// まとめた系列のレシートの枚数は、複数の系列に含まれるレシートを系列ごとに数えた合計になる
+func buildWidgetSeries(cells map[string][]widgetCell, periods int, metric string, maxSeries int) []WidgetSeries {
+	type total struct {
+		name string
+		cell widgetCell
+	}
+	totals := make([]total, 0, len(cells))
+	for name, row := range cells {
+		t := total{name: name}
+		for i := range row {
+			t.cell.add(&row[i])
+		}
+		totals = append(totals, t)
+	}
+	sort.Slice(totals, func(a, b int) bool {
+		va, vb := totals[a].cell.value(metric), totals[b].cell.value(metric)
+		if va != vb {
+			return va > vb
+		}
+		return totals[a].name < totals[b].name
+	})
+
+	// 「その他」を含めて系列数が上限を超える場合は、上限を超えた系列を「その他」にまとめて最後に置く
+	others := cells[widgetOthersSeries]
+	named := make([]string, 0, len(totals))
+	for _, t := range totals {
+		if t.name != widgetOthersSeries {
+			named = append(named, t.name)
+		}
+	}
+	kept := named
+	if others != nil && len(named) >= maxSeries || len(named) > maxSeries {
+		kept = named[:maxSeries-1]
+		merged := make([]widgetCell, periods)
+		for _, row := range append([][]widgetCell{others}, rowsOf(cells, named[maxSeries-1:])...) {
+			for i := range row {
+				merged[i].add(&row[i])
+			}
+		}
+		others = merged
+	}
+
+	series := make([]WidgetSeries, 0, len(kept)+1)
+	appendSeries := func(name string, row []widgetCell) {
+		s := WidgetSeries{Name: name, Data: make([]int64, periods)}
+		var sum widgetCell
+		for i := range row {
+			s.Data[i] = row[i].value(metric)
+			sum.add(&row[i])
+		}
+		s.Total = sum.value(metric)
+		series = append(series, s)
+	}
+	for _, name := range kept {
+		appendSeries(name, cells[name])
+	}
+	if others != nil {
+		appendSeries(widgetOthersSeries, others)
+	}
+	return series
+}
+
+// rowsOf 系列名の順に期間ごとの値を取得
+func rowsOf(cells map[string][]widgetCell, names []string) [][]widgetCell {
+	rows := make([][]widgetCell, 0, len(names))
+	for _, name := range names {
+		rows = append(rows, cells[name])
+	}
+	return rows
+}
+
+// widgetKeyFunc 系列の分け方から明細が含まれる系列名を返す関数を作成
+func widgetKeyFunc(groupBy string) (func(receipt *entity.Receipt, item *entity.ReceiptItem) []string, error) {
+	switch groupBy {
+	case "", WidgetGroupNone:
+		return func(*entity.Receipt, *entity.ReceiptItem) []string { return []string{"合計"} }, nil
+	case WidgetGroupCategory:
+		return func(_ *entity.Receipt, item *entity.ReceiptItem) []string {
+			return []string{widgetSeriesName(item.Category)}
+		}, nil
+	case WidgetGroupStore:
+		return func(receipt *entity.Receipt, _ *entity.ReceiptItem) []string {
+			return []string{widgetSeriesName(receipt.StoreName)}
+		}, nil
+	case WidgetGroupPaymentMethod:
+		return func(receipt *entity.Receipt, _ *entity.ReceiptItem) []string {
+			return []string{widgetSeriesName(receipt.PaymentMethod)}
+		}, nil
+	case WidgetGroupTag:
+		return func(receipt *entity.Receipt, _ *entity.ReceiptItem) []string {
+			if len(receipt.Tags) == 0 {
+				return []string{widgetOthersSeries}
+			}
+			return receipt.Tags
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown group_by %q", ErrInvalidReportPeriod, groupBy)
+	}
+}
+
+// matchReceipt レシートが絞り込みの条件に合うか判定（明細のカテゴリーは明細ごとに判定する）
+func (f WidgetFilter) matchReceipt(receipt *entity.Receipt) bool {
+	if f.StoreName != "" && receipt.StoreName != f.StoreName {
+		return false
+	}
+	if f.PaymentMethod != "" && receipt.PaymentMethod != f.PaymentMethod {
+		return false
+	}
+	if f.Tag != "" && !receipt.HasTag(f.Tag) {
+		return false
+	}
+	return true
+}
+
+// widgetSeriesName 空の値を「その他」として扱う
+func widgetSeriesName(value string) string {
+	if value == "" {
+		return widgetOthersSeries
+	}
+	return value
+}
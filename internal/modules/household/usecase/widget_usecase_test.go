@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+)
+
+func TestWidgetUseCase_GetWidgetData(t *testing.T) {
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2025, month, d, 12, 0, 0, 0, time.UTC)
+	}
+	receipts := []*entity.Receipt{
+		{ID: "r1", StoreName: "スーパーA", PaymentMethod: "現金", PurchaseDate: day(6, 2), Tags: []string{"旅行"}, Items: []entity.ReceiptItem{
+			{Name: "牛乳", Quantity: 1, Price: 200, Category: "食費"},
+			{Name: "洗剤", Quantity: 1, Price: 300, Category: "日用品"},
+		}},
+		{ID: "r2", StoreName: "スーパーA", PaymentMethod: "クレジットカード", PurchaseDate: day(6, 20), Items: []entity.ReceiptItem{
+			{Name: "パン", Quantity: 2, Price: 150, Category: "食費"},
+		}},
+		{ID: "r3", StoreName: "ドラッグストア", PaymentMethod: "現金", PurchaseDate: day(7, 3), Tags: []string{"旅行"}, Items: []entity.ReceiptItem{
+			{Name: "薬", Quantity: 1, Price: 1000, Category: "医療費"},
+			{Name: "菓子", Quantity: 1, Price: 101, Category: ""},
+		}},
+	}
+
+	tests := []struct {
+		name       string
+		query      WidgetQuery
+		wantStarts []string
+		wantSeries []WidgetSeries
+		wantErr    bool
+	}{
+		{
+			name:       "正常系: 月ごとの金額をカテゴリー別の系列で返す",
+			query:      WidgetQuery{Metric: WidgetMetricAmount, GroupBy: WidgetGroupCategory, Period: ReportGroupMonth, From: day(6, 1), To: day(7, 31)},
+			wantStarts: []string{"2025-06-01", "2025-07-01"},
+			wantSeries: []WidgetSeries{
+				{Name: "医療費", Total: 1000, Data: []int64{0, 1000}},
+				{Name: "食費", Total: 500, Data: []int64{500, 0}},
+				{Name: "日用品", Total: 300, Data: []int64{300, 0}},
+				{Name: "その他", Total: 101, Data: []int64{0, 101}},
+			},
+		},
+		{
+			name:       "正常系: 系列数の上限を超えた系列は「その他」にまとめる",
+			query:      WidgetQuery{Metric: WidgetMetricAmount, GroupBy: WidgetGroupCategory, Period: WidgetPeriodAll, From: day(6, 1), To: day(7, 31), MaxSeries: 2},
+			wantStarts: []string{"2025-06-01"},
+			wantSeries: []WidgetSeries{
+				{Name: "医療費", Total: 1000, Data: []int64{1000}},
+				{Name: "その他", Total: 901, Data: []int64{901}},
+			},
+		},
+		{
+			name:       "正常系: 店ごとのレシート1枚あたりの金額",
+			query:      WidgetQuery{Metric: WidgetMetricAverage, GroupBy: WidgetGroupStore, Period: WidgetPeriodAll, From: day(6, 1), To: day(7, 31)},
+			wantStarts: []string{"2025-06-01"},
+			wantSeries: []WidgetSeries{
+				{Name: "ドラッグストア", Total: 1101, Data: []int64{1101}},
+				{Name: "スーパーA", Total: 400, Data: []int64{400}},
+			},
+		},
+		{
+			name: "正常系: タグと支払い方法で絞り込んだレシートの枚数",
+			query: WidgetQuery{
+				Metric: WidgetMetricReceiptCount, GroupBy: WidgetGroupNone, Period: ReportGroupMonth, From: day(6, 1), To: day(7, 31),
+				Filter: WidgetFilter{Tag: "旅行", PaymentMethod: "現金"},
+			},
+			wantStarts: []string{"2025-06-01", "2025-07-01"},
+			wantSeries: []WidgetSeries{{Name: "合計", Total: 2, Data: []int64{1, 1}}},
+		},
+		{
+			name: "正常系: カテゴリーで絞り込んだ明細の件数を支払い方法別の系列で返す",
+			query: WidgetQuery{
+				Metric: WidgetMetricItemCount, GroupBy: WidgetGroupPaymentMethod, Period: WidgetPeriodAll, From: day(6, 1), To: day(7, 31),
+				Filter: WidgetFilter{Category: "食費"},
+			},
+			wantStarts: []string{"2025-06-01"},
+			wantSeries: []WidgetSeries{
+				{Name: "クレジットカード", Total: 1, Data: []int64{1}},
+				{Name: "現金", Total: 1, Data: []int64{1}},
+			},
+		},
+		{
+			name:    "異常系: 不明な指標",
+			query:   WidgetQuery{Metric: "profit", Period: ReportGroupMonth, From: day(6, 1), To: day(7, 31)},
+			wantErr: true,
+		},
+		{
+			name:    "異常系: 不明な系列の分け方",
+			query:   WidgetQuery{Metric: WidgetMetricAmount, GroupBy: "weekday", Period: ReportGroupMonth, From: day(6, 1), To: day(7, 31)},
+			wantErr: true,
+		},
+		{
+			name:    "異常系: 不明な期間",
+			query:   WidgetQuery{Metric: WidgetMetricAmount, Period: "year", From: day(6, 1), To: day(7, 31)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockReceipt := &MockReceiptRepository{
+				FindByDateRangeFunc: func(ctx context.Context, start, end time.Time) ([]*entity.Receipt, error) {
+					return receipts, nil
+				},
+			}
+			householdUseCase := NewHouseholdUseCase(mockReceipt, &MockExpenseRepository{})
+			householdUseCase.SetMonthStartDay(1)
+			uc := NewWidgetUseCase(householdUseCase)
+
+			data, err := uc.GetWidgetData(context.Background(), tt.query)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidReportPeriod) {
+					t.Errorf("GetWidgetData() error = %v, want ErrInvalidReportPeriod", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetWidgetData() error = %v", err)
+			}
+
+			starts := make([]string, 0, len(data.Periods))
+			for _, period := range data.Periods {
+				starts = append(starts, period.Start.Format(time.DateOnly))
+			}
+			if !reflect.DeepEqual(starts, tt.wantStarts) {
+				t.Errorf("starts = %v, want %v", starts, tt.wantStarts)
+			}
+			if !reflect.DeepEqual(data.Series, tt.wantSeries) {
+				t.Errorf("series = %+v, want %+v", data.Series, tt.wantSeries)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+// AI APIの予算超過時の縮退の手動切り替え
+const (
+	AIBudgetOverrideAuto      = "auto"      // 当月の利用料金が予算を超えた場合だけ縮退する（既定）
+	AIBudgetOverrideNormal    = "normal"    // 予算を超えても縮退しない
+	AIBudgetOverrideDowngrade = "downgrade" // 予算に関わらず縮退する
+)
+
+// AI APIの予算超過時の重要でない処理（カテゴリーの判定）の縮退の方法
+const (
+	AIDowngradeCheapModel = "cheap_model" // 安いモデルで判定する
+	AIDowngradeRules      = "rules"       // AIを使わずキーワードのルールだけで判定する
+)
+
+// AIBudgetEventExceeded 当月のAI APIの利用料金が予算を超えた場合の通知の種類
+const AIBudgetEventExceeded = "ai_budget.exceeded"
+
+// ErrInvalidAIBudgetOverride 縮退の手動切り替えの値が不正
+var ErrInvalidAIBudgetOverride = errors.New("invalid ai budget override")
+
+// AIModelUsage モデルごとの当月のAI APIの利用量
+type AIModelUsage struct {
+	Requests     int     `json:"requests"`
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	CostUSD      float64 `json:"cost_usd"`
+}
+
+// AIBudgetStatus 当月のAI APIの利用料金と縮退の状態
+type AIBudgetStatus struct {
+	Month      string                  `json:"month"` // 集計している月（YYYY-MM）
+	BudgetUSD  float64                 `json:"budget_usd"`
+	CostUSD    float64                 `json:"cost_usd"`
+	Exceeded   bool                    `json:"exceeded"`              // 当月の利用料金が予算を超えたか
+	Override   string                  `json:"override"`              // 縮退の手動切り替え（auto・normal・downgrade）
+	Downgraded bool                    `json:"downgraded"`            // 重要でない処理を縮退しているか
+	Downgrade  string                  `json:"downgrade"`             // 縮退の方法（cheap_model・rules）
+	CheapModel string                  `json:"cheap_model,omitempty"` // cheap_modelで使うモデル
+	Models     map[string]AIModelUsage `json:"models"`                // モデルごとの利用量
+}
+
+// AIBudget AI APIの利用料金の月ごとの記録と、予算超過時の縮退の状態
+type AIBudget interface {
+	// Status 当月の利用料金と縮退の状態を返す
+	Status(ctx context.Context) (AIBudgetStatus, error)
+	// SetOverride 縮退を手動で切り替える（不正な値の場合はErrInvalidAIBudgetOverride）
+	SetOverride(ctx context.Context, override string) error
+}
+
+// AIBudgetLedger 月ごとのAI APIの利用量と縮退の手動切り替えの保存先
+// 再起動しても当月の利用料金が0に戻らず、すべてのインスタンスで1つの予算を共有できるよう、Redisなどの共有の保存先に置く
+type AIBudgetLedger interface {
+	// Add 月（YYYY-MM）のモデルの利用量を加算し、加算後の月の利用料金の合計を返す
+	Add(ctx context.Context, month, model string, usage AIModelUsage) (float64, error)
+	// Usage 月のモデルごとの利用量と利用料金の合計を返す
+	Usage(ctx context.Context, month string) (map[string]AIModelUsage, float64, error)
+	// MarkNotified 月の予算超過の通知を記録し、初めて記録した場合だけtrueを返す（インスタンス間で1回だけ通知するため）
+	MarkNotified(ctx context.Context, month string) (bool, error)
+	// Override 縮退の手動切り替えを返す（未設定の場合はAIBudgetOverrideAuto）
+	Override(ctx context.Context) (string, error)
+	// SetOverride 縮退の手動切り替えを保存する（月が変わっても維持する）
+	SetOverride(ctx context.Context, override string) error
+}
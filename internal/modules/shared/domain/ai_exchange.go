@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AIExchange デバッグ用に記録したAI APIへのリクエストとレスポンス
+type AIExchange struct {
+	At         time.Time       `json:"at"`
+	Operation  string          `json:"operation"` // 処理の種類（例: recognize_receipt）
+	Model      string          `json:"model"`
+	Request    json.RawMessage `json:"request"`               // 送信したリクエストボディ（画像データは省略）
+	StatusCode int             `json:"status_code,omitempty"` // APIのステータスコード（送信に失敗した場合は0）
+	Response   string          `json:"response,omitempty"`    // 受信したレスポンスボディ
+	Error      string          `json:"error,omitempty"`
+	DurationMs int64           `json:"duration_ms"`
+}
+
+// AIExchangeLog AI APIへのリクエストとレスポンスのデバッグ用の記録先のインターフェース
+type AIExchangeLog interface {
+	// Record リクエストとレスポンスを記録する
+	Record(exchange AIExchange)
+	// Recent 新しい順に最大limit件の記録を返す（limitが0以下の場合はすべて）
+	Recent(limit int) []AIExchange
+	// Clear 記録をすべて削除する
+	Clear()
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// キャッシュの利用状況を集計する単位の既定値
+const (
+	CacheEndpointBackground = "(background)" // リクエストによらない処理（ジョブ・定期実行など）
+	CacheTenantDefault      = "default"      // テナントの指定がないリクエスト
+	CacheTenantOther        = "(other)"      // 集計する組み合わせの上限を超えたテナント
+)
+
+// CacheScope キャッシュの利用状況を集計する単位
+type CacheScope struct {
+	Endpoint string // ルーティングのパターン（例: GET /api/v1/reports/monthly）
+	Tenant   string // リクエストのテナント
+}
+
+type cacheScopeKey struct{}
+
+type cacheStatsDisabledKey struct{}
+
+// WithCacheScope キャッシュの利用状況を集計する単位をコンテキストに設定
+func WithCacheScope(ctx context.Context, scope CacheScope) context.Context {
+	return context.WithValue(ctx, cacheScopeKey{}, scope)
+}
+
+// CacheScopeFromContext コンテキストの集計単位を取得（未設定の場合はリクエストによらない処理）
+func CacheScopeFromContext(ctx context.Context) CacheScope {
+	if scope, ok := ctx.Value(cacheScopeKey{}).(CacheScope); ok {
+		return scope
+	}
+	return CacheScope{Endpoint: CacheEndpointBackground, Tenant: CacheTenantDefault}
+}
+
+// WithoutCacheStats キャッシュの利用状況に含めない内部の読み書き（キャッシュの世代の管理など）のコンテキスト
+func WithoutCacheStats(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheStatsDisabledKey{}, true)
+}
+
+// CacheStatsDisabled キャッシュの利用状況に含めない読み書きかチェック
+func CacheStatsDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(cacheStatsDisabledKey{}).(bool)
+	return disabled
+}
+
+// CacheUsage 集計単位ごとのキャッシュの利用状況
+type CacheUsage struct {
+	Endpoint     string  `json:"endpoint,omitempty"`
+	Tenant       string  `json:"tenant,omitempty"`
+	Hits         int64   `json:"hits"`
+	Misses       int64   `json:"misses"`
+	Errors       int64   `json:"errors"` // 取得に失敗した回数（障害でキャッシュの利用を停止している間を含む）
+	HitRate      float64 `json:"hit_rate"`
+	Sets         int64   `json:"sets"`
+	BytesWritten int64   `json:"bytes_written"`
+}
+
+// CacheStats 起動してからのキャッシュの利用状況
+type CacheStats struct {
+	Since  time.Time    `json:"since"`
+	Total  CacheUsage   `json:"total"`
+	Scopes []CacheUsage `json:"scopes"` // エンドポイント・テナントの順に並べる
+}
+
+// CacheKeyspace SCANで標本を取ったキャッシュのキーの件数・使用メモリ
+// 標本がすべてのキーでない場合は、標本の割合から全体を推定する
+type CacheKeyspace struct {
+	TotalKeys   int64                 `json:"total_keys"`
+	SampledKeys int                   `json:"sampled_keys"`
+	Exact       bool                  `json:"exact"` // すべてのキーを調べた（推定していない）
+	Prefixes    []CacheKeyspacePrefix `json:"prefixes"`
+}
+
+// CacheKeyspacePrefix キーの接頭辞ごとの件数・使用メモリ
+type CacheKeyspacePrefix struct {
+	Prefix        string `json:"prefix"`
+	Keys          int64  `json:"keys"`
+	Bytes         int64  `json:"bytes"`
+	NoExpiry      int64  `json:"no_expiry"`       // 有効期限のないキーの数
+	AvgTTLSeconds int64  `json:"avg_ttl_seconds"` // 有効期限のあるキーの残りの有効期限の平均
+}
+
+// CacheInspector キャッシュの利用状況の集計とキーの調査のインターフェース
+type CacheInspector interface {
+	// CacheStats 起動してからのエンドポイント・テナントごとのキャッシュの利用状況を返す
+	CacheStats() CacheStats
+	// SampleKeyspace 最大limit件のキーを調べ、接頭辞ごとのキーの件数・使用メモリを返す
+	SampleKeyspace(ctx context.Context, limit int) (*CacheKeyspace, error)
+}
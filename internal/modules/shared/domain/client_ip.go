@@ -0,0 +1,16 @@
+package domain
+
+import "context"
+
+type clientIPKey struct{}
+
+// WithClientIP リクエストの送信元のIPアドレス（信頼するプロキシを除いたもの）をコンテキストに設定
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// ClientIPFromContext コンテキストの送信元のIPアドレスを取得（リクエストによらない処理の場合は空）
+func ClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// Clock 現在時刻の取得元
+// 作成日時・更新日時・購入日の既定値などに使い、テストでは固定した時刻に差し替える
+type Clock interface {
+	// Now 現在時刻を返す
+	Now() time.Time
+}
+
+// SystemClock システムの時計（time.Now）
+type SystemClock struct{}
+
+// Now 現在時刻を返す
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock 常に同じ時刻を返す時計（テスト・過去の日時での登録に使う）
+type FixedClock time.Time
+
+// Now 固定した時刻を返す
+func (c FixedClock) Now() time.Time {
+	return time.Time(c)
+}
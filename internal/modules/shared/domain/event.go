@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// DomainEvent エンティティで起きた出来事
+// エンティティが変更時に記録し、ユースケースが保存後にEventPublisherで配信する
+type DomainEvent interface {
+	// EventName イベントの種類（例: receipt.created）
+	EventName() string
+	// OccurredAt イベントが起きた日時
+	OccurredAt() time.Time
+}
+
+// EventPublisher ドメインイベントの配信先のインターフェース
+// Webhook・通知・監査ログなどへの連携は配信先の購読者で行う
+type EventPublisher interface {
+	// Publish イベントを配信する（保存済みの変更に対して呼ぶため、購読者の失敗は呼び出し元に返さない）
+	Publish(ctx context.Context, events ...DomainEvent)
+}
+
+// Events 保存するまでドメインイベントを溜めておく（エンティティに埋め込んで使う）
+// 公開フィールドを持たないため、エンティティをJSONにしてもイベントは含まれない
+type Events struct {
+	pending []DomainEvent
+}
+
+// Record イベントを記録
+func (e *Events) Record(event DomainEvent) {
+	e.pending = append(e.pending, event)
+}
+
+// PullEvents 記録したイベントを取り出し、記録を空にする
+func (e *Events) PullEvents() []DomainEvent {
+	events := e.pending
+	e.pending = nil
+	return events
+}
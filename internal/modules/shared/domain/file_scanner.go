@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrFileInfected アップロードされたファイルからウイルス・マルウェアが検出された
+var ErrFileInfected = errors.New("infected file")
+
+// FileScanner アップロードされたファイルのウイルス・マルウェア検査のインターフェース
+type FileScanner interface {
+	// Scan ファイルを検査する
+	// 検出した場合はErrFileInfectedをラップしたエラー、検査自体に失敗した場合はそれ以外のエラーを返す
+	Scan(ctx context.Context, data []byte) error
+}
@@ -0,0 +1,9 @@
+package domain
+
+import "context"
+
+// HealthChecker 接続先の稼働状態を確認できる依存先（データベースなど）
+type HealthChecker interface {
+	// Ping 接続先に到達できない場合はエラーを返す
+	Ping(ctx context.Context) error
+}
@@ -0,0 +1,13 @@
+package domain
+
+// IDGenerator レシート・明細・家計簿エントリなどの識別子の生成方式
+// 生成する識別子はいずれも36文字以内で、既存のVARCHAR(36)の列に保存できる
+type IDGenerator interface {
+	// NewID 新しい識別子を生成
+	// seedは決定的な方式でのみ使用し、同じseedからは同じ識別子を返す（nilの場合はランダム）
+	NewID(seed []byte) string
+
+	// ChildID 親に紐づく識別子を生成（レシートの明細など、indexは親の中での順番）
+	// seedは決定的な方式でのみ使用し、同じ親・順番・seedからは同じ識別子を返す
+	ChildID(parentID string, index int, seed []byte) string
+}
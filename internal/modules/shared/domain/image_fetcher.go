@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrImageURLNotAllowed 許可されていないURL（スキーム・ホスト・接続先のIPアドレス）の画像が指定された
+	ErrImageURLNotAllowed = errors.New("image url not allowed")
+	// ErrImageTooLarge 取得した画像がサイズの上限を超えた
+	ErrImageTooLarge = errors.New("image too large")
+)
+
+// ImageFetcher URLで指定された画像（電子レシートのリンク・クラウドの写真の共有リンクなど）を取得するインターフェース
+type ImageFetcher interface {
+	// Fetch 画像を取得する
+	// 許可されていないURLの場合はErrImageURLNotAllowed、上限を超える場合はErrImageTooLargeをラップしたエラーを返す
+	Fetch(ctx context.Context, rawURL string) ([]byte, error)
+}
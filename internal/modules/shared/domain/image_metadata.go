@@ -0,0 +1,7 @@
+package domain
+
+// MetadataStripper 画像から撮影位置などのメタデータを取り除くインターフェース
+type MetadataStripper interface {
+	// StripMetadata メタデータを除いた画像を返す（メタデータを持たない形式はそのまま返す）
+	StripMetadata(data []byte) ([]byte, error)
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrImageNotFound 保存された画像が存在しない
+	ErrImageNotFound = errors.New("image not found")
+
+	// ErrStorageQuotaExceeded 画像の保存容量の上限を超える
+	ErrStorageQuotaExceeded = errors.New("storage quota exceeded")
+)
+
+// ImageStorage アップロード画像の保存先のインターフェース
+type ImageStorage interface {
+	// Save 画像をキーに対応付けて保存（既存の画像は上書き）
+	Save(ctx context.Context, key string, data []byte) error
+
+	// Load 画像を取得（存在しない場合はErrImageNotFound）
+	Load(ctx context.Context, key string) ([]byte, error)
+
+	// Delete 画像を削除（存在しない場合もエラーにしない）
+	Delete(ctx context.Context, key string) error
+
+	// ListBefore 指定時刻より前に保存された画像のキー一覧を取得
+	ListBefore(ctx context.Context, before time.Time) ([]string, error)
+}
+
+// StorageUsage 画像の保存容量の使用状況
+type StorageUsage struct {
+	UsedBytes  int64 // 保存済みの画像の合計サイズ
+	QuotaBytes int64 // 保存容量の上限（0は無制限）
+	Images     int   // 保存済みの画像数
+}
+
+// Exceeds 指定したサイズの画像を追加で保存すると上限を超えるか
+func (u StorageUsage) Exceeds(size int64) bool {
+	return u.QuotaBytes > 0 && u.UsedBytes+size > u.QuotaBytes
+}
+
+// StorageUsageReporter 保存容量の使用状況を返せる画像の保存先
+type StorageUsageReporter interface {
+	// Usage 現在の使用状況を取得
+	Usage(ctx context.Context) (StorageUsage, error)
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrQueueClosed クローズ済みのキューへの投入エラー
+var ErrQueueClosed = errors.New("job queue is closed")
+
+// Job 非同期ジョブ
+// 別プロセスのキューバックエンドでも扱えるよう、処理内容は種別とペイロードで表現する
+type Job struct {
+	ID         string
+	Type       string
+	Payload    []byte
+	EnqueuedAt time.Time
+}
+
+// JobHandler ジョブ種別ごとの処理関数
+type JobHandler func(ctx context.Context, job *Job) error
+
+// JobQueue 非同期ジョブキューのインターフェース
+type JobQueue interface {
+	// Register ジョブ種別に対する処理関数を登録
+	Register(jobType string, handler JobHandler)
+
+	// Start ジョブの受信を開始（処理関数をすべて登録してから呼び出す）
+	Start()
+
+	// Enqueue ジョブを投入
+	Enqueue(ctx context.Context, jobType string, payload []byte) error
+
+	// Flush 投入済みジョブがすべて完了するまで待機
+	Flush(ctx context.Context) error
+
+	// Close 新規投入を停止し、投入済みジョブの完了を待ってワーカーを終了
+	Close(ctx context.Context) error
+}
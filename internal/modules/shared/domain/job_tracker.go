@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrJobNotFound 追跡しているジョブが存在しない（保持期間を過ぎたものを含む）
+var ErrJobNotFound = errors.New("tracked job not found")
+
+// 追跡するジョブの状態
+const (
+	JobStateRunning   = "running"   // 処理中
+	JobStateCompleted = "completed" // 完了（一部の失敗を含む）
+	JobStateFailed    = "failed"    // 全体が失敗
+)
+
+// JobStatus 完了まで時間のかかるジョブの進み具合
+// 処理の途中の状態はジョブの種類ごとの形式でDataに保存する
+type JobStatus struct {
+	ID        string
+	Type      string
+	State     string // JobStateRunning・JobStateCompleted・JobStateFailed
+	Total     int    // 処理する件数
+	Succeeded int    // 成功した件数
+	Failed    int    // 失敗した件数
+	Error     string // 全体が失敗した理由
+	Data      []byte
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Done 処理が終わったか（完了または失敗）
+func (s *JobStatus) Done() bool {
+	return s.State != JobStateRunning
+}
+
+// JobTracker 外部サービスの完了を待つジョブなど、1回のジョブの実行で終わらない処理の進み具合の記録先
+// どのインスタンスからも状態を確認・更新できるよう、ジョブキューと同じバックエンドに保存する
+type JobTracker interface {
+	// Save 状態を保存（同じIDの状態は上書き）
+	Save(ctx context.Context, status *JobStatus) error
+
+	// Get 状態を取得（存在しない場合はErrJobNotFound）
+	Get(ctx context.Context, id string) (*JobStatus, error)
+
+	// ListRunning 処理中のジョブを作成日時の古い順に取得
+	ListRunning(ctx context.Context, jobType string) ([]*JobStatus, error)
+}
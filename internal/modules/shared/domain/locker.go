@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrLockNotAcquired 他のインスタンスがロックを保持している
+var ErrLockNotAcquired = errors.New("lock is held by another instance")
+
+// Locker 複数インスタンス間で排他制御する分散ロックのインターフェース
+type Locker interface {
+	// Acquire ロックを取得（取得できない場合はErrLockNotAcquired）
+	// ttlを過ぎるとロックは自動的に解放される
+	Acquire(ctx context.Context, name string, ttl time.Duration) (Lock, error)
+}
+
+// Lock 取得済みのロック
+type Lock interface {
+	// Refresh ロックの有効期限を延長
+	Refresh(ctx context.Context, ttl time.Duration) error
+
+	// Release ロックを解放（他のインスタンスが取得済みの場合は何もしない）
+	Release(ctx context.Context) error
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "context"
+
+// Messenger チャットサービス（LINEなど）のメッセージAPIのインターフェース
+type Messenger interface {
+	// DownloadContent 受信したメッセージの画像などのコンテンツを取得する
+	DownloadContent(ctx context.Context, messageID string) ([]byte, error)
+
+	// Reply 受信したメッセージにテキストで返信する（返信用トークンは1回のみ使える）
+	Reply(ctx context.Context, replyToken string, texts ...string) error
+}
@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Currency 金額の通貨
+// 金額は通貨の最小単位（円・セントなど）の整数で保存し、表示・入出力のときだけ小数に変換する
+type Currency struct {
+	Code   string // ISO 4217の通貨コード
+	Digits int    // 最小単位の小数点以下の桁数（JPY: 0、USD: 2）
+	Symbol string // 表示に使う記号
+}
+
+// DefaultCurrency 通貨の既定値
+var DefaultCurrency = Currency{Code: "JPY", Digits: 0, Symbol: "¥"}
+
+// currencies 対応する通貨
+var currencies = map[string]Currency{
+	"JPY": DefaultCurrency,
+	"KRW": {Code: "KRW", Digits: 0, Symbol: "₩"},
+	"USD": {Code: "USD", Digits: 2, Symbol: "$"},
+	"EUR": {Code: "EUR", Digits: 2, Symbol: "€"},
+	"GBP": {Code: "GBP", Digits: 2, Symbol: "£"},
+	"CNY": {Code: "CNY", Digits: 2, Symbol: "CN¥"},
+	"TWD": {Code: "TWD", Digits: 2, Symbol: "NT$"},
+	"HKD": {Code: "HKD", Digits: 2, Symbol: "HK$"},
+	"SGD": {Code: "SGD", Digits: 2, Symbol: "S$"},
+	"AUD": {Code: "AUD", Digits: 2, Symbol: "A$"},
+	"CAD": {Code: "CAD", Digits: 2, Symbol: "CA$"},
+	"CHF": {Code: "CHF", Digits: 2, Symbol: "CHF "},
+	"THB": {Code: "THB", Digits: 2, Symbol: "฿"},
+	"KWD": {Code: "KWD", Digits: 3, Symbol: "KD "},
+	"BHD": {Code: "BHD", Digits: 3, Symbol: "BD "},
+}
+
+// LookupCurrency 通貨コードから通貨を取得（空の場合は既定値）
+func LookupCurrency(code string) (Currency, error) {
+	if code == "" {
+		return DefaultCurrency, nil
+	}
+	currency, ok := currencies[strings.ToUpper(code)]
+	if !ok {
+		return Currency{}, fmt.Errorf("unsupported currency %q", code)
+	}
+	return currency, nil
+}
+
+// Scale 1単位あたりの最小単位の数（JPY: 1、USD: 100）
+func (c Currency) Scale() int64 {
+	scale := int64(1)
+	for range c.Digits {
+		scale *= 10
+	}
+	return scale
+}
+
+// Format 最小単位の金額を区切りなしの小数で表す（例: USDの1299は "12.99"、JPYの1500は "1500"）
+// CSV・仕訳など機械で読み込む出力に使う
+func (c Currency) Format(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+	}
+	abs := new(big.Int).Abs(big.NewInt(amount)).String()
+	if c.Digits == 0 {
+		return sign + abs
+	}
+	if len(abs) <= c.Digits {
+		abs = strings.Repeat("0", c.Digits-len(abs)+1) + abs
+	}
+	return sign + abs[:len(abs)-c.Digits] + "." + abs[len(abs)-c.Digits:]
+}
+
+// Display 最小単位の金額を記号と3桁区切りで表す（例: USDの129900は "$1,299.00"、JPYの1500は "¥1,500"）
+func (c Currency) Display(amount int64) string {
+	formatted := c.Format(amount)
+	sign := ""
+	if strings.HasPrefix(formatted, "-") {
+		sign, formatted = "-", formatted[1:]
+	}
+	integer, fraction, hasFraction := strings.Cut(formatted, ".")
+
+	var b strings.Builder
+	for i, r := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	if hasFraction {
+		b.WriteString("." + fraction)
+	}
+	return sign + c.Symbol + b.String()
+}
+
+// Parse 小数の金額（例: "12.99"、"1,234"、"-0.5"）を最小単位の整数に変換
+// 最小単位より細かい端数は四捨五入する
+func (c Currency) Parse(value string) (int64, error) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), ",", "")
+	if value == "" {
+		return 0, nil
+	}
+	rat, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	rat.Mul(rat, new(big.Rat).SetInt64(c.Scale()))
+
+	// FloatStringは指定した桁数で四捨五入する
+	rounded, err := strconv.ParseInt(rat.FloatString(0), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("amount out of range %q: %w", value, err)
+	}
+	return rounded, nil
+}
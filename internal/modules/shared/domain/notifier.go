@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Notification 外部へ送信する通知
+type Notification struct {
+	Event     string      `json:"event"`   // 通知の種類（例: warranty.expiring）
+	Message   string      `json:"message"` // 人が読むためのメッセージ
+	Data      interface{} `json:"data,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// Notifier 通知の送信先のインターフェース
+type Notifier interface {
+	// Notify 通知を送信する
+	Notify(ctx context.Context, notification Notification) error
+}
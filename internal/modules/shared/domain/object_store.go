@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrObjectNotFound オブジェクトストレージにキーのオブジェクトが存在しない
+var ErrObjectNotFound = errors.New("object not found")
+
+// ObjectStore 分析用のファイルなどを保存するオブジェクトストレージ（S3互換のバケット・ローカルディレクトリ）のインターフェース
+// キーは "/" 区切りのパス（例: receipts/year=2025/month=03/part-0.parquet）
+type ObjectStore interface {
+	// Put キーに対応付けてオブジェクトを保存（既存のオブジェクトは上書き）
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+
+	// Get オブジェクトを取得（存在しない場合はErrObjectNotFound）
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// DirectUploadStore クライアントが署名付きURLで直接オブジェクトをアップロードできるオブジェクトストレージ（S3互換のバケット）
+type DirectUploadStore interface {
+	ObjectStore
+
+	// PresignPut キーのオブジェクトをPUTでアップロードできる、有効期間付きの署名付きURLを発行
+	PresignPut(key string, expires time.Duration) (string, error)
+
+	// Delete オブジェクトを削除（存在しない場合もエラーにしない）
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrSecretNotFound 指定した名前の秘密情報が登録されていない
+var ErrSecretNotFound = errors.New("secret not found")
+
+// SecretProvider 外部サービスの認証情報などの秘密情報の取得元のインターフェース
+type SecretProvider interface {
+	// Secret 名前（例: freee_client_id）で秘密情報を取得する（未登録の場合は ErrSecretNotFound）
+	Secret(ctx context.Context, name string) (string, error)
+}
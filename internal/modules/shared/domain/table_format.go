@@ -0,0 +1,44 @@
+package domain
+
+import "io"
+
+// ColumnType 分析用の表の列の型
+type ColumnType string
+
+// 分析用の表の列の型（Writeに渡す値の型）
+const (
+	ColumnString    ColumnType = "string"    // string
+	ColumnInt64     ColumnType = "int64"     // int64
+	ColumnDouble    ColumnType = "double"    // float64
+	ColumnBool      ColumnType = "bool"      // bool
+	ColumnTimestamp ColumnType = "timestamp" // time.Time（UTCのミリ秒で保存する）
+	ColumnDate      ColumnType = "date"      // time.Time（time.Timeのタイムゾーンでの暦日を保存する）
+)
+
+// TableColumn 分析用の表の列
+type TableColumn struct {
+	Name     string     `json:"name"`
+	Type     ColumnType `json:"type"`
+	Optional bool       `json:"optional"` // nullを許す
+}
+
+// TableFormat 分析用の表形式のファイル（Parquetなど）の書き出しのインターフェース
+type TableFormat interface {
+	// Extension ファイルの拡張子（例: ".parquet"）
+	Extension() string
+
+	// ContentType ファイルのMIMEタイプ
+	ContentType() string
+
+	// NewWriter wに列の定義どおりの表を書き出すTableWriterを作成（metadataはファイルに埋め込む付加情報）
+	NewWriter(w io.Writer, columns []TableColumn, metadata map[string]string) TableWriter
+}
+
+// TableWriter 表の行を書き出すライター
+type TableWriter interface {
+	// Write 1行を書き出す（値は列の順で、Optionalの列はnilでnullにする）
+	Write(row []any) error
+
+	// Close 残りの行とファイルの末尾を書き出す（書き出したファイルはCloseまで読めない）
+	Close() error
+}
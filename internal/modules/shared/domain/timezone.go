@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// locationKey コンテキストにタイムゾーンを保持するキー
+type locationKey struct{}
+
+// WithLocation 日付の解釈・集計に使うタイムゾーンをコンテキストに設定する
+func WithLocation(ctx context.Context, loc *time.Location) context.Context {
+	return context.WithValue(ctx, locationKey{}, loc)
+}
+
+// LocationFromContext コンテキストのタイムゾーンを取得（未設定の場合はサーバーのタイムゾーン）
+// 購入日の解釈、期間の検索条件、月別・日別の集計はこのタイムゾーンの暦日で行う
+func LocationFromContext(ctx context.Context) *time.Location {
+	if loc, ok := ctx.Value(locationKey{}).(*time.Location); ok && loc != nil {
+		return loc
+	}
+	return time.Local
+}
@@ -0,0 +1,202 @@
+package accounting
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+// DefaultTimeout 会計サービスのAPI呼び出しのデフォルトのタイムアウト
+const DefaultTimeout = 30 * time.Second
+
+// Options 会計サービスの接続先と勘定科目の対応付け
+type Options struct {
+	BaseURL        string            // APIのURL
+	TokenURL       string            // OAuth2のトークンエンドポイント
+	CompanyID      string            // 事業所ID（freeeのみ）
+	Accounts       map[string]string // カテゴリーごとの費用の勘定科目ID
+	DefaultAccount string            // 対応付けのないカテゴリーの勘定科目ID
+	PaymentAccount string            // 支払元（貸方）の勘定科目ID（マネーフォワードのみ）
+	TaxCode        string            // 税区分コード（freeeのみ）
+	Timeout        time.Duration
+}
+
+// Client 会計サービスのREST APIクライアント
+// 取引の版は、会計サービスが返した取引の内容（JSON）のハッシュで表す
+type Client struct {
+	provider string
+	path     string // 取引のコレクションのパス
+	key      string // 応答で取引を包むキー
+	query    url.Values
+	payload  func(deal *entity.AccountingDeal) interface{}
+	options  Options
+	tokens   *tokenSource
+	client   *http.Client
+}
+
+// newClient 新しいClientを作成
+func newClient(provider, path, key string, options Options, secrets domain.SecretProvider) *Client {
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultTimeout
+	}
+	httpClient := &http.Client{Timeout: options.Timeout}
+	return &Client{
+		provider: provider,
+		path:     path,
+		key:      key,
+		query:    url.Values{},
+		options:  options,
+		tokens: &tokenSource{
+			provider: provider,
+			tokenURL: options.TokenURL,
+			secrets:  secrets,
+			client:   httpClient,
+		},
+		client: httpClient,
+	}
+}
+
+// SetTransport 外部への接続に使うTransportを設定（トークンの取得にも使う、未設定の場合はhttp.DefaultTransport）
+func (c *Client) SetTransport(transport http.RoundTripper) {
+	c.client.Transport = transport
+}
+
+// Provider 会計サービスの名前
+func (c *Client) Provider() string {
+	return c.provider
+}
+
+// CreateDeal 取引を登録
+func (c *Client) CreateDeal(ctx context.Context, deal *entity.AccountingDeal) (string, string, error) {
+	resource, err := c.do(ctx, http.MethodPost, c.path, c.payload(deal))
+	if err != nil {
+		return "", "", err
+	}
+
+	var created struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if err := json.Unmarshal(resource, &created); err != nil || len(created.ID) == 0 {
+		return "", "", fmt.Errorf("%s returned no deal id", c.provider)
+	}
+
+	id := string(created.ID)
+	if unquoted, err := strconv.Unquote(id); err == nil {
+		id = unquoted
+	}
+	version, err := resourceVersion(resource)
+	return id, version, err
+}
+
+// UpdateDeal 取引を更新
+func (c *Client) UpdateDeal(ctx context.Context, externalID string, deal *entity.AccountingDeal) (string, error) {
+	resource, err := c.do(ctx, http.MethodPut, c.path+"/"+url.PathEscape(externalID), c.payload(deal))
+	if err != nil {
+		return "", err
+	}
+	return resourceVersion(resource)
+}
+
+// DealVersion 取引の現在の版を取得
+func (c *Client) DealVersion(ctx context.Context, externalID string) (string, error) {
+	resource, err := c.do(ctx, http.MethodGet, c.path+"/"+url.PathEscape(externalID), nil)
+	if err != nil {
+		return "", err
+	}
+	return resourceVersion(resource)
+}
+
+// do APIを呼び出し、応答から取引のJSONを取り出す
+func (c *Client) do(ctx context.Context, method, path string, payload interface{}) (json.RawMessage, error) {
+	token, err := c.tokens.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s request: %w", c.provider, err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	endpoint := c.options.BaseURL + path
+	if len(c.query) > 0 {
+		endpoint += "?" + c.query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s request: %w", c.provider, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s api: %w", c.provider, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%s api returned status %d: %s", c.provider, resp.StatusCode, data)
+	}
+
+	var wrapper map[string]json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&wrapper); err != nil {
+		return nil, fmt.Errorf("failed to decode %s response: %w", c.provider, err)
+	}
+	resource, ok := wrapper[c.key]
+	if !ok {
+		return nil, fmt.Errorf("%s response has no %q", c.provider, c.key)
+	}
+	return resource, nil
+}
+
+// account カテゴリーの勘定科目ID
+func (c *Client) account(category string) interface{} {
+	if id, ok := c.options.Accounts[category]; ok && id != "" {
+		return idValue(id)
+	}
+	return idValue(c.options.DefaultAccount)
+}
+
+// resourceVersion 取引のJSONを正規化（キー順）してハッシュを求める
+func resourceVersion(resource json.RawMessage) (string, error) {
+	var v interface{}
+	if err := json.Unmarshal(resource, &v); err != nil {
+		return "", fmt.Errorf("failed to decode deal: %w", err)
+	}
+	canonical, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode deal: %w", err)
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// idValue 数値のIDは数値として、それ以外は文字列として送信する
+func idValue(id string) interface{} {
+	if n, err := strconv.ParseInt(id, 10, 64); err == nil {
+		return n
+	}
+	return id
+}
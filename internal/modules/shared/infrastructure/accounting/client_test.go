@@ -0,0 +1,200 @@
+package accounting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+// mapSecretProvider 秘密情報をマップから返す
+type mapSecretProvider map[string]string
+
+func (p mapSecretProvider) Secret(ctx context.Context, name string) (string, error) {
+	if v, ok := p[name]; ok {
+		return v, nil
+	}
+	return "", domain.ErrSecretNotFound
+}
+
+// fakeFreee トークンエンドポイントと取引APIを模したサーバー
+type fakeFreee struct {
+	mu            sync.Mutex
+	refreshTokens []string
+	deals         map[string]map[string]interface{}
+}
+
+func (f *fakeFreee) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path == "/token" {
+		_ = r.ParseForm()
+		f.refreshTokens = append(f.refreshTokens, r.PostForm.Get("refresh_token"))
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  "access-1",
+			"refresh_token": "rotated",
+			"expires_in":    3600,
+		})
+		return
+	}
+
+	if r.Header.Get("Authorization") != "Bearer access-1" || r.URL.Query().Get("company_id") != "123" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/api/1/deals":
+		var deal map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&deal)
+		deal["id"] = 1
+		f.deals["1"] = deal
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"deal": deal})
+	case r.URL.Path == "/api/1/deals/1":
+		if r.Method == http.MethodPut {
+			var deal map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&deal)
+			deal["id"] = 1
+			f.deals["1"] = deal
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"deal": f.deals["1"]})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestFreeeClient(t *testing.T) {
+	fake := &fakeFreee{deals: make(map[string]map[string]interface{})}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	secrets := mapSecretProvider{
+		"freee_client_id":     "id",
+		"freee_client_secret": "secret",
+		"freee_refresh_token": "initial",
+	}
+	client := NewFreeeClient(Options{
+		BaseURL:        server.URL,
+		TokenURL:       server.URL + "/token",
+		CompanyID:      "123",
+		Accounts:       map[string]string{"食費": "101"},
+		DefaultAccount: "999",
+		TaxCode:        "136",
+		Timeout:        5 * time.Second,
+	}, secrets)
+
+	deal := &entity.AccountingDeal{
+		ReceiptID: "receipt-1",
+		Date:      time.Date(2025, time.June, 5, 0, 0, 0, 0, time.Local),
+		Partner:   "スーパーA",
+		Amount:    1100,
+		Lines: []entity.AccountingDealLine{
+			{Category: "食費", Amount: 800, Description: "牛乳, パン"},
+			{Category: "日用品", Amount: 300, Description: "洗剤"},
+		},
+	}
+
+	id, version, err := client.CreateDeal(context.Background(), deal)
+	if err != nil {
+		t.Fatalf("CreateDeal() error = %v", err)
+	}
+	if id != "1" || version == "" {
+		t.Errorf("CreateDeal() = %q, %q", id, version)
+	}
+
+	details := fake.deals["1"]["details"].([]interface{})
+	if got := details[0].(map[string]interface{})["account_item_id"]; got != float64(101) {
+		t.Errorf("account_item_id = %v, want 101", got)
+	}
+	if got := details[1].(map[string]interface{})["account_item_id"]; got != float64(999) {
+		t.Errorf("default account_item_id = %v, want 999", got)
+	}
+
+	current, err := client.DealVersion(context.Background(), id)
+	if err != nil || current != version {
+		t.Errorf("DealVersion() = %q, %v, want %q", current, err, version)
+	}
+
+	// 会計サービス側で変更されると版が変わる
+	fake.deals["1"]["memo"] = "edited"
+	if changed, _ := client.DealVersion(context.Background(), id); changed == version {
+		t.Error("DealVersion() did not change after remote edit")
+	}
+
+	deal.Lines[0].Amount = 900
+	updated, err := client.UpdateDeal(context.Background(), id, deal)
+	if err != nil || updated == version {
+		t.Errorf("UpdateDeal() = %q, %v", updated, err)
+	}
+
+	// アクセストークンは有効期限まで使い回す
+	if len(fake.refreshTokens) != 1 || fake.refreshTokens[0] != "initial" {
+		t.Errorf("refresh tokens = %v, want [initial]", fake.refreshTokens)
+	}
+}
+
+func TestTokenSource(t *testing.T) {
+	var refreshed []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		refreshed = append(refreshed, r.PostForm.Get("refresh_token"))
+		if r.PostForm.Get("client_secret") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		// 期限切れ間近のトークンを返して毎回更新させる
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "token", "refresh_token": "rotated", "expires_in": 10})
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		secrets mapSecretProvider
+		want    string
+		wantErr bool
+	}{
+		{name: "正常系: 固定のアクセストークン", secrets: mapSecretProvider{"freee_access_token": "static"}, want: "static"},
+		{name: "正常系: リフレッシュトークンで更新", secrets: mapSecretProvider{"freee_client_id": "id", "freee_client_secret": "secret", "freee_refresh_token": "initial"}, want: "token"},
+		{name: "異常系: 認証情報が未登録", secrets: mapSecretProvider{}, wantErr: true},
+		{name: "異常系: トークンエンドポイントのエラー", secrets: mapSecretProvider{"freee_client_id": "id", "freee_client_secret": "wrong", "freee_refresh_token": "initial"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &tokenSource{provider: "freee", tokenURL: server.URL, secrets: tt.secrets, client: server.Client()}
+			got, err := s.Token(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Token() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Token() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("正常系: 発行されたリフレッシュトークンを引き継ぐ", func(t *testing.T) {
+		refreshed = nil
+		s := &tokenSource{provider: "freee", tokenURL: server.URL, client: server.Client(),
+			secrets: mapSecretProvider{"freee_client_id": "id", "freee_client_secret": "secret", "freee_refresh_token": "initial"}}
+		for i := 0; i < 2; i++ {
+			if _, err := s.Token(context.Background()); err != nil {
+				t.Fatalf("Token() error = %v", err)
+			}
+		}
+		if len(refreshed) != 2 || refreshed[0] != "initial" || refreshed[1] != "rotated" {
+			t.Errorf("refresh tokens = %v, want [initial rotated]", refreshed)
+		}
+	})
+
+	if _, err := (&tokenSource{provider: "freee", secrets: mapSecretProvider{}}).Token(context.Background()); !errors.Is(err, domain.ErrSecretNotFound) {
+		t.Errorf("Token() error = %v, want ErrSecretNotFound", err)
+	}
+}
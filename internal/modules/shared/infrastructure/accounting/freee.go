@@ -0,0 +1,33 @@
+package accounting
+
+import (
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+// NewFreeeClient freee会計の取引（支出）APIのクライアントを作成
+// 取引は未決済として登録し、レシートIDを管理番号（ref_number）に設定する
+func NewFreeeClient(options Options, secrets domain.SecretProvider) *Client {
+	c := newClient(entity.AccountingProviderFreee, "/api/1/deals", "deal", options, secrets)
+	c.query.Set("company_id", options.CompanyID)
+	c.payload = func(deal *entity.AccountingDeal) interface{} {
+		details := make([]map[string]interface{}, 0, len(deal.Lines))
+		for _, line := range deal.Lines {
+			details = append(details, map[string]interface{}{
+				"account_item_id": c.account(line.Category),
+				"tax_code":        idValue(options.TaxCode),
+				"amount":          line.Amount,
+				"description":     line.Description,
+			})
+		}
+		return map[string]interface{}{
+			"company_id": idValue(options.CompanyID),
+			"issue_date": deal.Date.Format("2006-01-02"),
+			"type":       "expense",
+			"ref_number": deal.ReceiptID,
+			"details":    details,
+			"memo":       deal.Partner,
+		}
+	}
+	return c
+}
@@ -0,0 +1,38 @@
+package accounting
+
+import (
+	"strings"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+// NewMoneyForwardClient マネーフォワード クラウド会計の仕訳APIのクライアントを作成
+// カテゴリーごとの費用を借方、支払元の勘定科目を貸方とする仕訳として登録する
+func NewMoneyForwardClient(options Options, secrets domain.SecretProvider) *Client {
+	c := newClient(entity.AccountingProviderMoneyForward, "/api/v3/journals", "journal", options, secrets)
+	c.payload = func(deal *entity.AccountingDeal) interface{} {
+		branches := make([]map[string]interface{}, 0, len(deal.Lines))
+		for _, line := range deal.Lines {
+			branches = append(branches, map[string]interface{}{
+				"remark": strings.TrimSpace(deal.Partner + " " + line.Description),
+				"debitor": map[string]interface{}{
+					"account_id": c.account(line.Category),
+					"value":      line.Amount,
+				},
+				"creditor": map[string]interface{}{
+					"account_id": idValue(options.PaymentAccount),
+					"value":      line.Amount,
+				},
+			})
+		}
+		return map[string]interface{}{
+			"journal": map[string]interface{}{
+				"transaction_date": deal.Date.Format("2006-01-02"),
+				"memo":             "receipt:" + deal.ReceiptID,
+				"branches":         branches,
+			},
+		}
+	}
+	return c
+}
@@ -0,0 +1,113 @@
+package accounting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+// tokenRefreshMargin アクセストークンの有効期限の何秒前に更新するか
+const tokenRefreshMargin = time.Minute
+
+// tokenSource 会計サービスのOAuth2のアクセストークンを取得・更新する
+// 秘密情報 <provider>_access_token が登録されている場合はそのまま使い、
+// それ以外は <provider>_client_id / _client_secret / _refresh_token でアクセストークンを更新する。
+// 更新時に新しいリフレッシュトークンが発行された場合はメモリ上で引き継ぐ
+type tokenSource struct {
+	provider string
+	tokenURL string
+	secrets  domain.SecretProvider
+	client   *http.Client
+
+	mu           sync.Mutex
+	accessToken  string
+	refreshToken string
+	expiresAt    time.Time
+}
+
+// tokenResponse トークンエンドポイントの応答
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// Token 有効なアクセストークンを取得
+func (s *tokenSource) Token(ctx context.Context) (string, error) {
+	if token, err := s.secrets.Secret(ctx, s.provider+"_access_token"); err == nil {
+		return token, nil
+	} else if !errors.Is(err, domain.ErrSecretNotFound) {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.accessToken != "" && time.Now().Add(tokenRefreshMargin).Before(s.expiresAt) {
+		return s.accessToken, nil
+	}
+
+	clientID, err := s.secrets.Secret(ctx, s.provider+"_client_id")
+	if err != nil {
+		return "", err
+	}
+	clientSecret, err := s.secrets.Secret(ctx, s.provider+"_client_secret")
+	if err != nil {
+		return "", err
+	}
+	refreshToken := s.refreshToken
+	if refreshToken == "" {
+		if refreshToken, err = s.secrets.Secret(ctx, s.provider+"_refresh_token"); err != nil {
+			return "", err
+		}
+	}
+
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {clientID},
+		"client_secret": {clientSecret},
+		"refresh_token": {refreshToken},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh %s access token: %w", s.provider, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("%s token endpoint returned status %d: %s", s.provider, resp.StatusCode, body)
+	}
+
+	var token tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("%s token endpoint returned no access token", s.provider)
+	}
+
+	s.accessToken = token.AccessToken
+	s.expiresAt = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	if token.RefreshToken != "" {
+		s.refreshToken = token.RefreshToken
+	}
+	return s.accessToken, nil
+}
@@ -0,0 +1,249 @@
+//go:build !no_ai
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"vision-api-app/internal/config"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+	"vision-api-app/internal/modules/vision/domain"
+)
+
+// ledgerTimeout コンテキストのない呼び出し（AIリポジトリのメソッド）で利用量の保存先にアクセスする時間の上限
+const ledgerTimeout = 2 * time.Second
+
+// BudgetedRepository AI APIの利用料金を月ごとに記録し、予算を超えた場合は重要でない処理（カテゴリーの判定）を縮退するAIリポジトリ
+// レシートの読み取りなど重要な処理は予算を超えても設定のモデルで処理する
+// 利用料金と縮退の手動切り替えは保存先（AIBudgetLedger）に記録し、保存先を共有するすべてのインスタンスで1つの予算を判定する
+type BudgetedRepository struct {
+	domain.AIRepository
+	cheap      domain.AIRepository // 縮退中にカテゴリーを判定する安いモデル（nilの場合はAIを使わずErrAIDowngradedを返す）
+	cheapModel string
+	budgetUSD  float64
+	prices     map[string]config.AIModelPrice
+	downgrade  string
+	notifier   sharedDomain.Notifier
+	clock      sharedDomain.Clock
+	location   *time.Location
+	ledger     sharedDomain.AIBudgetLedger
+}
+
+// NewBudgetedRepository 新しいBudgetedRepositoryを作成
+func NewBudgetedRepository(repo domain.AIRepository, cfg *config.AIBudgetConfig) *BudgetedRepository {
+	return &BudgetedRepository{
+		AIRepository: repo,
+		budgetUSD:    cfg.MonthlyUSD,
+		prices:       cfg.Prices,
+		downgrade:    sharedDomain.AIDowngradeRules,
+		clock:        sharedDomain.SystemClock{},
+		location:     time.Local,
+		ledger:       newMemoryLedger(),
+	}
+}
+
+// SetLedger 利用料金と縮退の手動切り替えの保存先を設定
+// 未設定の場合はプロセス内に記録するため、再起動で当月の利用料金が0に戻り、インスタンスごとに予算を判定する
+func (r *BudgetedRepository) SetLedger(ledger sharedDomain.AIBudgetLedger) {
+	r.ledger = ledger
+}
+
+// SetCheapRepository 縮退中にカテゴリーを判定する安いモデルのAIリポジトリを設定
+// 未設定の場合、縮退中はAIを使わずキーワードのルールで判定させる
+func (r *BudgetedRepository) SetCheapRepository(cheap domain.AIRepository, model string) {
+	r.cheap = cheap
+	r.cheapModel = model
+	r.downgrade = sharedDomain.AIDowngradeCheapModel
+}
+
+// SetNotifier 予算を超えた場合の通知先を設定（未設定の場合はログのみ）
+func (r *BudgetedRepository) SetNotifier(notifier sharedDomain.Notifier) {
+	r.notifier = notifier
+}
+
+// SetClock 月の判定に使う時計を設定する
+// 未設定の場合はシステムの時計を使う
+func (r *BudgetedRepository) SetClock(clock sharedDomain.Clock) {
+	r.clock = clock
+}
+
+// SetLocation 月の区切りに使うタイムゾーンを設定する
+// 未設定の場合はシステムのタイムゾーンを使う
+func (r *BudgetedRepository) SetLocation(location *time.Location) {
+	r.location = location
+}
+
+// Correct テキストを補正し、利用料金を記録
+func (r *BudgetedRepository) Correct(text string) (*domain.AIResult, error) {
+	return r.record(r.AIRepository.Correct(text))
+}
+
+// RecognizeImage 画像からテキストを認識し、利用料金を記録
+func (r *BudgetedRepository) RecognizeImage(imageData []byte) (*domain.AIResult, error) {
+	return r.record(r.AIRepository.RecognizeImage(imageData))
+}
+
+// RecognizeReceipt レシート画像から構造化データを抽出し、利用料金を記録
+func (r *BudgetedRepository) RecognizeReceipt(imageData []byte) (*domain.AIResult, error) {
+	return r.record(r.AIRepository.RecognizeReceipt(imageData))
+}
+
+// CategorizeReceipt カテゴリーを判定し、利用料金を記録
+// 縮退中は安いモデルで判定し、安いモデルが未設定の場合はAPIを呼び出さずにdomain.ErrAIDowngradedを返す
+func (r *BudgetedRepository) CategorizeReceipt(receiptInfo string) (*domain.AIResult, error) {
+	if !r.downgraded() {
+		return r.record(r.AIRepository.CategorizeReceipt(receiptInfo))
+	}
+	if r.cheap == nil {
+		return nil, fmt.Errorf("%w: monthly AI budget exceeded", domain.ErrAIDowngraded)
+	}
+	return r.record(r.cheap.CategorizeReceipt(receiptInfo))
+}
+
+// PromptVersion 元のAIリポジトリのプロンプト・モデルの版を返す
+// 版を返す処理（画像・レシートの読み取り）は縮退しないため、キャッシュキーは変わらない
+func (r *BudgetedRepository) PromptVersion(operation string) string {
+	return domain.PromptVersionOf(r.AIRepository, operation)
+}
+
+// Status 当月の利用料金と縮退の状態を返す
+func (r *BudgetedRepository) Status(ctx context.Context) (sharedDomain.AIBudgetStatus, error) {
+	month := r.currentMonth()
+	models, costUSD, err := r.ledger.Usage(ctx, month)
+	if err != nil {
+		return sharedDomain.AIBudgetStatus{}, fmt.Errorf("failed to get AI usage: %w", err)
+	}
+	override, err := r.ledger.Override(ctx)
+	if err != nil {
+		return sharedDomain.AIBudgetStatus{}, fmt.Errorf("failed to get AI budget override: %w", err)
+	}
+
+	exceeded := costUSD > r.budgetUSD
+	downgraded := exceeded
+	switch override {
+	case sharedDomain.AIBudgetOverrideNormal:
+		downgraded = false
+	case sharedDomain.AIBudgetOverrideDowngrade:
+		downgraded = true
+	}
+
+	return sharedDomain.AIBudgetStatus{
+		Month:      month,
+		BudgetUSD:  r.budgetUSD,
+		CostUSD:    costUSD,
+		Exceeded:   exceeded,
+		Override:   override,
+		Downgraded: downgraded,
+		Downgrade:  r.downgrade,
+		CheapModel: r.cheapModel,
+		Models:     models,
+	}, nil
+}
+
+// SetOverride 縮退を手動で切り替える（auto・normal・downgrade、月が変わっても維持する）
+func (r *BudgetedRepository) SetOverride(ctx context.Context, override string) error {
+	switch override {
+	case sharedDomain.AIBudgetOverrideAuto, sharedDomain.AIBudgetOverrideNormal, sharedDomain.AIBudgetOverrideDowngrade:
+	default:
+		return fmt.Errorf("%w: %q (auto, normal or downgrade)", sharedDomain.ErrInvalidAIBudgetOverride, override)
+	}
+
+	if err := r.ledger.SetOverride(ctx, override); err != nil {
+		return fmt.Errorf("failed to save AI budget override: %w", err)
+	}
+	return nil
+}
+
+// downgraded 縮退中か（保存先に接続できない場合は縮退せず、設定のモデルで処理を続ける）
+func (r *BudgetedRepository) downgraded() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), ledgerTimeout)
+	defer cancel()
+
+	status, err := r.Status(ctx)
+	if err != nil {
+		slog.Warn("Failed to get AI budget status, not downgrading", "error", err)
+		return false
+	}
+	return status.Downgraded
+}
+
+// record AI APIの結果のトークン数から利用料金を当月分に加え、初めて予算を超えた場合は通知する
+// 保存先に接続できない場合は記録を諦め、結果はそのまま返す
+func (r *BudgetedRepository) record(result *domain.AIResult, err error) (*domain.AIResult, error) {
+	if err != nil || result == nil {
+		return result, err
+	}
+	r.addUsage(result.Model, sharedDomain.AIModelUsage{
+		Requests:     1,
+		InputTokens:  result.InputTokens,
+		OutputTokens: result.OutputTokens,
+		CostUSD:      r.cost(result, 1),
+	})
+	return result, nil
+}
+
+// cost 結果のトークン数とモデルの料金から利用料金を計算（ratioは料金にかける割合）
+func (r *BudgetedRepository) cost(result *domain.AIResult, ratio float64) float64 {
+	price := r.prices[result.Model]
+	return (float64(result.InputTokens)*price.InputPerMTok + float64(result.OutputTokens)*price.OutputPerMTok) / 1e6 * ratio
+}
+
+// addUsage モデルの利用量を当月分に加え、初めて予算を超えた場合は通知する
+// 保存先に接続できない場合は記録を諦める
+func (r *BudgetedRepository) addUsage(model string, usage sharedDomain.AIModelUsage) {
+	ctx, cancel := context.WithTimeout(context.Background(), ledgerTimeout)
+	defer cancel()
+
+	month := r.currentMonth()
+	costUSD, err := r.ledger.Add(ctx, month, model, usage)
+	if err != nil {
+		slog.Warn("Failed to record AI usage", "model", model, "cost_usd", usage.CostUSD, "error", err)
+		return
+	}
+	if costUSD <= r.budgetUSD {
+		return
+	}
+
+	// 予算超過の通知はすべてのインスタンスを通じて月に1回だけ送る
+	first, err := r.ledger.MarkNotified(ctx, month)
+	if err != nil {
+		slog.Warn("Failed to record AI budget notification", "month", month, "error", err)
+		return
+	}
+	if first {
+		status, err := r.Status(ctx)
+		if err != nil {
+			slog.Warn("Failed to get AI budget status", "error", err)
+			return
+		}
+		r.notifyExceeded(status)
+	}
+}
+
+// currentMonth 利用料金を集計している月（YYYY-MM）
+func (r *BudgetedRepository) currentMonth() string {
+	return r.clock.Now().In(r.location).Format("2006-01")
+}
+
+// notifyExceeded 当月の利用料金が予算を超えたことを通知（通知先が未設定の場合はログのみ）
+func (r *BudgetedRepository) notifyExceeded(status sharedDomain.AIBudgetStatus) {
+	slog.Warn("Monthly AI budget exceeded", "month", status.Month, "cost_usd", status.CostUSD, "budget_usd", status.BudgetUSD, "downgraded", status.Downgraded, "downgrade", status.Downgrade)
+	if r.notifier == nil {
+		return
+	}
+	message := fmt.Sprintf("%sのAI APIの利用料金が$%.2fで、予算の$%.2fを超えました", status.Month, status.CostUSD, status.BudgetUSD)
+	if status.Downgraded {
+		message += "。カテゴリーの判定を縮退します"
+	}
+	if err := r.notifier.Notify(context.Background(), sharedDomain.Notification{
+		Event:     sharedDomain.AIBudgetEventExceeded,
+		Message:   message,
+		Data:      status,
+		CreatedAt: r.clock.Now(),
+	}); err != nil {
+		slog.Warn("Failed to send AI budget notification", "error", err)
+	}
+}
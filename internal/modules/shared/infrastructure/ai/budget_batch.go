@@ -0,0 +1,73 @@
+//go:build !no_ai
+
+package ai
+
+import (
+	"fmt"
+
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+	"vision-api-app/internal/modules/vision/domain"
+)
+
+// batchPriceRatio バッチ処理の料金の通常の料金に対する割合（Message Batches APIは半額）
+const batchPriceRatio = 0.5
+
+// SubmitReceiptBatch レシート画像の読み取りを元のAIリポジトリのバッチ処理で依頼
+// バッチ処理は急がない処理（読み直し）のため、縮退中はAPIを呼び出さずにdomain.ErrAIDowngradedを返す
+func (r *BudgetedRepository) SubmitReceiptBatch(images []domain.BatchImage) (string, error) {
+	batchRepo, err := r.batchRepository()
+	if err != nil {
+		return "", err
+	}
+	if r.downgraded() {
+		return "", fmt.Errorf("%w: monthly AI budget exceeded", domain.ErrAIDowngraded)
+	}
+	return batchRepo.SubmitReceiptBatch(images)
+}
+
+// GetBatch 元のAIリポジトリのバッチの処理状況を取得
+func (r *BudgetedRepository) GetBatch(batchID string) (*domain.BatchStatus, error) {
+	batchRepo, err := r.batchRepository()
+	if err != nil {
+		return nil, err
+	}
+	return batchRepo.GetBatch(batchID)
+}
+
+// BatchResults 元のAIリポジトリから処理が終わったバッチの結果を取得し、成功したリクエストの利用料金をモデルごとにまとめて記録
+func (r *BudgetedRepository) BatchResults(batchID string) ([]domain.BatchResult, error) {
+	batchRepo, err := r.batchRepository()
+	if err != nil {
+		return nil, err
+	}
+	results, err := batchRepo.BatchResults(batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	usages := make(map[string]sharedDomain.AIModelUsage)
+	for _, result := range results {
+		if result.Result == nil {
+			continue
+		}
+		usage := usages[result.Result.Model]
+		usage.Requests++
+		usage.InputTokens += result.Result.InputTokens
+		usage.OutputTokens += result.Result.OutputTokens
+		usage.CostUSD += r.cost(result.Result, batchPriceRatio)
+		usages[result.Result.Model] = usage
+	}
+	for model, usage := range usages {
+		r.addUsage(model, usage)
+	}
+	return results, nil
+}
+
+// batchRepository バッチ処理に対応した元のAIリポジトリ
+func (r *BudgetedRepository) batchRepository() (domain.BatchAIRepository, error) {
+	batchRepo, ok := r.AIRepository.(domain.BatchAIRepository)
+	if !ok {
+		return nil, fmt.Errorf("AI provider %s does not support batches", r.ProviderName())
+	}
+	return batchRepo, nil
+}
@@ -0,0 +1,97 @@
+//go:build !no_ai
+
+package ai
+
+import (
+	"context"
+	"maps"
+	"sync"
+
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// memoryLedger プロセス内のAI APIの利用量の記録先（共有の保存先を設定しない場合・テスト用）
+// 再起動すると記録は失われ、インスタンスごとに予算を判定する
+type memoryLedger struct {
+	mu       sync.Mutex
+	months   map[string]*monthUsage
+	override string
+}
+
+// monthUsage 月ごとの利用量
+type monthUsage struct {
+	models   map[string]sharedDomain.AIModelUsage
+	costUSD  float64
+	notified bool
+}
+
+// newMemoryLedger 新しいmemoryLedgerを作成
+func newMemoryLedger() *memoryLedger {
+	return &memoryLedger{
+		months:   make(map[string]*monthUsage),
+		override: sharedDomain.AIBudgetOverrideAuto,
+	}
+}
+
+// month 月の利用量を返す（前の月の記録は捨てる、呼び出し側でロックを取得する）
+func (l *memoryLedger) month(month string) *monthUsage {
+	usage, ok := l.months[month]
+	if !ok {
+		usage = &monthUsage{models: make(map[string]sharedDomain.AIModelUsage)}
+		l.months = map[string]*monthUsage{month: usage}
+	}
+	return usage
+}
+
+// Add 月のモデルの利用量を加算し、加算後の月の利用料金の合計を返す
+func (l *memoryLedger) Add(ctx context.Context, month, model string, usage sharedDomain.AIModelUsage) (float64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	m := l.month(month)
+	total := m.models[model]
+	total.Requests += usage.Requests
+	total.InputTokens += usage.InputTokens
+	total.OutputTokens += usage.OutputTokens
+	total.CostUSD += usage.CostUSD
+	m.models[model] = total
+	m.costUSD += usage.CostUSD
+	return m.costUSD, nil
+}
+
+// Usage 月のモデルごとの利用量と利用料金の合計を返す
+func (l *memoryLedger) Usage(ctx context.Context, month string) (map[string]sharedDomain.AIModelUsage, float64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	m := l.month(month)
+	return maps.Clone(m.models), m.costUSD, nil
+}
+
+// MarkNotified 月の予算超過の通知を記録し、初めて記録した場合だけtrueを返す
+func (l *memoryLedger) MarkNotified(ctx context.Context, month string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	m := l.month(month)
+	if m.notified {
+		return false, nil
+	}
+	m.notified = true
+	return true, nil
+}
+
+// Override 縮退の手動切り替えを返す
+func (l *memoryLedger) Override(ctx context.Context) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.override, nil
+}
+
+// SetOverride 縮退の手動切り替えを保存する
+func (l *memoryLedger) SetOverride(ctx context.Context, override string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.override = override
+	return nil
+}
@@ -0,0 +1,256 @@
+//go:build !no_ai
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/config"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+	"vision-api-app/internal/modules/vision/domain"
+)
+
+// fakeAIRepository 指定したモデル名・トークン数の結果を返し、カテゴリーの判定の回数を数えるテスト用のAIリポジトリ
+type fakeAIRepository struct {
+	model       string
+	categorized int
+}
+
+func (f *fakeAIRepository) result() (*domain.AIResult, error) {
+	return domain.NewAIResult("", "[]", 1_000_000, 100_000, f.model), nil
+}
+
+func (f *fakeAIRepository) Correct(text string) (*domain.AIResult, error) { return f.result() }
+
+func (f *fakeAIRepository) RecognizeImage(imageData []byte) (*domain.AIResult, error) {
+	return f.result()
+}
+
+func (f *fakeAIRepository) RecognizeReceipt(imageData []byte) (*domain.AIResult, error) {
+	return f.result()
+}
+
+func (f *fakeAIRepository) CategorizeReceipt(receiptInfo string) (*domain.AIResult, error) {
+	f.categorized++
+	return f.result()
+}
+
+func (f *fakeAIRepository) ProviderName() string { return "fake" }
+
+// recordingNotifier 送信した通知を記録するテスト用の通知先
+type recordingNotifier struct {
+	notifications []sharedDomain.Notification
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification sharedDomain.Notification) error {
+	n.notifications = append(n.notifications, notification)
+	return nil
+}
+
+func newTestBudgetedRepository(repo domain.AIRepository) *BudgetedRepository {
+	budget := NewBudgetedRepository(repo, &config.AIBudgetConfig{
+		MonthlyUSD: 2,
+		Prices: map[string]config.AIModelPrice{
+			"main":  {InputPerMTok: 1, OutputPerMTok: 5},
+			"cheap": {InputPerMTok: 0.1, OutputPerMTok: 0.4},
+		},
+	})
+	budget.SetClock(sharedDomain.FixedClock(time.Date(2025, time.June, 30, 23, 0, 0, 0, time.UTC)))
+	budget.SetLocation(time.UTC)
+	return budget
+}
+
+// budgetStatus 当月の利用料金と縮退の状態を取得
+func budgetStatus(t *testing.T, budget *BudgetedRepository) sharedDomain.AIBudgetStatus {
+	t.Helper()
+	status, err := budget.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	return status
+}
+
+func TestBudgetedRepository_Rules(t *testing.T) {
+	main := &fakeAIRepository{model: "main"}
+	budget := newTestBudgetedRepository(main)
+	notifier := &recordingNotifier{}
+	budget.SetNotifier(notifier)
+
+	// 1回あたり $1 + $0.5 = $1.5
+	if _, err := budget.CategorizeReceipt("info"); err != nil {
+		t.Fatalf("CategorizeReceipt() error = %v", err)
+	}
+	if status := budgetStatus(t, budget); status.Month != "2025-06" || status.CostUSD != 1.5 || status.Exceeded || status.Downgraded {
+		t.Errorf("Status() = %+v", status)
+	}
+	if _, err := budget.RecognizeReceipt(nil); err != nil {
+		t.Fatalf("RecognizeReceipt() error = %v", err)
+	}
+	status := budgetStatus(t, budget)
+	if status.CostUSD != 3 || !status.Exceeded || !status.Downgraded || status.Downgrade != sharedDomain.AIDowngradeRules || status.Models["main"].Requests != 2 {
+		t.Errorf("Status() = %+v", status)
+	}
+	if len(notifier.notifications) != 1 || notifier.notifications[0].Event != sharedDomain.AIBudgetEventExceeded {
+		t.Errorf("notifications = %+v", notifier.notifications)
+	}
+
+	// 縮退中はカテゴリーの判定でAPIを呼び出さない（レシートの読み取りは続ける）
+	if _, err := budget.CategorizeReceipt("info"); !errors.Is(err, domain.ErrAIDowngraded) {
+		t.Errorf("CategorizeReceipt() error = %v, want %v", err, domain.ErrAIDowngraded)
+	}
+	if _, err := budget.RecognizeReceipt(nil); err != nil {
+		t.Fatalf("RecognizeReceipt() error = %v", err)
+	}
+	if main.categorized != 1 || len(notifier.notifications) != 1 {
+		t.Errorf("categorized = %d, notifications = %d, want 1, 1", main.categorized, len(notifier.notifications))
+	}
+
+	// 手動で縮退を解除する
+	if err := budget.SetOverride(context.Background(), sharedDomain.AIBudgetOverrideNormal); err != nil {
+		t.Fatalf("SetOverride() error = %v", err)
+	}
+	if _, err := budget.CategorizeReceipt("info"); err != nil {
+		t.Errorf("CategorizeReceipt() error = %v", err)
+	}
+	if err := budget.SetOverride(context.Background(), "off"); !errors.Is(err, sharedDomain.ErrInvalidAIBudgetOverride) {
+		t.Errorf("SetOverride() error = %v, want %v", err, sharedDomain.ErrInvalidAIBudgetOverride)
+	}
+
+	// 月が変わると記録を0に戻す
+	budget.SetClock(sharedDomain.FixedClock(time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)))
+	if status := budgetStatus(t, budget); status.Month != "2025-07" || status.CostUSD != 0 || status.Exceeded || len(status.Models) != 0 {
+		t.Errorf("Status() = %+v", status)
+	}
+}
+
+func TestBudgetedRepository_CheapModel(t *testing.T) {
+	main := &fakeAIRepository{model: "main"}
+	cheap := &fakeAIRepository{model: "cheap"}
+	budget := newTestBudgetedRepository(main)
+	budget.SetCheapRepository(cheap, "cheap")
+
+	// 予算を超えていなくても手動で縮退できる
+	if err := budget.SetOverride(context.Background(), sharedDomain.AIBudgetOverrideDowngrade); err != nil {
+		t.Fatalf("SetOverride() error = %v", err)
+	}
+	result, err := budget.CategorizeReceipt("info")
+	if err != nil {
+		t.Fatalf("CategorizeReceipt() error = %v", err)
+	}
+	if result.Model != "cheap" || main.categorized != 0 || cheap.categorized != 1 {
+		t.Errorf("Model = %s, categorized = (%d, %d)", result.Model, main.categorized, cheap.categorized)
+	}
+	status := budgetStatus(t, budget)
+	if status.Exceeded || !status.Downgraded || status.Downgrade != sharedDomain.AIDowngradeCheapModel || status.CheapModel != "cheap" || status.CostUSD != 0.14 {
+		t.Errorf("Status() = %+v", status)
+	}
+}
+
+func TestBudgetedRepository_SharedLedger(t *testing.T) {
+	// 同じ保存先を使うインスタンス同士で1つの予算を判定する
+	ledger := newMemoryLedger()
+	first := newTestBudgetedRepository(&fakeAIRepository{model: "main"})
+	first.SetLedger(ledger)
+	second := newTestBudgetedRepository(&fakeAIRepository{model: "main"})
+	second.SetLedger(ledger)
+	notifier := &recordingNotifier{}
+	first.SetNotifier(notifier)
+	second.SetNotifier(notifier)
+
+	if _, err := first.RecognizeReceipt(nil); err != nil {
+		t.Fatalf("RecognizeReceipt() error = %v", err)
+	}
+	if _, err := second.RecognizeReceipt(nil); err != nil {
+		t.Fatalf("RecognizeReceipt() error = %v", err)
+	}
+	if status := budgetStatus(t, first); status.CostUSD != 3 || !status.Downgraded {
+		t.Errorf("Status() = %+v", status)
+	}
+	if _, err := first.RecognizeReceipt(nil); err != nil {
+		t.Fatalf("RecognizeReceipt() error = %v", err)
+	}
+	if len(notifier.notifications) != 1 {
+		t.Errorf("notifications = %d, want 1", len(notifier.notifications))
+	}
+
+	// 手動の切り替えもすべてのインスタンスに反映する
+	if err := second.SetOverride(context.Background(), sharedDomain.AIBudgetOverrideNormal); err != nil {
+		t.Fatalf("SetOverride() error = %v", err)
+	}
+	if status := budgetStatus(t, first); status.Override != sharedDomain.AIBudgetOverrideNormal || status.Downgraded {
+		t.Errorf("Status() = %+v", status)
+	}
+}
+
+// fakeBatchAIRepository バッチ処理に対応したテスト用のAIリポジトリ
+type fakeBatchAIRepository struct {
+	fakeAIRepository
+	submitted int
+	results   []domain.BatchResult
+}
+
+func (f *fakeBatchAIRepository) SubmitReceiptBatch(images []domain.BatchImage) (string, error) {
+	f.submitted++
+	return "batch-1", nil
+}
+
+func (f *fakeBatchAIRepository) GetBatch(batchID string) (*domain.BatchStatus, error) {
+	return &domain.BatchStatus{ID: batchID, Ended: true}, nil
+}
+
+func (f *fakeBatchAIRepository) BatchResults(batchID string) ([]domain.BatchResult, error) {
+	return f.results, nil
+}
+
+func TestBudgetedRepository_Batch(t *testing.T) {
+	main := &fakeBatchAIRepository{
+		fakeAIRepository: fakeAIRepository{model: "main"},
+		results: []domain.BatchResult{
+			{CustomID: "r1", Result: domain.NewAIResult("", "{}", 1_000_000, 100_000, "main")},
+			{CustomID: "r2", Result: domain.NewAIResult("", "{}", 1_000_000, 100_000, "main")},
+			{CustomID: "r3", Error: "errored"},
+		},
+	}
+	budget := newTestBudgetedRepository(main)
+
+	if _, err := budget.SubmitReceiptBatch([]domain.BatchImage{{CustomID: "r1"}}); err != nil {
+		t.Fatalf("SubmitReceiptBatch() error = %v", err)
+	}
+	if batch, err := budget.GetBatch("batch-1"); err != nil || !batch.Ended {
+		t.Fatalf("GetBatch() = %+v, %v", batch, err)
+	}
+	results, err := budget.BatchResults("batch-1")
+	if err != nil {
+		t.Fatalf("BatchResults() error = %v", err)
+	}
+	if len(results) != 3 {
+		t.Errorf("results = %d, want 3", len(results))
+	}
+
+	// 成功したリクエストだけをバッチ処理の料金（半額）で記録する: 2 × $1.5 × 0.5 = $1.5
+	status := budgetStatus(t, budget)
+	if status.CostUSD != 1.5 || status.Models["main"].Requests != 2 || status.Models["main"].InputTokens != 2_000_000 {
+		t.Errorf("Status() = %+v", status)
+	}
+
+	// 縮退中は新しいバッチを依頼しない
+	if err := budget.SetOverride(context.Background(), sharedDomain.AIBudgetOverrideDowngrade); err != nil {
+		t.Fatalf("SetOverride() error = %v", err)
+	}
+	if _, err := budget.SubmitReceiptBatch([]domain.BatchImage{{CustomID: "r4"}}); !errors.Is(err, domain.ErrAIDowngraded) {
+		t.Errorf("SubmitReceiptBatch() error = %v, want %v", err, domain.ErrAIDowngraded)
+	}
+	if main.submitted != 1 {
+		t.Errorf("submitted = %d, want 1", main.submitted)
+	}
+}
+
+func TestBudgetedRepository_BatchUnsupported(t *testing.T) {
+	budget := newTestBudgetedRepository(&fakeAIRepository{model: "main"})
+	if _, err := budget.SubmitReceiptBatch(nil); err == nil {
+		t.Error("SubmitReceiptBatch() error = nil, want error")
+	}
+}
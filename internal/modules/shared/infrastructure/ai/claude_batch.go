@@ -0,0 +1,233 @@
+//go:build !no_ai
+
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"vision-api-app/internal/modules/vision/domain"
+)
+
+// Message Batches APIのバッチの処理状況・リクエストの結果の種類
+const (
+	batchStatusEnded     = "ended"
+	batchResultSucceeded = "succeeded"
+)
+
+// batchRequest Message Batches APIの1件のリクエスト（paramsはMessages APIのリクエストと同じ）
+type batchRequest struct {
+	CustomID string             `json:"custom_id"`
+	Params   batchMessageParams `json:"params"`
+}
+
+type batchMessageParams struct {
+	Model     string         `json:"model"`
+	MaxTokens int            `json:"max_tokens"`
+	System    string         `json:"system"`
+	Messages  []batchMessage `json:"messages"`
+}
+
+type batchMessage struct {
+	Role    string              `json:"role"`
+	Content []batchContentBlock `json:"content"`
+}
+
+type batchContentBlock struct {
+	Type   string            `json:"type"`
+	Text   string            `json:"text,omitempty"`
+	Source *batchImageSource `json:"source,omitempty"`
+}
+
+type batchImageSource struct {
+	Type      string `json:"type"`
+	MediaType string `json:"media_type"`
+	Data      []byte `json:"data"` // json.Marshalでbase64に変換される
+}
+
+// batchResponse Message Batches APIのバッチ
+type batchResponse struct {
+	ID               string `json:"id"`
+	ProcessingStatus string `json:"processing_status"` // in_progress・canceling・ended
+	RequestCounts    struct {
+		Processing int `json:"processing"`
+		Succeeded  int `json:"succeeded"`
+		Errored    int `json:"errored"`
+		Canceled   int `json:"canceled"`
+		Expired    int `json:"expired"`
+	} `json:"request_counts"`
+	ResultsURL string `json:"results_url"` // 処理が終わるまではnull
+}
+
+// batchResultLine 結果のJSONLの1行
+type batchResultLine struct {
+	CustomID string `json:"custom_id"`
+	Result   struct {
+		Type    string           `json:"type"` // succeeded・errored・canceled・expired
+		Message messagesResponse `json:"message"`
+		Error   json.RawMessage  `json:"error"`
+	} `json:"result"`
+}
+
+// SubmitReceiptBatch レシート画像の読み取りをMessage Batches APIで依頼（通常のAPIより安いが、結果は最大24時間後）
+// バッチでは途中で切れた出力を再試行できないため、最大出力トークン数は再試行の上限（未設定の場合は処理の種類の最大出力トークン数）にする
+func (r *ClaudeRepository) SubmitReceiptBatch(images []domain.BatchImage) (string, error) {
+	maxTokens := max(r.maxTokensFor(operationRecognizeReceipt), r.maxTokensLimit)
+	requests := make([]batchRequest, 0, len(images))
+	for _, image := range images {
+		requests = append(requests, batchRequest{
+			CustomID: image.CustomID,
+			Params: batchMessageParams{
+				Model:     r.model,
+				MaxTokens: maxTokens,
+				System:    systemPromptReceipt,
+				Messages: []batchMessage{{
+					Role: "user",
+					Content: []batchContentBlock{
+						{Type: "image", Source: &batchImageSource{Type: "base64", MediaType: imageMediaType(image.ImageData), Data: image.ImageData}},
+						{Type: "text", Text: userPromptReceipt},
+					},
+				}},
+			},
+		})
+	}
+
+	jsonData, err := json.Marshal(map[string]any{"requests": requests})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal batch request: %w", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, r.batchEndpoint(), bytes.NewReader(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var batch batchResponse
+	if err := r.doBatch(req, func(body io.Reader) error {
+		return json.NewDecoder(body).Decode(&batch)
+	}); err != nil {
+		return "", err
+	}
+	return batch.ID, nil
+}
+
+// GetBatch バッチの処理状況を取得
+func (r *ClaudeRepository) GetBatch(batchID string) (*domain.BatchStatus, error) {
+	batch, err := r.getBatch(batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := batch.RequestCounts
+	return &domain.BatchStatus{
+		ID:        batch.ID,
+		Ended:     batch.ProcessingStatus == batchStatusEnded,
+		Pending:   counts.Processing,
+		Succeeded: counts.Succeeded,
+		Errored:   counts.Errored + counts.Canceled + counts.Expired,
+	}, nil
+}
+
+// BatchResults 処理が終わったバッチの結果を取得
+// 出力が途中で切れたリクエストは、読み取ったJSONが不完全なため失敗とする
+func (r *ClaudeRepository) BatchResults(batchID string) ([]domain.BatchResult, error) {
+	batch, err := r.getBatch(batchID)
+	if err != nil {
+		return nil, err
+	}
+	if batch.ProcessingStatus != batchStatusEnded || batch.ResultsURL == "" {
+		return nil, fmt.Errorf("batch %s has not ended: %s", batchID, batch.ProcessingStatus)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, batch.ResultsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var results []domain.BatchResult
+	err = r.doBatch(req, func(body io.Reader) error {
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+				continue
+			}
+			var line batchResultLine
+			if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+				return fmt.Errorf("failed to decode batch result: %w", err)
+			}
+			results = append(results, r.newBatchResult(&line))
+		}
+		return scanner.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// newBatchResult 結果のJSONLの1行からリクエストの結果を作成
+func (r *ClaudeRepository) newBatchResult(line *batchResultLine) domain.BatchResult {
+	result := domain.BatchResult{CustomID: line.CustomID}
+	switch {
+	case line.Result.Type != batchResultSucceeded:
+		result.Error = line.Result.Type
+		if len(line.Result.Error) > 0 {
+			result.Error += ": " + string(line.Result.Error)
+		}
+	case line.Result.Message.StopReason == stopReasonMaxTokens:
+		result.Error = ErrOutputTruncated.Error()
+	default:
+		result.Result = r.newAIResult("", "", &line.Result.Message)
+	}
+	return result
+}
+
+// getBatch バッチを取得
+func (r *ClaudeRepository) getBatch(batchID string) (*batchResponse, error) {
+	req, err := http.NewRequest(http.MethodGet, r.batchEndpoint()+"/"+url.PathEscape(batchID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var batch batchResponse
+	if err := r.doBatch(req, func(body io.Reader) error {
+		return json.NewDecoder(body).Decode(&batch)
+	}); err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// batchEndpoint Message Batches APIのエンドポイント（Messages APIのエンドポイントの下）
+func (r *ClaudeRepository) batchEndpoint() string {
+	return r.apiEndpoint + "/batches"
+}
+
+// doBatch Message Batches APIのリクエストを送信し、成功した場合はdecodeでレスポンスボディを読み出す
+func (r *ClaudeRepository) doBatch(req *http.Request, decode func(body io.Reader) error) error {
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", r.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("API request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+	if err := decode(resp.Body); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+//go:build !no_ai
+
+package ai
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"vision-api-app/internal/config"
+	"vision-api-app/internal/modules/vision/domain"
+)
+
+func TestClaudeRepository_ReceiptBatch(t *testing.T) {
+	var submitted batchRequestBody
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-api-key") != "test-key" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/messages/batches":
+			body, _ := io.ReadAll(r.Body)
+			if err := json.Unmarshal(body, &submitted); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			_, _ = io.WriteString(w, `{"id":"msgbatch_1","processing_status":"in_progress","request_counts":{"processing":3}}`)
+		case r.URL.Path == "/v1/messages/batches/msgbatch_1":
+			_, _ = io.WriteString(w, `{"id":"msgbatch_1","processing_status":"ended","request_counts":{"succeeded":1,"errored":1,"expired":1},"results_url":"`+server.URL+`/results/msgbatch_1"}`)
+		case r.URL.Path == "/results/msgbatch_1":
+			_, _ = io.WriteString(w, strings.Join([]string{
+				`{"custom_id":"receipt-1","result":{"type":"succeeded","message":{"content":[{"type":"text","text":"{\"store_name\":\"テストマート\"}"}],"stop_reason":"end_turn","usage":{"input_tokens":1200,"output_tokens":150}}}}`,
+				`{"custom_id":"receipt-2","result":{"type":"succeeded","message":{"content":[{"type":"text","text":"{\"store_"}],"stop_reason":"max_tokens","usage":{"input_tokens":1200,"output_tokens":8192}}}}`,
+				`{"custom_id":"receipt-3","result":{"type":"expired"}}`,
+				"",
+			}, "\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	repo := NewClaudeRepository(&config.AnthropicConfig{
+		APIKey:         "test-key",
+		Model:          "claude-haiku-4-5-20251001",
+		MaxTokens:      4096,
+		MaxTokensLimit: 8192,
+	})
+	repo.SetHTTPClient(server.Client())
+	repo.SetAPIEndpoint(server.URL + "/v1/messages")
+
+	batchID, err := repo.SubmitReceiptBatch([]domain.BatchImage{
+		{CustomID: "receipt-1", ImageData: []byte{0xFF, 0xD8, 0x01}},
+		{CustomID: "receipt-2", ImageData: []byte("png")},
+	})
+	if err != nil {
+		t.Fatalf("SubmitReceiptBatch() error = %v", err)
+	}
+	if batchID != "msgbatch_1" {
+		t.Errorf("batchID = %s, want msgbatch_1", batchID)
+	}
+	if len(submitted.Requests) != 2 {
+		t.Fatalf("requests = %+v", submitted.Requests)
+	}
+	// 再試行できないため、最大出力トークン数は再試行の上限にする
+	params := submitted.Requests[0].Params
+	if submitted.Requests[0].CustomID != "receipt-1" || params.MaxTokens != 8192 || params.System != systemPromptReceipt {
+		t.Errorf("request = %+v", submitted.Requests[0])
+	}
+	if source := params.Messages[0].Content[0].Source; source == nil || source.MediaType != "image/jpeg" || string(source.Data) != "\xFF\xD8\x01" {
+		t.Errorf("source = %+v", source)
+	}
+
+	status, err := repo.GetBatch(batchID)
+	if err != nil {
+		t.Fatalf("GetBatch() error = %v", err)
+	}
+	if !status.Ended || status.Succeeded != 1 || status.Errored != 2 {
+		t.Errorf("GetBatch() = %+v", status)
+	}
+
+	results, err := repo.BatchResults(batchID)
+	if err != nil {
+		t.Fatalf("BatchResults() error = %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("BatchResults() = %+v", results)
+	}
+	if r := results[0]; r.Result == nil || r.Result.CorrectedText != `{"store_name":"テストマート"}` || r.Result.InputTokens != 1200 || r.Result.Model != "claude-haiku-4-5-20251001" {
+		t.Errorf("results[0] = %+v", r)
+	}
+	// 出力が途中で切れた結果・期限切れは失敗とする
+	if r := results[1]; r.Result != nil || !strings.Contains(r.Error, "truncated") {
+		t.Errorf("results[1] = %+v", r)
+	}
+	if r := results[2]; r.Result != nil || r.Error != "expired" {
+		t.Errorf("results[2] = %+v", r)
+	}
+}
+
+// batchRequestBody テスト用に送信されたバッチのリクエストを読み取る
+type batchRequestBody struct {
+	Requests []batchRequest `json:"requests"`
+}
@@ -4,14 +4,18 @@ package ai
 
 import (
 	"bytes"
-	"encoding/base64"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"time"
 
 	"vision-api-app/internal/config"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
 	"vision-api-app/internal/modules/vision/domain"
 )
 
@@ -65,11 +69,28 @@ JSON形式で正確に情報を返してください。
 - purchase_date: 購入日時（YYYY-MM-DD HH:MM形式、時刻不明なら12:00）
 - total_amount: お買上金額（商品の合計金額、必ずitemsの合計と一致）
 - tax_amount: 消費税額（不明な場合は0）
-- items: 商品リスト（name, quantity, price）
+- items: 商品リスト（name, quantity, unit, price）
 
 オプション項目：
 - payment_method: 支払い方法
 - receipt_number: レシート番号
+- locale: レシートの言語と国・地域（BCP 47形式、例: ja-JP, en-US, de-DE）
+- currency: 金額の通貨（ISO 4217の通貨コード、例: JPY, USD, EUR）
+- receipt_type: レシートの種類（通常の購入は "purchase"、返品・返金のレシートは "refund"）
+
+量り売りの商品（100g当り198円 320g など）：
+- quantity に計量値、unit に単位（g または kg）、price に商品の金額を入れる
+- unit_price に印字された単価、unit_price_per に単価の基準量（100g当りなら100）を入れる
+- それ以外の商品は unit を "個" とし、price は1個の単価
+
+返品・返金のレシート（「返品」「返金」「取消」の表示があるもの）：
+- receipt_type を "refund" にする
+- total_amount・tax_amount・返品した商品の price は負の数にする（△・▲・末尾の - は負の記号）
+
+海外のレシート：
+- purchase_date は年・月・日の並びを確定できる場合のみ YYYY-MM-DD HH:MM 形式にする
+- 並びを確定できない場合（03/04/2025 など）は印字どおりの文字列を返す
+- 金額は通貨の単位の小数（例: 12.99ドルは 12.99）
 
 出力形式：
 {
@@ -79,7 +100,8 @@ JSON形式で正確に情報を返してください。
   "tax_amount": 150,
   "payment_method": "現金",
   "items": [
-    {"name": "商品名", "quantity": 1, "price": 500}
+    {"name": "商品名", "quantity": 1, "unit": "個", "price": 500},
+    {"name": "豚こま切れ", "quantity": 320, "unit": "g", "price": 634, "unit_price": 198, "unit_price_per": 100}
   ]
 }
 
@@ -133,125 +155,193 @@ JSON形式で正確に情報を返してください。
 
 出力形式：
 抽出したテキストをそのまま返してください。`
+
+	// userPromptImage 汎用テキスト抽出で画像とともに送る指示
+	userPromptImage = "この画像からすべてのテキストを抽出してください。"
+	// userPromptReceipt レシート読み取りで画像とともに送る指示
+	userPromptReceipt = "このレシート画像から情報を抽出してJSON形式で返してください。"
 )
 
+// AI APIの処理の種類（デバッグ用の記録に使う）
+const (
+	operationCorrect           = "correct"
+	operationRecognizeImage    = domain.OperationRecognizeImage
+	operationRecognizeReceipt  = domain.OperationRecognizeReceipt
+	operationCategorizeReceipt = "categorize_receipt"
+)
+
+// promptVersionLength キャッシュキーに含めるプロンプトの版（ハッシュの16進数）の長さ
+const promptVersionLength = 12
+
+// stopReasonMaxTokens 出力が最大出力トークン数に達して途中で切れたことを示すstop_reason
+const stopReasonMaxTokens = "max_tokens"
+
+// ErrOutputTruncated 最大出力トークン数の上限まで増やしても出力が途中で切れた
+var ErrOutputTruncated = errors.New("AI output truncated at max_tokens")
+
 // ClaudeRepository Claude APIのリポジトリ実装
 type ClaudeRepository struct {
-	apiKey      string
-	model       string
-	maxTokens   int
-	httpClient  *http.Client
-	apiEndpoint string // テスト用にエンドポイントを差し替え可能に
+	apiKey         string
+	model          string
+	maxTokens      int
+	taskMaxTokens  map[string]int // 処理の種類ごとの最大出力トークン数
+	maxTokensLimit int            // 出力が途中で切れた場合に再試行する最大出力トークン数の上限
+	httpClient     *http.Client
+	apiEndpoint    string // テスト用にエンドポイントを差し替え可能に
+	exchangeLog    sharedDomain.AIExchangeLog
 }
 
 // NewClaudeRepository 新しいClaudeRepositoryを作成
 func NewClaudeRepository(cfg *config.AnthropicConfig) *ClaudeRepository {
 	return &ClaudeRepository{
-		apiKey:      cfg.APIKey,
-		model:       cfg.Model,
-		maxTokens:   cfg.MaxTokens,
-		httpClient:  &http.Client{Timeout: 30 * time.Second},
-		apiEndpoint: "https://api.anthropic.com/v1/messages",
+		apiKey:    cfg.APIKey,
+		model:     cfg.Model,
+		maxTokens: cfg.MaxTokens,
+		taskMaxTokens: map[string]int{
+			operationRecognizeReceipt:  cfg.TaskMaxTokens.Receipt,
+			operationCategorizeReceipt: cfg.TaskMaxTokens.Categorize,
+			operationRecognizeImage:    cfg.TaskMaxTokens.Image,
+			operationCorrect:           cfg.TaskMaxTokens.Correct,
+		},
+		maxTokensLimit: cfg.MaxTokensLimit,
+		httpClient:     &http.Client{Timeout: 30 * time.Second},
+		apiEndpoint:    "https://api.anthropic.com/v1/messages",
 	}
 }
 
+// SetTransport 外部への接続に使うTransportを設定（プロキシ・追加のルート証明書など、未設定の場合はhttp.DefaultTransport）
+func (r *ClaudeRepository) SetTransport(transport http.RoundTripper) {
+	r.httpClient.Transport = transport
+}
+
 // SetHTTPClient テスト用にHTTPクライアントを設定（テストコードからのみ使用）
 func (r *ClaudeRepository) SetHTTPClient(client *http.Client) {
 	r.httpClient = client
 }
 
+// SetAPIEndpoint テスト用にAPIエンドポイントを設定（テストコードからのみ使用）
+func (r *ClaudeRepository) SetAPIEndpoint(endpoint string) {
+	r.apiEndpoint = endpoint
+}
+
+// SetExchangeLog デバッグ用にリクエストとレスポンスを記録する記録先を設定（未設定の場合は記録しない）
+func (r *ClaudeRepository) SetExchangeLog(exchangeLog sharedDomain.AIExchangeLog) {
+	r.exchangeLog = exchangeLog
+}
+
 // Correct テキストを補正（汎用）
 func (r *ClaudeRepository) Correct(text string) (*domain.AIResult, error) {
-	requestBody := map[string]interface{}{
-		"model":      r.model,
-		"max_tokens": r.maxTokens,
-		"system":     systemPromptGeneral,
-		"messages": []map[string]interface{}{
-			{
-				"role": "user",
-				"content": []map[string]string{
-					{"type": "text", "text": text},
-				},
-			},
-		},
-	}
-
-	jsonData, err := json.Marshal(requestBody)
+	response, err := r.complete(operationCorrect, func(maxTokens int) (*messagesResponse, error) {
+		return r.sendText(operationCorrect, maxTokens, systemPromptGeneral, text)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", r.apiEndpoint, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+	return r.newAIResult(text, text, response), nil
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", r.apiKey)
-	req.Header.Set("anthropic-version", "2023-06-01")
+// RecognizeImage 画像から直接テキストを認識（汎用）
+func (r *ClaudeRepository) RecognizeImage(imageData []byte) (*domain.AIResult, error) {
+	return r.recognizeImageWithPrompt(imageData, operationRecognizeImage, systemPromptGeneral, userPromptImage)
+}
 
-	resp, err := r.httpClient.Do(req)
+// RecognizeReceipt レシート画像から構造化データを抽出
+func (r *ClaudeRepository) RecognizeReceipt(imageData []byte) (*domain.AIResult, error) {
+	return r.recognizeImageWithPrompt(imageData, operationRecognizeReceipt, systemPromptReceipt, userPromptReceipt)
+}
+
+// CategorizeReceipt レシート情報から適切なカテゴリを判定
+func (r *ClaudeRepository) CategorizeReceipt(receiptInfo string) (*domain.AIResult, error) {
+	response, err := r.complete(operationCategorizeReceipt, func(maxTokens int) (*messagesResponse, error) {
+		return r.sendText(operationCategorizeReceipt, maxTokens, systemPromptCategorize, receiptInfo)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("API request failed: %w", err)
+		return nil, err
 	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
-	}
+	return r.newAIResult(receiptInfo, "", response), nil
+}
 
-	var response struct {
-		Content []struct {
-			Text string `json:"text"`
-		} `json:"content"`
-		Usage struct {
-			InputTokens  int `json:"input_tokens"`
-			OutputTokens int `json:"output_tokens"`
-		} `json:"usage"`
+// recognizeImageWithPrompt 画像認識の共通処理
+func (r *ClaudeRepository) recognizeImageWithPrompt(imageData []byte, operation, systemPrompt, userPrompt string) (*domain.AIResult, error) {
+	mediaType := imageMediaType(imageData)
+	response, err := r.complete(operation, func(maxTokens int) (*messagesResponse, error) {
+		return r.sendImage(operation, maxTokens, systemPrompt, mediaType, imageData, userPrompt)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
+	return r.newAIResult("", "", response), nil
+}
 
-	correctedText := text
+// newAIResult レスポンスからAIの処理結果を作成（応答のテキストがない場合はfallbackTextを結果とする）
+func (r *ClaudeRepository) newAIResult(originalText, fallbackText string, response *messagesResponse) *domain.AIResult {
+	text := fallbackText
 	if len(response.Content) > 0 {
-		correctedText = response.Content[0].Text
+		text = response.Content[0].Text
 	}
 
-	return domain.NewAIResult(
+	result := domain.NewAIResult(
+		originalText,
 		text,
-		correctedText,
 		response.Usage.InputTokens,
 		response.Usage.OutputTokens,
 		r.model,
-	), nil
+	)
+	result.StopReason = response.StopReason
+	return result
 }
 
-// RecognizeImage 画像から直接テキストを認識（汎用）
-func (r *ClaudeRepository) RecognizeImage(imageData []byte) (*domain.AIResult, error) {
-	return r.recognizeImageWithPrompt(imageData, systemPromptGeneral, "この画像からすべてのテキストを抽出してください。")
+// maxTokensFor 処理の種類の最大出力トークン数（処理ごとの設定がない場合は既定値）
+func (r *ClaudeRepository) maxTokensFor(operation string) int {
+	if maxTokens := r.taskMaxTokens[operation]; maxTokens > 0 {
+		return maxTokens
+	}
+	return r.maxTokens
 }
 
-// RecognizeReceipt レシート画像から構造化データを抽出
-func (r *ClaudeRepository) RecognizeReceipt(imageData []byte) (*domain.AIResult, error) {
-	return r.recognizeImageWithPrompt(imageData, systemPromptReceipt, "このレシート画像から情報を抽出してJSON形式で返してください。")
+// complete 処理の種類の最大出力トークン数でリクエストを送信する（途中で切れた場合は上限まで再試行する）
+func (r *ClaudeRepository) complete(operation string, send func(maxTokens int) (*messagesResponse, error)) (*messagesResponse, error) {
+	return completeWithRetry(operation, r.maxTokensFor(operation), r.maxTokensLimit, send)
 }
 
-// CategorizeReceipt レシート情報から適切なカテゴリを判定
-func (r *ClaudeRepository) CategorizeReceipt(receiptInfo string) (*domain.AIResult, error) {
+// completeWithRetry 最大出力トークン数maxTokensでリクエストを送信する
+// 出力が最大出力トークン数に達して途中で切れた場合（明細の多いレシートなど）は、最大出力トークン数を倍にして
+// maxTokensLimitまで再試行する。返すレスポンスのトークン数は再試行を含めた合計
+func completeWithRetry(operation string, maxTokens, maxTokensLimit int, send func(maxTokens int) (*messagesResponse, error)) (*messagesResponse, error) {
+	var inputTokens, outputTokens int
+	for {
+		response, err := send(maxTokens)
+		if err != nil {
+			return nil, err
+		}
+		inputTokens += response.Usage.InputTokens
+		outputTokens += response.Usage.OutputTokens
+		if response.StopReason != stopReasonMaxTokens {
+			response.Usage.InputTokens, response.Usage.OutputTokens = inputTokens, outputTokens
+			return response, nil
+		}
+		if maxTokens >= maxTokensLimit {
+			return nil, fmt.Errorf("%w: %s stopped at %d tokens", ErrOutputTruncated, operation, maxTokens)
+		}
+		slog.Warn("AI output was truncated, retrying with higher max_tokens", "operation", operation, "max_tokens", maxTokens)
+		maxTokens = min(maxTokens*2, maxTokensLimit)
+	}
+}
+
+// sendText テキストのリクエストを送信
+func (r *ClaudeRepository) sendText(operation string, maxTokens int, systemPrompt, text string) (*messagesResponse, error) {
 	requestBody := map[string]interface{}{
 		"model":      r.model,
-		"max_tokens": r.maxTokens,
-		"system":     systemPromptCategorize,
+		"max_tokens": maxTokens,
+		"system":     systemPrompt,
 		"messages": []map[string]interface{}{
 			{
 				"role": "user",
 				"content": []map[string]string{
-					{"type": "text", "text": receiptInfo},
+					{"type": "text", "text": text},
 				},
 			},
 		},
@@ -267,140 +357,138 @@ func (r *ClaudeRepository) CategorizeReceipt(receiptInfo string) (*domain.AIResu
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", r.apiKey)
-	req.Header.Set("anthropic-version", "2023-06-01")
+	return r.send(req, operation, jsonData)
+}
 
-	resp, err := r.httpClient.Do(req)
+// sendImage 画像のリクエストを送信
+func (r *ClaudeRepository) sendImage(operation string, maxTokens int, systemPrompt, mediaType string, imageData []byte, userPrompt string) (*messagesResponse, error) {
+	// 画像のbase64文字列とリクエスト全体をメモリに保持しないよう、リクエストボディを逐次書き込む
+	body, err := newImageRequestBody(r.model, maxTokens, systemPrompt, mediaType, imageData, userPrompt)
 	if err != nil {
-		return nil, fmt.Errorf("API request failed: %w", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
+	// 送信が途中で終わった場合も、戻る前に画像データの読み出しを終える
 	defer func() {
-		_ = resp.Body.Close()
+		_ = body.Close()
 	}()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
-	}
-
-	var response struct {
-		Content []struct {
-			Text string `json:"text"`
-		} `json:"content"`
-		Usage struct {
-			InputTokens  int `json:"input_tokens"`
-			OutputTokens int `json:"output_tokens"`
-		} `json:"usage"`
+	req, err := http.NewRequest("POST", r.apiEndpoint, body.Reader())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	req.ContentLength = body.Len()
+	req.GetBody = func() (io.ReadCloser, error) {
+		return body.Reader(), nil
 	}
 
-	categorizedText := ""
-	if len(response.Content) > 0 {
-		categorizedText = response.Content[0].Text
+	var requestLog []byte
+	if r.exchangeLog != nil {
+		requestLog = body.Elided()
 	}
-
-	return domain.NewAIResult(
-		receiptInfo,
-		categorizedText,
-		response.Usage.InputTokens,
-		response.Usage.OutputTokens,
-		r.model,
-	), nil
+	return r.send(req, operation, requestLog)
 }
 
-// recognizeImageWithPrompt 画像認識の共通処理
-func (r *ClaudeRepository) recognizeImageWithPrompt(imageData []byte, systemPrompt, userPrompt string) (*domain.AIResult, error) {
-	// 画像をbase64エンコード
-	imageBase64 := base64.StdEncoding.EncodeToString(imageData)
+// messagesResponse Messages APIのレスポンス
+type messagesResponse struct {
+	Content []struct {
+		Text string `json:"text"`
+	} `json:"content"`
+	StopReason string `json:"stop_reason"` // 出力が途中で切れた場合は "max_tokens"
+	Usage      struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
 
-	// 画像の形式を判定（簡易版）
-	mediaType := "image/png"
-	if len(imageData) > 2 && imageData[0] == 0xFF && imageData[1] == 0xD8 {
-		mediaType = "image/jpeg"
-	}
+// send リクエストを送信してレスポンスをデコードする
+// デバッグ用の記録先を設定した場合は、requestLog（画像データを省略したリクエストボディ）とレスポンスボディを記録する
+func (r *ClaudeRepository) send(req *http.Request, operation string, requestLog []byte) (*messagesResponse, error) {
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", r.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
 
-	requestBody := map[string]interface{}{
-		"model":      r.model,
-		"max_tokens": r.maxTokens,
-		"system":     systemPrompt,
-		"messages": []map[string]interface{}{
-			{
-				"role": "user",
-				"content": []map[string]interface{}{
-					{
-						"type": "image",
-						"source": map[string]string{
-							"type":       "base64",
-							"media_type": mediaType,
-							"data":       imageBase64,
-						},
-					},
-					{
-						"type": "text",
-						"text": userPrompt,
-					},
-				},
-			},
-		},
+	if r.exchangeLog == nil {
+		response, _, err := r.do(req, nil)
+		return response, err
 	}
 
-	jsonData, err := json.Marshal(requestBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	start := time.Now()
+	var captured bytes.Buffer
+	response, statusCode, err := r.do(req, &captured)
+	exchange := sharedDomain.AIExchange{
+		At:         start,
+		Operation:  operation,
+		Model:      r.model,
+		Request:    requestLog,
+		StatusCode: statusCode,
+		Response:   captured.String(),
+		DurationMs: time.Since(start).Milliseconds(),
 	}
-
-	req, err := http.NewRequest("POST", r.apiEndpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		exchange.Error = err.Error()
 	}
+	r.exchangeLog.Record(exchange)
+	return response, err
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", r.apiKey)
-	req.Header.Set("anthropic-version", "2023-06-01")
-
+// do リクエストを送信し、レスポンスとステータスコードを返す（captureを指定した場合は読み出したレスポンスボディを書き込む）
+func (r *ClaudeRepository) do(req *http.Request, capture io.Writer) (*messagesResponse, int, error) {
 	resp, err := r.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("API request failed: %w", err)
+		return nil, 0, fmt.Errorf("API request failed: %w", err)
 	}
 	defer func() {
 		_ = resp.Body.Close()
 	}()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	var reader io.Reader = resp.Body
+	if capture != nil {
+		reader = io.TeeReader(resp.Body, capture)
 	}
 
-	var response struct {
-		Content []struct {
-			Text string `json:"text"`
-		} `json:"content"`
-		Usage struct {
-			InputTokens  int `json:"input_tokens"`
-			OutputTokens int `json:"output_tokens"`
-		} `json:"usage"`
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(reader)
+		return nil, resp.StatusCode, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	var response messagesResponse
+	if err := json.NewDecoder(reader).Decode(&response); err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("failed to decode response: %w", err)
 	}
+	return &response, resp.StatusCode, nil
+}
 
-	recognizedText := ""
-	if len(response.Content) > 0 {
-		recognizedText = response.Content[0].Text
+// PromptVersion 処理の種類のモデル・プロンプトのハッシュ（AIの解析結果のキャッシュキーに含める）
+// プロンプトを書き換えた場合・モデルを変えた場合は版が変わり、以前の解析結果のキャッシュを使わない
+func (r *ClaudeRepository) PromptVersion(operation string) string {
+	return promptVersion(r.model, operation)
+}
+
+// promptVersion モデルと処理の種類のプロンプトのハッシュ（キャッシュする処理以外は空）
+func promptVersion(model, operation string) string {
+	var prompts []string
+	switch operation {
+	case operationRecognizeImage:
+		prompts = []string{systemPromptGeneral, userPromptImage}
+	case operationRecognizeReceipt:
+		prompts = []string{systemPromptReceipt, userPromptReceipt}
+	default:
+		return ""
+	}
+	hash := sha256.New()
+	for _, part := range append([]string{model}, prompts...) {
+		// 区切りを含めて、連結した結果が同じになる組み合わせを区別する
+		fmt.Fprintf(hash, "%d:%s", len(part), part)
 	}
+	return hex.EncodeToString(hash.Sum(nil))[:promptVersionLength]
+}
 
-	return domain.NewAIResult(
-		"",
-		recognizedText,
-		response.Usage.InputTokens,
-		response.Usage.OutputTokens,
-		r.model,
-	), nil
+// imageMediaType 画像の形式を判定（簡易版、JPEG以外はPNGとする）
+func imageMediaType(imageData []byte) string {
+	if len(imageData) > 2 && imageData[0] == 0xFF && imageData[1] == 0xD8 {
+		return "image/jpeg"
+	}
+	return "image/png"
 }
 
 // ProviderName プロバイダー名を返す
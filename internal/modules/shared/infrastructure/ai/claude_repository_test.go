@@ -0,0 +1,215 @@
+//go:build !no_ai
+
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"vision-api-app/internal/config"
+	"vision-api-app/internal/modules/shared/infrastructure/testsupport"
+)
+
+func newTestClaudeRepository(t *testing.T, server *testsupport.FakeAnthropicServer) *ClaudeRepository {
+	t.Helper()
+
+	repo := NewClaudeRepository(&config.AnthropicConfig{
+		APIKey:    "test-key",
+		Model:     "claude-haiku-4-5-20251001",
+		MaxTokens: 4096,
+	})
+	repo.SetHTTPClient(server.Client())
+	repo.SetAPIEndpoint(server.URL())
+	return repo
+}
+
+func TestClaudeRepository_RecognizeReceipt_Golden(t *testing.T) {
+	server := testsupport.NewFakeAnthropicServer(t, filepath.Join("testdata", "golden"))
+	repo := newTestClaudeRepository(t, server)
+
+	result, err := repo.RecognizeReceipt([]byte("receipt-image"))
+	if err != nil {
+		t.Fatalf("RecognizeReceipt() error = %v", err)
+	}
+
+	if !strings.Contains(result.CorrectedText, `"store_name":"テストマート"`) {
+		t.Errorf("CorrectedText = %s, want golden receipt JSON", result.CorrectedText)
+	}
+	if result.InputTokens != 1350 || result.OutputTokens != 280 {
+		t.Errorf("tokens = (%d, %d), want (1350, 280)", result.InputTokens, result.OutputTokens)
+	}
+	if server.RequestCount() != 1 {
+		t.Errorf("RequestCount() = %d, want 1", server.RequestCount())
+	}
+}
+
+func TestClaudeRepository_CategorizeReceipt_Golden(t *testing.T) {
+	server := testsupport.NewFakeAnthropicServer(t, filepath.Join("testdata", "golden"))
+	repo := newTestClaudeRepository(t, server)
+
+	result, err := repo.CategorizeReceipt("店名: テストマート\n1. 牛乳\n")
+	if err != nil {
+		t.Fatalf("CategorizeReceipt() error = %v", err)
+	}
+
+	if result.CorrectedText != `["食費"]` {
+		t.Errorf("CorrectedText = %s, want [\"食費\"]", result.CorrectedText)
+	}
+	if result.OriginalText != "店名: テストマート\n1. 牛乳\n" {
+		t.Errorf("OriginalText = %q", result.OriginalText)
+	}
+}
+
+func TestClaudeRepository_Register(t *testing.T) {
+	server := testsupport.NewFakeAnthropicServer(t, "")
+	server.SetFallback(testsupport.MessageResponse("抽出テキスト", 10, 5))
+	repo := newTestClaudeRepository(t, server)
+
+	result, err := repo.Correct("入力テキスト")
+	if err != nil {
+		t.Fatalf("Correct() error = %v", err)
+	}
+	if result.CorrectedText != "抽出テキスト" {
+		t.Errorf("CorrectedText = %s, want 抽出テキスト", result.CorrectedText)
+	}
+
+	// 記録したリクエストを登録すると同じリクエストに対して登録済みレスポンスが優先される
+	requests := server.Requests()
+	server.Register(requests[0], testsupport.MessageResponse("登録済み", 1, 1))
+
+	result, err = repo.Correct("入力テキスト")
+	if err != nil {
+		t.Fatalf("Correct() error = %v", err)
+	}
+	if result.CorrectedText != "登録済み" {
+		t.Errorf("CorrectedText = %s, want 登録済み", result.CorrectedText)
+	}
+}
+
+func TestClaudeRepository_TruncatedOutput(t *testing.T) {
+	server := testsupport.NewFakeAnthropicServer(t, "")
+	server.SetFallback(testsupport.TruncatedMessageResponse(`{"store_name":"テスト`, 10, 100))
+	repo := NewClaudeRepository(&config.AnthropicConfig{
+		APIKey:         "test-key",
+		Model:          "claude-haiku-4-5-20251001",
+		MaxTokens:      4096,
+		TaskMaxTokens:  config.AnthropicTaskMaxTokens{Correct: 100},
+		MaxTokensLimit: 400,
+	})
+	repo.SetHTTPClient(server.Client())
+	repo.SetAPIEndpoint(server.URL())
+
+	// 上限まで倍にしても途中で切れる場合はエラー
+	_, err := repo.Correct("入力テキスト")
+	if !errors.Is(err, ErrOutputTruncated) {
+		t.Fatalf("Correct() error = %v, want ErrOutputTruncated", err)
+	}
+	requests := server.Requests()
+	var maxTokens []int
+	for _, request := range requests {
+		var body struct {
+			MaxTokens int `json:"max_tokens"`
+		}
+		if err := json.Unmarshal(request, &body); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		maxTokens = append(maxTokens, body.MaxTokens)
+	}
+	if !slices.Equal(maxTokens, []int{100, 200, 400}) {
+		t.Errorf("max_tokens = %v, want [100 200 400]", maxTokens)
+	}
+
+	// 最大出力トークン数を増やして出力が収まった場合は、再試行を含めたトークン数を返す
+	server.Register(requests[1], testsupport.MessageResponse("補正済み", 10, 150))
+	result, err := repo.Correct("入力テキスト")
+	if err != nil {
+		t.Fatalf("Correct() error = %v", err)
+	}
+	if result.CorrectedText != "補正済み" || result.InputTokens != 20 || result.OutputTokens != 250 {
+		t.Errorf("result = %q (%d, %d), want 補正済み (20, 250)", result.CorrectedText, result.InputTokens, result.OutputTokens)
+	}
+	if result.StopReason != "end_turn" || result.Model != "claude-haiku-4-5-20251001" {
+		t.Errorf("result = (%q, %q), want (end_turn, claude-haiku-4-5-20251001)", result.StopReason, result.Model)
+	}
+}
+
+func TestClaudeRepository_MissingGolden(t *testing.T) {
+	server := testsupport.NewFakeAnthropicServer(t, filepath.Join("testdata", "golden"))
+	repo := newTestClaudeRepository(t, server)
+
+	_, err := repo.RecognizeImage([]byte("unknown-image"))
+	if err == nil {
+		t.Fatal("Expected error for missing golden response")
+	}
+	if !strings.Contains(err.Error(), "no golden response for request hash") {
+		t.Errorf("error = %v, want missing golden message", err)
+	}
+}
+
+func TestClaudeRepository_ExchangeLog(t *testing.T) {
+	server := testsupport.NewFakeAnthropicServer(t, filepath.Join("testdata", "golden"))
+	repo := newTestClaudeRepository(t, server)
+	exchangeLog, err := NewExchangeLog(10, "")
+	if err != nil {
+		t.Fatalf("NewExchangeLog() error = %v", err)
+	}
+	repo.SetExchangeLog(exchangeLog)
+
+	// 記録してもリクエストのバイト列は変わらない（ゴールデンファイルのハッシュが一致する）
+	if _, err := repo.RecognizeReceipt([]byte("receipt-image")); err != nil {
+		t.Fatalf("RecognizeReceipt() error = %v", err)
+	}
+	if _, err := repo.RecognizeImage([]byte("unknown-image")); err == nil {
+		t.Fatal("Expected error for missing golden response")
+	}
+
+	exchanges := exchangeLog.Recent(0)
+	if len(exchanges) != 2 {
+		t.Fatalf("Recent() = %d exchanges, want 2", len(exchanges))
+	}
+
+	failed, recognized := exchanges[0], exchanges[1]
+	if recognized.Operation != operationRecognizeReceipt || recognized.StatusCode != 200 || recognized.Error != "" {
+		t.Errorf("exchange = %+v, want successful recognize_receipt", recognized)
+	}
+	if !strings.Contains(recognized.Response, "テストマート") {
+		t.Errorf("Response = %s, want golden response body", recognized.Response)
+	}
+	request := string(recognized.Request)
+	if !strings.Contains(request, "<image elided: 13 bytes>") || strings.Contains(request, "cmVjZWlwdC1pbWFnZQ==") {
+		t.Errorf("Request = %s, want image data elided", request)
+	}
+	if !json.Valid(recognized.Request) {
+		t.Errorf("Request = %s, want valid JSON", request)
+	}
+
+	if failed.Operation != operationRecognizeImage || failed.Error == "" || failed.StatusCode == 200 {
+		t.Errorf("exchange = %+v, want failed recognize_image", failed)
+	}
+}
+
+func TestClaudeRepository_PromptVersion(t *testing.T) {
+	haiku := NewClaudeRepository(&config.AnthropicConfig{Model: "claude-haiku-4-5-20251001"})
+	sonnet := NewClaudeRepository(&config.AnthropicConfig{Model: "claude-sonnet-4-5"})
+
+	version := haiku.PromptVersion(operationRecognizeReceipt)
+	if len(version) != promptVersionLength {
+		t.Fatalf("expected %d characters, got %q", promptVersionLength, version)
+	}
+	if again := haiku.PromptVersion(operationRecognizeReceipt); again != version {
+		t.Errorf("expected stable version, got %q and %q", version, again)
+	}
+	if other := sonnet.PromptVersion(operationRecognizeReceipt); other == version {
+		t.Error("expected different versions for different models")
+	}
+	if image := haiku.PromptVersion(operationRecognizeImage); image == version {
+		t.Error("expected different versions for different operations")
+	}
+	if got := haiku.PromptVersion(operationCorrect); got != "" {
+		t.Errorf("expected empty version for unversioned operation, got %q", got)
+	}
+}
@@ -0,0 +1,100 @@
+//go:build !no_ai
+
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+// ExchangeLog AI APIへのリクエストとレスポンスを直近の件数だけメモリに保持するリングバッファ
+// ファイルを指定した場合は、あわせてJSON Lines形式で追記する
+type ExchangeLog struct {
+	mu      sync.Mutex
+	entries []domain.AIExchange
+	next    int // 次に書き込む位置
+	full    bool
+	file    *os.File
+}
+
+// NewExchangeLog 新しいExchangeLogを作成（pathが空の場合はメモリのみに保持）
+func NewExchangeLog(capacity int, path string) (*ExchangeLog, error) {
+	if capacity <= 0 {
+		return nil, fmt.Errorf("invalid AI debug log capacity: %d", capacity)
+	}
+	l := &ExchangeLog{entries: make([]domain.AIExchange, capacity)}
+	if path != "" {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open AI debug log file: %w", err)
+		}
+		l.file = file
+	}
+	return l, nil
+}
+
+// Record リクエストとレスポンスを記録する（容量を超えた場合は古いものから上書き）
+func (l *ExchangeLog) Record(exchange domain.AIExchange) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries[l.next] = exchange
+	l.next = (l.next + 1) % len(l.entries)
+	if l.next == 0 {
+		l.full = true
+	}
+
+	if l.file == nil {
+		return
+	}
+	line, err := json.Marshal(exchange)
+	if err == nil {
+		_, err = l.file.Write(append(line, '\n'))
+	}
+	if err != nil {
+		slog.Warn("Failed to write AI debug log", "error", err)
+	}
+}
+
+// Recent 新しい順に最大limit件の記録を返す（limitが0以下の場合はすべて）
+func (l *ExchangeLog) Recent(limit int) []domain.AIExchange {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := l.next
+	if l.full {
+		n = len(l.entries)
+	}
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	recent := make([]domain.AIExchange, 0, n)
+	for i := 1; i <= n; i++ {
+		recent = append(recent, l.entries[(l.next-i+len(l.entries))%len(l.entries)])
+	}
+	return recent
+}
+
+// Clear 記録をすべて削除する（ファイルに追記した記録は残す）
+func (l *ExchangeLog) Clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	clear(l.entries)
+	l.next = 0
+	l.full = false
+}
+
+// Close ファイルを閉じる
+func (l *ExchangeLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
@@ -0,0 +1,129 @@
+//go:build !no_ai
+
+package ai
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+func TestExchangeLog_Recent(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		records  int
+		limit    int
+		want     []string
+	}{
+		{
+			name:     "正常系: 新しい順に返す",
+			capacity: 5,
+			records:  3,
+			want:     []string{"op2", "op1", "op0"},
+		},
+		{
+			name:     "正常系: 容量を超えると古いものから上書きする",
+			capacity: 3,
+			records:  5,
+			want:     []string{"op4", "op3", "op2"},
+		},
+		{
+			name:     "正常系: 件数を指定する",
+			capacity: 3,
+			records:  5,
+			limit:    2,
+			want:     []string{"op4", "op3"},
+		},
+		{
+			name:     "正常系: 記録がない",
+			capacity: 3,
+			want:     []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := NewExchangeLog(tt.capacity, "")
+			if err != nil {
+				t.Fatalf("NewExchangeLog() error = %v", err)
+			}
+			for i := range tt.records {
+				l.Record(domain.AIExchange{Operation: "op" + string(rune('0'+i))})
+			}
+
+			got := l.Recent(tt.limit)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Recent() = %d exchanges, want %d", len(got), len(tt.want))
+			}
+			for i, op := range tt.want {
+				if got[i].Operation != op {
+					t.Errorf("Recent()[%d].Operation = %s, want %s", i, got[i].Operation, op)
+				}
+			}
+		})
+	}
+}
+
+func TestExchangeLog_Clear(t *testing.T) {
+	l, err := NewExchangeLog(2, "")
+	if err != nil {
+		t.Fatalf("NewExchangeLog() error = %v", err)
+	}
+	l.Record(domain.AIExchange{Operation: "a"})
+	l.Record(domain.AIExchange{Operation: "b"})
+	l.Record(domain.AIExchange{Operation: "c"})
+	l.Clear()
+	if got := l.Recent(0); len(got) != 0 {
+		t.Fatalf("Recent() after Clear() = %d exchanges, want 0", len(got))
+	}
+
+	l.Record(domain.AIExchange{Operation: "d"})
+	if got := l.Recent(0); len(got) != 1 || got[0].Operation != "d" {
+		t.Errorf("Recent() = %+v, want [d]", got)
+	}
+}
+
+func TestExchangeLog_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ai_debug.jsonl")
+	l, err := NewExchangeLog(1, path)
+	if err != nil {
+		t.Fatalf("NewExchangeLog() error = %v", err)
+	}
+	l.Record(domain.AIExchange{Operation: "a", Request: json.RawMessage(`{"model":"m"}`)})
+	l.Record(domain.AIExchange{Operation: "b", Request: json.RawMessage(`{"model":"m"}`)})
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// メモリの容量を超えてもファイルにはすべて追記する
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	var ops []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var exchange domain.AIExchange
+		if err := json.Unmarshal(scanner.Bytes(), &exchange); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		ops = append(ops, exchange.Operation)
+	}
+	if len(ops) != 2 || ops[0] != "a" || ops[1] != "b" {
+		t.Errorf("file operations = %v, want [a b]", ops)
+	}
+}
+
+func TestNewExchangeLog_InvalidCapacity(t *testing.T) {
+	if _, err := NewExchangeLog(0, ""); err == nil {
+		t.Error("Expected error for zero capacity")
+	}
+}
@@ -0,0 +1,311 @@
+//go:build !no_ai
+
+package ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"vision-api-app/internal/config"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+	"vision-api-app/internal/modules/vision/domain"
+)
+
+// geminiFinishReasonMaxTokens 出力が最大出力トークン数に達して途中で切れたことを示すfinishReason
+const geminiFinishReasonMaxTokens = "MAX_TOKENS"
+
+// GeminiRepository Google Gemini APIのリポジトリ実装
+// プロンプトはClaudeRepositoryと共通で、応答の終了理由・トークン数はClaudeRepositoryと同じ形に変換する
+type GeminiRepository struct {
+	apiKey         string
+	model          string
+	maxTokens      int
+	maxTokensLimit int // 出力が途中で切れた場合に再試行する最大出力トークン数の上限
+	httpClient     *http.Client
+	apiEndpoint    string // モデル名を含まないエンドポイント（テスト用に差し替え可能に）
+	exchangeLog    sharedDomain.AIExchangeLog
+}
+
+// NewGeminiRepository 新しいGeminiRepositoryを作成
+func NewGeminiRepository(cfg *config.GeminiConfig) *GeminiRepository {
+	return &GeminiRepository{
+		apiKey:         cfg.APIKey,
+		model:          cfg.Model,
+		maxTokens:      cfg.MaxTokens,
+		maxTokensLimit: cfg.MaxTokensLimit,
+		httpClient:     &http.Client{Timeout: 60 * time.Second},
+		apiEndpoint:    "https://generativelanguage.googleapis.com/v1beta/models",
+	}
+}
+
+// SetTransport 外部への接続に使うTransportを設定（プロキシ・追加のルート証明書など、未設定の場合はhttp.DefaultTransport）
+func (r *GeminiRepository) SetTransport(transport http.RoundTripper) {
+	r.httpClient.Transport = transport
+}
+
+// SetHTTPClient テスト用にHTTPクライアントを設定（テストコードからのみ使用）
+func (r *GeminiRepository) SetHTTPClient(client *http.Client) {
+	r.httpClient = client
+}
+
+// SetAPIEndpoint テスト用にAPIエンドポイントを設定（テストコードからのみ使用）
+func (r *GeminiRepository) SetAPIEndpoint(endpoint string) {
+	r.apiEndpoint = endpoint
+}
+
+// SetExchangeLog デバッグ用にリクエストとレスポンスを記録する記録先を設定（未設定の場合は記録しない）
+func (r *GeminiRepository) SetExchangeLog(exchangeLog sharedDomain.AIExchangeLog) {
+	r.exchangeLog = exchangeLog
+}
+
+// Correct テキストを補正（汎用）
+func (r *GeminiRepository) Correct(text string) (*domain.AIResult, error) {
+	response, err := r.generate(operationCorrect, systemPromptGeneral, geminiPart{Text: text})
+	if err != nil {
+		return nil, err
+	}
+	return r.newAIResult(text, text, response), nil
+}
+
+// RecognizeImage 画像から直接テキストを認識（汎用）
+func (r *GeminiRepository) RecognizeImage(imageData []byte) (*domain.AIResult, error) {
+	return r.recognizeImageWithPrompt(imageData, operationRecognizeImage, systemPromptGeneral, userPromptImage)
+}
+
+// RecognizeReceipt レシート画像から構造化データを抽出
+func (r *GeminiRepository) RecognizeReceipt(imageData []byte) (*domain.AIResult, error) {
+	return r.recognizeImageWithPrompt(imageData, operationRecognizeReceipt, systemPromptReceipt, userPromptReceipt)
+}
+
+// CategorizeReceipt レシート情報から適切なカテゴリを判定
+func (r *GeminiRepository) CategorizeReceipt(receiptInfo string) (*domain.AIResult, error) {
+	response, err := r.generate(operationCategorizeReceipt, systemPromptCategorize, geminiPart{Text: receiptInfo})
+	if err != nil {
+		return nil, err
+	}
+	return r.newAIResult(receiptInfo, "", response), nil
+}
+
+// PromptVersion 処理の種類のモデル・プロンプトのハッシュ（AIの解析結果のキャッシュキーに含める）
+func (r *GeminiRepository) PromptVersion(operation string) string {
+	return promptVersion(r.model, operation)
+}
+
+// ProviderName プロバイダー名を返す
+func (r *GeminiRepository) ProviderName() string {
+	return "Google Gemini"
+}
+
+// recognizeImageWithPrompt 画像認識の共通処理
+func (r *GeminiRepository) recognizeImageWithPrompt(imageData []byte, operation, systemPrompt, userPrompt string) (*domain.AIResult, error) {
+	image := geminiPart{InlineData: &geminiInlineData{MimeType: imageMediaType(imageData), Data: imageData}}
+	response, err := r.generate(operation, systemPrompt, image, geminiPart{Text: userPrompt})
+	if err != nil {
+		return nil, err
+	}
+	return r.newAIResult("", "", response), nil
+}
+
+// newAIResult レスポンスからAIの処理結果を作成（応答のテキストがない場合はfallbackTextを結果とする）
+func (r *GeminiRepository) newAIResult(originalText, fallbackText string, response *messagesResponse) *domain.AIResult {
+	text := fallbackText
+	if len(response.Content) > 0 {
+		text = response.Content[0].Text
+	}
+
+	result := domain.NewAIResult(
+		originalText,
+		text,
+		response.Usage.InputTokens,
+		response.Usage.OutputTokens,
+		r.model,
+	)
+	result.StopReason = response.StopReason
+	return result
+}
+
+// generate システムプロンプトと利用者のメッセージを送信（途中で切れた場合は上限まで再試行する）
+func (r *GeminiRepository) generate(operation, systemPrompt string, parts ...geminiPart) (*messagesResponse, error) {
+	return completeWithRetry(operation, r.maxTokens, r.maxTokensLimit, func(maxTokens int) (*messagesResponse, error) {
+		request := geminiRequest{
+			SystemInstruction: &geminiContent{Parts: []geminiPart{{Text: systemPrompt}}},
+			Contents:          []geminiContent{{Role: "user", Parts: parts}},
+			GenerationConfig:  geminiGenerationConfig{MaxOutputTokens: maxTokens},
+		}
+		return r.send(operation, request)
+	})
+}
+
+// geminiRequest generateContentのリクエスト
+type geminiRequest struct {
+	SystemInstruction *geminiContent         `json:"systemInstruction,omitempty"`
+	Contents          []geminiContent        `json:"contents"`
+	GenerationConfig  geminiGenerationConfig `json:"generationConfig"`
+}
+
+// geminiContent メッセージ（ロールと部品の並び）
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+// geminiPart メッセージの部品（テキスト・画像）
+type geminiPart struct {
+	Text       string            `json:"text,omitempty"`
+	InlineData *geminiInlineData `json:"inlineData,omitempty"`
+	Thought    bool              `json:"thought,omitempty"` // 思考の要約（応答のテキストに含めない）
+}
+
+// geminiInlineData リクエストに埋め込む画像（JSONではbase64文字列）
+type geminiInlineData struct {
+	MimeType string `json:"mimeType"`
+	Data     []byte `json:"data"`
+}
+
+// geminiGenerationConfig 生成の設定
+type geminiGenerationConfig struct {
+	MaxOutputTokens int `json:"maxOutputTokens"`
+}
+
+// geminiResponse generateContentのレスポンス
+type geminiResponse struct {
+	Candidates []struct {
+		Content      geminiContent `json:"content"`
+		FinishReason string        `json:"finishReason"`
+	} `json:"candidates"`
+	PromptFeedback struct {
+		BlockReason string `json:"blockReason"`
+	} `json:"promptFeedback"`
+	UsageMetadata struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+		ThoughtsTokenCount   int `json:"thoughtsTokenCount"` // 思考に使ったトークン数（出力トークンとして課金される）
+	} `json:"usageMetadata"`
+}
+
+// toMessagesResponse ClaudeRepositoryと同じ形（応答のテキスト・終了理由・トークン数）に変換
+// 終了理由は途中で切れた場合をmax_tokens、正常に終わった場合をend_turnとし、それ以外は小文字にする
+func (g *geminiResponse) toMessagesResponse() (*messagesResponse, error) {
+	if len(g.Candidates) == 0 {
+		if g.PromptFeedback.BlockReason != "" {
+			return nil, fmt.Errorf("API blocked the prompt: %s", g.PromptFeedback.BlockReason)
+		}
+		return nil, fmt.Errorf("API returned no candidates")
+	}
+
+	candidate := g.Candidates[0]
+	var text strings.Builder
+	for _, part := range candidate.Content.Parts {
+		if !part.Thought {
+			text.WriteString(part.Text)
+		}
+	}
+
+	response := &messagesResponse{}
+	response.Content = append(response.Content, struct {
+		Text string `json:"text"`
+	}{Text: text.String()})
+	switch candidate.FinishReason {
+	case geminiFinishReasonMaxTokens:
+		response.StopReason = stopReasonMaxTokens
+	case "STOP":
+		response.StopReason = "end_turn"
+	default:
+		response.StopReason = strings.ToLower(candidate.FinishReason)
+	}
+	response.Usage.InputTokens = g.UsageMetadata.PromptTokenCount
+	response.Usage.OutputTokens = g.UsageMetadata.CandidatesTokenCount + g.UsageMetadata.ThoughtsTokenCount
+	return response, nil
+}
+
+// send リクエストを送信してレスポンスを変換する
+// デバッグ用の記録先を設定した場合は、画像データを省略したリクエストボディとレスポンスボディを記録する
+func (r *GeminiRepository) send(operation string, request geminiRequest) (*messagesResponse, error) {
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequest("POST", r.apiEndpoint+"/"+r.model+":generateContent", bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-goog-api-key", r.apiKey)
+
+	if r.exchangeLog == nil {
+		response, _, err := r.do(req, nil)
+		return response, err
+	}
+
+	start := time.Now()
+	var captured bytes.Buffer
+	response, statusCode, err := r.do(req, &captured)
+	exchange := sharedDomain.AIExchange{
+		At:         start,
+		Operation:  operation,
+		Model:      r.model,
+		Request:    elideGeminiImages(request),
+		StatusCode: statusCode,
+		Response:   captured.String(),
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		exchange.Error = err.Error()
+	}
+	r.exchangeLog.Record(exchange)
+	return response, err
+}
+
+// do リクエストを送信し、レスポンスとステータスコードを返す（captureを指定した場合は読み出したレスポンスボディを書き込む）
+func (r *GeminiRepository) do(req *http.Request, capture io.Writer) (*messagesResponse, int, error) {
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("API request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var reader io.Reader = resp.Body
+	if capture != nil {
+		reader = io.TeeReader(resp.Body, capture)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(reader)
+		return nil, resp.StatusCode, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var response geminiResponse
+	if err := json.NewDecoder(reader).Decode(&response); err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("failed to decode response: %w", err)
+	}
+	converted, err := response.toMessagesResponse()
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+	return converted, resp.StatusCode, nil
+}
+
+// elideGeminiImages 画像データを省略したリクエストボディ（デバッグ用の記録に使う）
+func elideGeminiImages(request geminiRequest) []byte {
+	contents := make([]geminiContent, 0, len(request.Contents))
+	for _, content := range request.Contents {
+		parts := make([]geminiPart, 0, len(content.Parts))
+		for _, part := range content.Parts {
+			if part.InlineData != nil {
+				part = geminiPart{Text: fmt.Sprintf("<image elided: %d bytes>", len(part.InlineData.Data))}
+			}
+			parts = append(parts, part)
+		}
+		contents = append(contents, geminiContent{Role: content.Role, Parts: parts})
+	}
+	request.Contents = contents
+	data, _ := json.Marshal(request)
+	return data
+}
@@ -0,0 +1,155 @@
+//go:build !no_ai
+
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	"vision-api-app/internal/config"
+)
+
+// fakeGeminiServer generateContentのリクエストを記録し、最大出力トークン数に応じたレスポンスを返すテスト用サーバー
+type fakeGeminiServer struct {
+	mu       sync.Mutex
+	requests []geminiRequest
+	paths    []string
+	apiKeys  []string
+	respond  func(maxOutputTokens int) string
+}
+
+func newTestGeminiRepository(t *testing.T, fake *fakeGeminiServer, cfg *config.GeminiConfig) *GeminiRepository {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var request geminiRequest
+		if err := json.Unmarshal(body, &request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fake.mu.Lock()
+		fake.requests = append(fake.requests, request)
+		fake.paths = append(fake.paths, r.URL.Path)
+		fake.apiKeys = append(fake.apiKeys, r.Header.Get("x-goog-api-key"))
+		fake.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, fake.respond(request.GenerationConfig.MaxOutputTokens))
+	}))
+	t.Cleanup(server.Close)
+
+	repo := NewGeminiRepository(cfg)
+	repo.SetHTTPClient(server.Client())
+	repo.SetAPIEndpoint(server.URL + "/v1beta/models")
+	return repo
+}
+
+func TestGeminiRepository_RecognizeReceipt(t *testing.T) {
+	fake := &fakeGeminiServer{respond: func(int) string {
+		return `{
+			"candidates": [{"content": {"role": "model", "parts": [{"text": "考え中", "thought": true}, {"text": "{\"store_name\":"}, {"text": "\"テストマート\"}"}]}, "finishReason": "STOP"}],
+			"usageMetadata": {"promptTokenCount": 1200, "candidatesTokenCount": 80, "thoughtsTokenCount": 40}
+		}`
+	}}
+	repo := newTestGeminiRepository(t, fake, &config.GeminiConfig{APIKey: "test-key", Model: "gemini-2.5-flash", MaxTokens: 8192})
+
+	result, err := repo.RecognizeReceipt([]byte("\x89PNG\r\n\x1a\nreceipt-image"))
+	if err != nil {
+		t.Fatalf("RecognizeReceipt() error = %v", err)
+	}
+
+	// 思考の要約を除いた部品をつなげ、思考に使ったトークン数は出力トークンに含める
+	if result.CorrectedText != `{"store_name":"テストマート"}` {
+		t.Errorf("CorrectedText = %s", result.CorrectedText)
+	}
+	if result.InputTokens != 1200 || result.OutputTokens != 120 || result.TotalTokens() != 1320 {
+		t.Errorf("tokens = (%d, %d, %d), want (1200, 120, 1320)", result.InputTokens, result.OutputTokens, result.TotalTokens())
+	}
+	if result.StopReason != "end_turn" || result.Model != "gemini-2.5-flash" {
+		t.Errorf("result = (%q, %q), want (end_turn, gemini-2.5-flash)", result.StopReason, result.Model)
+	}
+
+	if len(fake.requests) != 1 {
+		t.Fatalf("requests = %d, want 1", len(fake.requests))
+	}
+	if fake.paths[0] != "/v1beta/models/gemini-2.5-flash:generateContent" || fake.apiKeys[0] != "test-key" {
+		t.Errorf("request = (%s, %s)", fake.paths[0], fake.apiKeys[0])
+	}
+	request := fake.requests[0]
+	if request.SystemInstruction == nil || request.SystemInstruction.Parts[0].Text != systemPromptReceipt {
+		t.Error("Expected receipt system instruction")
+	}
+	parts := request.Contents[0].Parts
+	if len(parts) != 2 || parts[0].InlineData == nil || parts[0].InlineData.MimeType != "image/png" || parts[1].Text != userPromptReceipt {
+		t.Errorf("parts = %+v", parts)
+	}
+	if request.GenerationConfig.MaxOutputTokens != 8192 {
+		t.Errorf("maxOutputTokens = %d, want 8192", request.GenerationConfig.MaxOutputTokens)
+	}
+}
+
+func TestGeminiRepository_TruncatedOutput(t *testing.T) {
+	fake := &fakeGeminiServer{respond: func(maxOutputTokens int) string {
+		if maxOutputTokens < 200 {
+			return `{"candidates": [{"content": {"parts": [{"text": "補正"}]}, "finishReason": "MAX_TOKENS"}], "usageMetadata": {"promptTokenCount": 10, "candidatesTokenCount": 100}}`
+		}
+		return `{"candidates": [{"content": {"parts": [{"text": "補正済み"}]}, "finishReason": "STOP"}], "usageMetadata": {"promptTokenCount": 10, "candidatesTokenCount": 150}}`
+	}}
+	repo := newTestGeminiRepository(t, fake, &config.GeminiConfig{APIKey: "test-key", Model: "gemini-2.5-flash", MaxTokens: 100, MaxTokensLimit: 400})
+
+	// 最大出力トークン数を増やして出力が収まった場合は、再試行を含めたトークン数を返す
+	result, err := repo.Correct("入力テキスト")
+	if err != nil {
+		t.Fatalf("Correct() error = %v", err)
+	}
+	if result.CorrectedText != "補正済み" || result.InputTokens != 20 || result.OutputTokens != 250 {
+		t.Errorf("result = %q (%d, %d), want 補正済み (20, 250)", result.CorrectedText, result.InputTokens, result.OutputTokens)
+	}
+
+	// 上限まで倍にしても途中で切れる場合はエラー
+	fake.requests = nil
+	fake.respond = func(int) string {
+		return `{"candidates": [{"content": {"parts": [{"text": "補正"}]}, "finishReason": "MAX_TOKENS"}], "usageMetadata": {"promptTokenCount": 10, "candidatesTokenCount": 100}}`
+	}
+	if _, err := repo.Correct("入力テキスト"); !errors.Is(err, ErrOutputTruncated) {
+		t.Fatalf("Correct() error = %v, want ErrOutputTruncated", err)
+	}
+	var maxTokens []int
+	for _, request := range fake.requests {
+		maxTokens = append(maxTokens, request.GenerationConfig.MaxOutputTokens)
+	}
+	if !slices.Equal(maxTokens, []int{100, 200, 400}) {
+		t.Errorf("maxOutputTokens = %v, want [100 200 400]", maxTokens)
+	}
+}
+
+func TestGeminiRepository_BlockedPrompt(t *testing.T) {
+	fake := &fakeGeminiServer{respond: func(int) string {
+		return `{"promptFeedback": {"blockReason": "SAFETY"}, "usageMetadata": {"promptTokenCount": 10}}`
+	}}
+	repo := newTestGeminiRepository(t, fake, &config.GeminiConfig{APIKey: "test-key", Model: "gemini-2.5-flash", MaxTokens: 100})
+
+	if _, err := repo.CategorizeReceipt("店名: テストマート"); err == nil {
+		t.Fatal("Expected error for blocked prompt")
+	}
+}
+
+func TestGeminiRepository_PromptVersion(t *testing.T) {
+	repo := NewGeminiRepository(&config.GeminiConfig{Model: "gemini-2.5-flash"})
+	claude := NewClaudeRepository(&config.AnthropicConfig{Model: "claude-haiku-4-5-20251001"})
+
+	// モデルが異なればキャッシュキーも異なる
+	if repo.PromptVersion(operationRecognizeReceipt) == claude.PromptVersion(operationRecognizeReceipt) {
+		t.Error("Expected prompt version to differ between providers")
+	}
+	if repo.ProviderName() != "Google Gemini" {
+		t.Errorf("ProviderName() = %s", repo.ProviderName())
+	}
+}
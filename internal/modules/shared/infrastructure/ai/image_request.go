@@ -0,0 +1,118 @@
+//go:build !no_ai
+
+package ai
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// imageChunkSize base64エンコードして書き込む画像の単位（3の倍数にしてパディングを途中に入れない）
+const imageChunkSize = 48 * 1024
+
+// imageRequestBody 画像認識のリクエストボディ
+// 画像の前後のJSONのみを保持し、画像はReaderで読み出すたびにbase64エンコードして書き込む。
+// json.Marshalでリクエスト全体を作る場合と同じバイト列になる（フィールドはキー順）
+type imageRequestBody struct {
+	prefix    []byte // 画像のbase64文字列の前まで
+	imageData []byte
+	suffix    []byte // 画像のbase64文字列の後から
+
+	mu      sync.Mutex
+	readers []*io.PipeReader
+	writers sync.WaitGroup
+}
+
+// newImageRequestBody 画像認識のリクエストボディを作成
+func newImageRequestBody(model string, maxTokens int, systemPrompt, mediaType string, imageData []byte, userPrompt string) (*imageRequestBody, error) {
+	// 文字列はjson.Marshalと同じエスケープで埋め込む
+	var strs [4][]byte
+	for i, v := range []string{model, systemPrompt, mediaType, userPrompt} {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		strs[i] = b
+	}
+	modelJSON, systemJSON, mediaTypeJSON, userPromptJSON := strs[0], strs[1], strs[2], strs[3]
+
+	return &imageRequestBody{
+		prefix:    fmt.Appendf(nil, `{"max_tokens":%d,"messages":[{"content":[{"source":{"data":"`, maxTokens),
+		imageData: imageData,
+		suffix:    fmt.Appendf(nil, `","media_type":%s,"type":"base64"},"type":"image"},{"text":%s,"type":"text"}],"role":"user"}],"model":%s,"system":%s}`, mediaTypeJSON, userPromptJSON, modelJSON, systemJSON),
+	}, nil
+}
+
+// Len リクエストボディのバイト数
+func (b *imageRequestBody) Len() int64 {
+	return int64(len(b.prefix) + base64.StdEncoding.EncodedLen(len(b.imageData)) + len(b.suffix))
+}
+
+// Elided 画像のbase64文字列を省略したリクエストボディ（デバッグ用の記録に使う）
+func (b *imageRequestBody) Elided() []byte {
+	elided := make([]byte, 0, len(b.prefix)+len(b.suffix)+32)
+	elided = append(elided, b.prefix...)
+	elided = fmt.Appendf(elided, "<image elided: %d bytes>", len(b.imageData))
+	return append(elided, b.suffix...)
+}
+
+// Reader リクエストボディを読み出すReaderを返す（読み出し側が閉じると書き込みを中断する）
+func (b *imageRequestBody) Reader() io.ReadCloser {
+	pr, pw := io.Pipe()
+	b.mu.Lock()
+	b.readers = append(b.readers, pr)
+	b.mu.Unlock()
+
+	b.writers.Add(1)
+	go func() {
+		defer b.writers.Done()
+		_, err := b.WriteTo(pw)
+		_ = pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+// Close すべてのReaderを閉じ、書き込みが終わるまで待つ
+// 呼び出し元は戻った後に画像データ（プールのバッファなど）を再利用できる
+func (b *imageRequestBody) Close() error {
+	b.mu.Lock()
+	readers := b.readers
+	b.readers = nil
+	b.mu.Unlock()
+
+	for _, pr := range readers {
+		_ = pr.Close()
+	}
+	b.writers.Wait()
+	return nil
+}
+
+// WriteTo リクエストボディを書き込む（画像は一定の単位ごとにbase64エンコードする）
+func (b *imageRequestBody) WriteTo(w io.Writer) (int64, error) {
+	var written int64
+	n, err := w.Write(b.prefix)
+	written += int64(n)
+	if err != nil {
+		return written, err
+	}
+
+	chunk := make([]byte, base64.StdEncoding.EncodedLen(imageChunkSize))
+	for data := b.imageData; len(data) > 0; {
+		size := min(len(data), imageChunkSize)
+		encodedLen := base64.StdEncoding.EncodedLen(size)
+		base64.StdEncoding.Encode(chunk, data[:size])
+		n, err := w.Write(chunk[:encodedLen])
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+		data = data[size:]
+	}
+
+	n, err = w.Write(b.suffix)
+	written += int64(n)
+	return written, err
+}
@@ -0,0 +1,156 @@
+//go:build !no_ai
+
+package ai
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+)
+
+// marshalImageRequest リクエスト全体をjson.Marshalで作成する（逐次書き込み前の実装、比較用）
+func marshalImageRequest(model string, maxTokens int, systemPrompt, mediaType string, imageData []byte, userPrompt string) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"model":      model,
+		"max_tokens": maxTokens,
+		"system":     systemPrompt,
+		"messages": []map[string]interface{}{
+			{
+				"role": "user",
+				"content": []map[string]interface{}{
+					{
+						"type": "image",
+						"source": map[string]string{
+							"type":       "base64",
+							"media_type": mediaType,
+							"data":       base64.StdEncoding.EncodeToString(imageData),
+						},
+					},
+					{
+						"type": "text",
+						"text": userPrompt,
+					},
+				},
+			},
+		},
+	})
+}
+
+// testImage ベンチマーク・テスト用の画像データ（JPEGのヘッダー + 疑似乱数）
+func testImage(size int) []byte {
+	data := make([]byte, size)
+	copy(data, []byte{0xFF, 0xD8})
+	x := uint32(1)
+	for i := 2; i < size; i++ {
+		x ^= x << 13
+		x ^= x >> 17
+		x ^= x << 5
+		data[i] = byte(x)
+	}
+	return data
+}
+
+func TestImageRequestBody(t *testing.T) {
+	tests := []struct {
+		name      string
+		imageSize int
+		prompt    string
+	}{
+		{name: "正常系: 空の画像", imageSize: 0, prompt: "読み取ってください"},
+		{name: "正常系: 3の倍数でない長さ", imageSize: 10, prompt: "改行\nと\"引用符\"と<HTML>&"},
+		{name: "正常系: 書き込み単位をまたぐ画像", imageSize: imageChunkSize*2 + 1, prompt: "レシート"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			image := testImage(tt.imageSize)
+			want, err := marshalImageRequest("claude-haiku-4-5-20251001", 4096, systemPromptReceipt, "image/jpeg", image, tt.prompt)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+
+			body, err := newImageRequestBody("claude-haiku-4-5-20251001", 4096, systemPromptReceipt, "image/jpeg", image, tt.prompt)
+			if err != nil {
+				t.Fatalf("newImageRequestBody() error = %v", err)
+			}
+			reader := body.Reader()
+			got, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+			_ = reader.Close()
+
+			if !bytes.Equal(got, want) {
+				t.Errorf("body differs from json.Marshal:\n got  %.200s\n want %.200s", got, want)
+			}
+			if body.Len() != int64(len(want)) {
+				t.Errorf("Len() = %d, want %d", body.Len(), len(want))
+			}
+		})
+	}
+}
+
+func TestImageRequestBody_ReaderClosed(t *testing.T) {
+	body, err := newImageRequestBody("model", 1, "system", "image/png", testImage(imageChunkSize*4), "prompt")
+	if err != nil {
+		t.Fatalf("newImageRequestBody() error = %v", err)
+	}
+
+	// 読み出しを途中でやめても、Closeで書き込みのgoroutineの終了を待つ
+	reader := body.Reader()
+	if _, err := reader.Read(make([]byte, 16)); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		_ = body.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close() did not return")
+	}
+	if _, err := body.WriteTo(failingWriter{}); err == nil {
+		t.Error("WriteTo() error = nil, want write error")
+	}
+}
+
+// failingWriter 常に書き込みに失敗するWriter
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, io.ErrClosedPipe
+}
+
+// BenchmarkImageRequestBody 5MBの画像でリクエストボディを作成する（-benchmem で割り当てを比較）
+func BenchmarkImageRequestBody(b *testing.B) {
+	image := testImage(5 << 20)
+
+	b.Run("marshal", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			data, err := marshalImageRequest("model", 4096, systemPromptReceipt, "image/jpeg", image, "prompt")
+			if err != nil {
+				b.Fatal(err)
+			}
+			_, _ = io.Copy(io.Discard, bytes.NewReader(data))
+		}
+	})
+
+	b.Run("stream", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			body, err := newImageRequestBody("model", 4096, systemPromptReceipt, "image/jpeg", image, "prompt")
+			if err != nil {
+				b.Fatal(err)
+			}
+			reader := body.Reader()
+			_, _ = io.Copy(io.Discard, reader)
+			_ = reader.Close()
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+const (
+	// aiBudgetKeyPrefix AI APIの利用量のキーの接頭辞
+	aiBudgetKeyPrefix = "ai_budget:"
+	// aiBudgetRetention 月ごとの利用量を保持する期間（前月分を確認できるよう2か月余り）
+	aiBudgetRetention = 70 * 24 * time.Hour
+)
+
+// RedisAIBudgetLedger RedisのAI APIの利用量の記録先（すべてのインスタンスで1つの予算を共有する）
+// 月の利用料金の合計は ai_budget:<YYYY-MM>:cost、モデルごとの利用量は ai_budget:<YYYY-MM>:models のハッシュ（<モデル>:<項目>）、
+// 予算超過の通知済みの印は ai_budget:<YYYY-MM>:notified、縮退の手動切り替えは ai_budget:override に保存する
+type RedisAIBudgetLedger struct {
+	client *redis.Client
+}
+
+// AIBudgetLedger キャッシュと同じRedisに保存するAI APIの利用量の記録先を取得
+func (r *RedisRepository) AIBudgetLedger() *RedisAIBudgetLedger {
+	return &RedisAIBudgetLedger{client: r.client}
+}
+
+// Add 月のモデルの利用量を加算し、加算後の月の利用料金の合計を返す
+func (l *RedisAIBudgetLedger) Add(ctx context.Context, month, model string, usage sharedDomain.AIModelUsage) (float64, error) {
+	modelsKey := l.modelsKey(month)
+	costKey := l.costKey(month)
+
+	pipe := l.client.TxPipeline()
+	pipe.HIncrBy(ctx, modelsKey, model+":requests", int64(usage.Requests))
+	pipe.HIncrBy(ctx, modelsKey, model+":input_tokens", int64(usage.InputTokens))
+	pipe.HIncrBy(ctx, modelsKey, model+":output_tokens", int64(usage.OutputTokens))
+	pipe.HIncrByFloat(ctx, modelsKey, model+":cost_usd", usage.CostUSD)
+	total := pipe.IncrByFloat(ctx, costKey, usage.CostUSD)
+	pipe.Expire(ctx, modelsKey, aiBudgetRetention)
+	pipe.Expire(ctx, costKey, aiBudgetRetention)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to add AI usage: %w", err)
+	}
+	return total.Val(), nil
+}
+
+// Usage 月のモデルごとの利用量と利用料金の合計を返す
+func (l *RedisAIBudgetLedger) Usage(ctx context.Context, month string) (map[string]sharedDomain.AIModelUsage, float64, error) {
+	fields, err := l.client.HGetAll(ctx, l.modelsKey(month)).Result()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get AI usage: %w", err)
+	}
+	costUSD, err := l.client.Get(ctx, l.costKey(month)).Float64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, 0, fmt.Errorf("failed to get AI cost: %w", err)
+	}
+
+	models := make(map[string]sharedDomain.AIModelUsage)
+	for field, value := range fields {
+		i := strings.LastIndex(field, ":")
+		if i < 0 {
+			continue
+		}
+		model := field[:i]
+		usage := models[model]
+		switch field[i+1:] {
+		case "requests":
+			usage.Requests, _ = strconv.Atoi(value)
+		case "input_tokens":
+			usage.InputTokens, _ = strconv.Atoi(value)
+		case "output_tokens":
+			usage.OutputTokens, _ = strconv.Atoi(value)
+		case "cost_usd":
+			usage.CostUSD, _ = strconv.ParseFloat(value, 64)
+		}
+		models[model] = usage
+	}
+	return models, costUSD, nil
+}
+
+// MarkNotified 月の予算超過の通知を記録し、初めて記録した場合だけtrueを返す
+func (l *RedisAIBudgetLedger) MarkNotified(ctx context.Context, month string) (bool, error) {
+	ok, err := l.client.SetNX(ctx, aiBudgetKeyPrefix+month+":notified", "1", aiBudgetRetention).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to mark AI budget notified: %w", err)
+	}
+	return ok, nil
+}
+
+// Override 縮退の手動切り替えを返す（未設定の場合はauto）
+func (l *RedisAIBudgetLedger) Override(ctx context.Context) (string, error) {
+	override, err := l.client.Get(ctx, aiBudgetKeyPrefix+"override").Result()
+	if errors.Is(err, redis.Nil) {
+		return sharedDomain.AIBudgetOverrideAuto, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get AI budget override: %w", err)
+	}
+	return override, nil
+}
+
+// SetOverride 縮退の手動切り替えを保存する（月が変わっても維持する）
+func (l *RedisAIBudgetLedger) SetOverride(ctx context.Context, override string) error {
+	if err := l.client.Set(ctx, aiBudgetKeyPrefix+"override", override, 0).Err(); err != nil {
+		return fmt.Errorf("failed to set AI budget override: %w", err)
+	}
+	return nil
+}
+
+func (l *RedisAIBudgetLedger) modelsKey(month string) string {
+	return aiBudgetKeyPrefix + month + ":models"
+}
+
+func (l *RedisAIBudgetLedger) costKey(month string) string {
+	return aiBudgetKeyPrefix + month + ":cost"
+}
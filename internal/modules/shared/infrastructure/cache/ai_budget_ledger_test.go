@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+func TestRedisAIBudgetLedger(t *testing.T) {
+	repo, cleanup := setupRedisRepo(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// 同じRedisを使うインスタンス同士で利用量を共有する
+	ledgers := []*RedisAIBudgetLedger{repo.AIBudgetLedger(), repo.AIBudgetLedger()}
+	for _, ledger := range ledgers {
+		if _, err := ledger.Add(ctx, "2025-06", "claude:haiku", sharedDomain.AIModelUsage{Requests: 1, InputTokens: 1000, OutputTokens: 100, CostUSD: 0.75}); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	total, err := ledgers[0].Add(ctx, "2025-07", "claude:haiku", sharedDomain.AIModelUsage{Requests: 1, CostUSD: 0.25})
+	if err != nil || total != 0.25 {
+		t.Errorf("Add() = %v, %v, want 0.25", total, err)
+	}
+
+	models, costUSD, err := ledgers[1].Usage(ctx, "2025-06")
+	if err != nil {
+		t.Fatalf("Usage() error = %v", err)
+	}
+	usage := models["claude:haiku"]
+	if math.Abs(costUSD-1.5) > 1e-9 || len(models) != 1 || usage.Requests != 2 || usage.InputTokens != 2000 || usage.OutputTokens != 200 || math.Abs(usage.CostUSD-1.5) > 1e-9 {
+		t.Errorf("Usage() = %+v, %v", models, costUSD)
+	}
+
+	// 予算超過の通知は月に1回だけ
+	for i, want := range []bool{true, false} {
+		if first, err := ledgers[i].MarkNotified(ctx, "2025-06"); err != nil || first != want {
+			t.Errorf("MarkNotified() = %v, %v, want %v", first, err, want)
+		}
+	}
+
+	if override, err := ledgers[1].Override(ctx); err != nil || override != sharedDomain.AIBudgetOverrideAuto {
+		t.Errorf("Override() = %q, %v, want auto", override, err)
+	}
+	if err := ledgers[0].SetOverride(ctx, sharedDomain.AIBudgetOverrideDowngrade); err != nil {
+		t.Fatalf("SetOverride() error = %v", err)
+	}
+	if override, err := ledgers[1].Override(ctx); err != nil || override != sharedDomain.AIBudgetOverrideDowngrade {
+		t.Errorf("Override() = %q, %v, want downgrade", override, err)
+	}
+}
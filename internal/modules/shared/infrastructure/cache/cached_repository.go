@@ -0,0 +1,174 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+const (
+	// receiptCacheKeyPrefix IDで取得したレシートのキャッシュキーの接頭辞（エンティティの形が変わった場合は版を上げる）
+	receiptCacheKeyPrefix = "repository:receipt:v1:"
+	// receiptCacheGenerationKey レシートのキャッシュの世代（明細だけを更新した場合など、まとめて無効化するたびに変わる）
+	receiptCacheGenerationKey = "repository:receipt:generation"
+	// categoryCacheKey カテゴリ一覧のキャッシュキー
+	categoryCacheKey = "repository:category:v1:all"
+)
+
+// CachedReceiptRepository IDによるレシートの取得をキャッシュから返すリポジトリ
+// 詳細画面・カテゴリー判定などで同じレシートを何度も読み込むため、短い期間だけ明細とともにキャッシュする
+// 書き込みに成功したレシートのキャッシュは削除し、それ以外の検索はそのままデータベースから読み込む
+// ReceiptRepositoryに書き込みのメソッドを追加した場合は、ここでもキャッシュを無効化すること
+type CachedReceiptRepository struct {
+	repository.ReceiptRepository
+	cache repository.CacheRepository
+	ttl   time.Duration
+}
+
+// NewCachedReceiptRepository 新しいCachedReceiptRepositoryを作成
+func NewCachedReceiptRepository(repo repository.ReceiptRepository, cache repository.CacheRepository, ttl time.Duration) *CachedReceiptRepository {
+	return &CachedReceiptRepository{
+		ReceiptRepository: repo,
+		cache:             cache,
+		ttl:               ttl,
+	}
+}
+
+// FindByID IDでレシートを取得（キャッシュにない場合はデータベースから読み込んでキャッシュする）
+// キャッシュを使えない場合（障害など）はデータベースから読み込む
+func (r *CachedReceiptRepository) FindByID(ctx context.Context, id string) (*entity.Receipt, error) {
+	key := r.key(ctx, id)
+	if data, err := r.cache.Get(ctx, key); err == nil {
+		var receipt entity.Receipt
+		if err := json.Unmarshal(data, &receipt); err == nil {
+			return &receipt, nil
+		}
+	}
+
+	receipt, err := r.ReceiptRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(receipt); err == nil {
+		_ = r.cache.Set(ctx, key, data, r.ttl)
+	}
+	return receipt, nil
+}
+
+// Create レシートを保存し、同じIDのキャッシュを削除
+func (r *CachedReceiptRepository) Create(ctx context.Context, receipt *entity.Receipt) error {
+	defer r.invalidate(ctx, receipt.ID)
+	return r.ReceiptRepository.Create(ctx, receipt)
+}
+
+// Update レシートを更新し、キャッシュを削除
+func (r *CachedReceiptRepository) Update(ctx context.Context, receipt *entity.Receipt) error {
+	defer r.invalidate(ctx, receipt.ID)
+	return r.ReceiptRepository.Update(ctx, receipt)
+}
+
+// Delete レシートを削除し、キャッシュを削除
+func (r *CachedReceiptRepository) Delete(ctx context.Context, id string) error {
+	defer r.invalidate(ctx, id)
+	return r.ReceiptRepository.Delete(ctx, id)
+}
+
+// MarkItemNotified 明細を通知済みにし、世代を変えてすべてのレシートのキャッシュを使わないようにする
+// 明細のIDからレシートを特定できないため、まとめて無効化する（期限の通知は1日1回のため影響は小さい）
+func (r *CachedReceiptRepository) MarkItemNotified(ctx context.Context, itemID, kind string, notifiedAt time.Time) error {
+	defer r.bump(ctx)
+	return r.ReceiptRepository.MarkItemNotified(ctx, itemID, kind, notifiedAt)
+}
+
+// Ping データベースに接続できるか確認（接続を確認できないリポジトリの場合は常に成功）
+// 埋め込んだインターフェースでは隠れるため、保存エラーが障害によるものかの判定のために引き継ぐ
+func (r *CachedReceiptRepository) Ping(ctx context.Context) error {
+	if checker, ok := r.ReceiptRepository.(sharedDomain.HealthChecker); ok {
+		return checker.Ping(ctx)
+	}
+	return nil
+}
+
+// invalidate レシートのキャッシュを削除（書き込みに失敗した場合も、途中まで反映された可能性があるため削除する）
+func (r *CachedReceiptRepository) invalidate(ctx context.Context, id string) {
+	_ = r.cache.Delete(context.WithoutCancel(ctx), r.key(ctx, id))
+}
+
+// bump キャッシュの世代を変える（期限切れで以前の世代に戻らないよう期限なしで保存する）
+func (r *CachedReceiptRepository) bump(ctx context.Context) {
+	generation := []byte(time.Now().Format(time.RFC3339Nano))
+	_ = r.cache.Set(sharedDomain.WithoutCacheStats(context.WithoutCancel(ctx)), receiptCacheGenerationKey, generation, 0)
+}
+
+// key レシートのキャッシュキー（世代・ID）
+func (r *CachedReceiptRepository) key(ctx context.Context, id string) string {
+	generation := "0"
+	if data, err := r.cache.Get(sharedDomain.WithoutCacheStats(ctx), receiptCacheGenerationKey); err == nil {
+		generation = string(data)
+	}
+	return receiptCacheKeyPrefix + generation + ":" + id
+}
+
+// CachedCategoryRepository カテゴリ一覧の取得をキャッシュから返すリポジトリ
+// 明細・利用明細のカテゴリー判定（ReceiptUseCaseがAIに示すカテゴリーを判定ごとに読み込む）とカテゴリ一覧のAPIで
+// 同じ一覧を何度も読み込むため、短い期間だけキャッシュし、カテゴリを書き込んだ場合は削除する
+type CachedCategoryRepository struct {
+	repository.CategoryRepository
+	cache repository.CacheRepository
+	ttl   time.Duration
+}
+
+// NewCachedCategoryRepository 新しいCachedCategoryRepositoryを作成
+func NewCachedCategoryRepository(repo repository.CategoryRepository, cache repository.CacheRepository, ttl time.Duration) *CachedCategoryRepository {
+	return &CachedCategoryRepository{
+		CategoryRepository: repo,
+		cache:              cache,
+		ttl:                ttl,
+	}
+}
+
+// FindAll 全カテゴリを取得（キャッシュにない場合はデータベースから読み込んでキャッシュする）
+func (r *CachedCategoryRepository) FindAll(ctx context.Context) ([]*entity.Category, error) {
+	if data, err := r.cache.Get(ctx, categoryCacheKey); err == nil {
+		var categories []*entity.Category
+		if err := json.Unmarshal(data, &categories); err == nil {
+			return categories, nil
+		}
+	}
+
+	categories, err := r.CategoryRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(categories); err == nil {
+		_ = r.cache.Set(ctx, categoryCacheKey, data, r.ttl)
+	}
+	return categories, nil
+}
+
+// Create カテゴリを保存し、一覧のキャッシュを削除
+func (r *CachedCategoryRepository) Create(ctx context.Context, category *entity.Category) error {
+	defer r.invalidate(ctx)
+	return r.CategoryRepository.Create(ctx, category)
+}
+
+// Update カテゴリを更新し、一覧のキャッシュを削除
+func (r *CachedCategoryRepository) Update(ctx context.Context, category *entity.Category) error {
+	defer r.invalidate(ctx)
+	return r.CategoryRepository.Update(ctx, category)
+}
+
+// Delete カテゴリを削除し、一覧のキャッシュを削除
+func (r *CachedCategoryRepository) Delete(ctx context.Context, id string) error {
+	defer r.invalidate(ctx)
+	return r.CategoryRepository.Delete(ctx, id)
+}
+
+// invalidate カテゴリ一覧のキャッシュを削除
+func (r *CachedCategoryRepository) invalidate(ctx context.Context) {
+	_ = r.cache.Delete(context.WithoutCancel(ctx), categoryCacheKey)
+}
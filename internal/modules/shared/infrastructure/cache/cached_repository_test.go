@@ -0,0 +1,199 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+)
+
+// memoryCache テスト用のメモリのキャッシュ（有効期限は扱わない）
+type memoryCache struct {
+	mu      sync.Mutex
+	entries map[string][]byte
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{entries: make(map[string][]byte)}
+}
+
+func (c *memoryCache) Set(ctx context.Context, key string, value []byte, expiration time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = value
+	return nil
+}
+
+func (c *memoryCache) Get(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if value, ok := c.entries[key]; ok {
+		return value, nil
+	}
+	return nil, errors.New("cache not found")
+}
+
+func (c *memoryCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+	return nil
+}
+
+func (c *memoryCache) Exists(ctx context.Context, key string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.entries[key]
+	return ok, nil
+}
+
+// countingReceiptRepository FindByIDの呼び出し回数を数えるテスト用のレシートリポジトリ
+type countingReceiptRepository struct {
+	repository.ReceiptRepository
+	receipts map[string]*entity.Receipt
+	finds    int
+}
+
+func (r *countingReceiptRepository) FindByID(ctx context.Context, id string) (*entity.Receipt, error) {
+	r.finds++
+	receipt, ok := r.receipts[id]
+	if !ok {
+		return nil, errors.New("receipt not found")
+	}
+	copied := *receipt
+	copied.Items = append([]entity.ReceiptItem(nil), receipt.Items...)
+	return &copied, nil
+}
+
+func (r *countingReceiptRepository) Update(ctx context.Context, receipt *entity.Receipt) error {
+	copied := *receipt
+	r.receipts[receipt.ID] = &copied
+	return nil
+}
+
+func (r *countingReceiptRepository) Delete(ctx context.Context, id string) error {
+	delete(r.receipts, id)
+	return nil
+}
+
+func (r *countingReceiptRepository) MarkItemNotified(ctx context.Context, itemID, kind string, notifiedAt time.Time) error {
+	for _, receipt := range r.receipts {
+		for i := range receipt.Items {
+			if receipt.Items[i].ID == itemID {
+				receipt.Items[i].ReturnNotifiedAt = &notifiedAt
+			}
+		}
+	}
+	return nil
+}
+
+func TestCachedReceiptRepository(t *testing.T) {
+	ctx := context.Background()
+	purchased := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	inner := &countingReceiptRepository{receipts: map[string]*entity.Receipt{
+		"r1": {ID: "r1", StoreName: "Store", PurchaseDate: purchased, TotalAmount: 300, Tags: []string{}, Items: []entity.ReceiptItem{
+			{ID: "i1", ReceiptID: "r1", Name: "Milk", Quantity: 1, Price: 300},
+		}},
+	}}
+	repo := NewCachedReceiptRepository(inner, newMemoryCache(), time.Minute)
+
+	// 2回目以降はキャッシュから返す
+	for range 3 {
+		receipt, err := repo.FindByID(ctx, "r1")
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		if receipt.StoreName != "Store" || !receipt.PurchaseDate.Equal(purchased) || len(receipt.Items) != 1 || receipt.Items[0].Name != "Milk" {
+			t.Errorf("FindByID() = %+v", receipt)
+		}
+	}
+	if inner.finds != 1 {
+		t.Errorf("inner finds = %d, want 1", inner.finds)
+	}
+
+	// 更新したレシートはデータベースから読み込み直す
+	updated := *inner.receipts["r1"]
+	updated.StoreName = "Renamed"
+	if err := repo.Update(ctx, &updated); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	receipt, err := repo.FindByID(ctx, "r1")
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if receipt.StoreName != "Renamed" || inner.finds != 2 {
+		t.Errorf("StoreName = %q, finds = %d, want re-read after update", receipt.StoreName, inner.finds)
+	}
+
+	// 明細の通知済みはレシートを特定できないため、すべてのキャッシュを使わない
+	if err := repo.MarkItemNotified(ctx, "i1", entity.DeadlineReturn, purchased); err != nil {
+		t.Fatalf("MarkItemNotified() error = %v", err)
+	}
+	receipt, err = repo.FindByID(ctx, "r1")
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if receipt.Items[0].ReturnNotifiedAt == nil || inner.finds != 3 {
+		t.Errorf("ReturnNotifiedAt = %v, finds = %d, want re-read after notification", receipt.Items[0].ReturnNotifiedAt, inner.finds)
+	}
+
+	// 削除したレシートはキャッシュからも返さない
+	if err := repo.Delete(ctx, "r1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.FindByID(ctx, "r1"); err == nil {
+		t.Error("FindByID() after Delete() error = nil, want not found")
+	}
+}
+
+// countingCategoryRepository FindAllの呼び出し回数を数えるテスト用のカテゴリリポジトリ
+type countingCategoryRepository struct {
+	repository.CategoryRepository
+	categories []*entity.Category
+	finds      int
+}
+
+func (r *countingCategoryRepository) FindAll(ctx context.Context) ([]*entity.Category, error) {
+	r.finds++
+	return r.categories, nil
+}
+
+func (r *countingCategoryRepository) Create(ctx context.Context, category *entity.Category) error {
+	r.categories = append(r.categories, category)
+	return nil
+}
+
+func TestCachedCategoryRepository(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingCategoryRepository{categories: []*entity.Category{{ID: "c1", Name: "食費"}}}
+	repo := NewCachedCategoryRepository(inner, newMemoryCache(), time.Minute)
+
+	for range 3 {
+		categories, err := repo.FindAll(ctx)
+		if err != nil {
+			t.Fatalf("FindAll() error = %v", err)
+		}
+		if len(categories) != 1 || categories[0].Name != "食費" {
+			t.Errorf("FindAll() = %+v", categories)
+		}
+	}
+	if inner.finds != 1 {
+		t.Errorf("inner finds = %d, want 1", inner.finds)
+	}
+
+	// カテゴリを追加すると一覧を読み込み直す
+	if err := repo.Create(ctx, &entity.Category{ID: "c2", Name: "日用品"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	categories, err := repo.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("FindAll() error = %v", err)
+	}
+	if len(categories) != 2 || inner.finds != 2 {
+		t.Errorf("FindAll() = %d categories, finds = %d, want re-read after create", len(categories), inner.finds)
+	}
+}
@@ -0,0 +1,141 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrCacheUnavailable Redisの障害を検知しているため、接続せずにキャッシュの利用を見送った
+var ErrCacheUnavailable = errors.New("cache unavailable")
+
+const (
+	// DefaultFailureThreshold 利用を停止するまでの連続失敗回数のデフォルト値
+	DefaultFailureThreshold = 3
+	// DefaultProbeInterval 停止中に復旧を確認する間隔のデフォルト値
+	DefaultProbeInterval = 5 * time.Second
+
+	// probeTimeout 復旧確認1回あたりのタイムアウト
+	probeTimeout = 2 * time.Second
+)
+
+// healthTracker Redisへの接続状態を追跡する
+// 連続して失敗した場合は利用を停止し、以後の呼び出しはタイムアウトを待たずに失敗させる。
+// 停止中はバックグラウンドで一定間隔ごとに接続を確認し、応答が戻れば利用を再開する
+type healthTracker struct {
+	mu        sync.Mutex
+	threshold int
+	interval  time.Duration
+	probe     func(ctx context.Context) error
+	failures  int
+	down      bool
+	stop      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// newHealthTracker 新しいhealthTrackerを作成
+func newHealthTracker(threshold int, interval time.Duration, probe func(ctx context.Context) error) *healthTracker {
+	if threshold <= 0 {
+		threshold = DefaultFailureThreshold
+	}
+	if interval <= 0 {
+		interval = DefaultProbeInterval
+	}
+	return &healthTracker{
+		threshold: threshold,
+		interval:  interval,
+		probe:     probe,
+		stop:      make(chan struct{}),
+	}
+}
+
+// Available Redisを利用できる状態か（停止中はfalse）
+func (h *healthTracker) Available() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return !h.down
+}
+
+// Record 呼び出し結果を記録し、連続失敗回数が閾値に達したら利用を停止する
+// キーが存在しない・呼び出し元のキャンセルは接続の失敗として数えない
+func (h *healthTracker) Record(err error) {
+	if errors.Is(err, redis.Nil) || errors.Is(err, context.Canceled) {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.down {
+		return
+	}
+	if err == nil {
+		h.failures = 0
+		return
+	}
+
+	h.failures++
+	if h.failures < h.threshold {
+		return
+	}
+
+	select {
+	case <-h.stop:
+		// Close済みの場合は復旧確認を開始しない
+		return
+	default:
+	}
+	h.down = true
+	slog.Warn("Redis unavailable, bypassing cache until it recovers",
+		"failures", h.failures,
+		"error", err,
+	)
+	h.wg.Add(1)
+	go h.probeLoop()
+}
+
+// probeLoop 停止中に一定間隔で接続を確認し、応答が戻れば利用を再開する
+func (h *healthTracker) probeLoop() {
+	defer h.wg.Done()
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-h.stop:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+		err := h.probe(ctx)
+		cancel()
+		if err != nil {
+			slog.Debug("Redis still unavailable", "error", err)
+			continue
+		}
+
+		h.mu.Lock()
+		h.down = false
+		h.failures = 0
+		h.mu.Unlock()
+		slog.Info("Redis recovered, resuming cache usage")
+		return
+	}
+}
+
+// Close 復旧確認を停止
+func (h *healthTracker) Close() {
+	h.mu.Lock()
+	select {
+	case <-h.stop:
+	default:
+		close(h.stop)
+	}
+	h.mu.Unlock()
+	h.wg.Wait()
+}
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestHealthTracker_Record(t *testing.T) {
+	connErr := errors.New("dial tcp: connection refused")
+
+	tests := []struct {
+		name          string
+		results       []error
+		wantAvailable bool
+	}{
+		{name: "正常系: 成功のみ", results: []error{nil, nil, nil}, wantAvailable: true},
+		{name: "正常系: 閾値未満の失敗", results: []error{connErr, connErr}, wantAvailable: true},
+		{name: "正常系: 成功で失敗回数をリセット", results: []error{connErr, connErr, nil, connErr}, wantAvailable: true},
+		{name: "正常系: キーなし・キャンセルは失敗に数えない", results: []error{redis.Nil, context.Canceled, redis.Nil, context.Canceled}, wantAvailable: true},
+		{name: "異常系: 連続失敗で停止", results: []error{connErr, connErr, connErr}, wantAvailable: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHealthTracker(3, time.Hour, func(ctx context.Context) error { return connErr })
+			defer h.Close()
+
+			for _, err := range tt.results {
+				h.Record(err)
+			}
+			if got := h.Available(); got != tt.wantAvailable {
+				t.Errorf("Available() = %v, want %v", got, tt.wantAvailable)
+			}
+		})
+	}
+}
+
+func TestHealthTracker_ProbeRecovers(t *testing.T) {
+	var recovered atomic.Bool
+	var probes atomic.Int32
+	h := newHealthTracker(1, 5*time.Millisecond, func(ctx context.Context) error {
+		probes.Add(1)
+		if recovered.Load() {
+			return nil
+		}
+		return errors.New("connection refused")
+	})
+	defer h.Close()
+
+	h.Record(errors.New("i/o timeout"))
+	if h.Available() {
+		t.Fatal("Available() = true after failure, want false")
+	}
+
+	// 停止中は応答が戻るまで確認を続ける
+	time.Sleep(30 * time.Millisecond)
+	if h.Available() {
+		t.Fatal("Available() = true while probe fails, want false")
+	}
+	if probes.Load() < 2 {
+		t.Errorf("probes = %d, want at least 2", probes.Load())
+	}
+
+	recovered.Store(true)
+	deadline := time.Now().Add(time.Second)
+	for !h.Available() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !h.Available() {
+		t.Error("Available() = false after recovery, want true")
+	}
+}
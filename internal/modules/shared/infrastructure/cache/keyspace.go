@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// scanBatchSize SCANの1回で調べるキーの目安
+const scanBatchSize = 100
+
+// keyspacePrefix キーの集計に使う接頭辞
+// AIの解析結果のキャッシュ（vision:receipt:<版>:<hash> など）は処理の種類まで、それ以外は最初の区切りまでとする
+func keyspacePrefix(key string) string {
+	parts := strings.SplitN(key, ":", 3)
+	if len(parts) == 3 && parts[0] == "vision" {
+		return parts[0] + ":" + parts[1]
+	}
+	return parts[0]
+}
+
+// SampleKeyspace SCANで最大limit件のキーを調べ、接頭辞ごとのキーの件数・使用メモリ・残りの有効期限を返す
+// すべてのキーを調べられなかった場合は、DBSIZEに対する標本の割合から件数・使用メモリを推定する
+func (r *RedisRepository) SampleKeyspace(ctx context.Context, limit int) (*sharedDomain.CacheKeyspace, error) {
+	if !r.health.Available() {
+		return nil, ErrCacheUnavailable
+	}
+
+	var keys []string
+	var cursor uint64
+	for {
+		batch, next, err := r.client.Scan(ctx, cursor, "*", scanBatchSize).Result()
+		r.health.Record(err)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan cache keys: %w", err)
+		}
+		keys = append(keys, batch...)
+		cursor = next
+		if cursor == 0 || len(keys) >= limit {
+			break
+		}
+	}
+	exact := cursor == 0 && len(keys) <= limit
+	keys = keys[:min(len(keys), limit)]
+
+	total, err := r.client.DBSize(ctx).Result()
+	r.health.Record(err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count cache keys: %w", err)
+	}
+
+	pipe := r.client.Pipeline()
+	memory := make([]*redis.IntCmd, len(keys))
+	ttls := make([]*redis.DurationCmd, len(keys))
+	for i, key := range keys {
+		memory[i] = pipe.MemoryUsage(ctx, key)
+		ttls[i] = pipe.TTL(ctx, key)
+	}
+	// 調べている間に期限切れ・削除されたキーはredis.Nilになるため、個別に読み飛ばす
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		r.health.Record(err)
+		return nil, fmt.Errorf("failed to inspect cache keys: %w", err)
+	}
+
+	type prefixSample struct {
+		keys, bytes, noExpiry, withTTL int64
+		ttlSeconds                     float64
+	}
+	samples := make(map[string]*prefixSample)
+	sampled := 0
+	for i, key := range keys {
+		size, err := memory[i].Result()
+		if err != nil {
+			continue
+		}
+		sampled++
+		prefix := keyspacePrefix(key)
+		sample, ok := samples[prefix]
+		if !ok {
+			sample = &prefixSample{}
+			samples[prefix] = sample
+		}
+		sample.keys++
+		sample.bytes += size
+		// 有効期限がない場合は-1、キーがない場合は-2が返る
+		switch ttl := ttls[i].Val(); {
+		case ttl > 0:
+			sample.withTTL++
+			sample.ttlSeconds += ttl.Seconds()
+		case ttl == -1:
+			sample.noExpiry++
+		}
+	}
+
+	// 標本がすべてのキーでない場合の推定の倍率
+	scale := 1.0
+	if !exact && sampled > 0 {
+		scale = float64(total) / float64(sampled)
+	}
+	estimate := func(n int64) int64 {
+		return int64(math.Round(float64(n) * scale))
+	}
+
+	keyspace := &sharedDomain.CacheKeyspace{
+		TotalKeys:   total,
+		SampledKeys: sampled,
+		Exact:       exact,
+		Prefixes:    make([]sharedDomain.CacheKeyspacePrefix, 0, len(samples)),
+	}
+	for prefix, sample := range samples {
+		p := sharedDomain.CacheKeyspacePrefix{
+			Prefix:   prefix,
+			Keys:     estimate(sample.keys),
+			Bytes:    estimate(sample.bytes),
+			NoExpiry: estimate(sample.noExpiry),
+		}
+		if sample.withTTL > 0 {
+			p.AvgTTLSeconds = int64(math.Round(sample.ttlSeconds / float64(sample.withTTL)))
+		}
+		keyspace.Prefixes = append(keyspace.Prefixes, p)
+	}
+	// 使用メモリの大きい順に並べる
+	slices.SortFunc(keyspace.Prefixes, func(a, b sharedDomain.CacheKeyspacePrefix) int {
+		return cmp.Or(cmp.Compare(b.Bytes, a.Bytes), cmp.Compare(a.Prefix, b.Prefix))
+	})
+	return keyspace, nil
+}
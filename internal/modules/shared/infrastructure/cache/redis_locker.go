@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"vision-api-app/internal/config"
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+// lockKeyPrefix ロックのキー接頭辞
+const lockKeyPrefix = "lock:"
+
+// 自分が保持しているロックのみを操作するため、トークンを照合してから更新する
+var (
+	releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+	refreshScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+)
+
+// RedisLocker Redisによる分散ロック実装
+type RedisLocker struct {
+	client *redis.Client
+}
+
+// NewRedisLocker 新しいRedisLockerを作成
+func NewRedisLocker(cfg *config.RedisConfig) (*RedisLocker, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+
+	// 接続確認
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	return &RedisLocker{client: client}, nil
+}
+
+// Acquire ロックを取得（取得できない場合はErrLockNotAcquired）
+func (l *RedisLocker) Acquire(ctx context.Context, name string, ttl time.Duration) (domain.Lock, error) {
+	lock := &redisLock{
+		client: l.client,
+		key:    lockKeyPrefix + name,
+		token:  newLockToken(),
+	}
+
+	ok, err := l.client.SetNX(ctx, lock.key, lock.token, ttl).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	if !ok {
+		return nil, domain.ErrLockNotAcquired
+	}
+	return lock, nil
+}
+
+// Close Redis接続を閉じる
+func (l *RedisLocker) Close() error {
+	return l.client.Close()
+}
+
+// redisLock 取得済みのRedisロック
+type redisLock struct {
+	client *redis.Client
+	key    string
+	token  string
+}
+
+// Refresh ロックの有効期限を延長（既に失っている場合はErrLockNotAcquired）
+func (l *redisLock) Refresh(ctx context.Context, ttl time.Duration) error {
+	n, err := refreshScript.Run(ctx, l.client, []string{l.key}, l.token, ttl.Milliseconds()).Int()
+	if err != nil {
+		return fmt.Errorf("failed to refresh lock: %w", err)
+	}
+	if n == 0 {
+		return domain.ErrLockNotAcquired
+	}
+	return nil
+}
+
+// Release ロックを解放
+func (l *redisLock) Release(ctx context.Context) error {
+	if err := releaseScript.Run(ctx, l.client, []string{l.key}, l.token).Err(); err != nil {
+		return fmt.Errorf("failed to release lock: %w", err)
+	}
+	return nil
+}
+
+// newLockToken ロック所有者を識別するランダムなトークンを生成
+func newLockToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
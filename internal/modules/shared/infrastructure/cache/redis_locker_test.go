@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/config"
+	"vision-api-app/internal/modules/shared/domain"
+	"vision-api-app/internal/modules/shared/infrastructure/testcontainer"
+)
+
+func setupRedisLocker(t *testing.T) (*RedisLocker, func()) {
+	t.Helper()
+	ctx := context.Background()
+
+	redisContainer, err := testcontainer.StartRedis(ctx, t)
+	if err != nil {
+		t.Fatalf("Failed to start redis container: %v", err)
+	}
+
+	port := 6379
+	if _, err := fmt.Sscanf(redisContainer.Port, "%d", &port); err != nil {
+		_ = redisContainer.Close(ctx)
+		t.Fatalf("Failed to parse redis port: %v", err)
+	}
+	locker, err := NewRedisLocker(&config.RedisConfig{
+		Host: redisContainer.Host,
+		Port: port,
+	})
+	if err != nil {
+		_ = redisContainer.Close(ctx)
+		t.Fatalf("Failed to create redis locker: %v", err)
+	}
+
+	return locker, func() {
+		_ = locker.Close()
+		_ = redisContainer.Close(ctx)
+	}
+}
+
+func TestRedisLocker_Acquire(t *testing.T) {
+	locker, cleanup := setupRedisLocker(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	lock, err := locker.Acquire(ctx, "test", time.Minute)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	// 保持中は他のインスタンスが取得できない
+	if _, err := locker.Acquire(ctx, "test", time.Minute); !errors.Is(err, domain.ErrLockNotAcquired) {
+		t.Errorf("Acquire() error = %v, want ErrLockNotAcquired", err)
+	}
+
+	if err := lock.Refresh(ctx, time.Minute); err != nil {
+		t.Errorf("Refresh() error = %v", err)
+	}
+
+	if err := lock.Release(ctx); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+
+	// 解放後は取得できる
+	lock2, err := locker.Acquire(ctx, "test", time.Minute)
+	if err != nil {
+		t.Fatalf("Acquire() after Release error = %v", err)
+	}
+
+	// 解放済みのロックでは他の所有者のロックを操作できない
+	if err := lock.Release(ctx); err != nil {
+		t.Errorf("Release() error = %v", err)
+	}
+	if err := lock.Refresh(ctx, time.Minute); !errors.Is(err, domain.ErrLockNotAcquired) {
+		t.Errorf("Refresh() error = %v, want ErrLockNotAcquired", err)
+	}
+	if _, err := locker.Acquire(ctx, "test", time.Minute); !errors.Is(err, domain.ErrLockNotAcquired) {
+		t.Errorf("Acquire() error = %v, want ErrLockNotAcquired", err)
+	}
+
+	_ = lock2.Release(ctx)
+}
+
+func TestRedisLocker_Expiration(t *testing.T) {
+	locker, cleanup := setupRedisLocker(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if _, err := locker.Acquire(ctx, "expire", 100*time.Millisecond); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	// 有効期限が切れると取得できる
+	if _, err := locker.Acquire(ctx, "expire", time.Minute); err != nil {
+		t.Errorf("Acquire() after expiration error = %v", err)
+	}
+}
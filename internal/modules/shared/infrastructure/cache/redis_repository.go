@@ -8,11 +8,15 @@ import (
 	"github.com/redis/go-redis/v9"
 
 	"vision-api-app/internal/config"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
 )
 
 // RedisRepository Redis実装
+// 接続の失敗が続いた場合はRedisへのアクセスを止め、ErrCacheUnavailableを即座に返す
 type RedisRepository struct {
 	client *redis.Client
+	health *healthTracker
+	stats  *Stats
 }
 
 // NewRedisRepository 新しいRedisRepositoryを作成
@@ -31,32 +35,73 @@ func NewRedisRepository(cfg *config.RedisConfig) (*RedisRepository, error) {
 		return nil, fmt.Errorf("failed to connect to redis: %w", err)
 	}
 
-	return &RedisRepository{client: client}, nil
+	probe := func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
+	}
+	interval := time.Duration(cfg.ProbeIntervalSeconds) * time.Second
+	return &RedisRepository{
+		client: client,
+		health: newHealthTracker(cfg.FailureThreshold, interval, probe),
+	}, nil
+}
+
+// Available Redisを利用できる状態か（障害を検知して利用を停止している間はfalse）
+func (r *RedisRepository) Available() bool {
+	return r.health.Available()
+}
+
+// SetStats エンドポイント・テナントごとのキャッシュの利用状況の集計先を設定（未設定の場合は集計しない）
+func (r *RedisRepository) SetStats(stats *Stats) {
+	r.stats = stats
+}
+
+// CacheStats 起動してからのエンドポイント・テナントごとのキャッシュの利用状況を取得
+func (r *RedisRepository) CacheStats() sharedDomain.CacheStats {
+	return r.stats.Snapshot()
 }
 
 // Set キーと値を設定
 func (r *RedisRepository) Set(ctx context.Context, key string, value []byte, expiration time.Duration) error {
-	if err := r.client.Set(ctx, key, value, expiration).Err(); err != nil {
+	if !r.health.Available() {
+		return ErrCacheUnavailable
+	}
+	err := r.client.Set(ctx, key, value, expiration).Err()
+	r.health.Record(err)
+	if err != nil {
 		return fmt.Errorf("failed to set cache: %w", err)
 	}
+	r.stats.RecordSet(ctx, len(value))
 	return nil
 }
 
 // Get キーから値を取得
 func (r *RedisRepository) Get(ctx context.Context, key string) ([]byte, error) {
+	if !r.health.Available() {
+		r.stats.RecordError(ctx)
+		return nil, ErrCacheUnavailable
+	}
 	val, err := r.client.Get(ctx, key).Bytes()
+	r.health.Record(err)
 	if err == redis.Nil {
+		r.stats.RecordMiss(ctx)
 		return nil, fmt.Errorf("cache not found: %s", key)
 	}
 	if err != nil {
+		r.stats.RecordError(ctx)
 		return nil, fmt.Errorf("failed to get cache: %w", err)
 	}
+	r.stats.RecordHit(ctx)
 	return val, nil
 }
 
 // Delete キーを削除
 func (r *RedisRepository) Delete(ctx context.Context, key string) error {
-	if err := r.client.Del(ctx, key).Err(); err != nil {
+	if !r.health.Available() {
+		return ErrCacheUnavailable
+	}
+	err := r.client.Del(ctx, key).Err()
+	r.health.Record(err)
+	if err != nil {
 		return fmt.Errorf("failed to delete cache: %w", err)
 	}
 	return nil
@@ -64,7 +109,11 @@ func (r *RedisRepository) Delete(ctx context.Context, key string) error {
 
 // Exists キーが存在するか確認
 func (r *RedisRepository) Exists(ctx context.Context, key string) (bool, error) {
+	if !r.health.Available() {
+		return false, ErrCacheUnavailable
+	}
 	count, err := r.client.Exists(ctx, key).Result()
+	r.health.Record(err)
 	if err != nil {
 		return false, fmt.Errorf("failed to check cache existence: %w", err)
 	}
@@ -73,5 +122,6 @@ func (r *RedisRepository) Exists(ctx context.Context, key string) (bool, error)
 
 // Close Redis接続を閉じる
 func (r *RedisRepository) Close() error {
+	r.health.Close()
 	return r.client.Close()
 }
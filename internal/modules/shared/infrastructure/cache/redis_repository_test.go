@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"vision-api-app/internal/config"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
 	"vision-api-app/internal/modules/shared/infrastructure/testcontainer"
 )
 
@@ -341,3 +342,76 @@ func TestRedisRepository_ExistsError(t *testing.T) {
 		t.Error("Exists() should return false for non-existent key")
 	}
 }
+
+func TestRedisRepository_Stats(t *testing.T) {
+	repo, cleanup := setupRedisRepo(t)
+	defer cleanup()
+
+	repo.SetStats(NewStats(0))
+	ctx := sharedDomain.WithCacheScope(context.Background(), sharedDomain.CacheScope{Endpoint: "GET /api/v1/reports/monthly", Tenant: "a"})
+	if err := repo.Set(ctx, "vision:receipt:1", []byte("hello"), time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if _, err := repo.Get(ctx, "vision:receipt:1"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	_, _ = repo.Get(ctx, "vision:receipt:missing")
+
+	stats := repo.CacheStats()
+	if len(stats.Scopes) != 1 {
+		t.Fatalf("Scopes = %+v, want 1 scope", stats.Scopes)
+	}
+	if got := stats.Scopes[0]; got.Hits != 1 || got.Misses != 1 || got.Sets != 1 || got.BytesWritten != 5 || got.HitRate != 50 {
+		t.Errorf("Scopes[0] = %+v", got)
+	}
+}
+
+func TestRedisRepository_SampleKeyspace(t *testing.T) {
+	repo, cleanup := setupRedisRepo(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	for i := range 10 {
+		if err := repo.Set(ctx, fmt.Sprintf("vision:receipt:%d", i), []byte("receipt"), time.Hour); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+	if err := repo.Set(ctx, "response_cache:generation", []byte("1"), 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	// すべてのキーを調べた場合は推定しない
+	keyspace, err := repo.SampleKeyspace(ctx, 100)
+	if err != nil {
+		t.Fatalf("SampleKeyspace() error = %v", err)
+	}
+	if !keyspace.Exact || keyspace.TotalKeys != 11 || keyspace.SampledKeys != 11 {
+		t.Errorf("keyspace = %+v, want exact sample of 11 keys", keyspace)
+	}
+	prefixes := make(map[string]sharedDomain.CacheKeyspacePrefix)
+	for _, p := range keyspace.Prefixes {
+		prefixes[p.Prefix] = p
+	}
+	if p := prefixes["vision:receipt"]; p.Keys != 10 || p.Bytes <= 0 || p.NoExpiry != 0 || p.AvgTTLSeconds <= 0 || p.AvgTTLSeconds > 3600 {
+		t.Errorf("vision:receipt = %+v", p)
+	}
+	if p := prefixes["response_cache"]; p.Keys != 1 || p.NoExpiry != 1 || p.AvgTTLSeconds != 0 {
+		t.Errorf("response_cache = %+v", p)
+	}
+
+	// 標本が一部の場合は全体の件数に合わせて推定する
+	keyspace, err = repo.SampleKeyspace(ctx, 5)
+	if err != nil {
+		t.Fatalf("SampleKeyspace() error = %v", err)
+	}
+	if keyspace.Exact || keyspace.SampledKeys != 5 {
+		t.Errorf("keyspace = %+v, want partial sample of 5 keys", keyspace)
+	}
+	var keys int64
+	for _, p := range keyspace.Prefixes {
+		keys += p.Keys
+	}
+	if keys < 10 || keys > 12 {
+		t.Errorf("estimated keys = %d, want about 11", keys)
+	}
+}
@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// Stats エンドポイント・テナントごとのキャッシュのヒット・ミスと書き込みの集計
+// 集計はインスタンスごとのメモリに保持し、組み合わせが上限を超えた場合のテナントは (other) にまとめる
+type Stats struct {
+	mu        sync.Mutex
+	usage     map[sharedDomain.CacheScope]*sharedDomain.CacheUsage
+	maxSeries int
+	since     time.Time
+}
+
+// NewStats 新しいStatsを作成（maxSeriesが0以下の場合は組み合わせの数を制限しない）
+func NewStats(maxSeries int) *Stats {
+	return &Stats{
+		usage:     make(map[sharedDomain.CacheScope]*sharedDomain.CacheUsage),
+		maxSeries: maxSeries,
+		since:     time.Now(),
+	}
+}
+
+// RecordHit キャッシュから取得できた
+func (s *Stats) RecordHit(ctx context.Context) {
+	s.record(ctx, func(u *sharedDomain.CacheUsage) { u.Hits++ })
+}
+
+// RecordMiss キャッシュにキーがなかった
+func (s *Stats) RecordMiss(ctx context.Context) {
+	s.record(ctx, func(u *sharedDomain.CacheUsage) { u.Misses++ })
+}
+
+// RecordError キャッシュから取得できなかった（障害など）
+func (s *Stats) RecordError(ctx context.Context) {
+	s.record(ctx, func(u *sharedDomain.CacheUsage) { u.Errors++ })
+}
+
+// RecordSet キャッシュに書き込んだ
+func (s *Stats) RecordSet(ctx context.Context, size int) {
+	s.record(ctx, func(u *sharedDomain.CacheUsage) {
+		u.Sets++
+		u.BytesWritten += int64(size)
+	})
+}
+
+// record コンテキストの集計単位の利用状況を更新（nilの場合・集計しない読み書きは何もしない）
+func (s *Stats) record(ctx context.Context, update func(*sharedDomain.CacheUsage)) {
+	if s == nil || sharedDomain.CacheStatsDisabled(ctx) {
+		return
+	}
+	scope := sharedDomain.CacheScopeFromContext(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.usage[scope]
+	if !ok && s.maxSeries > 0 && len(s.usage) >= s.maxSeries {
+		// テナントはリクエストで指定されるため、上限を超えた分はエンドポイントごとにまとめる
+		scope.Tenant = sharedDomain.CacheTenantOther
+		u, ok = s.usage[scope]
+	}
+	if !ok {
+		u = &sharedDomain.CacheUsage{Endpoint: scope.Endpoint, Tenant: scope.Tenant}
+		s.usage[scope] = u
+	}
+	update(u)
+}
+
+// Snapshot 起動してからの利用状況を取得
+func (s *Stats) Snapshot() sharedDomain.CacheStats {
+	if s == nil {
+		return sharedDomain.CacheStats{Scopes: []sharedDomain.CacheUsage{}}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := sharedDomain.CacheStats{
+		Since:  s.since,
+		Scopes: make([]sharedDomain.CacheUsage, 0, len(s.usage)),
+	}
+	for _, u := range s.usage {
+		usage := *u
+		usage.HitRate = hitRate(usage.Hits, usage.Misses)
+		stats.Scopes = append(stats.Scopes, usage)
+
+		stats.Total.Hits += u.Hits
+		stats.Total.Misses += u.Misses
+		stats.Total.Errors += u.Errors
+		stats.Total.Sets += u.Sets
+		stats.Total.BytesWritten += u.BytesWritten
+	}
+	stats.Total.HitRate = hitRate(stats.Total.Hits, stats.Total.Misses)
+	slices.SortFunc(stats.Scopes, func(a, b sharedDomain.CacheUsage) int {
+		return cmp.Or(cmp.Compare(a.Endpoint, b.Endpoint), cmp.Compare(a.Tenant, b.Tenant))
+	})
+	return stats
+}
+
+// hitRate ヒット率（%、小数点以下2桁、取得がない場合は0）
+func hitRate(hits, misses int64) float64 {
+	if hits+misses == 0 {
+		return 0
+	}
+	return math.Round(float64(hits)/float64(hits+misses)*10000) / 100
+}
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+func TestStats(t *testing.T) {
+	stats := NewStats(3)
+	monthly := sharedDomain.WithCacheScope(context.Background(), sharedDomain.CacheScope{Endpoint: "GET /api/v1/reports/monthly", Tenant: "a"})
+	receipt := sharedDomain.WithCacheScope(context.Background(), sharedDomain.CacheScope{Endpoint: "/api/v1/vision/receipt", Tenant: "a"})
+
+	stats.RecordHit(monthly)
+	stats.RecordHit(monthly)
+	stats.RecordHit(monthly)
+	stats.RecordMiss(monthly)
+	stats.RecordSet(monthly, 100)
+	stats.RecordMiss(receipt)
+	stats.RecordError(receipt)
+	// 集計に含めない読み書き
+	stats.RecordHit(sharedDomain.WithoutCacheStats(monthly))
+	// リクエストによらない処理
+	stats.RecordSet(context.Background(), 50)
+
+	// 組み合わせの上限を超えたテナントはエンドポイントごとにまとめる
+	for _, tenant := range []string{"b", "c"} {
+		ctx := sharedDomain.WithCacheScope(context.Background(), sharedDomain.CacheScope{Endpoint: "GET /api/v1/reports/monthly", Tenant: tenant})
+		stats.RecordHit(ctx)
+	}
+
+	snapshot := stats.Snapshot()
+	want := []sharedDomain.CacheUsage{
+		{Endpoint: sharedDomain.CacheEndpointBackground, Tenant: sharedDomain.CacheTenantDefault, Sets: 1, BytesWritten: 50},
+		{Endpoint: "/api/v1/vision/receipt", Tenant: "a", Misses: 1, Errors: 1},
+		{Endpoint: "GET /api/v1/reports/monthly", Tenant: sharedDomain.CacheTenantOther, Hits: 2, HitRate: 100},
+		{Endpoint: "GET /api/v1/reports/monthly", Tenant: "a", Hits: 3, Misses: 1, HitRate: 75, Sets: 1, BytesWritten: 100},
+	}
+	if len(snapshot.Scopes) != len(want) {
+		t.Fatalf("Scopes = %+v, want %d scopes", snapshot.Scopes, len(want))
+	}
+	for i := range want {
+		if snapshot.Scopes[i] != want[i] {
+			t.Errorf("Scopes[%d] = %+v, want %+v", i, snapshot.Scopes[i], want[i])
+		}
+	}
+
+	total := snapshot.Total
+	if total.Hits != 5 || total.Misses != 2 || total.Errors != 1 || total.Sets != 2 || total.BytesWritten != 150 {
+		t.Errorf("Total = %+v", total)
+	}
+	if total.HitRate != 71.43 {
+		t.Errorf("Total.HitRate = %v, want 71.43", total.HitRate)
+	}
+}
+
+func TestStats_Nil(t *testing.T) {
+	var stats *Stats
+	stats.RecordHit(context.Background())
+	if snapshot := stats.Snapshot(); len(snapshot.Scopes) != 0 || snapshot.Total.Hits != 0 {
+		t.Errorf("Snapshot() = %+v, want empty", snapshot)
+	}
+}
+
+func TestKeyspacePrefix(t *testing.T) {
+	tests := map[string]string{
+		"vision:receipt:abc":        "vision:receipt",
+		"vision:analyze:abc":        "vision:analyze",
+		"vision:jobs":               "vision",
+		"response_cache:generation": "response_cache",
+		"response_cache:1abc:def":   "response_cache",
+		"plain":                     "plain",
+	}
+	for key, want := range tests {
+		if got := keyspacePrefix(key); got != want {
+			t.Errorf("keyspacePrefix(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/mysqldialect"
+
+	"vision-api-app/internal/config"
+)
+
+// mysqlErrDuplicateEntry 一意制約に違反した場合のMySQLのエラー番号
+const mysqlErrDuplicateEntry = 1062
+
+// isDuplicateEntry 一意制約に違反したエラーかチェック
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry
+}
+
+// openDB MySQLに接続し、接続を確認したbun.DBを作成
+func openDB(cfg *config.MySQLConfig) (*bun.DB, error) {
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=true&loc=Local",
+		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Database)
+
+	sqldb, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	db := bun.NewDB(sqldb, mysqldialect.New())
+
+	// 接続確認
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return db, nil
+}
+
+// modelMapper BUNモデルMとエンティティEの相互変換（リポジトリごとに実装する）
+type modelMapper[M, E any] struct {
+	toModel  func(*E) (*M, error)
+	toEntity func(*M) (*E, error)
+}
+
+// infallible エラーを返さない変換をmodelMapperの形にする
+func infallible[A, B any](convert func(*A) *B) func(*A) (*B, error) {
+	return func(a *A) (*B, error) {
+		return convert(a), nil
+	}
+}
+
+// baseRepository 主キー（id列）で読み書きするBUNリポジトリの共通部分
+// 各リポジトリはこれを埋め込み、Create・FindByID・Update・Delete・Ping・Closeをそのまま公開する
+// 明細の差し替えなど独自の処理が必要なメソッドは、埋め込んだ側で同名のメソッドを定義して上書きする
+type baseRepository[M, E any] struct {
+	db     *bun.DB
+	name   string // エラーメッセージに使う名前（"expense entry"など）
+	mapper modelMapper[M, E]
+	scope  func(*bun.SelectQuery) *bun.SelectQuery // 検索に共通の条件・関連の読み込み（nilの場合はなし）
+}
+
+// newBaseRepository 新しいbaseRepositoryを作成
+func newBaseRepository[M, E any](db *bun.DB, name string, mapper modelMapper[M, E]) baseRepository[M, E] {
+	return baseRepository[M, E]{db: db, name: name, mapper: mapper}
+}
+
+// withScope 検索に共通の条件・関連の読み込みを設定したbaseRepositoryを返す
+func (r baseRepository[M, E]) withScope(scope func(*bun.SelectQuery) *bun.SelectQuery) baseRepository[M, E] {
+	r.scope = scope
+	return r
+}
+
+// Create エンティティを作成
+func (r *baseRepository[M, E]) Create(ctx context.Context, e *E) error {
+	model, err := r.mapper.toModel(e)
+	if err != nil {
+		return fmt.Errorf("failed to convert to model: %w", err)
+	}
+
+	if _, err := r.db.NewInsert().Model(model).Exec(ctx); err != nil {
+		return fmt.Errorf("failed to create %s: %w", r.name, err)
+	}
+	return nil
+}
+
+// FindByID IDでエンティティを検索
+func (r *baseRepository[M, E]) FindByID(ctx context.Context, id string) (*E, error) {
+	return r.findOne(ctx, id, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("?TableAlias.id = ?", id)
+	})
+}
+
+// Update エンティティを主キーで更新
+func (r *baseRepository[M, E]) Update(ctx context.Context, e *E) error {
+	model, err := r.mapper.toModel(e)
+	if err != nil {
+		return fmt.Errorf("failed to convert to model: %w", err)
+	}
+
+	if _, err := r.db.NewUpdate().Model(model).WherePK().Exec(ctx); err != nil {
+		return fmt.Errorf("failed to update %s: %w", r.name, err)
+	}
+	return nil
+}
+
+// Delete IDでエンティティを削除
+func (r *baseRepository[M, E]) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.NewDelete().Model((*M)(nil)).Where("id = ?", id).Exec(ctx); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", r.name, err)
+	}
+	return nil
+}
+
+// Ping データベースへの接続を確認
+func (r *baseRepository[M, E]) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+// Close データベース接続を閉じる
+func (r *baseRepository[M, E]) Close() error {
+	return r.db.Close()
+}
+
+// findOne 条件に一致する1件を検索（keyは見つからない場合のエラーメッセージに使う）
+func (r *baseRepository[M, E]) findOne(ctx context.Context, key string, where func(*bun.SelectQuery) *bun.SelectQuery) (*E, error) {
+	model := new(M)
+	err := where(r.newSelect(model)).Limit(1).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%s not found: %s", r.name, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s: %w", r.name, err)
+	}
+	return r.mapper.toEntity(model)
+}
+
+// findMany 条件に一致するエンティティを検索（limit・offsetが0の場合は指定しない）
+func (r *baseRepository[M, E]) findMany(ctx context.Context, limit, offset int, where func(*bun.SelectQuery) *bun.SelectQuery) ([]*E, error) {
+	var models []M
+	query := where(r.newSelect(&models))
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+	if err := query.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("failed to find %s: %w", r.name, err)
+	}
+	return r.toEntities(models)
+}
+
+// toEntities モデルの一覧をエンティティに変換
+func (r *baseRepository[M, E]) toEntities(models []M) ([]*E, error) {
+	entities := make([]*E, len(models))
+	for i := range models {
+		e, err := r.mapper.toEntity(&models[i])
+		if err != nil {
+			return nil, err
+		}
+		entities[i] = e
+	}
+	return entities, nil
+}
+
+// newSelect 共通の条件・関連の読み込みを適用した検索クエリを作成
+func (r *baseRepository[M, E]) newSelect(model interface{}) *bun.SelectQuery {
+	query := r.db.NewSelect().Model(model)
+	if r.scope != nil {
+		query = r.scope(query)
+	}
+	return query
+}
@@ -1,34 +1,55 @@
 package database
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/uptrace/bun"
-	"github.com/uptrace/bun/dialect/mysqldialect"
-
-	_ "github.com/go-sql-driver/mysql"
 
 	"vision-api-app/internal/config"
+	analytics "vision-api-app/internal/modules/analytics/domain"
+	expensereport "vision-api-app/internal/modules/expensereport/domain"
 	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
 )
 
 // Receipt BUNモデル
 type Receipt struct {
 	bun.BaseModel `bun:"table:receipts"`
 
-	ID            string    `bun:"id,pk,type:varchar(36)"`
-	StoreName     string    `bun:"store_name,notnull"`
-	PurchaseDate  time.Time `bun:"purchase_date,notnull"`
-	TotalAmount   int       `bun:"total_amount,notnull"`
-	TaxAmount     int       `bun:"tax_amount,notnull,default:0"`
-	PaymentMethod string    `bun:"payment_method,type:varchar(50),default:''"`
-	ReceiptNumber string    `bun:"receipt_number,type:varchar(100),default:''"`
-	Category      *string   `bun:"category,type:varchar(50)"`
-	CreatedAt     time.Time `bun:"created_at,notnull,default:current_timestamp"`
-	UpdatedAt     time.Time `bun:"updated_at,notnull,default:current_timestamp"`
+	ID                string    `bun:"id,pk,type:varchar(36)"`
+	StoreName         string    `bun:"store_name,notnull"`
+	PurchaseDate      time.Time `bun:"purchase_date,notnull"`
+	TotalAmount       int64     `bun:"total_amount,notnull"`
+	TaxAmount         int64     `bun:"tax_amount,notnull,default:0"`
+	PaymentMethod     string    `bun:"payment_method,type:varchar(50),default:''"`
+	ReceiptNumber     string    `bun:"receipt_number,type:varchar(100),default:''"`
+	ReceiptType       string    `bun:"receipt_type,notnull,type:varchar(10),default:'purchase'"`
+	Locale            string    `bun:"locale,notnull,type:varchar(35),default:''"`
+	Currency          string    `bun:"currency,notnull,type:char(3),default:''"`
+	Category          *string   `bun:"category,type:varchar(50)"`
+	NeedsReview       bool      `bun:"needs_review,notnull,default:false"`
+	JSONRepaired      bool      `bun:"json_repaired,notnull,default:false"`
+	DateOutOfRange    bool      `bun:"date_out_of_range,notnull,default:false"`
+	AIModel           string    `bun:"ai_model,notnull,type:varchar(100),default:''"`
+	AIStopReason      string    `bun:"ai_stop_reason,notnull,type:varchar(30),default:''"`
+	AICacheHit        bool      `bun:"ai_cache_hit,notnull,default:false"`
+	AIDurationMs      int64     `bun:"ai_duration_ms,notnull,default:0"`
+	RawExtraction     *string   `bun:"raw_extraction,type:mediumtext"`
+	CategorizationRaw *string   `bun:"categorization_raw,type:text"`
+	Tags              []string  `bun:"tags,type:json"`
+	Memo              *string   `bun:"memo,type:text"`
+	ImageHash         *string   `bun:"image_hash,type:char(64)"`
+	CreatedAt         time.Time `bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt         time.Time `bun:"updated_at,notnull,default:current_timestamp"`
 
 	Items []ReceiptItem `bun:"rel:has-many,join:id=receipt_id"`
 }
@@ -37,15 +58,61 @@ type Receipt struct {
 type ReceiptItem struct {
 	bun.BaseModel `bun:"table:receipt_items"`
 
+	ID             string    `bun:"id,pk,type:varchar(36)"`
+	ReceiptID      string    `bun:"receipt_id,notnull,unique:uk_receipt_position"`
+	Position       int       `bun:"position,notnull,default:0,unique:uk_receipt_position"` // レシート内の順番（0始まり）
+	Name           string    `bun:"name,notnull"`
+	NormalizedName string    `bun:"normalized_name,type:varchar(255),notnull,default:''"`
+	Quantity       int       `bun:"quantity,notnull,default:1"`
+	Price          int64     `bun:"price,notnull"`
+	Unit           string    `bun:"unit,notnull,type:varchar(10),default:''"`
+	Measure        float64   `bun:"measure,notnull,type:decimal(10,3),default:0"`
+	UnitPrice      int64     `bun:"unit_price,notnull,default:0"`
+	Category       *string   `bun:"category,type:varchar(50)"`
+	CategoryStatus string    `bun:"category_status,type:varchar(20),default:''"`
+	WarrantyMonths *int      `bun:"warranty_months"`
+	CreatedAt      time.Time `bun:"created_at,notnull,default:current_timestamp"`
+
+	ReturnNotifiedAt   *time.Time `bun:"return_notified_at"`
+	WarrantyNotifiedAt *time.Time `bun:"warranty_notified_at"`
+}
+
+// ReceiptRevision BUNモデル
+type ReceiptRevision struct {
+	bun.BaseModel `bun:"table:receipt_revisions"`
+
 	ID        string    `bun:"id,pk,type:varchar(36)"`
-	ReceiptID string    `bun:"receipt_id,notnull"`
-	Name      string    `bun:"name,notnull"`
-	Quantity  int       `bun:"quantity,notnull,default:1"`
-	Price     int       `bun:"price,notnull"`
-	Category  *string   `bun:"category,type:varchar(50)"`
+	ReceiptID string    `bun:"receipt_id,notnull,type:varchar(36)"`
+	Revision  int       `bun:"revision,notnull"`
+	Source    string    `bun:"source,notnull,type:varchar(20)"`
+	Snapshot  string    `bun:"snapshot,notnull,type:json"`
 	CreatedAt time.Time `bun:"created_at,notnull,default:current_timestamp"`
 }
 
+// ReceiptMerge BUNモデル
+type ReceiptMerge struct {
+	bun.BaseModel `bun:"table:receipt_merges"`
+
+	ID              string    `bun:"id,pk,type:varchar(36)"`
+	ReceiptID       string    `bun:"receipt_id,notnull,type:varchar(36)"`
+	MergedReceiptID string    `bun:"merged_receipt_id,notnull,type:varchar(36)"`
+	Before          string    `bun:"before_snapshot,notnull,type:json"`
+	MergedReceipt   string    `bun:"merged_snapshot,notnull,type:json"`
+	AddedItemIDs    []string  `bun:"added_item_ids,notnull,type:json"`
+	CreatedAt       time.Time `bun:"created_at,notnull,default:current_timestamp"`
+}
+
+// TrashEntry BUNモデル
+type TrashEntry struct {
+	bun.BaseModel `bun:"table:trash_entries"`
+
+	Kind      string    `bun:"kind,pk,type:varchar(20)"`
+	ItemID    string    `bun:"item_id,pk,type:varchar(36)"`
+	Snapshot  string    `bun:"snapshot,notnull,type:json"`
+	DeletedAt time.Time `bun:"deleted_at,notnull"`
+	PurgeAt   time.Time `bun:"purge_at,notnull"`
+}
+
 // ExpenseEntry BUNモデル
 type ExpenseEntry struct {
 	bun.BaseModel `bun:"table:expense_entries"`
@@ -54,9 +121,10 @@ type ExpenseEntry struct {
 	ReceiptID   *string   `bun:"receipt_id,type:varchar(36)"`
 	Date        time.Time `bun:"date,notnull"`
 	Category    string    `bun:"category,notnull,type:varchar(50)"`
-	Amount      int       `bun:"amount,notnull"`
+	Amount      int64     `bun:"amount,notnull"`
 	Description *string   `bun:"description,type:text"`
 	Tags        []string  `bun:"tags,type:json"`
+	Memo        *string   `bun:"memo,type:text"`
 	CreatedAt   time.Time `bun:"created_at,notnull,default:current_timestamp"`
 	UpdatedAt   time.Time `bun:"updated_at,notnull,default:current_timestamp"`
 }
@@ -72,42 +140,150 @@ type Category struct {
 	CreatedAt   time.Time `bun:"created_at,notnull,default:current_timestamp"`
 }
 
+// ReceiptSplit BUNモデル
+type ReceiptSplit struct {
+	bun.BaseModel `bun:"table:receipt_splits"`
+
+	ReceiptID    string                    `bun:"receipt_id,pk,type:varchar(36)"`
+	Payer        string                    `bun:"payer,type:varchar(100),notnull,default:''"`
+	Participants []ReceiptSplitParticipant `bun:"participants,notnull,type:json"`
+	Settled      bool                      `bun:"settled,notnull,default:false"`
+	CreatedAt    time.Time                 `bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt    time.Time                 `bun:"updated_at,notnull,default:current_timestamp"`
+}
+
+// ReceiptSplitParticipant 割り勘の参加者（receipt_splits.participants のJSON要素）
+type ReceiptSplitParticipant struct {
+	Name      string     `json:"name"`
+	ItemIDs   []string   `json:"item_ids"`
+	Subtotal  int64      `json:"subtotal"`
+	Tax       int64      `json:"tax"`
+	Total     int64      `json:"total"`
+	Settled   bool       `json:"settled"`
+	SettledAt *time.Time `json:"settled_at,omitempty"`
+}
+
+// AccountingSync BUNモデル
+type AccountingSync struct {
+	bun.BaseModel `bun:"table:accounting_syncs"`
+
+	ReceiptID        string     `bun:"receipt_id,pk,type:varchar(36)"`
+	Provider         string     `bun:"provider,pk,type:varchar(20)"`
+	Status           string     `bun:"status,notnull,type:varchar(20)"`
+	ExternalID       string     `bun:"external_id,notnull,type:varchar(100),default:''"`
+	RemoteVersion    string     `bun:"remote_version,notnull,type:char(64),default:''"`
+	ReceiptUpdatedAt time.Time  `bun:"receipt_updated_at,notnull"`
+	Attempts         int        `bun:"attempts,notnull,default:0"`
+	LastError        string     `bun:"last_error,notnull,type:text"`
+	SyncedAt         *time.Time `bun:"synced_at"`
+	UpdatedAt        time.Time  `bun:"updated_at,notnull,default:current_timestamp"`
+}
+
+// ItemAlias BUNモデル
+type ItemAlias struct {
+	bun.BaseModel `bun:"table:item_aliases"`
+
+	AliasKey      string    `bun:"alias_key,pk,type:varchar(255)"`
+	Alias         string    `bun:"alias,notnull,type:varchar(255)"`
+	CanonicalName string    `bun:"canonical_name,notnull,type:varchar(255)"`
+	CreatedAt     time.Time `bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt     time.Time `bun:"updated_at,notnull,default:current_timestamp"`
+}
+
+// ExpenseReport BUNモデル
+type ExpenseReport struct {
+	bun.BaseModel `bun:"table:expense_reports,alias:expense_report"`
+
+	ID           string     `bun:"id,pk,type:varchar(36)"`
+	Title        string     `bun:"title,notnull,type:varchar(255)"`
+	Applicant    string     `bun:"applicant,notnull,type:varchar(100)"`
+	Status       string     `bun:"status,notnull,type:varchar(20)"`
+	TotalAmount  int64      `bun:"total_amount,notnull,default:0"`
+	Approver     string     `bun:"approver,notnull,type:varchar(100),default:''"`
+	ReimbursedBy string     `bun:"reimbursed_by,notnull,type:varchar(100),default:''"`
+	Comment      string     `bun:"comment,notnull,type:text"`
+	SubmittedAt  *time.Time `bun:"submitted_at"`
+	ApprovedAt   *time.Time `bun:"approved_at"`
+	ReimbursedAt *time.Time `bun:"reimbursed_at"`
+	CreatedAt    time.Time  `bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt    time.Time  `bun:"updated_at,notnull,default:current_timestamp"`
+
+	Receipts []ExpenseReportReceipt `bun:"rel:has-many,join:id=report_id"`
+}
+
+// ExpenseReportReceipt BUNモデル（経費報告書に含めるレシート）
+type ExpenseReportReceipt struct {
+	bun.BaseModel `bun:"table:expense_report_receipts"`
+
+	ReportID  string `bun:"report_id,pk,type:varchar(36)"`
+	ReceiptID string `bun:"receipt_id,pk,type:varchar(36),unique:uk_receipt"` // 1件のレシートは1つの報告書にのみ含められる
+	Position  int    `bun:"position,notnull,default:0"`                       // 報告書内の順番（0始まり）
+}
+
+// Collection BUNモデル
+type Collection struct {
+	bun.BaseModel `bun:"table:collections,alias:collection"`
+
+	ID          string    `bun:"id,pk,type:varchar(36)"`
+	Name        string    `bun:"name,notnull,type:varchar(100)"`
+	Description string    `bun:"description,notnull,type:text"`
+	CreatedAt   time.Time `bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt   time.Time `bun:"updated_at,notnull,default:current_timestamp"`
+
+	Receipts []CollectionReceipt `bun:"rel:has-many,join:id=collection_id"`
+}
+
+// CollectionReceipt BUNモデル（コレクションに含めるレシート）
+type CollectionReceipt struct {
+	bun.BaseModel `bun:"table:collection_receipts"`
+
+	CollectionID string `bun:"collection_id,pk,type:varchar(36)"`
+	ReceiptID    string `bun:"receipt_id,pk,type:varchar(36)"`
+	Position     int    `bun:"position,notnull,default:0"` // コレクション内の順番（0始まり）
+}
+
+// SpendContribution BUNモデル（公開統計に提供された世帯の月の食費の合計）
+type SpendContribution struct {
+	bun.BaseModel `bun:"table:spend_contributions"`
+
+	ContributorID string    `bun:"contributor_id,pk,type:varchar(64)"`
+	Month         string    `bun:"month,pk,type:char(7)"` // YYYY-MM
+	Region        string    `bun:"region,notnull,type:varchar(50)"`
+	Currency      string    `bun:"currency,notnull,type:char(3)"`
+	Amount        int64     `bun:"amount,notnull"`
+	UpdatedAt     time.Time `bun:"updated_at,notnull,default:current_timestamp"`
+}
+
 // BunReceiptRepository BUN実装
+// レシートは明細とともに読み書きするため、Create・Updateは明細の保存を含めて上書きする
 type BunReceiptRepository struct {
-	db *bun.DB
+	baseRepository[Receipt, entity.Receipt]
 }
 
 // NewBunReceiptRepository 新しいBunReceiptRepositoryを作成
 func NewBunReceiptRepository(cfg *config.MySQLConfig) (*BunReceiptRepository, error) {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=true&loc=Local",
-		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Database)
-
-	sqldb, err := sql.Open("mysql", dsn)
+	db, err := openDB(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open database: %w", err)
-	}
-
-	db := bun.NewDB(sqldb, mysqldialect.New())
-
-	// 接続確認
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	if err := db.PingContext(ctx); err != nil {
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+		return nil, err
 	}
-
-	return &BunReceiptRepository{db: db}, nil
+	return NewBunReceiptRepositoryWithDB(db), nil
 }
 
 // NewBunReceiptRepositoryWithDB DBインスタンスから作成（テスト用）
 func NewBunReceiptRepositoryWithDB(db *bun.DB) *BunReceiptRepository {
-	return &BunReceiptRepository{db: db}
+	return &BunReceiptRepository{
+		baseRepository: newBaseRepository(db, "receipt", modelMapper[Receipt, entity.Receipt]{
+			toModel:  infallible(toReceiptModel),
+			toEntity: infallible(toReceiptEntity),
+		}).withScope(func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Relation("Items", orderItemsByPosition)
+		}),
+	}
 }
 
 // Create レシートを作成
 func (r *BunReceiptRepository) Create(ctx context.Context, receipt *entity.Receipt) error {
-	model := r.toModel(receipt)
+	model := toReceiptModel(receipt)
 
 	// トランザクション内で実行
 	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
@@ -125,33 +301,125 @@ func (r *BunReceiptRepository) Create(ctx context.Context, receipt *entity.Recei
 	})
 }
 
-// FindByID IDでレシートを検索
-func (r *BunReceiptRepository) FindByID(ctx context.Context, id string) (*entity.Receipt, error) {
-	model := &Receipt{}
-	err := r.db.NewSelect().
-		Model(model).
-		Relation("Items").
-		Where("id = ?", id).
-		Scan(ctx)
-
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("receipt not found: %s", id)
+// FindByIDs 指定したIDのレシートを明細とともにまとめて取得（idsの順、存在しないIDは含めない）
+// maxBatchIDs件ごとにレシート・明細を1回ずつ取得する
+func (r *BunReceiptRepository) FindByIDs(ctx context.Context, ids []string) ([]*entity.Receipt, error) {
+	found := make(map[string]*entity.Receipt, len(ids))
+	for _, batch := range batchIDs(ids) {
+		var models []Receipt
+		err := r.db.NewSelect().
+			Model(&models).
+			Relation("Items", orderItemsByPosition).
+			Where("receipt.id IN (?)", bun.In(batch)).
+			Scan(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find receipts: %w", err)
+		}
+		for i := range models {
+			found[models[i].ID] = toReceiptEntity(&models[i])
+		}
 	}
-	if err != nil {
-		return nil, fmt.Errorf("failed to find receipt: %w", err)
+
+	receipts := make([]*entity.Receipt, 0, len(found))
+	for _, id := range ids {
+		if receipt, ok := found[id]; ok {
+			receipts = append(receipts, receipt)
+			delete(found, id)
+		}
 	}
+	return receipts, nil
+}
 
-	return r.toEntity(model), nil
+// FindByImageHash 元画像のハッシュでレシートを検索
+func (r *BunReceiptRepository) FindByImageHash(ctx context.Context, imageHash string) (*entity.Receipt, error) {
+	return r.findOne(ctx, "image hash "+imageHash, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("image_hash = ?", imageHash)
+	})
 }
 
 // FindAll 全レシートを取得
 func (r *BunReceiptRepository) FindAll(ctx context.Context, limit, offset int) ([]*entity.Receipt, error) {
+	return r.findMany(ctx, limit, offset, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Order("purchase_date DESC")
+	})
+}
+
+// FindByDateRange 日付範囲でレシートを検索
+func (r *BunReceiptRepository) FindByDateRange(ctx context.Context, start, end time.Time) ([]*entity.Receipt, error) {
+	return r.findMany(ctx, 0, 0, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("purchase_date BETWEEN ? AND ?", start, end).Order("purchase_date DESC")
+	})
+}
+
+// receiptPageSize ForEachByDateRangeで1回に読み込むレシートの件数
+const receiptPageSize = 500
+
+// ForEachByDateRange 購入日時がstartからendまでのレシートを購入日の古い順に1件ずつfnに渡す
+// 購入日時・IDをキーにreceiptPageSize件ずつ読み込む（OFFSETを使わないため、後半のページも読み飛ばしが発生しない）
+func (r *BunReceiptRepository) ForEachByDateRange(ctx context.Context, start, end time.Time, fn func(*entity.Receipt) error) error {
+	var last *Receipt
+	for {
+		var models []Receipt
+		query := r.db.NewSelect().
+			Model(&models).
+			Relation("Items", orderItemsByPosition).
+			Where("receipt.purchase_date BETWEEN ? AND ?", start, end).
+			OrderExpr("receipt.purchase_date ASC, receipt.id ASC").
+			Limit(receiptPageSize)
+		if last != nil {
+			query = query.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+				return q.Where("receipt.purchase_date > ?", last.PurchaseDate).
+					WhereOr("receipt.purchase_date = ? AND receipt.id > ?", last.PurchaseDate, last.ID)
+			})
+		}
+		if err := query.Scan(ctx); err != nil {
+			return fmt.Errorf("failed to find receipts by date range: %w", err)
+		}
+
+		for i := range models {
+			if err := fn(toReceiptEntity(&models[i])); err != nil {
+				return err
+			}
+		}
+		if len(models) < receiptPageSize {
+			return nil
+		}
+		last = &models[len(models)-1]
+	}
+}
+
+// FindNeedsReview 要確認のレシートを取得
+func (r *BunReceiptRepository) FindNeedsReview(ctx context.Context, limit, offset int) ([]*entity.Receipt, error) {
+	return r.findMany(ctx, limit, offset, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("needs_review = ?", true).Order("purchase_date DESC")
+	})
+}
+
+// FindByFilter 検索条件に一致するレシートを取得
+func (r *BunReceiptRepository) FindByFilter(ctx context.Context, filter repository.ReceiptFilter, limit, offset int) ([]*entity.Receipt, error) {
 	var models []Receipt
 	query := r.db.NewSelect().
 		Model(&models).
-		Relation("Items").
+		Relation("Items", orderItemsByPosition).
 		Order("purchase_date DESC")
 
+	if filter.Tag != "" {
+		query = query.Where("JSON_CONTAINS(receipt.tags, JSON_QUOTE(?))", filter.Tag)
+	}
+	if filter.Keyword != "" {
+		pattern := likePattern(filter.Keyword)
+		query = query.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("receipt.store_name LIKE ?", pattern).
+				WhereOr("receipt.memo LIKE ?", pattern).
+				WhereOr("EXISTS (SELECT 1 FROM receipt_items AS ri WHERE ri.receipt_id = receipt.id AND ri.name LIKE ?)", pattern)
+		})
+	}
+
+	if filter.Category != "" {
+		column, args := itemCategoryCondition("ri", filter.Category)
+		query = query.Where("EXISTS (SELECT 1 FROM receipt_items AS ri WHERE ri.receipt_id = receipt.id AND "+column+")", args...)
+	}
+
 	if limit > 0 {
 		query = query.Limit(limit)
 	}
@@ -160,95 +428,300 @@ func (r *BunReceiptRepository) FindAll(ctx context.Context, limit, offset int) (
 	}
 
 	if err := query.Scan(ctx); err != nil {
-		return nil, fmt.Errorf("failed to find receipts: %w", err)
+		return nil, fmt.Errorf("failed to search receipts: %w", err)
 	}
 
 	receipts := make([]*entity.Receipt, len(models))
 	for i, model := range models {
-		receipts[i] = r.toEntity(&model)
+		receipts[i] = toReceiptEntity(&model)
 	}
 	return receipts, nil
 }
 
-// FindByDateRange 日付範囲でレシートを検索
-func (r *BunReceiptRepository) FindByDateRange(ctx context.Context, start, end time.Time) ([]*entity.Receipt, error) {
-	var models []Receipt
-	err := r.db.NewSelect().
-		Model(&models).
-		Relation("Items").
-		Where("purchase_date BETWEEN ? AND ?", start, end).
-		Order("purchase_date DESC").
-		Scan(ctx)
+// purchasedItemRow 購入した明細の検索結果
+type purchasedItemRow struct {
+	ReceiptItem `bun:",extend"`
 
+	StoreName    string    `bun:"store_name"`
+	PurchaseDate time.Time `bun:"purchase_date"`
+}
+
+// FindItemsByCategory 指定したカテゴリーの明細をレシートをまたいで購入日の新しい順に取得
+func (r *BunReceiptRepository) FindItemsByCategory(ctx context.Context, category string, limit, offset int) ([]*entity.PurchasedItem, error) {
+	column, args := itemCategoryCondition("receipt_item", category)
+	query := r.newPurchasedItemQuery().
+		Where(column, args...).
+		OrderExpr("r.purchase_date DESC, receipt_item.id")
+
+	items, err := r.scanPurchasedItems(ctx, query, limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find receipts by date range: %w", err)
+		return nil, fmt.Errorf("failed to find items by category: %w", err)
 	}
+	return items, nil
+}
 
-	receipts := make([]*entity.Receipt, len(models))
-	for i, model := range models {
-		receipts[i] = r.toEntity(&model)
+// FindItemsByNormalizedName 正規化した商品名が一致する明細をレシートをまたいで購入日の古い順に取得
+func (r *BunReceiptRepository) FindItemsByNormalizedName(ctx context.Context, normalizedName string, limit, offset int) ([]*entity.PurchasedItem, error) {
+	query := r.newPurchasedItemQuery().
+		Where("receipt_item.normalized_name = ?", normalizedName).
+		OrderExpr("r.purchase_date ASC, receipt_item.id")
+
+	items, err := r.scanPurchasedItems(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find items by name: %w", err)
 	}
-	return receipts, nil
+	return items, nil
 }
 
-// Update レシートを更新
-func (r *BunReceiptRepository) Update(ctx context.Context, receipt *entity.Receipt) error {
-	model := r.toModel(receipt)
+// FindWarrantyItems 単価がminPrice以上、または保証期間を設定した明細を購入日の古い順に取得
+func (r *BunReceiptRepository) FindWarrantyItems(ctx context.Context, minPrice int64) ([]*entity.PurchasedItem, error) {
+	query := r.newPurchasedItemQuery().
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("receipt_item.price >= ?", minPrice).
+				WhereOr("receipt_item.warranty_months IS NOT NULL")
+		}).
+		OrderExpr("r.purchase_date ASC, receipt_item.id")
+
+	items, err := r.scanPurchasedItems(ctx, query, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find warranty items: %w", err)
+	}
+	return items, nil
+}
+
+// MarkItemNotified 明細の返品期限（entity.DeadlineReturn）または保証期限（entity.DeadlineWarranty）を通知済みにする
+func (r *BunReceiptRepository) MarkItemNotified(ctx context.Context, itemID, kind string, notifiedAt time.Time) error {
+	var column string
+	switch kind {
+	case entity.DeadlineReturn:
+		column = "return_notified_at"
+	case entity.DeadlineWarranty:
+		column = "warranty_notified_at"
+	default:
+		return fmt.Errorf("unknown deadline kind: %s", kind)
+	}
+
 	_, err := r.db.NewUpdate().
-		Model(model).
-		WherePK().
+		Model((*ReceiptItem)(nil)).
+		Set("? = ?", bun.Ident(column), notifiedAt).
+		Where("id = ?", itemID).
 		Exec(ctx)
-
 	if err != nil {
-		return fmt.Errorf("failed to update receipt: %w", err)
+		return fmt.Errorf("failed to mark item notified: %w", err)
 	}
 	return nil
 }
 
-// Delete レシートを削除
-func (r *BunReceiptRepository) Delete(ctx context.Context, id string) error {
-	_, err := r.db.NewDelete().
-		Model((*Receipt)(nil)).
-		Where("id = ?", id).
-		Exec(ctx)
+// newPurchasedItemQuery 明細と購入したレシートの情報を取得するクエリを作成
+func (r *BunReceiptRepository) newPurchasedItemQuery() *bun.SelectQuery {
+	return r.db.NewSelect().
+		Model((*purchasedItemRow)(nil)).
+		ColumnExpr("receipt_item.*").
+		ColumnExpr("r.store_name, r.purchase_date").
+		Join("JOIN receipts AS r ON r.id = receipt_item.receipt_id")
+}
+
+// scanPurchasedItems 明細と購入したレシートの情報を取得
+func (r *BunReceiptRepository) scanPurchasedItems(ctx context.Context, query *bun.SelectQuery, limit, offset int) ([]*entity.PurchasedItem, error) {
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	var rows []purchasedItemRow
+	if err := query.Scan(ctx, &rows); err != nil {
+		return nil, err
+	}
 
+	items := make([]*entity.PurchasedItem, len(rows))
+	for i, row := range rows {
+		items[i] = &entity.PurchasedItem{
+			Item:         toItemEntity(&row.ReceiptItem),
+			StoreName:    row.StoreName,
+			PurchaseDate: row.PurchaseDate,
+		}
+	}
+	return items, nil
+}
+
+// maxBatchIDs まとめて取得する場合にIN句に含めるIDの上限（プレースホルダー数・クエリ長を抑える）
+const maxBatchIDs = 500
+
+// batchIDs 重複と空文字を除いたIDをmaxBatchIDs件ごとに分割
+func batchIDs(ids []string) [][]string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return slices.Collect(slices.Chunk(unique, maxBatchIDs))
+}
+
+// itemCategoryCondition 明細のカテゴリーの検索条件を作成
+// 集計と同じく、カテゴリー未設定の明細はデフォルトカテゴリー（その他）として扱う
+func itemCategoryCondition(alias, category string) (string, []interface{}) {
+	if category == entity.DefaultCategory {
+		return fmt.Sprintf("(%[1]s.category = ? OR %[1]s.category IS NULL OR %[1]s.category = '')", alias), []interface{}{category}
+	}
+	return alias + ".category = ?", []interface{}{category}
+}
+
+// Update レシートと明細を更新
+func (r *BunReceiptRepository) Update(ctx context.Context, receipt *entity.Receipt) error {
+	model := toReceiptModel(receipt)
+
+	// 明細は(receipt_id, position)で上書きするためトランザクション内で実行
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewUpdate().Model(model).WherePK().Exec(ctx); err != nil {
+			return fmt.Errorf("failed to update receipt: %w", err)
+		}
+
+		return upsertItems(ctx, tx, model.ID, model.Items)
+	})
+}
+
+// upsertItems レシートの明細を(receipt_id, position)で上書き保存し、なくなった明細を削除する
+// 既存の行をロックしてから更新するため、同じレシートを同時に再処理しても明細が混ざらない
+// 同じ順番で同じIDの明細は作成日時と未指定の通知日時を引き継ぐ
+func upsertItems(ctx context.Context, tx bun.Tx, receiptID string, items []ReceiptItem) error {
+	var existing []ReceiptItem
+	if err := tx.NewSelect().
+		Model(&existing).
+		Column("id", "position").
+		Where("receipt_id = ?", receiptID).
+		For("UPDATE").
+		Scan(ctx); err != nil {
+		return fmt.Errorf("failed to lock receipt items: %w", err)
+	}
+
+	// 別の順番に移るIDの行は、主キーと(receipt_id, position)の両方で衝突しないよう先に削除する
+	positions := make(map[string]int, len(items))
+	for _, item := range items {
+		positions[item.ID] = item.Position
+	}
+	var stale []string
+	for _, row := range existing {
+		if position, found := positions[row.ID]; row.Position >= len(items) || (found && position != row.Position) {
+			stale = append(stale, row.ID)
+		}
+	}
+	if len(stale) > 0 {
+		if _, err := tx.NewDelete().
+			Model((*ReceiptItem)(nil)).
+			Where("id IN (?)", bun.In(stale)).
+			Exec(ctx); err != nil {
+			return fmt.Errorf("failed to delete receipt items: %w", err)
+		}
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+	// idは比較に使うため最後に更新する
+	_, err := tx.NewInsert().
+		Model(&items).
+		On("DUPLICATE KEY UPDATE").
+		Set("created_at = IF(id = VALUES(id), created_at, VALUES(created_at))").
+		Set("return_notified_at = IF(id = VALUES(id), COALESCE(VALUES(return_notified_at), return_notified_at), VALUES(return_notified_at))").
+		Set("warranty_notified_at = IF(id = VALUES(id), COALESCE(VALUES(warranty_notified_at), warranty_notified_at), VALUES(warranty_notified_at))").
+		Set("name = VALUES(name)").
+		Set("normalized_name = VALUES(normalized_name)").
+		Set("quantity = VALUES(quantity)").
+		Set("price = VALUES(price)").
+		Set("unit = VALUES(unit)").
+		Set("measure = VALUES(measure)").
+		Set("unit_price = VALUES(unit_price)").
+		Set("category = VALUES(category)").
+		Set("category_status = VALUES(category_status)").
+		Set("warranty_months = VALUES(warranty_months)").
+		Set("id = VALUES(id)").
+		Exec(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to delete receipt: %w", err)
+		return fmt.Errorf("failed to upsert receipt items: %w", err)
 	}
 	return nil
 }
 
-// Close データベース接続を閉じる
-func (r *BunReceiptRepository) Close() error {
-	return r.db.Close()
+// orderItemsByPosition レシートの明細をレシート内の順番で読み込む
+func orderItemsByPosition(q *bun.SelectQuery) *bun.SelectQuery {
+	return q.Order("position ASC")
 }
 
-// toModel エンティティをモデルに変換
-func (r *BunReceiptRepository) toModel(receipt *entity.Receipt) *Receipt {
+// toReceiptModel エンティティをモデルに変換
+func toReceiptModel(receipt *entity.Receipt) *Receipt {
 	model := &Receipt{
-		ID:            receipt.ID,
-		StoreName:     receipt.StoreName,
-		PurchaseDate:  receipt.PurchaseDate,
-		TotalAmount:   receipt.TotalAmount,
-		TaxAmount:     receipt.TaxAmount,
-		PaymentMethod: receipt.PaymentMethod,
-		ReceiptNumber: receipt.ReceiptNumber,
-		CreatedAt:     receipt.CreatedAt,
-		UpdatedAt:     receipt.UpdatedAt,
+		ID:             receipt.ID,
+		StoreName:      receipt.StoreName,
+		PurchaseDate:   receipt.PurchaseDate,
+		TotalAmount:    receipt.TotalAmount,
+		TaxAmount:      receipt.TaxAmount,
+		PaymentMethod:  receipt.PaymentMethod,
+		ReceiptNumber:  receipt.ReceiptNumber,
+		ReceiptType:    cmp.Or(receipt.Type, entity.ReceiptTypePurchase),
+		Locale:         receipt.Locale,
+		Currency:       receipt.Currency,
+		NeedsReview:    receipt.NeedsReview,
+		JSONRepaired:   receipt.JSONRepaired,
+		DateOutOfRange: receipt.DateOutOfRange,
+		AIModel:        receipt.Extraction.Model,
+		AIStopReason:   receipt.Extraction.StopReason,
+		AICacheHit:     receipt.Extraction.CacheHit,
+		AIDurationMs:   receipt.Extraction.DurationMs,
+		Tags:           receipt.Tags,
+		CreatedAt:      receipt.CreatedAt,
+		UpdatedAt:      receipt.UpdatedAt,
+	}
+
+	// Tagsが nil の場合は空配列に
+	if model.Tags == nil {
+		model.Tags = []string{}
 	}
 
 	if receipt.Category != "" {
 		model.Category = &receipt.Category
 	}
 
-	for _, item := range receipt.Items {
+	if receipt.Extraction.Raw != "" {
+		model.RawExtraction = &receipt.Extraction.Raw
+	}
+
+	if receipt.CategorizationRaw != "" {
+		model.CategorizationRaw = &receipt.CategorizationRaw
+	}
+
+	if receipt.Memo != "" {
+		model.Memo = &receipt.Memo
+	}
+
+	if receipt.ImageHash != "" {
+		model.ImageHash = &receipt.ImageHash
+	}
+
+	// 明細の順番はItemsの並び順
+	for i, item := range receipt.Items {
 		bunItem := ReceiptItem{
-			ID:        item.ID,
-			ReceiptID: item.ReceiptID,
-			Name:      item.Name,
-			Quantity:  item.Quantity,
-			Price:     item.Price,
-			CreatedAt: item.CreatedAt,
+			ID:             item.ID,
+			ReceiptID:      item.ReceiptID,
+			Position:       i,
+			Name:           item.Name,
+			NormalizedName: entity.NormalizeItemName(item.Name),
+			Quantity:       item.Quantity,
+			Price:          item.Price,
+			Unit:           item.Unit,
+			Measure:        item.Measure,
+			UnitPrice:      item.UnitPrice,
+			CategoryStatus: item.CategoryStatus,
+			WarrantyMonths: item.WarrantyMonths,
+			CreatedAt:      item.CreatedAt,
+
+			ReturnNotifiedAt:   item.ReturnNotifiedAt,
+			WarrantyNotifiedAt: item.WarrantyNotifiedAt,
 		}
 		if item.Category != "" {
 			bunItem.Category = &item.Category
@@ -259,222 +732,413 @@ func (r *BunReceiptRepository) toModel(receipt *entity.Receipt) *Receipt {
 	return model
 }
 
-// toEntity モデルをエンティティに変換
-func (r *BunReceiptRepository) toEntity(model *Receipt) *entity.Receipt {
+// toReceiptEntity モデルをエンティティに変換
+func toReceiptEntity(model *Receipt) *entity.Receipt {
 	receipt := &entity.Receipt{
-		ID:            model.ID,
-		StoreName:     model.StoreName,
-		PurchaseDate:  model.PurchaseDate,
-		TotalAmount:   model.TotalAmount,
-		TaxAmount:     model.TaxAmount,
-		PaymentMethod: model.PaymentMethod,
-		ReceiptNumber: model.ReceiptNumber,
-		CreatedAt:     model.CreatedAt,
-		UpdatedAt:     model.UpdatedAt,
-		Items:         []entity.ReceiptItem{},
+		ID:             model.ID,
+		StoreName:      model.StoreName,
+		PurchaseDate:   model.PurchaseDate,
+		TotalAmount:    model.TotalAmount,
+		TaxAmount:      model.TaxAmount,
+		PaymentMethod:  model.PaymentMethod,
+		ReceiptNumber:  model.ReceiptNumber,
+		Type:           cmp.Or(model.ReceiptType, entity.ReceiptTypePurchase),
+		Locale:         model.Locale,
+		Currency:       model.Currency,
+		NeedsReview:    model.NeedsReview,
+		JSONRepaired:   model.JSONRepaired,
+		DateOutOfRange: model.DateOutOfRange,
+		Extraction: entity.ReceiptExtraction{
+			Model:      model.AIModel,
+			StopReason: model.AIStopReason,
+			CacheHit:   model.AICacheHit,
+			DurationMs: model.AIDurationMs,
+		},
+		Tags:      model.Tags,
+		CreatedAt: model.CreatedAt,
+		UpdatedAt: model.UpdatedAt,
+		Items:     []entity.ReceiptItem{},
+	}
+
+	// Tagsが nil の場合は空配列に
+	if receipt.Tags == nil {
+		receipt.Tags = []string{}
 	}
 
 	if model.Category != nil {
 		receipt.Category = *model.Category
 	}
 
-	for _, itemModel := range model.Items {
-		item := entity.ReceiptItem{
-			ID:        itemModel.ID,
-			ReceiptID: itemModel.ReceiptID,
-			Name:      itemModel.Name,
-			Quantity:  itemModel.Quantity,
-			Price:     itemModel.Price,
-			CreatedAt: itemModel.CreatedAt,
-		}
-		if itemModel.Category != nil {
-			item.Category = *itemModel.Category
-		}
-		receipt.Items = append(receipt.Items, item)
+	if model.RawExtraction != nil {
+		receipt.Extraction.Raw = *model.RawExtraction
 	}
 
-	return receipt
-}
-
-// BunExpenseRepository BUN実装
-type BunExpenseRepository struct {
-	db *bun.DB
-}
-
-// NewBunExpenseRepository 新しいBunExpenseRepositoryを作成
-func NewBunExpenseRepository(cfg *config.MySQLConfig) (*BunExpenseRepository, error) {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=true&loc=Local",
-		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Database)
-
-	sqldb, err := sql.Open("mysql", dsn)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open database: %w", err)
+	if model.CategorizationRaw != nil {
+		receipt.CategorizationRaw = *model.CategorizationRaw
 	}
 
-	db := bun.NewDB(sqldb, mysqldialect.New())
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	if model.Memo != nil {
+		receipt.Memo = *model.Memo
+	}
 
-	if err := db.PingContext(ctx); err != nil {
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+	if model.ImageHash != nil {
+		receipt.ImageHash = *model.ImageHash
 	}
 
-	return &BunExpenseRepository{db: db}, nil
-}
+	for _, itemModel := range model.Items {
+		receipt.Items = append(receipt.Items, toItemEntity(&itemModel))
+	}
 
-// NewBunExpenseRepositoryWithDB DBインスタンスから作成（テスト用）
-func NewBunExpenseRepositoryWithDB(db *bun.DB) *BunExpenseRepository {
-	return &BunExpenseRepository{db: db}
+	return receipt
 }
 
-// Create 家計簿エントリを作成
-func (r *BunExpenseRepository) Create(ctx context.Context, entry *entity.ExpenseEntry) error {
-	model, err := r.toExpenseModel(entry)
-	if err != nil {
-		return fmt.Errorf("failed to convert to model: %w", err)
+// toItemEntity BUNモデルから明細エンティティに変換
+func toItemEntity(model *ReceiptItem) entity.ReceiptItem {
+	item := entity.ReceiptItem{
+		ID:             model.ID,
+		ReceiptID:      model.ReceiptID,
+		Name:           model.Name,
+		Quantity:       model.Quantity,
+		Price:          model.Price,
+		Unit:           model.Unit,
+		Measure:        model.Measure,
+		UnitPrice:      model.UnitPrice,
+		CategoryStatus: model.CategoryStatus,
+		WarrantyMonths: model.WarrantyMonths,
+		CreatedAt:      model.CreatedAt,
+
+		ReturnNotifiedAt:   model.ReturnNotifiedAt,
+		WarrantyNotifiedAt: model.WarrantyNotifiedAt,
 	}
-
-	_, err = r.db.NewInsert().Model(model).Exec(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to create expense entry: %w", err)
+	if model.Category != nil {
+		item.Category = *model.Category
 	}
-	return nil
+	return item
 }
 
-// FindByID IDで家計簿エントリを検索
-func (r *BunExpenseRepository) FindByID(ctx context.Context, id string) (*entity.ExpenseEntry, error) {
-	model := &ExpenseEntry{}
-	err := r.db.NewSelect().
-		Model(model).
-		Where("id = ?", id).
-		Scan(ctx)
+// BunReceiptRevisionRepository BUN実装
+type BunReceiptRevisionRepository struct {
+	db *bun.DB
+}
 
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("expense entry not found: %s", id)
-	}
+// NewBunReceiptRevisionRepository 新しいBunReceiptRevisionRepositoryを作成
+func NewBunReceiptRevisionRepository(cfg *config.MySQLConfig) (*BunReceiptRevisionRepository, error) {
+	db, err := openDB(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find expense entry: %w", err)
+		return nil, err
 	}
-
-	return r.toExpenseEntity(model)
+	return NewBunReceiptRevisionRepositoryWithDB(db), nil
 }
 
-// FindAll 全家計簿エントリを取得
-func (r *BunExpenseRepository) FindAll(ctx context.Context, limit, offset int) ([]*entity.ExpenseEntry, error) {
-	var models []ExpenseEntry
-	query := r.db.NewSelect().
-		Model(&models).
-		Order("date DESC")
-
-	if limit > 0 {
-		query = query.Limit(limit)
-	}
-	if offset > 0 {
-		query = query.Offset(offset)
-	}
+// NewBunReceiptRevisionRepositoryWithDB DBインスタンスから作成（テスト用）
+func NewBunReceiptRevisionRepositoryWithDB(db *bun.DB) *BunReceiptRevisionRepository {
+	return &BunReceiptRevisionRepository{db: db}
+}
 
-	if err := query.Scan(ctx); err != nil {
-		return nil, fmt.Errorf("failed to find expense entries: %w", err)
+// Create 変更履歴を作成
+func (r *BunReceiptRevisionRepository) Create(ctx context.Context, revision *entity.ReceiptRevision) error {
+	model, err := r.toRevisionModel(revision)
+	if err != nil {
+		return err
 	}
 
-	entries := make([]*entity.ExpenseEntry, len(models))
-	for i, model := range models {
-		entry, err := r.toExpenseEntity(&model)
-		if err != nil {
-			return nil, err
-		}
-		entries[i] = entry
+	if _, err := r.db.NewInsert().Model(model).Exec(ctx); err != nil {
+		return fmt.Errorf("failed to create receipt revision: %w", err)
 	}
-	return entries, nil
+	return nil
 }
 
-// FindByDateRange 日付範囲で家計簿エントリを検索
-func (r *BunExpenseRepository) FindByDateRange(ctx context.Context, start, end time.Time) ([]*entity.ExpenseEntry, error) {
-	var models []ExpenseEntry
+// FindByReceiptID レシートの変更履歴をリビジョン順に取得
+func (r *BunReceiptRevisionRepository) FindByReceiptID(ctx context.Context, receiptID string) ([]*entity.ReceiptRevision, error) {
+	var models []ReceiptRevision
 	err := r.db.NewSelect().
 		Model(&models).
-		Where("date BETWEEN ? AND ?", start, end).
-		Order("date DESC").
+		Where("receipt_id = ?", receiptID).
+		Order("revision ASC").
 		Scan(ctx)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to find expense entries by date range: %w", err)
+		return nil, fmt.Errorf("failed to find receipt revisions: %w", err)
 	}
 
-	entries := make([]*entity.ExpenseEntry, len(models))
+	revisions := make([]*entity.ReceiptRevision, len(models))
 	for i, model := range models {
-		entry, err := r.toExpenseEntity(&model)
+		revision, err := r.toRevisionEntity(&model)
 		if err != nil {
 			return nil, err
 		}
-		entries[i] = entry
+		revisions[i] = revision
 	}
-	return entries, nil
+	return revisions, nil
 }
 
-// FindByCategory カテゴリで家計簿エントリを検索
-func (r *BunExpenseRepository) FindByCategory(ctx context.Context, category string) ([]*entity.ExpenseEntry, error) {
-	var models []ExpenseEntry
+// FindByRevision リビジョン番号で変更履歴を検索
+func (r *BunReceiptRevisionRepository) FindByRevision(ctx context.Context, receiptID string, revision int) (*entity.ReceiptRevision, error) {
+	model := &ReceiptRevision{}
 	err := r.db.NewSelect().
-		Model(&models).
-		Where("category = ?", category).
-		Order("date DESC").
+		Model(model).
+		Where("receipt_id = ?", receiptID).
+		Where("revision = ?", revision).
 		Scan(ctx)
 
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("receipt revision not found: %s@%d", receiptID, revision)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to find expense entries by category: %w", err)
+		return nil, fmt.Errorf("failed to find receipt revision: %w", err)
 	}
 
-	entries := make([]*entity.ExpenseEntry, len(models))
-	for i, model := range models {
-		entry, err := r.toExpenseEntity(&model)
-		if err != nil {
-			return nil, err
-		}
-		entries[i] = entry
-	}
-	return entries, nil
+	return r.toRevisionEntity(model)
 }
 
-// Update 家計簿エントリを更新
-func (r *BunExpenseRepository) Update(ctx context.Context, entry *entity.ExpenseEntry) error {
-	model, err := r.toExpenseModel(entry)
-	if err != nil {
-		return fmt.Errorf("failed to convert to model: %w", err)
+// Close データベース接続を閉じる
+func (r *BunReceiptRevisionRepository) Close() error {
+	return r.db.Close()
+}
+
+// toRevisionModel エンティティをモデルに変換（スナップショットはレシートモデルのJSON）
+func (r *BunReceiptRevisionRepository) toRevisionModel(revision *entity.ReceiptRevision) (*ReceiptRevision, error) {
+	snapshot, err := json.Marshal(toReceiptModel(&revision.Snapshot))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal receipt snapshot: %w", err)
 	}
 
-	_, err = r.db.NewUpdate().
-		Model(model).
-		WherePK().
-		Exec(ctx)
+	return &ReceiptRevision{
+		ID:        revision.ID,
+		ReceiptID: revision.ReceiptID,
+		Revision:  revision.Revision,
+		Source:    revision.Source,
+		Snapshot:  string(snapshot),
+		CreatedAt: revision.CreatedAt,
+	}, nil
+}
+
+// toRevisionEntity モデルをエンティティに変換
+func (r *BunReceiptRevisionRepository) toRevisionEntity(model *ReceiptRevision) (*entity.ReceiptRevision, error) {
+	var snapshot Receipt
+	if err := json.Unmarshal([]byte(model.Snapshot), &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal receipt snapshot: %w", err)
+	}
+
+	return &entity.ReceiptRevision{
+		ID:        model.ID,
+		ReceiptID: model.ReceiptID,
+		Revision:  model.Revision,
+		Source:    model.Source,
+		Snapshot:  *toReceiptEntity(&snapshot),
+		CreatedAt: model.CreatedAt,
+	}, nil
+}
+
+// BunReceiptMergeRepository BUN実装
+type BunReceiptMergeRepository struct {
+	db *bun.DB
+}
+
+// NewBunReceiptMergeRepository 新しいBunReceiptMergeRepositoryを作成
+func NewBunReceiptMergeRepository(cfg *config.MySQLConfig) (*BunReceiptMergeRepository, error) {
+	db, err := openDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewBunReceiptMergeRepositoryWithDB(db), nil
+}
+
+// NewBunReceiptMergeRepositoryWithDB DBインスタンスから作成（テスト用）
+func NewBunReceiptMergeRepositoryWithDB(db *bun.DB) *BunReceiptMergeRepository {
+	return &BunReceiptMergeRepository{db: db}
+}
 
+// Create 統合の記録を作成
+func (r *BunReceiptMergeRepository) Create(ctx context.Context, merge *entity.ReceiptMerge) error {
+	model, err := r.toMergeModel(merge)
 	if err != nil {
-		return fmt.Errorf("failed to update expense entry: %w", err)
+		return err
+	}
+
+	if _, err := r.db.NewInsert().Model(model).Exec(ctx); err != nil {
+		return fmt.Errorf("failed to create receipt merge: %w", err)
 	}
 	return nil
 }
 
-// Delete 家計簿エントリを削除
-func (r *BunExpenseRepository) Delete(ctx context.Context, id string) error {
+// FindByReceiptID 残したレシートに統合したレシートの記録を統合した順に取得
+func (r *BunReceiptMergeRepository) FindByReceiptID(ctx context.Context, receiptID string) ([]*entity.ReceiptMerge, error) {
+	var models []ReceiptMerge
+	err := r.db.NewSelect().
+		Model(&models).
+		Where("receipt_id = ?", receiptID).
+		Order("created_at ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find receipt merges: %w", err)
+	}
+
+	merges := make([]*entity.ReceiptMerge, len(models))
+	for i := range models {
+		merge, err := r.toMergeEntity(&models[i])
+		if err != nil {
+			return nil, err
+		}
+		merges[i] = merge
+	}
+	return merges, nil
+}
+
+// FindByMergedReceiptID 統合して削除したレシートのIDで記録を取得
+func (r *BunReceiptMergeRepository) FindByMergedReceiptID(ctx context.Context, mergedReceiptID string) (*entity.ReceiptMerge, error) {
+	model := &ReceiptMerge{}
+	err := r.db.NewSelect().
+		Model(model).
+		Where("merged_receipt_id = ?", mergedReceiptID).
+		Scan(ctx)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("receipt merge not found: %s", mergedReceiptID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find receipt merge: %w", err)
+	}
+
+	return r.toMergeEntity(model)
+}
+
+// DeleteByMergedReceiptID 統合の記録を削除
+func (r *BunReceiptMergeRepository) DeleteByMergedReceiptID(ctx context.Context, mergedReceiptID string) error {
 	_, err := r.db.NewDelete().
-		Model((*ExpenseEntry)(nil)).
-		Where("id = ?", id).
+		Model((*ReceiptMerge)(nil)).
+		Where("merged_receipt_id = ?", mergedReceiptID).
 		Exec(ctx)
 
 	if err != nil {
-		return fmt.Errorf("failed to delete expense entry: %w", err)
+		return fmt.Errorf("failed to delete receipt merge: %w", err)
 	}
 	return nil
 }
 
 // Close データベース接続を閉じる
-func (r *BunExpenseRepository) Close() error {
+func (r *BunReceiptMergeRepository) Close() error {
 	return r.db.Close()
 }
 
+// toMergeModel エンティティをモデルに変換（スナップショットはレシートモデルのJSON）
+func (r *BunReceiptMergeRepository) toMergeModel(merge *entity.ReceiptMerge) (*ReceiptMerge, error) {
+	before, err := json.Marshal(toReceiptModel(&merge.Before))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal receipt snapshot: %w", err)
+	}
+	merged, err := json.Marshal(toReceiptModel(&merge.MergedReceipt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal merged receipt snapshot: %w", err)
+	}
+
+	addedItemIDs := merge.AddedItemIDs
+	if addedItemIDs == nil {
+		addedItemIDs = []string{}
+	}
+	return &ReceiptMerge{
+		ID:              merge.ID,
+		ReceiptID:       merge.ReceiptID,
+		MergedReceiptID: merge.MergedReceiptID,
+		Before:          string(before),
+		MergedReceipt:   string(merged),
+		AddedItemIDs:    addedItemIDs,
+		CreatedAt:       merge.CreatedAt,
+	}, nil
+}
+
+// toMergeEntity モデルをエンティティに変換
+func (r *BunReceiptMergeRepository) toMergeEntity(model *ReceiptMerge) (*entity.ReceiptMerge, error) {
+	var before, merged Receipt
+	if err := json.Unmarshal([]byte(model.Before), &before); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal receipt snapshot: %w", err)
+	}
+	if err := json.Unmarshal([]byte(model.MergedReceipt), &merged); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal merged receipt snapshot: %w", err)
+	}
+
+	return &entity.ReceiptMerge{
+		ID:              model.ID,
+		ReceiptID:       model.ReceiptID,
+		MergedReceiptID: model.MergedReceiptID,
+		Before:          *toReceiptEntity(&before),
+		MergedReceipt:   *toReceiptEntity(&merged),
+		AddedItemIDs:    model.AddedItemIDs,
+		CreatedAt:       model.CreatedAt,
+	}, nil
+}
+
+// BunExpenseRepository BUN実装
+type BunExpenseRepository struct {
+	baseRepository[ExpenseEntry, entity.ExpenseEntry]
+}
+
+// NewBunExpenseRepository 新しいBunExpenseRepositoryを作成
+func NewBunExpenseRepository(cfg *config.MySQLConfig) (*BunExpenseRepository, error) {
+	db, err := openDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewBunExpenseRepositoryWithDB(db), nil
+}
+
+// NewBunExpenseRepositoryWithDB DBインスタンスから作成（テスト用）
+func NewBunExpenseRepositoryWithDB(db *bun.DB) *BunExpenseRepository {
+	return &BunExpenseRepository{
+		baseRepository: newBaseRepository(db, "expense entry", modelMapper[ExpenseEntry, entity.ExpenseEntry]{
+			toModel:  toExpenseModel,
+			toEntity: toExpenseEntity,
+		}),
+	}
+}
+
+// FindAll 全家計簿エントリを取得
+func (r *BunExpenseRepository) FindAll(ctx context.Context, limit, offset int) ([]*entity.ExpenseEntry, error) {
+	return r.findMany(ctx, limit, offset, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Order("date DESC")
+	})
+}
+
+// FindByDateRange 日付範囲で家計簿エントリを検索
+func (r *BunExpenseRepository) FindByDateRange(ctx context.Context, start, end time.Time) ([]*entity.ExpenseEntry, error) {
+	return r.findMany(ctx, 0, 0, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("date BETWEEN ? AND ?", start, end).Order("date DESC")
+	})
+}
+
+// FindByCategory カテゴリで家計簿エントリを検索
+func (r *BunExpenseRepository) FindByCategory(ctx context.Context, category string) ([]*entity.ExpenseEntry, error) {
+	return r.findMany(ctx, 0, 0, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("category = ?", category).Order("date DESC")
+	})
+}
+
+// FindByFilter 検索条件に一致する家計簿エントリを検索
+func (r *BunExpenseRepository) FindByFilter(ctx context.Context, filter repository.ExpenseFilter, limit, offset int) ([]*entity.ExpenseEntry, error) {
+	return r.findMany(ctx, limit, offset, func(q *bun.SelectQuery) *bun.SelectQuery {
+		if filter.Category != "" {
+			q = q.Where("category = ?", filter.Category)
+		}
+		if !filter.Start.IsZero() {
+			q = q.Where("date >= ?", filter.Start)
+		}
+		if !filter.End.IsZero() {
+			q = q.Where("date < ?", filter.End)
+		}
+		return q.Order("date DESC", "id ASC")
+	})
+}
+
+// FindByReceiptID レシートに紐づく家計簿エントリを検索
+func (r *BunExpenseRepository) FindByReceiptID(ctx context.Context, receiptID string) ([]*entity.ExpenseEntry, error) {
+	return r.findMany(ctx, 0, 0, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("receipt_id = ?", receiptID).Order("date ASC", "id ASC")
+	})
+}
+
 // toExpenseModel エンティティをモデルに変換
-func (r *BunExpenseRepository) toExpenseModel(entry *entity.ExpenseEntry) (*ExpenseEntry, error) {
+func toExpenseModel(entry *entity.ExpenseEntry) (*ExpenseEntry, error) {
 	model := &ExpenseEntry{
 		ID:        entry.ID,
 		Date:      entry.Date,
@@ -493,6 +1157,10 @@ func (r *BunExpenseRepository) toExpenseModel(entry *entity.ExpenseEntry) (*Expe
 		model.Description = &entry.Description
 	}
 
+	if entry.Memo != "" {
+		model.Memo = &entry.Memo
+	}
+
 	// Tagsが nil の場合は空配列に
 	if model.Tags == nil {
 		model.Tags = []string{}
@@ -502,7 +1170,7 @@ func (r *BunExpenseRepository) toExpenseModel(entry *entity.ExpenseEntry) (*Expe
 }
 
 // toExpenseEntity モデルをエンティティに変換
-func (r *BunExpenseRepository) toExpenseEntity(model *ExpenseEntry) (*entity.ExpenseEntry, error) {
+func toExpenseEntity(model *ExpenseEntry) (*entity.ExpenseEntry, error) {
 	entry := &entity.ExpenseEntry{
 		ID:        model.ID,
 		Date:      model.Date,
@@ -521,6 +1189,10 @@ func (r *BunExpenseRepository) toExpenseEntity(model *ExpenseEntry) (*entity.Exp
 		entry.Description = *model.Description
 	}
 
+	if model.Memo != nil {
+		entry.Memo = *model.Memo
+	}
+
 	// Tagsが nil の場合は空配列に
 	if entry.Tags == nil {
 		entry.Tags = []string{}
@@ -531,135 +1203,94 @@ func (r *BunExpenseRepository) toExpenseEntity(model *ExpenseEntry) (*entity.Exp
 
 // BunCategoryRepository BUN実装
 type BunCategoryRepository struct {
-	db *bun.DB
+	baseRepository[Category, entity.Category]
 }
 
 // NewBunCategoryRepository 新しいBunCategoryRepositoryを作成
 func NewBunCategoryRepository(cfg *config.MySQLConfig) (*BunCategoryRepository, error) {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=true&loc=Local",
-		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Database)
-
-	sqldb, err := sql.Open("mysql", dsn)
+	db, err := openDB(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open database: %w", err)
-	}
-
-	db := bun.NewDB(sqldb, mysqldialect.New())
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	if err := db.PingContext(ctx); err != nil {
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+		return nil, err
 	}
-
-	return &BunCategoryRepository{db: db}, nil
+	return NewBunCategoryRepositoryWithDB(db), nil
 }
 
 // NewBunCategoryRepositoryWithDB DBインスタンスから作成（テスト用）
 func NewBunCategoryRepositoryWithDB(db *bun.DB) *BunCategoryRepository {
-	return &BunCategoryRepository{db: db}
-}
-
-// Create カテゴリを作成
-func (r *BunCategoryRepository) Create(ctx context.Context, category *entity.Category) error {
-	model := r.toCategoryModel(category)
-	_, err := r.db.NewInsert().Model(model).Exec(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to create category: %w", err)
-	}
-	return nil
-}
-
-// FindByID IDでカテゴリを検索
-func (r *BunCategoryRepository) FindByID(ctx context.Context, id string) (*entity.Category, error) {
-	model := &Category{}
-	err := r.db.NewSelect().
-		Model(model).
-		Where("id = ?", id).
-		Scan(ctx)
-
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("category not found: %s", id)
+	return &BunCategoryRepository{
+		baseRepository: newBaseRepository(db, "category", modelMapper[Category, entity.Category]{
+			toModel:  infallible(toCategoryModel),
+			toEntity: infallible(toCategoryEntity),
+		}),
 	}
-	if err != nil {
-		return nil, fmt.Errorf("failed to find category: %w", err)
-	}
-
-	return r.toCategoryEntity(model), nil
 }
 
 // FindAll 全カテゴリを取得
 func (r *BunCategoryRepository) FindAll(ctx context.Context) ([]*entity.Category, error) {
-	var models []Category
-	err := r.db.NewSelect().
-		Model(&models).
-		Order("name ASC").
-		Scan(ctx)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to find categories: %w", err)
-	}
-
-	categories := make([]*entity.Category, len(models))
-	for i, model := range models {
-		categories[i] = r.toCategoryEntity(&model)
-	}
-	return categories, nil
+	return r.findMany(ctx, 0, 0, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Order("name ASC")
+	})
 }
 
-// FindByName 名前でカテゴリを検索
-func (r *BunCategoryRepository) FindByName(ctx context.Context, name string) (*entity.Category, error) {
-	model := &Category{}
-	err := r.db.NewSelect().
-		Model(model).
-		Where("name = ?", name).
-		Scan(ctx)
-
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("category not found: %s", name)
-	}
-	if err != nil {
-		return nil, fmt.Errorf("failed to find category: %w", err)
+// Create カテゴリを保存（同じ名前のカテゴリが登録済みの場合はentity.ErrCategoryExists）
+func (r *BunCategoryRepository) Create(ctx context.Context, category *entity.Category) error {
+	if err := r.baseRepository.Create(ctx, category); err != nil {
+		if isDuplicateEntry(err) {
+			return fmt.Errorf("%w: %s", entity.ErrCategoryExists, category.Name)
+		}
+		return err
 	}
-
-	return r.toCategoryEntity(model), nil
+	return nil
 }
 
-// Update カテゴリを更新
+// Update カテゴリを更新（同じ名前のカテゴリが登録済みの場合はentity.ErrCategoryExists）
 func (r *BunCategoryRepository) Update(ctx context.Context, category *entity.Category) error {
-	model := r.toCategoryModel(category)
-	_, err := r.db.NewUpdate().
-		Model(model).
-		WherePK().
-		Exec(ctx)
-
-	if err != nil {
-		return fmt.Errorf("failed to update category: %w", err)
+	if err := r.baseRepository.Update(ctx, category); err != nil {
+		if isDuplicateEntry(err) {
+			return fmt.Errorf("%w: %s", entity.ErrCategoryExists, category.Name)
+		}
+		return err
 	}
 	return nil
 }
 
-// Delete カテゴリを削除
+// Delete カテゴリを削除（登録されていない場合はentity.ErrCategoryNotFound）
 func (r *BunCategoryRepository) Delete(ctx context.Context, id string) error {
-	_, err := r.db.NewDelete().
+	result, err := r.db.NewDelete().
 		Model((*Category)(nil)).
 		Where("id = ?", id).
 		Exec(ctx)
-
 	if err != nil {
 		return fmt.Errorf("failed to delete category: %w", err)
 	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%w: %s", entity.ErrCategoryNotFound, id)
+	}
 	return nil
 }
 
-// Close データベース接続を閉じる
-func (r *BunCategoryRepository) Close() error {
-	return r.db.Close()
+// FindByID IDでカテゴリを検索（登録されていない場合はentity.ErrCategoryNotFound）
+func (r *BunCategoryRepository) FindByID(ctx context.Context, id string) (*entity.Category, error) {
+	model := new(Category)
+	err := r.newSelect(model).Where("?TableAlias.id = ?", id).Limit(1).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", entity.ErrCategoryNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find category: %w", err)
+	}
+	return r.mapper.toEntity(model)
+}
+
+// FindByName 名前でカテゴリを検索
+func (r *BunCategoryRepository) FindByName(ctx context.Context, name string) (*entity.Category, error) {
+	return r.findOne(ctx, name, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("name = ?", name)
+	})
 }
 
 // toCategoryModel エンティティをモデルに変換
-func (r *BunCategoryRepository) toCategoryModel(category *entity.Category) *Category {
+func toCategoryModel(category *entity.Category) *Category {
 	model := &Category{
 		ID:        category.ID,
 		Name:      category.Name,
@@ -678,7 +1309,7 @@ func (r *BunCategoryRepository) toCategoryModel(category *entity.Category) *Cate
 }
 
 // toCategoryEntity モデルをエンティティに変換
-func (r *BunCategoryRepository) toCategoryEntity(model *Category) *entity.Category {
+func toCategoryEntity(model *Category) *entity.Category {
 	category := &entity.Category{
 		ID:        model.ID,
 		Name:      model.Name,
@@ -695,3 +1326,1376 @@ func (r *BunCategoryRepository) toCategoryEntity(model *Category) *entity.Catego
 
 	return category
 }
+
+// BunTrashRepository BUN実装
+type BunTrashRepository struct {
+	db *bun.DB
+}
+
+// NewBunTrashRepository 新しいBunTrashRepositoryを作成
+func NewBunTrashRepository(cfg *config.MySQLConfig) (*BunTrashRepository, error) {
+	db, err := openDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewBunTrashRepositoryWithDB(db), nil
+}
+
+// NewBunTrashRepositoryWithDB DBインスタンスから作成（テスト用）
+func NewBunTrashRepositoryWithDB(db *bun.DB) *BunTrashRepository {
+	return &BunTrashRepository{db: db}
+}
+
+// Create ゴミ箱に項目を追加（同じ項目を削除し直した場合は上書き）
+func (r *BunTrashRepository) Create(ctx context.Context, entry *entity.TrashEntry) error {
+	model, err := r.toTrashModel(entry)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.NewInsert().
+		Model(model).
+		On("DUPLICATE KEY UPDATE").
+		Set("snapshot = VALUES(snapshot)").
+		Set("deleted_at = VALUES(deleted_at)").
+		Set("purge_at = VALUES(purge_at)").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create trash entry: %w", err)
+	}
+	return nil
+}
+
+// FindAll ゴミ箱の項目を削除日時の新しい順に取得（kindが空の場合はすべての種類）
+func (r *BunTrashRepository) FindAll(ctx context.Context, kind string, limit, offset int) ([]*entity.TrashEntry, error) {
+	var models []TrashEntry
+	query := r.db.NewSelect().Model(&models)
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	err := query.
+		Order("deleted_at DESC", "item_id ASC").
+		Limit(limit).
+		Offset(offset).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find trash entries: %w", err)
+	}
+	return r.toTrashEntities(models)
+}
+
+// FindByID 種類とIDでゴミ箱の項目を取得
+func (r *BunTrashRepository) FindByID(ctx context.Context, kind, id string) (*entity.TrashEntry, error) {
+	model := &TrashEntry{}
+	err := r.db.NewSelect().
+		Model(model).
+		Where("kind = ?", kind).
+		Where("item_id = ?", id).
+		Scan(ctx)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("trash entry not found: %s/%s", kind, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find trash entry: %w", err)
+	}
+	return r.toTrashEntity(model)
+}
+
+// FindExpired 完全に削除する日時がbefore以前の項目を古い順に最大limit件取得
+func (r *BunTrashRepository) FindExpired(ctx context.Context, before time.Time, limit int) ([]*entity.TrashEntry, error) {
+	var models []TrashEntry
+	err := r.db.NewSelect().
+		Model(&models).
+		Where("purge_at <= ?", before).
+		Order("purge_at ASC").
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expired trash entries: %w", err)
+	}
+	return r.toTrashEntities(models)
+}
+
+// Delete ゴミ箱から項目を削除
+func (r *BunTrashRepository) Delete(ctx context.Context, kind, id string) error {
+	_, err := r.db.NewDelete().
+		Model((*TrashEntry)(nil)).
+		Where("kind = ?", kind).
+		Where("item_id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to delete trash entry: %w", err)
+	}
+	return nil
+}
+
+// Close データベース接続を閉じる
+func (r *BunTrashRepository) Close() error {
+	return r.db.Close()
+}
+
+// toTrashModel エンティティをモデルに変換（スナップショットはレシート・家計簿エントリのモデルのJSON）
+func (r *BunTrashRepository) toTrashModel(entry *entity.TrashEntry) (*TrashEntry, error) {
+	var snapshot any
+	switch {
+	case entry.Receipt != nil:
+		snapshot = toReceiptModel(entry.Receipt)
+	case entry.Expense != nil:
+		model, err := toExpenseModel(entry.Expense)
+		if err != nil {
+			return nil, err
+		}
+		snapshot = model
+	default:
+		return nil, fmt.Errorf("trash entry has no snapshot: %s/%s", entry.Kind, entry.ID)
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal trash snapshot: %w", err)
+	}
+	return &TrashEntry{
+		Kind:      entry.Kind,
+		ItemID:    entry.ID,
+		Snapshot:  string(data),
+		DeletedAt: entry.DeletedAt,
+		PurgeAt:   entry.PurgeAt,
+	}, nil
+}
+
+// toTrashEntity モデルをエンティティに変換
+func (r *BunTrashRepository) toTrashEntity(model *TrashEntry) (*entity.TrashEntry, error) {
+	entry := &entity.TrashEntry{
+		Kind:      model.Kind,
+		ID:        model.ItemID,
+		DeletedAt: model.DeletedAt,
+		PurgeAt:   model.PurgeAt,
+	}
+
+	switch model.Kind {
+	case entity.TrashKindReceipt:
+		var snapshot Receipt
+		if err := json.Unmarshal([]byte(model.Snapshot), &snapshot); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal trash snapshot: %w", err)
+		}
+		entry.Receipt = toReceiptEntity(&snapshot)
+	case entity.TrashKindExpense:
+		var snapshot ExpenseEntry
+		if err := json.Unmarshal([]byte(model.Snapshot), &snapshot); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal trash snapshot: %w", err)
+		}
+		expense, err := toExpenseEntity(&snapshot)
+		if err != nil {
+			return nil, err
+		}
+		entry.Expense = expense
+	default:
+		return nil, fmt.Errorf("unknown trash kind: %s", model.Kind)
+	}
+	return entry, nil
+}
+
+// toTrashEntities モデルの一覧をエンティティに変換
+func (r *BunTrashRepository) toTrashEntities(models []TrashEntry) ([]*entity.TrashEntry, error) {
+	entries := make([]*entity.TrashEntry, len(models))
+	for i := range models {
+		entry, err := r.toTrashEntity(&models[i])
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = entry
+	}
+	return entries, nil
+}
+
+// BunSplitRepository BUN実装
+type BunSplitRepository struct {
+	db *bun.DB
+}
+
+// NewBunSplitRepository 新しいBunSplitRepositoryを作成
+func NewBunSplitRepository(cfg *config.MySQLConfig) (*BunSplitRepository, error) {
+	db, err := openDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewBunSplitRepositoryWithDB(db), nil
+}
+
+// NewBunSplitRepositoryWithDB DBインスタンスから作成（テスト用）
+func NewBunSplitRepositoryWithDB(db *bun.DB) *BunSplitRepository {
+	return &BunSplitRepository{db: db}
+}
+
+// Save 割り勘を保存（同じレシートの割り勘は上書き）
+func (r *BunSplitRepository) Save(ctx context.Context, split *entity.Split) error {
+	model := r.toSplitModel(split)
+
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewDelete().
+			Model((*ReceiptSplit)(nil)).
+			Where("receipt_id = ?", model.ReceiptID).
+			Exec(ctx); err != nil {
+			return fmt.Errorf("failed to delete receipt split: %w", err)
+		}
+
+		if _, err := tx.NewInsert().Model(model).Exec(ctx); err != nil {
+			return fmt.Errorf("failed to save receipt split: %w", err)
+		}
+		return nil
+	})
+}
+
+// FindByReceiptID レシートの割り勘を検索
+func (r *BunSplitRepository) FindByReceiptID(ctx context.Context, receiptID string) (*entity.Split, error) {
+	model := &ReceiptSplit{}
+	err := r.db.NewSelect().
+		Model(model).
+		Where("receipt_id = ?", receiptID).
+		Scan(ctx)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("receipt split not found: %s", receiptID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find receipt split: %w", err)
+	}
+
+	return r.toSplitEntity(model), nil
+}
+
+// FindUnsettled 未精算の参加者が残る割り勘を新しい順に取得
+func (r *BunSplitRepository) FindUnsettled(ctx context.Context, limit, offset int) ([]*entity.Split, error) {
+	var models []ReceiptSplit
+	err := r.db.NewSelect().
+		Model(&models).
+		Where("settled = ?", false).
+		Order("created_at DESC", "receipt_id ASC").
+		Limit(limit).
+		Offset(offset).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find unsettled receipt splits: %w", err)
+	}
+
+	splits := make([]*entity.Split, len(models))
+	for i := range models {
+		splits[i] = r.toSplitEntity(&models[i])
+	}
+	return splits, nil
+}
+
+// DeleteByReceiptID レシートの割り勘を削除
+func (r *BunSplitRepository) DeleteByReceiptID(ctx context.Context, receiptID string) error {
+	_, err := r.db.NewDelete().
+		Model((*ReceiptSplit)(nil)).
+		Where("receipt_id = ?", receiptID).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to delete receipt split: %w", err)
+	}
+	return nil
+}
+
+// Close データベース接続を閉じる
+func (r *BunSplitRepository) Close() error {
+	return r.db.Close()
+}
+
+// toSplitModel エンティティをモデルに変換（全員の精算が済んでいるかは検索用に列として持つ）
+func (r *BunSplitRepository) toSplitModel(split *entity.Split) *ReceiptSplit {
+	participants := make([]ReceiptSplitParticipant, len(split.Participants))
+	for i, p := range split.Participants {
+		participants[i] = ReceiptSplitParticipant{
+			Name:      p.Name,
+			ItemIDs:   p.ItemIDs,
+			Subtotal:  p.Subtotal,
+			Tax:       p.Tax,
+			Total:     p.Total,
+			Settled:   p.Settled,
+			SettledAt: p.SettledAt,
+		}
+	}
+
+	return &ReceiptSplit{
+		ReceiptID:    split.ReceiptID,
+		Payer:        split.Payer,
+		Participants: participants,
+		Settled:      split.IsSettled(),
+		CreatedAt:    split.CreatedAt,
+		UpdatedAt:    split.UpdatedAt,
+	}
+}
+
+// toSplitEntity モデルをエンティティに変換
+func (r *BunSplitRepository) toSplitEntity(model *ReceiptSplit) *entity.Split {
+	participants := make([]entity.SplitParticipant, len(model.Participants))
+	for i, p := range model.Participants {
+		itemIDs := p.ItemIDs
+		if itemIDs == nil {
+			itemIDs = []string{}
+		}
+		participants[i] = entity.SplitParticipant{
+			Name:      p.Name,
+			ItemIDs:   itemIDs,
+			Subtotal:  p.Subtotal,
+			Tax:       p.Tax,
+			Total:     p.Total,
+			Settled:   p.Settled,
+			SettledAt: p.SettledAt,
+		}
+	}
+
+	return &entity.Split{
+		ReceiptID:    model.ReceiptID,
+		Payer:        model.Payer,
+		Participants: participants,
+		CreatedAt:    model.CreatedAt,
+		UpdatedAt:    model.UpdatedAt,
+	}
+}
+
+// BunAccountingSyncRepository BUN実装
+type BunAccountingSyncRepository struct {
+	db *bun.DB
+}
+
+// NewBunAccountingSyncRepository 新しいBunAccountingSyncRepositoryを作成
+func NewBunAccountingSyncRepository(cfg *config.MySQLConfig) (*BunAccountingSyncRepository, error) {
+	db, err := openDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewBunAccountingSyncRepositoryWithDB(db), nil
+}
+
+// NewBunAccountingSyncRepositoryWithDB DBインスタンスから作成（テスト用）
+func NewBunAccountingSyncRepositoryWithDB(db *bun.DB) *BunAccountingSyncRepository {
+	return &BunAccountingSyncRepository{db: db}
+}
+
+// Save 同期状態を保存（同じレシート・会計サービスの同期状態は上書き）
+func (r *BunAccountingSyncRepository) Save(ctx context.Context, sync *entity.AccountingSync) error {
+	model := &AccountingSync{
+		ReceiptID:        sync.ReceiptID,
+		Provider:         sync.Provider,
+		Status:           sync.Status,
+		ExternalID:       sync.ExternalID,
+		RemoteVersion:    sync.RemoteVersion,
+		ReceiptUpdatedAt: sync.ReceiptUpdatedAt,
+		Attempts:         sync.Attempts,
+		LastError:        sync.LastError,
+		SyncedAt:         sync.SyncedAt,
+		UpdatedAt:        sync.UpdatedAt,
+	}
+
+	_, err := r.db.NewInsert().
+		Model(model).
+		On("DUPLICATE KEY UPDATE").
+		Set("status = VALUES(status)").
+		Set("external_id = VALUES(external_id)").
+		Set("remote_version = VALUES(remote_version)").
+		Set("receipt_updated_at = VALUES(receipt_updated_at)").
+		Set("attempts = VALUES(attempts)").
+		Set("last_error = VALUES(last_error)").
+		Set("synced_at = VALUES(synced_at)").
+		Set("updated_at = VALUES(updated_at)").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to save accounting sync: %w", err)
+	}
+	return nil
+}
+
+// FindByReceiptID レシートの会計サービスごとの同期状態を取得
+func (r *BunAccountingSyncRepository) FindByReceiptID(ctx context.Context, receiptID string) ([]*entity.AccountingSync, error) {
+	var models []AccountingSync
+	err := r.db.NewSelect().
+		Model(&models).
+		Where("receipt_id = ?", receiptID).
+		Order("provider ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find accounting syncs: %w", err)
+	}
+	return r.toSyncEntities(models), nil
+}
+
+// FindByReceiptIDs 複数のレシートの会計サービスごとの同期状態をまとめて取得
+func (r *BunAccountingSyncRepository) FindByReceiptIDs(ctx context.Context, receiptIDs []string) ([]*entity.AccountingSync, error) {
+	var syncs []*entity.AccountingSync
+	for _, batch := range batchIDs(receiptIDs) {
+		var models []AccountingSync
+		err := r.db.NewSelect().
+			Model(&models).
+			Where("receipt_id IN (?)", bun.In(batch)).
+			Order("receipt_id ASC", "provider ASC").
+			Scan(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find accounting syncs: %w", err)
+		}
+		syncs = append(syncs, r.toSyncEntities(models)...)
+	}
+	return syncs, nil
+}
+
+// FindByStatus 指定した状態の同期状態を更新日時の新しい順に取得
+func (r *BunAccountingSyncRepository) FindByStatus(ctx context.Context, provider, status string, limit, offset int) ([]*entity.AccountingSync, error) {
+	var models []AccountingSync
+	err := r.db.NewSelect().
+		Model(&models).
+		Where("provider = ?", provider).
+		Where("status = ?", status).
+		Order("updated_at DESC", "receipt_id ASC").
+		Limit(limit).
+		Offset(offset).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find accounting syncs: %w", err)
+	}
+	return r.toSyncEntities(models), nil
+}
+
+// FindPendingReceiptIDs 送信が必要なレシートのIDを更新日時の古い順に取得
+func (r *BunAccountingSyncRepository) FindPendingReceiptIDs(ctx context.Context, provider string, maxAttempts, limit int) ([]string, error) {
+	var ids []string
+	err := r.db.NewSelect().
+		TableExpr("receipts AS r").
+		Column("r.id").
+		Join("LEFT JOIN accounting_syncs AS s ON s.receipt_id = r.id AND s.provider = ?", provider).
+		Where("r.needs_review = ?", false).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.
+				Where("s.receipt_id IS NULL").
+				WhereOr("s.status = ? AND r.updated_at > s.receipt_updated_at", entity.SyncStatusSynced).
+				WhereOr("s.status = ? AND s.attempts < ?", entity.SyncStatusFailed, maxAttempts)
+		}).
+		Order("r.updated_at ASC").
+		Limit(limit).
+		Scan(ctx, &ids)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find receipts to sync: %w", err)
+	}
+	return ids, nil
+}
+
+// Close データベース接続を閉じる
+func (r *BunAccountingSyncRepository) Close() error {
+	return r.db.Close()
+}
+
+// toSyncEntities モデルをエンティティに変換
+func (r *BunAccountingSyncRepository) toSyncEntities(models []AccountingSync) []*entity.AccountingSync {
+	syncs := make([]*entity.AccountingSync, len(models))
+	for i, model := range models {
+		syncs[i] = &entity.AccountingSync{
+			ReceiptID:        model.ReceiptID,
+			Provider:         model.Provider,
+			Status:           model.Status,
+			ExternalID:       model.ExternalID,
+			RemoteVersion:    model.RemoteVersion,
+			ReceiptUpdatedAt: model.ReceiptUpdatedAt,
+			Attempts:         model.Attempts,
+			LastError:        model.LastError,
+			SyncedAt:         model.SyncedAt,
+			UpdatedAt:        model.UpdatedAt,
+		}
+	}
+	return syncs
+}
+
+// BunItemAliasRepository BUN実装
+type BunItemAliasRepository struct {
+	db *bun.DB
+}
+
+// NewBunItemAliasRepository 新しいBunItemAliasRepositoryを作成
+func NewBunItemAliasRepository(cfg *config.MySQLConfig) (*BunItemAliasRepository, error) {
+	db, err := openDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewBunItemAliasRepositoryWithDB(db), nil
+}
+
+// NewBunItemAliasRepositoryWithDB DBインスタンスから作成（テスト用）
+func NewBunItemAliasRepositoryWithDB(db *bun.DB) *BunItemAliasRepository {
+	return &BunItemAliasRepository{db: db}
+}
+
+// FindAll 別名を登録時の表記の順に取得
+func (r *BunItemAliasRepository) FindAll(ctx context.Context) ([]*entity.ItemAlias, error) {
+	var models []ItemAlias
+	if err := r.db.NewSelect().Model(&models).Order("alias ASC").Scan(ctx); err != nil {
+		return nil, fmt.Errorf("failed to find item aliases: %w", err)
+	}
+
+	aliases := make([]*entity.ItemAlias, len(models))
+	for i, model := range models {
+		aliases[i] = &entity.ItemAlias{
+			Alias:         model.Alias,
+			CanonicalName: model.CanonicalName,
+			CreatedAt:     model.CreatedAt,
+			UpdatedAt:     model.UpdatedAt,
+		}
+	}
+	return aliases, nil
+}
+
+// Save 別名を登録（同じキーの別名が登録済みの場合は表記と正式な商品名を上書き）
+func (r *BunItemAliasRepository) Save(ctx context.Context, alias *entity.ItemAlias) error {
+	model := &ItemAlias{
+		AliasKey:      alias.Key(),
+		Alias:         alias.Alias,
+		CanonicalName: alias.CanonicalName,
+		CreatedAt:     alias.CreatedAt,
+		UpdatedAt:     alias.UpdatedAt,
+	}
+
+	_, err := r.db.NewInsert().
+		Model(model).
+		On("DUPLICATE KEY UPDATE").
+		Set("alias = VALUES(alias)").
+		Set("canonical_name = VALUES(canonical_name)").
+		Set("updated_at = VALUES(updated_at)").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to save item alias: %w", err)
+	}
+	return nil
+}
+
+// Delete 別名を削除（登録されていない場合はentity.ErrItemAliasNotFound）
+func (r *BunItemAliasRepository) Delete(ctx context.Context, alias string) error {
+	result, err := r.db.NewDelete().
+		Model((*ItemAlias)(nil)).
+		Where("alias_key = ?", entity.ItemAliasKey(alias)).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete item alias: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%w: %s", entity.ErrItemAliasNotFound, alias)
+	}
+	return nil
+}
+
+// Close データベース接続を閉じる
+func (r *BunItemAliasRepository) Close() error {
+	return r.db.Close()
+}
+
+// BunExpenseReportRepository BUN実装
+// 経費報告書は含めるレシートとともに読み書きするため、Create・Updateはレシートの紐付けを含めて上書きする
+type BunExpenseReportRepository struct {
+	baseRepository[ExpenseReport, expensereport.ExpenseReport]
+}
+
+// NewBunExpenseReportRepository 新しいBunExpenseReportRepositoryを作成
+func NewBunExpenseReportRepository(cfg *config.MySQLConfig) (*BunExpenseReportRepository, error) {
+	db, err := openDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewBunExpenseReportRepositoryWithDB(db), nil
+}
+
+// NewBunExpenseReportRepositoryWithDB DBインスタンスから作成（テスト用）
+func NewBunExpenseReportRepositoryWithDB(db *bun.DB) *BunExpenseReportRepository {
+	return &BunExpenseReportRepository{
+		baseRepository: newBaseRepository(db, "expense report", modelMapper[ExpenseReport, expensereport.ExpenseReport]{
+			toModel:  infallible(toExpenseReportModel),
+			toEntity: infallible(toExpenseReportEntity),
+		}).withScope(func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Relation("Receipts", func(q *bun.SelectQuery) *bun.SelectQuery {
+				return q.Order("position ASC")
+			})
+		}),
+	}
+}
+
+// Create 経費報告書とレシートの紐付けを作成
+func (r *BunExpenseReportRepository) Create(ctx context.Context, report *expensereport.ExpenseReport) error {
+	model := toExpenseReportModel(report)
+
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(model).Exec(ctx); err != nil {
+			return fmt.Errorf("failed to create expense report: %w", err)
+		}
+		return insertExpenseReportReceipts(ctx, tx, model.Receipts)
+	})
+}
+
+// Update 経費報告書を更新し、レシートの紐付けを置き換える
+func (r *BunExpenseReportRepository) Update(ctx context.Context, report *expensereport.ExpenseReport) error {
+	model := toExpenseReportModel(report)
+
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewUpdate().Model(model).WherePK().Exec(ctx); err != nil {
+			return fmt.Errorf("failed to update expense report: %w", err)
+		}
+		if _, err := tx.NewDelete().
+			Model((*ExpenseReportReceipt)(nil)).
+			Where("report_id = ?", model.ID).
+			Exec(ctx); err != nil {
+			return fmt.Errorf("failed to delete expense report receipts: %w", err)
+		}
+		return insertExpenseReportReceipts(ctx, tx, model.Receipts)
+	})
+}
+
+// FindAll 経費報告書を更新日時の新しい順に取得（statusが空の場合はすべての状態）
+func (r *BunExpenseReportRepository) FindAll(ctx context.Context, status string, limit, offset int) ([]*expensereport.ExpenseReport, error) {
+	return r.findMany(ctx, limit, offset, func(q *bun.SelectQuery) *bun.SelectQuery {
+		if status != "" {
+			q = q.Where("?TableAlias.status = ?", status)
+		}
+		return q.Order("expense_report.updated_at DESC", "expense_report.id ASC")
+	})
+}
+
+// FindByReceiptIDs 指定したレシートのいずれかを含む経費報告書を取得
+func (r *BunExpenseReportRepository) FindByReceiptIDs(ctx context.Context, receiptIDs []string) ([]*expensereport.ExpenseReport, error) {
+	var reports []*expensereport.ExpenseReport
+	for _, batch := range batchIDs(receiptIDs) {
+		found, err := r.findMany(ctx, 0, 0, func(q *bun.SelectQuery) *bun.SelectQuery {
+			subq := r.db.NewSelect().
+				Model((*ExpenseReportReceipt)(nil)).
+				Column("report_id").
+				Where("receipt_id IN (?)", bun.In(batch))
+			return q.Where("?TableAlias.id IN (?)", subq).Order("expense_report.id ASC")
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, report := range found {
+			if !slices.ContainsFunc(reports, func(r *expensereport.ExpenseReport) bool { return r.ID == report.ID }) {
+				reports = append(reports, report)
+			}
+		}
+	}
+	return reports, nil
+}
+
+// insertExpenseReportReceipts 経費報告書に含めるレシートを紐付ける
+func insertExpenseReportReceipts(ctx context.Context, tx bun.Tx, receipts []ExpenseReportReceipt) error {
+	if len(receipts) == 0 {
+		return nil
+	}
+	if _, err := tx.NewInsert().Model(&receipts).Exec(ctx); err != nil {
+		return fmt.Errorf("failed to create expense report receipts: %w", err)
+	}
+	return nil
+}
+
+// toExpenseReportModel エンティティをモデルに変換
+func toExpenseReportModel(report *expensereport.ExpenseReport) *ExpenseReport {
+	model := &ExpenseReport{
+		ID:           report.ID,
+		Title:        report.Title,
+		Applicant:    report.Applicant,
+		Status:       report.Status,
+		TotalAmount:  report.TotalAmount,
+		Approver:     report.Approver,
+		ReimbursedBy: report.ReimbursedBy,
+		Comment:      report.Comment,
+		SubmittedAt:  report.SubmittedAt,
+		ApprovedAt:   report.ApprovedAt,
+		ReimbursedAt: report.ReimbursedAt,
+		CreatedAt:    report.CreatedAt,
+		UpdatedAt:    report.UpdatedAt,
+		Receipts:     make([]ExpenseReportReceipt, len(report.ReceiptIDs)),
+	}
+	for i, receiptID := range report.ReceiptIDs {
+		model.Receipts[i] = ExpenseReportReceipt{ReportID: report.ID, ReceiptID: receiptID, Position: i}
+	}
+	return model
+}
+
+// toExpenseReportEntity モデルをエンティティに変換
+func toExpenseReportEntity(model *ExpenseReport) *expensereport.ExpenseReport {
+	report := &expensereport.ExpenseReport{
+		ID:           model.ID,
+		Title:        model.Title,
+		Applicant:    model.Applicant,
+		Status:       model.Status,
+		ReceiptIDs:   make([]string, len(model.Receipts)),
+		TotalAmount:  model.TotalAmount,
+		Approver:     model.Approver,
+		ReimbursedBy: model.ReimbursedBy,
+		Comment:      model.Comment,
+		SubmittedAt:  model.SubmittedAt,
+		ApprovedAt:   model.ApprovedAt,
+		ReimbursedAt: model.ReimbursedAt,
+		CreatedAt:    model.CreatedAt,
+		UpdatedAt:    model.UpdatedAt,
+	}
+	for i, receipt := range model.Receipts {
+		report.ReceiptIDs[i] = receipt.ReceiptID
+	}
+	return report
+}
+
+// BunCollectionRepository BUN実装
+// コレクションは含めるレシートとともに読み書きするため、Create・Updateはレシートの紐付けを含めて上書きする
+type BunCollectionRepository struct {
+	baseRepository[Collection, entity.Collection]
+}
+
+// NewBunCollectionRepository 新しいBunCollectionRepositoryを作成
+func NewBunCollectionRepository(cfg *config.MySQLConfig) (*BunCollectionRepository, error) {
+	db, err := openDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewBunCollectionRepositoryWithDB(db), nil
+}
+
+// NewBunCollectionRepositoryWithDB DBインスタンスから作成（テスト用）
+func NewBunCollectionRepositoryWithDB(db *bun.DB) *BunCollectionRepository {
+	return &BunCollectionRepository{
+		baseRepository: newBaseRepository(db, "collection", modelMapper[Collection, entity.Collection]{
+			toModel:  infallible(toCollectionModel),
+			toEntity: infallible(toCollectionEntity),
+		}).withScope(func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Relation("Receipts", func(q *bun.SelectQuery) *bun.SelectQuery {
+				return q.Order("position ASC")
+			})
+		}),
+	}
+}
+
+// Create コレクションとレシートの紐付けを作成
+func (r *BunCollectionRepository) Create(ctx context.Context, collection *entity.Collection) error {
+	model := toCollectionModel(collection)
+
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(model).Exec(ctx); err != nil {
+			return fmt.Errorf("failed to create collection: %w", err)
+		}
+		return insertCollectionReceipts(ctx, tx, model.Receipts)
+	})
+}
+
+// Update コレクションを更新し、レシートの紐付けを置き換える
+func (r *BunCollectionRepository) Update(ctx context.Context, collection *entity.Collection) error {
+	model := toCollectionModel(collection)
+
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewUpdate().Model(model).WherePK().Exec(ctx); err != nil {
+			return fmt.Errorf("failed to update collection: %w", err)
+		}
+		if _, err := tx.NewDelete().
+			Model((*CollectionReceipt)(nil)).
+			Where("collection_id = ?", model.ID).
+			Exec(ctx); err != nil {
+			return fmt.Errorf("failed to delete collection receipts: %w", err)
+		}
+		return insertCollectionReceipts(ctx, tx, model.Receipts)
+	})
+}
+
+// Delete コレクションとレシートの紐付けを削除（レシート自体は削除しない）
+func (r *BunCollectionRepository) Delete(ctx context.Context, id string) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewDelete().
+			Model((*CollectionReceipt)(nil)).
+			Where("collection_id = ?", id).
+			Exec(ctx); err != nil {
+			return fmt.Errorf("failed to delete collection receipts: %w", err)
+		}
+		if _, err := tx.NewDelete().Model((*Collection)(nil)).Where("id = ?", id).Exec(ctx); err != nil {
+			return fmt.Errorf("failed to delete collection: %w", err)
+		}
+		return nil
+	})
+}
+
+// FindAll コレクションを名前の順に取得
+func (r *BunCollectionRepository) FindAll(ctx context.Context, limit, offset int) ([]*entity.Collection, error) {
+	return r.findMany(ctx, limit, offset, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Order("collection.name ASC", "collection.id ASC")
+	})
+}
+
+// FindByReceiptID 指定したレシートを含むコレクションを名前の順に取得
+func (r *BunCollectionRepository) FindByReceiptID(ctx context.Context, receiptID string) ([]*entity.Collection, error) {
+	return r.findMany(ctx, 0, 0, func(q *bun.SelectQuery) *bun.SelectQuery {
+		subq := r.db.NewSelect().
+			Model((*CollectionReceipt)(nil)).
+			Column("collection_id").
+			Where("receipt_id = ?", receiptID)
+		return q.Where("?TableAlias.id IN (?)", subq).Order("collection.name ASC", "collection.id ASC")
+	})
+}
+
+// insertCollectionReceipts コレクションに含めるレシートを紐付ける
+func insertCollectionReceipts(ctx context.Context, tx bun.Tx, receipts []CollectionReceipt) error {
+	if len(receipts) == 0 {
+		return nil
+	}
+	if _, err := tx.NewInsert().Model(&receipts).Exec(ctx); err != nil {
+		return fmt.Errorf("failed to create collection receipts: %w", err)
+	}
+	return nil
+}
+
+// toCollectionModel エンティティをモデルに変換
+func toCollectionModel(collection *entity.Collection) *Collection {
+	model := &Collection{
+		ID:          collection.ID,
+		Name:        collection.Name,
+		Description: collection.Description,
+		CreatedAt:   collection.CreatedAt,
+		UpdatedAt:   collection.UpdatedAt,
+		Receipts:    make([]CollectionReceipt, len(collection.ReceiptIDs)),
+	}
+	for i, receiptID := range collection.ReceiptIDs {
+		model.Receipts[i] = CollectionReceipt{CollectionID: collection.ID, ReceiptID: receiptID, Position: i}
+	}
+	return model
+}
+
+// toCollectionEntity モデルをエンティティに変換
+func toCollectionEntity(model *Collection) *entity.Collection {
+	collection := &entity.Collection{
+		ID:          model.ID,
+		Name:        model.Name,
+		Description: model.Description,
+		ReceiptIDs:  make([]string, len(model.Receipts)),
+		CreatedAt:   model.CreatedAt,
+		UpdatedAt:   model.UpdatedAt,
+	}
+	for i, receipt := range model.Receipts {
+		collection.ReceiptIDs[i] = receipt.ReceiptID
+	}
+	return collection
+}
+
+// likePattern 部分一致検索用のLIKEパターンを作成（ワイルドカード文字はエスケープ）
+func likePattern(keyword string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return "%" + replacer.Replace(keyword) + "%"
+}
+
+// BunAggregateRepository BUN実装（レポート用の集計）
+type BunAggregateRepository struct {
+	db *bun.DB
+}
+
+// NewBunAggregateRepository 新しいBunAggregateRepositoryを作成
+func NewBunAggregateRepository(cfg *config.MySQLConfig) (*BunAggregateRepository, error) {
+	db, err := openDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewBunAggregateRepositoryWithDB(db), nil
+}
+
+// NewBunAggregateRepositoryWithDB DBインスタンスから作成（テスト用）
+func NewBunAggregateRepositoryWithDB(db *bun.DB) *BunAggregateRepository {
+	return &BunAggregateRepository{db: db}
+}
+
+// categoryAmountRow 集計結果の行
+type categoryAmountRow struct {
+	Bucket   time.Time `bun:"bucket"`
+	Category string    `bun:"amount_category"`
+	Count    int       `bun:"amount_count"`
+	Total    int64     `bun:"amount_total"`
+}
+
+// SumItemsByCategory 購入日時がstartからendまでのレシートの明細の金額を時間帯・カテゴリーごとに合計
+func (r *BunAggregateRepository) SumItemsByCategory(ctx context.Context, start, end time.Time) ([]*entity.CategoryAmount, error) {
+	var rows []categoryAmountRow
+	err := r.db.NewSelect().
+		TableExpr("receipt_items AS ri").
+		Join("JOIN receipts AS r ON r.id = ri.receipt_id").
+		ColumnExpr(aggregateBucketExpr("r.purchase_date")+" AS bucket").
+		ColumnExpr("COALESCE(ri.category, '') AS amount_category").
+		ColumnExpr("COUNT(*) AS amount_count").
+		ColumnExpr("SUM(ri.price * ri.quantity) AS amount_total").
+		Where("r.purchase_date BETWEEN ? AND ?", start, end).
+		GroupExpr("bucket, amount_category").
+		OrderExpr("bucket").
+		Scan(ctx, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum receipt items by category: %w", err)
+	}
+	return toCategoryAmounts(rows), nil
+}
+
+// SumExpensesByCategory 日付がstartからendまでのカテゴリー付きの家計簿エントリの金額を時間帯・カテゴリーごとに合計
+func (r *BunAggregateRepository) SumExpensesByCategory(ctx context.Context, start, end time.Time) ([]*entity.CategoryAmount, error) {
+	var rows []categoryAmountRow
+	err := r.db.NewSelect().
+		TableExpr("expense_entries AS e").
+		ColumnExpr(aggregateBucketExpr("e.date")+" AS bucket").
+		ColumnExpr("e.category AS amount_category").
+		ColumnExpr("COUNT(*) AS amount_count").
+		ColumnExpr("SUM(e.amount) AS amount_total").
+		Where("e.date BETWEEN ? AND ?", start, end).
+		Where("e.category <> ''").
+		GroupExpr("bucket, amount_category").
+		OrderExpr("bucket").
+		Scan(ctx, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum expense entries by category: %w", err)
+	}
+	return toCategoryAmounts(rows), nil
+}
+
+// SumReceipts 購入日時がstartからendまでのレシートの枚数と合計金額を時間帯ごとに合計
+func (r *BunAggregateRepository) SumReceipts(ctx context.Context, start, end time.Time) ([]*entity.ReceiptAmount, error) {
+	var rows []struct {
+		Bucket time.Time `bun:"bucket"`
+		Count  int       `bun:"receipt_count"`
+		Total  int64     `bun:"receipt_total"`
+	}
+	err := r.db.NewSelect().
+		TableExpr("receipts AS r").
+		ColumnExpr(aggregateBucketExpr("r.purchase_date")+" AS bucket").
+		ColumnExpr("COUNT(*) AS receipt_count").
+		ColumnExpr("SUM(r.total_amount) AS receipt_total").
+		Where("r.purchase_date BETWEEN ? AND ?", start, end).
+		GroupExpr("bucket").
+		OrderExpr("bucket").
+		Scan(ctx, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum receipts: %w", err)
+	}
+
+	amounts := make([]*entity.ReceiptAmount, len(rows))
+	for i, row := range rows {
+		amounts[i] = &entity.ReceiptAmount{Time: row.Bucket, Count: row.Count, Total: row.Total}
+	}
+	return amounts, nil
+}
+
+// SumByCategory 購入日時・日付がstartからendまでのレシートの明細とカテゴリー付きの家計簿エントリを、時間帯に分けずにカテゴリーごとに合計
+// 明細と家計簿エントリをそれぞれカテゴリーごとに合計し、UNION ALLで1回の問い合わせにまとめる
+func (r *BunAggregateRepository) SumByCategory(ctx context.Context, start, end time.Time) ([]*entity.CategoryBreakdown, error) {
+	items := r.db.NewSelect().
+		TableExpr("receipt_items AS ri").
+		Join("JOIN receipts AS r ON r.id = ri.receipt_id").
+		ColumnExpr("COALESCE(ri.category, '') AS breakdown_category").
+		ColumnExpr("COUNT(DISTINCT ri.receipt_id) AS receipt_count").
+		ColumnExpr("COUNT(*) AS item_count").
+		ColumnExpr("SUM(ri.price * ri.quantity) AS item_total").
+		ColumnExpr("0 AS expense_count").
+		ColumnExpr("0 AS expense_total").
+		Where("r.purchase_date BETWEEN ? AND ?", start, end).
+		GroupExpr("breakdown_category")
+	expenses := r.db.NewSelect().
+		TableExpr("expense_entries AS e").
+		ColumnExpr("e.category AS breakdown_category").
+		ColumnExpr("0 AS receipt_count").
+		ColumnExpr("0 AS item_count").
+		ColumnExpr("0 AS item_total").
+		ColumnExpr("COUNT(*) AS expense_count").
+		ColumnExpr("SUM(e.amount) AS expense_total").
+		Where("e.date BETWEEN ? AND ?", start, end).
+		Where("e.category <> ''").
+		GroupExpr("breakdown_category")
+
+	var rows []struct {
+		Category     string `bun:"breakdown_category"`
+		ReceiptCount int    `bun:"receipt_count"`
+		ItemCount    int    `bun:"item_count"`
+		ItemTotal    int64  `bun:"item_total"`
+		ExpenseCount int    `bun:"expense_count"`
+		ExpenseTotal int64  `bun:"expense_total"`
+	}
+	err := r.db.NewSelect().
+		TableExpr("(?) AS t", items.UnionAll(expenses)).
+		ColumnExpr("t.breakdown_category").
+		ColumnExpr("SUM(t.receipt_count) AS receipt_count").
+		ColumnExpr("SUM(t.item_count) AS item_count").
+		ColumnExpr("SUM(t.item_total) AS item_total").
+		ColumnExpr("SUM(t.expense_count) AS expense_count").
+		ColumnExpr("SUM(t.expense_total) AS expense_total").
+		GroupExpr("t.breakdown_category").
+		OrderExpr("t.breakdown_category").
+		Scan(ctx, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum by category: %w", err)
+	}
+
+	breakdowns := make([]*entity.CategoryBreakdown, len(rows))
+	for i, row := range rows {
+		breakdowns[i] = &entity.CategoryBreakdown{
+			Category:     row.Category,
+			ReceiptCount: row.ReceiptCount,
+			ItemCount:    row.ItemCount,
+			ItemTotal:    row.ItemTotal,
+			ExpenseCount: row.ExpenseCount,
+			ExpenseTotal: row.ExpenseTotal,
+		}
+	}
+	return breakdowns, nil
+}
+
+// Close データベース接続を閉じる
+func (r *BunAggregateRepository) Close() error {
+	return r.db.Close()
+}
+
+// aggregateBucketExpr 日時の列を集計単位（repository.AggregateBucket）の時間帯の開始日時に切り捨てる式
+func aggregateBucketExpr(column string) string {
+	minutes := int(repository.AggregateBucket / time.Minute)
+	return fmt.Sprintf("CAST(TIMESTAMPADD(MINUTE, MINUTE(%[1]s) DIV %[2]d * %[2]d, DATE_FORMAT(%[1]s, '%%Y-%%m-%%d %%H:00:00')) AS DATETIME)", column, minutes)
+}
+
+// toCategoryAmounts 集計結果の行をエンティティに変換
+func toCategoryAmounts(rows []categoryAmountRow) []*entity.CategoryAmount {
+	amounts := make([]*entity.CategoryAmount, len(rows))
+	for i, row := range rows {
+		amounts[i] = &entity.CategoryAmount{
+			Time:     row.Bucket,
+			Category: row.Category,
+			Count:    row.Count,
+			Total:    row.Total,
+		}
+	}
+	return amounts
+}
+
+// AmountSetting BUNモデル（保存している金額の通貨。金額の換算を二重に適用しないよう記録する）
+type AmountSetting struct {
+	bun.BaseModel `bun:"table:amount_settings"`
+
+	ID        int       `bun:"id,pk"`
+	Currency  string    `bun:"currency,notnull,type:char(3)"`
+	Digits    int       `bun:"digits,notnull"`
+	UpdatedAt time.Time `bun:"updated_at,notnull,default:current_timestamp"`
+}
+
+// amountSettingID amount_settingsの唯一の行のID
+const amountSettingID = 1
+
+// amountRescaleBatchSize スナップショットを換算する際に一度に読み込む行数
+const amountRescaleBatchSize = 500
+
+// errAmountRescaleDryRun 検出のみの場合にトランザクションを取り消すためのエラー
+var errAmountRescaleDryRun = errors.New("amount rescale dry run")
+
+// AmountRescaleReport 保存済みの金額の換算結果
+type AmountRescaleReport struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Factor    int64  `json:"factor"` // 金額に掛けた倍率
+	DryRun    bool   `json:"dry_run"`
+	Receipts  int64  `json:"receipts"` // 金額が変わった行数（0円の行は数えない）
+	Items     int64  `json:"items"`
+	Expenses  int64  `json:"expenses"`
+	Revisions int    `json:"revisions"`
+	Merges    int    `json:"merges"`
+	Trash     int    `json:"trash"`
+	Splits    int    `json:"splits"`
+}
+
+// BunAmountRescaler 保存済みの金額を通貨の最小単位の桁数に合わせて換算する
+type BunAmountRescaler struct {
+	db *bun.DB
+}
+
+// NewBunAmountRescaler 新しいBunAmountRescalerを作成
+func NewBunAmountRescaler(cfg *config.MySQLConfig) (*BunAmountRescaler, error) {
+	db, err := openDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewBunAmountRescalerWithDB(db), nil
+}
+
+// NewBunAmountRescalerWithDB DBインスタンスから作成（テスト用）
+func NewBunAmountRescalerWithDB(db *bun.DB) *BunAmountRescaler {
+	return &BunAmountRescaler{db: db}
+}
+
+// StoredCurrency 保存している金額の通貨コードを取得
+func (r *BunAmountRescaler) StoredCurrency(ctx context.Context) (string, error) {
+	setting, err := r.storedSetting(ctx, r.db)
+	if err != nil {
+		return "", err
+	}
+	return setting.Currency, nil
+}
+
+// storedSetting 保存している金額の通貨を取得
+func (r *BunAmountRescaler) storedSetting(ctx context.Context, db bun.IDB) (*AmountSetting, error) {
+	setting := new(AmountSetting)
+	if err := db.NewSelect().Model(setting).Where("id = ?", amountSettingID).Scan(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get amount setting: %w", err)
+	}
+	return setting, nil
+}
+
+// Rescale 保存済みの金額（レシート・明細・家計簿エントリ、変更履歴・統合の記録・ゴミ箱・割り勘のスナップショット）を
+// 保存している通貨の最小単位からtoの最小単位に換算し、保存している通貨をtoとして記録する。
+// 桁数が減る換算は端数が失われるためエラーとし、保存している通貨がtoと同じ場合も二重に換算しないようエラーとする。
+// dryRunの場合は同じトランザクションで換算した件数を数えてから取り消す
+func (r *BunAmountRescaler) Rescale(ctx context.Context, to sharedDomain.Currency, dryRun bool) (*AmountRescaleReport, error) {
+	report := &AmountRescaleReport{To: to.Code, DryRun: dryRun}
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		setting := new(AmountSetting)
+		if err := tx.NewSelect().Model(setting).Where("id = ?", amountSettingID).For("UPDATE").Scan(ctx); err != nil {
+			return fmt.Errorf("failed to get amount setting: %w", err)
+		}
+		report.From = setting.Currency
+		if setting.Currency == to.Code {
+			return fmt.Errorf("amounts are already stored in %s", to.Code)
+		}
+		if to.Digits < setting.Digits {
+			return fmt.Errorf("cannot rescale amounts from %d to %d digits without losing fractions", setting.Digits, to.Digits)
+		}
+		report.Factor = 1
+		for range to.Digits - setting.Digits {
+			report.Factor *= 10
+		}
+
+		if err := r.rescaleColumns(ctx, tx, report); err != nil {
+			return err
+		}
+		if err := r.rescaleSnapshots(ctx, tx, report); err != nil {
+			return err
+		}
+
+		setting.Currency, setting.Digits, setting.UpdatedAt = to.Code, to.Digits, time.Now()
+		if _, err := tx.NewUpdate().Model(setting).WherePK().Exec(ctx); err != nil {
+			return fmt.Errorf("failed to update amount setting: %w", err)
+		}
+		if dryRun {
+			return errAmountRescaleDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errAmountRescaleDryRun) {
+		return nil, err
+	}
+	return report, nil
+}
+
+// rescaleColumns 金額の列を換算
+func (r *BunAmountRescaler) rescaleColumns(ctx context.Context, tx bun.Tx, report *AmountRescaleReport) error {
+	updates := []struct {
+		table string
+		set   string
+		count *int64
+	}{
+		{table: "receipts", set: "total_amount = total_amount * ?0, tax_amount = tax_amount * ?0", count: &report.Receipts},
+		{table: "receipt_items", set: "price = price * ?0", count: &report.Items},
+		{table: "expense_entries", set: "amount = amount * ?0", count: &report.Expenses},
+	}
+	for _, u := range updates {
+		res, err := tx.NewUpdate().Table(u.table).Set(u.set, report.Factor).Where("1 = 1").Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to rescale %s: %w", u.table, err)
+		}
+		if *u.count, err = res.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to count rescaled %s: %w", u.table, err)
+		}
+	}
+	return nil
+}
+
+// rescaleSnapshots JSONで保存したスナップショットの金額を換算
+func (r *BunAmountRescaler) rescaleSnapshots(ctx context.Context, tx bun.Tx, report *AmountRescaleReport) error {
+	factor := report.Factor
+	var err error
+
+	report.Revisions, err = rescaleBatches(ctx, tx, "id", func(revision *ReceiptRevision) (err error) {
+		revision.Snapshot, err = rescaleReceiptSnapshot(revision.Snapshot, factor)
+		return err
+	}, "snapshot")
+	if err != nil {
+		return fmt.Errorf("failed to rescale receipt revisions: %w", err)
+	}
+
+	report.Merges, err = rescaleBatches(ctx, tx, "id", func(merge *ReceiptMerge) (err error) {
+		if merge.Before, err = rescaleReceiptSnapshot(merge.Before, factor); err != nil {
+			return err
+		}
+		merge.MergedReceipt, err = rescaleReceiptSnapshot(merge.MergedReceipt, factor)
+		return err
+	}, "before_snapshot", "merged_snapshot")
+	if err != nil {
+		return fmt.Errorf("failed to rescale receipt merges: %w", err)
+	}
+
+	report.Trash, err = rescaleBatches(ctx, tx, "kind, item_id", func(entry *TrashEntry) (err error) {
+		if entry.Kind == entity.TrashKindExpense {
+			entry.Snapshot, err = rescaleSnapshot(entry.Snapshot, func(expense *ExpenseEntry) {
+				expense.Amount *= factor
+			})
+			return err
+		}
+		entry.Snapshot, err = rescaleReceiptSnapshot(entry.Snapshot, factor)
+		return err
+	}, "snapshot")
+	if err != nil {
+		return fmt.Errorf("failed to rescale trash entries: %w", err)
+	}
+
+	report.Splits, err = rescaleBatches(ctx, tx, "receipt_id", func(split *ReceiptSplit) error {
+		for i := range split.Participants {
+			p := &split.Participants[i]
+			p.Subtotal, p.Tax, p.Total = p.Subtotal*factor, p.Tax*factor, p.Total*factor
+		}
+		return nil
+	}, "participants")
+	if err != nil {
+		return fmt.Errorf("failed to rescale receipt splits: %w", err)
+	}
+	return nil
+}
+
+// rescaleBatches テーブルの行を主キーの順に一定件数ずつ読み込み、scaleで換算した列を更新して件数を返す
+func rescaleBatches[T any](ctx context.Context, tx bun.Tx, order string, scale func(*T) error, columns ...string) (int, error) {
+	count := 0
+	for offset := 0; ; offset += amountRescaleBatchSize {
+		var rows []T
+		if err := tx.NewSelect().Model(&rows).OrderExpr(order).Limit(amountRescaleBatchSize).Offset(offset).Scan(ctx); err != nil {
+			return count, err
+		}
+		for i := range rows {
+			if err := scale(&rows[i]); err != nil {
+				return count, err
+			}
+			if _, err := tx.NewUpdate().Model(&rows[i]).Column(columns...).WherePK().Exec(ctx); err != nil {
+				return count, err
+			}
+			count++
+		}
+		if len(rows) < amountRescaleBatchSize {
+			return count, nil
+		}
+	}
+}
+
+// rescaleReceiptSnapshot レシートのスナップショットの合計金額・消費税額・明細の単価を換算
+func rescaleReceiptSnapshot(data string, factor int64) (string, error) {
+	return rescaleSnapshot(data, func(receipt *Receipt) {
+		receipt.TotalAmount *= factor
+		receipt.TaxAmount *= factor
+		for i := range receipt.Items {
+			receipt.Items[i].Price *= factor
+		}
+	})
+}
+
+// rescaleSnapshot スナップショットのJSONをモデルとして読み込み、scaleで換算して書き戻す
+func rescaleSnapshot[T any](data string, scale func(*T)) (string, error) {
+	var model T
+	if err := json.Unmarshal([]byte(data), &model); err != nil {
+		return "", fmt.Errorf("failed to unmarshal snapshot: %w", err)
+	}
+	scale(&model)
+	rescaled, err := json.Marshal(&model)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	return string(rescaled), nil
+}
+
+// Close データベース接続を閉じる
+func (r *BunAmountRescaler) Close() error {
+	return r.db.Close()
+}
+
+// BunSpendContributionRepository BUN実装（公開統計に提供された集計値）
+type BunSpendContributionRepository struct {
+	db *bun.DB
+}
+
+// NewBunSpendContributionRepository 新しいBunSpendContributionRepositoryを作成
+func NewBunSpendContributionRepository(cfg *config.MySQLConfig) (*BunSpendContributionRepository, error) {
+	db, err := openDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewBunSpendContributionRepositoryWithDB(db), nil
+}
+
+// NewBunSpendContributionRepositoryWithDB DBインスタンスから作成（テスト用）
+func NewBunSpendContributionRepositoryWithDB(db *bun.DB) *BunSpendContributionRepository {
+	return &BunSpendContributionRepository{db: db}
+}
+
+// Save 集計値を保存（同じ世帯・月の集計値は地域を含めて上書き）
+func (r *BunSpendContributionRepository) Save(ctx context.Context, contribution *analytics.SpendContribution) error {
+	model := &SpendContribution{
+		ContributorID: contribution.ContributorID,
+		Month:         contribution.Month,
+		Region:        contribution.Region,
+		Currency:      contribution.Currency,
+		Amount:        contribution.Amount,
+		UpdatedAt:     contribution.UpdatedAt,
+	}
+
+	_, err := r.db.NewInsert().
+		Model(model).
+		On("DUPLICATE KEY UPDATE").
+		Set("region = VALUES(region)").
+		Set("currency = VALUES(currency)").
+		Set("amount = VALUES(amount)").
+		Set("updated_at = VALUES(updated_at)").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to save spend contribution: %w", err)
+	}
+	return nil
+}
+
+// Aggregate fromMonthからtoMonthまでの集計値を地域・月・通貨ごとに合計（月・地域の順）
+// 主キーが世帯・月のため、行数がそのまま提供した世帯数になる
+func (r *BunSpendContributionRepository) Aggregate(ctx context.Context, fromMonth, toMonth, region string) ([]*analytics.SpendGroup, error) {
+	var rows []struct {
+		Region       string `bun:"region"`
+		Month        string `bun:"month"`
+		Currency     string `bun:"currency"`
+		Contributors int    `bun:"contributors"`
+		Total        int64  `bun:"total"`
+	}
+	query := r.db.NewSelect().
+		TableExpr("spend_contributions AS sc").
+		ColumnExpr("sc.region, sc.month, sc.currency").
+		ColumnExpr("COUNT(*) AS contributors").
+		ColumnExpr("SUM(sc.amount) AS total").
+		Where("sc.month BETWEEN ? AND ?", fromMonth, toMonth).
+		GroupExpr("sc.month, sc.region, sc.currency").
+		OrderExpr("sc.month, sc.region, sc.currency")
+	if region != "" {
+		query = query.Where("sc.region = ?", region)
+	}
+	if err := query.Scan(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("failed to aggregate spend contributions: %w", err)
+	}
+
+	groups := make([]*analytics.SpendGroup, len(rows))
+	for i, row := range rows {
+		groups[i] = &analytics.SpendGroup{
+			Region:       row.Region,
+			Month:        row.Month,
+			Currency:     row.Currency,
+			Contributors: row.Contributors,
+			Total:        row.Total,
+		}
+	}
+	return groups, nil
+}
+
+// Close データベース接続を閉じる
+func (r *BunSpendContributionRepository) Close() error {
+	return r.db.Close()
+}
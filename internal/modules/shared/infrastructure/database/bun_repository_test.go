@@ -3,19 +3,26 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	analytics "vision-api-app/internal/modules/analytics/domain"
+	expensereport "vision-api-app/internal/modules/expensereport/domain"
 	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
 	"vision-api-app/internal/modules/shared/infrastructure/testcontainer"
+	"vision-api-app/internal/modules/shared/infrastructure/testsupport"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/mysqldialect"
 )
 
-func setupTestDB(t *testing.T) (*bun.DB, func()) {
+func setupTestDB(t testing.TB) (*bun.DB, func()) {
 	t.Helper()
 	ctx := context.Background()
 
@@ -43,6 +50,10 @@ func setupTestDB(t *testing.T) (*bun.DB, func()) {
 		_ = mysqlContainer.Close(ctx)
 		t.Fatalf("Failed to create receipt_items table: %v", err)
 	}
+	if _, err := db.NewCreateTable().Model((*ReceiptRevision)(nil)).IfNotExists().Exec(ctx); err != nil {
+		_ = mysqlContainer.Close(ctx)
+		t.Fatalf("Failed to create receipt_revisions table: %v", err)
+	}
 	if _, err := db.NewCreateTable().Model((*ExpenseEntry)(nil)).IfNotExists().Exec(ctx); err != nil {
 		_ = mysqlContainer.Close(ctx)
 		t.Fatalf("Failed to create expense_entries table: %v", err)
@@ -51,6 +62,50 @@ func setupTestDB(t *testing.T) (*bun.DB, func()) {
 		_ = mysqlContainer.Close(ctx)
 		t.Fatalf("Failed to create categories table: %v", err)
 	}
+	if _, err := db.NewCreateTable().Model((*ReceiptSplit)(nil)).IfNotExists().Exec(ctx); err != nil {
+		_ = mysqlContainer.Close(ctx)
+		t.Fatalf("Failed to create receipt_splits table: %v", err)
+	}
+	if _, err := db.NewCreateTable().Model((*ReceiptMerge)(nil)).IfNotExists().Exec(ctx); err != nil {
+		_ = mysqlContainer.Close(ctx)
+		t.Fatalf("Failed to create receipt_merges table: %v", err)
+	}
+	if _, err := db.NewCreateTable().Model((*TrashEntry)(nil)).IfNotExists().Exec(ctx); err != nil {
+		_ = mysqlContainer.Close(ctx)
+		t.Fatalf("Failed to create trash_entries table: %v", err)
+	}
+	if _, err := db.NewCreateTable().Model((*AccountingSync)(nil)).IfNotExists().Exec(ctx); err != nil {
+		_ = mysqlContainer.Close(ctx)
+		t.Fatalf("Failed to create accounting_syncs table: %v", err)
+	}
+	if _, err := db.NewCreateTable().Model((*ItemAlias)(nil)).IfNotExists().Exec(ctx); err != nil {
+		_ = mysqlContainer.Close(ctx)
+		t.Fatalf("Failed to create item_aliases table: %v", err)
+	}
+	if _, err := db.NewCreateTable().Model((*AmountSetting)(nil)).IfNotExists().Exec(ctx); err != nil {
+		_ = mysqlContainer.Close(ctx)
+		t.Fatalf("Failed to create amount_settings table: %v", err)
+	}
+	if _, err := db.NewCreateTable().Model((*ExpenseReport)(nil)).IfNotExists().Exec(ctx); err != nil {
+		_ = mysqlContainer.Close(ctx)
+		t.Fatalf("Failed to create expense_reports table: %v", err)
+	}
+	if _, err := db.NewCreateTable().Model((*ExpenseReportReceipt)(nil)).IfNotExists().Exec(ctx); err != nil {
+		_ = mysqlContainer.Close(ctx)
+		t.Fatalf("Failed to create expense_report_receipts table: %v", err)
+	}
+	if _, err := db.NewCreateTable().Model((*SpendContribution)(nil)).IfNotExists().Exec(ctx); err != nil {
+		_ = mysqlContainer.Close(ctx)
+		t.Fatalf("Failed to create spend_contributions table: %v", err)
+	}
+	if _, err := db.NewCreateTable().Model((*Collection)(nil)).IfNotExists().Exec(ctx); err != nil {
+		_ = mysqlContainer.Close(ctx)
+		t.Fatalf("Failed to create collections table: %v", err)
+	}
+	if _, err := db.NewCreateTable().Model((*CollectionReceipt)(nil)).IfNotExists().Exec(ctx); err != nil {
+		_ = mysqlContainer.Close(ctx)
+		t.Fatalf("Failed to create collection_receipts table: %v", err)
+	}
 
 	return db, func() {
 		_ = db.Close()
@@ -156,6 +211,78 @@ func TestBunReceiptRepository_FindByID(t *testing.T) {
 	}
 }
 
+func TestBunReceiptRepository_WeighedItems(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewBunReceiptRepositoryWithDB(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	receipt := &entity.Receipt{
+		ID:           "test-weighed-1",
+		StoreName:    "テストストア",
+		PurchaseDate: now,
+		TotalAmount:  834,
+		Items: []entity.ReceiptItem{
+			{Name: "豚こま切れ", Quantity: 1, Price: 634, Unit: entity.UnitGram, Measure: 320, UnitPrice: 1980},
+			{Name: "牛乳", Quantity: 1, Price: 200, Unit: entity.UnitPiece},
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := repo.Create(ctx, receipt); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	found, err := repo.FindByID(ctx, receipt.ID)
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if len(found.Items) != 2 {
+		t.Fatalf("len(Items) = %d, want 2", len(found.Items))
+	}
+	pork := found.Items[0]
+	if pork.Unit != entity.UnitGram || pork.Measure != 320 || pork.UnitPrice != 1980 || pork.Price != 634 {
+		t.Errorf("weighed item = %+v, want 320g of 1980/kg for 634", pork)
+	}
+	if found.Items[1].Unit != entity.UnitPiece {
+		t.Errorf("Unit = %q, want %q", found.Items[1].Unit, entity.UnitPiece)
+	}
+}
+
+func TestBunReceiptRepository_Refund(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewBunReceiptRepositoryWithDB(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	receipt := &entity.Receipt{
+		ID:           "test-refund-1",
+		StoreName:    "テストストア",
+		PurchaseDate: now,
+		TotalAmount:  -1100,
+		TaxAmount:    -100,
+		Type:         entity.ReceiptTypeRefund,
+		Items:        []entity.ReceiptItem{{Name: "シャツ", Quantity: 1, Price: -1100}},
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := repo.Create(ctx, receipt); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	found, err := repo.FindByID(ctx, receipt.ID)
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if !found.IsRefund() || found.TotalAmount != -1100 || found.TaxAmount != -100 || found.Items[0].Price != -1100 {
+		t.Errorf("refund = type %s, total %d, tax %d, price %d", found.Type, found.TotalAmount, found.TaxAmount, found.Items[0].Price)
+	}
+}
+
 func TestBunReceiptRepository_FindByDateRange(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -211,6 +338,350 @@ func TestBunReceiptRepository_FindByDateRange(t *testing.T) {
 	}
 }
 
+// TestBunReceiptRepository_FindByIDs まとめて取得のテスト（件数によらずクエリ数が一定）
+func TestBunReceiptRepository_FindByIDs(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewBunReceiptRepositoryWithDB(db)
+	ctx := context.Background()
+
+	baseTime := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	var ids []string
+	for i := range 5 {
+		id := fmt.Sprintf("test-ids-%d", i)
+		ids = append(ids, id)
+		receipt := &entity.Receipt{
+			ID:           id,
+			StoreName:    "ストア",
+			PurchaseDate: baseTime.AddDate(0, 0, i),
+			TotalAmount:  300,
+			Items: []entity.ReceiptItem{
+				{ID: id + "-1", Name: "商品1", Quantity: 1, Price: 100},
+				{ID: id + "-2", Name: "商品2", Quantity: 1, Price: 200},
+			},
+		}
+		if err := repo.Create(ctx, receipt); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	queries := testsupport.CountQueries(db)
+
+	// 明細の読み込みはレシートの件数によらず1回
+	all, err := repo.FindAll(ctx, 10, 0)
+	if err != nil || len(all) != 5 {
+		t.Fatalf("FindAll() = %d receipts, error = %v", len(all), err)
+	}
+	queries.AssertAtMost(t, 2)
+
+	// idsの順に返し、存在しないID・重複は含めない
+	want := []string{ids[3], ids[0], ids[4]}
+	found, err := repo.FindByIDs(ctx, []string{ids[3], "unknown", ids[0], ids[3], ids[4]})
+	if err != nil {
+		t.Fatalf("FindByIDs() error = %v", err)
+	}
+	queries.AssertAtMost(t, 2)
+	if len(found) != len(want) {
+		t.Fatalf("FindByIDs() = %d receipts, want %d", len(found), len(want))
+	}
+	for i, receipt := range found {
+		if receipt.ID != want[i] || len(receipt.Items) != 2 {
+			t.Errorf("FindByIDs()[%d] = %s with %d items, want %s with 2 items", i, receipt.ID, len(receipt.Items), want[i])
+		}
+	}
+
+	if found, err := repo.FindByIDs(ctx, nil); err != nil || len(found) != 0 {
+		t.Errorf("FindByIDs(nil) = %v, %v", found, err)
+	}
+	queries.AssertAtMost(t, 0)
+}
+
+// TestBunReceiptRepository_ForEachByDateRange 一定件数ずつ読み込みながら購入日の古い順に渡すテスト
+func TestBunReceiptRepository_ForEachByDateRange(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewBunReceiptRepositoryWithDB(db)
+	ctx := context.Background()
+
+	// ページの境界で同じ購入日時のレシートが分かれるように、2件ずつ同じ購入日時にする
+	baseTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	count := receiptPageSize + 3
+	receipts := make([]Receipt, 0, count)
+	items := make([]ReceiptItem, 0, count)
+	for i := range count {
+		id := fmt.Sprintf("test-each-%04d", i)
+		receipts = append(receipts, Receipt{ID: id, StoreName: "ストア", PurchaseDate: baseTime.Add(time.Duration(i/2) * time.Hour), TotalAmount: 100})
+		items = append(items, ReceiptItem{ID: id + "-1", ReceiptID: id, Name: "商品", Quantity: 1, Price: 100})
+	}
+	if _, err := db.NewInsert().Model(&receipts).Exec(ctx); err != nil {
+		t.Fatalf("Failed to insert receipts: %v", err)
+	}
+	if _, err := db.NewInsert().Model(&items).Exec(ctx); err != nil {
+		t.Fatalf("Failed to insert receipt items: %v", err)
+	}
+
+	queries := testsupport.CountQueries(db)
+	var ids []string
+	err := repo.ForEachByDateRange(ctx, baseTime, baseTime.AddDate(1, 0, 0), func(receipt *entity.Receipt) error {
+		if len(receipt.Items) != 1 {
+			t.Errorf("receipt %s has %d items, want 1", receipt.ID, len(receipt.Items))
+		}
+		ids = append(ids, receipt.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachByDateRange() error = %v", err)
+	}
+	// 2ページ分（レシート・明細を1回ずつ）
+	queries.AssertAtMost(t, 4)
+
+	if len(ids) != count {
+		t.Fatalf("ForEachByDateRange() = %d receipts, want %d", len(ids), count)
+	}
+	for i, id := range ids {
+		if want := fmt.Sprintf("test-each-%04d", i); id != want {
+			t.Fatalf("receipt[%d] = %s, want %s", i, id, want)
+		}
+	}
+
+	// fnのエラーで終了する
+	stop := errors.New("stop")
+	visited := 0
+	err = repo.ForEachByDateRange(ctx, baseTime, baseTime.AddDate(1, 0, 0), func(receipt *entity.Receipt) error {
+		visited++
+		return stop
+	})
+	if err != stop || visited != 1 {
+		t.Errorf("ForEachByDateRange() error = %v, visited = %d, want stop after 1", err, visited)
+	}
+}
+
+// TestBunReceiptRepository_FindByFilter タグ・キーワード検索のテスト
+func TestBunReceiptRepository_FindByFilter(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewBunReceiptRepositoryWithDB(db)
+	ctx := context.Background()
+
+	receipts := []*entity.Receipt{
+		{ID: "test-tag-1", StoreName: "ストア1", PurchaseDate: time.Now(), TotalAmount: 1000, Tags: []string{"旅行", "出張"}},
+		{ID: "test-tag-2", StoreName: "ストア2", PurchaseDate: time.Now(), TotalAmount: 2000, Tags: []string{"旅行"}},
+		{ID: "test-tag-3", StoreName: "ストア3", PurchaseDate: time.Now(), TotalAmount: 3000, Memo: "100%還元"},
+	}
+	for _, r := range receipts {
+		if err := repo.Create(ctx, r); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	found, err := repo.FindByFilter(ctx, repository.ReceiptFilter{Tag: "旅行"}, 0, 0)
+	if err != nil {
+		t.Fatalf("FindByFilter() error = %v", err)
+	}
+	if len(found) != 2 {
+		t.Errorf("Found %d receipts, want 2", len(found))
+	}
+
+	found, err = repo.FindByFilter(ctx, repository.ReceiptFilter{Tag: "出張"}, 0, 0)
+	if err != nil {
+		t.Fatalf("FindByFilter() error = %v", err)
+	}
+	if len(found) != 1 || found[0].ID != "test-tag-1" {
+		t.Errorf("FindByFilter(出張) = %v, want test-tag-1", found)
+	}
+
+	// メモの部分一致（%はワイルドカードではなく文字として扱う）
+	found, err = repo.FindByFilter(ctx, repository.ReceiptFilter{Keyword: "0%還"}, 0, 0)
+	if err != nil {
+		t.Fatalf("FindByFilter() error = %v", err)
+	}
+	if len(found) != 1 || found[0].Memo != "100%還元" {
+		t.Errorf("FindByFilter(keyword) = %v, want test-tag-3", found)
+	}
+
+	untagged, err := repo.FindByID(ctx, "test-tag-3")
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if untagged.Tags == nil || len(untagged.Tags) != 0 {
+		t.Errorf("Tags = %v, want empty slice", untagged.Tags)
+	}
+}
+
+func TestBunReceiptRepository_FindItemsByCategory(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewBunReceiptRepositoryWithDB(db)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	receipts := []*entity.Receipt{
+		{
+			ID: "test-cat-1", StoreName: "ドラッグストア", PurchaseDate: now.AddDate(0, 0, -1), TotalAmount: 896,
+			Items: []entity.ReceiptItem{
+				{ID: "test-cat-1-1", Name: "洗剤", Quantity: 2, Price: 298, Category: "日用品"},
+				{ID: "test-cat-1-2", Name: "のど飴", Quantity: 1, Price: 300},
+			},
+		},
+		{
+			ID: "test-cat-2", StoreName: "スーパー", PurchaseDate: now, TotalAmount: 398,
+			Items: []entity.ReceiptItem{
+				{ID: "test-cat-2-1", Name: "ティッシュ", Quantity: 1, Price: 398, Category: "日用品"},
+			},
+		},
+		{
+			ID: "test-cat-3", StoreName: "スーパー", PurchaseDate: now, TotalAmount: 200,
+			Items: []entity.ReceiptItem{
+				{ID: "test-cat-3-1", Name: "牛乳", Quantity: 1, Price: 200, Category: "食費"},
+			},
+		},
+	}
+	for _, r := range receipts {
+		if err := repo.Create(ctx, r); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	items, err := repo.FindItemsByCategory(ctx, "日用品", 0, 0)
+	if err != nil {
+		t.Fatalf("FindItemsByCategory() error = %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("Found %d items, want 2", len(items))
+	}
+	// 購入日の新しい順
+	if items[0].Item.ID != "test-cat-2-1" || items[0].StoreName != "スーパー" {
+		t.Errorf("items[0] = %+v, want test-cat-2-1 from スーパー", items[0])
+	}
+	if items[1].Item.ReceiptID != "test-cat-1" || items[1].Amount() != 596 {
+		t.Errorf("items[1] = %+v, want receipt test-cat-1 and amount 596", items[1])
+	}
+
+	items, err = repo.FindItemsByCategory(ctx, "日用品", 1, 1)
+	if err != nil {
+		t.Fatalf("FindItemsByCategory() error = %v", err)
+	}
+	if len(items) != 1 || items[0].Item.ID != "test-cat-1-1" {
+		t.Errorf("FindItemsByCategory(limit=1, offset=1) = %v, want test-cat-1-1", items)
+	}
+
+	// カテゴリー未設定の明細は「その他」として扱う
+	items, err = repo.FindItemsByCategory(ctx, entity.DefaultCategory, 0, 0)
+	if err != nil {
+		t.Fatalf("FindItemsByCategory() error = %v", err)
+	}
+	if len(items) != 1 || items[0].Item.ID != "test-cat-1-2" {
+		t.Errorf("FindItemsByCategory(その他) = %v, want test-cat-1-2", items)
+	}
+
+	found, err := repo.FindByFilter(ctx, repository.ReceiptFilter{Category: "日用品"}, 0, 0)
+	if err != nil {
+		t.Fatalf("FindByFilter() error = %v", err)
+	}
+	if len(found) != 2 {
+		t.Errorf("FindByFilter(日用品) found %d receipts, want 2", len(found))
+	}
+}
+
+func TestBunReceiptRepository_FindItemsByNormalizedName(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewBunReceiptRepositoryWithDB(db)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	receipts := []*entity.Receipt{
+		{
+			ID: "test-price-1", StoreName: "スーパーA", PurchaseDate: now, TotalAmount: 210,
+			Items: []entity.ReceiptItem{{ID: "test-price-1-1", Name: "牛乳", Quantity: 1, Price: 210}},
+		},
+		{
+			ID: "test-price-2", StoreName: "スーパーB", PurchaseDate: now.AddDate(0, -1, 0), TotalAmount: 398,
+			Items: []entity.ReceiptItem{
+				{ID: "test-price-2-1", Name: "※ 牛乳", Quantity: 2, Price: 199},
+				{ID: "test-price-2-2", Name: "低脂肪牛乳", Quantity: 1, Price: 180},
+			},
+		},
+	}
+	for _, r := range receipts {
+		if err := repo.Create(ctx, r); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	items, err := repo.FindItemsByNormalizedName(ctx, entity.NormalizeItemName("牛乳"), 0, 0)
+	if err != nil {
+		t.Fatalf("FindItemsByNormalizedName() error = %v", err)
+	}
+	// 購入日の古い順
+	if len(items) != 2 || items[0].Item.ID != "test-price-2-1" || items[1].Item.ID != "test-price-1-1" {
+		t.Fatalf("FindItemsByNormalizedName() = %v, want [test-price-2-1 test-price-1-1]", items)
+	}
+	if items[0].StoreName != "スーパーB" || items[0].Item.Price != 199 {
+		t.Errorf("items[0] = %+v, want スーパーB 199", items[0])
+	}
+}
+
+func TestBunReceiptRepository_FindWarrantyItems(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewBunReceiptRepositoryWithDB(db)
+	ctx := context.Background()
+
+	months := 6
+	receipt := &entity.Receipt{
+		ID: "test-warranty-1", StoreName: "家電店", PurchaseDate: time.Now(), TotalAmount: 83300,
+		Items: []entity.ReceiptItem{
+			{ID: "test-warranty-1-1", Name: "テレビ", Quantity: 1, Price: 80000},
+			{ID: "test-warranty-1-2", Name: "電気ケトル", Quantity: 1, Price: 3000, WarrantyMonths: &months},
+			{ID: "test-warranty-1-3", Name: "電池", Quantity: 1, Price: 300},
+		},
+	}
+	if err := repo.Create(ctx, receipt); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	items, err := repo.FindWarrantyItems(ctx, 10000)
+	if err != nil {
+		t.Fatalf("FindWarrantyItems() error = %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("Found %d items, want 2", len(items))
+	}
+	for _, item := range items {
+		if item.Item.ID == "test-warranty-1-2" && (item.Item.WarrantyMonths == nil || *item.Item.WarrantyMonths != 6) {
+			t.Errorf("WarrantyMonths = %v, want 6", item.Item.WarrantyMonths)
+		}
+	}
+
+	notifiedAt := time.Now().Truncate(time.Second)
+	if err := repo.MarkItemNotified(ctx, "test-warranty-1-1", entity.DeadlineWarranty, notifiedAt); err != nil {
+		t.Fatalf("MarkItemNotified() error = %v", err)
+	}
+	if err := repo.MarkItemNotified(ctx, "test-warranty-1-1", "unknown", notifiedAt); err == nil {
+		t.Error("MarkItemNotified(unknown) error = nil, want error")
+	}
+
+	found, err := repo.FindByID(ctx, "test-warranty-1")
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	for _, item := range found.Items {
+		if item.ID != "test-warranty-1-1" {
+			continue
+		}
+		if item.WarrantyNotifiedAt == nil || item.ReturnNotifiedAt != nil {
+			t.Errorf("notified = %v / %v, want warranty only", item.ReturnNotifiedAt, item.WarrantyNotifiedAt)
+		}
+	}
+}
+
 func TestBunExpenseRepository_Create(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -287,8 +758,44 @@ func TestBunCategoryRepository_Create(t *testing.T) {
 	}
 }
 
-// TestBunReceiptRepository_Update レシートの更新テスト
-func TestBunReceiptRepository_Update(t *testing.T) {
+// TestBunCategoryRepository_Duplicate カテゴリ名の重複・存在しないカテゴリの削除のテスト
+func TestBunCategoryRepository_Duplicate(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewBunCategoryRepositoryWithDB(db)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	food := entity.NewCategory("category-1", "食費", "", "#FF6B6B", now)
+	daily := entity.NewCategory("category-2", "日用品", "", "", now)
+	for _, category := range []*entity.Category{food, daily} {
+		if err := repo.Create(ctx, category); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	if err := repo.Create(ctx, entity.NewCategory("category-3", "食費", "", "", now)); !errors.Is(err, entity.ErrCategoryExists) {
+		t.Errorf("Create() error = %v, want %v", err, entity.ErrCategoryExists)
+	}
+	daily.Name = "食費"
+	if err := repo.Update(ctx, daily); !errors.Is(err, entity.ErrCategoryExists) {
+		t.Errorf("Update() error = %v, want %v", err, entity.ErrCategoryExists)
+	}
+
+	if err := repo.Delete(ctx, food.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := repo.Delete(ctx, food.ID); !errors.Is(err, entity.ErrCategoryNotFound) {
+		t.Errorf("Delete() error = %v, want %v", err, entity.ErrCategoryNotFound)
+	}
+	if _, err := repo.FindByID(ctx, food.ID); !errors.Is(err, entity.ErrCategoryNotFound) {
+		t.Errorf("FindByID() error = %v, want %v", err, entity.ErrCategoryNotFound)
+	}
+}
+
+// TestBunReceiptRepository_Update レシートの更新テスト
+func TestBunReceiptRepository_Update(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
@@ -327,6 +834,195 @@ func TestBunReceiptRepository_Update(t *testing.T) {
 	}
 }
 
+// TestBunReceiptRepository_FindByImageHash 元画像のハッシュによる検索テスト
+func TestBunReceiptRepository_FindByImageHash(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewBunReceiptRepositoryWithDB(db)
+	ctx := context.Background()
+
+	imageHash := strings.Repeat("ab", 32)
+	receipt := &entity.Receipt{
+		ID:           "0190a5b2-7c3d-7e4f-8a1b-2c3d4e5f6a7b",
+		StoreName:    "Store",
+		PurchaseDate: time.Now().Truncate(time.Second),
+		TotalAmount:  300,
+		ImageHash:    imageHash,
+	}
+	if err := repo.Create(ctx, receipt); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	found, err := repo.FindByImageHash(ctx, imageHash)
+	if err != nil {
+		t.Fatalf("FindByImageHash() error = %v", err)
+	}
+	if found.ID != receipt.ID || found.ImageHash != imageHash {
+		t.Errorf("FindByImageHash() = %s/%s, want %s/%s", found.ID, found.ImageHash, receipt.ID, imageHash)
+	}
+
+	if _, err := repo.FindByImageHash(ctx, strings.Repeat("cd", 32)); err == nil {
+		t.Error("FindByImageHash() of unknown hash error = nil, want error")
+	}
+}
+
+// TestBunReceiptRepository_UpdateItems 明細カテゴリーの更新テスト
+func TestBunReceiptRepository_UpdateItems(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewBunReceiptRepositoryWithDB(db)
+	ctx := context.Background()
+
+	receipt := &entity.Receipt{
+		ID:           "update-items-1",
+		StoreName:    "Store",
+		PurchaseDate: time.Now().Truncate(time.Second),
+		TotalAmount:  300,
+		Items: []entity.ReceiptItem{
+			{ID: "update-items-1-00000000", ReceiptID: "update-items-1", Name: "牛乳", Quantity: 1, Price: 200},
+			{ID: "update-items-1-00000001", ReceiptID: "update-items-1", Name: "洗剤", Quantity: 1, Price: 100},
+		},
+	}
+	if err := repo.Create(ctx, receipt); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// カテゴリーを設定して更新
+	receipt.Items[0].Category = "食費"
+	receipt.Items[1].Category = "日用品"
+	if err := repo.Update(ctx, receipt); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	updated, err := repo.FindByID(ctx, receipt.ID)
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if len(updated.Items) != 2 {
+		t.Fatalf("Items length = %v, want 2", len(updated.Items))
+	}
+	categories := map[string]string{}
+	for _, item := range updated.Items {
+		categories[item.Name] = item.Category
+	}
+	if categories["牛乳"] != "食費" || categories["洗剤"] != "日用品" {
+		t.Errorf("categories = %v, want 牛乳=食費, 洗剤=日用品", categories)
+	}
+}
+
+// TestBunReceiptRepository_UpdateUpsertsItemsByPosition 明細を順番で上書きするテスト
+func TestBunReceiptRepository_UpdateUpsertsItemsByPosition(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewBunReceiptRepositoryWithDB(db)
+	ctx := context.Background()
+
+	notifiedAt := time.Now().Truncate(time.Second)
+	receipt := &entity.Receipt{
+		ID:           "upsert-items-1",
+		StoreName:    "Store",
+		PurchaseDate: time.Now().Truncate(time.Second),
+		TotalAmount:  600,
+		Items: []entity.ReceiptItem{
+			{ID: "upsert-item-a", ReceiptID: "upsert-items-1", Name: "牛乳", Quantity: 1, Price: 200, ReturnNotifiedAt: &notifiedAt},
+			{ID: "upsert-item-b", ReceiptID: "upsert-items-1", Name: "洗剤", Quantity: 1, Price: 300},
+			{ID: "upsert-item-c", ReceiptID: "upsert-items-1", Name: "卵", Quantity: 1, Price: 100},
+		},
+	}
+	if err := repo.Create(ctx, receipt); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// 再処理で1件目は同じ明細、2件目以降は並びが入れ替わり、明細が1件減った
+	receipt.Items = []entity.ReceiptItem{
+		{ID: "upsert-item-a", ReceiptID: "upsert-items-1", Name: "牛乳", Quantity: 1, Price: 200},
+		{ID: "upsert-item-c", ReceiptID: "upsert-items-1", Name: "卵", Quantity: 1, Price: 100},
+	}
+	if err := repo.Update(ctx, receipt); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	updated, err := repo.FindByID(ctx, receipt.ID)
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if len(updated.Items) != 2 || updated.Items[0].ID != "upsert-item-a" || updated.Items[1].ID != "upsert-item-c" {
+		t.Fatalf("Items = %+v, want upsert-item-a, upsert-item-c in order", updated.Items)
+	}
+	// 同じ順番で同じIDの明細は通知日時を引き継ぐ
+	if updated.Items[0].ReturnNotifiedAt == nil {
+		t.Error("ReturnNotifiedAt was not kept for the unchanged item")
+	}
+
+	// 同じ内容で再度更新しても明細は増えない
+	if err := repo.Update(ctx, receipt); err != nil {
+		t.Fatalf("Update() again error = %v", err)
+	}
+	count, err := db.NewSelect().Model((*ReceiptItem)(nil)).Where("receipt_id = ?", receipt.ID).Count(ctx)
+	if err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	if count != 2 {
+		t.Errorf("item rows = %d, want 2", count)
+	}
+}
+
+// TestBunReceiptRevisionRepository_CreateAndFind 変更履歴の作成・取得テスト
+func TestBunReceiptRevisionRepository_CreateAndFind(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewBunReceiptRevisionRepositoryWithDB(db)
+	ctx := context.Background()
+
+	receipt := &entity.Receipt{
+		ID:           "revision-receipt-1",
+		StoreName:    "Store",
+		PurchaseDate: time.Now().Truncate(time.Second),
+		TotalAmount:  200,
+		Items: []entity.ReceiptItem{
+			{ID: "revision-receipt-1-00000000", ReceiptID: "revision-receipt-1", Name: "牛乳", Quantity: 1, Price: 200, Category: "食費"},
+		},
+	}
+	if err := repo.Create(ctx, entity.NewReceiptRevision("revision-1", receipt, 1, entity.RevisionSourceOriginal, time.Now())); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	receipt.StoreName = "Fixed Store"
+	if err := repo.Create(ctx, entity.NewReceiptRevision("revision-2", receipt, 2, entity.RevisionSourceManual, time.Now())); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	revisions, err := repo.FindByReceiptID(ctx, receipt.ID)
+	if err != nil {
+		t.Fatalf("FindByReceiptID() error = %v", err)
+	}
+	if len(revisions) != 2 {
+		t.Fatalf("revisions length = %v, want 2", len(revisions))
+	}
+	if revisions[0].Snapshot.StoreName != "Store" || revisions[1].Snapshot.StoreName != "Fixed Store" {
+		t.Errorf("snapshots = %v, %v", revisions[0].Snapshot.StoreName, revisions[1].Snapshot.StoreName)
+	}
+
+	first, err := repo.FindByRevision(ctx, receipt.ID, 1)
+	if err != nil {
+		t.Fatalf("FindByRevision() error = %v", err)
+	}
+	if first.Source != entity.RevisionSourceOriginal {
+		t.Errorf("Source = %v, want %v", first.Source, entity.RevisionSourceOriginal)
+	}
+	if len(first.Snapshot.Items) != 1 || first.Snapshot.Items[0].Category != "食費" {
+		t.Errorf("snapshot items = %+v", first.Snapshot.Items)
+	}
+
+	if _, err := repo.FindByRevision(ctx, receipt.ID, 3); err == nil {
+		t.Error("Expected error for missing revision")
+	}
+}
+
 // TestBunReceiptRepository_Delete レシートの削除テスト
 func TestBunReceiptRepository_Delete(t *testing.T) {
 	db, cleanup := setupTestDB(t)
@@ -372,7 +1068,7 @@ func TestBunExpenseRepository_FindAll(t *testing.T) {
 		entry := &entity.ExpenseEntry{
 			ID:          fmt.Sprintf("e%d", i),
 			Description: fmt.Sprintf("Expense %c", 'A'+i),
-			Amount:      100 * (i + 1),
+			Amount:      int64(100 * (i + 1)),
 			Date:        time.Now().Add(time.Duration(i) * time.Hour).Truncate(time.Second),
 			Category:    "Test",
 		}
@@ -400,6 +1096,80 @@ func TestBunExpenseRepository_FindAll(t *testing.T) {
 	}
 }
 
+// TestBunExpenseRepository_FindByReceiptID レシートに紐づく経費エントリの検索テスト
+func TestBunExpenseRepository_FindByReceiptID(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	receiptRepo := NewBunReceiptRepositoryWithDB(db)
+	repo := NewBunExpenseRepositoryWithDB(db)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	receipt := entity.NewReceipt("r1", "スーパー", now, 300, 0, "食費", now)
+	if err := receiptRepo.Create(ctx, receipt); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	receiptID := receipt.ID
+	entries := []*entity.ExpenseEntry{
+		{ID: "e2", ReceiptID: &receiptID, Amount: 200, Date: now, Category: "Test"},
+		{ID: "e1", ReceiptID: &receiptID, Amount: 100, Date: now.Add(-time.Hour), Category: "Test"},
+		{ID: "e3", Amount: 300, Date: now, Category: "Test"},
+	}
+	for _, entry := range entries {
+		if err := repo.Create(ctx, entry); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	found, err := repo.FindByReceiptID(ctx, "r1")
+	if err != nil {
+		t.Fatalf("FindByReceiptID() error = %v", err)
+	}
+	if len(found) != 2 || found[0].ID != "e1" || found[1].ID != "e2" {
+		t.Errorf("FindByReceiptID() = %+v, want e1, e2", found)
+	}
+}
+
+// TestBunExpenseRepository_FindByFilter 経費エントリのカテゴリー・日付範囲の検索テスト
+func TestBunExpenseRepository_FindByFilter(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewBunExpenseRepositoryWithDB(db)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	entries := []*entity.ExpenseEntry{
+		{ID: "e1", Amount: 100, Date: now.Add(-48 * time.Hour), Category: "食費"},
+		{ID: "e2", Amount: 200, Date: now.Add(-24 * time.Hour), Category: "食費"},
+		{ID: "e3", Amount: 300, Date: now, Category: "食費"},
+		{ID: "e4", Amount: 400, Date: now.Add(-24 * time.Hour), Category: "交通費"},
+	}
+	for _, entry := range entries {
+		if err := repo.Create(ctx, entry); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	found, err := repo.FindByFilter(ctx, repository.ExpenseFilter{Category: "食費", Start: now.Add(-24 * time.Hour), End: now}, 0, 0)
+	if err != nil {
+		t.Fatalf("FindByFilter() error = %v", err)
+	}
+	if len(found) != 1 || found[0].ID != "e2" {
+		t.Errorf("FindByFilter() = %+v, want e2", found)
+	}
+
+	// 条件がない場合はすべてを日付の新しい順に返す
+	found, err = repo.FindByFilter(ctx, repository.ExpenseFilter{}, 2, 1)
+	if err != nil {
+		t.Fatalf("FindByFilter() error = %v", err)
+	}
+	if len(found) != 2 || found[0].ID != "e2" || found[1].ID != "e4" {
+		t.Errorf("FindByFilter() = %+v, want e2, e4", found)
+	}
+}
+
 // TestBunExpenseRepository_FindByDateRange 経費エントリの日付範囲検索テスト
 func TestBunExpenseRepository_FindByDateRange(t *testing.T) {
 	db, cleanup := setupTestDB(t)
@@ -669,7 +1439,624 @@ func TestBunCategoryRepository_Delete(t *testing.T) {
 	}
 }
 
+func TestBunSplitRepository(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewBunSplitRepositoryWithDB(db)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	split := &entity.Split{
+		ReceiptID: "test-split-1",
+		Payer:     "A",
+		Participants: []entity.SplitParticipant{
+			{Name: "A", ItemIDs: []string{"item-1"}, Subtotal: 600, Tax: 60, Total: 660, Settled: true, SettledAt: &now},
+			{Name: "B", ItemIDs: []string{"item-2"}, Subtotal: 400, Tax: 40, Total: 440},
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := repo.Save(ctx, split); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	found, err := repo.FindByReceiptID(ctx, split.ReceiptID)
+	if err != nil {
+		t.Fatalf("FindByReceiptID() error = %v", err)
+	}
+	if found.Payer != "A" || len(found.Participants) != 2 || found.Participants[1].Total != 440 || found.Participants[0].SettledAt == nil {
+		t.Errorf("FindByReceiptID() = %+v", found)
+	}
+
+	unsettled, err := repo.FindUnsettled(ctx, 10, 0)
+	if err != nil || len(unsettled) != 1 {
+		t.Fatalf("FindUnsettled() = %d splits, error = %v, want 1", len(unsettled), err)
+	}
+
+	// 上書き保存で全員精算済みにすると未精算の一覧から外れる
+	split.Participants[1].Settled = true
+	if err := repo.Save(ctx, split); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if unsettled, err := repo.FindUnsettled(ctx, 10, 0); err != nil || len(unsettled) != 0 {
+		t.Errorf("FindUnsettled() = %d splits, error = %v, want 0", len(unsettled), err)
+	}
+
+	if err := repo.DeleteByReceiptID(ctx, split.ReceiptID); err != nil {
+		t.Fatalf("DeleteByReceiptID() error = %v", err)
+	}
+	if _, err := repo.FindByReceiptID(ctx, split.ReceiptID); err == nil {
+		t.Error("FindByReceiptID() after delete: expected error")
+	}
+}
+
+func TestBunReceiptMergeRepository(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewBunReceiptMergeRepositoryWithDB(db)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	merge := &entity.ReceiptMerge{
+		ID:              "merge-1",
+		ReceiptID:       "merge-receipt-1",
+		MergedReceiptID: "merge-receipt-2",
+		Before:          entity.Receipt{ID: "merge-receipt-1", StoreName: "Store", PurchaseDate: now, TotalAmount: 200},
+		MergedReceipt: entity.Receipt{
+			ID: "merge-receipt-2", StoreName: "Store", PurchaseDate: now, TotalAmount: 300,
+			Items: []entity.ReceiptItem{{ID: "merge-receipt-2-00000000", ReceiptID: "merge-receipt-2", Name: "卵", Quantity: 1, Price: 300}},
+		},
+		AddedItemIDs: []string{"merge-receipt-1-00000001"},
+		CreatedAt:    now,
+	}
+	if err := repo.Create(ctx, merge); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	merges, err := repo.FindByReceiptID(ctx, merge.ReceiptID)
+	if err != nil || len(merges) != 1 {
+		t.Fatalf("FindByReceiptID() = %d merges, error = %v, want 1", len(merges), err)
+	}
+	found, err := repo.FindByMergedReceiptID(ctx, merge.MergedReceiptID)
+	if err != nil {
+		t.Fatalf("FindByMergedReceiptID() error = %v", err)
+	}
+	if found.Before.TotalAmount != 200 || len(found.MergedReceipt.Items) != 1 || found.MergedReceipt.Items[0].Name != "卵" || len(found.AddedItemIDs) != 1 {
+		t.Errorf("FindByMergedReceiptID() = %+v", found)
+	}
+
+	if err := repo.DeleteByMergedReceiptID(ctx, merge.MergedReceiptID); err != nil {
+		t.Fatalf("DeleteByMergedReceiptID() error = %v", err)
+	}
+	if _, err := repo.FindByMergedReceiptID(ctx, merge.MergedReceiptID); err == nil {
+		t.Error("FindByMergedReceiptID() after delete: expected error")
+	}
+}
+
+func TestBunTrashRepository(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewBunTrashRepositoryWithDB(db)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	receipt := &entity.TrashEntry{
+		Kind: entity.TrashKindReceipt, ID: "trash-receipt-1",
+		Receipt: &entity.Receipt{
+			ID: "trash-receipt-1", StoreName: "Store", PurchaseDate: now, TotalAmount: 200,
+			Items: []entity.ReceiptItem{{ID: "trash-receipt-1-00000000", ReceiptID: "trash-receipt-1", Name: "牛乳", Quantity: 1, Price: 200}},
+		},
+		DeletedAt: now.Add(-time.Hour),
+		PurgeAt:   now.Add(-time.Minute),
+	}
+	expense := &entity.TrashEntry{
+		Kind: entity.TrashKindExpense, ID: "trash-expense-1",
+		Expense:   &entity.ExpenseEntry{ID: "trash-expense-1", Date: now, Category: "食費", Amount: 500, Tags: []string{}},
+		DeletedAt: now,
+		PurgeAt:   now.Add(24 * time.Hour),
+	}
+	for _, entry := range []*entity.TrashEntry{receipt, expense} {
+		if err := repo.Create(ctx, entry); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	entries, err := repo.FindAll(ctx, "", 10, 0)
+	if err != nil || len(entries) != 2 || entries[0].Kind != entity.TrashKindExpense || entries[0].Expense.Amount != 500 {
+		t.Fatalf("FindAll() = %+v, error = %v, want newest first", entries, err)
+	}
+	if receipts, err := repo.FindAll(ctx, entity.TrashKindReceipt, 10, 0); err != nil || len(receipts) != 1 || len(receipts[0].Receipt.Items) != 1 {
+		t.Errorf("FindAll(receipt) = %+v, error = %v", receipts, err)
+	}
+
+	expired, err := repo.FindExpired(ctx, now, 10)
+	if err != nil || len(expired) != 1 || expired[0].ID != receipt.ID {
+		t.Errorf("FindExpired() = %+v, error = %v, want receipt only", expired, err)
+	}
+
+	if err := repo.Delete(ctx, entity.TrashKindReceipt, receipt.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.FindByID(ctx, entity.TrashKindReceipt, receipt.ID); err == nil {
+		t.Error("FindByID() after delete: expected error")
+	}
+}
+
+func TestBunAmountRescaler(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if _, err := db.NewInsert().Model(&AmountSetting{ID: amountSettingID, Currency: "JPY", UpdatedAt: time.Now()}).Exec(ctx); err != nil {
+		t.Fatalf("Failed to insert amount setting: %v", err)
+	}
+
+	now := time.Now().Truncate(time.Second)
+	receipt := &entity.Receipt{
+		ID: "rescale-receipt-1", StoreName: "Store", PurchaseDate: now, TotalAmount: 1299, TaxAmount: 99, Tags: []string{},
+		Items: []entity.ReceiptItem{{ID: "rescale-receipt-1-00000000", ReceiptID: "rescale-receipt-1", Name: "Milk", Quantity: 1, Price: 1299}},
+	}
+	if err := NewBunReceiptRepositoryWithDB(db).Create(ctx, receipt); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := NewBunExpenseRepositoryWithDB(db).Create(ctx, &entity.ExpenseEntry{ID: "rescale-expense-1", Date: now, Category: "食費", Amount: 500, Tags: []string{}}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := NewBunReceiptRevisionRepositoryWithDB(db).Create(ctx, &entity.ReceiptRevision{ID: "rescale-revision-1", ReceiptID: receipt.ID, Revision: 1, Source: entity.RevisionSourceOriginal, Snapshot: *receipt, CreatedAt: now}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	trashRepo := NewBunTrashRepositoryWithDB(db)
+	if err := trashRepo.Create(ctx, &entity.TrashEntry{
+		Kind: entity.TrashKindExpense, ID: "rescale-expense-2",
+		Expense:   &entity.ExpenseEntry{ID: "rescale-expense-2", Date: now, Category: "食費", Amount: 300, Tags: []string{}},
+		DeletedAt: now, PurgeAt: now.Add(24 * time.Hour),
+	}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	rescaler := NewBunAmountRescalerWithDB(db)
+	usd, _ := sharedDomain.LookupCurrency("USD")
+
+	// 検出のみの場合は件数を数えて取り消す
+	report, err := rescaler.Rescale(ctx, usd, true)
+	if err != nil {
+		t.Fatalf("Rescale(dry run) error = %v", err)
+	}
+	if report.Factor != 100 || report.Receipts != 1 || report.Items != 1 || report.Expenses != 1 || report.Revisions != 1 || report.Trash != 1 {
+		t.Errorf("Rescale(dry run) = %+v", report)
+	}
+	if code, err := rescaler.StoredCurrency(ctx); err != nil || code != "JPY" {
+		t.Errorf("StoredCurrency() after dry run = %q, error = %v, want JPY", code, err)
+	}
+
+	if _, err := rescaler.Rescale(ctx, usd, false); err != nil {
+		t.Fatalf("Rescale() error = %v", err)
+	}
+	found, err := NewBunReceiptRepositoryWithDB(db).FindByID(ctx, receipt.ID)
+	if err != nil || found.TotalAmount != 129900 || found.TaxAmount != 9900 || found.Items[0].Price != 129900 {
+		t.Errorf("FindByID() after rescale = %+v, error = %v", found, err)
+	}
+	revisions, err := NewBunReceiptRevisionRepositoryWithDB(db).FindByReceiptID(ctx, receipt.ID)
+	if err != nil || len(revisions) != 1 || revisions[0].Snapshot.TotalAmount != 129900 {
+		t.Errorf("FindByReceiptID() after rescale = %+v, error = %v", revisions, err)
+	}
+	if entry, err := trashRepo.FindByID(ctx, entity.TrashKindExpense, "rescale-expense-2"); err != nil || entry.Expense.Amount != 30000 {
+		t.Errorf("FindByID() after rescale = %+v, error = %v", entry, err)
+	}
+
+	// 同じ通貨への換算は二重に適用しない
+	if _, err := rescaler.Rescale(ctx, usd, false); err == nil {
+		t.Error("Rescale() twice: expected error")
+	}
+	// 桁数が減る換算は端数が失われるため適用しない
+	if _, err := rescaler.Rescale(ctx, sharedDomain.DefaultCurrency, false); err == nil {
+		t.Error("Rescale() to fewer digits: expected error")
+	}
+}
+
+func TestBunAccountingSyncRepository(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	receiptRepo := NewBunReceiptRepositoryWithDB(db)
+	repo := NewBunAccountingSyncRepositoryWithDB(db)
+	ctx := context.Background()
+
+	updatedAt := time.Now().Truncate(time.Second)
+	for _, id := range []string{"test-sync-1", "test-sync-2", "test-sync-3"} {
+		receipt := &entity.Receipt{
+			ID: id, StoreName: "テストストア", PurchaseDate: updatedAt, TotalAmount: 100, UpdatedAt: updatedAt,
+			Items: []entity.ReceiptItem{{ID: id + "-1", Name: "商品", Quantity: 1, Price: 100}},
+		}
+		if id == "test-sync-3" {
+			receipt.NeedsReview = true
+		}
+		if err := receiptRepo.Create(ctx, receipt); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	// test-sync-1 は送信済み、test-sync-2 は未送信、test-sync-3 は要確認
+	sync := &entity.AccountingSync{
+		ReceiptID: "test-sync-1", Provider: entity.AccountingProviderFreee, Status: entity.SyncStatusSynced,
+		ExternalID: "123", RemoteVersion: "v1", ReceiptUpdatedAt: updatedAt, UpdatedAt: updatedAt,
+	}
+	if err := repo.Save(ctx, sync); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	ids, err := repo.FindPendingReceiptIDs(ctx, entity.AccountingProviderFreee, 3, 10)
+	if err != nil {
+		t.Fatalf("FindPendingReceiptIDs() error = %v", err)
+	}
+	if len(ids) != 1 || ids[0] != "test-sync-2" {
+		t.Errorf("FindPendingReceiptIDs() = %v, want [test-sync-2]", ids)
+	}
+
+	// 上書き保存で競合にすると送信対象から外れる
+	sync.Status = entity.SyncStatusConflict
+	sync.LastError = "conflict"
+	if err := repo.Save(ctx, sync); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	found, err := repo.FindByReceiptID(ctx, "test-sync-1")
+	if err != nil || len(found) != 1 || found[0].Status != entity.SyncStatusConflict || found[0].ExternalID != "123" {
+		t.Fatalf("FindByReceiptID() = %+v, %v", found, err)
+	}
+	conflicts, err := repo.FindByStatus(ctx, entity.AccountingProviderFreee, entity.SyncStatusConflict, 10, 0)
+	if err != nil || len(conflicts) != 1 {
+		t.Errorf("FindByStatus() = %d, %v, want 1", len(conflicts), err)
+	}
+
+	// 同期状態のないレシートは含めない
+	batch, err := repo.FindByReceiptIDs(ctx, []string{"test-sync-1", "test-sync-2"})
+	if err != nil || len(batch) != 1 || batch[0].ReceiptID != "test-sync-1" {
+		t.Errorf("FindByReceiptIDs() = %+v, %v", batch, err)
+	}
+}
+
+func TestBunAggregateRepository(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	receiptRepo := NewBunReceiptRepositoryWithDB(db)
+	expenseRepo := NewBunExpenseRepositoryWithDB(db)
+	repo := NewBunAggregateRepositoryWithDB(db)
+	ctx := context.Background()
+
+	at := func(d, hour, minute int) time.Time {
+		return time.Date(2025, 6, d, hour, minute, 0, 0, time.Local)
+	}
+	receipts := []*entity.Receipt{
+		{ID: "test-agg-1", StoreName: "テストストア", PurchaseDate: at(1, 10, 5), TotalAmount: 500, Items: []entity.ReceiptItem{
+			{ID: "test-agg-1-1", Name: "りんご", Quantity: 2, Price: 150, Category: "食費"},
+			{ID: "test-agg-1-2", Name: "洗剤", Quantity: 1, Price: 200},
+		}},
+		// 同じ時間帯（10:00〜10:15）のレシートは1行にまとめる
+		{ID: "test-agg-2", StoreName: "テストストア", PurchaseDate: at(1, 10, 14), TotalAmount: 100, Items: []entity.ReceiptItem{
+			{ID: "test-agg-2-1", Name: "パン", Quantity: 1, Price: 100, Category: "食費"},
+		}},
+		{ID: "test-agg-3", StoreName: "テストストア", PurchaseDate: at(1, 10, 15), TotalAmount: 80, Items: []entity.ReceiptItem{
+			{ID: "test-agg-3-1", Name: "牛乳", Quantity: 1, Price: 80, Category: "食費"},
+		}},
+		// 期間外
+		{ID: "test-agg-4", StoreName: "テストストア", PurchaseDate: at(3, 9, 0), TotalAmount: 999, Items: []entity.ReceiptItem{
+			{ID: "test-agg-4-1", Name: "本", Quantity: 1, Price: 999, Category: "教養"},
+		}},
+	}
+	for _, receipt := range receipts {
+		if err := receiptRepo.Create(ctx, receipt); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	for _, expense := range []*entity.ExpenseEntry{
+		{ID: "test-agg-e1", Date: at(1, 23, 59), Category: "交通費", Amount: 220},
+		{ID: "test-agg-e2", Date: at(1, 12, 0), Category: "", Amount: 1000},
+	} {
+		if err := expenseRepo.Create(ctx, expense); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	start, end := at(1, 0, 0), at(2, 0, 0).Add(-time.Nanosecond)
+	items, err := repo.SumItemsByCategory(ctx, start, end)
+	if err != nil {
+		t.Fatalf("SumItemsByCategory() error = %v", err)
+	}
+	want := map[string]entity.CategoryAmount{
+		"10:00/食費": {Count: 2, Total: 400},
+		"10:00/":   {Count: 1, Total: 200},
+		"10:15/食費": {Count: 1, Total: 80},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("SumItemsByCategory() = %d rows, want %d", len(items), len(want))
+	}
+	for _, item := range items {
+		w, ok := want[item.Time.In(time.Local).Format("15:04")+"/"+item.Category]
+		if !ok || item.Count != w.Count || item.Total != w.Total {
+			t.Errorf("SumItemsByCategory() row = %+v", item)
+		}
+	}
+
+	expenses, err := repo.SumExpensesByCategory(ctx, start, end)
+	if err != nil {
+		t.Fatalf("SumExpensesByCategory() error = %v", err)
+	}
+	// カテゴリーのない家計簿エントリは集計しない
+	if len(expenses) != 1 || expenses[0].Category != "交通費" || expenses[0].Total != 220 || !expenses[0].Time.Equal(at(1, 23, 45)) {
+		t.Errorf("SumExpensesByCategory() = %+v", expenses)
+	}
+
+	sums, err := repo.SumReceipts(ctx, start, end)
+	if err != nil {
+		t.Fatalf("SumReceipts() error = %v", err)
+	}
+	if len(sums) != 2 || sums[0].Count != 2 || sums[0].Total != 600 || !sums[0].Time.Equal(at(1, 10, 0)) || sums[1].Count != 1 || sums[1].Total != 80 {
+		t.Errorf("SumReceipts() = %+v", sums)
+	}
+
+	// 時間帯に分けずに明細・家計簿エントリをカテゴリーごとに合計する
+	breakdowns, err := repo.SumByCategory(ctx, start, end)
+	if err != nil {
+		t.Fatalf("SumByCategory() error = %v", err)
+	}
+	wantBreakdowns := map[string]entity.CategoryBreakdown{
+		"食費":  {ReceiptCount: 3, ItemCount: 3, ItemTotal: 480},
+		"":    {ReceiptCount: 1, ItemCount: 1, ItemTotal: 200},
+		"交通費": {ExpenseCount: 1, ExpenseTotal: 220},
+	}
+	if len(breakdowns) != len(wantBreakdowns) {
+		t.Fatalf("SumByCategory() = %d rows, want %d", len(breakdowns), len(wantBreakdowns))
+	}
+	for _, breakdown := range breakdowns {
+		w, ok := wantBreakdowns[breakdown.Category]
+		w.Category = breakdown.Category
+		if !ok || *breakdown != w {
+			t.Errorf("SumByCategory() row = %+v", breakdown)
+		}
+	}
+}
+
+// BenchmarkReportAggregation 10万件の明細の集計（レシートを読み込んでメモリで集計する場合とデータベースで集計する場合）
+func BenchmarkReportAggregation(b *testing.B) {
+	db, cleanup := setupTestDB(b)
+	defer cleanup()
+
+	ctx := context.Background()
+	const (
+		receiptCount    = 20000
+		itemsPerReceipt = 5
+		batchSize       = 1000
+	)
+	categories := []string{"食費", "日用品", "交通費", "教養", "医療費"}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)
+	for i := 0; i < receiptCount; i += batchSize {
+		receipts := make([]Receipt, 0, batchSize)
+		items := make([]ReceiptItem, 0, batchSize*itemsPerReceipt)
+		for j := i; j < i+batchSize; j++ {
+			id := fmt.Sprintf("bench-%06d", j)
+			receipts = append(receipts, Receipt{ID: id, StoreName: "テストストア", PurchaseDate: start.Add(time.Duration(j) * 23 * time.Minute), TotalAmount: 500})
+			for k := range itemsPerReceipt {
+				category := categories[(j+k)%len(categories)]
+				items = append(items, ReceiptItem{ID: fmt.Sprintf("%s-%d", id, k), ReceiptID: id, Name: "商品", Quantity: 1, Price: 100, Category: &category})
+			}
+		}
+		if _, err := db.NewInsert().Model(&receipts).Exec(ctx); err != nil {
+			b.Fatalf("Failed to insert receipts: %v", err)
+		}
+		if _, err := db.NewInsert().Model(&items).Exec(ctx); err != nil {
+			b.Fatalf("Failed to insert receipt items: %v", err)
+		}
+	}
+	end := start.AddDate(1, 0, 0).Add(-time.Nanosecond)
+
+	b.Run("FindByDateRange", func(b *testing.B) {
+		repo := NewBunReceiptRepositoryWithDB(db)
+		b.ReportAllocs()
+		for b.Loop() {
+			receipts, err := repo.FindByDateRange(ctx, start, end)
+			if err != nil {
+				b.Fatalf("FindByDateRange() error = %v", err)
+			}
+			totals := make(map[string]int64)
+			for _, receipt := range receipts {
+				for _, item := range receipt.Items {
+					totals[item.Category] += int64(item.Price) * int64(item.Quantity)
+				}
+			}
+		}
+	})
+
+	b.Run("SumItemsByCategory", func(b *testing.B) {
+		repo := NewBunAggregateRepositoryWithDB(db)
+		b.ReportAllocs()
+		for b.Loop() {
+			amounts, err := repo.SumItemsByCategory(ctx, start, end)
+			if err != nil {
+				b.Fatalf("SumItemsByCategory() error = %v", err)
+			}
+			totals := make(map[string]int64)
+			for _, amount := range amounts {
+				totals[amount.Category] += amount.Total
+			}
+		}
+	})
+}
+
 // TestBunReceiptRepository_Close Closeのテスト
+func TestBunItemAliasRepository(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewBunItemAliasRepositoryWithDB(db)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	alias := &entity.ItemAlias{Alias: "ｷｬﾍﾞﾂ1/2", CanonicalName: "キャベツ 1/2玉", CreatedAt: now, UpdatedAt: now}
+	if err := repo.Save(ctx, alias); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// 全角・半角の違う同じ別名は上書きになる
+	overwrite := &entity.ItemAlias{Alias: "キャベツ1/2", CanonicalName: "キャベツ（1/2玉）", CreatedAt: now, UpdatedAt: now}
+	if err := repo.Save(ctx, overwrite); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	aliases, err := repo.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("FindAll() error = %v", err)
+	}
+	if len(aliases) != 1 || aliases[0].Alias != "キャベツ1/2" || aliases[0].CanonicalName != "キャベツ（1/2玉）" {
+		t.Fatalf("FindAll() = %+v, want overwritten alias", aliases)
+	}
+
+	if err := repo.Delete(ctx, "ｷｬﾍﾞﾂ1/2"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := repo.Delete(ctx, "ｷｬﾍﾞﾂ1/2"); !errors.Is(err, entity.ErrItemAliasNotFound) {
+		t.Errorf("Delete() error = %v, want ErrItemAliasNotFound", err)
+	}
+}
+
+func TestBunExpenseReportRepository(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewBunExpenseReportRepositoryWithDB(db)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	report, err := expensereport.NewExpenseReport("report-1", "3月 出張", "山田", []string{"receipt-2", "receipt-1"}, now)
+	if err != nil {
+		t.Fatalf("NewExpenseReport() error = %v", err)
+	}
+	if err := repo.Create(ctx, report); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	other, _ := expensereport.NewExpenseReport("report-2", "3月 雑費", "山田", []string{"receipt-3"}, now.Add(-time.Hour))
+	if err := repo.Create(ctx, other); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// レシートは報告書内の順番で読み込む
+	found, err := repo.FindByID(ctx, "report-1")
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if len(found.ReceiptIDs) != 2 || found.ReceiptIDs[0] != "receipt-2" || found.ReceiptIDs[1] != "receipt-1" {
+		t.Errorf("FindByID().ReceiptIDs = %v, want [receipt-2 receipt-1]", found.ReceiptIDs)
+	}
+
+	// 更新でレシートの紐付けを置き換える
+	if err := found.Edit(found.Title, found.Applicant, []string{"receipt-1"}, now); err != nil {
+		t.Fatalf("Edit() error = %v", err)
+	}
+	if err := found.Submit(3200, now.Add(time.Hour)); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if err := repo.Update(ctx, found); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	submitted, err := repo.FindAll(ctx, expensereport.StatusSubmitted, 10, 0)
+	if err != nil {
+		t.Fatalf("FindAll() error = %v", err)
+	}
+	if len(submitted) != 1 || submitted[0].ID != "report-1" || submitted[0].TotalAmount != 3200 || len(submitted[0].ReceiptIDs) != 1 {
+		t.Errorf("FindAll(submitted) = %+v", submitted)
+	}
+	all, err := repo.FindAll(ctx, "", 10, 0)
+	if err != nil {
+		t.Fatalf("FindAll() error = %v", err)
+	}
+	if len(all) != 2 || all[0].ID != "report-1" {
+		t.Errorf("FindAll() = %+v, want newest first", all)
+	}
+
+	// 外したレシートは他の報告書の検索に含まれない
+	byReceipts, err := repo.FindByReceiptIDs(ctx, []string{"receipt-2", "receipt-3"})
+	if err != nil {
+		t.Fatalf("FindByReceiptIDs() error = %v", err)
+	}
+	if len(byReceipts) != 1 || byReceipts[0].ID != "report-2" {
+		t.Errorf("FindByReceiptIDs() = %+v, want [report-2]", byReceipts)
+	}
+
+	if err := repo.Delete(ctx, "report-2"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.FindByID(ctx, "report-2"); err == nil {
+		t.Error("FindByID() after Delete() should return error")
+	}
+}
+
+func TestBunCollectionRepository(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewBunCollectionRepositoryWithDB(db)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	taxes, _ := entity.NewCollection("collection-1", "2025年 確定申告", "", now)
+	if _, err := taxes.AddReceipts([]string{"receipt-2", "receipt-1"}, now); err != nil {
+		t.Fatalf("AddReceipts() error = %v", err)
+	}
+	if err := repo.Create(ctx, taxes); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	kitchen, _ := entity.NewCollection("collection-2", "キッチンのリフォーム", "", now)
+	if _, err := kitchen.AddReceipts([]string{"receipt-1"}, now); err != nil {
+		t.Fatalf("AddReceipts() error = %v", err)
+	}
+	if err := repo.Create(ctx, kitchen); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// レシートは追加した順で読み込む
+	found, err := repo.FindByID(ctx, "collection-1")
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if len(found.ReceiptIDs) != 2 || found.ReceiptIDs[0] != "receipt-2" || found.ReceiptIDs[1] != "receipt-1" {
+		t.Errorf("FindByID().ReceiptIDs = %v, want [receipt-2 receipt-1]", found.ReceiptIDs)
+	}
+
+	// 更新でレシートの紐付けを置き換える
+	found.RemoveReceipt("receipt-1", now)
+	if err := repo.Update(ctx, found); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	byReceipt, err := repo.FindByReceiptID(ctx, "receipt-1")
+	if err != nil {
+		t.Fatalf("FindByReceiptID() error = %v", err)
+	}
+	if len(byReceipt) != 1 || byReceipt[0].ID != "collection-2" {
+		t.Errorf("FindByReceiptID() = %+v, want [collection-2]", byReceipt)
+	}
+
+	all, err := repo.FindAll(ctx, 10, 0)
+	if err != nil {
+		t.Fatalf("FindAll() error = %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("FindAll() = %+v, want 2 collections", all)
+	}
+
+	if err := repo.Delete(ctx, "collection-2"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.FindByID(ctx, "collection-2"); err == nil {
+		t.Error("FindByID() after Delete() should return error")
+	}
+	if byReceipt, _ := repo.FindByReceiptID(ctx, "receipt-1"); len(byReceipt) != 0 {
+		t.Errorf("FindByReceiptID() after Delete() = %+v, want none", byReceipt)
+	}
+}
+
 func TestBunReceiptRepository_Close(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -701,3 +2088,86 @@ func TestBunCategoryRepository_Close(t *testing.T) {
 		t.Errorf("Close() error = %v", err)
 	}
 }
+
+// TestBatchIDs まとめて取得するIDの分割のテスト
+func TestBatchIDs(t *testing.T) {
+	many := make([]string, maxBatchIDs+1)
+	for i := range many {
+		many[i] = fmt.Sprintf("id-%d", i)
+	}
+
+	tests := []struct {
+		name  string
+		ids   []string
+		sizes []int
+	}{
+		{name: "正常系: 重複と空文字を除く", ids: []string{"a", "", "b", "a"}, sizes: []int{2}},
+		{name: "正常系: 上限ごとに分割", ids: many, sizes: []int{maxBatchIDs, 1}},
+		{name: "正常系: 空の場合は分割しない", ids: nil, sizes: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batches := batchIDs(tt.ids)
+			if len(batches) != len(tt.sizes) {
+				t.Fatalf("batchIDs() = %d batches, want %d", len(batches), len(tt.sizes))
+			}
+			for i, batch := range batches {
+				if len(batch) != tt.sizes[i] {
+					t.Errorf("batches[%d] = %d ids, want %d", i, len(batch), tt.sizes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBunSpendContributionRepository(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewBunSpendContributionRepositoryWithDB(db)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	contributions := []*analytics.SpendContribution{
+		{ContributorID: "household-0000001", Region: "東京都", Month: "2024-03", Amount: 40000, Currency: "JPY", UpdatedAt: now},
+		{ContributorID: "household-0000002", Region: "東京都", Month: "2024-03", Amount: 50000, Currency: "JPY", UpdatedAt: now},
+		{ContributorID: "household-0000003", Region: "大阪府", Month: "2024-03", Amount: 30000, Currency: "JPY", UpdatedAt: now},
+		{ContributorID: "household-0000001", Region: "東京都", Month: "2024-04", Amount: 45000, Currency: "JPY", UpdatedAt: now},
+		{ContributorID: "household-0000001", Region: "東京都", Month: "2024-05", Amount: 45000, Currency: "JPY", UpdatedAt: now},
+	}
+	for _, c := range contributions {
+		if err := repo.Save(ctx, c); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	// 同じ世帯・月の集計値は上書きになる
+	overwrite := &analytics.SpendContribution{ContributorID: "household-0000002", Region: "東京都", Month: "2024-03", Amount: 60000, Currency: "JPY", UpdatedAt: now}
+	if err := repo.Save(ctx, overwrite); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	groups, err := repo.Aggregate(ctx, "2024-03", "2024-04", "")
+	if err != nil {
+		t.Fatalf("Aggregate() error = %v", err)
+	}
+	if len(groups) != 3 {
+		t.Fatalf("Aggregate() returned %d groups, want 3: %+v", len(groups), groups)
+	}
+	tokyo := groups[1]
+	if groups[0].Region != "大阪府" || tokyo.Region != "東京都" || tokyo.Month != "2024-03" || tokyo.Contributors != 2 || tokyo.Total != 100000 {
+		t.Errorf("Aggregate() = %+v, %+v, want 大阪府 then 東京都 with 2 contributors and 100000", groups[0], tokyo)
+	}
+	if groups[2].Month != "2024-04" {
+		t.Errorf("Aggregate()[2].Month = %s, want 2024-04", groups[2].Month)
+	}
+
+	groups, err = repo.Aggregate(ctx, "2024-03", "2024-05", "大阪府")
+	if err != nil {
+		t.Fatalf("Aggregate() error = %v", err)
+	}
+	if len(groups) != 1 || groups[0].Contributors != 1 || groups[0].Total != 30000 {
+		t.Errorf("Aggregate(大阪府) = %+v, want one group with 30000", groups)
+	}
+}
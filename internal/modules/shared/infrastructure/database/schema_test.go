@@ -0,0 +1,148 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// schemaDir スキーマ・マイグレーションのSQLのディレクトリ
+var schemaDir = filepath.Join("..", "..", "..", "..", "..", "scripts")
+
+var (
+	createTablePattern = regexp.MustCompile(`(?s)CREATE TABLE IF NOT EXISTS (\w+) \((.*?)\n\) ENGINE`)
+	indexPattern       = regexp.MustCompile(`(?:PRIMARY KEY|UNIQUE KEY \w+|INDEX \w+) \(([^)]+)\)`)
+	columnPKPattern    = regexp.MustCompile(`(?m)^\s*(\w+) [^,\n]*PRIMARY KEY`)
+)
+
+// schemaIndexes init.sqlのテーブルごとのインデックス（列の並び）を取得
+func schemaIndexes(t *testing.T) map[string][][]string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(schemaDir, "init.sql"))
+	if err != nil {
+		t.Fatalf("Failed to read init.sql: %v", err)
+	}
+
+	indexes := make(map[string][][]string)
+	for _, table := range createTablePattern.FindAllStringSubmatch(string(data), -1) {
+		name, body := table[1], table[2]
+		for _, pk := range columnPKPattern.FindAllStringSubmatch(body, -1) {
+			indexes[name] = append(indexes[name], []string{pk[1]})
+		}
+		for _, index := range indexPattern.FindAllStringSubmatch(body, -1) {
+			var columns []string
+			for _, column := range strings.Split(index[1], ",") {
+				columns = append(columns, strings.TrimSpace(column))
+			}
+			indexes[name] = append(indexes[name], columns)
+		}
+	}
+	return indexes
+}
+
+// TestSchemaIndexes リポジトリの検索条件・並び順に使う列のインデックスがスキーマにあるかチェック
+// 検索を追加した場合はここに追加し、init.sqlとマイグレーションでインデックスを作成する
+func TestSchemaIndexes(t *testing.T) {
+	indexes := schemaIndexes(t)
+
+	tests := []struct {
+		name    string
+		table   string
+		columns []string // インデックスの先頭の列
+	}{
+		{name: "レシートの購入日（期間の検索・一覧の並び順・集計）", table: "receipts", columns: []string{"purchase_date"}},
+		{name: "レシートのカテゴリー", table: "receipts", columns: []string{"category"}},
+		{name: "レシートの店名", table: "receipts", columns: []string{"store_name"}},
+		{name: "レシートの要確認フラグ", table: "receipts", columns: []string{"needs_review"}},
+		{name: "レシートの画像ハッシュ（重複登録の検出）", table: "receipts", columns: []string{"image_hash"}},
+		{name: "明細のレシート（レシートの明細の読み込み・集計の結合）", table: "receipt_items", columns: []string{"receipt_id"}},
+		{name: "明細のレシート内の順番（再処理の上書き）", table: "receipt_items", columns: []string{"receipt_id", "position"}},
+		{name: "明細のカテゴリー", table: "receipt_items", columns: []string{"category"}},
+		{name: "明細の正規化した商品名（価格推移）", table: "receipt_items", columns: []string{"normalized_name"}},
+		{name: "家計簿エントリの日付とカテゴリー（期間の検索・集計）", table: "expense_entries", columns: []string{"date", "category"}},
+		{name: "家計簿エントリのカテゴリーと日付（カテゴリーの検索の並び順）", table: "expense_entries", columns: []string{"category", "date"}},
+		{name: "変更履歴のレシートとリビジョン", table: "receipt_revisions", columns: []string{"receipt_id", "revision"}},
+		{name: "統合の記録の残したレシートと統合日時", table: "receipt_merges", columns: []string{"receipt_id", "created_at"}},
+		{name: "統合の記録の削除したレシート", table: "receipt_merges", columns: []string{"merged_receipt_id"}},
+		{name: "ゴミ箱の削除日時（一覧の並び順）", table: "trash_entries", columns: []string{"deleted_at"}},
+		{name: "ゴミ箱の種類と削除日時（種類の絞り込み）", table: "trash_entries", columns: []string{"kind", "deleted_at"}},
+		{name: "ゴミ箱の完全に削除する日時", table: "trash_entries", columns: []string{"purge_at"}},
+		{name: "割り勘の精算状態と作成日時", table: "receipt_splits", columns: []string{"settled", "created_at"}},
+		{name: "会計サービスとの同期状態", table: "accounting_syncs", columns: []string{"provider", "status", "updated_at"}},
+		{name: "経費報告書の状態と更新日時（状態の絞り込み）", table: "expense_reports", columns: []string{"status", "updated_at"}},
+		{name: "経費報告書の更新日時（一覧の並び順）", table: "expense_reports", columns: []string{"updated_at"}},
+		{name: "経費報告書のレシート（別の報告書に含まれるレシートの検出）", table: "expense_report_receipts", columns: []string{"receipt_id"}},
+		{name: "公開統計の集計値の月と地域（期間・地域の集計）", table: "spend_contributions", columns: []string{"month", "region"}},
+		{name: "コレクション名（一覧の並び順）", table: "collections", columns: []string{"name"}},
+		{name: "コレクションのレシート（レシートを含むコレクションの検索）", table: "collection_receipts", columns: []string{"receipt_id"}},
+		{name: "カテゴリー名", table: "categories", columns: []string{"name"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tableIndexes, ok := indexes[tt.table]
+			if !ok {
+				t.Fatalf("table %s not found in init.sql", tt.table)
+			}
+			found := slices.ContainsFunc(tableIndexes, func(columns []string) bool {
+				return len(columns) >= len(tt.columns) && slices.Equal(columns[:len(tt.columns)], tt.columns)
+			})
+			if !found {
+				t.Errorf("no index on %s(%s), indexes = %v", tt.table, strings.Join(tt.columns, ", "), tableIndexes)
+			}
+		})
+	}
+}
+
+var (
+	checkConstraintPattern = regexp.MustCompile(`CONSTRAINT (\w+) CHECK`)
+	dropCheckPattern       = regexp.MustCompile(`DROP CHECK (\w+)`)
+)
+
+// TestSchemaCheckConstraints init.sqlのCHECK制約がマイグレーションでも作成されているかチェック
+// 後のマイグレーションで削除した制約は除く
+func TestSchemaCheckConstraints(t *testing.T) {
+	constraintNames := func(data []byte) []string {
+		var names []string
+		for _, match := range checkConstraintPattern.FindAllSubmatch(data, -1) {
+			names = append(names, string(match[1]))
+		}
+		return names
+	}
+
+	initSQL, err := os.ReadFile(filepath.Join(schemaDir, "init.sql"))
+	if err != nil {
+		t.Fatalf("Failed to read init.sql: %v", err)
+	}
+	want := constraintNames(initSQL)
+	if len(want) == 0 {
+		t.Fatal("no check constraints in init.sql")
+	}
+
+	migrations, err := filepath.Glob(filepath.Join(schemaDir, "migrations", "*.sql"))
+	if err != nil {
+		t.Fatalf("Failed to list migrations: %v", err)
+	}
+	var got []string
+	for _, path := range migrations {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		got = append(got, constraintNames(data)...)
+		for _, match := range dropCheckPattern.FindAllSubmatch(data, -1) {
+			if i := slices.Index(got, string(match[1])); i >= 0 {
+				got = slices.Delete(got, i, i+1)
+			}
+		}
+	}
+
+	slices.Sort(want)
+	slices.Sort(got)
+	if !slices.Equal(got, want) {
+		t.Errorf("check constraints in migrations = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,79 @@
+package egress
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// Options 外部（AI・Webhook・画像のURLなど）へのHTTP接続の設定
+type Options struct {
+	ProxyURL string // 経由するプロキシ（空の場合は環境変数 HTTPS_PROXY / HTTP_PROXY / NO_PROXY に従う）
+	NoProxy  string // プロキシを経由しないホスト（NO_PROXYと同じ形式のカンマ区切り、ProxyURLを指定した場合のみ）
+	CAFile   string // システムの証明書に加えて信頼するルート証明書（PEM、複数の証明書を連結してもよい）
+}
+
+// Egress 外部へのHTTP接続で共通のプロキシ・ルート証明書
+// TLSを検査するプロキシを経由する環境では、プロキシの発行する証明書のルート証明書をCAFileに指定する
+type Egress struct {
+	proxy   func(*url.URL) (*url.URL, error)
+	rootCAs *x509.CertPool // nilの場合はシステムの証明書のみ
+}
+
+// New 新しいEgressを作成（プロキシのURL・証明書が不正な場合はエラー）
+func New(options Options) (*Egress, error) {
+	proxyConfig := httpproxy.FromEnvironment()
+	if options.ProxyURL != "" {
+		if _, err := url.Parse(options.ProxyURL); err != nil {
+			return nil, fmt.Errorf("invalid proxy url: %w", err)
+		}
+		proxyConfig = &httpproxy.Config{
+			HTTPProxy:  options.ProxyURL,
+			HTTPSProxy: options.ProxyURL,
+			NoProxy:    options.NoProxy,
+		}
+	}
+	e := &Egress{proxy: proxyConfig.ProxyFunc()}
+
+	if options.CAFile != "" {
+		pem, err := os.ReadFile(options.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in ca file %s", options.CAFile)
+		}
+		e.rootCAs = pool
+	}
+	return e, nil
+}
+
+// Proxy リクエストが経由するプロキシ（nilの場合は直接接続する、http.Transport.Proxy に指定する）
+func (e *Egress) Proxy(req *http.Request) (*url.URL, error) {
+	return e.proxy(req.URL)
+}
+
+// TLSConfig 接続先の証明書の検証に追加のルート証明書を使うTLSの設定
+func (e *Egress) TLSConfig() *tls.Config {
+	return &tls.Config{
+		RootCAs:    e.rootCAs,
+		MinVersion: tls.VersionTLS12,
+	}
+}
+
+// Transport プロキシ・ルート証明書を設定したTransport（その他はhttp.DefaultTransportと同じ）
+func (e *Egress) Transport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = e.Proxy
+	transport.TLSClientConfig = e.TLSConfig()
+	return transport
+}
@@ -0,0 +1,91 @@
+package egress
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEgress_Proxy(t *testing.T) {
+	e, err := New(Options{ProxyURL: "http://proxy.internal:3128", NoProxy: "internal.example.com"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{name: "正常系: httpsはプロキシを経由", url: "https://api.anthropic.com/v1/messages", want: "http://proxy.internal:3128"},
+		{name: "正常系: httpもプロキシを経由", url: "http://hooks.example.com/notify", want: "http://proxy.internal:3128"},
+		{name: "正常系: NoProxyのホストは直接接続", url: "https://api.internal.example.com/scan"},
+		{name: "正常系: ループバックは直接接続", url: "http://127.0.0.1:3310/scan"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+			got, err := e.Proxy(req)
+			if err != nil {
+				t.Fatalf("Proxy() error = %v", err)
+			}
+			if (got == nil && tt.want != "") || (got != nil && got.String() != tt.want) {
+				t.Errorf("Proxy() = %v, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEgress_CAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, data, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	get := func(options Options) error {
+		e, err := New(options)
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		resp, err := (&http.Client{Transport: e.Transport()}).Get(server.URL)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	// 追加のルート証明書がない場合はテストサーバーの証明書を検証できない
+	if err := get(Options{}); err == nil {
+		t.Error("Get() without ca file error = nil, want certificate error")
+	}
+	if err := get(Options{CAFile: caFile}); err != nil {
+		t.Errorf("Get() with ca file error = %v", err)
+	}
+}
+
+func TestNew_InvalidCAFile(t *testing.T) {
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if _, err := New(Options{CAFile: caFile}); err == nil {
+		t.Error("New() with invalid pem error = nil, want error")
+	}
+	if _, err := New(Options{CAFile: filepath.Join(t.TempDir(), "missing.pem")}); err == nil {
+		t.Error("New() with missing file error = nil, want error")
+	}
+}
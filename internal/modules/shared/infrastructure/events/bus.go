@@ -0,0 +1,79 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+// AllEvents すべての種類のイベントを購読する場合の種類
+const AllEvents = "*"
+
+// Handler ドメインイベントの購読者
+type Handler func(ctx context.Context, event domain.DomainEvent) error
+
+// Bus ドメインイベントを購読者へ同期的に配信する（domain.EventPublisherの実装）
+// Webhook・通知・監査ログなどの連携は購読者として登録し、ユースケースからは個別に呼び出さない
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+// NewBus 新しいBusを作成
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[string][]Handler)}
+}
+
+// Subscribe イベントの種類に購読者を登録（AllEventsはすべての種類）
+func (b *Bus) Subscribe(eventName string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventName] = append(b.handlers[eventName], handler)
+}
+
+// Publish イベントを記録した順に購読者へ配信する
+// 変更は保存済みのため、購読者のエラーはログに出力して残りの購読者への配信を続ける
+func (b *Bus) Publish(ctx context.Context, events ...domain.DomainEvent) {
+	for _, event := range events {
+		for _, handler := range b.handlersFor(event.EventName()) {
+			if err := handler(ctx, event); err != nil {
+				slog.WarnContext(ctx, "Domain event handler failed", "event", event.EventName(), "error", err)
+			}
+		}
+	}
+}
+
+// handlersFor イベントの種類の購読者とすべての種類の購読者を取得
+func (b *Bus) handlersFor(eventName string) []Handler {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return slices.Concat(b.handlers[eventName], b.handlers[AllEvents])
+}
+
+// AuditLog イベントを監査ログに出力する購読者（リクエストによる変更は送信元のIPアドレスも出力する）
+func AuditLog() Handler {
+	return func(ctx context.Context, event domain.DomainEvent) error {
+		slog.InfoContext(ctx, "Domain event",
+			"event", event.EventName(),
+			"occurred_at", event.OccurredAt(),
+			"client_ip", domain.ClientIPFromContext(ctx),
+			"data", event,
+		)
+		return nil
+	}
+}
+
+// Notify イベントを通知として送信する購読者（通知のdataはイベントのJSON）
+func Notify(notifier domain.Notifier) Handler {
+	return func(ctx context.Context, event domain.DomainEvent) error {
+		return notifier.Notify(ctx, domain.Notification{
+			Event:     event.EventName(),
+			Message:   event.EventName(),
+			Data:      event,
+			CreatedAt: event.OccurredAt(),
+		})
+	}
+}
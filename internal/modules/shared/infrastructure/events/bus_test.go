@@ -0,0 +1,62 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+type testEvent struct {
+	name string
+}
+
+func (e testEvent) EventName() string     { return e.name }
+func (e testEvent) OccurredAt() time.Time { return time.Time{} }
+
+type recordingNotifier struct {
+	notifications []domain.Notification
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification domain.Notification) error {
+	n.notifications = append(n.notifications, notification)
+	return nil
+}
+
+func TestBus_Publish(t *testing.T) {
+	bus := NewBus()
+
+	var created, all []string
+	bus.Subscribe("receipt.created", func(ctx context.Context, event domain.DomainEvent) error {
+		created = append(created, event.EventName())
+		return errors.New("handler failed")
+	})
+	bus.Subscribe(AllEvents, func(ctx context.Context, event domain.DomainEvent) error {
+		all = append(all, event.EventName())
+		return nil
+	})
+
+	bus.Publish(context.Background(), testEvent{"receipt.created"}, testEvent{"receipt.total_corrected"})
+
+	if len(created) != 1 {
+		t.Errorf("created handler calls = %v, want 1", created)
+	}
+	// 先の購読者が失敗しても残りの購読者に配信する
+	if len(all) != 2 || all[0] != "receipt.created" || all[1] != "receipt.total_corrected" {
+		t.Errorf("all handler calls = %v, want both events in order", all)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	notifier := &recordingNotifier{}
+	bus := NewBus()
+	bus.Subscribe("receipt.created", Notify(notifier))
+
+	bus.Publish(context.Background(), testEvent{"receipt.created"}, testEvent{"receipt.item_categorized"})
+
+	if len(notifier.notifications) != 1 || notifier.notifications[0].Event != "receipt.created" {
+		t.Errorf("notifications = %+v, want only receipt.created", notifier.notifications)
+	}
+}
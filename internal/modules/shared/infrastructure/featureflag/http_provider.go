@@ -0,0 +1,56 @@
+package featureflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// HTTPProvider HTTPで機能フラグを取得するリモートプロバイダー
+// URLは {"機能名": true, ...} 形式のJSONを返す
+type HTTPProvider struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPProvider 新しいHTTPProviderを作成
+func NewHTTPProvider(url string) *HTTPProvider {
+	return &HTTPProvider{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// SetTransport 外部への接続に使うTransportを設定（プロキシ・追加のルート証明書など、未設定の場合はhttp.DefaultTransport）
+func (p *HTTPProvider) SetTransport(transport http.RoundTripper) {
+	p.client.Transport = transport
+}
+
+// Fetch 機能フラグを取得
+func (p *HTTPProvider) Fetch(ctx context.Context) (map[string]bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create feature flag request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch feature flags: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("feature flag provider returned status %d", resp.StatusCode)
+	}
+
+	var flags map[string]bool
+	if err := json.NewDecoder(resp.Body).Decode(&flags); err != nil {
+		return nil, fmt.Errorf("failed to decode feature flags: %w", err)
+	}
+	return flags, nil
+}
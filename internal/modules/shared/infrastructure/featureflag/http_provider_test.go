@@ -0,0 +1,63 @@
+package featureflag
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPProvider_Fetch(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    map[string]bool
+		wantErr bool
+	}{
+		{
+			name:   "正常系: 機能フラグを取得",
+			status: http.StatusOK,
+			body:   `{"direct_upload": true, "batch_upload": false}`,
+			want:   map[string]bool{"direct_upload": true, "batch_upload": false},
+		},
+		{
+			name:    "異常系: プロバイダーのエラー",
+			status:  http.StatusInternalServerError,
+			body:    `error`,
+			wantErr: true,
+		},
+		{
+			name:    "異常系: 不正なJSON",
+			status:  http.StatusOK,
+			body:    `{"direct_upload": "yes"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			got, err := NewHTTPProvider(server.URL).Fetch(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Fetch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Fetch() = %v, want %v", got, tt.want)
+			}
+			for name, enabled := range tt.want {
+				if got[name] != enabled {
+					t.Errorf("Fetch()[%s] = %v, want %v", name, got[name], enabled)
+				}
+			}
+		})
+	}
+}
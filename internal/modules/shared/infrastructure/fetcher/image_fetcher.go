@@ -0,0 +1,259 @@
+package fetcher
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+const (
+	// DefaultTimeout 画像の取得のデフォルトのタイムアウト
+	DefaultTimeout = 10 * time.Second
+	// DefaultMaxSize 取得する画像のデフォルトの最大サイズ
+	DefaultMaxSize = 10 << 20
+	// DefaultMaxRedirects リダイレクトを追跡するデフォルトの最大回数
+	DefaultMaxRedirects = 3
+)
+
+// sharedAddressSpace キャリアグレードNAT（RFC 6598）のアドレス範囲
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// Options 画像の取得の制限
+type Options struct {
+	AllowedHosts []string      // 取得を許可するホスト（サブドメインも許可する）
+	MaxSize      int64         // 0以下の場合は DefaultMaxSize
+	Timeout      time.Duration // 接続から読み込み完了までのタイムアウト（0以下の場合は DefaultTimeout）
+	MaxRedirects int           // 0以下の場合は DefaultMaxRedirects
+	// Proxy 経由するプロキシ（nilの場合は直接接続する）
+	// プロキシを経由するURLは、接続先の代わりに名前解決したアドレスをリクエストの前に検証する
+	Proxy     func(*http.Request) (*url.URL, error)
+	TLSConfig *tls.Config // nilの場合はシステムの証明書で検証する
+}
+
+// ImageFetcher URLの画像をSSRF対策をしたうえで取得する
+// httpsかつ許可したホストのURLのみを取得し、名前解決後の接続先がプライベート・ループバックなどのアドレスの場合は接続しない
+// リダイレクト先も同じ条件で検証する
+type ImageFetcher struct {
+	options Options
+	client  *http.Client
+	// allowAddr 接続を許可するIPアドレスの判定（テストでループバックのサーバーに接続するために差し替える）
+	allowAddr func(netip.Addr) bool
+	// schemes 許可するスキーム（テストでhttpを許可するために差し替える）
+	schemes []string
+	// proxies Proxyが返したプロキシのアドレス（プロキシへの接続はアドレスを検証しない）
+	proxies sync.Map
+	// lookup プロキシを経由するURLのホストの名前解決（テストで差し替える）
+	lookup func(ctx context.Context, host string) ([]netip.Addr, error)
+}
+
+// NewImageFetcher 新しいImageFetcherを作成
+func NewImageFetcher(options Options) *ImageFetcher {
+	if options.MaxSize <= 0 {
+		options.MaxSize = DefaultMaxSize
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultTimeout
+	}
+	if options.MaxRedirects <= 0 {
+		options.MaxRedirects = DefaultMaxRedirects
+	}
+	hosts := make([]string, 0, len(options.AllowedHosts))
+	for _, host := range options.AllowedHosts {
+		hosts = append(hosts, strings.ToLower(strings.TrimPrefix(strings.TrimSpace(host), "*.")))
+	}
+	options.AllowedHosts = hosts
+
+	f := &ImageFetcher{
+		options:   options,
+		allowAddr: isPublicAddr,
+		schemes:   []string{"https"},
+		lookup: func(ctx context.Context, host string) ([]netip.Addr, error) {
+			return net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+		},
+	}
+	dialer := &net.Dialer{
+		Timeout: options.Timeout,
+		// 名前解決後の実際の接続先を検証する（DNSリバインディング対策）
+		Control: func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !f.allowAddr(addrPort.Addr().Unmap()) {
+				return fmt.Errorf("%w: %s", domain.ErrImageURLNotAllowed, address)
+			}
+			return nil
+		},
+	}
+	proxyDialer := &net.Dialer{Timeout: options.Timeout}
+	f.client = &http.Client{
+		Timeout: options.Timeout,
+		Transport: &http.Transport{
+			// 環境変数のプロキシは使わず、設定したプロキシのみ経由する
+			Proxy: f.proxy,
+			DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+				if _, ok := f.proxies.Load(address); ok {
+					return proxyDialer.DialContext(ctx, network, address)
+				}
+				return dialer.DialContext(ctx, network, address)
+			},
+			TLSClientConfig:       options.TLSConfig,
+			TLSHandshakeTimeout:   options.Timeout,
+			ResponseHeaderTimeout: options.Timeout,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > options.MaxRedirects {
+				return fmt.Errorf("stopped after %d redirects", options.MaxRedirects)
+			}
+			return f.checkURL(req.Context(), req.URL)
+		},
+	}
+	return f
+}
+
+// proxy リクエストが経由するプロキシを返し、プロキシへの接続を許可する
+func (f *ImageFetcher) proxy(req *http.Request) (*url.URL, error) {
+	if f.options.Proxy == nil {
+		return nil, nil
+	}
+	proxyURL, err := f.options.Proxy(req)
+	if err != nil || proxyURL == nil {
+		return proxyURL, err
+	}
+	f.proxies.Store(proxyAddress(proxyURL), struct{}{})
+	return proxyURL, nil
+}
+
+// proxyAddress Transportがプロキシへ接続するアドレス（ポートを省略した場合はスキームの既定のポート）
+func proxyAddress(proxyURL *url.URL) string {
+	port := proxyURL.Port()
+	if port == "" {
+		switch proxyURL.Scheme {
+		case "https":
+			port = "443"
+		case "socks5", "socks5h":
+			port = "1080"
+		default:
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(proxyURL.Hostname(), port)
+}
+
+// Fetch 画像を取得
+func (f *ImageFetcher) Fetch(ctx context.Context, rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid url", domain.ErrImageURLNotAllowed)
+	}
+	if err := f.checkURL(ctx, u); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create image request: %w", err)
+	}
+	req.Header.Set("Accept", "image/*")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		if errors.Is(err, domain.ErrImageURLNotAllowed) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to fetch image: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("image url returned status %d", resp.StatusCode)
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !strings.HasPrefix(mediaType, "image/") && mediaType != "application/octet-stream" {
+		return nil, fmt.Errorf("image url returned unsupported content type: %q", mediaType)
+	}
+	if resp.ContentLength > f.options.MaxSize {
+		return nil, fmt.Errorf("%w: %d bytes", domain.ErrImageTooLarge, resp.ContentLength)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, f.options.MaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	if int64(len(data)) > f.options.MaxSize {
+		return nil, fmt.Errorf("%w: exceeds %d bytes", domain.ErrImageTooLarge, f.options.MaxSize)
+	}
+	return data, nil
+}
+
+// checkURL スキーム・ホストが許可されているか検証
+// プロキシを経由するURLは接続先のアドレスを検証できないため、名前解決したすべてのアドレスを検証する
+func (f *ImageFetcher) checkURL(ctx context.Context, u *url.URL) error {
+	if !slices.Contains(f.schemes, u.Scheme) {
+		return fmt.Errorf("%w: scheme %q", domain.ErrImageURLNotAllowed, u.Scheme)
+	}
+	if u.User != nil {
+		return fmt.Errorf("%w: credentials in url", domain.ErrImageURLNotAllowed)
+	}
+	host := strings.ToLower(u.Hostname())
+	if !f.allowedHost(host) {
+		return fmt.Errorf("%w: host %q", domain.ErrImageURLNotAllowed, host)
+	}
+	if f.options.Proxy == nil {
+		return nil
+	}
+	if proxyURL, err := f.options.Proxy(&http.Request{URL: u}); err != nil || proxyURL == nil {
+		return err
+	}
+	addrs, err := f.lookup(ctx, host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve image host: %w", err)
+	}
+	for _, addr := range addrs {
+		if !f.allowAddr(addr.Unmap()) {
+			return fmt.Errorf("%w: %s", domain.ErrImageURLNotAllowed, addr)
+		}
+	}
+	return nil
+}
+
+// allowedHost ホストが許可したホストかそのサブドメインか判定
+func (f *ImageFetcher) allowedHost(host string) bool {
+	if host == "" {
+		return false
+	}
+	for _, allowed := range f.options.AllowedHosts {
+		if allowed != "" && (host == allowed || strings.HasSuffix(host, "."+allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPublicAddr インターネット上のアドレスか判定（プライベート・ループバック・リンクローカルなどは接続しない）
+func isPublicAddr(addr netip.Addr) bool {
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified() &&
+		!sharedAddressSpace.Contains(addr)
+}
@@ -0,0 +1,99 @@
+package idgen
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+// 識別子の生成方式（ids.strategy で指定する）
+const (
+	StrategyUUIDv7 = "uuidv7" // 時刻順のUUID（デフォルト、インデックスの局所性が高い）
+	StrategyUUIDv4 = "uuidv4" // ランダムなUUID
+	StrategyHash   = "hash"   // 画像などの内容から決定的に生成（従来の方式）
+)
+
+// New 指定した方式のIDGeneratorを作成（空の場合はUUIDv7）
+func New(strategy string) (domain.IDGenerator, error) {
+	switch strategy {
+	case "", StrategyUUIDv7:
+		return UUIDv7Generator{}, nil
+	case StrategyUUIDv4:
+		return UUIDv4Generator{}, nil
+	case StrategyHash:
+		return HashGenerator{}, nil
+	default:
+		return nil, fmt.Errorf("unknown id strategy: %s", strategy)
+	}
+}
+
+// UUIDv7Generator 時刻順のUUID（RFC 9562 バージョン7）を生成
+// 先頭が生成時刻のため、新しい行が主キーのインデックスの末尾に追加され、ページ分割が起きにくい
+type UUIDv7Generator struct{}
+
+// NewID UUIDv7を生成（seedは使用しない）
+func (UUIDv7Generator) NewID(seed []byte) string {
+	id, err := uuid.NewV7()
+	if err != nil {
+		// 乱数を取得できない場合のみ失敗する（uuid.NewStringと同様に回復不能として扱う）
+		panic(fmt.Sprintf("failed to generate UUIDv7: %v", err))
+	}
+	return id.String()
+}
+
+// ChildID UUIDv7を生成（親の識別子は使用しない）
+func (g UUIDv7Generator) ChildID(parentID string, index int, seed []byte) string {
+	return g.NewID(nil)
+}
+
+// UUIDv4Generator ランダムなUUID（バージョン4）を生成
+type UUIDv4Generator struct{}
+
+// NewID UUIDv4を生成（seedは使用しない）
+func (UUIDv4Generator) NewID(seed []byte) string {
+	return uuid.NewString()
+}
+
+// ChildID UUIDv4を生成（親の識別子は使用しない）
+func (UUIDv4Generator) ChildID(parentID string, index int, seed []byte) string {
+	return uuid.NewString()
+}
+
+// HashGenerator 内容から決定的な識別子を生成（同じ画像からは同じレシートIDになる）
+// 識別子はUUID形式の文字列（8-4-4-4-12）だが、RFC 9562準拠のUUIDではなくSHA256ハッシュの先頭16バイト
+type HashGenerator struct{}
+
+// NewID seedのSHA256ハッシュから識別子を生成（seedがない場合はUUIDv4）
+func (HashGenerator) NewID(seed []byte) string {
+	if len(seed) == 0 {
+		return uuid.NewString()
+	}
+	return hashID(seed)
+}
+
+// ChildID 親の識別子・インデックス・seed（明細の商品名と単価など）のSHA256ハッシュから識別子を生成
+// 同じレシートを再処理して明細の並びが変わっても、別の明細に同じ識別子を割り当てない
+func (HashGenerator) ChildID(parentID string, index int, seed []byte) string {
+	data := make([]byte, 0, len(parentID)+len(seed)+16)
+	data = append(data, parentID...)
+	data = append(data, 0)
+	data = strconv.AppendInt(data, int64(index), 10)
+	data = append(data, 0)
+	data = append(data, seed...)
+	return hashID(data)
+}
+
+// hashID データのSHA256ハッシュの先頭16バイトをUUID形式の文字列にする
+func hashID(data []byte) string {
+	hash := sha256.Sum256(data)
+	return fmt.Sprintf("%x-%x-%x-%x-%x",
+		hash[0:4],
+		hash[4:6],
+		hash[6:8],
+		hash[8:10],
+		hash[10:16])
+}
@@ -0,0 +1,97 @@
+package idgen
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		wantErr  bool
+	}{
+		{name: "正常系: 未指定はUUIDv7", strategy: ""},
+		{name: "正常系: uuidv7", strategy: StrategyUUIDv7},
+		{name: "正常系: uuidv4", strategy: StrategyUUIDv4},
+		{name: "正常系: hash", strategy: StrategyHash},
+		{name: "異常系: 不明な方式", strategy: "ulid", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen, err := New(tt.strategy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && gen == nil {
+				t.Error("New() returned nil generator")
+			}
+		})
+	}
+}
+
+func TestUUIDv7Generator_NewID(t *testing.T) {
+	gen := UUIDv7Generator{}
+
+	ids := make([]string, 100)
+	for i := range ids {
+		ids[i] = gen.NewID([]byte("ignored"))
+	}
+
+	for _, id := range ids {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			t.Fatalf("uuid.Parse(%q) error = %v", id, err)
+		}
+		if parsed.Version() != 7 {
+			t.Errorf("Version() = %d, want 7", parsed.Version())
+		}
+	}
+	// 生成順に文字列として並ぶ（インデックスの末尾に追加される）
+	if !sort.StringsAreSorted(ids) {
+		t.Error("UUIDv7 ids are not sorted in generation order")
+	}
+	if gen.ChildID(ids[0], 0, nil) == gen.ChildID(ids[0], 0, nil) {
+		t.Error("ChildID() returned the same id twice")
+	}
+}
+
+func TestHashGenerator(t *testing.T) {
+	gen := HashGenerator{}
+
+	first := gen.NewID([]byte("receipt image"))
+	if first != gen.NewID([]byte("receipt image")) {
+		t.Error("NewID() is not deterministic for the same seed")
+	}
+	if first == gen.NewID([]byte("another image")) {
+		t.Error("NewID() returned the same id for different seeds")
+	}
+	if len(first) != 36 {
+		t.Errorf("NewID() length = %d, want 36", len(first))
+	}
+	if gen.NewID(nil) == gen.NewID(nil) {
+		t.Error("NewID(nil) should be random")
+	}
+
+	child := gen.ChildID(first, 3, []byte("牛乳\x00198"))
+	if child != gen.ChildID(first, 3, []byte("牛乳\x00198")) {
+		t.Error("ChildID() is not deterministic for the same parent, index and seed")
+	}
+	if len(child) != 36 {
+		t.Errorf("ChildID() length = %d, want 36", len(child))
+	}
+	// 再処理で明細の並びが変わっても、別の明細と同じ識別子にならない
+	others := []string{
+		gen.ChildID(first, 4, []byte("牛乳\x00198")),
+		gen.ChildID(first, 3, []byte("食パン\x00158")),
+		gen.ChildID(gen.NewID([]byte("another image")), 3, []byte("牛乳\x00198")),
+	}
+	for _, other := range others {
+		if other == child {
+			t.Errorf("ChildID() = %s collides with a different item", other)
+		}
+	}
+}
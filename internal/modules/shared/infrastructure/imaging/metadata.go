@@ -0,0 +1,221 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+)
+
+// ErrMalformedImage 画像の構造を解析できない
+var ErrMalformedImage = errors.New("malformed image")
+
+var (
+	jpegSOI       = []byte{0xFF, 0xD8}
+	pngSignature  = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n'}
+	exifHeader    = []byte("Exif\x00\x00")
+	orientationID = uint16(0x0112)
+)
+
+// MetadataStripper domain.MetadataStripperの実装
+type MetadataStripper struct{}
+
+// NewMetadataStripper 新しいMetadataStripperを作成
+func NewMetadataStripper() *MetadataStripper {
+	return &MetadataStripper{}
+}
+
+// StripMetadata 画像から位置情報を含むメタデータを取り除く
+func (s *MetadataStripper) StripMetadata(data []byte) ([]byte, error) {
+	return StripMetadata(data)
+}
+
+// StripMetadata 画像から位置情報を含むメタデータを取り除く
+// JPEGはEXIF・XMP・IPTC・コメントを削除し、表示の向きだけを最小限のEXIFとして残す。
+// PNGはテキスト・EXIF・タイムスタンプのチャンクを削除する。
+// それ以外の形式はメタデータを持たないものとしてそのまま返す
+func StripMetadata(data []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(data, jpegSOI):
+		return stripJPEG(data)
+	case bytes.HasPrefix(data, pngSignature):
+		return stripPNG(data)
+	default:
+		return data, nil
+	}
+}
+
+// stripJPEG JPEGのメタデータセグメントを削除
+func stripJPEG(data []byte) ([]byte, error) {
+	out := make([]byte, 0, len(data))
+	out = append(out, jpegSOI...)
+
+	orientation := uint16(0)
+	pos := len(jpegSOI)
+	for {
+		// マーカー前の埋め草（0xFF）を読み飛ばす
+		if pos >= len(data) || data[pos] != 0xFF {
+			return nil, fmt.Errorf("%w: missing JPEG marker", ErrMalformedImage)
+		}
+		for pos < len(data) && data[pos] == 0xFF {
+			pos++
+		}
+		if pos >= len(data) {
+			return nil, fmt.Errorf("%w: truncated JPEG", ErrMalformedImage)
+		}
+		marker := data[pos]
+		pos++
+
+		// 長さを持たないマーカー
+		if marker == 0xD9 || marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			out = append(out, 0xFF, marker)
+			if marker == 0xD9 {
+				return out, nil
+			}
+			continue
+		}
+
+		if pos+2 > len(data) {
+			return nil, fmt.Errorf("%w: truncated JPEG segment", ErrMalformedImage)
+		}
+		length := int(binary.BigEndian.Uint16(data[pos:]))
+		if length < 2 || pos+length > len(data) {
+			return nil, fmt.Errorf("%w: invalid JPEG segment length", ErrMalformedImage)
+		}
+		segment := data[pos : pos+length]
+		payload := segment[2:]
+		pos += length
+
+		switch {
+		case marker == 0xE1:
+			// APP1（EXIF・XMP）: 向きだけ取り出して削除
+			if o := exifOrientation(payload); o > 1 {
+				orientation = o
+			}
+			continue
+		case marker == 0xED || marker == 0xFE:
+			// APP13（IPTC）・COM（コメント）
+			continue
+		case marker == 0xDA:
+			// SOS以降は圧縮データなので、向きのEXIFを差し込んでから残りをそのまま出力する
+			out = appendOrientation(out, orientation)
+			out = append(out, 0xFF, marker)
+			out = append(out, segment...)
+			return append(out, data[pos:]...), nil
+		}
+
+		// APP0（JFIF）・APP2（ICCプロファイル）・APP14（Adobe）などの表示に必要なセグメントは残す
+		if marker >= 0xE0 && marker <= 0xEF {
+			out = append(out, 0xFF, marker)
+			out = append(out, segment...)
+			continue
+		}
+
+		// 最初の非APPセグメントの前に向きのEXIFを差し込む（APP0の直後になる）
+		out = appendOrientation(out, orientation)
+		orientation = 0
+		out = append(out, 0xFF, marker)
+		out = append(out, segment...)
+	}
+}
+
+// exifOrientation EXIFのIFD0から向き（Orientation）を取得（見つからない場合は0）
+func exifOrientation(payload []byte) uint16 {
+	if !bytes.HasPrefix(payload, exifHeader) {
+		return 0
+	}
+	tiff := payload[len(exifHeader):]
+	if len(tiff) < 8 {
+		return 0
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	offset := int(order.Uint32(tiff[4:]))
+	if offset+2 > len(tiff) {
+		return 0
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:]) == orientationID {
+			return order.Uint16(tiff[entry+8:])
+		}
+	}
+	return 0
+}
+
+// appendOrientation 向きだけを含む最小限のEXIFセグメントを追加（向きが標準の場合は追加しない）
+func appendOrientation(out []byte, orientation uint16) []byte {
+	if orientation <= 1 {
+		return out
+	}
+
+	tiff := make([]byte, 0, 26)
+	tiff = append(tiff, 'M', 'M', 0x00, 0x2A)
+	tiff = binary.BigEndian.AppendUint32(tiff, 8) // IFD0の位置
+	tiff = binary.BigEndian.AppendUint16(tiff, 1) // エントリ数
+	tiff = binary.BigEndian.AppendUint16(tiff, orientationID)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)           // SHORT
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)           // 値の個数
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation) // 値（4バイト領域の先頭）
+	tiff = append(tiff, 0x00, 0x00)
+	tiff = binary.BigEndian.AppendUint32(tiff, 0) // 次のIFDなし
+
+	out = append(out, 0xFF, 0xE1)
+	out = binary.BigEndian.AppendUint16(out, uint16(2+len(exifHeader)+len(tiff)))
+	out = append(out, exifHeader...)
+	return append(out, tiff...)
+}
+
+// pngMetadataChunks 削除するPNGのチャンク
+var pngMetadataChunks = map[string]bool{
+	"eXIf": true,
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"tIME": true,
+}
+
+// stripPNG PNGのメタデータチャンクを削除
+func stripPNG(data []byte) ([]byte, error) {
+	out := make([]byte, 0, len(data))
+	out = append(out, pngSignature...)
+
+	pos := len(pngSignature)
+	for pos < len(data) {
+		if pos+8 > len(data) {
+			return nil, fmt.Errorf("%w: truncated PNG chunk", ErrMalformedImage)
+		}
+		length := int(binary.BigEndian.Uint32(data[pos:]))
+		chunkType := string(data[pos+4 : pos+8])
+		end := pos + 12 + length // 長さ・種類・データ・CRC
+		if length < 0 || end > len(data) {
+			return nil, fmt.Errorf("%w: invalid PNG chunk length", ErrMalformedImage)
+		}
+		if crc32.ChecksumIEEE(data[pos+4:end-4]) != binary.BigEndian.Uint32(data[end-4:]) {
+			return nil, fmt.Errorf("%w: PNG chunk checksum mismatch", ErrMalformedImage)
+		}
+
+		if !pngMetadataChunks[chunkType] {
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+		if chunkType == "IEND" {
+			return out, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: missing PNG IEND chunk", ErrMalformedImage)
+}
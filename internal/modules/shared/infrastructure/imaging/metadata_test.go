@@ -0,0 +1,178 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// testImage テスト用の小さな画像
+func testImage() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for x := 0; x < 8; x++ {
+		img.Set(x, x, color.RGBA{R: 255, A: 255})
+	}
+	return img
+}
+
+// exifSegment 向きと位置情報（GPSLatitudeRef・マーカー文字列）を含むAPP1セグメントを作成
+func exifSegment(orientation uint16) []byte {
+	tiff := []byte{'I', 'I', 0x2A, 0x00}
+	tiff = binary.LittleEndian.AppendUint32(tiff, 8)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 2)
+	// Orientation
+	tiff = binary.LittleEndian.AppendUint16(tiff, 0x0112)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 3)
+	tiff = binary.LittleEndian.AppendUint32(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0x00, 0x00)
+	// GPSInfo（IFDの位置）
+	tiff = binary.LittleEndian.AppendUint16(tiff, 0x8825)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 4)
+	tiff = binary.LittleEndian.AppendUint32(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint32(tiff, 38)
+	tiff = binary.LittleEndian.AppendUint32(tiff, 0)
+	tiff = append(tiff, []byte("GPS:35.6812N,139.7671E")...)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+// jpegWithMetadata EXIF・コメント付きのJPEGを作成
+func jpegWithMetadata(t *testing.T, orientation uint16) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, testImage(), nil); err != nil {
+		t.Fatalf("jpeg.Encode() error = %v", err)
+	}
+	encoded := buf.Bytes()
+
+	comment := []byte("Shot at home")
+	com := []byte{0xFF, 0xFE}
+	com = binary.BigEndian.AppendUint16(com, uint16(len(comment)+2))
+	com = append(com, comment...)
+
+	data := append([]byte{}, encoded[:2]...)
+	data = append(data, exifSegment(orientation)...)
+	data = append(data, com...)
+	return append(data, encoded[2:]...)
+}
+
+// pngChunk PNGのチャンクを作成
+func pngChunk(chunkType string, payload []byte) []byte {
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(payload)))
+	chunk = append(chunk, chunkType...)
+	chunk = append(chunk, payload...)
+	return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+}
+
+// pngWithMetadata テキスト・EXIFチャンク付きのPNGを作成
+func pngWithMetadata(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage()); err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+	encoded := buf.Bytes()
+
+	// シグネチャ(8) + IHDR(25) の直後に差し込む
+	data := append([]byte{}, encoded[:33]...)
+	data = append(data, pngChunk("tEXt", []byte("Comment\x00GPS:35.6812N,139.7671E"))...)
+	data = append(data, pngChunk("eXIf", exifSegment(1)[4+6:])...)
+	return append(data, encoded[33:]...)
+}
+
+func TestStripMetadata_JPEG(t *testing.T) {
+	tests := []struct {
+		name            string
+		orientation     uint16
+		wantOrientation uint16
+	}{
+		{name: "正常系: 回転ありの向きは残す", orientation: 6, wantOrientation: 6},
+		{name: "正常系: 標準の向きはEXIFごと削除", orientation: 1, wantOrientation: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := jpegWithMetadata(t, tt.orientation)
+
+			stripped, err := StripMetadata(data)
+			if err != nil {
+				t.Fatalf("StripMetadata() error = %v", err)
+			}
+
+			for _, secret := range []string{"GPS:", "Shot at home"} {
+				if bytes.Contains(stripped, []byte(secret)) {
+					t.Errorf("stripped image still contains %q", secret)
+				}
+			}
+			if _, err := jpeg.Decode(bytes.NewReader(stripped)); err != nil {
+				t.Errorf("jpeg.Decode() error = %v", err)
+			}
+
+			var orientation uint16
+			if i := bytes.Index(stripped, []byte("Exif\x00\x00")); i >= 0 {
+				orientation = exifOrientation(stripped[i:])
+			}
+			if orientation != tt.wantOrientation {
+				t.Errorf("orientation = %d, want %d", orientation, tt.wantOrientation)
+			}
+		})
+	}
+}
+
+func TestStripMetadata_PNG(t *testing.T) {
+	data := pngWithMetadata(t)
+
+	stripped, err := StripMetadata(data)
+	if err != nil {
+		t.Fatalf("StripMetadata() error = %v", err)
+	}
+
+	for _, chunk := range []string{"tEXt", "eXIf", "GPS:"} {
+		if bytes.Contains(stripped, []byte(chunk)) {
+			t.Errorf("stripped image still contains %q", chunk)
+		}
+	}
+	if _, err := png.Decode(bytes.NewReader(stripped)); err != nil {
+		t.Errorf("png.Decode() error = %v", err)
+	}
+}
+
+func TestStripMetadata_Others(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{name: "正常系: 対象外の形式はそのまま", data: []byte("GIF89a...")},
+		{name: "異常系: 途中で切れたJPEG", data: []byte{0xFF, 0xD8, 0xFF, 0xE1, 0x00, 0x40}, wantErr: true},
+		{name: "異常系: 途中で切れたPNG", data: append(append([]byte{}, pngSignature...), 0x00, 0x00), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := StripMetadata(tt.data)
+			if tt.wantErr {
+				if !errors.Is(err, ErrMalformedImage) {
+					t.Errorf("StripMetadata() error = %v, want ErrMalformedImage", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("StripMetadata() error = %v", err)
+			}
+			if !bytes.Equal(got, tt.data) {
+				t.Errorf("StripMetadata() = %q, want unchanged", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package line
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTimeout LINE Messaging APIの呼び出しのデフォルトのタイムアウト
+	DefaultTimeout = 30 * time.Second
+	// DefaultAPIBaseURL Messaging APIのURL
+	DefaultAPIBaseURL = "https://api.line.me"
+	// DefaultDataAPIBaseURL コンテンツの取得に使うMessaging APIのURL
+	DefaultDataAPIBaseURL = "https://api-data.line.me"
+
+	// maxReplyMessages 1回の返信で送れるメッセージ数の上限
+	maxReplyMessages = 5
+	// maxTextLength テキストメッセージの最大文字数
+	maxTextLength = 5000
+	// maxContentSize 取得するコンテンツの最大サイズ
+	maxContentSize = 20 << 20
+)
+
+// Options Messaging APIの接続先
+type Options struct {
+	APIBaseURL     string // 空の場合は DefaultAPIBaseURL
+	DataAPIBaseURL string // 空の場合は DefaultDataAPIBaseURL
+	Timeout        time.Duration
+}
+
+// Client LINE Messaging APIのクライアント
+type Client struct {
+	accessToken string
+	options     Options
+	client      *http.Client
+}
+
+// NewClient 新しいClientを作成（accessTokenはチャネルアクセストークン）
+func NewClient(accessToken string, options Options) *Client {
+	if options.APIBaseURL == "" {
+		options.APIBaseURL = DefaultAPIBaseURL
+	}
+	if options.DataAPIBaseURL == "" {
+		options.DataAPIBaseURL = DefaultDataAPIBaseURL
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultTimeout
+	}
+	return &Client{
+		accessToken: accessToken,
+		options:     options,
+		client:      &http.Client{Timeout: options.Timeout},
+	}
+}
+
+// SetTransport 外部への接続に使うTransportを設定（プロキシ・追加のルート証明書など、未設定の場合はhttp.DefaultTransport）
+func (c *Client) SetTransport(transport http.RoundTripper) {
+	c.client.Transport = transport
+}
+
+// DownloadContent ユーザーが送信した画像などのコンテンツを取得
+func (c *Client) DownloadContent(ctx context.Context, messageID string) ([]byte, error) {
+	endpoint := strings.TrimRight(c.options.DataAPIBaseURL, "/") + "/v2/bot/message/" + url.PathEscape(messageID) + "/content"
+	resp, err := c.do(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxContentSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read line content: %w", err)
+	}
+	if len(data) > maxContentSize {
+		return nil, fmt.Errorf("line content exceeds %d bytes", maxContentSize)
+	}
+	return data, nil
+}
+
+// Reply 返信用トークンを使ってテキストメッセージを返信（上限を超えるメッセージ・文字は切り詰める）
+func (c *Client) Reply(ctx context.Context, replyToken string, texts ...string) error {
+	if len(texts) > maxReplyMessages {
+		texts = texts[:maxReplyMessages]
+	}
+	messages := make([]textMessage, 0, len(texts))
+	for _, text := range texts {
+		messages = append(messages, textMessage{Type: "text", Text: truncate(text, maxTextLength)})
+	}
+
+	body, err := json.Marshal(replyRequest{ReplyToken: replyToken, Messages: messages})
+	if err != nil {
+		return fmt.Errorf("failed to marshal line reply: %w", err)
+	}
+	endpoint := strings.TrimRight(c.options.APIBaseURL, "/") + "/v2/bot/message/reply"
+	resp, err := c.do(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	return nil
+}
+
+// replyRequest 返信APIのリクエスト
+type replyRequest struct {
+	ReplyToken string        `json:"replyToken"`
+	Messages   []textMessage `json:"messages"`
+}
+
+// textMessage テキストメッセージ
+type textMessage struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// do APIを呼び出す（2xx以外の応答はエラー、呼び出し元がレスポンスボディを閉じる）
+func (c *Client) do(ctx context.Context, method, endpoint string, body []byte) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create line request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call line api: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("line api returned status %d: %s", resp.StatusCode, data)
+	}
+	return resp, nil
+}
+
+// truncate 文字数（rune）の上限で切り詰める
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit])
+}
@@ -0,0 +1,90 @@
+package line
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_DownloadContent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("Authorization = %s", r.Header.Get("Authorization"))
+		}
+		if r.URL.Path != "/v2/bot/message/m1/content" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("image data"))
+	}))
+	defer server.Close()
+
+	c := NewClient("token", Options{DataAPIBaseURL: server.URL, Timeout: 5 * time.Second})
+
+	tests := []struct {
+		name      string
+		messageID string
+		want      string
+		wantErr   bool
+	}{
+		{name: "正常系: 画像の取得", messageID: "m1", want: "image data"},
+		{name: "異常系: 存在しないメッセージ", messageID: "missing", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := c.DownloadContent(context.Background(), tt.messageID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DownloadContent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(data) != tt.want {
+				t.Errorf("DownloadContent() = %q, want %q", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_Reply(t *testing.T) {
+	var received replyRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v2/bot/message/reply" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Content-Type = %s", r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode body: %v", err)
+		}
+		if received.ReplyToken == "expired" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message":"Invalid reply token"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("token", Options{APIBaseURL: server.URL})
+
+	if err := c.Reply(context.Background(), "r1", "登録しました", strings.Repeat("あ", maxTextLength+10)); err != nil {
+		t.Fatalf("Reply() error = %v", err)
+	}
+	if received.ReplyToken != "r1" || len(received.Messages) != 2 {
+		t.Fatalf("received = %+v", received)
+	}
+	if received.Messages[0].Type != "text" || received.Messages[0].Text != "登録しました" {
+		t.Errorf("Messages[0] = %+v", received.Messages[0])
+	}
+	if got := len([]rune(received.Messages[1].Text)); got != maxTextLength {
+		t.Errorf("Messages[1] length = %d, want %d", got, maxTextLength)
+	}
+
+	if err := c.Reply(context.Background(), "expired", "登録しました"); err == nil {
+		t.Error("Expected error for invalid reply token")
+	}
+}
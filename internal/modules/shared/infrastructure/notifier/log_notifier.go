@@ -0,0 +1,22 @@
+package notifier
+
+import (
+	"context"
+	"log/slog"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+// LogNotifier 通知をログに出力する（Webhook未設定時の送信先）
+type LogNotifier struct{}
+
+// NewLogNotifier 新しいLogNotifierを作成
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+// Notify 通知をログに出力
+func (n *LogNotifier) Notify(ctx context.Context, notification domain.Notification) error {
+	slog.InfoContext(ctx, "Notification", "event", notification.Event, "message", notification.Message)
+	return nil
+}
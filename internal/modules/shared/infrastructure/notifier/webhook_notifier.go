@@ -0,0 +1,66 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+// DefaultTimeout 通知の送信のデフォルトのタイムアウト
+const DefaultTimeout = 10 * time.Second
+
+// WebhookNotifier 通知をJSONで指定URLへPOSTする
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier 新しいWebhookNotifierを作成
+func NewWebhookNotifier(url string, timeout time.Duration) *WebhookNotifier {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// SetTransport 外部への接続に使うTransportを設定（プロキシ・追加のルート証明書など、未設定の場合はhttp.DefaultTransport）
+func (n *WebhookNotifier) SetTransport(transport http.RoundTripper) {
+	n.client.Transport = transport
+}
+
+// Notify 通知をWebhookへ送信（2xx以外の応答はエラー）
+func (n *WebhookNotifier) Notify(ctx context.Context, notification domain.Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, body)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	var received domain.Notification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Content-Type = %s", r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode body: %v", err)
+		}
+		if received.Event == "broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier(server.URL, 5*time.Second)
+
+	tests := []struct {
+		name    string
+		event   string
+		wantErr bool
+	}{
+		{name: "正常系: 送信", event: "warranty.expiring"},
+		{name: "異常系: Webhookのエラー", event: "broken", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := n.Notify(context.Background(), domain.Notification{Event: tt.event, Message: "テスト"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Notify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if received.Event != tt.event || received.Message != "テスト" {
+				t.Errorf("received = %+v", received)
+			}
+		})
+	}
+}
+
+func TestWebhookNotifier_Notify_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	if err := NewWebhookNotifier(url, time.Second).Notify(context.Background(), domain.Notification{Event: "test"}); err == nil {
+		t.Error("Notify() error = nil, want error")
+	}
+}
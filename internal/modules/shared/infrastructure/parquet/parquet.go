@@ -0,0 +1,462 @@
+// Package parquet 分析用のParquetファイルの書き出し
+// 入れ子のない列だけの表を、PLAINエンコーディング・GZIP圧縮の行グループごとに1ページで書き出す（DuckDB・BigQueryで読み込める最小限の実装）
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"time"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+// magic ファイルの先頭と末尾に置く識別子
+const magic = "PAR1"
+
+// DefaultRowGroupSize 1つの行グループにまとめる行数
+const DefaultRowGroupSize = 50000
+
+// createdBy ファイルのメタデータに記録する作成元
+const createdBy = "vision-api-app"
+
+// Parquetの物理型
+const (
+	typeBoolean   = 0
+	typeInt32     = 1
+	typeInt64     = 2
+	typeDouble    = 5
+	typeByteArray = 6
+)
+
+// Parquetの変換型（論理型）
+const (
+	convertedUTF8            = 0
+	convertedDate            = 6
+	convertedTimestampMillis = 9
+)
+
+// その他のメタデータの値
+const (
+	repetitionRequired = 0
+	repetitionOptional = 1
+	encodingPlain      = 0
+	encodingRLE        = 3
+	codecGzip          = 2
+	pageTypeData       = 0
+)
+
+// ErrClosed Close済みのWriterに書き込んだ
+var ErrClosed = errors.New("parquet writer is closed")
+
+// Format ParquetのTableFormat
+type Format struct{}
+
+// Extension ファイルの拡張子
+func (Format) Extension() string {
+	return ".parquet"
+}
+
+// ContentType ファイルのMIMEタイプ
+func (Format) ContentType() string {
+	return "application/vnd.apache.parquet"
+}
+
+// NewWriter 新しいWriterを作成
+func (Format) NewWriter(w io.Writer, columns []domain.TableColumn, metadata map[string]string) domain.TableWriter {
+	return NewWriter(w, columns, metadata)
+}
+
+// Writer 行をParquetファイルに書き出す
+// 行はDefaultRowGroupSize行ごとに行グループとして書き出し、Closeでファイルの末尾（メタデータ）を書き出す
+type Writer struct {
+	w            *countingWriter
+	columns      []domain.TableColumn
+	metadata     map[string]string
+	rowGroupSize int
+
+	buffers   []*columnBuffer // 書き出し前の行グループの列ごとの値
+	rows      int             // 書き出し前の行数
+	rowGroups []rowGroup      // 書き出し済みの行グループ
+	numRows   int64
+	started   bool // 先頭の識別子を書き出した
+	closed    bool
+	err       error // 書き出しに失敗した場合は以降の書き込みもこのエラーを返す
+}
+
+// columnBuffer 行グループの1列分の値
+type columnBuffer struct {
+	values  bytes.Buffer // PLAINエンコーディングの値（真偽値以外、nullは含めない）
+	bools   []bool       // 真偽値の列の値
+	defined []bool       // nullでないか（Optionalの列のみ）
+	count   int          // nullを含む値の数
+}
+
+// rowGroup 書き出し済みの行グループ
+type rowGroup struct {
+	columns []columnChunk
+	numRows int64
+}
+
+// columnChunk 書き出し済みの列のチャンク（1ページ）
+type columnChunk struct {
+	offset       int64
+	numValues    int64
+	uncompressed int64 // ページヘッダーを含む圧縮前のサイズ
+	compressed   int64 // ページヘッダーを含む圧縮後のサイズ
+}
+
+// countingWriter 書き出したバイト数を数える
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// NewWriter 新しいWriterを作成（metadataはファイルのkey_value_metadataに記録する）
+func NewWriter(w io.Writer, columns []domain.TableColumn, metadata map[string]string) *Writer {
+	writer := &Writer{
+		w:            &countingWriter{w: w},
+		columns:      columns,
+		metadata:     metadata,
+		rowGroupSize: DefaultRowGroupSize,
+	}
+	writer.resetBuffers()
+	return writer
+}
+
+// SetRowGroupSize 1つの行グループにまとめる行数を設定（0以下はデフォルト）
+func (w *Writer) SetRowGroupSize(rows int) {
+	if rows <= 0 {
+		rows = DefaultRowGroupSize
+	}
+	w.rowGroupSize = rows
+}
+
+// Write 1行を書き出す（値の型はTableColumnの型に従い、Optionalの列はnilでnullにする）
+func (w *Writer) Write(row []any) error {
+	if w.closed {
+		return ErrClosed
+	}
+	if w.err != nil {
+		return w.err
+	}
+	if len(row) != len(w.columns) {
+		return fmt.Errorf("row has %d values, want %d columns", len(row), len(w.columns))
+	}
+	// 型の誤りで行の途中まで追加しないよう、先にすべての値を確認する
+	for i, column := range w.columns {
+		if err := checkValue(column, row[i]); err != nil {
+			return err
+		}
+	}
+	for i, column := range w.columns {
+		w.buffers[i].append(column, row[i])
+	}
+	w.rows++
+	if w.rows >= w.rowGroupSize {
+		w.err = w.flushRowGroup()
+	}
+	return w.err
+}
+
+// Close 残りの行とファイルの末尾を書き出す（行がない場合は行グループのないファイルにする）
+func (w *Writer) Close() error {
+	if w.closed {
+		return w.err
+	}
+	w.closed = true
+	if w.err != nil {
+		return w.err
+	}
+	if w.rows > 0 {
+		if w.err = w.flushRowGroup(); w.err != nil {
+			return w.err
+		}
+	}
+	if w.err = w.writeMagic(); w.err != nil {
+		return w.err
+	}
+
+	footer := w.footer()
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(len(footer)))
+	for _, data := range [][]byte{footer, length[:], []byte(magic)} {
+		if _, err := w.w.Write(data); err != nil {
+			w.err = fmt.Errorf("failed to write parquet footer: %w", err)
+			return w.err
+		}
+	}
+	return nil
+}
+
+// writeMagic ファイルの先頭の識別子を書き出す（書き出し済みの場合は何もしない）
+func (w *Writer) writeMagic() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+	if _, err := io.WriteString(w.w, magic); err != nil {
+		return fmt.Errorf("failed to write parquet header: %w", err)
+	}
+	return nil
+}
+
+// flushRowGroup 書き出し前の行を1つの行グループとして書き出す
+func (w *Writer) flushRowGroup() error {
+	if err := w.writeMagic(); err != nil {
+		return err
+	}
+	group := rowGroup{numRows: int64(w.rows)}
+	for i, column := range w.columns {
+		chunk, err := w.writeColumnChunk(column, w.buffers[i])
+		if err != nil {
+			return fmt.Errorf("failed to write column %s: %w", column.Name, err)
+		}
+		group.columns = append(group.columns, chunk)
+	}
+	w.rowGroups = append(w.rowGroups, group)
+	w.numRows += int64(w.rows)
+	w.rows = 0
+	w.resetBuffers()
+	return nil
+}
+
+// writeColumnChunk 列の値を1つのデータページとして書き出す
+func (w *Writer) writeColumnChunk(column domain.TableColumn, buffer *columnBuffer) (columnChunk, error) {
+	// ページの内容: 定義レベル（Optionalの列のみ、長さ付きのRLE）と値
+	var page bytes.Buffer
+	if column.Optional {
+		levels := encodeLevels(buffer.defined)
+		_ = binary.Write(&page, binary.LittleEndian, uint32(len(levels)))
+		page.Write(levels)
+	}
+	if column.Type == domain.ColumnBool {
+		page.Write(packBools(buffer.bools))
+	} else {
+		page.Write(buffer.values.Bytes())
+	}
+
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	if _, err := zw.Write(page.Bytes()); err != nil {
+		return columnChunk{}, err
+	}
+	if err := zw.Close(); err != nil {
+		return columnChunk{}, err
+	}
+
+	header := newCompactWriter()
+	header.i32(1, pageTypeData)
+	header.i32(2, int32(page.Len()))
+	header.i32(3, int32(compressed.Len()))
+	header.structField(5, func() {
+		header.i32(1, int32(buffer.count))
+		header.i32(2, encodingPlain)
+		header.i32(3, encodingRLE)
+		header.i32(4, encodingRLE)
+	})
+	headerBytes := header.bytes()
+
+	chunk := columnChunk{
+		offset:       w.w.n,
+		numValues:    int64(buffer.count),
+		uncompressed: int64(len(headerBytes) + page.Len()),
+		compressed:   int64(len(headerBytes) + compressed.Len()),
+	}
+	if _, err := w.w.Write(headerBytes); err != nil {
+		return columnChunk{}, err
+	}
+	if _, err := w.w.Write(compressed.Bytes()); err != nil {
+		return columnChunk{}, err
+	}
+	return chunk, nil
+}
+
+// footer ファイルのメタデータ（FileMetaData）
+func (w *Writer) footer() []byte {
+	c := newCompactWriter()
+	c.i32(1, 1)
+	c.structList(2, len(w.columns)+1, func(i int) {
+		if i == 0 {
+			c.string(4, "schema")
+			c.i32(5, int32(len(w.columns)))
+			return
+		}
+		column := w.columns[i-1]
+		physical, converted := columnTypes(column.Type)
+		c.i32(1, physical)
+		repetition := int32(repetitionRequired)
+		if column.Optional {
+			repetition = repetitionOptional
+		}
+		c.i32(3, repetition)
+		c.string(4, column.Name)
+		if converted >= 0 {
+			c.i32(6, converted)
+		}
+	})
+	c.i64(3, w.numRows)
+	c.structList(4, len(w.rowGroups), func(i int) {
+		group := w.rowGroups[i]
+		var totalSize int64
+		c.structList(1, len(group.columns), func(j int) {
+			chunk := group.columns[j]
+			column := w.columns[j]
+			physical, _ := columnTypes(column.Type)
+			totalSize += chunk.uncompressed
+			c.i64(2, chunk.offset)
+			c.structField(3, func() {
+				c.i32(1, physical)
+				c.i32List(2, []int32{encodingPlain, encodingRLE})
+				c.stringList(3, []string{column.Name})
+				c.i32(4, codecGzip)
+				c.i64(5, chunk.numValues)
+				c.i64(6, chunk.uncompressed)
+				c.i64(7, chunk.compressed)
+				c.i64(9, chunk.offset)
+			})
+		})
+		c.i64(2, totalSize)
+		c.i64(3, group.numRows)
+	})
+	if len(w.metadata) > 0 {
+		keys := make([]string, 0, len(w.metadata))
+		for key := range w.metadata {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		c.structList(5, len(keys), func(i int) {
+			c.string(1, keys[i])
+			c.string(2, w.metadata[keys[i]])
+		})
+	}
+	c.string(6, createdBy)
+	return c.bytes()
+}
+
+// resetBuffers 次の行グループの列ごとのバッファーを用意する
+func (w *Writer) resetBuffers() {
+	w.buffers = make([]*columnBuffer, len(w.columns))
+	for i := range w.buffers {
+		w.buffers[i] = &columnBuffer{}
+	}
+}
+
+// columnTypes 列の型に対応する物理型と変換型（変換型がない場合は-1）
+func columnTypes(typ domain.ColumnType) (int32, int32) {
+	switch typ {
+	case domain.ColumnString:
+		return typeByteArray, convertedUTF8
+	case domain.ColumnInt64:
+		return typeInt64, -1
+	case domain.ColumnDouble:
+		return typeDouble, -1
+	case domain.ColumnBool:
+		return typeBoolean, -1
+	case domain.ColumnTimestamp:
+		return typeInt64, convertedTimestampMillis
+	case domain.ColumnDate:
+		return typeInt32, convertedDate
+	}
+	return typeByteArray, -1
+}
+
+// checkValue 値が列の型に合うか確認
+func checkValue(column domain.TableColumn, value any) error {
+	if value == nil {
+		if !column.Optional {
+			return fmt.Errorf("column %s: null value for required column", column.Name)
+		}
+		return nil
+	}
+	var ok bool
+	switch column.Type {
+	case domain.ColumnString:
+		_, ok = value.(string)
+	case domain.ColumnInt64:
+		_, ok = value.(int64)
+	case domain.ColumnDouble:
+		_, ok = value.(float64)
+	case domain.ColumnBool:
+		_, ok = value.(bool)
+	case domain.ColumnTimestamp, domain.ColumnDate:
+		_, ok = value.(time.Time)
+	default:
+		return fmt.Errorf("column %s: unsupported type %q", column.Name, column.Type)
+	}
+	if !ok {
+		return fmt.Errorf("column %s: %T is not a %s value", column.Name, value, column.Type)
+	}
+	return nil
+}
+
+// append 値を追加する（checkValueで確認済みの値）
+func (b *columnBuffer) append(column domain.TableColumn, value any) {
+	b.count++
+	if column.Optional {
+		b.defined = append(b.defined, value != nil)
+	}
+	if value == nil {
+		return
+	}
+	switch v := value.(type) {
+	case string:
+		_ = binary.Write(&b.values, binary.LittleEndian, uint32(len(v)))
+		b.values.WriteString(v)
+	case int64:
+		_ = binary.Write(&b.values, binary.LittleEndian, v)
+	case float64:
+		_ = binary.Write(&b.values, binary.LittleEndian, math.Float64bits(v))
+	case bool:
+		b.bools = append(b.bools, v)
+	case time.Time:
+		if column.Type == domain.ColumnDate {
+			// タイムゾーンでの暦日を1970-01-01からの日数で表す
+			date := time.Date(v.Year(), v.Month(), v.Day(), 0, 0, 0, 0, time.UTC)
+			_ = binary.Write(&b.values, binary.LittleEndian, int32(date.Unix()/86400))
+		} else {
+			_ = binary.Write(&b.values, binary.LittleEndian, v.UnixMilli())
+		}
+	}
+}
+
+// encodeLevels 定義レベル（0: null、1: 値あり）をビット幅1のRLEで符号化する
+func encodeLevels(defined []bool) []byte {
+	var buf []byte
+	for i := 0; i < len(defined); {
+		run := 1
+		for i+run < len(defined) && defined[i+run] == defined[i] {
+			run++
+		}
+		buf = binary.AppendUvarint(buf, uint64(run)<<1)
+		if defined[i] {
+			buf = append(buf, 1)
+		} else {
+			buf = append(buf, 0)
+		}
+		i += run
+	}
+	return buf
+}
+
+// packBools 真偽値を1ビットずつ下位ビットから詰める
+func packBools(values []bool) []byte {
+	buf := make([]byte, (len(values)+7)/8)
+	for i, v := range values {
+		if v {
+			buf[i/8] |= 1 << (i % 8)
+		}
+	}
+	return buf
+}
@@ -0,0 +1,312 @@
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+var testColumns = []domain.TableColumn{
+	{Name: "id", Type: domain.ColumnString},
+	{Name: "amount", Type: domain.ColumnInt64, Optional: true},
+	{Name: "rate", Type: domain.ColumnDouble},
+	{Name: "refund", Type: domain.ColumnBool},
+	{Name: "created_at", Type: domain.ColumnTimestamp},
+	{Name: "purchase_date", Type: domain.ColumnDate, Optional: true},
+}
+
+func TestWriter(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	created := time.Date(2025, time.March, 1, 9, 30, 0, 0, jst)
+	rows := [][]any{
+		{"r1", int64(1500), 0.1, false, created, time.Date(2025, time.March, 1, 0, 30, 0, 0, jst)},
+		{"r2", nil, 0.08, true, created.Add(time.Second), nil},
+		{"レシート3", int64(-300), 0.0, true, created.Add(time.Minute), time.Date(1969, time.December, 31, 0, 0, 0, 0, time.UTC)},
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, testColumns, map[string]string{"schema_version": "2", "table": "receipts"})
+	w.SetRowGroupSize(2)
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	data := buf.Bytes()
+	meta := readFooter(t, data)
+	if got := meta[3]; got != int64(3) {
+		t.Errorf("num_rows = %v, want 3", got)
+	}
+
+	// スキーマ: ルートと列、Optionalの列はOPTIONAL、変換型（UTF8・TIMESTAMP_MILLIS・DATE）
+	schema := meta[2].([]any)
+	if len(schema) != len(testColumns)+1 || schema[0].(thriftStruct)[5] != int64(len(testColumns)) {
+		t.Fatalf("schema = %v", schema)
+	}
+	wantSchema := []struct {
+		physical, repetition int64
+		converted            any
+	}{
+		{typeByteArray, repetitionRequired, int64(convertedUTF8)},
+		{typeInt64, repetitionOptional, nil},
+		{typeDouble, repetitionRequired, nil},
+		{typeBoolean, repetitionRequired, nil},
+		{typeInt64, repetitionRequired, int64(convertedTimestampMillis)},
+		{typeInt32, repetitionOptional, int64(convertedDate)},
+	}
+	for i, want := range wantSchema {
+		element := schema[i+1].(thriftStruct)
+		if element[4] != testColumns[i].Name || element[1] != want.physical || element[3] != want.repetition || element[6] != want.converted {
+			t.Errorf("schema[%d] = %v, want %+v", i+1, element, want)
+		}
+	}
+
+	// key_value_metadataはキーの順
+	kv := meta[5].([]any)
+	if len(kv) != 2 || kv[0].(thriftStruct)[1] != "schema_version" || kv[0].(thriftStruct)[2] != "2" {
+		t.Errorf("key_value_metadata = %v", kv)
+	}
+
+	// 行グループは2行と1行に分かれ、列ごとに読み戻すと書き込んだ値になる
+	groups := meta[4].([]any)
+	if len(groups) != 2 {
+		t.Fatalf("row groups = %d, want 2", len(groups))
+	}
+	got := make([][]any, 0, len(rows))
+	for _, g := range groups {
+		group := g.(thriftStruct)
+		columns := group[1].([]any)
+		n := int(group[3].(int64))
+		values := make([][]any, len(columns))
+		for j, c := range columns {
+			values[j] = readColumn(t, data, testColumns[j], c.(thriftStruct)[3].(thriftStruct))
+			if len(values[j]) != n {
+				t.Fatalf("column %s values = %d, want %d", testColumns[j].Name, len(values[j]), n)
+			}
+		}
+		for i := range n {
+			row := make([]any, len(columns))
+			for j := range columns {
+				row[j] = values[j][i]
+			}
+			got = append(got, row)
+		}
+	}
+
+	want := [][]any{
+		{"r1", int64(1500), 0.1, false, created.UnixMilli(), int64(20148)},
+		{"r2", nil, 0.08, true, created.UnixMilli() + 1000, nil},
+		{"レシート3", int64(-300), 0.0, true, created.UnixMilli() + 60000, int64(-1)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %v, want %v", got, want)
+	}
+}
+
+func TestWriter_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewWriter(&buf, testColumns, nil).Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	meta := readFooter(t, buf.Bytes())
+	if meta[3] != int64(0) || len(meta[4].([]any)) != 0 {
+		t.Errorf("metadata = %v, want no rows", meta)
+	}
+}
+
+func TestWriter_Errors(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		row  []any
+	}{
+		{name: "too few values", row: []any{"r1"}},
+		{name: "null for required", row: []any{nil, int64(1), 0.1, false, now, now}},
+		{name: "wrong type", row: []any{"r1", 1, 0.1, false, now, now}},
+		{name: "wrong time type", row: []any{"r1", int64(1), 0.1, false, "2025-03-01", now}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWriter(io.Discard, testColumns, nil)
+			if err := w.Write(tt.row); err == nil {
+				t.Error("Write() error = nil")
+			}
+			// 不正な行は追加しない
+			if w.rows != 0 || w.buffers[0].count != 0 {
+				t.Errorf("invalid row was buffered: rows = %d", w.rows)
+			}
+		})
+	}
+
+	w := NewWriter(io.Discard, testColumns, nil)
+	_ = w.Close()
+	if err := w.Write([]any{"r1", nil, 0.1, false, now, nil}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Write() after Close error = %v, want %v", err, ErrClosed)
+	}
+}
+
+func TestEncodeLevels(t *testing.T) {
+	got := encodeLevels([]bool{true, true, true, false, true})
+	want := []byte{3 << 1, 1, 1 << 1, 0, 1 << 1, 1}
+	if !bytes.Equal(got, want) {
+		t.Errorf("encodeLevels() = %v, want %v", got, want)
+	}
+}
+
+// thriftStruct Thrift Compact Protocolで読み込んだ構造体（フィールドIDごとの値）
+type thriftStruct map[int16]any
+
+// readFooter ファイルの識別子を確認し、末尾のメタデータを読み込む
+func readFooter(t *testing.T, data []byte) thriftStruct {
+	t.Helper()
+	if !bytes.HasPrefix(data, []byte(magic)) || !bytes.HasSuffix(data, []byte(magic)) {
+		t.Fatalf("missing magic: %q", data[:min(len(data), 8)])
+	}
+	length := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	return readStruct(t, bytes.NewReader(data[len(data)-8-length:len(data)-8]))
+}
+
+// readColumn 列のチャンクのデータページを読み込んで値を返す（日付は日数、日時はミリ秒）
+func readColumn(t *testing.T, data []byte, column domain.TableColumn, meta thriftStruct) []any {
+	t.Helper()
+	r := bytes.NewReader(data[meta[9].(int64):])
+	header := readStruct(t, r)
+	page := header[5].(thriftStruct)
+	compressed := make([]byte, header[3].(int64))
+	if _, err := io.ReadFull(r, compressed); err != nil {
+		t.Fatalf("failed to read page: %v", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+	content, err := io.ReadAll(zr)
+	if err != nil || int64(len(content)) != header[2].(int64) {
+		t.Fatalf("page content = %d bytes, err = %v", len(content), err)
+	}
+
+	n := int(page[1].(int64))
+	defined := make([]bool, n)
+	for i := range defined {
+		defined[i] = true
+	}
+	if column.Optional {
+		length := int(binary.LittleEndian.Uint32(content))
+		levels := bytes.NewReader(content[4 : 4+length])
+		for i := 0; i < n; {
+			run, _ := binary.ReadUvarint(levels)
+			value, _ := levels.ReadByte()
+			for range run >> 1 {
+				defined[i] = value == 1
+				i++
+			}
+		}
+		content = content[4+length:]
+	}
+
+	values := make([]any, n)
+	r = bytes.NewReader(content)
+	bit := 0
+	for i := range n {
+		if !defined[i] {
+			continue
+		}
+		switch column.Type {
+		case domain.ColumnString:
+			var length uint32
+			_ = binary.Read(r, binary.LittleEndian, &length)
+			s := make([]byte, length)
+			_, _ = io.ReadFull(r, s)
+			values[i] = string(s)
+		case domain.ColumnInt64, domain.ColumnTimestamp:
+			var v int64
+			_ = binary.Read(r, binary.LittleEndian, &v)
+			values[i] = v
+		case domain.ColumnDouble:
+			var v uint64
+			_ = binary.Read(r, binary.LittleEndian, &v)
+			values[i] = math.Float64frombits(v)
+		case domain.ColumnDate:
+			var v int32
+			_ = binary.Read(r, binary.LittleEndian, &v)
+			values[i] = int64(v)
+		case domain.ColumnBool:
+			values[i] = content[bit/8]&(1<<(bit%8)) != 0
+			bit++
+		}
+	}
+	return values
+}
+
+// readStruct Thrift Compact Protocolの構造体を読み込む
+func readStruct(t *testing.T, r *bytes.Reader) thriftStruct {
+	t.Helper()
+	s := thriftStruct{}
+	var last int16
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			t.Fatalf("unexpected end of struct: %v", err)
+		}
+		if b == 0 {
+			return s
+		}
+		id := last + int16(b>>4)
+		if b>>4 == 0 {
+			v, _ := binary.ReadUvarint(r)
+			id = int16(unzigzag(v))
+		}
+		last = id
+		s[id] = readValue(t, r, b&0x0f)
+	}
+}
+
+// readValue Thrift Compact Protocolの値を読み込む（整数はint64、文字列はstring）
+func readValue(t *testing.T, r *bytes.Reader, typ byte) any {
+	t.Helper()
+	switch typ {
+	case compactTrue:
+		return true
+	case compactFalse:
+		return false
+	case compactI32, compactI64:
+		v, _ := binary.ReadUvarint(r)
+		return unzigzag(v)
+	case compactBinary:
+		length, _ := binary.ReadUvarint(r)
+		b := make([]byte, length)
+		_, _ = io.ReadFull(r, b)
+		return string(b)
+	case compactList:
+		h, _ := r.ReadByte()
+		size := uint64(h >> 4)
+		if size == 15 {
+			size, _ = binary.ReadUvarint(r)
+		}
+		list := make([]any, 0, size)
+		for range size {
+			list = append(list, readValue(t, r, h&0x0f))
+		}
+		return list
+	case compactStruct:
+		return readStruct(t, r)
+	}
+	t.Fatalf("unsupported thrift type %d", typ)
+	return nil
+}
+
+func unzigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
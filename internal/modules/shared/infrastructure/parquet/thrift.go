@@ -0,0 +1,137 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift Compact Protocolの型
+const (
+	compactTrue   = 1
+	compactFalse  = 2
+	compactI32    = 5
+	compactI64    = 6
+	compactBinary = 8
+	compactList   = 9
+	compactStruct = 12
+)
+
+// compactWriter ParquetのメタデータをThrift Compact Protocolで書き出す
+// 構造体ごとに直前のフィールドIDを保持し、差分が1〜15の場合は型と同じ1バイトにまとめる
+type compactWriter struct {
+	buf     bytes.Buffer
+	lastIDs []int16
+}
+
+// newCompactWriter 新しいcompactWriterを作成（最上位の構造体のフィールドを書き出せる状態にする）
+func newCompactWriter() *compactWriter {
+	return &compactWriter{lastIDs: []int16{0}}
+}
+
+// bytes 最上位の構造体を閉じて書き出した内容を返す
+func (c *compactWriter) bytes() []byte {
+	c.buf.WriteByte(0)
+	return c.buf.Bytes()
+}
+
+// fieldHeader フィールドのIDと型を書き出す
+func (c *compactWriter) fieldHeader(id int16, typ byte) {
+	last := &c.lastIDs[len(c.lastIDs)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		c.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		c.buf.WriteByte(typ)
+		c.varint(zigzag(int64(id)))
+	}
+	*last = id
+}
+
+// i32 32ビット整数のフィールドを書き出す
+func (c *compactWriter) i32(id int16, v int32) {
+	c.fieldHeader(id, compactI32)
+	c.varint(zigzag(int64(v)))
+}
+
+// i64 64ビット整数のフィールドを書き出す
+func (c *compactWriter) i64(id int16, v int64) {
+	c.fieldHeader(id, compactI64)
+	c.varint(zigzag(v))
+}
+
+// bool 真偽値のフィールドを書き出す（値は型に含める）
+func (c *compactWriter) bool(id int16, v bool) {
+	if v {
+		c.fieldHeader(id, compactTrue)
+	} else {
+		c.fieldHeader(id, compactFalse)
+	}
+}
+
+// string 文字列のフィールドを書き出す
+func (c *compactWriter) string(id int16, s string) {
+	c.fieldHeader(id, compactBinary)
+	c.varint(uint64(len(s)))
+	c.buf.WriteString(s)
+}
+
+// structField 構造体のフィールドを書き出す（fnで構造体のフィールドを書き出す）
+func (c *compactWriter) structField(id int16, fn func()) {
+	c.fieldHeader(id, compactStruct)
+	c.nested(fn)
+}
+
+// structList 構造体のリストのフィールドを書き出す（fnでi番目の構造体のフィールドを書き出す）
+func (c *compactWriter) structList(id int16, n int, fn func(i int)) {
+	c.fieldHeader(id, compactList)
+	c.listHeader(n, compactStruct)
+	for i := range n {
+		c.nested(func() { fn(i) })
+	}
+}
+
+// i32List 32ビット整数のリストのフィールドを書き出す
+func (c *compactWriter) i32List(id int16, values []int32) {
+	c.fieldHeader(id, compactList)
+	c.listHeader(len(values), compactI32)
+	for _, v := range values {
+		c.varint(zigzag(int64(v)))
+	}
+}
+
+// stringList 文字列のリストのフィールドを書き出す
+func (c *compactWriter) stringList(id int16, values []string) {
+	c.fieldHeader(id, compactList)
+	c.listHeader(len(values), compactBinary)
+	for _, s := range values {
+		c.varint(uint64(len(s)))
+		c.buf.WriteString(s)
+	}
+}
+
+// nested 入れ子の構造体のフィールドを書き出して閉じる
+func (c *compactWriter) nested(fn func()) {
+	c.lastIDs = append(c.lastIDs, 0)
+	fn()
+	c.buf.WriteByte(0)
+	c.lastIDs = c.lastIDs[:len(c.lastIDs)-1]
+}
+
+// listHeader リストの要素数と要素の型を書き出す
+func (c *compactWriter) listHeader(n int, typ byte) {
+	if n < 15 {
+		c.buf.WriteByte(byte(n)<<4 | typ)
+		return
+	}
+	c.buf.WriteByte(0xf0 | typ)
+	c.varint(uint64(n))
+}
+
+// varint 可変長の符号なし整数を書き出す
+func (c *compactWriter) varint(v uint64) {
+	c.buf.Write(binary.AppendUvarint(nil, v))
+}
+
+// zigzag 符号付き整数をZigZag符号化する
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
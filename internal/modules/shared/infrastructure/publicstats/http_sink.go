@@ -0,0 +1,90 @@
+package publicstats
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	analytics "vision-api-app/internal/modules/analytics/domain"
+)
+
+// DefaultTimeout 集計値の送信のデフォルトのタイムアウト
+const DefaultTimeout = 10 * time.Second
+
+// contributionsPath 統計を集計するサーバーの集計値の受付API
+const contributionsPath = "/api/v1/public/stats/contributions"
+
+// HTTPSink この世帯の集計値を統計を集計するサーバー（別のvision-api-app）のAPIへPOSTする
+type HTTPSink struct {
+	endpoint string
+	apiKey   string
+	client   *http.Client
+}
+
+// NewHTTPSink 新しいHTTPSinkを作成（endpointは集計するサーバーのベースURL）
+func NewHTTPSink(endpoint, apiKey string, timeout time.Duration) *HTTPSink {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &HTTPSink{
+		endpoint: strings.TrimRight(endpoint, "/"),
+		apiKey:   apiKey,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+// SetTransport 外部への接続に使うTransportを設定（プロキシ・追加のルート証明書など、未設定の場合はhttp.DefaultTransport）
+func (s *HTTPSink) SetTransport(transport http.RoundTripper) {
+	s.client.Transport = transport
+}
+
+// contributionRequest 集計するサーバーに送る集計値（地域・月・合計金額のみ）
+type contributionRequest struct {
+	ContributorID string `json:"contributor_id"`
+	Region        string `json:"region"`
+	Month         string `json:"month"`
+	Amount        int64  `json:"amount"`
+	Currency      string `json:"currency"`
+}
+
+// Contribute 集計値を送信（2xx以外の応答はエラー）
+func (s *HTTPSink) Contribute(ctx context.Context, contribution *analytics.SpendContribution) error {
+	body, err := json.Marshal(contributionRequest{
+		ContributorID: contribution.ContributorID,
+		Region:        contribution.Region,
+		Month:         contribution.Month,
+		Amount:        contribution.Amount,
+		Currency:      contribution.Currency,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal spend contribution: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint+contributionsPath, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create contribution request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("X-API-Key", s.apiKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send spend contribution: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("stats server returned status %d: %s", resp.StatusCode, body)
+	}
+	return nil
+}
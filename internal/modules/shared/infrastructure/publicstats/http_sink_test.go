@@ -0,0 +1,71 @@
+package publicstats
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	analytics "vision-api-app/internal/modules/analytics/domain"
+)
+
+func TestHTTPSink_Contribute(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/public/stats/contributions" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if r.Header.Get("X-API-Key") != "key1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	contribution := &analytics.SpendContribution{
+		ContributorID: "household-0000001",
+		Region:        "東京都",
+		Month:         "2024-03",
+		Amount:        45000,
+		Currency:      "JPY",
+		UpdatedAt:     time.Now(),
+	}
+
+	tests := []struct {
+		name    string
+		apiKey  string
+		wantErr bool
+	}{
+		{name: "正常系: 送信", apiKey: "key1"},
+		{name: "異常系: APIキーの誤り", apiKey: "wrong", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received = nil
+			err := NewHTTPSink(server.URL+"/", tt.apiKey, 5*time.Second).Contribute(context.Background(), contribution)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Contribute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			// 地域・月・合計金額のみを送る
+			want := map[string]any{"contributor_id": "household-0000001", "region": "東京都", "month": "2024-03", "amount": float64(45000), "currency": "JPY"}
+			if len(received) != len(want) {
+				t.Fatalf("received = %v, want %v", received, want)
+			}
+			for key, value := range want {
+				if received[key] != value {
+					t.Errorf("received[%s] = %v, want %v", key, received[key], value)
+				}
+			}
+		})
+	}
+}
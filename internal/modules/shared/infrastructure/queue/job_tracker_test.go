@@ -0,0 +1,73 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+// testJobTracker 記録先の実装に共通する振る舞いを確認する
+func testJobTracker(t *testing.T, tracker domain.JobTracker) {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Date(2025, time.June, 1, 10, 0, 0, 0, time.UTC)
+
+	if _, err := tracker.Get(ctx, "missing"); !errors.Is(err, domain.ErrJobNotFound) {
+		t.Errorf("Get() error = %v, want ErrJobNotFound", err)
+	}
+
+	newer := &domain.JobStatus{ID: "job-2", Type: "backfill", State: domain.JobStateRunning, Total: 3, CreatedAt: now.Add(time.Minute)}
+	older := &domain.JobStatus{ID: "job-1", Type: "backfill", State: domain.JobStateRunning, Total: 2, Data: []byte(`{"batches":["b-1"]}`), CreatedAt: now}
+	other := &domain.JobStatus{ID: "job-3", Type: "other", State: domain.JobStateRunning, CreatedAt: now}
+	for _, status := range []*domain.JobStatus{newer, older, other} {
+		if err := tracker.Save(ctx, status); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	running, err := tracker.ListRunning(ctx, "backfill")
+	if err != nil {
+		t.Fatalf("ListRunning() error = %v", err)
+	}
+	if len(running) != 2 || running[0].ID != "job-1" || running[1].ID != "job-2" {
+		t.Fatalf("ListRunning() = %+v, want job-1, job-2", running)
+	}
+	if string(running[0].Data) != `{"batches":["b-1"]}` {
+		t.Errorf("Data = %s", running[0].Data)
+	}
+
+	// 終わったジョブは処理中の一覧から外れるが、状態は取得できる
+	older.State = domain.JobStateCompleted
+	older.Succeeded = 2
+	if err := tracker.Save(ctx, older); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	running, err = tracker.ListRunning(ctx, "backfill")
+	if err != nil {
+		t.Fatalf("ListRunning() error = %v", err)
+	}
+	if len(running) != 1 || running[0].ID != "job-2" {
+		t.Errorf("ListRunning() = %+v, want job-2", running)
+	}
+	got, err := tracker.Get(ctx, "job-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.State != domain.JobStateCompleted || got.Succeeded != 2 || !got.CreatedAt.Equal(now) {
+		t.Errorf("Get() = %+v", got)
+	}
+}
+
+func TestMemoryJobTracker(t *testing.T) {
+	testJobTracker(t, NewMemoryJobTracker())
+}
+
+func TestRedisJobTracker(t *testing.T) {
+	queues, cleanup := setupRedisStreamQueues(t, 1)
+	defer cleanup()
+
+	testJobTracker(t, queues[0].Tracker())
+}
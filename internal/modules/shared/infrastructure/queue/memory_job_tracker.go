@@ -0,0 +1,64 @@
+package queue
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+// MemoryJobTracker プロセス内のジョブの進み具合の記録先（MemoryQueueと組み合わせる、再起動すると失われる）
+type MemoryJobTracker struct {
+	mu       sync.Mutex
+	statuses map[string]domain.JobStatus
+}
+
+// NewMemoryJobTracker 新しいMemoryJobTrackerを作成
+func NewMemoryJobTracker() *MemoryJobTracker {
+	return &MemoryJobTracker{statuses: make(map[string]domain.JobStatus)}
+}
+
+// Save 状態を保存（同じIDの状態は上書き）
+func (t *MemoryJobTracker) Save(ctx context.Context, status *domain.JobStatus) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	saved := *status
+	saved.Data = slices.Clone(status.Data)
+	t.statuses[status.ID] = saved
+	return nil
+}
+
+// Get 状態を取得
+func (t *MemoryJobTracker) Get(ctx context.Context, id string) (*domain.JobStatus, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	status, ok := t.statuses[id]
+	if !ok {
+		return nil, domain.ErrJobNotFound
+	}
+	status.Data = slices.Clone(status.Data)
+	return &status, nil
+}
+
+// ListRunning 処理中のジョブを作成日時の古い順に取得
+func (t *MemoryJobTracker) ListRunning(ctx context.Context, jobType string) ([]*domain.JobStatus, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var running []*domain.JobStatus
+	for _, status := range t.statuses {
+		if status.Type == jobType && !status.Done() {
+			status.Data = slices.Clone(status.Data)
+			running = append(running, &status)
+		}
+	}
+	sortByCreatedAt(running)
+	return running, nil
+}
+
+// sortByCreatedAt ジョブの状態を作成日時の古い順に並べる
+func sortByCreatedAt(statuses []*domain.JobStatus) {
+	slices.SortFunc(statuses, func(a, b *domain.JobStatus) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+}
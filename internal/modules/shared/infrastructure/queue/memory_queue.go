@@ -0,0 +1,187 @@
+package queue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+const (
+	// DefaultWorkers デフォルトのワーカー数
+	DefaultWorkers = 2
+	// DefaultBufferSize デフォルトのキューバッファサイズ
+	DefaultBufferSize = 100
+)
+
+// MemoryQueue プロセス内ジョブキュー実装
+type MemoryQueue struct {
+	jobs chan *domain.Job
+	wg   sync.WaitGroup
+
+	mu       sync.Mutex
+	handlers map[string]domain.JobHandler
+	pending  int
+	waiters  []chan struct{}
+	closed   bool
+}
+
+// NewMemoryQueue 新しいMemoryQueueを作成してワーカーを起動
+func NewMemoryQueue(workers, bufferSize int) *MemoryQueue {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+
+	q := &MemoryQueue{
+		jobs:     make(chan *domain.Job, bufferSize),
+		handlers: make(map[string]domain.JobHandler),
+	}
+
+	for i := 0; i < workers; i++ {
+		q.wg.Add(1)
+		go q.worker()
+	}
+
+	return q
+}
+
+// Register ジョブ種別に対する処理関数を登録
+func (q *MemoryQueue) Register(jobType string, handler domain.JobHandler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[jobType] = handler
+}
+
+// Start 何もしない（プロセス内のジョブは処理関数の登録後にしか投入できないため、ワーカーは作成時に起動する）
+func (q *MemoryQueue) Start() {}
+
+// Enqueue ジョブを投入
+func (q *MemoryQueue) Enqueue(ctx context.Context, jobType string, payload []byte) error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return domain.ErrQueueClosed
+	}
+	if _, ok := q.handlers[jobType]; !ok {
+		q.mu.Unlock()
+		return fmt.Errorf("no handler registered for job type: %s", jobType)
+	}
+	q.pending++
+	q.mu.Unlock()
+
+	job := &domain.Job{
+		ID:         newJobID(),
+		Type:       jobType,
+		Payload:    payload,
+		EnqueuedAt: time.Now(),
+	}
+
+	select {
+	case q.jobs <- job:
+		return nil
+	case <-ctx.Done():
+		q.done()
+		return fmt.Errorf("failed to enqueue job: %w", ctx.Err())
+	}
+}
+
+// Flush 投入済みジョブがすべて完了するまで待機
+func (q *MemoryQueue) Flush(ctx context.Context) error {
+	q.mu.Lock()
+	if q.pending == 0 {
+		q.mu.Unlock()
+		return nil
+	}
+	idle := make(chan struct{})
+	q.waiters = append(q.waiters, idle)
+	q.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to flush job queue: %w", ctx.Err())
+	}
+}
+
+// Close 新規投入を停止し、投入済みジョブの完了を待ってワーカーを終了
+func (q *MemoryQueue) Close(ctx context.Context) error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return nil
+	}
+	q.closed = true
+	q.mu.Unlock()
+
+	if err := q.Flush(ctx); err != nil {
+		return err
+	}
+
+	close(q.jobs)
+	q.wg.Wait()
+	return nil
+}
+
+// Pending 未完了のジョブ数を返す
+func (q *MemoryQueue) Pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.pending
+}
+
+// worker ジョブを取り出して処理
+func (q *MemoryQueue) worker() {
+	defer q.wg.Done()
+
+	for job := range q.jobs {
+		q.process(job)
+	}
+}
+
+// process 1件のジョブを処理（パニックはログに記録して継続）
+func (q *MemoryQueue) process(job *domain.Job) {
+	defer q.done()
+	defer func() {
+		if err := recover(); err != nil {
+			slog.Error("Job panicked", "job_id", job.ID, "type", job.Type, "error", err)
+		}
+	}()
+
+	q.mu.Lock()
+	handler := q.handlers[job.Type]
+	q.mu.Unlock()
+
+	if err := handler(context.Background(), job); err != nil {
+		slog.Error("Job failed", "job_id", job.ID, "type", job.Type, "error", err)
+	}
+}
+
+// done 未完了ジョブ数を減らし、0件になったら待機者に通知
+func (q *MemoryQueue) done() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.pending--
+	if q.pending == 0 {
+		for _, waiter := range q.waiters {
+			close(waiter)
+		}
+		q.waiters = nil
+	}
+}
+
+// newJobID ランダムなジョブIDを生成
+func newJobID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
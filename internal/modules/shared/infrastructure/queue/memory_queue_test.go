@@ -0,0 +1,175 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+func TestMemoryQueue_Flush(t *testing.T) {
+	q := NewMemoryQueue(2, 10)
+	defer func() {
+		_ = q.Close(context.Background())
+	}()
+
+	var processed int32
+	q.Register("count", func(ctx context.Context, job *domain.Job) error {
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&processed, 1)
+		return nil
+	})
+
+	for i := 0; i < 5; i++ {
+		if err := q.Enqueue(context.Background(), "count", nil); err != nil {
+			t.Fatalf("Enqueue() error = %v", err)
+		}
+	}
+
+	if err := q.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	if got := atomic.LoadInt32(&processed); got != 5 {
+		t.Errorf("processed = %d, want 5", got)
+	}
+	if q.Pending() != 0 {
+		t.Errorf("Pending() = %d, want 0", q.Pending())
+	}
+}
+
+func TestMemoryQueue_FlushEmpty(t *testing.T) {
+	q := NewMemoryQueue(1, 1)
+	defer func() {
+		_ = q.Close(context.Background())
+	}()
+
+	if err := q.Flush(context.Background()); err != nil {
+		t.Errorf("Flush() error = %v", err)
+	}
+}
+
+func TestMemoryQueue_FlushTimeout(t *testing.T) {
+	q := NewMemoryQueue(1, 1)
+
+	release := make(chan struct{})
+	q.Register("block", func(ctx context.Context, job *domain.Job) error {
+		<-release
+		return nil
+	})
+
+	if err := q.Enqueue(context.Background(), "block", nil); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := q.Flush(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Flush() error = %v, want DeadlineExceeded", err)
+	}
+
+	close(release)
+	if err := q.Close(context.Background()); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
+
+func TestMemoryQueue_Close(t *testing.T) {
+	q := NewMemoryQueue(1, 10)
+
+	var processed int32
+	q.Register("count", func(ctx context.Context, job *domain.Job) error {
+		atomic.AddInt32(&processed, 1)
+		return nil
+	})
+
+	for i := 0; i < 3; i++ {
+		if err := q.Enqueue(context.Background(), "count", nil); err != nil {
+			t.Fatalf("Enqueue() error = %v", err)
+		}
+	}
+
+	if err := q.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// クローズ前に投入されたジョブはすべて処理される
+	if got := atomic.LoadInt32(&processed); got != 3 {
+		t.Errorf("processed = %d, want 3", got)
+	}
+
+	if err := q.Enqueue(context.Background(), "count", nil); !errors.Is(err, domain.ErrQueueClosed) {
+		t.Errorf("Enqueue() after Close error = %v, want ErrQueueClosed", err)
+	}
+}
+
+func TestMemoryQueue_UnknownJobType(t *testing.T) {
+	q := NewMemoryQueue(1, 1)
+	defer func() {
+		_ = q.Close(context.Background())
+	}()
+
+	if err := q.Enqueue(context.Background(), "unknown", nil); err == nil {
+		t.Error("Expected error for unregistered job type")
+	}
+	if q.Pending() != 0 {
+		t.Errorf("Pending() = %d, want 0", q.Pending())
+	}
+}
+
+func TestMemoryQueue_HandlerErrorAndPanic(t *testing.T) {
+	q := NewMemoryQueue(1, 10)
+	defer func() {
+		_ = q.Close(context.Background())
+	}()
+
+	q.Register("fail", func(ctx context.Context, job *domain.Job) error {
+		return errors.New("job error")
+	})
+	q.Register("panic", func(ctx context.Context, job *domain.Job) error {
+		panic("job panic")
+	})
+
+	if err := q.Enqueue(context.Background(), "fail", nil); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	if err := q.Enqueue(context.Background(), "panic", nil); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	// 失敗やパニックがあってもFlushは完了する
+	if err := q.Flush(context.Background()); err != nil {
+		t.Errorf("Flush() error = %v", err)
+	}
+}
+
+func TestMemoryQueue_Payload(t *testing.T) {
+	q := NewMemoryQueue(1, 1)
+	defer func() {
+		_ = q.Close(context.Background())
+	}()
+
+	var got string
+	q.Register("echo", func(ctx context.Context, job *domain.Job) error {
+		got = string(job.Payload)
+		if job.ID == "" {
+			t.Error("Expected job ID to be set")
+		}
+		return nil
+	})
+
+	if err := q.Enqueue(context.Background(), "echo", []byte("payload")); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	if err := q.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	if got != "payload" {
+		t.Errorf("payload = %s, want payload", got)
+	}
+}
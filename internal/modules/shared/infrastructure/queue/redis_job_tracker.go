@@ -0,0 +1,98 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+// jobStatusRetention 終わったジョブの状態を保持する期間
+const jobStatusRetention = 30 * 24 * time.Hour
+
+// RedisJobTracker Redisのジョブの進み具合の記録先（RedisStreamQueueと組み合わせ、すべてのインスタンスで共有する）
+// 状態は <stream>:status:<ID> にJSONで保存し、処理中のジョブのIDは <stream>:running:<種類> の集合で管理する
+type RedisJobTracker struct {
+	client *redis.Client
+	prefix string
+}
+
+// Tracker キューと同じRedisに保存するジョブの進み具合の記録先を取得
+func (q *RedisStreamQueue) Tracker() *RedisJobTracker {
+	return &RedisJobTracker{client: q.client, prefix: q.stream}
+}
+
+// Save 状態を保存（同じIDの状態は上書き、終わったジョブの状態は保持期間を過ぎると消える）
+func (t *RedisJobTracker) Save(ctx context.Context, status *domain.JobStatus) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job status: %w", err)
+	}
+
+	pipe := t.client.TxPipeline()
+	if status.Done() {
+		pipe.Set(ctx, t.statusKey(status.ID), data, jobStatusRetention)
+		pipe.SRem(ctx, t.runningKey(status.Type), status.ID)
+	} else {
+		pipe.Set(ctx, t.statusKey(status.ID), data, 0)
+		pipe.SAdd(ctx, t.runningKey(status.Type), status.ID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to save job status: %w", err)
+	}
+	return nil
+}
+
+// Get 状態を取得
+func (t *RedisJobTracker) Get(ctx context.Context, id string) (*domain.JobStatus, error) {
+	data, err := t.client.Get(ctx, t.statusKey(id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, domain.ErrJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job status: %w", err)
+	}
+
+	var status domain.JobStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal job status: %w", err)
+	}
+	return &status, nil
+}
+
+// ListRunning 処理中のジョブを作成日時の古い順に取得
+func (t *RedisJobTracker) ListRunning(ctx context.Context, jobType string) ([]*domain.JobStatus, error) {
+	ids, err := t.client.SMembers(ctx, t.runningKey(jobType)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list running jobs: %w", err)
+	}
+
+	var running []*domain.JobStatus
+	for _, id := range ids {
+		status, err := t.Get(ctx, id)
+		if errors.Is(err, domain.ErrJobNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !status.Done() {
+			running = append(running, status)
+		}
+	}
+	sortByCreatedAt(running)
+	return running, nil
+}
+
+func (t *RedisJobTracker) statusKey(id string) string {
+	return t.prefix + ":status:" + id
+}
+
+func (t *RedisJobTracker) runningKey(jobType string) string {
+	return t.prefix + ":running:" + jobType
+}
@@ -0,0 +1,422 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"vision-api-app/internal/config"
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+const (
+	// DefaultStream デフォルトのストリーム名
+	DefaultStream = "vision:jobs"
+	// DefaultGroup デフォルトのコンシューマーグループ名
+	DefaultGroup = "vision-workers"
+	// DefaultMaxDeliveries ジョブをデッドレターに移すまでの最大配信回数のデフォルト
+	DefaultMaxDeliveries = 5
+	// deadLetterSuffix 配信回数の上限を超えたジョブを移すストリーム名の接尾辞
+	deadLetterSuffix = ":dead"
+
+	// readBlock 新しいジョブを待つ最大時間（Close時の応答性のため短めにする）
+	readBlock = 2 * time.Second
+	// claimInterval 停止したインスタンスのジョブを引き取る間隔
+	claimInterval = time.Minute
+	// claimMinIdle 処理中のまま放置されたジョブとみなすまでの時間
+	claimMinIdle = 5 * time.Minute
+	// claimCount 1回の引き取りで確認する処理中のジョブの件数
+	claimCount = 10
+	// flushPollInterval Flush時にストリームの残件数を確認する間隔
+	flushPollInterval = 100 * time.Millisecond
+)
+
+// RedisStreamQueue Redis Streamsによるジョブキュー実装
+// 同じコンシューマーグループに参加するすべてのインスタンスでジョブを分担して処理する
+type RedisStreamQueue struct {
+	client        *redis.Client
+	stream        string
+	group         string
+	consumer      string
+	workers       int
+	maxDeliveries int64
+	minIdle       time.Duration // 処理中のまま放置されたジョブとみなすまでの時間（テストで短くする）
+	claimStart    string        // 次に引き取りを確認する処理中のジョブのID（claimerのみが使う）
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu       sync.Mutex
+	handlers map[string]domain.JobHandler
+	started  bool
+	closed   bool
+}
+
+// NewRedisStreamQueue 新しいRedisStreamQueueを作成
+// ワーカーは処理関数を登録した後のStartで起動する
+func NewRedisStreamQueue(redisCfg *config.RedisConfig, queueCfg *config.QueueConfig) (*RedisStreamQueue, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", redisCfg.Host, redisCfg.Port),
+		Password: redisCfg.Password,
+		DB:       redisCfg.DB,
+	})
+
+	// 接続確認
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	q, err := NewRedisStreamQueueWithClient(client, queueCfg)
+	if err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+	return q, nil
+}
+
+// NewRedisStreamQueueWithClient 既存のRedisクライアントでRedisStreamQueueを作成（テスト用）
+func NewRedisStreamQueueWithClient(client *redis.Client, queueCfg *config.QueueConfig) (*RedisStreamQueue, error) {
+	stream := queueCfg.Stream
+	if stream == "" {
+		stream = DefaultStream
+	}
+	group := queueCfg.Group
+	if group == "" {
+		group = DefaultGroup
+	}
+	workers := queueCfg.Workers
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	maxDeliveries := queueCfg.MaxDeliveries
+	if maxDeliveries <= 0 {
+		maxDeliveries = DefaultMaxDeliveries
+	}
+
+	// グループ作成前に投入されたジョブも処理できるよう先頭から読む
+	err := client.XGroupCreateMkStream(context.Background(), stream, group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil, fmt.Errorf("failed to create consumer group: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &RedisStreamQueue{
+		client:        client,
+		stream:        stream,
+		group:         group,
+		consumer:      newConsumerName(),
+		workers:       workers,
+		maxDeliveries: int64(maxDeliveries),
+		minIdle:       claimMinIdle,
+		claimStart:    "0-0",
+		ctx:           ctx,
+		cancel:        cancel,
+		handlers:      make(map[string]domain.JobHandler),
+	}
+	return q, nil
+}
+
+// Start ワーカーと停止したインスタンスのジョブの引き取りを起動
+// 前回の起動時の未処理のジョブもすぐに受信するため、処理関数をすべて登録してから呼び出す
+func (q *RedisStreamQueue) Start() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.started || q.closed {
+		return
+	}
+	q.started = true
+
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go q.worker(fmt.Sprintf("%s-%d", q.consumer, i))
+	}
+	q.wg.Add(1)
+	go q.claimer()
+}
+
+// Register ジョブ種別に対する処理関数を登録
+func (q *RedisStreamQueue) Register(jobType string, handler domain.JobHandler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[jobType] = handler
+}
+
+// Enqueue ジョブをストリームに追加
+func (q *RedisStreamQueue) Enqueue(ctx context.Context, jobType string, payload []byte) error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return domain.ErrQueueClosed
+	}
+	if _, ok := q.handlers[jobType]; !ok {
+		q.mu.Unlock()
+		return fmt.Errorf("no handler registered for job type: %s", jobType)
+	}
+	q.mu.Unlock()
+
+	err := q.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: q.stream,
+		Values: map[string]interface{}{
+			"id":          newJobID(),
+			"type":        jobType,
+			"payload":     payload,
+			"enqueued_at": time.Now().UnixMilli(),
+		},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to enqueue job: %w", err)
+	}
+	return nil
+}
+
+// Flush ストリーム上のジョブがすべて処理されるまで待機
+// 他のインスタンスが処理するジョブも含めて待つ
+func (q *RedisStreamQueue) Flush(ctx context.Context) error {
+	ticker := time.NewTicker(flushPollInterval)
+	defer ticker.Stop()
+
+	for {
+		n, err := q.client.XLen(ctx, q.stream).Result()
+		if err != nil && ctx.Err() == nil {
+			return fmt.Errorf("failed to check stream length: %w", err)
+		}
+		if err == nil && n == 0 {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return fmt.Errorf("failed to flush job queue: %w", ctx.Err())
+		}
+	}
+}
+
+// Close 新規投入と受信を停止し、処理中のジョブの完了を待ってワーカーを終了
+// 未処理のジョブはストリームに残り、他のインスタンスまたは次回起動時に処理される
+func (q *RedisStreamQueue) Close(ctx context.Context) error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return nil
+	}
+	q.closed = true
+	q.mu.Unlock()
+
+	q.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("failed to close job queue: %w", ctx.Err())
+	}
+
+	return q.client.Close()
+}
+
+// worker コンシューマーグループからジョブを受信して処理
+func (q *RedisStreamQueue) worker(consumer string) {
+	defer q.wg.Done()
+
+	for q.ctx.Err() == nil {
+		streams, err := q.client.XReadGroup(q.ctx, &redis.XReadGroupArgs{
+			Group:    q.group,
+			Consumer: consumer,
+			Streams:  []string{q.stream, ">"},
+			Count:    1,
+			Block:    readBlock,
+		}).Result()
+		if errors.Is(err, redis.Nil) || q.ctx.Err() != nil {
+			continue
+		}
+		if err != nil {
+			slog.Error("Failed to read job stream", "stream", q.stream, "error", err)
+			time.Sleep(time.Second)
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, message := range stream.Messages {
+				q.process(message)
+			}
+		}
+	}
+}
+
+// claimer 停止したインスタンスが処理中のまま残したジョブを定期的に引き取って処理
+func (q *RedisStreamQueue) claimer() {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(claimInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-q.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		q.claim()
+	}
+}
+
+// claim 処理中のまま放置されたジョブを前回の続きから引き取って処理
+// 配信回数が上限を超えたジョブ（処理のたびにインスタンスが停止する・処理関数がどのインスタンスにもないなど）は処理せずデッドレターに移す
+func (q *RedisStreamQueue) claim() {
+	messages, next, err := q.client.XAutoClaim(q.ctx, &redis.XAutoClaimArgs{
+		Stream:   q.stream,
+		Group:    q.group,
+		MinIdle:  q.minIdle,
+		Start:    q.claimStart,
+		Count:    claimCount,
+		Consumer: q.consumer,
+	}).Result()
+	if err != nil {
+		if q.ctx.Err() == nil {
+			slog.Error("Failed to claim stale jobs", "stream", q.stream, "error", err)
+		}
+		return
+	}
+	// 最後まで確認すると0-0が返り、次回は先頭から確認する
+	q.claimStart = next
+
+	for _, message := range messages {
+		deliveries, err := q.deliveries(message.ID)
+		if err != nil {
+			slog.Error("Failed to get job delivery count", "message_id", message.ID, "error", err)
+			continue
+		}
+		if deliveries > q.maxDeliveries {
+			q.deadLetter(message, deliveries)
+			continue
+		}
+		slog.Warn("Claimed stale job", "message_id", message.ID, "deliveries", deliveries)
+		q.process(message)
+	}
+}
+
+// deliveries 処理中のジョブの配信回数（XPENDINGの配信回数、引き取るたびに増える）
+func (q *RedisStreamQueue) deliveries(messageID string) (int64, error) {
+	pending, err := q.client.XPendingExt(q.ctx, &redis.XPendingExtArgs{
+		Stream: q.stream,
+		Group:  q.group,
+		Start:  messageID,
+		End:    messageID,
+		Count:  1,
+	}).Result()
+	if err != nil {
+		return 0, err
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+	return pending[0].RetryCount, nil
+}
+
+// deadLetter 配信回数の上限を超えたジョブをデッドレターのストリームに移し、元のストリームからは確認応答して削除
+// デッドレターのジョブは自動では処理しないため、原因を調べてから手動で投入し直す
+func (q *RedisStreamQueue) deadLetter(message redis.XMessage, deliveries int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	values := make(map[string]interface{}, len(message.Values)+2)
+	for k, v := range message.Values {
+		values[k] = v
+	}
+	values["message_id"] = message.ID
+	values["deliveries"] = deliveries
+
+	deadStream := q.stream + deadLetterSuffix
+	if err := q.client.XAdd(ctx, &redis.XAddArgs{Stream: deadStream, Values: values}).Err(); err != nil {
+		slog.Error("Failed to dead-letter job", "message_id", message.ID, "error", err)
+		return
+	}
+	slog.Error("Job exceeded max deliveries, moved to dead letter stream", "message_id", message.ID, "type", message.Values["type"], "deliveries", deliveries, "stream", deadStream)
+	q.ack(message.ID)
+}
+
+// process 1件のジョブを処理して確認応答（失敗・パニックはログに記録して破棄）
+// 処理関数が未登録のジョブは確認応答せずに保留のまま残し、処理関数を登録したインスタンスに引き取らせる
+func (q *RedisStreamQueue) process(message redis.XMessage) {
+	job := toJob(message)
+
+	q.mu.Lock()
+	handler, ok := q.handlers[job.Type]
+	q.mu.Unlock()
+	if !ok {
+		slog.Warn("No handler registered for job, leaving it pending", "job_id", job.ID, "type", job.Type)
+		return
+	}
+
+	defer q.ack(message.ID)
+	defer func() {
+		if err := recover(); err != nil {
+			slog.Error("Job panicked", "job_id", job.ID, "type", job.Type, "error", err)
+		}
+	}()
+
+	if err := handler(context.Background(), job); err != nil {
+		slog.Error("Job failed", "job_id", job.ID, "type", job.Type, "error", err)
+	}
+}
+
+// ack 処理済みのジョブを確認応答してストリームから削除
+func (q *RedisStreamQueue) ack(messageID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := q.client.XAck(ctx, q.stream, q.group, messageID).Err(); err != nil {
+		slog.Error("Failed to ack job", "message_id", messageID, "error", err)
+		return
+	}
+	if err := q.client.XDel(ctx, q.stream, messageID).Err(); err != nil {
+		slog.Error("Failed to delete acked job", "message_id", messageID, "error", err)
+	}
+}
+
+// toJob ストリームのメッセージをジョブに変換
+func toJob(message redis.XMessage) *domain.Job {
+	job := &domain.Job{ID: message.ID}
+	if v, ok := message.Values["id"].(string); ok {
+		job.ID = v
+	}
+	if v, ok := message.Values["type"].(string); ok {
+		job.Type = v
+	}
+	if v, ok := message.Values["payload"].(string); ok {
+		job.Payload = []byte(v)
+	}
+	if v, ok := message.Values["enqueued_at"].(string); ok {
+		if ms, err := strconv.ParseInt(v, 10, 64); err == nil {
+			job.EnqueuedAt = time.UnixMilli(ms)
+		}
+	}
+	return job
+}
+
+// newConsumerName インスタンスごとに一意なコンシューマー名を生成
+func newConsumerName() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "worker"
+	}
+	return host + "-" + newJobID()[:8]
+}
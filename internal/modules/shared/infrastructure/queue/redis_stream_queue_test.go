@@ -0,0 +1,248 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"vision-api-app/internal/config"
+	"vision-api-app/internal/modules/shared/domain"
+	"vision-api-app/internal/modules/shared/infrastructure/testcontainer"
+)
+
+func setupRedisStreamQueues(t *testing.T, n int) ([]*RedisStreamQueue, func()) {
+	t.Helper()
+	ctx := context.Background()
+
+	redisContainer, err := testcontainer.StartRedis(ctx, t)
+	if err != nil {
+		t.Fatalf("Failed to start redis container: %v", err)
+	}
+
+	// 同じストリームを共有する複数インスタンス
+	queues := make([]*RedisStreamQueue, 0, n)
+	for i := 0; i < n; i++ {
+		client := redis.NewClient(&redis.Options{
+			Addr: fmt.Sprintf("%s:%s", redisContainer.Host, redisContainer.Port),
+		})
+		q, err := NewRedisStreamQueueWithClient(client, &config.QueueConfig{Workers: 1})
+		if err != nil {
+			_ = redisContainer.Close(ctx)
+			t.Fatalf("NewRedisStreamQueueWithClient() error = %v", err)
+		}
+		queues = append(queues, q)
+	}
+
+	return queues, func() {
+		for _, q := range queues {
+			_ = q.Close(ctx)
+		}
+		_ = redisContainer.Close(ctx)
+	}
+}
+
+func TestRedisStreamQueue_ProcessAcrossInstances(t *testing.T) {
+	queues, cleanup := setupRedisStreamQueues(t, 2)
+	defer cleanup()
+
+	var processed int32
+	for _, q := range queues {
+		q.Register("count", func(ctx context.Context, job *domain.Job) error {
+			if string(job.Payload) != "payload" {
+				t.Errorf("payload = %s, want payload", job.Payload)
+			}
+			atomic.AddInt32(&processed, 1)
+			return nil
+		})
+		q.Start()
+	}
+
+	// 1台目で受け付けたジョブをいずれかのインスタンスが1回だけ処理する
+	for i := 0; i < 10; i++ {
+		if err := queues[0].Enqueue(context.Background(), "count", []byte("payload")); err != nil {
+			t.Fatalf("Enqueue() error = %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := queues[1].Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	if got := atomic.LoadInt32(&processed); got != 10 {
+		t.Errorf("processed = %d, want 10", got)
+	}
+}
+
+func TestRedisStreamQueue_HandlerErrorAndPanic(t *testing.T) {
+	queues, cleanup := setupRedisStreamQueues(t, 1)
+	defer cleanup()
+	q := queues[0]
+
+	q.Register("fail", func(ctx context.Context, job *domain.Job) error {
+		return errors.New("job error")
+	})
+	q.Register("panic", func(ctx context.Context, job *domain.Job) error {
+		panic("job panic")
+	})
+	q.Start()
+
+	if err := q.Enqueue(context.Background(), "fail", nil); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	if err := q.Enqueue(context.Background(), "panic", nil); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	// 失敗やパニックがあってもFlushは完了する
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := q.Flush(ctx); err != nil {
+		t.Errorf("Flush() error = %v", err)
+	}
+}
+
+func TestRedisStreamQueue_Start(t *testing.T) {
+	queues, cleanup := setupRedisStreamQueues(t, 2)
+	defer cleanup()
+	registered, unregistered := queues[0], queues[1]
+
+	var processed int32
+	registered.Register("count", func(ctx context.Context, job *domain.Job) error {
+		atomic.AddInt32(&processed, 1)
+		return nil
+	})
+
+	// 処理関数を登録していないインスタンスは受信したジョブを確認応答せずに残す
+	unregistered.Start()
+	if err := registered.Enqueue(context.Background(), "count", nil); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		pending, err := registered.client.XPending(context.Background(), registered.stream, registered.group).Result()
+		if err != nil {
+			t.Fatalf("XPending() error = %v", err)
+		}
+		if pending.Count == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("pending = %d, want 1", pending.Count)
+		}
+		time.Sleep(flushPollInterval)
+	}
+	if n, err := registered.client.XLen(context.Background(), registered.stream).Result(); err != nil || n != 1 {
+		t.Errorf("XLen() = %d, %v, want 1", n, err)
+	}
+
+	// Start前に投入したジョブはStart後に処理する
+	if err := unregistered.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := registered.Enqueue(context.Background(), "count", nil); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	registered.Start()
+
+	deadline = time.Now().Add(10 * time.Second)
+	for atomic.LoadInt32(&processed) < 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("job enqueued before Start was not processed")
+		}
+		time.Sleep(flushPollInterval)
+	}
+}
+
+func TestRedisStreamQueue_Close(t *testing.T) {
+	queues, cleanup := setupRedisStreamQueues(t, 1)
+	defer cleanup()
+	q := queues[0]
+
+	if err := q.Enqueue(context.Background(), "unknown", nil); err == nil {
+		t.Error("Expected error for unregistered job type")
+	}
+
+	if err := q.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := q.Enqueue(context.Background(), "count", nil); !errors.Is(err, domain.ErrQueueClosed) {
+		t.Errorf("Enqueue() after Close error = %v, want ErrQueueClosed", err)
+	}
+}
+
+// readPending ストリームのジョブをすべて別のコンシューマーで受信し、処理中のまま残す
+func readPending(t *testing.T, q *RedisStreamQueue, n int) {
+	t.Helper()
+	if _, err := q.client.XReadGroup(context.Background(), &redis.XReadGroupArgs{
+		Group:    q.group,
+		Consumer: "stopped",
+		Streams:  []string{q.stream, ">"},
+		Count:    int64(n),
+	}).Result(); err != nil {
+		t.Fatalf("XReadGroup() error = %v", err)
+	}
+}
+
+func TestRedisStreamQueue_ClaimDeadLetter(t *testing.T) {
+	queues, cleanup := setupRedisStreamQueues(t, 2)
+	defer cleanup()
+	registered, unregistered := queues[0], queues[1]
+	registered.Register("orphan", func(ctx context.Context, job *domain.Job) error { return nil })
+	unregistered.minIdle = 0
+	unregistered.maxDeliveries = 2
+
+	if err := registered.Enqueue(context.Background(), "orphan", []byte("payload")); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	readPending(t, unregistered, 1)
+
+	// 処理関数がないため引き取っても処理中のまま残り、配信回数が上限を超えるとデッドレターに移す
+	unregistered.claim()
+	if n, err := unregistered.client.XLen(context.Background(), unregistered.stream).Result(); err != nil || n != 1 {
+		t.Fatalf("XLen() = %d, %v, want 1", n, err)
+	}
+	unregistered.claim()
+
+	if n, err := unregistered.client.XLen(context.Background(), unregistered.stream).Result(); err != nil || n != 0 {
+		t.Errorf("XLen() = %d, %v, want 0", n, err)
+	}
+	dead, err := unregistered.client.XRange(context.Background(), unregistered.stream+deadLetterSuffix, "-", "+").Result()
+	if err != nil {
+		t.Fatalf("XRange() error = %v", err)
+	}
+	if len(dead) != 1 || dead[0].Values["type"] != "orphan" || dead[0].Values["payload"] != "payload" || dead[0].Values["deliveries"] != "3" {
+		t.Errorf("dead letters = %+v", dead)
+	}
+}
+
+func TestRedisStreamQueue_ClaimCursor(t *testing.T) {
+	queues, cleanup := setupRedisStreamQueues(t, 2)
+	defer cleanup()
+	registered, unregistered := queues[0], queues[1]
+	registered.Register("orphan", func(ctx context.Context, job *domain.Job) error { return nil })
+	unregistered.minIdle = 0
+
+	for i := 0; i < claimCount+1; i++ {
+		if err := registered.Enqueue(context.Background(), "orphan", nil); err != nil {
+			t.Fatalf("Enqueue() error = %v", err)
+		}
+	}
+	readPending(t, unregistered, claimCount+1)
+
+	// 1回で確認しきれない場合は次回に続きから確認し、最後まで確認すると先頭に戻る
+	unregistered.claim()
+	if unregistered.claimStart == "0-0" {
+		t.Error("claimStart = 0-0, want the next pending job")
+	}
+	unregistered.claim()
+	if unregistered.claimStart != "0-0" {
+		t.Errorf("claimStart = %s, want 0-0", unregistered.claimStart)
+	}
+}
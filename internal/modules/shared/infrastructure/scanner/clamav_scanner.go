@@ -0,0 +1,98 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+const (
+	// DefaultTimeout 1ファイルあたりの検査のデフォルトタイムアウト
+	DefaultTimeout = 30 * time.Second
+
+	// clamavChunkSize INSTREAMで送信するチャンクの大きさ（clamdのStreamMaxLength未満に分割）
+	clamavChunkSize = 64 << 10
+)
+
+// ClamAVScanner ClamAV（clamd）のTCPソケットを使った検査
+type ClamAVScanner struct {
+	address string
+	timeout time.Duration
+}
+
+// NewClamAVScanner 新しいClamAVScannerを作成（addressは clamav:3310 の形式）
+func NewClamAVScanner(address string, timeout time.Duration) *ClamAVScanner {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &ClamAVScanner{
+		address: address,
+		timeout: timeout,
+	}
+}
+
+// Scan INSTREAMコマンドでファイルを送信して検査
+func (s *ClamAVScanner) Scan(ctx context.Context, data []byte) error {
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	deadline := time.Now().Add(s.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return fmt.Errorf("failed to set clamd deadline: %w", err)
+	}
+
+	writer := bufio.NewWriter(conn)
+	if _, err := writer.WriteString("zINSTREAM\x00"); err != nil {
+		return fmt.Errorf("failed to send clamd command: %w", err)
+	}
+	for start := 0; start < len(data); start += clamavChunkSize {
+		end := min(start+clamavChunkSize, len(data))
+		if err := binary.Write(writer, binary.BigEndian, uint32(end-start)); err != nil {
+			return fmt.Errorf("failed to send data to clamd: %w", err)
+		}
+		if _, err := writer.Write(data[start:end]); err != nil {
+			return fmt.Errorf("failed to send data to clamd: %w", err)
+		}
+	}
+	// 長さ0のチャンクで送信終了
+	if err := binary.Write(writer, binary.BigEndian, uint32(0)); err != nil {
+		return fmt.Errorf("failed to send data to clamd: %w", err)
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to send data to clamd: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil {
+		return fmt.Errorf("failed to read clamd reply: %w", err)
+	}
+	return parseClamAVReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+// parseClamAVReply clamdの応答（stream: OK / stream: <シグネチャ> FOUND / ... ERROR）を解釈
+func parseClamAVReply(reply string) error {
+	result := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+	switch {
+	case result == "OK":
+		return nil
+	case strings.HasSuffix(result, " FOUND"):
+		return fmt.Errorf("%w: %s", domain.ErrFileInfected, strings.TrimSuffix(result, " FOUND"))
+	default:
+		return fmt.Errorf("clamd scan failed: %s", reply)
+	}
+}
@@ -0,0 +1,128 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+// eicar EICARテスト文字列（ウイルス検査の動作確認用）
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// startFakeClamd INSTREAMを受け取り、EICARを含む場合はFOUNDを返すclamdのスタブを起動
+func startFakeClamd(t *testing.T) (string, func() []byte) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	var mu sync.Mutex
+	var received []byte
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			reader := bufio.NewReader(conn)
+			command, _ := reader.ReadString(0)
+			var data []byte
+			for {
+				var size uint32
+				if err := binary.Read(reader, binary.BigEndian, &size); err != nil || size == 0 {
+					break
+				}
+				chunk := make([]byte, size)
+				if _, err := io.ReadFull(reader, chunk); err != nil {
+					break
+				}
+				data = append(data, chunk...)
+			}
+			mu.Lock()
+			received = data
+			mu.Unlock()
+
+			reply := "stream: OK\x00"
+			switch {
+			case command != "zINSTREAM\x00":
+				reply = "UNKNOWN COMMAND\x00"
+			case bytes.Contains(data, []byte("EICAR-STANDARD-ANTIVIRUS-TEST-FILE")):
+				reply = "stream: Eicar-Test-Signature FOUND\x00"
+			case bytes.Contains(data, []byte("broken")):
+				reply = "INSTREAM size limit exceeded. ERROR\x00"
+			}
+			_, _ = conn.Write([]byte(reply))
+			_ = conn.Close()
+		}
+	}()
+	return listener.Addr().String(), func() []byte {
+		mu.Lock()
+		defer mu.Unlock()
+		return received
+	}
+}
+
+func TestClamAVScanner_Scan(t *testing.T) {
+	address, received := startFakeClamd(t)
+	s := NewClamAVScanner(address, 5*time.Second)
+
+	large := bytes.Repeat([]byte("a"), clamavChunkSize*2+10)
+
+	tests := []struct {
+		name         string
+		data         []byte
+		wantInfected bool
+		wantErr      bool
+	}{
+		{name: "正常系: 問題のないファイル", data: []byte("receipt image")},
+		{name: "正常系: 複数チャンクに分割して送信", data: large},
+		{name: "異常系: ウイルスを検出", data: []byte(eicar), wantInfected: true, wantErr: true},
+		{name: "異常系: clamdのエラー", data: []byte("broken"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Scan(context.Background(), tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Scan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, domain.ErrFileInfected) != tt.wantInfected {
+				t.Errorf("Scan() error = %v, wantInfected %v", err, tt.wantInfected)
+			}
+			if tt.wantInfected && !strings.Contains(err.Error(), "Eicar-Test-Signature") {
+				t.Errorf("Scan() error = %v, want signature name", err)
+			}
+			if !tt.wantErr && !bytes.Equal(received(), tt.data) {
+				t.Errorf("clamd received %d bytes, want %d", len(received()), len(tt.data))
+			}
+		})
+	}
+}
+
+func TestClamAVScanner_Unavailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	address := listener.Addr().String()
+	_ = listener.Close()
+
+	err = NewClamAVScanner(address, time.Second).Scan(context.Background(), []byte("receipt image"))
+	if err == nil || errors.Is(err, domain.ErrFileInfected) {
+		t.Errorf("Scan() error = %v, want connection error", err)
+	}
+}
@@ -0,0 +1,73 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+// HTTPScanner 外部の検査APIを使った検査
+// ファイル本体を application/octet-stream でPOSTし、{"infected": bool, "signature": string} の応答を受け取る
+type HTTPScanner struct {
+	url    string
+	client *http.Client
+}
+
+// httpScanResult 検査APIの応答
+type httpScanResult struct {
+	Infected  bool   `json:"infected"`
+	Signature string `json:"signature"`
+}
+
+// NewHTTPScanner 新しいHTTPScannerを作成
+func NewHTTPScanner(url string, timeout time.Duration) *HTTPScanner {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &HTTPScanner{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// SetTransport 外部への接続に使うTransportを設定（プロキシ・追加のルート証明書など、未設定の場合はhttp.DefaultTransport）
+func (s *HTTPScanner) SetTransport(transport http.RoundTripper) {
+	s.client.Transport = transport
+}
+
+// Scan 検査APIへファイルを送信して検査
+func (s *HTTPScanner) Scan(ctx context.Context, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create scan request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call scanner: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("scanner returned status %d: %s", resp.StatusCode, body)
+	}
+
+	var result httpScanResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode scan result: %w", err)
+	}
+	if result.Infected {
+		return fmt.Errorf("%w: %s", domain.ErrFileInfected, result.Signature)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+func TestHTTPScanner_Scan(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/octet-stream" {
+			t.Errorf("Content-Type = %s", r.Header.Get("Content-Type"))
+		}
+		data, _ := io.ReadAll(r.Body)
+		switch {
+		case bytes.Contains(data, []byte("EICAR")):
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"infected": true, "signature": "Eicar-Test-Signature"})
+		case bytes.Contains(data, []byte("broken")):
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"infected": false})
+		}
+	}))
+	defer server.Close()
+
+	s := NewHTTPScanner(server.URL, 5*time.Second)
+
+	tests := []struct {
+		name         string
+		data         []byte
+		wantInfected bool
+		wantErr      bool
+	}{
+		{name: "正常系: 問題のないファイル", data: []byte("receipt image")},
+		{name: "異常系: ウイルスを検出", data: []byte(eicar), wantInfected: true, wantErr: true},
+		{name: "異常系: 検査APIのエラー", data: []byte("broken"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Scan(context.Background(), tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Scan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, domain.ErrFileInfected) != tt.wantInfected {
+				t.Errorf("Scan() error = %v, wantInfected %v", err, tt.wantInfected)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+// lockNamePrefix タスクごとのロック名の接頭辞
+const lockNamePrefix = "scheduler:"
+
+// TaskFunc 定期実行する処理
+type TaskFunc func(ctx context.Context) error
+
+// task 登録済みの定期実行タスク
+type task struct {
+	name     string
+	interval time.Duration
+	run      TaskFunc
+	local    bool // 分散ロックを使用せず各インスタンスで実行する
+}
+
+// Scheduler プロセス内で定期実行タスクを動かすスケジューラー
+type Scheduler struct {
+	mu      sync.Mutex
+	tasks   []task
+	locker  domain.Locker
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+}
+
+// NewScheduler 新しいSchedulerを作成
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// SetLocker 分散ロックを設定し、複数インスタンスでの重複実行を防ぐ
+// 未設定の場合は各インスタンスがそれぞれタスクを実行する
+func (s *Scheduler) SetLocker(locker domain.Locker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.locker = locker
+}
+
+// Add 定期実行タスクを登録（Start前に呼び出す）
+func (s *Scheduler) Add(name string, interval time.Duration, run TaskFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tasks = append(s.tasks, task{name: name, interval: interval, run: run})
+}
+
+// AddLocal インスタンスごとに実行する定期実行タスクを登録（Start前に呼び出す）
+// ローカルディスクなどインスタンス固有のデータを扱うタスク用で、分散ロックを設定していても全インスタンスで実行する
+func (s *Scheduler) AddLocal(name string, interval time.Duration, run TaskFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tasks = append(s.tasks, task{name: name, interval: interval, run: run, local: true})
+}
+
+// Start 登録済みタスクの定期実行を開始
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return
+	}
+	s.started = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, t := range s.tasks {
+		s.wg.Add(1)
+		go s.loop(ctx, t)
+	}
+}
+
+// Stop 定期実行を停止し、実行中のタスクの完了を待機
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.started {
+		s.mu.Unlock()
+		return nil
+	}
+	s.started = false
+	s.cancel()
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to stop scheduler: %w", ctx.Err())
+	}
+}
+
+// loop タスクを一定間隔で実行
+func (s *Scheduler) loop(ctx context.Context, t task) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runOnce(ctx, t)
+		}
+	}
+}
+
+// runOnce タスクを1回実行（パニックはログに記録して継続）
+func (s *Scheduler) runOnce(ctx context.Context, t task) {
+	defer func() {
+		if err := recover(); err != nil {
+			slog.Error("Scheduled task panicked", "task", t.name, "error", err)
+		}
+	}()
+
+	if s.locker != nil && !t.local {
+		lock, err := s.locker.Acquire(ctx, lockNamePrefix+t.name, t.interval)
+		if errors.Is(err, domain.ErrLockNotAcquired) {
+			slog.Debug("Scheduled task skipped, running on another instance", "task", t.name)
+			return
+		}
+		if err != nil {
+			// ロックの状態が分からない場合は重複実行を避けて今回は見送る
+			slog.Warn("Failed to acquire scheduler lock", "task", t.name, "error", err)
+			return
+		}
+		// 実行後も解放せず、有効期限（実行間隔）まで他のインスタンスの実行を抑止する
+		stop := s.keepAlive(ctx, t, lock)
+		defer stop()
+	}
+
+	start := time.Now()
+	if err := t.run(ctx); err != nil {
+		slog.Error("Scheduled task failed", "task", t.name, "error", err)
+		return
+	}
+	slog.Debug("Scheduled task completed", "task", t.name, "duration", time.Since(start))
+}
+
+// keepAlive 実行が長引いてもロックが切れないよう、実行中は有効期限を延長し続ける
+func (s *Scheduler) keepAlive(ctx context.Context, t task, lock domain.Lock) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(t.interval / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := lock.Refresh(ctx, t.interval); err != nil {
+					slog.Warn("Failed to refresh scheduler lock", "task", t.name, "error", err)
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
@@ -0,0 +1,208 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+// memoryLocker 複数インスタンスで共有されるロックを模したモック
+type memoryLocker struct {
+	mu    sync.Mutex
+	held  map[string]time.Time
+	fails bool
+}
+
+func newMemoryLocker() *memoryLocker {
+	return &memoryLocker{held: make(map[string]time.Time)}
+}
+
+func (l *memoryLocker) Acquire(ctx context.Context, name string, ttl time.Duration) (domain.Lock, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.fails {
+		return nil, errors.New("redis unavailable")
+	}
+	if expires, ok := l.held[name]; ok && time.Now().Before(expires) {
+		return nil, domain.ErrLockNotAcquired
+	}
+	l.held[name] = time.Now().Add(ttl)
+	return &memoryLock{locker: l, name: name}, nil
+}
+
+type memoryLock struct {
+	locker *memoryLocker
+	name   string
+}
+
+func (l *memoryLock) Refresh(ctx context.Context, ttl time.Duration) error {
+	l.locker.mu.Lock()
+	defer l.locker.mu.Unlock()
+	l.locker.held[l.name] = time.Now().Add(ttl)
+	return nil
+}
+
+func (l *memoryLock) Release(ctx context.Context) error {
+	l.locker.mu.Lock()
+	defer l.locker.mu.Unlock()
+	delete(l.locker.held, l.name)
+	return nil
+}
+
+func TestScheduler_RunsTasksPeriodically(t *testing.T) {
+	s := NewScheduler()
+
+	var count int32
+	s.Add("count", 5*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	})
+	s.Add("fail", 5*time.Millisecond, func(ctx context.Context) error {
+		return errors.New("task error")
+	})
+	s.Add("panic", 5*time.Millisecond, func(ctx context.Context) error {
+		panic("task panic")
+	})
+
+	s.Start()
+	time.Sleep(30 * time.Millisecond)
+
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	got := atomic.LoadInt32(&count)
+	if got < 2 {
+		t.Errorf("count = %d, want at least 2", got)
+	}
+
+	// 停止後は実行されない
+	time.Sleep(20 * time.Millisecond)
+	if after := atomic.LoadInt32(&count); after != got {
+		t.Errorf("count after Stop = %d, want %d", after, got)
+	}
+}
+
+func TestScheduler_StopWaitsForRunningTask(t *testing.T) {
+	s := NewScheduler()
+
+	var finished int32
+	s.Add("slow", time.Millisecond, func(ctx context.Context) error {
+		time.Sleep(20 * time.Millisecond)
+		atomic.StoreInt32(&finished, 1)
+		return nil
+	})
+
+	s.Start()
+	time.Sleep(5 * time.Millisecond)
+
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if atomic.LoadInt32(&finished) != 1 {
+		t.Error("Stop() returned before running task finished")
+	}
+}
+
+func TestScheduler_StopWithoutStart(t *testing.T) {
+	if err := NewScheduler().Stop(context.Background()); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+}
+
+func TestScheduler_LockerPreventsDoubleRun(t *testing.T) {
+	locker := newMemoryLocker()
+	interval := 20 * time.Millisecond
+
+	var count int32
+	var instances []*Scheduler
+	// 同じロックを共有する3インスタンス
+	for i := 0; i < 3; i++ {
+		s := NewScheduler()
+		s.SetLocker(locker)
+		s.Add("purge", interval, func(ctx context.Context) error {
+			atomic.AddInt32(&count, 1)
+			return nil
+		})
+		instances = append(instances, s)
+	}
+
+	for _, s := range instances {
+		s.Start()
+	}
+	time.Sleep(5*interval + interval/2)
+	for _, s := range instances {
+		if err := s.Stop(context.Background()); err != nil {
+			t.Fatalf("Stop() error = %v", err)
+		}
+	}
+
+	// 実行間隔ごとに1インスタンスのみが実行する
+	got := atomic.LoadInt32(&count)
+	if got < 1 || got > 6 {
+		t.Errorf("count = %d, want between 1 and 6", got)
+	}
+}
+
+func TestScheduler_LocalTaskRunsOnEveryInstance(t *testing.T) {
+	locker := newMemoryLocker()
+	interval := 10 * time.Millisecond
+
+	var counts [3]int32
+	var instances []*Scheduler
+	for i := range counts {
+		s := NewScheduler()
+		s.SetLocker(locker)
+		s.AddLocal("replay", interval, func(ctx context.Context) error {
+			atomic.AddInt32(&counts[i], 1)
+			return nil
+		})
+		instances = append(instances, s)
+	}
+
+	for _, s := range instances {
+		s.Start()
+	}
+	time.Sleep(5 * interval)
+	for _, s := range instances {
+		if err := s.Stop(context.Background()); err != nil {
+			t.Fatalf("Stop() error = %v", err)
+		}
+	}
+
+	// ロックを取得せず、すべてのインスタンスが実行する
+	for i := range counts {
+		if got := atomic.LoadInt32(&counts[i]); got == 0 {
+			t.Errorf("instance %d count = 0, want > 0", i)
+		}
+	}
+}
+
+func TestScheduler_LockerErrorSkipsRun(t *testing.T) {
+	locker := newMemoryLocker()
+	locker.fails = true
+
+	s := NewScheduler()
+	s.SetLocker(locker)
+
+	var count int32
+	s.Add("purge", 5*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	})
+
+	s.Start()
+	time.Sleep(30 * time.Millisecond)
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	if got := atomic.LoadInt32(&count); got != 0 {
+		t.Errorf("count = %d, want 0", got)
+	}
+}
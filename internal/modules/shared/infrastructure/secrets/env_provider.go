@@ -0,0 +1,33 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+// EnvSecretProvider 秘密情報を環境変数から取得する
+// 名前は大文字に変換して参照する（例: freee_client_id → FREEE_CLIENT_ID）
+type EnvSecretProvider struct {
+	prefix string
+}
+
+// NewEnvSecretProvider 新しいEnvSecretProviderを作成（prefixは環境変数名の接頭辞、例: "VISION_"）
+func NewEnvSecretProvider(prefix string) *EnvSecretProvider {
+	return &EnvSecretProvider{
+		prefix: prefix,
+	}
+}
+
+// Secret 環境変数から秘密情報を取得（未設定・空の場合は ErrSecretNotFound）
+func (p *EnvSecretProvider) Secret(ctx context.Context, name string) (string, error) {
+	key := p.prefix + strings.ToUpper(name)
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return "", fmt.Errorf("%w: %s", domain.ErrSecretNotFound, key)
+	}
+	return value, nil
+}
@@ -0,0 +1,43 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+func TestEnvSecretProvider_Secret(t *testing.T) {
+	t.Setenv("FREEE_CLIENT_ID", "client-id")
+	t.Setenv("TEST_FREEE_CLIENT_ID", "prefixed-client-id")
+	t.Setenv("FREEE_REFRESH_TOKEN", "")
+
+	tests := []struct {
+		name    string
+		prefix  string
+		secret  string
+		want    string
+		wantErr bool
+	}{
+		{name: "正常系: 大文字の環境変数を参照", secret: "freee_client_id", want: "client-id"},
+		{name: "正常系: 接頭辞付き", prefix: "TEST_", secret: "freee_client_id", want: "prefixed-client-id"},
+		{name: "異常系: 空の値は未登録扱い", secret: "freee_refresh_token", wantErr: true},
+		{name: "異常系: 未設定", secret: "unknown_secret", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewEnvSecretProvider(tt.prefix).Secret(context.Background(), tt.secret)
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrSecretNotFound) {
+					t.Errorf("Secret() error = %v, want ErrSecretNotFound", err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Secret() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
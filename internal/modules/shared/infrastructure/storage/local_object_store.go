@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+// LocalObjectStore ローカルディレクトリをオブジェクトストレージとして使う実装
+// キーの "/" はサブディレクトリになるため、DuckDBなどからディレクトリをそのまま読める
+type LocalObjectStore struct {
+	dir string
+}
+
+// NewLocalObjectStore 新しいLocalObjectStoreを作成
+func NewLocalObjectStore(dir string) (*LocalObjectStore, error) {
+	if dir == "" {
+		return nil, errors.New("object store directory is required")
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create object store directory: %w", err)
+	}
+	return &LocalObjectStore{dir: dir}, nil
+}
+
+// Put オブジェクトを保存
+// 書き込み途中のファイルが読まれないよう、一時ファイルに書いてからリネームする
+func (s *LocalObjectStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create object directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write object: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write object: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save object: %w", err)
+	}
+	return nil
+}
+
+// Get オブジェクトを取得
+func (s *LocalObjectStore) Get(ctx context.Context, key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrObjectNotFound, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object: %w", err)
+	}
+	return data, nil
+}
+
+// path キーから保存先のパスを求める（ディレクトリ外を指すキー・隠しファイルは拒否）
+func (s *LocalObjectStore) path(key string) (string, error) {
+	if key == "" {
+		return "", fmt.Errorf("invalid object key: %q", key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || strings.HasPrefix(segment, ".") || strings.ContainsRune(segment, '\\') {
+			return "", fmt.Errorf("invalid object key: %q", key)
+		}
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
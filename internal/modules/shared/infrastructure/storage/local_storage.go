@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+// DefaultImageDir デフォルトの画像保存ディレクトリ
+const DefaultImageDir = "data/images"
+
+// LocalImageStorage ローカルファイルシステムへの画像保存実装
+// 保存容量の上限（SetQuota）を設定した場合は、上限を超える画像の保存をErrStorageQuotaExceededで拒否する。
+// 上限はソフトクォータで、上限を下げても保存済みの画像は削除しない
+type LocalImageStorage struct {
+	dir string
+
+	mu     sync.Mutex
+	quota  int64 // 保存容量の上限（0は無制限）
+	used   int64 // 保存済みの画像の合計サイズ
+	images int   // 保存済みの画像数
+}
+
+// NewLocalImageStorage 新しいLocalImageStorageを作成
+func NewLocalImageStorage(dir string) (*LocalImageStorage, error) {
+	if dir == "" {
+		dir = DefaultImageDir
+	}
+
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create image directory: %w", err)
+	}
+
+	s := &LocalImageStorage{dir: dir}
+	if err := s.scanUsage(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SetQuota 保存容量の上限をバイト数で設定（0以下は無制限）
+func (s *LocalImageStorage) SetQuota(bytes int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.quota = max(bytes, 0)
+}
+
+// Usage 保存容量の使用状況を取得
+func (s *LocalImageStorage) Usage(ctx context.Context) (domain.StorageUsage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return domain.StorageUsage{
+		UsedBytes:  s.used,
+		QuotaBytes: s.quota,
+		Images:     s.images,
+	}, nil
+}
+
+// scanUsage 保存済みの画像から使用状況を求める（起動時に1回だけ実行）
+func (s *LocalImageStorage) scanUsage() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("failed to list images: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		s.used += info.Size()
+		s.images++
+	}
+	return nil
+}
+
+// sizeOf 保存済みの画像のサイズを取得（存在しない場合はfalse）
+func sizeOf(path string) (int64, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, false
+	}
+	return info.Size(), true
+}
+
+// Save 画像を保存
+// 書き込み途中のファイルが読まれないよう、一時ファイルに書いてからリネームする
+func (s *LocalImageStorage) Save(ctx context.Context, key string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	// 上限の確認から保存後の集計までをまとめて行い、同時に保存された画像で上限を超えないようにする
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 上書きする場合は既存の画像の分を差し引いて判定する
+	prevSize, exists := sizeOf(path)
+	size := int64(len(data))
+	usage := domain.StorageUsage{UsedBytes: s.used - prevSize, QuotaBytes: s.quota}
+	if usage.Exceeds(size) {
+		return fmt.Errorf("%w: %d of %d bytes used", domain.ErrStorageQuotaExceeded, s.used, s.quota)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write image: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write image: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save image: %w", err)
+	}
+
+	s.used += size - prevSize
+	if !exists {
+		s.images++
+	}
+	return nil
+}
+
+// Load 画像を取得
+func (s *LocalImageStorage) Load(ctx context.Context, key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrImageNotFound, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	return data, nil
+}
+
+// Delete 画像を削除
+func (s *LocalImageStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	size, exists := sizeOf(path)
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete image: %w", err)
+	}
+	if exists {
+		s.used -= size
+		s.images--
+	}
+	return nil
+}
+
+// ListBefore 指定時刻より前に保存された画像のキー一覧を取得
+func (s *LocalImageStorage) ListBefore(ctx context.Context, before time.Time) ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images: %w", err)
+	}
+
+	var keys []string
+	for _, entry := range entries {
+		// 書き込み途中の一時ファイルは対象外
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if info.ModTime().Before(before) {
+			keys = append(keys, entry.Name())
+		}
+	}
+	return keys, nil
+}
+
+// path キーから保存先のパスを求める（ディレクトリ外を指すキーは拒否）
+func (s *LocalImageStorage) path(key string) (string, error) {
+	if key == "" || key != filepath.Base(key) || strings.HasPrefix(key, ".") {
+		return "", fmt.Errorf("invalid image key: %q", key)
+	}
+	return filepath.Join(s.dir, key), nil
+}
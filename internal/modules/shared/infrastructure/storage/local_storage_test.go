@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+func TestLocalImageStorage_SaveLoadDelete(t *testing.T) {
+	s, err := NewLocalImageStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalImageStorage() error = %v", err)
+	}
+	ctx := context.Background()
+
+	if err := s.Save(ctx, "receipt-1", []byte("image data")); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, err := s.Load(ctx, "receipt-1")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !bytes.Equal(data, []byte("image data")) {
+		t.Errorf("Load() = %s, want image data", data)
+	}
+
+	if err := s.Delete(ctx, "receipt-1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := s.Load(ctx, "receipt-1"); !errors.Is(err, domain.ErrImageNotFound) {
+		t.Errorf("Load() after Delete error = %v, want ErrImageNotFound", err)
+	}
+
+	// 存在しない画像の削除はエラーにしない
+	if err := s.Delete(ctx, "receipt-1"); err != nil {
+		t.Errorf("Delete() of missing image error = %v", err)
+	}
+}
+
+func TestLocalImageStorage_InvalidKey(t *testing.T) {
+	s, err := NewLocalImageStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalImageStorage() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{name: "空のキー", key: ""},
+		{name: "親ディレクトリ参照", key: "../outside"},
+		{name: "サブディレクトリ", key: "dir/file"},
+		{name: "隠しファイル", key: ".hidden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.Save(context.Background(), tt.key, []byte("x")); err == nil {
+				t.Errorf("Save(%q) expected error", tt.key)
+			}
+		})
+	}
+}
+
+func TestLocalImageStorage_ListBefore(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewLocalImageStorage(dir)
+	if err != nil {
+		t.Fatalf("NewLocalImageStorage() error = %v", err)
+	}
+	ctx := context.Background()
+
+	for _, key := range []string{"old", "new"} {
+		if err := s.Save(ctx, key, []byte(key)); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "old"), old, old); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+
+	keys, err := s.ListBefore(ctx, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("ListBefore() error = %v", err)
+	}
+	if len(keys) != 1 || keys[0] != "old" {
+		t.Errorf("ListBefore() = %v, want [old]", keys)
+	}
+}
+
+func TestLocalImageStorage_Quota(t *testing.T) {
+	dir := t.TempDir()
+	// 起動前から保存されている画像も使用量に含める
+	if err := os.WriteFile(filepath.Join(dir, "existing"), make([]byte, 40), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	s, err := NewLocalImageStorage(dir)
+	if err != nil {
+		t.Fatalf("NewLocalImageStorage() error = %v", err)
+	}
+	s.SetQuota(100)
+	ctx := context.Background()
+
+	if err := s.Save(ctx, "receipt-1", make([]byte, 50)); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := s.Save(ctx, "receipt-2", make([]byte, 20)); !errors.Is(err, domain.ErrStorageQuotaExceeded) {
+		t.Errorf("Save() over quota error = %v, want ErrStorageQuotaExceeded", err)
+	}
+	// 上書きは既存の画像の分を差し引いて判定する
+	if err := s.Save(ctx, "receipt-1", make([]byte, 60)); err != nil {
+		t.Errorf("Save() overwrite error = %v", err)
+	}
+
+	usage, err := s.Usage(ctx)
+	if err != nil {
+		t.Fatalf("Usage() error = %v", err)
+	}
+	if usage.UsedBytes != 100 || usage.QuotaBytes != 100 || usage.Images != 2 {
+		t.Errorf("Usage() = %+v, want 100/100 bytes and 2 images", usage)
+	}
+
+	// 削除すると空いた分だけ保存できる
+	if err := s.Delete(ctx, "existing"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := s.Save(ctx, "receipt-2", make([]byte, 20)); err != nil {
+		t.Errorf("Save() after Delete error = %v", err)
+	}
+	usage, _ = s.Usage(ctx)
+	if usage.UsedBytes != 80 || usage.Images != 2 {
+		t.Errorf("Usage() after Delete = %+v, want 80 bytes and 2 images", usage)
+	}
+}
@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+)
+
+const (
+	// DefaultSpoolDir デフォルトのレシート一時保管ディレクトリ
+	DefaultSpoolDir = "data/spool"
+
+	// spoolFileSuffix 一時保管ファイルの拡張子
+	spoolFileSuffix = ".json"
+)
+
+// FileReceiptSpool ローカルファイルシステムへのレシート一時保管実装
+// レシート1件をJSONファイル1つとして保存するため、プロセスが再起動しても失われない
+type FileReceiptSpool struct {
+	dir string
+}
+
+// NewFileReceiptSpool 新しいFileReceiptSpoolを作成
+func NewFileReceiptSpool(dir string) (*FileReceiptSpool, error) {
+	if dir == "" {
+		dir = DefaultSpoolDir
+	}
+
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create spool directory: %w", err)
+	}
+
+	return &FileReceiptSpool{dir: dir}, nil
+}
+
+// Put レシートを一時保管
+// 書き込み途中のファイルが読まれないよう、一時ファイルに書いてからリネームする
+func (s *FileReceiptSpool) Put(ctx context.Context, receipt *entity.Receipt) error {
+	path, err := s.path(receipt.ID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(receipt)
+	if err != nil {
+		return fmt.Errorf("failed to encode spooled receipt: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".spool-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write spooled receipt: %w", err)
+	}
+	// 電源断でも失われないよう、リネーム前にディスクへ書き出す
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to sync spooled receipt: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write spooled receipt: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to spool receipt: %w", err)
+	}
+	return nil
+}
+
+// List 一時保管中のレシートを保管した順に取得
+func (s *FileReceiptSpool) List(ctx context.Context) ([]*entity.Receipt, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list spooled receipts: %w", err)
+	}
+
+	type spooled struct {
+		receipt *entity.Receipt
+		modTime time.Time
+	}
+	var items []spooled
+	for _, entry := range entries {
+		// 書き込み途中の一時ファイルは対象外
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, spoolFileSuffix) {
+			continue
+		}
+		info, err := entry.Info()
+		if errors.Is(err, os.ErrNotExist) {
+			// 一覧の取得後に書き戻されたもの
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat spooled receipt: %w", err)
+		}
+
+		data, err := os.ReadFile(filepath.Join(s.dir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read spooled receipt: %w", err)
+		}
+		var receipt entity.Receipt
+		if err := json.Unmarshal(data, &receipt); err != nil {
+			return nil, fmt.Errorf("failed to decode spooled receipt %s: %w", name, err)
+		}
+		items = append(items, spooled{receipt: &receipt, modTime: info.ModTime()})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].modTime.Before(items[j].modTime)
+	})
+
+	receipts := make([]*entity.Receipt, len(items))
+	for i, item := range items {
+		receipts[i] = item.receipt
+	}
+	return receipts, nil
+}
+
+// Remove 一時保管中のレシートを削除
+func (s *FileReceiptSpool) Remove(ctx context.Context, id string) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove spooled receipt: %w", err)
+	}
+	return nil
+}
+
+// path レシートIDから保管先のパスを求める（ディレクトリ外を指すIDは拒否）
+func (s *FileReceiptSpool) path(id string) (string, error) {
+	if id == "" || id != filepath.Base(id) || strings.HasPrefix(id, ".") {
+		return "", fmt.Errorf("invalid receipt id: %q", id)
+	}
+	return filepath.Join(s.dir, id+spoolFileSuffix), nil
+}
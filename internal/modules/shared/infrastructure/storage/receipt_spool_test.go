@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+)
+
+func TestFileReceiptSpool_PutListRemove(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewFileReceiptSpool(dir)
+	if err != nil {
+		t.Fatalf("NewFileReceiptSpool() error = %v", err)
+	}
+	ctx := context.Background()
+
+	first := entity.NewReceipt("receipt-1", "スーパーA", time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), 1200, 100, "食費", time.Now())
+	first.AddItem(entity.NewReceiptItem("receipt-1-00000000", "receipt-1", "牛乳", 1, 200, time.Now()))
+	second := entity.NewReceipt("receipt-2", "薬局B", time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC), 800, 72, "医療費", time.Now())
+
+	if err := s.Put(ctx, first); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if err := s.Put(ctx, second); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	// 保管した順に並ぶよう、最初のファイルの更新時刻を古くする
+	old := time.Now().Add(-time.Minute)
+	if err := os.Chtimes(filepath.Join(dir, "receipt-1.json"), old, old); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+
+	receipts, err := s.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(receipts) != 2 {
+		t.Fatalf("List() returned %d receipts, want 2", len(receipts))
+	}
+	if receipts[0].ID != "receipt-1" || receipts[1].ID != "receipt-2" {
+		t.Errorf("List() order = [%s %s], want [receipt-1 receipt-2]", receipts[0].ID, receipts[1].ID)
+	}
+	if receipts[0].StoreName != "スーパーA" || len(receipts[0].Items) != 1 || receipts[0].Items[0].Name != "牛乳" {
+		t.Errorf("List()[0] = %+v, want spooled receipt with items", receipts[0])
+	}
+
+	if err := s.Remove(ctx, "receipt-1"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	// 存在しないレシートの削除はエラーにしない
+	if err := s.Remove(ctx, "receipt-1"); err != nil {
+		t.Errorf("Remove() of missing receipt error = %v", err)
+	}
+
+	receipts, err = s.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(receipts) != 1 || receipts[0].ID != "receipt-2" {
+		t.Errorf("List() after Remove = %v, want [receipt-2]", receipts)
+	}
+}
+
+func TestFileReceiptSpool_InvalidID(t *testing.T) {
+	s, err := NewFileReceiptSpool(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileReceiptSpool() error = %v", err)
+	}
+
+	for _, id := range []string{"", "../outside", ".hidden"} {
+		if err := s.Put(context.Background(), &entity.Receipt{ID: id}); err == nil {
+			t.Errorf("Put(%q) error = nil, want error", id)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"vision-api-app/internal/modules/shared/domain"
+)
+
+// S3Config S3互換のオブジェクトストレージの接続設定
+type S3Config struct {
+	Endpoint        string // 例: https://s3.ap-northeast-1.amazonaws.com（空の場合はRegionのAWSのエンドポイント）
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	Prefix          string // キーの前に付けるパス（例: warehouse）
+}
+
+// S3ObjectStore S3互換のオブジェクトストレージ（AWS S3・MinIO・Cloudflare R2など）の実装
+// パス形式のURL（エンドポイント/バケット/キー）にAWS署名バージョン4で署名して送る
+type S3ObjectStore struct {
+	endpoint *url.URL
+	config   S3Config
+	client   *http.Client
+	now      func() time.Time
+}
+
+// NewS3ObjectStore 新しいS3ObjectStoreを作成
+func NewS3ObjectStore(config S3Config) (*S3ObjectStore, error) {
+	if config.Bucket == "" || config.Region == "" {
+		return nil, errors.New("s3 bucket and region are required")
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, errors.New("s3 access key is required")
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://s3." + config.Region + ".amazonaws.com"
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(config.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint: %q", config.Endpoint)
+	}
+	config.Prefix = strings.Trim(config.Prefix, "/")
+
+	return &S3ObjectStore{
+		endpoint: endpoint,
+		config:   config,
+		client:   &http.Client{Timeout: 60 * time.Second},
+		now:      time.Now,
+	}, nil
+}
+
+// SetTransport 外部への接続に使うTransportを設定（プロキシ・追加のルート証明書など、未設定の場合はhttp.DefaultTransport）
+func (s *S3ObjectStore) SetTransport(transport http.RoundTripper) {
+	s.client.Transport = transport
+}
+
+// Put オブジェクトを保存
+func (s *S3ObjectStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.sign(req, data)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to put object: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to put object %s: %w", key, statusError(resp))
+	}
+	return nil
+}
+
+// Get オブジェクトを取得
+func (s *S3ObjectStore) Get(ctx context.Context, key string) ([]byte, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, nil)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", domain.ErrObjectNotFound, key)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get object %s: %w", key, statusError(resp))
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object: %w", err)
+	}
+	return data, nil
+}
+
+// Delete オブジェクトを削除（存在しない場合もエラーにしない）
+func (s *S3ObjectStore) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	s.sign(req, nil)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete object: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("failed to delete object %s: %w", key, statusError(resp))
+	}
+	return nil
+}
+
+// PresignPut キーのオブジェクトをPUTで直接アップロードできる署名付きURLを発行
+func (s *S3ObjectStore) PresignPut(key string, expires time.Duration) (string, error) {
+	req, err := s.newRequest(context.Background(), http.MethodPut, key, nil)
+	if err != nil {
+		return "", err
+	}
+	s.presign(req, expires)
+	return req.URL.String(), nil
+}
+
+// newRequest キーのオブジェクトへのリクエストを作成
+// パスは署名と同じ規則でエンコードし、送るパスと署名するパスを一致させる
+func (s *S3ObjectStore) newRequest(ctx context.Context, method, key string, body []byte) (*http.Request, error) {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return nil, fmt.Errorf("invalid object key: %q", key)
+	}
+	path := "/" + s.config.Bucket + "/" + key
+	if s.config.Prefix != "" {
+		path = "/" + s.config.Bucket + "/" + s.config.Prefix + "/" + key
+	}
+
+	u := *s.endpoint
+	u.Path = s.endpoint.Path + path
+	u.RawPath = s.endpoint.EscapedPath() + uriEncode(path, false)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create object request: %w", err)
+	}
+	return req, nil
+}
+
+// sign リクエストにAWS署名バージョン4のAuthorizationヘッダーを付ける
+// Hostと設定済みのすべてのヘッダーを署名の対象にする
+func (s *S3ObjectStore) sign(req *http.Request, payload []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := s.scope(date)
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+	signature := hex.EncodeToString(hmacSHA256(s.signingKey(date), stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// presign リクエストのURLにAWS署名バージョン4のクエリ文字列の署名を付ける
+// 署名するヘッダーはHostのみで、本文は署名しない（UNSIGNED-PAYLOAD）
+func (s *S3ObjectStore) presign(req *http.Request, expires time.Duration) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	scope := s.scope(date)
+
+	query := req.URL.Query()
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", s.config.AccessKeyID+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.FormatInt(int64(expires/time.Second), 10))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(query),
+		"host:" + req.URL.Host + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+	query.Set("X-Amz-Signature", hex.EncodeToString(hmacSHA256(s.signingKey(date), stringToSign)))
+
+	req.URL.RawQuery = canonicalQuery(query)
+}
+
+// scope 署名の日付・リージョンの範囲
+func (s *S3ObjectStore) scope(date string) string {
+	return date + "/" + s.config.Region + "/s3/aws4_request"
+}
+
+// signingKey 日付・リージョンの範囲の署名鍵をシークレットアクセスキーから導出
+func (s *S3ObjectStore) signingKey(date string) []byte {
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretAccessKey), date)
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, "s3")
+	return hmacSHA256(key, "aws4_request")
+}
+
+// canonicalQuery 署名用にクエリ文字列をキーの順に並べてエンコードする
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, k := range keys {
+		values := append([]string(nil), query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			pairs = append(pairs, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode AWS署名の規則でエンコードする（英数字と -._~ 以外をエンコードし、encodeSlashがfalseなら "/" は残す）
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '.', c == '_', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// statusError エラーのレスポンスのステータスと本文の先頭をエラーにする
+func statusError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("object store returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
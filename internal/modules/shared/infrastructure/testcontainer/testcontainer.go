@@ -62,7 +62,7 @@ func StartRedis(ctx context.Context, t *testing.T) (*RedisContainer, error) {
 }
 
 // StartMySQL MySQLコンテナを起動
-func StartMySQL(ctx context.Context, t *testing.T) (*MySQLContainer, error) {
+func StartMySQL(ctx context.Context, t testing.TB) (*MySQLContainer, error) {
 	t.Helper()
 
 	const (
@@ -0,0 +1,155 @@
+package testsupport
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// FakeAnthropicServer Anthropic Messages API互換のテスト用サーバー
+// リクエストボディのSHA256ハッシュをキーにゴールデンレスポンスを返す
+type FakeAnthropicServer struct {
+	server    *httptest.Server
+	goldenDir string
+
+	mu        sync.Mutex
+	responses map[string][]byte
+	fallback  []byte
+	requests  [][]byte
+}
+
+// NewFakeAnthropicServer 新しいFakeAnthropicServerを起動
+// goldenDirが空でない場合、<goldenDir>/<hash>.json をレスポンスとして使用する
+// サーバーはテスト終了時に自動で停止される
+func NewFakeAnthropicServer(t *testing.T, goldenDir string) *FakeAnthropicServer {
+	t.Helper()
+
+	s := &FakeAnthropicServer{
+		goldenDir: goldenDir,
+		responses: make(map[string][]byte),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.server.Close)
+
+	return s
+}
+
+// URL Messages APIのエンドポイントURLを返す
+func (s *FakeAnthropicServer) URL() string {
+	return s.server.URL + "/v1/messages"
+}
+
+// Client サーバーに接続するHTTPクライアントを返す
+func (s *FakeAnthropicServer) Client() *http.Client {
+	return s.server.Client()
+}
+
+// Register リクエストボディに対するレスポンスを登録
+func (s *FakeAnthropicServer) Register(requestBody, responseBody []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses[RequestHash(requestBody)] = responseBody
+}
+
+// SetFallback 一致するゴールデンレスポンスがない場合のレスポンスを設定
+func (s *FakeAnthropicServer) SetFallback(responseBody []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fallback = responseBody
+}
+
+// RequestCount 受信したリクエスト数を返す
+func (s *FakeAnthropicServer) RequestCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+// Requests 受信したリクエストボディを受信順に返す
+func (s *FakeAnthropicServer) Requests() [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	requests := make([][]byte, len(s.requests))
+	copy(requests, s.requests)
+	return requests
+}
+
+// handle リクエストハッシュに対応するレスポンスを返す
+func (s *FakeAnthropicServer) handle(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	hash := RequestHash(body)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, body)
+	response, ok := s.responses[hash]
+	fallback := s.fallback
+	s.mu.Unlock()
+
+	if !ok && s.goldenDir != "" {
+		if data, err := os.ReadFile(filepath.Join(s.goldenDir, hash+".json")); err == nil {
+			response, ok = data, true
+		}
+	}
+	if !ok && fallback != nil {
+		response, ok = fallback, true
+	}
+	if !ok {
+		// ゴールデンファイル作成時に必要なハッシュをエラーメッセージに含める
+		http.Error(w, fmt.Sprintf("no golden response for request hash %s", hash), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(response)
+}
+
+// RequestHash リクエストボディのハッシュを返す
+func RequestHash(body []byte) string {
+	hash := sha256.Sum256(body)
+	return hex.EncodeToString(hash[:])
+}
+
+// MessageResponse Messages API形式のレスポンスJSONを生成
+func MessageResponse(text string, inputTokens, outputTokens int) []byte {
+	return messageResponse(text, "end_turn", inputTokens, outputTokens)
+}
+
+// TruncatedMessageResponse 出力が最大出力トークン数で途中で切れたMessages API形式のレスポンスJSONを生成
+func TruncatedMessageResponse(text string, inputTokens, outputTokens int) []byte {
+	return messageResponse(text, "max_tokens", inputTokens, outputTokens)
+}
+
+// messageResponse stop_reasonを指定してMessages API形式のレスポンスJSONを生成
+func messageResponse(text, stopReason string, inputTokens, outputTokens int) []byte {
+	response := map[string]interface{}{
+		"id":          "msg_test",
+		"type":        "message",
+		"role":        "assistant",
+		"model":       "claude-haiku-4-5-20251001",
+		"stop_reason": stopReason,
+		"content": []map[string]string{
+			{"type": "text", "text": text},
+		},
+		"usage": map[string]int{
+			"input_tokens":  inputTokens,
+			"output_tokens": outputTokens,
+		},
+	}
+
+	data, _ := json.Marshal(response)
+	return data
+}
@@ -0,0 +1,64 @@
+package testsupport
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/uptrace/bun"
+)
+
+// QueryCounter 実行したSQLを記録するBunのクエリフック（N+1の検出用）
+type QueryCounter struct {
+	mu      sync.Mutex
+	queries []string
+}
+
+// CountQueries DBにQueryCounterを追加する
+// Bunのクエリフックは削除できないため、テストごとに作成したDBで使用する
+func CountQueries(db *bun.DB) *QueryCounter {
+	c := &QueryCounter{}
+	db.AddQueryHook(c)
+	return c
+}
+
+// BeforeQuery 何もしない（bun.QueryHookの実装）
+func (c *QueryCounter) BeforeQuery(ctx context.Context, _ *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+// AfterQuery 実行したSQLを記録（bun.QueryHookの実装）
+func (c *QueryCounter) AfterQuery(_ context.Context, event *bun.QueryEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queries = append(c.queries, event.Query)
+}
+
+// Count Resetの後に実行したSQLの数
+func (c *QueryCounter) Count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.queries)
+}
+
+// Reset 記録したSQLを消去
+func (c *QueryCounter) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queries = nil
+}
+
+// AssertAtMost Resetの後に実行したSQLがlimit件以下であることを確認し、記録を消去する
+// 超えた場合は実行したSQLを一覧にしてテストを失敗させる
+func (c *QueryCounter) AssertAtMost(t testing.TB, limit int) {
+	t.Helper()
+	c.mu.Lock()
+	queries := c.queries
+	c.queries = nil
+	c.mu.Unlock()
+
+	if len(queries) > limit {
+		t.Errorf("executed %d queries, want at most %d:\n%s", len(queries), limit, strings.Join(queries, "\n"))
+	}
+}
@@ -0,0 +1,73 @@
+package bufpool
+
+import (
+	"bytes"
+	"expvar"
+	"io"
+	"sync"
+)
+
+// maxRetainedSize プールに戻すバッファの最大容量（これより大きいバッファは捨ててメモリを解放する）
+const maxRetainedSize = 16 << 20 // 16MB
+
+// bytes.MinRead 分の余裕を持たせてReadFromが読み込みの最後に容量を倍増させないようにする
+const readSlack = bytes.MinRead
+
+// metrics アップロードの読み込みバッファの割り当て状況（/debug/vars の upload_buffers で公開）
+var metrics = expvar.NewMap("upload_buffers")
+
+var pool = sync.Pool{
+	New: func() any {
+		metrics.Add("allocated", 1)
+		return new(bytes.Buffer)
+	},
+}
+
+// Buffer プールから取得した読み込みバッファ
+// Releaseした後はBytesで取得したスライスを参照してはならない
+type Buffer struct {
+	buf *bytes.Buffer
+}
+
+// ReadAll rの内容をプールのバッファに読み込む（sizeHintが分かる場合は事前に容量を確保する）
+// 読み込みに失敗した場合もバッファはプールに戻す
+func ReadAll(r io.Reader, sizeHint int64) (*Buffer, error) {
+	metrics.Add("gets", 1)
+	buf := pool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	initial := buf.Cap()
+	if sizeHint > 0 && sizeHint <= maxRetainedSize {
+		buf.Grow(int(sizeHint) + readSlack)
+	}
+	_, err := buf.ReadFrom(r)
+	if grown := buf.Cap() - initial; grown > 0 {
+		metrics.Add("grown_bytes", int64(grown))
+	}
+	b := &Buffer{buf: buf}
+	if err != nil {
+		b.Release()
+		return nil, err
+	}
+	metrics.Add("read_bytes", int64(buf.Len()))
+	return b, nil
+}
+
+// Bytes 読み込んだデータ
+func (b *Buffer) Bytes() []byte {
+	return b.buf.Bytes()
+}
+
+// Release バッファをプールに戻す（複数回呼び出してもよい）
+func (b *Buffer) Release() {
+	if b == nil || b.buf == nil {
+		return
+	}
+	if b.buf.Cap() > maxRetainedSize {
+		metrics.Add("discarded", 1)
+	} else {
+		b.buf.Reset()
+		pool.Put(b.buf)
+	}
+	b.buf = nil
+}
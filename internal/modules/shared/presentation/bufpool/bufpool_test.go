@@ -0,0 +1,88 @@
+package bufpool
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReadAll(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		sizeHint int64
+		readErr  error
+		wantErr  bool
+	}{
+		{name: "正常系: サイズ不明", data: bytes.Repeat([]byte("a"), 100000)},
+		{name: "正常系: サイズを指定", data: bytes.Repeat([]byte("b"), 100000), sizeHint: 100000},
+		{name: "正常系: 空のデータ", data: nil},
+		{name: "異常系: 読み込みに失敗", readErr: errors.New("read error"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r io.Reader = bytes.NewReader(tt.data)
+			if tt.readErr != nil {
+				r = iotest.ErrReader(tt.readErr)
+			}
+
+			buf, err := ReadAll(r, tt.sizeHint)
+			if tt.wantErr {
+				if !errors.Is(err, tt.readErr) {
+					t.Fatalf("ReadAll() error = %v, want %v", err, tt.readErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+			if !bytes.Equal(buf.Bytes(), tt.data) {
+				t.Errorf("Bytes() = %d bytes, want %d bytes", len(buf.Bytes()), len(tt.data))
+			}
+			buf.Release()
+			buf.Release()
+		})
+	}
+}
+
+func TestRelease_DiscardsLargeBuffer(t *testing.T) {
+	before := metrics.Get("discarded")
+	buf, err := ReadAll(bytes.NewReader(make([]byte, maxRetainedSize+1)), 0)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	buf.Release()
+
+	after := metrics.Get("discarded")
+	if after == nil || (before != nil && after.String() == before.String()) {
+		t.Errorf("discarded = %v, want incremented from %v", after, before)
+	}
+}
+
+// BenchmarkReadAll 5MBの画像の読み込み（-benchmem で io.ReadAll と割り当てを比較）
+func BenchmarkReadAll(b *testing.B) {
+	data := make([]byte, 5<<20)
+
+	b.Run("io.ReadAll", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := io.ReadAll(bytes.NewReader(data)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("pool", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			buf, err := ReadAll(bytes.NewReader(data), int64(len(data)))
+			if err != nil {
+				b.Fatal(err)
+			}
+			buf.Release()
+		}
+	})
+}
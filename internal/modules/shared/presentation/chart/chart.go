@@ -0,0 +1,154 @@
+// Package chart レポート・通知に添付するグラフ（カテゴリー別の円グラフ、月別の推移の折れ線グラフ）のPNG画像の描画
+// フォントを同梱しないため、画像に描く文字は数字と一部の記号（, . % - / :）に限り、
+// カテゴリー名などの凡例は呼び出し側がPalette の色とあわせて表示する
+package chart
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+)
+
+// 画像の大きさのデフォルト（ピクセル）
+const (
+	DefaultWidth  = 640
+	DefaultHeight = 360
+)
+
+// 画像の大きさの範囲（ピクセル）
+const (
+	MinSize = 120
+	MaxSize = 2000
+)
+
+// Palette 系列・項目の色（項目が多い場合は繰り返す）
+var Palette = []color.RGBA{
+	{R: 0x4e, G: 0x79, B: 0xa7, A: 0xff},
+	{R: 0xf2, G: 0x8e, B: 0x2b, A: 0xff},
+	{R: 0xe1, G: 0x57, B: 0x59, A: 0xff},
+	{R: 0x76, G: 0xb7, B: 0xb2, A: 0xff},
+	{R: 0x59, G: 0xa1, B: 0x4f, A: 0xff},
+	{R: 0xed, G: 0xc9, B: 0x48, A: 0xff},
+	{R: 0xb0, G: 0x7a, B: 0xa1, A: 0xff},
+	{R: 0xff, G: 0x9d, B: 0xa7, A: 0xff},
+	{R: 0x9c, G: 0x75, B: 0x5f, A: 0xff},
+	{R: 0xba, G: 0xb0, B: 0xac, A: 0xff},
+}
+
+// 背景・軸・文字の色
+var (
+	backgroundColor = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	axisColor       = color.RGBA{R: 0x66, G: 0x66, B: 0x66, A: 0xff}
+	gridColor       = color.RGBA{R: 0xe0, G: 0xe0, B: 0xe0, A: 0xff}
+	textColor       = color.RGBA{R: 0x33, G: 0x33, B: 0x33, A: 0xff}
+)
+
+// Color i番目の項目の色
+func Color(i int) color.RGBA {
+	return Palette[i%len(Palette)]
+}
+
+// Slice 円グラフの項目
+type Slice struct {
+	Label string // 凡例に表示する名前（画像には描かない）
+	Value int64  // 0以下の項目は描かない
+}
+
+// Point 折れ線グラフの点
+type Point struct {
+	Label string // 横軸の目盛り（数字と記号のみ描画する、例: "1"、"2025-01"）
+	Value int64
+}
+
+// Size 画像の大きさを範囲内に収める（0の場合はデフォルト）
+func Size(width, height int) (int, int) {
+	if width == 0 {
+		width = DefaultWidth
+	}
+	if height == 0 {
+		height = DefaultHeight
+	}
+	return min(max(width, MinSize), MaxSize), min(max(height, MinSize), MaxSize)
+}
+
+// EncodePNG 画像をPNGで書き出す
+func EncodePNG(w io.Writer, img image.Image) error {
+	if err := png.Encode(w, img); err != nil {
+		return fmt.Errorf("failed to encode chart: %w", err)
+	}
+	return nil
+}
+
+// newCanvas 背景を塗った画像を作成
+func newCanvas(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	fillRect(img, img.Bounds(), backgroundColor)
+	return img
+}
+
+// fillRect 長方形を塗りつぶす
+func fillRect(img *image.RGBA, rect image.Rectangle, c color.RGBA) {
+	rect = rect.Intersect(img.Bounds())
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+// blend 画素にcoverage（0〜1）の割合で色を重ねる
+func blend(img *image.RGBA, x, y int, c color.RGBA, coverage float64) {
+	if !(image.Point{X: x, Y: y}.In(img.Bounds())) || coverage <= 0 {
+		return
+	}
+	if coverage >= 1 {
+		img.SetRGBA(x, y, c)
+		return
+	}
+	bg := img.RGBAAt(x, y)
+	mix := func(a, b uint8) uint8 {
+		return uint8(math.Round(float64(a)*(1-coverage) + float64(b)*coverage))
+	}
+	img.SetRGBA(x, y, color.RGBA{R: mix(bg.R, c.R), G: mix(bg.G, c.G), B: mix(bg.B, c.B), A: 0xff})
+}
+
+// drawLine 太さwidthの線分を描く（端は丸める）
+func drawLine(img *image.RGBA, x1, y1, x2, y2, width float64, c color.RGBA) {
+	radius := width / 2
+	minX, maxX := int(math.Floor(min(x1, x2)-radius)), int(math.Ceil(max(x1, x2)+radius))
+	minY, maxY := int(math.Floor(min(y1, y2)-radius)), int(math.Ceil(max(y1, y2)+radius))
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			d := segmentDistance(float64(x)+0.5, float64(y)+0.5, x1, y1, x2, y2)
+			// 線の縁の1ピクセルを距離に応じて薄くしてなめらかにする
+			blend(img, x, y, c, radius+0.5-d)
+		}
+	}
+}
+
+// segmentDistance 点(px, py)と線分の距離
+func segmentDistance(px, py, x1, y1, x2, y2 float64) float64 {
+	dx, dy := x2-x1, y2-y1
+	t := 0.0
+	if length := dx*dx + dy*dy; length > 0 {
+		t = min(max(((px-x1)*dx+(py-y1)*dy)/length, 0), 1)
+	}
+	return math.Hypot(px-(x1+t*dx), py-(y1+t*dy))
+}
+
+// formatAmount 金額を3桁区切りの文字列に変換
+func formatAmount(v int64) string {
+	s := strconv.FormatInt(v, 10)
+	sign := ""
+	if v < 0 {
+		sign, s = "-", s[1:]
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return sign + s
+}
@@ -0,0 +1,180 @@
+package chart
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestPie(t *testing.T) {
+	img := Pie([]Slice{{Label: "食費", Value: 300}, {Label: "日用品", Value: 100}, {Label: "返品", Value: -50}}, 400, 200)
+	if img.Bounds().Dx() != 400 || img.Bounds().Dy() != 200 {
+		t.Fatalf("Pie() size = %v", img.Bounds())
+	}
+
+	// 円の中心は(100, 100)、半径は96。12時から時計回りに食費が3/4、日用品が1/4
+	tests := []struct {
+		name string
+		x, y int
+		want color.RGBA
+	}{
+		{name: "right", x: 150, y: 100, want: Color(0)},
+		{name: "bottom", x: 100, y: 150, want: Color(0)},
+		{name: "upper left", x: 60, y: 60, want: Color(1)},
+		{name: "outside", x: 2, y: 2, want: backgroundColor},
+	}
+	for _, tt := range tests {
+		if got := img.RGBAAt(tt.x, tt.y); got != tt.want {
+			t.Errorf("%s: pixel(%d, %d) = %v, want %v", tt.name, tt.x, tt.y, got, tt.want)
+		}
+	}
+
+	// 凡例は0以下の項目を除いた2行
+	legend := 0
+	for y := range 200 {
+		if img.RGBAAt(206, y) == Color(0) || img.RGBAAt(206, y) == Color(1) {
+			legend++
+		}
+	}
+	if legend == 0 {
+		t.Error("Pie() legend swatches not drawn")
+	}
+	for y := range 200 {
+		if img.RGBAAt(206, y) == Color(2) {
+			t.Fatal("Pie() drew legend for non-positive slice")
+		}
+	}
+}
+
+func TestPie_WithoutLegend(t *testing.T) {
+	img := Pie([]Slice{{Label: "食費", Value: 100}}, 200, 200)
+	if got := img.RGBAAt(100, 100); got != Color(0) {
+		t.Errorf("Pie() center = %v, want %v", got, Color(0))
+	}
+	if got := img.RGBAAt(1, 1); got != backgroundColor {
+		t.Errorf("Pie() corner = %v, want %v", got, backgroundColor)
+	}
+}
+
+func TestPie_Empty(t *testing.T) {
+	img := Pie(nil, 0, 0)
+	if img.Bounds().Dx() != DefaultWidth || img.Bounds().Dy() != DefaultHeight {
+		t.Fatalf("Pie() size = %v", img.Bounds())
+	}
+	if got := img.RGBAAt(DefaultHeight/2, DefaultHeight/2); got != emptyColor {
+		t.Errorf("Pie() center = %v, want %v", got, emptyColor)
+	}
+}
+
+func TestLine(t *testing.T) {
+	points := []Point{{Label: "1", Value: 1000}, {Label: "2", Value: 3000}, {Label: "3", Value: 2000}}
+	img := Line(points, 300, 200)
+
+	var buf bytes.Buffer
+	if err := EncodePNG(&buf, img); err != nil {
+		t.Fatalf("EncodePNG() error = %v", err)
+	}
+	decoded, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("png.Decode() error = %v", err)
+	}
+	if decoded.Bounds() != img.Bounds() {
+		t.Errorf("decoded size = %v, want %v", decoded.Bounds(), img.Bounds())
+	}
+
+	lineColor := 0
+	for y := range 200 {
+		for x := range 300 {
+			if img.RGBAAt(x, y) == Color(0) {
+				lineColor++
+			}
+		}
+	}
+	if lineColor == 0 {
+		t.Error("Line() did not draw the series")
+	}
+
+	// 点がない場合も軸だけ描く
+	if img := Line(nil, 0, 0); img.Bounds().Dx() != DefaultWidth {
+		t.Errorf("Line(nil) size = %v", img.Bounds())
+	}
+}
+
+func TestTopSlices(t *testing.T) {
+	var slices []Slice
+	for i, label := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "その他", "i", "j", "k"} {
+		slices = append(slices, Slice{Label: label, Value: int64(100 - i)})
+	}
+	slices = append(slices, Slice{Label: "refund", Value: -10}, Slice{Label: "zero"})
+
+	got := TopSlices(slices, "その他")
+	if len(got) != len(Palette) {
+		t.Fatalf("TopSlices() = %d slices, want %d", len(got), len(Palette))
+	}
+	// 上位9件（「その他」を除く）と、残りと既存の「その他」をまとめた項目
+	if got[0].Label != "a" || got[8].Label != "i" {
+		t.Errorf("TopSlices() order = %+v", got)
+	}
+	if last := got[len(got)-1]; last.Label != "その他" || last.Value != 92+90+89 {
+		t.Errorf("TopSlices() other = %+v, want その他 %d", last, 92+90+89)
+	}
+
+	// 色数以下の場合はまとめずに並べ替えるだけ
+	got = TopSlices([]Slice{{Label: "b", Value: 10}, {Label: "a", Value: 10}, {Label: "c", Value: 20}}, "その他")
+	if len(got) != 3 || got[0].Label != "c" || got[1].Label != "a" {
+		t.Errorf("TopSlices() = %+v", got)
+	}
+}
+
+func TestSize(t *testing.T) {
+	tests := []struct {
+		width, height int
+		wantW, wantH  int
+	}{
+		{width: 0, height: 0, wantW: DefaultWidth, wantH: DefaultHeight},
+		{width: 10, height: 5000, wantW: MinSize, wantH: MaxSize},
+		{width: 800, height: 400, wantW: 800, wantH: 400},
+	}
+	for _, tt := range tests {
+		if w, h := Size(tt.width, tt.height); w != tt.wantW || h != tt.wantH {
+			t.Errorf("Size(%d, %d) = %d, %d, want %d, %d", tt.width, tt.height, w, h, tt.wantW, tt.wantH)
+		}
+	}
+}
+
+func TestNiceStep(t *testing.T) {
+	tests := []struct {
+		raw  float64
+		want float64
+	}{
+		{raw: 0, want: 1},
+		{raw: 0.3, want: 0.5},
+		{raw: 12500, want: 20000},
+		{raw: 37500, want: 50000},
+		{raw: 100, want: 100},
+	}
+	for _, tt := range tests {
+		if got := niceStep(tt.raw); got != tt.want {
+			t.Errorf("niceStep(%v) = %v, want %v", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := map[int64]string{0: "0", 999: "999", 1000: "1,000", 1234567: "1,234,567", -98000: "-98,000"}
+	for v, want := range tests {
+		if got := formatAmount(v); got != want {
+			t.Errorf("formatAmount(%d) = %q, want %q", v, got, want)
+		}
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	if got := textWidth("1,000", 2); got != (5*glyphAdvance-1)*2 {
+		t.Errorf("textWidth() = %d", got)
+	}
+	if got := textWidth("", 2); got != 0 {
+		t.Errorf("textWidth(\"\") = %d, want 0", got)
+	}
+}
@@ -0,0 +1,69 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+)
+
+// 文字の大きさ（ピクセル、拡大前）
+const (
+	glyphWidth   = 5
+	glyphHeight  = 7
+	glyphAdvance = glyphWidth + 1
+)
+
+// glyphs 5×7ドットの字形（各行の下位5ビットを左から描く）
+var glyphs = map[rune][glyphHeight]uint8{
+	'0': {0b01110, 0b10001, 0b10011, 0b10101, 0b11001, 0b10001, 0b01110},
+	'1': {0b00100, 0b01100, 0b00100, 0b00100, 0b00100, 0b00100, 0b01110},
+	'2': {0b01110, 0b10001, 0b00001, 0b00010, 0b00100, 0b01000, 0b11111},
+	'3': {0b11111, 0b00010, 0b00100, 0b00010, 0b00001, 0b10001, 0b01110},
+	'4': {0b00010, 0b00110, 0b01010, 0b10010, 0b11111, 0b00010, 0b00010},
+	'5': {0b11111, 0b10000, 0b11110, 0b00001, 0b00001, 0b10001, 0b01110},
+	'6': {0b00110, 0b01000, 0b10000, 0b11110, 0b10001, 0b10001, 0b01110},
+	'7': {0b11111, 0b00001, 0b00010, 0b00100, 0b01000, 0b01000, 0b01000},
+	'8': {0b01110, 0b10001, 0b10001, 0b01110, 0b10001, 0b10001, 0b01110},
+	'9': {0b01110, 0b10001, 0b10001, 0b01111, 0b00001, 0b00010, 0b01100},
+	',': {0b00000, 0b00000, 0b00000, 0b00000, 0b00110, 0b00100, 0b01000},
+	'.': {0b00000, 0b00000, 0b00000, 0b00000, 0b00000, 0b01100, 0b01100},
+	'%': {0b11000, 0b11001, 0b00010, 0b00100, 0b01000, 0b10011, 0b00011},
+	'-': {0b00000, 0b00000, 0b00000, 0b11111, 0b00000, 0b00000, 0b00000},
+	'/': {0b00000, 0b00001, 0b00010, 0b00100, 0b01000, 0b10000, 0b00000},
+	':': {0b00000, 0b01100, 0b01100, 0b00000, 0b01100, 0b01100, 0b00000},
+}
+
+// textWidth 文字列を描いた幅（scaleは1ドットのピクセル数）
+func textWidth(text string, scale int) int {
+	n := len([]rune(text))
+	if n == 0 {
+		return 0
+	}
+	return (n*glyphAdvance - 1) * scale
+}
+
+// textHeight 文字の高さ
+func textHeight(scale int) int {
+	return glyphHeight * scale
+}
+
+// drawText 文字列を描く（x, yは左上の位置、字形のない文字は空白にする）
+func drawText(img *image.RGBA, x, y, scale int, text string, c color.RGBA) {
+	for _, r := range text {
+		glyph, ok := glyphs[r]
+		if ok {
+			for row, bits := range glyph {
+				for col := range glyphWidth {
+					if bits&(1<<(glyphWidth-1-col)) != 0 {
+						fillRect(img, image.Rect(x+col*scale, y+row*scale, x+(col+1)*scale, y+(row+1)*scale), c)
+					}
+				}
+			}
+		}
+		x += glyphAdvance * scale
+	}
+}
+
+// textScale 画像の大きさに合わせた文字の拡大率
+func textScale(width, height int) int {
+	return max(1, min(width, height)/180)
+}
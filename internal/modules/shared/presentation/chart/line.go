@@ -0,0 +1,94 @@
+package chart
+
+import (
+	"image"
+	"math"
+)
+
+// lineTicks 縦軸の目盛りの数の目安
+const lineTicks = 4
+
+// Line 月別の支出の推移などの折れ線グラフを描く
+// 縦軸は0（負の値がある場合は最小値）から最大値を含むきりのよい値まで、横軸の目盛りは重ならないよう間引いて描く
+func Line(points []Point, width, height int) *image.RGBA {
+	width, height = Size(width, height)
+	img := newCanvas(width, height)
+	scale := textScale(width, height)
+	margin := 4 * scale
+
+	var low, high int64
+	for _, point := range points {
+		low, high = min(low, point.Value), max(high, point.Value)
+	}
+	step := max(niceStep(float64(high-low)/lineTicks), 1) // 金額は整数のため1未満にしない
+	bottom := math.Floor(float64(low)/step) * step
+	top := math.Max(math.Ceil(float64(high)/step)*step, bottom+step)
+
+	// 縦軸の目盛りの文字の幅だけ左を空ける
+	labelWidth := 0
+	for v := bottom; v <= top; v += step {
+		labelWidth = max(labelWidth, textWidth(formatAmount(int64(v)), scale))
+	}
+	plot := image.Rect(margin*2+labelWidth, margin+textHeight(scale)/2, width-margin*2, height-margin*2-textHeight(scale))
+	yOf := func(v float64) float64 {
+		return float64(plot.Max.Y) - (v-bottom)/(top-bottom)*float64(plot.Dy())
+	}
+
+	for v := bottom; v <= top; v += step {
+		y := yOf(v)
+		drawLine(img, float64(plot.Min.X), y, float64(plot.Max.X), y, 1, gridColor)
+		label := formatAmount(int64(v))
+		drawText(img, plot.Min.X-margin-textWidth(label, scale), int(y)-textHeight(scale)/2, scale, label, textColor)
+	}
+	drawLine(img, float64(plot.Min.X), float64(plot.Min.Y), float64(plot.Min.X), float64(plot.Max.Y), 1, axisColor)
+	drawLine(img, float64(plot.Min.X), yOf(0), float64(plot.Max.X), yOf(0), 1, axisColor)
+
+	if len(points) == 0 {
+		return img
+	}
+
+	// 点は横軸の区間の中央に置く
+	xOf := func(i int) float64 {
+		return float64(plot.Min.X) + (float64(i)+0.5)*float64(plot.Dx())/float64(len(points))
+	}
+
+	// 横軸の目盛りは最も長いものが重ならない間隔で描く
+	maxLabel := 0
+	for _, point := range points {
+		maxLabel = max(maxLabel, textWidth(point.Label, scale))
+	}
+	every := 1
+	if slot := float64(plot.Dx()) / float64(len(points)); slot > 0 {
+		every = max(1, int(math.Ceil(float64(maxLabel+glyphAdvance*scale*2)/slot)))
+	}
+	for i, point := range points {
+		if i%every != 0 {
+			continue
+		}
+		drawText(img, int(xOf(i))-textWidth(point.Label, scale)/2, plot.Max.Y+margin, scale, point.Label, textColor)
+	}
+
+	lineWidth := float64(scale) * 1.5
+	for i := 1; i < len(points); i++ {
+		drawLine(img, xOf(i-1), yOf(float64(points[i-1].Value)), xOf(i), yOf(float64(points[i].Value)), lineWidth, Color(0))
+	}
+	for i, point := range points {
+		x, y := xOf(i), yOf(float64(point.Value))
+		drawLine(img, x, y, x, y, lineWidth*2.5, Color(0))
+	}
+	return img
+}
+
+// niceStep 目盛りの間隔を1・2・5の10のべき乗倍に切り上げる
+func niceStep(raw float64) float64 {
+	if raw <= 0 {
+		return 1
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if raw <= m*magnitude {
+			return m * magnitude
+		}
+	}
+	return 10 * magnitude
+}
@@ -0,0 +1,140 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// pieSamples 円の縁をなめらかにするための1ピクセルあたりの縦横の標本数
+const pieSamples = 4
+
+// emptyColor 項目がない場合の円の色
+var emptyColor = color.RGBA{R: 0xee, G: 0xee, B: 0xee, A: 0xff}
+
+// Pie カテゴリー別の内訳などの円グラフを描く
+// 項目は12時の位置から時計回りに並べ、右側の凡例に色・割合・金額を描く（名前はPaletteの色で呼び出し側が対応付ける）
+// 凡例に収まらない項目は円には描くが凡例を省略する。幅が高さ以下の場合は凡例を描かず円だけを描く（呼び出し側で凡例を表示する場合）
+func Pie(slices []Slice, width, height int) *image.RGBA {
+	width, height = Size(width, height)
+	img := newCanvas(width, height)
+	scale := textScale(width, height)
+	margin := 4 * scale
+
+	var total int64
+	for _, slice := range slices {
+		total += max(slice.Value, 0)
+	}
+
+	// 円は左側、凡例は右側に描く
+	legend := width > height
+	diameter := min(height, width) - 2*margin
+	if legend {
+		diameter = min(diameter, width*3/5-2*margin)
+	}
+	cx, cy, radius := float64(margin)+float64(diameter)/2, float64(height)/2, float64(diameter)/2
+
+	// 各項目の終わりの角度（12時の位置を0とした時計回りの割合）
+	ends := make([]float64, len(slices))
+	var sum int64
+	for i, slice := range slices {
+		sum += max(slice.Value, 0)
+		if total > 0 {
+			ends[i] = float64(sum) / float64(total)
+		}
+	}
+
+	for y := int(cy - radius - 1); y <= int(cy+radius+1); y++ {
+		for x := int(cx - radius - 1); x <= int(cx+radius+1); x++ {
+			var r, g, b, hits float64
+			for sy := range pieSamples {
+				for sx := range pieSamples {
+					px := float64(x) + (float64(sx)+0.5)/pieSamples - cx
+					py := float64(y) + (float64(sy)+0.5)/pieSamples - cy
+					if px*px+py*py > radius*radius {
+						continue
+					}
+					c := emptyColor
+					if total > 0 {
+						c = Color(pieSlice(ends, px, py))
+					}
+					r, g, b, hits = r+float64(c.R), g+float64(c.G), b+float64(c.B), hits+1
+				}
+			}
+			if hits > 0 {
+				c := color.RGBA{R: uint8(r / hits), G: uint8(g / hits), B: uint8(b / hits), A: 0xff}
+				blend(img, x, y, c, hits/(pieSamples*pieSamples))
+			}
+		}
+	}
+
+	// 凡例（色・割合・金額）
+	if total <= 0 || !legend {
+		return img
+	}
+	x := margin*3 + diameter
+	lineHeight := textHeight(scale) * 2
+	y := max(margin, (height-lineHeight*len(slices))/2)
+	for i, slice := range slices {
+		if slice.Value <= 0 {
+			continue
+		}
+		if y+textHeight(scale) > height-margin {
+			break
+		}
+		fillRect(img, image.Rect(x, y, x+textHeight(scale), y+textHeight(scale)), Color(i))
+		percent := strconv.FormatFloat(math.Round(float64(slice.Value)*1000/float64(total))/10, 'f', 1, 64) + "%"
+		drawText(img, x+textHeight(scale)*2, y, scale, percent, textColor)
+		drawText(img, x+textHeight(scale)*2+textWidth("100.0%", scale)+glyphAdvance*scale*2, y, scale, formatAmount(slice.Value), textColor)
+		y += lineHeight
+	}
+	return img
+}
+
+// TopSlices 金額の大きい順（同額は名前順）に並べ、0以下の項目を除いた円グラフの項目を返す
+// Paletteの色数を超える項目はotherの名前の最後の項目にまとめる（同じ名前の項目も含める）
+func TopSlices(slices []Slice, other string) []Slice {
+	sorted := make([]Slice, 0, len(slices))
+	for _, slice := range slices {
+		if slice.Value > 0 {
+			sorted = append(sorted, slice)
+		}
+	}
+	sort.Slice(sorted, func(a, b int) bool {
+		if sorted[a].Value != sorted[b].Value {
+			return sorted[a].Value > sorted[b].Value
+		}
+		return sorted[a].Label < sorted[b].Label
+	})
+	if len(sorted) <= len(Palette) {
+		return sorted
+	}
+
+	rest := Slice{Label: other}
+	top := make([]Slice, 0, len(Palette))
+	for _, slice := range sorted {
+		if len(top) < len(Palette)-1 && slice.Label != other {
+			top = append(top, slice)
+		} else {
+			rest.Value += slice.Value
+		}
+	}
+	return append(top, rest)
+}
+
+// pieSlice 円の中心からの位置(px, py)を含む項目
+func pieSlice(ends []float64, px, py float64) int {
+	// 12時の位置を0とした時計回りの割合（画像のyは下向き）
+	angle := math.Atan2(px, -py) / (2 * math.Pi)
+	if angle < 0 {
+		angle++
+	}
+	for i, end := range ends {
+		if angle < end {
+			return i
+		}
+	}
+	return len(ends) - 1
+}
@@ -0,0 +1,224 @@
+// Package pdf 帳票の出力に使う最小限のPDFの作成
+// フォントは埋め込まず、PDFビューアーが標準で持つ日本語フォント（HeiseiKakuGo-W5）を参照する
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// A4の用紙サイズ（ポイント）
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// fontName コンテンツから参照するフォントのリソース名
+const fontName = "F1"
+
+// Document 複数ページのPDF文書
+type Document struct {
+	title string
+	pages []*Page
+}
+
+// NewDocument 新しいDocumentを作成（titleは文書のプロパティに設定する）
+func NewDocument(title string) *Document {
+	return &Document{title: title}
+}
+
+// AddPage A4縦のページを追加
+func (d *Document) AddPage() *Page {
+	page := &Page{}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// Page 1ページ分の描画内容
+// 座標はポイント単位で、用紙の左上を原点とし下方向をyの正とする
+type Page struct {
+	content bytes.Buffer
+	images  []image.Image // 描画する画像（リソース名はIm1から順に付ける）
+}
+
+// Text 文字列を描画（x, yは文字列の左端・ベースラインの位置）
+func (p *Page) Text(x, y, size float64, text string) {
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s %s Td <%s> Tj ET\n",
+		fontName, formatNumber(size), formatNumber(x), formatNumber(PageHeight-y), encodeText(text))
+}
+
+// TextRight 文字列を右揃えで描画（rightは文字列の右端の位置）
+func (p *Page) TextRight(right, y, size float64, text string) {
+	p.Text(right-TextWidth(text, size), y, size, text)
+}
+
+// Line 線を描画
+func (p *Page) Line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(&p.content, "0.5 w %s %s m %s %s l S\n",
+		formatNumber(x1), formatNumber(PageHeight-y1), formatNumber(x2), formatNumber(PageHeight-y2))
+}
+
+// Rect 塗りつぶした長方形を描画（x, yは左上の位置）
+func (p *Page) Rect(x, y, width, height float64, c color.Color) {
+	r, g, b, _ := c.RGBA()
+	fmt.Fprintf(&p.content, "q %s %s %s rg %s %s %s %s re f Q\n",
+		formatNumber(float64(r)/0xffff), formatNumber(float64(g)/0xffff), formatNumber(float64(b)/0xffff),
+		formatNumber(x), formatNumber(PageHeight-y-height), formatNumber(width), formatNumber(height))
+}
+
+// Image 画像を描画（x, yは左上の位置、画像は幅・高さに合わせて拡大・縮小する）
+// 透過は扱わず、半透明の画素は白の背景と合成する
+func (p *Page) Image(x, y, width, height float64, img image.Image) {
+	p.images = append(p.images, img)
+	fmt.Fprintf(&p.content, "q %s 0 0 %s %s %s cm /Im%d Do Q\n",
+		formatNumber(width), formatNumber(height), formatNumber(x), formatNumber(PageHeight-y-height), len(p.images))
+}
+
+// TextWidth 文字列を描画した幅（半角文字は全角文字の半分の幅とする）
+func TextWidth(text string, size float64) float64 {
+	var units int
+	for _, r := range text {
+		if isHalfWidth(r) {
+			units += 500
+		} else {
+			units += 1000
+		}
+	}
+	return float64(units) * size / 1000
+}
+
+// isHalfWidth UniJIS-UCS2-HW-Hで半角の字形に対応する文字か
+func isHalfWidth(r rune) bool {
+	return (r >= 0x20 && r <= 0x7e) || (r >= 0xff61 && r <= 0xff9f)
+}
+
+// WriteTo PDFを書き出す
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	pages := d.pages
+	if len(pages) == 0 {
+		pages = []*Page{{}}
+	}
+
+	// 1: カタログ、2: ページツリー、3〜5: フォント、6: 文書情報、7以降: ページとコンテンツ、その後に画像
+	const firstPageObject = 7
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPageObject+i*2)
+	}
+	nextImageObject := firstPageObject + len(pages)*2
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type0 /BaseFont /HeiseiKakuGo-W5 /Encoding /UniJIS-UCS2-HW-H /DescendantFonts [4 0 R] >>",
+		"<< /Type /Font /Subtype /CIDFontType0 /BaseFont /HeiseiKakuGo-W5" +
+			" /CIDSystemInfo << /Registry (Adobe) /Ordering (Japan1) /Supplement 2 >>" +
+			" /FontDescriptor 5 0 R /DW 1000 /W [231 389 500 631 631 500] >>",
+		"<< /Type /FontDescriptor /FontName /HeiseiKakuGo-W5 /Flags 4 /FontBBox [-92 -250 1010 922]" +
+			" /ItalicAngle 0 /Ascent 752 /Descent -221 /CapHeight 737 /StemV 114 >>",
+		fmt.Sprintf("<< /Title <FEFF%s> /Producer (vision-api-app) >>", encodeText(d.title)),
+	}
+	var images []string
+	for i, page := range pages {
+		stream, err := compress(page.content.Bytes())
+		if err != nil {
+			return 0, err
+		}
+		var xobjects strings.Builder
+		for j, img := range page.images {
+			object, err := imageObject(img)
+			if err != nil {
+				return 0, err
+			}
+			images = append(images, object)
+			fmt.Fprintf(&xobjects, " /Im%d %d 0 R", j+1, nextImageObject)
+			nextImageObject++
+		}
+		resources := fmt.Sprintf("/Font << /%s 3 0 R >>", fontName)
+		if xobjects.Len() > 0 {
+			resources += " /XObject <<" + xobjects.String() + " >>"
+		}
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << %s >> /Contents %d 0 R >>",
+				formatNumber(PageWidth), formatNumber(PageHeight), resources, firstPageObject+i*2+1),
+			fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", len(stream), stream),
+		)
+	}
+	objects = append(objects, images...)
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 6 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return buf.WriteTo(w)
+}
+
+// compress コンテンツ・画像をFlateDecodeで圧縮
+func compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress stream: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress stream: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// imageObject 画像をRGBの画像オブジェクトに変換
+func imageObject(img image.Image) (string, error) {
+	bounds := img.Bounds()
+	pixels := make([]byte, 0, bounds.Dx()*bounds.Dy()*3)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			// 乗算済みのアルファ値で白と合成する
+			r, g, b, a := img.At(x, y).RGBA()
+			white := 0xffff - a
+			pixels = append(pixels, byte((r+white)>>8), byte((g+white)>>8), byte((b+white)>>8))
+		}
+	}
+	stream, err := compress(pixels)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8"+
+		" /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", bounds.Dx(), bounds.Dy(), len(stream), stream), nil
+}
+
+// encodeText 文字列をUTF-16BEの16進数に変換
+// UniJIS-UCS2-HW-HはBMP外の文字に対応しないため〓に置き換える
+func encodeText(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if r > 0xffff || utf16.IsSurrogate(r) {
+			r = '〓'
+		}
+		fmt.Fprintf(&b, "%04X", r)
+	}
+	return b.String()
+}
+
+// formatNumber 座標・サイズを小数点以下2桁までの文字列に変換
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
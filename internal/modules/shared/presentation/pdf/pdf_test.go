@@ -0,0 +1,158 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"image"
+	"image/color"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDocument_WriteTo(t *testing.T) {
+	doc := NewDocument("経費報告書")
+	page := doc.AddPage()
+	page.Text(40, 60, 18, "経費報告書")
+	page.TextRight(555, 100, 10, "¥1,500")
+	page.Line(40, 110, 555, 110)
+	doc.AddPage().Text(40, 60, 10, "2ページ目 🍣")
+
+	var buf bytes.Buffer
+	n, err := doc.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	data := buf.Bytes()
+	if n != int64(len(data)) {
+		t.Errorf("WriteTo() = %d, want %d", n, len(data))
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Fatalf("WriteTo() is not a PDF: %q", data[:min(len(data), 20)])
+	}
+
+	// 相互参照表の位置・各オブジェクトの位置が正しい
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(data)
+	if m == nil {
+		t.Fatal("startxref not found")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(data[xref:], []byte("xref\n0 11\n")) {
+		t.Fatalf("xref offset %d does not point to xref table", xref)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(data[xref:], -1)
+	if len(entries) != 10 {
+		t.Fatalf("xref entries = %d, want 10", len(entries))
+	}
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		if want := strconv.Itoa(i+1) + " 0 obj\n"; !bytes.HasPrefix(data[offset:], []byte(want)) {
+			t.Errorf("object %d offset %d points to %q", i+1, offset, data[offset:offset+10])
+		}
+	}
+
+	// 文書のタイトルはUTF-16BEで設定する
+	if !bytes.Contains(data, []byte("/Title <FEFF7D4C8CBB5831544A66F8>")) {
+		t.Error("Title is not encoded as UTF-16BE")
+	}
+
+	// ページのコンテンツはFlateDecodeで圧縮し、座標は左下原点に変換する
+	contents := pageContents(t, data)
+	if len(contents) != 2 {
+		t.Fatalf("page contents = %d, want 2", len(contents))
+	}
+	wantFirst := []string{
+		"BT /F1 18 Tf 40 781.89 Td <7D4C8CBB5831544A66F8> Tj ET",
+		// 右揃えは半角文字を全角の半分の幅として計算する（¥は全角、"1,500"は半角）
+		"BT /F1 10 Tf 520 741.89 Td <00A50031002C003500300030> Tj ET",
+		"0.5 w 40 731.89 m 555 731.89 l S",
+	}
+	for _, want := range wantFirst {
+		if !strings.Contains(contents[0], want) {
+			t.Errorf("page 1 content = %q, want to contain %q", contents[0], want)
+		}
+	}
+	// BMP外の文字は〓に置き換える
+	if !strings.Contains(contents[1], "<003230DA30FC30B876EE00203013>") {
+		t.Errorf("page 2 content = %q", contents[1])
+	}
+}
+
+func TestPage_Image(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img.SetRGBA(0, 0, color.RGBA{R: 0xff, A: 0xff})
+	img.SetRGBA(1, 0, color.RGBA{}) // 透明の画素は白にする
+
+	doc := NewDocument("グラフ")
+	page := doc.AddPage()
+	page.Image(40, 100, 200, 100, img)
+	page.Rect(40, 210, 10, 10, color.RGBA{B: 0xff, A: 0xff})
+
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	data := buf.Bytes()
+
+	// 画像はページとコンテンツの後のオブジェクトとし、ページのリソースから参照する
+	if !bytes.Contains(data, []byte("/XObject << /Im1 9 0 R >>")) {
+		t.Error("page resources do not reference the image")
+	}
+	if !bytes.Contains(data, []byte("9 0 obj\n<< /Type /XObject /Subtype /Image /Width 2 /Height 1 /ColorSpace /DeviceRGB")) {
+		t.Error("image object not written")
+	}
+
+	contents := pageContents(t, data)
+	if len(contents) != 2 {
+		t.Fatalf("streams = %d, want 2", len(contents))
+	}
+	for _, want := range []string{
+		"q 200 0 0 100 40 641.89 cm /Im1 Do Q",
+		"q 0 0 1 rg 40 621.89 10 10 re f Q",
+	} {
+		if !strings.Contains(contents[0], want) {
+			t.Errorf("page content = %q, want to contain %q", contents[0], want)
+		}
+	}
+	if want := "\xff\x00\x00\xff\xff\xff"; contents[1] != want {
+		t.Errorf("image pixels = %q, want %q", contents[1], want)
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	tests := []struct {
+		text string
+		want float64
+	}{
+		{text: "", want: 0},
+		{text: "ABC", want: 15},
+		{text: "経費", want: 20},
+		{text: "ｶﾞ1", want: 15},
+	}
+	for _, tt := range tests {
+		if got := TextWidth(tt.text, 10); got != tt.want {
+			t.Errorf("TextWidth(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+// pageContents ページのコンテンツを展開して返す
+func pageContents(t *testing.T, data []byte) []string {
+	t.Helper()
+	var contents []string
+	for _, m := range regexp.MustCompile(`(?s)/Length (\d+) /Filter /FlateDecode >>\nstream\n`).FindAllSubmatchIndex(data, -1) {
+		length, _ := strconv.Atoi(string(data[m[2]:m[3]]))
+		zr, err := zlib.NewReader(bytes.NewReader(data[m[1] : m[1]+length]))
+		if err != nil {
+			t.Fatalf("zlib.NewReader() error = %v", err)
+		}
+		content, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatalf("failed to decompress content: %v", err)
+		}
+		contents = append(contents, string(content))
+	}
+	return contents
+}
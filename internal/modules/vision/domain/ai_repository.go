@@ -1,5 +1,12 @@
 package domain
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
 // AIRepository AI補正のリポジトリインターフェース
 type AIRepository interface {
 	// Correct テキストを補正（汎用）
@@ -17,3 +24,38 @@ type AIRepository interface {
 	// ProviderName プロバイダー名を返す
 	ProviderName() string
 }
+
+// ErrAIDowngraded 予算の超過などでAIを使わない縮退中のため、AI APIを呼び出さなかった
+// 呼び出し側はキーワードのルールなどAIを使わない方法で処理する
+var ErrAIDowngraded = errors.New("ai is downgraded")
+
+// AIの処理の種類（キャッシュキーに含めるプロンプトの版の取得に使う）
+const (
+	OperationRecognizeImage   = "recognize_image"
+	OperationRecognizeReceipt = "recognize_receipt"
+)
+
+// PromptVersioner プロンプト・モデルの版を返すAIリポジトリ
+// AIの解析結果のキャッシュキーに含め、プロンプト・モデルを変えた場合は以前の解析結果を使わないようにする
+type PromptVersioner interface {
+	// PromptVersion 処理の種類のプロンプト・モデルから決まる版（プロンプト・モデルを変えると変わる、未対応の処理の場合は空）
+	PromptVersion(operation string) string
+}
+
+// PromptVersionOf AIリポジトリの処理の種類の版を取得（PromptVersionerでない場合は空）
+func PromptVersionOf(repo AIRepository, operation string) string {
+	if versioner, ok := repo.(PromptVersioner); ok {
+		return versioner.PromptVersion(operation)
+	}
+	return ""
+}
+
+// AICacheKey 画像などの入力のハッシュからAIの解析結果のキャッシュキーを生成（vision:<prefix>:<版>:<ハッシュ>）
+// 版が空の場合は版を含めない（プロンプトの版を導入する前のキャッシュキーと同じ）
+func AICacheKey(prefix string, data []byte, version string) string {
+	hash := sha256.Sum256(data)
+	if version == "" {
+		return fmt.Sprintf("vision:%s:%s", prefix, hex.EncodeToString(hash[:]))
+	}
+	return fmt.Sprintf("vision:%s:%s:%s", prefix, version, hex.EncodeToString(hash[:]))
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+type fakeVersionedAIRepository struct {
+	AIRepository
+	version string
+}
+
+func (r *fakeVersionedAIRepository) PromptVersion(operation string) string {
+	if operation == OperationRecognizeReceipt {
+		return r.version
+	}
+	return ""
+}
+
+func TestAICacheKey(t *testing.T) {
+	data := []byte("image")
+
+	legacy := AICacheKey("receipt", data, "")
+	if !strings.HasPrefix(legacy, "vision:receipt:") || strings.Count(legacy, ":") != 2 {
+		t.Errorf("unexpected legacy key %q", legacy)
+	}
+
+	versioned := AICacheKey("receipt", data, "abc123")
+	if !strings.HasPrefix(versioned, "vision:receipt:abc123:") {
+		t.Errorf("unexpected versioned key %q", versioned)
+	}
+	if strings.TrimPrefix(versioned, "vision:receipt:abc123:") != strings.TrimPrefix(legacy, "vision:receipt:") {
+		t.Error("expected the same hash regardless of version")
+	}
+	if AICacheKey("receipt", data, "def456") == versioned {
+		t.Error("expected different keys for different versions")
+	}
+}
+
+func TestPromptVersionOf(t *testing.T) {
+	repo := &fakeVersionedAIRepository{version: "v1"}
+	if got := PromptVersionOf(repo, OperationRecognizeReceipt); got != "v1" {
+		t.Errorf("expected v1, got %q", got)
+	}
+	if got := PromptVersionOf(repo, OperationRecognizeImage); got != "" {
+		t.Errorf("expected empty version, got %q", got)
+	}
+
+	var plain AIRepository = struct{ AIRepository }{}
+	if got := PromptVersionOf(plain, OperationRecognizeReceipt); got != "" {
+		t.Errorf("expected empty version for non-versioner, got %q", got)
+	}
+}
@@ -9,6 +9,7 @@ type AIResult struct {
 	InputTokens   int
 	OutputTokens  int
 	Model         string
+	StopReason    string // 出力の終了理由（end_turn、max_tokensなど）
 	ProcessedAt   time.Time
 }
 
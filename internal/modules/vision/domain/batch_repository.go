@@ -0,0 +1,36 @@
+package domain
+
+// BatchImage バッチでまとめて読み取るレシート画像
+type BatchImage struct {
+	CustomID  string // 結果と対応付ける識別子（レシートIDなど）
+	ImageData []byte
+}
+
+// BatchStatus バッチの処理状況
+type BatchStatus struct {
+	ID        string
+	Ended     bool // すべてのリクエストの処理が終わった（結果を取得できる）
+	Pending   int  // 処理中のリクエスト数
+	Succeeded int
+	Errored   int // 失敗・取り消し・期限切れのリクエスト数
+}
+
+// BatchResult バッチの1件のリクエストの結果
+type BatchResult struct {
+	CustomID string
+	Result   *AIResult // 失敗した場合はnil
+	Error    string    // 失敗した理由
+}
+
+// BatchAIRepository 非同期のバッチ処理に対応したAIリポジトリ
+// 結果を待たずにリクエストをまとめて送り、後で結果を取得する（大量のレシートの読み直しなど、急がない処理を安く行う）
+type BatchAIRepository interface {
+	// SubmitReceiptBatch レシート画像の読み取りをバッチで依頼し、バッチのIDを返す
+	SubmitReceiptBatch(images []BatchImage) (string, error)
+
+	// GetBatch バッチの処理状況を取得
+	GetBatch(batchID string) (*BatchStatus, error)
+
+	// BatchResults 処理が終わったバッチの結果を取得
+	BatchResults(batchID string) ([]BatchResult, error)
+}
@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ErrDiffTooLarge 比較する行数が上限を超えた
+var ErrDiffTooLarge = errors.New("text is too large to compare")
+
+// DiffOp 差分の種類
+type DiffOp string
+
+const (
+	DiffEqual  DiffOp = "equal"  // 両方にある行
+	DiffInsert DiffOp = "insert" // 変更後にのみある行
+	DiffDelete DiffOp = "delete" // 変更前にのみある行
+)
+
+// DiffLine 差分の1行
+type DiffLine struct {
+	Op         DiffOp
+	Text       string // 変更後の行（削除した行は変更前の行）
+	BeforeLine int    // 変更前の行番号（1始まり、挿入した行は0）
+	AfterLine  int    // 変更後の行番号（1始まり、削除した行は0）
+}
+
+// TextDiff 2つのテキストの行単位の差分
+type TextDiff struct {
+	Lines     []DiffLine
+	Added     int
+	Removed   int
+	Unchanged int
+}
+
+// HasChanges 差分があるかどうかを判定
+func (d *TextDiff) HasChanges() bool {
+	return d.Added > 0 || d.Removed > 0
+}
+
+// DiffText beforeからafterへの行単位の差分を求める（空行は無視する）
+// OCRの結果は全角・半角や空白の入れ方が揺れるため、NFKCで正規化して連続する空白を1つにまとめた行を比較する
+// 行数がmaxLinesを超える場合は ErrDiffTooLarge を返す
+func DiffText(before, after string, maxLines int) (*TextDiff, error) {
+	a, b := splitLines(before), splitLines(after)
+	if len(a.lines) > maxLines || len(b.lines) > maxLines {
+		return nil, ErrDiffTooLarge
+	}
+
+	diff := &TextDiff{}
+	for _, line := range diffLines(a, b) {
+		switch line.Op {
+		case DiffInsert:
+			diff.Added++
+		case DiffDelete:
+			diff.Removed++
+		default:
+			diff.Unchanged++
+		}
+		diff.Lines = append(diff.Lines, line)
+	}
+	return diff, nil
+}
+
+// textLines 比較する行（空行を除く）
+type textLines struct {
+	lines   []string // 元の行
+	keys    []string // 比較に使う正規化した行
+	numbers []int    // 元のテキストでの行番号
+}
+
+// splitLines テキストを比較する行に分割
+func splitLines(text string) textLines {
+	var t textLines
+	for i, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		key := strings.Join(strings.Fields(norm.NFKC.String(line)), " ")
+		if key == "" {
+			continue
+		}
+		t.lines = append(t.lines, strings.TrimRight(line, " \t"))
+		t.keys = append(t.keys, key)
+		t.numbers = append(t.numbers, i+1)
+	}
+	return t
+}
+
+// diffLines Myersのアルゴリズムで最短の編集手順を求める（削除を挿入より先に並べる）
+func diffLines(a, b textLines) []DiffLine {
+	n, m := len(a.keys), len(b.keys)
+
+	// trace[d] は d 回目の探索を始める前の各対角線 k（-d-1〜d+1）の到達位置 x
+	var trace [][]int
+	v := []int{0, 0, 0}
+	for d := 0; ; d++ {
+		trace = append(trace, v)
+		next := make([]int, 2*d+5)
+		reached := false
+		for k := -d; k <= d; k += 2 {
+			x := nextX(v, d, k)
+			y := x - k
+			for x < n && y < m && a.keys[x] == b.keys[y] {
+				x++
+				y++
+			}
+			next[k+d+2] = x
+			if x >= n && y >= m {
+				reached = true
+				break
+			}
+		}
+		if reached {
+			break
+		}
+		v = next
+	}
+
+	// 終点から始点へ辿る
+	var lines []DiffLine
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && at(v, d, k-1) < at(v, d, k+1)) {
+			prevK = k + 1
+		}
+		prevX := at(v, d, prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			lines = append(lines, DiffLine{Op: DiffEqual, Text: b.lines[y], BeforeLine: a.numbers[x], AfterLine: b.numbers[y]})
+		}
+		if d == 0 {
+			break
+		}
+		if x == prevX {
+			y--
+			lines = append(lines, DiffLine{Op: DiffInsert, Text: b.lines[y], AfterLine: b.numbers[y]})
+		} else {
+			x--
+			lines = append(lines, DiffLine{Op: DiffDelete, Text: a.lines[x], BeforeLine: a.numbers[x]})
+		}
+		x, y = prevX, prevY
+	}
+
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return lines
+}
+
+// nextX d 回目の探索で対角線 k の始点となる x（上から来る挿入か、左から来る削除の遠い方）
+func nextX(v []int, d, k int) int {
+	if k == -d || (k != d && at(v, d, k-1) < at(v, d, k+1)) {
+		return at(v, d, k+1)
+	}
+	return at(v, d, k-1) + 1
+}
+
+// at d 回目の探索を始める前の対角線 k の到達位置
+func at(v []int, d, k int) int {
+	return v[k+d+1]
+}
@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// formatDiff 差分を「+」「-」「 」付きの行で表す
+func formatDiff(diff *TextDiff) string {
+	var b strings.Builder
+	for _, line := range diff.Lines {
+		switch line.Op {
+		case DiffInsert:
+			b.WriteString("+")
+		case DiffDelete:
+			b.WriteString("-")
+		default:
+			b.WriteString(" ")
+		}
+		b.WriteString(line.Text)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func TestDiffText(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   string
+	}{
+		{
+			name:   "正常系: 同じテキスト",
+			before: "第1条 目的\n第2条 期間",
+			after:  "第1条 目的\n第2条 期間",
+			want:   " 第1条 目的\n 第2条 期間\n",
+		},
+		{
+			name:   "正常系: 行の変更は削除と挿入",
+			before: "第1条 目的\n契約期間は1年とする\n第3条 解除",
+			after:  "第1条 目的\n契約期間は2年とする\n第3条 解除",
+			want:   " 第1条 目的\n-契約期間は1年とする\n+契約期間は2年とする\n 第3条 解除\n",
+		},
+		{
+			name:   "正常系: 行の追加と削除",
+			before: "A\nB\nC\nD",
+			after:  "A\nC\nD\nE",
+			want:   " A\n-B\n C\n D\n+E\n",
+		},
+		{
+			name:   "正常系: 全角・半角と空白の揺れは同じ行",
+			before: "第１条　目的\n\n金額  10,000円",
+			after:  "第1条 目的\r\n金額 10,000円\n",
+			want:   " 第1条 目的\n 金額 10,000円\n",
+		},
+		{
+			name:   "正常系: 空の変更前",
+			before: "",
+			after:  "A\nB",
+			want:   "+A\n+B\n",
+		},
+		{
+			name:   "正常系: すべて異なる",
+			before: "A\nB",
+			after:  "C",
+			want:   "-A\n-B\n+C\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := DiffText(tt.before, tt.after, 100)
+			if err != nil {
+				t.Fatalf("DiffText() error = %v", err)
+			}
+			if got := formatDiff(diff); got != tt.want {
+				t.Errorf("DiffText() =\n%s\nwant\n%s", got, tt.want)
+			}
+			if diff.Added+diff.Removed+diff.Unchanged != len(diff.Lines) {
+				t.Errorf("counts = %d+%d+%d, lines = %d", diff.Added, diff.Removed, diff.Unchanged, len(diff.Lines))
+			}
+		})
+	}
+}
+
+func TestDiffText_LineNumbers(t *testing.T) {
+	diff, err := DiffText("A\n\nB\nC", "A\nX\nC", 100)
+	if err != nil {
+		t.Fatalf("DiffText() error = %v", err)
+	}
+
+	// 空行を除いて比較しても、行番号は元のテキストの行番号
+	want := []DiffLine{
+		{Op: DiffEqual, Text: "A", BeforeLine: 1, AfterLine: 1},
+		{Op: DiffDelete, Text: "B", BeforeLine: 3},
+		{Op: DiffInsert, Text: "X", AfterLine: 2},
+		{Op: DiffEqual, Text: "C", BeforeLine: 4, AfterLine: 3},
+	}
+	if len(diff.Lines) != len(want) {
+		t.Fatalf("Lines = %+v, want %+v", diff.Lines, want)
+	}
+	for i := range want {
+		if diff.Lines[i] != want[i] {
+			t.Errorf("Lines[%d] = %+v, want %+v", i, diff.Lines[i], want[i])
+		}
+	}
+	if !diff.HasChanges() || diff.Added != 1 || diff.Removed != 1 || diff.Unchanged != 2 {
+		t.Errorf("counts = +%d -%d =%d", diff.Added, diff.Removed, diff.Unchanged)
+	}
+}
+
+func TestDiffText_TooLarge(t *testing.T) {
+	var lines []string
+	for i := range 11 {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	if _, err := DiffText(strings.Join(lines, "\n"), "", 10); !errors.Is(err, ErrDiffTooLarge) {
+		t.Errorf("DiffText() error = %v, want ErrDiffTooLarge", err)
+	}
+}
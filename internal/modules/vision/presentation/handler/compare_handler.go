@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"vision-api-app/internal/modules/shared/presentation/bufpool"
+	"vision-api-app/internal/modules/vision/domain"
+)
+
+// maxCompareRequestOverhead 比較のリクエストで2枚の画像以外（マルチパートの区切り・ヘッダー）に許容するバイト数
+const maxCompareRequestOverhead = 64 << 10
+
+// CompareResponse 2つの画像のテキストの差分のレスポンス
+type CompareResponse struct {
+	Success    bool                 `json:"success"`
+	Before     *CompareTextResponse `json:"before"`
+	After      *CompareTextResponse `json:"after"`
+	Diff       *TextDiffResponse    `json:"diff"`
+	DurationMs int64                `json:"duration_ms"` // 処理時間（ミリ秒）
+}
+
+// CompareTextResponse 比較した画像から抽出したテキスト
+type CompareTextResponse struct {
+	Text   string            `json:"text"`
+	Tokens *AITokensResponse `json:"tokens"`
+	Cached bool              `json:"cached"` // キャッシュした結果を使った（画像解析APIと共通のキャッシュ）
+}
+
+// TextDiffResponse 行単位の差分
+type TextDiffResponse struct {
+	Changed   bool               `json:"changed"`
+	Added     int                `json:"added"`
+	Removed   int                `json:"removed"`
+	Unchanged int                `json:"unchanged"`
+	Lines     []DiffLineResponse `json:"lines"`
+}
+
+// DiffLineResponse 差分の1行
+type DiffLineResponse struct {
+	Op         string `json:"op"` // equal / insert / delete
+	Text       string `json:"text"`
+	BeforeLine int    `json:"before_line,omitempty"` // 変更前の行番号（1始まり）
+	AfterLine  int    `json:"after_line,omitempty"`  // 変更後の行番号（1始まり）
+}
+
+// HandleCompare 2つの画像（契約書の版違いのページなど）からテキストを抽出し、行単位の差分を返す
+// multipart/form-data の before と after に画像を指定する（それぞれ最大10MB）
+func (h *VisionHandler) HandleCompare(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 2*maxImageSize+maxCompareRequestOverhead)
+	if err := r.ParseMultipartForm(maxImageSize); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			h.sendError(w, "Images are too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		h.sendError(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	images := make([]*bufpool.Buffer, 0, 2)
+	defer func() {
+		for _, buf := range images {
+			buf.Release()
+		}
+	}()
+	for _, field := range []string{"before", "after"} {
+		buf, status, err := readFormImage(r, field)
+		if err != nil {
+			h.sendError(w, err.Error(), status)
+			return
+		}
+		images = append(images, buf)
+	}
+
+	startedAt := time.Now()
+
+	// ウイルス検査（キャッシュ確認・AI送信の前に実施）
+	for _, buf := range images {
+		if !h.scanImage(w, r, buf.Bytes()) {
+			return
+		}
+	}
+
+	// 2枚の画像を並行して解析（画像解析APIと同じ処理・キャッシュを使う）
+	recognized := make([]*recognizedText, len(images))
+	errs := make([]error, len(images))
+	var wg sync.WaitGroup
+	for i, buf := range images {
+		wg.Go(func() {
+			recognized[i], errs[i] = h.recognizeText(r.Context(), buf.Bytes())
+		})
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		h.sendError(w, fmt.Sprintf("Vision API failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	diff, err := h.compareUseCase.Compare(recognized[0].text, recognized[1].text)
+	if errors.Is(err, domain.ErrDiffTooLarge) {
+		h.sendError(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		h.sendError(w, fmt.Sprintf("Compare failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	response := CompareResponse{
+		Success:    true,
+		Before:     newCompareTextResponse(recognized[0]),
+		After:      newCompareTextResponse(recognized[1]),
+		Diff:       newTextDiffResponse(diff),
+		DurationMs: time.Since(startedAt).Milliseconds(),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// readFormImage マルチパートフォームのfieldの画像を読み込む（エラーの場合はレスポンスのステータスコードを返す）
+func readFormImage(r *http.Request, field string) (*bufpool.Buffer, int, error) {
+	file, header, err := r.FormFile(field)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("image file %q is required", field)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	if header.Size > maxImageSize {
+		return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("image %q is too large", field)
+	}
+
+	buf, err := bufpool.ReadAll(file, header.Size)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to read image %q", field)
+	}
+	if len(buf.Bytes()) == 0 {
+		buf.Release()
+		return nil, http.StatusBadRequest, fmt.Errorf("image %q is empty", field)
+	}
+	return buf, 0, nil
+}
+
+// newCompareTextResponse 抽出したテキストのレスポンスを作成（キャッシュした結果のトークン数は0）
+func newCompareTextResponse(recognized *recognizedText) *CompareTextResponse {
+	response := &CompareTextResponse{
+		Text:   recognized.text,
+		Tokens: &AITokensResponse{},
+		Cached: recognized.aiResult == nil,
+	}
+	if recognized.aiResult != nil {
+		response.Tokens = &AITokensResponse{
+			InputTokens:  recognized.aiResult.InputTokens,
+			OutputTokens: recognized.aiResult.OutputTokens,
+			TotalTokens:  recognized.aiResult.TotalTokens(),
+		}
+	}
+	return response
+}
+
+// newTextDiffResponse 差分のレスポンスを作成
+func newTextDiffResponse(diff *domain.TextDiff) *TextDiffResponse {
+	response := &TextDiffResponse{
+		Changed:   diff.HasChanges(),
+		Added:     diff.Added,
+		Removed:   diff.Removed,
+		Unchanged: diff.Unchanged,
+		Lines:     make([]DiffLineResponse, 0, len(diff.Lines)),
+	}
+	for _, line := range diff.Lines {
+		response.Lines = append(response.Lines, DiffLineResponse{
+			Op:         string(line.Op),
+			Text:       line.Text,
+			BeforeLine: line.BeforeLine,
+			AfterLine:  line.AfterLine,
+		})
+	}
+	return response
+}
@@ -1,22 +1,37 @@
 package handler
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
+	"bytes"
+	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
+	"mime"
 	"net/http"
+	"strings"
 	"time"
 
 	"vision-api-app/internal/modules/household/domain/repository"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+	"vision-api-app/internal/modules/shared/presentation/bufpool"
+	"vision-api-app/internal/modules/vision/domain"
 	"vision-api-app/internal/modules/vision/usecase"
 )
 
+// maxImageSize アップロード画像の最大サイズ（10MB）
+const maxImageSize = 10 << 20
+
+// maxBase64RequestOverhead base64のJSONリクエストで画像以外（ファイル名・キー）に許容するバイト数
+const maxBase64RequestOverhead = 4 << 10
+
 // VisionHandler Vision API処理のハンドラー
 type VisionHandler struct {
 	aiCorrectionUseCase *usecase.AICorrectionUseCase
+	compareUseCase      *usecase.CompareUseCase
 	cacheRepo           repository.CacheRepository
+	fileScanner         sharedDomain.FileScanner
+	imageFetcher        sharedDomain.ImageFetcher
 }
 
 // NewVisionHandler 新しいVisionHandlerを作成
@@ -26,16 +41,68 @@ func NewVisionHandler(
 ) *VisionHandler {
 	return &VisionHandler{
 		aiCorrectionUseCase: aiCorrectionUseCase,
+		compareUseCase:      usecase.NewCompareUseCase(usecase.DefaultMaxCompareLines),
 		cacheRepo:           cacheRepo,
 	}
 }
 
+// SetFileScanner アップロードファイルのウイルス検査を設定する
+// 未設定の場合は検査しない
+func (h *VisionHandler) SetFileScanner(fileScanner sharedDomain.FileScanner) {
+	h.fileScanner = fileScanner
+}
+
+// SetImageFetcher image_urlで指定された画像の取得を設定する
+// 未設定の場合はimage_urlを受け付けない
+func (h *VisionHandler) SetImageFetcher(imageFetcher sharedDomain.ImageFetcher) {
+	h.imageFetcher = imageFetcher
+}
+
+// jsonImageRequest 画像をJSONで指定するリクエスト（multipartを送れないクライアント向け）
+// image_base64 と image_url のどちらか一方を指定する
+type jsonImageRequest struct {
+	ImageBase64 string `json:"image_base64"` // 画像のbase64（data:image/jpeg;base64, のようなData URLの接頭辞も可）
+	ImageURL    string `json:"image_url"`    // 画像のURL（電子レシートのリンク・クラウドの写真の共有リンクなど）
+	Filename    string `json:"filename"`     // 元のファイル名（任意）
+}
+
 // VisionResponse Vision APIレスポンス
 type VisionResponse struct {
-	Success bool              `json:"success"`
-	Text    string            `json:"text"`
-	Tokens  *AITokensResponse `json:"tokens,omitempty"`
-	Error   string            `json:"error,omitempty"`
+	Success    bool              `json:"success"`
+	Text       string            `json:"text"`
+	Tokens     *AITokensResponse `json:"tokens,omitempty"`
+	Model      string            `json:"model,omitempty"`       // 処理したAIのモデル（キャッシュした結果の場合は空）
+	StopReason string            `json:"stop_reason,omitempty"` // AIの出力の終了理由（end_turn、max_tokensなど）
+	Cached     bool              `json:"cached"`                // キャッシュした結果を返した（X-Cache: HIT）
+	DurationMs int64             `json:"duration_ms"`           // 処理時間（ミリ秒）
+	Error      string            `json:"error,omitempty"`
+}
+
+// newVisionResponse AIの処理結果からレスポンスを作成
+func newVisionResponse(aiResult *domain.AIResult, startedAt time.Time) VisionResponse {
+	return VisionResponse{
+		Success: true,
+		Text:    aiResult.CorrectedText,
+		Tokens: &AITokensResponse{
+			InputTokens:  aiResult.InputTokens,
+			OutputTokens: aiResult.OutputTokens,
+			TotalTokens:  aiResult.TotalTokens(),
+		},
+		Model:      aiResult.Model,
+		StopReason: aiResult.StopReason,
+		DurationMs: time.Since(startedAt).Milliseconds(),
+	}
+}
+
+// newCachedVisionResponse キャッシュした結果からレスポンスを作成（AIを呼び出していないためトークン数は0）
+func newCachedVisionResponse(text string, startedAt time.Time) VisionResponse {
+	return VisionResponse{
+		Success:    true,
+		Text:       text,
+		Tokens:     &AITokensResponse{},
+		Cached:     true,
+		DurationMs: time.Since(startedAt).Milliseconds(),
+	}
 }
 
 // AITokensResponse AIトークン使用量のレスポンス
@@ -54,79 +121,73 @@ func (h *VisionHandler) HandleAnalyze(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
-	// マルチパートフォームのパース
-	if err := r.ParseMultipartForm(10 << 20); err != nil { // 10MB制限
-		h.sendError(w, "Failed to parse form", http.StatusBadRequest)
+	// 画像データの読み込み（バッファはレスポンスを返した後にプールへ戻す）
+	buf, ok := h.readImage(w, r)
+	if !ok {
 		return
 	}
+	defer buf.Release()
+	imageData := buf.Bytes()
 
-	// 画像ファイルの取得
-	file, _, err := r.FormFile("image")
-	if err != nil {
-		h.sendError(w, "Image file is required", http.StatusBadRequest)
+	startedAt := time.Now()
+
+	// ウイルス検査（キャッシュ確認・AI送信の前に実施）
+	if !h.scanImage(w, r, imageData) {
 		return
 	}
-	defer func() {
-		_ = file.Close()
-	}()
 
-	// 画像データの読み込み
-	imageData, err := io.ReadAll(file)
+	// Claude Vision APIで画像解析（キャッシュした結果があればそれを返す）
+	recognized, err := h.recognizeText(ctx, imageData)
 	if err != nil {
-		h.sendError(w, "Failed to read image", http.StatusInternalServerError)
+		h.sendError(w, fmt.Sprintf("Vision API failed: %v", err), http.StatusInternalServerError)
 		return
 	}
+	if recognized.aiResult == nil {
+		response := newCachedVisionResponse(recognized.text, startedAt)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Cache", "HIT")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// レスポンスの構築
+	response := newVisionResponse(recognized.aiResult, startedAt)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Cache", "MISS")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(response)
+}
 
+// recognizedText 画像から抽出したテキスト
+type recognizedText struct {
+	text     string
+	aiResult *domain.AIResult // キャッシュした結果の場合はnil
+}
+
+// recognizeText 画像からテキストを抽出（画像のハッシュでキャッシュし、同じ画像はAIを呼び出さない）
+func (h *VisionHandler) recognizeText(ctx context.Context, imageData []byte) (*recognizedText, error) {
 	// キャッシュキーの生成
-	cacheKey := h.generateCacheKey("analyze", imageData)
+	cacheKey := h.generateCacheKey("analyze", domain.OperationRecognizeImage, imageData)
 
 	// Redisキャッシュチェック
 	if h.cacheRepo != nil {
 		if cached, err := h.cacheRepo.Get(ctx, cacheKey); err == nil && len(cached) > 0 {
-			response := VisionResponse{
-				Success: true,
-				Text:    string(cached),
-				Tokens: &AITokensResponse{
-					InputTokens:  0,
-					OutputTokens: 0,
-					TotalTokens:  0,
-				},
-			}
-			w.Header().Set("Content-Type", "application/json")
-			w.Header().Set("X-Cache", "HIT")
-			w.WriteHeader(http.StatusOK)
-			_ = json.NewEncoder(w).Encode(response)
-			return
+			return &recognizedText{text: string(cached)}, nil
 		}
 	}
 
-	// Claude Vision APIで画像解析
 	aiResult, err := h.aiCorrectionUseCase.RecognizeImage(imageData)
 	if err != nil {
-		h.sendError(w, fmt.Sprintf("Vision API failed: %v", err), http.StatusInternalServerError)
-		return
+		return nil, err
 	}
 
 	// Redisにキャッシュ保存（24時間）
 	if h.cacheRepo != nil {
 		_ = h.cacheRepo.Set(ctx, cacheKey, []byte(aiResult.CorrectedText), 24*time.Hour)
 	}
-
-	// レスポンスの構築
-	response := VisionResponse{
-		Success: true,
-		Text:    aiResult.CorrectedText,
-		Tokens: &AITokensResponse{
-			InputTokens:  aiResult.InputTokens,
-			OutputTokens: aiResult.OutputTokens,
-			TotalTokens:  aiResult.TotalTokens(),
-		},
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("X-Cache", "MISS")
-	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(response)
+	return &recognizedText{text: aiResult.CorrectedText, aiResult: aiResult}, nil
 }
 
 // HandleReceiptAnalyze レシート画像解析ハンドラー
@@ -136,47 +197,65 @@ func (h *VisionHandler) HandleReceiptAnalyze(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	ctx := r.Context()
+	// 画像データの読み込み（バッファはレスポンスを返した後にプールへ戻す）
+	buf, ok := h.readImage(w, r)
+	if !ok {
+		return
+	}
+	defer buf.Release()
+	imageData := buf.Bytes()
 
-	// マルチパートフォームのパース
-	if err := r.ParseMultipartForm(10 << 20); err != nil { // 10MB制限
-		h.sendError(w, "Failed to parse form", http.StatusBadRequest)
+	h.analyzeReceipt(w, r, imageData)
+}
+
+// HandleReceiptSimple 画像をリクエストボディに直接含めるレシート画像解析ハンドラー
+// マルチパートを組み立てにくいiOSショートカット・curlなど向け（Content-Type: image/jpeg などで送信）
+func (h *VisionHandler) HandleReceiptSimple(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if !strings.HasPrefix(mediaType, "image/") && mediaType != "application/octet-stream" {
+		h.sendError(w, "Content-Type must be an image type (e.g. image/jpeg)", http.StatusUnsupportedMediaType)
 		return
 	}
 
-	// 画像ファイルの取得
-	file, _, err := r.FormFile("image")
+	// 画像データの読み込み（マルチパートと同じ10MB制限、バッファはレスポンスを返した後にプールへ戻す）
+	buf, err := bufpool.ReadAll(http.MaxBytesReader(w, r.Body, maxImageSize), r.ContentLength)
 	if err != nil {
-		h.sendError(w, "Image file is required", http.StatusBadRequest)
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			h.sendError(w, "Image is too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		h.sendError(w, "Failed to read image", http.StatusBadRequest)
+		return
+	}
+	defer buf.Release()
+	imageData := buf.Bytes()
+	if len(imageData) == 0 {
+		h.sendError(w, "Image body is required", http.StatusBadRequest)
 		return
 	}
-	defer func() {
-		_ = file.Close()
-	}()
 
-	// 画像データの読み込み
-	imageData, err := io.ReadAll(file)
-	if err != nil {
-		h.sendError(w, "Failed to read image", http.StatusInternalServerError)
+	h.analyzeReceipt(w, r, imageData)
+}
+
+// analyzeReceipt 読み込んだレシート画像を解析してレスポンスを送信
+func (h *VisionHandler) analyzeReceipt(w http.ResponseWriter, r *http.Request, imageData []byte) {
+	ctx := r.Context()
+	startedAt := time.Now()
+
+	// ウイルス検査（キャッシュ確認・AI送信の前に実施）
+	if !h.scanImage(w, r, imageData) {
 		return
 	}
 
 	// キャッシュキーの生成（画像データのハッシュ）
-	cacheKey := h.generateCacheKey("receipt", imageData)
+	cacheKey := h.generateCacheKey("receipt", domain.OperationRecognizeReceipt, imageData)
 
 	// Redisキャッシュチェック
 	if h.cacheRepo != nil {
 		if cached, err := h.cacheRepo.Get(ctx, cacheKey); err == nil && len(cached) > 0 {
 			// キャッシュヒット
-			response := VisionResponse{
-				Success: true,
-				Text:    string(cached),
-				Tokens: &AITokensResponse{
-					InputTokens:  0,
-					OutputTokens: 0,
-					TotalTokens:  0,
-				},
-			}
+			response := newCachedVisionResponse(string(cached), startedAt)
 			w.Header().Set("Content-Type", "application/json")
 			w.Header().Set("X-Cache", "HIT")
 			w.WriteHeader(http.StatusOK)
@@ -198,15 +277,7 @@ func (h *VisionHandler) HandleReceiptAnalyze(w http.ResponseWriter, r *http.Requ
 	}
 
 	// レスポンスの構築
-	response := VisionResponse{
-		Success: true,
-		Text:    aiResult.CorrectedText,
-		Tokens: &AITokensResponse{
-			InputTokens:  aiResult.InputTokens,
-			OutputTokens: aiResult.OutputTokens,
-			TotalTokens:  aiResult.TotalTokens(),
-		},
-	}
+	response := newVisionResponse(aiResult, startedAt)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("X-Cache", "MISS")
@@ -237,28 +308,163 @@ func (h *VisionHandler) HandleCategorize(w http.ResponseWriter, r *http.Request)
 	}
 
 	// カテゴリ判定実行
+	startedAt := time.Now()
 	aiResult, err := h.aiCorrectionUseCase.CategorizeReceipt(request.ReceiptInfo)
+	if errors.Is(err, domain.ErrAIDowngraded) {
+		h.sendError(w, "Categorization is suspended: monthly AI budget exceeded", http.StatusServiceUnavailable)
+		return
+	}
 	if err != nil {
 		h.sendError(w, fmt.Sprintf("Categorization failed: %v", err), http.StatusInternalServerError)
 		return
 	}
 
 	// レスポンスの構築
-	response := VisionResponse{
-		Success: true,
-		Text:    aiResult.CorrectedText,
-		Tokens: &AITokensResponse{
-			InputTokens:  aiResult.InputTokens,
-			OutputTokens: aiResult.OutputTokens,
-			TotalTokens:  aiResult.TotalTokens(),
-		},
-	}
+	response := newVisionResponse(aiResult, startedAt)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(response)
 }
 
+// readImage リクエストから画像を読み込む（multipart/form-data の image、または application/json の image_base64・image_url）
+// 読み込めない場合はエラーレスポンスを送信してfalseを返す
+func (h *VisionHandler) readImage(w http.ResponseWriter, r *http.Request) (*bufpool.Buffer, bool) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/json" {
+		return h.readJSONImage(w, r)
+	}
+
+	// マルチパートフォームのパース
+	if err := r.ParseMultipartForm(maxImageSize); err != nil {
+		h.sendError(w, "Failed to parse form", http.StatusBadRequest)
+		return nil, false
+	}
+
+	// 画像ファイルの取得
+	file, header, err := r.FormFile("image")
+	if err != nil {
+		h.sendError(w, "Image file is required", http.StatusBadRequest)
+		return nil, false
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	buf, err := bufpool.ReadAll(file, header.Size)
+	if err != nil {
+		h.sendError(w, "Failed to read image", http.StatusInternalServerError)
+		return nil, false
+	}
+	return buf, true
+}
+
+// readJSONImage JSONのimage_base64またはimage_urlから画像を読み込む
+func (h *VisionHandler) readJSONImage(w http.ResponseWriter, r *http.Request) (*bufpool.Buffer, bool) {
+	limit := int64(base64.StdEncoding.EncodedLen(maxImageSize) + maxBase64RequestOverhead)
+	var req jsonImageRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, limit)).Decode(&req); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			h.sendError(w, "Image is too large", http.StatusRequestEntityTooLarge)
+			return nil, false
+		}
+		h.sendError(w, "Invalid request body", http.StatusBadRequest)
+		return nil, false
+	}
+
+	if req.ImageURL != "" {
+		if req.ImageBase64 != "" {
+			h.sendError(w, "Specify either image_base64 or image_url", http.StatusBadRequest)
+			return nil, false
+		}
+		return h.fetchImage(w, r, req.ImageURL)
+	}
+	return h.decodeBase64Image(w, req.ImageBase64)
+}
+
+// decodeBase64Image image_base64の画像をデコードする
+// デコード後のサイズはデコードする前に文字数から求めて検証し、上限を超える場合は413を返す
+func (h *VisionHandler) decodeBase64Image(w http.ResponseWriter, encoded string) (*bufpool.Buffer, bool) {
+	if prefix, data, ok := strings.Cut(encoded, ","); ok && strings.HasPrefix(prefix, "data:") {
+		encoded = data
+	}
+	if encoded == "" {
+		h.sendError(w, "image_base64 or image_url is required", http.StatusBadRequest)
+		return nil, false
+	}
+	size := base64.StdEncoding.DecodedLen(len(encoded))
+	if size > maxImageSize+2 { // パディングを除いた実際のサイズは最大2バイト小さい
+		h.sendError(w, "Image is too large", http.StatusRequestEntityTooLarge)
+		return nil, false
+	}
+
+	buf, err := bufpool.ReadAll(base64.NewDecoder(base64.StdEncoding, strings.NewReader(encoded)), int64(size))
+	if err != nil {
+		h.sendError(w, "image_base64 is not valid base64", http.StatusBadRequest)
+		return nil, false
+	}
+	if len(buf.Bytes()) > maxImageSize {
+		buf.Release()
+		h.sendError(w, "Image is too large", http.StatusRequestEntityTooLarge)
+		return nil, false
+	}
+	return buf, true
+}
+
+// fetchImage image_urlの画像を取得する
+// 許可されていないURLの場合は400、上限を超える場合は413、取得に失敗した場合は502を返す
+func (h *VisionHandler) fetchImage(w http.ResponseWriter, r *http.Request, imageURL string) (*bufpool.Buffer, bool) {
+	if h.imageFetcher == nil {
+		h.sendError(w, "image_url is not enabled", http.StatusBadRequest)
+		return nil, false
+	}
+
+	data, err := h.imageFetcher.Fetch(r.Context(), imageURL)
+	switch {
+	case err == nil:
+	case errors.Is(err, sharedDomain.ErrImageURLNotAllowed):
+		h.sendError(w, "image_url is not allowed", http.StatusBadRequest)
+		return nil, false
+	case errors.Is(err, sharedDomain.ErrImageTooLarge):
+		h.sendError(w, "Image is too large", http.StatusRequestEntityTooLarge)
+		return nil, false
+	default:
+		h.sendError(w, fmt.Sprintf("Failed to fetch image_url: %v", err), http.StatusBadGateway)
+		return nil, false
+	}
+	if len(data) == 0 {
+		h.sendError(w, "image_url returned an empty image", http.StatusBadGateway)
+		return nil, false
+	}
+
+	buf, err := bufpool.ReadAll(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		h.sendError(w, "Failed to read image", http.StatusInternalServerError)
+		return nil, false
+	}
+	return buf, true
+}
+
+// scanImage アップロード画像を検査し、問題がなければtrueを返す
+// 検出した場合は422、検査できなかった場合は500を返して処理を中断する
+func (h *VisionHandler) scanImage(w http.ResponseWriter, r *http.Request, imageData []byte) bool {
+	if h.fileScanner == nil {
+		return true
+	}
+
+	err := h.fileScanner.Scan(r.Context(), imageData)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, sharedDomain.ErrFileInfected):
+		h.sendError(w, "File rejected by malware scan", http.StatusUnprocessableEntity)
+	default:
+		h.sendError(w, fmt.Sprintf("Malware scan failed: %v", err), http.StatusInternalServerError)
+	}
+	return false
+}
+
 // sendError エラーレスポンスを送信
 func (h *VisionHandler) sendError(w http.ResponseWriter, message string, statusCode int) {
 	response := VisionResponse{
@@ -271,8 +477,7 @@ func (h *VisionHandler) sendError(w http.ResponseWriter, message string, statusC
 	_ = json.NewEncoder(w).Encode(response)
 }
 
-// generateCacheKey キャッシュキーを生成
-func (h *VisionHandler) generateCacheKey(prefix string, data []byte) string {
-	hash := sha256.Sum256(data)
-	return fmt.Sprintf("vision:%s:%s", prefix, hex.EncodeToString(hash[:]))
+// generateCacheKey キャッシュキーを生成（プロンプト・モデルの版を含め、変更した場合は以前の解析結果を使わない）
+func (h *VisionHandler) generateCacheKey(prefix, operation string, data []byte) string {
+	return domain.AICacheKey(prefix, data, h.aiCorrectionUseCase.PromptVersion(operation))
 }
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// fakeImageFetcher URLごとに決めた画像・エラーを返すテスト用の画像の取得
+type fakeImageFetcher struct {
+	images map[string][]byte
+}
+
+func (f *fakeImageFetcher) Fetch(ctx context.Context, rawURL string) ([]byte, error) {
+	data, ok := f.images[rawURL]
+	if !ok {
+		return nil, fmt.Errorf("fetch %s: %w", rawURL, sharedDomain.ErrImageURLNotAllowed)
+	}
+	return data, nil
+}
+
+func jsonImageBody(t *testing.T, req jsonImageRequest) string {
+	t.Helper()
+	body, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	return string(body)
+}
+
+func TestVisionHandler_ReadJSONImage(t *testing.T) {
+	image := []byte("\xff\xd8\xffjpeg image")
+	encoded := base64.StdEncoding.EncodeToString(image)
+	// base64のリクエストの上限（画像の上限をbase64にした長さ＋画像以外の分）
+	limit := base64.StdEncoding.EncodedLen(maxImageSize) + maxBase64RequestOverhead
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int    // 0の場合は読み込めること
+		wantError  string // エラーレスポンスのメッセージ
+	}{
+		{
+			name: "base64",
+			body: jsonImageBody(t, jsonImageRequest{ImageBase64: encoded}),
+		},
+		{
+			name: "data URLの接頭辞を除く",
+			body: jsonImageBody(t, jsonImageRequest{ImageBase64: "data:image/jpeg;base64," + encoded}),
+		},
+		{
+			name: "image_url",
+			body: jsonImageBody(t, jsonImageRequest{ImageURL: "https://example.com/receipt.jpg"}),
+		},
+		{
+			name:       "許可されていないimage_url",
+			body:       jsonImageBody(t, jsonImageRequest{ImageURL: "http://127.0.0.1/receipt.jpg"}),
+			wantStatus: http.StatusBadRequest,
+			wantError:  "image_url is not allowed",
+		},
+		{
+			name:       "image_base64とimage_urlの両方",
+			body:       jsonImageBody(t, jsonImageRequest{ImageBase64: encoded, ImageURL: "https://example.com/receipt.jpg"}),
+			wantStatus: http.StatusBadRequest,
+			wantError:  "Specify either image_base64 or image_url",
+		},
+		{
+			name:       "どちらもない",
+			body:       `{}`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  "image_base64 or image_url is required",
+		},
+		{
+			name:       "data URLの接頭辞だけ",
+			body:       jsonImageBody(t, jsonImageRequest{ImageBase64: "data:image/jpeg;base64,"}),
+			wantStatus: http.StatusBadRequest,
+			wantError:  "image_base64 or image_url is required",
+		},
+		{
+			name:       "不正なbase64",
+			body:       jsonImageBody(t, jsonImageRequest{ImageBase64: "not base64!"}),
+			wantStatus: http.StatusBadRequest,
+			wantError:  "image_base64 is not valid base64",
+		},
+		{
+			name:       "不正なJSON",
+			body:       `{"image_base64":`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  "Invalid request body",
+		},
+		{
+			name:       "デコード後のサイズが上限を超える",
+			body:       jsonImageBody(t, jsonImageRequest{ImageBase64: strings.Repeat("A", base64.StdEncoding.EncodedLen(maxImageSize+3))}),
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantError:  "Image is too large",
+		},
+		{
+			name:       "リクエストが上限を超える",
+			body:       jsonImageBody(t, jsonImageRequest{ImageBase64: strings.Repeat("A", limit)}),
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantError:  "Image is too large",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewVisionHandler(nil, nil)
+			h.SetImageFetcher(&fakeImageFetcher{images: map[string][]byte{"https://example.com/receipt.jpg": image}})
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/vision/analyze", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			buf, ok := h.readImage(w, r)
+			if tt.wantStatus == 0 {
+				if !ok {
+					t.Fatalf("readImage() failed: %d %s", w.Code, w.Body.String())
+				}
+				defer buf.Release()
+				if string(buf.Bytes()) != string(image) {
+					t.Errorf("image = %q, want %q", buf.Bytes(), image)
+				}
+				return
+			}
+
+			if ok {
+				buf.Release()
+				t.Fatal("readImage() succeeded, want error")
+			}
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			var response VisionResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if response.Error != tt.wantError {
+				t.Errorf("error = %q, want %q", response.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestVisionHandler_ReadJSONImage_WithoutImageFetcher(t *testing.T) {
+	h := NewVisionHandler(nil, nil)
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/vision/analyze", strings.NewReader(`{"image_url":"https://example.com/receipt.jpg"}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	if buf, ok := h.readImage(w, r); ok {
+		buf.Release()
+		t.Fatal("readImage() succeeded, want error")
+	}
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "image_url is not enabled") {
+		t.Errorf("response = %d %s", w.Code, w.Body.String())
+	}
+}
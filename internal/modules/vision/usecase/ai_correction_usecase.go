@@ -83,6 +83,11 @@ func (uc *AICorrectionUseCase) CategorizeReceipt(receiptInfo string) (*domain.AI
 	return result, nil
 }
 
+// PromptVersion 処理の種類のプロンプト・モデルの版を取得（AIの解析結果のキャッシュキーに含める、版のないプロバイダーの場合は空）
+func (uc *AICorrectionUseCase) PromptVersion(operation string) string {
+	return domain.PromptVersionOf(uc.aiRepo, operation)
+}
+
 // GetProviderName プロバイダー名を取得
 func (uc *AICorrectionUseCase) GetProviderName() string {
 	return uc.aiRepo.ProviderName()
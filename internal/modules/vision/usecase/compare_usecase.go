@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"fmt"
+
+	"vision-api-app/internal/modules/vision/domain"
+)
+
+// DefaultMaxCompareLines 比較できる1つのテキストの行数の上限（差分の計算に使うメモリを抑える）
+const DefaultMaxCompareLines = 500
+
+// CompareUseCase 2つの画像（契約書の版違いのページなど）から抽出したテキストの差分を求めるユースケース
+type CompareUseCase struct {
+	maxLines int
+}
+
+// NewCompareUseCase 新しいCompareUseCaseを作成（maxLinesが0以下の場合はデフォルト値）
+func NewCompareUseCase(maxLines int) *CompareUseCase {
+	if maxLines <= 0 {
+		maxLines = DefaultMaxCompareLines
+	}
+	return &CompareUseCase{
+		maxLines: maxLines,
+	}
+}
+
+// Compare 変更前と変更後のテキストの行単位の差分を求める
+func (uc *CompareUseCase) Compare(before, after string) (*domain.TextDiff, error) {
+	diff, err := domain.DiffText(before, after, uc.maxLines)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare text: %w (max %d lines)", err, uc.maxLines)
+	}
+	return diff, nil
+}
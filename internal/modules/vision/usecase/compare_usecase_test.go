@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"vision-api-app/internal/modules/vision/domain"
+)
+
+func TestCompareUseCase_Compare(t *testing.T) {
+	uc := NewCompareUseCase(0)
+
+	diff, err := uc.Compare("甲と乙は\n契約期間は1年とする", "甲と乙は\n契約期間は2年とする")
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+	if diff.Added != 1 || diff.Removed != 1 || diff.Unchanged != 1 {
+		t.Errorf("Compare() = +%d -%d =%d, want +1 -1 =1", diff.Added, diff.Removed, diff.Unchanged)
+	}
+}
+
+func TestCompareUseCase_Compare_TooLarge(t *testing.T) {
+	uc := NewCompareUseCase(2)
+
+	_, err := uc.Compare("A", strings.Repeat("line\n", 3))
+	if !errors.Is(err, domain.ErrDiffTooLarge) {
+		t.Errorf("Compare() error = %v, want ErrDiffTooLarge", err)
+	}
+}
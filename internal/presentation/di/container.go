@@ -1,43 +1,191 @@
 package di
 
 import (
+	"cmp"
+	"context"
+	"crypto/rand"
 	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
 
 	"vision-api-app/internal/config"
+	analyticsDomain "vision-api-app/internal/modules/analytics/domain"
+	analyticsHandler "vision-api-app/internal/modules/analytics/presentation/handler"
+	analyticsUsecase "vision-api-app/internal/modules/analytics/usecase"
+	expenseReportDomain "vision-api-app/internal/modules/expensereport/domain"
+	expenseReportHandler "vision-api-app/internal/modules/expensereport/presentation/handler"
+	expenseReportUsecase "vision-api-app/internal/modules/expensereport/usecase"
+	householdEntity "vision-api-app/internal/modules/household/domain/entity"
+	householdRepository "vision-api-app/internal/modules/household/domain/repository"
 	householdHandler "vision-api-app/internal/modules/household/presentation/handler"
 	householdUsecase "vision-api-app/internal/modules/household/usecase"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+	sharedAccounting "vision-api-app/internal/modules/shared/infrastructure/accounting"
 	sharedAI "vision-api-app/internal/modules/shared/infrastructure/ai"
 	sharedCache "vision-api-app/internal/modules/shared/infrastructure/cache"
 	sharedDB "vision-api-app/internal/modules/shared/infrastructure/database"
+	sharedEgress "vision-api-app/internal/modules/shared/infrastructure/egress"
+	sharedEvents "vision-api-app/internal/modules/shared/infrastructure/events"
+	sharedFeatureFlag "vision-api-app/internal/modules/shared/infrastructure/featureflag"
+	sharedFetcher "vision-api-app/internal/modules/shared/infrastructure/fetcher"
+	sharedIDGen "vision-api-app/internal/modules/shared/infrastructure/idgen"
+	sharedImaging "vision-api-app/internal/modules/shared/infrastructure/imaging"
+	sharedLine "vision-api-app/internal/modules/shared/infrastructure/line"
+	sharedNotifier "vision-api-app/internal/modules/shared/infrastructure/notifier"
+	sharedParquet "vision-api-app/internal/modules/shared/infrastructure/parquet"
+	sharedPublicStats "vision-api-app/internal/modules/shared/infrastructure/publicstats"
+	sharedQueue "vision-api-app/internal/modules/shared/infrastructure/queue"
+	sharedScanner "vision-api-app/internal/modules/shared/infrastructure/scanner"
+	sharedScheduler "vision-api-app/internal/modules/shared/infrastructure/scheduler"
+	sharedSecrets "vision-api-app/internal/modules/shared/infrastructure/secrets"
+	sharedStorage "vision-api-app/internal/modules/shared/infrastructure/storage"
+	visionDomain "vision-api-app/internal/modules/vision/domain"
 	visionHandler "vision-api-app/internal/modules/vision/presentation/handler"
 	visionUsecase "vision-api-app/internal/modules/vision/usecase"
+	"vision-api-app/internal/presentation/http/admin"
+	"vision-api-app/internal/presentation/http/health"
+	"vision-api-app/internal/presentation/http/middleware"
+	"vision-api-app/internal/presentation/http/spa"
+	"vision-api-app/internal/presentation/web"
+	webAssets "vision-api-app/web"
 )
 
+// spoolReplayInterval 一時保管したレシートをデータベースへ書き戻す間隔
+const spoolReplayInterval = 30 * time.Second
+
 // Container DIコンテナ
 type Container struct {
 	// Shared Infrastructure
-	aiRepo      *sharedAI.ClaudeRepository
-	cacheRepo   *sharedCache.RedisRepository
-	receiptRepo *sharedDB.BunReceiptRepository
-	expenseRepo *sharedDB.BunExpenseRepository
+	transport     http.RoundTripper // 外部への接続で共通のTransport（プロキシ・追加のルート証明書）
+	aiRepo        aiRepository
+	aiExchangeLog *sharedAI.ExchangeLog
+	aiBudget      *sharedAI.BudgetedRepository
+	components    []admin.ContainerComponent // 組み立てた実装（管理APIでの確認用）
+	cacheRepo     *sharedCache.RedisRepository
+	locker        *sharedCache.RedisLocker
+	receiptRepo   *sharedDB.BunReceiptRepository
+	revisionRepo  *sharedDB.BunReceiptRevisionRepository
+	expenseRepo   *sharedDB.BunExpenseRepository
+	categoryRepo  *sharedDB.BunCategoryRepository
+	splitRepo     *sharedDB.BunSplitRepository
+	mergeRepo     *sharedDB.BunReceiptMergeRepository
+	trashRepo     *sharedDB.BunTrashRepository
+	itemAliasRepo *sharedDB.BunItemAliasRepository
+	aggregateRepo *sharedDB.BunAggregateRepository
+	syncRepo      *sharedDB.BunAccountingSyncRepository
+	reportRepo    *sharedDB.BunExpenseReportRepository
+	collectRepo   *sharedDB.BunCollectionRepository
+	spendRepo     *sharedDB.BunSpendContributionRepository
+	jobQueue      sharedDomain.JobQueue
+	jobTracker    sharedDomain.JobTracker
+	imageStorage  sharedDomain.ImageStorage
+	receiptSpool  *sharedStorage.FileReceiptSpool
+	scheduler     *sharedScheduler.Scheduler
 
 	// Vision Module
 	aiCorrectionUseCase *visionUsecase.AICorrectionUseCase
 	visionHandler       *visionHandler.VisionHandler
 
 	// Household Module
-	receiptUseCase   *householdUsecase.ReceiptUseCase
-	householdUseCase *householdUsecase.HouseholdUseCase
-	webHandler       *householdHandler.WebHandler
+	receiptUseCase     *householdUsecase.ReceiptUseCase
+	householdUseCase   *householdUsecase.HouseholdUseCase
+	warehouseUseCase   *householdUsecase.WarehouseExportUseCase
+	webHandler         *web.Handler
+	receiptHandler     *householdHandler.ReceiptHandler
+	lineHandler        *householdHandler.LineHandler
+	categoryHandler    *householdHandler.CategoryHandler
+	itemHandler        *householdHandler.ItemHandler
+	warrantyHandler    *householdHandler.WarrantyHandler
+	calendarHandler    *householdHandler.CalendarHandler
+	splitHandler       *householdHandler.SplitHandler
+	accountingHandler  *householdHandler.AccountingHandler
+	reconcileHandler   *householdHandler.ReconciliationHandler
+	documentHandler    *householdHandler.DocumentHandler
+	collectionHandler  *householdHandler.CollectionHandler
+	uploadHandler      *householdHandler.UploadHandler
+	draftHandler       *householdHandler.DraftHandler
+	mergeHandler       *householdHandler.MergeHandler
+	trashHandler       *householdHandler.TrashHandler
+	interchangeHandler *householdHandler.InterchangeHandler
+	backfillHandler    *householdHandler.BackfillHandler
+	expenseHandler     *householdHandler.ExpenseHandler
+	reportHandler      *householdHandler.ReportHandler
+	widgetHandler      *householdHandler.WidgetHandler
+	spaHandler         *spa.Handler
+
+	// Analytics Module
+	suggestionHandler  *analyticsHandler.SuggestionHandler
+	publicStatsHandler *analyticsHandler.PublicStatsHandler
+
+	// Expense Report Module
+	expenseReportHandler *expenseReportHandler.ExpenseReportHandler
+
+	// Operations
+	maintenance       *middleware.Maintenance
+	realIP            *middleware.RealIP
+	loadShedder       *middleware.LoadShedder
+	featureFlags      *middleware.FeatureFlags
+	responseTransform *middleware.ResponseTransform
+	responseCache     *middleware.ResponseCache
+	slo               *middleware.SLOTracker
+	location          *time.Location
+	currency          sharedDomain.Currency
+	clock             sharedDomain.Clock
+	stopFeatureFlags  context.CancelFunc
+	adminHandler      *admin.Handler
+	adminToken        string
+	apiKeys           []string
+	diagnostics       config.DiagnosticsConfig
+	cacheStats        config.CacheStatsConfig
+	serverTLS         config.ServerTLSConfig
+	healthHandler     *health.Handler
 }
 
 // NewContainer 新しいContainerを作成
-func NewContainer(cfg *config.Config) (*Container, error) {
+// オプションで導入先ごとのレシート処理のフック・時計を組み込める
+func NewContainer(cfg *config.Config, opts ...Option) (*Container, error) {
 	container := &Container{}
+	o := newOptions(opts)
+
+	// Shared: Clock（作成日時・更新日時・期限の判定、組み立てた実装の初期化日時に使う現在時刻）
+	container.clock = sharedDomain.SystemClock{}
+	if o.clock != nil {
+		container.clock = o.clock
+	}
+
+	// Shared Infrastructure: Outbound HTTP（AI・Webhook・画像のURLなど外部への接続で共通のプロキシ・ルート証明書）
+	outbound, err := sharedEgress.New(sharedEgress.Options{
+		ProxyURL: cfg.Outbound.ProxyURL,
+		NoProxy:  cfg.Outbound.NoProxy,
+		CAFile:   cfg.Outbound.CAFile,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize outbound http: %w", err)
+	}
+	transport := outbound.Transport()
+	container.transport = transport
 
 	// Shared Infrastructure: AI Repository
-	aiRepo := sharedAI.NewClaudeRepository(&cfg.Anthropic)
+	aiRepo, err := newAIRepository(cfg, transport)
+	if err != nil {
+		return nil, err
+	}
 	container.aiRepo = aiRepo
+	container.recordAIRepository(cfg, aiRepo)
+
+	// Shared Infrastructure: AI Debug Log（プロンプト・レスポンスの記録、調査時のみ有効化）
+	if cfg.AIDebug.Enabled {
+		exchangeLog, err := sharedAI.NewExchangeLog(cfg.AIDebug.Capacity, cfg.AIDebug.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize AI debug log: %w", err)
+		}
+		aiRepo.SetExchangeLog(exchangeLog)
+		container.aiExchangeLog = exchangeLog
+		slog.Warn("AI debug logging is enabled, prompts and responses are recorded", "capacity", cfg.AIDebug.Capacity, "file", cfg.AIDebug.File)
+	}
 
 	// Shared Infrastructure: Cache Repository
 	cacheRepo, err := sharedCache.NewRedisRepository(&cfg.Redis)
@@ -45,45 +193,972 @@ func NewContainer(cfg *config.Config) (*Container, error) {
 		return nil, fmt.Errorf("failed to initialize cache repository: %w", err)
 	}
 	container.cacheRepo = cacheRepo
+	container.recordCache(&cfg.Redis)
+	if cfg.CacheStats.Enabled {
+		cacheRepo.SetStats(sharedCache.NewStats(cfg.CacheStats.MaxSeries))
+	}
 
-	// Shared Infrastructure: Receipt Repository
-	receiptRepo, err := sharedDB.NewBunReceiptRepository(&cfg.MySQL)
+	// Shared Infrastructure: Distributed Lock
+	locker, err := sharedCache.NewRedisLocker(&cfg.Redis)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize receipt repository: %w", err)
+		return nil, fmt.Errorf("failed to initialize distributed lock: %w", err)
 	}
-	container.receiptRepo = receiptRepo
+	container.locker = locker
 
-	// Shared Infrastructure: Expense Repository
-	expenseRepo, err := sharedDB.NewBunExpenseRepository(&cfg.MySQL)
+	// Shared Infrastructure: Job Queue
+	switch cfg.Queue.Backend {
+	case "", "memory":
+		container.jobQueue = sharedQueue.NewMemoryQueue(cfg.Queue.Workers, cfg.Queue.BufferSize)
+		container.jobTracker = sharedQueue.NewMemoryJobTracker()
+	case "redis":
+		jobQueue, err := sharedQueue.NewRedisStreamQueue(&cfg.Redis, &cfg.Queue)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize job queue: %w", err)
+		}
+		container.jobQueue = jobQueue
+		container.jobTracker = jobQueue.Tracker()
+	default:
+		return nil, fmt.Errorf("unknown job queue backend: %s", cfg.Queue.Backend)
+	}
+	container.recordComponent("queue", cmp.Or(cfg.Queue.Backend, "memory"), cfg.Queue, nil)
+
+	// Shared Infrastructure: File Scanner
+	var fileScanner sharedDomain.FileScanner
+	scanTimeout := time.Duration(cfg.Scanner.TimeoutSeconds) * time.Second
+	switch cfg.Scanner.Backend {
+	case "", "none":
+	case "clamav":
+		fileScanner = sharedScanner.NewClamAVScanner(cfg.Scanner.Address, scanTimeout)
+	case "http":
+		httpScanner := sharedScanner.NewHTTPScanner(cfg.Scanner.URL, scanTimeout)
+		httpScanner.SetTransport(transport)
+		fileScanner = httpScanner
+	default:
+		return nil, fmt.Errorf("unknown file scanner backend: %s", cfg.Scanner.Backend)
+	}
+	container.recordComponent("scanner", cmp.Or(cfg.Scanner.Backend, "none"), cfg.Scanner, nil)
+
+	// Shared: Timezone（購入日の解釈・期間の集計に使うデフォルトのタイムゾーン）
+	location, err := cfg.Locale.Location()
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize expense repository: %w", err)
+		return nil, err
 	}
-	container.expenseRepo = expenseRepo
+	container.location = location
+
+	// Shared: Currency（金額は通貨の最小単位の整数で保存する）
+	currency, err := sharedDomain.LookupCurrency(cfg.Locale.Currency)
+	if err != nil {
+		return nil, err
+	}
+	container.currency = currency
+
+	// Shared Infrastructure: AI Budget（AI APIの利用料金の月ごとの記録、予算超過時のカテゴリーの判定の縮退）
+	var budgetedAIRepo visionDomain.AIRepository = aiRepo
+	if cfg.AIBudget.MonthlyUSD > 0 {
+		aiBudget, err := newAIBudget(cfg, aiRepo, transport, container.aiExchangeLog)
+		if err != nil {
+			return nil, err
+		}
+		aiBudget.SetNotifier(newNotifier(&cfg.Notifications, transport))
+		aiBudget.SetClock(container.clock)
+		aiBudget.SetLocation(location)
+		// 再起動しても当月の利用料金を引き継ぎ、すべてのインスタンスで1つの予算を判定するためRedisに記録する
+		aiBudget.SetLedger(cacheRepo.AIBudgetLedger())
+		container.aiBudget = aiBudget
+		budgetedAIRepo = aiBudget
+		container.recordAIBudget(&cfg.AIBudget)
+	}
+
+	// Shared Infrastructure: Scheduler
+	container.scheduler = sharedScheduler.NewScheduler()
+	container.scheduler.SetLocker(locker)
 
 	// Vision Module: UseCase
-	aiCorrectionUseCase := visionUsecase.NewAICorrectionUseCase(aiRepo)
+	aiCorrectionUseCase := visionUsecase.NewAICorrectionUseCase(budgetedAIRepo)
 	container.aiCorrectionUseCase = aiCorrectionUseCase
 
 	// Vision Module: Handler
 	visionHandler := visionHandler.NewVisionHandler(aiCorrectionUseCase, cacheRepo)
+	visionHandler.SetFileScanner(fileScanner)
+	if cfg.ImageURLs.Enabled {
+		visionHandler.SetImageFetcher(sharedFetcher.NewImageFetcher(sharedFetcher.Options{
+			AllowedHosts: cfg.ImageURLs.AllowedHosts,
+			MaxSize:      int64(cfg.ImageURLs.MaxSizeMB) << 20,
+			Timeout:      time.Duration(cfg.ImageURLs.TimeoutSeconds) * time.Second,
+			MaxRedirects: cfg.ImageURLs.MaxRedirects,
+			Proxy:        outbound.Proxy,
+			TLSConfig:    outbound.TLSConfig(),
+		}))
+	}
 	container.visionHandler = visionHandler
 
+	// Household Module（MySQL未設定の場合はレシートの保存を無効化し、AIのみのエンドポイントで起動）
+	container.recordDatabase(&cfg.MySQL)
+	if cfg.MySQL.Configured() {
+		if err := container.initHousehold(cfg, o, budgetedAIRepo, cacheRepo, fileScanner); err != nil {
+			return nil, err
+		}
+	} else {
+		slog.Warn("MySQL is not configured, receipt persistence is disabled")
+	}
+
+	// Operations: Maintenance Mode / Admin API
+	container.maintenance = middleware.NewMaintenance(cfg.Maintenance.Enabled, cfg.Maintenance.Message)
+	realIP, err := middleware.NewRealIP(cfg.ClientIP.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize client ip resolution: %w", err)
+	}
+	container.realIP = realIP
+	if cfg.LoadShedding.Enabled {
+		container.loadShedder = middleware.NewLoadShedder(middleware.LoadSheddingLimits{
+			MaxInFlight:       cfg.LoadShedding.MaxInFlight,
+			MaxQueue:          cfg.LoadShedding.MaxQueue,
+			QueueTimeout:      time.Duration(cfg.LoadShedding.QueueTimeoutSeconds) * time.Second,
+			LargeRequestBytes: int64(cfg.LoadShedding.LargeRequestKB) << 10,
+			MaxLargeInFlight:  cfg.LoadShedding.MaxLargeInFlight,
+			RetryAfter:        time.Duration(cfg.LoadShedding.RetryAfterSeconds) * time.Second,
+		})
+	}
+	container.featureFlags = middleware.NewFeatureFlags(cfg.Features.Flags)
+	if cfg.Reports.CacheTTLSeconds > 0 {
+		container.responseCache = middleware.NewResponseCache(cacheRepo, time.Duration(cfg.Reports.CacheTTLSeconds)*time.Second)
+	}
+	container.responseTransform = middleware.NewResponseTransform(cfg.Response.FieldNaming, cfg.Response.LocalizedFields, cfg.Response.Translations)
+	if cfg.Features.RemoteURL != "" {
+		interval := time.Duration(cfg.Features.RefreshSeconds) * time.Second
+		if interval <= 0 {
+			interval = time.Minute
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		container.stopFeatureFlags = cancel
+		provider := sharedFeatureFlag.NewHTTPProvider(cfg.Features.RemoteURL)
+		provider.SetTransport(transport)
+		go container.featureFlags.Watch(ctx, provider, interval)
+	}
+	if cfg.SLO.Enabled {
+		container.slo = newSLOTracker(&cfg.SLO, newNotifier(&cfg.Notifications, transport))
+		// 処理結果はインスタンスごとに記録するため、全インスタンスで判定する
+		interval := time.Duration(cfg.SLO.CheckIntervalSeconds) * time.Second
+		if interval <= 0 {
+			interval = time.Minute
+		}
+		container.scheduler.AddLocal("slo-check", interval, func(ctx context.Context) error {
+			_, err := container.slo.Check(ctx)
+			return err
+		})
+	}
+	container.adminHandler = admin.NewHandler(container.maintenance, container.featureFlags, container.receiptUseCase)
+	container.adminHandler.SetSLOTracker(container.slo)
+	if container.aiExchangeLog != nil {
+		container.adminHandler.SetAIExchangeLog(container.aiExchangeLog)
+	}
+	if container.aiBudget != nil {
+		container.adminHandler.SetAIBudget(container.aiBudget)
+	}
+	if container.warehouseUseCase != nil {
+		container.adminHandler.SetWarehouseExportUseCase(container.warehouseUseCase)
+	}
+	if cfg.CacheStats.Enabled {
+		container.adminHandler.SetCacheInspector(cacheRepo, cfg.CacheStats.SampleKeys)
+	}
+	container.recordNotifier(&cfg.Notifications)
+	container.adminHandler.SetContainerComponents(container.components)
+	container.adminToken = cfg.Admin.Token
+	container.apiKeys = cfg.APIKeys.Keys
+	container.diagnostics = cfg.Diagnostics
+	container.cacheStats = cfg.CacheStats
+	container.serverTLS = cfg.ServerTLS
+	container.healthHandler = health.NewHandler(container.receiptUseCase, cacheRepo)
+
+	container.scheduler.Start()
+	// 処理関数をすべて登録してからジョブの受信を開始する（前回の起動時の未処理のジョブを取りこぼさないため）
+	container.jobQueue.Start()
+
+	return container, nil
+}
+
+// checkStoredCurrency 保存している金額の通貨が設定の通貨と一致するか確認
+// 通貨を記録するテーブルがない（マイグレーション未適用の）場合は警告のみで起動する
+func checkStoredCurrency(cfg *config.MySQLConfig, currency sharedDomain.Currency) error {
+	rescaler, err := sharedDB.NewBunAmountRescaler(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize amount rescaler: %w", err)
+	}
+	defer func() { _ = rescaler.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stored, err := rescaler.StoredCurrency(ctx)
+	if err != nil {
+		slog.Warn("Failed to check stored amount currency, apply scripts/migrations/013_amount_minor_units.sql", "error", err)
+		return nil
+	}
+	if stored != currency.Code {
+		return fmt.Errorf("amounts are stored in %s but locale.currency is %s: run `vision-api rescale-amounts` to convert them", stored, currency.Code)
+	}
+	return nil
+}
+
+// initHousehold レシートの保存を伴う家計簿モジュールを初期化
+func (c *Container) initHousehold(cfg *config.Config, o options, aiRepo visionDomain.AIRepository, cacheRepo *sharedCache.RedisRepository, fileScanner sharedDomain.FileScanner) error {
+	// Shared Infrastructure: Receipt Repository
+	receiptRepo, err := sharedDB.NewBunReceiptRepository(&cfg.MySQL)
+	if err != nil {
+		return fmt.Errorf("failed to initialize receipt repository: %w", err)
+	}
+	c.receiptRepo = receiptRepo
+
+	// Shared Infrastructure: Receipt Repository Cache（IDで取得したレシートを短い期間キャッシュし、書き込んだ場合は削除する）
+	var receipts householdRepository.ReceiptRepository = receiptRepo
+	if cfg.RepoCache.Enabled {
+		receipts = sharedCache.NewCachedReceiptRepository(receiptRepo, cacheRepo, time.Duration(cfg.RepoCache.TTLSeconds)*time.Second)
+	}
+
+	// Shared Infrastructure: Amount Currency（保存済みの金額と設定の通貨が異なる場合は金額を取り違えるため起動しない）
+	if err := checkStoredCurrency(&cfg.MySQL, c.currency); err != nil {
+		return err
+	}
+
+	// Shared Infrastructure: Receipt Revision Repository
+	revisionRepo, err := sharedDB.NewBunReceiptRevisionRepository(&cfg.MySQL)
+	if err != nil {
+		return fmt.Errorf("failed to initialize receipt revision repository: %w", err)
+	}
+	c.revisionRepo = revisionRepo
+
+	// Shared Infrastructure: Expense Repository
+	expenseRepo, err := sharedDB.NewBunExpenseRepository(&cfg.MySQL)
+	if err != nil {
+		return fmt.Errorf("failed to initialize expense repository: %w", err)
+	}
+	c.expenseRepo = expenseRepo
+
+	// Shared Infrastructure: Category Repository（一覧はキャッシュし、書き込んだ場合は削除する）
+	categoryRepo, err := sharedDB.NewBunCategoryRepository(&cfg.MySQL)
+	if err != nil {
+		return fmt.Errorf("failed to initialize category repository: %w", err)
+	}
+	c.categoryRepo = categoryRepo
+	var categories householdRepository.CategoryRepository = categoryRepo
+	if cfg.RepoCache.Enabled {
+		categories = sharedCache.NewCachedCategoryRepository(categoryRepo, cacheRepo, time.Duration(cfg.RepoCache.TTLSeconds)*time.Second)
+	}
+
+	// Shared Infrastructure: Split Repository
+	splitRepo, err := sharedDB.NewBunSplitRepository(&cfg.MySQL)
+	if err != nil {
+		return fmt.Errorf("failed to initialize split repository: %w", err)
+	}
+	c.splitRepo = splitRepo
+
+	// Shared Infrastructure: Receipt Merge Repository（二重登録したレシートの統合の記録）
+	mergeRepo, err := sharedDB.NewBunReceiptMergeRepository(&cfg.MySQL)
+	if err != nil {
+		return fmt.Errorf("failed to initialize receipt merge repository: %w", err)
+	}
+	c.mergeRepo = mergeRepo
+
+	// Shared Infrastructure: Trash Repository（削除したレシート・家計簿エントリのゴミ箱）
+	trashRepo, err := sharedDB.NewBunTrashRepository(&cfg.MySQL)
+	if err != nil {
+		return fmt.Errorf("failed to initialize trash repository: %w", err)
+	}
+	c.trashRepo = trashRepo
+
+	// Shared Infrastructure: Item Alias Repository（商品名の別名辞書）
+	itemAliasRepo, err := sharedDB.NewBunItemAliasRepository(&cfg.MySQL)
+	if err != nil {
+		return fmt.Errorf("failed to initialize item alias repository: %w", err)
+	}
+	c.itemAliasRepo = itemAliasRepo
+
+	// Shared Infrastructure: Aggregate Repository（レポートの集計）
+	aggregateRepo, err := sharedDB.NewBunAggregateRepository(&cfg.MySQL)
+	if err != nil {
+		return fmt.Errorf("failed to initialize aggregate repository: %w", err)
+	}
+	c.aggregateRepo = aggregateRepo
+
+	// Shared Infrastructure: Expense Report Repository（会社の経費報告書）
+	reportRepo, err := sharedDB.NewBunExpenseReportRepository(&cfg.MySQL)
+	if err != nil {
+		return fmt.Errorf("failed to initialize expense report repository: %w", err)
+	}
+	c.reportRepo = reportRepo
+
+	// Shared Infrastructure: Collection Repository（レシートのコレクション）
+	collectRepo, err := sharedDB.NewBunCollectionRepository(&cfg.MySQL)
+	if err != nil {
+		return fmt.Errorf("failed to initialize collection repository: %w", err)
+	}
+	c.collectRepo = collectRepo
+
+	// Shared Infrastructure: Accounting Sync Repository
+	syncRepo, err := sharedDB.NewBunAccountingSyncRepository(&cfg.MySQL)
+	if err != nil {
+		return fmt.Errorf("failed to initialize accounting sync repository: %w", err)
+	}
+	c.syncRepo = syncRepo
+
+	// Shared Infrastructure: Image Storage
+	imageStorage, err := sharedStorage.NewLocalImageStorage(cfg.Storage.ImageDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize image storage: %w", err)
+	}
+	imageStorage.SetQuota(int64(cfg.Storage.QuotaMB) << 20)
+	c.imageStorage = imageStorage
+	c.recordComponent("storage", "local", cfg.Storage, map[string]string{
+		"image_dir": cfg.Storage.ImageDir,
+		"spool_dir": cfg.Storage.SpoolDir,
+	})
+
+	// Shared Infrastructure: Receipt Spool（データベース障害中のレシートの一時保管先）
+	receiptSpool, err := sharedStorage.NewFileReceiptSpool(cfg.Storage.SpoolDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize receipt spool: %w", err)
+	}
+	c.receiptSpool = receiptSpool
+
+	// Shared Infrastructure: ID Generator
+	idGenerator, err := sharedIDGen.New(cfg.IDs.Strategy)
+	if err != nil {
+		return err
+	}
+
+	// Household Module: Name Normalizer
+	nameNormalizer, err := newNameNormalizer(&cfg.Names)
+	if err != nil {
+		return err
+	}
+
 	// Household Module: Receipt UseCase
-	receiptUseCase := householdUsecase.NewReceiptUseCase(aiRepo, receiptRepo, cacheRepo)
-	container.receiptUseCase = receiptUseCase
+	receiptUseCase := householdUsecase.NewReceiptUseCase(aiRepo, receipts, cacheRepo)
+	receiptUseCase.SetJobQueue(c.jobQueue)
+	receiptUseCase.SetRevisionRepository(revisionRepo)
+	receiptUseCase.SetImageStorage(imageStorage)
+	receiptUseCase.SetMetadataStripper(sharedImaging.NewMetadataStripper())
+	receiptUseCase.SetFileScanner(fileScanner)
+	receiptUseCase.SetReceiptSpool(receiptSpool)
+	receiptUseCase.SetIDGenerator(idGenerator)
+	receiptUseCase.SetCurrency(c.currency)
+	receiptUseCase.SetClock(c.clock)
+	receiptUseCase.SetNameNormalizer(nameNormalizer)
+	receiptUseCase.SetPurchaseDateRules(householdUsecase.PurchaseDateRules{
+		MaxAgeYears:     cfg.PurchaseDates.MaxAgeYears,
+		FutureTolerance: time.Duration(cfg.PurchaseDates.FutureToleranceHours) * time.Hour,
+		Clamp:           cfg.PurchaseDates.Clamp,
+	})
+	receiptUseCase.SetCategoryRules(householdUsecase.CategoryRules(cfg.AIBudget.Rules))
+	receiptUseCase.SetItemAliasRepository(itemAliasRepo)
+	receiptUseCase.SetCategoryRepository(categories)
+	receiptUseCase.SetImageHooks(o.imageHooks...)
+	receiptUseCase.SetReceiptHooks(o.receiptHooks...)
+	eventBus := newEventBus(&cfg.Events, newNotifier(&cfg.Notifications, c.transport))
+	receiptUseCase.SetEventPublisher(eventBus)
+	c.receiptUseCase = receiptUseCase
 
 	// Household Module: Household UseCase
-	householdUseCase := householdUsecase.NewHouseholdUseCase(receiptRepo, expenseRepo)
-	container.householdUseCase = householdUseCase
+	householdUseCase := householdUsecase.NewHouseholdUseCase(receipts, expenseRepo)
+	householdUseCase.SetMonthStartDay(cfg.Reports.MonthStartDay)
+	householdUseCase.SetAggregateRepository(aggregateRepo)
+	c.householdUseCase = householdUseCase
+
+	// Household Module: Medical Report UseCase
+	medicalReportUseCase := householdUsecase.NewMedicalReportUseCase(receipts, householdUsecase.MedicalRules{
+		Categories:       cfg.Reports.Medical.Categories,
+		Keywords:         cfg.Reports.Medical.Keywords,
+		PatientTagPrefix: cfg.Reports.Medical.PatientTagPrefix,
+		DefaultPatient:   cfg.Reports.Medical.DefaultPatient,
+	})
 
-	// Household Module: Web Handler
-	webHandler, err := householdHandler.NewWebHandler(receiptUseCase, householdUseCase)
+	// Web UI: Server-rendered Pages
+	webHandler, err := web.NewHandler(receiptUseCase, householdUseCase, medicalReportUseCase)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize web handler: %w", err)
+		return fmt.Errorf("failed to initialize web handler: %w", err)
 	}
-	container.webHandler = webHandler
+	c.webHandler = webHandler
 
-	return container, nil
+	// Web UI: Embedded SPA（classicの場合はサーバーレンダリングの画面のみ）
+	switch cfg.Web.UI {
+	case "", "spa":
+		appFiles, err := fs.Sub(webAssets.App, "app")
+		if err != nil {
+			return fmt.Errorf("failed to load SPA files: %w", err)
+		}
+		spaHandler, err := spa.NewHandler(appFiles)
+		if err != nil {
+			return fmt.Errorf("failed to initialize SPA handler: %w", err)
+		}
+		spaHandler.SetCurrency(c.currency)
+		c.spaHandler = spaHandler
+	case "classic":
+	default:
+		return fmt.Errorf("unknown web ui: %s", cfg.Web.UI)
+	}
+
+	// Household Module: Receipt API Handler
+	c.receiptHandler = householdHandler.NewReceiptHandler(receiptUseCase)
+
+	// Household Module: LINE Webhook Handler（トークで送ったレシートの写真を登録）
+	if cfg.Line.Enabled {
+		lineHandler, err := newLineHandler(&cfg.Line, receiptUseCase, c.jobQueue, c.transport)
+		if err != nil {
+			return err
+		}
+		c.lineHandler = lineHandler
+	}
+
+	// Household Module: Category API Handler
+	categoryUseCase := householdUsecase.NewCategoryUseCase(categories)
+	categoryUseCase.SetIDGenerator(idGenerator)
+	categoryUseCase.SetClock(c.clock)
+	c.categoryHandler = householdHandler.NewCategoryHandler(receiptUseCase, categoryUseCase)
+
+	// Household Module: Item API Handler
+	c.itemHandler = householdHandler.NewItemHandler(
+		householdUsecase.NewPriceHistoryUseCase(receipts),
+		householdUsecase.NewItemAliasUseCase(itemAliasRepo),
+	)
+
+	// Household Module: Direct Upload API Handler
+	uploadUseCase, err := newUploadUseCase(&cfg.Uploads, receiptUseCase, imageStorage, c.clock, c.transport)
+	if err != nil {
+		return err
+	}
+	c.uploadHandler = householdHandler.NewUploadHandler(uploadUseCase)
+
+	// Household Module: Draft API Handler（読み取り結果を確認してから保存）
+	draftTTL := time.Duration(cfg.Drafts.ExpiryMinutes) * time.Minute
+	if draftTTL <= 0 {
+		draftTTL = time.Hour
+	}
+	draftUseCase := householdUsecase.NewDraftUseCase(receiptUseCase, imageStorage, draftTTL)
+	c.draftHandler = householdHandler.NewDraftHandler(draftUseCase)
+
+	// Household Module: Merge API Handler（二重登録したレシートの統合と取り消し）
+	c.mergeHandler = householdHandler.NewMergeHandler(householdUsecase.NewMergeUseCase(receiptUseCase, mergeRepo))
+
+	// Household Module: Trash API Handler（削除・元に戻す・完全に削除）
+	trashRetention := time.Duration(cfg.Trash.RetentionDays) * 24 * time.Hour
+	if trashRetention <= 0 {
+		trashRetention = 30 * 24 * time.Hour
+	}
+	trashUseCase := householdUsecase.NewTrashUseCase(receiptUseCase, expenseRepo, trashRepo, trashRetention)
+	c.trashHandler = householdHandler.NewTrashHandler(trashUseCase)
+	c.trashHandler.SetClock(c.clock)
+
+	// Household Module: Interchange API Handler（インスタンス間の移行用のレシートの書き出し・読み込み）
+	c.interchangeHandler = householdHandler.NewInterchangeHandler(householdUsecase.NewInterchangeUseCase(receiptUseCase, expenseRepo))
+
+	// Household Module: Backfill API Handler（元画像のバッチ処理での読み直し、バッチ処理に対応したAIの場合のみ）
+	// 対応しているかは元のAIリポジトリで判定し、バッチ処理の利用料金も予算に含めるため予算を管理するAIリポジトリ経由で依頼する
+	var backfillUseCase *householdUsecase.BackfillUseCase
+	if _, ok := c.aiRepo.(visionDomain.BatchAIRepository); ok {
+		batchRepo := aiRepo.(visionDomain.BatchAIRepository)
+		backfillUseCase = householdUsecase.NewBackfillUseCase(receiptUseCase, batchRepo, c.jobTracker, householdUsecase.BackfillRules{
+			BatchSize:     cfg.Backfills.BatchSize,
+			BatchMaxBytes: int64(cfg.Backfills.BatchMaxMB) << 20,
+			MaxReceipts:   cfg.Backfills.MaxReceipts,
+		})
+		backfillUseCase.SetJobQueue(c.jobQueue)
+		c.backfillHandler = householdHandler.NewBackfillHandler(backfillUseCase)
+	}
+
+	// Household Module: Report API Handler
+	ledgerCurrency := c.currency
+	if cfg.Reports.Ledger.Currency != "" {
+		ledgerCurrency.Code = cfg.Reports.Ledger.Currency
+	}
+	ledgerUseCase := householdUsecase.NewLedgerUseCase(receipts, householdUsecase.LedgerRules{
+		Currency:          ledgerCurrency,
+		Accounts:          cfg.Reports.Ledger.Accounts,
+		DefaultAccount:    cfg.Reports.Ledger.DefaultAccount,
+		PaymentAccounts:   cfg.Reports.Ledger.PaymentAccounts,
+		PaymentAccount:    cfg.Reports.Ledger.PaymentAccount,
+		AdjustmentAccount: cfg.Reports.Ledger.AdjustmentAccount,
+	})
+	exportUseCase := householdUsecase.NewExportUseCase(receipts)
+	exportUseCase.SetCurrency(c.currency)
+	c.reportHandler = householdHandler.NewReportHandler(medicalReportUseCase, householdUseCase, ledgerUseCase, exportUseCase)
+	c.reportHandler.SetClock(c.clock)
+	c.widgetHandler = householdHandler.NewWidgetHandler(householdUsecase.NewWidgetUseCase(householdUseCase))
+
+	// Household Module: Warehouse Export（分析用のParquetファイルの書き出し）
+	if cfg.Warehouse.Enabled {
+		warehouseUseCase, err := newWarehouseExportUseCase(&cfg.Warehouse, receipts, expenseRepo, c.transport)
+		if err != nil {
+			return err
+		}
+		warehouseUseCase.SetCurrency(c.currency)
+		c.warehouseUseCase = warehouseUseCase
+	}
+
+	// Household Module: Expense API Handler
+	expenseUseCase := householdUsecase.NewExpenseUseCase(expenseRepo)
+	expenseUseCase.SetEventPublisher(eventBus)
+	expenseUseCase.SetClock(c.clock)
+	expenseUseCase.SetIDGenerator(idGenerator)
+	expenseUseCase.SetAggregateRepository(aggregateRepo)
+	expenseUseCase.SetMonthStartDay(cfg.Reports.MonthStartDay)
+	c.expenseHandler = householdHandler.NewExpenseHandler(expenseUseCase, householdUsecase.NewExpenseCategorizationUseCase(receiptUseCase))
+
+	// Household Module: Warranty API Handler
+	warrantyUseCase := householdUsecase.NewWarrantyUseCase(receipts, householdUsecase.WarrantyRules{
+		MinAmount:     int64(cfg.Warranties.MinAmount) * c.currency.Scale(),
+		DefaultMonths: cfg.Warranties.DefaultMonths,
+		ReturnDays:    cfg.Warranties.ReturnDays,
+		NotifyDays:    cfg.Warranties.NotifyDays,
+	})
+	warrantyUseCase.SetNotifier(newNotifier(&cfg.Notifications, c.transport))
+	c.warrantyHandler = householdHandler.NewWarrantyHandler(warrantyUseCase)
+
+	// Household Module: Calendar API Handler（返品・保証期限のiCalendarのフィード）
+	calendarUseCase, err := newCalendarUseCase(&cfg.Warranties.Calendar, warrantyUseCase)
+	if err != nil {
+		return err
+	}
+	calendarUseCase.SetCurrency(c.currency)
+	calendarUseCase.SetClock(c.clock)
+	c.calendarHandler = householdHandler.NewCalendarHandler(calendarUseCase)
+
+	// Household Module: Split API Handler
+	c.splitHandler = householdHandler.NewSplitHandler(householdUsecase.NewSplitUseCase(receipts, splitRepo))
+
+	// Household Module: Accounting Sync API Handler
+	accountingSyncUseCase := newAccountingSyncUseCase(&cfg.Accounting, receipts, syncRepo, c.transport)
+	c.accountingHandler = householdHandler.NewAccountingHandler(accountingSyncUseCase)
+
+	// Household Module: Reconciliation API Handler
+	reconciliationUseCase := householdUsecase.NewReconciliationUseCase(receipts, householdUsecase.ReconciliationRules{
+		DateWindowDays: cfg.Reconciliation.DateWindowDays,
+		PaymentMethods: cfg.Reconciliation.PaymentMethods,
+	})
+	reconciliationUseCase.SetCurrency(c.currency)
+	c.reconcileHandler = householdHandler.NewReconciliationHandler(reconciliationUseCase)
+
+	// Household Module: Document API Handler（レシートの画像と利用明細のCSVの自動振り分け）
+	c.documentHandler = householdHandler.NewDocumentHandler(householdUsecase.NewDocumentUseCase(receiptUseCase, reconciliationUseCase))
+
+	// Household Module: Collection API Handler（レシートのコレクションと、コレクションごとの書き出し・集計）
+	collectionUseCase := householdUsecase.NewCollectionUseCase(collectRepo, receipts)
+	collectionUseCase.SetIDGenerator(idGenerator)
+	c.collectionHandler = householdHandler.NewCollectionHandler(collectionUseCase)
+	c.collectionHandler.SetCurrency(c.currency)
+
+	// Analytics Module: Suggestion API Handler
+	shoppingListUseCase := analyticsUsecase.NewShoppingListUseCase(receipts, analyticsUsecase.ShoppingListRules{
+		LookbackDays: cfg.Analytics.ShoppingList.LookbackDays,
+		MinPurchases: cfg.Analytics.ShoppingList.MinPurchases,
+		HorizonDays:  cfg.Analytics.ShoppingList.HorizonDays,
+	})
+	c.suggestionHandler = analyticsHandler.NewSuggestionHandler(shoppingListUseCase)
+
+	// Analytics Module: Public Stats API Handler（統計を集計するサーバーとして、提供された食費の合計を地域別に公開）
+	if cfg.Analytics.PublicStats.Enabled {
+		spendRepo, err := sharedDB.NewBunSpendContributionRepository(&cfg.MySQL)
+		if err != nil {
+			return fmt.Errorf("failed to initialize spend contribution repository: %w", err)
+		}
+		c.spendRepo = spendRepo
+		publicStatsUseCase := analyticsUsecase.NewPublicStatsUseCase(spendRepo, analyticsUsecase.PublicStatsRules{
+			MinContributors: cfg.Analytics.PublicStats.MinContributors,
+			RoundTo:         int64(cfg.Analytics.PublicStats.RoundTo) * c.currency.Scale(),
+			MaxMonths:       cfg.Analytics.PublicStats.MaxMonths,
+		})
+		c.publicStatsHandler = analyticsHandler.NewPublicStatsHandler(publicStatsUseCase)
+	}
+
+	// Analytics Module: Spend Sharing（参加に同意した場合のみ、この世帯の前月の食費の合計を提供）
+	var spendSharingUseCase *analyticsUsecase.SpendSharingUseCase
+	if cfg.Analytics.PublicStats.Share.Enabled {
+		spendSharingUseCase, err = newSpendSharingUseCase(&cfg.Analytics.PublicStats.Share, aggregateRepo, c.currency, c.transport)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Expense Report Module: Expense Report API Handler（承認者のキーが空の場合はその承認者を無効にする）
+	expenseReportUseCase := expenseReportUsecase.NewExpenseReportUseCase(reportRepo, receipts)
+	expenseReportUseCase.SetIDGenerator(idGenerator)
+	var approvers []expenseReportUsecase.ApproverCredential
+	for _, approver := range cfg.ExpenseReports.Approvers {
+		if approver.Role != expenseReportDomain.RoleApprover && approver.Role != expenseReportDomain.RoleAccountant {
+			return fmt.Errorf("unknown expense report approver role: %s", approver.Role)
+		}
+		if approver.Key == "" {
+			continue
+		}
+		approvers = append(approvers, expenseReportUsecase.ApproverCredential{
+			Key:      approver.Key,
+			Approver: expenseReportDomain.Approver{Name: approver.Name, Role: approver.Role},
+		})
+	}
+	expenseReportUseCase.SetApprovers(approvers)
+	c.expenseReportHandler = expenseReportHandler.NewExpenseReportHandler(expenseReportUseCase, c.currency)
+
+	// Scheduled Tasks
+	if cfg.Storage.ImageRetentionDays > 0 {
+		retention := time.Duration(cfg.Storage.ImageRetentionDays) * 24 * time.Hour
+		c.scheduler.Add("receipt-image-retention", time.Hour, func(ctx context.Context) error {
+			_, err := receiptUseCase.ScrubExpiredImages(ctx, retention)
+			return err
+		})
+	}
+	if backfillUseCase != nil {
+		interval := time.Duration(cfg.Backfills.PollIntervalMinutes) * time.Minute
+		if interval <= 0 {
+			interval = 5 * time.Minute
+		}
+		c.scheduler.Add("receipt-backfill-poll", interval, withLocation(c.location, func(ctx context.Context) error {
+			_, err := backfillUseCase.PollBackfills(ctx)
+			return err
+		}))
+	}
+	c.scheduler.Add("warranty-expiry-notification", time.Hour, withLocation(c.location, func(ctx context.Context) error {
+		_, err := warrantyUseCase.NotifyExpiring(ctx)
+		return err
+	}))
+	if len(accountingSyncUseCase.Providers()) > 0 {
+		c.scheduler.Add("accounting-sync", time.Duration(cfg.Accounting.SyncIntervalMinutes)*time.Minute, withLocation(c.location, func(ctx context.Context) error {
+			_, err := accountingSyncUseCase.SyncPending(ctx)
+			return err
+		}))
+	}
+	if c.warehouseUseCase != nil {
+		interval := time.Duration(cfg.Warehouse.IntervalHours) * time.Hour
+		if interval <= 0 {
+			interval = 24 * time.Hour
+		}
+		c.scheduler.Add("warehouse-export", interval, withLocation(c.location, func(ctx context.Context) error {
+			_, err := c.warehouseUseCase.ExportRecent(ctx)
+			return err
+		}))
+	}
+	if spendSharingUseCase != nil {
+		c.scheduler.Add("public-stats-share", 24*time.Hour, withLocation(c.location, func(ctx context.Context) error {
+			_, err := spendSharingUseCase.SharePreviousMonth(ctx)
+			return err
+		}))
+	}
+	c.scheduler.Add("upload-cleanup", time.Hour, func(ctx context.Context) error {
+		_, err := uploadUseCase.CleanupExpiredUploads(ctx)
+		return err
+	})
+	c.scheduler.Add("draft-cleanup", time.Hour, func(ctx context.Context) error {
+		_, err := draftUseCase.CleanupExpiredDrafts(ctx)
+		return err
+	})
+	c.scheduler.Add("trash-purge", time.Hour, func(ctx context.Context) error {
+		_, err := trashUseCase.PurgeExpired(ctx)
+		return err
+	})
+	// 一時保管先は各インスタンスのローカルディスクのため、全インスタンスで書き戻す
+	c.scheduler.AddLocal("receipt-spool-replay", spoolReplayInterval, func(ctx context.Context) error {
+		_, err := receiptUseCase.ReplayPendingSaves(ctx)
+		return err
+	})
+
+	return nil
+}
+
+// withLocation 定期実行タスクのコンテキストにデフォルトのタイムゾーンを設定する（リクエストと同じ暦日で処理する）
+func withLocation(loc *time.Location, run sharedScheduler.TaskFunc) sharedScheduler.TaskFunc {
+	return func(ctx context.Context) error {
+		return run(sharedDomain.WithLocation(ctx, loc))
+	}
+}
+
+// aiRepository AIのプロバイダーのリポジトリ（デバッグ用の記録先を設定できる）
+type aiRepository interface {
+	visionDomain.AIRepository
+	SetExchangeLog(exchangeLog sharedDomain.AIExchangeLog)
+}
+
+// newAIRepository 設定のプロバイダー（anthropic・gemini）のAIリポジトリを作成
+func newAIRepository(cfg *config.Config, transport http.RoundTripper) (aiRepository, error) {
+	switch cfg.AI.Provider {
+	case "", "anthropic":
+		repo := sharedAI.NewClaudeRepository(&cfg.Anthropic)
+		repo.SetTransport(transport)
+		return repo, nil
+	case "gemini":
+		repo := sharedAI.NewGeminiRepository(&cfg.Gemini)
+		repo.SetTransport(transport)
+		return repo, nil
+	default:
+		return nil, fmt.Errorf("unsupported ai.provider %q (anthropic or gemini)", cfg.AI.Provider)
+	}
+}
+
+// newAIBudget AI APIの利用料金の記録・予算超過時の縮退を作成
+// cheap_modelの場合は、設定のプロバイダーでcheap_modelを使うAIリポジトリをあわせて作成する
+func newAIBudget(cfg *config.Config, aiRepo visionDomain.AIRepository, transport http.RoundTripper, exchangeLog *sharedAI.ExchangeLog) (*sharedAI.BudgetedRepository, error) {
+	budget := sharedAI.NewBudgetedRepository(aiRepo, &cfg.AIBudget)
+	switch cfg.AIBudget.Downgrade {
+	case "", sharedDomain.AIDowngradeRules:
+	case sharedDomain.AIDowngradeCheapModel:
+		if cfg.AIBudget.CheapModel == "" {
+			return nil, fmt.Errorf("ai_budget.cheap_model is required for downgrade %q", cfg.AIBudget.Downgrade)
+		}
+		cheapCfg := *cfg
+		cheapCfg.Anthropic.Model = cfg.AIBudget.CheapModel
+		cheapCfg.Gemini.Model = cfg.AIBudget.CheapModel
+		cheap, err := newAIRepository(&cheapCfg, transport)
+		if err != nil {
+			return nil, err
+		}
+		if exchangeLog != nil {
+			cheap.SetExchangeLog(exchangeLog)
+		}
+		budget.SetCheapRepository(cheap, cfg.AIBudget.CheapModel)
+	default:
+		return nil, fmt.Errorf("unsupported ai_budget.downgrade %q (cheap_model or rules)", cfg.AIBudget.Downgrade)
+	}
+	return budget, nil
+}
+
+// newSLOTracker レシート処理のSLOの記録・判定を作成
+func newSLOTracker(cfg *config.SLOConfig, notifier sharedDomain.Notifier) *middleware.SLOTracker {
+	tracker := middleware.NewSLOTracker(middleware.SLOObjectives{
+		Window:            time.Duration(cfg.WindowMinutes) * time.Minute,
+		LatencyPercentile: cfg.LatencyPercentile,
+		LatencyThreshold:  time.Duration(cfg.LatencyThresholdMs) * time.Millisecond,
+		SuccessTarget:     cfg.SuccessTarget,
+		MinRequests:       cfg.MinRequests,
+	})
+	tracker.SetNotifier(notifier)
+	return tracker
+}
+
+// newNotifier 通知の送信先を作成（Webhook未設定の場合はログに出力）
+func newNotifier(cfg *config.NotificationsConfig, transport http.RoundTripper) sharedDomain.Notifier {
+	if cfg.WebhookURL == "" {
+		return sharedNotifier.NewLogNotifier()
+	}
+	notifier := sharedNotifier.NewWebhookNotifier(cfg.WebhookURL, time.Duration(cfg.TimeoutSeconds)*time.Second)
+	notifier.SetTransport(transport)
+	return notifier
+}
+
+// newNameNormalizer 店名・商品名の正規化を作成（無効な場合はnil）
+func newNameNormalizer(cfg *config.NamesConfig) (*householdEntity.NameNormalizer, error) {
+	if !cfg.Normalize {
+		return nil, nil
+	}
+	rules := make([]householdEntity.NameRule, 0, len(cfg.Rules))
+	for _, rule := range cfg.Rules {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid names.rules pattern %q: %w", rule.Pattern, err)
+		}
+		rules = append(rules, householdEntity.NameRule{Pattern: pattern, Replace: rule.Replace})
+	}
+	return householdEntity.NewNameNormalizer(rules), nil
+}
+
+// newEventBus ドメインイベントの配信先を作成し、設定に応じて監査ログ・通知の購読者を登録
+func newEventBus(cfg *config.EventsConfig, notifier sharedDomain.Notifier) *sharedEvents.Bus {
+	bus := sharedEvents.NewBus()
+	if cfg.AuditLog {
+		bus.Subscribe(sharedEvents.AllEvents, sharedEvents.AuditLog())
+	}
+	for _, name := range cfg.Notify {
+		bus.Subscribe(name, sharedEvents.Notify(notifier))
+	}
+	return bus
+}
+
+// newAccountingSyncUseCase 会計サービスとの同期のユースケースを作成（有効な会計サービスのみ同期先に追加）
+// OAuthの認証情報は環境変数（FREEE_CLIENT_ID / FREEE_CLIENT_SECRET / FREEE_REFRESH_TOKEN など）から取得する
+func newAccountingSyncUseCase(cfg *config.AccountingConfig, receiptRepo householdRepository.ReceiptRepository, syncRepo *sharedDB.BunAccountingSyncRepository, transport http.RoundTripper) *householdUsecase.AccountingSyncUseCase {
+	uc := householdUsecase.NewAccountingSyncUseCase(receiptRepo, syncRepo, householdUsecase.AccountingSyncRules{
+		MaxAttempts: cfg.MaxAttempts,
+		BatchSize:   cfg.BatchSize,
+	})
+
+	secrets := sharedSecrets.NewEnvSecretProvider("")
+	options := func(p *config.AccountingProviderConfig) sharedAccounting.Options {
+		return sharedAccounting.Options{
+			BaseURL:        p.BaseURL,
+			TokenURL:       p.TokenURL,
+			CompanyID:      p.CompanyID,
+			Accounts:       p.Accounts,
+			DefaultAccount: p.DefaultAccount,
+			PaymentAccount: p.PaymentAccount,
+			TaxCode:        p.TaxCode,
+			Timeout:        time.Duration(cfg.TimeoutSeconds) * time.Second,
+		}
+	}
+	if cfg.Freee.Enabled {
+		client := sharedAccounting.NewFreeeClient(options(&cfg.Freee), secrets)
+		client.SetTransport(transport)
+		uc.AddProvider(client)
+	}
+	if cfg.MoneyForward.Enabled {
+		client := sharedAccounting.NewMoneyForwardClient(options(&cfg.MoneyForward), secrets)
+		client.SetTransport(transport)
+		uc.AddProvider(client)
+	}
+	return uc
+}
+
+// newLineHandler LINE Messaging APIのWebhookのハンドラーを作成
+// チャネルシークレット・チャネルアクセストークンは環境変数（LINE_CHANNEL_SECRET / LINE_CHANNEL_ACCESS_TOKEN）から取得する
+func newLineHandler(cfg *config.LineConfig, receiptUseCase *householdUsecase.ReceiptUseCase, jobQueue sharedDomain.JobQueue, transport http.RoundTripper) (*householdHandler.LineHandler, error) {
+	ctx := context.Background()
+	secrets := sharedSecrets.NewEnvSecretProvider("")
+	channelSecret, err := secrets.Secret(ctx, "line_channel_secret")
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize LINE webhook: %w", err)
+	}
+	accessToken, err := secrets.Secret(ctx, "line_channel_access_token")
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize LINE webhook: %w", err)
+	}
+
+	messenger := sharedLine.NewClient(accessToken, sharedLine.Options{
+		APIBaseURL:     cfg.APIBaseURL,
+		DataAPIBaseURL: cfg.DataAPIBaseURL,
+		Timeout:        time.Duration(cfg.TimeoutSeconds) * time.Second,
+	})
+	messenger.SetTransport(transport)
+	lineUseCase := householdUsecase.NewLineUseCase(receiptUseCase, messenger, cfg.Tags)
+	lineUseCase.SetJobQueue(jobQueue)
+	return householdHandler.NewLineHandler(lineUseCase, channelSecret), nil
+}
+
+// newWarehouseExportUseCase 分析用のファイルの書き出しのユースケースを作成（s3.bucketを設定した場合はS3互換のストレージ、それ以外はディレクトリに書き出す）
+// S3互換のストレージのアクセスキーは環境変数（WAREHOUSE_S3_ACCESS_KEY_ID / WAREHOUSE_S3_SECRET_ACCESS_KEY）から取得する
+func newWarehouseExportUseCase(cfg *config.WarehouseConfig, receiptRepo householdRepository.ReceiptRepository, expenseRepo *sharedDB.BunExpenseRepository, transport http.RoundTripper) (*householdUsecase.WarehouseExportUseCase, error) {
+	var store sharedDomain.ObjectStore
+	if cfg.S3.Bucket != "" {
+		ctx := context.Background()
+		secrets := sharedSecrets.NewEnvSecretProvider("")
+		accessKeyID, err := secrets.Secret(ctx, "warehouse_s3_access_key_id")
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize warehouse export: %w", err)
+		}
+		secretAccessKey, err := secrets.Secret(ctx, "warehouse_s3_secret_access_key")
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize warehouse export: %w", err)
+		}
+		s3Store, err := sharedStorage.NewS3ObjectStore(sharedStorage.S3Config{
+			Endpoint:        cfg.S3.Endpoint,
+			Region:          cfg.S3.Region,
+			Bucket:          cfg.S3.Bucket,
+			AccessKeyID:     accessKeyID,
+			SecretAccessKey: secretAccessKey,
+			Prefix:          cfg.S3.Prefix,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize warehouse export: %w", err)
+		}
+		s3Store.SetTransport(transport)
+		store = s3Store
+	} else {
+		localStore, err := sharedStorage.NewLocalObjectStore(cfg.Dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize warehouse export: %w", err)
+		}
+		store = localStore
+	}
+
+	uc := householdUsecase.NewWarehouseExportUseCase(receiptRepo, expenseRepo, store, sharedParquet.Format{})
+	uc.SetLookbackMonths(cfg.LookbackMonths)
+	return uc, nil
+}
+
+// newSpendSharingUseCase 公開統計への食費の合計の提供を作成（識別子・地域の誤りは起動時に検出する）
+func newSpendSharingUseCase(cfg *config.SpendSharingConfig, aggregateRepo *sharedDB.BunAggregateRepository, currency sharedDomain.Currency, transport http.RoundTripper) (*analyticsUsecase.SpendSharingUseCase, error) {
+	if cfg.Endpoint == "" {
+		return nil, fmt.Errorf("analytics.public_stats.share.endpoint is required")
+	}
+	probe := &analyticsDomain.SpendContribution{ContributorID: cfg.ContributorID, Region: cfg.Region, Month: "2000-01", Currency: currency.Code}
+	if err := probe.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid analytics.public_stats.share: %w", err)
+	}
+	apiKey, err := sharedSecrets.NewEnvSecretProvider("").Secret(context.Background(), "public_stats_api_key")
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize public stats sharing: %w", err)
+	}
+
+	sink := sharedPublicStats.NewHTTPSink(cfg.Endpoint, apiKey, time.Duration(cfg.TimeoutSeconds)*time.Second)
+	sink.SetTransport(transport)
+	return analyticsUsecase.NewSpendSharingUseCase(aggregateRepo, sink, analyticsUsecase.SpendSharingRules{
+		ContributorID: cfg.ContributorID,
+		Region:        probe.Region,
+		Categories:    cfg.Categories,
+		Currency:      currency.Code,
+	}), nil
+}
+
+// newCalendarUseCase 返品・保証期限のフィードのユースケースを作成（未設定の項目はデフォルト値を使用）
+func newCalendarUseCase(cfg *config.WarrantyCalendarConfig, warrantyUseCase *householdUsecase.WarrantyUseCase) (*householdUsecase.CalendarUseCase, error) {
+	secret := []byte(cfg.Secret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate calendar secret: %w", err)
+		}
+	}
+
+	days := cfg.Days
+	if days <= 0 {
+		days = 365
+	}
+	return householdUsecase.NewCalendarUseCase(warrantyUseCase, secret, days), nil
+}
+
+// newUploadUseCase 直接アップロードのユースケースを作成（未設定の項目はデフォルト値を使用）
+// s3.bucketを設定した場合は画像本体をS3互換のストレージへ直接アップロードさせ、アクセスキーは環境変数（UPLOADS_S3_ACCESS_KEY_ID / UPLOADS_S3_SECRET_ACCESS_KEY）から取得する
+func newUploadUseCase(cfg *config.UploadsConfig, receiptUseCase *householdUsecase.ReceiptUseCase, imageStorage sharedDomain.ImageStorage, clock sharedDomain.Clock, transport http.RoundTripper) (*householdUsecase.UploadUseCase, error) {
+	secret := []byte(cfg.Secret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate upload secret: %w", err)
+		}
+	}
+
+	ttl := time.Duration(cfg.ExpiryMinutes) * time.Minute
+	if ttl <= 0 {
+		ttl = 15 * time.Minute
+	}
+
+	maxSize := int64(cfg.MaxSizeMB) << 20
+	if maxSize <= 0 {
+		maxSize = 20 << 20
+	}
+
+	uploadUseCase := householdUsecase.NewUploadUseCase(receiptUseCase, imageStorage, secret, ttl, maxSize)
+	uploadUseCase.SetClock(clock)
+
+	if cfg.S3.Bucket != "" {
+		ctx := context.Background()
+		secrets := sharedSecrets.NewEnvSecretProvider("")
+		accessKeyID, err := secrets.Secret(ctx, "uploads_s3_access_key_id")
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize direct uploads: %w", err)
+		}
+		secretAccessKey, err := secrets.Secret(ctx, "uploads_s3_secret_access_key")
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize direct uploads: %w", err)
+		}
+		s3Store, err := sharedStorage.NewS3ObjectStore(sharedStorage.S3Config{
+			Endpoint:        cfg.S3.Endpoint,
+			Region:          cfg.S3.Region,
+			Bucket:          cfg.S3.Bucket,
+			AccessKeyID:     accessKeyID,
+			SecretAccessKey: secretAccessKey,
+			Prefix:          cfg.S3.Prefix,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize direct uploads: %w", err)
+		}
+		s3Store.SetTransport(transport)
+		uploadUseCase.SetObjectStore(s3Store)
+	}
+	return uploadUseCase, nil
 }
 
 // AICorrectionUseCase Vision AI補正ユースケースを取得
@@ -96,11 +1171,247 @@ func (c *Container) VisionHandler() *visionHandler.VisionHandler {
 	return c.visionHandler
 }
 
-// WebHandler Web UIハンドラーを取得
-func (c *Container) WebHandler() *householdHandler.WebHandler {
+// WebHandler サーバーレンダリングのWeb画面ハンドラーを取得
+func (c *Container) WebHandler() *web.Handler {
 	return c.webHandler
 }
 
+// SPAHandler 埋め込みSPAハンドラーを取得（classic UIの場合はnil）
+func (c *Container) SPAHandler() *spa.Handler {
+	return c.spaHandler
+}
+
+// ExpenseHandler 家計簿エントリAPIハンドラーを取得
+func (c *Container) ExpenseHandler() *householdHandler.ExpenseHandler {
+	return c.expenseHandler
+}
+
+// ReportHandler レポートAPIハンドラーを取得
+func (c *Container) ReportHandler() *householdHandler.ReportHandler {
+	return c.reportHandler
+}
+
+// WidgetHandler ダッシュボードのウィジェット向けの集計APIハンドラーを取得
+func (c *Container) WidgetHandler() *householdHandler.WidgetHandler {
+	return c.widgetHandler
+}
+
+// ReceiptHandler レシートAPIハンドラーを取得
+func (c *Container) ReceiptHandler() *householdHandler.ReceiptHandler {
+	return c.receiptHandler
+}
+
+// CategoryHandler カテゴリーAPIハンドラーを取得
+func (c *Container) CategoryHandler() *householdHandler.CategoryHandler {
+	return c.categoryHandler
+}
+
+// ItemHandler 商品APIハンドラーを取得
+func (c *Container) ItemHandler() *householdHandler.ItemHandler {
+	return c.itemHandler
+}
+
+// WarrantyHandler 返品・保証期限APIハンドラーを取得
+func (c *Container) WarrantyHandler() *householdHandler.WarrantyHandler {
+	return c.warrantyHandler
+}
+
+// CalendarHandler 返品・保証期限のiCalendarのフィードのハンドラーを取得
+func (c *Container) CalendarHandler() *householdHandler.CalendarHandler {
+	return c.calendarHandler
+}
+
+// MergeHandler レシートの統合APIハンドラーを取得
+func (c *Container) MergeHandler() *householdHandler.MergeHandler {
+	return c.mergeHandler
+}
+
+// TrashHandler ゴミ箱APIハンドラーを取得
+func (c *Container) TrashHandler() *householdHandler.TrashHandler {
+	return c.trashHandler
+}
+
+// InterchangeHandler 移行用のレシートの書き出し・読み込みAPIハンドラーを取得
+func (c *Container) InterchangeHandler() *householdHandler.InterchangeHandler {
+	return c.interchangeHandler
+}
+
+// BackfillHandler 元画像の読み直しAPIハンドラーを取得（バッチ処理に対応していないAIの場合はnil）
+func (c *Container) BackfillHandler() *householdHandler.BackfillHandler {
+	return c.backfillHandler
+}
+
+// SplitHandler 割り勘APIハンドラーを取得
+func (c *Container) SplitHandler() *householdHandler.SplitHandler {
+	return c.splitHandler
+}
+
+// LineHandler LINE Messaging APIのWebhookのハンドラーを取得（無効な場合はnil）
+func (c *Container) LineHandler() *householdHandler.LineHandler {
+	return c.lineHandler
+}
+
+// AccountingHandler 会計サービスとの同期APIハンドラーを取得
+func (c *Container) AccountingHandler() *householdHandler.AccountingHandler {
+	return c.accountingHandler
+}
+
+// ReconciliationHandler 利用明細とレシートの突き合わせAPIハンドラーを取得
+func (c *Container) ReconciliationHandler() *householdHandler.ReconciliationHandler {
+	return c.reconcileHandler
+}
+
+// DocumentHandler ファイルの自動振り分けAPIハンドラーを取得
+func (c *Container) DocumentHandler() *householdHandler.DocumentHandler {
+	return c.documentHandler
+}
+
+// CollectionHandler レシートのコレクションAPIハンドラーを取得
+func (c *Container) CollectionHandler() *householdHandler.CollectionHandler {
+	return c.collectionHandler
+}
+
+// UploadHandler 直接アップロードAPIハンドラーを取得
+func (c *Container) UploadHandler() *householdHandler.UploadHandler {
+	return c.uploadHandler
+}
+
+// DraftHandler 2段階のレシート登録APIハンドラーを取得
+func (c *Container) DraftHandler() *householdHandler.DraftHandler {
+	return c.draftHandler
+}
+
+// SuggestionHandler 購入パターンに基づく提案APIハンドラーを取得
+func (c *Container) SuggestionHandler() *analyticsHandler.SuggestionHandler {
+	return c.suggestionHandler
+}
+
+// PublicStatsHandler 地域別の平均の食費の公開統計APIハンドラーを取得（無効な場合はnil）
+func (c *Container) PublicStatsHandler() *analyticsHandler.PublicStatsHandler {
+	return c.publicStatsHandler
+}
+
+// ExpenseReportHandler 経費報告書APIハンドラーを取得
+func (c *Container) ExpenseReportHandler() *expenseReportHandler.ExpenseReportHandler {
+	return c.expenseReportHandler
+}
+
+// RealIP リクエストの送信元のIPアドレスを解決するミドルウェアを取得
+func (c *Container) RealIP() *middleware.RealIP {
+	return c.realIP
+}
+
+// LoadShedder 同時に処理するリクエスト数を制限するミドルウェアを取得（無効な場合はnil）
+func (c *Container) LoadShedder() *middleware.LoadShedder {
+	return c.loadShedder
+}
+
+// Maintenance メンテナンスモードを取得
+func (c *Container) Maintenance() *middleware.Maintenance {
+	return c.maintenance
+}
+
+// FeatureFlags 機能フラグを取得
+func (c *Container) FeatureFlags() *middleware.FeatureFlags {
+	return c.featureFlags
+}
+
+// Location デフォルトのタイムゾーンを取得
+func (c *Container) Location() *time.Location {
+	return c.location
+}
+
+// Currency 金額の通貨を取得
+func (c *Container) Currency() sharedDomain.Currency {
+	return c.currency
+}
+
+// ResponseTransform JSONレスポンスのフィールド名の変換・多言語化を取得
+func (c *Container) ResponseTransform() *middleware.ResponseTransform {
+	return c.responseTransform
+}
+
+// SLO レシート処理のSLOの記録・判定を取得（無効な場合はnil）
+func (c *Container) SLO() *middleware.SLOTracker {
+	return c.slo
+}
+
+// ResponseCache レポートのレスポンスのキャッシュを取得（無効な場合はnil）
+func (c *Container) ResponseCache() *middleware.ResponseCache {
+	return c.responseCache
+}
+
+// AdminHandler 管理APIハンドラーを取得
+func (c *Container) AdminHandler() *admin.Handler {
+	return c.adminHandler
+}
+
+// Diagnostics pprof・expvarによるランタイム診断の設定を取得
+func (c *Container) Diagnostics() config.DiagnosticsConfig {
+	return c.diagnostics
+}
+
+// CacheStats キャッシュの利用状況の集計の設定を取得
+func (c *Container) CacheStats() config.CacheStatsConfig {
+	return c.cacheStats
+}
+
+// ServerTLS APIサーバーのHTTPS・クライアント証明書による認証の設定を取得
+func (c *Container) ServerTLS() config.ServerTLSConfig {
+	return c.serverTLS
+}
+
+// AdminToken 管理APIのトークンを取得（空の場合は管理APIを無効化）
+func (c *Container) AdminToken() string {
+	return c.adminToken
+}
+
+// APIKeys 自動化ツール向けAPIのAPIキーを取得（空の場合はAPIキー認証のAPIを無効化）
+func (c *Container) APIKeys() []string {
+	return c.apiKeys
+}
+
+// HealthHandler ヘルスチェック・レディネスチェックのハンドラーを取得
+func (c *Container) HealthHandler() *health.Handler {
+	return c.healthHandler
+}
+
+// PersistenceEnabled レシートの保存が有効か（MySQL未設定の場合はAIのみのエンドポイントで動作）
+func (c *Container) PersistenceEnabled() bool {
+	return c.receiptUseCase != nil
+}
+
+// ReceiptUseCase レシートのユースケースを取得（MySQL未設定の場合はnil）
+func (c *Container) ReceiptUseCase() *householdUsecase.ReceiptUseCase {
+	return c.receiptUseCase
+}
+
+// JobQueue ジョブキューを取得
+func (c *Container) JobQueue() sharedDomain.JobQueue {
+	return c.jobQueue
+}
+
+// Drain 定期実行を停止し、バックグラウンドジョブの完了を待ってキューを停止
+func (c *Container) Drain(ctx context.Context) error {
+	// 機能フラグの再取得を停止
+	if c.stopFeatureFlags != nil {
+		c.stopFeatureFlags()
+	}
+
+	// 定期実行タスクがジョブを投入しなくなってからキューを停止する
+	if c.scheduler != nil {
+		if err := c.scheduler.Stop(ctx); err != nil {
+			return err
+		}
+	}
+	if c.jobQueue != nil {
+		if err := c.jobQueue.Close(ctx); err != nil {
+			return fmt.Errorf("failed to drain job queue: %w", err)
+		}
+	}
+	return nil
+}
+
 // Close リソースをクローズ
 func (c *Container) Close() error {
 	if c.cacheRepo != nil {
@@ -109,17 +1420,88 @@ func (c *Container) Close() error {
 		}
 	}
 
+	if c.locker != nil {
+		if err := c.locker.Close(); err != nil {
+			return fmt.Errorf("failed to close distributed lock: %w", err)
+		}
+	}
+
 	if c.receiptRepo != nil {
 		if err := c.receiptRepo.Close(); err != nil {
 			return fmt.Errorf("failed to close receipt repository: %w", err)
 		}
 	}
 
+	if c.revisionRepo != nil {
+		if err := c.revisionRepo.Close(); err != nil {
+			return fmt.Errorf("failed to close receipt revision repository: %w", err)
+		}
+	}
+
 	if c.expenseRepo != nil {
 		if err := c.expenseRepo.Close(); err != nil {
 			return fmt.Errorf("failed to close expense repository: %w", err)
 		}
 	}
 
+	if c.categoryRepo != nil {
+		if err := c.categoryRepo.Close(); err != nil {
+			return fmt.Errorf("failed to close category repository: %w", err)
+		}
+	}
+	if c.trashRepo != nil {
+		if err := c.trashRepo.Close(); err != nil {
+			return fmt.Errorf("failed to close trash repository: %w", err)
+		}
+	}
+	if c.itemAliasRepo != nil {
+		if err := c.itemAliasRepo.Close(); err != nil {
+			return fmt.Errorf("failed to close item alias repository: %w", err)
+		}
+	}
+	if c.reportRepo != nil {
+		if err := c.reportRepo.Close(); err != nil {
+			return fmt.Errorf("failed to close expense report repository: %w", err)
+		}
+	}
+	if c.collectRepo != nil {
+		if err := c.collectRepo.Close(); err != nil {
+			return fmt.Errorf("failed to close collection repository: %w", err)
+		}
+	}
+	if c.spendRepo != nil {
+		if err := c.spendRepo.Close(); err != nil {
+			return fmt.Errorf("failed to close spend contribution repository: %w", err)
+		}
+	}
+	if c.mergeRepo != nil {
+		if err := c.mergeRepo.Close(); err != nil {
+			return fmt.Errorf("failed to close receipt merge repository: %w", err)
+		}
+	}
+	if c.splitRepo != nil {
+		if err := c.splitRepo.Close(); err != nil {
+			return fmt.Errorf("failed to close split repository: %w", err)
+		}
+	}
+
+	if c.aggregateRepo != nil {
+		if err := c.aggregateRepo.Close(); err != nil {
+			return fmt.Errorf("failed to close aggregate repository: %w", err)
+		}
+	}
+
+	if c.syncRepo != nil {
+		if err := c.syncRepo.Close(); err != nil {
+			return fmt.Errorf("failed to close accounting sync repository: %w", err)
+		}
+	}
+
+	if c.aiExchangeLog != nil {
+		if err := c.aiExchangeLog.Close(); err != nil {
+			return fmt.Errorf("failed to close AI debug log: %w", err)
+		}
+	}
+
 	return nil
 }
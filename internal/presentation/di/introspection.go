@@ -0,0 +1,115 @@
+package di
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+
+	"vision-api-app/internal/config"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+	visionDomain "vision-api-app/internal/modules/vision/domain"
+	"vision-api-app/internal/presentation/http/admin"
+)
+
+// redactedSecret 設定されている秘密情報の代わりに返す値
+const redactedSecret = "<redacted>"
+
+// recordComponent 組み立てた実装を記録する（管理APIの /api/v1/admin/container で返す）
+// settingsは秘密情報を伏せた設定で、値そのものは返さずハッシュだけを返す
+func (c *Container) recordComponent(name, implementation string, settings any, details map[string]string) {
+	c.components = append(c.components, admin.ContainerComponent{
+		Name:           name,
+		Implementation: implementation,
+		Details:        details,
+		Fingerprint:    configFingerprint(settings),
+		InitializedAt:  c.clock.Now(),
+	})
+}
+
+// recordAIRepository 設定のプロバイダーのAIリポジトリを記録する
+func (c *Container) recordAIRepository(cfg *config.Config, repo visionDomain.AIRepository) {
+	if cfg.AI.Provider == "gemini" {
+		settings := cfg.Gemini
+		settings.APIKey = redactSecret(settings.APIKey)
+		c.recordComponent("ai", repo.ProviderName(), settings, map[string]string{
+			"provider": cfg.AI.Provider,
+			"model":    settings.Model,
+			"api_key":  settings.APIKey,
+		})
+		return
+	}
+	settings := cfg.Anthropic
+	settings.APIKey = redactSecret(settings.APIKey)
+	c.recordComponent("ai", repo.ProviderName(), settings, map[string]string{
+		"provider": "anthropic",
+		"model":    settings.Model,
+		"api_key":  settings.APIKey,
+	})
+}
+
+// recordAIBudget AI APIの予算と予算超過時の縮退を記録する
+func (c *Container) recordAIBudget(cfg *config.AIBudgetConfig) {
+	c.recordComponent("ai_budget", cmp.Or(cfg.Downgrade, sharedDomain.AIDowngradeRules), *cfg, map[string]string{
+		"monthly_usd": strconv.FormatFloat(cfg.MonthlyUSD, 'f', -1, 64),
+		"cheap_model": cfg.CheapModel,
+		"ledger":      "redis",
+	})
+}
+
+// recordCache Redisのキャッシュを記録する
+func (c *Container) recordCache(cfg *config.RedisConfig) {
+	settings := *cfg
+	settings.Password = redactSecret(settings.Password)
+	c.recordComponent("cache", "redis", settings, map[string]string{
+		"address":  settings.Host + ":" + strconv.Itoa(settings.Port),
+		"db":       strconv.Itoa(settings.DB),
+		"password": settings.Password,
+	})
+}
+
+// recordDatabase MySQLのデータベースを記録する（未設定の場合はdisabled）
+func (c *Container) recordDatabase(cfg *config.MySQLConfig) {
+	if !cfg.Configured() {
+		c.recordComponent("database", "disabled", nil, nil)
+		return
+	}
+	settings := *cfg
+	settings.Password = redactSecret(settings.Password)
+	c.recordComponent("database", "mysql (bun)", settings, map[string]string{
+		"address":  settings.Host + ":" + strconv.Itoa(settings.Port),
+		"database": settings.Database,
+		"user":     settings.User,
+		"password": settings.Password,
+	})
+}
+
+// recordNotifier 通知の送信先を記録する（Webhook未設定の場合はlog）
+func (c *Container) recordNotifier(cfg *config.NotificationsConfig) {
+	implementation := "log"
+	if cfg.WebhookURL != "" {
+		implementation = "webhook"
+	}
+	settings := *cfg
+	settings.WebhookURL = redactSecret(settings.WebhookURL)
+	c.recordComponent("notifier", implementation, settings, nil)
+}
+
+// configFingerprint 設定のハッシュの先頭12文字（インスタンス間で設定が同じかの確認用）
+func configFingerprint(settings any) string {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// redactSecret 秘密情報を伏せる（空の場合は空のまま返し、設定されているかどうかだけ分かるようにする）
+func redactSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redactedSecret
+}
@@ -0,0 +1,80 @@
+package di
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/config"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+	visionDomain "vision-api-app/internal/modules/vision/domain"
+	"vision-api-app/internal/presentation/http/admin"
+)
+
+// fakeAIRepository プロバイダー名だけを返すテスト用のAIリポジトリ
+type fakeAIRepository struct {
+	visionDomain.AIRepository
+	provider string
+}
+
+func (f *fakeAIRepository) ProviderName() string {
+	return f.provider
+}
+
+func TestContainer_RecordComponents_RedactsSecrets(t *testing.T) {
+	secrets := []string{
+		"sk-ant-secret-key",
+		"gemini-secret-key",
+		"redis-secret-password",
+		"mysql-secret-password",
+		"https://hooks.example.com/secret-token",
+	}
+	cfg := config.DefaultConfig()
+	cfg.Anthropic.APIKey = secrets[0]
+	cfg.Gemini.APIKey = secrets[1]
+	cfg.Redis.Password = secrets[2]
+	cfg.MySQL = config.MySQLConfig{Host: "db", Port: 3306, User: "app", Password: secrets[3], Database: "household"}
+	cfg.Notifications.WebhookURL = secrets[4]
+
+	initializedAt := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	c := &Container{clock: sharedDomain.FixedClock(initializedAt)}
+	c.recordAIRepository(cfg, &fakeAIRepository{provider: "anthropic"})
+	geminiCfg := *cfg
+	geminiCfg.AI.Provider = "gemini"
+	c.recordAIRepository(&geminiCfg, &fakeAIRepository{provider: "gemini"})
+	c.recordCache(&cfg.Redis)
+	c.recordDatabase(&cfg.MySQL)
+	c.recordNotifier(&cfg.Notifications)
+
+	handler := admin.NewHandler(nil, nil, nil)
+	handler.SetContainerComponents(c.components)
+	rec := httptest.NewRecorder()
+	handler.HandleGetContainer(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/container", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	body := rec.Body.String()
+	for _, secret := range secrets {
+		if strings.Contains(body, secret) {
+			t.Errorf("response contains secret %q: %s", secret, body)
+		}
+	}
+
+	if len(c.components) != 5 {
+		t.Fatalf("components = %d, want 5", len(c.components))
+	}
+	// 設定されている秘密情報は値を伏せ、設定の有無だけ分かるようにする
+	for i, key := range map[int]string{0: "api_key", 1: "api_key", 2: "password", 3: "password"} {
+		if got := c.components[i].Details[key]; got != redactedSecret {
+			t.Errorf("%s %s = %q, want %q", c.components[i].Name, key, got, redactedSecret)
+		}
+	}
+	for _, component := range c.components {
+		if !component.InitializedAt.Equal(initializedAt) {
+			t.Errorf("%s InitializedAt = %v, want %v", component.Name, component.InitializedAt, initializedAt)
+		}
+	}
+}
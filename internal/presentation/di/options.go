@@ -0,0 +1,47 @@
+package di
+
+import (
+	householdUsecase "vision-api-app/internal/modules/household/usecase"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// Option DIコンテナの生成時に導入先ごとの処理を組み込むオプション
+type Option func(*options)
+
+// options DIコンテナのオプションの設定値
+type options struct {
+	imageHooks   []householdUsecase.ImageHook
+	receiptHooks []householdUsecase.ReceiptHook
+	clock        sharedDomain.Clock
+}
+
+// WithImageHooks AIに送る前のレシート画像を加工するフックを登録する（複数回指定した場合は登録順に呼び出す）
+func WithImageHooks(hooks ...householdUsecase.ImageHook) Option {
+	return func(o *options) {
+		o.imageHooks = append(o.imageHooks, hooks...)
+	}
+}
+
+// WithReceiptHooks 読み取ったレシートを保存前に補完するフックを登録する（複数回指定した場合は登録順に呼び出す）
+func WithReceiptHooks(hooks ...householdUsecase.ReceiptHook) Option {
+	return func(o *options) {
+		o.receiptHooks = append(o.receiptHooks, hooks...)
+	}
+}
+
+// WithClock 作成日時・更新日時・期限の判定に使う時計を設定する（未指定の場合はシステムの時計）
+// 検証環境で日時を固定する場合などに使う
+func WithClock(clock sharedDomain.Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+// newOptions オプションを適用した設定値を作成
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
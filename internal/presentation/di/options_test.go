@@ -0,0 +1,20 @@
+package di
+
+import (
+	"testing"
+	"time"
+
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+func TestNewOptions_WithClock(t *testing.T) {
+	if o := newOptions(nil); o.clock != nil {
+		t.Errorf("clock = %v, want nil without WithClock", o.clock)
+	}
+
+	clock := sharedDomain.FixedClock(time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC))
+	o := newOptions([]Option{WithClock(clock)})
+	if o.clock != clock {
+		t.Errorf("clock = %v, want %v", o.clock, clock)
+	}
+}
@@ -0,0 +1,481 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"vision-api-app/internal/modules/household/usecase"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+	"vision-api-app/internal/presentation/http/middleware"
+)
+
+// MaintenanceRequest メンテナンスモード切り替えのリクエスト
+type MaintenanceRequest struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message"`
+}
+
+// MaintenanceStatus メンテナンスモードの状態
+type MaintenanceStatus struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message"`
+}
+
+// AIBudgetRequest AI APIの予算超過時の縮退の手動切り替えのリクエスト
+type AIBudgetRequest struct {
+	Override string `json:"override"` // auto・normal・downgrade
+}
+
+// ContainerComponent DIコンテナで組み立てた実装
+type ContainerComponent struct {
+	Name           string            `json:"name"`              // 役割（ai・cache・database・storageなど）
+	Implementation string            `json:"implementation"`    // 使用している実装
+	Details        map[string]string `json:"details,omitempty"` // 接続先・モデルなど（秘密情報は設定の有無のみ）
+	Fingerprint    string            `json:"fingerprint"`       // 秘密情報を伏せた設定のハッシュ（インスタンス間の設定の違いの確認用）
+	InitializedAt  time.Time         `json:"initialized_at"`
+}
+
+// APIResponse 管理APIの共通レスポンス
+type APIResponse struct {
+	Success bool        `json:"success"`
+	Data    interface{} `json:"data,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// Handler 運用管理APIハンドラー
+// receiptUseCaseがnilの場合はレシートの保存が無効な構成として、レシートの修復は行わない
+type Handler struct {
+	maintenance    *middleware.Maintenance
+	featureFlags   *middleware.FeatureFlags
+	receiptUseCase *usecase.ReceiptUseCase
+	slo            *middleware.SLOTracker
+	aiExchangeLog  sharedDomain.AIExchangeLog
+	aiBudget       sharedDomain.AIBudget
+	components     []ContainerComponent
+	warehouse      *usecase.WarehouseExportUseCase
+	cache          sharedDomain.CacheInspector
+	cacheSample    int
+}
+
+// maxCacheSampleKeys 1回の取得でSCANして調べるキーの数の上限
+const maxCacheSampleKeys = 100000
+
+// CacheStatsResponse キャッシュの利用状況のレスポンス
+type CacheStatsResponse struct {
+	sharedDomain.CacheStats
+	Keyspace *sharedDomain.CacheKeyspace `json:"keyspace,omitempty"` // キーを調べられなかった場合（障害など）は省略
+}
+
+// NewHandler 新しいHandlerを作成
+func NewHandler(maintenance *middleware.Maintenance, featureFlags *middleware.FeatureFlags, receiptUseCase *usecase.ReceiptUseCase) *Handler {
+	return &Handler{
+		maintenance:    maintenance,
+		featureFlags:   featureFlags,
+		receiptUseCase: receiptUseCase,
+	}
+}
+
+// SetSLOTracker レシート処理のSLOの記録・判定を設定（未設定の場合はSLOの状態を返さない）
+func (h *Handler) SetSLOTracker(slo *middleware.SLOTracker) {
+	h.slo = slo
+}
+
+// HandleGetSLO 直近のウィンドウのレシート処理のSLOの状態を取得
+func (h *Handler) HandleGetSLO(w http.ResponseWriter, r *http.Request) {
+	if h.slo == nil {
+		h.writeJSON(w, http.StatusServiceUnavailable, APIResponse{
+			Success: false,
+			Error:   "SLO tracking is disabled",
+		})
+		return
+	}
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    h.slo.Status(),
+	})
+}
+
+// SetCacheInspector キャッシュの利用状況の集計とキーの調査を設定（未設定の場合はキャッシュの利用状況を返さない）
+// sampleKeysはSCANして調べるキーの数の既定値
+func (h *Handler) SetCacheInspector(cache sharedDomain.CacheInspector, sampleKeys int) {
+	h.cache = cache
+	h.cacheSample = sampleKeys
+}
+
+// HandleGetCacheStats エンドポイント・テナントごとのキャッシュのヒット率と、接頭辞ごとのキーの件数・使用メモリを取得
+// ?sample= でSCANして調べるキーの数を指定する（全体がそれより多い場合は標本から推定する）
+func (h *Handler) HandleGetCacheStats(w http.ResponseWriter, r *http.Request) {
+	if h.cache == nil {
+		h.writeJSON(w, http.StatusServiceUnavailable, APIResponse{
+			Success: false,
+			Error:   "Cache stats are disabled",
+		})
+		return
+	}
+
+	sample := h.cacheSample
+	if v := r.URL.Query().Get("sample"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > maxCacheSampleKeys {
+			h.writeJSON(w, http.StatusBadRequest, APIResponse{
+				Success: false,
+				Error:   "Invalid sample: must be 1-" + strconv.Itoa(maxCacheSampleKeys),
+			})
+			return
+		}
+		sample = parsed
+	}
+
+	response := CacheStatsResponse{CacheStats: h.cache.CacheStats()}
+	keyspace, err := h.cache.SampleKeyspace(r.Context(), sample)
+	if err != nil {
+		slog.Warn("Failed to sample cache keyspace", "error", err)
+	} else {
+		response.Keyspace = keyspace
+	}
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// SetAIExchangeLog AI APIへのリクエストとレスポンスの記録先を設定（未設定の場合はデバッグ用の記録を返さない）
+func (h *Handler) SetAIExchangeLog(exchangeLog sharedDomain.AIExchangeLog) {
+	h.aiExchangeLog = exchangeLog
+}
+
+// HandleGetAIDebug 記録したAI APIへのリクエストとレスポンスを新しい順に取得
+// ?limit= で件数を指定する（省略した場合はすべて）
+func (h *Handler) HandleGetAIDebug(w http.ResponseWriter, r *http.Request) {
+	if h.aiExchangeLog == nil {
+		h.writeAIDebugDisabled(w)
+		return
+	}
+
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			h.writeJSON(w, http.StatusBadRequest, APIResponse{
+				Success: false,
+				Error:   "Invalid limit",
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    h.aiExchangeLog.Recent(limit),
+	})
+}
+
+// HandleClearAIDebug 記録したAI APIへのリクエストとレスポンスを削除
+func (h *Handler) HandleClearAIDebug(w http.ResponseWriter, r *http.Request) {
+	if h.aiExchangeLog == nil {
+		h.writeAIDebugDisabled(w)
+		return
+	}
+	h.aiExchangeLog.Clear()
+	slog.Warn("AI debug log cleared via admin API")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeAIDebugDisabled デバッグ用の記録が無効な場合のレスポンスを書き込み
+func (h *Handler) writeAIDebugDisabled(w http.ResponseWriter) {
+	h.writeJSON(w, http.StatusServiceUnavailable, APIResponse{
+		Success: false,
+		Error:   "AI debug logging is disabled",
+	})
+}
+
+// SetAIBudget AI APIの利用料金の記録と予算超過時の縮退を設定（未設定の場合は予算の状態を返さない）
+func (h *Handler) SetAIBudget(aiBudget sharedDomain.AIBudget) {
+	h.aiBudget = aiBudget
+}
+
+// HandleGetAIBudget 当月のAI APIの利用料金と縮退の状態を取得
+func (h *Handler) HandleGetAIBudget(w http.ResponseWriter, r *http.Request) {
+	if h.aiBudget == nil {
+		h.writeAIBudgetDisabled(w)
+		return
+	}
+	h.writeAIBudgetStatus(w, r)
+}
+
+// HandleUpdateAIBudget 予算超過時の縮退を手動で切り替える（auto: 予算で判断、normal: 縮退しない、downgrade: 常に縮退）
+func (h *Handler) HandleUpdateAIBudget(w http.ResponseWriter, r *http.Request) {
+	if h.aiBudget == nil {
+		h.writeAIBudgetDisabled(w)
+		return
+	}
+
+	var req AIBudgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeJSON(w, http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Invalid request body",
+		})
+		return
+	}
+	if err := h.aiBudget.SetOverride(r.Context(), req.Override); err != nil {
+		if errors.Is(err, sharedDomain.ErrInvalidAIBudgetOverride) {
+			h.writeJSON(w, http.StatusBadRequest, APIResponse{
+				Success: false,
+				Error:   "Invalid override: must be auto, normal or downgrade",
+			})
+			return
+		}
+		slog.Error("Failed to update AI budget override", "error", err)
+		h.writeJSON(w, http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Error:   "Failed to update AI budget override",
+		})
+		return
+	}
+
+	slog.Warn("AI budget override changed via admin API", "override", req.Override)
+	h.writeAIBudgetStatus(w, r)
+}
+
+// writeAIBudgetStatus 当月のAI APIの利用料金と縮退の状態を書き込み
+func (h *Handler) writeAIBudgetStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.aiBudget.Status(r.Context())
+	if err != nil {
+		slog.Error("Failed to get AI budget status", "error", err)
+		h.writeJSON(w, http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Error:   "Failed to get AI budget status",
+		})
+		return
+	}
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    status,
+	})
+}
+
+// writeAIBudgetDisabled AI APIの予算が無効な場合のレスポンスを書き込み
+func (h *Handler) writeAIBudgetDisabled(w http.ResponseWriter) {
+	h.writeJSON(w, http.StatusServiceUnavailable, APIResponse{
+		Success: false,
+		Error:   "AI budget is disabled",
+	})
+}
+
+// SetContainerComponents DIコンテナで組み立てた実装を設定
+func (h *Handler) SetContainerComponents(components []ContainerComponent) {
+	h.components = components
+}
+
+// HandleGetContainer DIコンテナで組み立てた実装（AIのプロバイダー・キャッシュ・ストレージ・データベースなど）を初期化の順に取得
+func (h *Handler) HandleGetContainer(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    h.components,
+	})
+}
+
+// HandleGetFeatures 現在の機能フラグの一覧を取得（リモートの値を反映済み）
+func (h *Handler) HandleGetFeatures(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    h.featureFlags.Snapshot(),
+	})
+}
+
+// HandleGetMaintenance メンテナンスモードの状態を取得
+func (h *Handler) HandleGetMaintenance(w http.ResponseWriter, r *http.Request) {
+	h.writeMaintenanceStatus(w)
+}
+
+// HandleUpdateMaintenance メンテナンスモードを切り替える
+func (h *Handler) HandleUpdateMaintenance(w http.ResponseWriter, r *http.Request) {
+	var req MaintenanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeJSON(w, http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Invalid request body",
+		})
+		return
+	}
+
+	h.maintenance.Set(req.Enabled, req.Message)
+	slog.Warn("Maintenance mode changed", "enabled", req.Enabled)
+	h.writeMaintenanceStatus(w)
+}
+
+// writeMaintenanceStatus 現在のメンテナンスモードの状態を書き込み
+func (h *Handler) writeMaintenanceStatus(w http.ResponseWriter) {
+	enabled, message := h.maintenance.Status()
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    MaintenanceStatus{Enabled: enabled, Message: message},
+	})
+}
+
+// HandleRepairTotals 保存済みのレシートの合計金額を明細から検証・修復し、結果を返す
+// ?dry_run=true の場合は件数の集計のみで保存しない
+func (h *Handler) HandleRepairTotals(w http.ResponseWriter, r *http.Request) {
+	if h.receiptUseCase == nil {
+		h.writeJSON(w, http.StatusServiceUnavailable, APIResponse{
+			Success: false,
+			Error:   "Receipt persistence is disabled",
+		})
+		return
+	}
+
+	dryRun, ok := h.parseDryRun(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.receiptUseCase.RepairTotals(r.Context(), dryRun)
+	if err != nil {
+		slog.Error("Failed to repair receipt totals", "error", err)
+		h.writeJSON(w, http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Error:   "Failed to repair receipt totals",
+		})
+		return
+	}
+
+	slog.Warn("Receipt totals repaired via admin API", "dry_run", dryRun, "flagged", report.Flagged, "rederived", report.Rederived)
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    report,
+	})
+}
+
+// HandleNormalizeNames 保存済みのレシートの店名・商品名を names の設定で正規化し、結果を返す
+// ?dry_run=true の場合は件数の集計のみで保存しない
+func (h *Handler) HandleNormalizeNames(w http.ResponseWriter, r *http.Request) {
+	if h.receiptUseCase == nil {
+		h.writeJSON(w, http.StatusServiceUnavailable, APIResponse{
+			Success: false,
+			Error:   "Receipt persistence is disabled",
+		})
+		return
+	}
+
+	dryRun, ok := h.parseDryRun(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.receiptUseCase.NormalizeNames(r.Context(), dryRun)
+	if err != nil {
+		slog.Error("Failed to normalize receipt names", "error", err)
+		h.writeJSON(w, http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Error:   "Failed to normalize receipt names",
+		})
+		return
+	}
+
+	slog.Warn("Receipt names normalized via admin API", "dry_run", dryRun, "normalized", report.Normalized)
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    report,
+	})
+}
+
+// SetWarehouseExportUseCase 分析用のファイルの書き出しを設定（未設定の場合は書き出さない）
+func (h *Handler) SetWarehouseExportUseCase(warehouse *usecase.WarehouseExportUseCase) {
+	h.warehouse = warehouse
+}
+
+// HandleWarehouseExport 指定月のレシート・明細・家計簿エントリを分析用のファイルに書き出し、結果を返す
+// ?year= と ?month= で月を指定する（省略した場合は当月）
+func (h *Handler) HandleWarehouseExport(w http.ResponseWriter, r *http.Request) {
+	if h.warehouse == nil {
+		h.writeJSON(w, http.StatusServiceUnavailable, APIResponse{
+			Success: false,
+			Error:   "Warehouse export is disabled",
+		})
+		return
+	}
+
+	now := time.Now().In(sharedDomain.LocationFromContext(r.Context()))
+	year, month := now.Year(), int(now.Month())
+	query := r.URL.Query()
+	for _, param := range []struct {
+		name   string
+		target *int
+	}{{"year", &year}, {"month", &month}} {
+		name, target := param.name, param.target
+		v := query.Get(name)
+		if v == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(v)
+		if err != nil {
+			h.writeJSON(w, http.StatusBadRequest, APIResponse{
+				Success: false,
+				Error:   "Invalid " + name,
+			})
+			return
+		}
+		*target = parsed
+	}
+
+	report, err := h.warehouse.ExportMonth(r.Context(), year, month)
+	if errors.Is(err, usecase.ErrInvalidReportPeriod) {
+		h.writeJSON(w, http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Invalid year or month",
+		})
+		return
+	}
+	if errors.Is(err, usecase.ErrIncompatibleSchema) {
+		slog.Error("Warehouse schema is incompatible", "error", err)
+		h.writeJSON(w, http.StatusConflict, APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to export warehouse files", "error", err)
+		h.writeJSON(w, http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Error:   "Failed to export warehouse files",
+		})
+		return
+	}
+
+	slog.Info("Warehouse files exported via admin API", "year", year, "month", month)
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    report,
+	})
+}
+
+// parseDryRun ?dry_run を解析する（不正な場合は400を書き込んでfalseを返す）
+func (h *Handler) parseDryRun(w http.ResponseWriter, r *http.Request) (bool, bool) {
+	v := r.URL.Query().Get("dry_run")
+	if v == "" {
+		return false, true
+	}
+	dryRun, err := strconv.ParseBool(v)
+	if err != nil {
+		h.writeJSON(w, http.StatusBadRequest, APIResponse{
+			Success: false,
+			Error:   "Invalid dry_run",
+		})
+		return false, false
+	}
+	return dryRun, true
+}
+
+// writeJSON JSONレスポンスを書き込み
+func (h *Handler) writeJSON(w http.ResponseWriter, status int, response APIResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
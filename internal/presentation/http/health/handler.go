@@ -0,0 +1,121 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"vision-api-app/internal/modules/household/usecase"
+)
+
+const (
+	// Version ヘルスチェックで返すアプリケーションのバージョン
+	Version = "3.0.0"
+
+	// readyTimeout 依存先の確認にかける時間の上限
+	readyTimeout = 3 * time.Second
+)
+
+// 依存先ごとの状態
+const (
+	checkOK          = "ok"
+	checkUnavailable = "unavailable"
+	checkDisabled    = "disabled" // レシートの保存が無効な構成（MySQL未設定）
+)
+
+// ReadyResponse レディネスチェックのレスポンス
+type ReadyResponse struct {
+	Status       string            `json:"status"` // ready / degraded / unavailable
+	Checks       map[string]string `json:"checks"`
+	PendingSaves int               `json:"pending_saves"` // データベースへの保存を待っているレシート数
+}
+
+// CacheStatus キャッシュの稼働状態
+type CacheStatus interface {
+	// Available 障害を検知してキャッシュの利用を停止している間はfalse
+	Available() bool
+}
+
+// Handler ヘルスチェック・レディネスチェックのハンドラー
+// receiptUseCaseがnilの場合はレシートの保存が無効な構成としてデータベースを確認しない
+type Handler struct {
+	receiptUseCase *usecase.ReceiptUseCase
+	cache          CacheStatus
+}
+
+// NewHandler 新しいHandlerを作成
+func NewHandler(receiptUseCase *usecase.ReceiptUseCase, cache CacheStatus) *Handler {
+	return &Handler{
+		receiptUseCase: receiptUseCase,
+		cache:          cache,
+	}
+}
+
+// HandleHealth プロセスが応答できるかを返す（依存先は確認しない）
+func (h *Handler) HandleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(`{"status":"ok","version":"` + Version + `"}`))
+}
+
+// HandleReady リクエストを受け付けられるかを依存先の状態とあわせて返す
+// データベース障害中でもレシートを一時保管できる場合や、キャッシュを使わずに処理している場合は
+// 縮退運転（degraded）として200を返し、一時保管もできない場合は503を返す
+func (h *Handler) HandleReady(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+	defer cancel()
+
+	resp := ReadyResponse{
+		Status: "ready",
+		Checks: map[string]string{"database": checkOK},
+	}
+	statusCode := http.StatusOK
+
+	status := usecase.SaveStatus{DatabaseAvailable: true}
+	var err error
+	if h.receiptUseCase == nil {
+		resp.Checks["database"] = checkDisabled
+	} else {
+		status, err = h.receiptUseCase.SaveStatus(ctx)
+	}
+	resp.PendingSaves = status.PendingSaves
+	if status.SpoolEnabled {
+		resp.Checks["spool"] = checkOK
+		if err != nil {
+			slog.Error("Failed to check receipt spool", "error", err)
+			resp.Checks["spool"] = checkUnavailable
+		}
+	}
+
+	cacheAvailable := true
+	if h.cache != nil {
+		cacheAvailable = h.cache.Available()
+		resp.Checks["cache"] = checkOK
+		if !cacheAvailable {
+			resp.Checks["cache"] = checkUnavailable
+		}
+	}
+
+	switch {
+	case !status.DatabaseAvailable && status.SpoolEnabled && err == nil:
+		resp.Status = "degraded"
+		resp.Checks["database"] = checkUnavailable
+	case !status.DatabaseAvailable:
+		resp.Status = "unavailable"
+		resp.Checks["database"] = checkUnavailable
+		statusCode = http.StatusServiceUnavailable
+	case resp.PendingSaves > 0 || err != nil || !cacheAvailable:
+		// 復旧直後で書き戻しが終わっていない、一時保管先を確認できない、またはキャッシュを使わずに処理している
+		resp.Status = "degraded"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(resp)
+}
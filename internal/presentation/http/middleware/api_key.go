@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// APIKeyAuth 自動化ツール（iOSショートカット・curlなど）向けAPIのAPIキー認証ミドルウェア
+// キーは X-API-Key ヘッダーまたは Authorization: Bearer で指定する
+// 有効なキーが1つもない場合はAPIを無効化して404を返す
+func APIKeyAuth(keys []string, next http.Handler) http.Handler {
+	valid := make([][]byte, 0, len(keys))
+	for _, key := range keys {
+		if key = strings.TrimSpace(key); key != "" {
+			valid = append(valid, []byte(key))
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(valid) == 0 {
+			http.NotFound(w, r)
+			return
+		}
+
+		given := r.Header.Get("X-API-Key")
+		if given == "" {
+			given, _ = strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		}
+		if given == "" || !matchAPIKey(valid, []byte(given)) {
+			slog.WarnContext(r.Context(), "Unauthorized API key request", "path", r.URL.Path, "client_ip", sharedDomain.ClientIPFromContext(r.Context()))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(ErrorResponse{
+				Success: false,
+				Error:   "Unauthorized",
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// matchAPIKey いずれかのキーと一致するか（すべてのキーと比較して時間差を出さない）
+func matchAPIKey(keys [][]byte, given []byte) bool {
+	matched := 0
+	for _, key := range keys {
+		matched |= subtle.ConstantTimeCompare(given, key)
+	}
+	return matched == 1
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// maxTenantLength テナントとして集計するヘッダーの値の最大長（超えた分は切り捨てる）
+const maxTenantLength = 64
+
+// CacheScope キャッシュの利用状況を集計する単位（エンドポイントとテナント）をコンテキストに設定するミドルウェア
+// エンドポイントはmuxのルーティングのパターン、テナントはtenantHeaderのヘッダーの値（ない場合は default）とする
+func CacheScope(mux *http.ServeMux, tenantHeader string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scope := sharedDomain.CacheScope{Tenant: sharedDomain.CacheTenantDefault}
+		// パスそのものではなくパターンを使い、レシートのIDなどで組み合わせが増えないようにする
+		if _, pattern := mux.Handler(r); pattern != "" {
+			scope.Endpoint = pattern
+		} else {
+			scope.Endpoint = "(unmatched)"
+		}
+		if tenantHeader != "" {
+			if tenant := strings.TrimSpace(r.Header.Get(tenantHeader)); tenant != "" {
+				scope.Tenant = tenant[:min(len(tenant), maxTenantLength)]
+			}
+		}
+
+		next.ServeHTTP(w, r.WithContext(sharedDomain.WithCacheScope(r.Context(), scope)))
+	})
+}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// FeatureFlagProvider 機能フラグのリモート取得元（設定サービスなど）
+type FeatureFlagProvider interface {
+	// Fetch 機能名ごとの有効・無効を取得
+	Fetch(ctx context.Context) (map[string]bool, error)
+}
+
+// FeatureFlags 機能ごとにエンドポイントを有効化する機能フラグ
+// 新しいエンドポイントを無効のままデプロイし、環境ごとに有効化するために使用する
+type FeatureFlags struct {
+	mu       sync.RWMutex
+	defaults map[string]bool
+	remote   map[string]bool
+}
+
+// NewFeatureFlags 設定ファイルの値を初期値として新しいFeatureFlagsを作成
+func NewFeatureFlags(defaults map[string]bool) *FeatureFlags {
+	return &FeatureFlags{defaults: maps.Clone(defaults)}
+}
+
+// Enabled 機能が有効か判定（リモートの値を優先し、どちらにもない機能は無効）
+func (f *FeatureFlags) Enabled(name string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if enabled, ok := f.remote[name]; ok {
+		return enabled
+	}
+	return f.defaults[name]
+}
+
+// Snapshot 現在の機能フラグの一覧を返す
+func (f *FeatureFlags) Snapshot() map[string]bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	flags := maps.Clone(f.defaults)
+	if flags == nil {
+		flags = make(map[string]bool)
+	}
+	maps.Copy(flags, f.remote)
+	return flags
+}
+
+// Refresh リモートから機能フラグを取得して反映（失敗した場合は前回の値を使い続ける）
+func (f *FeatureFlags) Refresh(ctx context.Context, provider FeatureFlagProvider) error {
+	remote, err := provider.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.remote = remote
+	return nil
+}
+
+// Watch 一定間隔でリモートの機能フラグを再取得する（ctxがキャンセルされるまでブロック）
+// 各インスタンスがそれぞれ取得するため、分散ロックは使用しない
+func (f *FeatureFlags) Watch(ctx context.Context, provider FeatureFlagProvider, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := f.Refresh(ctx, provider); err != nil && ctx.Err() == nil {
+			slog.Warn("Failed to refresh feature flags", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Require 機能が無効の場合は404を返すミドルウェア（エンドポイントの存在自体を隠す）
+func (f *FeatureFlags) Require(name string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !f.Enabled(name) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(ErrorResponse{
+				Success: false,
+				Error:   "Not found",
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
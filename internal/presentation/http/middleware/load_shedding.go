@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// LoadSheddingLimits 同時に処理するリクエストの上限
+type LoadSheddingLimits struct {
+	MaxInFlight       int           // 同時に処理するリクエストの上限
+	MaxQueue          int           // 上限に達した場合に空きを待つリクエストの上限（超えた分はすぐに503を返す）
+	QueueTimeout      time.Duration // 空きを待つ最大時間
+	LargeRequestBytes int64         // 大きなリクエストとみなすボディのサイズ（Content-Length、不明な場合も大きなリクエストとみなす）
+	MaxLargeInFlight  int           // 同時に処理する大きなリクエストの上限（画像のアップロードなどでメモリを使い切らないようにする）
+	RetryAfter        time.Duration // 503のRetry-Afterで再試行を促すまでの時間
+}
+
+// LoadShedder 同時に処理するリクエスト数を制限し、過負荷の場合は503を返すミドルウェア
+// 上限に達した場合は最大MaxQueue件までQueueTimeoutの間だけ空きを待ち、それ以外はボディを読み込む前に拒否する
+// ヘルスチェック・管理APIは過負荷でも確認・操作できるよう制限しない
+type LoadShedder struct {
+	limits     LoadSheddingLimits
+	slots      chan struct{}
+	largeSlots chan struct{}
+	waiting    atomic.Int64
+	retryAfter string
+}
+
+// NewLoadShedder 新しいLoadShedderを作成
+func NewLoadShedder(limits LoadSheddingLimits) *LoadShedder {
+	limits.MaxInFlight = max(limits.MaxInFlight, 1)
+	limits.MaxLargeInFlight = min(max(limits.MaxLargeInFlight, 1), limits.MaxInFlight)
+	limits.MaxQueue = max(limits.MaxQueue, 0)
+	return &LoadShedder{
+		limits:     limits,
+		slots:      make(chan struct{}, limits.MaxInFlight),
+		largeSlots: make(chan struct{}, limits.MaxLargeInFlight),
+		retryAfter: strconv.Itoa(max(int(limits.RetryAfter.Seconds()), 1)),
+	}
+}
+
+// Handler 同時に処理するリクエスト数を制限するハンドラー
+func (l *LoadShedder) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" || r.URL.Path == "/ready" || strings.HasPrefix(r.URL.Path, adminPathPrefix) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// 大きなリクエストは先に専用の枠を確保し、全体の枠を長く占有しないようにする
+		if l.isLarge(r) {
+			if !l.acquire(r.Context(), l.largeSlots) {
+				l.reject(w)
+				return
+			}
+			defer func() { <-l.largeSlots }()
+		}
+		if !l.acquire(r.Context(), l.slots) {
+			l.reject(w)
+			return
+		}
+		defer func() { <-l.slots }()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isLarge 大きなリクエストか判定（ボディのあるメソッドでサイズが不明な場合も含む）
+func (l *LoadShedder) isLarge(r *http.Request) bool {
+	if l.limits.LargeRequestBytes <= 0 || isSafeMethod(r.Method) {
+		return false
+	}
+	return r.ContentLength < 0 || r.ContentLength >= l.limits.LargeRequestBytes
+}
+
+// acquire 枠を確保（空きがない場合は待ち行列に空きがあれば待つ、確保できなかった場合はfalse）
+func (l *LoadShedder) acquire(ctx context.Context, slots chan struct{}) bool {
+	select {
+	case slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	if l.waiting.Add(1) > int64(l.limits.MaxQueue) {
+		l.waiting.Add(-1)
+		return false
+	}
+	defer l.waiting.Add(-1)
+
+	timer := time.NewTimer(l.limits.QueueTimeout)
+	defer timer.Stop()
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// reject 過負荷のため503を返す
+func (l *LoadShedder) reject(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", l.retryAfter)
+	w.WriteHeader(http.StatusServiceUnavailable)
+	_ = json.NewEncoder(w).Encode(ErrorResponse{
+		Success: false,
+		Error:   "Server is busy, please retry later",
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"log/slog"
 	"net/http"
 	"time"
+
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
 )
 
 // responseWriter ステータスコードをキャプチャするためのラッパー
@@ -24,6 +26,11 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Unwrap 元のResponseWriterを返す（http.ResponseControllerで書き込み期限などを設定するため）
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // Logger ロギングミドルウェア
 func Logger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -46,6 +53,7 @@ func Logger(next http.Handler) http.Handler {
 			"status", rw.statusCode,
 			"bytes", rw.written,
 			"duration", duration,
+			"client_ip", sharedDomain.ClientIPFromContext(r.Context()),
 		)
 	})
 }
@@ -53,8 +61,8 @@ func Logger(next http.Handler) http.Handler {
 // LoggerWithHealthCheck ヘルスチェックを除外するロギングミドルウェア
 func LoggerWithHealthCheck(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// ヘルスチェックは正常時ログ出力しない
-		if r.URL.Path == "/health" {
+		// ヘルスチェック・レディネスチェックは正常時ログ出力しない
+		if r.URL.Path == "/health" || r.URL.Path == "/ready" {
 			rw := &responseWriter{
 				ResponseWriter: w,
 				statusCode:     http.StatusOK,
@@ -65,6 +73,7 @@ func LoggerWithHealthCheck(next http.Handler) http.Handler {
 			if rw.statusCode != http.StatusOK {
 				slog.Error("Health check failed",
 					"status", rw.statusCode,
+					"client_ip", sharedDomain.ClientIPFromContext(r.Context()),
 				)
 			}
 			return
@@ -85,6 +94,7 @@ func LoggerWithHealthCheck(next http.Handler) http.Handler {
 			"status", rw.statusCode,
 			"bytes", rw.written,
 			"duration", duration,
+			"client_ip", sharedDomain.ClientIPFromContext(r.Context()),
 		)
 	})
 }
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// adminPathPrefix 管理APIのパス接頭辞（メンテナンス中も解除できるよう除外する）
+const adminPathPrefix = "/api/v1/admin/"
+
+// defaultMaintenanceMessage メッセージ未設定時にメンテナンス中に返すメッセージ
+const defaultMaintenanceMessage = "ただいまメンテナンス中です。しばらくしてから再度お試しください。"
+
+// MaintenanceResponse メンテナンス中のレスポンス
+type MaintenanceResponse struct {
+	Success     bool   `json:"success"`
+	Error       string `json:"error"`
+	Maintenance bool   `json:"maintenance"`
+}
+
+// Maintenance 再起動なしで切り替えられるメンテナンスモード
+type Maintenance struct {
+	mu      sync.RWMutex
+	enabled bool
+	message string
+}
+
+// NewMaintenance 新しいMaintenanceを作成
+func NewMaintenance(enabled bool, message string) *Maintenance {
+	m := &Maintenance{}
+	m.Set(enabled, message)
+	return m
+}
+
+// Set メンテナンスモードを切り替える（メッセージが空の場合はデフォルトを使用）
+func (m *Maintenance) Set(enabled bool, message string) {
+	if message == "" {
+		message = defaultMaintenanceMessage
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.enabled = enabled
+	m.message = message
+}
+
+// Status 現在の状態とメッセージを返す
+func (m *Maintenance) Status() (bool, string) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.enabled, m.message
+}
+
+// Handler メンテナンス中は更新系リクエストに503を返すミドルウェア
+// 参照系リクエスト・ヘルスチェック・管理APIはメンテナンス中も処理する
+func (m *Maintenance) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		enabled, message := m.Status()
+		if !enabled || isSafeMethod(r.Method) || strings.HasPrefix(r.URL.Path, adminPathPrefix) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(MaintenanceResponse{
+			Success:     false,
+			Error:       message,
+			Maintenance: true,
+		})
+	})
+}
+
+// isSafeMethod データを変更しないHTTPメソッドか判定
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// AdminAuth 管理APIのBearerトークン認証ミドルウェア
+// トークンが未設定の場合は管理APIを無効化して404を返す
+func AdminAuth(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token == "" {
+			http.NotFound(w, r)
+			return
+		}
+
+		given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			slog.WarnContext(r.Context(), "Unauthorized admin request", "path", r.URL.Path, "client_ip", sharedDomain.ClientIPFromContext(r.Context()))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(ErrorResponse{
+				Success: false,
+				Error:   "Unauthorized",
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -1,11 +1,17 @@
 package middleware
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
+
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
 )
 
 func TestCORS(t *testing.T) {
@@ -187,6 +193,12 @@ func TestLoggerWithHealthCheck(t *testing.T) {
 			statusCode:     http.StatusInternalServerError,
 			expectedStatus: http.StatusInternalServerError,
 		},
+		{
+			name:           "異常系: /ready with 503 (log error)",
+			path:           "/ready",
+			statusCode:     http.StatusServiceUnavailable,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
 		{
 			name:           "正常系: /api/test with 200 (normal log)",
 			path:           "/api/test",
@@ -370,7 +382,7 @@ func TestRecovery(t *testing.T) {
 		},
 		{
 			name:           "異常系: Error panic",
-			panicValue:     http.ErrAbortHandler,
+			panicValue:     errors.New("test error"),
 			expectedStatus: http.StatusInternalServerError,
 		},
 		{
@@ -423,6 +435,25 @@ func TestRecovery(t *testing.T) {
 	}
 }
 
+func TestRecovery_AbortHandler(t *testing.T) {
+	// 書き込み途中のレスポンスの中断はそのままサーバーに伝える（エラーのJSONを続けて書き込まない）
+	handler := Recovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("partial"))
+		panic(http.ErrAbortHandler)
+	}))
+
+	rec := httptest.NewRecorder()
+	defer func() {
+		if p := recover(); p != http.ErrAbortHandler {
+			t.Errorf("recover() = %v, want http.ErrAbortHandler", p)
+		}
+		if rec.Body.String() != "partial" {
+			t.Errorf("body = %q, want %q", rec.Body.String(), "partial")
+		}
+	}()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/test", nil))
+}
+
 func TestRecovery_NoPanic(t *testing.T) {
 	handler := Recovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -490,3 +521,906 @@ func TestMiddlewareChain_WithPanic(t *testing.T) {
 		t.Error("CORS header not set even before panic")
 	}
 }
+
+func TestMaintenance(t *testing.T) {
+	maintenance := NewMaintenance(true, "")
+	handler := maintenance.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{
+			name:       "正常系: GET request passes through",
+			method:     http.MethodGet,
+			path:       "/api/v1/receipts",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "正常系: health check passes through",
+			method:     http.MethodGet,
+			path:       "/health",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "正常系: admin API passes through",
+			method:     http.MethodPut,
+			path:       "/api/v1/admin/maintenance",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "異常系: POST request is rejected",
+			method:     http.MethodPost,
+			path:       "/api/v1/vision/receipt",
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:       "異常系: PATCH request is rejected",
+			method:     http.MethodPatch,
+			path:       "/api/v1/receipts/1",
+			wantStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status code = %d, want %d", rec.Code, tt.wantStatus)
+			}
+
+			if tt.wantStatus == http.StatusServiceUnavailable {
+				var response MaintenanceResponse
+				if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if !response.Maintenance || response.Error == "" {
+					t.Errorf("response = %+v, want maintenance message", response)
+				}
+				if rec.Header().Get("Retry-After") == "" {
+					t.Error("Expected Retry-After header")
+				}
+			}
+		})
+	}
+}
+
+func TestMaintenance_Toggle(t *testing.T) {
+	maintenance := NewMaintenance(false, "")
+	handler := maintenance.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func() int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/upload", nil))
+		return rec.Code
+	}
+
+	if got := serve(); got != http.StatusOK {
+		t.Errorf("status code = %d, want %d", got, http.StatusOK)
+	}
+
+	maintenance.Set(true, "DB移行中")
+	if got := serve(); got != http.StatusServiceUnavailable {
+		t.Errorf("status code = %d, want %d", got, http.StatusServiceUnavailable)
+	}
+	if enabled, message := maintenance.Status(); !enabled || message != "DB移行中" {
+		t.Errorf("Status() = %v, %s, want true, DB移行中", enabled, message)
+	}
+
+	maintenance.Set(false, "")
+	if got := serve(); got != http.StatusOK {
+		t.Errorf("status code = %d, want %d", got, http.StatusOK)
+	}
+}
+
+func TestAdminAuth(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		wantStatus    int
+	}{
+		{
+			name:          "正常系: valid token",
+			token:         "secret",
+			authorization: "Bearer secret",
+			wantStatus:    http.StatusOK,
+		},
+		{
+			name:          "異常系: invalid token",
+			token:         "secret",
+			authorization: "Bearer wrong",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:       "異常系: missing token",
+			token:      "secret",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:          "異常系: admin API disabled",
+			token:         "",
+			authorization: "Bearer ",
+			wantStatus:    http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/maintenance", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+
+			AdminAuth(tt.token, next).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status code = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name          string
+		keys          []string
+		apiKey        string
+		authorization string
+		wantStatus    int
+	}{
+		{
+			name:       "正常系: X-API-Key",
+			keys:       []string{"key1", "key2"},
+			apiKey:     "key2",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:          "正常系: Bearer",
+			keys:          []string{"key1"},
+			authorization: "Bearer key1",
+			wantStatus:    http.StatusOK,
+		},
+		{
+			name:       "異常系: invalid key",
+			keys:       []string{"key1"},
+			apiKey:     "wrong",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "異常系: missing key",
+			keys:       []string{"key1"},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "異常系: no keys configured",
+			keys:       []string{"", " "},
+			apiKey:     " ",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/vision/receipt/simple", nil)
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+
+			APIKeyAuth(tt.keys, next).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status code = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// stubFlagProvider 固定の機能フラグを返すスタブ
+type stubFlagProvider struct {
+	flags map[string]bool
+	err   error
+}
+
+func (p stubFlagProvider) Fetch(ctx context.Context) (map[string]bool, error) {
+	return p.flags, p.err
+}
+
+func TestFeatureFlags(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	flags := NewFeatureFlags(map[string]bool{"direct_upload": true, "batch_upload": false})
+
+	tests := []struct {
+		name       string
+		provider   *stubFlagProvider
+		feature    string
+		wantStatus int
+	}{
+		{name: "正常系: 設定で有効な機能", feature: "direct_upload", wantStatus: http.StatusOK},
+		{name: "異常系: 設定で無効な機能", feature: "batch_upload", wantStatus: http.StatusNotFound},
+		{name: "異常系: 未定義の機能は無効", feature: "invoices", wantStatus: http.StatusNotFound},
+		{
+			name:       "正常系: リモートで有効化",
+			provider:   &stubFlagProvider{flags: map[string]bool{"batch_upload": true}},
+			feature:    "batch_upload",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "正常系: リモートの取得に失敗した場合は前回の値を使う",
+			provider:   &stubFlagProvider{err: errors.New("unavailable")},
+			feature:    "batch_upload",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "正常系: リモートで無効化",
+			provider:   &stubFlagProvider{flags: map[string]bool{"direct_upload": false}},
+			feature:    "direct_upload",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.provider != nil {
+				err := flags.Refresh(context.Background(), tt.provider)
+				if (err != nil) != (tt.provider.err != nil) {
+					t.Fatalf("Refresh() error = %v", err)
+				}
+			}
+
+			rec := httptest.NewRecorder()
+			flags.Require(tt.feature, next).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/uploads/presign", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status code = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+
+	// リモートで上書きされていない機能は設定の値のまま
+	snapshot := flags.Snapshot()
+	if snapshot["direct_upload"] || snapshot["batch_upload"] {
+		t.Errorf("Snapshot() = %v, want remote values to override", snapshot)
+	}
+}
+
+func TestPersistenceDisabled(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/vision/analyze", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/api/v1/receipts", PersistenceRequired)
+	handler := PersistenceDisabled(mux)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{name: "正常系: AIのみのエンドポイントは利用できる", path: "/api/v1/vision/analyze", wantStatus: http.StatusOK},
+		{name: "異常系: 保存が必要なエンドポイントは503", path: "/api/v1/receipts", wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status code = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get(PersistenceHeader); got != "disabled" {
+				t.Errorf("%s = %q, want disabled", PersistenceHeader, got)
+			}
+			if tt.wantStatus == http.StatusServiceUnavailable {
+				var resp ErrorResponse
+				if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Success || !strings.Contains(resp.Error, "persistence is disabled") {
+					t.Errorf("response = %+v, want persistence disabled error", resp)
+				}
+			}
+		})
+	}
+}
+
+func TestResponseTransform(t *testing.T) {
+	translations := map[string]map[string]string{
+		"en": {"食費": "Food", "現金": "Cash"},
+	}
+	jsonHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"success":true,"data":{"store_name":"食費商店","total_amount":1500,"payment_method":"現金","items":[{"item_name":"牛乳","category":"食費"}]}}` + "\n"))
+	})
+
+	tests := []struct {
+		name           string
+		naming         string
+		headers        map[string]string
+		handler        http.Handler
+		want           string
+		wantLanguage   string
+		wantSkipDecode bool
+	}{
+		{
+			name:    "正常系: 設定がsnakeで翻訳なしの場合はそのまま返す",
+			naming:  FieldNamingSnake,
+			handler: jsonHandler,
+			want:    `{"data":{"items":[{"category":"食費","item_name":"牛乳"}],"payment_method":"現金","store_name":"食費商店","total_amount":1500},"success":true}`,
+		},
+		{
+			name:    "正常系: 設定でキャメルケースに変換",
+			naming:  FieldNamingCamel,
+			handler: jsonHandler,
+			want:    `{"data":{"items":[{"category":"食費","itemName":"牛乳"}],"paymentMethod":"現金","storeName":"食費商店","totalAmount":1500},"success":true}`,
+		},
+		{
+			name:    "正常系: ヘッダーでキャメルケースを指定",
+			naming:  FieldNamingSnake,
+			headers: map[string]string{"X-JSON-Naming": "camel"},
+			handler: jsonHandler,
+			want:    `{"data":{"items":[{"category":"食費","itemName":"牛乳"}],"paymentMethod":"現金","storeName":"食費商店","totalAmount":1500},"success":true}`,
+		},
+		{
+			name:         "正常系: Accept-Languageで対象のフィールドのみ翻訳",
+			naming:       FieldNamingSnake,
+			headers:      map[string]string{"Accept-Language": "ja;q=0.5, en-US;q=0.9"},
+			handler:      jsonHandler,
+			want:         `{"data":{"items":[{"category":"Food","item_name":"牛乳"}],"payment_method":"Cash","store_name":"食費商店","total_amount":1500},"success":true}`,
+			wantLanguage: "en",
+		},
+		{
+			name:    "正常系: 日本語が優先の場合は翻訳しない",
+			naming:  FieldNamingSnake,
+			headers: map[string]string{"Accept-Language": "ja-JP, en;q=0.8"},
+			handler: jsonHandler,
+			want:    `{"data":{"items":[{"category":"食費","item_name":"牛乳"}],"payment_method":"現金","store_name":"食費商店","total_amount":1500},"success":true}`,
+		},
+		{
+			name:    "正常系: JSON以外のレスポンスは変換しない",
+			naming:  FieldNamingCamel,
+			headers: map[string]string{"Accept-Language": "en"},
+			handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/csv")
+				_, _ = w.Write([]byte("store_name,category\n食費商店,食費\n"))
+			}),
+			want:           "store_name,category\n食費商店,食費\n",
+			wantSkipDecode: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transform := NewResponseTransform(tt.naming, []string{"category", "payment_method"}, translations)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/receipts/1", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			transform.Handler(tt.handler).ServeHTTP(rec, req)
+
+			if tt.wantSkipDecode {
+				if rec.Body.String() != tt.want {
+					t.Errorf("body = %q, want %q", rec.Body.String(), tt.want)
+				}
+				return
+			}
+
+			if rec.Code != http.StatusCreated {
+				t.Errorf("status code = %d, want %d", rec.Code, http.StatusCreated)
+			}
+			// キーの順序によらず比較するため、両方を再エンコードする
+			var got, want any
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			_ = json.Unmarshal([]byte(tt.want), &want)
+			gotJSON, _ := json.Marshal(got)
+			wantJSON, _ := json.Marshal(want)
+			if string(gotJSON) != string(wantJSON) {
+				t.Errorf("body = %s, want %s", gotJSON, wantJSON)
+			}
+			if lang := rec.Header().Get("Content-Language"); lang != tt.wantLanguage {
+				t.Errorf("Content-Language = %q, want %q", lang, tt.wantLanguage)
+			}
+		})
+	}
+}
+
+func TestTimezone(t *testing.T) {
+	defaultLocation := time.FixedZone("JST", 9*60*60)
+
+	tests := []struct {
+		name       string
+		target     string
+		header     string
+		wantStatus int
+		wantName   string
+	}{
+		{name: "正常系: 指定なしはデフォルトのタイムゾーン", target: "/api/v1/reports/monthly", wantStatus: http.StatusOK, wantName: "JST"},
+		{name: "正常系: ヘッダーで指定", target: "/api/v1/reports/monthly", header: "UTC", wantStatus: http.StatusOK, wantName: "UTC"},
+		{name: "正常系: パラメーターはヘッダーより優先", target: "/api/v1/reports/monthly?tz=America/New_York", header: "UTC", wantStatus: http.StatusOK, wantName: "America/New_York"},
+		{name: "異常系: 不正なタイムゾーン", target: "/api/v1/reports/monthly", header: "Mars/Olympus", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotName string
+			handler := Timezone(defaultLocation, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotName = sharedDomain.LocationFromContext(r.Context()).String()
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set("X-Timezone", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status code = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if gotName != tt.wantName {
+				t.Errorf("location = %q, want %q", gotName, tt.wantName)
+			}
+		})
+	}
+}
+
+// recordingNotifier 送信した通知を記録するテスト用のNotifier
+type recordingNotifier struct {
+	events []string
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification sharedDomain.Notification) error {
+	n.events = append(n.events, notification.Event)
+	return nil
+}
+
+func TestSLOTracker_Handler(t *testing.T) {
+	tracker := NewSLOTracker(SLOObjectives{Window: time.Hour, LatencyPercentile: 95, SuccessTarget: 99})
+	handler := tracker.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("status") {
+		case "400":
+			w.WriteHeader(http.StatusBadRequest)
+		case "500":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+
+	for _, status := range []string{"200", "400", "500"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/vision/receipt?status="+status, nil))
+	}
+
+	// 4xxはエラーバジェットを消費しない
+	status := tracker.Status()
+	if status.Requests != 3 || status.Errors != 1 {
+		t.Errorf("Status() = %+v, want 3 requests and 1 error", status)
+	}
+}
+
+func TestSLOTracker_Check(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	notifier := &recordingNotifier{}
+	tracker := NewSLOTracker(SLOObjectives{
+		Window:            10 * time.Minute,
+		LatencyPercentile: 95,
+		LatencyThreshold:  time.Second,
+		SuccessTarget:     90,
+		MinRequests:       10,
+	})
+	tracker.now = func() time.Time { return now }
+	tracker.SetNotifier(notifier)
+
+	observe := func(n int, duration time.Duration, failed bool) {
+		for range n {
+			tracker.Observe(duration, failed)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		advance    time.Duration
+		observe    func()
+		wantEvents []string
+		wantStatus func(SLOStatus) bool
+	}{
+		{
+			name:       "正常系: 最小のリクエスト数に満たない間は通知しない",
+			observe:    func() { observe(5, 3*time.Second, true) },
+			wantStatus: func(s SLOStatus) bool { return !s.Evaluated && !s.LatencyBreached },
+		},
+		{
+			name:       "異常系: p95の処理時間とエラー率が目標を外れると通知する",
+			observe:    func() { observe(5, 3*time.Second, false) },
+			wantEvents: []string{SLOEventLatencyBreached, SLOEventErrorBudgetExhausted},
+			wantStatus: func(s SLOStatus) bool {
+				return s.Evaluated && s.LatencyBreached && s.ErrorBudgetExhausted && s.LatencyMs == 3000 && s.SuccessRate == 50
+			},
+		},
+		{
+			name:       "異常系: 目標を外れたままの間は再通知しない",
+			observe:    func() { observe(1, 3*time.Second, false) },
+			wantEvents: []string{SLOEventLatencyBreached, SLOEventErrorBudgetExhausted},
+			wantStatus: func(s SLOStatus) bool { return s.LatencyBreached && s.ErrorBudgetExhausted },
+		},
+		{
+			name:       "正常系: ウィンドウから古い処理結果が外れると回復を通知する",
+			advance:    11 * time.Minute,
+			observe:    func() { observe(20, 200*time.Millisecond, false) },
+			wantEvents: []string{SLOEventLatencyBreached, SLOEventErrorBudgetExhausted, SLOEventRecovered, SLOEventRecovered},
+			wantStatus: func(s SLOStatus) bool {
+				return s.Requests == 20 && !s.LatencyBreached && !s.ErrorBudgetExhausted && s.ErrorBudgetRemaining == 100
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = now.Add(tt.advance)
+			tt.observe()
+
+			status, err := tracker.Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if !tt.wantStatus(status) {
+				t.Errorf("Check() status = %+v", status)
+			}
+			if strings.Join(notifier.events, ",") != strings.Join(tt.wantEvents, ",") {
+				t.Errorf("events = %v, want %v", notifier.events, tt.wantEvents)
+			}
+		})
+	}
+}
+
+// memoryCacheStore テスト用のメモリ上のキャッシュ
+type memoryCacheStore struct {
+	data map[string][]byte
+}
+
+func (s *memoryCacheStore) Set(ctx context.Context, key string, value []byte, expiration time.Duration) error {
+	s.data[key] = value
+	return nil
+}
+
+func (s *memoryCacheStore) Get(ctx context.Context, key string) ([]byte, error) {
+	value, ok := s.data[key]
+	if !ok {
+		return nil, errors.New("cache not found")
+	}
+	return value, nil
+}
+
+func TestResponseCache(t *testing.T) {
+	store := &memoryCacheStore{data: make(map[string][]byte)}
+	cache := NewResponseCache(store, time.Minute)
+
+	calls := 0
+	report := cache.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Query().Get("year") == "bad" {
+			writeJSONError(w, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"calls":` + strconv.Itoa(calls) + `}`))
+	}))
+	write := cache.Invalidate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/receipts/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	get := func(target string, loc *time.Location) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req = req.WithContext(sharedDomain.WithLocation(req.Context(), loc))
+		rec := httptest.NewRecorder()
+		report.ServeHTTP(rec, req)
+		return rec
+	}
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+
+	tests := []struct {
+		name      string
+		do        func() *httptest.ResponseRecorder
+		wantCache string
+		wantBody  string
+	}{
+		{
+			name: "正常系: 初回はハンドラーで集計する",
+			do: func() *httptest.ResponseRecorder {
+				return get("/api/v1/reports/monthly?year=2025&month_start_day=1", time.UTC)
+			},
+			wantCache: "MISS",
+			wantBody:  `{"calls":1}`,
+		},
+		{
+			name: "正常系: パラメーターの順序が違っても同じキャッシュを返す",
+			do: func() *httptest.ResponseRecorder {
+				return get("/api/v1/reports/monthly?month_start_day=1&year=2025", time.UTC)
+			},
+			wantCache: "HIT",
+			wantBody:  `{"calls":1}`,
+		},
+		{
+			name: "正常系: タイムゾーンが違う場合は別に集計する",
+			do: func() *httptest.ResponseRecorder {
+				return get("/api/v1/reports/monthly?year=2025&month_start_day=1", tokyo)
+			},
+			wantCache: "MISS",
+			wantBody:  `{"calls":2}`,
+		},
+		{
+			name: "正常系: 失敗した更新系リクエストではキャッシュを無効化しない",
+			do: func() *httptest.ResponseRecorder {
+				write.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPatch, "/api/v1/receipts/missing", nil))
+				return get("/api/v1/reports/monthly?year=2025&month_start_day=1", time.UTC)
+			},
+			wantCache: "HIT",
+			wantBody:  `{"calls":1}`,
+		},
+		{
+			name: "正常系: 更新系リクエストが成功するとキャッシュを無効化する",
+			do: func() *httptest.ResponseRecorder {
+				write.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/v1/receipts", nil))
+				return get("/api/v1/reports/monthly?year=2025&month_start_day=1", time.UTC)
+			},
+			wantCache: "MISS",
+			wantBody:  `{"calls":3}`,
+		},
+		{
+			name: "異常系: エラーのレスポンスはキャッシュしない",
+			do: func() *httptest.ResponseRecorder {
+				get("/api/v1/reports/monthly?year=bad", time.UTC)
+				return get("/api/v1/reports/monthly?year=bad", time.UTC)
+			},
+			wantCache: "MISS",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := tt.do()
+			if got := rec.Header().Get("X-Cache"); got != tt.wantCache {
+				t.Errorf("X-Cache = %s, want %s", got, tt.wantCache)
+			}
+			if tt.wantBody != "" {
+				if rec.Body.String() != tt.wantBody {
+					t.Errorf("body = %s, want %s", rec.Body.String(), tt.wantBody)
+				}
+				if rec.Header().Get("Content-Type") != "application/json" {
+					t.Errorf("Content-Type = %s, want application/json", rec.Header().Get("Content-Type"))
+				}
+			}
+		})
+	}
+}
+
+// writeJSONError テスト用のエラーレスポンス
+func writeJSONError(w http.ResponseWriter, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(ErrorResponse{Success: false, Error: http.StatusText(status)})
+}
+
+func TestCacheScope(t *testing.T) {
+	var got sharedDomain.CacheScope
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/receipts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		got = sharedDomain.CacheScopeFromContext(r.Context())
+	})
+	handler := CacheScope(mux, "X-Tenant-ID", mux)
+
+	tests := []struct {
+		name   string
+		path   string
+		tenant string
+		want   sharedDomain.CacheScope
+	}{
+		{
+			name:   "パスのIDによらずパターンで集計する",
+			path:   "/api/v1/receipts/receipt-1",
+			tenant: "tenant-a",
+			want:   sharedDomain.CacheScope{Endpoint: "GET /api/v1/receipts/{id}", Tenant: "tenant-a"},
+		},
+		{
+			name: "テナントの指定がない場合はdefault",
+			path: "/api/v1/receipts/receipt-2",
+			want: sharedDomain.CacheScope{Endpoint: "GET /api/v1/receipts/{id}", Tenant: sharedDomain.CacheTenantDefault},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.tenant != "" {
+				req.Header.Set("X-Tenant-ID", tt.tenant)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			if got != tt.want {
+				t.Errorf("scope = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRealIP(t *testing.T) {
+	realIP, err := NewRealIP([]string{"10.0.0.0/8", "192.0.2.1"})
+	if err != nil {
+		t.Fatalf("NewRealIP() error = %v", err)
+	}
+	var got string
+	handler := realIP.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = sharedDomain.ClientIPFromContext(r.Context())
+	}))
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		realIP       string
+		want         string
+	}{
+		{name: "プロキシを経由しない接続", remoteAddr: "203.0.113.5:4321", want: "203.0.113.5"},
+		{name: "信頼しない接続元のヘッダーは無視する", remoteAddr: "203.0.113.5:4321", forwardedFor: []string{"198.51.100.7"}, want: "203.0.113.5"},
+		{name: "信頼するプロキシのX-Forwarded-For", remoteAddr: "10.1.2.3:4321", forwardedFor: []string{"198.51.100.7"}, want: "198.51.100.7"},
+		{name: "信頼するプロキシを右からたどる", remoteAddr: "10.1.2.3:4321", forwardedFor: []string{"203.0.113.9, 198.51.100.7, 10.4.5.6"}, want: "198.51.100.7"},
+		{name: "複数のヘッダーをまとめてたどる", remoteAddr: "192.0.2.1:4321", forwardedFor: []string{"203.0.113.9", "198.51.100.7, 10.4.5.6"}, want: "198.51.100.7"},
+		{name: "クライアントが偽装した左側の値は使わない", remoteAddr: "10.1.2.3:4321", forwardedFor: []string{"127.0.0.1, 198.51.100.7"}, want: "198.51.100.7"},
+		{name: "すべて信頼するプロキシの場合は最も左", remoteAddr: "10.1.2.3:4321", forwardedFor: []string{"10.9.9.9, 10.4.5.6"}, want: "10.9.9.9"},
+		{name: "不正な値の場合は直前のプロキシ", remoteAddr: "10.1.2.3:4321", forwardedFor: []string{"198.51.100.7, unknown"}, want: "10.1.2.3"},
+		{name: "X-Forwarded-ForがなければX-Real-IP", remoteAddr: "10.1.2.3:4321", realIP: "198.51.100.7", want: "198.51.100.7"},
+		{name: "IPv4射影アドレスのプロキシ", remoteAddr: "[::ffff:10.1.2.3]:4321", forwardedFor: []string{"2001:db8::1"}, want: "2001:db8::1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/receipts", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwardedFor {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			if got != tt.want {
+				t.Errorf("client ip = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRealIP_Invalid(t *testing.T) {
+	for _, proxy := range []string{"10.0.0.0/33", "proxy.internal"} {
+		if _, err := NewRealIP([]string{proxy}); err == nil {
+			t.Errorf("NewRealIP(%q) error = nil, want error", proxy)
+		}
+	}
+}
+
+func TestLoadShedder(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 4)
+	blocking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("block") != "" {
+			started <- struct{}{}
+			<-release
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	serve := func(handler http.Handler, req *http.Request) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	// block 処理中のままにするリクエストを開始し、処理が始まるまで待つ
+	block := func(t *testing.T, handler http.Handler, req *http.Request) chan *httptest.ResponseRecorder {
+		t.Helper()
+		done := make(chan *httptest.ResponseRecorder, 1)
+		go func() { done <- serve(handler, req) }()
+		<-started
+		return done
+	}
+
+	t.Run("上限を超えて待ち行列もない場合は503", func(t *testing.T) {
+		handler := NewLoadShedder(LoadSheddingLimits{MaxInFlight: 1, MaxQueue: 0, QueueTimeout: time.Second, RetryAfter: 5 * time.Second}).Handler(blocking)
+		done := block(t, handler, httptest.NewRequest(http.MethodGet, "/api/v1/receipts?block=1", nil))
+
+		rec := serve(handler, httptest.NewRequest(http.MethodGet, "/api/v1/receipts", nil))
+		if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") != "5" {
+			t.Errorf("status = %d, Retry-After = %q, want 503 and 5", rec.Code, rec.Header().Get("Retry-After"))
+		}
+		// ヘルスチェック・管理APIは制限しない
+		for _, path := range []string{"/health", "/api/v1/admin/maintenance"} {
+			if rec := serve(handler, httptest.NewRequest(http.MethodGet, path, nil)); rec.Code != http.StatusOK {
+				t.Errorf("%s status = %d, want 200", path, rec.Code)
+			}
+		}
+
+		release <- struct{}{}
+		if rec := <-done; rec.Code != http.StatusOK {
+			t.Errorf("in-flight status = %d, want 200", rec.Code)
+		}
+	})
+
+	t.Run("待ち行列のリクエストは空きができれば処理する", func(t *testing.T) {
+		handler := NewLoadShedder(LoadSheddingLimits{MaxInFlight: 1, MaxQueue: 1, QueueTimeout: 5 * time.Second}).Handler(blocking)
+		done := block(t, handler, httptest.NewRequest(http.MethodGet, "/api/v1/receipts?block=1", nil))
+
+		queued := make(chan *httptest.ResponseRecorder, 1)
+		go func() { queued <- serve(handler, httptest.NewRequest(http.MethodGet, "/api/v1/receipts", nil)) }()
+		time.Sleep(20 * time.Millisecond)
+		release <- struct{}{}
+
+		if rec := <-done; rec.Code != http.StatusOK {
+			t.Errorf("in-flight status = %d, want 200", rec.Code)
+		}
+		if rec := <-queued; rec.Code != http.StatusOK {
+			t.Errorf("queued status = %d, want 200", rec.Code)
+		}
+	})
+
+	t.Run("待ち時間を超えた場合は503", func(t *testing.T) {
+		handler := NewLoadShedder(LoadSheddingLimits{MaxInFlight: 1, MaxQueue: 1, QueueTimeout: 20 * time.Millisecond}).Handler(blocking)
+		done := block(t, handler, httptest.NewRequest(http.MethodGet, "/api/v1/receipts?block=1", nil))
+
+		if rec := serve(handler, httptest.NewRequest(http.MethodGet, "/api/v1/receipts", nil)); rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("status = %d, want 503", rec.Code)
+		}
+		release <- struct{}{}
+		<-done
+	})
+
+	t.Run("大きなリクエストは専用の上限で制限する", func(t *testing.T) {
+		handler := NewLoadShedder(LoadSheddingLimits{MaxInFlight: 10, MaxQueue: 0, LargeRequestBytes: 16, MaxLargeInFlight: 1}).Handler(blocking)
+		upload := func(query string) *http.Request {
+			return httptest.NewRequest(http.MethodPost, "/api/v1/receipts"+query, strings.NewReader(strings.Repeat("x", 32)))
+		}
+		done := block(t, handler, upload("?block=1"))
+
+		if rec := serve(handler, upload("")); rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("large status = %d, want 503", rec.Code)
+		}
+		small := httptest.NewRequest(http.MethodPost, "/api/v1/receipts", strings.NewReader("{}"))
+		if rec := serve(handler, small); rec.Code != http.StatusOK {
+			t.Errorf("small status = %d, want 200", rec.Code)
+		}
+		release <- struct{}{}
+		<-done
+	})
+}
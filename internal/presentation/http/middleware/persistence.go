@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// PersistenceHeader レシートの保存が有効かを示すレスポンスヘッダー
+const PersistenceHeader = "X-Receipt-Persistence"
+
+// persistenceDisabledMessage 保存が無効な構成で、保存を必要とするエンドポイントが返すメッセージ
+const persistenceDisabledMessage = "Receipt persistence is disabled because MySQL is not configured; only /api/v1/vision/* is available"
+
+// PersistenceDisabled レシートの保存が無効な構成（MySQL未設定）で使用するミドルウェア
+// すべてのレスポンスに保存が無効であることを示すヘッダーを付ける
+func PersistenceDisabled(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(PersistenceHeader, "disabled")
+		next.ServeHTTP(w, r)
+	})
+}
+
+// PersistenceRequired レシートの保存を必要とするエンドポイントの代わりに503を返すハンドラー
+func PersistenceRequired(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	_ = json.NewEncoder(w).Encode(ErrorResponse{
+		Success: false,
+		Error:   persistenceDisabledMessage,
+	})
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// RealIP リクエストの送信元のIPアドレスを解決するミドルウェア
+// 接続元が信頼するプロキシの場合のみ X-Forwarded-For（ない場合は X-Real-IP）を使い、それ以外はヘッダーを無視する
+// X-Forwarded-For は右（接続元に近い側）からたどり、信頼するプロキシでない最初のアドレスを送信元とする
+type RealIP struct {
+	trusted []netip.Prefix
+}
+
+// NewRealIP 新しいRealIPを作成（trustedProxiesはCIDRまたはIPアドレス、不正な値はエラー）
+func NewRealIP(trustedProxies []string) (*RealIP, error) {
+	m := &RealIP{}
+	for _, proxy := range trustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if !strings.Contains(proxy, "/") {
+			addr, err := netip.ParseAddr(proxy)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+			}
+			addr = addr.Unmap()
+			m.trusted = append(m.trusted, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		m.trusted = append(m.trusted, prefix.Masked())
+	}
+	return m, nil
+}
+
+// Handler 送信元のIPアドレスをコンテキストに設定するハンドラー
+func (m *RealIP) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(sharedDomain.WithClientIP(r.Context(), m.Resolve(r))))
+	})
+}
+
+// Resolve リクエストの送信元のIPアドレスを解決
+func (m *RealIP) Resolve(r *http.Request) string {
+	remote, ok := parseAddr(r.RemoteAddr)
+	if !ok {
+		return r.RemoteAddr
+	}
+	if !m.isTrusted(remote) {
+		return remote.String()
+	}
+
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+	if len(hops) == 0 {
+		if addr, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+			return addr.Unmap().String()
+		}
+		return remote.String()
+	}
+
+	client := remote
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, ok := parseAddr(strings.TrimSpace(hops[i]))
+		if !ok {
+			// 不正な値より左は信頼できないため、直前のプロキシを送信元とする
+			break
+		}
+		client = addr
+		if !m.isTrusted(addr) {
+			break
+		}
+	}
+	return client.String()
+}
+
+// isTrusted 信頼するプロキシのアドレスか判定
+func (m *RealIP) isTrusted(addr netip.Addr) bool {
+	for _, prefix := range m.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAddr ポート付き・なしのIPアドレスを解析
+func parseAddr(value string) (netip.Addr, bool) {
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
@@ -18,6 +18,10 @@ func Recovery(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
+				// 書き込み途中のレスポンスの中断（http.ErrAbortHandler）はサーバーに接続を切らせる
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
 				slog.Error("Panic recovered",
 					"error", err,
 					"stack", string(debug.Stack()),
@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// responseCacheGenerationKey キャッシュの世代（更新系のリクエストが成功するたびに変わる）を保存するキー
+const responseCacheGenerationKey = "response_cache:generation"
+
+// cachedResponseHeaders キャッシュしたレスポンスとともに保存するヘッダー
+var cachedResponseHeaders = []string{"Content-Type", "Content-Disposition"}
+
+// ResponseCacheStore レスポンスのキャッシュの保存先
+type ResponseCacheStore interface {
+	Set(ctx context.Context, key string, value []byte, expiration time.Duration) error
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// cachedResponse キャッシュしたレスポンス
+type cachedResponse struct {
+	Header map[string]string `json:"header"`
+	Body   []byte            `json:"body"`
+}
+
+// ResponseCache 集計に時間のかかるレポートのレスポンスを短時間キャッシュする
+// キャッシュキーはパス・クエリパラメーター・タイムゾーンと世代から作り、
+// レシート・家計簿の更新系リクエストが成功すると世代を変えて以前のキャッシュを使わないようにする
+type ResponseCache struct {
+	store ResponseCacheStore
+	ttl   time.Duration
+}
+
+// NewResponseCache 新しいResponseCacheを作成
+func NewResponseCache(store ResponseCacheStore, ttl time.Duration) *ResponseCache {
+	return &ResponseCache{
+		store: store,
+		ttl:   ttl,
+	}
+}
+
+// Handler GETリクエストの200のレスポンスをキャッシュするミドルウェア
+// キャッシュを使えない場合（保存先の障害など）はそのまま処理する
+func (c *ResponseCache) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		key := c.key(r)
+		if data, err := c.store.Get(ctx, key); err == nil {
+			var cached cachedResponse
+			if err := json.Unmarshal(data, &cached); err == nil {
+				for name, value := range cached.Header {
+					w.Header().Set(name, value)
+				}
+				w.Header().Set("X-Cache", "HIT")
+				w.Header().Set("Content-Length", strconv.Itoa(len(cached.Body)))
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write(cached.Body)
+				return
+			}
+		}
+
+		w.Header().Set("X-Cache", "MISS")
+		cw := &cacheWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(cw, r)
+		if cw.status != http.StatusOK {
+			return
+		}
+
+		cached := cachedResponse{Header: make(map[string]string), Body: cw.body.Bytes()}
+		for _, name := range cachedResponseHeaders {
+			if value := w.Header().Get(name); value != "" {
+				cached.Header[name] = value
+			}
+		}
+		if data, err := json.Marshal(cached); err == nil {
+			if err := c.store.Set(ctx, key, data, c.ttl); err != nil {
+				slog.Debug("Failed to cache response", "path", r.URL.Path, "error", err)
+			}
+		}
+	})
+}
+
+// Invalidate 更新系のリクエストが成功した場合にキャッシュの世代を変えるミドルウェア
+func (c *ResponseCache) Invalidate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isSafeMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(rw, r)
+		if rw.statusCode < http.StatusBadRequest {
+			c.Bump(r.Context())
+		}
+	})
+}
+
+// Bump キャッシュの世代を変え、以前のレスポンスのキャッシュを使わないようにする
+func (c *ResponseCache) Bump(ctx context.Context) {
+	generation := strconv.FormatInt(time.Now().UnixNano(), 36)
+	// 世代は期限なしで保存する（期限切れで以前の世代に戻らないようにする）
+	// 世代の読み書きはキャッシュの利用状況に含めない（レポートのヒット率が高く見えないようにする）
+	if err := c.store.Set(sharedDomain.WithoutCacheStats(context.WithoutCancel(ctx)), responseCacheGenerationKey, []byte(generation), 0); err != nil {
+		slog.Warn("Failed to invalidate response cache", "error", err)
+	}
+}
+
+// key リクエストのキャッシュキー（世代・パス・クエリパラメーター・タイムゾーン）
+func (c *ResponseCache) key(r *http.Request) string {
+	generation := "0"
+	if data, err := c.store.Get(sharedDomain.WithoutCacheStats(r.Context()), responseCacheGenerationKey); err == nil {
+		generation = string(data)
+	}
+
+	h := sha256.New()
+	h.Write([]byte(r.URL.Path))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.Query().Encode())) // パラメーターの順序によらず同じキーにする
+	h.Write([]byte{0})
+	h.Write([]byte(sharedDomain.LocationFromContext(r.Context()).String()))
+	return "response_cache:" + generation + ":" + hex.EncodeToString(h.Sum(nil))
+}
+
+// cacheWriter レスポンスを書き込みながらキャッシュ用に保持するResponseWriter
+type cacheWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+// WriteHeader ステータスコードを保持して書き込む
+func (cw *cacheWriter) WriteHeader(code int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+	cw.status = code
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+// Write 200の場合のみキャッシュ用に保持する
+func (cw *cacheWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.status == http.StatusOK {
+		cw.body.Write(b)
+	}
+	return cw.ResponseWriter.Write(b)
+}
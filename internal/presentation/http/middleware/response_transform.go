@@ -0,0 +1,239 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// JSONのフィールド名の表記
+const (
+	FieldNamingSnake = "snake" // スネークケース（保存・内部処理と同じ表記）
+	FieldNamingCamel = "camel" // キャメルケース
+)
+
+// fieldNamingHeader クライアントごとにフィールド名の表記を指定するヘッダー
+const fieldNamingHeader = "X-JSON-Naming"
+
+// ResponseTransform JSONレスポンスのフィールド名の変換と表示文字列の多言語化
+// 保存済みのデータは変更せず、レスポンスを返す直前に変換する
+type ResponseTransform struct {
+	naming       string
+	fields       []string                     // 翻訳するフィールド（スネークケースの名前）
+	translations map[string]map[string]string // 言語 → 原文 → 翻訳
+}
+
+// NewResponseTransform 新しいResponseTransformを作成
+// namingはデフォルトのフィールド名の表記、translationsはAccept-Languageで選択する言語ごとの翻訳
+func NewResponseTransform(naming string, fields []string, translations map[string]map[string]string) *ResponseTransform {
+	if naming != FieldNamingCamel {
+		naming = FieldNamingSnake
+	}
+	normalized := make(map[string]map[string]string, len(translations))
+	for lang, dict := range translations {
+		normalized[strings.ToLower(lang)] = dict
+	}
+	return &ResponseTransform{
+		naming:       naming,
+		fields:       fields,
+		translations: normalized,
+	}
+}
+
+// Handler JSONレスポンスを変換するミドルウェア
+// X-JSON-Naming ヘッダー（snake / camel）でフィールド名の表記を、Accept-Language で翻訳の言語を選択する
+func (t *ResponseTransform) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(t.translations) > 0 {
+			w.Header().Add("Vary", "Accept-Language")
+		}
+
+		naming := t.naming
+		if v := strings.ToLower(r.Header.Get(fieldNamingHeader)); v == FieldNamingSnake || v == FieldNamingCamel {
+			naming = v
+		}
+		lang, dict := t.language(r.Header.Get("Accept-Language"))
+		if naming == FieldNamingSnake && dict == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		tw := &transformWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(tw, r)
+		if !tw.buffering {
+			return
+		}
+
+		body := tw.buf.Bytes()
+		var v any
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		if err := decoder.Decode(&v); err == nil {
+			if transformed, err := json.Marshal(t.transform(v, "", naming, dict)); err == nil {
+				body = append(transformed, '\n')
+				if dict != nil {
+					w.Header().Set("Content-Language", lang)
+				}
+			}
+		}
+
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(tw.status)
+		_, _ = w.Write(body)
+	})
+}
+
+// language Accept-Languageから翻訳のある言語を選択する（翻訳がない・原文の言語が優先の場合はnil）
+func (t *ResponseTransform) language(acceptLanguage string) (string, map[string]string) {
+	if len(t.translations) == 0 {
+		return "", nil
+	}
+	for _, tag := range parseAcceptLanguage(acceptLanguage) {
+		if dict, ok := t.translations[tag]; ok {
+			return tag, dict
+		}
+		if base, _, found := strings.Cut(tag, "-"); found {
+			if dict, ok := t.translations[base]; ok {
+				return base, dict
+			}
+		}
+		if tag == "ja" || strings.HasPrefix(tag, "ja-") {
+			return "", nil
+		}
+	}
+	return "", nil
+}
+
+// transform JSONの値のフィールド名を変換し、翻訳対象のフィールドの文字列を翻訳する
+func (t *ResponseTransform) transform(v any, key, naming string, dict map[string]string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, child := range v {
+			name := k
+			if naming == FieldNamingCamel {
+				name = snakeToCamel(k)
+			}
+			out[name] = t.transform(child, k, naming, dict)
+		}
+		return out
+	case []any:
+		for i, child := range v {
+			v[i] = t.transform(child, key, naming, dict)
+		}
+		return v
+	case string:
+		if dict != nil && slices.Contains(t.fields, key) {
+			if translated, ok := dict[v]; ok {
+				return translated
+			}
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// snakeToCamel スネークケースのフィールド名をキャメルケースに変換する（"total_amount" → "totalAmount"）
+func snakeToCamel(name string) string {
+	if !strings.Contains(name, "_") {
+		return name
+	}
+	var b strings.Builder
+	upper := false
+	for i, r := range name {
+		switch {
+		case r == '_' && i > 0:
+			upper = true
+		case upper:
+			b.WriteString(strings.ToUpper(string(r)))
+			upper = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// parseAcceptLanguage Accept-Languageの言語タグを優先度の高い順に返す（q=0は除外）
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		if q > 0 {
+			tags = append(tags, weighted{tag: tag, q: q})
+		}
+	}
+	slices.SortStableFunc(tags, func(a, b weighted) int {
+		switch {
+		case a.q > b.q:
+			return -1
+		case a.q < b.q:
+			return 1
+		}
+		return 0
+	})
+
+	result := make([]string, len(tags))
+	for i, w := range tags {
+		result[i] = w.tag
+	}
+	return result
+}
+
+// transformWriter JSONのレスポンスのみバッファリングするResponseWriter
+// JSON以外（画像・CSV・HTMLなど）はそのまま書き込む
+type transformWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	buffering   bool
+	buf         bytes.Buffer
+}
+
+// WriteHeader Content-TypeがJSONの場合はステータスコードを保持し、変換後に書き込む
+func (tw *transformWriter) WriteHeader(code int) {
+	if tw.wroteHeader {
+		return
+	}
+	tw.wroteHeader = true
+	tw.status = code
+	if strings.HasPrefix(tw.Header().Get("Content-Type"), "application/json") {
+		tw.buffering = true
+		return
+	}
+	tw.ResponseWriter.WriteHeader(code)
+}
+
+// Write JSONの場合はバッファに書き込む
+func (tw *transformWriter) Write(b []byte) (int, error) {
+	if !tw.wroteHeader {
+		tw.WriteHeader(http.StatusOK)
+	}
+	if tw.buffering {
+		return tw.buf.Write(b)
+	}
+	return tw.ResponseWriter.Write(b)
+}
+
+// Unwrap 元のResponseWriterを返す（http.ResponseControllerで書き込み期限などを設定するため）
+func (tw *transformWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
@@ -0,0 +1,236 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// maxSLOSamples ローリングウィンドウに保持する処理結果の上限（超えた場合は古いものから捨てる）
+const maxSLOSamples = 100000
+
+// SLOの通知の種類
+const (
+	SLOEventLatencyBreached      = "slo.latency_breached"       // 処理時間のパーセンタイルが上限を超えた
+	SLOEventErrorBudgetExhausted = "slo.error_budget_exhausted" // エラー率がエラーバジェットを超えた
+	SLOEventRecovered            = "slo.recovered"              // 目標を外れていた指標が回復した
+)
+
+// SLOObjectives レシート処理のSLO（サービスレベル目標）
+type SLOObjectives struct {
+	Window            time.Duration // 集計するローリングウィンドウ
+	LatencyPercentile float64       // 遅延を判定するパーセンタイル（例: 95）
+	LatencyThreshold  time.Duration // パーセンタイルの処理時間の上限
+	SuccessTarget     float64       // 成功率の目標（%）
+	MinRequests       int           // 判定に必要な最小のリクエスト数
+}
+
+// SLOStatus 直近のウィンドウのSLOの状態
+type SLOStatus struct {
+	WindowSeconds        int64   `json:"window_seconds"`
+	Requests             int     `json:"requests"`
+	Errors               int     `json:"errors"`
+	LatencyPercentile    float64 `json:"latency_percentile"`
+	LatencyMs            int64   `json:"latency_ms"` // パーセンタイルの処理時間（ミリ秒）
+	LatencyThresholdMs   int64   `json:"latency_threshold_ms"`
+	SuccessRate          float64 `json:"success_rate"`           // 成功率（%）
+	SuccessTarget        float64 `json:"success_target"`         // 成功率の目標（%）
+	ErrorBudgetRemaining float64 `json:"error_budget_remaining"` // 残りのエラーバジェット（%、負の場合は超過）
+	Evaluated            bool    `json:"evaluated"`              // リクエスト数が最小数以上で判定した
+	LatencyBreached      bool    `json:"latency_breached"`
+	ErrorBudgetExhausted bool    `json:"error_budget_exhausted"`
+}
+
+// sloSample 1件の処理結果
+type sloSample struct {
+	at       time.Time
+	duration time.Duration
+	failed   bool
+}
+
+// SLOTracker レシート処理の処理時間とエラーを記録し、ローリングウィンドウでSLOを判定する
+// 記録はインスタンスごとのメモリに保持する
+type SLOTracker struct {
+	mu              sync.Mutex
+	objectives      SLOObjectives
+	samples         []sloSample
+	notifier        sharedDomain.Notifier
+	latencyAlerting bool
+	budgetAlerting  bool
+	now             func() time.Time
+}
+
+// NewSLOTracker 新しいSLOTrackerを作成
+func NewSLOTracker(objectives SLOObjectives) *SLOTracker {
+	return &SLOTracker{
+		objectives: objectives,
+		now:        time.Now,
+	}
+}
+
+// SetNotifier SLOを外れた・回復した場合の通知先を設定（未設定の場合は判定のみ）
+func (t *SLOTracker) SetNotifier(notifier sharedDomain.Notifier) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.notifier = notifier
+}
+
+// Observe 処理結果を記録
+func (t *SLOTracker) Observe(duration time.Duration, failed bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	t.prune(now)
+	if len(t.samples) >= maxSLOSamples {
+		t.samples = t.samples[1:]
+	}
+	t.samples = append(t.samples, sloSample{at: now, duration: duration, failed: failed})
+}
+
+// Handler 処理時間とエラー（5xx・panic）を記録するミドルウェア
+// 4xxはクライアントの誤りのためエラーバジェットを消費しない
+func (t *SLOTracker) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := t.now()
+		rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		defer func() {
+			if p := recover(); p != nil {
+				t.Observe(t.now().Sub(start), true)
+				panic(p)
+			}
+		}()
+		next.ServeHTTP(rw, r)
+		t.Observe(t.now().Sub(start), rw.statusCode >= http.StatusInternalServerError)
+	})
+}
+
+// Status 直近のウィンドウのSLOの状態を返す
+func (t *SLOTracker) Status() SLOStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.prune(t.now())
+
+	o := t.objectives
+	status := SLOStatus{
+		WindowSeconds:        int64(o.Window / time.Second),
+		Requests:             len(t.samples),
+		LatencyPercentile:    o.LatencyPercentile,
+		LatencyThresholdMs:   o.LatencyThreshold.Milliseconds(),
+		SuccessRate:          100,
+		SuccessTarget:        o.SuccessTarget,
+		ErrorBudgetRemaining: 100,
+	}
+	if status.Requests == 0 {
+		return status
+	}
+
+	durations := make([]time.Duration, len(t.samples))
+	for i, s := range t.samples {
+		durations[i] = s.duration
+		if s.failed {
+			status.Errors++
+		}
+	}
+	slices.Sort(durations)
+	status.LatencyMs = percentile(durations, o.LatencyPercentile).Milliseconds()
+
+	errorRate := float64(status.Errors) / float64(status.Requests)
+	status.SuccessRate = (1 - errorRate) * 100
+	if budget := 1 - o.SuccessTarget/100; budget > 0 {
+		status.ErrorBudgetRemaining = (1 - errorRate/budget) * 100
+	} else if status.Errors > 0 {
+		status.ErrorBudgetRemaining = -100
+	}
+
+	status.Evaluated = status.Requests >= o.MinRequests
+	if status.Evaluated {
+		status.LatencyBreached = o.LatencyThreshold > 0 && status.LatencyMs > o.LatencyThreshold.Milliseconds()
+		status.ErrorBudgetExhausted = status.ErrorBudgetRemaining < 0
+	}
+	return status
+}
+
+// Check SLOを判定し、目標を外れた・回復した場合に通知する（定期実行タスク用）
+// 同じ状態が続く間は再通知しない。リクエスト数が最小数に満たない間は状態を変えない
+func (t *SLOTracker) Check(ctx context.Context) (SLOStatus, error) {
+	status := t.Status()
+	if !status.Evaluated {
+		return status, nil
+	}
+
+	t.mu.Lock()
+	notifier := t.notifier
+	latencyAlerting, budgetAlerting := t.latencyAlerting, t.budgetAlerting
+	t.mu.Unlock()
+
+	if status.LatencyBreached != latencyAlerting {
+		event, message := SLOEventLatencyBreached, fmt.Sprintf("レシート処理のp%gが%dmsで、上限の%dmsを超えています", status.LatencyPercentile, status.LatencyMs, status.LatencyThresholdMs)
+		if !status.LatencyBreached {
+			event, message = SLOEventRecovered, fmt.Sprintf("レシート処理のp%gが%dmsに回復しました", status.LatencyPercentile, status.LatencyMs)
+		}
+		if err := t.notify(ctx, notifier, event, message, status); err != nil {
+			return status, err
+		}
+		t.mu.Lock()
+		t.latencyAlerting = status.LatencyBreached
+		t.mu.Unlock()
+	}
+
+	if status.ErrorBudgetExhausted != budgetAlerting {
+		event, message := SLOEventErrorBudgetExhausted, fmt.Sprintf("レシート処理の成功率が%.2f%%で、目標の%g%%を下回っています", status.SuccessRate, status.SuccessTarget)
+		if !status.ErrorBudgetExhausted {
+			event, message = SLOEventRecovered, fmt.Sprintf("レシート処理の成功率が%.2f%%に回復しました", status.SuccessRate)
+		}
+		if err := t.notify(ctx, notifier, event, message, status); err != nil {
+			return status, err
+		}
+		t.mu.Lock()
+		t.budgetAlerting = status.ErrorBudgetExhausted
+		t.mu.Unlock()
+	}
+
+	return status, nil
+}
+
+// notify SLOの通知を送信（通知先が未設定の場合はログのみ）
+func (t *SLOTracker) notify(ctx context.Context, notifier sharedDomain.Notifier, event, message string, status SLOStatus) error {
+	slog.Warn("SLO status changed", "event", event, "latency_ms", status.LatencyMs, "success_rate", status.SuccessRate, "requests", status.Requests)
+	if notifier == nil {
+		return nil
+	}
+	if err := notifier.Notify(ctx, sharedDomain.Notification{
+		Event:     event,
+		Message:   message,
+		Data:      status,
+		CreatedAt: t.now(),
+	}); err != nil {
+		return fmt.Errorf("failed to send SLO notification: %w", err)
+	}
+	return nil
+}
+
+// prune ウィンドウより古い処理結果を捨てる（呼び出し側でロックを取得する）
+func (t *SLOTracker) prune(now time.Time) {
+	cutoff := now.Add(-t.objectives.Window)
+	i := 0
+	for i < len(t.samples) && !t.samples[i].at.After(cutoff) {
+		i++
+	}
+	if i > 0 {
+		t.samples = slices.Delete(t.samples, 0, i)
+	}
+}
+
+// percentile 昇順に並べた処理時間のパーセンタイル（nearest-rank法）
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	rank = min(max(rank, 1), len(sorted))
+	return sorted[rank-1]
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// timezoneHeader リクエストごとにタイムゾーンを指定するヘッダー（IANA名、例: Asia/Tokyo）
+const timezoneHeader = "X-Timezone"
+
+// Timezone リクエストのタイムゾーンをコンテキストに設定するミドルウェア
+// ?tz= パラメーター、X-Timezone ヘッダー、defaultLocation の順に優先し、不正な名前の場合は400を返す
+func Timezone(defaultLocation *time.Location, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		loc := defaultLocation
+		name := r.URL.Query().Get("tz")
+		if name == "" {
+			name = r.Header.Get(timezoneHeader)
+		}
+		if name != "" {
+			parsed, err := time.LoadLocation(name)
+			if err != nil {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(ErrorResponse{
+					Success: false,
+					Error:   "invalid timezone: " + name,
+				})
+				return
+			}
+			loc = parsed
+		}
+
+		next.ServeHTTP(w, r.WithContext(sharedDomain.WithLocation(r.Context(), loc)))
+	})
+}
@@ -5,45 +5,325 @@ import (
 
 	"vision-api-app/internal/presentation/di"
 	"vision-api-app/internal/presentation/http/middleware"
+	"vision-api-app/internal/presentation/web"
+)
+
+// 機能フラグの名前（features.flags・リモートプロバイダーで指定する）
+const (
+	// FeatureDirectUpload 署名付きURLによる直接アップロード・分割アップロード
+	FeatureDirectUpload = "direct_upload"
 )
 
 // NewRouter 新しいルーターを作成
 func NewRouter(container *di.Container) http.Handler {
 	mux := http.NewServeMux()
 
-	// Web UI ハンドラー
-	webHandler := container.WebHandler()
-	mux.HandleFunc("/", webHandler.HandleUploadPage)
-	mux.HandleFunc("/upload", webHandler.HandleUpload)
-	mux.HandleFunc("/result", webHandler.HandleResult)
-	mux.HandleFunc("/household", webHandler.HandleHousehold)
-
 	// Static files
-	fs := http.FileServer(http.Dir("web/static"))
-	mux.Handle("/static/", http.StripPrefix("/static/", fs))
+	mux.Handle("/static/", web.StaticHandler("/static/", "web/static"))
 
 	// Vision API ハンドラー
 	visionHandler := container.VisionHandler()
 	mux.HandleFunc("/api/v1/vision/analyze", visionHandler.HandleAnalyze)
-	mux.HandleFunc("/api/v1/vision/receipt", visionHandler.HandleReceiptAnalyze)
+	mux.Handle("/api/v1/vision/receipt", observeSLO(container, visionHandler.HandleReceiptAnalyze))
+	// 画像をリクエストボディに直接含める解析（iOSショートカット・curl向け、APIキー認証）
+	mux.Handle("POST /api/v1/vision/receipt/simple", middleware.APIKeyAuth(container.APIKeys(), observeSLO(container, visionHandler.HandleReceiptSimple)))
 	mux.HandleFunc("/api/v1/vision/categorize", visionHandler.HandleCategorize)
+	mux.HandleFunc("POST /api/v1/vision/compare", visionHandler.HandleCompare)
 
-	// Health check
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
+	// レシートの保存を伴うWeb UI・API（MySQL未設定の場合は503を返す）
+	if container.PersistenceEnabled() {
+		registerHouseholdRoutes(mux, container)
+	} else {
+		for _, pattern := range persistenceRoutes {
+			mux.HandleFunc(pattern, middleware.PersistenceRequired)
 		}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(`{"status":"ok","version":"3.0.0"}`))
-	})
+	}
+
+	// Admin API ハンドラー（トークン認証）
+	adminHandler := container.AdminHandler()
+	adminToken := container.AdminToken()
+	mux.Handle("GET /api/v1/admin/maintenance", middleware.AdminAuth(adminToken, http.HandlerFunc(adminHandler.HandleGetMaintenance)))
+	mux.Handle("PUT /api/v1/admin/maintenance", middleware.AdminAuth(adminToken, http.HandlerFunc(adminHandler.HandleUpdateMaintenance)))
+	mux.Handle("GET /api/v1/admin/features", middleware.AdminAuth(adminToken, http.HandlerFunc(adminHandler.HandleGetFeatures)))
+	mux.Handle("GET /api/v1/admin/container", middleware.AdminAuth(adminToken, http.HandlerFunc(adminHandler.HandleGetContainer)))
+	mux.Handle("GET /api/v1/admin/slo", middleware.AdminAuth(adminToken, http.HandlerFunc(adminHandler.HandleGetSLO)))
+	mux.Handle("GET /api/v1/admin/cache/stats", middleware.AdminAuth(adminToken, http.HandlerFunc(adminHandler.HandleGetCacheStats)))
+	mux.Handle("POST /api/v1/admin/repair/totals", middleware.AdminAuth(adminToken, http.HandlerFunc(adminHandler.HandleRepairTotals)))
+	mux.Handle("POST /api/v1/admin/repair/names", middleware.AdminAuth(adminToken, http.HandlerFunc(adminHandler.HandleNormalizeNames)))
+	mux.Handle("POST /api/v1/admin/warehouse/export", middleware.AdminAuth(adminToken, http.HandlerFunc(adminHandler.HandleWarehouseExport)))
+	mux.Handle("GET /api/v1/admin/ai-debug", middleware.AdminAuth(adminToken, http.HandlerFunc(adminHandler.HandleGetAIDebug)))
+	mux.Handle("DELETE /api/v1/admin/ai-debug", middleware.AdminAuth(adminToken, http.HandlerFunc(adminHandler.HandleClearAIDebug)))
+	mux.Handle("GET /api/v1/admin/ai-budget", middleware.AdminAuth(adminToken, http.HandlerFunc(adminHandler.HandleGetAIBudget)))
+	mux.Handle("PUT /api/v1/admin/ai-budget", middleware.AdminAuth(adminToken, http.HandlerFunc(adminHandler.HandleUpdateAIBudget)))
+
+	// ランタイム診断（pprof・expvar、別ポートを指定しない場合は管理APIと同じトークン認証）
+	if diagnostics := container.Diagnostics(); diagnostics.Enabled && diagnostics.Address == "" {
+		mux.Handle("/debug/", middleware.AdminAuth(adminToken, NewDiagnosticsHandler()))
+	}
+
+	// Health check / Readiness check
+	healthHandler := container.HealthHandler()
+	mux.HandleFunc("/health", healthHandler.HandleHealth)
+	mux.HandleFunc("GET /ready", healthHandler.HandleReady)
 
 	// ミドルウェアの適用
 	var h http.Handler = mux
+	if cacheStats := container.CacheStats(); cacheStats.Enabled {
+		h = middleware.CacheScope(mux, cacheStats.TenantHeader, h)
+	}
 	h = middleware.Recovery(h)
+	if responseCache := container.ResponseCache(); responseCache != nil {
+		h = responseCache.Invalidate(h)
+	}
+	h = middleware.Timezone(container.Location(), h)
+	h = container.Maintenance().Handler(h)
+	h = container.ResponseTransform().Handler(h)
+	if loadShedder := container.LoadShedder(); loadShedder != nil {
+		h = loadShedder.Handler(h)
+	}
 	h = middleware.LoggerWithHealthCheck(h)
+	h = container.RealIP().Handler(h)
 	h = middleware.CORS(h)
+	if !container.PersistenceEnabled() {
+		h = middleware.PersistenceDisabled(h)
+	}
 
 	return h
 }
+
+// observeSLO レシート処理のハンドラーの処理時間・エラーをSLOとして記録する（SLOが無効な場合はそのまま）
+func observeSLO(container *di.Container, handler http.HandlerFunc) http.Handler {
+	if slo := container.SLO(); slo != nil {
+		return slo.Handler(handler)
+	}
+	return handler
+}
+
+// cacheReport レポートのレスポンスをキャッシュする（キャッシュが無効な場合はそのまま）
+func cacheReport(container *di.Container, handler http.HandlerFunc) http.Handler {
+	if responseCache := container.ResponseCache(); responseCache != nil {
+		return responseCache.Handler(handler)
+	}
+	return handler
+}
+
+// persistenceRoutes レシートの保存が無効な構成で503を返すパス
+var persistenceRoutes = []string{
+	"/{$}",
+	"/upload",
+	"/result",
+	"/household",
+	"/reports",
+	"/app/",
+	"/api/v1/vision/receipt/extract",
+	"/api/v1/receipts",
+	"/api/v1/receipts/",
+	"/api/v1/uploads/",
+	"/api/v1/expenses",
+	"/api/v1/expenses/",
+	"/api/v1/reports/",
+	"/api/v1/usage/",
+	"/api/v1/categories",
+	"/api/v1/categories/",
+	"/api/v1/items/",
+	"/api/v1/suggestions/",
+	"/api/v1/public/",
+	"/api/v1/warranties/",
+	"/api/v1/splits",
+	"/api/v1/trash",
+	"/api/v1/trash/",
+	"/api/v1/accounting/",
+	"/api/v1/reconciliations",
+	"/api/v1/documents",
+	"/api/v1/collections",
+	"/api/v1/collections/",
+	"/api/v1/expense-reports",
+	"/api/v1/expense-reports/",
+	"/api/v1/webhooks/",
+	"/api/v1/backfills",
+	"/api/v1/backfills/",
+}
+
+// registerHouseholdRoutes レシートの保存を伴うWeb UI・APIのルートを登録
+func registerHouseholdRoutes(mux *http.ServeMux, container *di.Container) {
+	features := container.FeatureFlags()
+
+	// Web UI ハンドラー（サーバーレンダリング）
+	webHandler := container.WebHandler()
+	mux.HandleFunc("/", webHandler.HandleUploadPage)
+	mux.HandleFunc("GET /upload", webHandler.HandleUploadPage)
+	mux.Handle("POST /upload", observeSLO(container, webHandler.HandleUpload))
+	mux.HandleFunc("/result", webHandler.HandleResult)
+	mux.HandleFunc("/household", webHandler.HandleHousehold)
+	mux.HandleFunc("/reports", webHandler.HandleReports)
+
+	// 埋め込みSPA（有効な場合はトップページをSPAにする）
+	if spaHandler := container.SPAHandler(); spaHandler != nil {
+		mux.HandleFunc("GET /{$}", spaHandler.HandleIndex)
+		mux.Handle("GET /app/", web.CacheControl(web.StaticMaxAge, spaHandler.AssetHandler("/app/")))
+	}
+
+	// Receipt API ハンドラー
+	receiptHandler := container.ReceiptHandler()
+	mux.HandleFunc("GET /api/v1/receipts", receiptHandler.HandleList)
+	mux.Handle("POST /api/v1/receipts", observeSLO(container, receiptHandler.HandleCreate))
+	mux.HandleFunc("GET /api/v1/receipts/needs-review", receiptHandler.HandleListNeedsReview)
+	mux.HandleFunc("GET /api/v1/receipts/{id}", receiptHandler.HandleGet)
+	mux.HandleFunc("PUT /api/v1/receipts/{id}", receiptHandler.HandlePut)
+	mux.HandleFunc("PATCH /api/v1/receipts/{id}", receiptHandler.HandlePatch)
+	mux.HandleFunc("GET /api/v1/receipts/{id}/history", receiptHandler.HandleGetHistory)
+	mux.HandleFunc("POST /api/v1/receipts/{id}/revert", receiptHandler.HandleRevert)
+	mux.Handle("POST /api/v1/receipts/{id}/reprocess", observeSLO(container, receiptHandler.HandleReprocess))
+	mux.HandleFunc("GET /api/v1/receipts/{id}/extraction", receiptHandler.HandleGetExtraction)
+	mux.HandleFunc("GET /api/v1/usage/storage", receiptHandler.HandleStorageUsage)
+
+	// Draft API ハンドラー（読み取り結果を確認・修正してから保存する2段階の登録）
+	draftHandler := container.DraftHandler()
+	mux.Handle("POST /api/v1/vision/receipt/extract", observeSLO(container, draftHandler.HandleExtract))
+	mux.HandleFunc("POST /api/v1/receipts/confirm", draftHandler.HandleConfirm)
+
+	// Merge API ハンドラー（二重に登録したレシートの統合と取り消し）
+	mergeHandler := container.MergeHandler()
+	mux.HandleFunc("POST /api/v1/receipts/merge", mergeHandler.HandleMerge)
+	mux.HandleFunc("GET /api/v1/receipts/{id}/merges", mergeHandler.HandleList)
+	mux.HandleFunc("POST /api/v1/receipts/{id}/unmerge", mergeHandler.HandleUnmerge)
+
+	// Trash API ハンドラー（削除したレシート・家計簿エントリは保持期限までゴミ箱から元に戻せる）
+	trashHandler := container.TrashHandler()
+	mux.HandleFunc("DELETE /api/v1/receipts/{id}", trashHandler.HandleDeleteReceipt)
+	mux.HandleFunc("DELETE /api/v1/expenses/{id}", trashHandler.HandleDeleteExpense)
+	mux.HandleFunc("GET /api/v1/trash", trashHandler.HandleList)
+	mux.HandleFunc("POST /api/v1/trash/restore", trashHandler.HandleRestore)
+	mux.HandleFunc("POST /api/v1/trash/purge", trashHandler.HandlePurge)
+
+	// Interchange API ハンドラー（セルフホストからクラウドへの移行など、インスタンス間でレシートを移す）
+	interchangeHandler := container.InterchangeHandler()
+	mux.HandleFunc("GET /api/v1/receipts/{id}/export", interchangeHandler.HandleExport)
+	mux.HandleFunc("POST /api/v1/receipts/import", interchangeHandler.HandleImport)
+
+	// Backfill API ハンドラー（保存済みの元画像をバッチ処理でまとめて読み直す。バッチ処理に対応したAIプロバイダーのみ）
+	if backfillHandler := container.BackfillHandler(); backfillHandler != nil {
+		mux.HandleFunc("POST /api/v1/backfills", backfillHandler.HandleStart)
+		mux.HandleFunc("GET /api/v1/backfills/{id}", backfillHandler.HandleGet)
+	}
+
+	// LINE Webhook ハンドラー（トークで送ったレシートの写真を登録して結果を返信）
+	if lineHandler := container.LineHandler(); lineHandler != nil {
+		mux.HandleFunc("POST /api/v1/webhooks/line", lineHandler.HandleWebhook)
+	}
+
+	// Category API ハンドラー（カテゴリーの管理、カテゴリー別のレシート・明細）
+	categoryHandler := container.CategoryHandler()
+	mux.HandleFunc("GET /api/v1/categories", categoryHandler.HandleList)
+	mux.HandleFunc("POST /api/v1/categories", categoryHandler.HandleCreate)
+	mux.HandleFunc("PUT /api/v1/categories/{id}", categoryHandler.HandlePut)
+	mux.HandleFunc("DELETE /api/v1/categories/{id}", categoryHandler.HandleDelete)
+	mux.HandleFunc("GET /api/v1/categories/{name}/receipts", categoryHandler.HandleListReceipts)
+	mux.HandleFunc("GET /api/v1/categories/{name}/items", categoryHandler.HandleListItems)
+
+	// Item API ハンドラー（商品の価格推移と、管理者が登録する商品名の別名辞書）
+	itemHandler := container.ItemHandler()
+	mux.HandleFunc("GET /api/v1/items/price-history", itemHandler.HandlePriceHistory)
+	adminToken := container.AdminToken()
+	mux.Handle("GET /api/v1/items/aliases", middleware.AdminAuth(adminToken, http.HandlerFunc(itemHandler.HandleListAliases)))
+	mux.Handle("PUT /api/v1/items/aliases", middleware.AdminAuth(adminToken, http.HandlerFunc(itemHandler.HandlePutAlias)))
+	mux.Handle("DELETE /api/v1/items/aliases/{alias...}", middleware.AdminAuth(adminToken, http.HandlerFunc(itemHandler.HandleDeleteAlias)))
+
+	// Warranty API ハンドラー（返品・保証期限）
+	warrantyHandler := container.WarrantyHandler()
+	mux.HandleFunc("GET /api/v1/warranties/expiring", warrantyHandler.HandleListExpiring)
+
+	// Calendar API ハンドラー（返品・保証期限のiCalendarのフィード、購読URLの発行は管理者のみ）
+	calendarHandler := container.CalendarHandler()
+	mux.Handle("POST /api/v1/warranties/calendar/token", middleware.AdminAuth(adminToken, http.HandlerFunc(calendarHandler.HandleIssueFeed)))
+	mux.HandleFunc("GET /api/v1/warranties/calendar.ics", calendarHandler.HandleFeed)
+
+	// Split API ハンドラー（割り勘と精算状態）
+	splitHandler := container.SplitHandler()
+	mux.HandleFunc("PUT /api/v1/receipts/{id}/split", splitHandler.HandlePut)
+	mux.HandleFunc("GET /api/v1/receipts/{id}/split", splitHandler.HandleGet)
+	mux.HandleFunc("DELETE /api/v1/receipts/{id}/split", splitHandler.HandleDelete)
+	mux.HandleFunc("PATCH /api/v1/receipts/{id}/split/participants/{name}", splitHandler.HandleSettle)
+	mux.HandleFunc("GET /api/v1/splits", splitHandler.HandleListUnsettled)
+
+	// Accounting API ハンドラー（freee / マネーフォワード クラウド会計との同期）
+	accountingHandler := container.AccountingHandler()
+	mux.HandleFunc("GET /api/v1/receipts/{id}/sync", accountingHandler.HandleGetStatus)
+	mux.HandleFunc("POST /api/v1/receipts/{id}/sync/{provider}", accountingHandler.HandleSync)
+	mux.HandleFunc("POST /api/v1/receipts/{id}/sync/{provider}/resolve", accountingHandler.HandleResolve)
+	mux.HandleFunc("GET /api/v1/accounting/{provider}/syncs", accountingHandler.HandleList)
+
+	// Reconciliation API ハンドラー（銀行・クレジットカードの利用明細との突き合わせ）
+	reconciliationHandler := container.ReconciliationHandler()
+	mux.HandleFunc("POST /api/v1/reconciliations", reconciliationHandler.HandleReconcile)
+
+	// Document API ハンドラー（レシートの画像は登録、利用明細のCSVは突き合わせに自動で振り分け）
+	mux.HandleFunc("POST /api/v1/documents", container.DocumentHandler().HandleCreate)
+
+	// Collection API ハンドラー（目的ごとにレシートをまとめ、まとめたレシートだけで書き出し・集計する）
+	collectionHandler := container.CollectionHandler()
+	mux.HandleFunc("GET /api/v1/collections", collectionHandler.HandleList)
+	mux.HandleFunc("POST /api/v1/collections", collectionHandler.HandleCreate)
+	mux.HandleFunc("GET /api/v1/collections/{id}", collectionHandler.HandleGet)
+	mux.HandleFunc("PATCH /api/v1/collections/{id}", collectionHandler.HandlePatch)
+	mux.HandleFunc("DELETE /api/v1/collections/{id}", collectionHandler.HandleDelete)
+	mux.HandleFunc("GET /api/v1/collections/{id}/receipts", collectionHandler.HandleListReceipts)
+	mux.HandleFunc("POST /api/v1/collections/{id}/receipts", collectionHandler.HandleAddReceipts)
+	mux.HandleFunc("DELETE /api/v1/collections/{id}/receipts/{receipt_id}", collectionHandler.HandleRemoveReceipt)
+	mux.HandleFunc("GET /api/v1/collections/{id}/report", collectionHandler.HandleReport)
+	mux.HandleFunc("GET /api/v1/collections/{id}/export", collectionHandler.HandleExport)
+	mux.HandleFunc("GET /api/v1/receipts/{id}/collections", collectionHandler.HandleListByReceipt)
+
+	// Direct Upload API ハンドラー（署名付きURL、機能フラグ: direct_upload）
+	uploadHandler := container.UploadHandler()
+	mux.Handle("POST /api/v1/uploads/presign", features.Require(FeatureDirectUpload, http.HandlerFunc(uploadHandler.HandlePresign)))
+	mux.Handle("PUT /api/v1/uploads/{id}", features.Require(FeatureDirectUpload, http.HandlerFunc(uploadHandler.HandleUpload)))
+	mux.Handle("GET /api/v1/uploads/{id}", features.Require(FeatureDirectUpload, http.HandlerFunc(uploadHandler.HandleStatus)))
+	mux.Handle("POST /api/v1/uploads/{id}/complete", features.Require(FeatureDirectUpload, observeSLO(container, uploadHandler.HandleComplete)))
+
+	// Expense API ハンドラー
+	expenseHandler := container.ExpenseHandler()
+	mux.HandleFunc("GET /api/v1/expenses", expenseHandler.HandleList)
+	mux.HandleFunc("POST /api/v1/expenses", expenseHandler.HandleCreate)
+	mux.HandleFunc("GET /api/v1/expenses/{id}", expenseHandler.HandleGet)
+	mux.HandleFunc("PUT /api/v1/expenses/{id}", expenseHandler.HandlePut)
+	mux.HandleFunc("POST /api/v1/expenses/categorize", expenseHandler.HandleCategorize)
+	mux.Handle("GET /api/v1/expenses/summary", cacheReport(container, expenseHandler.HandleSummary))
+	mux.HandleFunc("PATCH /api/v1/expenses/{id}", expenseHandler.HandlePatch)
+
+	// Report API ハンドラー（レスポンスを短時間キャッシュし、更新系のリクエストで無効化する）
+	reportHandler := container.ReportHandler()
+	mux.Handle("GET /api/v1/reports/monthly", cacheReport(container, reportHandler.HandleMonthly))
+	mux.Handle("GET /api/v1/reports/summary", cacheReport(container, reportHandler.HandleSummary))
+	mux.Handle("GET /api/v1/reports/calendar", cacheReport(container, reportHandler.HandleCalendar))
+	mux.Handle("GET /api/v1/reports/medical-deduction", cacheReport(container, reportHandler.HandleMedicalDeduction))
+	mux.Handle("GET /api/v1/reports/ledger", cacheReport(container, reportHandler.HandleLedger))
+	mux.Handle("GET /api/v1/reports/widget", cacheReport(container, container.WidgetHandler().HandleWidget))
+	mux.Handle("GET /api/v1/reports/charts/categories", cacheReport(container, reportHandler.HandleCategoryChart))
+	mux.Handle("GET /api/v1/reports/charts/monthly", cacheReport(container, reportHandler.HandleMonthlyChart))
+	// 明細のエクスポートは書き出しながら返すため、レスポンスをキャッシュしない
+	mux.HandleFunc("GET /api/v1/reports/export", reportHandler.HandleExport)
+
+	// Suggestion API ハンドラー（購入パターンの分析）
+	suggestionHandler := container.SuggestionHandler()
+	mux.HandleFunc("GET /api/v1/suggestions/shopping-list", suggestionHandler.HandleShoppingList)
+
+	// Public Stats API ハンドラー（地域別の平均の食費、集計値の提供はAPIキーで認証）
+	if publicStatsHandler := container.PublicStatsHandler(); publicStatsHandler != nil {
+		mux.Handle("GET /api/v1/public/stats/spend", cacheReport(container, publicStatsHandler.HandleGetSpendStats))
+		mux.Handle("POST /api/v1/public/stats/contributions", middleware.APIKeyAuth(container.APIKeys(), http.HandlerFunc(publicStatsHandler.HandleContribute)))
+	}
+
+	// Expense Report API ハンドラー（会社の経費報告書、承認・差し戻し・精算は承認者のキーで認証）
+	expenseReportHandler := container.ExpenseReportHandler()
+	mux.HandleFunc("GET /api/v1/expense-reports", expenseReportHandler.HandleList)
+	mux.HandleFunc("POST /api/v1/expense-reports", expenseReportHandler.HandleCreate)
+	mux.HandleFunc("GET /api/v1/expense-reports/{id}", expenseReportHandler.HandleGet)
+	mux.HandleFunc("PATCH /api/v1/expense-reports/{id}", expenseReportHandler.HandlePatch)
+	mux.HandleFunc("DELETE /api/v1/expense-reports/{id}", expenseReportHandler.HandleDelete)
+	mux.HandleFunc("GET /api/v1/expense-reports/{id}/pdf", expenseReportHandler.HandlePDF)
+	mux.HandleFunc("POST /api/v1/expense-reports/{id}/submit", expenseReportHandler.HandleSubmit)
+	mux.HandleFunc("POST /api/v1/expense-reports/{id}/approve", expenseReportHandler.HandleApprove)
+	mux.HandleFunc("POST /api/v1/expense-reports/{id}/reject", expenseReportHandler.HandleReject)
+	mux.HandleFunc("POST /api/v1/expense-reports/{id}/reimburse", expenseReportHandler.HandleReimburse)
+}
@@ -0,0 +1,62 @@
+package servertls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"vision-api-app/internal/config"
+)
+
+// ErrClientNotAllowed クライアント証明書のCNが許可されていない
+var ErrClientNotAllowed = errors.New("client certificate common name is not allowed")
+
+// NewConfig APIサーバーのHTTPSの設定を作成
+// client_authを有効にした場合は、ca_fileのCAが発行したクライアント証明書を要求し、allowed_cnsを指定した場合はCNも検証する
+// 検証はTLSのハンドシェイクで行うため、許可されていないクライアントはHTTPのリクエストを送る前に切断される
+func NewConfig(cfg *config.ServerTLSConfig) (*tls.Config, error) {
+	certificate, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if !cfg.ClientAuth.Enabled {
+		return tlsConfig, nil
+	}
+
+	pem, err := os.ReadFile(cfg.ClientAuth.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client ca file: %w", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in client ca file %s", cfg.ClientAuth.CAFile)
+	}
+	tlsConfig.ClientCAs = clientCAs
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	if len(cfg.ClientAuth.AllowedCNs) > 0 {
+		allowed := slices.Clone(cfg.ClientAuth.AllowedCNs)
+		tlsConfig.VerifyConnection = func(state tls.ConnectionState) error {
+			return verifyCommonName(state, allowed)
+		}
+	}
+	return tlsConfig, nil
+}
+
+// verifyCommonName 検証済みのクライアント証明書のCNが許可されているか確認
+func verifyCommonName(state tls.ConnectionState, allowed []string) error {
+	if len(state.PeerCertificates) == 0 {
+		return ErrClientNotAllowed
+	}
+	commonName := state.PeerCertificates[0].Subject.CommonName
+	if !slices.Contains(allowed, commonName) {
+		return fmt.Errorf("%w: %q", ErrClientNotAllowed, commonName)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package servertls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"vision-api-app/internal/config"
+)
+
+// testCA テスト用の証明書を発行するCA
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate() error = %v", err)
+	}
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue CNを指定して証明書を発行し、証明書と秘密鍵のPEMを返す
+func (ca *testCA) issue(t *testing.T, commonName string, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey() error = %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return path
+}
+
+func TestNewConfig_ClientAuth(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	otherCA := newTestCA(t)
+	serverCert, serverKey := ca.issue(t, "vision-api", x509.ExtKeyUsageServerAuth)
+
+	tlsConfig, err := NewConfig(&config.ServerTLSConfig{
+		Enabled:  true,
+		CertFile: writeFile(t, dir, "server.pem", serverCert),
+		KeyFile:  writeFile(t, dir, "server-key.pem", serverKey),
+		ClientAuth: config.ClientAuthConfig{
+			Enabled:    true,
+			CAFile:     writeFile(t, dir, "ca.pem", ca.pem),
+			AllowedCNs: []string{"billing-service"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewConfig() error = %v", err)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	server.TLS = tlsConfig
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(ca.cert)
+
+	tests := []struct {
+		name    string
+		issuer  *testCA
+		cn      string
+		wantErr bool
+	}{
+		{name: "正常系: 許可したCNのクライアント証明書", issuer: ca, cn: "billing-service"},
+		{name: "異常系: 許可していないCN", issuer: ca, cn: "batch-worker", wantErr: true},
+		{name: "異常系: 別のCAが発行した証明書", issuer: otherCA, cn: "billing-service", wantErr: true},
+		{name: "異常系: クライアント証明書なし", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientTLS := &tls.Config{RootCAs: rootCAs}
+			if tt.issuer != nil {
+				certPEM, keyPEM := tt.issuer.issue(t, tt.cn, x509.ExtKeyUsageClientAuth)
+				certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+				if err != nil {
+					t.Fatalf("X509KeyPair() error = %v", err)
+				}
+				clientTLS.Certificates = []tls.Certificate{certificate}
+			}
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: clientTLS}}
+
+			resp, err := client.Get(server.URL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				_ = resp.Body.Close()
+				if resp.StatusCode != http.StatusNoContent {
+					t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNoContent)
+				}
+			}
+		})
+	}
+}
+
+func TestNewConfig_Errors(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	serverCert, serverKey := ca.issue(t, "vision-api", x509.ExtKeyUsageServerAuth)
+	certFile := writeFile(t, dir, "server.pem", serverCert)
+	keyFile := writeFile(t, dir, "server-key.pem", serverKey)
+
+	tests := []struct {
+		name string
+		cfg  config.ServerTLSConfig
+	}{
+		{name: "異常系: サーバー証明書がない", cfg: config.ServerTLSConfig{CertFile: filepath.Join(dir, "missing.pem"), KeyFile: keyFile}},
+		{name: "異常系: クライアント証明書のCAがない", cfg: config.ServerTLSConfig{CertFile: certFile, KeyFile: keyFile, ClientAuth: config.ClientAuthConfig{Enabled: true, CAFile: filepath.Join(dir, "missing-ca.pem")}}},
+		{name: "異常系: CAのファイルに証明書がない", cfg: config.ServerTLSConfig{CertFile: certFile, KeyFile: keyFile, ClientAuth: config.ClientAuthConfig{Enabled: true, CAFile: writeFile(t, dir, "empty.pem", []byte("not a certificate"))}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewConfig(&tt.cfg); err == nil {
+				t.Error("NewConfig() error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package spa
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io/fs"
+	"net/http"
+	"regexp"
+
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+// currencyMetaPattern index.htmlの金額の通貨を指定するmetaタグ
+var currencyMetaPattern = regexp.MustCompile(`<meta name="currency"[^>]*>`)
+
+// Handler 埋め込みSPAを配信するハンドラー
+type Handler struct {
+	files fs.FS
+	index []byte
+}
+
+// NewHandler 新しいHandlerを作成（filesのルートにindex.htmlが必要）
+func NewHandler(files fs.FS) (*Handler, error) {
+	index, err := fs.ReadFile(files, "index.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SPA index: %w", err)
+	}
+
+	return &Handler{
+		files: files,
+		index: index,
+	}, nil
+}
+
+// SetCurrency index.htmlのmetaタグに金額の通貨を埋め込む（SPAは金額を最小単位の整数で受け取り、この通貨で表示する）
+func (h *Handler) SetCurrency(currency sharedDomain.Currency) {
+	meta := fmt.Sprintf(`<meta name="currency" content="%s" data-digits="%d" data-symbol="%s">`,
+		html.EscapeString(currency.Code), currency.Digits, html.EscapeString(currency.Symbol))
+	h.index = currencyMetaPattern.ReplaceAllLiteral(bytes.Clone(h.index), []byte(meta))
+}
+
+// HandleIndex SPAのエントリーポイントを返す
+// 更新後のJavaScript・CSSを確実に読み込ませるため、index.htmlは毎回再検証させる
+func (h *Handler) HandleIndex(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(h.index)
+}
+
+// AssetHandler SPAのJavaScript・CSSを返すハンドラー（prefixを除いたパスで参照）
+func (h *Handler) AssetHandler(prefix string) http.Handler {
+	return http.StripPrefix(prefix, http.FileServer(http.FS(h.files)))
+}
@@ -0,0 +1,91 @@
+package spa
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+)
+
+func TestHandler(t *testing.T) {
+	files := fstest.MapFS{
+		"index.html": {Data: []byte("<html>index</html>")},
+		"app.js":     {Data: []byte("console.log('app')")},
+	}
+
+	h, err := NewHandler(files)
+	if err != nil {
+		t.Fatalf("NewHandler() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		handler    http.Handler
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "正常系: index",
+			handler:    http.HandlerFunc(h.HandleIndex),
+			path:       "/",
+			wantStatus: http.StatusOK,
+			wantBody:   "index",
+		},
+		{
+			name:       "正常系: asset",
+			handler:    h.AssetHandler("/app/"),
+			path:       "/app/app.js",
+			wantStatus: http.StatusOK,
+			wantBody:   "console.log",
+		},
+		{
+			name:       "異常系: missing asset",
+			handler:    h.AssetHandler("/app/"),
+			path:       "/app/missing.js",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status code = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %s, want to contain %s", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestNewHandler_MissingIndex(t *testing.T) {
+	if _, err := NewHandler(fstest.MapFS{}); err == nil {
+		t.Error("Expected error for missing index.html")
+	}
+}
+
+func TestHandler_SetCurrency(t *testing.T) {
+	files := fstest.MapFS{
+		"index.html": {Data: []byte(`<head><meta name="currency" content="JPY" data-digits="0" data-symbol="¥"></head>`)},
+	}
+	h, err := NewHandler(files)
+	if err != nil {
+		t.Fatalf("NewHandler() error = %v", err)
+	}
+	usd, _ := sharedDomain.LookupCurrency("USD")
+	h.SetCurrency(usd)
+
+	rec := httptest.NewRecorder()
+	h.HandleIndex(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	want := `<meta name="currency" content="USD" data-digits="2" data-symbol="$">`
+	if !strings.Contains(rec.Body.String(), want) {
+		t.Errorf("body = %s, want to contain %s", rec.Body.String(), want)
+	}
+}
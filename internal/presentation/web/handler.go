@@ -0,0 +1,255 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"vision-api-app/internal/modules/household/domain/entity"
+	"vision-api-app/internal/modules/household/domain/repository"
+	"vision-api-app/internal/modules/household/usecase"
+	sharedDomain "vision-api-app/internal/modules/shared/domain"
+	"vision-api-app/internal/modules/shared/presentation/bufpool"
+)
+
+// templateDir テンプレートの配置ディレクトリ
+var templateDir = filepath.Join("web", "templates")
+
+// Handler サーバーレンダリングのWeb画面ハンドラー
+type Handler struct {
+	receiptUseCase       *usecase.ReceiptUseCase
+	householdUseCase     *usecase.HouseholdUseCase
+	medicalReportUseCase *usecase.MedicalReportUseCase
+	templates            map[string]*template.Template
+}
+
+// NewHandler 新しいHandlerを作成
+func NewHandler(receiptUseCase *usecase.ReceiptUseCase, householdUseCase *usecase.HouseholdUseCase, medicalReportUseCase *usecase.MedicalReportUseCase) (*Handler, error) {
+	// カスタム関数を定義
+	currency := receiptUseCase.Currency()
+	funcMap := template.FuncMap{
+		// money 最小単位の金額を通貨の記号と3桁区切りで表示
+		"money": func(amount int64) string {
+			return currency.Display(amount)
+		},
+	}
+
+	// テンプレートを事前にパース（共通レイアウト + 画面ごとのページ）
+	templates := make(map[string]*template.Template)
+	for _, page := range []string{"upload", "result", "household", "reports"} {
+		tmpl, err := template.New("base.html").Funcs(funcMap).ParseFiles(
+			filepath.Join(templateDir, "layout", "base.html"),
+			filepath.Join(templateDir, "layout", "header.html"),
+			filepath.Join(templateDir, "layout", "footer.html"),
+			filepath.Join(templateDir, "pages", page+".html"),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s template: %w", page, err)
+		}
+		templates[page] = tmpl
+	}
+
+	return &Handler{
+		receiptUseCase:       receiptUseCase,
+		householdUseCase:     householdUseCase,
+		medicalReportUseCase: medicalReportUseCase,
+		templates:            templates,
+	}, nil
+}
+
+// HandleUploadPage アップロード画面を表示
+func (h *Handler) HandleUploadPage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	h.render(w, "upload", map[string]interface{}{
+		"Title": "レシート登録",
+	})
+}
+
+// HandleUpload 画像アップロード処理
+func (h *Handler) HandleUpload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// マルチパートフォームのパース
+	if err := r.ParseMultipartForm(10 << 20); err != nil { // 10MB制限
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	// 画像ファイルの取得
+	file, header, err := r.FormFile("image")
+	if err != nil {
+		http.Error(w, "Image file is required", http.StatusBadRequest)
+		return
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	// 画像データの読み込み（バッファはレスポンスを返した後にプールへ戻す）
+	buf, err := bufpool.ReadAll(file, header.Size)
+	if err != nil {
+		http.Error(w, "Failed to read image", http.StatusInternalServerError)
+		return
+	}
+	defer buf.Release()
+	imageData := buf.Bytes()
+
+	// レシート処理（タグはカンマ区切り、メモは自由記入で任意指定）
+	receipt, err := h.receiptUseCase.ProcessReceiptImageWithOptions(r.Context(), imageData, usecase.ProcessOptions{
+		Tags: parseTagList(r.FormValue("tags")),
+		Memo: r.FormValue("memo"),
+	})
+	if errors.Is(err, sharedDomain.ErrFileInfected) {
+		http.Error(w, "ウイルス検査で問題が検出されたため、ファイルを受け付けられません", http.StatusUnprocessableEntity)
+		return
+	}
+	if errors.Is(err, sharedDomain.ErrStorageQuotaExceeded) {
+		http.Error(w, "画像の保存容量の上限に達したため、レシートを登録できません。古いレシートを削除するか、管理者に上限の引き上げを依頼してください", http.StatusInsufficientStorage)
+		return
+	}
+	if errors.Is(err, usecase.ErrInvalidReceipt) {
+		http.Error(w, fmt.Sprintf("レシートの読み取り結果が不正なため登録できません: %v", err), http.StatusUnprocessableEntity)
+		return
+	}
+	// データベース障害中に一時保管した場合も、結果画面で内容を確認できる
+	if err != nil && !errors.Is(err, usecase.ErrSavePending) {
+		http.Error(w, fmt.Sprintf("レシート認識に失敗しました: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	// 結果画面にリダイレクト
+	http.Redirect(w, r, fmt.Sprintf("/result?id=%s", receipt.ID), http.StatusSeeOther)
+}
+
+// HandleResult 結果表示画面
+func (h *Handler) HandleResult(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// IDパラメータの取得
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "ID is required", http.StatusBadRequest)
+		return
+	}
+
+	// レシート取得
+	receipt, err := h.receiptUseCase.GetReceipt(r.Context(), id)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("レシートが見つかりません: %v", err), http.StatusNotFound)
+		return
+	}
+
+	h.render(w, "result", map[string]interface{}{
+		"Title":   "レシート詳細",
+		"Receipt": receipt,
+	})
+}
+
+// HandleHousehold 家計簿一覧画面
+func (h *Handler) HandleHousehold(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// タグによる絞り込み（任意）
+	tag := r.URL.Query().Get("tag")
+
+	// レシート一覧を取得
+	receipts, err := h.receiptUseCase.SearchReceipts(r.Context(), repository.ReceiptFilter{Tag: tag}, 100, 0)
+	if err != nil {
+		http.Error(w, "Failed to get receipts", http.StatusInternalServerError)
+		return
+	}
+
+	// カテゴリ別集計を取得
+	summary, err := h.householdUseCase.GetCategorySummaryByTag(r.Context(), tag)
+	if err != nil {
+		http.Error(w, "Failed to get category summary", http.StatusInternalServerError)
+		return
+	}
+
+	h.render(w, "household", map[string]interface{}{
+		"Title":           "家計簿一覧",
+		"Tag":             tag,
+		"Receipts":        receipts,
+		"CategorySummary": summary,
+	})
+}
+
+// HandleReports レポート画面（月別集計・医療費控除）
+func (h *Handler) HandleReports(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	year := time.Now().Year()
+	if v := r.URL.Query().Get("year"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1900 || n > 9999 {
+			http.Error(w, fmt.Sprintf("invalid year: %s", v), http.StatusBadRequest)
+			return
+		}
+		year = n
+	}
+
+	monthly, err := h.householdUseCase.GetMonthlySummary(r.Context(), year, 0)
+	if err != nil {
+		http.Error(w, "Failed to get monthly summary", http.StatusInternalServerError)
+		return
+	}
+
+	var yearTotal int64
+	for _, month := range monthly {
+		yearTotal += month.Total
+	}
+
+	medical, err := h.medicalReportUseCase.GenerateReport(r.Context(), year)
+	if err != nil {
+		http.Error(w, "Failed to generate medical deduction report", http.StatusInternalServerError)
+		return
+	}
+
+	h.render(w, "reports", map[string]interface{}{
+		"Title":     "レポート",
+		"Year":      year,
+		"PrevYear":  year - 1,
+		"NextYear":  year + 1,
+		"Monthly":   monthly,
+		"YearTotal": yearTotal,
+		"Medical":   medical,
+	})
+}
+
+// render テンプレートを描画（集計結果が変わるため画面はキャッシュさせない）
+func (h *Handler) render(w http.ResponseWriter, page string, data map[string]interface{}) {
+	w.Header().Set("Cache-Control", "no-cache")
+	if err := h.templates[page].ExecuteTemplate(w, "base.html", data); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to render template: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// parseTagList カンマ区切りのタグ文字列を解析
+func parseTagList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return entity.NormalizeTags(strings.Split(value, ","))
+}
@@ -0,0 +1,53 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// StaticMaxAge 静的ファイル（CSS・JavaScript）をブラウザにキャッシュさせる期間
+	StaticMaxAge = time.Hour
+)
+
+// StaticHandler ディレクトリ内の静的ファイルを配信するハンドラー（prefixを除いたパスで参照）
+func StaticHandler(prefix, dir string) http.Handler {
+	return CacheControl(StaticMaxAge, http.StripPrefix(prefix, http.FileServer(http.Dir(dir))))
+}
+
+// CacheControl 正常なレスポンスにキャッシュヘッダーを付与するミドルウェア
+// 期限切れ後はLast-Modified・ETagによる条件付きリクエストで再検証させる
+func CacheControl(maxAge time.Duration, next http.Handler) http.Handler {
+	value := fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds()))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(&cacheControlWriter{ResponseWriter: w, value: value}, r)
+	})
+}
+
+// cacheControlWriter ステータスコードが確定した時点でキャッシュヘッダーを設定するラッパー
+// エラーレスポンスはキャッシュさせない
+type cacheControlWriter struct {
+	http.ResponseWriter
+	value       string
+	wroteHeader bool
+}
+
+func (w *cacheControlWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if code < http.StatusBadRequest {
+			w.Header().Set("Cache-Control", w.value)
+		} else {
+			w.Header().Set("Cache-Control", "no-store")
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *cacheControlWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
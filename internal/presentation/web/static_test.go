@@ -0,0 +1,52 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStaticHandler(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "style.css"), []byte("body{}"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	h := StaticHandler("/static/", dir)
+
+	tests := []struct {
+		name             string
+		path             string
+		wantStatus       int
+		wantCacheControl string
+	}{
+		{
+			name:             "正常系: 存在するファイルはキャッシュ可能",
+			path:             "/static/style.css",
+			wantStatus:       http.StatusOK,
+			wantCacheControl: "public, max-age=3600",
+		},
+		{
+			name:             "異常系: 存在しないファイルはキャッシュさせない",
+			path:             "/static/missing.css",
+			wantStatus:       http.StatusNotFound,
+			wantCacheControl: "no-store",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Cache-Control"); got != tt.wantCacheControl {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.wantCacheControl)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Health プロセスが応答できるかを取得
+func (c *Client) Health(ctx context.Context) (*HealthStatus, error) {
+	var status HealthStatus
+	if _, err := c.getRaw(ctx, "/health", &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Ready リクエストを受け付けられるかを依存先の状態とあわせて取得
+// 受け付けられない場合（503）も、依存先の状態とともに*APIErrorを返す
+func (c *Client) Ready(ctx context.Context) (*ReadyStatus, error) {
+	var status ReadyStatus
+	code, err := c.getRaw(ctx, "/ready", &status)
+	if err != nil {
+		return nil, err
+	}
+	if code >= http.StatusBadRequest {
+		return &status, &APIError{StatusCode: code, Message: status.Status}
+	}
+	return &status, nil
+}
+
+// GetMaintenance メンテナンスモードの状態を取得
+func (c *Client) GetMaintenance(ctx context.Context) (*MaintenanceStatus, error) {
+	return c.maintenance(ctx, request{method: http.MethodGet, path: "/api/v1/admin/maintenance", admin: true})
+}
+
+// UpdateMaintenance メンテナンスモードを切り替える
+func (c *Client) UpdateMaintenance(ctx context.Context, status MaintenanceStatus) (*MaintenanceStatus, error) {
+	req, err := jsonRequest(http.MethodPut, "/api/v1/admin/maintenance", status)
+	if err != nil {
+		return nil, err
+	}
+	req.admin = true
+	return c.maintenance(ctx, req)
+}
+
+// GetFeatures 現在の機能フラグの一覧を取得
+func (c *Client) GetFeatures(ctx context.Context) (map[string]bool, error) {
+	var features map[string]bool
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/admin/features", admin: true}, &features)
+	return features, err
+}
+
+// GetContainer DIコンテナで組み立てた実装（AIのプロバイダー・キャッシュ・ストレージ・データベースなど）を初期化の順に取得
+func (c *Client) GetContainer(ctx context.Context) ([]ContainerComponent, error) {
+	var components []ContainerComponent
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/admin/container", admin: true}, &components)
+	return components, err
+}
+
+// GetSLO 直近のウィンドウのレシート処理のSLOの状態を取得
+func (c *Client) GetSLO(ctx context.Context) (*SLOStatus, error) {
+	var status SLOStatus
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/admin/slo", admin: true}, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// GetCacheStats エンドポイント・テナントごとのキャッシュのヒット率と、接頭辞ごとのキーの件数・使用メモリを取得
+// sampleはSCANして調べるキーの数（0以下の場合はサーバーの既定値）
+func (c *Client) GetCacheStats(ctx context.Context, sample int) (*CacheStats, error) {
+	req := request{method: http.MethodGet, path: "/api/v1/admin/cache/stats", admin: true}
+	if sample > 0 {
+		req.query = url.Values{"sample": {strconv.Itoa(sample)}}
+	}
+	var stats CacheStats
+	if err := c.do(ctx, req, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// RepairTotals 保存済みのレシートの合計金額を明細から検証・修復（dryRunの場合は検証のみ）
+func (c *Client) RepairTotals(ctx context.Context, dryRun bool) (*TotalsRepairReport, error) {
+	req := request{
+		method: http.MethodPost,
+		path:   "/api/v1/admin/repair/totals",
+		query:  url.Values{"dry_run": {strconv.FormatBool(dryRun)}},
+		admin:  true,
+	}
+	var report TotalsRepairReport
+	if err := c.do(ctx, req, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// NormalizeNames 保存済みのレシートの店名・商品名の表記ゆれを正規化（dryRunの場合は検出のみ）
+func (c *Client) NormalizeNames(ctx context.Context, dryRun bool) (*NameNormalizationReport, error) {
+	req := request{
+		method: http.MethodPost,
+		path:   "/api/v1/admin/repair/names",
+		query:  url.Values{"dry_run": {strconv.FormatBool(dryRun)}},
+		admin:  true,
+	}
+	var report NameNormalizationReport
+	if err := c.do(ctx, req, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// ExportWarehouse 指定月のレシート・明細・家計簿エントリを分析用のParquetファイルに書き出す（yearとmonthが0の場合は当月）
+func (c *Client) ExportWarehouse(ctx context.Context, year, month int) (*WarehouseExportReport, error) {
+	query := url.Values{}
+	setInt(query, "year", year)
+	setInt(query, "month", month)
+	req := request{
+		method: http.MethodPost,
+		path:   "/api/v1/admin/warehouse/export",
+		query:  query,
+		admin:  true,
+	}
+	var report WarehouseExportReport
+	if err := c.do(ctx, req, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// GetAIDebugLog 記録したAI APIへのリクエストとレスポンスを新しい順に取得（limitが0以下の場合はすべて）
+func (c *Client) GetAIDebugLog(ctx context.Context, limit int) ([]AIExchange, error) {
+	query := url.Values{}
+	setInt(query, "limit", limit)
+	var exchanges []AIExchange
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/admin/ai-debug", query: query, admin: true}, &exchanges)
+	return exchanges, err
+}
+
+// ClearAIDebugLog 記録したAI APIへのリクエストとレスポンスを削除
+func (c *Client) ClearAIDebugLog(ctx context.Context) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/api/v1/admin/ai-debug", admin: true}, nil)
+}
+
+// GetAIBudget 当月のAI APIの利用料金と、予算超過時のカテゴリーの判定の縮退の状態を取得
+func (c *Client) GetAIBudget(ctx context.Context) (*AIBudgetStatus, error) {
+	return c.aiBudget(ctx, request{method: http.MethodGet, path: "/api/v1/admin/ai-budget", admin: true})
+}
+
+// UpdateAIBudget 予算超過時のカテゴリーの判定の縮退を手動で切り替える（auto・normal・downgrade）
+func (c *Client) UpdateAIBudget(ctx context.Context, override string) (*AIBudgetStatus, error) {
+	req, err := jsonRequest(http.MethodPut, "/api/v1/admin/ai-budget", map[string]string{"override": override})
+	if err != nil {
+		return nil, err
+	}
+	req.admin = true
+	return c.aiBudget(ctx, req)
+}
+
+// aiBudget AI APIの予算の状態を返すAPIのリクエストを送信
+func (c *Client) aiBudget(ctx context.Context, req request) (*AIBudgetStatus, error) {
+	var status AIBudgetStatus
+	if err := c.do(ctx, req, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// maintenance メンテナンスモードの状態を返すAPIのリクエストを送信
+func (c *Client) maintenance(ctx context.Context, req request) (*MaintenanceStatus, error) {
+	var status MaintenanceStatus
+	if err := c.do(ctx, req, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// getRaw 共通レスポンスで包まれていないJSON（ヘルスチェック）を取得し、ステータスコードを返す
+func (c *Client) getRaw(ctx context.Context, path string, out any) (int, error) {
+	resp, err := c.send(ctx, request{method: http.MethodGet, path: path})
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		if resp.StatusCode >= http.StatusBadRequest {
+			return resp.StatusCode, &APIError{StatusCode: resp.StatusCode}
+		}
+		return resp.StatusCode, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,269 @@
+// Package client Vision API AppのREST APIのGoクライアント
+//
+// api/openapi.yaml の各オペレーションに型付きのメソッドを用意し、
+// multipartのリクエストの組み立てや共通レスポンス（success / data / error）の解析を隠す。
+// 標準ライブラリのみに依存するため、他のサービスからそのままimportできる。
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// defaultTimeout HTTPクライアントのデフォルトのタイムアウト（レシートの認識に時間がかかるため長めにする）
+const defaultTimeout = 2 * time.Minute
+
+// APIError APIがエラーのステータスコードを返した
+type APIError struct {
+	StatusCode int
+	Message    string // レスポンスのerror（空の場合はステータスのテキスト）
+}
+
+// Error エラーメッセージ
+func (e *APIError) Error() string {
+	message := e.Message
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+	return fmt.Sprintf("vision-api: status %d: %s", e.StatusCode, message)
+}
+
+// IsNotFound エラーが404かチェック
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// Client Vision API AppのAPIクライアント
+type Client struct {
+	baseURL     *url.URL
+	httpClient  *http.Client
+	adminToken  string
+	apiKey      string
+	approverKey string
+	timezone    string
+}
+
+// New 新しいClientを作成（baseURLは http://localhost:8080 のようにスキームとホストを含める）
+func New(baseURL string) (*Client, error) {
+	u, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid base URL: %s", baseURL)
+	}
+	return &Client{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}, nil
+}
+
+// SetHTTPClient リクエストに使うHTTPクライアントを設定
+func (c *Client) SetHTTPClient(httpClient *http.Client) {
+	c.httpClient = httpClient
+}
+
+// SetAdminToken 管理APIのBearerトークンを設定（管理APIのリクエストにのみ付与する）
+func (c *Client) SetAdminToken(token string) {
+	c.adminToken = token
+}
+
+// SetAPIKey 自動化ツール向けAPIのAPIキーを設定（X-API-Key ヘッダー、APIキー認証のAPIにのみ付与する）
+func (c *Client) SetAPIKey(key string) {
+	c.apiKey = key
+}
+
+// SetApproverKey 経費報告書の承認者のキーを設定（X-Approver-Key ヘッダー、承認・差し戻し・精算のリクエストにのみ付与する）
+func (c *Client) SetApproverKey(key string) {
+	c.approverKey = key
+}
+
+// SetTimezone 購入日の解釈・期間の集計に使うタイムゾーン（例: Asia/Tokyo）を設定（X-Timezone ヘッダー）
+func (c *Client) SetTimezone(name string) {
+	c.timezone = name
+}
+
+// Download ダウンロードしたファイル（呼び出し元がBodyを閉じる）
+type Download struct {
+	Filename    string
+	ContentType string
+	Body        io.ReadCloser
+}
+
+// envelope 家計簿・管理APIの共通レスポンス
+type envelope struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Error   string          `json:"error"`
+}
+
+// request APIへのリクエスト
+type request struct {
+	method      string
+	path        string // ベースURLからのパス（署名付きURLのようにクエリを含んでもよい）
+	query       url.Values
+	body        io.Reader
+	contentType string
+	header      http.Header
+	admin       bool // 管理APIのトークンを付与する
+	apiKey      bool // APIキーを付与する
+	approverKey bool // 経費報告書の承認者のキーを付与する
+}
+
+// send リクエストを送信してレスポンスを返す（呼び出し元がレスポンスボディを閉じる）
+func (c *Client) send(ctx context.Context, req request) (*http.Response, error) {
+	ref, err := url.Parse(req.path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path: %w", err)
+	}
+	u := c.baseURL.ResolveReference(ref)
+	if len(req.query) > 0 {
+		query := u.Query()
+		for key, values := range req.query {
+			query[key] = values
+		}
+		u.RawQuery = query.Encode()
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, u.String(), req.body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, values := range req.header {
+		httpReq.Header[key] = values
+	}
+	if req.contentType != "" {
+		httpReq.Header.Set("Content-Type", req.contentType)
+	}
+	if c.timezone != "" {
+		httpReq.Header.Set("X-Timezone", c.timezone)
+	}
+	if req.admin && c.adminToken != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.adminToken)
+	}
+	if req.apiKey && c.apiKey != "" {
+		httpReq.Header.Set("X-API-Key", c.apiKey)
+	}
+	if req.approverKey && c.approverKey != "" {
+		httpReq.Header.Set("X-Approver-Key", c.approverKey)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// do リクエストを送信し、共通レスポンスのdataをoutにデコードする（outがnilの場合はデコードしない）
+// エラーのステータスコードでもdataを返すAPI（会計サービスとの競合など）は、outにデコードしたうえで*APIErrorを返す
+func (c *Client) do(ctx context.Context, req request, out any) error {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	var env envelope
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		if resp.StatusCode >= http.StatusBadRequest {
+			return &APIError{StatusCode: resp.StatusCode}
+		}
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if out != nil && len(env.Data) > 0 && string(env.Data) != "null" {
+		if err := json.Unmarshal(env.Data, out); err != nil {
+			return fmt.Errorf("failed to decode response data: %w", err)
+		}
+	}
+	if resp.StatusCode >= http.StatusBadRequest || !env.Success {
+		return &APIError{StatusCode: resp.StatusCode, Message: env.Error}
+	}
+	return nil
+}
+
+// download リクエストを送信し、レスポンスボディをファイルとして返す
+func (c *Client) download(ctx context.Context, req request) (*Download, error) {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		var env envelope
+		_ = json.NewDecoder(resp.Body).Decode(&env)
+		return nil, &APIError{StatusCode: resp.StatusCode, Message: env.Error}
+	}
+
+	download := &Download{
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        resp.Body,
+	}
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		download.Filename = params["filename"]
+	}
+	return download, nil
+}
+
+// jsonRequest JSONのリクエストボディを作成
+func jsonRequest(method, path string, body any) (request, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return request{}, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	return request{
+		method:      method,
+		path:        path,
+		body:        bytes.NewReader(data),
+		contentType: "application/json",
+	}, nil
+}
+
+// multipartRequest ファイルとフォームの値からmultipart/form-dataのリクエストボディを作成
+func multipartRequest(path, field, filename string, file io.Reader, values map[string]string) (request, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for name, value := range values {
+		if value == "" {
+			continue
+		}
+		if err := mw.WriteField(name, value); err != nil {
+			return request{}, fmt.Errorf("failed to write form field: %w", err)
+		}
+	}
+	part, err := mw.CreateFormFile(field, filename)
+	if err != nil {
+		return request{}, fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return request{}, fmt.Errorf("failed to read file: %w", err)
+	}
+	if err := mw.Close(); err != nil {
+		return request{}, fmt.Errorf("failed to close form: %w", err)
+	}
+	return request{
+		method:      http.MethodPost,
+		path:        path,
+		body:        &buf,
+		contentType: mw.FormDataContentType(),
+	}, nil
+}
@@ -0,0 +1,281 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestClient テスト用のサーバーとクライアントを作成
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c, err := New(server.URL)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return c
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		wantErr bool
+	}{
+		{name: "正常系: スキームとホスト", baseURL: "http://localhost:8080"},
+		{name: "正常系: 末尾のスラッシュ", baseURL: "https://example.com/"},
+		{name: "異常系: スキームなし", baseURL: "localhost:8080", wantErr: true},
+		{name: "異常系: 空", baseURL: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.baseURL)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_CreateReceipt(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/receipts" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "" {
+			t.Error("Admin token should not be sent to household API")
+		}
+		if got := r.Header.Get("X-Timezone"); got != "Asia/Tokyo" {
+			t.Errorf("X-Timezone = %q, want Asia/Tokyo", got)
+		}
+		file, header, err := r.FormFile("image")
+		if err != nil {
+			t.Fatalf("FormFile() error = %v", err)
+		}
+		data, _ := io.ReadAll(file)
+		if string(data) != "image-data" || header.Filename != "receipt.jpg" {
+			t.Errorf("file = %q (%s)", data, header.Filename)
+		}
+		if got := r.FormValue("tags"); got != "食費,週末" {
+			t.Errorf("tags = %q", got)
+		}
+		if got := r.FormValue("keep_location"); got != "true" {
+			t.Errorf("keep_location = %q", got)
+		}
+		if got := r.FormValue("created_at"); got != "2024-04-01T10:00:00Z" {
+			t.Errorf("created_at = %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"success":true,"data":{"id":"r1","store_name":"スーパー","total_amount":1280,"items":[{"id":"i1","name":"牛乳","quantity":1,"price":200}]}}`)
+	})
+	c.SetAdminToken("secret")
+	c.SetTimezone("Asia/Tokyo")
+
+	receipt, err := c.CreateReceipt(context.Background(), strings.NewReader("image-data"), "receipt.jpg", ReceiptOptions{
+		Tags:         []string{"食費", "週末"},
+		KeepLocation: true,
+		CreatedAt:    time.Date(2024, time.April, 1, 10, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("CreateReceipt() error = %v", err)
+	}
+	if receipt.ID != "r1" || receipt.TotalAmount != 1280 || len(receipt.Items) != 1 {
+		t.Errorf("receipt = %+v", receipt)
+	}
+}
+
+func TestClient_AnalyzeReceipt(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		cache      string
+		body       string
+		wantErr    bool
+		wantCached bool
+	}{
+		{
+			name:       "正常系: キャッシュ",
+			status:     http.StatusOK,
+			cache:      "HIT",
+			body:       `{"success":true,"text":"{}","tokens":{"input_tokens":1,"output_tokens":2,"total_tokens":3}}`,
+			wantCached: true,
+		},
+		{
+			name:    "異常系: 認識の失敗",
+			status:  http.StatusInternalServerError,
+			body:    `{"success":false,"error":"recognition failed"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v1/vision/receipt" {
+					t.Errorf("path = %s", r.URL.Path)
+				}
+				w.Header().Set("X-Cache", tt.cache)
+				w.WriteHeader(tt.status)
+				_, _ = io.WriteString(w, tt.body)
+			})
+
+			result, err := c.AnalyzeReceipt(context.Background(), strings.NewReader("image"), "receipt.png")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AnalyzeReceipt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.Message != "recognition failed" {
+					t.Errorf("error = %v", err)
+				}
+				return
+			}
+			if result.Cached != tt.wantCached || result.Tokens == nil || result.Tokens.TotalTokens != 3 {
+				t.Errorf("result = %+v", result)
+			}
+		})
+	}
+}
+
+func TestClient_AnalyzeReceiptSimple(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/vision/receipt/simple" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if got := r.Header.Get("X-API-Key"); got != "key" {
+			t.Errorf("X-API-Key = %q", got)
+		}
+		if got := r.Header.Get("Content-Type"); got != "image/jpeg" {
+			t.Errorf("Content-Type = %q", got)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "jpeg" {
+			t.Errorf("body = %q", body)
+		}
+		_, _ = io.WriteString(w, `{"success":true,"text":"{}"}`)
+	})
+	c.SetAPIKey("key")
+
+	if _, err := c.AnalyzeReceiptSimple(context.Background(), strings.NewReader("jpeg"), "image/jpeg"); err != nil {
+		t.Fatalf("AnalyzeReceiptSimple() error = %v", err)
+	}
+}
+
+func TestClient_GetReceipt_NotFound(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v1/receipts/a%2Fb" {
+			t.Errorf("path = %s", r.URL.EscapedPath())
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"success":false,"error":"receipt not found"}`)
+	})
+
+	_, err := c.GetReceipt(context.Background(), "a/b")
+	if !IsNotFound(err) {
+		t.Fatalf("GetReceipt() error = %v, want not found", err)
+	}
+	if !strings.Contains(err.Error(), "receipt not found") {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestClient_SyncReceipt_Conflict(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/receipts/r1/sync/freee" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusConflict)
+		_, _ = io.WriteString(w, `{"success":false,"data":{"receipt_id":"r1","provider":"freee","status":"conflict"},"error":"changed remotely"}`)
+	})
+
+	sync, err := c.SyncReceipt(context.Background(), "r1", "freee")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict {
+		t.Fatalf("SyncReceipt() error = %v, want conflict", err)
+	}
+	if sync == nil || sync.Status != "conflict" {
+		t.Errorf("sync = %+v, want conflict status", sync)
+	}
+}
+
+func TestClient_ExportReceipts(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Encode(); got != "format=csv&month=4&year=2025" {
+			t.Errorf("query = %s", got)
+		}
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="receipts_2025_04.csv"`)
+		_, _ = io.WriteString(w, "id,store\n")
+	})
+
+	download, err := c.ExportReceipts(context.Background(), PeriodParams{Year: 2025, Month: 4, Format: "csv"})
+	if err != nil {
+		t.Fatalf("ExportReceipts() error = %v", err)
+	}
+	defer func() {
+		_ = download.Body.Close()
+	}()
+	body, _ := io.ReadAll(download.Body)
+	if download.Filename != "receipts_2025_04.csv" || string(body) != "id,store\n" {
+		t.Errorf("download = %s %q", download.Filename, body)
+	}
+}
+
+func TestClient_Admin(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+		switch r.Method {
+		case http.MethodPost:
+			if got := r.URL.Query().Get("dry_run"); got != "true" {
+				t.Errorf("dry_run = %q", got)
+			}
+			_, _ = io.WriteString(w, `{"success":true,"data":{"dry_run":true,"scanned":3,"mismatched":1,"mismatched_ids":["r2"]}}`)
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	c.SetAdminToken("secret")
+
+	report, err := c.RepairTotals(context.Background(), true)
+	if err != nil {
+		t.Fatalf("RepairTotals() error = %v", err)
+	}
+	if !report.DryRun || report.Scanned != 3 || len(report.MismatchedIDs) != 1 {
+		t.Errorf("report = %+v", report)
+	}
+	if err := c.ClearAIDebugLog(context.Background()); err != nil {
+		t.Errorf("ClearAIDebugLog() error = %v", err)
+	}
+}
+
+func TestClient_UploadChunk(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("signature"); got != "sig" {
+			t.Errorf("signature = %q", got)
+		}
+		if got := r.Header.Get("Content-Range"); got != "bytes 4-7/10" {
+			t.Errorf("Content-Range = %q", got)
+		}
+		_, _ = io.WriteString(w, `{"success":true,"data":{"upload_id":"u1","offset":8,"complete":false}}`)
+	})
+
+	progress, err := c.UploadChunk(context.Background(), "/api/v1/uploads/u1?expires=1&signature=sig", []byte("abcd"), 4, 10)
+	if err != nil {
+		t.Fatalf("UploadChunk() error = %v", err)
+	}
+	if progress.Offset != 8 || progress.Complete {
+		t.Errorf("progress = %+v", progress)
+	}
+}
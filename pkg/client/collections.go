@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// ListCollections コレクションを名前の順に取得
+func (c *Client) ListCollections(ctx context.Context, page Page) ([]Collection, error) {
+	var collections []Collection
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/collections", query: page.values()}, &collections)
+	return collections, err
+}
+
+// CreateCollection 空のコレクションを作成
+func (c *Client) CreateCollection(ctx context.Context, name, description string) (*Collection, error) {
+	req, err := jsonRequest(http.MethodPost, "/api/v1/collections", CollectionPatch{Name: &name, Description: &description})
+	if err != nil {
+		return nil, err
+	}
+	return c.collection(ctx, req)
+}
+
+// GetCollection コレクションを取得
+func (c *Client) GetCollection(ctx context.Context, id string) (*Collection, error) {
+	return c.collection(ctx, request{method: http.MethodGet, path: collectionPath(id)})
+}
+
+// PatchCollection コレクションの名前・説明を変更
+func (c *Client) PatchCollection(ctx context.Context, id string, patch CollectionPatch) (*Collection, error) {
+	req, err := jsonRequest(http.MethodPatch, collectionPath(id), patch)
+	if err != nil {
+		return nil, err
+	}
+	return c.collection(ctx, req)
+}
+
+// DeleteCollection コレクションを削除（含めていたレシートは削除しない）
+func (c *Client) DeleteCollection(ctx context.Context, id string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: collectionPath(id)}, nil)
+}
+
+// ListCollectionReceipts コレクションに含めるレシートを追加した順に取得
+func (c *Client) ListCollectionReceipts(ctx context.Context, id string) ([]Receipt, error) {
+	var receipts []Receipt
+	err := c.do(ctx, request{method: http.MethodGet, path: collectionPath(id) + "/receipts"}, &receipts)
+	return receipts, err
+}
+
+// AddCollectionReceipts レシートをコレクションに追加（含まれているレシートは無視する）
+func (c *Client) AddCollectionReceipts(ctx context.Context, id string, receiptIDs []string) (*Collection, error) {
+	req, err := jsonRequest(http.MethodPost, collectionPath(id)+"/receipts", map[string][]string{"receipt_ids": receiptIDs})
+	if err != nil {
+		return nil, err
+	}
+	return c.collection(ctx, req)
+}
+
+// RemoveCollectionReceipt レシートをコレクションから外す（レシート自体は削除しない）
+func (c *Client) RemoveCollectionReceipt(ctx context.Context, id, receiptID string) (*Collection, error) {
+	return c.collection(ctx, request{method: http.MethodDelete, path: collectionPath(id) + "/receipts/" + url.PathEscape(receiptID)})
+}
+
+// GetCollectionReport コレクションに含めるレシートの枚数・合計金額・期間・カテゴリー別の合計を取得
+func (c *Client) GetCollectionReport(ctx context.Context, id string) (*CollectionReport, error) {
+	var report CollectionReport
+	if err := c.do(ctx, request{method: http.MethodGet, path: collectionPath(id) + "/report"}, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// ExportCollection コレクションに含めるレシートの明細（format: csv / xlsx、空の場合はcsv）をダウンロード
+func (c *Client) ExportCollection(ctx context.Context, id, format string) (*Download, error) {
+	query := url.Values{}
+	if format != "" {
+		query.Set("format", format)
+	}
+	return c.download(ctx, request{method: http.MethodGet, path: collectionPath(id) + "/export", query: query})
+}
+
+// ListReceiptCollections レシートを含むコレクションを名前の順に取得
+func (c *Client) ListReceiptCollections(ctx context.Context, receiptID string) ([]Collection, error) {
+	var collections []Collection
+	err := c.do(ctx, request{method: http.MethodGet, path: receiptPath(receiptID) + "/collections"}, &collections)
+	return collections, err
+}
+
+// collection コレクションを返すリクエストを送信
+func (c *Client) collection(ctx context.Context, req request) (*Collection, error) {
+	var collection Collection
+	if err := c.do(ctx, req, &collection); err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
+
+// collectionPath コレクションのパス
+func collectionPath(id string) string {
+	return "/api/v1/collections/" + url.PathEscape(id)
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// ListExpenseReports 経費報告書を更新日時の新しい順に取得（レシートの概要は含まない）
+func (c *Client) ListExpenseReports(ctx context.Context, params ListExpenseReportsParams) ([]ExpenseReport, error) {
+	query := params.Page.values()
+	if params.Status != "" {
+		query.Set("status", params.Status)
+	}
+	var reports []ExpenseReport
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/expense-reports", query: query}, &reports)
+	return reports, err
+}
+
+// CreateExpenseReport レシートをまとめて作成中の経費報告書を作成
+func (c *Client) CreateExpenseReport(ctx context.Context, report ExpenseReportCreate) (*ExpenseReport, error) {
+	req, err := jsonRequest(http.MethodPost, "/api/v1/expense-reports", report)
+	if err != nil {
+		return nil, err
+	}
+	return c.expenseReport(ctx, req)
+}
+
+// GetExpenseReport 経費報告書を含まれるレシートの概要とともに取得
+func (c *Client) GetExpenseReport(ctx context.Context, id string) (*ExpenseReport, error) {
+	return c.expenseReport(ctx, request{method: http.MethodGet, path: expenseReportPath(id)})
+}
+
+// PatchExpenseReport 作成中の経費報告書の件名・申請者・レシートを変更
+func (c *Client) PatchExpenseReport(ctx context.Context, id string, patch ExpenseReportPatch) (*ExpenseReport, error) {
+	req, err := jsonRequest(http.MethodPatch, expenseReportPath(id), patch)
+	if err != nil {
+		return nil, err
+	}
+	return c.expenseReport(ctx, req)
+}
+
+// DeleteExpenseReport 作成中の経費報告書を削除
+func (c *Client) DeleteExpenseReport(ctx context.Context, id string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: expenseReportPath(id)}, nil)
+}
+
+// DownloadExpenseReportPDF 経費報告書をPDFでダウンロード
+func (c *Client) DownloadExpenseReportPDF(ctx context.Context, id string) (*Download, error) {
+	return c.download(ctx, request{method: http.MethodGet, path: expenseReportPath(id) + "/pdf"})
+}
+
+// SubmitExpenseReport 経費報告書を申請（合計金額は申請時のレシートの合計で確定する）
+func (c *Client) SubmitExpenseReport(ctx context.Context, id string) (*ExpenseReport, error) {
+	return c.expenseReport(ctx, request{method: http.MethodPost, path: expenseReportPath(id) + "/submit"})
+}
+
+// ApproveExpenseReport 申請済みの経費報告書を承認（SetApproverKeyで承認者のキーを設定する）
+func (c *Client) ApproveExpenseReport(ctx context.Context, id, comment string) (*ExpenseReport, error) {
+	return c.reviewExpenseReport(ctx, id, "/approve", comment)
+}
+
+// RejectExpenseReport 申請済みの経費報告書を差し戻して作成中に戻す（SetApproverKeyで承認者のキーを設定する）
+func (c *Client) RejectExpenseReport(ctx context.Context, id, comment string) (*ExpenseReport, error) {
+	return c.reviewExpenseReport(ctx, id, "/reject", comment)
+}
+
+// ReimburseExpenseReport 承認済みの経費報告書を精算済みにする（SetApproverKeyで経理担当者のキーを設定する）
+func (c *Client) ReimburseExpenseReport(ctx context.Context, id string) (*ExpenseReport, error) {
+	return c.expenseReport(ctx, request{method: http.MethodPost, path: expenseReportPath(id) + "/reimburse", approverKey: true})
+}
+
+// reviewExpenseReport 承認・差し戻しのリクエストを送信
+func (c *Client) reviewExpenseReport(ctx context.Context, id, action, comment string) (*ExpenseReport, error) {
+	req, err := jsonRequest(http.MethodPost, expenseReportPath(id)+action, map[string]string{"comment": comment})
+	if err != nil {
+		return nil, err
+	}
+	req.approverKey = true
+	return c.expenseReport(ctx, req)
+}
+
+// expenseReport 経費報告書を返すリクエストを送信
+func (c *Client) expenseReport(ctx context.Context, req request) (*ExpenseReport, error) {
+	var report ExpenseReport
+	if err := c.do(ctx, req, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// expenseReportPath 経費報告書のパス
+func expenseReportPath(id string) string {
+	return "/api/v1/expense-reports/" + url.PathEscape(id)
+}
@@ -0,0 +1,711 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CreateReceipt レシート画像をアップロードして登録
+// データベース障害中で一時保管された場合（202）も、登録予定のレシートを返す
+func (c *Client) CreateReceipt(ctx context.Context, image io.Reader, filename string, opts ReceiptOptions) (*Receipt, error) {
+	values := map[string]string{
+		"tags": strings.Join(opts.Tags, ","),
+		"memo": opts.Memo,
+	}
+	if opts.KeepLocation {
+		values["keep_location"] = "true"
+	}
+	if !opts.CreatedAt.IsZero() {
+		values["created_at"] = opts.CreatedAt.Format(time.RFC3339)
+	}
+	req, err := multipartRequest("/api/v1/receipts", "image", filename, image, values)
+	if err != nil {
+		return nil, err
+	}
+	var receipt Receipt
+	if err := c.do(ctx, req, &receipt); err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+// ExtractReceipt レシート画像を読み取って下書きを作成（ConfirmReceiptで確認するまで家計簿に反映しない）
+func (c *Client) ExtractReceipt(ctx context.Context, image io.Reader, filename string, opts ReceiptOptions) (*ReceiptDraft, error) {
+	values := map[string]string{
+		"tags": strings.Join(opts.Tags, ","),
+		"memo": opts.Memo,
+	}
+	if opts.KeepLocation {
+		values["keep_location"] = "true"
+	}
+	req, err := multipartRequest("/api/v1/vision/receipt/extract", "image", filename, image, values)
+	if err != nil {
+		return nil, err
+	}
+	var draft ReceiptDraft
+	if err := c.do(ctx, req, &draft); err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
+
+// ConfirmReceipt 下書きに修正を反映してレシートを登録（修正しない項目はnilのまま）
+// データベース障害中で一時保管された場合（202）も、登録予定のレシートを返す
+func (c *Client) ConfirmReceipt(ctx context.Context, draftToken string, patch ReceiptPatch) (*Receipt, error) {
+	body := struct {
+		DraftToken string `json:"draft_token"`
+		ReceiptPatch
+	}{DraftToken: draftToken, ReceiptPatch: patch}
+	req, err := jsonRequest(http.MethodPost, "/api/v1/receipts/confirm", body)
+	if err != nil {
+		return nil, err
+	}
+	return c.receipt(ctx, req)
+}
+
+// ListReceipts レシート一覧を取得（タグ・キーワードで絞り込み）
+func (c *Client) ListReceipts(ctx context.Context, params ListReceiptsParams) ([]Receipt, error) {
+	query := params.Page.values()
+	if params.Tag != "" {
+		query.Set("tag", params.Tag)
+	}
+	if params.Query != "" {
+		query.Set("q", params.Query)
+	}
+	var receipts []Receipt
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/receipts", query: query}, &receipts)
+	return receipts, err
+}
+
+// ListReceiptsNeedingReview 要確認のレシート一覧を取得
+func (c *Client) ListReceiptsNeedingReview(ctx context.Context, page Page) ([]Receipt, error) {
+	var receipts []Receipt
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/receipts/needs-review", query: page.values()}, &receipts)
+	return receipts, err
+}
+
+// GetReceipt レシートを取得
+func (c *Client) GetReceipt(ctx context.Context, id string) (*Receipt, error) {
+	return c.receipt(ctx, request{method: http.MethodGet, path: receiptPath(id)})
+}
+
+// PatchReceipt レシートを部分的に修正
+func (c *Client) PatchReceipt(ctx context.Context, id string, patch ReceiptPatch) (*Receipt, error) {
+	req, err := jsonRequest(http.MethodPatch, receiptPath(id), patch)
+	if err != nil {
+		return nil, err
+	}
+	return c.receipt(ctx, req)
+}
+
+// ReplaceReceipt レシートの編集できる項目をすべて置き換える
+// StoreName・PurchaseDate・TotalAmount・Itemsは必須で、省略した種類・タグ・メモは既定値になる
+func (c *Client) ReplaceReceipt(ctx context.Context, id string, replacement ReceiptPatch) (*Receipt, error) {
+	req, err := jsonRequest(http.MethodPut, receiptPath(id), replacement)
+	if err != nil {
+		return nil, err
+	}
+	return c.receipt(ctx, req)
+}
+
+// DeleteReceipt レシートをゴミ箱に移す
+func (c *Client) DeleteReceipt(ctx context.Context, id string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: receiptPath(id)}, nil)
+}
+
+// GetReceiptHistory レシートの変更履歴を取得
+func (c *Client) GetReceiptHistory(ctx context.Context, id string) ([]ReceiptRevision, error) {
+	var revisions []ReceiptRevision
+	err := c.do(ctx, request{method: http.MethodGet, path: receiptPath(id) + "/history"}, &revisions)
+	return revisions, err
+}
+
+// ExportReceipt レシートと紐づく家計簿エントリを移行用の文書として書き出す（schemaVersionが0の場合はサーバーの現在の版）
+func (c *Client) ExportReceipt(ctx context.Context, id string, schemaVersion int) (*ReceiptInterchange, error) {
+	query := url.Values{}
+	if schemaVersion > 0 {
+		query.Set("schema_version", strconv.Itoa(schemaVersion))
+	}
+	download, err := c.download(ctx, request{method: http.MethodGet, path: receiptPath(id) + "/export", query: query})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = download.Body.Close()
+	}()
+
+	var doc ReceiptInterchange
+	if err := json.NewDecoder(download.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode receipt export: %w", err)
+	}
+	return &doc, nil
+}
+
+// ImportReceipt 移行用の文書のレシートと家計簿エントリを登録
+func (c *Client) ImportReceipt(ctx context.Context, doc *ReceiptInterchange) (*Receipt, error) {
+	req, err := jsonRequest(http.MethodPost, "/api/v1/receipts/import", doc)
+	if err != nil {
+		return nil, err
+	}
+	return c.receipt(ctx, req)
+}
+
+// RevertReceipt レシートを指定リビジョンの状態に戻す
+func (c *Client) RevertReceipt(ctx context.Context, id string, revision int) (*Receipt, error) {
+	req, err := jsonRequest(http.MethodPost, receiptPath(id)+"/revert", map[string]int{"revision": revision})
+	if err != nil {
+		return nil, err
+	}
+	return c.receipt(ctx, req)
+}
+
+// MergeReceipts 二重に登録したレシートを統合し、mergedReceiptIDのレシートを削除する
+func (c *Client) MergeReceipts(ctx context.Context, receiptID, mergedReceiptID string) (*MergeResult, error) {
+	req, err := jsonRequest(http.MethodPost, "/api/v1/receipts/merge", map[string]string{
+		"receipt_id":        receiptID,
+		"merged_receipt_id": mergedReceiptID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var result MergeResult
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListReceiptMerges レシートに統合したレシートの記録を取得
+func (c *Client) ListReceiptMerges(ctx context.Context, id string) ([]ReceiptMerge, error) {
+	var merges []ReceiptMerge
+	err := c.do(ctx, request{method: http.MethodGet, path: receiptPath(id) + "/merges"}, &merges)
+	return merges, err
+}
+
+// UnmergeReceipts 統合を取り消し、削除したレシートを登録し直す
+func (c *Client) UnmergeReceipts(ctx context.Context, id, mergedReceiptID string) (*UnmergeResult, error) {
+	req, err := jsonRequest(http.MethodPost, receiptPath(id)+"/unmerge", map[string]string{"merged_receipt_id": mergedReceiptID})
+	if err != nil {
+		return nil, err
+	}
+	var result UnmergeResult
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ReprocessReceipt 保存済みの元画像からレシートを再処理
+func (c *Client) ReprocessReceipt(ctx context.Context, id string) (*Receipt, error) {
+	return c.receipt(ctx, request{method: http.MethodPost, path: receiptPath(id) + "/reprocess"})
+}
+
+// GetReceiptExtraction 保存したAIの出力の原文を解析し直し、現在のレシートと比較（AIは呼び出さない）
+func (c *Client) GetReceiptExtraction(ctx context.Context, id string) (*ExtractionComparison, error) {
+	var comparison ExtractionComparison
+	if err := c.do(ctx, request{method: http.MethodGet, path: receiptPath(id) + "/extraction"}, &comparison); err != nil {
+		return nil, err
+	}
+	return &comparison, nil
+}
+
+// StartBackfill 保存済みの元画像からレシートをまとめて読み直す（結果はAIのバッチ処理が終わった後に反映）
+func (c *Client) StartBackfill(ctx context.Context, receiptIDs []string) (*Backfill, error) {
+	req, err := jsonRequest(http.MethodPost, "/api/v1/backfills", map[string][]string{"receipt_ids": receiptIDs})
+	if err != nil {
+		return nil, err
+	}
+	var backfill Backfill
+	if err := c.do(ctx, req, &backfill); err != nil {
+		return nil, err
+	}
+	return &backfill, nil
+}
+
+// GetBackfill 読み直しの進み具合を取得
+func (c *Client) GetBackfill(ctx context.Context, id string) (*Backfill, error) {
+	var backfill Backfill
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/backfills/" + url.PathEscape(id)}, &backfill); err != nil {
+		return nil, err
+	}
+	return &backfill, nil
+}
+
+// GetStorageUsage 元画像の保存容量の使用状況を取得
+func (c *Client) GetStorageUsage(ctx context.Context) (*StorageUsage, error) {
+	var usage StorageUsage
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/usage/storage"}, &usage); err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+// ListCategories カテゴリー一覧を名前の順に取得
+func (c *Client) ListCategories(ctx context.Context) ([]Category, error) {
+	var categories []Category
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/categories"}, &categories)
+	return categories, err
+}
+
+// CreateCategory カテゴリーを登録（同じ名前のカテゴリーが登録済みの場合は409）
+func (c *Client) CreateCategory(ctx context.Context, input CategoryInput) (*Category, error) {
+	return c.sendCategory(ctx, http.MethodPost, "/api/v1/categories", input)
+}
+
+// ReplaceCategory カテゴリーの名前・説明・表示色を置き換える
+func (c *Client) ReplaceCategory(ctx context.Context, id string, input CategoryInput) (*Category, error) {
+	return c.sendCategory(ctx, http.MethodPut, "/api/v1/categories/"+url.PathEscape(id), input)
+}
+
+// DeleteCategory カテゴリーを削除
+func (c *Client) DeleteCategory(ctx context.Context, id string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/api/v1/categories/" + url.PathEscape(id)}, nil)
+}
+
+// sendCategory カテゴリーの登録・置き換えリクエストを送信
+func (c *Client) sendCategory(ctx context.Context, method, path string, input CategoryInput) (*Category, error) {
+	req, err := jsonRequest(method, path, input)
+	if err != nil {
+		return nil, err
+	}
+	var category Category
+	if err := c.do(ctx, req, &category); err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// ListCategoryReceipts 指定したカテゴリーの明細を含むレシート一覧を取得
+func (c *Client) ListCategoryReceipts(ctx context.Context, category string, page Page) ([]Receipt, error) {
+	var receipts []Receipt
+	err := c.do(ctx, request{method: http.MethodGet, path: categoryPath(category) + "/receipts", query: page.values()}, &receipts)
+	return receipts, err
+}
+
+// ListCategoryItems 指定したカテゴリーの明細をレシートをまたいで取得
+func (c *Client) ListCategoryItems(ctx context.Context, category string, page Page) ([]CategoryItem, error) {
+	var items []CategoryItem
+	err := c.do(ctx, request{method: http.MethodGet, path: categoryPath(category) + "/items", query: page.values()}, &items)
+	return items, err
+}
+
+// GetPriceHistory 商品の価格推移と店舗別の単価を取得
+func (c *Client) GetPriceHistory(ctx context.Context, name string) (*PriceHistory, error) {
+	var history PriceHistory
+	query := url.Values{"name": {name}}
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/items/price-history", query: query}, &history); err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+// ListItemAliases 商品名の別名辞書の一覧を取得（管理APIのトークンが必要）
+func (c *Client) ListItemAliases(ctx context.Context) ([]ItemAlias, error) {
+	var aliases []ItemAlias
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/items/aliases", admin: true}, &aliases)
+	return aliases, err
+}
+
+// PutItemAlias 商品名の別名を登録（同じ別名が登録済みの場合は上書き、管理APIのトークンが必要）
+func (c *Client) PutItemAlias(ctx context.Context, alias, canonicalName string) (*ItemAlias, error) {
+	req, err := jsonRequest(http.MethodPut, "/api/v1/items/aliases", map[string]string{"alias": alias, "canonical_name": canonicalName})
+	if err != nil {
+		return nil, err
+	}
+	req.admin = true
+	var itemAlias ItemAlias
+	if err := c.do(ctx, req, &itemAlias); err != nil {
+		return nil, err
+	}
+	return &itemAlias, nil
+}
+
+// DeleteItemAlias 商品名の別名を削除（管理APIのトークンが必要）
+func (c *Client) DeleteItemAlias(ctx context.Context, alias string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/api/v1/items/aliases/" + url.PathEscape(alias), admin: true}, nil)
+}
+
+// ListExpiringWarranties 返品・保証期限がdays日以内の明細を取得（daysが0以下の場合はサーバーのデフォルト）
+func (c *Client) ListExpiringWarranties(ctx context.Context, days int) ([]ExpiringItem, error) {
+	query := url.Values{}
+	if days > 0 {
+		query.Set("days", strconv.Itoa(days))
+	}
+	var items []ExpiringItem
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/warranties/expiring", query: query}, &items)
+	return items, err
+}
+
+// IssueWarrantyCalendarToken 返品・保証期限のiCalendarのフィードの購読URLを発行（管理APIのトークンが必要）
+func (c *Client) IssueWarrantyCalendarToken(ctx context.Context) (*CalendarFeed, error) {
+	var feed CalendarFeed
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/api/v1/warranties/calendar/token", admin: true}, &feed); err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// GetWarrantyCalendar 返品・保証期限のiCalendarのフィードを取得（tokenは購読URLのトークン）
+func (c *Client) GetWarrantyCalendar(ctx context.Context, token string) (*Download, error) {
+	query := url.Values{}
+	query.Set("token", token)
+	return c.download(ctx, request{method: http.MethodGet, path: "/api/v1/warranties/calendar.ics", query: query})
+}
+
+// PutSplit レシートの割り勘を設定（既存の割り勘は置き換え）
+func (c *Client) PutSplit(ctx context.Context, receiptID string, split SplitRequest) (*Split, error) {
+	req, err := jsonRequest(http.MethodPut, receiptPath(receiptID)+"/split", split)
+	if err != nil {
+		return nil, err
+	}
+	return c.split(ctx, req)
+}
+
+// GetSplit レシートの割り勘を取得
+func (c *Client) GetSplit(ctx context.Context, receiptID string) (*Split, error) {
+	return c.split(ctx, request{method: http.MethodGet, path: receiptPath(receiptID) + "/split"})
+}
+
+// DeleteSplit レシートの割り勘を削除
+func (c *Client) DeleteSplit(ctx context.Context, receiptID string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: receiptPath(receiptID) + "/split"}, nil)
+}
+
+// SettleSplitParticipant 参加者の精算状態を変更
+func (c *Client) SettleSplitParticipant(ctx context.Context, receiptID, participant string, settled bool) (*Split, error) {
+	path := receiptPath(receiptID) + "/split/participants/" + url.PathEscape(participant)
+	req, err := jsonRequest(http.MethodPatch, path, map[string]bool{"settled": settled})
+	if err != nil {
+		return nil, err
+	}
+	return c.split(ctx, req)
+}
+
+// ListUnsettledSplits 未精算の参加者が残る割り勘を取得
+func (c *Client) ListUnsettledSplits(ctx context.Context, page Page) ([]Split, error) {
+	var splits []Split
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/splits", query: page.values()}, &splits)
+	return splits, err
+}
+
+// GetSyncStatus レシートの会計サービスとの同期状態を取得
+func (c *Client) GetSyncStatus(ctx context.Context, receiptID string) ([]AccountingSync, error) {
+	var syncs []AccountingSync
+	err := c.do(ctx, request{method: http.MethodGet, path: receiptPath(receiptID) + "/sync"}, &syncs)
+	return syncs, err
+}
+
+// SyncReceipt レシートを会計サービス（freee / moneyforward）へ同期
+// 会計サービス側で変更されていた場合は、競合した同期状態と409の*APIErrorを返す
+func (c *Client) SyncReceipt(ctx context.Context, receiptID, provider string) (*AccountingSync, error) {
+	return c.accountingSync(ctx, request{method: http.MethodPost, path: syncPath(receiptID, provider)})
+}
+
+// ResolveSync 会計サービスとの競合を解決（strategy: local はレシートで上書き、remote は会計サービス側を正とする）
+func (c *Client) ResolveSync(ctx context.Context, receiptID, provider, strategy string) (*AccountingSync, error) {
+	req, err := jsonRequest(http.MethodPost, syncPath(receiptID, provider)+"/resolve", map[string]string{"strategy": strategy})
+	if err != nil {
+		return nil, err
+	}
+	return c.accountingSync(ctx, req)
+}
+
+// ListSyncs 会計サービスの同期状態を状態で絞り込んで取得
+func (c *Client) ListSyncs(ctx context.Context, provider string, params ListSyncsParams) ([]AccountingSync, error) {
+	query := params.Page.values()
+	if params.Status != "" {
+		query.Set("status", params.Status)
+	}
+	var syncs []AccountingSync
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/accounting/" + url.PathEscape(provider) + "/syncs", query: query}, &syncs)
+	return syncs, err
+}
+
+// Reconcile 銀行・クレジットカードの利用明細のCSVをレシートと突き合わせる
+// paymentMethodsを指定した場合は、明細に請求が載るはずのレシートをその支払い方法に限る
+func (c *Client) Reconcile(ctx context.Context, statement io.Reader, paymentMethods []string) (*Reconciliation, error) {
+	req := request{
+		method:      http.MethodPost,
+		path:        "/api/v1/reconciliations",
+		body:        statement,
+		contentType: "text/csv",
+	}
+	if len(paymentMethods) > 0 {
+		req.query = url.Values{"payment_method": paymentMethods}
+	}
+	var reconciliation Reconciliation
+	if err := c.do(ctx, req, &reconciliation); err != nil {
+		return nil, err
+	}
+	return &reconciliation, nil
+}
+
+// CreateDocument ファイルの内容から種類を判定し、レシートの画像は登録、利用明細のCSVはレシートと突き合わせる
+// paymentMethodsは利用明細の場合のみ使い、明細に請求が載るはずのレシートをその支払い方法に限る
+func (c *Client) CreateDocument(ctx context.Context, document io.Reader, paymentMethods []string) (*Document, error) {
+	req := request{
+		method:      http.MethodPost,
+		path:        "/api/v1/documents",
+		body:        document,
+		contentType: "application/octet-stream",
+	}
+	if len(paymentMethods) > 0 {
+		req.query = url.Values{"payment_method": paymentMethods}
+	}
+	var doc Document
+	if err := c.do(ctx, req, &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// PresignUpload アップロード用の署名付きURLを発行（機能フラグ direct_upload が必要）
+func (c *Client) PresignUpload(ctx context.Context) (*PresignedUpload, error) {
+	var upload PresignedUpload
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/api/v1/uploads/presign"}, &upload); err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
+
+// UploadImage 署名付きURL（PresignedUpload.UploadURL）へ画像本体をアップロード
+func (c *Client) UploadImage(ctx context.Context, uploadURL string, image []byte) (*UploadProgress, error) {
+	req := request{
+		method:      http.MethodPut,
+		path:        uploadURL,
+		body:        bytes.NewReader(image),
+		contentType: "application/octet-stream",
+	}
+	var progress UploadProgress
+	if err := c.do(ctx, req, &progress); err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+// UploadChunk 署名付きURLへ画像の一部（offsetから、全体はtotalバイト）を分割アップロード
+// 受信済みの位置と異なる場合は409の*APIErrorを返すため、GetUploadStatusで再開位置を確認する
+func (c *Client) UploadChunk(ctx context.Context, uploadURL string, chunk []byte, offset, total int64) (*UploadProgress, error) {
+	req := request{
+		method:      http.MethodPut,
+		path:        uploadURL,
+		body:        bytes.NewReader(chunk),
+		contentType: "application/octet-stream",
+		header: http.Header{
+			"Content-Range": {fmt.Sprintf("bytes %d-%d/%d", offset, offset+int64(len(chunk))-1, total)},
+		},
+	}
+	var progress UploadProgress
+	if err := c.do(ctx, req, &progress); err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+// GetUploadStatus 署名付きURLのアップロードの受信状況を取得
+func (c *Client) GetUploadStatus(ctx context.Context, uploadURL string) (*UploadProgress, error) {
+	var progress UploadProgress
+	if err := c.do(ctx, request{method: http.MethodGet, path: uploadURL}, &progress); err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+// CompleteUpload アップロード完了を通知（PresignedUpload.CompleteURL）してレシートを登録
+func (c *Client) CompleteUpload(ctx context.Context, completeURL string, opts ReceiptOptions) (*Receipt, error) {
+	req, err := jsonRequest(http.MethodPost, completeURL, map[string]any{
+		"tags":          opts.Tags,
+		"memo":          opts.Memo,
+		"keep_location": opts.KeepLocation,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.receipt(ctx, req)
+}
+
+// CategorizeExpenses 取り込んだ利用明細などの摘要のカテゴリーをまとめて判定（判定結果は保存しない）
+func (c *Client) CategorizeExpenses(ctx context.Context, descriptions []string) (*ExpenseCategorization, error) {
+	req, err := jsonRequest(http.MethodPost, "/api/v1/expenses/categorize", map[string]any{
+		"descriptions": descriptions,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var result ExpenseCategorization
+	if err := c.do(ctx, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListExpenses 家計簿エントリ一覧を日付の新しい順に取得
+func (c *Client) ListExpenses(ctx context.Context, params ListExpensesParams) ([]Expense, error) {
+	query := params.Page.values()
+	for key, value := range map[string]string{"category": params.Category, "from": params.From, "to": params.To} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	var expenses []Expense
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/expenses", query: query}, &expenses)
+	return expenses, err
+}
+
+// GetExpense 家計簿エントリを取得
+func (c *Client) GetExpense(ctx context.Context, id string) (*Expense, error) {
+	var expense Expense
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/expenses/" + url.PathEscape(id)}, &expense); err != nil {
+		return nil, err
+	}
+	return &expense, nil
+}
+
+// GetExpenseSummary 月（YYYY-MM、空の場合は当月）のレシートの明細・家計簿エントリをカテゴリーごとに集計
+func (c *Client) GetExpenseSummary(ctx context.Context, month string) (*ExpenseSummary, error) {
+	query := url.Values{}
+	if month != "" {
+		query.Set("month", month)
+	}
+	var summary ExpenseSummary
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/expenses/summary", query: query}, &summary); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// CreateExpense レシートに紐づかない家計簿エントリを登録
+func (c *Client) CreateExpense(ctx context.Context, input ExpenseInput) (*Expense, error) {
+	return c.sendExpense(ctx, http.MethodPost, "/api/v1/expenses", input)
+}
+
+// ReplaceExpense 家計簿エントリの編集できる項目をすべて置き換える
+func (c *Client) ReplaceExpense(ctx context.Context, id string, input ExpenseInput) (*Expense, error) {
+	return c.sendExpense(ctx, http.MethodPut, "/api/v1/expenses/"+url.PathEscape(id), input)
+}
+
+// sendExpense 家計簿エントリの登録・置き換えリクエストを送信
+func (c *Client) sendExpense(ctx context.Context, method, path string, input ExpenseInput) (*Expense, error) {
+	req, err := jsonRequest(method, path, input)
+	if err != nil {
+		return nil, err
+	}
+	var expense Expense
+	if err := c.do(ctx, req, &expense); err != nil {
+		return nil, err
+	}
+	return &expense, nil
+}
+
+// PatchExpense 家計簿エントリを部分的に修正
+func (c *Client) PatchExpense(ctx context.Context, id string, patch ExpensePatch) (*Expense, error) {
+	req, err := jsonRequest(http.MethodPatch, "/api/v1/expenses/"+url.PathEscape(id), patch)
+	if err != nil {
+		return nil, err
+	}
+	var expense Expense
+	if err := c.do(ctx, req, &expense); err != nil {
+		return nil, err
+	}
+	return &expense, nil
+}
+
+// DeleteExpense 家計簿エントリをゴミ箱に移す
+func (c *Client) DeleteExpense(ctx context.Context, id string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/api/v1/expenses/" + url.PathEscape(id)}, nil)
+}
+
+// ListTrash ゴミ箱の項目を削除日時の新しい順に取得（kindが空の場合はすべての種類）
+func (c *Client) ListTrash(ctx context.Context, kind string, page Page) ([]TrashEntry, error) {
+	query := page.values()
+	if kind != "" {
+		query.Set("kind", kind)
+	}
+	var entries []TrashEntry
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/trash", query: query}, &entries)
+	return entries, err
+}
+
+// RestoreTrash ゴミ箱の項目をまとめて元に戻す
+func (c *Client) RestoreTrash(ctx context.Context, items []TrashItem) ([]TrashResult, error) {
+	return c.trashBatch(ctx, "/api/v1/trash/restore", items)
+}
+
+// PurgeTrash ゴミ箱の項目をまとめて完全に削除する
+func (c *Client) PurgeTrash(ctx context.Context, items []TrashItem) ([]TrashResult, error) {
+	return c.trashBatch(ctx, "/api/v1/trash/purge", items)
+}
+
+// trashBatch ゴミ箱の一括操作のリクエストを送信
+func (c *Client) trashBatch(ctx context.Context, path string, items []TrashItem) ([]TrashResult, error) {
+	req, err := jsonRequest(http.MethodPost, path, map[string][]TrashItem{"items": items})
+	if err != nil {
+		return nil, err
+	}
+	var results []TrashResult
+	err = c.do(ctx, req, &results)
+	return results, err
+}
+
+// receipt レシートを返すAPIのリクエストを送信
+func (c *Client) receipt(ctx context.Context, req request) (*Receipt, error) {
+	var receipt Receipt
+	if err := c.do(ctx, req, &receipt); err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+// split 割り勘を返すAPIのリクエストを送信
+func (c *Client) split(ctx context.Context, req request) (*Split, error) {
+	var split Split
+	if err := c.do(ctx, req, &split); err != nil {
+		return nil, err
+	}
+	return &split, nil
+}
+
+// accountingSync 同期状態を返すAPIのリクエストを送信（409の場合も同期状態を返す）
+func (c *Client) accountingSync(ctx context.Context, req request) (*AccountingSync, error) {
+	var sync AccountingSync
+	err := c.do(ctx, req, &sync)
+	if sync.ReceiptID == "" {
+		return nil, err
+	}
+	return &sync, err
+}
+
+// receiptPath レシートのパス
+func receiptPath(id string) string {
+	return "/api/v1/receipts/" + url.PathEscape(id)
+}
+
+// categoryPath カテゴリーのパス
+func categoryPath(name string) string {
+	return "/api/v1/categories/" + url.PathEscape(name)
+}
+
+// syncPath 会計サービスへの同期のパス
+func syncPath(receiptID, provider string) string {
+	return receiptPath(receiptID) + "/sync/" + url.PathEscape(provider)
+}
+
+// values 一覧の取得範囲のクエリパラメーター
+func (p Page) values() url.Values {
+	query := url.Values{}
+	if p.Limit > 0 {
+		query.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Offset > 0 {
+		query.Set("offset", strconv.Itoa(p.Offset))
+	}
+	return query
+}
@@ -0,0 +1,32 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// GetSpendStats 地域・月ごとの1世帯あたりの平均の食費を取得（from・toはYYYY-MM、空の場合はサーバーのデフォルト、regionが空の場合はすべての地域）
+func (c *Client) GetSpendStats(ctx context.Context, from, to, region string) (*PublicSpendStats, error) {
+	query := url.Values{}
+	for key, value := range map[string]string{"from": from, "to": to, "region": region} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	var stats PublicSpendStats
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/public/stats/spend", query: query}, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// ContributeSpend 月の食費の合計を公開統計に提供する（APIキーが必要、同じ世帯・月の集計値は置き換える）
+func (c *Client) ContributeSpend(ctx context.Context, contribution SpendContribution) error {
+	req, err := jsonRequest(http.MethodPost, "/api/v1/public/stats/contributions", contribution)
+	if err != nil {
+		return err
+	}
+	req.apiKey = true
+	return c.do(ctx, req, nil)
+}
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// GetMonthlyReport 月別・カテゴリ別の支出集計を取得
+func (c *Client) GetMonthlyReport(ctx context.Context, params MonthlyReportParams) (*MonthlyReport, error) {
+	query := url.Values{}
+	setInt(query, "year", params.Year)
+	setInt(query, "month_start_day", params.MonthStartDay)
+	var report MonthlyReport
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/reports/monthly", query: query}, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// GetSummaryReport 任意の期間の支出を日・週・月ごとにカテゴリ別集計
+func (c *Client) GetSummaryReport(ctx context.Context, params SummaryReportParams) (*PeriodReport, error) {
+	query := url.Values{
+		"from": {params.From.Format(time.DateOnly)},
+		"to":   {params.To.Format(time.DateOnly)},
+	}
+	if params.GroupBy != "" {
+		query.Set("group_by", params.GroupBy)
+	}
+	setInt(query, "month_start_day", params.MonthStartDay)
+	var report PeriodReport
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/reports/summary", query: query}, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// GetCalendarReport 日ごとのレシートの枚数と合計金額を取得（yearが0の場合は今年、monthが0の場合は年全体）
+func (c *Client) GetCalendarReport(ctx context.Context, year, month int) (*Calendar, error) {
+	query := url.Values{}
+	setInt(query, "year", year)
+	setInt(query, "month", month)
+	var calendar Calendar
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/reports/calendar", query: query}, &calendar); err != nil {
+		return nil, err
+	}
+	return &calendar, nil
+}
+
+// GetWidgetData ダッシュボードのウィジェット向けに指標・系列・期間を指定してレシートの明細を集計
+func (c *Client) GetWidgetData(ctx context.Context, params WidgetParams) (*WidgetData, error) {
+	query := url.Values{
+		"from": {params.From.Format(time.DateOnly)},
+		"to":   {params.To.Format(time.DateOnly)},
+	}
+	for key, value := range map[string]string{
+		"metric":         params.Metric,
+		"group_by":       params.GroupBy,
+		"period":         params.Period,
+		"category":       params.Category,
+		"store":          params.Store,
+		"payment_method": params.PaymentMethod,
+		"tag":            params.Tag,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	setInt(query, "month_start_day", params.MonthStartDay)
+	setInt(query, "limit", params.Limit)
+	var data WidgetData
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/reports/widget", query: query}, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// DownloadCategoryChart カテゴリー別の支出の円グラフをPNGでダウンロード
+func (c *Client) DownloadCategoryChart(ctx context.Context, params CategoryChartParams) (*Download, error) {
+	query := url.Values{}
+	if !params.From.IsZero() {
+		query.Set("from", params.From.Format(time.DateOnly))
+	}
+	if !params.To.IsZero() {
+		query.Set("to", params.To.Format(time.DateOnly))
+	}
+	setInt(query, "width", params.Width)
+	setInt(query, "height", params.Height)
+	return c.download(ctx, request{method: http.MethodGet, path: "/api/v1/reports/charts/categories", query: query})
+}
+
+// DownloadMonthlyChart 月別の支出の推移の折れ線グラフをPNGでダウンロード
+func (c *Client) DownloadMonthlyChart(ctx context.Context, params MonthlyChartParams) (*Download, error) {
+	query := url.Values{}
+	setInt(query, "year", params.Year)
+	setInt(query, "month_start_day", params.MonthStartDay)
+	setInt(query, "width", params.Width)
+	setInt(query, "height", params.Height)
+	return c.download(ctx, request{method: http.MethodGet, path: "/api/v1/reports/charts/monthly", query: query})
+}
+
+// GetMedicalDeductionReport 医療費控除の明細を取得（yearが0の場合は前年）
+func (c *Client) GetMedicalDeductionReport(ctx context.Context, year int) (*MedicalDeductionReport, error) {
+	query := url.Values{}
+	setInt(query, "year", year)
+	var report MedicalDeductionReport
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/reports/medical-deduction", query: query}, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// DownloadMedicalDeductionReport 医療費控除の明細をファイル（format: csv / xlsx）としてダウンロード
+func (c *Client) DownloadMedicalDeductionReport(ctx context.Context, year int, format string) (*Download, error) {
+	query := url.Values{"format": {format}}
+	setInt(query, "year", year)
+	return c.download(ctx, request{method: http.MethodGet, path: "/api/v1/reports/medical-deduction", query: query})
+}
+
+// DownloadLedger レシートを複式簿記の仕訳（format: hledger / beancount）としてダウンロード
+func (c *Client) DownloadLedger(ctx context.Context, params PeriodParams) (*Download, error) {
+	return c.download(ctx, request{method: http.MethodGet, path: "/api/v1/reports/ledger", query: params.values()})
+}
+
+// ExportReceipts レシートの明細（format: csv / xlsx）をダウンロード
+func (c *Client) ExportReceipts(ctx context.Context, params PeriodParams) (*Download, error) {
+	return c.download(ctx, request{method: http.MethodGet, path: "/api/v1/reports/export", query: params.values()})
+}
+
+// GetShoppingList 購入パターンからdays日先までに買いそうな商品を取得（daysが0以下の場合はサーバーのデフォルト）
+func (c *Client) GetShoppingList(ctx context.Context, days int) ([]ShoppingSuggestion, error) {
+	query := url.Values{}
+	setInt(query, "days", days)
+	var suggestions []ShoppingSuggestion
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/suggestions/shopping-list", query: query}, &suggestions)
+	return suggestions, err
+}
+
+// values ダウンロードする期間と形式のクエリパラメーター
+func (p PeriodParams) values() url.Values {
+	query := url.Values{}
+	setInt(query, "year", p.Year)
+	setInt(query, "month", p.Month)
+	if p.Format != "" {
+		query.Set("format", p.Format)
+	}
+	return query
+}
+
+// setInt 正の値の場合のみクエリパラメーターを設定
+func setInt(query url.Values, key string, value int) {
+	if value > 0 {
+		query.Set(key, strconv.Itoa(value))
+	}
+}
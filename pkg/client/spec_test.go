@@ -0,0 +1,52 @@
+package client
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TestClient_CoversSpec api/openapi.yaml のすべてのオペレーションにメソッドがあるかチェック
+func TestClient_CoversSpec(t *testing.T) {
+	data, err := os.ReadFile("../../api/openapi.yaml")
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	var spec struct {
+		Paths map[string]map[string]yaml.Node `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	clientType := reflect.TypeOf(&Client{})
+	operations := 0
+	for path, methods := range spec.Paths {
+		for method, node := range methods {
+			if method == "parameters" {
+				continue // パス共通のパラメーター
+			}
+			var op struct {
+				OperationID string `yaml:"operationId"`
+			}
+			if err := node.Decode(&op); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if op.OperationID == "" {
+				t.Errorf("%s %s: operationId is missing", strings.ToUpper(method), path)
+				continue
+			}
+			operations++
+			name := strings.ToUpper(op.OperationID[:1]) + op.OperationID[1:]
+			if _, ok := clientType.MethodByName(name); !ok {
+				t.Errorf("%s %s: Client.%s is missing", strings.ToUpper(method), path, name)
+			}
+		}
+	}
+	if operations == 0 {
+		t.Error("Expected operations in the spec")
+	}
+}
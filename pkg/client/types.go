@@ -0,0 +1,992 @@
+package client
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// HealthStatus ヘルスチェックの結果
+type HealthStatus struct {
+	Status  string `json:"status"`
+	Version string `json:"version"`
+}
+
+// ReadyStatus レディネスチェックの結果
+type ReadyStatus struct {
+	Status       string            `json:"status"` // ready / degraded / unavailable
+	Checks       map[string]string `json:"checks"`
+	PendingSaves int               `json:"pending_saves"` // データベースへの保存を待っているレシート数
+}
+
+// VisionResult 画像認識・カテゴリ判定の結果
+type VisionResult struct {
+	Success    bool      `json:"success"`
+	Text       string    `json:"text"`
+	Tokens     *AITokens `json:"tokens,omitempty"`
+	Model      string    `json:"model,omitempty"`       // 処理したAIのモデル（キャッシュした結果の場合は空）
+	StopReason string    `json:"stop_reason,omitempty"` // AIの出力の終了理由（end_turn、max_tokensなど）
+	Cached     bool      `json:"cached"`                // キャッシュした結果を返した（X-Cache: HIT）
+	DurationMs int64     `json:"duration_ms"`           // 処理時間（ミリ秒）
+	Error      string    `json:"error,omitempty"`
+}
+
+// CompareImage 比較する画像
+type CompareImage struct {
+	Body     io.Reader
+	Filename string
+}
+
+// CompareResult 2つの画像から抽出したテキストの差分
+type CompareResult struct {
+	Success    bool        `json:"success"`
+	Before     CompareText `json:"before"`
+	After      CompareText `json:"after"`
+	Diff       TextDiff    `json:"diff"`
+	DurationMs int64       `json:"duration_ms"`
+}
+
+// CompareText 比較した画像から抽出したテキスト
+type CompareText struct {
+	Text   string    `json:"text"`
+	Tokens *AITokens `json:"tokens"`
+	Cached bool      `json:"cached"`
+}
+
+// TextDiff 行単位の差分
+type TextDiff struct {
+	Changed   bool       `json:"changed"`
+	Added     int        `json:"added"`
+	Removed   int        `json:"removed"`
+	Unchanged int        `json:"unchanged"`
+	Lines     []DiffLine `json:"lines"`
+}
+
+// DiffLine 差分の1行（op: equal / insert / delete）
+type DiffLine struct {
+	Op         string `json:"op"`
+	Text       string `json:"text"`
+	BeforeLine int    `json:"before_line,omitempty"`
+	AfterLine  int    `json:"after_line,omitempty"`
+}
+
+// AITokens AIの使用トークン数
+type AITokens struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
+
+// Receipt レシート
+type Receipt struct {
+	ID                string        `json:"id"`
+	StoreName         string        `json:"store_name"`
+	PurchaseDate      time.Time     `json:"purchase_date"`
+	TotalAmount       int64         `json:"total_amount"`
+	TaxAmount         int64         `json:"tax_amount"`
+	PaymentMethod     string        `json:"payment_method"`
+	ReceiptNumber     string        `json:"receipt_number"`
+	ReceiptType       string        `json:"receipt_type"`       // purchase（購入）・refund（返品、金額は0以下）
+	Locale            string        `json:"locale,omitempty"`   // レシートのロケール（BCP 47）
+	Currency          string        `json:"currency,omitempty"` // レシートに印字された通貨（ISO 4217）
+	Category          string        `json:"category"`
+	NeedsReview       bool          `json:"needs_review"`
+	JSONRepaired      bool          `json:"json_repaired,omitempty"`     // AIの応答のJSONを修復して読み取った
+	DateOutOfRange    bool          `json:"date_out_of_range,omitempty"` // 読み取った購入日が妥当な範囲外だった
+	Extraction        *Extraction   `json:"extraction,omitempty"`        // AIによる読み取りの記録（手動登録のレシートはnil）
+	CategorizationRaw string        `json:"categorization_raw,omitempty"`
+	Tags              []string      `json:"tags"`
+	Memo              string        `json:"memo"`
+	Items             []ReceiptItem `json:"items"`
+	CreatedAt         time.Time     `json:"created_at"`
+	UpdatedAt         time.Time     `json:"updated_at"`
+}
+
+// Extraction AIによるレシート画像の読み取りの記録
+type Extraction struct {
+	Model      string `json:"model,omitempty"`       // 読み取ったAIのモデル（キャッシュした結果の場合は空）
+	StopReason string `json:"stop_reason,omitempty"` // AIの出力の終了理由（end_turn、max_tokensなど）
+	Cached     bool   `json:"cached"`                // キャッシュした読み取り結果を使った
+	DurationMs int64  `json:"duration_ms"`           // 読み取りにかかった時間（ミリ秒）
+}
+
+// ExtractionComparison AIの出力の原文と、原文を解析し直したレシート・現在のレシートの比較
+type ExtractionComparison struct {
+	Raw           string   `json:"raw"`            // AIの出力の原文
+	Extracted     Receipt  `json:"extracted"`      // 原文を現在の解析処理で読み取ったレシート
+	Current       Receipt  `json:"current"`        // 保存されている現在のレシート
+	ChangedFields []string `json:"changed_fields"` // 読み取った値と現在の値が異なる項目
+}
+
+// Backfill 保存済みの元画像からの読み直しの進み具合
+type Backfill struct {
+	ID               string    `json:"id"`
+	State            string    `json:"state"` // running・completed・failed
+	Total            int       `json:"total"`
+	Succeeded        int       `json:"succeeded"`
+	Failed           int       `json:"failed"`
+	FailedReceiptIDs []string  `json:"failed_receipt_ids"`
+	Batches          int       `json:"batches"`
+	Error            string    `json:"error,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// ReceiptItem レシートの明細
+type ReceiptItem struct {
+	ID             string  `json:"id"`
+	Name           string  `json:"name"`
+	Quantity       int     `json:"quantity"`
+	Price          int64   `json:"price"`                // 単価（量り売りの明細は計量した金額）
+	Unit           string  `json:"unit"`                 // 数量の単位（個・g・kg）
+	Measure        float64 `json:"measure,omitempty"`    // 量り売りの計量値
+	UnitPrice      int64   `json:"unit_price,omitempty"` // 量り売りの1kgあたりの単価
+	Category       string  `json:"category"`
+	CategoryStatus string  `json:"category_status"`
+	WarrantyMonths *int    `json:"warranty_months,omitempty"`
+}
+
+// ReceiptPatch レシートの部分修正（nilのフィールドは変更しない）
+type ReceiptPatch struct {
+	StoreName    *string             `json:"store_name,omitempty"`
+	PurchaseDate *time.Time          `json:"purchase_date,omitempty"`
+	TotalAmount  *int64              `json:"total_amount,omitempty"`
+	ReceiptType  *string             `json:"receipt_type,omitempty"` // purchase・refund
+	Tags         *[]string           `json:"tags,omitempty"`
+	Memo         *string             `json:"memo,omitempty"`
+	Items        *[]ReceiptItemPatch `json:"items,omitempty"` // 明細を置き換える
+}
+
+// ReceiptItemPatch 明細の修正（IDを省略した明細は追加扱い）
+type ReceiptItemPatch struct {
+	ID             string   `json:"id,omitempty"`
+	Name           string   `json:"name"`
+	Quantity       int      `json:"quantity"`
+	Price          int64    `json:"price"`
+	Unit           string   `json:"unit,omitempty"`       // 省略時は既存の明細の単位・計量値・単価を引き継ぐ
+	Measure        *float64 `json:"measure,omitempty"`    // 量り売りの計量値
+	UnitPrice      *int64   `json:"unit_price,omitempty"` // 量り売りの1kgあたりの単価
+	Category       string   `json:"category,omitempty"`
+	WarrantyMonths *int     `json:"warranty_months,omitempty"` // 保証期間（月数）、0は保証なし
+}
+
+// ReceiptDraft 読み取ったまま保存していないレシートの下書き
+type ReceiptDraft struct {
+	DraftToken  string    `json:"draft_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	DuplicateOf string    `json:"duplicate_of,omitempty"` // 同じ画像から登録済みのレシートのID
+	Receipt     Receipt   `json:"receipt"`
+}
+
+// ReceiptRevision レシートの変更履歴
+type ReceiptRevision struct {
+	Revision  int       `json:"revision"`
+	Source    string    `json:"source"`
+	Receipt   Receipt   `json:"receipt"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReceiptMerge レシートの統合の記録
+type ReceiptMerge struct {
+	MergedReceiptID string    `json:"merged_receipt_id"`
+	MergedReceipt   Receipt   `json:"merged_receipt"` // 統合した時点の削除したレシート
+	AddedItemIDs    []string  `json:"added_item_ids"` // 残したレシートに追加した明細のID
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// MergeResult レシートの統合の結果
+type MergeResult struct {
+	Receipt Receipt      `json:"receipt"`
+	Merge   ReceiptMerge `json:"merge"`
+}
+
+// UnmergeResult 統合の取り消しの結果
+type UnmergeResult struct {
+	Receipt  Receipt `json:"receipt"`  // 統合前の状態に戻したレシート
+	Restored Receipt `json:"restored"` // 登録し直したレシート
+}
+
+// ReceiptOptions レシートの登録時に付与する情報
+type ReceiptOptions struct {
+	Tags         []string
+	Memo         string
+	KeepLocation bool      // 位置情報などのメタデータを画像に残す
+	CreatedAt    time.Time // 過去のレシートとして登録する日時（CreateReceiptのみ、ゼロ値の場合は現在時刻）
+}
+
+// StorageUsage 元画像の保存容量の使用状況
+type StorageUsage struct {
+	UsedBytes      int64  `json:"used_bytes"`
+	QuotaBytes     int64  `json:"quota_bytes"`               // 0は無制限
+	RemainingBytes *int64 `json:"remaining_bytes,omitempty"` // 無制限の場合はnil
+	Images         int    `json:"images"`
+	Exceeded       bool   `json:"exceeded"`
+}
+
+// CategoryItem カテゴリー別の明細
+type CategoryItem struct {
+	ID             string    `json:"id"`
+	ReceiptID      string    `json:"receipt_id"`
+	Name           string    `json:"name"`
+	Quantity       int       `json:"quantity"`
+	Price          int64     `json:"price"`
+	Amount         int64     `json:"amount"` // 単価×数量
+	Category       string    `json:"category"`
+	CategoryStatus string    `json:"category_status"`
+	StoreName      string    `json:"store_name"`
+	PurchaseDate   time.Time `json:"purchase_date"`
+}
+
+// PriceHistory 商品の価格推移
+type PriceHistory struct {
+	Name           string       `json:"name"`
+	NormalizedName string       `json:"normalized_name"`
+	Points         []PricePoint `json:"points"` // 購入日の古い順
+	Stores         []StorePrice `json:"stores"` // 平均単価の安い順
+}
+
+// ItemAlias 商品名の別名（OCRで読み取った略称など）と正式な商品名の対応
+type ItemAlias struct {
+	Alias         string    `json:"alias"`
+	CanonicalName string    `json:"canonical_name"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// PricePoint 購入1回分の単価
+type PricePoint struct {
+	Date      time.Time `json:"date"`
+	StoreName string    `json:"store_name"`
+	ReceiptID string    `json:"receipt_id"`
+	Name      string    `json:"name"`
+	Price     int64     `json:"price"`
+	Quantity  int       `json:"quantity"`
+}
+
+// StorePrice 店舗別の単価
+type StorePrice struct {
+	StoreName  string    `json:"store_name"`
+	Count      int       `json:"count"`
+	MinPrice   int64     `json:"min_price"`
+	MaxPrice   int64     `json:"max_price"`
+	AvgPrice   int64     `json:"avg_price"`
+	LastPrice  int64     `json:"last_price"`
+	LastBought time.Time `json:"last_bought"`
+}
+
+// ExpiringItem 返品・保証期限が近い明細
+type ExpiringItem struct {
+	ReceiptID      string    `json:"receipt_id"`
+	ItemID         string    `json:"item_id"`
+	Name           string    `json:"name"`
+	Price          int64     `json:"price"`
+	StoreName      string    `json:"store_name"`
+	PurchaseDate   time.Time `json:"purchase_date"`
+	WarrantyMonths *int      `json:"warranty_months,omitempty"`
+	Kind           string    `json:"kind"` // return: 返品期限, warranty: 保証期限
+	Deadline       time.Time `json:"deadline"`
+}
+
+// CalendarFeed 返品・保証期限のiCalendarのフィードの購読URL
+type CalendarFeed struct {
+	FeedURL string `json:"feed_url"` // ホストを除いたURL（トークンを含む）
+}
+
+// SplitRequest 割り勘の設定
+type SplitRequest struct {
+	Payer        string                    `json:"payer,omitempty"`
+	Participants []SplitParticipantRequest `json:"participants"`
+}
+
+// SplitParticipantRequest 参加者と負担する明細
+type SplitParticipantRequest struct {
+	Name    string   `json:"name"`
+	ItemIDs []string `json:"item_ids"`
+}
+
+// Split 割り勘と精算状態
+type Split struct {
+	ReceiptID      string             `json:"receipt_id"`
+	Payer          string             `json:"payer,omitempty"`
+	Participants   []SplitParticipant `json:"participants"`
+	Settled        bool               `json:"settled"`         // 全員の精算が済んでいるか
+	UnsettledTotal int64              `json:"unsettled_total"` // 未精算の負担額の合計
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+}
+
+// SplitParticipant 参加者の負担額と精算状態
+type SplitParticipant struct {
+	Name      string     `json:"name"`
+	ItemIDs   []string   `json:"item_ids"`
+	Subtotal  int64      `json:"subtotal"`
+	Tax       int64      `json:"tax"`
+	Total     int64      `json:"total"`
+	Settled   bool       `json:"settled"`
+	SettledAt *time.Time `json:"settled_at,omitempty"`
+}
+
+// AccountingSync 会計サービスとの同期状態
+type AccountingSync struct {
+	ReceiptID  string     `json:"receipt_id"`
+	Provider   string     `json:"provider"`
+	Status     string     `json:"status"` // synced / failed / conflict
+	ExternalID string     `json:"external_id,omitempty"`
+	Attempts   int        `json:"attempts"`
+	LastError  string     `json:"last_error,omitempty"`
+	SyncedAt   *time.Time `json:"synced_at,omitempty"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// ListSyncsParams 同期状態の一覧の絞り込み
+type ListSyncsParams struct {
+	Status string // synced / failed / conflict（空の場合は conflict）
+	Page
+}
+
+// StatementLine 利用明細の行
+type StatementLine struct {
+	Row         int       `json:"row"` // CSVの行番号
+	Date        time.Time `json:"date"`
+	Description string    `json:"description"`
+	Amount      int64     `json:"amount"`
+}
+
+// ReconciledReceipt 突き合わせたレシート
+type ReconciledReceipt struct {
+	ID            string    `json:"id"`
+	StoreName     string    `json:"store_name"`
+	PurchaseDate  time.Time `json:"purchase_date"`
+	TotalAmount   int64     `json:"total_amount"`
+	PaymentMethod string    `json:"payment_method"`
+}
+
+// ReconciliationMatch 利用明細の行と対応するレシート
+type ReconciliationMatch struct {
+	Line         StatementLine     `json:"line"`
+	Receipt      ReconciledReceipt `json:"receipt"`
+	DaysApart    int               `json:"days_apart"`
+	StoreMatched bool              `json:"store_matched"`
+}
+
+// Document 自動で振り分けたファイルの処理結果（Typeに対応するフィールドのみ設定される）
+type Document struct {
+	Type           string          `json:"type"` // receipt・statement
+	Receipt        *Receipt        `json:"receipt,omitempty"`
+	Reconciliation *Reconciliation `json:"reconciliation,omitempty"`
+}
+
+// Reconciliation 利用明細とレシートの突き合わせ結果
+type Reconciliation struct {
+	Start             time.Time             `json:"start"`
+	End               time.Time             `json:"end"`
+	Matches           []ReconciliationMatch `json:"matches"`
+	UnmatchedLines    []StatementLine       `json:"unmatched_lines"`    // レシートのない支払い
+	UnmatchedReceipts []ReconciledReceipt   `json:"unmatched_receipts"` // 明細に請求がないレシート
+	Skipped           int                   `json:"skipped"`            // 入金・返金など対象外の行数
+}
+
+// Collection レシートのコレクション（フォルダー）
+type Collection struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ReceiptIDs  []string  `json:"receipt_ids"` // 追加した順
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CollectionPatch コレクションの作成・部分更新（nilのフィールドは変更しない）
+type CollectionPatch struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// CollectionReport コレクションに含めるレシートの集計
+type CollectionReport struct {
+	Collection   Collection        `json:"collection"`
+	ReceiptCount int               `json:"receipt_count"`
+	Total        int64             `json:"total"`
+	Tax          int64             `json:"tax"`
+	Start        *time.Time        `json:"start,omitempty"` // 最も古いレシートの購入日時
+	End          *time.Time        `json:"end,omitempty"`   // 最も新しいレシートの購入日時
+	Categories   []CategorySummary `json:"categories"`
+}
+
+// ExpenseReport 経費報告書
+type ExpenseReport struct {
+	ID           string                 `json:"id"`
+	Title        string                 `json:"title"`
+	Applicant    string                 `json:"applicant"`
+	Status       string                 `json:"status"` // draft / submitted / approved / reimbursed
+	ReceiptIDs   []string               `json:"receipt_ids"`
+	Receipts     []ExpenseReportReceipt `json:"receipts,omitempty"` // 含まれるレシートの概要（一覧では空）
+	TotalAmount  int64                  `json:"total_amount"`       // 申請時に確定した合計金額（作成中は0）
+	Approver     string                 `json:"approver,omitempty"`
+	ReimbursedBy string                 `json:"reimbursed_by,omitempty"`
+	Comment      string                 `json:"comment,omitempty"`
+	SubmittedAt  *time.Time             `json:"submitted_at,omitempty"`
+	ApprovedAt   *time.Time             `json:"approved_at,omitempty"`
+	ReimbursedAt *time.Time             `json:"reimbursed_at,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+}
+
+// ExpenseReportReceipt 経費報告書に含まれるレシートの概要
+type ExpenseReportReceipt struct {
+	ID           string    `json:"id"`
+	StoreName    string    `json:"store_name"`
+	PurchaseDate time.Time `json:"purchase_date"`
+	TotalAmount  int64     `json:"total_amount"`
+	Refund       bool      `json:"refund"`
+	NeedsReview  bool      `json:"needs_review"`
+}
+
+// ExpenseReportCreate 経費報告書の作成リクエスト
+type ExpenseReportCreate struct {
+	Title      string   `json:"title"`
+	Applicant  string   `json:"applicant"`
+	ReceiptIDs []string `json:"receipt_ids,omitempty"`
+}
+
+// ExpenseReportPatch 経費報告書の部分更新（nilのフィールドは変更しない）
+type ExpenseReportPatch struct {
+	Title      *string   `json:"title,omitempty"`
+	Applicant  *string   `json:"applicant,omitempty"`
+	ReceiptIDs *[]string `json:"receipt_ids,omitempty"`
+}
+
+// ListExpenseReportsParams 経費報告書の一覧の絞り込み
+type ListExpenseReportsParams struct {
+	Status string // draft / submitted / approved / reimbursed（空の場合はすべて）
+	Page
+}
+
+// PresignedUpload アップロード用の署名付きURL
+type PresignedUpload struct {
+	UploadID    string    `json:"upload_id"`
+	Method      string    `json:"method"`
+	UploadURL   string    `json:"upload_url"`
+	CompleteURL string    `json:"complete_url"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	MaxSize     int64     `json:"max_size"`
+}
+
+// UploadProgress アップロードの受信状況
+type UploadProgress struct {
+	UploadID string `json:"upload_id"`
+	Offset   int64  `json:"offset"`
+	Complete bool   `json:"complete"`
+}
+
+// Expense 家計簿エントリ
+type Expense struct {
+	ID          string    `json:"id"`
+	ReceiptID   *string   `json:"receipt_id"`
+	Date        time.Time `json:"date"`
+	Category    string    `json:"category"`
+	Amount      int64     `json:"amount"`
+	Description string    `json:"description"`
+	Tags        []string  `json:"tags"`
+	Memo        string    `json:"memo"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ListExpensesParams 家計簿エントリ一覧の絞り込み条件
+type ListExpensesParams struct {
+	Category string
+	From     string // 日付の範囲の開始日（YYYY-MM-DD、この日を含む）
+	To       string // 日付の範囲の終了日（YYYY-MM-DD、この日を含む）
+	Page
+}
+
+// ExpenseInput 家計簿エントリの登録・置き換えの内容（置き換えでは省略した項目は空になる）
+type ExpenseInput struct {
+	Date        string   `json:"date"` // RFC3339、またはYYYY-MM-DD
+	Category    string   `json:"category"`
+	Amount      int64    `json:"amount"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Memo        string   `json:"memo,omitempty"`
+}
+
+// ExpensePatch 家計簿エントリの部分修正（nilのフィールドは変更しない）
+type ExpensePatch struct {
+	Memo *string `json:"memo,omitempty"`
+}
+
+// ExpenseSummary 月のカテゴリーごとの支出の集計
+type ExpenseSummary struct {
+	Month      string                 `json:"month"` // YYYY-MM
+	Start      time.Time              `json:"start"`
+	End        time.Time              `json:"end"` // 翌月の初日の0時（この日時を含まない）
+	Count      int                    `json:"count"`
+	Total      int64                  `json:"total"`
+	Categories []ExpenseCategoryShare `json:"categories"` // 金額の大きい順
+}
+
+// ExpenseCategoryShare カテゴリーごとの支出の集計
+type ExpenseCategoryShare struct {
+	Category     string  `json:"category"`
+	Count        int     `json:"count"`
+	Total        int64   `json:"total"`
+	Share        float64 `json:"share"` // 月の合計に対する割合（%）
+	ReceiptCount int     `json:"receipt_count"`
+	ItemCount    int     `json:"item_count"`
+	ItemTotal    int64   `json:"item_total"`
+	ExpenseCount int     `json:"expense_count"`
+	ExpenseTotal int64   `json:"expense_total"`
+}
+
+// CategorySuggestion 摘要ごとのカテゴリーの判定結果
+type CategorySuggestion struct {
+	Description string `json:"description"`
+	Category    string `json:"category"`
+	Status      string `json:"status"` // auto / auto_failed（判定できなかった場合はその他）
+}
+
+// Category カテゴリー
+type Category struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Color       string    `json:"color"` // #RRGGBB
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CategoryInput カテゴリーの登録・置き換えの内容（置き換えでは省略した説明・表示色は空になる）
+type CategoryInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Color       string `json:"color,omitempty"` // #RRGGBB
+}
+
+// ExpenseCategorization 摘要のカテゴリーの一括判定結果（Suggestionsはリクエストと同じ順番）
+type ExpenseCategorization struct {
+	Suggestions []CategorySuggestion `json:"suggestions"`
+	Tokens      AITokens             `json:"tokens"`
+}
+
+// TrashEntry ゴミ箱の項目
+type TrashEntry struct {
+	Kind          string    `json:"kind"` // receipt / expense
+	ID            string    `json:"id"`
+	DeletedAt     time.Time `json:"deleted_at"`
+	PurgeAt       time.Time `json:"purge_at"`
+	RemainingDays int       `json:"remaining_days"` // 完全に削除されるまでの日数
+	Receipt       *Receipt  `json:"receipt,omitempty"`
+	Expense       *Expense  `json:"expense,omitempty"`
+}
+
+// TrashItem ゴミ箱の一括操作で指定する項目
+type TrashItem struct {
+	Kind string `json:"kind"`
+	ID   string `json:"id"`
+}
+
+// TrashResult ゴミ箱の一括操作の項目ごとの結果
+type TrashResult struct {
+	Kind   string `json:"kind"`
+	ID     string `json:"id"`
+	Status string `json:"status"` // restored / purged / not_found / conflict / invalid / failed
+}
+
+// ReceiptInterchange インスタンス間でレシートを移行するための文書（書き出したものをそのまま読み込める）
+// 金額はすべてCurrencyの最小単位の整数
+type ReceiptInterchange struct {
+	SchemaVersion int                  `json:"schema_version"`
+	ExportedAt    time.Time            `json:"exported_at"`
+	Currency      string               `json:"currency"` // 金額の通貨（書き出したインスタンスの設定の通貨）
+	Receipt       *InterchangeReceipt  `json:"receipt"`
+	Expenses      []InterchangeExpense `json:"expenses"`
+}
+
+// InterchangeReceipt 移行用の文書のレシート
+type InterchangeReceipt struct {
+	ID            string            `json:"id"`
+	StoreName     string            `json:"store_name"`
+	PurchaseDate  time.Time         `json:"purchase_date"`
+	TotalAmount   int64             `json:"total_amount"`
+	TaxAmount     int64             `json:"tax_amount"`
+	PaymentMethod string            `json:"payment_method,omitempty"`
+	ReceiptNumber string            `json:"receipt_number,omitempty"`
+	ReceiptType   string            `json:"receipt_type,omitempty"`
+	Locale        string            `json:"locale,omitempty"`
+	Currency      string            `json:"currency,omitempty"` // レシートに印字された通貨
+	Category      string            `json:"category,omitempty"`
+	NeedsReview   bool              `json:"needs_review"`
+	Tags          []string          `json:"tags"`
+	Memo          string            `json:"memo,omitempty"`
+	ImageHash     string            `json:"image_hash,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+	Items         []InterchangeItem `json:"items"`
+}
+
+// InterchangeItem 移行用の文書の明細
+type InterchangeItem struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	Quantity       int       `json:"quantity"`
+	Price          int64     `json:"price"`
+	Unit           string    `json:"unit,omitempty"`
+	Measure        float64   `json:"measure,omitempty"`
+	UnitPrice      int64     `json:"unit_price,omitempty"`
+	Category       string    `json:"category,omitempty"`
+	CategoryStatus string    `json:"category_status,omitempty"`
+	WarrantyMonths *int      `json:"warranty_months,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// InterchangeExpense 移行用の文書のレシートに紐づく家計簿エントリ
+type InterchangeExpense struct {
+	ID          string    `json:"id"`
+	Date        time.Time `json:"date"`
+	Category    string    `json:"category"`
+	Amount      int64     `json:"amount"`
+	Description string    `json:"description,omitempty"`
+	Tags        []string  `json:"tags"`
+	Memo        string    `json:"memo,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CategorySummary カテゴリ別の集計
+type CategorySummary struct {
+	Category string `json:"category"`
+	Count    int    `json:"count"`
+	Total    int64  `json:"total"`
+}
+
+// MonthlyReport 月別・カテゴリ別の支出集計
+type MonthlyReport struct {
+	Year   int              `json:"year"`
+	Months []MonthlySummary `json:"months"`
+}
+
+// MonthlySummary 1か月分の集計
+type MonthlySummary struct {
+	Month      int               `json:"month"`
+	Start      time.Time         `json:"start"`
+	End        time.Time         `json:"end"` // この日時を含まない
+	Total      int64             `json:"total"`
+	Categories []CategorySummary `json:"categories"`
+}
+
+// MonthlyReportParams 月別集計の条件（0の場合はサーバーのデフォルト）
+type MonthlyReportParams struct {
+	Year          int
+	MonthStartDay int
+}
+
+// CategoryChartParams カテゴリー別の円グラフの期間・大きさ（ゼロ値はサーバーのデフォルト）
+type CategoryChartParams struct {
+	From   time.Time // 省略時は今月の1日
+	To     time.Time // 省略時は今日
+	Width  int
+	Height int
+}
+
+// MonthlyChartParams 月別の推移の折れ線グラフの年・大きさ（ゼロ値はサーバーのデフォルト）
+type MonthlyChartParams struct {
+	Year          int
+	MonthStartDay int
+	Width         int
+	Height        int
+}
+
+// PeriodReport 任意の期間の集計
+type PeriodReport struct {
+	From    string          `json:"from"`
+	To      string          `json:"to"`
+	GroupBy string          `json:"group_by"`
+	Total   int64           `json:"total"`
+	Periods []PeriodSummary `json:"periods"`
+}
+
+// PeriodSummary 日・週・月ごとの集計
+type PeriodSummary struct {
+	Start      time.Time         `json:"start"`
+	End        time.Time         `json:"end"` // この日時を含まない
+	Total      int64             `json:"total"`
+	Categories []CategorySummary `json:"categories"`
+}
+
+// SummaryReportParams 期間集計の条件
+type SummaryReportParams struct {
+	From          time.Time // この日を含む（日付のみ使う）
+	To            time.Time // この日を含む（日付のみ使う）
+	GroupBy       string    // day / week / month（空の場合は month）
+	MonthStartDay int       // 0の場合はサーバーのデフォルト
+}
+
+// Calendar 日ごとのレシートの集計
+type Calendar struct {
+	Year     int           `json:"year"`
+	Month    int           `json:"month"` // 年全体の場合は0
+	Count    int           `json:"count"`
+	Total    int64         `json:"total"`
+	MaxTotal int64         `json:"max_total"`
+	Days     []CalendarDay `json:"days"`
+}
+
+// CalendarDay 日ごとの集計
+type CalendarDay struct {
+	Date    string `json:"date"`
+	Weekday int    `json:"weekday"` // 0（日曜）〜6（土曜）
+	Count   int    `json:"count"`
+	Total   int64  `json:"total"`
+	Level   int    `json:"level"` // ヒートマップの色の段階（0〜4）
+}
+
+// WidgetParams ウィジェットの集計条件（空のフィールドはサーバーのデフォルト）
+type WidgetParams struct {
+	From          time.Time // この日を含む（日付のみ使う）
+	To            time.Time // この日を含む（日付のみ使う）
+	Metric        string    // amount / item_count / receipt_count / average
+	GroupBy       string    // none / category / store / payment_method / tag
+	Period        string    // day / week / month / all
+	MonthStartDay int
+	Category      string
+	Store         string
+	PaymentMethod string
+	Tag           string
+	Limit         int // 系列数
+}
+
+// WidgetData グラフにそのまま渡せる形のウィジェットの集計結果
+type WidgetData struct {
+	Metric  string         `json:"metric"`
+	GroupBy string         `json:"group_by"`
+	Period  string         `json:"period"`
+	From    string         `json:"from"`
+	To      string         `json:"to"`
+	Labels  []string       `json:"labels"`
+	Periods []WidgetPeriod `json:"periods"`
+	Series  []WidgetSeries `json:"series"`
+}
+
+// WidgetPeriod ウィジェットの横軸の期間
+type WidgetPeriod struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"` // この日時を含まない
+}
+
+// WidgetSeries ウィジェットの系列
+type WidgetSeries struct {
+	Name  string  `json:"name"`
+	Total int64   `json:"total"`
+	Data  []int64 `json:"data"` // Labelsと同じ順の値
+}
+
+// MedicalDeductionReport 医療費控除の明細
+type MedicalDeductionReport struct {
+	Year      int                 `json:"year"`
+	Rows      []MedicalExpenseRow `json:"rows"`
+	Total     int64               `json:"total"`
+	Deduction int64               `json:"deduction"`
+}
+
+// MedicalExpenseRow 医療費控除の明細の行
+type MedicalExpenseRow struct {
+	ReceiptID string    `json:"receipt_id"`
+	Patient   string    `json:"patient"`
+	Provider  string    `json:"provider"`
+	Kind      string    `json:"kind"`
+	Amount    int64     `json:"amount"`
+	Date      time.Time `json:"date"`
+}
+
+// PeriodParams ダウンロードする期間と形式（Yearが0の場合は今年、Monthが0の場合は年全体）
+type PeriodParams struct {
+	Year   int
+	Month  int
+	Format string // 空の場合はサーバーのデフォルト
+}
+
+// ShoppingSuggestion 買い物リストの候補
+type ShoppingSuggestion struct {
+	Name           string    `json:"name"`
+	NormalizedName string    `json:"normalized_name"`
+	Purchases      int       `json:"purchases"`
+	IntervalDays   int       `json:"interval_days"`
+	LastPurchased  time.Time `json:"last_purchased"`
+	NextPurchase   time.Time `json:"next_purchase"`
+	Overdue        bool      `json:"overdue"`
+	LastPrice      int64     `json:"last_price"`
+	LastStore      string    `json:"last_store"`
+}
+
+// PublicSpendStats 地域別の平均の食費の公開統計
+type PublicSpendStats struct {
+	MinContributors int             `json:"min_contributors"` // 公開に必要な提供した世帯数
+	Stats           []RegionalSpend `json:"stats"`
+}
+
+// RegionalSpend 地域・月ごとの1世帯あたりの平均の食費
+type RegionalSpend struct {
+	Region        string `json:"region"`
+	Month         string `json:"month"`
+	Currency      string `json:"currency"`
+	Contributors  int    `json:"contributors"`
+	AverageAmount int64  `json:"average_amount"`
+}
+
+// SpendContribution 公開統計に提供する月の食費の合計
+type SpendContribution struct {
+	ContributorID string `json:"contributor_id"`
+	Region        string `json:"region"`
+	Month         string `json:"month"` // YYYY-MM
+	Amount        int64  `json:"amount"`
+	Currency      string `json:"currency"`
+}
+
+// MaintenanceStatus メンテナンスモードの状態
+type MaintenanceStatus struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message"`
+}
+
+// SLOStatus レシート処理のSLOの状態
+type SLOStatus struct {
+	WindowSeconds        int64   `json:"window_seconds"`
+	Requests             int     `json:"requests"`
+	Errors               int     `json:"errors"`
+	LatencyPercentile    float64 `json:"latency_percentile"`
+	LatencyMs            int64   `json:"latency_ms"`
+	LatencyThresholdMs   int64   `json:"latency_threshold_ms"`
+	SuccessRate          float64 `json:"success_rate"`
+	SuccessTarget        float64 `json:"success_target"`
+	ErrorBudgetRemaining float64 `json:"error_budget_remaining"`
+	Evaluated            bool    `json:"evaluated"`
+	LatencyBreached      bool    `json:"latency_breached"`
+	ErrorBudgetExhausted bool    `json:"error_budget_exhausted"`
+}
+
+// CacheUsage エンドポイント・テナントごとのキャッシュの利用状況
+type CacheUsage struct {
+	Endpoint     string  `json:"endpoint,omitempty"`
+	Tenant       string  `json:"tenant,omitempty"`
+	Hits         int64   `json:"hits"`
+	Misses       int64   `json:"misses"`
+	Errors       int64   `json:"errors"`
+	HitRate      float64 `json:"hit_rate"` // ヒット率（%）
+	Sets         int64   `json:"sets"`
+	BytesWritten int64   `json:"bytes_written"`
+}
+
+// CacheKeyspacePrefix キーの接頭辞ごとの件数・使用メモリ
+type CacheKeyspacePrefix struct {
+	Prefix        string `json:"prefix"`
+	Keys          int64  `json:"keys"`
+	Bytes         int64  `json:"bytes"`
+	NoExpiry      int64  `json:"no_expiry"`
+	AvgTTLSeconds int64  `json:"avg_ttl_seconds"`
+}
+
+// CacheKeyspace SCANで標本を取ったキーの件数・使用メモリ（Exactがfalseの場合は推定値）
+type CacheKeyspace struct {
+	TotalKeys   int64                 `json:"total_keys"`
+	SampledKeys int                   `json:"sampled_keys"`
+	Exact       bool                  `json:"exact"`
+	Prefixes    []CacheKeyspacePrefix `json:"prefixes"`
+}
+
+// CacheStats キャッシュの利用状況
+type CacheStats struct {
+	Since    time.Time      `json:"since"`
+	Total    CacheUsage     `json:"total"`
+	Scopes   []CacheUsage   `json:"scopes"`
+	Keyspace *CacheKeyspace `json:"keyspace,omitempty"` // キーを調べられなかった場合はnil
+}
+
+// TotalsRepairReport レシートの合計金額の修復結果
+type TotalsRepairReport struct {
+	DryRun        bool     `json:"dry_run"`
+	Scanned       int      `json:"scanned"`
+	Rederived     int      `json:"rederived"`
+	Mismatched    int      `json:"mismatched"`
+	Flagged       int      `json:"flagged"`
+	Failed        int      `json:"failed"`
+	MismatchedIDs []string `json:"mismatched_ids"`
+}
+
+// NameNormalizationReport レシートの店名・商品名の正規化結果
+type NameNormalizationReport struct {
+	DryRun     bool     `json:"dry_run"`
+	Scanned    int      `json:"scanned"`
+	Normalized int      `json:"normalized"`
+	Failed     int      `json:"failed"`
+	ChangedIDs []string `json:"changed_ids"`
+}
+
+// WarehouseExportReport 分析用のファイルの1か月分の書き出し結果
+type WarehouseExportReport struct {
+	Year   int                    `json:"year"`
+	Month  int                    `json:"month"`
+	Tables []WarehouseTableReport `json:"tables"`
+}
+
+// WarehouseTableReport 1つの表の書き出し結果
+type WarehouseTableReport struct {
+	Table         string `json:"table"`
+	Key           string `json:"key"`
+	Rows          int    `json:"rows"`
+	Bytes         int    `json:"bytes"`
+	SchemaVersion int    `json:"schema_version"`
+}
+
+// AIExchange 記録したAI APIへのリクエストとレスポンス
+type AIExchange struct {
+	At         time.Time       `json:"at"`
+	Operation  string          `json:"operation"`
+	Model      string          `json:"model"`
+	Request    json.RawMessage `json:"request"` // 画像データは省略されている
+	StatusCode int             `json:"status_code,omitempty"`
+	Response   string          `json:"response,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	DurationMs int64           `json:"duration_ms"`
+}
+
+// ContainerComponent サーバーで組み立てた実装
+type ContainerComponent struct {
+	Name           string            `json:"name"` // ai・cache・databaseなど
+	Implementation string            `json:"implementation"`
+	Details        map[string]string `json:"details,omitempty"` // 秘密情報は設定されている場合 <redacted>
+	Fingerprint    string            `json:"fingerprint"`       // 秘密情報を伏せた設定のハッシュ
+	InitializedAt  time.Time         `json:"initialized_at"`
+}
+
+// AIModelUsage モデルごとの当月のAI APIの利用量
+type AIModelUsage struct {
+	Requests     int     `json:"requests"`
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	CostUSD      float64 `json:"cost_usd"`
+}
+
+// AIBudgetStatus 当月のAI APIの利用料金と縮退の状態
+type AIBudgetStatus struct {
+	Month      string                  `json:"month"`
+	BudgetUSD  float64                 `json:"budget_usd"`
+	CostUSD    float64                 `json:"cost_usd"`
+	Exceeded   bool                    `json:"exceeded"`
+	Override   string                  `json:"override"`   // auto・normal・downgrade
+	Downgraded bool                    `json:"downgraded"` // カテゴリーの判定を縮退しているか
+	Downgrade  string                  `json:"downgrade"`  // cheap_model・rules
+	CheapModel string                  `json:"cheap_model,omitempty"`
+	Models     map[string]AIModelUsage `json:"models"`
+}
+
+// Page 一覧の取得範囲（0の場合はサーバーのデフォルト）
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// ListReceiptsParams レシート一覧の絞り込み
+type ListReceiptsParams struct {
+	Tag   string
+	Query string // 店名・明細名のキーワード
+	Page
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+// AnalyzeImage 画像からテキストを抽出
+func (c *Client) AnalyzeImage(ctx context.Context, image io.Reader, filename string) (*VisionResult, error) {
+	req, err := multipartRequest("/api/v1/vision/analyze", "image", filename, image, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.vision(ctx, req)
+}
+
+// AnalyzeReceipt レシート画像から構造化データ（JSON文字列）を抽出
+func (c *Client) AnalyzeReceipt(ctx context.Context, image io.Reader, filename string) (*VisionResult, error) {
+	req, err := multipartRequest("/api/v1/vision/receipt", "image", filename, image, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.vision(ctx, req)
+}
+
+// AnalyzeReceiptSimple 画像をリクエストボディに直接含めてレシートを解析（SetAPIKeyで設定したAPIキーが必要）
+// contentTypeは画像の形式（例: image/jpeg）
+func (c *Client) AnalyzeReceiptSimple(ctx context.Context, image io.Reader, contentType string) (*VisionResult, error) {
+	return c.vision(ctx, request{
+		method:      http.MethodPost,
+		path:        "/api/v1/vision/receipt/simple",
+		body:        image,
+		contentType: contentType,
+		apiKey:      true,
+	})
+}
+
+// CategorizeReceipt レシート情報からカテゴリを判定
+func (c *Client) CategorizeReceipt(ctx context.Context, receiptInfo string) (*VisionResult, error) {
+	req, err := jsonRequest(http.MethodPost, "/api/v1/vision/categorize", map[string]string{"receipt_info": receiptInfo})
+	if err != nil {
+		return nil, err
+	}
+	return c.vision(ctx, req)
+}
+
+// CompareImages 2つの画像（契約書の版違いのページなど）から抽出したテキストの行単位の差分を取得
+func (c *Client) CompareImages(ctx context.Context, before, after CompareImage) (*CompareResult, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for _, image := range []struct {
+		field string
+		image CompareImage
+	}{{"before", before}, {"after", after}} {
+		part, err := mw.CreateFormFile(image.field, image.image.Filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create form file: %w", err)
+		}
+		if _, err := io.Copy(part, image.image.Body); err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close form: %w", err)
+	}
+
+	resp, err := c.send(ctx, request{
+		method:      http.MethodPost,
+		path:        "/api/v1/vision/compare",
+		body:        &buf,
+		contentType: mw.FormDataContentType(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var result VisionResult
+		_ = json.NewDecoder(resp.Body).Decode(&result)
+		return nil, &APIError{StatusCode: resp.StatusCode, Message: result.Error}
+	}
+	var result CompareResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &result, nil
+}
+
+// vision Vision APIのリクエストを送信（Vision APIは家計簿APIと異なりtext・tokensを直接返す）
+func (c *Client) vision(ctx context.Context, req request) (*VisionResult, error) {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var result VisionResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		if resp.StatusCode >= http.StatusBadRequest {
+			return nil, &APIError{StatusCode: resp.StatusCode}
+		}
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest || !result.Success {
+		return nil, &APIError{StatusCode: resp.StatusCode, Message: result.Error}
+	}
+	result.Cached = resp.Header.Get("X-Cache") == "HIT"
+	return &result, nil
+}
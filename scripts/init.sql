@@ -11,29 +11,200 @@ CREATE TABLE IF NOT EXISTS receipts (
     id VARCHAR(36) PRIMARY KEY,
     store_name VARCHAR(255) NOT NULL,
     purchase_date DATETIME NOT NULL,
-    total_amount INT NOT NULL COMMENT '実際に使った金額',
-    tax_amount INT NOT NULL DEFAULT 0 COMMENT '消費税額',
+    total_amount BIGINT NOT NULL COMMENT '実際に使った金額（通貨の最小単位、返品は負）',
+    tax_amount BIGINT NOT NULL DEFAULT 0 COMMENT '消費税額（通貨の最小単位）',
     payment_method VARCHAR(50) DEFAULT '' COMMENT '支払い方法',
     receipt_number VARCHAR(100) DEFAULT '' COMMENT 'レシート番号',
+    receipt_type VARCHAR(10) NOT NULL DEFAULT 'purchase' COMMENT 'レシートの種類（purchase/refund）',
+    locale VARCHAR(35) NOT NULL DEFAULT '' COMMENT 'レシートのロケール（BCP 47）',
+    currency CHAR(3) NOT NULL DEFAULT '' COMMENT 'レシートに印字された通貨（ISO 4217、空の場合は設定の通貨）',
     category VARCHAR(50),
+    needs_review BOOLEAN NOT NULL DEFAULT FALSE COMMENT '要確認フラグ',
+    json_repaired BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'AIの応答のJSONを修復して読み取った',
+    date_out_of_range BOOLEAN NOT NULL DEFAULT FALSE COMMENT '読み取った購入日が妥当な範囲外だった',
+    ai_model VARCHAR(100) NOT NULL DEFAULT '' COMMENT '読み取ったAIのモデル（キャッシュした結果・手動登録の場合は空）',
+    ai_stop_reason VARCHAR(30) NOT NULL DEFAULT '' COMMENT 'AIの出力の終了理由',
+    ai_cache_hit BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'キャッシュした読み取り結果を使った',
+    ai_duration_ms INT NOT NULL DEFAULT 0 COMMENT '読み取りにかかった時間（ミリ秒）',
+    raw_extraction MEDIUMTEXT COMMENT 'AIの出力の原文（解析前の読み取り結果のJSON）',
+    categorization_raw TEXT COMMENT 'カテゴリー判定時のAIレスポンス（原文）',
+    tags JSON COMMENT 'タグ（文字列配列）',
+    memo TEXT COMMENT 'メモ',
+    image_hash CHAR(64) COMMENT '元画像のSHA256ハッシュ（重複登録の検出用）',
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_image_hash (image_hash),
     INDEX idx_purchase_date (purchase_date),
-    INDEX idx_category (category)
+    INDEX idx_category (category),
+    INDEX idx_store_name (store_name),
+    INDEX idx_needs_review (needs_review),
+    CONSTRAINT chk_receipts_store_name CHECK (TRIM(store_name) <> ''),
+    CONSTRAINT chk_receipts_receipt_type CHECK (receipt_type IN ('purchase', 'refund')),
+    CONSTRAINT chk_receipts_amount_sign CHECK (
+        (receipt_type = 'purchase' AND total_amount >= 0 AND tax_amount >= 0)
+        OR (receipt_type = 'refund' AND total_amount <= 0 AND tax_amount <= 0)
+    )
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
 -- Receipt items table
 CREATE TABLE IF NOT EXISTS receipt_items (
-    id VARCHAR(50) PRIMARY KEY COMMENT 'レシートID(36文字) + ハイフン + インデックス(8桁) = 45文字',
+    id VARCHAR(50) PRIMARY KEY COMMENT 'UUIDv7/UUIDv4/hash方式（36文字）、従来のhash方式ではレシートID(36文字) + ハイフン + インデックス(8桁) = 45文字',
     receipt_id VARCHAR(36) NOT NULL,
+    position INT NOT NULL DEFAULT 0 COMMENT 'レシート内の順番（0始まり）',
     name VARCHAR(255) NOT NULL,
+    normalized_name VARCHAR(255) NOT NULL DEFAULT '' COMMENT '表記ゆれを正規化した商品名（価格推移の検索用）',
     quantity INT NOT NULL DEFAULT 1,
-    price INT NOT NULL,
+    price BIGINT NOT NULL COMMENT '単価（通貨の最小単位、量り売りは計量した金額）',
+    unit VARCHAR(10) NOT NULL DEFAULT '' COMMENT '数量の単位（個/g/kg、空は個数）',
+    measure DECIMAL(10,3) NOT NULL DEFAULT 0 COMMENT '量り売りの計量値（unitの単位）',
+    unit_price BIGINT NOT NULL DEFAULT 0 COMMENT '量り売りの1kgあたりの単価（通貨の最小単位）',
     category VARCHAR(50) COMMENT '明細項目のカテゴリー',
+    category_status VARCHAR(20) NOT NULL DEFAULT '' COMMENT 'カテゴリーの判定状態（pending/auto/auto_failed/manual）',
+    warranty_months INT COMMENT '保証期間（月数）、NULLは既定値、0は保証なし',
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    return_notified_at DATETIME COMMENT '返品期限が近いことを通知した日時',
+    warranty_notified_at DATETIME COMMENT '保証期限が近いことを通知した日時',
     FOREIGN KEY (receipt_id) REFERENCES receipts(id) ON DELETE CASCADE,
+    UNIQUE KEY uk_receipt_position (receipt_id, position),
     INDEX idx_receipt_id (receipt_id),
-    INDEX idx_category (category)
+    INDEX idx_category (category),
+    INDEX idx_name (name),
+    INDEX idx_normalized_name (normalized_name),
+    INDEX idx_price (price),
+    CONSTRAINT chk_receipt_items_name CHECK (TRIM(name) <> ''),
+    CONSTRAINT chk_receipt_items_quantity CHECK (quantity > 0),
+    CONSTRAINT chk_receipt_items_measure CHECK (measure >= 0),
+    CONSTRAINT chk_receipt_items_unit_price CHECK (unit_price >= 0),
+    CONSTRAINT chk_receipt_items_warranty_months CHECK (warranty_months IS NULL OR warranty_months >= 0)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Receipt revisions table
+CREATE TABLE IF NOT EXISTS receipt_revisions (
+    id VARCHAR(36) PRIMARY KEY,
+    receipt_id VARCHAR(36) NOT NULL,
+    revision INT NOT NULL COMMENT '1から始まるリビジョン番号',
+    source VARCHAR(20) NOT NULL COMMENT '変更の発生元（original/manual/reprocess/revert/repair/merge/unmerge/normalize）',
+    snapshot JSON NOT NULL COMMENT '変更後のレシート全体のスナップショット',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (receipt_id) REFERENCES receipts(id) ON DELETE CASCADE,
+    UNIQUE KEY uk_receipt_revision (receipt_id, revision)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Receipt merges table
+CREATE TABLE IF NOT EXISTS receipt_merges (
+    id VARCHAR(36) PRIMARY KEY,
+    receipt_id VARCHAR(36) NOT NULL COMMENT '残したレシート',
+    merged_receipt_id VARCHAR(36) NOT NULL COMMENT '統合して削除したレシート',
+    before_snapshot JSON NOT NULL COMMENT '統合前の残したレシートのスナップショット',
+    merged_snapshot JSON NOT NULL COMMENT '削除したレシートのスナップショット',
+    added_item_ids JSON NOT NULL COMMENT '統合で残したレシートに追加した明細のID',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (receipt_id) REFERENCES receipts(id) ON DELETE CASCADE,
+    UNIQUE KEY uk_merged_receipt (merged_receipt_id),
+    INDEX idx_receipt_created_at (receipt_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Trash entries table
+CREATE TABLE IF NOT EXISTS trash_entries (
+    kind VARCHAR(20) NOT NULL COMMENT '項目の種類（receipt/expense）',
+    item_id VARCHAR(36) NOT NULL COMMENT '削除したレシート・家計簿エントリのID',
+    snapshot JSON NOT NULL COMMENT '削除時点のスナップショット',
+    deleted_at DATETIME NOT NULL,
+    purge_at DATETIME NOT NULL COMMENT '完全に削除する日時',
+    PRIMARY KEY (kind, item_id),
+    INDEX idx_deleted_at (deleted_at),
+    INDEX idx_kind_deleted_at (kind, deleted_at),
+    INDEX idx_purge_at (purge_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Receipt splits table
+CREATE TABLE IF NOT EXISTS receipt_splits (
+    receipt_id VARCHAR(36) PRIMARY KEY,
+    payer VARCHAR(100) NOT NULL DEFAULT '' COMMENT '支払いを立て替えた参加者',
+    participants JSON NOT NULL COMMENT '参加者ごとの担当明細・負担額・精算状態',
+    settled BOOLEAN NOT NULL DEFAULT FALSE COMMENT '全員の精算が済んでいるか',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (receipt_id) REFERENCES receipts(id) ON DELETE CASCADE,
+    INDEX idx_settled_created_at (settled, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Accounting syncs table
+CREATE TABLE IF NOT EXISTS accounting_syncs (
+    receipt_id VARCHAR(36) NOT NULL,
+    provider VARCHAR(20) NOT NULL COMMENT '会計サービス（freee/moneyforward）',
+    status VARCHAR(20) NOT NULL COMMENT '同期状態（synced/failed/conflict）',
+    external_id VARCHAR(100) NOT NULL DEFAULT '' COMMENT '会計サービス側の取引ID',
+    remote_version CHAR(64) NOT NULL DEFAULT '' COMMENT '最後に送信・確認した取引の内容のハッシュ',
+    receipt_updated_at DATETIME NOT NULL COMMENT '最後に送信したレシートの更新日時',
+    attempts INT NOT NULL DEFAULT 0 COMMENT '連続して失敗した回数',
+    last_error TEXT NOT NULL,
+    synced_at DATETIME,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (receipt_id, provider),
+    FOREIGN KEY (receipt_id) REFERENCES receipts(id) ON DELETE CASCADE,
+    INDEX idx_provider_status (provider, status, updated_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Item aliases table
+CREATE TABLE IF NOT EXISTS item_aliases (
+    alias_key VARCHAR(255) NOT NULL COMMENT '正規化した別名（照合用）',
+    alias VARCHAR(255) NOT NULL COMMENT '登録時の別名の表記',
+    canonical_name VARCHAR(255) NOT NULL COMMENT '正式な商品名',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (alias_key)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Expense reports table
+CREATE TABLE IF NOT EXISTS expense_reports (
+    id VARCHAR(36) PRIMARY KEY,
+    title VARCHAR(255) NOT NULL COMMENT '件名',
+    applicant VARCHAR(100) NOT NULL COMMENT '申請者',
+    status VARCHAR(20) NOT NULL COMMENT '状態（draft/submitted/approved/reimbursed）',
+    total_amount BIGINT NOT NULL DEFAULT 0 COMMENT '申請時に確定した合計金額（通貨の最小単位）',
+    approver VARCHAR(100) NOT NULL DEFAULT '' COMMENT '承認・差し戻しした承認者',
+    reimbursed_by VARCHAR(100) NOT NULL DEFAULT '' COMMENT '精算した経理担当者',
+    comment TEXT NOT NULL COMMENT '承認・差し戻しのコメント',
+    submitted_at DATETIME,
+    approved_at DATETIME,
+    reimbursed_at DATETIME,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_status_updated_at (status, updated_at),
+    INDEX idx_updated_at (updated_at),
+    CONSTRAINT chk_expense_reports_status CHECK (status IN ('draft', 'submitted', 'approved', 'reimbursed'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Expense report receipts table
+CREATE TABLE IF NOT EXISTS expense_report_receipts (
+    report_id VARCHAR(36) NOT NULL,
+    receipt_id VARCHAR(36) NOT NULL COMMENT 'ゴミ箱から元に戻したレシートも報告書に残すため外部キーにしない',
+    position INT NOT NULL DEFAULT 0 COMMENT '報告書内の順番（0始まり）',
+    PRIMARY KEY (report_id, receipt_id),
+    FOREIGN KEY (report_id) REFERENCES expense_reports(id) ON DELETE CASCADE,
+    UNIQUE KEY uk_receipt (receipt_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Collections table
+CREATE TABLE IF NOT EXISTS collections (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL COMMENT 'コレクション名（「2025年 確定申告」など）',
+    description TEXT NOT NULL COMMENT '説明',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_name (name),
+    CONSTRAINT chk_collections_name CHECK (TRIM(name) <> '')
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Collection receipts table
+CREATE TABLE IF NOT EXISTS collection_receipts (
+    collection_id VARCHAR(36) NOT NULL,
+    receipt_id VARCHAR(36) NOT NULL COMMENT 'ゴミ箱から元に戻したレシートもコレクションに残すため外部キーにしない',
+    position INT NOT NULL DEFAULT 0 COMMENT 'コレクション内の順番（0始まり）',
+    PRIMARY KEY (collection_id, receipt_id),
+    FOREIGN KEY (collection_id) REFERENCES collections(id) ON DELETE CASCADE,
+    INDEX idx_receipt_id (receipt_id)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
 -- Expense entries table
@@ -42,16 +213,43 @@ CREATE TABLE IF NOT EXISTS expense_entries (
     receipt_id VARCHAR(36),
     date DATETIME NOT NULL,
     category VARCHAR(50) NOT NULL,
-    amount INT NOT NULL,
+    amount BIGINT NOT NULL COMMENT '金額（通貨の最小単位）',
     description TEXT,
     tags JSON,
+    memo TEXT COMMENT 'メモ',
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     FOREIGN KEY (receipt_id) REFERENCES receipts(id) ON DELETE SET NULL,
-    INDEX idx_date (date),
-    INDEX idx_category (category)
+    INDEX idx_date_category (date, category),
+    INDEX idx_category_date (category, date),
+    CONSTRAINT chk_expense_entries_category CHECK (TRIM(category) <> ''),
+    CONSTRAINT chk_expense_entries_amount CHECK (amount >= 0)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
+-- Spend contributions table（公開統計への参加に同意した世帯から提供された月の食費の合計）
+CREATE TABLE IF NOT EXISTS spend_contributions (
+    contributor_id VARCHAR(64) NOT NULL COMMENT '世帯ごとのランダムな識別子',
+    month CHAR(7) NOT NULL COMMENT '対象月（YYYY-MM）',
+    region VARCHAR(50) NOT NULL COMMENT '都道府県などの地域',
+    currency CHAR(3) NOT NULL COMMENT '金額の通貨（ISO 4217）',
+    amount BIGINT NOT NULL COMMENT '月の食費の合計（通貨の最小単位）',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (contributor_id, month),
+    INDEX idx_month_region (month, region),
+    CONSTRAINT chk_spend_contributions_amount CHECK (amount >= 0)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Amount settings table
+CREATE TABLE IF NOT EXISTS amount_settings (
+    id TINYINT PRIMARY KEY,
+    currency CHAR(3) NOT NULL COMMENT '保存している金額の通貨（ISO 4217）',
+    digits TINYINT NOT NULL COMMENT '保存している金額の最小単位の小数点以下の桁数',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 金額は円単位で保存する（JPY以外の通貨は vision-api rescale-amounts で切り替える）
+INSERT IGNORE INTO amount_settings (id, currency, digits) VALUES (1, 'JPY', 0);
+
 -- Categories table
 CREATE TABLE IF NOT EXISTS categories (
     id VARCHAR(36) PRIMARY KEY,
@@ -0,0 +1,11 @@
+-- カテゴリー判定結果の保存と要確認フラグ
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+USE household;
+
+ALTER TABLE receipts
+    ADD COLUMN needs_review BOOLEAN NOT NULL DEFAULT FALSE COMMENT '要確認フラグ' AFTER category,
+    ADD COLUMN categorization_raw TEXT COMMENT 'カテゴリー判定時のAIレスポンス（原文）' AFTER needs_review,
+    ADD INDEX idx_needs_review (needs_review);
+
+ALTER TABLE receipt_items
+    ADD COLUMN category_status VARCHAR(20) NOT NULL DEFAULT '' COMMENT 'カテゴリーの判定状態（pending/auto/auto_failed/manual）' AFTER category;
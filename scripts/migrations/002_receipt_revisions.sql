@@ -0,0 +1,15 @@
+-- レシートの変更履歴
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+USE household;
+
+-- Receipt revisions table
+CREATE TABLE IF NOT EXISTS receipt_revisions (
+    id VARCHAR(36) PRIMARY KEY,
+    receipt_id VARCHAR(36) NOT NULL,
+    revision INT NOT NULL COMMENT '1から始まるリビジョン番号',
+    source VARCHAR(20) NOT NULL COMMENT '変更の発生元（original/manual/reprocess/revert）',
+    snapshot JSON NOT NULL COMMENT '変更後のレシート全体のスナップショット',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (receipt_id) REFERENCES receipts(id) ON DELETE CASCADE,
+    UNIQUE KEY uk_receipt_revision (receipt_id, revision)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
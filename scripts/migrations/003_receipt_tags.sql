@@ -0,0 +1,6 @@
+-- レシートのタグ
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+USE household;
+
+ALTER TABLE receipts
+    ADD COLUMN tags JSON COMMENT 'タグ（文字列配列）' AFTER categorization_raw;
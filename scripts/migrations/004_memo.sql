@@ -0,0 +1,9 @@
+-- レシート・家計簿エントリのメモ
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+USE household;
+
+ALTER TABLE receipts
+    ADD COLUMN memo TEXT COMMENT 'メモ' AFTER tags;
+
+ALTER TABLE expense_entries
+    ADD COLUMN memo TEXT COMMENT 'メモ' AFTER tags;
@@ -0,0 +1,8 @@
+-- 元画像のハッシュによる重複登録の検出
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+-- 既存のレシートは画像から決定的に生成したIDで重複を検出するため、値の埋め戻しは不要
+USE household;
+
+ALTER TABLE receipts
+    ADD COLUMN image_hash CHAR(64) COMMENT '元画像のSHA256ハッシュ（重複登録の検出用）' AFTER memo,
+    ADD UNIQUE KEY uk_image_hash (image_hash);
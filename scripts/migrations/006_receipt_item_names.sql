@@ -0,0 +1,14 @@
+-- 商品名の価格推移を検索するための正規化した商品名とインデックス
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+-- 既存の明細は空白を除いた商品名で埋め戻す。全角・半角、英字の大文字・小文字、ひらがな・カタカナの違いは
+-- 照合順序（utf8mb4_unicode_ci）で同一視されるため、アプリケーションの正規化と同じ明細が一致する
+USE household;
+
+ALTER TABLE receipt_items
+    ADD COLUMN normalized_name VARCHAR(255) NOT NULL DEFAULT '' COMMENT '表記ゆれを正規化した商品名（価格推移の検索用）' AFTER name,
+    ADD INDEX idx_name (name),
+    ADD INDEX idx_normalized_name (normalized_name);
+
+UPDATE receipt_items
+SET normalized_name = LOWER(REPLACE(REPLACE(name, ' ', ''), '　', ''))
+WHERE normalized_name = '';
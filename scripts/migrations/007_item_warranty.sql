@@ -0,0 +1,10 @@
+-- 高額な商品の返品・保証期限の管理
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+-- 保証期間が未設定（NULL）の明細は設定ファイルの warranties.default_months を使う
+USE household;
+
+ALTER TABLE receipt_items
+    ADD COLUMN warranty_months INT COMMENT '保証期間（月数）、NULLは既定値、0は保証なし' AFTER category_status,
+    ADD COLUMN return_notified_at DATETIME COMMENT '返品期限が近いことを通知した日時' AFTER created_at,
+    ADD COLUMN warranty_notified_at DATETIME COMMENT '保証期限が近いことを通知した日時' AFTER return_notified_at,
+    ADD INDEX idx_price (price);
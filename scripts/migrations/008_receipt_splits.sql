@@ -0,0 +1,14 @@
+-- レシートの割り勘（参加者ごとの負担額と精算状態）
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+USE household;
+
+CREATE TABLE IF NOT EXISTS receipt_splits (
+    receipt_id VARCHAR(36) PRIMARY KEY,
+    payer VARCHAR(100) NOT NULL DEFAULT '' COMMENT '支払いを立て替えた参加者',
+    participants JSON NOT NULL COMMENT '参加者ごとの担当明細・負担額・精算状態',
+    settled BOOLEAN NOT NULL DEFAULT FALSE COMMENT '全員の精算が済んでいるか',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (receipt_id) REFERENCES receipts(id) ON DELETE CASCADE,
+    INDEX idx_settled_created_at (settled, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
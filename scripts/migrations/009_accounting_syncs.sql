@@ -0,0 +1,19 @@
+-- 会計サービス（freee / マネーフォワード クラウド会計）との同期状態
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+USE household;
+
+CREATE TABLE IF NOT EXISTS accounting_syncs (
+    receipt_id VARCHAR(36) NOT NULL,
+    provider VARCHAR(20) NOT NULL COMMENT '会計サービス（freee/moneyforward）',
+    status VARCHAR(20) NOT NULL COMMENT '同期状態（synced/failed/conflict）',
+    external_id VARCHAR(100) NOT NULL DEFAULT '' COMMENT '会計サービス側の取引ID',
+    remote_version CHAR(64) NOT NULL DEFAULT '' COMMENT '最後に送信・確認した取引の内容のハッシュ',
+    receipt_updated_at DATETIME NOT NULL COMMENT '最後に送信したレシートの更新日時',
+    attempts INT NOT NULL DEFAULT 0 COMMENT '連続して失敗した回数',
+    last_error TEXT NOT NULL,
+    synced_at DATETIME,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (receipt_id, provider),
+    FOREIGN KEY (receipt_id) REFERENCES receipts(id) ON DELETE CASCADE,
+    INDEX idx_provider_status (provider, status, updated_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,13 @@
+-- 一覧・集計の検索条件のインデックス
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+-- 家計簿エントリは期間・カテゴリーの検索と集計を複合インデックスで行い、先頭の列が重複する単一列のインデックスは削除する
+USE household;
+
+ALTER TABLE receipts
+    ADD INDEX idx_store_name (store_name);
+
+ALTER TABLE expense_entries
+    ADD INDEX idx_date_category (date, category),
+    ADD INDEX idx_category_date (category, date),
+    DROP INDEX idx_date,
+    DROP INDEX idx_category;
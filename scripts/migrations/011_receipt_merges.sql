@@ -0,0 +1,16 @@
+-- 二重登録したレシートの統合の記録（統合の取り消しに使う）
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+USE household;
+
+CREATE TABLE IF NOT EXISTS receipt_merges (
+    id VARCHAR(36) PRIMARY KEY,
+    receipt_id VARCHAR(36) NOT NULL COMMENT '残したレシート',
+    merged_receipt_id VARCHAR(36) NOT NULL COMMENT '統合して削除したレシート',
+    before_snapshot JSON NOT NULL COMMENT '統合前の残したレシートのスナップショット',
+    merged_snapshot JSON NOT NULL COMMENT '削除したレシートのスナップショット',
+    added_item_ids JSON NOT NULL COMMENT '統合で残したレシートに追加した明細のID',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (receipt_id) REFERENCES receipts(id) ON DELETE CASCADE,
+    UNIQUE KEY uk_merged_receipt (merged_receipt_id),
+    INDEX idx_receipt_created_at (receipt_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,15 @@
+-- 削除したレシート・家計簿エントリのゴミ箱（保持期限まで元に戻せる）
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+USE household;
+
+CREATE TABLE IF NOT EXISTS trash_entries (
+    kind VARCHAR(20) NOT NULL COMMENT '項目の種類（receipt/expense）',
+    item_id VARCHAR(36) NOT NULL COMMENT '削除したレシート・家計簿エントリのID',
+    snapshot JSON NOT NULL COMMENT '削除時点のスナップショット',
+    deleted_at DATETIME NOT NULL,
+    purge_at DATETIME NOT NULL COMMENT '完全に削除する日時',
+    PRIMARY KEY (kind, item_id),
+    INDEX idx_deleted_at (deleted_at),
+    INDEX idx_kind_deleted_at (kind, deleted_at),
+    INDEX idx_purge_at (purge_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,24 @@
+-- 金額を通貨の最小単位（円・セントなど）の整数として保存する
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+-- JPY以外の通貨に切り替える場合は、適用後に vision-api rescale-amounts で保存済みの金額を換算する
+USE household;
+
+ALTER TABLE receipts
+    MODIFY total_amount BIGINT NOT NULL COMMENT '実際に使った金額（通貨の最小単位）',
+    MODIFY tax_amount BIGINT NOT NULL DEFAULT 0 COMMENT '消費税額（通貨の最小単位）';
+
+ALTER TABLE receipt_items
+    MODIFY price BIGINT NOT NULL COMMENT '単価（通貨の最小単位）';
+
+ALTER TABLE expense_entries
+    MODIFY amount BIGINT NOT NULL COMMENT '金額（通貨の最小単位）';
+
+CREATE TABLE IF NOT EXISTS amount_settings (
+    id TINYINT PRIMARY KEY,
+    currency CHAR(3) NOT NULL COMMENT '保存している金額の通貨（ISO 4217）',
+    digits TINYINT NOT NULL COMMENT '保存している金額の最小単位の小数点以下の桁数',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- これまでの金額は円単位で保存している
+INSERT IGNORE INTO amount_settings (id, currency, digits) VALUES (1, 'JPY', 0);
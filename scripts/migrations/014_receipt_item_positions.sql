@@ -0,0 +1,17 @@
+-- 明細のレシート内の順番
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+-- 再処理で明細を(receipt_id, position)で上書きするため、既存の明細にIDの順（従来のhash方式の連番・UUIDv7の生成順）で番号を振って一意にする
+USE household;
+
+ALTER TABLE receipt_items
+    ADD COLUMN position INT NOT NULL DEFAULT 0 COMMENT 'レシート内の順番（0始まり）' AFTER receipt_id;
+
+UPDATE receipt_items ri
+JOIN (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY receipt_id ORDER BY id) - 1 AS pos
+    FROM receipt_items
+) ranked ON ranked.id = ri.id
+SET ri.position = ranked.pos;
+
+ALTER TABLE receipt_items
+    ADD UNIQUE KEY uk_receipt_position (receipt_id, position);
@@ -0,0 +1,22 @@
+-- 保存する値の制約（アプリケーションの検証と同じ条件）
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+-- 制約に違反する行があると失敗するため、先に次のクエリで確認して修正する
+--   SELECT id FROM receipts WHERE TRIM(store_name) = '' OR total_amount < 0 OR tax_amount < 0;
+--   SELECT id FROM receipt_items WHERE TRIM(name) = '' OR quantity <= 0 OR price < 0 OR warranty_months < 0;
+--   SELECT id FROM expense_entries WHERE TRIM(category) = '' OR amount < 0;
+USE household;
+
+ALTER TABLE receipts
+    ADD CONSTRAINT chk_receipts_store_name CHECK (TRIM(store_name) <> ''),
+    ADD CONSTRAINT chk_receipts_total_amount CHECK (total_amount >= 0),
+    ADD CONSTRAINT chk_receipts_tax_amount CHECK (tax_amount >= 0);
+
+ALTER TABLE receipt_items
+    ADD CONSTRAINT chk_receipt_items_name CHECK (TRIM(name) <> ''),
+    ADD CONSTRAINT chk_receipt_items_quantity CHECK (quantity > 0),
+    ADD CONSTRAINT chk_receipt_items_price CHECK (price >= 0),
+    ADD CONSTRAINT chk_receipt_items_warranty_months CHECK (warranty_months IS NULL OR warranty_months >= 0);
+
+ALTER TABLE expense_entries
+    ADD CONSTRAINT chk_expense_entries_category CHECK (TRIM(category) <> ''),
+    ADD CONSTRAINT chk_expense_entries_amount CHECK (amount >= 0);
@@ -0,0 +1,12 @@
+-- 商品名の別名辞書（OCRで読み取った略称を正式な商品名に置き換える）
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+USE household;
+
+CREATE TABLE IF NOT EXISTS item_aliases (
+    alias_key VARCHAR(255) NOT NULL COMMENT '正規化した別名（照合用）',
+    alias VARCHAR(255) NOT NULL COMMENT '登録時の別名の表記',
+    canonical_name VARCHAR(255) NOT NULL COMMENT '正式な商品名',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (alias_key)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
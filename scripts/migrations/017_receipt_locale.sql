@@ -0,0 +1,8 @@
+-- レシートのロケール・通貨（海外のレシートの日付・金額の解釈に使用）
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+-- 既存のレシートは空のまま（設定の通貨のレシートとして扱う）
+USE household;
+
+ALTER TABLE receipts
+    ADD COLUMN locale VARCHAR(35) NOT NULL DEFAULT '' COMMENT 'レシートのロケール（BCP 47）' AFTER receipt_number,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT '' COMMENT 'レシートに印字された通貨（ISO 4217、空の場合は設定の通貨）' AFTER locale;
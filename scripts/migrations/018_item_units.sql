@@ -0,0 +1,11 @@
+-- 明細の数量の単位と量り売りの計量値・単価
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+-- 既存の明細は単位が空のまま（個数で数える明細として扱う）
+USE household;
+
+ALTER TABLE receipt_items
+    ADD COLUMN unit VARCHAR(10) NOT NULL DEFAULT '' COMMENT '数量の単位（個/g/kg、空は個数）' AFTER price,
+    ADD COLUMN measure DECIMAL(10,3) NOT NULL DEFAULT 0 COMMENT '量り売りの計量値（unitの単位）' AFTER unit,
+    ADD COLUMN unit_price BIGINT NOT NULL DEFAULT 0 COMMENT '量り売りの1kgあたりの単価（通貨の最小単位）' AFTER measure,
+    ADD CONSTRAINT chk_receipt_items_measure CHECK (measure >= 0),
+    ADD CONSTRAINT chk_receipt_items_unit_price CHECK (unit_price >= 0);
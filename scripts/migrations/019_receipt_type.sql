@@ -0,0 +1,17 @@
+-- レシートの種類（購入・返品）と返品レシートの負の金額
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+-- 既存のレシートは購入として扱う。金額の符号の制約は種類ごとの制約に置き換え、明細の金額は値引き・返品のため負を許す
+USE household;
+
+ALTER TABLE receipts
+    ADD COLUMN receipt_type VARCHAR(10) NOT NULL DEFAULT 'purchase' COMMENT 'レシートの種類（purchase/refund）' AFTER receipt_number,
+    DROP CHECK chk_receipts_total_amount,
+    DROP CHECK chk_receipts_tax_amount,
+    ADD CONSTRAINT chk_receipts_receipt_type CHECK (receipt_type IN ('purchase', 'refund')),
+    ADD CONSTRAINT chk_receipts_amount_sign CHECK (
+        (receipt_type = 'purchase' AND total_amount >= 0 AND tax_amount >= 0)
+        OR (receipt_type = 'refund' AND total_amount <= 0 AND tax_amount <= 0)
+    );
+
+ALTER TABLE receipt_items
+    DROP CHECK chk_receipt_items_price;
@@ -0,0 +1,6 @@
+-- AIの応答のJSONを修復して読み取ったレシートの記録（読み取り品質の集計用）
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+USE household;
+
+ALTER TABLE receipts
+    ADD COLUMN json_repaired BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'AIの応答のJSONを修復して読み取った' AFTER needs_review;
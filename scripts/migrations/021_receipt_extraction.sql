@@ -0,0 +1,9 @@
+-- AIによるレシート画像の読み取りの記録（モデル・終了理由・キャッシュの利用・処理時間）
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+USE household;
+
+ALTER TABLE receipts
+    ADD COLUMN ai_model VARCHAR(100) NOT NULL DEFAULT '' COMMENT '読み取ったAIのモデル（キャッシュした結果・手動登録の場合は空）' AFTER json_repaired,
+    ADD COLUMN ai_stop_reason VARCHAR(30) NOT NULL DEFAULT '' COMMENT 'AIの出力の終了理由' AFTER ai_model,
+    ADD COLUMN ai_cache_hit BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'キャッシュした読み取り結果を使った' AFTER ai_stop_reason,
+    ADD COLUMN ai_duration_ms INT NOT NULL DEFAULT 0 COMMENT '読み取りにかかった時間（ミリ秒）' AFTER ai_cache_hit;
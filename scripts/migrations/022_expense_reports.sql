@@ -0,0 +1,31 @@
+-- 経費報告書（会社の経費として精算するレシートをまとめ、承認フローを管理する）
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+USE household;
+
+CREATE TABLE IF NOT EXISTS expense_reports (
+    id VARCHAR(36) PRIMARY KEY,
+    title VARCHAR(255) NOT NULL COMMENT '件名',
+    applicant VARCHAR(100) NOT NULL COMMENT '申請者',
+    status VARCHAR(20) NOT NULL COMMENT '状態（draft/submitted/approved/reimbursed）',
+    total_amount BIGINT NOT NULL DEFAULT 0 COMMENT '申請時に確定した合計金額（通貨の最小単位）',
+    approver VARCHAR(100) NOT NULL DEFAULT '' COMMENT '承認・差し戻しした承認者',
+    reimbursed_by VARCHAR(100) NOT NULL DEFAULT '' COMMENT '精算した経理担当者',
+    comment TEXT NOT NULL COMMENT '承認・差し戻しのコメント',
+    submitted_at DATETIME,
+    approved_at DATETIME,
+    reimbursed_at DATETIME,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_status_updated_at (status, updated_at),
+    INDEX idx_updated_at (updated_at),
+    CONSTRAINT chk_expense_reports_status CHECK (status IN ('draft', 'submitted', 'approved', 'reimbursed'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS expense_report_receipts (
+    report_id VARCHAR(36) NOT NULL,
+    receipt_id VARCHAR(36) NOT NULL COMMENT 'ゴミ箱から元に戻したレシートも報告書に残すため外部キーにしない',
+    position INT NOT NULL DEFAULT 0 COMMENT '報告書内の順番（0始まり）',
+    PRIMARY KEY (report_id, receipt_id),
+    FOREIGN KEY (report_id) REFERENCES expense_reports(id) ON DELETE CASCADE,
+    UNIQUE KEY uk_receipt (receipt_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
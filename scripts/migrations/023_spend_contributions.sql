@@ -0,0 +1,15 @@
+-- 公開統計に提供された集計値（参加に同意した世帯の地域・月ごとの食費の合計）
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+USE household;
+
+CREATE TABLE IF NOT EXISTS spend_contributions (
+    contributor_id VARCHAR(64) NOT NULL COMMENT '世帯ごとのランダムな識別子',
+    month CHAR(7) NOT NULL COMMENT '対象月（YYYY-MM）',
+    region VARCHAR(50) NOT NULL COMMENT '都道府県などの地域',
+    currency CHAR(3) NOT NULL COMMENT '金額の通貨（ISO 4217）',
+    amount BIGINT NOT NULL COMMENT '月の食費の合計（通貨の最小単位）',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (contributor_id, month),
+    INDEX idx_month_region (month, region),
+    CONSTRAINT chk_spend_contributions_amount CHECK (amount >= 0)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
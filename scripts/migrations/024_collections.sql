@@ -0,0 +1,22 @@
+-- コレクション（目的ごとにレシートをまとめるフォルダー）
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+USE household;
+
+CREATE TABLE IF NOT EXISTS collections (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL COMMENT 'コレクション名（「2025年 確定申告」など）',
+    description TEXT NOT NULL COMMENT '説明',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_name (name),
+    CONSTRAINT chk_collections_name CHECK (TRIM(name) <> '')
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS collection_receipts (
+    collection_id VARCHAR(36) NOT NULL,
+    receipt_id VARCHAR(36) NOT NULL COMMENT 'ゴミ箱から元に戻したレシートもコレクションに残すため外部キーにしない',
+    position INT NOT NULL DEFAULT 0 COMMENT 'コレクション内の順番（0始まり）',
+    PRIMARY KEY (collection_id, receipt_id),
+    FOREIGN KEY (collection_id) REFERENCES collections(id) ON DELETE CASCADE,
+    INDEX idx_receipt_id (receipt_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,7 @@
+-- AIの出力の原文（再処理・監査・読み取り精度の評価でAIを呼び出さずに解析結果と比較する）
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+-- 適用前に登録したレシートは原文が空のまま（再処理すると保存される）
+USE household;
+
+ALTER TABLE receipts
+    ADD COLUMN raw_extraction MEDIUMTEXT COMMENT 'AIの出力の原文（解析前の読み取り結果のJSON）' AFTER ai_duration_ms;
@@ -0,0 +1,6 @@
+-- 読み取った購入日が妥当な範囲外だったレシートの記録（未来・古すぎる日付は読み間違いの可能性があり要確認にする）
+-- 既存のデータベースに適用する（新規環境は init.sql に含まれる）
+USE household;
+
+ALTER TABLE receipts
+    ADD COLUMN date_out_of_range BOOLEAN NOT NULL DEFAULT FALSE COMMENT '読み取った購入日が妥当な範囲外だった' AFTER json_repaired;
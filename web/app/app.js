@@ -0,0 +1,335 @@
+// 家計簿アプリ SPA
+// ハッシュでページを切り替え、REST API（/api/v1）からデータを取得する
+(function () {
+    'use strict';
+
+    var app = document.getElementById('app');
+    var pollTimer = null;
+
+    // API呼び出し（共通レスポンス {success, data, error} を展開）
+    function api(method, path, body) {
+        var options = { method: method, headers: {} };
+        if (body instanceof FormData) {
+            options.body = body;
+        } else if (body !== undefined) {
+            options.headers['Content-Type'] = 'application/json';
+            options.body = JSON.stringify(body);
+        }
+        return fetch('/api/v1' + path, options).then(function (res) {
+            return res.json().then(function (json) {
+                if (!res.ok || !json.success) {
+                    throw new Error(json.error || ('HTTP ' + res.status));
+                }
+                return json.data;
+            });
+        });
+    }
+
+    function escapeHTML(value) {
+        return String(value === undefined || value === null ? '' : value)
+            .replace(/&/g, '&amp;')
+            .replace(/</g, '&lt;')
+            .replace(/>/g, '&gt;')
+            .replace(/"/g, '&quot;')
+            .replace(/'/g, '&#39;');
+    }
+
+    // 金額の通貨（サーバーがindex.htmlのmetaタグに埋め込む）
+    var currencyMeta = document.querySelector('meta[name="currency"]');
+    var currencyDigits = currencyMeta ? Number(currencyMeta.getAttribute('data-digits')) || 0 : 0;
+    var currencySymbol = currencyMeta ? currencyMeta.getAttribute('data-symbol') : '¥';
+
+    // 最小単位の整数の金額を通貨の記号と3桁区切りで表示
+    function yen(value) {
+        var amount = Number(value || 0) / Math.pow(10, currencyDigits);
+        return currencySymbol + amount.toLocaleString('ja-JP', {
+            minimumFractionDigits: currencyDigits,
+            maximumFractionDigits: currencyDigits
+        });
+    }
+
+    function formatDate(value) {
+        var d = new Date(value);
+        if (isNaN(d.getTime())) {
+            return '';
+        }
+        return d.getFullYear() + '-' + pad(d.getMonth() + 1) + '-' + pad(d.getDate());
+    }
+
+    function pad(n) {
+        return n < 10 ? '0' + n : String(n);
+    }
+
+    function showError(err) {
+        app.innerHTML = '<div class="error-message">' + escapeHTML(err.message) + '</div>';
+    }
+
+    var statusLabels = {
+        pending: '判定中',
+        auto: '自動判定',
+        auto_failed: '要確認',
+        manual: '手動設定'
+    };
+
+    // ルーティング
+    function route() {
+        if (pollTimer) {
+            clearTimeout(pollTimer);
+            pollTimer = null;
+        }
+
+        var hash = location.hash.replace(/^#/, '') || '/';
+        var match = hash.match(/^\/receipts\/([^/?]+)$/);
+        if (match) {
+            renderDetail(decodeURIComponent(match[1]));
+        } else if (hash === '/upload') {
+            renderUpload();
+        } else if (hash.indexOf('/charts') === 0) {
+            renderCharts(new URLSearchParams(hash.split('?')[1] || ''));
+        } else {
+            renderList(new URLSearchParams(hash.split('?')[1] || ''));
+        }
+    }
+
+    // レシート一覧
+    function renderList(params) {
+        var q = params.get('q') || '';
+        var tag = params.get('tag') || '';
+        var query = new URLSearchParams({ limit: '100' });
+        if (q) { query.set('q', q); }
+        if (tag) { query.set('tag', tag); }
+
+        app.innerHTML = '<p class="loading-text">読み込み中...</p>';
+        api('GET', '/receipts?' + query.toString()).then(function (receipts) {
+            var rows = receipts.map(function (r) {
+                return '<tr>' +
+                    '<td>' + escapeHTML(formatDate(r.purchase_date)) + '</td>' +
+                    '<td><a href="#/receipts/' + encodeURIComponent(r.id) + '">' + escapeHTML(r.store_name) + '</a></td>' +
+                    '<td class="amount">' + yen(r.total_amount) + '</td>' +
+                    '<td>' + r.tags.map(function (t) { return '<span class="tag">' + escapeHTML(t) + '</span>'; }).join(' ') + '</td>' +
+                    '<td>' + (r.needs_review ? '<span class="badge badge-warning">要確認</span>' : '') + '</td>' +
+                    '</tr>';
+            }).join('');
+
+            app.innerHTML =
+                '<section class="household-section">' +
+                '<h2>レシート一覧</h2>' +
+                '<form class="tag-filter" id="search-form">' +
+                '<input type="text" name="q" placeholder="店名・商品名・メモ" value="' + escapeHTML(q) + '">' +
+                '<input type="text" name="tag" placeholder="タグ" value="' + escapeHTML(tag) + '">' +
+                '<button type="submit" class="btn btn-primary">検索</button>' +
+                '</form>' +
+                (receipts.length === 0 ? '<div class="empty-message"><p>レシートがありません</p></div>' :
+                    '<table class="receipts-table"><thead><tr>' +
+                    '<th>日付</th><th>店名</th><th>金額</th><th>タグ</th><th></th>' +
+                    '</tr></thead><tbody>' + rows + '</tbody></table>') +
+                '</section>';
+
+            document.getElementById('search-form').addEventListener('submit', function (e) {
+                e.preventDefault();
+                var form = new FormData(e.target);
+                var next = new URLSearchParams();
+                if (form.get('q')) { next.set('q', form.get('q')); }
+                if (form.get('tag')) { next.set('tag', form.get('tag')); }
+                location.hash = '/' + (next.toString() ? '?' + next.toString() : '');
+            });
+        }).catch(showError);
+    }
+
+    // レシート登録
+    function renderUpload() {
+        app.innerHTML =
+            '<section class="upload-section">' +
+            '<h2>レシート登録</h2>' +
+            '<form id="upload-form" class="upload-form">' +
+            '<div class="form-group"><label for="image">レシート画像</label>' +
+            '<input type="file" id="image" name="image" accept="image/*" required></div>' +
+            '<div class="form-group"><label for="tags">タグ（カンマ区切り）</label>' +
+            '<input type="text" id="tags" name="tags" placeholder="例: 旅行, 仕事"></div>' +
+            '<div class="form-group"><label for="memo">メモ</label>' +
+            '<textarea id="memo" name="memo" rows="2"></textarea></div>' +
+            '<button type="submit" class="btn btn-primary" id="upload-button">アップロード</button>' +
+            '<p class="upload-status" id="upload-status"></p>' +
+            '</form>' +
+            '</section>';
+
+        document.getElementById('upload-form').addEventListener('submit', function (e) {
+            e.preventDefault();
+            var button = document.getElementById('upload-button');
+            var status = document.getElementById('upload-status');
+            button.disabled = true;
+            status.textContent = 'レシートを認識しています...';
+
+            api('POST', '/receipts', new FormData(e.target)).then(function (receipt) {
+                location.hash = '/receipts/' + encodeURIComponent(receipt.id);
+            }).catch(function (err) {
+                button.disabled = false;
+                status.textContent = 'エラー: ' + err.message;
+            });
+        });
+    }
+
+    // レシート詳細・編集
+    function renderDetail(id) {
+        api('GET', '/receipts/' + encodeURIComponent(id)).then(function (receipt) {
+            var pending = receipt.items.some(function (item) { return item.category_status === 'pending'; });
+
+            var items = receipt.items.map(function (item) {
+                return '<tr data-id="' + escapeHTML(item.id) + '">' +
+                    '<td><input type="text" name="name" value="' + escapeHTML(item.name) + '"></td>' +
+                    '<td><input type="number" name="quantity" min="1" value="' + item.quantity + '"></td>' +
+                    '<td><input type="number" name="price" min="0" value="' + item.price + '"></td>' +
+                    '<td><input type="text" name="category" value="' + escapeHTML(item.category) + '"></td>' +
+                    '<td><span class="badge badge-' + escapeHTML(item.category_status) + '">' +
+                    escapeHTML(statusLabels[item.category_status] || item.category_status) + '</span></td>' +
+                    '<td><button type="button" class="btn btn-secondary remove-item">削除</button></td>' +
+                    '</tr>';
+            }).join('');
+
+            app.innerHTML =
+                '<section class="result-section">' +
+                '<h2>レシート詳細</h2>' +
+                (pending ? '<p class="processing-status">明細のカテゴリーを判定しています...</p>' : '') +
+                (receipt.needs_review ? '<p class="badge badge-warning">要確認の明細があります</p>' : '') +
+                '<form id="receipt-form">' +
+                '<div class="form-group"><label>店名</label><input type="text" name="store_name" value="' + escapeHTML(receipt.store_name) + '"></div>' +
+                '<div class="form-group"><label>購入日</label><input type="date" name="purchase_date" value="' + formatDate(receipt.purchase_date) + '"></div>' +
+                '<div class="form-group"><label>合計金額</label><input type="number" name="total_amount" min="0" value="' + receipt.total_amount + '"></div>' +
+                '<div class="form-group"><label>タグ（カンマ区切り）</label><input type="text" name="tags" value="' + escapeHTML(receipt.tags.join(', ')) + '"></div>' +
+                '<div class="form-group"><label>メモ</label><textarea name="memo" rows="2">' + escapeHTML(receipt.memo) + '</textarea></div>' +
+                '<h3>明細</h3>' +
+                '<table class="items-table"><thead><tr>' +
+                '<th>商品名</th><th>数量</th><th>単価</th><th>カテゴリー</th><th>判定</th><th></th>' +
+                '</tr></thead><tbody id="items">' + items + '</tbody></table>' +
+                '<button type="button" class="btn btn-secondary" id="add-item">明細を追加</button>' +
+                '<div class="form-actions">' +
+                '<button type="submit" class="btn btn-primary">保存</button>' +
+                '<a href="#/" class="btn btn-secondary">一覧に戻る</a>' +
+                '</div>' +
+                '<p class="upload-status" id="save-status"></p>' +
+                '</form>' +
+                '</section>';
+
+            bindDetailForm(receipt);
+
+            // カテゴリー判定が終わるまで定期的に再取得する
+            if (pending) {
+                pollTimer = setTimeout(function () { renderDetail(id); }, 2000);
+            }
+        }).catch(showError);
+    }
+
+    function bindDetailForm(receipt) {
+        var form = document.getElementById('receipt-form');
+        var tbody = document.getElementById('items');
+
+        tbody.addEventListener('click', function (e) {
+            if (e.target.classList.contains('remove-item')) {
+                e.target.closest('tr').remove();
+            }
+        });
+
+        document.getElementById('add-item').addEventListener('click', function () {
+            var row = document.createElement('tr');
+            row.dataset.id = '';
+            row.innerHTML =
+                '<td><input type="text" name="name"></td>' +
+                '<td><input type="number" name="quantity" min="1" value="1"></td>' +
+                '<td><input type="number" name="price" min="0" value="0"></td>' +
+                '<td><input type="text" name="category"></td>' +
+                '<td></td>' +
+                '<td><button type="button" class="btn btn-secondary remove-item">削除</button></td>';
+            tbody.appendChild(row);
+        });
+
+        form.addEventListener('submit', function (e) {
+            e.preventDefault();
+            if (pollTimer) {
+                clearTimeout(pollTimer);
+                pollTimer = null;
+            }
+
+            var date = form.elements.purchase_date.value.split('-');
+            var items = Array.prototype.map.call(tbody.querySelectorAll('tr'), function (row) {
+                return {
+                    id: row.dataset.id,
+                    name: row.querySelector('[name=name]').value,
+                    quantity: Number(row.querySelector('[name=quantity]').value),
+                    price: Number(row.querySelector('[name=price]').value),
+                    category: row.querySelector('[name=category]').value
+                };
+            });
+
+            var patch = {
+                store_name: form.elements.store_name.value,
+                total_amount: Number(form.elements.total_amount.value),
+                tags: form.elements.tags.value.split(',').map(function (t) { return t.trim(); }).filter(Boolean),
+                memo: form.elements.memo.value,
+                items: items
+            };
+            if (date.length === 3) {
+                var original = new Date(receipt.purchase_date);
+                patch.purchase_date = new Date(Number(date[0]), Number(date[1]) - 1, Number(date[2]),
+                    original.getHours(), original.getMinutes()).toISOString();
+            }
+
+            var status = document.getElementById('save-status');
+            status.textContent = '保存しています...';
+            api('PATCH', '/receipts/' + encodeURIComponent(receipt.id), patch).then(function () {
+                renderDetail(receipt.id);
+            }).catch(function (err) {
+                status.textContent = 'エラー: ' + err.message;
+            });
+        });
+    }
+
+    // 月別グラフ
+    function renderCharts(params) {
+        var year = Number(params.get('year')) || new Date().getFullYear();
+
+        app.innerHTML = '<p class="loading-text">読み込み中...</p>';
+        api('GET', '/reports/monthly?year=' + year).then(function (report) {
+            var max = Math.max.apply(null, report.months.map(function (m) { return m.total; }).concat([1]));
+            var yearTotal = report.months.reduce(function (sum, m) { return sum + m.total; }, 0);
+
+            var bars = report.months.map(function (m) {
+                var height = Math.round(m.total / max * 100);
+                var title = m.categories.map(function (c) { return c.category + ': ' + yen(c.total); }).join('\n');
+                return '<div class="chart-bar" title="' + escapeHTML(title) + '">' +
+                    '<span class="chart-value">' + (m.total > 0 ? yen(m.total) : '') + '</span>' +
+                    '<div class="chart-fill" style="height:' + height + '%"></div>' +
+                    '<span class="chart-label">' + m.month + '月</span>' +
+                    '</div>';
+            }).join('');
+
+            // 年間のカテゴリ別合計
+            var categories = {};
+            report.months.forEach(function (m) {
+                m.categories.forEach(function (c) {
+                    categories[c.category] = (categories[c.category] || 0) + c.total;
+                });
+            });
+            var categoryRows = Object.keys(categories).sort(function (a, b) {
+                return categories[b] - categories[a];
+            }).map(function (name) {
+                return '<tr><td>' + escapeHTML(name) + '</td><td class="amount">' + yen(categories[name]) + '</td></tr>';
+            }).join('');
+
+            app.innerHTML =
+                '<section class="household-section">' +
+                '<h2>月別グラフ</h2>' +
+                '<div class="chart-nav">' +
+                '<a href="#/charts?year=' + (year - 1) + '" class="btn btn-secondary">前年</a>' +
+                '<strong>' + year + '年（合計 ' + yen(yearTotal) + '）</strong>' +
+                '<a href="#/charts?year=' + (year + 1) + '" class="btn btn-secondary">翌年</a>' +
+                '</div>' +
+                '<div class="chart">' + bars + '</div>' +
+                '<h3>カテゴリ別</h3>' +
+                '<table class="summary-table"><tbody>' + categoryRows + '</tbody></table>' +
+                '</section>';
+        }).catch(showError);
+    }
+
+    window.addEventListener('hashchange', route);
+    route();
+})();
@@ -0,0 +1,9 @@
+// Package web Web UIのリソース
+package web
+
+import "embed"
+
+// App go:embedで埋め込んだSPAの静的ファイル（app/ 以下）
+//
+//go:embed app
+var App embed.FS